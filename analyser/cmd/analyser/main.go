@@ -3,13 +3,14 @@ package main
 import (
 	"context"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/config"
-	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/health"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/orchestrator"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/logging"
 )
 
 // main is the entry point for the Analyser service.
@@ -24,10 +25,10 @@ import (
 // Lifecycle:
 //  1. Load configuration from environment variables
 //  2. Initialize orchestrator with detection engine and service connections
-//  3. Start health check server (port 8081)
+//  3. Start health check server (port 8081); a port that can't be bound fails startup
 //  4. Start gRPC server to receive metrics
 //  5. Listen for shutdown signals (SIGINT, SIGTERM)
-//  6. Gracefully close all connections on shutdown
+//  6. On shutdown, finish snapshots being processed, then close connections
 func main() {
 	log.Printf("StartupMonkey Analyser starting...")
 
@@ -37,12 +38,14 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	logging.Setup("analyser", cfg.LogLevel)
+
 	log.Printf("Configuration loaded successfully")
 	log.Printf("  gRPC Port: %s", cfg.GRPCPort)
 	log.Printf("  Health Port: %s", cfg.HealthPort)
 	log.Printf("  NATS URL: %s", cfg.NatsURL)
 	log.Printf("  Knowledge Address: %s", cfg.KnowledgeAddress)
-	log.Printf("  Detectors Enabled: %v", cfg.EnableAllDetectors)
+	log.Printf("  Detectors: %v", cfg.Detectors)
 
 	// Log configured thresholds
 	log.Printf("Detection Thresholds:")
@@ -69,14 +72,10 @@ func main() {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
-	// Start health check HTTP server for container orchestration
-	// Exposes /health endpoint on configured port (default: 8081)
-	health.StartHealthCheckServer(cfg.HealthPort)
-
 	// Start gRPC server in background goroutine
 	go func() {
 		if err := orch.Run(ctx); err != nil && err != context.Canceled {
-			log.Printf("Orchestrator error: %v", err)
+			slog.Error("Orchestrator error", "error", err)
 		}
 	}()
 
@@ -89,7 +88,7 @@ func main() {
 
 	// Close all connections and cleanup resources
 	if err := orch.Stop(); err != nil {
-		log.Printf("Error during shutdown: %v", err)
+		slog.Error("Error during shutdown", "error", err)
 	}
 
 	log.Printf("Analyser stopped successfully")
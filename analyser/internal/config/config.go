@@ -3,11 +3,22 @@ package config
 import (
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
-
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/detector"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/logging"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/transport"
 	"github.com/joho/godotenv"
 )
 
+// DefaultTerminateDenyApplications protects schema migration tools from idle transaction termination.
+const DefaultTerminateDenyApplications = "*migrat*,flyway*,liquibase*"
+
 // Config holds all configuration for the Analyser service.
 type Config struct {
 	// Service addresses
@@ -16,19 +27,52 @@ type Config struct {
 	NatsURL          string
 	KnowledgeAddress string
 
+	// Mutual TLS for gRPC links (GRPC_TLS_*); plaintext when unset
+	GRPCTLS transport.TLSConfig
+
+	// Log verbosity (LOG_LEVEL: debug, info, warn, error)
+	LogLevel slog.Level
+
+	// Use JetStream durable consumers (false falls back to core NATS)
+	NatsJetStream bool
+
 	// Detection thresholds (configurable per detector)
 	Thresholds DetectionThresholds
 
-	// Feature flags
-	EnableAllDetectors bool
+	// How often threshold overrides are re-read from Knowledge (THRESHOLD_REFRESH_SECS)
+	ThresholdRefreshInterval time.Duration
+
+	// How long a database's policy is used before it is re-read from Knowledge (POLICY_CACHE_SECS)
+	PolicyCacheTTL time.Duration
+
+	// How long shutdown waits for snapshots being processed (SHUTDOWN_GRACE_PERIOD_SECONDS)
+	ShutdownGracePeriod time.Duration
+
+	// Snapshots a minute accepted per database (MAX_SNAPSHOTS_PER_MINUTE, 0 disables)
+	MaxSnapshotsPerMinute int
+
+	// Relative change in an active detection's value that re-publishes it (VALUE_CHANGE_THRESHOLD, 0 disables)
+	ValueChangeThreshold float64
+
+	// Idle sessions never sent for termination, as glob patterns (TERMINATE_DENY_APPLICATIONS, TERMINATE_DENY_USERS)
+	TerminateDenyApplications []string
+	TerminateDenyUsers        []string
+
+	// Detectors to run by name, or "all" (DETECTORS); see detector.Names
+	Detectors []string
+
+	// Main threshold per detector name (DETECTOR_<NAME>_THRESHOLD), already applied to Thresholds
+	DetectorThresholds map[string]float64
 }
 
 // DetectionThresholds contains configurable thresholds for each detector.
 // These can be adjusted via Dashboard.
 type DetectionThresholds struct {
 	// Connection Pool Detector
-	ConnectionPoolWarning  float64 // e.g., 0.7 = 70% utilization
-	ConnectionPoolCritical float64 // e.g., 0.9 = 90% utilization
+	ConnectionPoolWarning   float64 // e.g., 0.7 = 70% utilization
+	ConnectionPoolCritical  float64 // e.g., 0.9 = 90% utilization
+	ConnectionPoolIdle      float64 // Idle connections / max, e.g., 0.5 = 50%
+	ConnectionPoolIdleShare float64 // Idle / (active + idle) for idle connections to dominate
 
 	// Missing Index Detector
 	SequentialScanThreshold      int32   // Minimum seq scans to trigger
@@ -42,13 +86,51 @@ type DetectionThresholds struct {
 	CacheHitRateThreshold float64 // Minimum cache hit rate (0.0-1.0)
 
 	// Table Bloat Detector
-	TableBloatThreshold float64 // Dead tuple ratio (0.0-1.0), e.g., 0.1 = 10%
+	TableBloatThreshold             float64 // Dead tuple ratio (0.0-1.0), e.g., 0.1 = 10%
+	TableBloatFullRatio             float64 // Dead tuple ratio at which VACUUM FULL is recommended
+	TableBloatFullMaxSizeMB         float64 // Larger tables are never rewritten with VACUUM FULL (megabytes)
+	TableBloatTuneMinSizeMB         float64 // Tables this large get autovacuum tuned instead (megabytes)
+	TableBloatAutovacuumScaleFactor float64 // autovacuum_vacuum_scale_factor set when tuning
 
 	// Long Running Query Detector
 	LongRunningQueryThresholdSecs float64 // Query duration in seconds
 
 	// Idle Transaction Detector
 	IdleTransactionThresholdSecs float64 // Idle in transaction duration in seconds
+
+	// Replication Lag Detector
+	ReplicationLagThresholdSecs float64 // Replay lag in seconds (Info)
+	ReplicationLagWarningSecs   float64 // Replay lag in seconds (Warning)
+	ReplicationLagCriticalSecs  float64 // Replay lag in seconds (Critical)
+	ReplicationLagThresholdMB   float64 // WAL not yet replayed in megabytes (Info; 0 disables)
+
+	// Deadlock Detector
+	DeadlockThreshold float64 // Deadlocks per collection interval that must be exceeded
+
+	// Autovacuum Starvation Detector
+	AutovacuumAgeThresholdSecs float64 // Last autovacuum older than this (seconds)
+	AutovacuumStarvationCycles int32   // Consecutive cycles of dead tuple growth
+
+	// Unused Index Detector
+	UnusedIndexMinSizeMB float64 // Ignore indexes smaller than this (megabytes)
+	UnusedIndexCycles    int32   // Consecutive cycles with zero index scans
+
+	// Lock Contention Detector
+	LockWaitingConnections float64 // Connections waiting on locks that must be exceeded
+	LockContentionCycles   int32   // Consecutive cycles above the threshold
+
+	// Blocking Lock Detector
+	LockWaitThresholdSecs float64 // How long a lock must have blocked others (seconds)
+
+	// Connection Churn Detector
+	ConnectionChurnPerSec float64 // New connections per second
+
+	// Temp File Spill Detector
+	TempSpillThresholdMB float64 // Temp file megabytes written per collection interval
+
+	// Storage Growth Detector
+	StorageUsageThreshold  float64 // Fraction of the disk used (0-1)
+	StorageTimeToFullHours float64 // Projected hours until the disk is full (0 disables)
 }
 
 // Load reads configuration from environment variables and .env file.
@@ -79,15 +161,25 @@ func Load() (*Config, error) {
 		HealthPort:       getEnvOrDefault("HEALTH_PORT", "8081"),
 		NatsURL:          getEnvOrDefault("NATS_URL", "nats://localhost:4222"),
 		KnowledgeAddress: getEnvOrDefault("KNOWLEDGE_ADDRESS", "localhost:50053"),
+		GRPCTLS:          transport.TLSConfigFromEnv(),
+		NatsJetStream:    getEnvOrDefault("NATS_JETSTREAM", "true") == "true",
+
+		ThresholdRefreshInterval: time.Duration(parseIntOrDefault("THRESHOLD_REFRESH_SECS", 30)) * time.Second,
+		PolicyCacheTTL:           time.Duration(parseIntOrDefault("POLICY_CACHE_SECS", 15)) * time.Second,
+		ShutdownGracePeriod:      time.Duration(parseIntOrDefault("SHUTDOWN_GRACE_PERIOD_SECONDS", 15)) * time.Second,
+		MaxSnapshotsPerMinute:    parseIntOrDefault("MAX_SNAPSHOTS_PER_MINUTE", 60),
+		ValueChangeThreshold:     parseFloatOrDefault("VALUE_CHANGE_THRESHOLD", 0.2),
 
-		// Feature flags
-		EnableAllDetectors: getEnvOrDefault("ENABLE_ALL_DETECTORS", "true") == "true",
+		TerminateDenyApplications: parseList(getEnvOrDefault("TERMINATE_DENY_APPLICATIONS", DefaultTerminateDenyApplications)),
+		TerminateDenyUsers:        parseList(os.Getenv("TERMINATE_DENY_USERS")),
 
 		// Default thresholds
 		Thresholds: DetectionThresholds{
 			// Connection Pool (changed from 0.8 to 0.1 for local testing)
-			ConnectionPoolWarning:  parseFloatOrDefault("THRESHOLD_CONNECTION_POOL_WARNING", 0.7),
-			ConnectionPoolCritical: parseFloatOrDefault("THRESHOLD_CONNECTION_POOL_CRITICAL", 0.1),
+			ConnectionPoolWarning:   parseFloatOrDefault("THRESHOLD_CONNECTION_POOL_WARNING", 0.7),
+			ConnectionPoolCritical:  parseFloatOrDefault("THRESHOLD_CONNECTION_POOL_CRITICAL", 0.1),
+			ConnectionPoolIdle:      parseFloatOrDefault("THRESHOLD_CONNECTION_POOL_IDLE", 0.5),
+			ConnectionPoolIdleShare: parseFloatOrDefault("THRESHOLD_CONNECTION_POOL_IDLE_SHARE", 0.7),
 
 			// Missing Index
 			SequentialScanThreshold:      int32(parseIntOrDefault("THRESHOLD_SEQ_SCAN", 1)),
@@ -101,16 +193,74 @@ func Load() (*Config, error) {
 			CacheHitRateThreshold: parseFloatOrDefault("THRESHOLD_CACHE_HIT_RATE", 0.8),
 
 			// Table Bloat
-			TableBloatThreshold: parseFloatOrDefault("THRESHOLD_TABLE_BLOAT", 0.1),
+			TableBloatThreshold:             parseFloatOrDefault("THRESHOLD_TABLE_BLOAT", 0.1),
+			TableBloatFullRatio:             parseFloatOrDefault("THRESHOLD_TABLE_BLOAT_FULL_RATIO", 0.5),
+			TableBloatFullMaxSizeMB:         parseFloatOrDefault("THRESHOLD_TABLE_BLOAT_FULL_MAX_SIZE_MB", 1024),
+			TableBloatTuneMinSizeMB:         parseFloatOrDefault("THRESHOLD_TABLE_BLOAT_TUNE_MIN_SIZE_MB", 1024),
+			TableBloatAutovacuumScaleFactor: parseFloatOrDefault("THRESHOLD_TABLE_BLOAT_AUTOVACUUM_SCALE_FACTOR", 0.05),
 
 			// Long Running Query
 			LongRunningQueryThresholdSecs: parseFloatOrDefault("THRESHOLD_LONG_QUERY_SECS", 30.0),
 
 			// Idle Transaction
 			IdleTransactionThresholdSecs: parseFloatOrDefault("THRESHOLD_IDLE_TXN_SECS", 300.0),
+
+			// Replication Lag
+			ReplicationLagThresholdSecs: parseFloatOrDefault("THRESHOLD_REPLICATION_LAG_SECS", 10.0),
+			ReplicationLagWarningSecs:   parseFloatOrDefault("THRESHOLD_REPLICATION_LAG_WARNING_SECS", 30.0),
+			ReplicationLagCriticalSecs:  parseFloatOrDefault("THRESHOLD_REPLICATION_LAG_CRITICAL_SECS", 120.0),
+			ReplicationLagThresholdMB:   parseFloatOrDefault("THRESHOLD_REPLICATION_LAG_MB", 256.0),
+
+			// Deadlock
+			DeadlockThreshold: parseFloatOrDefault("THRESHOLD_DEADLOCKS", 0),
+
+			// Autovacuum Starvation
+			AutovacuumAgeThresholdSecs: parseFloatOrDefault("THRESHOLD_AUTOVACUUM_AGE_SECS", 3600.0),
+			AutovacuumStarvationCycles: int32(parseIntOrDefault("THRESHOLD_AUTOVACUUM_STARVATION_CYCLES", 3)),
+
+			// Unused Index
+			UnusedIndexMinSizeMB: parseFloatOrDefault("THRESHOLD_UNUSED_INDEX_MIN_SIZE_MB", 10.0),
+			UnusedIndexCycles:    int32(parseIntOrDefault("THRESHOLD_UNUSED_INDEX_CYCLES", 120)),
+
+			// Lock Contention
+			LockWaitingConnections: parseFloatOrDefault("THRESHOLD_LOCK_WAITING_CONNECTIONS", 5),
+			LockContentionCycles:   int32(parseIntOrDefault("THRESHOLD_LOCK_CONTENTION_CYCLES", 3)),
+
+			// Blocking Lock
+			LockWaitThresholdSecs: parseFloatOrDefault("THRESHOLD_LOCK_WAIT_SECS", 60.0),
+
+			// Connection Churn
+			ConnectionChurnPerSec: parseFloatOrDefault("THRESHOLD_CONNECTION_CHURN_PER_SEC", 10.0),
+
+			// Temp File Spill
+			TempSpillThresholdMB: parseFloatOrDefault("THRESHOLD_TEMP_SPILL_MB", 100.0),
+
+			// Storage Growth
+			StorageUsageThreshold:  parseFloatOrDefault("THRESHOLD_STORAGE_USAGE", 0.85),
+			StorageTimeToFullHours: parseFloatOrDefault("THRESHOLD_STORAGE_TIME_TO_FULL_HOURS", 72.0),
 		},
 	}
 
+	detectors := getEnvOrDefault("DETECTORS", "all")
+	if os.Getenv("DETECTORS") == "" && os.Getenv("ENABLE_ALL_DETECTORS") == "false" {
+		// Older deployments switched every detector off this way
+		detectors = "none"
+	}
+	config.Detectors = parseList(detectors)
+
+	detectorThresholds, err := parseDetectorThresholds(os.Environ())
+	if err != nil {
+		return nil, err
+	}
+	config.DetectorThresholds = detectorThresholds
+	config.Thresholds = config.Thresholds.withDetectorThresholds(detectorThresholds)
+
+	logLevel, err := logging.ParseLevel(os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		return nil, err
+	}
+	config.LogLevel = logLevel
+
 	if err := config.Validate(); err != nil {
 		return nil, err
 	}
@@ -120,6 +270,10 @@ func Load() (*Config, error) {
 
 // Validate checks that required configuration is present.
 func (c *Config) Validate() error {
+	if err := c.GRPCTLS.Validate(); err != nil {
+		return err
+	}
+
 	if c.GRPCPort == "" {
 		return fmt.Errorf("GRPC_PORT is required")
 	}
@@ -128,16 +282,93 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("KNOWLEDGE_ADDRESS is required")
 	}
 
-	// Validate threshold ranges
-	if c.Thresholds.ConnectionPoolWarning < 0 || c.Thresholds.ConnectionPoolWarning > 1 {
-		return fmt.Errorf("CONNECTION_POOL_WARNING must be between 0 and 1")
+	if c.ThresholdRefreshInterval <= 0 {
+		return fmt.Errorf("THRESHOLD_REFRESH_SECS must be positive")
+	}
+
+	if c.PolicyCacheTTL <= 0 {
+		return fmt.Errorf("POLICY_CACHE_SECS must be positive")
 	}
 
-	if c.Thresholds.CacheHitRateThreshold < 0 || c.Thresholds.CacheHitRateThreshold > 1 {
-		return fmt.Errorf("CACHE_HIT_RATE_THRESHOLD must be between 0 and 1")
+	if c.ShutdownGracePeriod < 0 {
+		return fmt.Errorf("SHUTDOWN_GRACE_PERIOD_SECONDS must not be negative")
 	}
 
-	return nil
+	if c.MaxSnapshotsPerMinute < 0 {
+		return fmt.Errorf("MAX_SNAPSHOTS_PER_MINUTE must not be negative")
+	}
+
+	if c.ValueChangeThreshold < 0 {
+		return fmt.Errorf("VALUE_CHANGE_THRESHOLD must not be negative")
+	}
+
+	for _, pattern := range append(append([]string{}, c.TerminateDenyApplications...), c.TerminateDenyUsers...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid terminate denylist pattern %q: %w", pattern, err)
+		}
+	}
+
+	if _, err := c.EnabledDetectors(); err != nil {
+		return err
+	}
+
+	for name := range c.DetectorThresholds {
+		if _, ok := detector.Lookup(name); !ok {
+			return fmt.Errorf("unknown detector %q in %s (valid detectors: %s)",
+				name, detectorThresholdEnv(name), strings.Join(detector.Names(), ", "))
+		}
+	}
+
+	return c.Thresholds.Validate()
+}
+
+// EnabledDetectors returns the names of the detectors to run, in run order, expanding "all".
+// Unknown names are an error listing the valid ones.
+func (c *Config) EnabledDetectors() ([]string, error) {
+	enabled := make(map[string]bool, len(c.Detectors))
+	for _, name := range c.Detectors {
+		if name == "all" {
+			return detector.Names(), nil
+		}
+		if _, ok := detector.Lookup(name); !ok {
+			return nil, fmt.Errorf("unknown detector %q in DETECTORS (valid detectors: %s)",
+				name, strings.Join(detector.Names(), ", "))
+		}
+		enabled[name] = true
+	}
+
+	var names []string
+	for _, name := range detector.Names() {
+		if enabled[name] {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// parseDetectorThresholds reads DETECTOR_<NAME>_THRESHOLD variables from environ, keyed by
+// lower-cased detector name.
+func parseDetectorThresholds(environ []string) (map[string]float64, error) {
+	thresholds := make(map[string]float64)
+	for _, entry := range environ {
+		key, value, _ := strings.Cut(entry, "=")
+		if !strings.HasPrefix(key, "DETECTOR_") || !strings.HasSuffix(key, "_THRESHOLD") || value == "" {
+			continue
+		}
+		name := strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(key, "DETECTOR_"), "_THRESHOLD"))
+
+		threshold, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+		thresholds[name] = threshold
+	}
+	return thresholds, nil
+}
+
+// detectorThresholdEnv returns the variable that sets a detector's main threshold.
+func detectorThresholdEnv(name string) string {
+	return "DETECTOR_" + strings.ToUpper(name) + "_THRESHOLD"
 }
 
 // Helper functions
@@ -148,6 +379,18 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+// parseList splits a comma-separated list; "none" yields an empty list.
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" && item != "none" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func parseFloatOrDefault(key string, defaultValue float64) float64 {
 	if value := os.Getenv(key); value != "" {
 		var result float64
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/detector"
+)
+
+// thresholdSetters maps the names used for threshold overrides in Knowledge (and by the
+// Dashboard) onto DetectionThresholds fields.
+var thresholdSetters = map[string]func(t *DetectionThresholds, v float64){
+	"connection_pool_warning":             func(t *DetectionThresholds, v float64) { t.ConnectionPoolWarning = v },
+	"connection_pool_critical":            func(t *DetectionThresholds, v float64) { t.ConnectionPoolCritical = v },
+	"connection_pool_idle":                func(t *DetectionThresholds, v float64) { t.ConnectionPoolIdle = v },
+	"connection_pool_idle_share":          func(t *DetectionThresholds, v float64) { t.ConnectionPoolIdleShare = v },
+	"sequential_scan_threshold":           func(t *DetectionThresholds, v float64) { t.SequentialScanThreshold = int32(v) },
+	"sequential_scan_delta":               func(t *DetectionThresholds, v float64) { t.SequentialScanDeltaThreshold = v },
+	"p95_latency_ms":                      func(t *DetectionThresholds, v float64) { t.P95LatencyThresholdMs = v },
+	"p99_latency_ms":                      func(t *DetectionThresholds, v float64) { t.P99LatencyThresholdMs = v },
+	"cache_hit_rate_threshold":            func(t *DetectionThresholds, v float64) { t.CacheHitRateThreshold = v },
+	"table_bloat":                         func(t *DetectionThresholds, v float64) { t.TableBloatThreshold = v },
+	"table_bloat_full_ratio":              func(t *DetectionThresholds, v float64) { t.TableBloatFullRatio = v },
+	"table_bloat_full_max_size_mb":        func(t *DetectionThresholds, v float64) { t.TableBloatFullMaxSizeMB = v },
+	"table_bloat_tune_min_size_mb":        func(t *DetectionThresholds, v float64) { t.TableBloatTuneMinSizeMB = v },
+	"table_bloat_autovacuum_scale_factor": func(t *DetectionThresholds, v float64) { t.TableBloatAutovacuumScaleFactor = v },
+	"long_running_query_secs":             func(t *DetectionThresholds, v float64) { t.LongRunningQueryThresholdSecs = v },
+	"idle_transaction_secs":               func(t *DetectionThresholds, v float64) { t.IdleTransactionThresholdSecs = v },
+	"replication_lag_secs":                func(t *DetectionThresholds, v float64) { t.ReplicationLagThresholdSecs = v },
+	"replication_lag_warning_secs":        func(t *DetectionThresholds, v float64) { t.ReplicationLagWarningSecs = v },
+	"replication_lag_critical_secs":       func(t *DetectionThresholds, v float64) { t.ReplicationLagCriticalSecs = v },
+	"replication_lag_mb":                  func(t *DetectionThresholds, v float64) { t.ReplicationLagThresholdMB = v },
+	"deadlocks":                           func(t *DetectionThresholds, v float64) { t.DeadlockThreshold = v },
+	"autovacuum_age_secs":                 func(t *DetectionThresholds, v float64) { t.AutovacuumAgeThresholdSecs = v },
+	"autovacuum_starvation_cycles":        func(t *DetectionThresholds, v float64) { t.AutovacuumStarvationCycles = int32(v) },
+	"unused_index_min_size_mb":            func(t *DetectionThresholds, v float64) { t.UnusedIndexMinSizeMB = v },
+	"unused_index_cycles":                 func(t *DetectionThresholds, v float64) { t.UnusedIndexCycles = int32(v) },
+	"lock_waiting_connections":            func(t *DetectionThresholds, v float64) { t.LockWaitingConnections = v },
+	"lock_contention_cycles":              func(t *DetectionThresholds, v float64) { t.LockContentionCycles = int32(v) },
+	"lock_wait_secs":                      func(t *DetectionThresholds, v float64) { t.LockWaitThresholdSecs = v },
+	"connection_churn_per_sec":            func(t *DetectionThresholds, v float64) { t.ConnectionChurnPerSec = v },
+	"temp_spill_mb":                       func(t *DetectionThresholds, v float64) { t.TempSpillThresholdMB = v },
+	"storage_usage":                       func(t *DetectionThresholds, v float64) { t.StorageUsageThreshold = v },
+	"storage_time_to_full_hours":          func(t *DetectionThresholds, v float64) { t.StorageTimeToFullHours = v },
+}
+
+// ThresholdNames returns the threshold names that can be overridden, sorted.
+func ThresholdNames() []string {
+	names := make([]string, 0, len(thresholdSetters))
+	for name := range thresholdSetters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithOverrides returns a copy of t with the named overrides applied. Names that don't match
+// a threshold are returned as unknown and otherwise ignored.
+func (t DetectionThresholds) WithOverrides(values map[string]float64) (DetectionThresholds, []string) {
+	var unknown []string
+
+	for name, value := range values {
+		set, ok := thresholdSetters[name]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		set(&t, value)
+	}
+
+	sort.Strings(unknown)
+	return t, unknown
+}
+
+// withDetectorThresholds returns a copy of t with each named detector's main threshold set.
+// Unknown detectors are left for Config.Validate to report.
+func (t DetectionThresholds) withDetectorThresholds(values map[string]float64) DetectionThresholds {
+	for name, value := range values {
+		if reg, ok := detector.Lookup(name); ok && len(reg.Thresholds) > 0 {
+			t, _ = t.WithOverrides(map[string]float64{reg.Thresholds[0]: value})
+		}
+	}
+	return t
+}
+
+// Values returns every threshold by the name used for overrides.
+func (t DetectionThresholds) Values() map[string]float64 {
+	return map[string]float64{
+		"connection_pool_warning":             t.ConnectionPoolWarning,
+		"connection_pool_critical":            t.ConnectionPoolCritical,
+		"connection_pool_idle":                t.ConnectionPoolIdle,
+		"connection_pool_idle_share":          t.ConnectionPoolIdleShare,
+		"sequential_scan_threshold":           float64(t.SequentialScanThreshold),
+		"sequential_scan_delta":               t.SequentialScanDeltaThreshold,
+		"p95_latency_ms":                      t.P95LatencyThresholdMs,
+		"p99_latency_ms":                      t.P99LatencyThresholdMs,
+		"cache_hit_rate_threshold":            t.CacheHitRateThreshold,
+		"table_bloat":                         t.TableBloatThreshold,
+		"table_bloat_full_ratio":              t.TableBloatFullRatio,
+		"table_bloat_full_max_size_mb":        t.TableBloatFullMaxSizeMB,
+		"table_bloat_tune_min_size_mb":        t.TableBloatTuneMinSizeMB,
+		"table_bloat_autovacuum_scale_factor": t.TableBloatAutovacuumScaleFactor,
+		"long_running_query_secs":             t.LongRunningQueryThresholdSecs,
+		"idle_transaction_secs":               t.IdleTransactionThresholdSecs,
+		"replication_lag_secs":                t.ReplicationLagThresholdSecs,
+		"replication_lag_warning_secs":        t.ReplicationLagWarningSecs,
+		"replication_lag_critical_secs":       t.ReplicationLagCriticalSecs,
+		"replication_lag_mb":                  t.ReplicationLagThresholdMB,
+		"deadlocks":                           t.DeadlockThreshold,
+		"autovacuum_age_secs":                 t.AutovacuumAgeThresholdSecs,
+		"autovacuum_starvation_cycles":        float64(t.AutovacuumStarvationCycles),
+		"unused_index_min_size_mb":            t.UnusedIndexMinSizeMB,
+		"unused_index_cycles":                 float64(t.UnusedIndexCycles),
+		"lock_waiting_connections":            t.LockWaitingConnections,
+		"lock_contention_cycles":              float64(t.LockContentionCycles),
+		"lock_wait_secs":                      t.LockWaitThresholdSecs,
+		"connection_churn_per_sec":            t.ConnectionChurnPerSec,
+		"temp_spill_mb":                       t.TempSpillThresholdMB,
+		"storage_usage":                       t.StorageUsageThreshold,
+		"storage_time_to_full_hours":          t.StorageTimeToFullHours,
+	}
+}
+
+// Validate checks threshold ranges and ordering.
+func (t DetectionThresholds) Validate() error {
+	if t.ConnectionPoolWarning < 0 || t.ConnectionPoolWarning > 1 {
+		return fmt.Errorf("CONNECTION_POOL_WARNING must be between 0 and 1")
+	}
+
+	if t.ConnectionPoolIdle < 0 || t.ConnectionPoolIdle > 1 ||
+		t.ConnectionPoolIdleShare < 0 || t.ConnectionPoolIdleShare > 1 {
+		return fmt.Errorf("THRESHOLD_CONNECTION_POOL_IDLE and THRESHOLD_CONNECTION_POOL_IDLE_SHARE must be between 0 and 1")
+	}
+
+	if t.CacheHitRateThreshold < 0 || t.CacheHitRateThreshold > 1 {
+		return fmt.Errorf("CACHE_HIT_RATE_THRESHOLD must be between 0 and 1")
+	}
+
+	if t.TableBloatAutovacuumScaleFactor < 0 || t.TableBloatAutovacuumScaleFactor > 1 {
+		return fmt.Errorf("THRESHOLD_TABLE_BLOAT_AUTOVACUUM_SCALE_FACTOR must be between 0 and 1")
+	}
+
+	if t.ReplicationLagThresholdSecs > t.ReplicationLagWarningSecs ||
+		t.ReplicationLagWarningSecs > t.ReplicationLagCriticalSecs {
+		return fmt.Errorf("replication lag thresholds must satisfy THRESHOLD_REPLICATION_LAG_SECS <= WARNING_SECS <= CRITICAL_SECS")
+	}
+
+	if t.ReplicationLagThresholdMB < 0 {
+		return fmt.Errorf("THRESHOLD_REPLICATION_LAG_MB must not be negative")
+	}
+
+	if t.ConnectionChurnPerSec < 0 {
+		return fmt.Errorf("THRESHOLD_CONNECTION_CHURN_PER_SEC must not be negative")
+	}
+
+	if t.TempSpillThresholdMB < 0 {
+		return fmt.Errorf("THRESHOLD_TEMP_SPILL_MB must not be negative")
+	}
+
+	if t.StorageUsageThreshold < 0 || t.StorageUsageThreshold > 1 {
+		return fmt.Errorf("THRESHOLD_STORAGE_USAGE must be between 0 and 1")
+	}
+
+	if t.StorageTimeToFullHours < 0 {
+		return fmt.Errorf("THRESHOLD_STORAGE_TIME_TO_FULL_HOURS must not be negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,195 @@
+package detector
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+)
+
+// AutovacuumStarvationDetector fires when a table's dead tuples keep growing across
+// consecutive collection cycles while autovacuum has not run on it recently.
+type AutovacuumStarvationDetector struct {
+	autovacuumAgeSecs float64 // Last autovacuum must be older than this
+	consecutiveCycles int     // Cycles of monotonic dead tuple growth required
+	minDeadTuples     float64 // Ignore small tables
+
+	mu         sync.Mutex
+	growthRuns map[string]int // table -> consecutive growth cycles
+}
+
+func NewAutovacuumStarvationDetector() *AutovacuumStarvationDetector {
+	return &AutovacuumStarvationDetector{
+		autovacuumAgeSecs: 3600.0, // 1 hour default
+		consecutiveCycles: 3,
+		minDeadTuples:     1000,
+		growthRuns:        make(map[string]int),
+	}
+}
+
+func (d *AutovacuumStarvationDetector) Name() string {
+	return "autovacuum_starvation"
+}
+
+func (d *AutovacuumStarvationDetector) Category() models.DetectionCategory {
+	return models.CategoryStorage
+}
+
+// Clone returns a detector with the same thresholds and no table history.
+func (d *AutovacuumStarvationDetector) Clone() Detector {
+	return &AutovacuumStarvationDetector{
+		autovacuumAgeSecs: d.autovacuumAgeSecs,
+		consecutiveCycles: d.consecutiveCycles,
+		minDeadTuples:     d.minDeadTuples,
+		growthRuns:        make(map[string]int),
+	}
+}
+
+func (d *AutovacuumStarvationDetector) Detect(snapshot *normaliser.NormalisedMetrics) *models.Detection {
+	if snapshot.MetricDeltas == nil || snapshot.ExtendedMetrics == nil {
+		return nil
+	}
+
+	// Skip the cycle without touching streaks, or a too-short interval shows no dead tuple growth
+	if !snapshot.DeltasReliable {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var worstTable string
+	var worstDeadTuples float64
+	var worstRun int
+
+	seen := make(map[string]bool)
+
+	for key, deadTuples := range snapshot.ExtendedMetrics {
+		if !strings.HasPrefix(key, "pg.table.") || !strings.HasSuffix(key, ".dead_tuples") {
+			continue
+		}
+
+		table := strings.TrimSuffix(strings.TrimPrefix(key, "pg.table."), ".dead_tuples")
+		seen[table] = true
+
+		delta, hasDelta := snapshot.MetricDeltas[key]
+		if !hasDelta || delta <= 0 {
+			delete(d.growthRuns, table)
+			continue
+		}
+
+		d.growthRuns[table]++
+		run := d.growthRuns[table]
+
+		if run < d.consecutiveCycles || deadTuples < d.minDeadTuples {
+			continue
+		}
+
+		if !d.autovacuumStale(snapshot, table) {
+			continue
+		}
+
+		if deadTuples > worstDeadTuples {
+			worstTable = table
+			worstDeadTuples = deadTuples
+			worstRun = run
+		}
+	}
+
+	// Tables that dropped out of the collected set lose their streak
+	for table := range d.growthRuns {
+		if !seen[table] {
+			delete(d.growthRuns, table)
+		}
+	}
+
+	if worstTable == "" {
+		return nil
+	}
+
+	prefixKey := fmt.Sprintf("pg.table.%s", worstTable)
+	lastAutovacuum := snapshot.Labels[prefixKey+".last_autovacuum"]
+	liveTuples := snapshot.ExtendedMetrics[prefixKey+".live_tuples"]
+	growth := snapshot.MetricDeltas[prefixKey+".dead_tuples"]
+
+	var severity models.DetectionSeverity
+	if worstRun >= d.consecutiveCycles*3 {
+		severity = models.SeverityCritical
+	} else if worstRun >= d.consecutiveCycles*2 {
+		severity = models.SeverityWarning
+	} else {
+		severity = models.SeverityInfo
+	}
+
+	detection := models.NewDetection(d.Name(), d.Category(), snapshot.DatabaseID)
+	detection.Severity = severity
+	detection.Value = worstDeadTuples
+	detection.Timestamp = snapshot.Timestamp
+
+	detection.Title = fmt.Sprintf("Autovacuum not keeping up on '%s'", worstTable)
+	detection.Description = fmt.Sprintf(
+		"Dead tuples on table '%s' have grown for %d consecutive collection cycles (now %.0f, +%.0f last cycle) "+
+			"and autovacuum last ran: %s. Autovacuum may be blocked by long transactions or throttled by cost limits.",
+		worstTable, worstRun, worstDeadTuples, growth, lastAutovacuum,
+	)
+
+	detection.Evidence = map[string]interface{}{
+		"table_name":          worstTable,
+		"dead_tuples":         worstDeadTuples,
+		"live_tuples":         liveTuples,
+		"dead_tuple_growth":   growth,
+		"consecutive_cycles":  worstRun,
+		"last_autovacuum":     lastAutovacuum,
+		"autovacuum_age_secs": d.autovacuumAgeSecs,
+	}
+
+	detection.Recommendation = fmt.Sprintf(
+		"Run VACUUM ANALYZE on '%s' now, then check for long-running or idle-in-transaction sessions "+
+			"holding back the xmin horizon, and consider lowering autovacuum_vacuum_scale_factor for this table.",
+		worstTable,
+	)
+
+	detection.ActionType = "vacuum_table"
+	detection.ActionMetadata = map[string]interface{}{
+		"table_name": worstTable,
+		"priority":   "medium",
+	}
+
+	return detection
+}
+
+// autovacuumStale reports whether the table's last autovacuum is older than the threshold (or never ran).
+func (d *AutovacuumStarvationDetector) autovacuumStale(snapshot *normaliser.NormalisedMetrics, table string) bool {
+	label, ok := snapshot.Labels[fmt.Sprintf("pg.table.%s.last_autovacuum", table)]
+	if !ok {
+		return false
+	}
+
+	if label == "never" {
+		return true
+	}
+
+	lastRun, err := time.Parse(time.RFC3339, label)
+	if err != nil {
+		return false
+	}
+
+	age := float64(snapshot.Timestamp - lastRun.Unix())
+	return age >= d.autovacuumAgeSecs
+}
+
+// SetThreshold sets how old (in seconds) the last autovacuum must be before firing.
+func (d *AutovacuumStarvationDetector) SetThreshold(autovacuumAgeSecs float64) {
+	d.autovacuumAgeSecs = autovacuumAgeSecs
+}
+
+// SetConsecutiveCycles sets how many cycles of dead tuple growth are required before firing.
+func (d *AutovacuumStarvationDetector) SetConsecutiveCycles(cycles int) {
+	if cycles < 1 {
+		cycles = 1
+	}
+	d.consecutiveCycles = cycles
+}
@@ -0,0 +1,152 @@
+package detector
+
+import (
+	"fmt"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+)
+
+// BlockingLockDetector fires when a session has held a lock other sessions are waiting on
+// for longer than the threshold, and asks for the blocker to be terminated. The longest
+// waiter's wait is a lower bound on how long the blocker has held the lock. It is the only
+// detector that sends a lock holder for termination; lock_contention reports the pileup.
+type BlockingLockDetector struct {
+	thresholdSecs float64
+
+	// Blockers matching these are never sent for termination, e.g. migrations
+	denyApplications []string
+	denyUsers        []string
+}
+
+func NewBlockingLockDetector() *BlockingLockDetector {
+	return &BlockingLockDetector{
+		thresholdSecs: 60.0,
+	}
+}
+
+func (d *BlockingLockDetector) Name() string {
+	return "blocking_lock"
+}
+
+func (d *BlockingLockDetector) Category() models.DetectionCategory {
+	return models.CategoryConnection
+}
+
+func (d *BlockingLockDetector) Detect(snapshot *normaliser.NormalisedMetrics) *models.Detection {
+	waitSecs, found := snapshot.ExtendedMetrics["pg.lock_wait_secs"]
+	if !found || waitSecs < d.thresholdSecs {
+		return nil
+	}
+
+	// Without a live blocker there is nothing to terminate; lock_contention covers the pileup
+	blockerPID := snapshot.Labels["pg.blocking_pid"]
+	if blockerPID == "" {
+		return nil
+	}
+
+	blockerUser := snapshot.Labels["pg.lock_blocker_user"]
+	blockerApplication := snapshot.Labels["pg.lock_blocker_application"]
+	blockerQuery := snapshot.Labels["pg.lock_blocker_query"]
+	relation := snapshot.Labels["pg.lock_wait_relation"]
+	blockedCount := snapshot.ExtendedMetrics["pg.blocked_count"]
+
+	var severity models.DetectionSeverity
+	if waitSecs >= 300 { // 5 minutes
+		severity = models.SeverityCritical
+	} else if waitSecs >= 180 { // 3 minutes
+		severity = models.SeverityWarning
+	} else {
+		severity = models.SeverityInfo
+	}
+
+	detection := models.NewDetection(d.Name(), d.Category(), snapshot.DatabaseID)
+	detection.Severity = severity
+	detection.Value = waitSecs
+	detection.Timestamp = snapshot.Timestamp
+
+	detection.Title = fmt.Sprintf("Session %s has blocked others for %.0fs", blockerPID, waitSecs)
+	detection.Description = fmt.Sprintf(
+		"Session %s (user '%s') holds a lock that %.0f session(s) are waiting on; the longest has waited %.0fs "+
+			"(threshold: %.0fs). Blocker's last query: %s",
+		blockerPID, blockerUser, blockedCount, waitSecs, d.thresholdSecs, blockerQuery,
+	)
+
+	detection.Evidence = map[string]interface{}{
+		"blocker_pid":         blockerPID,
+		"blocker_user":        blockerUser,
+		"blocker_application": blockerApplication,
+		"blocker_query":       blockerQuery,
+		"blocker_state":       snapshot.Labels["pg.lock_blocker_state"],
+		"blocked_count":       blockedCount,
+		"lock_wait_secs":      waitSecs,
+		"threshold_secs":      d.thresholdSecs,
+		"blocked_pid":         snapshot.Labels["pg.lock_wait_pid"],
+		"blocked_query":       snapshot.Labels["pg.lock_wait_query"],
+		"blocked_relation":    relation,
+	}
+
+	if reason := protectedSessionReason(blockerApplication, blockerUser, d.denyApplications, d.denyUsers); reason != "" {
+		return d.protectedBlockerDetection(detection, blockerPID, reason)
+	}
+
+	detection.Recommendation = fmt.Sprintf(
+		"Terminate the blocking session (PID %s, user '%s') to release its locks, then find out why it holds them "+
+			"for so long - typically a transaction left open or a long statement on a hot table.",
+		blockerPID, blockerUser,
+	)
+
+	detection.ActionType = "terminate_query"
+	detection.ActionMetadata = map[string]interface{}{
+		"pid":              blockerPID,
+		"username":         blockerUser,
+		"application_name": blockerApplication,
+		"query":            blockerQuery, // The blocker's current or last statement
+		"graceful":         false,        // Cancelling the statement would leave an open transaction holding the lock
+	}
+
+	return detection
+}
+
+// protectedBlockerDetection turns the detection into a recommendation for a blocker on the
+// denylist, so the Executor is never asked to terminate it.
+func (d *BlockingLockDetector) protectedBlockerDetection(detection *models.Detection, pid, reason string) *models.Detection {
+	detection.Evidence["protected"] = reason
+
+	detection.Recommendation = fmt.Sprintf(
+		"The blocking session (PID %s) is protected (%s) and will not be terminated automatically. "+
+			"Check whether it is still making progress before ending it by hand.",
+		pid, reason,
+	)
+
+	detection.ActionType = "deadlock_investigation_recommendation"
+	detection.ActionMetadata = map[string]interface{}{
+		"priority": string(detection.Severity),
+		"pid":      pid,
+		"safe_option": map[string]interface{}{
+			"title":            "Review protected blocking session",
+			"description":      detection.Recommendation,
+			"risk_level":       "safe",
+			"requires_restart": false,
+			"steps": []string{
+				fmt.Sprintf("Inspect the session: SELECT * FROM pg_stat_activity WHERE pid = %s;", pid),
+				"Confirm with the owning team whether it is still needed",
+				fmt.Sprintf("If it is stuck, end it manually: SELECT pg_terminate_backend(%s);", pid),
+			},
+		},
+	}
+
+	return detection
+}
+
+// SetThreshold sets how long, in seconds, a lock must have blocked others before firing.
+func (d *BlockingLockDetector) SetThreshold(thresholdSecs float64) {
+	d.thresholdSecs = thresholdSecs
+}
+
+// SetDenylist sets the application_name and username patterns (globs such as "*migrat*")
+// whose blocking sessions are reported without a terminate action.
+func (d *BlockingLockDetector) SetDenylist(applications, users []string) {
+	d.denyApplications = applications
+	d.denyUsers = users
+}
@@ -48,6 +48,7 @@ func (d *CacheMissDetector) Detect(snapshot *normaliser.NormalisedMetrics) *mode
 
 	detection := models.NewDetection(d.Name(), d.Category(), snapshot.DatabaseID)
 	detection.Severity = severity
+	detection.Value = hitRate
 	detection.Timestamp = snapshot.Timestamp
 
 	hitPercent := int(hitRate * 100)
@@ -70,7 +71,6 @@ func (d *CacheMissDetector) Detect(snapshot *normaliser.NormalisedMetrics) *mode
 
 	detection.Recommendation = d.getRecommendation(snapshot.DatabaseType, hitRate)
 
-	// Changed from "increase_cache_size" to "cache_optimization_recommendation"
 	detection.ActionType = "cache_optimization_recommendation"
 	detection.ActionMetadata = map[string]interface{}{
 		"priority":         "medium",
@@ -99,6 +99,13 @@ func (d *CacheMissDetector) Detect(snapshot *normaliser.NormalisedMetrics) *mode
 		},
 	}
 
+	// A containerized Postgres can be restarted by the Executor, so the safe option
+	// becomes an action instead of a list of manual steps
+	if container := snapshot.Labels[normaliser.LabelContainerName]; container != "" && isPostgres(snapshot.DatabaseType) {
+		detection.ActionType = "increase_cache_size"
+		detection.ActionMetadata["container_name"] = container
+	}
+
 	return detection
 }
 
@@ -244,6 +251,11 @@ func (d *CacheMissDetector) getRecommendation(dbType string, hitRate float64) st
 	}
 }
 
+// isPostgres reports whether the database type is PostgreSQL.
+func isPostgres(dbType string) bool {
+	return dbType == "postgres" || dbType == "postgresql"
+}
+
 func (d *CacheMissDetector) SetThreshold(threshold float64) {
 	d.hitRateThreshold = threshold
 }
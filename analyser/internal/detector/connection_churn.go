@@ -0,0 +1,105 @@
+package detector
+
+import (
+	"fmt"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+)
+
+// ConnectionChurnDetector fires when clients open new connections faster than the threshold.
+// Each one costs a backend process and authentication, so heavy churn is worth a pooler even
+// when connection_pool_exhaustion sees plenty of headroom in any one snapshot. It needs
+// PostgreSQL 14, the first to count sessions in pg_stat_database; the Collector lists
+// session_stats as unavailable on older servers.
+type ConnectionChurnDetector struct {
+	threshold float64 // New connections per second
+}
+
+func NewConnectionChurnDetector() *ConnectionChurnDetector {
+	return &ConnectionChurnDetector{
+		threshold: 10.0, // Info; Warning at 2x, Critical at 4x
+	}
+}
+
+func (d *ConnectionChurnDetector) Name() string {
+	return "connection_churn"
+}
+
+func (d *ConnectionChurnDetector) Category() models.DetectionCategory {
+	return models.CategoryConnection
+}
+
+func (d *ConnectionChurnDetector) Detect(snapshot *normaliser.NormalisedMetrics) *models.Detection {
+	if !snapshot.DeltasReliable || snapshot.TimeDeltaSeconds <= 0 {
+		return nil
+	}
+
+	sessions, found := snapshot.MetricDeltas["sessions"]
+	if !found {
+		return nil
+	}
+
+	rate := sessions / snapshot.TimeDeltaSeconds
+	if rate < d.threshold {
+		return nil
+	}
+
+	var severity models.DetectionSeverity
+	if rate >= d.threshold*4 {
+		severity = models.SeverityCritical
+	} else if rate >= d.threshold*2 {
+		severity = models.SeverityWarning
+	} else {
+		severity = models.SeverityInfo
+	}
+
+	detection := models.NewDetection(d.Name(), d.Category(), snapshot.DatabaseID)
+	detection.Severity = severity
+	detection.Value = rate
+	detection.Timestamp = snapshot.Timestamp
+
+	detection.Title = fmt.Sprintf("%.1f new connections per second", rate)
+	detection.Description = fmt.Sprintf(
+		"Clients opened %.0f connections in the last %.0fs (%.1f/s, threshold: %.1f/s). Every new connection "+
+			"forks a backend and authenticates, which costs more than the short work most of them do.",
+		sessions, snapshot.TimeDeltaSeconds, rate, d.threshold,
+	)
+
+	detection.Evidence = map[string]interface{}{
+		"new_connections":     sessions,
+		"interval_secs":       snapshot.TimeDeltaSeconds,
+		"connections_per_sec": rate,
+		"threshold_per_sec":   d.threshold,
+	}
+
+	// Few transactions per connection means clients connect per request rather than pooling
+	if transactions, found := snapshot.MetricDeltas["transactions"]; found && sessions > 0 {
+		detection.Evidence["transactions_per_connection"] = transactions / sessions
+	}
+	if snapshot.Measurements.ActiveConnections != nil {
+		detection.Evidence["active_connections"] = int(*snapshot.Measurements.ActiveConnections)
+	}
+	if snapshot.Measurements.IdleConnections != nil {
+		detection.Evidence["idle_connections"] = int(*snapshot.Measurements.IdleConnections)
+	}
+
+	detection.Recommendation = "Put a connection pooler such as PgBouncer in front of the database so clients reuse " +
+		"server connections, or enable connection pooling in the application's database driver."
+
+	// For Executor
+	detection.ActionType = "deploy_connection_pooler"
+	detection.ActionMetadata = map[string]interface{}{
+		"priority":            string(severity),
+		"database_type":       snapshot.DatabaseType,
+		"recommended_tool":    "pgbouncer",
+		"connections_per_sec": rate,
+	}
+
+	return detection
+}
+
+// SetThreshold sets the new connections per second at which an Info detection fires.
+func (d *ConnectionChurnDetector) SetThreshold(perSec float64) {
+	d.threshold = perSec
+}
@@ -2,6 +2,8 @@ package detector
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/models"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
@@ -9,11 +11,18 @@ import (
 
 type ConnectionPoolDetector struct {
 	usageThreshold float64
+
+	// Idle saturation: idle connections hold at least idleThreshold of max_connections
+	// and make up at least idleShareThreshold of all open connections
+	idleThreshold      float64
+	idleShareThreshold float64
 }
 
 func NewConnectionPoolDetection() *ConnectionPoolDetector {
 	return &ConnectionPoolDetector{
-		usageThreshold: 0.25,
+		usageThreshold:     0.25,
+		idleThreshold:      0.5,
+		idleShareThreshold: 0.7,
 	}
 }
 
@@ -39,6 +48,14 @@ func (d *ConnectionPoolDetector) Detect(snapshot *normaliser.NormalisedMetrics)
 
 	usageRatio := active / max
 
+	// Idle connections from a leaking app need fixing at the source, not a pooler
+	if snapshot.Measurements.IdleConnections != nil {
+		idle := float64(*snapshot.Measurements.IdleConnections)
+		if detection := d.detectIdleSaturation(snapshot, active, idle, max); detection != nil {
+			return detection
+		}
+	}
+
 	// Check if below threshold
 	if usageRatio < d.usageThreshold {
 		return nil
@@ -56,6 +73,7 @@ func (d *ConnectionPoolDetector) Detect(snapshot *normaliser.NormalisedMetrics)
 
 	detection := models.NewDetection(d.Name(), d.Category(), snapshot.DatabaseID)
 	detection.Severity = severity
+	detection.Value = usageRatio
 	detection.Timestamp = snapshot.Timestamp
 
 	usagePercentage := int(usageRatio * 100)
@@ -75,6 +93,12 @@ func (d *ConnectionPoolDetector) Detect(snapshot *normaliser.NormalisedMetrics)
 		"connection_health":  snapshot.ConnectionHealth,
 	}
 
+	if snapshot.Measurements.IdleConnections != nil {
+		idle := float64(*snapshot.Measurements.IdleConnections)
+		detection.Evidence["idle_connections"] = int(idle)
+		detection.Evidence["idle_ratio"] = idle / max
+	}
+
 	detection.Recommendation = d.getRecommendation(snapshot.DatabaseType, usagePercentage)
 	// For Executor
 	detection.ActionType = "deploy_connection_pooler"
@@ -88,6 +112,126 @@ func (d *ConnectionPoolDetector) Detect(snapshot *normaliser.NormalisedMetrics)
 	return detection
 }
 
+// detectIdleSaturation fires when idle connections, rather than active work, are filling the pool.
+func (d *ConnectionPoolDetector) detectIdleSaturation(snapshot *normaliser.NormalisedMetrics, active, idle, max float64) *models.Detection {
+	if idle == 0 {
+		return nil
+	}
+
+	idleRatio := idle / max
+	idleShare := idle / (active + idle)
+	if idleRatio < d.idleThreshold || idleShare < d.idleShareThreshold {
+		return nil
+	}
+
+	usageRatio := active / max
+	openRatio := (active + idle) / max
+
+	var severity models.DetectionSeverity
+	if openRatio >= 0.95 {
+		severity = models.SeverityCritical
+	} else if openRatio >= 0.85 {
+		severity = models.SeverityWarning
+	} else {
+		severity = models.SeverityInfo
+	}
+
+	apps := parseApplicationCounts(snapshot.Labels["pg.idle_connection_apps"])
+	topApps := make([]string, 0, len(apps))
+	for _, app := range apps {
+		topApps = append(topApps, fmt.Sprintf("%s (%d)", app.name, app.count))
+	}
+	offenders := "unknown"
+	if len(topApps) > 0 {
+		offenders = strings.Join(topApps, ", ")
+	}
+
+	detection := models.NewDetection(d.Name(), d.Category(), snapshot.DatabaseID)
+	detection.Severity = severity
+	detection.Value = idleRatio
+	detection.Timestamp = snapshot.Timestamp
+
+	idlePercentage := int(idleRatio * 100)
+	detection.Title = fmt.Sprintf("Idle connections holding %d%% of connection pool", idlePercentage)
+	detection.Description = fmt.Sprintf(
+		"%d of %d available connections are idle while only %d are active. "+
+			"An application is opening connections and not returning them. "+
+			"Top idle connections by application: %s",
+		int(idle), int(max), int(active), offenders,
+	)
+
+	appCounts := make(map[string]interface{}, len(apps))
+	for _, app := range apps {
+		appCounts[app.name] = app.count
+	}
+
+	detection.Evidence = map[string]interface{}{
+		"active_connections": int(active),
+		"idle_connections":   int(idle),
+		"max_connections":    int(max),
+		"usage_ratio":        usageRatio,
+		"idle_ratio":         idleRatio,
+		"idle_share":         idleShare,
+		"idle_by_app":        appCounts,
+		"connection_health":  snapshot.ConnectionHealth,
+	}
+
+	detection.Recommendation = fmt.Sprintf(
+		"Fix connection handling in the applications holding idle connections (%s): close connections after use "+
+			"and cap the client-side pool size. Setting idle_session_timeout will reclaim leaked connections meanwhile. "+
+			"A connection pooler only hides the leak.",
+		offenders,
+	)
+
+	steps := []string{
+		fmt.Sprintf("Check connection handling in: %s", offenders),
+		"Lower the application's connection pool max size and idle timeout",
+		"Set idle_session_timeout (PostgreSQL 14+) to close connections left idle",
+		"Monitor idle connections in Dashboard",
+	}
+
+	detection.ActionType = "idle_connection_cleanup"
+	detection.ActionMetadata = map[string]interface{}{
+		"priority":      "high",
+		"database_type": snapshot.DatabaseType,
+		"identifier":    "idle_connections",
+		"idle_percent":  idlePercentage,
+		"applications":  topApps,
+
+		"safe_option": map[string]interface{}{
+			"title":            "Fix idle connection leak",
+			"description":      detection.Description,
+			"risk_level":       "safe",
+			"requires_restart": false,
+			"steps":            steps,
+		},
+	}
+
+	return detection
+}
+
+type applicationCount struct {
+	name  string
+	count int
+}
+
+// parseApplicationCounts decodes the collector's "app=count,app=count" label.
+func parseApplicationCounts(label string) []applicationCount {
+	var apps []applicationCount
+	for _, pair := range strings.Split(label, ",") {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			continue
+		}
+		apps = append(apps, applicationCount{name: name, count: count})
+	}
+	return apps
+}
+
 func (d *ConnectionPoolDetector) getRecommendation(dbType string, usagePercent int) string {
 	switch dbType {
 	case "postgres", "postgresql":
@@ -139,3 +283,10 @@ func (d *ConnectionPoolDetector) calculateRecommendedPoolSize(usagePercent int)
 func (d *ConnectionPoolDetector) SetThreshold(threshold float64) {
 	d.usageThreshold = threshold
 }
+
+// SetIdleThresholds sets when idle connections count as saturating the pool: idle/max must
+// reach idleThreshold and idle/(active+idle) must reach idleShareThreshold.
+func (d *ConnectionPoolDetector) SetIdleThresholds(idleThreshold, idleShareThreshold float64) {
+	d.idleThreshold = idleThreshold
+	d.idleShareThreshold = idleShareThreshold
+}
@@ -0,0 +1,134 @@
+package detector
+
+import (
+	"fmt"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+)
+
+type DeadlockDetector struct {
+	threshold float64 // Deadlocks per collection interval
+}
+
+func NewDeadlockDetector() *DeadlockDetector {
+	return &DeadlockDetector{
+		threshold: 0, // Any deadlock is worth surfacing
+	}
+}
+
+func (d *DeadlockDetector) Name() string {
+	return "deadlock"
+}
+
+func (d *DeadlockDetector) Category() models.DetectionCategory {
+	return models.CategoryQuery
+}
+
+func (d *DeadlockDetector) Detect(snapshot *normaliser.NormalisedMetrics) *models.Detection {
+	// An unreliable delta can't be turned into a rate; the next cycle will have one
+	if snapshot.MetricDeltas == nil || !snapshot.DeltasReliable {
+		return nil
+	}
+
+	delta, found := snapshot.MetricDeltas["deadlocks"]
+	if !found || delta <= d.threshold {
+		return nil
+	}
+
+	// Rate normalises for differing collection intervals
+	var ratePerMin float64
+	if snapshot.TimeDeltaSeconds > 0 {
+		ratePerMin = delta / snapshot.TimeDeltaSeconds * 60
+	}
+
+	var severity models.DetectionSeverity
+	if ratePerMin >= 5 {
+		severity = models.SeverityCritical
+	} else if ratePerMin >= 1 {
+		severity = models.SeverityWarning
+	} else {
+		severity = models.SeverityInfo
+	}
+
+	detection := models.NewDetection(d.Name(), d.Category(), snapshot.DatabaseID)
+	detection.Severity = severity
+	detection.Value = ratePerMin
+	detection.Timestamp = snapshot.Timestamp
+
+	detection.Title = fmt.Sprintf("%.0f deadlock(s) detected since last collection", delta)
+	detection.Description = fmt.Sprintf(
+		"PostgreSQL resolved %.0f deadlock(s) in the last %.0fs (%.2f/min) by aborting one of the transactions involved. "+
+			"Deadlocks indicate transactions acquiring locks on the same rows or tables in conflicting order.",
+		delta, snapshot.TimeDeltaSeconds, ratePerMin,
+	)
+
+	detection.Evidence = map[string]interface{}{
+		"deadlock_delta":     delta,
+		"deadlocks_per_min":  ratePerMin,
+		"interval_secs":      snapshot.TimeDeltaSeconds,
+		"threshold":          d.threshold,
+		"lock_waiting_count": snapshot.ExtendedMetrics["pg.blocked_count"],
+	}
+
+	// pg_stat_activity evidence is only present if a backend was blocked at collection time
+	relation := snapshot.Labels["pg.lock_wait_relation"]
+	query := snapshot.Labels["pg.lock_wait_query"]
+	if pid, ok := snapshot.Labels["pg.lock_wait_pid"]; ok {
+		detection.Evidence["blocked_pid"] = pid
+		detection.Evidence["blocked_user"] = snapshot.Labels["pg.lock_wait_user"]
+		detection.Evidence["blocked_query"] = query
+		detection.Evidence["blocked_relation"] = relation
+		detection.Evidence["blocked_duration_secs"] = snapshot.ExtendedMetrics["pg.lock_wait_secs"]
+	}
+
+	detection.Recommendation = d.getRecommendation(relation, query)
+
+	// Recommendation only - deadlocks require application changes
+	detection.ActionType = "deadlock_investigation_recommendation"
+	detection.ActionMetadata = map[string]interface{}{
+		"priority":      string(severity),
+		"database_type": snapshot.DatabaseType,
+		"safe_option": map[string]interface{}{
+			"title":            "Investigate Deadlocking Transactions",
+			"description":      detection.Recommendation,
+			"risk_level":       "safe",
+			"requires_restart": false,
+			"steps":            d.getInvestigationSteps(relation),
+		},
+	}
+
+	return detection
+}
+
+func (d *DeadlockDetector) getRecommendation(relation, query string) string {
+	if relation != "" {
+		return fmt.Sprintf(
+			"Transactions are contending for locks on table '%s' (e.g. %s). "+
+				"Ensure all code paths lock rows in a consistent order and keep transactions short.",
+			relation, query,
+		)
+	}
+
+	return "Enable log_lock_waits and review the PostgreSQL log for the deadlock report, which lists the queries involved. " +
+		"Ensure all code paths lock rows in a consistent order and keep transactions short."
+}
+
+func (d *DeadlockDetector) getInvestigationSteps(relation string) []string {
+	steps := []string{
+		"Set log_lock_waits = on and review the deadlock details in the PostgreSQL log",
+		"Query pg_stat_activity WHERE wait_event_type = 'Lock' to see currently blocked sessions",
+		"Join pg_locks to pg_stat_activity to identify which sessions hold the conflicting locks",
+	}
+
+	if relation != "" {
+		steps = append(steps, fmt.Sprintf("Review application transactions that update '%s' for inconsistent lock ordering", relation))
+	}
+
+	return append(steps, "Use SELECT ... FOR UPDATE in a consistent order, or retry aborted transactions in the application")
+}
+
+// SetThreshold sets the number of deadlocks per collection interval that must be exceeded.
+func (d *DeadlockDetector) SetThreshold(threshold float64) {
+	d.threshold = threshold
+}
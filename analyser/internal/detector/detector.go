@@ -12,3 +12,21 @@ type Detector interface {
 
 	Category() models.DetectionCategory
 }
+
+// StatefulDetector is implemented by detectors that remember earlier snapshots. The engine
+// clones one per database so history from one database never feeds another's detections.
+type StatefulDetector interface {
+	Detector
+
+	// Clone returns a detector with the same configuration and no history.
+	Clone() Detector
+}
+
+// MultiDetector is implemented by detectors that can find several issues in one snapshot,
+// e.g. one missing index per table. The engine calls DetectMany instead of Detect.
+type MultiDetector interface {
+	Detector
+
+	// DetectMany returns every issue found; each needs its own deduplication key.
+	DetectMany(snapshot *normaliser.NormalisedMetrics) []*models.Detection
+}
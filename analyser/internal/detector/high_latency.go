@@ -7,6 +7,13 @@ import (
 	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
 )
 
+// Which queries the slow query report covers: those averaging over the threshold, at most
+// the limit of them before grouping
+const (
+	slowQueryReportThresholdMs = 500.0
+	slowQueryReportLimit       = 20
+)
+
 type HighLatencyDetector struct {
 	p95LatencyThreshold float64
 }
@@ -56,6 +63,7 @@ func (d *HighLatencyDetector) Detect(snapshot *normaliser.NormalisedMetrics) *mo
 
 	detection := models.NewDetection(d.Name(), d.Category(), snapshot.DatabaseID)
 	detection.Severity = severity
+	detection.Value = latency
 	detection.Timestamp = snapshot.Timestamp
 
 	detection.Title = fmt.Sprintf("High query latency detected (%.0fms %s)", latency, latencyType)
@@ -97,6 +105,10 @@ func (d *HighLatencyDetector) Detect(snapshot *normaliser.NormalisedMetrics) *mo
 		"database_type":  snapshot.DatabaseType,
 		"p95_latency_ms": latency,
 		"threshold_ms":   d.p95LatencyThreshold,
+		// The Executor reports slow queries instead where the database's config can't be tuned
+		"fallback_action_type":    "report_slow_queries",
+		"slow_query_threshold_ms": slowQueryReportThresholdMs,
+		"slow_query_limit":        slowQueryReportLimit,
 	}
 
 	// Include avg latency if available
@@ -116,8 +128,8 @@ func (d *HighLatencyDetector) getRecommendation(dbType string) string {
 		return "StartupMonkey will tune MySQL configuration (innodb_buffer_pool_size, tmp_table_size) " +
 			"to improve query performance and identify slow queries that require optimization."
 	case "mongodb":
-		return "StartupMonkey will tune MongoDB configuration (wiredTigerCacheSizeGB) " +
-			"to improve query performance and identify slow queries that require optimization."
+		return "StartupMonkey will report the slowest operations from the MongoDB profiler, " +
+			"grouped by shape, with the indexes or query changes they need."
 	case "sqlite":
 		return "StartupMonkey will tune SQLite configuration (cache_size, journal_mode) " +
 			"to improve query performance. For high-traffic applications, consider migrating to PostgreSQL or MySQL."
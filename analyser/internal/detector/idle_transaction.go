@@ -2,6 +2,8 @@ package detector
 
 import (
 	"fmt"
+	"path"
+	"strings"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/models"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
@@ -9,6 +11,10 @@ import (
 
 type IdleTransactionDetector struct {
 	thresholdSecs float64
+
+	// Sessions matching these are never sent for termination, e.g. migrations
+	denyApplications []string
+	denyUsers        []string
 }
 
 func NewIdleTransactionDetector() *IdleTransactionDetector {
@@ -37,6 +43,7 @@ func (d *IdleTransactionDetector) Detect(snapshot *normaliser.NormalisedMetrics)
 	}
 
 	username := snapshot.Labels["pg.idle_txn_user"]
+	application := snapshot.Labels["pg.idle_txn_application"]
 	query := snapshot.Labels["pg.idle_txn_query"]
 
 	var severity models.DetectionSeverity
@@ -50,6 +57,7 @@ func (d *IdleTransactionDetector) Detect(snapshot *normaliser.NormalisedMetrics)
 
 	detection := models.NewDetection(d.Name(), d.Category(), snapshot.DatabaseID)
 	detection.Severity = severity
+	detection.Value = duration
 	detection.Timestamp = snapshot.Timestamp
 
 	durationMins := duration / 60
@@ -64,11 +72,16 @@ func (d *IdleTransactionDetector) Detect(snapshot *normaliser.NormalisedMetrics)
 	detection.Evidence = map[string]interface{}{
 		"pid":                pid,
 		"username":           username,
+		"application_name":   application,
 		"query":              query,
 		"idle_duration_secs": duration,
 		"idle_duration_mins": durationMins,
 	}
 
+	if reason := protectedSessionReason(application, username, d.denyApplications, d.denyUsers); reason != "" {
+		return d.protectedSessionDetection(detection, pid, reason)
+	}
+
 	detection.Recommendation = fmt.Sprintf(
 		"Terminate the idle connection (PID %s) to release locks and free the connection slot. "+
 			"Investigate the application code to ensure transactions are properly committed or rolled back.",
@@ -77,14 +90,76 @@ func (d *IdleTransactionDetector) Detect(snapshot *normaliser.NormalisedMetrics)
 
 	detection.ActionType = "terminate_query"
 	detection.ActionMetadata = map[string]interface{}{
+		"pid":              pid,
+		"username":         username,
+		"application_name": application,
+		"query":            query, // Last statement of the open transaction
+		"graceful":         false, // Idle transactions should be terminated, not cancelled
+	}
+
+	return detection
+}
+
+// protectedSessionDetection turns the detection into a recommendation for a session on the
+// denylist, so the Executor is never asked to terminate it.
+func (d *IdleTransactionDetector) protectedSessionDetection(detection *models.Detection, pid, reason string) *models.Detection {
+	detection.Evidence["protected"] = reason
+
+	detection.Recommendation = fmt.Sprintf(
+		"The idle session (PID %s) is protected (%s) and will not be terminated automatically. "+
+			"Check whether it is still doing work and commit or roll back the transaction from the application.",
+		pid, reason,
+	)
+
+	detection.ActionType = "idle_transaction_recommendation"
+	detection.ActionMetadata = map[string]interface{}{
+		"priority": "medium",
 		"pid":      pid,
-		"username": username,
-		"graceful": false, // Idle transactions should be terminated, not cancelled
+		"safe_option": map[string]interface{}{
+			"title":            "Review protected idle transaction",
+			"description":      detection.Recommendation,
+			"risk_level":       "safe",
+			"requires_restart": false,
+			"steps": []string{
+				fmt.Sprintf("Inspect the session: SELECT * FROM pg_stat_activity WHERE pid = %s;", pid),
+				"Confirm with the owning team whether the transaction is still needed",
+				fmt.Sprintf("If it is abandoned, end it manually: SELECT pg_terminate_backend(%s);", pid),
+			},
+		},
 	}
 
 	return detection
 }
 
+// protectedSessionReason returns why a session must not be terminated, or "" if it may be.
+func protectedSessionReason(application, username string, denyApplications, denyUsers []string) string {
+	if application != "" && matchesAnyPattern(application, denyApplications) {
+		return fmt.Sprintf("application '%s' is on the denylist", application)
+	}
+	if username != "" && matchesAnyPattern(username, denyUsers) {
+		return fmt.Sprintf("user '%s' is on the denylist", username)
+	}
+	return ""
+}
+
+// matchesAnyPattern reports whether value matches one of the case-insensitive glob patterns.
+func matchesAnyPattern(value string, patterns []string) bool {
+	value = strings.ToLower(value)
+	for _, pattern := range patterns {
+		if matched, err := path.Match(strings.ToLower(pattern), value); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
 func (d *IdleTransactionDetector) SetThreshold(thresholdSecs float64) {
 	d.thresholdSecs = thresholdSecs
 }
+
+// SetDenylist sets the application_name and username patterns (globs such as "*migrat*")
+// whose sessions are reported without a terminate action.
+func (d *IdleTransactionDetector) SetDenylist(applications, users []string) {
+	d.denyApplications = applications
+	d.denyUsers = users
+}
@@ -0,0 +1,165 @@
+package detector
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+)
+
+// LockContentionDetector fires when more backends than the threshold are blocked on
+// locks for consecutive collection cycles - a lock pileup rather than a saturated pool.
+// It recommends investigation; BlockingLockDetector owns terminating the blocker.
+type LockContentionDetector struct {
+	waitingThreshold  float64 // Waiting connections that must be exceeded
+	consecutiveCycles int     // Cycles above the threshold required
+
+	mu  sync.Mutex
+	run int // consecutive cycles above the threshold
+}
+
+func NewLockContentionDetector() *LockContentionDetector {
+	return &LockContentionDetector{
+		waitingThreshold:  5,
+		consecutiveCycles: 3,
+	}
+}
+
+func (d *LockContentionDetector) Name() string {
+	return "lock_contention"
+}
+
+func (d *LockContentionDetector) Category() models.DetectionCategory {
+	return models.CategoryConnection
+}
+
+// Clone returns a detector with the same thresholds and no history.
+func (d *LockContentionDetector) Clone() Detector {
+	return &LockContentionDetector{
+		waitingThreshold:  d.waitingThreshold,
+		consecutiveCycles: d.consecutiveCycles,
+	}
+}
+
+func (d *LockContentionDetector) Detect(snapshot *normaliser.NormalisedMetrics) *models.Detection {
+	if snapshot.Measurements.WaitingConnections == nil {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	waiting := float64(*snapshot.Measurements.WaitingConnections)
+	if waiting <= d.waitingThreshold {
+		d.run = 0
+		return nil
+	}
+
+	d.run++
+	if d.run < d.consecutiveCycles {
+		return nil
+	}
+
+	var severity models.DetectionSeverity
+	if waiting > d.waitingThreshold*4 {
+		severity = models.SeverityCritical
+	} else if waiting > d.waitingThreshold*2 {
+		severity = models.SeverityWarning
+	} else {
+		severity = models.SeverityInfo
+	}
+
+	longestWait := snapshot.ExtendedMetrics["pg.lock_wait_secs"]
+	relation := snapshot.Labels["pg.lock_wait_relation"]
+	blockerPID := snapshot.Labels["pg.blocking_pid"]
+
+	detection := models.NewDetection(d.Name(), d.Category(), snapshot.DatabaseID)
+	detection.Severity = severity
+	detection.Value = waiting
+	detection.Timestamp = snapshot.Timestamp
+
+	detection.Title = fmt.Sprintf("%.0f connections waiting on locks", waiting)
+	detection.Description = fmt.Sprintf(
+		"%.0f backends have been blocked on locks (threshold: %.0f) for %d consecutive collection cycles; "+
+			"the longest has waited %.0fs. Blocked sessions hold their connections, so a lock pileup "+
+			"looks like pool exhaustion to the application.",
+		waiting, d.waitingThreshold, d.run, longestWait,
+	)
+
+	detection.Evidence = map[string]interface{}{
+		"waiting_connections": waiting,
+		"threshold":           d.waitingThreshold,
+		"consecutive_cycles":  d.run,
+		"longest_wait_secs":   longestWait,
+	}
+
+	if pid, ok := snapshot.Labels["pg.lock_wait_pid"]; ok {
+		detection.Evidence["blocked_pid"] = pid
+		detection.Evidence["blocked_query"] = snapshot.Labels["pg.lock_wait_query"]
+		detection.Evidence["blocked_relation"] = relation
+	}
+	if pids, ok := snapshot.Labels["pg.lock_wait_blocking_pids"]; ok {
+		detection.Evidence["blocking_pids"] = strings.Split(pids, ",")
+	}
+
+	steps := []string{
+		"Query pg_stat_activity WHERE wait_event_type = 'Lock' to list blocked sessions",
+		"Run SELECT pg_blocking_pids(pid) for the longest waiter to find the lock holder",
+		"Check whether the holder is idle in transaction or running a long statement",
+	}
+	detection.Recommendation = "Query pg_stat_activity joined to pg_blocking_pids() to find the session holding the lock, " +
+		"then shorten or split the transaction that holds it."
+
+	// Terminating the blocker is left to blocking_lock, which fires once it has held the
+	// lock past its threshold, so a blocker is never sent for termination twice
+	if blockerPID != "" {
+		blockerUser := snapshot.Labels["pg.lock_blocker_user"]
+
+		detection.Evidence["blocker_pid"] = blockerPID
+		detection.Evidence["blocker_user"] = blockerUser
+		detection.Evidence["blocker_query"] = snapshot.Labels["pg.lock_blocker_query"]
+		detection.Evidence["blocker_state"] = snapshot.Labels["pg.lock_blocker_state"]
+
+		detection.Recommendation = fmt.Sprintf(
+			"Session %s (user '%s') holds the lock the longest waiter is blocked on. Find out why it holds it for "+
+				"so long - typically a transaction left open or a long statement on a hot table - and shorten or "+
+				"split that transaction.",
+			blockerPID, blockerUser,
+		)
+		steps = []string{
+			fmt.Sprintf("Inspect the blocking session: SELECT * FROM pg_stat_activity WHERE pid = %s;", blockerPID),
+			"Check whether it is idle in transaction or running a long statement",
+			"Query pg_stat_activity WHERE wait_event_type = 'Lock' to see who else is queued behind it",
+		}
+	}
+
+	detection.ActionType = "deadlock_investigation_recommendation"
+	detection.ActionMetadata = map[string]interface{}{
+		"priority":      string(severity),
+		"database_type": snapshot.DatabaseType,
+		"safe_option": map[string]interface{}{
+			"title":            "Find the Blocking Session",
+			"description":      detection.Recommendation,
+			"risk_level":       "safe",
+			"requires_restart": false,
+			"steps":            steps,
+		},
+	}
+
+	return detection
+}
+
+// SetThreshold sets the number of waiting connections that must be exceeded.
+func (d *LockContentionDetector) SetThreshold(waiting float64) {
+	d.waitingThreshold = waiting
+}
+
+// SetConsecutiveCycles sets how many cycles above the threshold are required before firing.
+func (d *LockContentionDetector) SetConsecutiveCycles(cycles int) {
+	if cycles < 1 {
+		cycles = 1
+	}
+	d.consecutiveCycles = cycles
+}
@@ -50,6 +50,7 @@ func (d *LongRunningQueryDetector) Detect(snapshot *normaliser.NormalisedMetrics
 
 	detection := models.NewDetection(d.Name(), d.Category(), snapshot.DatabaseID)
 	detection.Severity = severity
+	detection.Value = duration
 	detection.Timestamp = snapshot.Timestamp
 
 	detection.Title = fmt.Sprintf("Long-running query detected (%.0fs)", duration)
@@ -78,7 +79,8 @@ func (d *LongRunningQueryDetector) Detect(snapshot *normaliser.NormalisedMetrics
 	detection.ActionMetadata = map[string]interface{}{
 		"pid":      pid,
 		"username": username,
-		"graceful": true, // pg_cancel_backend first, pg_terminate_backend if needed
+		"query":    queryText, // The statement that has run past the threshold
+		"graceful": true,      // pg_cancel_backend first, pg_terminate_backend if needed
 	}
 
 	return detection
@@ -29,22 +29,29 @@ func (d *MissingIndexDetector) Category() models.DetectionCategory {
 }
 
 func (d *MissingIndexDetector) Detect(snapshot *normaliser.NormalisedMetrics) *models.Detection {
+	if detections := d.DetectMany(snapshot); len(detections) > 0 {
+		return detections[0]
+	}
+	return nil
+}
+
+// DetectMany reports one detection per candidate table the collector recommended columns
+// for, most scanned first, so several unindexed tables are fixed in the same cycle.
+func (d *MissingIndexDetector) DetectMany(snapshot *normaliser.NormalisedMetrics) []*models.Detection {
 	if snapshot.Measurements.SequentialScans == nil {
 		return nil
 	}
 
 	seqScans := snapshot.Measurements.SequentialScans
 
-	if snapshot.MetricDeltas != nil {
-		if delta, exists := snapshot.MetricDeltas["sequential_scans"]; exists {
-			if delta <= d.sequentialScanDeltaThreshold {
-				return nil
-			}
-		}
-	} else {
-		if *seqScans <= d.sequentialScanThreshold {
+	// Judge by this cycle's scans when the delta can be trusted; after a restart or a clock
+	// problem it may read low, so fall back to the cumulative count
+	if delta, exists := snapshot.MetricDeltas["sequential_scans"]; exists && snapshot.DeltasReliable {
+		if delta <= d.sequentialScanDeltaThreshold {
 			return nil
 		}
+	} else if *seqScans <= d.sequentialScanThreshold {
+		return nil
 	}
 
 	// Database-agnostic label lookup
@@ -53,39 +60,71 @@ func (d *MissingIndexDetector) Detect(snapshot *normaliser.NormalisedMetrics) *m
 		return nil
 	}
 
-	_, recommendedColumn := findLabelBySuffix(snapshot.Labels, "recommended_index_column")
-	if recommendedColumn == "" {
-		return nil
+	var detections []*models.Detection
+	for _, table := range candidateTables(snapshot.Labels, prefix, worstTable) {
+		// Use the same prefix for extended metrics (e.g., "pg.table." or "mysql.table.")
+		tablePrefix := fmt.Sprintf("%s.table.%s", prefix, table)
+
+		// A candidate that is no longer being scanned this cycle needs no index
+		if delta, exists := snapshot.MetricDeltas[tablePrefix+".seq_scans"]; exists && snapshot.DeltasReliable && delta <= d.sequentialScanDeltaThreshold {
+			continue
+		}
+
+		recommendedColumns := splitColumns(snapshot.Labels[tablePrefix+".recommended_index_columns"])
+		if len(recommendedColumns) == 0 && table == worstTable {
+			recommendedColumns = recommendedIndexColumns(snapshot.Labels, prefix)
+		}
+		if len(recommendedColumns) == 0 {
+			continue
+		}
+
+		detections = append(detections, d.indexDetection(snapshot, table, tablePrefix, recommendedColumns))
 	}
 
-	// Use the same prefix for extended metrics (e.g., "pg.table." or "mysql.table.")
-	tablePrefix := fmt.Sprintf("%s.table.%s", prefix, worstTable)
+	// Columns come from pg_stat_statements - without it, ask for it instead of guessing
+	if len(detections) == 0 && snapshot.Labels["pg.stat_statements"] == "unavailable" {
+		tablePrefix := fmt.Sprintf("%s.table.%s", prefix, worstTable)
+		tableSeqScans := int64(snapshot.ExtendedMetrics[tablePrefix+".seq_scans"])
+		seqTupRead := int64(snapshot.ExtendedMetrics[tablePrefix+".seq_tup_read"])
+		return []*models.Detection{d.enableQueryStatsDetection(snapshot, worstTable, tableSeqScans, seqTupRead)}
+	}
+
+	return detections
+}
+
+// indexDetection recommends an index on table over the given columns.
+func (d *MissingIndexDetector) indexDetection(snapshot *normaliser.NormalisedMetrics, table, tablePrefix string, recommendedColumns []string) *models.Detection {
 	tableSeqScans := int64(snapshot.ExtendedMetrics[tablePrefix+".seq_scans"])
 	seqTupRead := int64(snapshot.ExtendedMetrics[tablePrefix+".seq_tup_read"])
+	columnList := strings.Join(recommendedColumns, ", ")
 
 	detection := models.NewDetection(d.Name(), d.Category(), snapshot.DatabaseID)
 	detection.Severity = models.SeverityWarning
+	detection.Value = float64(tableSeqScans)
 	detection.Timestamp = snapshot.Timestamp
 
-	detection.Title = fmt.Sprintf("Sequential scans detected on table '%s'", worstTable)
+	detection.Title = fmt.Sprintf("Sequential scans detected on table '%s'", table)
 	detection.Description = fmt.Sprintf(
 		"Table '%s' is performing %d sequential scans (%d rows read). "+
-			"Column '%s' is frequently filtered in queries without an index, "+
+			"Column(s) '%s' are frequently filtered or sorted on in queries without an index, "+
 			"causing full table scans.",
-		worstTable, tableSeqScans, seqTupRead, recommendedColumn,
+		table, tableSeqScans, seqTupRead, columnList,
 	)
 
 	detection.Evidence = map[string]interface{}{
-		"table_name":       worstTable,
-		"column_name":      recommendedColumn,
+		"table_name":       table,
+		"column_name":      recommendedColumns[0],
+		"column_names":     recommendedColumns,
 		"sequential_scans": tableSeqScans,
 		"rows_read":        seqTupRead,
 		"query_health":     snapshot.QueryHealth,
 		"database_type":    snapshot.DatabaseType,
 	}
 
-	if snapshot.MetricDeltas != nil {
-		if delta, exists := snapshot.MetricDeltas["sequential_scans"]; exists {
+	if snapshot.DeltasReliable {
+		if delta, exists := snapshot.MetricDeltas[tablePrefix+".seq_scans"]; exists {
+			detection.Evidence["sequential_scans_delta"] = delta
+		} else if delta, exists := snapshot.MetricDeltas["sequential_scans"]; exists {
 			detection.Evidence["sequential_scans_delta"] = delta
 		}
 		if snapshot.TimeDeltaSeconds > 0 {
@@ -93,16 +132,25 @@ func (d *MissingIndexDetector) Detect(snapshot *normaliser.NormalisedMetrics) *m
 		}
 	}
 
-	detection.Recommendation = fmt.Sprintf(
-		"Create an index on %s.%s to optimize query performance. "+
-			"This column was identified through query analysis.",
-		worstTable, recommendedColumn,
-	)
+	if len(recommendedColumns) == 1 {
+		detection.Recommendation = fmt.Sprintf(
+			"Create an index on %s.%s to optimize query performance. "+
+				"This column was identified through query analysis.",
+			table, recommendedColumns[0],
+		)
+	} else {
+		detection.Recommendation = fmt.Sprintf(
+			"Create an index on %s (%s) to optimize query performance. "+
+				"The column order was identified through query analysis.",
+			table, columnList,
+		)
+	}
 
 	detection.ActionType = "create_index"
 	detection.ActionMetadata = map[string]interface{}{
-		"table_name":    worstTable,
-		"column_name":   recommendedColumn,
+		"table_name":    table,
+		"column_name":   recommendedColumns[0],
+		"column_names":  recommendedColumns,
 		"database_type": snapshot.DatabaseType,
 		"priority":      "high",
 	}
@@ -110,6 +158,89 @@ func (d *MissingIndexDetector) Detect(snapshot *normaliser.NormalisedMetrics) *m
 	return detection
 }
 
+// enableQueryStatsDetection reports sequential scans that cannot be matched to a column
+// because pg_stat_statements is not installed. It is a recommendation: create_index is
+// never sent without a column.
+func (d *MissingIndexDetector) enableQueryStatsDetection(snapshot *normaliser.NormalisedMetrics, table string, seqScans, rowsRead int64) *models.Detection {
+	detection := models.NewDetection(d.Name(), d.Category(), snapshot.DatabaseID)
+	detection.Severity = models.SeverityInfo
+	detection.Value = float64(seqScans)
+	detection.Timestamp = snapshot.Timestamp
+
+	detection.Title = fmt.Sprintf("Sequential scans on table '%s' cannot be analysed", table)
+	detection.Description = fmt.Sprintf(
+		"Table '%s' is performing %d sequential scans (%d rows read), but pg_stat_statements is not enabled, "+
+			"so the queries causing them - and the columns worth indexing - cannot be identified.",
+		table, seqScans, rowsRead,
+	)
+
+	detection.Evidence = map[string]interface{}{
+		"table_name":         table,
+		"sequential_scans":   seqScans,
+		"rows_read":          rowsRead,
+		"query_health":       snapshot.QueryHealth,
+		"database_type":      snapshot.DatabaseType,
+		"pg_stat_statements": "unavailable",
+	}
+
+	detection.Recommendation = "Enable the pg_stat_statements extension so StartupMonkey can recommend which columns to index."
+
+	detection.ActionType = "enable_query_stats_recommendation"
+	detection.ActionMetadata = map[string]interface{}{
+		"priority":      "low",
+		"database_type": snapshot.DatabaseType,
+		"table_name":    table,
+		"safe_option": map[string]interface{}{
+			"title":            "Enable pg_stat_statements",
+			"description":      detection.Recommendation,
+			"risk_level":       "safe",
+			"requires_restart": true,
+			"steps": []string{
+				"Add pg_stat_statements to shared_preload_libraries: ALTER SYSTEM SET shared_preload_libraries = 'pg_stat_statements';",
+				"Restart PostgreSQL so the library is loaded",
+				"Create the extension in the monitored database: CREATE EXTENSION IF NOT EXISTS pg_stat_statements;",
+				fmt.Sprintf("Wait a few collection cycles - StartupMonkey will then recommend an index for '%s'", table),
+			},
+		},
+	}
+
+	return detection
+}
+
+// candidateTables returns the tables worth an index, most scanned first. Adapters that only
+// report the worst table yield just that one.
+func candidateTables(labels map[string]string, prefix, worstTable string) []string {
+	if tables := splitColumns(labels[prefix+".seq_scan_candidate_tables"]); len(tables) > 0 {
+		return tables
+	}
+	return []string{worstTable}
+}
+
+// recommendedIndexColumns returns the worst table's ordered index columns from the collector
+// labels, falling back to the single-column label for adapters that only report one.
+func recommendedIndexColumns(labels map[string]string, prefix string) []string {
+	if columns := splitColumns(labels[prefix+".recommended_index_columns"]); len(columns) > 0 {
+		return columns
+	}
+
+	if column := labels[prefix+".recommended_index_column"]; column != "" {
+		return []string{column}
+	}
+
+	return nil
+}
+
+// splitColumns splits a comma-separated label value, dropping empty entries.
+func splitColumns(list string) []string {
+	var columns []string
+	for _, column := range strings.Split(list, ",") {
+		if column = strings.TrimSpace(column); column != "" {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
 // findLabelBySuffix searches for a label ending with the given suffix.
 // Returns the prefix (e.g., "pg", "mysql") and the value.
 func findLabelBySuffix(labels map[string]string, suffix string) (string, string) {
@@ -0,0 +1,120 @@
+package detector
+
+// Registration describes a detector the Analyser can run, by the name used to enable it.
+type Registration struct {
+	Name string // Matches the detector's Name()
+
+	// Threshold names (as overridden in Knowledge) the detector reads. The first is its main
+	// threshold, the one DETECTOR_<NAME>_THRESHOLD sets.
+	Thresholds []string
+
+	New func() Detector
+}
+
+// registry lists every detector in the order the engine runs them.
+var registry = []Registration{
+	{
+		Name:       "connection_pool_exhaustion",
+		Thresholds: []string{"connection_pool_critical", "connection_pool_idle", "connection_pool_idle_share"},
+		New:        func() Detector { return NewConnectionPoolDetection() },
+	},
+	{
+		Name:       "missing_index",
+		Thresholds: []string{"sequential_scan_delta", "sequential_scan_threshold"},
+		New:        func() Detector { return NewMissingIndexDetector() },
+	},
+	{
+		Name:       "high_query_latency",
+		Thresholds: []string{"p95_latency_ms"},
+		New:        func() Detector { return NewHighLatencyDetector() },
+	},
+	{
+		Name:       "cache_miss_rate_high",
+		Thresholds: []string{"cache_hit_rate_threshold"},
+		New:        func() Detector { return NewCacheMissDetector() },
+	},
+	{
+		Name:       "table_bloat",
+		Thresholds: []string{"table_bloat"},
+		New:        func() Detector { return NewTableBloatDetector() },
+	},
+	{
+		Name:       "long_running_query",
+		Thresholds: []string{"long_running_query_secs"},
+		New:        func() Detector { return NewLongRunningQueryDetector() },
+	},
+	{
+		Name:       "idle_transaction",
+		Thresholds: []string{"idle_transaction_secs"},
+		New:        func() Detector { return NewIdleTransactionDetector() },
+	},
+	{
+		Name:       "replication_lag",
+		Thresholds: []string{"replication_lag_secs", "replication_lag_warning_secs", "replication_lag_critical_secs", "replication_lag_mb"},
+		New:        func() Detector { return NewReplicationLagDetector() },
+	},
+	{
+		Name:       "deadlock",
+		Thresholds: []string{"deadlocks"},
+		New:        func() Detector { return NewDeadlockDetector() },
+	},
+	{
+		Name:       "autovacuum_starvation",
+		Thresholds: []string{"autovacuum_age_secs", "autovacuum_starvation_cycles"},
+		New:        func() Detector { return NewAutovacuumStarvationDetector() },
+	},
+	{
+		Name:       "unused_index",
+		Thresholds: []string{"unused_index_min_size_mb", "unused_index_cycles"},
+		New:        func() Detector { return NewUnusedIndexDetector() },
+	},
+	{
+		Name:       "lock_contention",
+		Thresholds: []string{"lock_waiting_connections", "lock_contention_cycles"},
+		New:        func() Detector { return NewLockContentionDetector() },
+	},
+	{
+		Name:       "blocking_lock",
+		Thresholds: []string{"lock_wait_secs"},
+		New:        func() Detector { return NewBlockingLockDetector() },
+	},
+	{
+		Name:       "connection_churn",
+		Thresholds: []string{"connection_churn_per_sec"},
+		New:        func() Detector { return NewConnectionChurnDetector() },
+	},
+	{
+		Name:       "temp_file_spill",
+		Thresholds: []string{"temp_spill_mb"},
+		New:        func() Detector { return NewTempFileSpillDetector() },
+	},
+	{
+		Name:       "storage_growth",
+		Thresholds: []string{"storage_usage", "storage_time_to_full_hours"},
+		New:        func() Detector { return NewStorageGrowthDetector() },
+	},
+}
+
+// Registrations returns every detector the Analyser can run, in run order.
+func Registrations() []Registration {
+	return append([]Registration(nil), registry...)
+}
+
+// Lookup finds a detector's registration by name.
+func Lookup(name string) (Registration, bool) {
+	for _, reg := range registry {
+		if reg.Name == name {
+			return reg, true
+		}
+	}
+	return Registration{}, false
+}
+
+// Names returns the names of every detector the Analyser can run, in run order.
+func Names() []string {
+	names := make([]string, len(registry))
+	for i, reg := range registry {
+		names[i] = reg.Name
+	}
+	return names
+}
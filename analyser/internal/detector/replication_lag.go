@@ -0,0 +1,174 @@
+package detector
+
+import (
+	"fmt"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+)
+
+// ReplicationLagDetector fires when a replica falls behind the primary, measured in time
+// (replay_lag) or in WAL not yet replayed. replay_lag stops being reported once the primary
+// goes quiet, so a replica stuck behind an idle primary only shows in bytes.
+type ReplicationLagDetector struct {
+	thresholdSecs  float64
+	warningSecs    float64
+	criticalSecs   float64
+	thresholdBytes float64 // 0 disables the byte check
+}
+
+func NewReplicationLagDetector() *ReplicationLagDetector {
+	return &ReplicationLagDetector{
+		thresholdSecs:  10.0,              // Info
+		warningSecs:    30.0,              // Warning
+		criticalSecs:   120.0,             // Critical
+		thresholdBytes: 256 * 1024 * 1024, // Info; Warning at 2x, Critical at 4x
+	}
+}
+
+func (d *ReplicationLagDetector) Name() string {
+	return "replication_lag"
+}
+
+func (d *ReplicationLagDetector) Category() models.DetectionCategory {
+	return models.CategoryReplication
+}
+
+func (d *ReplicationLagDetector) Detect(snapshot *normaliser.NormalisedMetrics) *models.Detection {
+	lag, hasLag := snapshot.ExtendedMetrics["pg.max_replication_lag_secs"]
+	lagBytes, hasBytes := snapshot.ExtendedMetrics["pg.max_replication_lag_bytes"]
+
+	overSecs := hasLag && lag >= d.thresholdSecs
+	overBytes := hasBytes && d.thresholdBytes > 0 && lagBytes >= d.thresholdBytes
+	if !overSecs && !overBytes {
+		return nil
+	}
+
+	replica, hasReplica := snapshot.Labels["pg.worst_lag_replica"]
+	if !hasReplica {
+		return nil
+	}
+
+	state := snapshot.Labels["pg.worst_lag_replica_state"]
+	clientAddr := snapshot.Labels["pg.worst_lag_replica_addr"]
+	writeLag := snapshot.ExtendedMetrics["pg.max_replication_write_lag_secs"]
+	replicaCount := snapshot.ExtendedMetrics["pg.replica_count"]
+
+	// When only the byte backlog is over, point at the replica holding it, which need not be
+	// the one furthest behind in time
+	if bytesReplica, ok := snapshot.Labels["pg.worst_bytes_lag_replica"]; ok && !overSecs {
+		prefix := "pg.replica." + bytesReplica
+		replica = bytesReplica
+		state = snapshot.Labels["pg.worst_bytes_lag_replica_state"]
+		clientAddr = snapshot.Labels["pg.worst_bytes_lag_replica_addr"]
+		lag = snapshot.ExtendedMetrics[prefix+".replay_lag_secs"]
+		writeLag = snapshot.ExtendedMetrics[prefix+".write_lag_secs"]
+	}
+
+	lagMB := lagBytes / (1024 * 1024)
+
+	severity := d.severity(lag, lagBytes)
+
+	detection := models.NewDetection(d.Name(), d.Category(), snapshot.DatabaseID)
+	detection.Severity = severity
+	detection.Timestamp = snapshot.Timestamp
+
+	if overSecs {
+		detection.Value = lag
+		detection.Title = fmt.Sprintf("Replica '%s' is %.0fs behind primary", replica, lag)
+	} else {
+		detection.Value = lagBytes
+		detection.Title = fmt.Sprintf("Replica '%s' is %.0fMB of WAL behind primary", replica, lagMB)
+	}
+	detection.Description = fmt.Sprintf(
+		"Replica '%s' (state: %s) has a replay lag of %.1fs and write lag of %.1fs, and the replica furthest "+
+			"behind in WAL has %.0fMB sent but not yet replayed. Reads served from a lagging replica may "+
+			"return stale data, and failover to it would lose recent writes.",
+		replica, state, lag, writeLag, lagMB,
+	)
+
+	detection.Evidence = map[string]interface{}{
+		"application_name": replica,
+		"client_addr":      clientAddr,
+		"state":            state,
+		"replay_lag_secs":  lag,
+		"write_lag_secs":   writeLag,
+		"replay_lag_bytes": lagBytes,
+		"replica_count":    replicaCount,
+		"threshold_secs":   d.thresholdSecs,
+		"threshold_bytes":  d.thresholdBytes,
+	}
+
+	detection.Recommendation = fmt.Sprintf(
+		"Replication lag on '%s' usually comes from one of: long-running transactions or heavy write bursts "+
+			"on the primary generating WAL faster than it can be replayed; network throughput between primary "+
+			"and replica; or a replica with less CPU or slower disks than the primary. Queries on the replica "+
+			"can also hold up replay - review hot_standby_feedback and max_standby_streaming_delay.",
+		replica,
+	)
+
+	// No safe autonomous fix - surfaced for investigation
+	detection.ActionType = "replication_lag_recommendation"
+	detection.ActionMetadata = map[string]interface{}{
+		"priority":         string(severity),
+		"database_type":    snapshot.DatabaseType,
+		"application_name": replica,
+		"replay_lag_secs":  lag,
+		"replay_lag_bytes": lagBytes,
+		"safe_option": map[string]interface{}{
+			"title":            "Investigate Replication Lag",
+			"description":      detection.Recommendation,
+			"risk_level":       "safe",
+			"requires_restart": false,
+			"steps": []string{
+				"On the primary, look for long-running transactions: SELECT pid, xact_start, query FROM pg_stat_activity WHERE xact_start < now() - interval '5 minutes';",
+				"Compare sent_lsn, write_lsn, flush_lsn and replay_lsn in pg_stat_replication: a gap before write_lsn points at the network, a gap after it at the replica",
+				fmt.Sprintf("Check CPU, disk I/O and network throughput on replica '%s' against the primary", replica),
+				"On the replica, check pg_stat_database_conflicts and whether queries are delaying replay",
+			},
+		},
+	}
+
+	return detection
+}
+
+// severity is the more severe of the tiers for lag in seconds and lag in bytes.
+func (d *ReplicationLagDetector) severity(lagSecs, lagBytes float64) models.DetectionSeverity {
+	severity := models.SeverityInfo
+	if lagSecs >= d.criticalSecs {
+		severity = models.SeverityCritical
+	} else if lagSecs >= d.warningSecs {
+		severity = models.SeverityWarning
+	}
+
+	if d.thresholdBytes > 0 {
+		bytesSeverity := models.SeverityInfo
+		if lagBytes >= d.thresholdBytes*4 {
+			bytesSeverity = models.SeverityCritical
+		} else if lagBytes >= d.thresholdBytes*2 {
+			bytesSeverity = models.SeverityWarning
+		}
+		if bytesSeverity.Rank() > severity.Rank() {
+			severity = bytesSeverity
+		}
+	}
+
+	return severity
+}
+
+// SetThreshold sets the lag (in seconds) at which an Info detection fires.
+func (d *ReplicationLagDetector) SetThreshold(thresholdSecs float64) {
+	d.thresholdSecs = thresholdSecs
+}
+
+// SetSeverityThresholds sets the lag (in seconds) at which detections escalate to Warning and Critical.
+func (d *ReplicationLagDetector) SetSeverityThresholds(warningSecs, criticalSecs float64) {
+	d.warningSecs = warningSecs
+	d.criticalSecs = criticalSecs
+}
+
+// SetByteThreshold sets the WAL not yet replayed (in bytes) at which an Info detection fires,
+// escalating at twice and four times it; 0 only checks the lag in seconds.
+func (d *ReplicationLagDetector) SetByteThreshold(thresholdBytes float64) {
+	d.thresholdBytes = thresholdBytes
+}
@@ -0,0 +1,208 @@
+package detector
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+)
+
+// storageGrowthSmoothing is the weight each new growth sample gets in the running average,
+// so one bulk load or VACUUM FULL doesn't swing the projection.
+const storageGrowthSmoothing = 0.2
+
+// StorageGrowthDetector fires before a database runs out of disk: when the disk is fuller
+// than the usage threshold, or when storage growth, averaged over recent cycles, would
+// fill it within the time-to-full window. It needs the disk's size, which the Collector
+// reports from host metrics or a disk usage query.
+type StorageGrowthDetector struct {
+	usageThreshold  float64 // Fraction of the disk used (Warning; Critical halfway to full)
+	timeToFullHours float64 // Projected hours until full (Warning; Critical at a third); 0 disables
+	minSamples      int     // Growth samples required before projecting
+
+	mu           sync.Mutex
+	growthPerSec float64 // smoothed growth of used storage, bytes per second
+	samples      int
+}
+
+func NewStorageGrowthDetector() *StorageGrowthDetector {
+	return &StorageGrowthDetector{
+		usageThreshold:  0.85,
+		timeToFullHours: 72,
+		minSamples:      5,
+	}
+}
+
+func (d *StorageGrowthDetector) Name() string {
+	return "storage_growth"
+}
+
+func (d *StorageGrowthDetector) Category() models.DetectionCategory {
+	return models.CategoryStorage
+}
+
+// Clone returns a detector with the same thresholds and no growth history.
+func (d *StorageGrowthDetector) Clone() Detector {
+	return &StorageGrowthDetector{
+		usageThreshold:  d.usageThreshold,
+		timeToFullHours: d.timeToFullHours,
+		minSamples:      d.minSamples,
+	}
+}
+
+func (d *StorageGrowthDetector) Detect(snapshot *normaliser.NormalisedMetrics) *models.Detection {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// Growth is tracked even while the disk size is unknown, so a projection is ready once it is
+	if delta, exists := snapshot.MetricDeltas["used_storage_bytes"]; exists && snapshot.DeltasReliable && snapshot.TimeDeltaSeconds > 0 {
+		rate := delta / snapshot.TimeDeltaSeconds
+		if d.samples == 0 {
+			d.growthPerSec = rate
+		} else {
+			d.growthPerSec += storageGrowthSmoothing * (rate - d.growthPerSec)
+		}
+		d.samples++
+	}
+
+	m := snapshot.Measurements
+	if m.TotalStorageBytes == nil || *m.TotalStorageBytes <= 0 {
+		return nil
+	}
+	total := float64(*m.TotalStorageBytes)
+
+	// Free space covers everything on the disk, not just this database, so it wins when known
+	var free float64
+	switch {
+	case m.FreeStorageBytes != nil:
+		free = float64(*m.FreeStorageBytes)
+	case m.UsedStorageBytes != nil:
+		free = total - float64(*m.UsedStorageBytes)
+	default:
+		return nil
+	}
+	free = max(free, 0)
+	usage := 1 - free/total
+
+	hoursToFull := -1.0 // not projected: too few samples, or storage isn't growing
+	if d.samples >= d.minSamples && d.growthPerSec > 0 {
+		hoursToFull = free / d.growthPerSec / 3600
+	}
+
+	overUsage := usage >= d.usageThreshold
+	overTime := d.timeToFullHours > 0 && hoursToFull >= 0 && hoursToFull < d.timeToFullHours
+	if !overUsage && !overTime {
+		return nil
+	}
+
+	severity := d.severity(usage, hoursToFull)
+
+	detection := models.NewDetection(d.Name(), d.Category(), snapshot.DatabaseID)
+	detection.Severity = severity
+	detection.Value = usage
+	detection.Timestamp = snapshot.Timestamp
+
+	growthPerHour := d.growthPerSec * 3600
+	growthMBPerHour := growthPerHour / (1024 * 1024)
+	freeGB := free / (1024 * 1024 * 1024)
+
+	if hoursToFull >= 0 {
+		detection.Title = fmt.Sprintf("Disk %.0f%% full, projected full in %.0fh", usage*100, hoursToFull)
+		detection.Description = fmt.Sprintf(
+			"The disk holding database '%s' is %.1f%% full with %.1fGB free. Storage is growing by %.1fMB/hour, "+
+				"which fills the disk in about %.0f hours (window: %.0fh). The database stops accepting writes when it does.",
+			snapshot.DatabaseID, usage*100, freeGB, growthMBPerHour, hoursToFull, d.timeToFullHours,
+		)
+	} else {
+		detection.Title = fmt.Sprintf("Disk %.0f%% full", usage*100)
+		detection.Description = fmt.Sprintf(
+			"The disk holding database '%s' is %.1f%% full with %.1fGB free (threshold: %.0f%%). "+
+				"The database stops accepting writes when it fills.",
+			snapshot.DatabaseID, usage*100, freeGB, d.usageThreshold*100,
+		)
+	}
+
+	detection.Evidence = map[string]interface{}{
+		"total_bytes":           total,
+		"free_bytes":            free,
+		"usage":                 usage,
+		"growth_bytes_per_hour": growthPerHour,
+		"growth_samples":        d.samples,
+		"usage_threshold":       d.usageThreshold,
+		"time_to_full_hours":    d.timeToFullHours,
+	}
+	if m.UsedStorageBytes != nil {
+		detection.Evidence["database_bytes"] = float64(*m.UsedStorageBytes)
+	}
+	if hoursToFull >= 0 {
+		fullAt := time.Unix(snapshot.Timestamp, 0).Add(time.Duration(hoursToFull * float64(time.Hour)))
+		detection.Evidence["hours_to_full"] = hoursToFull
+		detection.Evidence["projected_full_at"] = fullAt.UTC().Format(time.RFC3339)
+	}
+
+	detection.Recommendation = "Free or add disk space before it runs out: drop data that is no longer needed, " +
+		"reclaim space held by bloated tables and unused indexes, check that WAL or binary logs are not retained " +
+		"by a stale replication slot or a failing archiver, and grow the volume if the growth is expected."
+
+	// Freeing space needs a decision about what to delete - surfaced for the operator
+	detection.ActionType = "storage_growth_recommendation"
+	detection.ActionMetadata = map[string]interface{}{
+		"priority":      string(severity),
+		"database_type": snapshot.DatabaseType,
+		"usage":         usage,
+		"safe_option": map[string]interface{}{
+			"title":            "Free or Add Disk Space",
+			"description":      detection.Recommendation,
+			"risk_level":       "safe",
+			"requires_restart": false,
+			"steps": []string{
+				"Find the largest tables and indexes, and check whether their growth is expected",
+				"Act on any table_bloat and unused_index detections for this database",
+				"Check for WAL or binary logs piling up behind an inactive replication slot or a failing archive command",
+				"If the growth is expected, grow the volume before the projected full time",
+			},
+		},
+	}
+	if hoursToFull >= 0 {
+		detection.ActionMetadata["hours_to_full"] = hoursToFull
+	}
+
+	return detection
+}
+
+// severity is the more severe of the tiers for disk usage and time to full.
+func (d *StorageGrowthDetector) severity(usage, hoursToFull float64) models.DetectionSeverity {
+	severity := models.SeverityInfo
+	if usage >= (d.usageThreshold+1)/2 {
+		severity = models.SeverityCritical
+	} else if usage >= d.usageThreshold {
+		severity = models.SeverityWarning
+	}
+
+	if d.timeToFullHours > 0 && hoursToFull >= 0 {
+		timeSeverity := models.SeverityInfo
+		if hoursToFull < d.timeToFullHours/3 {
+			timeSeverity = models.SeverityCritical
+		} else if hoursToFull < d.timeToFullHours {
+			timeSeverity = models.SeverityWarning
+		}
+		if timeSeverity.Rank() > severity.Rank() {
+			severity = timeSeverity
+		}
+	}
+
+	return severity
+}
+
+// SetThreshold sets the fraction of the disk used (0-1) at which a Warning fires.
+func (d *StorageGrowthDetector) SetThreshold(usage float64) {
+	d.usageThreshold = usage
+}
+
+// SetTimeToFullThreshold sets how many hours from full the disk must be projected to be
+// before a Warning fires, escalating to Critical at a third of it; 0 only checks usage.
+func (d *StorageGrowthDetector) SetTimeToFullThreshold(hours float64) {
+	d.timeToFullHours = hours
+}
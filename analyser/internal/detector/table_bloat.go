@@ -7,13 +7,30 @@ import (
 	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
 )
 
+// Vacuum modes the Executor's vacuum_table action understands.
+const (
+	VacuumModeAnalyze        = "analyze"
+	VacuumModeFull           = "full"
+	VacuumModeTuneAutovacuum = "tune_autovacuum"
+)
+
 type TableBloatDetector struct {
 	bloatRatioThreshold float64
+
+	// Mode selection; see chooseMode
+	fullRatio             float64
+	fullMaxSizeBytes      float64
+	tuneMinSizeBytes      float64
+	autovacuumScaleFactor float64
 }
 
 func NewTableBloatDetector() *TableBloatDetector {
 	return &TableBloatDetector{
-		bloatRatioThreshold: 0.1, // 10% dead tuples
+		bloatRatioThreshold:   0.1, // 10% dead tuples
+		fullRatio:             0.5,
+		fullMaxSizeBytes:      1024 * 1024 * 1024,
+		tuneMinSizeBytes:      1024 * 1024 * 1024,
+		autovacuumScaleFactor: 0.05,
 	}
 }
 
@@ -39,6 +56,8 @@ func (d *TableBloatDetector) Detect(snapshot *normaliser.NormalisedMetrics) *mod
 	prefix := fmt.Sprintf("pg.table.%s", worstTable)
 	liveTuples := int64(snapshot.ExtendedMetrics[prefix+".live_tuples"])
 	deadTuples := int64(snapshot.ExtendedMetrics[prefix+".dead_tuples"])
+	sizeBytes, sizeKnown := snapshot.ExtendedMetrics[prefix+".size_bytes"]
+	mode := d.chooseMode(bloatRatio, sizeBytes, sizeKnown)
 
 	var severity models.DetectionSeverity
 	if bloatRatio >= 0.3 {
@@ -51,6 +70,7 @@ func (d *TableBloatDetector) Detect(snapshot *normaliser.NormalisedMetrics) *mod
 
 	detection := models.NewDetection(d.Name(), d.Category(), snapshot.DatabaseID)
 	detection.Severity = severity
+	detection.Value = bloatRatio
 	detection.Timestamp = snapshot.Timestamp
 
 	bloatPercent := int(bloatRatio * 100)
@@ -68,24 +88,71 @@ func (d *TableBloatDetector) Detect(snapshot *normaliser.NormalisedMetrics) *mod
 		"dead_tuples":   deadTuples,
 		"bloat_ratio":   bloatRatio,
 		"bloat_percent": bloatPercent,
+		"vacuum_mode":   mode,
+	}
+	if sizeKnown {
+		detection.Evidence["size_bytes"] = int64(sizeBytes)
 	}
 
-	detection.Recommendation = fmt.Sprintf(
-		"Run VACUUM ANALYZE on table '%s' to reclaim space from dead tuples "+
-			"and update query planner statistics. This operation is non-blocking "+
-			"and safe to run on production databases.",
-		worstTable,
-	)
+	detection.Recommendation = d.recommendation(worstTable, mode)
 
 	detection.ActionType = "vacuum_table"
 	detection.ActionMetadata = map[string]interface{}{
 		"table_name": worstTable,
 		"priority":   d.getPriority(bloatRatio),
+		"mode":       mode,
+	}
+	if mode == VacuumModeTuneAutovacuum && d.autovacuumScaleFactor > 0 {
+		detection.ActionMetadata["autovacuum_vacuum_scale_factor"] = d.autovacuumScaleFactor
 	}
 
 	return detection
 }
 
+// chooseMode picks how to deal with the bloat. Heavily bloated tables small enough to
+// rewrite get VACUUM FULL; large tables get autovacuum tuned so it keeps up with them,
+// since the default scale factor lets dead tuples pile up in proportion to table size.
+// Everything else, and tables whose size isn't reported, gets VACUUM ANALYZE.
+func (d *TableBloatDetector) chooseMode(bloatRatio, sizeBytes float64, sizeKnown bool) string {
+	if !sizeKnown {
+		return VacuumModeAnalyze
+	}
+	if bloatRatio >= d.fullRatio && sizeBytes <= d.fullMaxSizeBytes {
+		return VacuumModeFull
+	}
+	if sizeBytes >= d.tuneMinSizeBytes {
+		return VacuumModeTuneAutovacuum
+	}
+	return VacuumModeAnalyze
+}
+
+func (d *TableBloatDetector) recommendation(table, mode string) string {
+	switch mode {
+	case VacuumModeFull:
+		return fmt.Sprintf(
+			"Run VACUUM FULL on table '%s' to rewrite it and return the space held by dead "+
+				"tuples to the operating system. VACUUM FULL takes an ACCESS EXCLUSIVE lock, "+
+				"blocking reads and writes until it finishes, and needs free disk space for a "+
+				"full copy of the table, so it only runs inside the maintenance window.",
+			table,
+		)
+	case VacuumModeTuneAutovacuum:
+		return fmt.Sprintf(
+			"Lower autovacuum_vacuum_scale_factor on table '%s' (ALTER TABLE ... SET) so "+
+				"autovacuum runs after a smaller share of its rows change. On a table this "+
+				"large the default lets dead tuples accumulate faster than they are cleaned up.",
+			table,
+		)
+	default:
+		return fmt.Sprintf(
+			"Run VACUUM ANALYZE on table '%s' to reclaim space from dead tuples "+
+				"and update query planner statistics. This operation is non-blocking "+
+				"and safe to run on production databases.",
+			table,
+		)
+	}
+}
+
 func (d *TableBloatDetector) getPriority(bloatRatio float64) string {
 	if bloatRatio >= 0.3 {
 		return "high"
@@ -98,3 +165,18 @@ func (d *TableBloatDetector) getPriority(bloatRatio float64) string {
 func (d *TableBloatDetector) SetThreshold(threshold float64) {
 	d.bloatRatioThreshold = threshold
 }
+
+// SetModeThresholds sets when VACUUM FULL (bloat ratio at least fullRatio on a table no
+// larger than fullMaxSizeBytes) or autovacuum tuning (tables of at least tuneMinSizeBytes)
+// is recommended instead of VACUUM ANALYZE.
+func (d *TableBloatDetector) SetModeThresholds(fullRatio, fullMaxSizeBytes, tuneMinSizeBytes float64) {
+	d.fullRatio = fullRatio
+	d.fullMaxSizeBytes = fullMaxSizeBytes
+	d.tuneMinSizeBytes = tuneMinSizeBytes
+}
+
+// SetAutovacuumScaleFactor sets the autovacuum_vacuum_scale_factor recommended when tuning;
+// 0 leaves the choice to the Executor.
+func (d *TableBloatDetector) SetAutovacuumScaleFactor(scaleFactor float64) {
+	d.autovacuumScaleFactor = scaleFactor
+}
@@ -0,0 +1,104 @@
+package detector
+
+import (
+	"fmt"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+)
+
+// TempFileSpillDetector fires when queries write more temporary files per collection
+// interval than the threshold: sorts and hashes that didn't fit in work_mem spilled to disk.
+// The spill volume goes to the Executor so it can size work_mem to fit them.
+type TempFileSpillDetector struct {
+	thresholdBytes float64 // Temp bytes written per interval
+}
+
+func NewTempFileSpillDetector() *TempFileSpillDetector {
+	return &TempFileSpillDetector{
+		thresholdBytes: 100 * 1024 * 1024, // Info; Warning at 2x, Critical at 4x
+	}
+}
+
+func (d *TempFileSpillDetector) Name() string {
+	return "temp_file_spill"
+}
+
+func (d *TempFileSpillDetector) Category() models.DetectionCategory {
+	return models.CategoryQuery
+}
+
+func (d *TempFileSpillDetector) Detect(snapshot *normaliser.NormalisedMetrics) *models.Detection {
+	if !snapshot.DeltasReliable {
+		return nil
+	}
+
+	tempBytes, found := snapshot.MetricDeltas["temp_bytes"]
+	if !found || tempBytes < d.thresholdBytes {
+		return nil
+	}
+
+	tempFiles := snapshot.MetricDeltas["temp_files"]
+	if tempFiles <= 0 {
+		return nil
+	}
+	avgSpillBytes := tempBytes / tempFiles
+
+	var severity models.DetectionSeverity
+	if tempBytes >= d.thresholdBytes*4 {
+		severity = models.SeverityCritical
+	} else if tempBytes >= d.thresholdBytes*2 {
+		severity = models.SeverityWarning
+	} else {
+		severity = models.SeverityInfo
+	}
+
+	tempMB := tempBytes / (1024 * 1024)
+	avgSpillMB := avgSpillBytes / (1024 * 1024)
+
+	detection := models.NewDetection(d.Name(), d.Category(), snapshot.DatabaseID)
+	detection.Severity = severity
+	detection.Value = tempBytes
+	detection.Timestamp = snapshot.Timestamp
+
+	detection.Title = fmt.Sprintf("Queries spilled %.0fMB to temp files", tempMB)
+	detection.Description = fmt.Sprintf(
+		"Queries wrote %.0f temp files totalling %.0fMB in the last %.0fs (threshold: %.0fMB), %.1fMB each on average. "+
+			"Sorts and hashes that don't fit in work_mem spill to disk, which is far slower than memory.",
+		tempFiles, tempMB, snapshot.TimeDeltaSeconds, d.thresholdBytes/(1024*1024), avgSpillMB,
+	)
+
+	detection.Evidence = map[string]interface{}{
+		"temp_files":      tempFiles,
+		"temp_bytes":      tempBytes,
+		"avg_spill_bytes": avgSpillBytes,
+		"interval_secs":   snapshot.TimeDeltaSeconds,
+		"threshold_bytes": d.thresholdBytes,
+	}
+
+	detection.Recommendation = fmt.Sprintf(
+		"StartupMonkey will raise work_mem to fit the average %.1fMB spill. work_mem is allowed per sort or hash in "+
+			"every connection, so for queries that still spill, look for missing indexes or large unbounded sorts.",
+		avgSpillMB,
+	)
+
+	// For Executor: the spill volume sizes work_mem
+	detection.ActionType = "tune_config_high_latency"
+	detection.ActionMetadata = map[string]interface{}{
+		"database_type":   snapshot.DatabaseType,
+		"temp_files":      tempFiles,
+		"temp_bytes":      tempBytes,
+		"avg_spill_bytes": avgSpillBytes,
+		// The Executor reports slow queries instead where the database's config can't be tuned
+		"fallback_action_type":    "report_slow_queries",
+		"slow_query_threshold_ms": slowQueryReportThresholdMs,
+		"slow_query_limit":        slowQueryReportLimit,
+	}
+
+	return detection
+}
+
+// SetThreshold sets the temp bytes written per collection interval at which an Info detection fires.
+func (d *TempFileSpillDetector) SetThreshold(thresholdBytes float64) {
+	d.thresholdBytes = thresholdBytes
+}
@@ -0,0 +1,163 @@
+package detector
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+)
+
+// UnusedIndexDetector fires when a large index has not been scanned for a number of
+// consecutive collection cycles. Unused indexes cost disk space and slow down every write.
+type UnusedIndexDetector struct {
+	minSizeBytes float64 // Ignore small indexes
+	unusedCycles int     // Consecutive cycles with zero scans required
+
+	mu       sync.Mutex
+	idleRuns map[string]int // index -> consecutive cycles without scans
+}
+
+func NewUnusedIndexDetector() *UnusedIndexDetector {
+	return &UnusedIndexDetector{
+		minSizeBytes: 10 * 1024 * 1024, // 10 MB default
+		unusedCycles: 120,              // 1 hour at a 30s collection interval
+		idleRuns:     make(map[string]int),
+	}
+}
+
+func (d *UnusedIndexDetector) Name() string {
+	return "unused_index"
+}
+
+func (d *UnusedIndexDetector) Category() models.DetectionCategory {
+	return models.CategoryStorage
+}
+
+// Clone returns a detector with the same thresholds and no index history.
+func (d *UnusedIndexDetector) Clone() Detector {
+	return &UnusedIndexDetector{
+		minSizeBytes: d.minSizeBytes,
+		unusedCycles: d.unusedCycles,
+		idleRuns:     make(map[string]int),
+	}
+}
+
+func (d *UnusedIndexDetector) Detect(snapshot *normaliser.NormalisedMetrics) *models.Detection {
+	if snapshot.MetricDeltas == nil || snapshot.ExtendedMetrics == nil {
+		return nil
+	}
+
+	// Skip the cycle without touching streaks, or an index looks idle only because its counter was reset
+	if !snapshot.DeltasReliable {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var worstIndex string
+	var worstSize float64
+	var worstRun int
+
+	seen := make(map[string]bool)
+
+	for key := range snapshot.ExtendedMetrics {
+		if !strings.HasPrefix(key, "pg.index.") || !strings.HasSuffix(key, ".idx_scans") {
+			continue
+		}
+
+		index := strings.TrimSuffix(strings.TrimPrefix(key, "pg.index."), ".idx_scans")
+		seen[index] = true
+
+		delta, hasDelta := snapshot.MetricDeltas[key]
+		if !hasDelta {
+			continue
+		}
+
+		if delta > 0 {
+			delete(d.idleRuns, index)
+			continue
+		}
+
+		d.idleRuns[index]++
+		run := d.idleRuns[index]
+
+		size := snapshot.ExtendedMetrics[fmt.Sprintf("pg.index.%s.size_bytes", index)]
+		if run < d.unusedCycles || size < d.minSizeBytes {
+			continue
+		}
+
+		if size > worstSize {
+			worstIndex = index
+			worstSize = size
+			worstRun = run
+		}
+	}
+
+	// Indexes that dropped out of the collected set lose their streak
+	for index := range d.idleRuns {
+		if !seen[index] {
+			delete(d.idleRuns, index)
+		}
+	}
+
+	if worstIndex == "" {
+		return nil
+	}
+
+	prefixKey := fmt.Sprintf("pg.index.%s", worstIndex)
+	tableName := snapshot.Labels[prefixKey+".table"]
+	totalScans := snapshot.ExtendedMetrics[prefixKey+".idx_scans"]
+	sizeMB := worstSize / (1024 * 1024)
+
+	detection := models.NewDetection(d.Name(), d.Category(), snapshot.DatabaseID)
+	detection.Severity = models.SeverityInfo
+	detection.Value = worstSize
+	detection.Timestamp = snapshot.Timestamp
+
+	detection.Title = fmt.Sprintf("Unused index '%s' on '%s' (%.1f MB)", worstIndex, tableName, sizeMB)
+	detection.Description = fmt.Sprintf(
+		"Index '%s' on table '%s' uses %.1f MB and has not been scanned for %d consecutive collection cycles. "+
+			"Every insert and update still has to maintain it.",
+		worstIndex, tableName, sizeMB, worstRun,
+	)
+
+	detection.Evidence = map[string]interface{}{
+		"index_name":         worstIndex,
+		"table_name":         tableName,
+		"index_size_bytes":   int64(worstSize),
+		"total_idx_scans":    totalScans,
+		"consecutive_cycles": worstRun,
+	}
+
+	detection.Recommendation = fmt.Sprintf(
+		"Drop index '%s' if no query relies on it. Check replicas and infrequent jobs (reports, month-end batches) "+
+			"first - idx_scan is only counted on this server since the last statistics reset.",
+		worstIndex,
+	)
+
+	detection.ActionType = "drop_index"
+	detection.ActionMetadata = map[string]interface{}{
+		"index_name":       worstIndex,
+		"table_name":       tableName,
+		"index_size_bytes": int64(worstSize),
+		"priority":         "low",
+	}
+
+	return detection
+}
+
+// SetThreshold sets the minimum index size (in bytes) worth reporting.
+func (d *UnusedIndexDetector) SetThreshold(minSizeBytes float64) {
+	d.minSizeBytes = minSizeBytes
+}
+
+// SetUnusedCycles sets how many consecutive cycles without scans are required before firing.
+func (d *UnusedIndexDetector) SetUnusedCycles(cycles int) {
+	if cycles < 1 {
+		cycles = 1
+	}
+	d.unusedCycles = cycles
+}
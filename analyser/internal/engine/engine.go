@@ -1,56 +1,194 @@
 package engine
 
 import (
-	"log"
+	"log/slog"
+	"sync"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/detector"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/models"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/logging"
 )
 
-// Engine populated of detectors
+// Configurator applies a database's settings, such as its thresholds, to one of its detectors.
+type Configurator func(databaseID string, d detector.Detector)
+
+// Engine populated of detectors. Registered detectors act as templates: stateful ones are
+// cloned per database on first sight, so one database's history can't affect another's.
+// Detectors registered through a factory are built fresh per database and can be
+// configured per database.
 type Engine struct {
+	mu            sync.Mutex
+	registrations []registration
+	perDatabase   map[string]*detectorSet // database_id -> detector set
+	configure     Configurator
+}
+
+type registration struct {
+	template    detector.Detector
+	newDetector func() detector.Detector // nil for detectors registered directly
+}
+
+// detectorSet is one database's detectors. The lock is held while they run or are
+// reconfigured, so a threshold change never races a Detect call.
+type detectorSet struct {
+	mu        sync.Mutex
 	detectors []detector.Detector
+	private   []bool // false for detectors shared with other databases
 }
 
 // Create a new detection engine
 func NewEngine() *Engine {
 	return &Engine{
-		detectors: make([]detector.Detector, 0),
+		registrations: make([]registration, 0),
+		perDatabase:   make(map[string]*detectorSet),
 	}
 }
 
 // Add new detector to the engine
 func (e *Engine) RegisterDetector(d detector.Detector) {
-	e.detectors = append(e.detectors, d)
-	log.Printf("Registered detector: %s (category: %s)", d.Name(), d.Category())
+	e.register(registration{template: d})
+}
+
+// RegisterDetectorFactory registers a detector built fresh for every database, so each
+// database's instance can be given its own thresholds by the Configurator.
+func (e *Engine) RegisterDetectorFactory(newDetector func() detector.Detector) {
+	e.register(registration{template: newDetector(), newDetector: newDetector})
+}
+
+func (e *Engine) register(reg registration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.registrations = append(e.registrations, reg)
+	for databaseID, set := range e.perDatabase {
+		set.mu.Lock()
+		e.addLocked(set, databaseID, reg)
+		set.mu.Unlock()
+	}
+
+	slog.Debug("Registered detector", "detector", reg.template.Name(), "category", reg.template.Category())
+}
+
+// SetConfigurator sets the hook applied to each database's private detectors when its set is
+// created and on every Reconfigure. Detectors shared between databases are never configured.
+func (e *Engine) SetConfigurator(configure Configurator) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.configure = configure
+}
+
+// Reconfigure re-applies the Configurator to every database's detectors, e.g. after
+// thresholds change. Detector state is kept.
+func (e *Engine) Reconfigure() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.configure == nil {
+		return
+	}
+
+	for databaseID, set := range e.perDatabase {
+		set.mu.Lock()
+		for i, d := range set.detectors {
+			if set.private[i] {
+				e.configure(databaseID, d)
+			}
+		}
+		set.mu.Unlock()
+	}
 }
 
 // Runs all detectors on provided metrics snapshot from collector
 func (e *Engine) RunDetectors(snapshot *normaliser.NormalisedMetrics) []*models.Detection {
 	var detections []*models.Detection
 
-	for _, det := range e.detectors {
-		if detection := det.Detect(snapshot); detection != nil {
-			log.Printf("Detection [%s] %s - %s", detection.Severity, det.Name(), detection.Title)
+	logger := logging.With(snapshot.CorrelationID).With("database_id", snapshot.DatabaseID)
+
+	set := e.detectorsFor(snapshot.DatabaseID)
+	set.mu.Lock()
+	defer set.mu.Unlock()
+
+	for _, det := range set.detectors {
+		for _, detection := range runDetector(det, snapshot) {
+			detection.CorrelationID = snapshot.CorrelationID
+			logger.Debug("Detector fired", "detector", det.Name(), "severity", detection.Severity, "title", detection.Title)
 			detections = append(detections, detection)
 		}
 	}
 
-	if len(detections) == 0 {
-		log.Printf("No issues detected (database: %s)", snapshot.DatabaseID)
-	} else {
-		log.Printf("Found %d issues in database: %s", len(detections), snapshot.DatabaseID)
+	return detections
+}
+
+// runDetector returns everything a detector finds in the snapshot.
+func runDetector(det detector.Detector, snapshot *normaliser.NormalisedMetrics) []*models.Detection {
+	if multi, ok := det.(detector.MultiDetector); ok {
+		return multi.DetectMany(snapshot)
 	}
+	if detection := det.Detect(snapshot); detection != nil {
+		return []*models.Detection{detection}
+	}
+	return nil
+}
 
-	return detections
+// detectorsFor returns the database's detector set, creating it the first time the database is seen.
+func (e *Engine) detectorsFor(databaseID string) *detectorSet {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if set, ok := e.perDatabase[databaseID]; ok {
+		return set
+	}
+
+	set := &detectorSet{}
+	for _, reg := range e.registrations {
+		e.addLocked(set, databaseID, reg)
+	}
+	e.perDatabase[databaseID] = set
+
+	slog.Debug("Created detector set", "database_id", databaseID, "detectors", len(set.detectors))
+	return set
+}
+
+// addLocked appends reg's instance for the database, configuring it if it is private.
+// Callers hold e.mu and the set's lock (or own a set not yet published).
+func (e *Engine) addLocked(set *detectorSet, databaseID string, reg registration) {
+	d, private := instanceFor(reg)
+	if private && e.configure != nil {
+		e.configure(databaseID, d)
+	}
+
+	set.detectors = append(set.detectors, d)
+	set.private = append(set.private, private)
+}
+
+// ForgetDatabase drops a database's detector state, e.g. once it is deregistered.
+func (e *Engine) ForgetDatabase(databaseID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.perDatabase, databaseID)
 }
 
 // Returns list of registered detectors
 func (e *Engine) GetRegisteredDetectors() []string {
-	names := make([]string, len(e.detectors))
-	for i, det := range e.detectors {
-		names[i] = det.Name()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	names := make([]string, len(e.registrations))
+	for i, reg := range e.registrations {
+		names[i] = reg.template.Name()
 	}
 	return names
 }
+
+// instanceFor builds a database's instance of a detector: factory-built and cloned stateful
+// detectors are private to the database; stateless ones are safe to share between databases.
+func instanceFor(reg registration) (detector.Detector, bool) {
+	if reg.newDetector != nil {
+		return reg.newDetector(), true
+	}
+	if stateful, ok := reg.template.(detector.StatefulDetector); ok {
+		return stateful.Clone(), true
+	}
+	return reg.template, false
+}
@@ -0,0 +1,144 @@
+package eventbus
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// JetStream stream and consumer settings. The Executor declares the same streams,
+// so whichever service starts first creates them.
+const (
+	detectionsStream = "DETECTIONS"
+	actionsStream    = "ACTIONS"
+	streamMaxAge     = 24 * time.Hour
+
+	completionsConsumer = "analyser-completions"
+
+	// RollbackRequestSubject carries rollback requests for actions that failed verification
+	RollbackRequestSubject = "actions.rollback.request"
+
+	// maxDeliver bounds redelivery of a message the consumer keeps failing to process
+	maxDeliver = 5
+	ackWait    = 30 * time.Second
+)
+
+var streamConfigs = []*nats.StreamConfig{
+	{
+		Name:     detectionsStream,
+		Subjects: []string{"detections", "detections.failed"},
+		Storage:  nats.FileStorage,
+		MaxAge:   streamMaxAge,
+	},
+	{
+		Name:     actionsStream,
+		Subjects: []string{"actions.status", "actions.completed", RollbackRequestSubject},
+		Storage:  nats.FileStorage,
+		MaxAge:   streamMaxAge,
+	},
+}
+
+// ensureStreams creates the detection and action streams if they do not exist yet.
+func ensureStreams(js nats.JetStreamContext) error {
+	for _, cfg := range streamConfigs {
+		if info, err := js.StreamInfo(cfg.Name); err == nil {
+			// Streams created by an older release may be missing newer subjects
+			if !sameSubjects(info.Config.Subjects, cfg.Subjects) {
+				if _, err := js.UpdateStream(cfg); err != nil {
+					return fmt.Errorf("failed to update stream %s: %w", cfg.Name, err)
+				}
+				log.Printf("Updated JetStream stream %s (%v)", cfg.Name, cfg.Subjects)
+			}
+			continue
+		} else if !errors.Is(err, nats.ErrStreamNotFound) {
+			return fmt.Errorf("failed to look up stream %s (is JetStream enabled? set NATS_JETSTREAM=false for a plain nats-server): %w", cfg.Name, err)
+		}
+
+		if _, err := js.AddStream(cfg); err != nil {
+			return fmt.Errorf("failed to create stream %s: %w", cfg.Name, err)
+		}
+		log.Printf("Created JetStream stream %s (%v)", cfg.Name, cfg.Subjects)
+	}
+
+	return nil
+}
+
+func sameSubjects(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ensureConsumer creates a durable push consumer if it does not exist yet. Creating
+// it explicitly (rather than via Subscribe) keeps it alive across unsubscribes, so
+// messages published while this service is down are delivered when it returns.
+func ensureConsumer(js nats.JetStreamContext, stream, durable, subject string) error {
+	if _, err := js.ConsumerInfo(stream, durable); err == nil {
+		return nil
+	} else if !errors.Is(err, nats.ErrConsumerNotFound) {
+		return fmt.Errorf("failed to look up consumer %s: %w", durable, err)
+	}
+
+	_, err := js.AddConsumer(stream, &nats.ConsumerConfig{
+		Durable:        durable,
+		DeliverSubject: "deliver." + durable,
+		FilterSubject:  subject,
+		DeliverPolicy:  nats.DeliverAllPolicy,
+		AckPolicy:      nats.AckExplicitPolicy,
+		AckWait:        ackWait,
+		MaxDeliver:     maxDeliver,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create consumer %s: %w", durable, err)
+	}
+
+	log.Printf("Created JetStream consumer %s on %s", durable, subject)
+	return nil
+}
+
+// ack acknowledges a JetStream message. Core NATS messages need no acknowledgement.
+func (s *Subscriber) ack(msg *nats.Msg) {
+	if s.js == nil {
+		return
+	}
+	if err := msg.Ack(); err != nil {
+		slog.Warn("Failed to ack message", "subject", msg.Subject, "error", err)
+	}
+}
+
+// nak asks JetStream to redeliver a message, giving up once maxDeliver is reached.
+func (s *Subscriber) nak(msg *nats.Msg) {
+	if s.js == nil {
+		return
+	}
+
+	if meta, err := msg.Metadata(); err == nil && meta.NumDelivered >= maxDeliver {
+		log.Printf("Giving up on message on %s after %d deliveries", msg.Subject, meta.NumDelivered)
+		s.term(msg)
+		return
+	}
+
+	if err := msg.Nak(); err != nil {
+		slog.Warn("Failed to nak message", "subject", msg.Subject, "error", err)
+	}
+}
+
+// term stops redelivery of a message that can never be processed (e.g. malformed JSON).
+func (s *Subscriber) term(msg *nats.Msg) {
+	if s.js == nil {
+		return
+	}
+	if err := msg.Term(); err != nil {
+		slog.Warn("Failed to terminate message", "subject", msg.Subject, "error", err)
+	}
+}
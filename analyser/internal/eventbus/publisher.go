@@ -2,6 +2,7 @@ package eventbus
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"time"
 
@@ -13,10 +14,12 @@ import (
 // Publisher publishes events to NATS
 type Publisher struct {
 	conn *nats.Conn
+	js   nats.JetStreamContext // nil when running on core NATS
 }
 
-// NewPublisher creates a new event bus publisher
-func NewPublisher(natsURL string) (*Publisher, error) {
+// NewPublisher creates a new event bus publisher. With useJetStream, detections are
+// persisted to the DETECTIONS stream so they survive an Executor restart.
+func NewPublisher(natsURL string, useJetStream bool) (*Publisher, error) {
 	conn, err := nats.Connect(natsURL,
 		nats.RetryOnFailedConnect(true),
 		nats.MaxReconnects(10),
@@ -28,9 +31,26 @@ func NewPublisher(natsURL string) (*Publisher, error) {
 
 	log.Printf("Analyser (Pub) connected to NATS at %s", natsURL)
 
-	return &Publisher{
+	publisher := &Publisher{
 		conn: conn,
-	}, nil
+	}
+
+	if useJetStream {
+		js, err := conn.JetStream()
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+		}
+
+		if err := ensureStreams(js); err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		publisher.js = js
+	}
+
+	return publisher, nil
 }
 
 // PublishDetection publishes a detection to the "detections" topic
@@ -40,7 +60,11 @@ func (p *Publisher) PublishDetection(detection *models.Detection) error {
 		return err
 	}
 
-	if err := p.conn.Publish("detections", data); err != nil {
+	if p.js != nil {
+		if _, err := p.js.Publish("detections", data); err != nil {
+			return err
+		}
+	} else if err := p.conn.Publish("detections", data); err != nil {
 		return err
 	}
 
@@ -49,14 +73,19 @@ func (p *Publisher) PublishDetection(detection *models.Detection) error {
 	return nil
 }
 
-// PublishRollbackRequest publishes a rollback request to the "rollback.requested" topic
+// PublishRollbackRequest publishes a rollback request to the "actions.rollback.request" topic.
+// With JetStream it is persisted, so an Executor that is down still rolls the action back.
 func (p *Publisher) PublishRollbackRequest(request *verification.RollbackRequest) error {
 	data, err := json.Marshal(request)
 	if err != nil {
 		return err
 	}
 
-	if err := p.conn.Publish("rollback.requested", data); err != nil {
+	if p.js != nil {
+		if _, err := p.js.Publish(RollbackRequestSubject, data); err != nil {
+			return err
+		}
+	} else if err := p.conn.Publish(RollbackRequestSubject, data); err != nil {
 		return err
 	}
 
@@ -66,6 +95,27 @@ func (p *Publisher) PublishRollbackRequest(request *verification.RollbackRequest
 	return nil
 }
 
+// EffectivenessSubject carries before/after metric reports for verified actions.
+const EffectivenessSubject = "actions.effectiveness"
+
+// PublishEffectiveness publishes an action's before/after metric report to
+// "actions.effectiveness". Knowledge keeps the durable copy, so core NATS is enough.
+func (p *Publisher) PublishEffectiveness(report *verification.EffectivenessReport) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	if err := p.conn.Publish(EffectivenessSubject, data); err != nil {
+		return err
+	}
+
+	log.Printf("Published effectiveness report to event bus: action=%s %s %.2f -> %.2f (%s)",
+		report.ActionID, report.Metric, report.MetricBefore, report.MetricAfter, report.Verdict)
+
+	return nil
+}
+
 // Close closes the NATS connection
 func (p *Publisher) Close() {
 	if p.conn != nil {
@@ -3,15 +3,19 @@ package eventbus
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
+	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/knowledge"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/verification"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/logging"
 	"github.com/nats-io/nats.go"
 )
 
-// ActionCompletedEvent represents an action completion event
+// ActionCompletedEvent is an action's settled outcome: completed, failed or rolled back
 type ActionCompletedEvent struct {
 	ActionID     string `json:"action_id"`
 	DetectionID  string `json:"detection_id"`
@@ -22,16 +26,21 @@ type ActionCompletedEvent struct {
 	Solution     string `json:"solution"`
 	Message      string `json:"message"`
 	Timestamp    int64  `json:"timestamp"`
+
+	CorrelationID string `json:"correlation_id,omitempty"`
 }
 
 type Subscriber struct {
 	conn                *nats.Conn
+	js                  nats.JetStreamContext // nil when running on core NATS
 	subscription        *nats.Subscription
 	knowledgeClient     *knowledge.KnowledgeClient
 	verificationTracker *verification.Tracker
 }
 
-func NewSubscriber(natsURL string, knowledgeClient *knowledge.KnowledgeClient, tracker *verification.Tracker) (*Subscriber, error) {
+// NewSubscriber connects to NATS. With useJetStream, completions are consumed from a
+// durable JetStream consumer so none are lost while the Analyser is down.
+func NewSubscriber(natsURL string, useJetStream bool, knowledgeClient *knowledge.KnowledgeClient, tracker *verification.Tracker) (*Subscriber, error) {
 	conn, err := nats.Connect(natsURL,
 		nats.RetryOnFailedConnect(true),
 		nats.MaxReconnects(10),
@@ -44,22 +53,50 @@ func NewSubscriber(natsURL string, knowledgeClient *knowledge.KnowledgeClient, t
 
 	log.Printf("Analyser (Sub) connected to NATS at %s", natsURL)
 
-	return &Subscriber{
+	subscriber := &Subscriber{
 		conn:                conn,
 		knowledgeClient:     knowledgeClient,
 		verificationTracker: tracker,
-	}, nil
+	}
+
+	if useJetStream {
+		js, err := conn.JetStream()
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+		}
+
+		if err := ensureStreams(js); err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		if err := ensureConsumer(js, actionsStream, completionsConsumer, "actions.completed"); err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		subscriber.js = js
+	}
+
+	return subscriber, nil
 }
 
-// Start begins listening for action completion events
+// Start begins listening for action outcome events
 func (s *Subscriber) Start() error {
 	var err error
 
 	log.Printf("Subscribing to 'actions.completed' for feedback loop...")
 
-	s.subscription, err = s.conn.Subscribe("actions.completed", func(msg *nats.Msg) {
-		s.handleActionCompleted(msg)
-	})
+	if s.js != nil {
+		s.subscription, err = s.js.Subscribe("actions.completed", func(msg *nats.Msg) {
+			s.handleActionCompleted(msg)
+		}, nats.Bind(actionsStream, completionsConsumer), nats.ManualAck())
+	} else {
+		s.subscription, err = s.conn.Subscribe("actions.completed", func(msg *nats.Msg) {
+			s.handleActionCompleted(msg)
+		})
+	}
 
 	if err != nil {
 		return err
@@ -71,24 +108,48 @@ func (s *Subscriber) Start() error {
 }
 
 func (s *Subscriber) handleActionCompleted(msg *nats.Msg) {
-	log.Printf("Received action completion event (%d bytes)", len(msg.Data))
+	slog.Debug("Received action completion event", "bytes", len(msg.Data))
 
 	var event ActionCompletedEvent
 	if err := json.Unmarshal(msg.Data, &event); err != nil {
-		log.Printf("Failed to unmarshal action completion: %v", err)
+		slog.Error("Failed to unmarshal action completion", "error", err)
+		s.term(msg)
 		return
 	}
 
-	// Only process completed actions (not failed)
-	if event.Status != "completed" {
-		log.Printf("Action %s not completed (status: %s), skipping verification", event.ActionID, event.Status)
+	if err := s.HandleActionOutcome(&event); err != nil {
+		s.nak(msg)
 		return
 	}
 
-	log.Printf("Action completed: %s (type: %s)", event.ActionID, event.ActionType)
-	log.Printf("   Detection: %s", event.DetectionID)
-	log.Printf("   Detection Key: %s", event.DetectionKey)
-	log.Printf("   Solution: %s", event.Solution)
+	s.ack(msg)
+}
+
+// HandleActionOutcome feeds an action's outcome back into the detection it acted on. A
+// completed action is verified against later metrics or resolves its detection straight
+// away; a failed or rolled back one drops any pending verification and reopens the
+// detection, since the fix is not in place. An error means the event should be retried.
+func (s *Subscriber) HandleActionOutcome(event *ActionCompletedEvent) error {
+	logger := logging.With(event.CorrelationID).With(
+		"action_id", event.ActionID,
+		"action_type", event.ActionType,
+		"detection_id", event.DetectionID)
+
+	switch event.Status {
+	case "completed":
+		return s.handleCompleted(event, logger)
+	case "failed", "rolled_back":
+		return s.handleUndone(event, logger)
+	default:
+		logger.Info("Action outcome not settled, skipping", "status", event.Status)
+		return nil
+	}
+}
+
+func (s *Subscriber) handleCompleted(event *ActionCompletedEvent, logger *slog.Logger) error {
+	logger.Info("Action completed",
+		"detection_key", event.DetectionKey,
+		"solution", event.Solution)
 
 	// Check if this action type supports autonomous verification
 	if s.supportsAutonomousVerification(event.ActionType) {
@@ -101,18 +162,38 @@ func (s *Subscriber) handleActionCompleted(msg *nats.Msg) {
 				event.ActionType,
 				event.DatabaseID,
 			)
-			log.Printf("Action %s added to verification queue (will verify after %d cycles)",
-				event.ActionID, verification.DefaultVerificationCycles)
+			logger.Info("Action added to verification queue", "cycles", verification.DefaultVerificationCycles)
 		} else {
-			log.Printf("Warning: Action %s has no detection key, marking resolved immediately", event.ActionID)
-			s.markResolved(event.DetectionID, event.Solution)
+			logger.Warn("Action has no detection key, marking resolved immediately")
+			return s.markResolved(event.DetectionID, event.Solution)
 		}
-	} else {
-		// Actions that can't be autonomously verified (e.g., PgBouncer, Redis)
-		// Mark as resolved immediately
-		log.Printf("Action type %s does not support autonomous verification, marking resolved", event.ActionType)
-		s.markResolved(event.DetectionID, event.Solution)
+		return nil
 	}
+
+	// Actions that can't be autonomously verified (e.g., PgBouncer, Redis)
+	// Mark as resolved immediately
+	logger.Info("Action type does not support autonomous verification, marking resolved")
+	return s.markResolved(event.DetectionID, event.Solution)
+}
+
+func (s *Subscriber) handleUndone(event *ActionCompletedEvent, logger *slog.Logger) error {
+	if s.verificationTracker != nil && s.verificationTracker.CancelVerification(event.DetectionKey, event.ActionID) {
+		logger.Info("Pending verification cancelled", "status", event.Status)
+	}
+
+	if event.DetectionID == "" {
+		logger.Warn("Action outcome has no detection, nothing to reopen", "status", event.Status)
+		return nil
+	}
+
+	reason := "action " + event.ActionID + " " + strings.ReplaceAll(event.Status, "_", " ")
+	if err := s.knowledgeClient.ReopenDetection(context.Background(), event.DetectionID, reason); err != nil {
+		logger.Warn("Failed to reopen detection in Knowledge", "error", err)
+		return err
+	}
+
+	logger.Info("Detection reopened", "status", event.Status)
+	return nil
 }
 
 // supportsAutonomousVerification returns true if the action type can be verified by monitoring metrics
@@ -124,7 +205,7 @@ func (s *Subscriber) supportsAutonomousVerification(actionType string) bool {
 	case "tune_config_high_latency":
 		// Can verify: latency should decrease
 		return true
-	case "cache_optimization_recommendation":
+	case "cache_optimization_recommendation", "increase_cache_size":
 		// Can verify: cache hit rate should increase
 		return true
 	case "deploy_connection_pooler", "deploy_redis":
@@ -135,13 +216,14 @@ func (s *Subscriber) supportsAutonomousVerification(actionType string) bool {
 	}
 }
 
-func (s *Subscriber) markResolved(detectionID, solution string) {
+func (s *Subscriber) markResolved(detectionID, solution string) error {
 	ctx := context.Background()
 	if err := s.knowledgeClient.MarkDetectionResolved(ctx, detectionID, solution); err != nil {
-		log.Printf("Warning: Failed to mark detection resolved in Knowledge: %v", err)
-		return
+		slog.Warn("Failed to mark detection resolved in Knowledge", "error", err)
+		return err
 	}
 	log.Printf("Detection marked as resolved in Knowledge: %s", detectionID)
+	return nil
 }
 
 func (s *Subscriber) Close() {
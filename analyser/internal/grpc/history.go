@@ -0,0 +1,42 @@
+package grpcserver
+
+import (
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+)
+
+// historyValues picks the health scores and key measurements kept in Knowledge's metric
+// history for a snapshot. Measurements the database didn't report are left out.
+func historyValues(snapshot *normaliser.NormalisedMetrics) map[string]float64 {
+	values := map[string]float64{
+		"health_score":      snapshot.HealthScore,
+		"connection_health": snapshot.ConnectionHealth,
+		"query_health":      snapshot.QueryHealth,
+		"storage_health":    snapshot.StorageHealth,
+		"cache_health":      snapshot.CacheHealth,
+	}
+
+	m := snapshot.Measurements
+	if m.ActiveConnections != nil {
+		values["active_connections"] = float64(*m.ActiveConnections)
+	}
+	if m.WaitingConnections != nil {
+		values["waiting_connections"] = float64(*m.WaitingConnections)
+	}
+	if m.AvgQueryLatencyMs != nil {
+		values["avg_query_latency_ms"] = *m.AvgQueryLatencyMs
+	}
+	if m.P95QueryLatencyMs != nil {
+		values["p95_query_latency_ms"] = *m.P95QueryLatencyMs
+	}
+	if m.CacheHitRate != nil {
+		values["cache_hit_rate"] = *m.CacheHitRate
+	}
+	if m.UsedStorageBytes != nil {
+		values["used_storage_bytes"] = float64(*m.UsedStorageBytes)
+	}
+	if delta, ok := snapshot.MetricDeltas["sequential_scans"]; ok && snapshot.DeltasReliable {
+		values["sequential_scans_per_interval"] = delta
+	}
+
+	return values
+}
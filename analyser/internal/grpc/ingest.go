@@ -0,0 +1,165 @@
+package grpcserver
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/metrics"
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+)
+
+// Reasons a snapshot is rejected before it reaches the detectors.
+const (
+	RejectMissingDatabaseID = "missing_database_id"
+	RejectMissingTimestamp  = "missing_timestamp"
+	RejectFutureTimestamp   = "future_timestamp"
+	RejectDuplicate         = "duplicate_timestamp"
+	RejectOutOfOrder        = "out_of_order"
+	RejectRateLimited       = "rate_limited"
+)
+
+const (
+	// DefaultMaxSnapshotsPerMinute is the per-database snapshot rate accepted by default;
+	// a Collector's normal interval is 10s or more
+	DefaultMaxSnapshotsPerMinute = 60
+
+	// maxClockSkew is how far ahead of the Analyser's clock a snapshot timestamp may be
+	maxClockSkew = 5 * time.Minute
+
+	// rejectionLogInterval limits rejection warnings to one per database and reason
+	rejectionLogInterval = time.Minute
+
+	// maxRejectionsPerAck caps the rejections listed in one ack
+	maxRejectionsPerAck = 50
+)
+
+// ingestGuard validates snapshots and limits how fast each database may send them. It is
+// shared by every stream, so a Collector reconnecting can't reset its own limits.
+type ingestGuard struct {
+	mu            sync.Mutex
+	perMinute     int // 0 disables rate limiting
+	lastTimestamp map[string]int64
+	buckets       map[string]*tokenBucket
+	warnings      map[string]*rejectionWarning // database ID + reason
+	now           func() time.Time
+}
+
+// tokenBucket allows perMinute snapshots a minute, with bursts up to perMinute so a
+// Collector resending its buffer after a reconnect is not cut off.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rejectionWarning tracks the rejections not yet logged for a database and reason.
+type rejectionWarning struct {
+	loggedAt   time.Time
+	suppressed int64
+}
+
+func newIngestGuard(perMinute int) *ingestGuard {
+	return &ingestGuard{
+		perMinute:     perMinute,
+		lastTimestamp: map[string]int64{},
+		buckets:       map[string]*tokenBucket{},
+		warnings:      map[string]*rejectionWarning{},
+		now:           time.Now,
+	}
+}
+
+func (g *ingestGuard) setRateLimit(perMinute int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.perMinute = perMinute
+	g.buckets = map[string]*tokenBucket{}
+}
+
+// admit returns the reason snapshot must be dropped, or "" if the detectors should run on it.
+func (g *ingestGuard) admit(snapshot *pb.MetricSnapshot) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+
+	switch {
+	case snapshot.DatabaseId == "":
+		return RejectMissingDatabaseID
+	case snapshot.Timestamp <= 0:
+		return RejectMissingTimestamp
+	case time.Unix(snapshot.Timestamp, 0).After(now.Add(maxClockSkew)):
+		return RejectFutureTimestamp
+	}
+
+	if last, ok := g.lastTimestamp[snapshot.DatabaseId]; ok {
+		if snapshot.Timestamp == last {
+			return RejectDuplicate
+		}
+		if snapshot.Timestamp < last {
+			return RejectOutOfOrder
+		}
+	}
+
+	// Replayed snapshots are a Collector catching up after an outage, not a runaway one
+	if g.perMinute > 0 && !snapshot.Replayed && !g.takeToken(snapshot.DatabaseId, now) {
+		return RejectRateLimited
+	}
+
+	g.lastTimestamp[snapshot.DatabaseId] = snapshot.Timestamp
+	return ""
+}
+
+// takeToken spends one of databaseID's tokens. Caller must hold mu.
+func (g *ingestGuard) takeToken(databaseID string, now time.Time) bool {
+	capacity := float64(g.perMinute)
+
+	bucket, ok := g.buckets[databaseID]
+	if !ok {
+		bucket = &tokenBucket{tokens: capacity, last: now}
+		g.buckets[databaseID] = bucket
+	}
+
+	refill := now.Sub(bucket.last).Minutes() * capacity
+	bucket.tokens = min(capacity, bucket.tokens+refill)
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// recordRejection counts a dropped snapshot and logs a warning, at most once per
+// rejectionLogInterval for each database and reason.
+func (g *ingestGuard) recordRejection(snapshot *pb.MetricSnapshot, reason string) {
+	metrics.SnapshotsRejected.WithLabelValues(snapshot.DatabaseId, reason).Inc()
+
+	g.mu.Lock()
+	key := snapshot.DatabaseId + "|" + reason
+	warning, ok := g.warnings[key]
+	if !ok {
+		warning = &rejectionWarning{}
+		g.warnings[key] = warning
+	}
+
+	now := g.now()
+	if now.Sub(warning.loggedAt) < rejectionLogInterval {
+		warning.suppressed++
+		g.mu.Unlock()
+		return
+	}
+
+	suppressed := warning.suppressed
+	warning.loggedAt = now
+	warning.suppressed = 0
+	g.mu.Unlock()
+
+	slog.Warn("Snapshot rejected",
+		"database_id", snapshot.DatabaseId,
+		"sequence", snapshot.Sequence,
+		"timestamp", snapshot.Timestamp,
+		"reason", reason,
+		"suppressed_since_last_warning", suppressed)
+}
@@ -2,25 +2,56 @@ package grpcserver
 
 import (
 	"context"
-	"fmt"
 	"io"
 	"log"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
 
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/config"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/detector"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/engine"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/eventbus"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/knowledge"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/metrics"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/models"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/verification"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
 	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/detectionkey"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/logging"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/policy"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// DefaultAckInterval is how often the Analyser acknowledges snapshots on a metrics stream.
+const DefaultAckInterval = 2 * time.Second
+
+// DefaultValueChangeThreshold is the relative change in an active detection's value that
+// re-publishes it as updated.
+const DefaultValueChangeThreshold = 0.2
+
 type MetricsServer struct {
 	pb.UnimplementedMetricsServiceServer
 	engine              *engine.Engine
 	publisher           *eventbus.Publisher
 	knowledgeClient     *knowledge.KnowledgeClient
 	verificationTracker *verification.Tracker // NEW: for autonomous rollback
+	ackInterval         time.Duration         // How often snapshots on a stream are acknowledged
+	ingest              *ingestGuard          // Validation and per-database rate limits
+	valueChange         float64               // Relative value change that re-publishes an active detection
+	thresholdsFor       func(databaseID string) config.DetectionThresholds
+	policies            *policy.Cache // Per-database policies from Knowledge; nil applies none
+
+	snapshotsMu   sync.RWMutex
+	lastSnapshots map[string]*normaliser.NormalisedMetrics // Latest snapshot per database
+
+	// Set by Drain; drainMu orders it against snapshots starting to process
+	drainMu    sync.RWMutex
+	draining   bool
+	processing sync.WaitGroup
 }
 
 func NewMetricsServer(
@@ -29,141 +60,459 @@ func NewMetricsServer(
 	kc *knowledge.KnowledgeClient,
 	tracker *verification.Tracker, // NEW
 ) *MetricsServer {
-	return &MetricsServer{
+	s := &MetricsServer{
 		engine:              eng,
 		publisher:           pub,
 		knowledgeClient:     kc,
 		verificationTracker: tracker,
+		ackInterval:         DefaultAckInterval,
+		ingest:              newIngestGuard(DefaultMaxSnapshotsPerMinute),
+		valueChange:         DefaultValueChangeThreshold,
+		lastSnapshots:       make(map[string]*normaliser.NormalisedMetrics),
 	}
+	if kc != nil {
+		s.policies = policy.NewCache(kc.GetDatabasePolicy, policy.DefaultCacheTTL)
+	}
+	return s
 }
 
-// generateDetectionKey creates a unique key for deduplication
-func (s *MetricsServer) generateDetectionKey(detection *models.Detection) string {
-	issueIdentifier := s.extractIssueIdentifier(detection)
+// SetAckInterval changes how often snapshots are acknowledged.
+func (s *MetricsServer) SetAckInterval(interval time.Duration) {
+	s.ackInterval = interval
+}
 
-	return fmt.Sprintf("%s:%s:%s",
-		detection.DatabaseID,
-		detection.DetectorName,
-		issueIdentifier,
-	)
+// SetSnapshotRateLimit changes how many snapshots a minute are accepted per database;
+// 0 disables the limit.
+func (s *MetricsServer) SetSnapshotRateLimit(perMinute int) {
+	s.ingest.setRateLimit(perMinute)
 }
 
-// extractIssueIdentifier gets the unique part from detection metadata
-func (s *MetricsServer) extractIssueIdentifier(detection *models.Detection) string {
-	if detection.ActionMetadata != nil {
-		if table, hasTable := detection.ActionMetadata["table_name"].(string); hasTable {
-			if column, hasColumn := detection.ActionMetadata["column_name"].(string); hasColumn {
-				return fmt.Sprintf("%s.%s", table, column)
-			}
-			return table
-		}
+// SetValueChangeThreshold changes the relative change in an active detection's value that
+// re-publishes it; 0 disables change-based re-publishing.
+func (s *MetricsServer) SetValueChangeThreshold(threshold float64) {
+	s.valueChange = threshold
+}
 
-		if identifier, ok := detection.ActionMetadata["identifier"].(string); ok {
-			return identifier
-		}
+// SetThresholdSource sets where GetRegisteredDetectors reads the thresholds in effect for a
+// database; without one it lists detectors without thresholds.
+func (s *MetricsServer) SetThresholdSource(thresholdsFor func(databaseID string) config.DetectionThresholds) {
+	s.thresholdsFor = thresholdsFor
+}
+
+// SetPolicyCacheTTL changes how long a database's policy is used before it is read from
+// Knowledge again.
+func (s *MetricsServer) SetPolicyCacheTTL(ttl time.Duration) {
+	if s.knowledgeClient != nil {
+		s.policies = policy.NewCache(s.knowledgeClient.GetDatabasePolicy, ttl)
 	}
+}
 
-	if detection.Evidence != nil {
-		if identifier, ok := detection.Evidence["identifier"].(string); ok {
-			return identifier
-		}
+// databasePolicy returns the policy for a database, or nil when there is none or it can't
+// be read. A failed read keeps the last policy read in force.
+func (s *MetricsServer) databasePolicy(ctx context.Context, logger *slog.Logger, databaseID string) *pb.DatabasePolicy {
+	if s.policies == nil {
+		return nil
+	}
 
-		if queryHash, ok := detection.Evidence["query_hash"].(string); ok {
-			return queryHash
-		}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	p, err := s.policies.Get(ctx, databaseID)
+	if err != nil {
+		logger.Warn("Failed to read database policy, using the last one read", "error", err)
+	}
+	return p
+}
+
+// valueChanged reports whether current has moved far enough from the stored value to
+// re-publish. A stored value of zero predates value tracking, so there is nothing to compare.
+func (s *MetricsServer) valueChanged(stored, current float64) bool {
+	if s.valueChange <= 0 || stored == 0 {
+		return false
 	}
+	return math.Abs(current-stored)/math.Abs(stored) > s.valueChange
+}
+
+// LastSnapshot returns the most recent snapshot processed for a database, or nil.
+func (s *MetricsServer) LastSnapshot(databaseID string) *normaliser.NormalisedMetrics {
+	s.snapshotsMu.RLock()
+	defer s.snapshotsMu.RUnlock()
+	return s.lastSnapshots[databaseID]
+}
 
-	return string(detection.Category)
+// generateDetectionKey creates the key detections of the same issue share, for deduplication
+func (s *MetricsServer) generateDetectionKey(detection *models.Detection) string {
+	identifier := detectionkey.Identifier(detection.ActionMetadata, detection.Evidence, string(detection.Category))
+	return detectionkey.Build(detection.DatabaseID, detection.DetectorName, identifier)
 }
 
+// StreamMetrics receives snapshots over one long-lived stream from the Collector. Snapshots
+// are validated and processed as they arrive; acks are sent every ackInterval with the
+// highest sequence received, so the Collector can drop what it holds for resending, and
+// the snapshots rejected since the previous ack, so it can log what was dropped.
 func (s *MetricsServer) StreamMetrics(stream pb.MetricsService_StreamMetricsServer) error {
-	log.Println("Client connected, waiting for metrics stream...")
+	slog.Info("Collector stream opened")
+
+	var (
+		mu           sync.Mutex
+		received     int64
+		acked        int64
+		lastSequence uint64
+		accepted     int64
+		rejected     int64
+		rejections   []*pb.SnapshotRejection
+	)
 
-	metricsCount := int64(0)
+	// sendAck serialises Send calls between the ticker and the final ack
+	sendAck := func() error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// Nothing new since the last ack
+		if received == acked {
+			return nil
+		}
+
+		status := "healthy"
+		if rejected > 0 {
+			status = "degraded"
+		}
+
+		if err := stream.Send(&pb.MetricsAck{
+			TotalMetrics:  received,
+			Status:        status,
+			AckedSequence: lastSequence,
+			Accepted:      accepted,
+			Rejected:      rejected,
+			Rejections:    rejections,
+		}); err != nil {
+			return err
+		}
+		acked = received
+		accepted, rejected, rejections = 0, 0, nil
+		return nil
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(s.ackInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := sendAck(); err != nil {
+					slog.Warn("Failed to send metrics ack", "error", err)
+					return
+				}
+			}
+		}
+	}()
+
+	stopAcks := func() {
+		close(done)
+		wg.Wait()
+	}
 
 	for {
 		snapshot, err := stream.Recv()
 		if err == io.EOF {
-			log.Printf("Stream closed. Total metrics received: %d", metricsCount)
-			return stream.SendAndClose(&pb.MetricsAck{
-				TotalMetrics: metricsCount,
-				Status:       "healthy",
-			})
+			stopAcks()
+			slog.Info("Collector stream closed", "snapshots", received)
+			return sendAck()
 		}
 
 		if err != nil {
-			log.Printf("Error receiving metric: %v", err)
+			stopAcks()
+			slog.Error("Metrics stream failed", "error", err, "snapshots", received)
 			return err
 		}
 
-		metricsCount++
-		log.Printf("Metric #%d received:", metricsCount)
-		log.Printf("  Database: %s (%s)", snapshot.DatabaseId, snapshot.DatabaseType)
-		log.Printf("  Health Score: %.2f", snapshot.HealthScore)
+		// Once draining, the snapshot is left unacked so the Collector resends it to the
+		// next Analyser, and the stream ends after acking what was processed
+		if !s.beginProcessing() {
+			stopAcks()
+			slog.Info("Analyser shutting down, closing Collector stream", "snapshots", received)
+			if err := sendAck(); err != nil {
+				return err
+			}
+			return status.Error(codes.Unavailable, "analyser is shutting down")
+		}
 
-		normalised := s.toNormalisedMetrics(snapshot)
+		// Rejected snapshots are still acked: resending them would be rejected again
+		reason := s.ingest.admit(snapshot)
+		if reason == "" {
+			s.processSnapshot(snapshot)
+		} else {
+			s.ingest.recordRejection(snapshot, reason)
+		}
+		s.processing.Done()
 
-		detections := s.engine.RunDetectors(normalised)
+		mu.Lock()
+		received++
+		lastSequence = max(lastSequence, snapshot.Sequence)
+		if reason == "" {
+			accepted++
+		} else {
+			rejected++
+			if len(rejections) < maxRejectionsPerAck {
+				rejections = append(rejections, &pb.SnapshotRejection{
+					Sequence:   snapshot.Sequence,
+					DatabaseId: snapshot.DatabaseId,
+					Reason:     reason,
+				})
+			}
+		}
+		mu.Unlock()
+	}
+}
 
-		if len(detections) > 0 {
-			log.Printf("Found %d issues in database: %s", len(detections), snapshot.DatabaseId)
+// beginProcessing registers a snapshot about to be processed, or reports false once Drain
+// has been called.
+func (s *MetricsServer) beginProcessing() bool {
+	s.drainMu.RLock()
+	defer s.drainMu.RUnlock()
 
-			publishedCount := 0
-			skippedCount := 0
-			rollbackTriggered := 0
+	if s.draining {
+		return false
+	}
+	s.processing.Add(1)
+	return true
+}
+
+// Drain stops processing new snapshots and waits, up to timeout, for those being processed
+// to finish registering and publishing their detections. It reports whether they all did.
+func (s *MetricsServer) Drain(timeout time.Duration) bool {
+	s.drainMu.Lock()
+	s.draining = true
+	s.drainMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.processing.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
 
-			for _, detection := range detections {
-				key := s.generateDetectionKey(detection)
-				detection.Key = key
+// processSnapshot runs the detectors over one snapshot and publishes what they find.
+func (s *MetricsServer) processSnapshot(snapshot *pb.MetricSnapshot) {
+	logger := logging.With(snapshot.CorrelationId).With("database_id", snapshot.DatabaseId)
+	logger.Debug("Snapshot received",
+		"sequence", snapshot.Sequence,
+		"database_type", snapshot.DatabaseType,
+		"health_score", snapshot.HealthScore)
 
-				// NEW: Check if this detection has a pending verification
-				// If so, the previous action didn't help - trigger rollback
-				if s.verificationTracker != nil && s.verificationTracker.OnDetectionFired(key) {
-					log.Printf("Detection re-fired during verification period, rollback triggered: %s", key)
-					rollbackTriggered++
-					continue // Don't publish this detection again, rollback is in progress
-				}
+	metrics.SnapshotsProcessed.WithLabelValues(snapshot.DatabaseId).Inc()
+
+	normalised := s.toNormalisedMetrics(snapshot)
+
+	// Kept in Knowledge so trends survive restarts and the Dashboard can chart them
+	if s.knowledgeClient != nil {
+		ctx := logging.WithCorrelationID(context.Background(), snapshot.CorrelationId)
+		if err := s.knowledgeClient.RecordMetricSample(ctx, snapshot.DatabaseId, snapshot.Timestamp, historyValues(normalised)); err != nil {
+			logger.Warn("Failed to record metric history", "error", err)
+		}
+	}
+
+	// A replayed snapshot describes the database during an outage, not now: it fills the
+	// history but acting on it, or measuring actions against it, would be wrong
+	if snapshot.Replayed {
+		logger.Debug("Replayed snapshot recorded, detectors skipped", "timestamp", snapshot.Timestamp)
+		return
+	}
+
+	s.snapshotsMu.Lock()
+	s.lastSnapshots[snapshot.DatabaseId] = normalised
+	s.snapshotsMu.Unlock()
 
-				ctx := context.Background()
-				isActive, err := s.knowledgeClient.IsDetectionActive(ctx, key)
-				if err != nil {
-					log.Printf("Warning: failed to check knowledge: %v (publishing anyway)", err)
-				} else if isActive {
-					log.Printf("Detection already active, skipping: %s (key: %s)", detection.Title, key)
-					skippedCount++
-					continue
+	detectStart := time.Now()
+	detections := s.engine.RunDetectors(normalised)
+	metrics.DetectionDuration.Observe(time.Since(detectStart).Seconds())
+
+	if len(detections) > 0 {
+		byDetector := make(map[string]int)
+		for _, detection := range detections {
+			byDetector[detection.DetectorName]++
+		}
+		logger.Info("Issues found", "count", len(detections), "by_detector", byDetector)
+
+		publishedCount := 0
+		skippedCount := 0
+		escalatedCount := 0
+		updatedCount := 0
+		suppressedCount := 0
+		ignoredCount := 0
+		rollbackTriggered := 0
+
+		// Read once per snapshot: every detection in it is for the same database
+		dbPolicy := s.databasePolicy(logging.WithCorrelationID(context.Background(), snapshot.CorrelationId), logger, snapshot.DatabaseId)
+
+		for _, detection := range detections {
+			key := s.generateDetectionKey(detection)
+			detection.Key = key
+			detLogger := logger.With("detection_key", key, "detector", detection.DetectorName)
+
+			// Operators asked never to hear from this detector about this database
+			if policy.DetectorIgnored(dbPolicy, detection.DetectorName) {
+				detLogger.Debug("Detector ignored by database policy, skipping")
+				ignoredCount++
+				metrics.DetectionsSuppressed.WithLabelValues(detection.DetectorName, metrics.SuppressedByPolicy).Inc()
+				continue
+			}
+
+			// NEW: Check if this detection has a pending verification
+			// If so, the previous action didn't help - trigger rollback
+			if s.verificationTracker != nil && s.verificationTracker.OnDetectionFired(key) {
+				detLogger.Info("Detection re-fired during verification period, rollback triggered")
+				rollbackTriggered++
+				metrics.DetectionsSuppressed.WithLabelValues(detection.DetectorName, metrics.SuppressedVerification).Inc()
+				continue // Don't publish this detection again, rollback is in progress
+			}
+
+			// Kept as the before value should an action be taken for this detection
+			if s.verificationTracker != nil {
+				s.verificationTracker.RecordBaseline(key, detection.ActionType, normalised)
+			}
+
+			ctx := logging.WithCorrelationID(context.Background(), snapshot.CorrelationId)
+			status, err := s.knowledgeClient.IsDetectionActive(ctx, detection)
+			if err != nil {
+				detLogger.Warn("Failed to check Knowledge, publishing anyway", "error", err)
+			} else if status.Suppressed {
+				detLogger.Debug("Detection key suppressed, skipping", "reason", status.SuppressionReason)
+				// Keep an already active detection listed, with its suppression reason
+				if status.IsActive {
+					if err := s.knowledgeClient.RefreshDetection(ctx, key); err != nil {
+						detLogger.Warn("Failed to refresh detection last seen", "error", err)
+					}
+				}
+				suppressedCount++
+				metrics.DetectionsSuppressed.WithLabelValues(detection.DetectorName, metrics.SuppressedByOperator).Inc()
+				continue
+			} else if status.IsActive && detection.Severity.Rank() > status.Severity.Rank() {
+				// Severity escalated: update the existing detection and re-publish it so
+				// the Executor and Dashboard can react to the new severity
+				detLogger.Info("Detection escalated",
+					"detection_id", status.DetectionID,
+					"from", status.Severity,
+					"to", detection.Severity)
+				if err := s.knowledgeClient.UpdateDetectionSeverity(ctx, status.DetectionID, detection.Severity, detection.Value); err != nil {
+					detLogger.Warn("Failed to update detection severity", "error", err)
 				}
 
-				log.Printf("\t[%s] %s", detection.Severity, detection.Title)
-				log.Printf("\t%s", detection.Description)
-				log.Printf("\tRecommendation: %s", detection.Recommendation)
+				detection.ID = status.DetectionID
+				detection.Escalated = true
 
-				if err := s.knowledgeClient.RegisterDetection(ctx, detection); err != nil {
-					log.Printf("Warning: failed to register with knowledge: %v", err)
+				if err := s.publisher.PublishDetection(detection); err != nil {
+					detLogger.Error("Failed to publish escalated detection", "error", err)
+					metrics.DetectionsSuppressed.WithLabelValues(detection.DetectorName, metrics.SuppressedPublishFailed).Inc()
+				} else {
+					escalatedCount++
+					metrics.DetectionsFired.WithLabelValues(detection.DetectorName, string(detection.Severity)).Inc()
+				}
+				continue
+			} else if status.IsActive && s.valueChanged(status.Value, detection.Value) {
+				// The issue moved significantly without changing severity, e.g. a cache hit
+				// rate sliding from 85% to 40%: store the new value and re-publish
+				detLogger.Info("Detection value changed",
+					"detection_id", status.DetectionID,
+					"from", status.Value,
+					"to", detection.Value)
+				if err := s.knowledgeClient.UpdateDetectionSeverity(ctx, status.DetectionID, detection.Severity, detection.Value); err != nil {
+					detLogger.Warn("Failed to update detection value", "error", err)
 				}
 
+				detection.ID = status.DetectionID
+				detection.Updated = true
+
 				if err := s.publisher.PublishDetection(detection); err != nil {
-					log.Printf("\tFailed to publish detection event: %v", err)
+					detLogger.Error("Failed to publish updated detection", "error", err)
+					metrics.DetectionsSuppressed.WithLabelValues(detection.DetectorName, metrics.SuppressedPublishFailed).Inc()
 				} else {
-					log.Printf("\tPublished to event bus")
-					publishedCount++
+					updatedCount++
+					metrics.DetectionsFired.WithLabelValues(detection.DetectorName, string(detection.Severity)).Inc()
+				}
+				continue
+			} else if status.IsActive {
+				detLogger.Debug("Detection already active, skipping", "detection_id", status.DetectionID)
+				// Detections registered before values were tracked get one to compare against
+				if status.Value == 0 && detection.Value != 0 {
+					if err := s.knowledgeClient.UpdateDetectionSeverity(ctx, status.DetectionID, status.Severity, detection.Value); err != nil {
+						detLogger.Warn("Failed to record detection value", "error", err)
+					}
+				}
+				if err := s.knowledgeClient.RefreshDetection(ctx, key); err != nil {
+					detLogger.Warn("Failed to refresh detection last seen", "error", err)
 				}
+				skippedCount++
+				metrics.DetectionsSuppressed.WithLabelValues(detection.DetectorName, metrics.SuppressedAlreadyActive).Inc()
+				continue
 			}
 
-			log.Printf("Detection Summary: %d published, %d skipped, %d triggered rollback",
-				publishedCount, skippedCount, rollbackTriggered)
-		} else {
-			log.Printf("No issues detected in database: %s", snapshot.DatabaseId)
+			detLogger = detLogger.With("detection_id", detection.ID)
+			detLogger.Debug("Detection details",
+				"description", detection.Description,
+				"recommendation", detection.Recommendation)
+
+			// Another Analyser (or an earlier snapshot) may have registered this key since the
+			// check above; only the one whose registration lands publishes
+			registered, activeID, err := s.knowledgeClient.RegisterDetectionIfAbsent(ctx, detection)
+			if err != nil {
+				detLogger.Warn("Failed to register detection with Knowledge, publishing anyway", "error", err)
+			} else if !registered {
+				detLogger.Debug("Detection registered concurrently elsewhere, skipping", "active_detection_id", activeID)
+				skippedCount++
+				metrics.DetectionsSuppressed.WithLabelValues(detection.DetectorName, metrics.SuppressedAlreadyActive).Inc()
+				continue
+			}
+
+			if err := s.publisher.PublishDetection(detection); err != nil {
+				detLogger.Error("Failed to publish detection", "error", err)
+				metrics.DetectionsSuppressed.WithLabelValues(detection.DetectorName, metrics.SuppressedPublishFailed).Inc()
+			} else {
+				detLogger.Info("Detection published",
+					"severity", detection.Severity,
+					"title", detection.Title,
+					"action_type", detection.ActionType)
+				publishedCount++
+				metrics.DetectionsFired.WithLabelValues(detection.DetectorName, string(detection.Severity)).Inc()
+			}
 		}
 
-		// NEW: After each collection cycle, update verification tracker
-		// This increments cycle counts and marks verified actions as resolved
-		if s.verificationTracker != nil {
-			s.verificationTracker.OnCollectionCycle()
+		logger.Info("Detection summary",
+			"published", publishedCount,
+			"escalated", escalatedCount,
+			"updated", updatedCount,
+			"skipped", skippedCount,
+			"suppressed", suppressedCount,
+			"ignored_by_policy", ignoredCount,
+			"rollback_triggered", rollbackTriggered)
+	} else {
+		logger.Debug("No issues detected")
+	}
 
-			if pending := s.verificationTracker.GetPendingCount(); pending > 0 {
-				log.Printf("Pending verifications: %d", pending)
-			}
+	// Each snapshot is one collection cycle for its database: advance that database's
+	// pending verifications and mark those that held up as resolved
+	if s.verificationTracker != nil {
+		s.verificationTracker.OnDatabaseCycle(snapshot.DatabaseId)
+		pending := s.verificationTracker.GetPendingCount()
+		metrics.PendingVerifications.Set(float64(pending))
+
+		if pending > 0 {
+			slog.Debug("Pending verifications", "count", pending)
 		}
 	}
 }
@@ -178,11 +527,43 @@ func (s *MetricsServer) RegisterDatabase(ctx context.Context, info *pb.DatabaseI
 	}, nil
 }
 
+// GetRegisteredDetectors lists the detectors this Analyser runs, in run order, with the
+// thresholds each reads as currently in effect for the requested database.
+func (s *MetricsServer) GetRegisteredDetectors(ctx context.Context, req *pb.RegisteredDetectorsRequest) (*pb.RegisteredDetectorsResponse, error) {
+	var values map[string]float64
+	if s.thresholdsFor != nil {
+		values = s.thresholdsFor(req.DatabaseId).Values()
+	}
+
+	resp := &pb.RegisteredDetectorsResponse{}
+	for _, name := range s.engine.GetRegisteredDetectors() {
+		registered := &pb.RegisteredDetector{Name: name}
+
+		if reg, ok := detector.Lookup(name); ok {
+			registered.Category = string(reg.New().Category())
+			if values != nil {
+				registered.Thresholds = make(map[string]float64, len(reg.Thresholds))
+				for _, threshold := range reg.Thresholds {
+					registered.Thresholds[threshold] = values[threshold]
+				}
+			}
+		}
+
+		resp.Detectors = append(resp.Detectors, registered)
+	}
+
+	return resp, nil
+}
+
 func (s *MetricsServer) toNormalisedMetrics(snapshot *pb.MetricSnapshot) *normaliser.NormalisedMetrics {
 	normalised := &normaliser.NormalisedMetrics{
-		DatabaseID:   snapshot.DatabaseId,
-		DatabaseType: snapshot.DatabaseType,
-		Timestamp:    snapshot.Timestamp,
+		DatabaseID:     snapshot.DatabaseId,
+		DatabaseType:   snapshot.DatabaseType,
+		Timestamp:      snapshot.Timestamp,
+		ElapsedSeconds: snapshot.ElapsedSeconds,
+		CorrelationID:  snapshot.CorrelationId,
+
+		CollectionIntervalSeconds: snapshot.CollectionIntervalSeconds,
 
 		HealthScore:      snapshot.HealthScore,
 		ConnectionHealth: snapshot.ConnectionHealth,
@@ -204,6 +585,13 @@ func (s *MetricsServer) toNormalisedMetrics(snapshot *pb.MetricSnapshot) *normal
 		normalised.TimeDeltaSeconds = *snapshot.TimeDeltaSeconds
 	}
 
+	// Collectors that predate the flag only sent deltas over a positive interval
+	if snapshot.DeltasReliable != nil {
+		normalised.DeltasReliable = *snapshot.DeltasReliable
+	} else {
+		normalised.DeltasReliable = normalised.TimeDeltaSeconds > 0
+	}
+
 	if snapshot.Measurements != nil {
 		normalised.Measurements = normaliser.Measurements{
 			ActiveConnections:  snapshot.Measurements.ActiveConnections,
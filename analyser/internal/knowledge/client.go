@@ -2,13 +2,17 @@ package knowledge
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"log/slog"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/verification"
 	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/transport"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 type KnowledgeClient struct {
@@ -16,10 +20,13 @@ type KnowledgeClient struct {
 	client pb.KnowledgeServiceClient
 }
 
-func NewKnowledgeClient(addr string) (*KnowledgeClient, error) {
-	conn, err := grpc.NewClient(addr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+func NewKnowledgeClient(addr string, tlsConfig transport.TLSConfig) (*KnowledgeClient, error) {
+	credentials, err := tlsConfig.DialOption()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.NewClient(addr, credentials)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Knowledge service at %s: %w", addr, err)
 	}
@@ -32,35 +39,144 @@ func NewKnowledgeClient(addr string) (*KnowledgeClient, error) {
 	}, nil
 }
 
-func (k *KnowledgeClient) IsDetectionActive(ctx context.Context, key string) (bool, error) {
+// DetectionStatus describes the active detection Knowledge holds for a key, and whether
+// operators have suppressed the key.
+type DetectionStatus struct {
+	IsActive    bool
+	DetectionID string
+	Severity    models.DetectionSeverity
+	Value       float64
+
+	Suppressed        bool
+	SuppressionReason string
+}
+
+// IsDetectionActive reports whether a detection with the same key is active, along with its stored severity.
+func (k *KnowledgeClient) IsDetectionActive(ctx context.Context, detection *models.Detection) (*DetectionStatus, error) {
 	resp, err := k.client.IsDetectionActive(ctx, &pb.DetectionKeyRequest{
+		Key:      detection.Key,
+		Severity: string(detection.Severity),
+		Value:    detection.Value,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &DetectionStatus{
+		IsActive:    resp.IsActive,
+		DetectionID: resp.DetectionId,
+		Severity:    models.DetectionSeverity(resp.Severity),
+		Value:       resp.Value,
+
+		Suppressed:        resp.Suppressed,
+		SuppressionReason: resp.SuppressionReason,
+	}, nil
+}
+
+// UpdateDetectionSeverity stores a new severity and value on an active detection.
+func (k *KnowledgeClient) UpdateDetectionSeverity(ctx context.Context, detectionID string, severity models.DetectionSeverity, value float64) error {
+	resp, err := k.client.UpdateDetectionSeverity(ctx, &pb.UpdateDetectionSeverityRequest{
+		DetectionId: detectionID,
+		Severity:    string(severity),
+		Value:       value,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update detection severity: %w", err)
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("knowledge rejected severity update: %s", resp.Message)
+	}
+
+	return nil
+}
+
+// RefreshDetection tells Knowledge the detection for key is still firing, so it is not swept as stale.
+func (k *KnowledgeClient) RefreshDetection(ctx context.Context, key string) error {
+	resp, err := k.client.RefreshDetection(ctx, &pb.DetectionKeyRequest{
 		Key: key,
 	})
 	if err != nil {
-		return false, err
+		return fmt.Errorf("failed to refresh detection: %w", err)
 	}
 
-	return resp.IsActive, nil
+	if !resp.Success {
+		return fmt.Errorf("knowledge rejected detection refresh: %s", resp.Message)
+	}
+
+	return nil
 }
 
-func (k *KnowledgeClient) RegisterDetection(ctx context.Context, detection *models.Detection) error {
-	_, err := k.client.RegisterDetection(ctx, &pb.RegisterDetectionRequest{
+// RegisterDetectionIfAbsent registers detection unless one with its key is already active,
+// returning whether it was registered and the ID of the detection now holding the key.
+// Checking and registering is one step in Knowledge, so only one caller wins a race.
+func (k *KnowledgeClient) RegisterDetectionIfAbsent(ctx context.Context, detection *models.Detection) (bool, string, error) {
+	resp, err := k.client.RegisterDetectionIfAbsent(ctx, registerDetectionRequest(detection))
+	if err != nil {
+		return false, "", fmt.Errorf("failed to register detection with Knowledge: %w", err)
+	}
+
+	if !resp.Success {
+		return false, "", fmt.Errorf("knowledge rejected detection registration: %s", resp.Message)
+	}
+
+	return resp.Registered, resp.DetectionId, nil
+}
+
+func registerDetectionRequest(detection *models.Detection) *pb.RegisterDetectionRequest {
+	return &pb.RegisterDetectionRequest{
 		Id:         detection.ID,
 		Key:        detection.Key,
 		Severity:   string(detection.Severity),
 		Category:   string(detection.Category),
 		DatabaseId: detection.DatabaseID,
-		Value:      0, // TODO: Extract meaningful value from Evidence
+		Value:      detection.Value,
 		CreatedAt:  detection.Timestamp,
-	})
 
+		Title:          detection.Title,
+		Description:    detection.Description,
+		Recommendation: detection.Recommendation,
+		ActionType:     detection.ActionType,
+		Evidence:       encodeDetail(detection.ID, "evidence", detection.Evidence),
+		ActionMetadata: encodeDetail(detection.ID, "action metadata", detection.ActionMetadata),
+	}
+}
+
+// RecordActionOutcome stores the before and after metrics of a verified action.
+func (k *KnowledgeClient) RecordActionOutcome(ctx context.Context, report *verification.EffectivenessReport) error {
+	resp, err := k.client.RecordActionOutcome(ctx, &pb.RecordActionOutcomeRequest{
+		ActionId:     report.ActionID,
+		Metric:       report.Metric,
+		MetricBefore: report.MetricBefore,
+		MetricAfter:  report.MetricAfter,
+		Verdict:      report.Verdict,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to register detection with Knowledge: %w", err)
+		return fmt.Errorf("failed to record action outcome: %w", err)
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("knowledge rejected action outcome: %s", resp.Message)
 	}
 
 	return nil
 }
 
+// encodeDetail JSON-encodes a detection's evidence or action metadata for Knowledge. A value
+// that can't be encoded is left out rather than failing the registration.
+func encodeDetail(detectionID, name string, detail map[string]interface{}) string {
+	if len(detail) == 0 {
+		return ""
+	}
+
+	data, err := json.Marshal(detail)
+	if err != nil {
+		slog.Warn("Failed to encode detection field", "field", name, "detection_id", detectionID, "error", err)
+		return ""
+	}
+	return string(data)
+}
+
 func (k *KnowledgeClient) MarkDetectionResolved(ctx context.Context, detectionID string, solution string) error {
 	_, err := k.client.MarkDetectionResolved(ctx, &pb.ResolveDetectionRequest{
 		DetectionId: detectionID,
@@ -76,6 +192,43 @@ func (k *KnowledgeClient) MarkDetectionResolved(ctx context.Context, detectionID
 	return nil
 }
 
+// ReopenDetection puts a resolved detection back in the active set because the action that
+// fixed it failed or was rolled back.
+func (k *KnowledgeClient) ReopenDetection(ctx context.Context, detectionID string, reason string) error {
+	resp, err := k.client.ReopenDetection(ctx, &pb.ReopenDetectionRequest{
+		DetectionId: detectionID,
+		Reason:      reason,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reopen detection: %w", err)
+	}
+	if !resp.Success {
+		return fmt.Errorf("failed to reopen detection: %s", resp.Message)
+	}
+
+	log.Printf("Detection reopened in Knowledge: %s (%s)", detectionID, reason)
+
+	return nil
+}
+
+// RecordMetricSample adds one snapshot's values to the database's metric history.
+func (k *KnowledgeClient) RecordMetricSample(ctx context.Context, databaseID string, timestamp int64, values map[string]float64) error {
+	resp, err := k.client.RecordMetricSample(ctx, &pb.MetricSample{
+		DatabaseId: databaseID,
+		Timestamp:  timestamp,
+		Values:     values,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record metric sample: %w", err)
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("knowledge rejected metric sample: %s", resp.Message)
+	}
+
+	return nil
+}
+
 // GetSystemConfig fetches the system configuration from Knowledge service.
 func (k *KnowledgeClient) GetSystemConfig(ctx context.Context) (*pb.SystemConfig, error) {
 	resp, err := k.client.GetSystemConfig(ctx, &pb.GetSystemConfigRequest{})
@@ -86,6 +239,39 @@ func (k *KnowledgeClient) GetSystemConfig(ctx context.Context) (*pb.SystemConfig
 	return resp, nil
 }
 
+// GetThresholds fetches every threshold override set: the global default (empty database
+// ID) and one per database.
+func (k *KnowledgeClient) GetThresholds(ctx context.Context) ([]*pb.ThresholdSet, error) {
+	resp, err := k.client.GetThresholds(ctx, &pb.GetThresholdsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("GetThresholds RPC failed: %w", err)
+	}
+
+	return resp.Sets, nil
+}
+
+// GetDatabasePolicy fetches a database's policy; one without a policy has empty lists.
+func (k *KnowledgeClient) GetDatabasePolicy(ctx context.Context, databaseID string) (*pb.DatabasePolicy, error) {
+	resp, err := k.client.GetDatabasePolicy(ctx, &pb.GetDatabasePolicyRequest{DatabaseId: databaseID})
+	if err != nil {
+		return nil, fmt.Errorf("GetDatabasePolicy RPC failed: %w", err)
+	}
+
+	return resp, nil
+}
+
+// Ping checks that the Knowledge service is reachable and serving.
+func (k *KnowledgeClient) Ping(ctx context.Context) error {
+	resp, err := healthpb.NewHealthClient(k.conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		return fmt.Errorf("knowledge unreachable: %w", err)
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("knowledge is %s", resp.Status)
+	}
+	return nil
+}
+
 func (k *KnowledgeClient) Close() error {
 	if k.conn != nil {
 		return k.conn.Close()
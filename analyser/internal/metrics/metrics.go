@@ -0,0 +1,75 @@
+// Package metrics exposes Prometheus metrics for the Analyser service.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Reasons a detection can be suppressed instead of published.
+const (
+	SuppressedAlreadyActive = "already_active"
+	SuppressedVerification  = "verification_rollback"
+	SuppressedPublishFailed = "publish_failed"
+	SuppressedByOperator    = "operator_suppressed"
+	SuppressedByPolicy      = "database_policy"
+)
+
+var (
+	// SnapshotsProcessed counts metric snapshots received from the Collector.
+	SnapshotsProcessed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "analyser",
+		Name:      "snapshots_processed_total",
+		Help:      "Number of metric snapshots processed per database.",
+	}, []string{"database_id"})
+
+	// SnapshotsRejected counts snapshots dropped at ingest, before the detectors run.
+	SnapshotsRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "analyser",
+		Name:      "snapshots_rejected_total",
+		Help:      "Number of metric snapshots rejected per database and reason.",
+	}, []string{"database_id", "reason"})
+
+	// DetectionsFired counts detections published to the event bus.
+	DetectionsFired = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "analyser",
+		Name:      "detections_fired_total",
+		Help:      "Number of detections published per detector.",
+	}, []string{"detector", "severity"})
+
+	// DetectionsSuppressed counts detections that fired but were not published.
+	DetectionsSuppressed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "analyser",
+		Name:      "detections_suppressed_total",
+		Help:      "Number of detections suppressed per detector and reason.",
+	}, []string{"detector", "reason"})
+
+	// DetectionDuration tracks how long running all detectors on a snapshot takes.
+	DetectionDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "analyser",
+		Name:      "detection_duration_seconds",
+		Help:      "Duration of running all detectors against a snapshot.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	// PendingVerifications reports actions awaiting verification.
+	PendingVerifications = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "analyser",
+		Name:      "pending_verifications",
+		Help:      "Number of completed actions awaiting verification.",
+	})
+)
+
+// Handler returns the HTTP handler serving metrics in Prometheus format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -6,10 +6,11 @@ import "time"
 type DetectionCategory string
 
 const (
-	CategoryQuery      DetectionCategory = "query"
-	CategoryConnection DetectionCategory = "connection"
-	CategoryCache      DetectionCategory = "cache"
-	CategoryStorage    DetectionCategory = "storage"
+	CategoryQuery       DetectionCategory = "query"
+	CategoryConnection  DetectionCategory = "connection"
+	CategoryCache       DetectionCategory = "cache"
+	CategoryStorage     DetectionCategory = "storage"
+	CategoryReplication DetectionCategory = "replication"
 )
 
 // DetectionSeverity indicates urgency
@@ -21,6 +22,20 @@ const (
 	SeverityCritical DetectionSeverity = "critical"
 )
 
+// Rank orders severities so escalations can be compared; unknown severities rank lowest.
+func (s DetectionSeverity) Rank() int {
+	switch s {
+	case SeverityCritical:
+		return 3
+	case SeverityWarning:
+		return 2
+	case SeverityInfo:
+		return 1
+	default:
+		return 0
+	}
+}
+
 // Detection holds info on a detected issue
 type Detection struct {
 	ID           string            `json:"id"`
@@ -32,15 +47,28 @@ type Detection struct {
 	DatabaseID string `json:"database_id"`
 	Timestamp  int64  `json:"timestamp"`
 
+	// CorrelationID is copied from the snapshot that produced the detection
+	CorrelationID string `json:"correlation_id,omitempty"`
+
 	Title       string `json:"title"`
 	Description string `json:"description"`
 
 	Evidence map[string]interface{} `json:"evidence"`
 
+	// Value is the headline measurement behind the detection (hit rate, usage ratio,
+	// latency, bloat ratio...), used to notice when an active issue changes significantly
+	Value float64 `json:"value"`
+
 	Recommendation string `json:"recommendation"`
 
 	ActionType     string                 `json:"action_type,omitempty"`
 	ActionMetadata map[string]interface{} `json:"action_metadata,omitempty"`
+
+	// Escalated is set when an already-active detection is re-published at a higher severity
+	Escalated bool `json:"escalated,omitempty"`
+
+	// Updated is set when an already-active detection is re-published because its value moved
+	Updated bool `json:"updated,omitempty"`
 }
 
 func NewDetection(detectorName string, category DetectionCategory, databaseId string) *Detection {
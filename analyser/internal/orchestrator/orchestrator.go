@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"net"
+	"strings"
 	"time"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/config"
@@ -13,19 +15,28 @@ import (
 	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/eventbus"
 	grpcserver "github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/grpc"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/knowledge"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/metrics"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/thresholds"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/verification"
 	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/health"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 )
 
+// grpcStopTimeout bounds how long Stop waits for open metrics streams to finish.
+const grpcStopTimeout = 5 * time.Second
+
+// healthStopTimeout bounds how long Stop waits for in-flight health checks.
+const healthStopTimeout = 5 * time.Second
+
 // Orchestrator manages the Analyser service lifecycle and coordinates
 // metric analysis, detection, and event publishing.
 //
 // Lifecycle:
-//  1. Start() - Initializes detection engine, NATS, Knowledge, and gRPC server
+//  1. Start() - Initializes detection engine, NATS, Knowledge, gRPC and health servers
 //  2. Run() - Starts gRPC server to receive metrics from Collector
-//  3. Stop() - Gracefully closes all connections and resources
+//  3. Stop() - Finishes snapshots being processed, then closes all connections and resources
 //
 // The orchestrator implements graceful degradation:
 //   - NATS failure: Detections registered with Knowledge but not published (Executor unavailable)
@@ -34,7 +45,8 @@ type Orchestrator struct {
 	config *config.Config
 
 	// Detection engine and registered detectors
-	engine *engine.Engine
+	engine     *engine.Engine
+	thresholds *thresholds.Store // Per-database thresholds, refreshed from Knowledge
 
 	// Downstream service connections
 	publisher       *eventbus.Publisher        // NATS publisher for detections
@@ -42,8 +54,10 @@ type Orchestrator struct {
 	knowledgeClient *knowledge.KnowledgeClient // Knowledge service client
 
 	// gRPC server
-	grpcServer   *grpc.Server
-	grpcListener net.Listener
+	grpcServer    *grpc.Server
+	metricsServer *grpcserver.MetricsServer
+	healthServer  *health.Server
+	grpcListener  net.Listener
 
 	// Verification tracker for auto rollback (temporary)
 	verificationTracker *verification.Tracker
@@ -82,6 +96,10 @@ func (o *Orchestrator) Start() error {
 		return fmt.Errorf("failed to initialize gRPC server: %w", err)
 	}
 
+	if err := o.startHealthServer(); err != nil {
+		return fmt.Errorf("failed to start health server: %w", err)
+	}
+
 	log.Printf("Analyser Orchestrator started successfully")
 	return nil
 }
@@ -100,7 +118,7 @@ func (o *Orchestrator) fetchThresholdsFromKnowledge() {
 
 	config, err := o.knowledgeClient.GetSystemConfig(ctx)
 	if err != nil {
-		log.Printf("Failed to fetch config from Knowledge: %v - using default thresholds", err)
+		slog.Error("Failed to fetch config from Knowledge, using default thresholds", "error", err)
 		return
 	}
 
@@ -147,74 +165,107 @@ func (o *Orchestrator) initializeEngine() error {
 	log.Printf("Initializing detection engine...")
 
 	o.engine = engine.NewEngine()
+	o.thresholds = thresholds.NewStore(o.config.Thresholds)
 
-	if !o.config.EnableAllDetectors {
-		log.Printf("Warning: Not all detectors enabled (ENABLE_ALL_DETECTORS=false)")
-		return nil
+	// Register the enabled detectors with configured thresholds, then apply overrides from Knowledge
+	if err := o.registerDetectors(); err != nil {
+		return err
 	}
-
-	// Register detectors with configured thresholds
-	o.registerDetectors()
+	o.refreshThresholds(context.Background())
 
 	detectorNames := o.engine.GetRegisteredDetectors()
+	if len(detectorNames) == 0 {
+		slog.Warn("No detectors enabled (DETECTORS=none)")
+	}
 	log.Printf("Detection engine initialized with %d detectors: %v", len(detectorNames), detectorNames)
 	return nil
 }
 
-// registerDetectors registers all available detectors with the engine and applies configured thresholds.
-// registerDetectors registers all available detectors with the engine and applies configured thresholds.
-func (o *Orchestrator) registerDetectors() {
+// registerDetectors registers the detectors enabled by DETECTORS with the engine. Each database
+// gets its own instances, configured with the configured thresholds plus any overrides from Knowledge.
+func (o *Orchestrator) registerDetectors() error {
+	enabled, err := o.config.EnabledDetectors()
+	if err != nil {
+		return err
+	}
+
 	log.Printf("Registering detectors with configured thresholds...")
 
-	// Connection Pool Detector
-	connPoolDetector := detector.NewConnectionPoolDetection()
-	connPoolDetector.SetThreshold(o.config.Thresholds.ConnectionPoolCritical)
-	o.engine.RegisterDetector(connPoolDetector)
-	log.Printf("  - Connection Pool: threshold=%.2f (%.0f%%)",
-		o.config.Thresholds.ConnectionPoolCritical,
-		o.config.Thresholds.ConnectionPoolCritical*100)
-
-	// Missing Index Detector
-	missingIndexDetector := detector.NewMissingIndexDetector()
-	missingIndexDetector.SetThreshold(o.config.Thresholds.SequentialScanThreshold)
-	missingIndexDetector.SetDeltaThreshold(o.config.Thresholds.SequentialScanDeltaThreshold)
-	o.engine.RegisterDetector(missingIndexDetector)
-	log.Printf("  - Missing Index: seq_scan_threshold=%d, delta_threshold=%.1f",
-		o.config.Thresholds.SequentialScanThreshold,
-		o.config.Thresholds.SequentialScanDeltaThreshold)
-
-	// High Latency Detector
-	latencyDetector := detector.NewHighLatencyDetector()
-	latencyDetector.SetThreshold(o.config.Thresholds.P95LatencyThresholdMs)
-	o.engine.RegisterDetector(latencyDetector)
-	log.Printf("  - High Latency: p95_threshold=%.0fms",
-		o.config.Thresholds.P95LatencyThresholdMs)
-
-	// Cache Miss Detector
-	cacheMissDetector := detector.NewCacheMissDetector()
-	cacheMissDetector.SetThreshold(o.config.Thresholds.CacheHitRateThreshold)
-	o.engine.RegisterDetector(cacheMissDetector)
-	log.Printf("  - Cache Miss: hit_rate_threshold=%.2f (%.0f%%)",
-		o.config.Thresholds.CacheHitRateThreshold,
-		o.config.Thresholds.CacheHitRateThreshold*100)
-
-	// Table Bloat Detector
-	tableBloatDetector := detector.NewTableBloatDetector()
-	tableBloatDetector.SetThreshold(o.config.Thresholds.TableBloatThreshold)
-	o.engine.RegisterDetector(tableBloatDetector)
-	log.Printf("  - Table Bloat: threshold=%.0f%%", o.config.Thresholds.TableBloatThreshold*100)
-
-	// Long Running Query Detector
-	longQueryDetector := detector.NewLongRunningQueryDetector()
-	longQueryDetector.SetThreshold(o.config.Thresholds.LongRunningQueryThresholdSecs)
-	o.engine.RegisterDetector(longQueryDetector)
-	log.Printf("  - Long Running Query: threshold=%.0fs", o.config.Thresholds.LongRunningQueryThresholdSecs)
-
-	// Idle Transaction Detector
-	idleTxnDetector := detector.NewIdleTransactionDetector()
-	idleTxnDetector.SetThreshold(o.config.Thresholds.IdleTransactionThresholdSecs)
-	o.engine.RegisterDetector(idleTxnDetector)
-	log.Printf("  - Idle Transaction: threshold=%.0fs", o.config.Thresholds.IdleTransactionThresholdSecs)
+	o.engine.SetConfigurator(func(databaseID string, d detector.Detector) {
+		thresholds.Apply(d, o.thresholds.For(databaseID))
+	})
+
+	values := o.config.Thresholds.Values()
+	denylist := fmt.Sprintf("deny applications=%v, deny users=%v", o.config.TerminateDenyApplications, o.config.TerminateDenyUsers)
+	for _, name := range enabled {
+		reg, _ := detector.Lookup(name)
+
+		settings := make([]string, 0, len(reg.Thresholds)+1)
+		for _, threshold := range reg.Thresholds {
+			settings = append(settings, fmt.Sprintf("%s=%g", threshold, values[threshold]))
+		}
+
+		// Detectors that terminate sessions leave the denylisted ones alone
+		newDetector := reg.New
+		switch name {
+		case "idle_transaction":
+			newDetector = func() detector.Detector {
+				d := detector.NewIdleTransactionDetector()
+				d.SetDenylist(o.config.TerminateDenyApplications, o.config.TerminateDenyUsers)
+				return d
+			}
+			settings = append(settings, denylist)
+		case "blocking_lock":
+			newDetector = func() detector.Detector {
+				d := detector.NewBlockingLockDetector()
+				d.SetDenylist(o.config.TerminateDenyApplications, o.config.TerminateDenyUsers)
+				return d
+			}
+			settings = append(settings, denylist)
+		}
+
+		o.engine.RegisterDetectorFactory(newDetector)
+		log.Printf("  - %s: %s", name, strings.Join(settings, ", "))
+	}
+
+	return nil
+}
+
+// refreshThresholds reads threshold overrides from Knowledge and reconfigures every
+// database's detectors when they changed.
+func (o *Orchestrator) refreshThresholds(ctx context.Context) {
+	if o.knowledgeClient == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	sets, err := o.knowledgeClient.GetThresholds(ctx)
+	if err != nil {
+		slog.Error("Failed to fetch threshold overrides from Knowledge", "error", err)
+		return
+	}
+
+	if o.thresholds.Update(sets) {
+		log.Printf("Threshold overrides changed (%d sets) - reconfiguring detectors", len(sets))
+		o.engine.Reconfigure()
+	}
+}
+
+// watchThresholds re-reads threshold overrides every ThresholdRefreshInterval until ctx ends.
+func (o *Orchestrator) watchThresholds(ctx context.Context) {
+	ticker := time.NewTicker(o.config.ThresholdRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			o.refreshThresholds(ctx)
+		}
+	}
 }
 
 // initializeVerificationTracker creates the verification tracker for autonomous rollback.
@@ -234,11 +285,13 @@ func (o *Orchestrator) initializeVerificationTracker() {
 
 		// Rollback callback
 		func(request *verification.RollbackRequest) {
-			if o.publisher != nil {
-				log.Printf("Verification failed - requesting rollback for action %s", request.ActionID)
-				if err := o.publisher.PublishRollbackRequest(request); err != nil {
-					log.Printf("Failed to publish rollback request: %v", err)
-				}
+			if o.publisher == nil {
+				slog.Warn("Verification failed for action but NATS is unavailable, rollback not requested", "action_id", request.ActionID)
+				return
+			}
+			slog.Warn("Verification failed, requesting rollback", "action_id", request.ActionID)
+			if err := o.publisher.PublishRollbackRequest(request); err != nil {
+				slog.Error("Failed to publish rollback request", "error", err)
 			}
 		},
 
@@ -248,12 +301,28 @@ func (o *Orchestrator) initializeVerificationTracker() {
 				log.Printf("Action verified - marking detection %s as resolved", detectionID)
 				ctx := context.Background()
 				if err := o.knowledgeClient.MarkDetectionResolved(ctx, detectionID, "verified_by_metrics"); err != nil {
-					log.Printf("Failed to mark detection resolved: %v", err)
+					slog.Error("Failed to mark detection resolved", "error", err)
 				}
 			}
 		},
 	)
 
+	// Effectiveness callback: before/after metrics for the Dashboard
+	o.verificationTracker.SetEffectivenessHandler(func(report *verification.EffectivenessReport) {
+		if o.publisher != nil {
+			if err := o.publisher.PublishEffectiveness(report); err != nil {
+				slog.Error("Failed to publish effectiveness report", "error", err)
+			}
+		}
+		if o.knowledgeClient != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := o.knowledgeClient.RecordActionOutcome(ctx, report); err != nil {
+				slog.Error("Failed to record action outcome", "error", err)
+			}
+		}
+	})
+
 	log.Printf("Verification tracker initialized (3 cycle verification)")
 }
 
@@ -263,9 +332,9 @@ func (o *Orchestrator) initializeVerificationTracker() {
 func (o *Orchestrator) connectKnowledge() {
 	log.Printf("Connecting to Knowledge service at: %s", o.config.KnowledgeAddress)
 
-	client, err := knowledge.NewKnowledgeClient(o.config.KnowledgeAddress)
+	client, err := knowledge.NewKnowledgeClient(o.config.KnowledgeAddress, o.config.GRPCTLS)
 	if err != nil {
-		log.Printf("Warning: failed to connect to Knowledge service: %v", err)
+		slog.Warn("Failed to connect to Knowledge service", "error", err)
 		log.Printf("Detection deduplication unavailable - duplicate actions may be triggered")
 		return
 	}
@@ -286,9 +355,9 @@ func (o *Orchestrator) connectNATS() {
 	log.Printf("Connecting to NATS at: %s", o.config.NatsURL)
 
 	// Initialize publisher
-	publisher, err := eventbus.NewPublisher(o.config.NatsURL)
+	publisher, err := eventbus.NewPublisher(o.config.NatsURL, o.config.NatsJetStream)
 	if err != nil {
-		log.Printf("Warning: failed to connect NATS publisher: %v", err)
+		slog.Warn("Failed to connect NATS publisher", "error", err)
 		log.Printf("Detections will not be published - Executor unavailable")
 	} else {
 		o.publisher = publisher
@@ -297,14 +366,14 @@ func (o *Orchestrator) connectNATS() {
 
 	// Initialize subscriber for action completion events
 	if o.knowledgeClient != nil {
-		subscriber, err := eventbus.NewSubscriber(o.config.NatsURL, o.knowledgeClient, o.verificationTracker)
+		subscriber, err := eventbus.NewSubscriber(o.config.NatsURL, o.config.NatsJetStream, o.knowledgeClient, o.verificationTracker)
 		if err != nil {
-			log.Printf("Warning: failed to create NATS subscriber: %v", err)
+			slog.Warn("Failed to create NATS subscriber", "error", err)
 			log.Printf("Action completion tracking unavailable")
 		} else {
 			o.subscriber = subscriber
 			if err := subscriber.Start(); err != nil {
-				log.Printf("Warning: failed to start NATS subscriber: %v", err)
+				slog.Warn("Failed to start NATS subscriber", "error", err)
 			} else {
 				log.Printf("Connected to NATS subscriber")
 			}
@@ -325,12 +394,25 @@ func (o *Orchestrator) initializeGRPCServer() error {
 	}
 	o.grpcListener = listener
 
-	// Create gRPC server
-	o.grpcServer = grpc.NewServer()
+	// Create gRPC server (mutual TLS when GRPC_TLS_* is configured)
+	serverOptions, err := o.config.GRPCTLS.ServerOptions()
+	if err != nil {
+		return err
+	}
+	if o.config.GRPCTLS.Enabled() {
+		log.Printf("gRPC server requires mutual TLS")
+	}
+	o.grpcServer = grpc.NewServer(serverOptions...)
 
 	// Register metrics service with detection engine, publisher, and knowledge client
 	metricsServer := grpcserver.NewMetricsServer(o.engine, o.publisher, o.knowledgeClient, o.verificationTracker)
+	metricsServer.SetSnapshotRateLimit(o.config.MaxSnapshotsPerMinute)
+	metricsServer.SetValueChangeThreshold(o.config.ValueChangeThreshold)
+	metricsServer.SetThresholdSource(o.thresholds.For)
+	metricsServer.SetPolicyCacheTTL(o.config.PolicyCacheTTL)
+	o.verificationTracker.SetSnapshotSource(metricsServer.LastSnapshot)
 	pb.RegisterMetricsServiceServer(o.grpcServer, metricsServer)
+	o.metricsServer = metricsServer
 
 	// Enable gRPC reflection for debugging (grpcurl, etc.)
 	reflection.Register(o.grpcServer)
@@ -339,6 +421,35 @@ func (o *Orchestrator) initializeGRPCServer() error {
 	return nil
 }
 
+// startHealthServer serves /health with the state of NATS and Knowledge. Both are optional
+// connections, so either being down reports the Analyser degraded rather than unhealthy.
+func (o *Orchestrator) startHealthServer() error {
+	o.healthServer = health.NewServer("analyser", o.config.HealthPort, metrics.Handler())
+
+	o.healthServer.AddCheck("nats", false, func(ctx context.Context) error {
+		if o.publisher == nil || !o.publisher.IsConnected() {
+			return fmt.Errorf("not connected")
+		}
+		return nil
+	})
+	o.healthServer.AddCheck("knowledge", false, func(ctx context.Context) error {
+		if o.knowledgeClient == nil {
+			return fmt.Errorf("not connected")
+		}
+		return o.knowledgeClient.Ping(ctx)
+	})
+
+	return o.healthServer.Start()
+}
+
+// HealthAddr returns the address /health is served on, or "" before Start.
+func (o *Orchestrator) HealthAddr() string {
+	if o.healthServer == nil {
+		return ""
+	}
+	return o.healthServer.Addr()
+}
+
 // Run starts the gRPC server and blocks until the context is cancelled or an error occurs.
 // Metrics are received from Collector, analyzed by the detection engine, and detections are published to NATS.
 func (o *Orchestrator) Run(ctx context.Context) error {
@@ -354,6 +465,11 @@ func (o *Orchestrator) Run(ctx context.Context) error {
 
 	log.Printf("Analyser ready - listening for metrics from Collector")
 
+	// Pick up threshold changes made through the Dashboard
+	if o.knowledgeClient != nil {
+		go o.watchThresholds(ctx)
+	}
+
 	// Wait for context cancellation or server error
 	select {
 	case <-ctx.Done():
@@ -369,10 +485,40 @@ func (o *Orchestrator) Run(ctx context.Context) error {
 func (o *Orchestrator) Stop() error {
 	log.Printf("Stopping Orchestrator...")
 
-	// Stop gRPC server (graceful shutdown with timeout)
+	// Let snapshots being processed finish registering and publishing their detections
+	// while Knowledge and NATS are still connected; new ones are left for the Collector to resend
+	if o.metricsServer != nil {
+		log.Printf("Draining snapshot processing...")
+		if !o.metricsServer.Drain(o.config.ShutdownGracePeriod) {
+			log.Printf("Snapshot processing did not finish within %s", o.config.ShutdownGracePeriod)
+		}
+	}
+
+	// Stop gRPC server (graceful shutdown with timeout). The Collector's metrics stream stays
+	// open indefinitely, so GracefulStop would otherwise wait for it forever.
 	if o.grpcServer != nil {
 		log.Printf("Stopping gRPC server...")
-		o.grpcServer.GracefulStop()
+		stopped := make(chan struct{})
+		go func() {
+			o.grpcServer.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-time.After(grpcStopTimeout):
+			log.Printf("gRPC server did not stop within %s, closing open streams", grpcStopTimeout)
+			o.grpcServer.Stop()
+		}
+	}
+
+	// Stop health check server
+	if o.healthServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), healthStopTimeout)
+		if err := o.healthServer.Shutdown(ctx); err != nil {
+			slog.Error("Error stopping health server", "error", err)
+		}
+		cancel()
 	}
 
 	// Close NATS subscriber
@@ -388,7 +534,7 @@ func (o *Orchestrator) Stop() error {
 	// Close Knowledge client
 	if o.knowledgeClient != nil {
 		if err := o.knowledgeClient.Close(); err != nil {
-			log.Printf("Error closing Knowledge client: %v", err)
+			slog.Error("Error closing Knowledge client", "error", err)
 		}
 	}
 
@@ -0,0 +1,147 @@
+// Package thresholds layers the threshold overrides stored in Knowledge over the Analyser's
+// configured thresholds and applies the result to detectors, per database.
+package thresholds
+
+import (
+	"log/slog"
+	"reflect"
+	"sync"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/config"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/detector"
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+)
+
+// Store resolves each database's thresholds: configured values, then the global override
+// set, then the database's own set. A set that would produce invalid thresholds is ignored.
+type Store struct {
+	mu        sync.RWMutex
+	base      config.DetectionThresholds
+	received  overrides // as last read from Knowledge
+	effective overrides // received, minus sets rejected as invalid
+}
+
+type overrides struct {
+	global     map[string]float64
+	byDatabase map[string]map[string]float64
+}
+
+// NewStore creates a store with no overrides.
+func NewStore(base config.DetectionThresholds) *Store {
+	return &Store{
+		base:     base,
+		received: overrides{byDatabase: make(map[string]map[string]float64)},
+	}
+}
+
+// Update replaces the overrides with the sets read from Knowledge and reports whether
+// anything changed.
+func (s *Store) Update(sets []*pb.ThresholdSet) bool {
+	received := overrides{byDatabase: make(map[string]map[string]float64)}
+	for _, set := range sets {
+		if len(set.Values) == 0 {
+			continue
+		}
+		if set.DatabaseId == "" {
+			received.global = set.Values
+		} else {
+			received.byDatabase[set.DatabaseId] = set.Values
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if reflect.DeepEqual(received, s.received) {
+		return false
+	}
+	s.received = received
+
+	// Validate once per change rather than every time a detector is configured
+	effective := overrides{byDatabase: make(map[string]map[string]float64)}
+	globalThresholds, ok := s.resolve(s.base, "", received.global)
+	if ok {
+		effective.global = received.global
+	}
+	for databaseID, values := range received.byDatabase {
+		if _, ok := s.resolve(globalThresholds, databaseID, values); ok {
+			effective.byDatabase[databaseID] = values
+		}
+	}
+
+	s.effective = effective
+	return true
+}
+
+// resolve applies values over base, logging unknown names and rejecting invalid results.
+func (s *Store) resolve(base config.DetectionThresholds, databaseID string, values map[string]float64) (config.DetectionThresholds, bool) {
+	resolved, unknown := base.WithOverrides(values)
+	if len(unknown) > 0 {
+		slog.Warn("Ignoring unknown threshold overrides", "database_id", databaseID, "names", unknown)
+	}
+
+	if err := resolved.Validate(); err != nil {
+		slog.Warn("Ignoring invalid threshold overrides", "database_id", databaseID, "error", err)
+		return base, false
+	}
+
+	return resolved, true
+}
+
+// For returns the thresholds in effect for a database.
+func (s *Store) For(databaseID string) config.DetectionThresholds {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	resolved, _ := s.base.WithOverrides(s.effective.global)
+	resolved, _ = resolved.WithOverrides(s.effective.byDatabase[databaseID])
+	return resolved
+}
+
+// Apply sets a detector's thresholds. Detectors without thresholds are left alone.
+func Apply(d detector.Detector, t config.DetectionThresholds) {
+	switch det := d.(type) {
+	case *detector.ConnectionPoolDetector:
+		det.SetThreshold(t.ConnectionPoolCritical)
+		det.SetIdleThresholds(t.ConnectionPoolIdle, t.ConnectionPoolIdleShare)
+	case *detector.MissingIndexDetector:
+		det.SetThreshold(t.SequentialScanThreshold)
+		det.SetDeltaThreshold(t.SequentialScanDeltaThreshold)
+	case *detector.HighLatencyDetector:
+		det.SetThreshold(t.P95LatencyThresholdMs)
+	case *detector.CacheMissDetector:
+		det.SetThreshold(t.CacheHitRateThreshold)
+	case *detector.TableBloatDetector:
+		det.SetThreshold(t.TableBloatThreshold)
+		det.SetModeThresholds(t.TableBloatFullRatio, t.TableBloatFullMaxSizeMB*1024*1024, t.TableBloatTuneMinSizeMB*1024*1024)
+		det.SetAutovacuumScaleFactor(t.TableBloatAutovacuumScaleFactor)
+	case *detector.LongRunningQueryDetector:
+		det.SetThreshold(t.LongRunningQueryThresholdSecs)
+	case *detector.IdleTransactionDetector:
+		det.SetThreshold(t.IdleTransactionThresholdSecs)
+	case *detector.ReplicationLagDetector:
+		det.SetThreshold(t.ReplicationLagThresholdSecs)
+		det.SetSeverityThresholds(t.ReplicationLagWarningSecs, t.ReplicationLagCriticalSecs)
+		det.SetByteThreshold(t.ReplicationLagThresholdMB * 1024 * 1024)
+	case *detector.DeadlockDetector:
+		det.SetThreshold(t.DeadlockThreshold)
+	case *detector.AutovacuumStarvationDetector:
+		det.SetThreshold(t.AutovacuumAgeThresholdSecs)
+		det.SetConsecutiveCycles(int(t.AutovacuumStarvationCycles))
+	case *detector.UnusedIndexDetector:
+		det.SetThreshold(t.UnusedIndexMinSizeMB * 1024 * 1024)
+		det.SetUnusedCycles(int(t.UnusedIndexCycles))
+	case *detector.LockContentionDetector:
+		det.SetThreshold(t.LockWaitingConnections)
+		det.SetConsecutiveCycles(int(t.LockContentionCycles))
+	case *detector.BlockingLockDetector:
+		det.SetThreshold(t.LockWaitThresholdSecs)
+	case *detector.ConnectionChurnDetector:
+		det.SetThreshold(t.ConnectionChurnPerSec)
+	case *detector.TempFileSpillDetector:
+		det.SetThreshold(t.TempSpillThresholdMB * 1024 * 1024)
+	case *detector.StorageGrowthDetector:
+		det.SetThreshold(t.StorageUsageThreshold)
+		det.SetTimeToFullThreshold(t.StorageTimeToFullHours)
+	}
+}
@@ -0,0 +1,50 @@
+package verification
+
+import (
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+)
+
+// Verdicts recorded for an action once verification finishes.
+const (
+	VerdictEffective   = "effective"   // The issue stayed away for the verification cycles
+	VerdictIneffective = "ineffective" // The issue fired again and the action is rolled back
+)
+
+// EffectivenessReport compares the metric behind a detection before and after the action
+// taken for it, e.g. sequential scans per interval dropping from 1200 to 3 after an index.
+type EffectivenessReport struct {
+	ActionID     string  `json:"action_id"`
+	DetectionID  string  `json:"detection_id"`
+	ActionType   string  `json:"action_type"`
+	DatabaseID   string  `json:"database_id"`
+	Metric       string  `json:"metric"`
+	MetricBefore float64 `json:"metric_before"`
+	MetricAfter  float64 `json:"metric_after"`
+	Verdict      string  `json:"verdict"`
+	Timestamp    int64   `json:"timestamp"`
+}
+
+// Measure reads the metric an action type is meant to improve from a snapshot. ok is false
+// for action types that are not verified by metrics, or when the snapshot lacks the metric.
+func Measure(actionType string, snapshot *normaliser.NormalisedMetrics) (metric string, value float64, ok bool) {
+	switch actionType {
+	case "create_index":
+		delta, exists := snapshot.MetricDeltas["sequential_scans"]
+		return "sequential_scans_per_interval", delta, exists && snapshot.DeltasReliable
+
+	case "tune_config_high_latency":
+		if latency := snapshot.Measurements.P95QueryLatencyMs; latency != nil {
+			return "p95_latency_ms", *latency, true
+		}
+		if latency := snapshot.Measurements.AvgQueryLatencyMs; latency != nil {
+			return "avg_latency_ms", *latency, true
+		}
+
+	case "cache_optimization_recommendation", "increase_cache_size":
+		if hitRate := snapshot.Measurements.CacheHitRate; hitRate != nil {
+			return "cache_hit_rate", *hitRate, true
+		}
+	}
+
+	return "", 0, false
+}
@@ -4,6 +4,8 @@ import (
 	"log"
 	"sync"
 	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
 )
 
 const (
@@ -15,6 +17,9 @@ const (
 
 	// Max time to wait for verification before giving up (not rolling back, just abandoning check)
 	MaxVerificationTime = 10 * time.Minute
+
+	// How long a detection's metric is kept as the baseline for an action after it last fired
+	baselineMaxAge = time.Hour
 )
 
 // PendingVerification tracks an action awaiting verification
@@ -26,6 +31,18 @@ type PendingVerification struct {
 	DatabaseID    string
 	CompletedAt   time.Time
 	CyclesElapsed int
+
+	// Metric and MetricBefore hold the detection's metric when it last fired; Metric is
+	// empty when no baseline was measured
+	Metric       string
+	MetricBefore float64
+}
+
+// baseline is a detection's metric, measured when the detection fired.
+type baseline struct {
+	metric     string
+	value      float64
+	measuredAt time.Time
 }
 
 // RollbackRequest is published when verification fails
@@ -45,6 +62,10 @@ type Tracker struct {
 	requiredCycles   int
 	onRollbackNeeded func(request *RollbackRequest)
 	onVerified       func(detectionID string)
+
+	baselines       map[string]baseline // keyed by DetectionKey
+	snapshots       func(databaseID string) *normaliser.NormalisedMetrics
+	onEffectiveness func(report *EffectivenessReport)
 }
 
 // NewTracker creates a new verification tracker
@@ -58,7 +79,38 @@ func NewTracker(requiredCycles int, onRollbackNeeded func(*RollbackRequest), onV
 		requiredCycles:   requiredCycles,
 		onRollbackNeeded: onRollbackNeeded,
 		onVerified:       onVerified,
+		baselines:        make(map[string]baseline),
+	}
+}
+
+// SetSnapshotSource sets where the latest snapshot for a database is read from when an
+// action's metric is measured again at the end of verification.
+func (t *Tracker) SetSnapshotSource(source func(databaseID string) *normaliser.NormalisedMetrics) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.snapshots = source
+}
+
+// SetEffectivenessHandler registers fn to receive a before/after report for each action
+// whose verification finishes with both measurements available.
+func (t *Tracker) SetEffectivenessHandler(fn func(report *EffectivenessReport)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onEffectiveness = fn
+}
+
+// RecordBaseline measures the metric an action for this detection would be judged by, so
+// the value when the detection last fired can be compared with the value after the action.
+func (t *Tracker) RecordBaseline(detectionKey, actionType string, snapshot *normaliser.NormalisedMetrics) {
+	metric, value, ok := Measure(actionType, snapshot)
+	if !ok {
+		return
 	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.baselines[detectionKey] = baseline{metric: metric, value: value, measuredAt: time.Now()}
 }
 
 // AddPendingVerification adds an action to be verified
@@ -66,7 +118,7 @@ func (t *Tracker) AddPendingVerification(detectionKey, detectionID, actionID, ac
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	t.pending[detectionKey] = &PendingVerification{
+	pv := &PendingVerification{
 		DetectionKey:  detectionKey,
 		DetectionID:   detectionID,
 		ActionID:      actionID,
@@ -76,6 +128,14 @@ func (t *Tracker) AddPendingVerification(detectionKey, detectionID, actionID, ac
 		CyclesElapsed: 0,
 	}
 
+	if b, ok := t.baselines[detectionKey]; ok {
+		pv.Metric = b.metric
+		pv.MetricBefore = b.value
+		delete(t.baselines, detectionKey)
+	}
+
+	t.pending[detectionKey] = pv
+
 	log.Printf("[Verification] Added pending verification for action %s (detection: %s, key: %s)",
 		actionID, detectionID, detectionKey)
 }
@@ -102,6 +162,8 @@ func (t *Tracker) OnDetectionFired(detectionKey string) bool {
 	log.Printf("[Verification] Action %s did not resolve issue (detection key: %s fired again after %d cycles)",
 		pv.ActionID, detectionKey, pv.CyclesElapsed)
 
+	t.reportEffectiveness(pv, VerdictIneffective)
+
 	// Trigger rollback
 	if t.onRollbackNeeded != nil {
 		t.onRollbackNeeded(&RollbackRequest{
@@ -123,6 +185,17 @@ func (t *Tracker) OnDetectionFired(detectionKey string) bool {
 // OnCollectionCycle is called after each metrics collection
 // It increments cycle counts and marks verified actions as resolved
 func (t *Tracker) OnCollectionCycle() {
+	t.advance("")
+}
+
+// OnDatabaseCycle is called after each snapshot from a database. Only that database's
+// verifications advance, so a database reporting often can't rush another's verification.
+func (t *Tracker) OnDatabaseCycle(databaseID string) {
+	t.advance(databaseID)
+}
+
+// advance counts a cycle for pending verifications on databaseID, or on every database when empty.
+func (t *Tracker) advance(databaseID string) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
@@ -137,6 +210,10 @@ func (t *Tracker) OnCollectionCycle() {
 			continue
 		}
 
+		if databaseID != "" && pv.DatabaseID != databaseID {
+			continue
+		}
+
 		pv.CyclesElapsed++
 
 		// Check if enough cycles passed without re-detection
@@ -144,6 +221,8 @@ func (t *Tracker) OnCollectionCycle() {
 			log.Printf("[Verification] Action %s verified after %d cycles - marking resolved",
 				pv.ActionID, pv.CyclesElapsed)
 
+			t.reportEffectiveness(pv, VerdictEffective)
+
 			// Trigger resolved callback
 			if t.onVerified != nil {
 				t.onVerified(pv.DetectionID)
@@ -157,6 +236,70 @@ func (t *Tracker) OnCollectionCycle() {
 	for _, key := range toRemove {
 		delete(t.pending, key)
 	}
+
+	for key, b := range t.baselines {
+		if now.Sub(b.measuredAt) > baselineMaxAge {
+			delete(t.baselines, key)
+		}
+	}
+}
+
+// reportEffectiveness measures pv's metric on the latest snapshot of its database and reports
+// it against the baseline. Nothing is reported if either measurement is missing.
+func (t *Tracker) reportEffectiveness(pv *PendingVerification, verdict string) {
+	if t.onEffectiveness == nil || t.snapshots == nil || pv.Metric == "" {
+		return
+	}
+
+	snapshot := t.snapshots(pv.DatabaseID)
+	if snapshot == nil {
+		return
+	}
+
+	metric, after, ok := Measure(pv.ActionType, snapshot)
+	if !ok || metric != pv.Metric {
+		log.Printf("[Verification] No %s measurement for action %s, effectiveness not reported", pv.Metric, pv.ActionID)
+		return
+	}
+
+	t.onEffectiveness(&EffectivenessReport{
+		ActionID:     pv.ActionID,
+		DetectionID:  pv.DetectionID,
+		ActionType:   pv.ActionType,
+		DatabaseID:   pv.DatabaseID,
+		Metric:       metric,
+		MetricBefore: pv.MetricBefore,
+		MetricAfter:  after,
+		Verdict:      verdict,
+		Timestamp:    time.Now().Unix(),
+	})
+}
+
+// CancelVerification drops the pending verification of an action that failed or was rolled
+// back, so it is neither confirmed nor rolled back again. Without a detection key the pending
+// verifications are searched by action ID. It reports whether one was dropped.
+func (t *Tracker) CancelVerification(detectionKey, actionID string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if detectionKey != "" {
+		pv, exists := t.pending[detectionKey]
+		if !exists || (actionID != "" && pv.ActionID != actionID) {
+			return false
+		}
+		delete(t.pending, detectionKey)
+		log.Printf("[Verification] Cancelled verification for action %s (key: %s)", pv.ActionID, detectionKey)
+		return true
+	}
+
+	for key, pv := range t.pending {
+		if actionID != "" && pv.ActionID == actionID {
+			delete(t.pending, key)
+			log.Printf("[Verification] Cancelled verification for action %s (key: %s)", actionID, key)
+			return true
+		}
+	}
+	return false
 }
 
 // IsPendingVerification checks if a detection key has a pending verification
@@ -0,0 +1,157 @@
+package unit
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/eventbus"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/knowledge"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/verification"
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// fakeDetectionStore records the state Knowledge would keep for each detection.
+type fakeDetectionStore struct {
+	pb.UnimplementedKnowledgeServiceServer
+
+	mu     sync.Mutex
+	states map[string]string
+}
+
+func (f *fakeDetectionStore) MarkDetectionResolved(ctx context.Context, req *pb.ResolveDetectionRequest) (*pb.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.states[req.DetectionId] = "resolved"
+	return &pb.Response{Success: true}, nil
+}
+
+func (f *fakeDetectionStore) ReopenDetection(ctx context.Context, req *pb.ReopenDetectionRequest) (*pb.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.states[req.DetectionId] == "resolved" {
+		f.states[req.DetectionId] = "reopened"
+	}
+	return &pb.Response{Success: true}, nil
+}
+
+func (f *fakeDetectionStore) state(detectionID string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.states[detectionID]
+}
+
+func startOutcomeSubscriber(t *testing.T, tracker *verification.Tracker) (*eventbus.Subscriber, *fakeDetectionStore) {
+	t.Helper()
+
+	store := &fakeDetectionStore{states: map[string]string{}}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcServer := grpc.NewServer()
+	pb.RegisterKnowledgeServiceServer(grpcServer, store)
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	knowledgeClient, err := knowledge.NewKnowledgeClient(lis.Addr().String(), transport.TLSConfig{})
+	require.NoError(t, err)
+	t.Cleanup(func() { knowledgeClient.Close() })
+
+	// Nothing listens on this port; the subscriber keeps retrying NATS in the background
+	subscriber, err := eventbus.NewSubscriber("nats://127.0.0.1:1", false, knowledgeClient, tracker)
+	require.NoError(t, err)
+	t.Cleanup(subscriber.Close)
+
+	return subscriber, store
+}
+
+func TestHandleActionOutcome_RollbackReopensResolvedDetection(t *testing.T) {
+	tracker := verification.NewTracker(3, nil, nil)
+	subscriber, store := startOutcomeSubscriber(t, tracker)
+
+	completed := &eventbus.ActionCompletedEvent{
+		ActionID:    "action-pool-1",
+		DetectionID: "detection-pool-1",
+		ActionType:  "deploy_connection_pooler",
+		DatabaseID:  "testdb",
+		Status:      "completed",
+		Solution:    "pgbouncer_deployed",
+	}
+	require.NoError(t, subscriber.HandleActionOutcome(completed))
+	assert.Equal(t, "resolved", store.state("detection-pool-1"))
+
+	rolledBack := *completed
+	rolledBack.Status = "rolled_back"
+	rolledBack.Solution = ""
+	require.NoError(t, subscriber.HandleActionOutcome(&rolledBack))
+	assert.Equal(t, "reopened", store.state("detection-pool-1"))
+}
+
+func TestHandleActionOutcome_RollbackCancelsPendingVerification(t *testing.T) {
+	tracker := verification.NewTracker(3, nil, nil)
+	subscriber, store := startOutcomeSubscriber(t, tracker)
+
+	completed := &eventbus.ActionCompletedEvent{
+		ActionID:     "action-index-1",
+		DetectionID:  "detection-index-1",
+		DetectionKey: "testdb:missing_index:posts.user_id",
+		ActionType:   "create_index",
+		DatabaseID:   "testdb",
+		Status:       "completed",
+	}
+	require.NoError(t, subscriber.HandleActionOutcome(completed))
+	assert.True(t, tracker.IsPendingVerification("testdb:missing_index:posts.user_id"))
+	assert.Empty(t, store.state("detection-index-1"), "verified actions are not resolved straight away")
+
+	rolledBack := *completed
+	rolledBack.Status = "rolled_back"
+	require.NoError(t, subscriber.HandleActionOutcome(&rolledBack))
+	assert.False(t, tracker.IsPendingVerification("testdb:missing_index:posts.user_id"))
+	assert.Equal(t, 0, tracker.GetPendingCount())
+}
+
+func TestHandleActionOutcome_FailedReopensDetection(t *testing.T) {
+	tracker := verification.NewTracker(3, nil, nil)
+	subscriber, store := startOutcomeSubscriber(t, tracker)
+
+	store.states["detection-redis-1"] = "resolved"
+
+	// A failed event from a rollback path carries no detection key
+	failed := &eventbus.ActionCompletedEvent{
+		ActionID:    "action-redis-1",
+		DetectionID: "detection-redis-1",
+		ActionType:  "deploy_redis",
+		DatabaseID:  "testdb",
+		Status:      "failed",
+	}
+	require.NoError(t, subscriber.HandleActionOutcome(failed))
+	assert.Equal(t, "reopened", store.state("detection-redis-1"))
+}
+
+func TestHandleActionOutcome_ReopenFailureIsRetried(t *testing.T) {
+	tracker := verification.NewTracker(3, nil, nil)
+
+	knowledgeClient, err := knowledge.NewKnowledgeClient("127.0.0.1:1", transport.TLSConfig{})
+	require.NoError(t, err)
+	defer knowledgeClient.Close()
+
+	subscriber, err := eventbus.NewSubscriber("nats://127.0.0.1:1", false, knowledgeClient, tracker)
+	require.NoError(t, err)
+	defer subscriber.Close()
+
+	err = subscriber.HandleActionOutcome(&eventbus.ActionCompletedEvent{
+		ActionID:    "action-redis-2",
+		DetectionID: "detection-redis-2",
+		ActionType:  "deploy_redis",
+		Status:      "rolled_back",
+	})
+	assert.Error(t, err)
+}
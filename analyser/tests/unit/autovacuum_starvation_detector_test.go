@@ -0,0 +1,81 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/detector"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+	"github.com/stretchr/testify/assert"
+)
+
+func autovacuumSnapshot(deadTuples, delta float64, lastAutovacuum string) *normaliser.NormalisedMetrics {
+	return &normaliser.NormalisedMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "postgres",
+		Timestamp:    time.Now().Unix(),
+		Labels: map[string]string{
+			"pg.table.orders.last_autovacuum": lastAutovacuum,
+		},
+		ExtendedMetrics: map[string]float64{
+			"pg.table.orders.dead_tuples": deadTuples,
+			"pg.table.orders.live_tuples": 100000,
+		},
+		DeltasReliable: true,
+		MetricDeltas: map[string]float64{
+			"pg.table.orders.dead_tuples": delta,
+		},
+	}
+}
+
+func TestAutovacuumStarvationDetector_FiresAfterConsecutiveGrowth(t *testing.T) {
+	det := detector.NewAutovacuumStarvationDetector()
+	stale := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+
+	assert.Nil(t, det.Detect(autovacuumSnapshot(5000, 500, stale)))
+	assert.Nil(t, det.Detect(autovacuumSnapshot(5500, 500, stale)))
+	detection := det.Detect(autovacuumSnapshot(6000, 500, stale))
+
+	assert.NotNil(t, detection, "Detection should fire after 3 cycles of growth")
+	assert.Equal(t, "autovacuum_starvation", detection.DetectorName)
+	assert.Equal(t, models.CategoryStorage, detection.Category)
+	assert.Equal(t, "vacuum_table", detection.ActionType)
+	assert.Equal(t, "orders", detection.ActionMetadata["table_name"])
+}
+
+func TestAutovacuumStarvationDetector_GrowthStreakResets(t *testing.T) {
+	det := detector.NewAutovacuumStarvationDetector()
+	det.SetConsecutiveCycles(2)
+
+	det.Detect(autovacuumSnapshot(5000, 500, "never"))
+	det.Detect(autovacuumSnapshot(4000, -1000, "never"))
+	detection := det.Detect(autovacuumSnapshot(4500, 500, "never"))
+
+	assert.Nil(t, detection, "A shrinking cycle should reset the growth streak")
+}
+
+func TestAutovacuumStarvationDetector_NoDetectionWhenAutovacuumRecent(t *testing.T) {
+	det := detector.NewAutovacuumStarvationDetector()
+	det.SetConsecutiveCycles(1)
+	recent := time.Now().Add(-5 * time.Minute).UTC().Format(time.RFC3339)
+
+	detection := det.Detect(autovacuumSnapshot(5000, 500, recent))
+
+	assert.Nil(t, detection, "Detection should not fire when autovacuum ran recently")
+}
+
+func TestAutovacuumStarvationDetector_NoDetectionWhenDataMissing(t *testing.T) {
+	det := detector.NewAutovacuumStarvationDetector()
+
+	snapshot := &normaliser.NormalisedMetrics{
+		DatabaseID:      "test-db",
+		DatabaseType:    "postgres",
+		Labels:          map[string]string{},
+		ExtendedMetrics: map[string]float64{},
+	}
+
+	detection := det.Detect(snapshot)
+
+	assert.Nil(t, detection, "Detection should not fire without dead tuple deltas")
+}
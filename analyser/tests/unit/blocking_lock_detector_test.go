@@ -0,0 +1,159 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/config"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/detector"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/thresholds"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func blockingLockSnapshot(waitSecs float64) *normaliser.NormalisedMetrics {
+	return &normaliser.NormalisedMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "postgres",
+		Labels: map[string]string{
+			"pg.lock_wait_pid":            "200",
+			"pg.lock_wait_query":          "UPDATE orders SET total = 0 WHERE id = 1",
+			"pg.lock_wait_relation":       "orders",
+			"pg.lock_wait_blocking_pids":  "100",
+			"pg.blocking_pid":             "100",
+			"pg.lock_blocker_user":        "app_user",
+			"pg.lock_blocker_application": "orders-api",
+			"pg.lock_blocker_query":       "UPDATE orders SET status = 'paid' WHERE id = 1",
+			"pg.lock_blocker_state":       "idle in transaction",
+		},
+		ExtendedMetrics: map[string]float64{
+			"pg.blocked_count":  4,
+			"pg.lock_wait_secs": waitSecs,
+		},
+	}
+}
+
+func TestBlockingLockDetector_FiresWhenAboveThreshold(t *testing.T) {
+	det := detector.NewBlockingLockDetector()
+
+	detection := det.Detect(blockingLockSnapshot(90))
+
+	require.NotNil(t, detection, "Detection should fire when a lock has blocked others > 60s")
+	assert.Equal(t, "blocking_lock", detection.DetectorName)
+	assert.Equal(t, models.CategoryConnection, detection.Category)
+	assert.Equal(t, "terminate_query", detection.ActionType)
+	assert.Equal(t, 90.0, detection.Value)
+}
+
+func TestBlockingLockDetector_NoDetectionWhenBelowThreshold(t *testing.T) {
+	det := detector.NewBlockingLockDetector()
+
+	assert.Nil(t, det.Detect(blockingLockSnapshot(30)), "Detection should not fire when the wait is < 60s")
+}
+
+func TestBlockingLockDetector_NoDetectionWhenDataMissing(t *testing.T) {
+	det := detector.NewBlockingLockDetector()
+
+	snapshot := &normaliser.NormalisedMetrics{
+		DatabaseID:      "test-db",
+		DatabaseType:    "postgres",
+		Labels:          map[string]string{},
+		ExtendedMetrics: map[string]float64{},
+	}
+
+	assert.Nil(t, det.Detect(snapshot), "Detection should not fire when data is missing")
+}
+
+func TestBlockingLockDetector_NoDetectionWithoutBlocker(t *testing.T) {
+	det := detector.NewBlockingLockDetector()
+
+	snapshot := blockingLockSnapshot(600)
+	delete(snapshot.Labels, "pg.blocking_pid")
+
+	assert.Nil(t, det.Detect(snapshot), "Nothing to terminate when the blocker has gone away")
+}
+
+func TestBlockingLockDetector_Severity(t *testing.T) {
+	det := detector.NewBlockingLockDetector()
+
+	cases := []struct {
+		waitSecs float64
+		want     models.DetectionSeverity
+	}{
+		{60, models.SeverityInfo},
+		{179, models.SeverityInfo},
+		{180, models.SeverityWarning},
+		{299, models.SeverityWarning},
+		{300, models.SeverityCritical},
+		{1200, models.SeverityCritical},
+	}
+
+	for _, tc := range cases {
+		detection := det.Detect(blockingLockSnapshot(tc.waitSecs))
+		require.NotNil(t, detection, "%.0fs", tc.waitSecs)
+		assert.Equal(t, tc.want, detection.Severity, "%.0fs", tc.waitSecs)
+	}
+}
+
+func TestBlockingLockDetector_CustomThreshold(t *testing.T) {
+	det := detector.NewBlockingLockDetector()
+	det.SetThreshold(20)
+
+	assert.NotNil(t, det.Detect(blockingLockSnapshot(30)), "Detection should fire above a lowered threshold")
+}
+
+func TestBlockingLockDetector_ActionMetadata(t *testing.T) {
+	det := detector.NewBlockingLockDetector()
+
+	detection := det.Detect(blockingLockSnapshot(120))
+
+	require.NotNil(t, detection)
+	assert.Equal(t, "100", detection.ActionMetadata["pid"], "The blocker is terminated, not the waiter")
+	assert.Equal(t, false, detection.ActionMetadata["graceful"])
+	assert.Equal(t, "app_user", detection.ActionMetadata["username"])
+	assert.Equal(t, "orders-api", detection.ActionMetadata["application_name"])
+	assert.Equal(t, "UPDATE orders SET status = 'paid' WHERE id = 1", detection.ActionMetadata["query"])
+
+	assert.Equal(t, 4.0, detection.Evidence["blocked_count"])
+	assert.Equal(t, "200", detection.Evidence["blocked_pid"])
+	assert.Equal(t, "orders", detection.Evidence["blocked_relation"])
+}
+
+func TestBlockingLockDetector_DeniedBlockerIsRecommendation(t *testing.T) {
+	det := detector.NewBlockingLockDetector()
+	det.SetDenylist([]string{"*migrat*"}, []string{"replicator"})
+
+	snapshot := blockingLockSnapshot(400)
+	snapshot.Labels["pg.lock_blocker_application"] = "Schema-Migrate"
+
+	detection := det.Detect(snapshot)
+
+	require.NotNil(t, detection, "Denied blockers are still reported")
+	assert.Equal(t, "deadlock_investigation_recommendation", detection.ActionType)
+	assert.Nil(t, detection.ActionMetadata["graceful"], "No termination metadata for a denied session")
+	assert.Contains(t, detection.Evidence["protected"], "Schema-Migrate")
+
+	// The username list is checked as well
+	snapshot.Labels["pg.lock_blocker_application"] = "psql"
+	snapshot.Labels["pg.lock_blocker_user"] = "replicator"
+
+	detection = det.Detect(snapshot)
+
+	require.NotNil(t, detection)
+	assert.Equal(t, "deadlock_investigation_recommendation", detection.ActionType)
+}
+
+func TestBlockingLockDetector_ThresholdFromConfig(t *testing.T) {
+	t.Setenv("THRESHOLD_LOCK_WAIT_SECS", "15")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 15.0, cfg.Thresholds.LockWaitThresholdSecs)
+	assert.Equal(t, 15.0, cfg.Thresholds.Values()["lock_wait_secs"])
+
+	det := detector.NewBlockingLockDetector()
+	thresholds.Apply(det, cfg.Thresholds)
+
+	assert.NotNil(t, det.Detect(blockingLockSnapshot(20)))
+}
@@ -29,6 +29,7 @@ func TestCacheRateMissDetector_FiresWhenBelowThreshold(t *testing.T) {
 	assert.Equal(t, models.CategoryCache, detection.Category)
 	assert.Equal(t, models.SeverityInfo, detection.Severity)
 	assert.Contains(t, detection.Title, "85%")
+	assert.Equal(t, 0.85, detection.Value, "Value should carry the hit rate")
 }
 
 func TestCacheRateMissDetector_NoDetectionWhenAboveThreshold(t *testing.T) {
@@ -216,3 +217,48 @@ func TestCacheMissDetector_EvidenceData(t *testing.T) {
 	assert.True(t, ok, "Evidence should contain cache_health")
 	assert.Equal(t, 0.85, cacheHealth)
 }
+
+func TestCacheMissDetector_ContainerizedPostgresIncreasesCache(t *testing.T) {
+	det := detector.NewCacheMissDetector()
+
+	hitRate := 0.85
+	snapshot := &normaliser.NormalisedMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "postgres",
+		Measurements: normaliser.Measurements{
+			CacheHitRate: &hitRate,
+		},
+		Labels: map[string]string{
+			normaliser.LabelContainerName: "startupmonkey-postgres",
+		},
+	}
+
+	detection := det.Detect(snapshot)
+
+	assert.NotNil(t, detection)
+	assert.Equal(t, "increase_cache_size", detection.ActionType)
+	assert.Equal(t, "startupmonkey-postgres", detection.ActionMetadata["container_name"])
+	assert.Contains(t, detection.ActionMetadata, "safe_option")
+}
+
+func TestCacheMissDetector_ContainerizedMySQLFallsBackToRecommendation(t *testing.T) {
+	det := detector.NewCacheMissDetector()
+
+	hitRate := 0.85
+	snapshot := &normaliser.NormalisedMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "mysql",
+		Measurements: normaliser.Measurements{
+			CacheHitRate: &hitRate,
+		},
+		Labels: map[string]string{
+			normaliser.LabelContainerName: "orders-mysql",
+		},
+	}
+
+	detection := det.Detect(snapshot)
+
+	assert.NotNil(t, detection)
+	assert.Equal(t, "cache_optimization_recommendation", detection.ActionType)
+	assert.NotContains(t, detection.ActionMetadata, "container_name")
+}
@@ -0,0 +1,129 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/config"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/detector"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/thresholds"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/detectionkey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// churnSnapshot describes newConnections opened over a 30s interval.
+func churnSnapshot(newConnections float64) *normaliser.NormalisedMetrics {
+	active := int32(20)
+	max := int32(100)
+	return &normaliser.NormalisedMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "postgres",
+		Measurements: normaliser.Measurements{
+			ActiveConnections: &active,
+			MaxConnections:    &max,
+		},
+		MetricDeltas: map[string]float64{
+			"sessions":     newConnections,
+			"transactions": newConnections * 2,
+		},
+		TimeDeltaSeconds: 30,
+		DeltasReliable:   true,
+		ExtendedMetrics:  map[string]float64{},
+		Labels:           map[string]string{},
+	}
+}
+
+func TestConnectionChurnDetector_FiresWhenAboveThreshold(t *testing.T) {
+	det := detector.NewConnectionChurnDetector()
+
+	detection := det.Detect(churnSnapshot(450)) // 15/s
+
+	require.NotNil(t, detection, "Detection should fire when > 10 connections/s are opened")
+	assert.Equal(t, "connection_churn", detection.DetectorName)
+	assert.Equal(t, models.CategoryConnection, detection.Category)
+	assert.Equal(t, models.SeverityInfo, detection.Severity)
+	assert.Equal(t, "deploy_connection_pooler", detection.ActionType)
+	assert.Equal(t, 15.0, detection.Value)
+	assert.Equal(t, 2.0, detection.Evidence["transactions_per_connection"])
+	assert.Equal(t, 20, detection.Evidence["active_connections"])
+}
+
+func TestConnectionChurnDetector_NoDetectionWhenBelowThreshold(t *testing.T) {
+	det := detector.NewConnectionChurnDetector()
+
+	assert.Nil(t, det.Detect(churnSnapshot(150)), "Detection should not fire at 5 connections/s")
+}
+
+func TestConnectionChurnDetector_NoDetectionWhenDataMissing(t *testing.T) {
+	det := detector.NewConnectionChurnDetector()
+
+	snapshot := churnSnapshot(900)
+	delete(snapshot.MetricDeltas, "sessions")
+
+	assert.Nil(t, det.Detect(snapshot), "Detection should not fire without a session count")
+}
+
+func TestConnectionChurnDetector_UnreliableDeltasIgnored(t *testing.T) {
+	det := detector.NewConnectionChurnDetector()
+
+	// A stats reset makes the whole counter look like this cycle's connections
+	snapshot := churnSnapshot(900)
+	snapshot.DeltasReliable = false
+
+	assert.Nil(t, det.Detect(snapshot))
+}
+
+func TestConnectionChurnDetector_Severity(t *testing.T) {
+	det := detector.NewConnectionChurnDetector()
+
+	cases := []struct {
+		newConnections float64
+		want           models.DetectionSeverity
+	}{
+		{300, models.SeverityInfo},      // 10/s
+		{599, models.SeverityInfo},      // just under 20/s
+		{600, models.SeverityWarning},   // 20/s
+		{1200, models.SeverityCritical}, // 40/s
+		{3000, models.SeverityCritical},
+	}
+
+	for _, tc := range cases {
+		detection := det.Detect(churnSnapshot(tc.newConnections))
+		require.NotNil(t, detection, "%.0f connections", tc.newConnections)
+		assert.Equal(t, tc.want, detection.Severity, "%.0f connections", tc.newConnections)
+	}
+}
+
+func TestConnectionChurnDetector_KeyDistinctFromPoolExhaustion(t *testing.T) {
+	snapshot := churnSnapshot(900)
+	active := int32(96) // the pool is nearly full as well
+	snapshot.Measurements.ActiveConnections = &active
+
+	churn := detector.NewConnectionChurnDetector().Detect(snapshot)
+	exhaustion := detector.NewConnectionPoolDetection().Detect(snapshot)
+	require.NotNil(t, churn)
+	require.NotNil(t, exhaustion)
+
+	key := func(d *models.Detection) string {
+		identifier := detectionkey.Identifier(d.ActionMetadata, d.Evidence, string(d.Category))
+		return detectionkey.Build(d.DatabaseID, d.DetectorName, identifier)
+	}
+
+	assert.Equal(t, churn.ActionType, exhaustion.ActionType)
+	assert.False(t, detectionkey.SameIssue(key(churn), key(exhaustion)), "Both are reported, not deduplicated into one")
+}
+
+func TestConnectionChurnDetector_ThresholdFromConfig(t *testing.T) {
+	t.Setenv("THRESHOLD_CONNECTION_CHURN_PER_SEC", "2")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, cfg.Thresholds.Values()["connection_churn_per_sec"])
+
+	det := detector.NewConnectionChurnDetector()
+	thresholds.Apply(det, cfg.Thresholds)
+
+	assert.NotNil(t, det.Detect(churnSnapshot(90)), "3/s is above a lowered threshold")
+}
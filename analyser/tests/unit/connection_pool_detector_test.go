@@ -30,6 +30,7 @@ func TestConnectionPoolDetector_FiresWhenAboveThreshold(t *testing.T) {
 	assert.Equal(t, "connection_pool_exhaustion", detection.DetectorName)
 	assert.Equal(t, models.CategoryConnection, detection.Category)
 	assert.Equal(t, models.SeverityWarning, detection.Severity)
+	assert.InDelta(t, 0.85, detection.Value, 0.0001, "Value should carry the usage ratio")
 }
 
 func TestConnectionPoolDetector_NoDetectionBelowThreshold(t *testing.T) {
@@ -173,3 +174,80 @@ func TestConnectionPoolDetector_ZeroDivisionProtection(t *testing.T) {
 
 	assert.Nil(t, detection, "Detection should not fire when max is 0")
 }
+
+func TestConnectionPoolDetector_IdleSaturationRecommendsCleanup(t *testing.T) {
+	det := detector.NewConnectionPoolDetection()
+
+	active := int32(5)
+	idle := int32(80)
+	max := int32(100)
+
+	snapshot := &normaliser.NormalisedMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "postgres",
+		Labels: map[string]string{
+			"pg.idle_connection_apps": "billing-api=70,worker=10",
+		},
+		Measurements: normaliser.Measurements{
+			ActiveConnections: &active,
+			IdleConnections:   &idle,
+			MaxConnections:    &max,
+		},
+	}
+
+	detection := det.Detect(snapshot)
+
+	assert.NotNil(t, detection, "Detection should fire when idle connections fill the pool.")
+	assert.Equal(t, "idle_connection_cleanup", detection.ActionType)
+	assert.Equal(t, models.SeverityWarning, detection.Severity)
+	assert.InDelta(t, 0.8, detection.Evidence["idle_ratio"], 0.001)
+	assert.InDelta(t, 0.05, detection.Evidence["usage_ratio"], 0.001)
+	assert.Equal(t, map[string]interface{}{"billing-api": 70, "worker": 10}, detection.Evidence["idle_by_app"])
+	assert.Contains(t, detection.Description, "billing-api (70)")
+}
+
+func TestConnectionPoolDetector_ActiveSaturationStillDeploysPooler(t *testing.T) {
+	det := detector.NewConnectionPoolDetection()
+
+	active := int32(90)
+	idle := int32(5)
+	max := int32(100)
+
+	snapshot := &normaliser.NormalisedMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "postgres",
+		Measurements: normaliser.Measurements{
+			ActiveConnections: &active,
+			IdleConnections:   &idle,
+			MaxConnections:    &max,
+		},
+	}
+
+	detection := det.Detect(snapshot)
+
+	assert.NotNil(t, detection)
+	assert.Equal(t, "deploy_connection_pooler", detection.ActionType)
+	assert.InDelta(t, 0.05, detection.Evidence["idle_ratio"], 0.001)
+}
+
+func TestConnectionPoolDetector_IdleThresholdsConfigurable(t *testing.T) {
+	det := detector.NewConnectionPoolDetection()
+	det.SetThreshold(0.9)
+	det.SetIdleThresholds(0.9, 0.7)
+
+	active := int32(5)
+	idle := int32(80)
+	max := int32(100)
+
+	snapshot := &normaliser.NormalisedMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "postgres",
+		Measurements: normaliser.Measurements{
+			ActiveConnections: &active,
+			IdleConnections:   &idle,
+			MaxConnections:    &max,
+		},
+	}
+
+	assert.Nil(t, det.Detect(snapshot), "Detection should not fire below the idle threshold.")
+}
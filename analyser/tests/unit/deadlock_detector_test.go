@@ -0,0 +1,75 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/detector"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+	"github.com/stretchr/testify/assert"
+)
+
+func deadlockSnapshot(delta, intervalSecs float64) *normaliser.NormalisedMetrics {
+	return &normaliser.NormalisedMetrics{
+		DatabaseID:       "test-db",
+		DatabaseType:     "postgres",
+		TimeDeltaSeconds: intervalSecs,
+		DeltasReliable:   true,
+		MetricDeltas: map[string]float64{
+			"deadlocks": delta,
+		},
+		Labels: map[string]string{
+			"pg.lock_wait_pid":      "4242",
+			"pg.lock_wait_user":     "app_user",
+			"pg.lock_wait_query":    "UPDATE orders SET status = 'paid' WHERE id = 7",
+			"pg.lock_wait_relation": "orders",
+		},
+		ExtendedMetrics: map[string]float64{
+			"pg.blocked_count": 1,
+		},
+	}
+}
+
+func TestDeadlockDetector_FiresWhenDeltaAboveThreshold(t *testing.T) {
+	det := detector.NewDeadlockDetector()
+
+	detection := det.Detect(deadlockSnapshot(1, 120))
+
+	assert.NotNil(t, detection, "Detection should fire on any new deadlock by default")
+	assert.Equal(t, "deadlock", detection.DetectorName)
+	assert.Equal(t, models.CategoryQuery, detection.Category)
+	assert.Equal(t, "deadlock_investigation_recommendation", detection.ActionType)
+	assert.Equal(t, models.SeverityInfo, detection.Severity)
+	assert.Equal(t, "orders", detection.Evidence["blocked_relation"])
+}
+
+func TestDeadlockDetector_SeverityScalesWithRate(t *testing.T) {
+	det := detector.NewDeadlockDetector()
+
+	assert.Equal(t, models.SeverityWarning, det.Detect(deadlockSnapshot(2, 60)).Severity)
+	assert.Equal(t, models.SeverityCritical, det.Detect(deadlockSnapshot(10, 60)).Severity)
+}
+
+func TestDeadlockDetector_NoDetectionWhenBelowThreshold(t *testing.T) {
+	det := detector.NewDeadlockDetector()
+	det.SetThreshold(3)
+
+	detection := det.Detect(deadlockSnapshot(3, 60))
+
+	assert.Nil(t, detection, "Detection should not fire when delta does not exceed threshold")
+}
+
+func TestDeadlockDetector_NoDetectionWhenDataMissing(t *testing.T) {
+	det := detector.NewDeadlockDetector()
+
+	snapshot := &normaliser.NormalisedMetrics{
+		DatabaseID:      "test-db",
+		DatabaseType:    "postgres",
+		Labels:          map[string]string{},
+		ExtendedMetrics: map[string]float64{},
+	}
+
+	detection := det.Detect(snapshot)
+
+	assert.Nil(t, detection, "Detection should not fire without a deadlock delta")
+}
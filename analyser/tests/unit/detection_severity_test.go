@@ -0,0 +1,14 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectionSeverity_Rank(t *testing.T) {
+	assert.Greater(t, models.SeverityCritical.Rank(), models.SeverityWarning.Rank())
+	assert.Greater(t, models.SeverityWarning.Rank(), models.SeverityInfo.Rank())
+	assert.Greater(t, models.SeverityInfo.Rank(), models.DetectionSeverity("").Rank())
+}
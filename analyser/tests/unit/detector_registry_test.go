@@ -0,0 +1,106 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/config"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/detector"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/engine"
+	grpcserver "github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/grpc"
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectorRegistry_NamesMatchDetectors(t *testing.T) {
+	thresholdNames := config.DetectionThresholds{}.Values()
+
+	for _, reg := range detector.Registrations() {
+		d := reg.New()
+		assert.Equal(t, reg.Name, d.Name())
+		require.NotEmpty(t, reg.Thresholds, "%s has no thresholds", reg.Name)
+		for _, name := range reg.Thresholds {
+			_, ok := thresholdNames[name]
+			assert.True(t, ok, "%s reads unknown threshold %s", reg.Name, name)
+		}
+	}
+}
+
+func TestEnabledDetectors_ExpandsAllInRunOrder(t *testing.T) {
+	cfg := &config.Config{Detectors: []string{"all"}}
+	names, err := cfg.EnabledDetectors()
+	require.NoError(t, err)
+	assert.Equal(t, detector.Names(), names)
+
+	cfg.Detectors = []string{"deadlock", "missing_index"}
+	names, err = cfg.EnabledDetectors()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"missing_index", "deadlock"}, names)
+
+	cfg.Detectors = nil
+	names, err = cfg.EnabledDetectors()
+	require.NoError(t, err)
+	assert.Empty(t, names)
+}
+
+func TestEnabledDetectors_UnknownNameListsValidOnes(t *testing.T) {
+	cfg := &config.Config{Detectors: []string{"missing_index", "missing_indexes"}}
+	_, err := cfg.EnabledDetectors()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"missing_indexes"`)
+	assert.Contains(t, err.Error(), "connection_pool_exhaustion, missing_index,")
+}
+
+func TestLoad_DetectorsFromEnv(t *testing.T) {
+	t.Setenv("DETECTORS", "cache_miss_rate_high, deadlock")
+	t.Setenv("DETECTOR_CACHE_MISS_RATE_HIGH_THRESHOLD", "0.95")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+
+	names, err := cfg.EnabledDetectors()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cache_miss_rate_high", "deadlock"}, names)
+	assert.Equal(t, 0.95, cfg.Thresholds.CacheHitRateThreshold)
+}
+
+func TestLoad_RejectsUnknownDetectors(t *testing.T) {
+	t.Setenv("DETECTORS", "not_a_detector")
+	_, err := config.Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "valid detectors")
+
+	t.Setenv("DETECTORS", "all")
+	t.Setenv("DETECTOR_NOT_A_DETECTOR_THRESHOLD", "1")
+	_, err = config.Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "DETECTOR_NOT_A_DETECTOR_THRESHOLD")
+}
+
+func TestGetRegisteredDetectors_ListsThresholdsInEffect(t *testing.T) {
+	detectionEngine := engine.NewEngine()
+	detectionEngine.RegisterDetector(detector.NewMissingIndexDetector())
+	detectionEngine.RegisterDetector(detector.NewCacheMissDetector())
+
+	server := grpcserver.NewMetricsServer(detectionEngine, nil, nil, nil)
+	server.SetThresholdSource(func(databaseID string) config.DetectionThresholds {
+		thresholds := baseThresholds()
+		if databaseID == "replica" {
+			thresholds.CacheHitRateThreshold = 0.5
+		}
+		return thresholds
+	})
+	client := startMetricsServer(t, server)
+
+	resp, err := client.GetRegisteredDetectors(context.Background(), &pb.RegisteredDetectorsRequest{DatabaseId: "replica"})
+	require.NoError(t, err)
+	require.Len(t, resp.Detectors, 2)
+
+	assert.Equal(t, "missing_index", resp.Detectors[0].Name)
+	assert.Equal(t, "query", resp.Detectors[0].Category)
+	assert.Equal(t, 10.0, resp.Detectors[0].Thresholds["sequential_scan_delta"])
+
+	assert.Equal(t, "cache_miss_rate_high", resp.Detectors[1].Name)
+	assert.Equal(t, 0.5, resp.Detectors[1].Thresholds["cache_hit_rate_threshold"])
+}
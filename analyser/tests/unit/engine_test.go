@@ -69,3 +69,83 @@ func TestEngine_RunDetectors_MultipleIssues(t *testing.T) {
 	assert.Contains(t, detectorNames, "cache_miss_rate_high")
 	assert.Contains(t, detectorNames, "connection_pool_exhaustion")
 }
+
+func TestEngine_RunDetectors_CopiesCorrelationID(t *testing.T) {
+	eng := engine.NewEngine()
+	eng.RegisterDetector(detector.NewCacheMissDetector())
+
+	hitRate := 0.5
+	snapshot := &normaliser.NormalisedMetrics{
+		DatabaseID:    "test-db",
+		DatabaseType:  "postgres",
+		CorrelationID: "cycle-123",
+		Measurements: normaliser.Measurements{
+			CacheHitRate: &hitRate,
+		},
+	}
+
+	detections := eng.RunDetectors(snapshot)
+
+	assert.Len(t, detections, 1)
+	assert.Equal(t, "cycle-123", detections[0].CorrelationID)
+}
+
+func TestEngine_RunDetectors_IsolatesStatePerDatabase(t *testing.T) {
+	unusedIndex := detector.NewUnusedIndexDetector()
+	unusedIndex.SetUnusedCycles(3)
+
+	eng := engine.NewEngine()
+	eng.RegisterDetector(unusedIndex)
+
+	snapshotFor := func(databaseID string, scanDelta float64) *normaliser.NormalisedMetrics {
+		snapshot := unusedIndexSnapshot(50*1024*1024, scanDelta)
+		snapshot.DatabaseID = databaseID
+		return snapshot
+	}
+
+	// db-a's index sits idle while db-b's identically named index is scanned every cycle
+	for cycle := 1; cycle <= 3; cycle++ {
+		detectionsA := eng.RunDetectors(snapshotFor("db-a", 0))
+		detectionsB := eng.RunDetectors(snapshotFor("db-b", 5))
+
+		assert.Empty(t, detectionsB, "db-b scans its index and should never fire")
+		if cycle < 3 {
+			assert.Empty(t, detectionsA, "db-a should not fire before 3 idle cycles (cycle %d)", cycle)
+			continue
+		}
+		if assert.Len(t, detectionsA, 1, "db-a's streak should survive db-b's scans") {
+			assert.Equal(t, "db-a", detectionsA[0].DatabaseID)
+		}
+	}
+
+	// Two idle cycles on db-c must not combine with db-a's history
+	assert.Empty(t, eng.RunDetectors(snapshotFor("db-c", 0)))
+	assert.Empty(t, eng.RunDetectors(snapshotFor("db-c", 0)))
+	assert.Len(t, eng.RunDetectors(snapshotFor("db-c", 0)), 1)
+}
+
+func TestEngine_ForgetDatabase_ResetsState(t *testing.T) {
+	unusedIndex := detector.NewUnusedIndexDetector()
+	unusedIndex.SetUnusedCycles(2)
+
+	eng := engine.NewEngine()
+	eng.RegisterDetector(unusedIndex)
+
+	assert.Empty(t, eng.RunDetectors(unusedIndexSnapshot(50*1024*1024, 0)))
+	eng.ForgetDatabase("test-db")
+
+	assert.Empty(t, eng.RunDetectors(unusedIndexSnapshot(50*1024*1024, 0)), "streak should restart after ForgetDatabase")
+	assert.Len(t, eng.RunDetectors(unusedIndexSnapshot(50*1024*1024, 0)), 1)
+}
+
+func TestEngine_RunDetectors_MultipleDetectionsFromOneDetector(t *testing.T) {
+	eng := engine.NewEngine()
+	eng.RegisterDetector(detector.NewMissingIndexDetector())
+
+	detections := eng.RunDetectors(multiTableSnapshot())
+
+	assert.Len(t, detections, 2, "One detection per candidate table above the delta threshold")
+	for _, d := range detections {
+		assert.Equal(t, "missing_index", d.DetectorName)
+	}
+}
@@ -2,20 +2,30 @@ package unit
 
 import (
 	"context"
+	"io"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/engine"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/eventbus"
 	grpcserver "github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/grpc"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/knowledge"
 	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/transport"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 )
 
 func TestNewMetricsServer(t *testing.T) {
 	detectionEngine := engine.NewEngine()
-	publisher, _ := eventbus.NewPublisher("test")
-	knowledgeClient, _ := knowledge.NewKnowledgeClient("localhost:50053")
+	publisher, _ := eventbus.NewPublisher("test", false)
+	knowledgeClient, _ := knowledge.NewKnowledgeClient("localhost:50053", transport.TLSConfig{})
 	server := grpcserver.NewMetricsServer(detectionEngine, publisher, knowledgeClient, nil)
 
 	assert.NotNil(t, server)
@@ -23,8 +33,8 @@ func TestNewMetricsServer(t *testing.T) {
 
 func TestRegisterDatabase(t *testing.T) {
 	detectionEngine := engine.NewEngine()
-	publisher, _ := eventbus.NewPublisher("test")
-	knowledgeClient, _ := knowledge.NewKnowledgeClient("localhost:50053")
+	publisher, _ := eventbus.NewPublisher("test", false)
+	knowledgeClient, _ := knowledge.NewKnowledgeClient("localhost:50053", transport.TLSConfig{})
 	server := grpcserver.NewMetricsServer(detectionEngine, publisher, knowledgeClient, nil)
 	ctx := context.Background()
 
@@ -42,7 +52,236 @@ func TestRegisterDatabase(t *testing.T) {
 	assert.Equal(t, "test-db-1", ack.AssignedId)
 }
 
-func TestPlaceholder_StreamMetrics(t *testing.T) {
-	// Placeholder - full stream testing in integration tests
-	t.Log("StreamMetrics tested via integration tests (Issue #10)")
+func startMetricsServer(t *testing.T, server *grpcserver.MetricsServer) pb.MetricsServiceClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer()
+	pb.RegisterMetricsServiceServer(grpcServer, server)
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewMetricsServiceClient(conn)
+}
+
+func TestStreamMetrics_AcksPeriodicallyWithoutClosing(t *testing.T) {
+	server := grpcserver.NewMetricsServer(engine.NewEngine(), nil, nil, nil)
+	server.SetAckInterval(10 * time.Millisecond)
+	client := startMetricsServer(t, server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamMetrics(ctx)
+	require.NoError(t, err)
+
+	for seq := uint64(1); seq <= 3; seq++ {
+		require.NoError(t, stream.Send(&pb.MetricSnapshot{DatabaseId: "test-db", Sequence: seq}))
+	}
+
+	// The ack arrives while the stream is still open
+	ack, err := stream.Recv()
+	require.NoError(t, err)
+	assert.LessOrEqual(t, ack.AckedSequence, uint64(3))
+
+	for ack.AckedSequence < 3 {
+		ack, err = stream.Recv()
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int64(3), ack.TotalMetrics)
+
+	// A later snapshot on the same stream is acknowledged too
+	require.NoError(t, stream.Send(&pb.MetricSnapshot{DatabaseId: "test-db", Sequence: 4}))
+	ack, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), ack.AckedSequence)
+
+	require.NoError(t, stream.CloseSend())
+	_, err = stream.Recv()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestStreamMetrics_FinalAckOnClose(t *testing.T) {
+	server := grpcserver.NewMetricsServer(engine.NewEngine(), nil, nil, nil)
+	server.SetAckInterval(time.Hour)
+	client := startMetricsServer(t, server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamMetrics(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Send(&pb.MetricSnapshot{DatabaseId: "test-db", Sequence: 7}))
+	require.NoError(t, stream.CloseSend())
+
+	ack, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), ack.AckedSequence)
+	assert.Equal(t, int64(1), ack.TotalMetrics)
+}
+
+// streamAndClose sends snapshots on one stream and returns the final ack.
+func streamAndClose(t *testing.T, server *grpcserver.MetricsServer, snapshots ...*pb.MetricSnapshot) *pb.MetricsAck {
+	t.Helper()
+
+	server.SetAckInterval(time.Hour)
+	client := startMetricsServer(t, server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamMetrics(ctx)
+	require.NoError(t, err)
+
+	for _, snapshot := range snapshots {
+		require.NoError(t, stream.Send(snapshot))
+	}
+	require.NoError(t, stream.CloseSend())
+
+	ack, err := stream.Recv()
+	require.NoError(t, err)
+	return ack
+}
+
+func TestStreamMetrics_RejectsMalformedSnapshots(t *testing.T) {
+	server := grpcserver.NewMetricsServer(engine.NewEngine(), nil, nil, nil)
+	now := time.Now().Unix()
+
+	ack := streamAndClose(t, server,
+		&pb.MetricSnapshot{DatabaseId: "", Sequence: 1, Timestamp: now},
+		&pb.MetricSnapshot{DatabaseId: "test-db", Sequence: 2, Timestamp: 0},
+		&pb.MetricSnapshot{DatabaseId: "test-db", Sequence: 3, Timestamp: now + 3600},
+	)
+
+	assert.Equal(t, int64(0), ack.Accepted)
+	assert.Equal(t, int64(3), ack.Rejected)
+	assert.Equal(t, "degraded", ack.Status)
+
+	// Rejected snapshots are still acknowledged so the Collector doesn't resend them
+	assert.Equal(t, uint64(3), ack.AckedSequence)
+	assert.Equal(t, int64(3), ack.TotalMetrics)
+
+	require.Len(t, ack.Rejections, 3)
+	assert.Equal(t, grpcserver.RejectMissingDatabaseID, ack.Rejections[0].Reason)
+	assert.Equal(t, grpcserver.RejectMissingTimestamp, ack.Rejections[1].Reason)
+	assert.Equal(t, grpcserver.RejectFutureTimestamp, ack.Rejections[2].Reason)
+	assert.Equal(t, uint64(2), ack.Rejections[1].Sequence)
+	assert.Equal(t, "test-db", ack.Rejections[1].DatabaseId)
+}
+
+func TestStreamMetrics_RejectsDuplicateAndOutOfOrderTimestamps(t *testing.T) {
+	server := grpcserver.NewMetricsServer(engine.NewEngine(), nil, nil, nil)
+	now := time.Now().Unix()
+
+	ack := streamAndClose(t, server,
+		&pb.MetricSnapshot{DatabaseId: "test-db", Sequence: 1, Timestamp: now - 20},
+		&pb.MetricSnapshot{DatabaseId: "test-db", Sequence: 2, Timestamp: now - 20},
+		&pb.MetricSnapshot{DatabaseId: "test-db", Sequence: 3, Timestamp: now - 30},
+		&pb.MetricSnapshot{DatabaseId: "other-db", Sequence: 4, Timestamp: now - 20},
+		&pb.MetricSnapshot{DatabaseId: "test-db", Sequence: 5, Timestamp: now - 10},
+	)
+
+	assert.Equal(t, int64(3), ack.Accepted)
+	assert.Equal(t, int64(2), ack.Rejected)
+	require.Len(t, ack.Rejections, 2)
+	assert.Equal(t, uint64(2), ack.Rejections[0].Sequence)
+	assert.Equal(t, grpcserver.RejectDuplicate, ack.Rejections[0].Reason)
+	assert.Equal(t, uint64(3), ack.Rejections[1].Sequence)
+	assert.Equal(t, grpcserver.RejectOutOfOrder, ack.Rejections[1].Reason)
+}
+
+func TestStreamMetrics_RateLimitsPerDatabase(t *testing.T) {
+	server := grpcserver.NewMetricsServer(engine.NewEngine(), nil, nil, nil)
+	server.SetSnapshotRateLimit(3)
+	now := time.Now().Unix()
+
+	var snapshots []*pb.MetricSnapshot
+	for seq := uint64(1); seq <= 5; seq++ {
+		snapshots = append(snapshots, &pb.MetricSnapshot{DatabaseId: "noisy-db", Sequence: seq, Timestamp: now - 60 + int64(seq)})
+	}
+	snapshots = append(snapshots, &pb.MetricSnapshot{DatabaseId: "quiet-db", Sequence: 6, Timestamp: now})
+
+	ack := streamAndClose(t, server, snapshots...)
+
+	// noisy-db's burst is cut off at the limit; quiet-db has its own allowance
+	assert.Equal(t, int64(4), ack.Accepted)
+	assert.Equal(t, int64(2), ack.Rejected)
+	for _, rejection := range ack.Rejections {
+		assert.Equal(t, "noisy-db", rejection.DatabaseId)
+		assert.Equal(t, grpcserver.RejectRateLimited, rejection.Reason)
+	}
+}
+
+func TestStreamMetrics_RateLimitDisabled(t *testing.T) {
+	server := grpcserver.NewMetricsServer(engine.NewEngine(), nil, nil, nil)
+	server.SetSnapshotRateLimit(0)
+	now := time.Now().Unix()
+
+	var snapshots []*pb.MetricSnapshot
+	for seq := uint64(1); seq <= 100; seq++ {
+		snapshots = append(snapshots, &pb.MetricSnapshot{DatabaseId: "test-db", Sequence: seq, Timestamp: now - 200 + int64(seq)})
+	}
+
+	ack := streamAndClose(t, server, snapshots...)
+
+	assert.Equal(t, int64(100), ack.Accepted)
+	assert.Equal(t, int64(0), ack.Rejected)
+	assert.Equal(t, "healthy", ack.Status)
+}
+
+func TestStreamMetrics_ReplayedSnapshotsSkipRateLimitAndDetectors(t *testing.T) {
+	server := grpcserver.NewMetricsServer(engine.NewEngine(), nil, nil, nil)
+	server.SetSnapshotRateLimit(3)
+	now := time.Now().Unix()
+
+	var snapshots []*pb.MetricSnapshot
+	for seq := uint64(1); seq <= 5; seq++ {
+		snapshots = append(snapshots, &pb.MetricSnapshot{DatabaseId: "catching-up", Sequence: seq, Timestamp: now - 600 + int64(seq), Replayed: true})
+	}
+
+	ack := streamAndClose(t, server, snapshots...)
+
+	// A Collector replaying its spool is not cut off, but the past is not acted on
+	assert.Equal(t, int64(5), ack.Accepted)
+	assert.Equal(t, int64(0), ack.Rejected)
+	assert.Nil(t, server.LastSnapshot("catching-up"))
+}
+
+func TestStreamMetrics_DrainLeavesNewSnapshotsForResend(t *testing.T) {
+	server := grpcserver.NewMetricsServer(engine.NewEngine(), nil, nil, nil)
+	server.SetAckInterval(10 * time.Millisecond)
+	client := startMetricsServer(t, server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamMetrics(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Send(&pb.MetricSnapshot{DatabaseId: "test-db", Sequence: 1}))
+	ack, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), ack.AckedSequence)
+
+	assert.True(t, server.Drain(time.Second), "nothing is being processed, so draining is immediate")
+
+	// Arrives after the drain: not processed or acked, so the Collector resends it elsewhere
+	require.NoError(t, stream.Send(&pb.MetricSnapshot{DatabaseId: "test-db", Sequence: 2}))
+	for {
+		ack, err = stream.Recv()
+		if err != nil {
+			break
+		}
+		assert.Less(t, ack.AckedSequence, uint64(2))
+	}
+	assert.Equal(t, codes.Unavailable, status.Code(err))
 }
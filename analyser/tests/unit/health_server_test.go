@@ -0,0 +1,98 @@
+package unit
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/config"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/orchestrator"
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// startServingKnowledge runs a Knowledge stand-in that only answers gRPC health checks.
+func startServingKnowledge(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	pb.RegisterKnowledgeServiceServer(server, &pb.UnimplementedKnowledgeServiceServer{})
+	healthpb.RegisterHealthServer(server, grpchealth.NewServer())
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+// startAnalyser starts the Analyser on free ports with NATS unreachable.
+func startAnalyser(t *testing.T, knowledgeAddress string) string {
+	t.Helper()
+
+	t.Setenv("GRPC_PORT", "0")
+	t.Setenv("HEALTH_PORT", "0")
+	t.Setenv("KNOWLEDGE_ADDRESS", knowledgeAddress)
+	t.Setenv("NATS_URL", "nats://127.0.0.1:1")
+	t.Setenv("NATS_JETSTREAM", "false")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+
+	o := orchestrator.NewOrchestrator(cfg)
+	require.NoError(t, o.Start())
+	t.Cleanup(func() { o.Stop() })
+
+	return "http://" + o.HealthAddr()
+}
+
+func getAnalyserHealth(t *testing.T, baseURL string) (int, health.HealthResponse) {
+	t.Helper()
+
+	resp, err := http.Get(baseURL + "/health")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var body health.HealthResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return resp.StatusCode, body
+}
+
+func TestAnalyserHealth_NATSDownIsDegraded(t *testing.T) {
+	code, body := getAnalyserHealth(t, startAnalyser(t, startServingKnowledge(t)))
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "degraded", body.Status)
+	assert.Equal(t, "analyser", body.Service)
+	assert.Equal(t, "up", body.Dependencies["knowledge"].Status)
+	assert.Equal(t, "down", body.Dependencies["nats"].Status)
+	assert.False(t, body.Dependencies["nats"].Critical)
+}
+
+func TestAnalyserHealth_OptionalConnectionsDownStayServing(t *testing.T) {
+	// Nothing listens here, so Knowledge is unreachable as well as NATS
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	unreachable := listener.Addr().String()
+	listener.Close()
+
+	baseURL := startAnalyser(t, unreachable)
+	code, body := getAnalyserHealth(t, baseURL)
+
+	assert.Equal(t, http.StatusOK, code, "neither connection is critical")
+	assert.Equal(t, "degraded", body.Status)
+	assert.Equal(t, "down", body.Dependencies["knowledge"].Status)
+	assert.Equal(t, "down", body.Dependencies["nats"].Status)
+
+	resp, err := http.Get(baseURL + "/metrics")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -138,3 +138,24 @@ func TestHighLatencyDetector_MySQLRecommendation(t *testing.T) {
 	assert.Contains(t, detection.Recommendation, "innodb_buffer_pool_size")
 	assert.Contains(t, detection.Recommendation, "MySQL")
 }
+
+func TestHighLatencyDetector_FallsBackToSlowQueryReport(t *testing.T) {
+	det := detector.NewHighLatencyDetector()
+
+	avgLatency := 150.0
+	snapshot := &normaliser.NormalisedMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "mongodb",
+		Measurements: normaliser.Measurements{
+			AvgQueryLatencyMs: &avgLatency,
+		},
+	}
+
+	detection := det.Detect(snapshot)
+
+	assert.NotNil(t, detection)
+	assert.Equal(t, "tune_config_high_latency", detection.ActionType)
+	assert.Equal(t, "report_slow_queries", detection.ActionMetadata["fallback_action_type"])
+	assert.Equal(t, 500.0, detection.ActionMetadata["slow_query_threshold_ms"])
+	assert.Equal(t, 20, detection.ActionMetadata["slow_query_limit"])
+}
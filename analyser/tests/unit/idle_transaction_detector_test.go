@@ -219,3 +219,63 @@ func TestIdleTransactionDetector_TitleShowsMinutes(t *testing.T) {
 	assert.NotNil(t, detection)
 	assert.Contains(t, detection.Title, "10 minutes")
 }
+
+func TestIdleTransactionDetector_PassesApplicationName(t *testing.T) {
+	det := detector.NewIdleTransactionDetector()
+
+	snapshot := &normaliser.NormalisedMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "postgres",
+		Labels: map[string]string{
+			"pg.idle_txn_pid":         "12345",
+			"pg.idle_txn_user":        "app_user",
+			"pg.idle_txn_application": "orders-api",
+			"pg.idle_txn_query":       "SELECT 1",
+		},
+		ExtendedMetrics: map[string]float64{
+			"pg.idle_txn_duration_secs": 400.0,
+		},
+	}
+
+	detection := det.Detect(snapshot)
+
+	assert.NotNil(t, detection)
+	assert.Equal(t, "terminate_query", detection.ActionType)
+	assert.Equal(t, "orders-api", detection.ActionMetadata["application_name"])
+	assert.Equal(t, "orders-api", detection.Evidence["application_name"])
+}
+
+func TestIdleTransactionDetector_DeniedSessionIsRecommendation(t *testing.T) {
+	det := detector.NewIdleTransactionDetector()
+	det.SetDenylist([]string{"*migrat*"}, []string{"replicator"})
+
+	snapshot := &normaliser.NormalisedMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "postgres",
+		Labels: map[string]string{
+			"pg.idle_txn_pid":         "12345",
+			"pg.idle_txn_user":        "app_user",
+			"pg.idle_txn_application": "Schema-Migrate",
+			"pg.idle_txn_query":       "ALTER TABLE users ADD COLUMN age int",
+		},
+		ExtendedMetrics: map[string]float64{
+			"pg.idle_txn_duration_secs": 1000.0,
+		},
+	}
+
+	detection := det.Detect(snapshot)
+
+	assert.NotNil(t, detection, "Denied sessions are still reported")
+	assert.Equal(t, "idle_transaction_recommendation", detection.ActionType)
+	assert.Nil(t, detection.ActionMetadata["graceful"], "No termination metadata for a denied session")
+	assert.Contains(t, detection.Evidence["protected"], "Schema-Migrate")
+
+	// The username list is checked as well
+	snapshot.Labels["pg.idle_txn_application"] = "psql"
+	snapshot.Labels["pg.idle_txn_user"] = "replicator"
+
+	detection = det.Detect(snapshot)
+
+	assert.NotNil(t, detection)
+	assert.Equal(t, "idle_transaction_recommendation", detection.ActionType)
+}
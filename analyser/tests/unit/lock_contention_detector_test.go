@@ -0,0 +1,129 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/detector"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lockContentionSnapshot(waiting int32, withBlocker bool) *normaliser.NormalisedMetrics {
+	snapshot := &normaliser.NormalisedMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "postgres",
+		Timestamp:    time.Now().Unix(),
+		Measurements: normaliser.Measurements{
+			WaitingConnections: &waiting,
+		},
+		ExtendedMetrics: map[string]float64{
+			"pg.blocked_count":  float64(waiting),
+			"pg.lock_wait_secs": 42,
+		},
+		Labels: map[string]string{
+			"pg.lock_wait_pid":      "200",
+			"pg.lock_wait_query":    "UPDATE orders SET status = 'paid' WHERE id = 1",
+			"pg.lock_wait_relation": "orders",
+		},
+	}
+
+	if withBlocker {
+		snapshot.Labels["pg.lock_wait_blocking_pids"] = "100"
+		snapshot.Labels["pg.blocking_pid"] = "100"
+		snapshot.Labels["pg.lock_blocker_user"] = "app"
+		snapshot.Labels["pg.lock_blocker_query"] = "UPDATE orders SET status = 'shipped'"
+		snapshot.Labels["pg.lock_blocker_state"] = "idle in transaction"
+	}
+
+	return snapshot
+}
+
+func TestLockContentionDetector_FiresAfterConsecutiveCycles(t *testing.T) {
+	det := detector.NewLockContentionDetector()
+
+	assert.Nil(t, det.Detect(lockContentionSnapshot(8, true)))
+	assert.Nil(t, det.Detect(lockContentionSnapshot(8, true)))
+	detection := det.Detect(lockContentionSnapshot(8, true))
+
+	require.NotNil(t, detection, "Detection should fire after 3 cycles above the threshold")
+	assert.Equal(t, "lock_contention", detection.DetectorName)
+	assert.Equal(t, models.CategoryConnection, detection.Category)
+	assert.Equal(t, []string{"100"}, detection.Evidence["blocking_pids"])
+	assert.Equal(t, "100", detection.Evidence["blocker_pid"])
+}
+
+func TestLockContentionDetector_LeavesTerminatingBlockerToBlockingLock(t *testing.T) {
+	det := detector.NewLockContentionDetector()
+	det.SetConsecutiveCycles(1)
+
+	detection := det.Detect(lockContentionSnapshot(8, true))
+
+	require.NotNil(t, detection)
+	assert.Equal(t, "deadlock_investigation_recommendation", detection.ActionType,
+		"only blocking_lock sends the blocker for termination")
+	assert.Contains(t, detection.Recommendation, "Session 100")
+	assert.NotContains(t, detection.ActionMetadata, "pid")
+}
+
+func TestLockContentionDetector_StreakResetsBelowThreshold(t *testing.T) {
+	det := detector.NewLockContentionDetector()
+	det.SetConsecutiveCycles(2)
+
+	det.Detect(lockContentionSnapshot(8, true))
+	det.Detect(lockContentionSnapshot(2, true))
+	detection := det.Detect(lockContentionSnapshot(8, true))
+
+	assert.Nil(t, detection, "A cycle below the threshold should reset the streak")
+}
+
+func TestLockContentionDetector_SeverityTiers(t *testing.T) {
+	tests := []struct {
+		name     string
+		waiting  int32
+		expected models.DetectionSeverity
+	}{
+		{"just over threshold", 6, models.SeverityInfo},
+		{"over twice threshold", 11, models.SeverityWarning},
+		{"over four times threshold", 21, models.SeverityCritical},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			det := detector.NewLockContentionDetector()
+			det.SetConsecutiveCycles(1)
+
+			detection := det.Detect(lockContentionSnapshot(tt.waiting, true))
+
+			require.NotNil(t, detection)
+			assert.Equal(t, tt.expected, detection.Severity)
+		})
+	}
+}
+
+func TestLockContentionDetector_RecommendsInvestigationWithoutBlocker(t *testing.T) {
+	det := detector.NewLockContentionDetector()
+	det.SetConsecutiveCycles(1)
+
+	detection := det.Detect(lockContentionSnapshot(8, false))
+
+	require.NotNil(t, detection)
+	assert.Equal(t, "deadlock_investigation_recommendation", detection.ActionType)
+	assert.NotContains(t, detection.ActionMetadata, "pid")
+}
+
+func TestLockContentionDetector_NoDetectionWithoutWaitingMetric(t *testing.T) {
+	det := detector.NewLockContentionDetector()
+	det.SetConsecutiveCycles(1)
+
+	snapshot := &normaliser.NormalisedMetrics{
+		DatabaseID:      "test-db",
+		DatabaseType:    "postgres",
+		Labels:          map[string]string{},
+		ExtendedMetrics: map[string]float64{},
+	}
+
+	assert.Nil(t, det.Detect(snapshot))
+}
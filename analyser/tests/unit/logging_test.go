@@ -0,0 +1,48 @@
+package unit
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLevel(t *testing.T) {
+	cases := map[string]slog.Level{
+		"":        slog.LevelInfo,
+		"info":    slog.LevelInfo,
+		"DEBUG":   slog.LevelDebug,
+		"warn":    slog.LevelWarn,
+		"warning": slog.LevelWarn,
+		"error":   slog.LevelError,
+	}
+
+	for value, want := range cases {
+		level, err := logging.ParseLevel(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, level, value)
+	}
+}
+
+func TestParseLevel_RejectsUnknown(t *testing.T) {
+	_, err := logging.ParseLevel("verbose")
+	assert.Error(t, err)
+}
+
+func TestCorrelationID_RoundTripsThroughContext(t *testing.T) {
+	ctx := logging.WithCorrelationID(context.Background(), "abc123")
+
+	assert.Equal(t, "abc123", logging.CorrelationID(ctx))
+	assert.Empty(t, logging.CorrelationID(context.Background()))
+}
+
+func TestNewCorrelationID_IsUnique(t *testing.T) {
+	a := logging.NewCorrelationID()
+	b := logging.NewCorrelationID()
+
+	assert.Len(t, a, 16)
+	assert.NotEqual(t, a, b)
+}
@@ -1,6 +1,7 @@
 package unit
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/detector"
@@ -139,6 +140,7 @@ func TestMissingIndexDetector_RecommendationContent(t *testing.T) {
 		Measurements: normaliser.Measurements{
 			SequentialScans: &seqScans,
 		},
+		DeltasReliable: true,
 		MetricDeltas: map[string]float64{
 			"sequential_scans": 500,
 		},
@@ -158,3 +160,204 @@ func TestMissingIndexDetector_RecommendationContent(t *testing.T) {
 	assert.Contains(t, detection.Recommendation, "Create an index on users.email")
 	assert.Contains(t, detection.Recommendation, "optimize query performance")
 }
+
+func TestMissingIndexDetector_MultiColumnRecommendation(t *testing.T) {
+	det := detector.NewMissingIndexDetector()
+
+	seqScans := int32(15)
+	snapshot := &normaliser.NormalisedMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "postgres",
+		Labels: map[string]string{
+			"pg.worst_seq_scan_table":      "orders",
+			"pg.recommended_index_column":  "customer_id",
+			"pg.recommended_index_columns": "customer_id,created_at",
+		},
+		ExtendedMetrics: map[string]float64{
+			"pg.table.orders.seq_scans":    15,
+			"pg.table.orders.seq_tup_read": 2000,
+		},
+		Measurements: normaliser.Measurements{
+			SequentialScans: &seqScans,
+		},
+	}
+
+	detection := det.Detect(snapshot)
+
+	require.NotNil(t, detection)
+	assert.Equal(t, []string{"customer_id", "created_at"}, detection.ActionMetadata["column_names"])
+	assert.Equal(t, "customer_id", detection.ActionMetadata["column_name"])
+	assert.Contains(t, detection.Recommendation, "orders (customer_id, created_at)")
+}
+
+func TestMissingIndexDetector_RecommendsPgStatStatementsWhenUnavailable(t *testing.T) {
+	det := detector.NewMissingIndexDetector()
+
+	seqScans := int32(15)
+	snapshot := &normaliser.NormalisedMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "postgres",
+		Labels: map[string]string{
+			"pg.worst_seq_scan_table": "users",
+			"pg.stat_statements":      "unavailable",
+		},
+		ExtendedMetrics: map[string]float64{
+			"pg.table.users.seq_scans":    15,
+			"pg.table.users.seq_tup_read": 2000,
+		},
+		Measurements: normaliser.Measurements{
+			SequentialScans: &seqScans,
+		},
+	}
+
+	detection := det.Detect(snapshot)
+
+	require.NotNil(t, detection)
+	assert.Equal(t, "enable_query_stats_recommendation", detection.ActionType)
+	assert.NotEqual(t, "create_index", detection.ActionType)
+	assert.Equal(t, models.SeverityInfo, detection.Severity)
+
+	safe, ok := detection.ActionMetadata["safe_option"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "safe", safe["risk_level"])
+
+	steps, ok := safe["steps"].([]string)
+	require.True(t, ok)
+	assert.Contains(t, steps, "Create the extension in the monitored database: CREATE EXTENSION IF NOT EXISTS pg_stat_statements;")
+}
+
+func TestMissingIndexDetector_NoDetectionWithoutColumnsWhenPgStatStatementsAvailable(t *testing.T) {
+	det := detector.NewMissingIndexDetector()
+
+	seqScans := int32(15)
+	snapshot := &normaliser.NormalisedMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "postgres",
+		Labels: map[string]string{
+			"pg.worst_seq_scan_table": "users",
+			"pg.stat_statements":      "available",
+		},
+		ExtendedMetrics: map[string]float64{
+			"pg.table.users.seq_scans":    15,
+			"pg.table.users.seq_tup_read": 2000,
+		},
+		Measurements: normaliser.Measurements{
+			SequentialScans: &seqScans,
+		},
+	}
+
+	assert.Nil(t, det.Detect(snapshot), "create_index must never be sent without a column")
+}
+
+// multiTableSnapshot has three sequentially scanned tables, each with recommended columns.
+func multiTableSnapshot() *normaliser.NormalisedMetrics {
+	seqScans := int32(900)
+	return &normaliser.NormalisedMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "postgres",
+		Labels: map[string]string{
+			"pg.worst_seq_scan_table":                     "orders",
+			"pg.seq_scan_candidate_tables":                "orders,users,invoices",
+			"pg.recommended_index_column":                 "customer_id",
+			"pg.recommended_index_columns":                "customer_id",
+			"pg.table.orders.recommended_index_columns":   "customer_id",
+			"pg.table.users.recommended_index_columns":    "email",
+			"pg.table.invoices.recommended_index_columns": "order_id,issued_at",
+		},
+		ExtendedMetrics: map[string]float64{
+			"pg.table.orders.seq_scans":   500,
+			"pg.table.users.seq_scans":    300,
+			"pg.table.invoices.seq_scans": 100,
+		},
+		DeltasReliable: true,
+		MetricDeltas: map[string]float64{
+			"sequential_scans":            90,
+			"pg.table.orders.seq_scans":   50,
+			"pg.table.users.seq_scans":    30,
+			"pg.table.invoices.seq_scans": 10,
+		},
+		Measurements: normaliser.Measurements{
+			SequentialScans: &seqScans,
+		},
+	}
+}
+
+func TestMissingIndexDetector_DetectsEveryCandidateTable(t *testing.T) {
+	det := detector.NewMissingIndexDetector()
+	snapshot := multiTableSnapshot()
+	snapshot.MetricDeltas["pg.table.invoices.seq_scans"] = 50
+
+	detections := det.DetectMany(snapshot)
+
+	require.Len(t, detections, 3)
+	assert.Equal(t, "orders", detections[0].ActionMetadata["table_name"])
+	assert.Equal(t, "users", detections[1].ActionMetadata["table_name"])
+	assert.Equal(t, []string{"email"}, detections[1].ActionMetadata["column_names"])
+	assert.Equal(t, "invoices", detections[2].ActionMetadata["table_name"])
+	assert.Equal(t, []string{"order_id", "issued_at"}, detections[2].ActionMetadata["column_names"])
+	assert.Equal(t, 50.0, detections[2].Evidence["sequential_scans_delta"])
+
+	// Detect still reports the worst table
+	detection := det.Detect(snapshot)
+	require.NotNil(t, detection)
+	assert.Equal(t, "orders", detection.ActionMetadata["table_name"])
+}
+
+func TestMissingIndexDetector_SkipsCandidatesBelowDeltaThreshold(t *testing.T) {
+	det := detector.NewMissingIndexDetector()
+
+	detections := det.DetectMany(multiTableSnapshot())
+
+	require.Len(t, detections, 2, "invoices gained only 10 scans this cycle")
+	for _, detection := range detections {
+		assert.NotEqual(t, "invoices", detection.ActionMetadata["table_name"])
+	}
+}
+
+func TestMissingIndexDetector_SingleQueryStatsRecommendationForCandidates(t *testing.T) {
+	det := detector.NewMissingIndexDetector()
+	snapshot := multiTableSnapshot()
+	for key := range snapshot.Labels {
+		if strings.HasSuffix(key, "recommended_index_columns") || strings.HasSuffix(key, "recommended_index_column") {
+			delete(snapshot.Labels, key)
+		}
+	}
+	snapshot.Labels["pg.stat_statements"] = "unavailable"
+
+	detections := det.DetectMany(snapshot)
+
+	require.Len(t, detections, 1)
+	assert.Equal(t, "enable_query_stats_recommendation", detections[0].ActionType)
+	assert.Equal(t, "orders", detections[0].ActionMetadata["table_name"])
+}
+
+func TestMissingIndexDetector_UnreliableDeltaFallsBackToCumulativeScans(t *testing.T) {
+	det := detector.NewMissingIndexDetector()
+
+	seqScans := int32(5000)
+	snapshot := &normaliser.NormalisedMetrics{
+		DatabaseID:   "testdb",
+		DatabaseType: "postgres",
+		Measurements: normaliser.Measurements{
+			SequentialScans: &seqScans,
+		},
+		// Two snapshots in the same second: the delta reads zero without meaning it
+		DeltasReliable: false,
+		MetricDeltas: map[string]float64{
+			"sequential_scans":         0,
+			"pg.table.users.seq_scans": 0,
+		},
+		Labels: map[string]string{
+			"pg.worst_seq_scan_table":     "users",
+			"pg.recommended_index_column": "email",
+		},
+		ExtendedMetrics: map[string]float64{
+			"pg.table.users.seq_scans": 5000,
+		},
+	}
+
+	detection := det.Detect(snapshot)
+
+	require.NotNil(t, detection, "An unreliable zero delta must not suppress the detection")
+	assert.NotContains(t, detection.Evidence, "sequential_scans_delta")
+}
@@ -0,0 +1,194 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/detector"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+	"github.com/stretchr/testify/assert"
+)
+
+func replicationSnapshot(lagSecs float64) *normaliser.NormalisedMetrics {
+	return &normaliser.NormalisedMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "postgres",
+		Labels: map[string]string{
+			"pg.worst_lag_replica":       "replica-1",
+			"pg.worst_lag_replica_state": "streaming",
+		},
+		ExtendedMetrics: map[string]float64{
+			"pg.replica_count":                  2,
+			"pg.max_replication_lag_secs":       lagSecs,
+			"pg.max_replication_write_lag_secs": 1.0,
+		},
+	}
+}
+
+func TestReplicationLagDetector_FiresWhenAboveThreshold(t *testing.T) {
+	det := detector.NewReplicationLagDetector()
+
+	detection := det.Detect(replicationSnapshot(15.0))
+
+	assert.NotNil(t, detection, "Detection should fire when lag > 10s")
+	assert.Equal(t, "replication_lag", detection.DetectorName)
+	assert.Equal(t, models.CategoryReplication, detection.Category)
+	assert.Equal(t, models.SeverityInfo, detection.Severity)
+	assert.Equal(t, "replica-1", detection.Evidence["application_name"])
+}
+
+func TestReplicationLagDetector_SeverityEscalates(t *testing.T) {
+	det := detector.NewReplicationLagDetector()
+
+	assert.Equal(t, models.SeverityWarning, det.Detect(replicationSnapshot(45.0)).Severity)
+	assert.Equal(t, models.SeverityCritical, det.Detect(replicationSnapshot(180.0)).Severity)
+}
+
+func TestReplicationLagDetector_CustomThresholds(t *testing.T) {
+	det := detector.NewReplicationLagDetector()
+	det.SetThreshold(60.0)
+	det.SetSeverityThresholds(90.0, 300.0)
+
+	assert.Nil(t, det.Detect(replicationSnapshot(45.0)))
+	assert.Equal(t, models.SeverityWarning, det.Detect(replicationSnapshot(120.0)).Severity)
+}
+
+func TestReplicationLagDetector_NoDetectionWhenBelowThreshold(t *testing.T) {
+	det := detector.NewReplicationLagDetector()
+
+	detection := det.Detect(replicationSnapshot(2.0))
+
+	assert.Nil(t, detection, "Detection should not fire when lag < 10s")
+}
+
+func TestReplicationLagDetector_NoDetectionWhenDataMissing(t *testing.T) {
+	det := detector.NewReplicationLagDetector()
+
+	snapshot := &normaliser.NormalisedMetrics{
+		DatabaseID:      "test-db",
+		DatabaseType:    "postgres",
+		Labels:          map[string]string{},
+		ExtendedMetrics: map[string]float64{},
+	}
+
+	detection := det.Detect(snapshot)
+
+	assert.Nil(t, detection, "Detection should not fire without replication metrics")
+}
+
+func TestReplicationLagDetector_IsRecommendation(t *testing.T) {
+	det := detector.NewReplicationLagDetector()
+
+	detection := det.Detect(replicationSnapshot(45.0))
+
+	assert.NotNil(t, detection)
+	assert.Equal(t, "replication_lag_recommendation", detection.ActionType)
+	assert.Equal(t, "warning", detection.ActionMetadata["priority"])
+	assert.Contains(t, detection.Recommendation, "long-running transactions")
+	assert.Contains(t, detection.Recommendation, "network")
+
+	safeOption, ok := detection.ActionMetadata["safe_option"].(map[string]interface{})
+	assert.True(t, ok, "Recommendation should carry a safe option")
+	assert.Equal(t, "safe", safeOption["risk_level"])
+}
+
+func TestReplicationLagDetector_FiresOnBytesWithoutTimeLag(t *testing.T) {
+	det := detector.NewReplicationLagDetector()
+
+	// replay_lag is not reported once the primary goes quiet
+	snapshot := replicationSnapshot(0)
+	snapshot.ExtendedMetrics["pg.max_replication_lag_bytes"] = 300 * 1024 * 1024
+
+	detection := det.Detect(snapshot)
+
+	assert.NotNil(t, detection, "Detection should fire when WAL lag > 256MB")
+	assert.Equal(t, models.SeverityInfo, detection.Severity)
+	assert.Equal(t, float64(300*1024*1024), detection.Value)
+	assert.Equal(t, float64(300*1024*1024), detection.Evidence["replay_lag_bytes"])
+}
+
+func TestReplicationLagDetector_ByteSeverityEscalates(t *testing.T) {
+	det := detector.NewReplicationLagDetector()
+	det.SetByteThreshold(100)
+
+	cases := []struct {
+		lagSecs  float64
+		lagBytes float64
+		want     models.DetectionSeverity
+	}{
+		{0, 150, models.SeverityInfo},
+		{0, 200, models.SeverityWarning},
+		{0, 400, models.SeverityCritical},
+		{45, 150, models.SeverityWarning},   // seconds tier is higher
+		{15, 400, models.SeverityCritical},  // bytes tier is higher
+		{180, 100, models.SeverityCritical}, // both fire
+	}
+
+	for _, tc := range cases {
+		snapshot := replicationSnapshot(tc.lagSecs)
+		snapshot.ExtendedMetrics["pg.max_replication_lag_bytes"] = tc.lagBytes
+
+		detection := det.Detect(snapshot)
+		if assert.NotNil(t, detection, "%.0fs / %.0f bytes", tc.lagSecs, tc.lagBytes) {
+			assert.Equal(t, tc.want, detection.Severity, "%.0fs / %.0f bytes", tc.lagSecs, tc.lagBytes)
+		}
+	}
+}
+
+func TestReplicationLagDetector_ByteThresholdDisabled(t *testing.T) {
+	det := detector.NewReplicationLagDetector()
+	det.SetByteThreshold(0)
+
+	snapshot := replicationSnapshot(0)
+	snapshot.ExtendedMetrics["pg.max_replication_lag_bytes"] = 10 * 1024 * 1024 * 1024
+
+	assert.Nil(t, det.Detect(snapshot), "Only lag in seconds is checked with the byte threshold disabled")
+}
+
+func TestReplicationLagDetector_NoDetectionWithoutReplica(t *testing.T) {
+	det := detector.NewReplicationLagDetector()
+
+	snapshot := replicationSnapshot(200)
+	snapshot.ExtendedMetrics["pg.max_replication_lag_bytes"] = 10 * 1024 * 1024 * 1024
+	delete(snapshot.Labels, "pg.worst_lag_replica")
+
+	assert.Nil(t, det.Detect(snapshot), "Detection should not fire without a replica to name")
+}
+
+func TestReplicationLagDetector_BytesOnlyNamesReplicaWithBacklog(t *testing.T) {
+	det := detector.NewReplicationLagDetector()
+
+	// replica-1 is furthest behind in time, but replica-2 holds the WAL backlog
+	snapshot := replicationSnapshot(2.0)
+	snapshot.Labels["pg.worst_lag_replica_addr"] = "10.0.0.5"
+	snapshot.Labels["pg.worst_bytes_lag_replica"] = "replica-2"
+	snapshot.Labels["pg.worst_bytes_lag_replica_state"] = "catchup"
+	snapshot.Labels["pg.worst_bytes_lag_replica_addr"] = "10.0.0.6"
+	snapshot.ExtendedMetrics["pg.max_replication_lag_bytes"] = 300 * 1024 * 1024
+	snapshot.ExtendedMetrics["pg.replica.replica-2.replay_lag_secs"] = 0.5
+
+	detection := det.Detect(snapshot)
+
+	assert.NotNil(t, detection)
+	assert.Equal(t, "replica-2", detection.Evidence["application_name"])
+	assert.Equal(t, "10.0.0.6", detection.Evidence["client_addr"])
+	assert.Equal(t, "catchup", detection.Evidence["state"])
+	assert.Equal(t, 0.5, detection.Evidence["replay_lag_secs"])
+	assert.Contains(t, detection.Title, "replica-2")
+	assert.Contains(t, detection.Description, "replica-2")
+	assert.Contains(t, detection.Recommendation, "replica-2")
+	assert.Equal(t, "replica-2", detection.ActionMetadata["application_name"])
+}
+
+func TestReplicationLagDetector_TimeLagNamesReplicaFurthestBehindInTime(t *testing.T) {
+	det := detector.NewReplicationLagDetector()
+
+	snapshot := replicationSnapshot(45.0)
+	snapshot.Labels["pg.worst_bytes_lag_replica"] = "replica-2"
+	snapshot.ExtendedMetrics["pg.max_replication_lag_bytes"] = 300 * 1024 * 1024
+
+	detection := det.Detect(snapshot)
+
+	assert.NotNil(t, detection)
+	assert.Equal(t, "replica-1", detection.Evidence["application_name"])
+}
@@ -0,0 +1,174 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/config"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/detector"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/thresholds"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const gib = 1024 * 1024 * 1024
+
+// storageGrowthSnapshot describes a 100GiB disk with usedGiB in use, grown by growthGiB over
+// the last hour.
+func storageGrowthSnapshot(usedGiB, growthGiB float64) *normaliser.NormalisedMetrics {
+	total := int64(100 * gib)
+	used := int64(usedGiB * gib)
+	return &normaliser.NormalisedMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "postgres",
+		Timestamp:    time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		Measurements: normaliser.Measurements{
+			UsedStorageBytes:  &used,
+			TotalStorageBytes: &total,
+		},
+		MetricDeltas:     map[string]float64{"used_storage_bytes": growthGiB * gib},
+		TimeDeltaSeconds: 3600,
+		DeltasReliable:   true,
+		ExtendedMetrics:  map[string]float64{},
+		Labels:           map[string]string{},
+	}
+}
+
+// warmUp feeds the detector enough growth samples to start projecting.
+func warmUp(det detector.Detector, usedGiB, growthGiB float64) {
+	for range 4 {
+		det.Detect(storageGrowthSnapshot(usedGiB, growthGiB))
+	}
+}
+
+func TestStorageGrowthDetector_FiresWhenUsageAboveThreshold(t *testing.T) {
+	det := detector.NewStorageGrowthDetector()
+
+	detection := det.Detect(storageGrowthSnapshot(88, 0))
+
+	require.NotNil(t, detection, "Detection should fire when the disk is > 85% full")
+	assert.Equal(t, "storage_growth", detection.DetectorName)
+	assert.Equal(t, models.CategoryStorage, detection.Category)
+	assert.Equal(t, models.SeverityWarning, detection.Severity)
+	assert.Equal(t, "storage_growth_recommendation", detection.ActionType)
+	assert.InDelta(t, 0.88, detection.Value, 0.001)
+	assert.NotContains(t, detection.Evidence, "projected_full_at", "No projection before enough samples")
+}
+
+func TestStorageGrowthDetector_NoDetectionWhenBelowThreshold(t *testing.T) {
+	det := detector.NewStorageGrowthDetector()
+
+	for range 10 {
+		assert.Nil(t, det.Detect(storageGrowthSnapshot(50, 0.1)), "50% full, full in ~500h")
+	}
+}
+
+func TestStorageGrowthDetector_NoDetectionWithoutDiskSize(t *testing.T) {
+	det := detector.NewStorageGrowthDetector()
+
+	snapshot := storageGrowthSnapshot(99, 5)
+	snapshot.Measurements.TotalStorageBytes = nil
+
+	assert.Nil(t, det.Detect(snapshot), "Usage can't be judged without the disk's size")
+}
+
+func TestStorageGrowthDetector_ProjectsTimeToFull(t *testing.T) {
+	det := detector.NewStorageGrowthDetector()
+	warmUp(det, 60, 1)
+
+	// 40GiB free at 1GiB/hour
+	detection := det.Detect(storageGrowthSnapshot(60, 1))
+
+	require.NotNil(t, detection, "Detection should fire when the disk is projected full within 72h")
+	assert.Equal(t, models.SeverityWarning, detection.Severity)
+	assert.InDelta(t, 40.0, detection.Evidence["hours_to_full"], 0.01)
+	assert.InDelta(t, float64(gib), detection.Evidence["growth_bytes_per_hour"], 1)
+	assert.Equal(t, "2026-01-02T16:00:00Z", detection.Evidence["projected_full_at"])
+}
+
+func TestStorageGrowthDetector_Severity(t *testing.T) {
+	cases := []struct {
+		usedGiB, growthGiB float64
+		want               models.DetectionSeverity
+	}{
+		{86, 0, models.SeverityWarning},
+		{93, 0, models.SeverityCritical},
+		{40, 1, models.SeverityWarning},  // full in 60h
+		{80, 1, models.SeverityCritical}, // full in 20h, under a third of the window
+	}
+
+	for _, tc := range cases {
+		det := detector.NewStorageGrowthDetector()
+		warmUp(det, tc.usedGiB, tc.growthGiB)
+
+		detection := det.Detect(storageGrowthSnapshot(tc.usedGiB, tc.growthGiB))
+		require.NotNil(t, detection, "%.0fGiB used", tc.usedGiB)
+		assert.Equal(t, tc.want, detection.Severity, "%.0fGiB used", tc.usedGiB)
+	}
+}
+
+func TestStorageGrowthDetector_GrowthIsSmoothed(t *testing.T) {
+	det := detector.NewStorageGrowthDetector()
+	warmUp(det, 30, 0)
+
+	// A one-off 5GiB bulk load is averaged down to 1GiB/hour: 70h to full, not 14h
+	detection := det.Detect(storageGrowthSnapshot(30, 5))
+
+	require.NotNil(t, detection)
+	assert.Equal(t, models.SeverityWarning, detection.Severity)
+	assert.InDelta(t, 70.0, detection.Evidence["hours_to_full"], 0.01)
+}
+
+func TestStorageGrowthDetector_UnreliableDeltasIgnored(t *testing.T) {
+	det := detector.NewStorageGrowthDetector()
+
+	for range 10 {
+		snapshot := storageGrowthSnapshot(60, 1)
+		snapshot.DeltasReliable = false
+		assert.Nil(t, det.Detect(snapshot))
+	}
+}
+
+func TestStorageGrowthDetector_FreeSpaceWinsOverDatabaseSize(t *testing.T) {
+	det := detector.NewStorageGrowthDetector()
+
+	// The database is small, but other files fill the disk
+	snapshot := storageGrowthSnapshot(10, 0)
+	free := int64(5 * gib)
+	snapshot.Measurements.FreeStorageBytes = &free
+
+	detection := det.Detect(snapshot)
+
+	require.NotNil(t, detection)
+	assert.InDelta(t, 0.95, detection.Value, 0.001)
+	assert.Equal(t, float64(10*gib), detection.Evidence["database_bytes"])
+}
+
+func TestStorageGrowthDetector_CloneHasNoHistory(t *testing.T) {
+	det := detector.NewStorageGrowthDetector()
+	warmUp(det, 60, 1)
+
+	clone := det.Clone()
+
+	assert.NotNil(t, det.Detect(storageGrowthSnapshot(60, 1)))
+	assert.Nil(t, clone.Detect(storageGrowthSnapshot(60, 1)), "A clone needs its own samples before projecting")
+}
+
+func TestStorageGrowthDetector_ThresholdsFromConfig(t *testing.T) {
+	t.Setenv("THRESHOLD_STORAGE_USAGE", "0.5")
+	t.Setenv("THRESHOLD_STORAGE_TIME_TO_FULL_HOURS", "0")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, cfg.Thresholds.Values()["storage_usage"])
+	assert.Equal(t, 0.0, cfg.Thresholds.Values()["storage_time_to_full_hours"])
+
+	det := detector.NewStorageGrowthDetector()
+	thresholds.Apply(det, cfg.Thresholds)
+	warmUp(det, 45, 5)
+
+	assert.Nil(t, det.Detect(storageGrowthSnapshot(45, 5)), "The time-to-full check is disabled")
+	assert.NotNil(t, det.Detect(storageGrowthSnapshot(55, 5)))
+}
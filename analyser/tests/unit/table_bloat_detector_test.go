@@ -32,6 +32,7 @@ func TestTableBloatDetector_FiresWhenAboveThreshold(t *testing.T) {
 	assert.Equal(t, "table_bloat", detection.DetectorName)
 	assert.Equal(t, models.CategoryStorage, detection.Category)
 	assert.Equal(t, "vacuum_table", detection.ActionType)
+	assert.Equal(t, 0.15, detection.Value, "Value should carry the bloat ratio")
 }
 
 func TestTableBloatDetector_NoDetectionWhenBelowThreshold(t *testing.T) {
@@ -186,3 +187,78 @@ func TestTableBloatDetector_ActionMetadata(t *testing.T) {
 	assert.Equal(t, "posts", detection.ActionMetadata["table_name"])
 	assert.Contains(t, detection.Recommendation, "VACUUM ANALYZE")
 }
+
+func bloatSnapshot(ratio, sizeBytes float64) *normaliser.NormalisedMetrics {
+	return &normaliser.NormalisedMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "postgres",
+		Labels: map[string]string{
+			"pg.worst_bloat_table": "posts",
+		},
+		ExtendedMetrics: map[string]float64{
+			"pg.worst_bloat_ratio":       ratio,
+			"pg.table.posts.live_tuples": 100000,
+			"pg.table.posts.dead_tuples": 100000 * ratio,
+			"pg.table.posts.bloat_ratio": ratio,
+			"pg.table.posts.size_bytes":  sizeBytes,
+		},
+	}
+}
+
+func TestTableBloatDetector_ChoosesVacuumMode(t *testing.T) {
+	const mb = 1024 * 1024
+
+	tests := []struct {
+		name      string
+		ratio     float64
+		sizeBytes float64
+		mode      string
+	}{
+		{"moderate bloat on a small table", 0.2, 50 * mb, detector.VacuumModeAnalyze},
+		{"heavy bloat on a small table", 0.6, 50 * mb, detector.VacuumModeFull},
+		{"heavy bloat on a large table", 0.6, 5000 * mb, detector.VacuumModeTuneAutovacuum},
+		{"moderate bloat on a large table", 0.2, 5000 * mb, detector.VacuumModeTuneAutovacuum},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			det := detector.NewTableBloatDetector()
+
+			detection := det.Detect(bloatSnapshot(tt.ratio, tt.sizeBytes))
+
+			assert.NotNil(t, detection)
+			assert.Equal(t, tt.mode, detection.ActionMetadata["mode"])
+			assert.Equal(t, tt.mode, detection.Evidence["vacuum_mode"])
+		})
+	}
+}
+
+func TestTableBloatDetector_UnknownSizeUsesAnalyze(t *testing.T) {
+	det := detector.NewTableBloatDetector()
+	snapshot := bloatSnapshot(0.9, 0)
+	delete(snapshot.ExtendedMetrics, "pg.table.posts.size_bytes")
+
+	detection := det.Detect(snapshot)
+
+	assert.NotNil(t, detection)
+	assert.Equal(t, detector.VacuumModeAnalyze, detection.ActionMetadata["mode"])
+	assert.NotContains(t, detection.Evidence, "size_bytes")
+}
+
+func TestTableBloatDetector_ModeThresholdsConfigurable(t *testing.T) {
+	det := detector.NewTableBloatDetector()
+	det.SetModeThresholds(0.3, 100*1024*1024, 10*1024*1024*1024)
+	det.SetAutovacuumScaleFactor(0.01)
+
+	full := det.Detect(bloatSnapshot(0.35, 80*1024*1024))
+	assert.Equal(t, detector.VacuumModeFull, full.ActionMetadata["mode"])
+	assert.Contains(t, full.Recommendation, "ACCESS EXCLUSIVE")
+
+	// Too big to rewrite, too small to tune
+	analyze := det.Detect(bloatSnapshot(0.35, 500*1024*1024))
+	assert.Equal(t, detector.VacuumModeAnalyze, analyze.ActionMetadata["mode"])
+
+	tune := det.Detect(bloatSnapshot(0.35, 20*1024*1024*1024))
+	assert.Equal(t, detector.VacuumModeTuneAutovacuum, tune.ActionMetadata["mode"])
+	assert.Equal(t, 0.01, tune.ActionMetadata["autovacuum_vacuum_scale_factor"])
+}
@@ -0,0 +1,114 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/config"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/detector"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/thresholds"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mib = 1024 * 1024
+
+func tempSpillSnapshot(tempFiles, tempMB float64) *normaliser.NormalisedMetrics {
+	return &normaliser.NormalisedMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "postgres",
+		MetricDeltas: map[string]float64{
+			"temp_files": tempFiles,
+			"temp_bytes": tempMB * mib,
+		},
+		TimeDeltaSeconds: 30,
+		DeltasReliable:   true,
+		ExtendedMetrics:  map[string]float64{},
+		Labels:           map[string]string{},
+	}
+}
+
+func TestTempFileSpillDetector_FiresWhenAboveThreshold(t *testing.T) {
+	det := detector.NewTempFileSpillDetector()
+
+	detection := det.Detect(tempSpillSnapshot(6, 150))
+
+	require.NotNil(t, detection, "Detection should fire when > 100MB spills in an interval")
+	assert.Equal(t, "temp_file_spill", detection.DetectorName)
+	assert.Equal(t, models.CategoryQuery, detection.Category)
+	assert.Equal(t, models.SeverityInfo, detection.Severity)
+	assert.Equal(t, float64(150*mib), detection.Value)
+}
+
+func TestTempFileSpillDetector_NoDetectionWhenBelowThreshold(t *testing.T) {
+	det := detector.NewTempFileSpillDetector()
+
+	assert.Nil(t, det.Detect(tempSpillSnapshot(3, 40)), "Detection should not fire when < 100MB spills")
+}
+
+func TestTempFileSpillDetector_NoDetectionWhenDataMissing(t *testing.T) {
+	det := detector.NewTempFileSpillDetector()
+
+	snapshot := tempSpillSnapshot(6, 500)
+	snapshot.MetricDeltas = map[string]float64{}
+
+	assert.Nil(t, det.Detect(snapshot), "Detection should not fire without temp file deltas")
+}
+
+func TestTempFileSpillDetector_UnreliableDeltasIgnored(t *testing.T) {
+	det := detector.NewTempFileSpillDetector()
+
+	snapshot := tempSpillSnapshot(6, 500)
+	snapshot.DeltasReliable = false
+
+	assert.Nil(t, det.Detect(snapshot), "A stats reset makes the whole counter look like this interval's spill")
+}
+
+func TestTempFileSpillDetector_Severity(t *testing.T) {
+	det := detector.NewTempFileSpillDetector()
+
+	cases := []struct {
+		tempMB float64
+		want   models.DetectionSeverity
+	}{
+		{100, models.SeverityInfo},
+		{199, models.SeverityInfo},
+		{200, models.SeverityWarning},
+		{399, models.SeverityWarning},
+		{400, models.SeverityCritical},
+		{5000, models.SeverityCritical},
+	}
+
+	for _, tc := range cases {
+		detection := det.Detect(tempSpillSnapshot(10, tc.tempMB))
+		require.NotNil(t, detection, "%.0fMB", tc.tempMB)
+		assert.Equal(t, tc.want, detection.Severity, "%.0fMB", tc.tempMB)
+	}
+}
+
+func TestTempFileSpillDetector_ActionMetadataCarriesSpill(t *testing.T) {
+	det := detector.NewTempFileSpillDetector()
+
+	detection := det.Detect(tempSpillSnapshot(8, 256))
+
+	require.NotNil(t, detection)
+	assert.Equal(t, "tune_config_high_latency", detection.ActionType)
+	assert.Equal(t, 8.0, detection.ActionMetadata["temp_files"])
+	assert.Equal(t, float64(256*mib), detection.ActionMetadata["temp_bytes"])
+	assert.Equal(t, float64(32*mib), detection.ActionMetadata["avg_spill_bytes"])
+	assert.Equal(t, "report_slow_queries", detection.ActionMetadata["fallback_action_type"])
+}
+
+func TestTempFileSpillDetector_ThresholdFromConfig(t *testing.T) {
+	t.Setenv("THRESHOLD_TEMP_SPILL_MB", "10")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 10.0, cfg.Thresholds.Values()["temp_spill_mb"])
+
+	det := detector.NewTempFileSpillDetector()
+	thresholds.Apply(det, cfg.Thresholds)
+
+	assert.NotNil(t, det.Detect(tempSpillSnapshot(2, 20)), "20MB is above a lowered threshold")
+}
@@ -0,0 +1,119 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/config"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/detector"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/engine"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/thresholds"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func baseThresholds() config.DetectionThresholds {
+	return config.DetectionThresholds{
+		SequentialScanThreshold:      1,
+		SequentialScanDeltaThreshold: 10,
+		CacheHitRateThreshold:        0.8,
+		ReplicationLagThresholdSecs:  10,
+		ReplicationLagWarningSecs:    30,
+		ReplicationLagCriticalSecs:   300,
+	}
+}
+
+func seqScanSnapshot(databaseID string, delta float64) *normaliser.NormalisedMetrics {
+	seqScans := int32(5000)
+	return &normaliser.NormalisedMetrics{
+		DatabaseID:   databaseID,
+		DatabaseType: "postgres",
+		Labels: map[string]string{
+			"pg.worst_seq_scan_table":     "orders",
+			"pg.recommended_index_column": "customer_id",
+		},
+		ExtendedMetrics: map[string]float64{
+			"pg.table.orders.seq_scans":    5000,
+			"pg.table.orders.seq_tup_read": 500000,
+		},
+		Measurements: normaliser.Measurements{
+			SequentialScans: &seqScans,
+		},
+		DeltasReliable: true,
+		MetricDeltas: map[string]float64{
+			"sequential_scans": delta,
+		},
+	}
+}
+
+func TestThresholdStore_LayersGlobalAndDatabaseOverrides(t *testing.T) {
+	store := thresholds.NewStore(baseThresholds())
+
+	changed := store.Update([]*pb.ThresholdSet{
+		{Values: map[string]float64{"sequential_scan_delta": 100, "p95_latency_ms": 250}},
+		{DatabaseId: "replica", Values: map[string]float64{"sequential_scan_delta": 5000}},
+	})
+	assert.True(t, changed)
+
+	primary := store.For("primary")
+	assert.Equal(t, 100.0, primary.SequentialScanDeltaThreshold, "Global override applies to every database")
+	assert.Equal(t, 250.0, primary.P95LatencyThresholdMs)
+
+	replica := store.For("replica")
+	assert.Equal(t, 5000.0, replica.SequentialScanDeltaThreshold, "Database override wins over the global one")
+	assert.Equal(t, 250.0, replica.P95LatencyThresholdMs, "Names the database doesn't override fall back to global")
+	assert.Equal(t, 0.8, replica.CacheHitRateThreshold, "Names nobody overrides keep the configured value")
+}
+
+func TestThresholdStore_UpdateReportsChanges(t *testing.T) {
+	store := thresholds.NewStore(baseThresholds())
+
+	assert.False(t, store.Update(nil), "No overrides is the starting state")
+
+	sets := []*pb.ThresholdSet{{DatabaseId: "replica", Values: map[string]float64{"table_bloat": 0.3}}}
+	assert.True(t, store.Update(sets))
+	assert.False(t, store.Update(sets), "Same overrides again should not trigger a reconfigure")
+	assert.True(t, store.Update(nil), "Clearing overrides is a change")
+	assert.Equal(t, 0.0, store.For("replica").TableBloatThreshold)
+}
+
+func TestThresholdStore_IgnoresInvalidAndUnknownOverrides(t *testing.T) {
+	store := thresholds.NewStore(baseThresholds())
+
+	store.Update([]*pb.ThresholdSet{
+		{DatabaseId: "broken", Values: map[string]float64{"cache_hit_rate_threshold": 2, "table_bloat": 0.5}},
+		{DatabaseId: "typo", Values: map[string]float64{"sequental_scan_delta": 1, "table_bloat": 0.4}},
+	})
+
+	broken := store.For("broken")
+	assert.Equal(t, 0.8, broken.CacheHitRateThreshold, "An invalid set is ignored as a whole")
+	assert.Equal(t, 0.0, broken.TableBloatThreshold)
+
+	typo := store.For("typo")
+	assert.Equal(t, 10.0, typo.SequentialScanDeltaThreshold, "Unknown names are ignored")
+	assert.Equal(t, 0.4, typo.TableBloatThreshold, "Known names in the same set still apply")
+}
+
+func TestEngine_ConfiguratorAppliesPerDatabaseThresholds(t *testing.T) {
+	store := thresholds.NewStore(baseThresholds())
+	store.Update([]*pb.ThresholdSet{
+		{DatabaseId: "replica", Values: map[string]float64{"sequential_scan_delta": 5000}},
+	})
+
+	eng := engine.NewEngine()
+	eng.SetConfigurator(func(databaseID string, d detector.Detector) {
+		thresholds.Apply(d, store.For(databaseID))
+	})
+	eng.RegisterDetectorFactory(func() detector.Detector { return detector.NewMissingIndexDetector() })
+
+	assert.Len(t, eng.RunDetectors(seqScanSnapshot("primary", 500)), 1, "Primary uses the configured delta threshold")
+	assert.Empty(t, eng.RunDetectors(seqScanSnapshot("replica", 500)), "Replica tolerates more sequential scans")
+
+	// Lowering the replica's threshold takes effect without recreating its detectors
+	store.Update([]*pb.ThresholdSet{
+		{DatabaseId: "replica", Values: map[string]float64{"sequential_scan_delta": 100}},
+	})
+	eng.Reconfigure()
+
+	assert.Len(t, eng.RunDetectors(seqScanSnapshot("replica", 500)), 1)
+}
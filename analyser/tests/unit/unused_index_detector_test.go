@@ -0,0 +1,92 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/detector"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+	"github.com/stretchr/testify/assert"
+)
+
+func unusedIndexSnapshot(sizeBytes, scanDelta float64) *normaliser.NormalisedMetrics {
+	return &normaliser.NormalisedMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "postgres",
+		Timestamp:    time.Now().Unix(),
+		Labels: map[string]string{
+			"pg.index.idx_orders_status.table": "orders",
+		},
+		ExtendedMetrics: map[string]float64{
+			"pg.index.idx_orders_status.idx_scans":  42,
+			"pg.index.idx_orders_status.size_bytes": sizeBytes,
+		},
+		DeltasReliable: true,
+		MetricDeltas: map[string]float64{
+			"pg.index.idx_orders_status.idx_scans": scanDelta,
+		},
+	}
+}
+
+func TestUnusedIndexDetector_FiresAfterConsecutiveIdleCycles(t *testing.T) {
+	det := detector.NewUnusedIndexDetector()
+	det.SetUnusedCycles(3)
+
+	assert.Nil(t, det.Detect(unusedIndexSnapshot(50*1024*1024, 0)))
+	assert.Nil(t, det.Detect(unusedIndexSnapshot(50*1024*1024, 0)))
+	detection := det.Detect(unusedIndexSnapshot(50*1024*1024, 0))
+
+	assert.NotNil(t, detection, "Detection should fire after 3 cycles without scans")
+	assert.Equal(t, "unused_index", detection.DetectorName)
+	assert.Equal(t, models.CategoryStorage, detection.Category)
+	assert.Equal(t, models.SeverityInfo, detection.Severity)
+	assert.Equal(t, "drop_index", detection.ActionType)
+	assert.Equal(t, "idx_orders_status", detection.ActionMetadata["index_name"])
+	assert.Equal(t, "orders", detection.ActionMetadata["table_name"])
+	assert.Equal(t, int64(50*1024*1024), detection.ActionMetadata["index_size_bytes"])
+}
+
+func TestUnusedIndexDetector_ScanResetsStreak(t *testing.T) {
+	det := detector.NewUnusedIndexDetector()
+	det.SetUnusedCycles(2)
+
+	det.Detect(unusedIndexSnapshot(50*1024*1024, 0))
+	det.Detect(unusedIndexSnapshot(50*1024*1024, 5))
+	detection := det.Detect(unusedIndexSnapshot(50*1024*1024, 0))
+
+	assert.Nil(t, detection, "A cycle with index scans should reset the idle streak")
+}
+
+func TestUnusedIndexDetector_IgnoresSmallIndexes(t *testing.T) {
+	det := detector.NewUnusedIndexDetector()
+	det.SetUnusedCycles(1)
+	det.SetThreshold(100 * 1024 * 1024)
+
+	detection := det.Detect(unusedIndexSnapshot(50*1024*1024, 0))
+
+	assert.Nil(t, detection, "Indexes below the size threshold should not be reported")
+}
+
+func TestUnusedIndexDetector_NoDeltaOnFirstCycle(t *testing.T) {
+	det := detector.NewUnusedIndexDetector()
+	det.SetUnusedCycles(1)
+
+	snapshot := unusedIndexSnapshot(50*1024*1024, 0)
+	snapshot.MetricDeltas = map[string]float64{}
+
+	assert.Nil(t, det.Detect(snapshot), "Without a delta the index cannot be judged unused")
+}
+
+func TestUnusedIndexDetector_UnreliableCycleLeavesStreakAlone(t *testing.T) {
+	det := detector.NewUnusedIndexDetector()
+	det.SetUnusedCycles(2)
+
+	det.Detect(unusedIndexSnapshot(50*1024*1024, 0))
+
+	reset := unusedIndexSnapshot(50*1024*1024, 0)
+	reset.DeltasReliable = false
+	assert.Nil(t, det.Detect(reset), "A cycle after a counter reset says nothing about use")
+
+	assert.NotNil(t, det.Detect(unusedIndexSnapshot(50*1024*1024, 0)), "The streak carries over the unreliable cycle")
+}
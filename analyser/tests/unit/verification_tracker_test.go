@@ -4,7 +4,9 @@ import (
 	"testing"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/analyser/internal/verification"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewTracker(t *testing.T) {
@@ -163,6 +165,29 @@ func TestOnCollectionCycle_MultipleVerifications(t *testing.T) {
 	assert.Equal(t, 0, tracker.GetPendingCount())
 }
 
+func TestOnDatabaseCycle_OnlyAdvancesThatDatabase(t *testing.T) {
+	var verified []string
+	tracker := verification.NewTracker(2, nil, func(detectionID string) {
+		verified = append(verified, detectionID)
+	})
+
+	tracker.AddPendingVerification("key1", "det-1", "action-1", "create_index", "db1")
+	tracker.AddPendingVerification("key2", "det-2", "action-2", "create_index", "db2")
+
+	// db1 reports twice before db2 reports at all
+	tracker.OnDatabaseCycle("db1")
+	tracker.OnDatabaseCycle("db1")
+
+	assert.Equal(t, []string{"det-1"}, verified, "Only db1's action should be verified")
+	assert.True(t, tracker.IsPendingVerification("key2"), "db2's verification should not advance on db1 snapshots")
+
+	tracker.OnDatabaseCycle("db2")
+	tracker.OnDatabaseCycle("db2")
+
+	assert.Equal(t, []string{"det-1", "det-2"}, verified)
+	assert.Equal(t, 0, tracker.GetPendingCount())
+}
+
 func TestGetPendingVerifications(t *testing.T) {
 	tracker := verification.NewTracker(3, nil, nil)
 
@@ -217,3 +242,88 @@ func TestOverwriteExistingVerification(t *testing.T) {
 	assert.Equal(t, "action-2", pending[0].ActionID, "Should have new action ID")
 	assert.Equal(t, 0, pending[0].CyclesElapsed, "Cycles should reset")
 }
+
+func TestEffectivenessReportedWhenVerified(t *testing.T) {
+	var report *verification.EffectivenessReport
+	tracker := verification.NewTracker(2, nil, nil)
+	tracker.SetEffectivenessHandler(func(r *verification.EffectivenessReport) { report = r })
+
+	latest := seqScanSnapshot("testdb", 1200)
+	tracker.SetSnapshotSource(func(databaseID string) *normaliser.NormalisedMetrics {
+		assert.Equal(t, "testdb", databaseID)
+		return latest
+	})
+
+	key := "testdb:missing_index:users.email"
+	tracker.RecordBaseline(key, "create_index", latest)
+	tracker.AddPendingVerification(key, "detection-123", "action-456", "create_index", "testdb")
+
+	latest = seqScanSnapshot("testdb", 3)
+	tracker.OnDatabaseCycle("testdb")
+	tracker.OnDatabaseCycle("testdb")
+
+	require.NotNil(t, report, "Should report effectiveness on verification")
+	assert.Equal(t, "action-456", report.ActionID)
+	assert.Equal(t, "detection-123", report.DetectionID)
+	assert.Equal(t, "sequential_scans_per_interval", report.Metric)
+	assert.Equal(t, 1200.0, report.MetricBefore)
+	assert.Equal(t, 3.0, report.MetricAfter)
+	assert.Equal(t, verification.VerdictEffective, report.Verdict)
+}
+
+func TestEffectivenessReportedWhenIssueReturns(t *testing.T) {
+	var report *verification.EffectivenessReport
+	tracker := verification.NewTracker(3, nil, nil)
+	tracker.SetEffectivenessHandler(func(r *verification.EffectivenessReport) { report = r })
+
+	hitRate := 0.6
+	latest := &normaliser.NormalisedMetrics{
+		DatabaseID:   "testdb",
+		Measurements: normaliser.Measurements{CacheHitRate: &hitRate},
+	}
+	tracker.SetSnapshotSource(func(string) *normaliser.NormalisedMetrics { return latest })
+
+	key := "testdb:cache_miss_rate_high:"
+	tracker.RecordBaseline(key, "increase_cache_size", latest)
+	tracker.AddPendingVerification(key, "detection-123", "action-456", "increase_cache_size", "testdb")
+
+	hitRate = 0.65
+	tracker.OnDatabaseCycle("testdb")
+	tracker.OnDetectionFired(key)
+
+	require.NotNil(t, report, "Should report effectiveness on rollback")
+	assert.Equal(t, "cache_hit_rate", report.Metric)
+	assert.Equal(t, 0.6, report.MetricBefore)
+	assert.Equal(t, 0.65, report.MetricAfter)
+	assert.Equal(t, verification.VerdictIneffective, report.Verdict)
+}
+
+func TestEffectivenessNotReportedWithoutBaseline(t *testing.T) {
+	reported := false
+	tracker := verification.NewTracker(1, nil, nil)
+	tracker.SetEffectivenessHandler(func(*verification.EffectivenessReport) { reported = true })
+	tracker.SetSnapshotSource(func(string) *normaliser.NormalisedMetrics { return seqScanSnapshot("testdb", 3) })
+
+	tracker.AddPendingVerification("testdb:missing_index:users.email", "detection-123", "action-456", "create_index", "testdb")
+	tracker.OnDatabaseCycle("testdb")
+
+	assert.False(t, reported, "No baseline means nothing to compare against")
+	assert.Equal(t, 0, tracker.GetPendingCount())
+}
+
+func TestCancelVerification(t *testing.T) {
+	tracker := verification.NewTracker(3, nil, nil)
+
+	tracker.AddPendingVerification("testdb:missing_index:posts.user_id", "detection-1", "action-1", "create_index", "testdb")
+	tracker.AddPendingVerification("testdb:high_latency:global", "detection-2", "action-2", "tune_config_high_latency", "testdb")
+
+	// A different action for the same key leaves the verification alone
+	assert.False(t, tracker.CancelVerification("testdb:missing_index:posts.user_id", "action-9"))
+	assert.True(t, tracker.CancelVerification("testdb:missing_index:posts.user_id", "action-1"))
+	assert.False(t, tracker.IsPendingVerification("testdb:missing_index:posts.user_id"))
+
+	// Without a key the verification is found by action ID
+	assert.True(t, tracker.CancelVerification("", "action-2"))
+	assert.Equal(t, 0, tracker.GetPendingCount())
+	assert.False(t, tracker.CancelVerification("", "action-2"))
+}
@@ -3,13 +3,14 @@ package main
 import (
 	"context"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/config"
-	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/health"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/orchestrator"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/logging"
 )
 
 func main() {
@@ -21,11 +22,14 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	logging.Setup("collector", cfg.LogLevel)
+
 	log.Printf("Configuration loaded")
 	log.Printf("  Knowledge Address: %s", cfg.KnowledgeAddress)
 	log.Printf("  Analyser Address: %s", cfg.AnalyserAddress)
 	log.Printf("  Collection Interval: %v", cfg.CollectionInterval)
 	log.Printf("  Sync Interval: %v", cfg.SyncInterval)
+	log.Printf("  Health Port: %s", cfg.HealthPort)
 
 	// Create orchestrator
 	orch := orchestrator.NewOrchestrator(cfg)
@@ -43,8 +47,10 @@ func main() {
 		cancel()
 	}()
 
-	// Start health check server
-	health.StartHealthCheckServer("8080")
+	// Start health check server before waiting on databases; a port clash is fatal
+	if err := orch.StartHealthServer(); err != nil {
+		log.Fatalf("Failed to start health server: %v", err)
+	}
 
 	// Initialize orchestrator (will wait for databases from Knowledge)
 	if err := orch.Start(ctx); err != nil {
@@ -54,18 +60,16 @@ func main() {
 			log.Fatalf("Failed to start orchestrator: %v", err)
 		}
 		orch.Stop()
-		health.StopHealthCheckServer()
 		return
 	}
 
 	// Start metric collection (blocks until context cancelled)
 	if err := orch.Run(ctx); err != nil && err != context.Canceled {
-		log.Printf("Orchestrator error: %v", err)
+		slog.Error("Orchestrator error", "error", err)
 	}
 
-	health.StopHealthCheckServer()
 	if err := orch.Stop(); err != nil {
-		log.Printf("Error during shutdown: %v", err)
+		slog.Error("Error during shutdown", "error", err)
 	}
 
 	log.Printf("Collector stopped successfully")
@@ -0,0 +1,54 @@
+package adapter
+
+// Capabilities says which metric families a database's adapter can provide. Families it
+// can't provide are left unset in RawMetrics, so the normaliser doesn't score them.
+type Capabilities struct {
+	Connections bool // Active, idle and maximum connections
+	Cache       bool // Buffer or page cache hit rate
+	Latency     bool // Query latency percentiles and slow queries
+	TableStats  bool // Per-table scans and index usage, for the missing index detector
+	Storage     bool // Database size on disk
+}
+
+// Families lists the metric families covered, in a fixed order, for logging.
+func (c Capabilities) Families() []string {
+	families := []string{}
+	for _, family := range []struct {
+		name      string
+		supported bool
+	}{
+		{"connections", c.Connections},
+		{"cache", c.Cache},
+		{"latency", c.Latency},
+		{"table_stats", c.TableStats},
+		{"storage", c.Storage},
+	} {
+		if family.supported {
+			families = append(families, family.name)
+		}
+	}
+	return families
+}
+
+// Driver describes the database type an adapter handles and what it can collect.
+type Driver struct {
+	Name         string   // Canonical type, e.g. "postgres"
+	Aliases      []string // Other types that select it, e.g. "postgresql"
+	Capabilities Capabilities
+	New          func(connectionString, databaseID string) MetricAdapter
+}
+
+// Registered drivers, in registration order
+var drivers []Driver
+
+// RegisterDriver adds an adapter's driver. Each adapter calls it from its own init, and
+// the registry package picks the drivers up from its init, which runs after this
+// package's.
+func RegisterDriver(driver Driver) {
+	drivers = append(drivers, driver)
+}
+
+// Drivers returns every registered driver.
+func Drivers() []Driver {
+	return append([]Driver(nil), drivers...)
+}
@@ -14,6 +14,20 @@ type MetricAdapter interface {
 	GetUnavailableFeatures() []string
 }
 
+// IndexCandidateConfigurer is implemented by adapters that recommend index columns for
+// several sequentially scanned tables per cycle rather than only the worst one.
+type IndexCandidateConfigurer interface {
+	// SetIndexCandidateLimit sets how many tables, most scanned first, get recommendations.
+	SetIndexCandidateLimit(limit int)
+}
+
+// DiskUsageConfigurer is implemented by adapters that can read the data disk's capacity
+// through a query the operator supplies.
+type DiskUsageConfigurer interface {
+	// SetDiskUsageQuery sets the query returning total and free bytes; empty disables it.
+	SetDiskUsageQuery(query string)
+}
+
 var (
 	// ErrNotConnected is returned when Connect() has not been called or failed.
 	ErrNotConnected = errors.New("adapter: not connected to database")
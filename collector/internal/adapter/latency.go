@@ -0,0 +1,87 @@
+package adapter
+
+import "sort"
+
+// StatementLatency is one pg_stat_statements entry: how often a statement ran and how
+// long it took on average.
+type StatementLatency struct {
+	Query      string
+	Calls      int64
+	MeanExecMs float64
+}
+
+// LatencySummary is the call-weighted latency across a set of statements.
+type LatencySummary struct {
+	AvgMs float64
+	P50Ms float64
+	P95Ms float64
+	P99Ms float64
+}
+
+// SummariseLatency weights each statement's mean by its calls. Percentiles are
+// approximate: every call of a statement is assumed to take its mean. Returns nil if
+// no statement has been called.
+func SummariseLatency(statements []StatementLatency) *LatencySummary {
+	var totalCalls int64
+	var totalMs float64
+	ordered := make([]StatementLatency, 0, len(statements))
+	for _, s := range statements {
+		if s.Calls <= 0 {
+			continue
+		}
+		totalCalls += s.Calls
+		totalMs += float64(s.Calls) * s.MeanExecMs
+		ordered = append(ordered, s)
+	}
+
+	if totalCalls == 0 {
+		return nil
+	}
+
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].MeanExecMs < ordered[j].MeanExecMs
+	})
+
+	return &LatencySummary{
+		AvgMs: totalMs / float64(totalCalls),
+		P50Ms: weightedPercentile(ordered, totalCalls, 0.50),
+		P95Ms: weightedPercentile(ordered, totalCalls, 0.95),
+		P99Ms: weightedPercentile(ordered, totalCalls, 0.99),
+	}
+}
+
+// weightedPercentile returns the mean of the statement whose calls cross the
+// percentile. ordered must be sorted by MeanExecMs.
+func weightedPercentile(ordered []StatementLatency, totalCalls int64, percentile float64) float64 {
+	target := percentile * float64(totalCalls)
+
+	var seen int64
+	for _, s := range ordered {
+		seen += s.Calls
+		if float64(seen) >= target {
+			return s.MeanExecMs
+		}
+	}
+
+	return ordered[len(ordered)-1].MeanExecMs
+}
+
+// WorstStatements returns up to limit statements averaging at least thresholdMs,
+// slowest first.
+func WorstStatements(statements []StatementLatency, thresholdMs float64, limit int) []StatementLatency {
+	var slow []StatementLatency
+	for _, s := range statements {
+		if s.Calls > 0 && s.MeanExecMs >= thresholdMs {
+			slow = append(slow, s)
+		}
+	}
+
+	sort.Slice(slow, func(i, j int) bool {
+		return slow[i].MeanExecMs > slow[j].MeanExecMs
+	})
+
+	if len(slow) > limit {
+		slow = slow[:limit]
+	}
+	return slow
+}
@@ -11,6 +11,10 @@ type RawMetrics struct {
 	DatabaseType string
 	Timestamp    int64
 
+	// CollectedAt is when collection started. It carries Go's monotonic clock reading, so
+	// the interval between two collections is unaffected by the wall clock being stepped
+	CollectedAt time.Time
+
 	// Core metric categories (nil if not available for this database type)
 	Connections *ConnectionMetrics
 	Queries     *QueryMetrics
@@ -23,6 +27,9 @@ type RawMetrics struct {
 
 	// Structured logs (future use)
 	Logs []LogEntry
+
+	// How long each metric query took, most expensive first: the Collector's own load
+	QueryCosts []QueryCost
 }
 
 // ConnectionMetrics tracks database connection pool statistics.
@@ -61,6 +68,16 @@ type CacheMetrics struct {
 	CacheSizeBytes *int64
 }
 
+// CacheHitRate returns hits / (hits + misses). With no block accesses at all
+// nothing has missed the cache, so the rate is 1.0.
+func CacheHitRate(hits, misses int64) float64 {
+	total := hits + misses
+	if total <= 0 {
+		return 1.0
+	}
+	return float64(hits) / float64(total)
+}
+
 // SlowQuery represents a query that exceeded performance thresholds.
 type SlowQuery struct {
 	Query      string
@@ -79,10 +96,12 @@ type LogEntry struct {
 
 // NewRawMetrics creates a new RawMetrics instance with initialised maps.
 func NewRawMetrics(databaseID, databaseType string) *RawMetrics {
+	now := time.Now()
 	return &RawMetrics{
 		DatabaseID:      databaseID,
 		DatabaseType:    databaseType,
-		Timestamp:       time.Now().Unix(),
+		Timestamp:       now.Unix(),
+		CollectedAt:     now,
 		ExtendedMetrics: make(map[string]float64),
 		Labels:          make(map[string]string),
 	}
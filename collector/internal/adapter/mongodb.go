@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -18,6 +19,18 @@ type MongoDBAdapter struct {
 	databaseName     string
 	client           *mongo.Client
 	database         *mongo.Database
+	costs            queryCosts
+}
+
+func init() {
+	RegisterDriver(Driver{
+		Name:         "mongodb",
+		Aliases:      []string{"mongo"},
+		Capabilities: Capabilities{Connections: true, Cache: true, TableStats: true, Storage: true},
+		New: func(connectionString, databaseID string) MetricAdapter {
+			return NewMongoDBAdapter(connectionString, databaseID)
+		},
+	})
 }
 
 func NewMongoDBAdapter(connectionString, databaseID string) *MongoDBAdapter {
@@ -58,6 +71,7 @@ func (m *MongoDBAdapter) Connect() error {
 	}
 
 	if err := client.Ping(ctx, nil); err != nil {
+		client.Disconnect(ctx)
 		return fmt.Errorf("failed to ping MongoDB: %w", err)
 	}
 
@@ -75,103 +89,145 @@ func (m *MongoDBAdapter) CollectMetrics() (*RawMetrics, error) {
 
 	ctx := context.Background()
 	metrics := NewRawMetrics(m.databaseID, "mongodb")
+	defer func() { metrics.QueryCosts = m.costs.take() }()
 
 	// Server status for connections and cache
 	if err := m.collectServerStatus(ctx, metrics); err != nil {
-		log.Printf("Warning: failed to get server status: %v", err)
+		slog.Warn("Failed to get server status", "error", err)
 	}
 
 	// Collection scan stats
 	if err := m.collectCollectionScans(ctx, metrics); err != nil {
-		log.Printf("Warning: failed to get collection scans: %v", err)
+		slog.Warn("Failed to get collection scans", "error", err)
 	}
 
 	// Database stats
 	if err := m.collectDatabaseStats(ctx, metrics); err != nil {
-		log.Printf("Warning: failed to get database stats: %v", err)
+		slog.Warn("Failed to get database stats", "error", err)
+	}
+
+	// Long-running operations
+	if err := m.collectLongRunningOps(ctx, metrics); err != nil {
+		slog.Warn("Failed to get current operations", "error", err)
 	}
 
 	return metrics, nil
 }
 
 func (m *MongoDBAdapter) collectServerStatus(ctx context.Context, metrics *RawMetrics) error {
+	defer m.costs.track("server_status", time.Now())
+
 	var result bson.M
 	err := m.database.RunCommand(ctx, bson.D{{Key: "serverStatus", Value: 1}}).Decode(&result)
 	if err != nil {
 		return err
 	}
 
-	// Connections
-	if connections, ok := result["connections"].(bson.M); ok {
-		if current, ok := connections["current"].(int32); ok {
+	ApplyMongoServerStatus(metrics, result)
+	return nil
+}
+
+// ApplyMongoServerStatus fills connection and WiredTiger cache metrics from a serverStatus
+// reply. Fields the server didn't report are left nil, so they aren't scored.
+func ApplyMongoServerStatus(metrics *RawMetrics, status bson.M) {
+	// Connections: current counts idle ones too, so active (4.0.7+) is preferred
+	if connections, ok := status["connections"].(bson.M); ok {
+		current, hasCurrent := bsonInt64(connections["current"])
+		available, hasAvailable := bsonInt64(connections["available"])
+
+		if hasCurrent {
 			active := int32(current)
-			metrics.Connections = &ConnectionMetrics{
-				Active: &active,
+			conns := &ConnectionMetrics{Active: &active}
+
+			if activeOnly, ok := bsonInt64(connections["active"]); ok {
+				active = int32(activeOnly)
+				idle := int32(max(current-activeOnly, 0))
+				conns.Idle = &idle
 			}
-		}
-		if available, ok := connections["available"].(int32); ok {
-			if metrics.Connections != nil && metrics.Connections.Active != nil {
-				maxConn := *metrics.Connections.Active + int32(available)
-				metrics.Connections.Max = &maxConn
+			if hasAvailable {
+				maxConn := int32(current + available)
+				conns.Max = &maxConn
 			}
+			metrics.Connections = conns
 		}
 	}
 
-	// WiredTiger cache
-	if wt, ok := result["wiredTiger"].(bson.M); ok {
+	// WiredTiger cache: every page read into the cache missed it
+	if wt, ok := status["wiredTiger"].(bson.M); ok {
 		if cache, ok := wt["cache"].(bson.M); ok {
-			if pagesRead, ok := cache["pages read into cache"].(int64); ok {
-				if pagesRequested, ok := cache["pages requested from the cache"].(int64); ok {
-					if pagesRequested > 0 {
-						hitRate := float64(pagesRequested-pagesRead) / float64(pagesRequested)
-						if hitRate < 0 {
-							hitRate = 0
-						}
-						metrics.Cache = &CacheMetrics{
-							HitRate: &hitRate,
-						}
-					}
+			pagesRead, hasRead := bsonInt64(cache["pages read into cache"])
+			pagesRequested, hasRequested := bsonInt64(cache["pages requested from the cache"])
+			if hasRead && hasRequested {
+				hitRate := CacheHitRate(max(pagesRequested-pagesRead, 0), pagesRead)
+				metrics.Cache = &CacheMetrics{HitRate: &hitRate}
+			}
+
+			if metrics.Cache != nil {
+				if maxBytes, ok := bsonInt64(cache["maximum bytes configured"]); ok {
+					metrics.Cache.CacheSizeBytes = &maxBytes
 				}
 			}
 
-			if bytesInCache, ok := cache["bytes currently in the cache"].(int64); ok {
+			if bytesInCache, ok := bsonInt64(cache["bytes currently in the cache"]); ok {
 				metrics.ExtendedMetrics["mongodb.cache_bytes_in_cache"] = float64(bytesInCache)
 			}
+			if bytesRead, ok := bsonInt64(cache["bytes read into cache"]); ok {
+				metrics.ExtendedMetrics["mongodb.cache_bytes_read_into_cache"] = float64(bytesRead)
+			}
 		}
 	}
 
 	// Global lock / active clients
-	if globalLock, ok := result["globalLock"].(bson.M); ok {
+	if globalLock, ok := status["globalLock"].(bson.M); ok {
 		if activeClients, ok := globalLock["activeClients"].(bson.M); ok {
-			if readers, ok := activeClients["readers"].(int32); ok {
+			if readers, ok := bsonInt64(activeClients["readers"]); ok {
 				metrics.ExtendedMetrics["mongodb.active_readers"] = float64(readers)
 			}
-			if writers, ok := activeClients["writers"].(int32); ok {
+			if writers, ok := bsonInt64(activeClients["writers"]); ok {
 				metrics.ExtendedMetrics["mongodb.active_writers"] = float64(writers)
 			}
 		}
 	}
 
 	// Operation counters
-	if opcounters, ok := result["opcounters"].(bson.M); ok {
-		if query, ok := opcounters["query"].(int64); ok {
-			metrics.ExtendedMetrics["mongodb.opcounters_query"] = float64(query)
-		}
-		if insert, ok := opcounters["insert"].(int64); ok {
-			metrics.ExtendedMetrics["mongodb.opcounters_insert"] = float64(insert)
-		}
-		if update, ok := opcounters["update"].(int64); ok {
-			metrics.ExtendedMetrics["mongodb.opcounters_update"] = float64(update)
-		}
-		if del, ok := opcounters["delete"].(int64); ok {
-			metrics.ExtendedMetrics["mongodb.opcounters_delete"] = float64(del)
+	if opcounters, ok := status["opcounters"].(bson.M); ok {
+		for _, op := range []string{"query", "insert", "update", "delete"} {
+			if count, ok := bsonInt64(opcounters[op]); ok {
+				metrics.ExtendedMetrics["mongodb.opcounters_"+op] = float64(count)
+			}
 		}
 	}
+}
 
-	return nil
+// bsonInt64 reads a numeric BSON field, which the server may send as an int32, int64 or
+// double depending on its magnitude.
+func bsonInt64(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case float64:
+		return int64(n), true
+	case int:
+		return int64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// bsonFloat64 is bsonInt64 for fields with a fractional part.
+func bsonFloat64(v interface{}) (float64, bool) {
+	if f, ok := v.(float64); ok {
+		return f, true
+	}
+	n, ok := bsonInt64(v)
+	return float64(n), ok
 }
 
 func (m *MongoDBAdapter) collectCollectionScans(ctx context.Context, metrics *RawMetrics) error {
+	defer m.costs.track("collection_scans", time.Now())
+
 	// Try profile collection first (requires --profile 2)
 	profileColl := m.database.Collection("system.profile")
 
@@ -398,38 +454,118 @@ func (m *MongoDBAdapter) guessIndexField(ctx context.Context, collName string, m
 }
 
 func (m *MongoDBAdapter) collectDatabaseStats(ctx context.Context, metrics *RawMetrics) error {
+	defer m.costs.track("database_stats", time.Now())
+
 	var stats bson.M
 	err := m.database.RunCommand(ctx, bson.D{{Key: "dbStats", Value: 1}}).Decode(&stats)
 	if err != nil {
 		return err
 	}
 
-	if dataSize, ok := stats["dataSize"].(int64); ok {
-		metrics.Storage = &StorageMetrics{
-			UsedSizeBytes: &dataSize,
+	ApplyMongoDBStats(metrics, stats)
+	return nil
+}
+
+// ApplyMongoDBStats fills storage metrics from a dbStats reply. The database's size on
+// disk is storageSize; fsTotalSize and fsUsedSize (3.6+) describe the filesystem holding
+// it, so storage health can be scored without host metrics.
+func ApplyMongoDBStats(metrics *RawMetrics, stats bson.M) {
+	storage := &StorageMetrics{}
+
+	if storageSize, ok := bsonInt64(stats["storageSize"]); ok {
+		storage.UsedSizeBytes = &storageSize
+	}
+
+	if dataSize, ok := bsonInt64(stats["dataSize"]); ok {
+		if storage.UsedSizeBytes == nil {
+			storage.UsedSizeBytes = &dataSize
 		}
 		metrics.ExtendedMetrics["mongodb.database_size_bytes"] = float64(dataSize)
 		metrics.ExtendedMetrics["mongodb.database_size_mb"] = float64(dataSize) / 1024 / 1024
-	} else if dataSize, ok := stats["dataSize"].(float64); ok {
-		size := int64(dataSize)
-		metrics.Storage = &StorageMetrics{
-			UsedSizeBytes: &size,
-		}
-		metrics.ExtendedMetrics["mongodb.database_size_bytes"] = dataSize
-		metrics.ExtendedMetrics["mongodb.database_size_mb"] = dataSize / 1024 / 1024
 	}
 
-	if indexSize, ok := stats["indexSize"].(int64); ok {
+	if indexSize, ok := bsonInt64(stats["indexSize"]); ok {
+		storage.IndexSizeBytes = &indexSize
 		metrics.ExtendedMetrics["mongodb.index_size_bytes"] = float64(indexSize)
 	}
 
-	if collections, ok := stats["collections"].(int32); ok {
+	fsTotal, hasTotal := bsonInt64(stats["fsTotalSize"])
+	fsUsed, hasUsed := bsonInt64(stats["fsUsedSize"])
+	if hasTotal && hasUsed && fsTotal > 0 {
+		free := max(fsTotal-fsUsed, 0)
+		storage.TotalSizeBytes = &fsTotal
+		storage.FreeSpaceBytes = &free
+	}
+
+	if storage.UsedSizeBytes != nil || storage.TotalSizeBytes != nil {
+		metrics.Storage = storage
+	}
+
+	if collections, ok := bsonInt64(stats["collections"]); ok {
 		metrics.ExtendedMetrics["mongodb.collection_count"] = float64(collections)
 	}
+}
+
+// longRunningOpThresholdSecs matches the Postgres adapter's long-running query threshold.
+const longRunningOpThresholdSecs = 10
+
+func (m *MongoDBAdapter) collectLongRunningOps(ctx context.Context, metrics *RawMetrics) error {
+	defer m.costs.track("current_op", time.Now())
+
+	var result struct {
+		Inprog []bson.M `bson:"inprog"`
+	}
+	err := m.client.Database("admin").RunCommand(ctx, bson.D{
+		{Key: "currentOp", Value: 1},
+		{Key: "active", Value: true},
+		{Key: "secs_running", Value: bson.M{"$gte": longRunningOpThresholdSecs}},
+	}).Decode(&result)
+	if err != nil {
+		return err
+	}
 
+	ApplyMongoCurrentOp(metrics, result.Inprog)
 	return nil
 }
 
+// ApplyMongoCurrentOp reports the long-running operations from a currentOp reply, with
+// the longest one labelled so it can be found and killed with db.killOp.
+func ApplyMongoCurrentOp(metrics *RawMetrics, ops []bson.M) {
+	var longest bson.M
+	var longestSecs float64
+	count := 0
+
+	for _, op := range ops {
+		secs, ok := bsonFloat64(op["secs_running"])
+		if !ok || secs < longRunningOpThresholdSecs {
+			continue
+		}
+		// Replication, TTL and other internal threads run indefinitely
+		if desc, _ := op["desc"].(string); !strings.HasPrefix(desc, "conn") {
+			continue
+		}
+
+		count++
+		if longest == nil || secs > longestSecs {
+			longest, longestSecs = op, secs
+		}
+	}
+
+	metrics.ExtendedMetrics["mongodb.long_running_op_count"] = float64(count)
+	if longest == nil {
+		return
+	}
+
+	metrics.ExtendedMetrics["mongodb.longest_op_duration_secs"] = longestSecs
+	metrics.Labels["mongodb.longest_op_id"] = fmt.Sprint(longest["opid"])
+	if opType, ok := longest["op"].(string); ok {
+		metrics.Labels["mongodb.longest_op_type"] = opType
+	}
+	if ns, ok := longest["ns"].(string); ok {
+		metrics.Labels["mongodb.longest_op_ns"] = ns
+	}
+}
+
 func (m *MongoDBAdapter) Close() error {
 	if m.client != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
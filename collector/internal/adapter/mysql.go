@@ -5,11 +5,11 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"log"
+	"log/slog"
 	"strings"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 )
 
 // MySQLAdapter implements MetricAdapter for MySQL databases.
@@ -18,6 +18,18 @@ type MySQLAdapter struct {
 	databaseID               string
 	db                       *sql.DB
 	performanceSchemaEnabled bool
+	costs                    queryCosts
+}
+
+func init() {
+	RegisterDriver(Driver{
+		Name:         "mysql",
+		Aliases:      []string{"mariadb"},
+		Capabilities: Capabilities{Connections: true, Cache: true, TableStats: true, Storage: true},
+		New: func(connectionString, databaseID string) MetricAdapter {
+			return NewMySQLAdapter(connectionString, databaseID)
+		},
+	})
 }
 
 // NewMySQLAdapter creates a new MySQL adapter.
@@ -35,10 +47,20 @@ func (m *MySQLAdapter) Connect() error {
 	// Required: user:pass@tcp(host:port)/dbname
 	dsn := m.convertConnectionString(m.connectionString)
 
-	db, err := sql.Open("mysql", dsn)
+	// sql.Open defers most DSN errors to the first query; catch them here instead
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return fmt.Errorf("invalid MySQL connection string: %w", err)
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+
+	connector, err := mysql.NewConnector(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to open connection: %w", err)
 	}
+	db := sql.OpenDB(connector)
 
 	// Configure connection pool
 	db.SetMaxOpenConns(5)
@@ -51,7 +73,7 @@ func (m *MySQLAdapter) Connect() error {
 
 	if err := db.PingContext(ctx); err != nil {
 		db.Close()
-		return fmt.Errorf("failed to ping database: %w", err)
+		return fmt.Errorf("failed to connect to MySQL at %s: %w", cfg.Addr, err)
 	}
 
 	m.db = db
@@ -102,14 +124,14 @@ func (m *MySQLAdapter) checkPerformanceSchema(ctx context.Context) {
 	var enabled string
 	err := m.db.QueryRowContext(ctx, "SHOW VARIABLES LIKE 'performance_schema'").Scan(&enabled, &enabled)
 	if err != nil {
-		log.Printf("Warning: could not check performance_schema: %v", err)
+		slog.Warn("Could not check performance_schema", "error", err)
 		m.performanceSchemaEnabled = false
 		return
 	}
 
 	m.performanceSchemaEnabled = strings.ToUpper(enabled) == "ON"
 	if !m.performanceSchemaEnabled {
-		log.Printf("Warning: performance_schema is disabled")
+		slog.Warn("performance_schema is disabled")
 	}
 }
 
@@ -123,27 +145,33 @@ func (m *MySQLAdapter) CollectMetrics() (*RawMetrics, error) {
 	defer cancel()
 
 	metrics := NewRawMetrics(m.databaseID, "mysql")
+	defer func() { metrics.QueryCosts = m.costs.take() }()
 
-	// Connection metrics
-	activeConn, err := m.getActiveConnections(ctx)
-	if err != nil {
-		log.Printf("Warning: failed to get active connections: %v", err)
+	// Connection metrics; a count whose query failed stays nil rather than reading as zero
+	conns := &ConnectionMetrics{}
+	if activeConn, err := m.getActiveConnections(ctx); err != nil {
+		slog.Warn("Failed to get active connections", "error", err)
+	} else {
+		conns.Active = &activeConn
 	}
 
-	maxConn, err := m.getMaxConnections(ctx)
-	if err != nil {
-		log.Printf("Warning: failed to get max connections: %v", err)
+	if idleConn, err := m.getIdleConnections(ctx); err != nil {
+		slog.Warn("Failed to get idle connections", "error", err)
+	} else {
+		conns.Idle = &idleConn
 	}
 
-	metrics.Connections = &ConnectionMetrics{
-		Active: &activeConn,
-		Max:    &maxConn,
+	if maxConn, err := m.getMaxConnections(ctx); err != nil {
+		slog.Warn("Failed to get max connections", "error", err)
+	} else {
+		conns.Max = &maxConn
 	}
+	metrics.Connections = conns
 
 	// Storage metrics
 	dbSizeBytes, err := m.getDatabaseSizeBytes(ctx)
 	if err != nil {
-		log.Printf("Warning: failed to get database size: %v", err)
+		slog.Warn("Failed to get database size", "error", err)
 	} else {
 		metrics.Storage = &StorageMetrics{
 			UsedSizeBytes: &dbSizeBytes,
@@ -155,7 +183,7 @@ func (m *MySQLAdapter) CollectMetrics() (*RawMetrics, error) {
 	// Cache metrics (InnoDB buffer pool)
 	cacheHitRate, err := m.getCacheHitRate(ctx)
 	if err != nil {
-		log.Printf("Warning: failed to get cache hit rate: %v", err)
+		slog.Warn("Failed to get cache hit rate", "error", err)
 	} else {
 		metrics.Cache = &CacheMetrics{
 			HitRate: &cacheHitRate,
@@ -166,7 +194,7 @@ func (m *MySQLAdapter) CollectMetrics() (*RawMetrics, error) {
 	if m.performanceSchemaEnabled {
 		seqScans, err := m.getFullTableScans(ctx)
 		if err != nil {
-			log.Printf("Warning: failed to get table scans: %v", err)
+			slog.Warn("Failed to get table scans", "error", err)
 		} else {
 			metrics.Queries = &QueryMetrics{
 				SequentialScans: &seqScans,
@@ -176,13 +204,16 @@ func (m *MySQLAdapter) CollectMetrics() (*RawMetrics, error) {
 		// Table scan statistics
 		tableStats, err := m.getTableScanStats(ctx)
 		if err != nil {
-			log.Printf("Warning: failed to get table scan stats: %v", err)
+			slog.Warn("Failed to get table scan stats", "error", err)
 		} else if len(tableStats) > 0 {
 			worstTable := tableStats[0]
 
+			// seq_scans matches the Postgres keys the missing index detector reads;
+			// full_scans is the same count under MySQL's own name
 			for _, table := range tableStats {
 				prefix := fmt.Sprintf("mysql.table.%s", table.TableName)
 				metrics.ExtendedMetrics[prefix+".seq_scans"] = float64(table.SeqScans)
+				metrics.ExtendedMetrics[prefix+".full_scans"] = float64(table.SeqScans)
 				metrics.ExtendedMetrics[prefix+".seq_tup_read"] = float64(table.RowsRead)
 				metrics.ExtendedMetrics[prefix+".idx_scans"] = float64(table.IdxScans)
 			}
@@ -204,6 +235,8 @@ func (m *MySQLAdapter) CollectMetrics() (*RawMetrics, error) {
 
 // getActiveConnections returns the number of active connections.
 func (m *MySQLAdapter) getActiveConnections(ctx context.Context) (int32, error) {
+	defer m.costs.track("active_connections", time.Now())
+
 	var count int32
 	err := m.db.QueryRowContext(ctx, `
 		SELECT COUNT(*) FROM information_schema.processlist 
@@ -215,8 +248,25 @@ func (m *MySQLAdapter) getActiveConnections(ctx context.Context) (int32, error)
 	return count, nil
 }
 
+// getIdleConnections returns the number of connections waiting for a command.
+func (m *MySQLAdapter) getIdleConnections(ctx context.Context) (int32, error) {
+	defer m.costs.track("idle_connections", time.Now())
+
+	var count int32
+	err := m.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM information_schema.processlist
+		WHERE command = 'Sleep'
+	`).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 // getMaxConnections returns the maximum allowed connections.
 func (m *MySQLAdapter) getMaxConnections(ctx context.Context) (int32, error) {
+	defer m.costs.track("max_connections", time.Now())
+
 	var varName string
 	var maxConn int32
 	err := m.db.QueryRowContext(ctx, "SHOW VARIABLES LIKE 'max_connections'").Scan(&varName, &maxConn)
@@ -228,6 +278,8 @@ func (m *MySQLAdapter) getMaxConnections(ctx context.Context) (int32, error) {
 
 // getDatabaseSizeBytes returns the size of the current database in bytes.
 func (m *MySQLAdapter) getDatabaseSizeBytes(ctx context.Context) (int64, error) {
+	defer m.costs.track("database_size_bytes", time.Now())
+
 	var sizeBytes int64
 	err := m.db.QueryRowContext(ctx, `
 		SELECT COALESCE(SUM(data_length + index_length), 0)
@@ -242,6 +294,8 @@ func (m *MySQLAdapter) getDatabaseSizeBytes(ctx context.Context) (int64, error)
 
 // getCacheHitRate returns the InnoDB buffer pool hit rate.
 func (m *MySQLAdapter) getCacheHitRate(ctx context.Context) (float64, error) {
+	defer m.costs.track("cache_hit_rate", time.Now())
+
 	var readRequests, diskReads int64
 
 	// Get buffer pool read requests (logical reads)
@@ -257,13 +311,8 @@ func (m *MySQLAdapter) getCacheHitRate(ctx context.Context) (float64, error) {
 		return 0, fmt.Errorf("failed to get disk reads: %w", err)
 	}
 
-	if readRequests == 0 {
-		return 1.0, nil // No reads yet, assume 100% hit rate
-	}
-
-	// Hit rate = (requests - disk reads) / requests
-	hitRate := float64(readRequests-diskReads) / float64(readRequests)
-	return hitRate, nil
+	// Every disk read started as a read request that missed the buffer pool
+	return CacheHitRate(readRequests-diskReads, diskReads), nil
 }
 
 // MySQLTableScanStat holds table scan statistics for MySQL.
@@ -276,6 +325,8 @@ type MySQLTableScanStat struct {
 
 // getFullTableScans returns total full table scans across all tables.
 func (m *MySQLAdapter) getFullTableScans(ctx context.Context) (int32, error) {
+	defer m.costs.track("full_table_scans", time.Now())
+
 	var count int64
 	err := m.db.QueryRowContext(ctx, `
 		SELECT COALESCE(SUM(COUNT_READ), 0)
@@ -291,6 +342,8 @@ func (m *MySQLAdapter) getFullTableScans(ctx context.Context) (int32, error) {
 
 // getTableScanStats returns per-table scan statistics.
 func (m *MySQLAdapter) getTableScanStats(ctx context.Context) ([]MySQLTableScanStat, error) {
+	defer m.costs.track("table_scan_stats", time.Now())
+
 	rows, err := m.db.QueryContext(ctx, `
 		SELECT 
 			t1.OBJECT_NAME as table_name,
@@ -329,6 +382,8 @@ func (m *MySQLAdapter) getTableScanStats(ctx context.Context) ([]MySQLTableScanS
 // guessIndexColumn attempts to identify a column that should be indexed.
 // This is a simplified approach - checks for columns commonly used in WHERE clauses.
 func (m *MySQLAdapter) guessIndexColumn(ctx context.Context, tableName string) string {
+	defer m.costs.track("guess_index_column", time.Now())
+
 	// Get columns that are likely filter candidates (not primary key, not already indexed)
 	rows, err := m.db.QueryContext(ctx, `
 		SELECT c.COLUMN_NAME
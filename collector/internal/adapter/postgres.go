@@ -3,24 +3,65 @@ package adapter
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"regexp"
+	"log/slog"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+const (
+	// slowStatementThresholdMs marks a statement slow by its mean execution time
+	slowStatementThresholdMs = 100.0
+	// slowStatementLimit caps how many slow statements are reported per cycle
+	slowStatementLimit = 10
+	// pgStatStatementsRecheck is how often a missing pg_stat_statements is looked for again,
+	// so enabling it is picked up without restarting the Collector
+	pgStatStatementsRecheck = 5 * time.Minute
+	// pgExecTimeColumnsVersion is the first server_version_num whose pg_stat_statements
+	// names its timing columns mean_exec_time / total_exec_time rather than mean_time / total_time
+	pgExecTimeColumnsVersion = 130000
+	// pgLockWaitStartVersion is the first server_version_num with pg_locks.waitstart
+	pgLockWaitStartVersion = 140000
+	// pgSessionStatsVersion is the first server_version_num with pg_stat_database.sessions
+	pgSessionStatsVersion = 140000
+)
+
+// PgPool is the part of *pgxpool.Pool the PostgreSQL adapter queries through.
+type PgPool interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Ping(ctx context.Context) error
+	Close()
+}
+
 // PostgresAdapter implements MetricAdapter for PostgreSQL databases.
 type PostgresAdapter struct {
 	connectionString          string
 	databaseID                string
-	pool                      *pgxpool.Pool
+	pool                      PgPool
 	pgStatStatementsAvailable bool
+	pgStatStatementsCheckedAt time.Time
+	serverVersion             int    // server_version_num, 0 if it could not be read
+	meanTimeColumn            string // pg_stat_statements column names, which PG13 renamed
+	totalTimeColumn           string
+	latencyFailing            bool // a latency query failure was logged; logged again once it recovers
+	indexCandidateLimit       int
+	diskUsageQuery            string // operator-supplied; returns the data disk's total and free bytes
+	costs                     queryCosts
 }
 
+// DefaultIndexCandidateLimit is how many of the most sequentially scanned tables get index
+// column recommendations each cycle.
+const DefaultIndexCandidateLimit = 5
+
 // TableScanStat holds sequential and index scan statistics for a table.
 type TableScanStat struct {
 	TableName  string
@@ -35,10 +76,25 @@ type TableBloatStat struct {
 	LiveTuples     int64
 	DeadTuples     int64
 	BloatRatio     float64
+	SizeBytes      int64 // Including indexes and TOAST
 	LastVacuum     *time.Time
 	LastAutoVacuum *time.Time
 }
 
+// IndexUsageStat holds scan count and on-disk size for a secondary index.
+type IndexUsageStat struct {
+	IndexName string
+	TableName string
+	IdxScans  int64
+	SizeBytes int64
+}
+
+// ApplicationConnections holds the number of connections opened by one application_name.
+type ApplicationConnections struct {
+	ApplicationName string
+	Count           int64
+}
+
 // LongRunningQuery holds information about a query running longer than expected.
 type LongRunningQuery struct {
 	PID          int32
@@ -55,32 +111,109 @@ type IdleTransaction struct {
 	PID              int32
 	Username         string
 	DatabaseName     string
+	ApplicationName  string
 	Query            string
 	IdleDurationSecs float64
 }
 
+// ReplicationStat holds streaming replication lag for a single replica.
+type ReplicationStat struct {
+	ApplicationName string
+	ClientAddr      string
+	State           string
+	WriteLagSecs    float64
+	ReplayLagSecs   float64
+	ReplayLagBytes  int64 // WAL sent to the replica but not yet replayed
+}
+
+// LockWait holds information about a backend blocked waiting on a lock.
+type LockWait struct {
+	PID          int32
+	Username     string
+	Query        string
+	Relation     string
+	WaitDuration float64
+	BlockingPIDs []int32 // pg_blocking_pids - the sessions holding the lock
+}
+
+// Backend describes what a single backend is doing.
+type Backend struct {
+	PID             int32
+	Username        string
+	ApplicationName string
+	Query           string
+	State           string
+}
+
+func init() {
+	RegisterDriver(Driver{
+		Name:         "postgres",
+		Aliases:      []string{"postgresql"},
+		Capabilities: Capabilities{Connections: true, Cache: true, Latency: true, TableStats: true, Storage: true},
+		New: func(connectionString, databaseID string) MetricAdapter {
+			return NewPostgresAdapter(connectionString, databaseID)
+		},
+	})
+}
+
 // NewPostgresAdapter creates a new PostgreSQL adapter.
 func NewPostgresAdapter(connectionString string, databaseID string) *PostgresAdapter {
 	return &PostgresAdapter{
-		connectionString: connectionString,
-		databaseID:       databaseID,
-		pool:             nil,
+		connectionString:    connectionString,
+		databaseID:          databaseID,
+		pool:                nil,
+		indexCandidateLimit: DefaultIndexCandidateLimit,
 	}
 }
 
-// Connect establishes a connection pool to the PostgreSQL database.
+// NewPostgresAdapterWithPool creates a PostgreSQL adapter that collects through an existing
+// pool instead of opening its own in Connect.
+func NewPostgresAdapterWithPool(pool PgPool, databaseID string) *PostgresAdapter {
+	p := NewPostgresAdapter("", databaseID)
+	p.pool = pool
+	return p
+}
+
+// SetIndexCandidateLimit sets how many tables get index column recommendations per cycle.
+func (p *PostgresAdapter) SetIndexCandidateLimit(limit int) {
+	if limit < 1 {
+		limit = 1
+	}
+	p.indexCandidateLimit = limit
+}
+
+// SetDiskUsageQuery sets a query returning the total and free bytes of the disk holding the
+// data directory, as two bigint columns. PostgreSQL can't stat its own filesystem, so this is
+// left to the operator, e.g. a function wrapping df; empty disables it.
+func (p *PostgresAdapter) SetDiskUsageQuery(query string) {
+	p.diskUsageQuery = query
+}
+
+// Connect establishes a connection pool to the PostgreSQL database, unless the adapter was
+// given one by NewPostgresAdapterWithPool.
 func (p *PostgresAdapter) Connect() error {
 	ctx := context.Background()
 
-	pool, err := pgxpool.New(ctx, p.connectionString)
-	if err != nil {
-		return fmt.Errorf("failed to connect: %w", err)
+	if p.pool == nil {
+		pool, err := pgxpool.New(ctx, p.connectionString)
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+
+		p.pool = pool
 	}
 
-	p.pool = pool
+	if err := p.pool.QueryRow(ctx, `SELECT current_setting('server_version_num')::int`).Scan(&p.serverVersion); err != nil {
+		slog.Warn("Failed to get server version", "database_id", p.databaseID, "error", err)
+		p.serverVersion = 0
+	}
+
+	if p.serverVersion > 0 && p.serverVersion < pgSessionStatsVersion {
+		log.Printf("pg_stat_database.sessions needs PostgreSQL 14 (%s reports %d), connection churn detection disabled", p.databaseID, p.serverVersion)
+	}
 
 	if err := p.ensurePgStatStatements(ctx); err != nil {
-		log.Printf("Warning: pg_stat_statements setup issue: %v", err)
+		log.Printf("pg_stat_statements unavailable for %s, query analysis disabled: %v", p.databaseID, err)
 	}
 
 	return nil
@@ -94,6 +227,7 @@ func (p *PostgresAdapter) CollectMetrics() (*RawMetrics, error) {
 
 	ctx := context.Background()
 	metrics := NewRawMetrics(p.databaseID, "postgresql")
+	defer func() { metrics.QueryCosts = p.costs.take() }()
 
 	// Connection metrics
 	activeConn, err := p.getActiveConnections(ctx)
@@ -117,6 +251,18 @@ func (p *PostgresAdapter) CollectMetrics() (*RawMetrics, error) {
 		Max:    &maxConn,
 	}
 
+	// Idle connections per application (points at the app leaking connections)
+	idleApps, err := p.getIdleConnectionsByApplication(ctx)
+	if err != nil {
+		slog.Warn("Failed to get idle connections by application", "error", err)
+	} else if len(idleApps) > 0 {
+		counts := make([]string, 0, len(idleApps))
+		for _, app := range idleApps {
+			counts = append(counts, fmt.Sprintf("%s=%d", app.ApplicationName, app.Count))
+		}
+		metrics.Labels["pg.idle_connection_apps"] = strings.Join(counts, ",")
+	}
+
 	// Storage metrics
 	dbSizeBytes, err := p.getDatabaseSizeBytes(ctx)
 	if err != nil {
@@ -130,14 +276,36 @@ func (p *PostgresAdapter) CollectMetrics() (*RawMetrics, error) {
 	dbSizeMB := float64(dbSizeBytes) / (1024 * 1024)
 	metrics.ExtendedMetrics["pg.database_size_mb"] = dbSizeMB
 
+	// Every database on the server shares the disk, so their combined size is tracked too
+	tablespacesBytes, err := p.getTablespacesSizeBytes(ctx)
+	if err != nil {
+		slog.Warn("Failed to get tablespace sizes", "error", err)
+	} else {
+		metrics.ExtendedMetrics["pg.tablespaces_size_bytes"] = float64(tablespacesBytes)
+	}
+
+	// Disk capacity; host metrics, when configured, overwrite these with their own reading
+	if p.diskUsageQuery != "" {
+		totalBytes, freeBytes, err := p.getDiskUsage(ctx)
+		if err != nil {
+			slog.Warn("Failed to get disk usage", "error", err)
+		} else {
+			metrics.Storage.TotalSizeBytes = &totalBytes
+			metrics.Storage.FreeSpaceBytes = &freeBytes
+		}
+	}
+
 	// Cache metrics
-	cacheHitRate, err := p.getCacheHitRate(ctx)
+	cacheHits, cacheMisses, err := p.getCacheStats(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	cacheHitRate := CacheHitRate(cacheHits, cacheMisses)
 	metrics.Cache = &CacheMetrics{
-		HitRate: &cacheHitRate,
+		HitRate:   &cacheHitRate,
+		HitCount:  &cacheHits,
+		MissCount: &cacheMisses,
 	}
 
 	// Query metrics
@@ -150,10 +318,54 @@ func (p *PostgresAdapter) CollectMetrics() (*RawMetrics, error) {
 		SequentialScans: &seqScans,
 	}
 
+	// Query latency needs pg_stat_statements; it may have been enabled since Connect
+	if !p.pgStatStatementsAvailable && time.Since(p.pgStatStatementsCheckedAt) >= pgStatStatementsRecheck {
+		if err := p.ensurePgStatStatements(ctx); err == nil {
+			log.Printf("pg_stat_statements is now available for %s", p.databaseID)
+		}
+	}
+
+	if p.pgStatStatementsAvailable {
+		statements, err := p.getStatementLatencies(ctx)
+		if err != nil {
+			p.latencyFailed(err)
+		} else {
+			if p.latencyFailing {
+				log.Printf("Query latency collection recovered for %s", p.databaseID)
+				p.latencyFailing = false
+			}
+
+			if summary := SummariseLatency(statements); summary != nil {
+				metrics.Queries.AvgLatencyMs = &summary.AvgMs
+				metrics.Queries.P50LatencyMs = &summary.P50Ms
+				metrics.Queries.P95LatencyMs = &summary.P95Ms
+				metrics.Queries.P99LatencyMs = &summary.P99Ms
+
+				slow := WorstStatements(statements, slowStatementThresholdMs, slowStatementLimit)
+				metrics.Queries.SlowQueries = make([]SlowQuery, 0, len(slow))
+				for _, s := range slow {
+					metrics.Queries.SlowQueries = append(metrics.Queries.SlowQueries, SlowQuery{
+						Query:      s.Query,
+						DurationMs: s.MeanExecMs,
+						Timestamp:  metrics.Timestamp,
+						Source:     "pg_stat_statements",
+					})
+				}
+			}
+		}
+	}
+
+	// Checked after the query, which notices the extension being dropped
+	if p.pgStatStatementsAvailable {
+		metrics.Labels["pg.stat_statements"] = "available"
+	} else {
+		metrics.Labels["pg.stat_statements"] = "unavailable"
+	}
+
 	// Table scan statistics
 	tableStats, err := p.getTableScans(ctx)
 	if err != nil {
-		log.Printf("Warning: failed to get table stats: %v", err)
+		slog.Warn("Failed to get table stats", "error", err)
 	} else if len(tableStats) > 0 {
 		worstTable := tableStats[0]
 
@@ -166,24 +378,55 @@ func (p *PostgresAdapter) CollectMetrics() (*RawMetrics, error) {
 
 		metrics.Labels["pg.worst_seq_scan_table"] = worstTable.TableName
 
-		recommendedColumns, err := p.analyseSlowQueries(ctx, worstTable.TableName)
-		if err != nil {
-			log.Printf("Warning: could not analyse queries: %v", err)
-		} else if len(recommendedColumns) > 0 {
-			metrics.Labels["pg.recommended_index_column"] = recommendedColumns[0]
+		candidates := tableStats
+		if len(candidates) > p.indexCandidateLimit {
+			candidates = candidates[:p.indexCandidateLimit]
+		}
+		candidateNames := make([]string, 0, len(candidates))
+		for _, table := range candidates {
+			candidateNames = append(candidateNames, table.TableName)
+		}
+		metrics.Labels["pg.seq_scan_candidate_tables"] = strings.Join(candidateNames, ",")
+
+		// Without pg_stat_statements there are no queries to take columns from; the
+		// pg.stat_statements label tells the Analyser why no column is recommended
+		if p.pgStatStatementsAvailable {
+			for _, table := range candidates {
+				recommendedColumns, err := p.analyseSlowQueries(ctx, table.TableName)
+				if err != nil {
+					slog.Warn("Could not analyse queries", "table_name", table.TableName, "error", err)
+					continue
+				}
+				if len(recommendedColumns) == 0 {
+					continue
+				}
+
+				metrics.Labels[fmt.Sprintf("pg.table.%s.recommended_index_columns", table.TableName)] = strings.Join(recommendedColumns, ",")
+				if table.TableName == worstTable.TableName {
+					metrics.Labels["pg.recommended_index_column"] = recommendedColumns[0]
+					metrics.Labels["pg.recommended_index_columns"] = strings.Join(recommendedColumns, ",")
+				}
+			}
 		}
 	}
 
 	// Table bloat statistics
 	bloatStats, err := p.getTableBloat(ctx)
 	if err != nil {
-		log.Printf("Warning: failed to get table bloat stats: %v", err)
+		slog.Warn("Failed to get table bloat stats", "error", err)
 	} else if len(bloatStats) > 0 {
 		for _, table := range bloatStats {
 			prefix := fmt.Sprintf("pg.table.%s", table.TableName)
 			metrics.ExtendedMetrics[prefix+".live_tuples"] = float64(table.LiveTuples)
 			metrics.ExtendedMetrics[prefix+".dead_tuples"] = float64(table.DeadTuples)
 			metrics.ExtendedMetrics[prefix+".bloat_ratio"] = table.BloatRatio
+			metrics.ExtendedMetrics[prefix+".size_bytes"] = float64(table.SizeBytes)
+
+			if table.LastAutoVacuum != nil {
+				metrics.Labels[prefix+".last_autovacuum"] = table.LastAutoVacuum.UTC().Format(time.RFC3339)
+			} else {
+				metrics.Labels[prefix+".last_autovacuum"] = "never"
+			}
 		}
 
 		worstBloat := bloatStats[0]
@@ -193,10 +436,23 @@ func (p *PostgresAdapter) CollectMetrics() (*RawMetrics, error) {
 		}
 	}
 
+	// Index usage (idx_scan is cumulative - normaliser computes the per-cycle delta)
+	indexStats, err := p.getIndexUsage(ctx)
+	if err != nil {
+		slog.Warn("Failed to get index usage stats", "error", err)
+	} else {
+		for _, index := range indexStats {
+			prefix := fmt.Sprintf("pg.index.%s", index.IndexName)
+			metrics.ExtendedMetrics[prefix+".idx_scans"] = float64(index.IdxScans)
+			metrics.ExtendedMetrics[prefix+".size_bytes"] = float64(index.SizeBytes)
+			metrics.Labels[prefix+".table"] = index.TableName
+		}
+	}
+
 	// Long-running queries
 	longQueries, err := p.getLongRunningQueries(ctx, 10.0)
 	if err != nil {
-		log.Printf("Warning: failed to get long-running queries: %v", err)
+		slog.Warn("Failed to get long-running queries", "error", err)
 	} else {
 		metrics.ExtendedMetrics["pg.long_running_query_count"] = float64(len(longQueries))
 
@@ -212,7 +468,7 @@ func (p *PostgresAdapter) CollectMetrics() (*RawMetrics, error) {
 	// Idle transactions
 	idleTransactions, err := p.getIdleTransactions(ctx, 60.0)
 	if err != nil {
-		log.Printf("Warning: failed to get idle transactions: %v", err)
+		slog.Warn("Failed to get idle transactions", "error", err)
 	} else {
 		metrics.ExtendedMetrics["pg.idle_transaction_count"] = float64(len(idleTransactions))
 
@@ -220,11 +476,119 @@ func (p *PostgresAdapter) CollectMetrics() (*RawMetrics, error) {
 			worst := idleTransactions[0]
 			metrics.Labels["pg.idle_txn_pid"] = fmt.Sprintf("%d", worst.PID)
 			metrics.Labels["pg.idle_txn_user"] = worst.Username
+			metrics.Labels["pg.idle_txn_application"] = worst.ApplicationName
 			metrics.Labels["pg.idle_txn_query"] = worst.Query
 			metrics.ExtendedMetrics["pg.idle_txn_duration_secs"] = worst.IdleDurationSecs
 		}
 	}
 
+	// Deadlocks (cumulative counter - normaliser computes the per-cycle delta)
+	deadlocks, err := p.getDeadlocks(ctx)
+	if err != nil {
+		slog.Warn("Failed to get deadlock count", "error", err)
+	} else {
+		metrics.ExtendedMetrics["pg.deadlocks"] = float64(deadlocks)
+	}
+
+	// Sessions and transactions (cumulative counters - connection churn is their per-cycle delta)
+	sessions, xacts, err := p.getSessionStats(ctx)
+	if err != nil {
+		slog.Warn("Failed to get session stats", "error", err)
+	} else {
+		if sessions != nil {
+			metrics.ExtendedMetrics["pg.sessions_total"] = float64(*sessions)
+		}
+		metrics.ExtendedMetrics["pg.xact_total"] = float64(xacts)
+	}
+
+	// Temp files (cumulative counters - sorts and hashes spilling past work_mem)
+	tempFiles, tempBytes, err := p.getTempFileStats(ctx)
+	if err != nil {
+		slog.Warn("Failed to get temp file stats", "error", err)
+	} else {
+		metrics.ExtendedMetrics["pg.temp_files"] = float64(tempFiles)
+		metrics.ExtendedMetrics["pg.temp_bytes"] = float64(tempBytes)
+	}
+
+	// Lock waits (evidence for deadlock investigation and lock contention)
+	waiting, err := p.getWaitingConnections(ctx)
+	if err != nil {
+		slog.Warn("Failed to count waiting connections", "error", err)
+	} else {
+		metrics.Connections.Waiting = &waiting
+		metrics.ExtendedMetrics["pg.blocked_count"] = float64(waiting)
+	}
+
+	lockWaits, err := p.getLockWaits(ctx)
+	if err != nil {
+		slog.Warn("Failed to get lock waits", "error", err)
+	} else if len(lockWaits) > 0 {
+		worst := lockWaits[0]
+		metrics.Labels["pg.lock_wait_pid"] = fmt.Sprintf("%d", worst.PID)
+		metrics.Labels["pg.lock_wait_user"] = worst.Username
+		metrics.Labels["pg.lock_wait_query"] = worst.Query
+		metrics.Labels["pg.lock_wait_relation"] = worst.Relation
+		metrics.ExtendedMetrics["pg.lock_wait_secs"] = worst.WaitDuration
+
+		if len(worst.BlockingPIDs) > 0 {
+			pids := make([]string, 0, len(worst.BlockingPIDs))
+			for _, pid := range worst.BlockingPIDs {
+				pids = append(pids, fmt.Sprintf("%d", pid))
+			}
+			metrics.Labels["pg.lock_wait_blocking_pids"] = strings.Join(pids, ",")
+
+			// The first blocker is the one to terminate if the pileup persists
+			blocker, err := p.getBackend(ctx, worst.BlockingPIDs[0])
+			if err != nil {
+				slog.Warn("Failed to get blocking backend", "error", err)
+			} else if blocker != nil {
+				metrics.Labels["pg.blocking_pid"] = fmt.Sprintf("%d", blocker.PID)
+				metrics.Labels["pg.lock_blocker_user"] = blocker.Username
+				metrics.Labels["pg.lock_blocker_application"] = blocker.ApplicationName
+				metrics.Labels["pg.lock_blocker_query"] = blocker.Query
+				metrics.Labels["pg.lock_blocker_state"] = blocker.State
+			}
+		}
+	}
+
+	// Replication lag (only populated on a primary with attached replicas)
+	replicas, err := p.getReplicationStats(ctx)
+	if err != nil {
+		slog.Warn("Failed to get replication stats", "error", err)
+	} else {
+		metrics.ExtendedMetrics["pg.replica_count"] = float64(len(replicas))
+
+		for _, replica := range replicas {
+			prefix := fmt.Sprintf("pg.replica.%s", replica.ApplicationName)
+			metrics.ExtendedMetrics[prefix+".replay_lag_secs"] = replica.ReplayLagSecs
+			metrics.ExtendedMetrics[prefix+".write_lag_secs"] = replica.WriteLagSecs
+			metrics.ExtendedMetrics[prefix+".replay_lag_bytes"] = float64(replica.ReplayLagBytes)
+			metrics.Labels[prefix+".state"] = replica.State
+		}
+
+		if len(replicas) > 0 {
+			worst := replicas[0]
+			metrics.Labels["pg.worst_lag_replica"] = worst.ApplicationName
+			metrics.Labels["pg.worst_lag_replica_state"] = worst.State
+			metrics.Labels["pg.worst_lag_replica_addr"] = worst.ClientAddr
+			metrics.ExtendedMetrics["pg.max_replication_lag_secs"] = worst.ReplayLagSecs
+			metrics.ExtendedMetrics["pg.max_replication_write_lag_secs"] = worst.WriteLagSecs
+
+			// replay_lag is NULL once the primary goes quiet, so the byte backlog is tracked
+			// across all replicas rather than just the one furthest behind in time
+			worstBytes := replicas[0]
+			for _, replica := range replicas {
+				if replica.ReplayLagBytes > worstBytes.ReplayLagBytes {
+					worstBytes = replica
+				}
+			}
+			metrics.Labels["pg.worst_bytes_lag_replica"] = worstBytes.ApplicationName
+			metrics.Labels["pg.worst_bytes_lag_replica_state"] = worstBytes.State
+			metrics.Labels["pg.worst_bytes_lag_replica_addr"] = worstBytes.ClientAddr
+			metrics.ExtendedMetrics["pg.max_replication_lag_bytes"] = float64(worstBytes.ReplayLagBytes)
+		}
+	}
+
 	return metrics, nil
 }
 
@@ -257,10 +621,16 @@ func (p *PostgresAdapter) GetUnavailableFeatures() []string {
 	if !p.pgStatStatementsAvailable {
 		features = append(features, "pg_stat_statements")
 	}
+	// Sessions are only counted from PostgreSQL 14, and connection churn is their delta
+	if p.serverVersion < pgSessionStatsVersion {
+		features = append(features, "session_stats")
+	}
 	return features
 }
 
 func (p *PostgresAdapter) getActiveConnections(ctx context.Context) (int32, error) {
+	defer p.costs.track("active_connections", time.Now())
+
 	var count int32
 	query := "SELECT count(*) FROM pg_stat_activity WHERE state = 'active'"
 
@@ -272,6 +642,8 @@ func (p *PostgresAdapter) getActiveConnections(ctx context.Context) (int32, erro
 }
 
 func (p *PostgresAdapter) getIdleConnections(ctx context.Context) (int32, error) {
+	defer p.costs.track("idle_connections", time.Now())
+
 	var count int32
 	query := "SELECT count(*) FROM pg_stat_activity WHERE state = 'idle'"
 
@@ -282,7 +654,44 @@ func (p *PostgresAdapter) getIdleConnections(ctx context.Context) (int32, error)
 	return count, nil
 }
 
+// getIdleConnectionsByApplication groups idle connections by application_name, largest first.
+func (p *PostgresAdapter) getIdleConnectionsByApplication(ctx context.Context) ([]ApplicationConnections, error) {
+	defer p.costs.track("idle_connections_by_application", time.Now())
+
+	query := `
+		SELECT
+			COALESCE(NULLIF(application_name, ''), 'unknown'),
+			count(*)
+		FROM pg_stat_activity
+		WHERE state = 'idle'
+		GROUP BY 1
+		ORDER BY 2 DESC
+		LIMIT 5
+	`
+
+	rows, err := p.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query idle connections by application: %w", err)
+	}
+	defer rows.Close()
+
+	var apps []ApplicationConnections
+	for rows.Next() {
+		var a ApplicationConnections
+		if err := rows.Scan(&a.ApplicationName, &a.Count); err != nil {
+			return nil, err
+		}
+		// Commas and equals signs would break the label encoding
+		a.ApplicationName = strings.NewReplacer(",", "_", "=", "_").Replace(a.ApplicationName)
+		apps = append(apps, a)
+	}
+
+	return apps, nil
+}
+
 func (p *PostgresAdapter) getMaxConnections(ctx context.Context) (int32, error) {
+	defer p.costs.track("max_connections", time.Now())
+
 	var countString string
 	query := "SHOW max_connections"
 
@@ -299,6 +708,8 @@ func (p *PostgresAdapter) getMaxConnections(ctx context.Context) (int32, error)
 }
 
 func (p *PostgresAdapter) getDatabaseSizeBytes(ctx context.Context) (int64, error) {
+	defer p.costs.track("database_size_bytes", time.Now())
+
 	var sizeBytes int64
 	query := "SELECT pg_database_size(current_database())"
 
@@ -309,30 +720,56 @@ func (p *PostgresAdapter) getDatabaseSizeBytes(ctx context.Context) (int64, erro
 	return sizeBytes, nil
 }
 
-func (p *PostgresAdapter) getCacheHitRate(ctx context.Context) (float64, error) {
-	var blkReads, blksHit int64
+// getTablespacesSizeBytes returns the combined size of every tablespace on the server.
+func (p *PostgresAdapter) getTablespacesSizeBytes(ctx context.Context) (int64, error) {
+	defer p.costs.track("tablespaces_size_bytes", time.Now())
+
+	var sizeBytes int64
+	query := "SELECT COALESCE(SUM(pg_tablespace_size(oid)), 0)::int8 FROM pg_tablespace"
+
+	if err := p.pool.QueryRow(ctx, query).Scan(&sizeBytes); err != nil {
+		return 0, fmt.Errorf("failed to get tablespace size: %w", err)
+	}
+
+	return sizeBytes, nil
+}
+
+// getDiskUsage runs the configured disk usage query.
+func (p *PostgresAdapter) getDiskUsage(ctx context.Context) (totalBytes, freeBytes int64, err error) {
+	defer p.costs.track("disk_usage", time.Now())
+
+	if err := p.pool.QueryRow(ctx, p.diskUsageQuery).Scan(&totalBytes, &freeBytes); err != nil {
+		return 0, 0, fmt.Errorf("failed to run disk usage query: %w", err)
+	}
+	if totalBytes <= 0 || freeBytes < 0 || freeBytes > totalBytes {
+		return 0, 0, fmt.Errorf("disk usage query returned total %d, free %d", totalBytes, freeBytes)
+	}
+
+	return totalBytes, freeBytes, nil
+}
+
+// getCacheStats returns the lifetime buffer hits and disk reads for the current database.
+func (p *PostgresAdapter) getCacheStats(ctx context.Context) (hits, misses int64, err error) {
+	defer p.costs.track("cache_stats", time.Now())
 
 	query := `
 		SELECT
-			sum(blks_read) as blks_read,
-			sum(blks_hit) as blks_hit
+			COALESCE(sum(blks_hit), 0) as blks_hit,
+			COALESCE(sum(blks_read), 0) as blks_read
 		FROM pg_stat_database
 		WHERE datname = current_database()
 	`
 
-	if err := p.pool.QueryRow(ctx, query).Scan(&blkReads, &blksHit); err != nil {
-		return 0, fmt.Errorf("failed to get cache stats: %w", err)
-	}
-
-	total := blkReads + blksHit
-	if total == 0 {
-		return 0, nil
+	if err := p.pool.QueryRow(ctx, query).Scan(&hits, &misses); err != nil {
+		return 0, 0, fmt.Errorf("failed to get cache stats: %w", err)
 	}
 
-	return float64(blksHit) / float64(total), nil
+	return hits, misses, nil
 }
 
 func (p *PostgresAdapter) getSequentialScans(ctx context.Context) (int32, error) {
+	defer p.costs.track("sequential_scans", time.Now())
+
 	var seqScans int64
 	query := `
 		SELECT COALESCE(SUM(seq_scan), 0)
@@ -348,6 +785,8 @@ func (p *PostgresAdapter) getSequentialScans(ctx context.Context) (int32, error)
 }
 
 func (p *PostgresAdapter) getTableScans(ctx context.Context) ([]TableScanStat, error) {
+	defer p.costs.track("table_scans", time.Now())
+
 	query := `
 		SELECT 
 			relname,
@@ -379,25 +818,29 @@ func (p *PostgresAdapter) getTableScans(ctx context.Context) ([]TableScanStat, e
 	return stats, nil
 }
 
+// analyseSlowQueries recommends index columns for tableName from the queries that spend
+// the most time on it. Columns are ordered for a multi-column index.
 func (p *PostgresAdapter) analyseSlowQueries(ctx context.Context, tableName string) ([]string, error) {
+	defer p.costs.track("analyse_slow_queries", time.Now())
+
 	if !p.pgStatStatementsAvailable {
 		return nil, fmt.Errorf("pg_stat_statements not available")
 	}
 
-	query := `
+	query := fmt.Sprintf(`
 		SELECT 
 			query,
 			calls,
-			mean_exec_time,
-			total_exec_time
+			%s
 		FROM pg_stat_statements
 		WHERE query ILIKE $1
 		AND calls > 1
-		ORDER BY mean_exec_time DESC
-		LIMIT 10
-	`
+		ORDER BY %s DESC
+		LIMIT 20
+	`, p.meanTimeColumn, p.totalTimeColumn)
 
-	pattern := fmt.Sprintf("%%FROM %s%%", tableName)
+	// Broad match - ExtractQueryColumns only keeps columns that belong to tableName
+	pattern := fmt.Sprintf("%%%s%%", tableName)
 
 	rows, err := p.pool.Query(ctx, query, pattern)
 	if err != nil {
@@ -405,59 +848,78 @@ func (p *PostgresAdapter) analyseSlowQueries(ctx context.Context, tableName stri
 	}
 	defer rows.Close()
 
-	columnFrequency := make(map[string]int)
-
+	var queries []WeightedQuery
 	for rows.Next() {
-		var sqlQuery string
-		var calls int64
-		var meanExecTime, totalExecTime float64
-
-		if err := rows.Scan(&sqlQuery, &calls, &meanExecTime, &totalExecTime); err != nil {
+		var q WeightedQuery
+		if err := rows.Scan(&q.Query, &q.Calls, &q.MeanExecTimeMs); err != nil {
 			continue
 		}
+		queries = append(queries, q)
+	}
 
-		columns := extractFilteredColumns(sqlQuery)
-		for _, col := range columns {
-			columnFrequency[col] += int(calls)
-		}
+	return RecommendIndexColumns(queries, tableName), nil
+}
+
+// getStatementLatencies returns every statement pg_stat_statements has recorded for the
+// current database.
+func (p *PostgresAdapter) getStatementLatencies(ctx context.Context) ([]StatementLatency, error) {
+	defer p.costs.track("statement_latencies", time.Now())
+
+	query := fmt.Sprintf(`
+		SELECT query, calls, %s
+		FROM pg_stat_statements
+		WHERE dbid = (SELECT oid FROM pg_database WHERE datname = current_database())
+		AND calls > 0
+	`, p.meanTimeColumn)
+
+	rows, err := p.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pg_stat_statements: %w", err)
 	}
+	defer rows.Close()
 
-	var recommendedColumns []string
-	for col := range columnFrequency {
-		recommendedColumns = append(recommendedColumns, col)
+	var statements []StatementLatency
+	for rows.Next() {
+		var s StatementLatency
+		if err := rows.Scan(&s.Query, &s.Calls, &s.MeanExecMs); err != nil {
+			continue
+		}
+		statements = append(statements, s)
 	}
 
-	return recommendedColumns, nil
+	return statements, rows.Err()
 }
 
-func extractFilteredColumns(query string) []string {
-	var columns []string
-
-	patterns := []string{
-		`WHERE\s+(\w+)\s*=`,
-		`WHERE\s+(\w+)\s+IN`,
-		`WHERE\s+(\w+)\s*[><]`,
-		`AND\s+(\w+)\s*=`,
-		`AND\s+(\w+)\s+IN`,
-		`AND\s+(\w+)\s*[><]`,
+// latencyFailed logs a failed latency query once, rather than every cycle, leaving the
+// latency fields unset. If the extension was dropped it is looked for again later.
+func (p *PostgresAdapter) latencyFailed(err error) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "42P01" { // undefined_table
+		p.pgStatStatementsAvailable = false
+		p.pgStatStatementsCheckedAt = time.Now()
+		log.Printf("pg_stat_statements no longer available for %s, query latency disabled: %v", p.databaseID, err)
+		return
 	}
 
-	queryUpper := strings.ToUpper(query)
-
-	for _, pattern := range patterns {
-		re := regexp.MustCompile(pattern)
-		matches := re.FindAllStringSubmatch(queryUpper, -1)
-		for _, match := range matches {
-			if len(match) > 1 {
-				columns = append(columns, strings.ToLower(match[1]))
-			}
-		}
+	if !p.latencyFailing {
+		slog.Warn("Failed to get query latency", "database_id", p.databaseID, "error", err)
+		p.latencyFailing = true
 	}
+}
 
-	return columns
+// setStatementTimeColumns picks the pg_stat_statements timing columns for the server version.
+func (p *PostgresAdapter) setStatementTimeColumns() {
+	p.meanTimeColumn, p.totalTimeColumn = "mean_exec_time", "total_exec_time"
+	if p.serverVersion > 0 && p.serverVersion < pgExecTimeColumnsVersion {
+		p.meanTimeColumn, p.totalTimeColumn = "mean_time", "total_time"
+	}
 }
 
+// ensurePgStatStatements checks pg_extension for pg_stat_statements, creating the extension
+// if the library is preloaded but the extension was never created.
 func (p *PostgresAdapter) ensurePgStatStatements(ctx context.Context) error {
+	p.pgStatStatementsCheckedAt = time.Now()
+
 	var exists bool
 	err := p.pool.QueryRow(ctx, `
 		SELECT EXISTS (
@@ -471,6 +933,7 @@ func (p *PostgresAdapter) ensurePgStatStatements(ctx context.Context) error {
 	}
 
 	if exists {
+		p.setStatementTimeColumns()
 		p.pgStatStatementsAvailable = true
 		return nil
 	}
@@ -489,17 +952,21 @@ func (p *PostgresAdapter) ensurePgStatStatements(ctx context.Context) error {
 		return fmt.Errorf("failed to create extension: %w", err)
 	}
 
+	p.setStatementTimeColumns()
 	p.pgStatStatementsAvailable = true
 	log.Printf("pg_stat_statements extension enabled")
 	return nil
 }
 
 func (p *PostgresAdapter) getTableBloat(ctx context.Context) ([]TableBloatStat, error) {
+	defer p.costs.track("table_bloat", time.Now())
+
 	query := `
 		SELECT 
 			relname,
 			n_live_tup,
 			n_dead_tup,
+			pg_total_relation_size(relid),
 			last_vacuum,
 			last_autovacuum
 		FROM pg_stat_user_tables
@@ -518,7 +985,7 @@ func (p *PostgresAdapter) getTableBloat(ctx context.Context) ([]TableBloatStat,
 	var stats []TableBloatStat
 	for rows.Next() {
 		var s TableBloatStat
-		if err := rows.Scan(&s.TableName, &s.LiveTuples, &s.DeadTuples, &s.LastVacuum, &s.LastAutoVacuum); err != nil {
+		if err := rows.Scan(&s.TableName, &s.LiveTuples, &s.DeadTuples, &s.SizeBytes, &s.LastVacuum, &s.LastAutoVacuum); err != nil {
 			return nil, err
 		}
 		if s.LiveTuples > 0 {
@@ -530,7 +997,48 @@ func (p *PostgresAdapter) getTableBloat(ctx context.Context) ([]TableBloatStat,
 	return stats, nil
 }
 
+// getIndexUsage returns scan counts and sizes for the largest user indexes. Indexes that
+// back a primary key, unique or exclusion constraint are skipped since they cannot be
+// dropped without changing the schema's guarantees.
+func (p *PostgresAdapter) getIndexUsage(ctx context.Context) ([]IndexUsageStat, error) {
+	defer p.costs.track("index_usage", time.Now())
+
+	query := `
+		SELECT
+			s.indexrelname,
+			s.relname,
+			s.idx_scan,
+			pg_relation_size(s.indexrelid)
+		FROM pg_stat_user_indexes s
+		JOIN pg_index i ON i.indexrelid = s.indexrelid
+		WHERE NOT i.indisprimary
+		AND NOT i.indisunique
+		AND NOT EXISTS (SELECT 1 FROM pg_constraint c WHERE c.conindid = s.indexrelid)
+		ORDER BY pg_relation_size(s.indexrelid) DESC
+		LIMIT 20
+	`
+
+	rows, err := p.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query index usage: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []IndexUsageStat
+	for rows.Next() {
+		var s IndexUsageStat
+		if err := rows.Scan(&s.IndexName, &s.TableName, &s.IdxScans, &s.SizeBytes); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+
+	return stats, nil
+}
+
 func (p *PostgresAdapter) getLongRunningQueries(ctx context.Context, thresholdSecs float64) ([]LongRunningQuery, error) {
+	defer p.costs.track("long_running_queries", time.Now())
+
 	query := `
 		SELECT 
 			pid,
@@ -569,11 +1077,14 @@ func (p *PostgresAdapter) getLongRunningQueries(ctx context.Context, thresholdSe
 }
 
 func (p *PostgresAdapter) getIdleTransactions(ctx context.Context, thresholdSecs float64) ([]IdleTransaction, error) {
+	defer p.costs.track("idle_transactions", time.Now())
+
 	query := `
 		SELECT 
 			pid,
 			usename,
 			datname,
+			COALESCE(application_name, '') as application_name,
 			LEFT(COALESCE(query, ''), 200) as query,
 			EXTRACT(EPOCH FROM (now() - state_change)) as idle_duration_secs
 		FROM pg_stat_activity
@@ -594,7 +1105,7 @@ func (p *PostgresAdapter) getIdleTransactions(ctx context.Context, thresholdSecs
 	var transactions []IdleTransaction
 	for rows.Next() {
 		var t IdleTransaction
-		if err := rows.Scan(&t.PID, &t.Username, &t.DatabaseName, &t.Query, &t.IdleDurationSecs); err != nil {
+		if err := rows.Scan(&t.PID, &t.Username, &t.DatabaseName, &t.ApplicationName, &t.Query, &t.IdleDurationSecs); err != nil {
 			return nil, err
 		}
 		transactions = append(transactions, t)
@@ -602,3 +1113,183 @@ func (p *PostgresAdapter) getIdleTransactions(ctx context.Context, thresholdSecs
 
 	return transactions, nil
 }
+
+func (p *PostgresAdapter) getReplicationStats(ctx context.Context) ([]ReplicationStat, error) {
+	defer p.costs.track("replication_stats", time.Now())
+
+	query := `
+		SELECT 
+			COALESCE(NULLIF(application_name, ''), 'unknown') as application_name,
+			COALESCE(client_addr::text, '') as client_addr,
+			COALESCE(state, 'unknown') as state,
+			COALESCE(EXTRACT(EPOCH FROM write_lag), 0)::float8 as write_lag_secs,
+			COALESCE(EXTRACT(EPOCH FROM replay_lag), 0)::float8 as replay_lag_secs,
+			COALESCE(pg_wal_lsn_diff(sent_lsn, replay_lsn), 0)::int8 as replay_lag_bytes
+		FROM pg_stat_replication
+		ORDER BY replay_lag_secs DESC, replay_lag_bytes DESC
+	`
+
+	rows, err := p.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query replication stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []ReplicationStat
+	for rows.Next() {
+		var s ReplicationStat
+		if err := rows.Scan(&s.ApplicationName, &s.ClientAddr, &s.State, &s.WriteLagSecs, &s.ReplayLagSecs, &s.ReplayLagBytes); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+
+	return stats, rows.Err()
+}
+
+func (p *PostgresAdapter) getDeadlocks(ctx context.Context) (int64, error) {
+	defer p.costs.track("deadlocks", time.Now())
+
+	var deadlocks int64
+	err := p.pool.QueryRow(ctx, `
+		SELECT COALESCE(deadlocks, 0)
+		FROM pg_stat_database
+		WHERE datname = current_database()
+	`).Scan(&deadlocks)
+	return deadlocks, err
+}
+
+// getSessionStats returns how many sessions have been established to the current database
+// and how many transactions it has committed or rolled back. Sessions are only counted from
+// PostgreSQL 14, and are nil before it.
+func (p *PostgresAdapter) getSessionStats(ctx context.Context) (sessions *int64, xacts int64, err error) {
+	defer p.costs.track("session_stats", time.Now())
+
+	sessionsColumn := "NULL::int8"
+	if p.serverVersion >= pgSessionStatsVersion {
+		sessionsColumn = "COALESCE(sessions, 0)"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s, COALESCE(xact_commit, 0) + COALESCE(xact_rollback, 0)
+		FROM pg_stat_database
+		WHERE datname = current_database()
+	`, sessionsColumn)
+
+	if err := p.pool.QueryRow(ctx, query).Scan(&sessions, &xacts); err != nil {
+		return nil, 0, fmt.Errorf("failed to get session stats: %w", err)
+	}
+
+	return sessions, xacts, nil
+}
+
+// getTempFileStats returns how many temporary files queries on the current database have
+// written, and their total size.
+func (p *PostgresAdapter) getTempFileStats(ctx context.Context) (files, bytes int64, err error) {
+	defer p.costs.track("temp_file_stats", time.Now())
+
+	query := `
+		SELECT COALESCE(temp_files, 0), COALESCE(temp_bytes, 0)
+		FROM pg_stat_database
+		WHERE datname = current_database()
+	`
+
+	if err := p.pool.QueryRow(ctx, query).Scan(&files, &bytes); err != nil {
+		return 0, 0, fmt.Errorf("failed to get temp file stats: %w", err)
+	}
+
+	return files, bytes, nil
+}
+
+// getLockWaits returns the backends in the current database blocked on a lock, longest
+// wait first. Before PostgreSQL 14 the wait is timed from the backend's last state change,
+// which overstates it when the query ran for a while before blocking.
+func (p *PostgresAdapter) getLockWaits(ctx context.Context) ([]LockWait, error) {
+	defer p.costs.track("lock_waits", time.Now())
+
+	waitStart := "COALESCE(a.state_change, a.query_start, now())"
+	if p.serverVersion >= pgLockWaitStartVersion {
+		waitStart = "COALESCE(l.waitstart, a.state_change, a.query_start, now())"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT pid, usename, query, relation, wait_secs, blocking_pids
+		FROM (
+			SELECT DISTINCT ON (a.pid)
+				a.pid,
+				COALESCE(a.usename, '') as usename,
+				LEFT(COALESCE(a.query, ''), 200) as query,
+				COALESCE(c.relname, '') as relation,
+				EXTRACT(EPOCH FROM (now() - %s))::float8 as wait_secs,
+				pg_blocking_pids(a.pid) as blocking_pids
+			FROM pg_stat_activity a
+			LEFT JOIN pg_locks l ON l.pid = a.pid AND NOT l.granted
+			LEFT JOIN pg_class c ON c.oid = l.relation
+			WHERE a.wait_event_type = 'Lock'
+			AND a.pid != pg_backend_pid()
+			AND a.datname = current_database()
+			ORDER BY a.pid
+		) waits
+		ORDER BY wait_secs DESC
+		LIMIT 10
+	`, waitStart)
+
+	rows, err := p.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query lock waits: %w", err)
+	}
+	defer rows.Close()
+
+	var waits []LockWait
+	for rows.Next() {
+		var w LockWait
+		if err := rows.Scan(&w.PID, &w.Username, &w.Query, &w.Relation, &w.WaitDuration, &w.BlockingPIDs); err != nil {
+			return nil, err
+		}
+		waits = append(waits, w)
+	}
+
+	return waits, rows.Err()
+}
+
+// getWaitingConnections counts backends in the current database blocked on a lock.
+func (p *PostgresAdapter) getWaitingConnections(ctx context.Context) (int32, error) {
+	defer p.costs.track("waiting_connections", time.Now())
+
+	var count int32
+	query := `
+		SELECT count(*)
+		FROM pg_stat_activity
+		WHERE wait_event_type = 'Lock'
+		AND pid != pg_backend_pid()
+		AND datname = current_database()
+	`
+
+	if err := p.pool.QueryRow(ctx, query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count waiting connections: %w", err)
+	}
+
+	return count, nil
+}
+
+// getBackend returns what a backend is doing, or nil if it has gone away.
+func (p *PostgresAdapter) getBackend(ctx context.Context, pid int32) (*Backend, error) {
+	defer p.costs.track("backend", time.Now())
+
+	query := `
+		SELECT pid, COALESCE(usename, ''), COALESCE(application_name, ''), LEFT(COALESCE(query, ''), 200), COALESCE(state, '')
+		FROM pg_stat_activity
+		WHERE pid = $1
+	`
+
+	var b Backend
+	err := p.pool.QueryRow(ctx, query, pid).Scan(&b.PID, &b.Username, &b.ApplicationName, &b.Query, &b.State)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backend %d: %w", pid, err)
+	}
+
+	return &b, nil
+}
@@ -0,0 +1,413 @@
+package adapter
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// maxIndexColumns caps how many columns a recommended index may have.
+const maxIndexColumns = 3
+
+// QueryColumns are the columns of one table that a query filters or sorts on,
+// grouped by how a B-tree index can use them.
+type QueryColumns struct {
+	Equality []string // col = x, col IN (...), col IS NULL, join conditions
+	Range    []string // col > x, col BETWEEN x AND y, col LIKE 'x%'
+	OrderBy  []string
+}
+
+// WeightedQuery is a normalised query with its pg_stat_statements usage.
+type WeightedQuery struct {
+	Query          string
+	Calls          int64
+	MeanExecTimeMs float64
+}
+
+// IndexColumns orders the columns for a B-tree index: equality columns first, then
+// sort columns, then a single range column (nothing after a range column can be used).
+func (q QueryColumns) IndexColumns() []string {
+	var columns []string
+	seen := map[string]bool{}
+
+	add := func(column string) {
+		if !seen[column] && len(columns) < maxIndexColumns {
+			seen[column] = true
+			columns = append(columns, column)
+		}
+	}
+
+	for _, column := range q.Equality {
+		add(column)
+	}
+	for _, column := range q.OrderBy {
+		add(column)
+	}
+	for _, column := range q.Range {
+		if !seen[column] {
+			add(column)
+			break
+		}
+	}
+
+	return columns
+}
+
+// RecommendIndexColumns returns the index column list that would serve the most query
+// time on tableName, weighting each query by calls * mean execution time.
+func RecommendIndexColumns(queries []WeightedQuery, tableName string) []string {
+	weights := map[string]float64{}
+	candidates := map[string][]string{}
+
+	for _, q := range queries {
+		columns := ExtractQueryColumns(q.Query, tableName).IndexColumns()
+		if len(columns) == 0 {
+			continue
+		}
+
+		key := strings.Join(columns, ",")
+		weights[key] += float64(q.Calls) * q.MeanExecTimeMs
+		candidates[key] = columns
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(candidates))
+	for key := range candidates {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if weights[keys[i]] != weights[keys[j]] {
+			return weights[keys[i]] > weights[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	return candidates[keys[0]]
+}
+
+type sqlTokenKind int
+
+const (
+	tokenIdent sqlTokenKind = iota
+	tokenKeyword
+	tokenLiteral // numbers, strings and parameters ($1, ?)
+	tokenOperator
+	tokenPunct
+)
+
+type sqlToken struct {
+	kind  sqlTokenKind
+	value string // identifiers and keywords are lower-cased unless quoted
+}
+
+// sqlKeywords are words that can never be column references.
+var sqlKeywords = map[string]bool{
+	"select": true, "from": true, "where": true, "and": true, "or": true, "not": true,
+	"in": true, "is": true, "null": true, "true": true, "false": true, "like": true,
+	"ilike": true, "between": true, "join": true, "inner": true, "left": true, "right": true,
+	"full": true, "outer": true, "cross": true, "on": true, "using": true, "as": true,
+	"order": true, "group": true, "by": true, "having": true, "limit": true, "offset": true,
+	"asc": true, "desc": true, "nulls": true, "first": true, "last": true, "union": true,
+	"all": true, "distinct": true, "exists": true, "any": true, "some": true, "case": true,
+	"when": true, "then": true, "else": true, "end": true, "returning": true, "for": true,
+	"update": true, "delete": true, "insert": true, "into": true, "values": true, "set": true,
+	"with": true, "lateral": true, "natural": true, "window": true, "fetch": true,
+}
+
+// tokenizeSQL splits a query into tokens, dropping whitespace and comments.
+func tokenizeSQL(query string) []sqlToken {
+	var tokens []sqlToken
+	runes := []rune(query)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i+1 < len(runes) && !(runes[i] == '*' && runes[i+1] == '/') {
+				i++
+			}
+			i += 2
+
+		case r == '\'':
+			i++
+			for i < len(runes) {
+				if runes[i] == '\'' {
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						i += 2
+						continue
+					}
+					break
+				}
+				i++
+			}
+			i++
+			tokens = append(tokens, sqlToken{kind: tokenLiteral, value: "'"})
+
+		case r == '"':
+			start := i + 1
+			i++
+			for i < len(runes) && runes[i] != '"' {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: tokenIdent, value: string(runes[start:min(i, len(runes))])})
+			i++
+
+		case r == '$' || r == '?' || unicode.IsDigit(r):
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: tokenLiteral, value: "?"})
+
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || runes[i] == '$' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			word := strings.ToLower(string(runes[start:i]))
+			kind := tokenIdent
+			if sqlKeywords[word] {
+				kind = tokenKeyword
+			}
+			tokens = append(tokens, sqlToken{kind: kind, value: word})
+
+		case strings.ContainsRune("=<>!", r):
+			start := i
+			for i < len(runes) && strings.ContainsRune("=<>!", runes[i]) {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: tokenOperator, value: string(runes[start:i])})
+
+		default:
+			tokens = append(tokens, sqlToken{kind: tokenPunct, value: string(r)})
+			i++
+		}
+	}
+
+	return tokens
+}
+
+type sqlClause int
+
+const (
+	clauseOther sqlClause = iota
+	clauseFilter
+	clauseOrderBy
+)
+
+// ExtractQueryColumns returns the columns of tableName that the query filters on in
+// WHERE and JOIN ... ON conditions, and sorts on in ORDER BY. Columns qualified with a
+// different table or alias are ignored; unqualified columns are only attributed to
+// tableName when it is the only table in the query.
+func ExtractQueryColumns(query, tableName string) QueryColumns {
+	tokens := tokenizeSQL(query)
+	names, tableCount := tableReferences(tokens, tableName)
+
+	var result QueryColumns
+	if len(names) == 0 {
+		return result
+	}
+
+	ownsColumn := func(qualifier string) bool {
+		if qualifier == "" {
+			return tableCount == 1
+		}
+		return names[qualifier]
+	}
+
+	clause := clauseOther
+	var stack []sqlClause
+
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+
+		if tok.kind == tokenPunct {
+			switch tok.value {
+			case "(":
+				stack = append(stack, clause)
+			case ")":
+				if len(stack) > 0 {
+					clause = stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+				}
+			}
+			continue
+		}
+
+		if tok.kind == tokenKeyword {
+			switch tok.value {
+			case "where", "on":
+				clause = clauseFilter
+			case "order":
+				if tokenAt(tokens, i+1).value == "by" {
+					clause = clauseOrderBy
+					i++
+				}
+			case "select", "from", "join", "group", "having", "limit", "offset", "returning", "union", "window", "fetch", "for":
+				clause = clauseOther
+			}
+			continue
+		}
+
+		if tok.kind != tokenIdent || clause == clauseOther {
+			continue
+		}
+
+		// Type names in casts ($1::text) are not columns
+		if tokenAt(tokens, i-1).value == ":" {
+			continue
+		}
+
+		// Column reference: ident or qualifier.ident
+		qualifier, column, end := "", tok.value, i
+		if tokenAt(tokens, i+1).value == "." && tokenAt(tokens, i+2).kind == tokenIdent {
+			qualifier, column, end = tok.value, tokens[i+2].value, i+2
+		}
+
+		// Function calls (lower(email) = $1) cannot use a plain column index
+		if tokenAt(tokens, end+1).value == "(" {
+			i = end
+			continue
+		}
+
+		if !ownsColumn(qualifier) {
+			i = end
+			continue
+		}
+
+		switch clause {
+		case clauseFilter:
+			switch classifyPredicate(tokens, i, end) {
+			case "equality":
+				result.Equality = appendUnique(result.Equality, column)
+			case "range":
+				result.Range = appendUnique(result.Range, column)
+			}
+		case clauseOrderBy:
+			result.OrderBy = appendUnique(result.OrderBy, column)
+		}
+
+		i = end
+	}
+
+	return result
+}
+
+// tableReferences finds the names (table name and aliases) that refer to tableName in
+// FROM and JOIN clauses, and counts the distinct tables the query reads.
+func tableReferences(tokens []sqlToken, tableName string) (map[string]bool, int) {
+	target := strings.ToLower(tableName)
+	names := map[string]bool{}
+	tables := map[string]bool{}
+
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].kind != tokenKeyword || (tokens[i].value != "from" && tokens[i].value != "join") {
+			continue
+		}
+
+		// FROM a, b, c - walk the comma-separated list
+		for j := i + 1; j < len(tokens); {
+			if tokens[j].kind != tokenIdent {
+				break
+			}
+
+			name, end := tokens[j].value, j
+			if tokenAt(tokens, j+1).value == "." && tokenAt(tokens, j+2).kind == tokenIdent {
+				name, end = tokens[j+2].value, j+2
+			}
+			tables[name] = true
+
+			alias := ""
+			k := end + 1
+			if tokenAt(tokens, k).value == "as" {
+				k++
+			}
+			if tokenAt(tokens, k).kind == tokenIdent {
+				alias = tokens[k].value
+				end = k
+			}
+
+			if strings.ToLower(name) == target {
+				names[name] = true
+				if alias != "" {
+					names[alias] = true
+				}
+			}
+
+			if tokens[i].value == "join" || tokenAt(tokens, end+1).value != "," {
+				break
+			}
+			j = end + 2
+		}
+	}
+
+	return names, len(tables)
+}
+
+// classifyPredicate looks at the operator around the column at tokens[start:end+1].
+func classifyPredicate(tokens []sqlToken, start, end int) string {
+	after := tokenAt(tokens, end+1)
+	if after.kind == tokenKeyword && after.value == "not" {
+		after = tokenAt(tokens, end+2)
+	}
+
+	if op := classifyOperator(after); op != "" {
+		return op
+	}
+
+	// Reversed comparisons: $1 = col, 10 < col
+	if start > 0 {
+		return classifyOperator(tokens[start-1])
+	}
+
+	return ""
+}
+
+func classifyOperator(tok sqlToken) string {
+	switch tok.kind {
+	case tokenOperator:
+		switch tok.value {
+		case "=":
+			return "equality"
+		case "<", ">", "<=", ">=":
+			return "range"
+		}
+	case tokenKeyword:
+		switch tok.value {
+		case "in", "is":
+			return "equality"
+		case "between", "like", "ilike":
+			return "range"
+		}
+	}
+	return ""
+}
+
+func tokenAt(tokens []sqlToken, i int) sqlToken {
+	if i < 0 || i >= len(tokens) {
+		return sqlToken{kind: tokenPunct}
+	}
+	return tokens[i]
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,62 @@
+package adapter
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// QueryCost is how long one of an adapter's metric queries took during a collection.
+// Queries run once per table are summed under one name.
+type QueryCost struct {
+	Query    string
+	Duration time.Duration
+	Calls    int
+}
+
+// queryCosts records the cost of each metric query during one collection, so the
+// Collector can see the load it puts on the database.
+type queryCosts struct {
+	mu    sync.Mutex
+	costs map[string]*QueryCost
+}
+
+// track adds the time since start to query's cost. Call it deferred at the top of each
+// metric query: defer p.costs.track("active_connections", time.Now()).
+func (q *queryCosts) track(query string, start time.Time) {
+	elapsed := time.Since(start)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.costs == nil {
+		q.costs = make(map[string]*QueryCost)
+	}
+	cost, ok := q.costs[query]
+	if !ok {
+		cost = &QueryCost{Query: query}
+		q.costs[query] = cost
+	}
+	cost.Duration += elapsed
+	cost.Calls++
+}
+
+// take returns the costs recorded since the last take, most expensive first, and resets them.
+func (q *queryCosts) take() []QueryCost {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	costs := make([]QueryCost, 0, len(q.costs))
+	for _, cost := range q.costs {
+		costs = append(costs, *cost)
+	}
+	q.costs = nil
+
+	sort.Slice(costs, func(i, j int) bool {
+		if costs[i].Duration != costs[j].Duration {
+			return costs[i].Duration > costs[j].Duration
+		}
+		return costs[i].Query < costs[j].Query
+	})
+	return costs
+}
@@ -0,0 +1,292 @@
+// Package adapter provides database-specific metric collection implementations.
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteAdapter implements MetricAdapter for SQLite database files.
+//
+// SQLite runs inside the application, so there is no server to ask about connections and
+// the per-statement counters SQLite keeps (sqlite3_stmt_status) belong to the process that
+// prepared the statements. The adapter reads what the file itself records: its page
+// layout, cache and journal settings, and which tables have no index. Connection and
+// sequential scan metrics are left unset so they aren't scored.
+type SQLiteAdapter struct {
+	connectionString string
+	databaseID       string
+	path             string
+	db               *sql.DB
+	costs            queryCosts
+}
+
+// No connection pool and no cache or scan counters readable from outside the application
+func init() {
+	RegisterDriver(Driver{
+		Name:         "sqlite",
+		Aliases:      []string{"sqlite3"},
+		Capabilities: Capabilities{Storage: true},
+		New: func(connectionString, databaseID string) MetricAdapter {
+			return NewSQLiteAdapter(connectionString, databaseID)
+		},
+	})
+}
+
+// NewSQLiteAdapter creates a new SQLite adapter. The connection string is a file path,
+// optionally written as sqlite:///path/to/app.db or file:path/to/app.db.
+func NewSQLiteAdapter(connectionString string, databaseID string) *SQLiteAdapter {
+	return &SQLiteAdapter{
+		connectionString: connectionString,
+		databaseID:       databaseID,
+		path:             sqlitePath(connectionString),
+	}
+}
+
+// sqlitePath strips the URL scheme and query parameters from a SQLite connection string.
+func sqlitePath(connStr string) string {
+	switch {
+	case strings.HasPrefix(connStr, "sqlite://"):
+		connStr = strings.TrimPrefix(connStr, "sqlite://")
+	case strings.HasPrefix(connStr, "sqlite:"):
+		connStr = strings.TrimPrefix(connStr, "sqlite:")
+	case strings.HasPrefix(connStr, "file:"):
+		connStr = strings.TrimPrefix(connStr, "file:")
+	}
+	if qIdx := strings.Index(connStr, "?"); qIdx != -1 {
+		connStr = connStr[:qIdx]
+	}
+	return connStr
+}
+
+// Connect opens the database file read-only. A missing file is an error rather than a new
+// empty database.
+func (s *SQLiteAdapter) Connect() error {
+	if s.path == "" {
+		return fmt.Errorf("SQLite connection string must be a database file path")
+	}
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("failed to open SQLite database: %s is a directory", s.path)
+	}
+
+	// Read-only, and waiting out the application's write locks rather than failing
+	dsn := fmt.Sprintf("file:%s?mode=ro&_pragma=busy_timeout(5000)", s.path)
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Reading the schema fails on a file that isn't a SQLite database
+	var schemaVersion int64
+	if err := db.QueryRowContext(ctx, "PRAGMA schema_version").Scan(&schemaVersion); err != nil {
+		db.Close()
+		return fmt.Errorf("failed to read SQLite database %s: %w", s.path, err)
+	}
+
+	s.db = db
+	return nil
+}
+
+// CollectMetrics gathers metrics from the SQLite database file.
+func (s *SQLiteAdapter) CollectMetrics() (*RawMetrics, error) {
+	if s.db == nil {
+		return nil, ErrNotConnected
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	metrics := NewRawMetrics(s.databaseID, "sqlite")
+	defer func() { metrics.QueryCosts = s.costs.take() }()
+
+	// Storage metrics: the file is page_count pages, freelist_count of them unused
+	pageSize, err := s.pragmaInt(ctx, "page_size")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page size: %w", err)
+	}
+	pageCount, err := s.pragmaInt(ctx, "page_count")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page count: %w", err)
+	}
+	freelistCount, err := s.pragmaInt(ctx, "freelist_count")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read freelist count: %w", err)
+	}
+
+	usedBytes := pageCount * pageSize
+	metrics.Storage = &StorageMetrics{
+		UsedSizeBytes: &usedBytes,
+	}
+	metrics.ExtendedMetrics["sqlite.page_size"] = float64(pageSize)
+	metrics.ExtendedMetrics["sqlite.page_count"] = float64(pageCount)
+	metrics.ExtendedMetrics["sqlite.freelist_count"] = float64(freelistCount)
+	metrics.ExtendedMetrics["sqlite.database_size_mb"] = float64(usedBytes) / (1024 * 1024)
+	if pageCount > 0 {
+		// Pages VACUUM would reclaim
+		metrics.ExtendedMetrics["sqlite.freelist_ratio"] = float64(freelistCount) / float64(pageCount)
+	}
+
+	// Cache metrics: cache_size is in pages when positive, in KiB when negative. SQLite
+	// keeps no hit counters across connections, so only the size is known.
+	cacheSize, err := s.pragmaInt(ctx, "cache_size")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache size: %w", err)
+	}
+	cacheBytes := cacheSize * pageSize
+	if cacheSize < 0 {
+		cacheBytes = -cacheSize * 1024
+	}
+	metrics.Cache = &CacheMetrics{
+		CacheSizeBytes: &cacheBytes,
+	}
+	metrics.ExtendedMetrics["sqlite.cache_size_bytes"] = float64(cacheBytes)
+
+	var journalMode string
+	if err := s.db.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&journalMode); err == nil {
+		metrics.Labels["sqlite.journal_mode"] = journalMode
+	}
+
+	// Tables without an index are scanned in full by every filtered query
+	if err := s.collectUnindexedTables(ctx, metrics); err != nil {
+		return nil, fmt.Errorf("failed to read table indexes: %w", err)
+	}
+
+	return metrics, nil
+}
+
+// pragmaInt reads an integer-valued PRAGMA.
+func (s *SQLiteAdapter) pragmaInt(ctx context.Context, name string) (int64, error) {
+	defer s.costs.track(name, time.Now())
+
+	var value int64
+	err := s.db.QueryRowContext(ctx, "PRAGMA "+name).Scan(&value)
+	return value, err
+}
+
+// SQLiteTableStat describes a table with no index of its own.
+type SQLiteTableStat struct {
+	TableName string
+	Rows      int64 // From sqlite_stat1, or -1 if ANALYZE hasn't run
+}
+
+// collectUnindexedTables reports the tables with no index, largest first. Row counts come
+// from sqlite_stat1 when ANALYZE has run; counting rows directly would scan every table.
+func (s *SQLiteAdapter) collectUnindexedTables(ctx context.Context, metrics *RawMetrics) error {
+	defer s.costs.track("unindexed_tables", time.Now())
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT m.name
+		FROM sqlite_master m
+		WHERE m.type = 'table'
+		AND m.name NOT LIKE 'sqlite_%'
+		AND NOT EXISTS (
+			SELECT 1 FROM sqlite_master i
+			WHERE i.type = 'index' AND i.tbl_name = m.name
+		)
+		ORDER BY m.name
+	`)
+	if err != nil {
+		return err
+	}
+
+	var tables []SQLiteTableStat
+	for rows.Next() {
+		var table SQLiteTableStat
+		if err := rows.Scan(&table.TableName); err != nil {
+			rows.Close()
+			return err
+		}
+		table.Rows = -1
+		tables = append(tables, table)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range tables {
+		if n, ok := s.analysedRowCount(ctx, tables[i].TableName); ok {
+			tables[i].Rows = n
+		}
+	}
+
+	metrics.ExtendedMetrics["sqlite.unindexed_table_count"] = float64(len(tables))
+
+	var largest *SQLiteTableStat
+	for i, table := range tables {
+		if table.Rows >= 0 {
+			metrics.ExtendedMetrics[fmt.Sprintf("sqlite.table.%s.rows", table.TableName)] = float64(table.Rows)
+		}
+		if largest == nil || table.Rows > largest.Rows {
+			largest = &tables[i]
+		}
+	}
+	if largest != nil {
+		metrics.Labels["sqlite.largest_unindexed_table"] = largest.TableName
+	}
+
+	return nil
+}
+
+// analysedRowCount reads a table's row count from sqlite_stat1, which only exists once
+// ANALYZE has run.
+func (s *SQLiteAdapter) analysedRowCount(ctx context.Context, table string) (int64, bool) {
+	var stat string
+	err := s.db.QueryRowContext(ctx, "SELECT stat FROM sqlite_stat1 WHERE tbl = ? AND idx IS NULL", table).Scan(&stat)
+	if err != nil {
+		return 0, false
+	}
+
+	var n int64
+	if _, err := fmt.Sscan(stat, &n); err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// Close closes the database file.
+func (s *SQLiteAdapter) Close() error {
+	if s.db != nil {
+		err := s.db.Close()
+		s.db = nil
+		return err
+	}
+	return nil
+}
+
+// HealthCheck verifies the database file is still readable.
+func (s *SQLiteAdapter) HealthCheck() error {
+	if s.db == nil {
+		return ErrNotConnected
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var schemaVersion int64
+	if err := s.db.QueryRowContext(ctx, "PRAGMA schema_version").Scan(&schemaVersion); err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+
+	return nil
+}
+
+// GetUnavailableFeatures returns a list of features that are not available.
+func (s *SQLiteAdapter) GetUnavailableFeatures() []string {
+	return []string{"connection_metrics", "query_statistics", "cache_hit_rate"}
+}
@@ -2,30 +2,78 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/logging"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/transport"
 	"github.com/joho/godotenv"
 )
 
 // Config holds bootstrap configuration for the Collector service.
-// Database connections are managed dynamically via Knowledge service.
+// Database connections are managed dynamically via Knowledge service;
+// databases listed in DATABASES are registered with Knowledge on startup.
 type Config struct {
 	// Service addresses
 	AnalyserAddress  string
 	NatsURL          string
 	KnowledgeAddress string
+	HealthPort       string
+
+	// Mutual TLS for gRPC links (GRPC_TLS_*); plaintext when unset
+	GRPCTLS transport.TLSConfig
+
+	// Log verbosity (LOG_LEVEL: debug, info, warn, error)
+	LogLevel slog.Level
 
 	// Operational settings
 	CollectionInterval time.Duration
 	SyncInterval       time.Duration // How often to check for database changes
+	AnalyserBufferSize int           // Unacknowledged snapshots held for resending to the Analyser
+	SpoolPath          string        // File snapshots are kept in while the Analyser is unreachable; empty disables
+	SpoolSize          int           // Most snapshots the spool holds before dropping the oldest
+	IndexCandidates    int           // Most sequentially scanned tables given index recommendations per cycle
+	DiskUsageQuery     string        // Returns the data disk's total and free bytes (PostgreSQL); empty disables
+
+	// Adaptive collection: a database whose HealthScore drops below CollectionBackoffHealth,
+	// or whose metric queries take longer than CollectionBudget, is collected less often,
+	// up to every CollectionMaxInterval, until it recovers
+	CollectionMaxInterval   time.Duration // at COLLECTION_INTERVAL, the interval never stretches
+	CollectionBackoffHealth float64       // 0 disables the health trigger
+	CollectionBudget        time.Duration // 0 disables the duration trigger
+
+	// Host metrics (HOST_METRICS_SOURCE: none, proc or node_exporter), applied to every database
+	HostMetricsSource string
+	HostProcPath      string // /proc to read for the proc source
+	NodeExporterURL   string // node_exporter metrics endpoint for the node_exporter source
+	HostDataPath      string // Database data directory; its filesystem is reported as the disk
+
+	// Statically configured databases (registered with Knowledge on startup)
+	Databases []DatabaseConfig
 
 	// Feature flags
 	EnableMetricsPublishing bool
 }
 
+// DatabaseConfig describes a single database the Collector should monitor.
+type DatabaseConfig struct {
+	ID               string `json:"id"`
+	Type             string `json:"type"`
+	ConnectionString string `json:"connection_string"`
+	Name             string `json:"name"`
+	Container        string `json:"container,omitempty"` // Docker container the database runs in, if any
+}
+
+// defaultCollectionMaxInterval is how far a database's collection interval stretches under
+// load when COLLECTION_MAX_INTERVAL is unset, unless COLLECTION_INTERVAL is already longer.
+const defaultCollectionMaxInterval = 60 * time.Second
+
 // Load loads configuration from environment variables.
 func Load() (*Config, error) {
 	envPaths := []string{
@@ -51,6 +99,13 @@ func Load() (*Config, error) {
 		AnalyserAddress:         getEnvOrDefault("ANALYSER_ADDRESS", "localhost:50051"),
 		NatsURL:                 getEnvOrDefault("NATS_URL", "nats://localhost:4222"),
 		KnowledgeAddress:        getEnvOrDefault("KNOWLEDGE_ADDRESS", "localhost:50053"),
+		HealthPort:              getEnvOrDefault("HEALTH_PORT", "8080"),
+		SpoolPath:               os.Getenv("SNAPSHOT_SPOOL_PATH"),
+		HostMetricsSource:       getEnvOrDefault("HOST_METRICS_SOURCE", "none"),
+		HostProcPath:            getEnvOrDefault("HOST_PROC_PATH", "/proc"),
+		NodeExporterURL:         os.Getenv("NODE_EXPORTER_URL"),
+		HostDataPath:            getEnvOrDefault("HOST_DATA_PATH", "/"),
+		GRPCTLS:                 transport.TLSConfigFromEnv(),
 		EnableMetricsPublishing: getEnvOrDefault("ENABLE_METRICS_PUBLISHING", "true") == "true",
 	}
 
@@ -62,6 +117,28 @@ func Load() (*Config, error) {
 	}
 	config.CollectionInterval = interval
 
+	// Parse adaptive collection settings
+	config.CollectionMaxInterval = max(defaultCollectionMaxInterval, interval)
+	if maxStr := os.Getenv("COLLECTION_MAX_INTERVAL"); maxStr != "" {
+		maxInterval, err := time.ParseDuration(maxStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid COLLECTION_MAX_INTERVAL: %w", err)
+		}
+		config.CollectionMaxInterval = maxInterval
+	}
+
+	backoffHealth, err := strconv.ParseFloat(getEnvOrDefault("COLLECTION_BACKOFF_HEALTH", "0.5"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid COLLECTION_BACKOFF_HEALTH: %w", err)
+	}
+	config.CollectionBackoffHealth = backoffHealth
+
+	budget, err := time.ParseDuration(getEnvOrDefault("COLLECTION_BUDGET", "2s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid COLLECTION_BUDGET: %w", err)
+	}
+	config.CollectionBudget = budget
+
 	// Parse sync interval (how often to check for new/removed databases)
 	syncStr := getEnvOrDefault("SYNC_INTERVAL", "30s")
 	syncInterval, err := time.ParseDuration(syncStr)
@@ -70,6 +147,45 @@ func Load() (*Config, error) {
 	}
 	config.SyncInterval = syncInterval
 
+	// Parse Analyser buffer size (unacknowledged snapshots kept for resending)
+	bufferSize, err := strconv.Atoi(getEnvOrDefault("ANALYSER_BUFFER_SIZE", "100"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ANALYSER_BUFFER_SIZE: %w", err)
+	}
+	config.AnalyserBufferSize = bufferSize
+
+	// Parse spool size (snapshots kept on disk while the Analyser is unreachable)
+	spoolSize, err := strconv.Atoi(getEnvOrDefault("SNAPSHOT_SPOOL_SIZE", "500"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SNAPSHOT_SPOOL_SIZE: %w", err)
+	}
+	config.SpoolSize = spoolSize
+
+	// Parse how many tables get index recommendations per cycle
+	indexCandidates, err := strconv.Atoi(getEnvOrDefault("INDEX_CANDIDATE_TABLES", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid INDEX_CANDIDATE_TABLES: %w", err)
+	}
+	config.IndexCandidates = indexCandidates
+
+	config.DiskUsageQuery = os.Getenv("PG_DISK_USAGE_QUERY")
+
+	// Parse statically configured databases
+	databases, err := parseDatabases(os.Getenv("DATABASES"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DATABASES: %w", err)
+	}
+	if len(databases) == 0 {
+		databases = legacyDatabase()
+	}
+	config.Databases = databases
+
+	logLevel, err := logging.ParseLevel(os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		return nil, err
+	}
+	config.LogLevel = logLevel
+
 	if err := config.Validate(); err != nil {
 		return nil, err
 	}
@@ -79,6 +195,10 @@ func Load() (*Config, error) {
 
 // Validate checks that required configuration is present.
 func (c *Config) Validate() error {
+	if err := c.GRPCTLS.Validate(); err != nil {
+		return err
+	}
+
 	if c.AnalyserAddress == "" {
 		return fmt.Errorf("ANALYSER_ADDRESS is required")
 	}
@@ -91,13 +211,138 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("COLLECTION_INTERVAL must be at least 1 second")
 	}
 
+	// Unset (zero) leaves the interval fixed
+	if c.CollectionMaxInterval != 0 && c.CollectionMaxInterval < c.CollectionInterval {
+		return fmt.Errorf("COLLECTION_MAX_INTERVAL must not be shorter than COLLECTION_INTERVAL")
+	}
+
+	if c.CollectionBackoffHealth < 0 || c.CollectionBackoffHealth > 1 {
+		return fmt.Errorf("COLLECTION_BACKOFF_HEALTH must be between 0 and 1")
+	}
+
+	if c.CollectionBudget < 0 {
+		return fmt.Errorf("COLLECTION_BUDGET must not be negative")
+	}
+
 	if c.SyncInterval < 5*time.Second {
 		return fmt.Errorf("SYNC_INTERVAL must be at least 5 seconds")
 	}
 
+	if c.AnalyserBufferSize < 0 {
+		return fmt.Errorf("ANALYSER_BUFFER_SIZE must be 0 or greater")
+	}
+
+	if c.SpoolPath != "" && c.SpoolSize < 1 {
+		return fmt.Errorf("SNAPSHOT_SPOOL_SIZE must be at least 1")
+	}
+
+	if c.IndexCandidates < 1 {
+		return fmt.Errorf("INDEX_CANDIDATE_TABLES must be at least 1")
+	}
+
+	switch c.HostMetricsSource {
+	case "", "none", "proc":
+	case "node_exporter":
+		if c.NodeExporterURL == "" {
+			return fmt.Errorf("NODE_EXPORTER_URL is required when HOST_METRICS_SOURCE is node_exporter")
+		}
+	default:
+		return fmt.Errorf("HOST_METRICS_SOURCE must be none, proc or node_exporter")
+	}
+
+	seen := make(map[string]bool)
+	for i, db := range c.Databases {
+		if db.ID == "" {
+			return fmt.Errorf("DATABASES entry %d is missing an id", i)
+		}
+		if db.Type == "" {
+			return fmt.Errorf("DATABASES entry %s is missing an adapter type", db.ID)
+		}
+		if db.ConnectionString == "" {
+			return fmt.Errorf("DATABASES entry %s is missing a connection string", db.ID)
+		}
+		if seen[db.ID] {
+			return fmt.Errorf("DATABASES contains duplicate id: %s", db.ID)
+		}
+		seen[db.ID] = true
+	}
+
 	return nil
 }
 
+// parseDatabases parses the DATABASES environment variable.
+//
+// Two formats are accepted:
+//   - JSON array: [{"id":"orders","type":"postgres","connection_string":"postgres://...","name":"Orders","container":"orders-db"}]
+//   - Comma-separated entries of id|type|connection_string|name|container (name and container are optional)
+func parseDatabases(value string) ([]DatabaseConfig, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	var databases []DatabaseConfig
+
+	if strings.HasPrefix(value, "[") {
+		if err := json.Unmarshal([]byte(value), &databases); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+	} else {
+		for _, entry := range strings.Split(value, ",") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+
+			parts := strings.Split(entry, "|")
+			if len(parts) < 3 || len(parts) > 5 {
+				return nil, fmt.Errorf("entry %q must be id|type|connection_string[|name[|container]]", entry)
+			}
+
+			db := DatabaseConfig{
+				ID:               strings.TrimSpace(parts[0]),
+				Type:             strings.TrimSpace(parts[1]),
+				ConnectionString: strings.TrimSpace(parts[2]),
+			}
+			if len(parts) >= 4 {
+				db.Name = strings.TrimSpace(parts[3])
+			}
+			if len(parts) == 5 {
+				db.Container = strings.TrimSpace(parts[4])
+			}
+
+			databases = append(databases, db)
+		}
+	}
+
+	for i := range databases {
+		if databases[i].Name == "" {
+			databases[i].Name = databases[i].ID
+		}
+	}
+
+	return databases, nil
+}
+
+// legacyDatabase builds a single database entry from the older
+// DB_CONNECTION_STRING / DB_ADAPTER / DATABASE_ID / DATABASE_NAME / DB_CONTAINER variables.
+func legacyDatabase() []DatabaseConfig {
+	connStr := os.Getenv("DB_CONNECTION_STRING")
+	if connStr == "" {
+		return nil
+	}
+
+	id := getEnvOrDefault("DATABASE_ID", "default")
+
+	return []DatabaseConfig{{
+		ID:               id,
+		Type:             getEnvOrDefault("DB_ADAPTER", "postgres"),
+		ConnectionString: connStr,
+		Name:             getEnvOrDefault("DATABASE_NAME", id),
+		Container:        os.Getenv("DB_CONTAINER"),
+	}}
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -0,0 +1,128 @@
+// Package deployment works out where a monitored database runs, so actions that depend
+// on it (restarting a container, addressing the database from a new container) do not
+// have to guess from the connection string.
+package deployment
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"strings"
+)
+
+// Deployment contexts.
+const (
+	KindDocker = "docker" // database runs in a Docker container
+	KindLocal  = "local"  // database is on the Collector's own host
+	KindRemote = "remote" // database is elsewhere on the network
+	KindCloud  = "cloud"  // database is a managed cloud service
+)
+
+// Knowledge database metadata keys the context is registered under.
+const (
+	MetadataContext   = "deployment_context"
+	MetadataContainer = "docker_container"
+	MetadataProvider  = "cloud_provider"
+)
+
+// cloudSuffixes maps managed database endpoint suffixes to their provider.
+var cloudSuffixes = []struct {
+	suffix   string
+	provider string
+}{
+	{".rds.amazonaws.com", "aws-rds"},
+	{".database.azure.com", "azure"},
+	{".database.windows.net", "azure"},
+	{".cloudsql.goog", "gcp-cloudsql"},
+	{".supabase.co", "supabase"},
+	{".supabase.com", "supabase"},
+	{".neon.tech", "neon"},
+	{".db.ondigitalocean.com", "digitalocean"},
+	{".aivencloud.com", "aiven"},
+	{".render.com", "render"},
+	{".mongodb.net", "mongodb-atlas"},
+	{".psdb.cloud", "planetscale"},
+}
+
+// Context describes where a database runs.
+type Context struct {
+	Kind      string
+	Container string // Docker container name, when Kind is docker and the container is known
+	Provider  string // managed service, when Kind is cloud
+}
+
+// Metadata renders the context as Knowledge database metadata.
+func (c Context) Metadata() map[string]string {
+	metadata := map[string]string{MetadataContext: c.Kind}
+	if c.Container != "" {
+		metadata[MetadataContainer] = c.Container
+	}
+	if c.Provider != "" {
+		metadata[MetadataProvider] = c.Provider
+	}
+	return metadata
+}
+
+// ContainerLookup finds the running Docker container a host refers to.
+type ContainerLookup interface {
+	// FindContainer returns the container name, or "" if host is not a known container.
+	FindContainer(ctx context.Context, host string) (string, error)
+}
+
+// Detector classifies database hosts. The Docker lookup is optional.
+type Detector struct {
+	lookup ContainerLookup
+}
+
+// NewDetector creates a detector. A nil lookup skips asking Docker.
+func NewDetector(lookup ContainerLookup) *Detector {
+	return &Detector{lookup: lookup}
+}
+
+// Detect classifies host. A container named in config is trusted over detection.
+func (d *Detector) Detect(ctx context.Context, host, configuredContainer string) Context {
+	if configuredContainer != "" {
+		return Context{Kind: KindDocker, Container: configuredContainer}
+	}
+
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	if isLoopback(host) {
+		return Context{Kind: KindLocal}
+	}
+
+	for _, cloud := range cloudSuffixes {
+		if strings.HasSuffix(host, cloud.suffix) {
+			return Context{Kind: KindCloud, Provider: cloud.provider}
+		}
+	}
+
+	if d.lookup != nil {
+		name, err := d.lookup.FindContainer(ctx, host)
+		if err != nil {
+			slog.Warn("Docker lookup failed", "host", host, "error", err)
+		} else if name != "" {
+			return Context{Kind: KindDocker, Container: name}
+		}
+	}
+
+	// Addresses that are not a container's, private or not, are another machine
+	if ip := net.ParseIP(host); ip != nil {
+		return Context{Kind: KindRemote}
+	}
+
+	// A bare name like "postgres" only resolves on a Docker network
+	if !strings.Contains(host, ".") {
+		return Context{Kind: KindDocker}
+	}
+
+	return Context{Kind: KindRemote}
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
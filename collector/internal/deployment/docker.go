@@ -0,0 +1,117 @@
+package deployment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const defaultDockerSocket = "/var/run/docker.sock"
+
+// DockerLookup finds containers through the Docker Engine API on a unix socket.
+type DockerLookup struct {
+	client *http.Client
+}
+
+// NewDockerLookup returns a lookup against the socket in DOCKER_HOST (or the default
+// socket), or nil if no socket is available - Docker is optional for the Collector.
+func NewDockerLookup() *DockerLookup {
+	socket := defaultDockerSocket
+	if dockerHost := os.Getenv("DOCKER_HOST"); dockerHost != "" {
+		if !strings.HasPrefix(dockerHost, "unix://") {
+			return nil
+		}
+		socket = strings.TrimPrefix(dockerHost, "unix://")
+	}
+
+	if _, err := os.Stat(socket); err != nil {
+		return nil
+	}
+
+	return &DockerLookup{
+		client: &http.Client{
+			Timeout: 5 * time.Second,
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var dialer net.Dialer
+					return dialer.DialContext(ctx, "unix", socket)
+				},
+			},
+		},
+	}
+}
+
+// dockerContainer is the subset of GET /containers/json the lookup reads.
+type dockerContainer struct {
+	Names           []string `json:"Names"`
+	NetworkSettings struct {
+		Networks map[string]struct {
+			IPAddress string   `json:"IPAddress"`
+			Aliases   []string `json:"Aliases"`
+			DNSNames  []string `json:"DNSNames"`
+		} `json:"Networks"`
+	} `json:"NetworkSettings"`
+}
+
+// FindContainer matches host against running containers' names, network aliases and IPs.
+func (l *DockerLookup) FindContainer(ctx context.Context, host string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://docker/containers/json", nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to list containers: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to list containers: status %d", resp.StatusCode)
+	}
+
+	var containers []dockerContainer
+	if err := json.NewDecoder(resp.Body).Decode(&containers); err != nil {
+		return "", fmt.Errorf("failed to decode container list: %w", err)
+	}
+
+	return matchContainer(containers, host), nil
+}
+
+// matchContainer returns the name of the container host refers to, or "".
+func matchContainer(containers []dockerContainer, host string) string {
+	for _, ctr := range containers {
+		if len(ctr.Names) == 0 {
+			continue
+		}
+		name := strings.TrimPrefix(ctr.Names[0], "/")
+
+		for _, n := range ctr.Names {
+			if strings.TrimPrefix(n, "/") == host {
+				return name
+			}
+		}
+
+		for _, endpoint := range ctr.NetworkSettings.Networks {
+			if endpoint.IPAddress == host || contains(endpoint.Aliases, host) || contains(endpoint.DNSNames, host) {
+				return name
+			}
+		}
+	}
+
+	return ""
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
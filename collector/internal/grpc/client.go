@@ -3,76 +3,479 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"log/slog"
+	"sync"
+	"time"
 
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/metrics"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/spool"
 	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/transport"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
-// MetricsClient handles streaming metrics to the Analyser service.
+const (
+	// DefaultBufferSize is how many unacknowledged snapshots are held for resending.
+	DefaultBufferSize = 100
+
+	initialReconnectBackoff = 500 * time.Millisecond
+	defaultMaxBackoff       = 30 * time.Second
+
+	// closeAckTimeout bounds how long Close waits for the Analyser's final ack
+	closeAckTimeout = 2 * time.Second
+)
+
+// ErrSnapshotsBuffered is returned when the Analyser is unavailable and the snapshots were
+// kept in memory to be resent once the connection is re-established.
+var ErrSnapshotsBuffered = errors.New("analyser unavailable, snapshots buffered")
+
+// StreamStats describes the state of the link to the Analyser.
+type StreamStats struct {
+	Connected         bool      `json:"connected"`
+	BufferedSnapshots int       `json:"buffered_snapshots"` // sent or waiting, not yet acknowledged
+	DroppedSnapshots  int64     `json:"dropped_snapshots"`
+	RejectedSnapshots int64     `json:"rejected_snapshots"` // acknowledged but dropped by the Analyser
+	Reconnects        int64     `json:"reconnects"`
+	SpooledSnapshots  int       `json:"spooled_snapshots"` // on disk, waiting to be replayed
+	LastAckedSequence uint64    `json:"last_acked_sequence"`
+	LastAckAt         time.Time `json:"last_ack_at,omitzero"`
+	LastError         string    `json:"last_error,omitempty"`
+	LastErrorAt       time.Time `json:"last_error_at,omitzero"`
+}
+
+// MetricsClient streams metrics to the Analyser over one long-lived stream. Snapshots are
+// kept until the Analyser acknowledges them, so when the stream breaks they are resent on
+// the next one. Reconnects use exponential backoff.
+//
+// With a spool, snapshots are written to disk instead while the stream is down and replayed
+// in order, marked as replayed, once it is back. Until the spool is empty new snapshots
+// queue behind it, so the Analyser still receives every database's snapshots in order.
 type MetricsClient struct {
 	analyserAddress string
+	tlsConfig       transport.TLSConfig
 	conn            *grpc.ClientConn
 	client          pb.MetricsServiceClient
+
+	mu           sync.Mutex
+	bufferSize   int
+	maxBackoff   time.Duration
+	stream       pb.MetricsService_StreamMetricsClient
+	streamCancel context.CancelFunc
+	streamDone   chan struct{} // closed when the stream's ack receiver exits
+	hadStream    bool
+	pending      []*pb.MetricSnapshot // unacknowledged, in sequence order
+	spool        *spool.Spool         // nil keeps unacknowledged snapshots in memory only
+	replaying    int                  // spooled snapshots sent on the current stream, oldest first
+	nextSequence uint64
+	backoff      time.Duration
+	nextAttempt  time.Time
+	dropped      int64
+	rejected     int64
+	reconnects   int64
+	lastAcked    uint64
+	lastAckAt    time.Time
+	lastError    string
+	lastErrorAt  time.Time
 }
 
 // NewMetricsClient creates a new MetricsClient for the given Analyser address.
-func NewMetricsClient(analyserAddress string) *MetricsClient {
+func NewMetricsClient(analyserAddress string, tlsConfig transport.TLSConfig) *MetricsClient {
 	return &MetricsClient{
 		analyserAddress: analyserAddress,
+		tlsConfig:       tlsConfig,
+		bufferSize:      DefaultBufferSize,
+		maxBackoff:      defaultMaxBackoff,
 	}
 }
 
-// Connect establishes a gRPC connection to the Analyser service.
+// SetBufferSize sets how many unacknowledged snapshots are kept; the oldest are dropped
+// first once the buffer is full.
+func (c *MetricsClient) SetBufferSize(size int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.bufferSize = size
+}
+
+// SetMaxBackoff caps the delay between reconnect attempts. The Collector uses its
+// collection interval, so a reconnect is tried at least once per cycle.
+func (c *MetricsClient) SetMaxBackoff(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxBackoff = d
+}
+
+// SetSpool keeps snapshots on disk while the Analyser is unreachable, to be replayed once it
+// is back. Set it before Connect.
+func (c *MetricsClient) SetSpool(s *spool.Spool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.spool = s
+}
+
+// Connect creates the gRPC connection and opens the metrics stream. An unreachable
+// Analyser is not an error: the stream is retried on the next Send.
 func (c *MetricsClient) Connect() error {
 	if c.analyserAddress == "" {
 		return fmt.Errorf("analyser address cannot be empty")
 	}
 
-	conn, err := grpc.NewClient(
-		c.analyserAddress,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.dial(); err != nil {
+		return err
+	}
+
+	if err := c.openStreamLocked(); err != nil {
+		log.Printf("Analyser not reachable yet, will retry: %v", err)
+		c.scheduleRetryLocked(err)
+		return nil
+	}
+
+	log.Printf("Connected to Analyser: %s", c.analyserAddress)
+	return nil
+}
+
+func (c *MetricsClient) dial() error {
+	credentials, err := c.tlsConfig.DialOption()
+	if err != nil {
+		return err
+	}
+
+	conn, err := grpc.NewClient(c.analyserAddress, credentials)
 	if err != nil {
 		return fmt.Errorf("failed to create client: %w", err)
 	}
 
 	c.conn = conn
 	c.client = pb.NewMetricsServiceClient(conn)
-
-	log.Printf("Connected to Analyser: %s", c.analyserAddress)
 	return nil
 }
 
-// StreamMetrics sends a batch of metric snapshots to the Analyser.
-func (c *MetricsClient) StreamMetrics(ctx context.Context, metrics []*pb.MetricSnapshot) (*pb.MetricsAck, error) {
+// Send queues a snapshot and writes it to the stream. Acknowledgements arrive
+// asynchronously; until then the snapshot is held for resending. If the stream is down the
+// snapshot is buffered and ErrSnapshotsBuffered is returned.
+func (c *MetricsClient) Send(snapshot *pb.MetricSnapshot) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.client == nil {
-		return nil, fmt.Errorf("client not connected")
+		return fmt.Errorf("client not connected")
 	}
 
+	if c.spool != nil && (c.stream == nil || c.spool.Len() > 0) {
+		return c.sendSpooledLocked(snapshot)
+	}
+
+	c.nextSequence++
+	snapshot.Sequence = c.nextSequence
+	c.bufferLocked(snapshot)
+
+	if c.stream == nil {
+		// Still backing off - keep the snapshot for the next attempt
+		if time.Now().Before(c.nextAttempt) {
+			return fmt.Errorf("%w (%d buffered)", ErrSnapshotsBuffered, len(c.pending))
+		}
+
+		// Opening the stream sends everything pending, including this snapshot
+		if err := c.openStreamLocked(); err != nil {
+			c.scheduleRetryLocked(err)
+			return fmt.Errorf("%w: %v", ErrSnapshotsBuffered, err)
+		}
+		return nil
+	}
+
+	if err := c.stream.Send(snapshot); err != nil {
+		c.resetStreamLocked(err)
+		return fmt.Errorf("%w (%d buffered)", ErrSnapshotsBuffered, len(c.pending))
+	}
+
+	return nil
+}
+
+// sendSpooledLocked appends a snapshot to the spool, then replays the spool if the stream is
+// up or can be reopened.
+func (c *MetricsClient) sendSpooledLocked(snapshot *pb.MetricSnapshot) error {
+	snapshot.Replayed = true
+	if err := c.spool.Append(snapshot); err != nil {
+		slog.Warn("Failed to spool snapshot", "error", err)
+	}
+	metrics.AnalyserSpooledSnapshots.Set(float64(c.spool.Len()))
+
+	if c.stream == nil {
+		if time.Now().Before(c.nextAttempt) {
+			return fmt.Errorf("%w (%d spooled)", ErrSnapshotsBuffered, c.spool.Len())
+		}
+
+		// Opening the stream replays the spool, including this snapshot
+		if err := c.openStreamLocked(); err != nil {
+			c.scheduleRetryLocked(err)
+			return fmt.Errorf("%w: %v", ErrSnapshotsBuffered, err)
+		}
+		return nil
+	}
+
+	if err := c.replayLocked(c.stream); err != nil {
+		c.resetStreamLocked(err)
+		return fmt.Errorf("%w (%d spooled)", ErrSnapshotsBuffered, c.spool.Len())
+	}
+	return nil
+}
+
+// replayLocked sends the spooled snapshots not yet sent on stream. They stay spooled until
+// acknowledged.
+func (c *MetricsClient) replayLocked(stream pb.MetricsService_StreamMetricsClient) error {
+	for _, snapshot := range c.spool.Peek(c.replaying) {
+		c.nextSequence++
+		snapshot.Sequence = c.nextSequence
+		c.pending = append(c.pending, snapshot)
+
+		if err := stream.Send(snapshot); err != nil {
+			return err
+		}
+		c.replaying++
+	}
+
+	return nil
+}
+
+// openStreamLocked opens a new stream and resends every unacknowledged snapshot on it.
+func (c *MetricsClient) openStreamLocked() error {
+	// A fresh connection reaches a restarted Analyser at once rather than after the old
+	// channel's own backoff
+	if c.hadStream {
+		if c.conn != nil {
+			c.conn.Close()
+		}
+		if err := c.dial(); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
 	stream, err := c.client.StreamMetrics(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create stream: %w", err)
+		cancel()
+		return fmt.Errorf("failed to open stream: %w", err)
 	}
 
-	for _, metric := range metrics {
-		if err := stream.Send(metric); err != nil {
-			return nil, fmt.Errorf("failed to send metric: %w", err)
+	for _, snapshot := range c.pending {
+		if err := stream.Send(snapshot); err != nil {
+			cancel()
+			return fmt.Errorf("failed to resend buffered snapshots: %w", err)
 		}
 	}
 
-	ack, err := stream.CloseAndRecv()
-	if err != nil {
-		return nil, fmt.Errorf("failed to receive ack: %w", err)
+	// With a spool nothing is pending here: unacknowledged snapshots were spooled when
+	// the last stream was lost
+	if c.spool != nil && c.spool.Len() > 0 {
+		log.Printf("Replaying %d spooled snapshots to Analyser", c.spool.Len())
+		c.replaying = 0
+		if err := c.replayLocked(stream); err != nil {
+			cancel()
+			c.pending = nil
+			c.replaying = 0
+			return fmt.Errorf("failed to replay spooled snapshots: %w", err)
+		}
+	}
+
+	if c.hadStream {
+		c.reconnects++
+		metrics.AnalyserReconnects.Inc()
+		log.Printf("Reconnected to Analyser, resent %d unacknowledged snapshots", len(c.pending))
+	}
+
+	c.stream = stream
+	c.streamCancel = cancel
+	c.streamDone = make(chan struct{})
+	c.hadStream = true
+	c.backoff = 0
+
+	go c.receiveAcks(stream, c.streamDone)
+
+	return nil
+}
+
+// receiveAcks drops acknowledged snapshots until the stream ends.
+func (c *MetricsClient) receiveAcks(stream pb.MetricsService_StreamMetricsClient, done chan struct{}) {
+	defer close(done)
+
+	for {
+		ack, err := stream.Recv()
+
+		c.mu.Lock()
+		if err != nil {
+			// Only the current stream may mark the link down; an old one was replaced on purpose
+			if c.stream == stream {
+				if err == io.EOF {
+					err = errors.New("analyser closed the stream")
+				}
+				c.resetStreamLocked(err)
+			}
+			c.mu.Unlock()
+			return
+		}
+		c.ackLocked(ack)
+		c.mu.Unlock()
+	}
+}
+
+func (c *MetricsClient) ackLocked(ack *pb.MetricsAck) {
+	acked := 0
+	replayed := 0
+	for acked < len(c.pending) && c.pending[acked].Sequence <= ack.AckedSequence {
+		if c.pending[acked].Replayed {
+			replayed++
+		}
+		acked++
+	}
+	c.pending = c.pending[acked:]
+
+	if c.spool != nil && replayed > 0 {
+		if err := c.spool.Remove(replayed); err != nil {
+			slog.Warn("Failed to remove replayed snapshots from spool", "error", err)
+		}
+		c.replaying = max(c.replaying-replayed, 0)
+		metrics.AnalyserSpooledSnapshots.Set(float64(c.spool.Len()))
+	}
+
+	c.lastAcked = ack.AckedSequence
+	c.lastAckAt = time.Now()
+	metrics.AnalyserBufferedSnapshots.Set(float64(len(c.pending)))
+
+	if ack.Rejected == 0 {
+		return
+	}
+
+	// Rejections are capped per ack; Rejected holds the full count
+	c.rejected += ack.Rejected
+	for _, rejection := range ack.Rejections {
+		log.Printf("Analyser rejected snapshot %d for %s: %s",
+			rejection.Sequence, rejection.DatabaseId, rejection.Reason)
+		metrics.AnalyserRejectedSnapshots.WithLabelValues(rejection.Reason).Inc()
+	}
+	if unlisted := ack.Rejected - int64(len(ack.Rejections)); unlisted > 0 {
+		log.Printf("Analyser rejected %d more snapshots", unlisted)
+		metrics.AnalyserRejectedSnapshots.WithLabelValues("unlisted").Add(float64(unlisted))
+	}
+}
+
+// resetStreamLocked tears down a broken stream and schedules a reconnect.
+func (c *MetricsClient) resetStreamLocked(err error) {
+	if c.streamCancel != nil {
+		c.streamCancel()
+	}
+	c.stream = nil
+	c.streamCancel = nil
+	c.spoolPendingLocked()
+
+	c.scheduleRetryLocked(err)
+	log.Printf("Analyser stream lost, buffering snapshots (%d unacknowledged, retry in %s): %v",
+		len(c.pending), c.backoff, err)
+}
+
+// spoolPendingLocked moves unacknowledged snapshots to the spool, to be replayed on the next
+// stream. Replayed snapshots are already there. Snapshots are only sent live while the spool
+// is empty, so the live ones go to the end in order.
+func (c *MetricsClient) spoolPendingLocked() {
+	if c.spool == nil {
+		return
+	}
+
+	for _, snapshot := range c.pending {
+		if snapshot.Replayed {
+			continue
+		}
+		snapshot.Replayed = true
+		if err := c.spool.Append(snapshot); err != nil {
+			slog.Warn("Failed to spool snapshot", "error", err)
+		}
+	}
+	c.pending = nil
+	c.replaying = 0
+
+	metrics.AnalyserBufferedSnapshots.Set(0)
+	metrics.AnalyserSpooledSnapshots.Set(float64(c.spool.Len()))
+}
+
+// bufferLocked appends a snapshot, dropping the oldest beyond the buffer size.
+func (c *MetricsClient) bufferLocked(snapshot *pb.MetricSnapshot) {
+	c.pending = append(c.pending, snapshot)
+
+	if overflow := len(c.pending) - c.bufferSize; overflow > 0 {
+		c.pending = c.pending[overflow:]
+		c.dropped += int64(overflow)
+		metrics.AnalyserDroppedSnapshots.Add(float64(overflow))
 	}
 
-	return ack, nil
+	metrics.AnalyserBufferedSnapshots.Set(float64(len(c.pending)))
 }
 
-// Close closes the gRPC connection.
+func (c *MetricsClient) scheduleRetryLocked(err error) {
+	c.lastError = err.Error()
+	c.lastErrorAt = time.Now()
+
+	if c.backoff == 0 {
+		c.backoff = initialReconnectBackoff
+	} else {
+		c.backoff *= 2
+	}
+	c.backoff = min(c.backoff, c.maxBackoff)
+	c.nextAttempt = time.Now().Add(c.backoff)
+}
+
+// Stats returns the current state of the link to the Analyser.
+func (c *MetricsClient) Stats() StreamStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	spooled := 0
+	if c.spool != nil {
+		spooled = c.spool.Len()
+	}
+
+	return StreamStats{
+		Connected:         c.stream != nil,
+		BufferedSnapshots: len(c.pending),
+		SpooledSnapshots:  spooled,
+		DroppedSnapshots:  c.dropped,
+		RejectedSnapshots: c.rejected,
+		Reconnects:        c.reconnects,
+		LastAckedSequence: c.lastAcked,
+		LastAckAt:         c.lastAckAt,
+		LastError:         c.lastError,
+		LastErrorAt:       c.lastErrorAt,
+	}
+}
+
+// Close ends the stream, waiting briefly for the Analyser's final ack, then closes the
+// gRPC connection. Snapshots still unacknowledged are spooled for the next run.
 func (c *MetricsClient) Close() error {
+	c.mu.Lock()
+	stream, cancel, done := c.stream, c.streamCancel, c.streamDone
+	c.stream = nil
+	c.streamCancel = nil
+	c.mu.Unlock()
+
+	if stream != nil {
+		if err := stream.CloseSend(); err == nil {
+			select {
+			case <-done:
+			case <-time.After(closeAckTimeout):
+			}
+		}
+		cancel()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.spoolPendingLocked()
+
 	if c.conn != nil {
 		err := c.conn.Close()
 		c.conn = nil
@@ -2,86 +2,94 @@
 package health
 
 import (
-	"context"
-	"encoding/json"
-	"log"
-	"net/http"
 	"sync"
-	"time"
-)
 
-var (
-	startTime           time.Time
-	unavailableFeatures []string
-	mu                  sync.RWMutex
-	server              *http.Server
+	grpcclient "github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/grpc"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/metrics"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/schedule"
+	sharedhealth "github.com/EricMurray-e-m-dev/StartupMonkey/proto/health"
 )
 
-func init() {
-	startTime = time.Now()
-}
-
-// HealthResponse represents the JSON response from the health endpoint.
+// HealthResponse represents the JSON response from the health endpoint: the standard
+// fields shared by every service plus the Collector's own state.
 type HealthResponse struct {
-	Status              string   `json:"status"`
-	Service             string   `json:"service"`
-	UptimeSeconds       int64    `json:"uptime_seconds"`
-	Timestamp           int64    `json:"timestamp"`
+	sharedhealth.HealthResponse
+
 	UnavailableFeatures []string `json:"unavailable_features,omitempty"`
+
+	// Analyser link state; status is "degraded" while the stream is down
+	Analyser *grpcclient.StreamStats `json:"analyser,omitempty"`
+
+	// Collection pacing per database ID; a stretched interval doesn't affect status
+	Collection map[string]schedule.Stats `json:"collection,omitempty"`
+}
+
+// Server is the shared health server with the Collector's additions to /health.
+type Server struct {
+	*sharedhealth.Server
+
+	mu                  sync.RWMutex
+	unavailableFeatures []string
+	analyserStats       func() grpcclient.StreamStats
+	collectionStats     func() map[string]schedule.Stats
+}
+
+// NewServer creates a health server for the service on the given port ("0" picks a free one).
+func NewServer(service, port string) *Server {
+	s := &Server{Server: sharedhealth.NewServer(service, port, metrics.Handler())}
+	s.SetExtension(s.extend)
+	return s
 }
 
 // SetUnavailableFeatures updates the list of unavailable database features.
-func SetUnavailableFeatures(features []string) {
-	mu.Lock()
-	defer mu.Unlock()
-	unavailableFeatures = features
+func (s *Server) SetUnavailableFeatures(features []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.unavailableFeatures = features
 }
 
-// StartHealthCheckServer starts the HTTP health check server on the given port.
-func StartHealthCheckServer(port string) {
-	mux := http.NewServeMux()
-	mux.HandleFunc("/health", healthHandler)
+// SetAnalyserStats registers the source of the Analyser link state shown on /health.
+func (s *Server) SetAnalyserStats(fn func() grpcclient.StreamStats) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	server = &http.Server{
-		Addr:    ":" + port,
-		Handler: mux,
-	}
+	s.analyserStats = fn
+}
 
-	log.Printf("Health check listening on :%s", port)
+// SetCollectionStats registers the source of the per-database collection pacing shown on /health.
+func (s *Server) SetCollectionStats(fn func() map[string]schedule.Stats) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	go func() {
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Health server failed: %v", err)
-		}
-	}()
+	s.collectionStats = fn
 }
 
-// StopHealthCheckServer gracefully stops the health check server.
-func StopHealthCheckServer() {
-	if server != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
+// extend adds the Collector's state to a health response. The Analyser link being down
+// reports the Collector degraded.
+func (s *Server) extend(base *sharedhealth.HealthResponse) interface{} {
+	s.mu.RLock()
+	features := s.unavailableFeatures
+	statsFn := s.analyserStats
+	collectionFn := s.collectionStats
+	s.mu.RUnlock()
+
+	response := &HealthResponse{UnavailableFeatures: features}
 
-		if err := server.Shutdown(ctx); err != nil {
-			log.Printf("Error stopping health server: %v", err)
+	if statsFn != nil {
+		stats := statsFn()
+		response.Analyser = &stats
+		if !stats.Connected && base.Status == "healthy" {
+			base.Status = "degraded"
 		}
 	}
-}
 
-func healthHandler(w http.ResponseWriter, r *http.Request) {
-	mu.RLock()
-	features := unavailableFeatures
-	mu.RUnlock()
-
-	response := &HealthResponse{
-		Status:              "healthy",
-		Service:             "collector",
-		UptimeSeconds:       int64(time.Since(startTime).Seconds()),
-		Timestamp:           time.Now().Unix(),
-		UnavailableFeatures: features,
+	if collectionFn != nil {
+		if stats := collectionFn(); len(stats) > 0 {
+			response.Collection = stats
+		}
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
+	response.HealthResponse = *base
+	return response
 }
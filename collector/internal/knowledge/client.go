@@ -5,14 +5,16 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
 	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/transport"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 // Client handles communication with the Knowledge service.
@@ -27,16 +29,19 @@ type DatabaseInfo struct {
 	ConnectionString string
 	DatabaseType     string
 	DatabaseName     string
+	Metadata         map[string]string
 }
 
 // NewClient creates a new Knowledge service client and establishes a gRPC connection.
-func NewClient(address string) (*Client, error) {
+func NewClient(address string, tlsConfig transport.TLSConfig) (*Client, error) {
 	log.Printf("Connecting to Knowledge service at: %s", address)
 
-	conn, err := grpc.NewClient(
-		address,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	credentials, err := tlsConfig.DialOption()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.NewClient(address, credentials)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Knowledge service: %w", err)
 	}
@@ -66,7 +71,12 @@ func (c *Client) ListDatabases(ctx context.Context, enabledOnly bool) ([]*pb.Reg
 
 // RegisterDatabase registers a database with the Knowledge service.
 func (c *Client) RegisterDatabase(ctx context.Context, info *DatabaseInfo) error {
-	host, port := parseConnectionString(info.ConnectionString, info.DatabaseType)
+	host, port := ParseConnectionString(info.ConnectionString, info.DatabaseType)
+
+	metadata := info.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
 
 	req := &pb.RegisterDatabaseRequest{
 		DatabaseId:       info.DatabaseID,
@@ -77,7 +87,7 @@ func (c *Client) RegisterDatabase(ctx context.Context, info *DatabaseInfo) error
 		Port:             port,
 		Version:          "unknown",
 		RegisteredAt:     time.Now().Unix(),
-		Metadata:         map[string]string{},
+		Metadata:         metadata,
 		Enabled:          true, // New databases are enabled by default
 	}
 
@@ -94,6 +104,26 @@ func (c *Client) RegisterDatabase(ctx context.Context, info *DatabaseInfo) error
 	return nil
 }
 
+// UpdateDatabaseMetadata merges metadata into a registered, enabled database.
+func (c *Client) UpdateDatabaseMetadata(ctx context.Context, databaseID string, metadata map[string]string) error {
+	req := &pb.UpdateDatabaseRequest{
+		DatabaseId: databaseID,
+		Enabled:    true, // UpdateDatabase always sets enabled; only enabled databases are collected
+		Metadata:   metadata,
+	}
+
+	resp, err := c.client.UpdateDatabase(ctx, req)
+	if err != nil {
+		return fmt.Errorf("metadata update RPC failed: %w", err)
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("Knowledge rejected metadata update: %s", resp.Message)
+	}
+
+	return nil
+}
+
 // UpdateDatabaseHealth updates the health status of a registered database.
 func (c *Client) UpdateDatabaseHealth(ctx context.Context, databaseID, status string, healthScore float64) error {
 	req := &pb.UpdateDatabaseHealthRequest{
@@ -125,6 +155,18 @@ func (c *Client) GetSystemConfig(ctx context.Context) (*pb.SystemConfig, error)
 	return resp, nil
 }
 
+// Ping checks that the Knowledge service is reachable and serving.
+func (c *Client) Ping(ctx context.Context) error {
+	resp, err := healthpb.NewHealthClient(c.conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		return fmt.Errorf("knowledge unreachable: %w", err)
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("knowledge is %s", resp.Status)
+	}
+	return nil
+}
+
 // Close gracefully closes the gRPC connection to Knowledge service.
 func (c *Client) Close() error {
 	if c.conn != nil {
@@ -133,8 +175,8 @@ func (c *Client) Close() error {
 	return nil
 }
 
-// parseConnectionString extracts host and port from a database connection string.
-func parseConnectionString(connStr, dbType string) (string, int32) {
+// ParseConnectionString extracts host and port from a database connection string.
+func ParseConnectionString(connStr, dbType string) (string, int32) {
 	host := "localhost"
 	port := int32(5432)
 
@@ -147,6 +189,28 @@ func parseConnectionString(connStr, dbType string) (string, int32) {
 		port = 27017
 	}
 
+	// SQLite is a file on the Collector's own host
+	if dbType == "sqlite" || dbType == "sqlite3" {
+		return "localhost", 0
+	}
+
+	// MySQL DSNs address the server as user:pass@tcp(host:port)/db
+	if start := strings.Index(connStr, "@tcp("); start != -1 {
+		addr := connStr[start+len("@tcp("):]
+		if end := strings.Index(addr, ")"); end != -1 {
+			addr = addr[:end]
+		}
+		if h, p, err := net.SplitHostPort(addr); err == nil {
+			host = h
+			if n, err := strconv.Atoi(p); err == nil {
+				port = int32(n)
+			}
+		} else if addr != "" {
+			host = addr
+		}
+		return host, port
+	}
+
 	if strings.Contains(connStr, "://") {
 		u, err := url.Parse(connStr)
 		if err == nil {
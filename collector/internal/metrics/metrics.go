@@ -0,0 +1,116 @@
+// Package metrics exposes Prometheus metrics for the Collector service.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// CycleDuration tracks how long a full collection cycle across all databases takes.
+	CycleDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "collector",
+		Name:      "cycle_duration_seconds",
+		Help:      "Duration of a full collection cycle across all databases.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	// CollectionDuration tracks how long collecting from a single database takes.
+	CollectionDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "collector",
+		Name:      "collection_duration_seconds",
+		Help:      "Duration of metric collection for a single database.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"database_id"})
+
+	// QueryDuration tracks how long each metric query takes: the Collector's own load.
+	QueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "collector",
+		Name:      "query_duration_seconds",
+		Help:      "Duration of each metric query run against a database.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"database_id", "query"})
+
+	// CollectionInterval reports the interval each database is being collected at.
+	CollectionInterval = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "collector",
+		Name:      "collection_interval_seconds",
+		Help:      "Interval each database is collected at, stretched while it is under load.",
+	}, []string{"database_id"})
+
+	// CollectionsTotal counts successful collections per database.
+	CollectionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "collector",
+		Name:      "collections_total",
+		Help:      "Number of successful metric collections per database.",
+	}, []string{"database_id"})
+
+	// CollectionFailures counts failed collections per database.
+	CollectionFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "collector",
+		Name:      "collection_failures_total",
+		Help:      "Number of failed metric collections per database.",
+	}, []string{"database_id"})
+
+	// DatabasesMonitored reports the number of databases with an active adapter.
+	DatabasesMonitored = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "collector",
+		Name:      "databases_monitored",
+		Help:      "Number of databases currently being monitored.",
+	})
+
+	// AnalyserBufferedSnapshots reports snapshots held until the Analyser acknowledges them.
+	AnalyserBufferedSnapshots = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "collector",
+		Name:      "analyser_buffered_snapshots",
+		Help:      "Snapshots sent or waiting to be sent that the Analyser has not acknowledged.",
+	})
+
+	// AnalyserSpooledSnapshots reports snapshots kept on disk while the Analyser is unreachable.
+	AnalyserSpooledSnapshots = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "collector",
+		Name:      "analyser_spooled_snapshots",
+		Help:      "Snapshots spooled to disk, waiting to be replayed to the Analyser.",
+	})
+
+	// AnalyserDroppedSnapshots counts buffered snapshots discarded because the buffer was full.
+	AnalyserDroppedSnapshots = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "collector",
+		Name:      "analyser_dropped_snapshots_total",
+		Help:      "Snapshots dropped because the Analyser buffer was full.",
+	})
+
+	// AnalyserRejectedSnapshots counts snapshots the Analyser acknowledged but dropped.
+	AnalyserRejectedSnapshots = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "collector",
+		Name:      "analyser_rejected_snapshots_total",
+		Help:      "Snapshots rejected by the Analyser per reason.",
+	}, []string{"reason"})
+
+	// AnalyserReconnects counts successful reconnections to the Analyser.
+	AnalyserReconnects = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "collector",
+		Name:      "analyser_reconnects_total",
+		Help:      "Number of times the Analyser stream was re-established.",
+	})
+)
+
+// Handler returns the HTTP handler serving metrics in Prometheus format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
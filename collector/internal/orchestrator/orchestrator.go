@@ -4,19 +4,31 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/adapter"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/config"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/deployment"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/eventbus"
 	grpcclient "github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/grpc"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/health"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/knowledge"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/metrics"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/registry"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/schedule"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/spool"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/system"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
 	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/logging"
 )
 
 // AdapterEntry holds an adapter and its associated components for a single database.
@@ -27,8 +39,50 @@ type AdapterEntry struct {
 	DBType     string
 	DBName     string
 	ConnString string
+
+	// Metric families the database's adapter provides
+	Capabilities registry.Capabilities
+
+	// Where the database runs, from its Knowledge metadata
+	DeploymentContext string
+	ContainerName     string // Docker container the database runs in, empty when unknown
+
+	// collecting guards against overlapping collection cycles for this database
+	collecting sync.Mutex
+
+	// interval stretches while the database is under load, so collecting adds less to it
+	interval *schedule.Interval
+	// How long the last collection's metric queries ran, judged against the budget
+	queryTime time.Duration
+
+	// Last health report sent to Knowledge, used to throttle updates
+	lastHealthStatus  string
+	lastHealthScore   float64
+	cyclesSinceHealth int
+
+	// Set once the missing disk total has been reported, so it is logged only once
+	storageTotalReported bool
 }
 
+// Health reporting is throttled: Knowledge is only updated when the status
+// changes, the score moves by more than healthScoreChangeThreshold, or every
+// healthReportEveryCycles cycles so last_seen stays fresh.
+const (
+	healthScoreChangeThreshold = 0.05
+	healthReportEveryCycles    = 6
+	healthReportTimeout        = 5 * time.Second
+)
+
+// slowQueriesLogged is how many of the most expensive metric queries are named when a
+// collection goes over its budget.
+const slowQueriesLogged = 3
+
+// hostMetricsTimeout bounds reading the database host's metrics each cycle.
+const hostMetricsTimeout = 5 * time.Second
+
+// healthStopTimeout bounds how long Stop waits for in-flight /health requests.
+const healthStopTimeout = 5 * time.Second
+
 // Orchestrator manages the Collector service lifecycle and coordinates
 // metric collection, normalization, and distribution to downstream services.
 type Orchestrator struct {
@@ -38,29 +92,113 @@ type Orchestrator struct {
 	adapters   map[string]*AdapterEntry
 	adaptersMu sync.RWMutex
 
+	// Databases from config that have been registered with Knowledge
+	registered   map[string]bool
+	registeredMu sync.Mutex
+
+	// Classifies where each database runs for its Knowledge metadata
+	deployment *deployment.Detector
+
+	// Reads the database host's load, memory and disk; nil unless HOST_METRICS_SOURCE is set
+	hostSource system.HostSource
+
 	// Downstream service connections
 	client          *grpcclient.MetricsClient
+	spool           *spool.Spool // nil unless SNAPSHOT_SPOOL_PATH is set
 	natsPublisher   *eventbus.Publisher
 	knowledgeClient *knowledge.Client
+
+	healthServer *health.Server
 }
 
 // NewOrchestrator creates a new Orchestrator instance.
 func NewOrchestrator(cfg *config.Config) *Orchestrator {
+	// Docker is optional - without its socket, containers are only known from config
+	var lookup deployment.ContainerLookup
+	if docker := deployment.NewDockerLookup(); docker != nil {
+		lookup = docker
+	}
+
+	hostSource, err := system.NewHostSource(cfg.HostMetricsSource, cfg.HostProcPath, cfg.NodeExporterURL, cfg.HostDataPath)
+	if err != nil {
+		slog.Warn("Host metrics disabled", "error", err)
+	}
+
 	return &Orchestrator{
-		config:   cfg,
-		adapters: make(map[string]*AdapterEntry),
+		config:       cfg,
+		adapters:     make(map[string]*AdapterEntry),
+		registered:   make(map[string]bool),
+		deployment:   deployment.NewDetector(lookup),
+		hostSource:   hostSource,
+		healthServer: health.NewServer("collector", cfg.HealthPort),
+	}
+}
+
+// StartHealthServer serves /health before Start so the Collector reports its state while
+// it waits for databases. Knowledge is required; NATS and the databases are not.
+func (o *Orchestrator) StartHealthServer() error {
+	o.healthServer.AddCheck("knowledge", true, func(ctx context.Context) error {
+		if o.knowledgeClient == nil {
+			return fmt.Errorf("not connected")
+		}
+		return o.knowledgeClient.Ping(ctx)
+	})
+	if o.config.EnableMetricsPublishing && o.config.NatsURL != "" {
+		o.healthServer.AddCheck("nats", false, func(ctx context.Context) error {
+			if o.natsPublisher == nil || !o.natsPublisher.IsConnected() {
+				return fmt.Errorf("not connected")
+			}
+			return nil
+		})
+	}
+	o.healthServer.AddCheck("databases", false, o.checkDatabases)
+	o.healthServer.SetCollectionStats(o.collectionStats)
+
+	return o.healthServer.Start()
+}
+
+// checkDatabases health checks every monitored database, failing if any is unreachable.
+func (o *Orchestrator) checkDatabases(ctx context.Context) error {
+	o.adaptersMu.RLock()
+	entries := make([]*AdapterEntry, 0, len(o.adapters))
+	for _, entry := range o.adapters {
+		entries = append(entries, entry)
+	}
+	o.adaptersMu.RUnlock()
+
+	if len(entries) == 0 {
+		return fmt.Errorf("no databases monitored")
 	}
+
+	var failed []string
+	for _, entry := range entries {
+		if err := entry.Adapter.HealthCheck(); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", entry.DatabaseID, err))
+		}
+	}
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return fmt.Errorf("%d of %d unreachable: %s", len(failed), len(entries), strings.Join(failed, "; "))
+	}
+	return nil
 }
 
 // Start initializes all service connections and prepares the orchestrator for metric collection.
 func (o *Orchestrator) Start(ctx context.Context) error {
 	log.Printf("Starting Collector Orchestrator...")
 
+	for _, db := range registry.Databases() {
+		log.Printf("Supported database: %s (collects %s)", db.Name, strings.Join(db.Capabilities.Families(), ", "))
+	}
+
 	// Connect to Knowledge first
 	if err := o.connectKnowledge(); err != nil {
 		return fmt.Errorf("failed to connect to Knowledge: %w", err)
 	}
 
+	// Register databases from DATABASES config
+	o.registerConfiguredDatabases(ctx)
+
 	// Wait for at least one enabled database
 	if err := o.waitForDatabases(ctx); err != nil {
 		return fmt.Errorf("failed to get databases from Knowledge: %w", err)
@@ -79,7 +217,7 @@ func (o *Orchestrator) Start(ctx context.Context) error {
 
 // connectKnowledge establishes gRPC connection to Knowledge service.
 func (o *Orchestrator) connectKnowledge() error {
-	client, err := knowledge.NewClient(o.config.KnowledgeAddress)
+	client, err := knowledge.NewClient(o.config.KnowledgeAddress, o.config.GRPCTLS)
 	if err != nil {
 		return fmt.Errorf("failed to connect: %w", err)
 	}
@@ -89,6 +227,41 @@ func (o *Orchestrator) connectKnowledge() error {
 	return nil
 }
 
+// registerConfiguredDatabases registers databases from config with Knowledge.
+// Entries that fail to register are retried on the next sync.
+func (o *Orchestrator) registerConfiguredDatabases(ctx context.Context) {
+	o.registeredMu.Lock()
+	defer o.registeredMu.Unlock()
+
+	for _, db := range o.config.Databases {
+		if o.registered[db.ID] {
+			continue
+		}
+
+		err := o.knowledgeClient.RegisterDatabase(ctx, &knowledge.DatabaseInfo{
+			DatabaseID:       db.ID,
+			ConnectionString: db.ConnectionString,
+			DatabaseType:     db.Type,
+			DatabaseName:     db.Name,
+			Metadata:         o.detectDeployment(ctx, db.ConnectionString, db.Type, db.Container).Metadata(),
+		})
+		if err != nil {
+			slog.Warn("Failed to register database, will retry", "database_id", db.ID, "error", err)
+			continue
+		}
+
+		o.registered[db.ID] = true
+	}
+}
+
+// detectDeployment works out where a database runs from its connection string.
+func (o *Orchestrator) detectDeployment(ctx context.Context, connectionString, dbType, container string) deployment.Context {
+	host, _ := knowledge.ParseConnectionString(connectionString, dbType)
+	detected := o.deployment.Detect(ctx, host, container)
+	log.Printf("Database at %s runs in %s context", host, detected.Kind)
+	return detected
+}
+
 // waitForDatabases polls Knowledge until at least one enabled database exists.
 func (o *Orchestrator) waitForDatabases(ctx context.Context) error {
 	log.Printf("Waiting for enabled databases from Knowledge...")
@@ -103,7 +276,7 @@ func (o *Orchestrator) waitForDatabases(ctx context.Context) error {
 
 		case <-ticker.C:
 			if err := o.syncDatabases(ctx); err != nil {
-				log.Printf("Failed to sync databases: %v (retrying...)", err)
+				slog.Error("Failed to sync databases, retrying", "error", err)
 				continue
 			}
 
@@ -125,6 +298,8 @@ func (o *Orchestrator) waitForDatabases(ctx context.Context) error {
 // syncDatabases synchronizes adapters with the databases registered in Knowledge.
 // Adds new databases, removes unregistered/disabled ones.
 func (o *Orchestrator) syncDatabases(ctx context.Context) error {
+	o.registerConfiguredDatabases(ctx)
+
 	databases, err := o.knowledgeClient.ListDatabases(ctx, true) // enabled_only=true
 	if err != nil {
 		return fmt.Errorf("failed to list databases: %w", err)
@@ -144,9 +319,10 @@ func (o *Orchestrator) syncDatabases(ctx context.Context) error {
 		if !knownIDs[id] {
 			log.Printf("Removing adapter for database: %s (no longer enabled)", id)
 			if err := entry.Adapter.Close(); err != nil {
-				log.Printf("Error closing adapter for %s: %v", id, err)
+				slog.Error("Error closing adapter", "database_id", id, "error", err)
 			}
 			delete(o.adapters, id)
+			metrics.CollectionInterval.DeleteLabelValues(id)
 		}
 	}
 
@@ -158,24 +334,58 @@ func (o *Orchestrator) syncDatabases(ctx context.Context) error {
 
 		log.Printf("Adding adapter for database: %s (type: %s)", db.DatabaseId, db.DatabaseType)
 
+		// Databases onboarded through the Dashboard were not registered by the Collector
+		if db.Metadata[deployment.MetadataContext] == "" {
+			o.backfillDeployment(ctx, db)
+		}
+
 		entry, err := o.createAdapterEntry(db)
 		if err != nil {
-			log.Printf("Failed to create adapter for %s: %v", db.DatabaseId, err)
+			slog.Error("Failed to create adapter", "database_id", db.DatabaseId, "error", err)
 			continue
 		}
 
 		o.adapters[db.DatabaseId] = entry
-		log.Printf("Database connected: %s (%s)", db.DatabaseId, db.DatabaseName)
+		log.Printf("Database connected: %s (%s), collecting %s",
+			db.DatabaseId, db.DatabaseName, strings.Join(entry.Capabilities.Families(), ", "))
 	}
 
+	metrics.DatabasesMonitored.Set(float64(len(o.adapters)))
+
 	return nil
 }
 
+// backfillDeployment detects the deployment context of a database registered without one
+// and records it in Knowledge. db.Metadata is updated either way so labels are set.
+func (o *Orchestrator) backfillDeployment(ctx context.Context, db *pb.RegisteredDatabase) {
+	detected := o.detectDeployment(ctx, db.ConnectionString, db.DatabaseType, "")
+	metadata := detected.Metadata()
+
+	if err := o.knowledgeClient.UpdateDatabaseMetadata(ctx, db.DatabaseId, metadata); err != nil {
+		slog.Warn("Failed to record deployment context", "database_id", db.DatabaseId, "error", err)
+	}
+
+	if db.Metadata == nil {
+		db.Metadata = make(map[string]string)
+	}
+	for key, value := range metadata {
+		db.Metadata[key] = value
+	}
+}
+
 // createAdapterEntry creates a new adapter entry for a database.
 func (o *Orchestrator) createAdapterEntry(db *pb.RegisteredDatabase) (*AdapterEntry, error) {
-	adpt, err := adapter.NewAdapter(db.DatabaseType, db.ConnectionString, db.DatabaseId)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create adapter: %w", err)
+	registered, ok := registry.Lookup(db.DatabaseType)
+	if !ok {
+		return nil, fmt.Errorf("failed to create adapter: %w: %q", adapter.ErrUnsupportedDatabase, db.DatabaseType)
+	}
+
+	adpt := registered.NewAdapter(db.ConnectionString, db.DatabaseId)
+	if configurer, ok := adpt.(adapter.IndexCandidateConfigurer); ok {
+		configurer.SetIndexCandidateLimit(o.config.IndexCandidates)
+	}
+	if configurer, ok := adpt.(adapter.DiskUsageConfigurer); ok {
+		configurer.SetDiskUsageQuery(o.config.DiskUsageQuery)
 	}
 
 	if err := adpt.Connect(); err != nil {
@@ -189,22 +399,64 @@ func (o *Orchestrator) createAdapterEntry(db *pb.RegisteredDatabase) (*AdapterEn
 
 	return &AdapterEntry{
 		Adapter:    adpt,
-		Normaliser: normaliser.NewNormaliser(db.DatabaseType),
+		Normaliser: registered.NewNormaliser(),
 		DatabaseID: db.DatabaseId,
 		DBType:     db.DatabaseType,
 		DBName:     db.DatabaseName,
 		ConnString: db.ConnectionString,
+
+		Capabilities: registered.Capabilities,
+
+		DeploymentContext: db.Metadata[deployment.MetadataContext],
+		ContainerName:     db.Metadata[deployment.MetadataContainer],
+
+		interval: schedule.NewInterval(o.scheduleSettings()),
 	}, nil
 }
 
+// scheduleSettings is how each database's collection interval adapts to its load.
+func (o *Orchestrator) scheduleSettings() schedule.Settings {
+	return schedule.Settings{
+		Base:            o.config.CollectionInterval,
+		Max:             o.config.CollectionMaxInterval,
+		HealthThreshold: o.config.CollectionBackoffHealth,
+		Budget:          o.config.CollectionBudget,
+	}
+}
+
+// collectionStats reports each database's collection pacing for /health.
+func (o *Orchestrator) collectionStats() map[string]schedule.Stats {
+	o.adaptersMu.RLock()
+	defer o.adaptersMu.RUnlock()
+
+	stats := make(map[string]schedule.Stats, len(o.adapters))
+	for id, entry := range o.adapters {
+		stats[id] = entry.interval.Stats()
+	}
+	return stats
+}
+
 // connectAnalyser establishes gRPC connection to the Analyser service.
 func (o *Orchestrator) connectAnalyser() error {
 	log.Printf("Connecting to Analyser at: %s", o.config.AnalyserAddress)
 
-	o.client = grpcclient.NewMetricsClient(o.config.AnalyserAddress)
+	o.client = grpcclient.NewMetricsClient(o.config.AnalyserAddress, o.config.GRPCTLS)
+	o.client.SetBufferSize(o.config.AnalyserBufferSize)
+	// Retry at least once per collection cycle while the Analyser is down
+	o.client.SetMaxBackoff(o.config.CollectionInterval)
+	if o.config.SpoolPath != "" {
+		snapshotSpool, err := spool.Open(o.config.SpoolPath, o.config.SpoolSize)
+		if err != nil {
+			return fmt.Errorf("failed to open snapshot spool: %w", err)
+		}
+		o.spool = snapshotSpool
+		o.client.SetSpool(snapshotSpool)
+		log.Printf("Spooling snapshots to %s while the Analyser is unreachable (up to %d)", o.config.SpoolPath, o.config.SpoolSize)
+	}
 	if err := o.client.Connect(); err != nil {
 		return fmt.Errorf("failed to connect: %w", err)
 	}
+	o.healthServer.SetAnalyserStats(o.client.Stats)
 
 	log.Printf("Connected to Analyser")
 	return nil
@@ -221,7 +473,7 @@ func (o *Orchestrator) connectNATS() {
 
 	publisher, err := eventbus.NewPublisher(o.config.NatsURL)
 	if err != nil {
-		log.Printf("Warning: failed to connect to NATS: %v", err)
+		slog.Warn("Failed to connect to NATS", "error", err)
 		return
 	}
 
@@ -229,10 +481,12 @@ func (o *Orchestrator) connectNATS() {
 	log.Printf("Connected to NATS")
 }
 
-// Run starts the periodic metric collection loop.
+// Run starts the periodic metric collection loop. The ticker runs at the configured
+// interval; each database is collected on the ticks its own, possibly stretched, interval
+// falls on.
 func (o *Orchestrator) Run(ctx context.Context) error {
-	log.Printf("Starting metric collection (interval: %v, sync: %v)",
-		o.config.CollectionInterval, o.config.SyncInterval)
+	log.Printf("Starting metric collection (interval: %v, up to %v under load, sync: %v)",
+		o.config.CollectionInterval, max(o.config.CollectionMaxInterval, o.config.CollectionInterval), o.config.SyncInterval)
 
 	collectionTicker := time.NewTicker(o.config.CollectionInterval)
 	defer collectionTicker.Stop()
@@ -254,57 +508,203 @@ func (o *Orchestrator) Run(ctx context.Context) error {
 
 		case <-syncTicker.C:
 			if err := o.syncDatabases(ctx); err != nil {
-				log.Printf("Error syncing databases: %v", err)
+				slog.Error("Error syncing databases", "error", err)
 			}
 		}
 	}
 }
 
-// collectFromAllDatabases collects metrics from all connected databases.
+// collectFromAllDatabases collects metrics from the connected databases due this tick.
 func (o *Orchestrator) collectFromAllDatabases(ctx context.Context) {
 	o.adaptersMu.RLock()
+	total := len(o.adapters)
 	entries := make([]*AdapterEntry, 0, len(o.adapters))
 	for _, entry := range o.adapters {
-		entries = append(entries, entry)
+		if entry.interval.Tick() {
+			entries = append(entries, entry)
+		}
 	}
 	o.adaptersMu.RUnlock()
 
-	if len(entries) == 0 {
+	if total == 0 {
 		log.Printf("No databases to collect from")
 		return
 	}
+	if len(entries) == 0 {
+		// Every database is backed off this tick
+		return
+	}
 
-	log.Printf("--- Collection Cycle Start (%d databases) ---", len(entries))
+	slog.Debug("Collection cycle start", "databases", len(entries))
+
+	cycleStart := time.Now()
+	defer func() {
+		metrics.CycleDuration.Observe(time.Since(cycleStart).Seconds())
+	}()
 
 	// Collect system metrics once (shared across all databases)
 	var sysMetrics *system.Metrics
 	var sysErr error
 	sysMetrics, sysErr = system.Collect()
 	if sysErr != nil {
-		log.Printf("Warning: failed to collect system metrics: %v", sysErr)
+		slog.Warn("Failed to collect system metrics", "error", sysErr)
 	}
 
+	hostMetrics := o.collectHostMetrics(ctx)
+
+	// Collect from each database concurrently so a slow or failing
+	// database does not hold up the others
+	var wg sync.WaitGroup
 	for _, entry := range entries {
-		if err := o.collectAndSend(ctx, entry, sysMetrics); err != nil {
-			log.Printf("Error collecting from %s: %v", entry.DatabaseID, err)
-			// Update health status in Knowledge
-			o.updateDatabaseHealth(ctx, entry.DatabaseID, "degraded", 0.5)
-		} else {
-			o.updateDatabaseHealth(ctx, entry.DatabaseID, "healthy", 1.0)
+		wg.Add(1)
+		go func(entry *AdapterEntry) {
+			defer wg.Done()
+			o.collectFromDatabase(ctx, entry, sysMetrics, hostMetrics)
+		}(entry)
+	}
+	wg.Wait()
+
+	slog.Debug("Collection cycle complete", "duration", time.Since(cycleStart))
+}
+
+// collectHostMetrics reads the database host once per cycle. Without a source, or when it
+// fails, databases are collected without host metrics rather than with zeros.
+func (o *Orchestrator) collectHostMetrics(ctx context.Context) *system.HostMetrics {
+	if o.hostSource == nil {
+		return nil
+	}
+
+	hostCtx, cancel := context.WithTimeout(ctx, hostMetricsTimeout)
+	defer cancel()
+
+	hostMetrics, err := o.hostSource.Collect(hostCtx)
+	if err != nil {
+		slog.Warn("Failed to collect host metrics", "source", o.config.HostMetricsSource, "error", err)
+		return nil
+	}
+	return hostMetrics
+}
+
+// collectFromDatabase runs one collection cycle for a single database and
+// reports the outcome to Knowledge. Skips the cycle if the previous one is still running.
+func (o *Orchestrator) collectFromDatabase(ctx context.Context, entry *AdapterEntry, sysMetrics *system.Metrics, hostMetrics *system.HostMetrics) {
+	if !entry.collecting.TryLock() {
+		slog.Warn("Skipping collection: previous collection still in progress", "database_id", entry.DatabaseID)
+		return
+	}
+	defer entry.collecting.Unlock()
+
+	// Each database's collection gets its own correlation ID, carried on the snapshot so the
+	// Analyser and Executor log the same ID for whatever this cycle detects
+	correlationID := logging.NewCorrelationID()
+	ctx = logging.WithCorrelationID(ctx, correlationID)
+	logger := logging.With(correlationID).With("database_id", entry.DatabaseID)
+
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Recovered from panic during collection", "panic", r)
+			metrics.CollectionFailures.WithLabelValues(entry.DatabaseID).Inc()
+			o.reportHealth(ctx, entry, "degraded", 0.5)
 		}
+	}()
+
+	start := time.Now()
+	normalised, err := o.collectAndSend(ctx, entry, sysMetrics, hostMetrics)
+	metrics.CollectionDuration.WithLabelValues(entry.DatabaseID).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		logger.Error("Collection failed", "error", err)
+		metrics.CollectionFailures.WithLabelValues(entry.DatabaseID).Inc()
+		// Update health status in Knowledge
+		o.reportHealth(ctx, entry, "degraded", 0.5)
+		o.adaptInterval(logger, entry, 0, false, entry.queryTime)
+	} else {
+		metrics.CollectionsTotal.WithLabelValues(entry.DatabaseID).Inc()
+		o.reportHealth(ctx, entry, "healthy", normalised.HealthScore)
+		o.adaptInterval(logger, entry, normalised.HealthScore, true, entry.queryTime)
+	}
+}
+
+// adaptInterval stretches or shrinks the database's collection interval after a
+// collection, logging when it changes.
+func (o *Orchestrator) adaptInterval(logger *slog.Logger, entry *AdapterEntry, health float64, healthKnown bool, took time.Duration) {
+	if !entry.interval.Observe(health, healthKnown, took) {
+		return
+	}
+
+	interval := entry.interval.Current()
+	metrics.CollectionInterval.WithLabelValues(entry.DatabaseID).Set(interval.Seconds())
+
+	if reason := entry.interval.Reason(); reason != "" {
+		logger.Warn("Collection interval stretched to reduce load on the database", "interval", interval, "reason", reason)
+	} else {
+		logger.Info("Collection interval restored", "interval", interval)
+	}
+}
+
+// reportStorageTotal warns once per database when the first collection has no disk total.
+// Without it the Analyser's storage growth detector can't project when the disk fills, so
+// the warning names the settings that provide one.
+func (o *Orchestrator) reportStorageTotal(logger *slog.Logger, entry *AdapterEntry, normalised *normaliser.NormalisedMetrics) {
+	if entry.storageTotalReported {
+		return
+	}
+	entry.storageTotalReported = true
+
+	if total := normalised.Measurements.TotalStorageBytes; total != nil && *total > 0 {
+		return
 	}
+	logger.Warn("Disk size unknown, storage growth detection disabled for this database",
+		"host_metrics_source", o.config.HostMetricsSource,
+		"hint", "set HOST_METRICS_SOURCE with HOST_DATA_PATH, or PG_DISK_USAGE_QUERY for PostgreSQL")
+}
+
+// logQueryCosts records how long each metric query took and names the most expensive
+// ones when the collection went over its budget.
+func (o *Orchestrator) logQueryCosts(logger *slog.Logger, entry *AdapterEntry, costs []adapter.QueryCost, took time.Duration) {
+	for _, cost := range costs {
+		metrics.QueryDuration.WithLabelValues(entry.DatabaseID, cost.Query).Observe(cost.Duration.Seconds())
+	}
+
+	if len(costs) == 0 {
+		return
+	}
+
+	budget := o.config.CollectionBudget
+	if budget <= 0 || took <= budget {
+		logger.Debug("Collection cost", "took", took, "queries", formatQueryCosts(costs))
+		return
+	}
+
+	logger.Warn("Metric queries went over the collection budget",
+		"took", took, "budget", budget, "slowest", formatQueryCosts(costs[:min(len(costs), slowQueriesLogged)]))
+}
 
-	log.Printf("--- Collection Cycle Complete ---")
+// formatQueryCosts renders costs as "name=12ms, name=3ms (x5)".
+func formatQueryCosts(costs []adapter.QueryCost) string {
+	parts := make([]string, 0, len(costs))
+	for _, cost := range costs {
+		part := fmt.Sprintf("%s=%s", cost.Query, cost.Duration.Round(time.Microsecond))
+		if cost.Calls > 1 {
+			part += fmt.Sprintf(" (x%d)", cost.Calls)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
 }
 
 // collectAndSend performs a single metric collection cycle for one database.
-func (o *Orchestrator) collectAndSend(ctx context.Context, entry *AdapterEntry, sysMetrics *system.Metrics) error {
-	log.Printf("Collecting metrics from: %s", entry.DatabaseID)
+func (o *Orchestrator) collectAndSend(ctx context.Context, entry *AdapterEntry, sysMetrics *system.Metrics, hostMetrics *system.HostMetrics) (*normaliser.NormalisedMetrics, error) {
+	logger := logging.FromContext(ctx).With("database_id", entry.DatabaseID)
+	logger.Debug("Collecting metrics")
 
+	queryStart := time.Now()
 	rawMetrics, err := entry.Adapter.CollectMetrics()
+	entry.queryTime = time.Since(queryStart)
 	if err != nil {
-		return fmt.Errorf("metric collection failed: %w", err)
+		return nil, fmt.Errorf("metric collection failed: %w", err)
 	}
+	o.logQueryCosts(logger, entry, rawMetrics.QueryCosts, entry.queryTime)
 
 	// Add system metrics if available
 	if sysMetrics != nil {
@@ -312,35 +712,90 @@ func (o *Orchestrator) collectAndSend(ctx context.Context, entry *AdapterEntry,
 			rawMetrics.ExtendedMetrics[k] = v
 		}
 	}
+	if hostMetrics != nil {
+		hostMetrics.Apply(rawMetrics)
+	}
 
 	normalised, err := entry.Normaliser.Normalise(rawMetrics)
 	if err != nil {
-		return fmt.Errorf("normalization failed: %w", err)
+		return nil, fmt.Errorf("normalization failed: %w", err)
+	}
+	o.reportStorageTotal(logger, entry, normalised)
+
+	normalised.CorrelationID = logging.CorrelationID(ctx)
+	normalised.CollectionIntervalSeconds = entry.interval.Current().Seconds()
+	if entry.DeploymentContext != "" {
+		normalised.Labels[normaliser.LabelDeploymentContext] = entry.DeploymentContext
+	}
+	if entry.ContainerName != "" {
+		normalised.Labels[normaliser.LabelContainerName] = entry.ContainerName
 	}
 
 	snapshot := o.toProtobuf(normalised)
+	snapshot.DatabaseId = entry.DatabaseID
 
-	ack, err := o.client.StreamMetrics(ctx, []*pb.MetricSnapshot{snapshot})
-	if err != nil {
-		return fmt.Errorf("failed to send metrics to Analyser: %w", err)
+	err = o.client.Send(snapshot)
+	switch {
+	case errors.Is(err, grpcclient.ErrSnapshotsBuffered):
+		// The database was collected fine; the snapshot is resent once the Analyser is back
+		logger.Warn("Analyser unavailable, snapshot buffered", "error", err)
+	case err != nil:
+		return nil, fmt.Errorf("failed to send metrics to Analyser: %w", err)
+	default:
+		logger.Debug("Metrics sent to Analyser", "health_score", normalised.HealthScore, "sequence", snapshot.Sequence)
 	}
 
-	log.Printf("  %s: Health=%.2f, Ack=%d metrics", entry.DatabaseID, normalised.HealthScore, ack.TotalMetrics)
-
 	if o.natsPublisher != nil {
 		if err := o.natsPublisher.PublishMetrics(normalised); err != nil {
-			log.Printf("Warning: failed to publish metrics to NATS: %v", err)
+			logger.Warn("Failed to publish metrics to NATS", "error", err)
 		}
 	}
 
-	return nil
+	return normalised, nil
+}
+
+// reportHealth sends the database health to Knowledge if the throttle allows it.
+// Failed updates are retried on the next cycle.
+func (o *Orchestrator) reportHealth(ctx context.Context, entry *AdapterEntry, status string, score float64) {
+	entry.cyclesSinceHealth++
+
+	if !shouldReportHealth(entry, status, score) {
+		return
+	}
+
+	if err := o.updateDatabaseHealth(ctx, entry.DatabaseID, status, score); err != nil {
+		logging.FromContext(ctx).Warn("Failed to update health in Knowledge", "database_id", entry.DatabaseID, "error", err)
+		return
+	}
+
+	entry.lastHealthStatus = status
+	entry.lastHealthScore = score
+	entry.cyclesSinceHealth = 0
+}
+
+// shouldReportHealth decides whether a health update is worth sending.
+func shouldReportHealth(entry *AdapterEntry, status string, score float64) bool {
+	if entry.lastHealthStatus != status {
+		return true
+	}
+
+	if math.Abs(score-entry.lastHealthScore) > healthScoreChangeThreshold {
+		return true
+	}
+
+	return entry.cyclesSinceHealth >= healthReportEveryCycles
 }
 
 // updateDatabaseHealth updates the health status in Knowledge.
-func (o *Orchestrator) updateDatabaseHealth(ctx context.Context, dbID, status string, score float64) {
-	if err := o.knowledgeClient.UpdateDatabaseHealth(ctx, dbID, status, score); err != nil {
-		log.Printf("Warning: failed to update health for %s: %v", dbID, err)
+func (o *Orchestrator) updateDatabaseHealth(ctx context.Context, dbID, status string, score float64) error {
+	if o.knowledgeClient == nil {
+		return fmt.Errorf("knowledge client not connected")
 	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthReportTimeout)
+	defer cancel()
+
+	return o.knowledgeClient.UpdateDatabaseHealth(ctx, dbID, status, score)
 }
 
 // Stop gracefully closes all connections.
@@ -351,7 +806,7 @@ func (o *Orchestrator) Stop() error {
 	o.adaptersMu.Lock()
 	for id, entry := range o.adapters {
 		if err := entry.Adapter.Close(); err != nil {
-			log.Printf("Error closing adapter %s: %v", id, err)
+			slog.Error("Error closing adapter", "database_id", id, "error", err)
 		}
 	}
 	o.adapters = make(map[string]*AdapterEntry)
@@ -359,7 +814,14 @@ func (o *Orchestrator) Stop() error {
 
 	if o.client != nil {
 		if err := o.client.Close(); err != nil {
-			log.Printf("Error closing Analyser connection: %v", err)
+			slog.Error("Error closing Analyser connection", "error", err)
+		}
+	}
+
+	// After the client, which spools what the Analyser didn't acknowledge
+	if o.spool != nil {
+		if err := o.spool.Close(); err != nil {
+			slog.Error("Error closing snapshot spool", "error", err)
 		}
 	}
 
@@ -369,10 +831,16 @@ func (o *Orchestrator) Stop() error {
 
 	if o.knowledgeClient != nil {
 		if err := o.knowledgeClient.Close(); err != nil {
-			log.Printf("Error closing Knowledge client: %v", err)
+			slog.Error("Error closing Knowledge client", "error", err)
 		}
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), healthStopTimeout)
+	defer cancel()
+	if err := o.healthServer.Shutdown(ctx); err != nil {
+		slog.Error("Error stopping health server", "error", err)
+	}
+
 	log.Printf("Orchestrator stopped successfully")
 	return nil
 }
@@ -380,9 +848,13 @@ func (o *Orchestrator) Stop() error {
 // toProtobuf converts normalized metrics to protobuf format.
 func (o *Orchestrator) toProtobuf(n *normaliser.NormalisedMetrics) *pb.MetricSnapshot {
 	snapshot := &pb.MetricSnapshot{
-		DatabaseId:   n.DatabaseID,
-		DatabaseType: n.DatabaseType,
-		Timestamp:    n.Timestamp,
+		DatabaseId:     n.DatabaseID,
+		DatabaseType:   n.DatabaseType,
+		Timestamp:      n.Timestamp,
+		ElapsedSeconds: n.ElapsedSeconds,
+		CorrelationId:  n.CorrelationID,
+
+		CollectionIntervalSeconds: n.CollectionIntervalSeconds,
 
 		HealthScore:      n.HealthScore,
 		ConnectionHealth: n.ConnectionHealth,
@@ -393,6 +865,7 @@ func (o *Orchestrator) toProtobuf(n *normaliser.NormalisedMetrics) *pb.MetricSna
 		AvailableMetrics: n.AvailableMetrics,
 		MetricDeltas:     n.MetricDeltas,
 		TimeDeltaSeconds: &n.TimeDeltaSeconds,
+		DeltasReliable:   &n.DeltasReliable,
 
 		ExtendedMetrics: n.ExtendedMetrics,
 		Labels:          n.Labels,
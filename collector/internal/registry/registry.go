@@ -0,0 +1,119 @@
+// Package registry maps database types to the adapter and normaliser that handle them.
+// Adapters and normalisers register themselves from init, so supporting a new database
+// means adding its two files rather than editing every factory.
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/adapter"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+)
+
+// Capabilities says which metric families a database's adapter can provide.
+type Capabilities = adapter.Capabilities
+
+// Database describes how the Collector handles one database type.
+type Database struct {
+	Name         string   // Canonical type, e.g. "postgres"
+	Aliases      []string // Other types that select it, e.g. "postgresql"
+	Capabilities Capabilities
+
+	NewAdapter    func(connectionString, databaseID string) adapter.MetricAdapter
+	NewNormaliser func() normaliser.Normaliser
+}
+
+var (
+	mu sync.RWMutex
+
+	// Registered databases by name and by alias
+	databases = make(map[string]*Database)
+)
+
+// Each adapter registers its driver, and each normaliser itself, from their own init;
+// this pairs them up once both packages are initialised.
+func init() {
+	for _, driver := range adapter.Drivers() {
+		Register(Database{
+			Name:          driver.Name,
+			Aliases:       driver.Aliases,
+			Capabilities:  driver.Capabilities,
+			NewAdapter:    driver.New,
+			NewNormaliser: normaliser.Constructor(driver.Name),
+		})
+	}
+}
+
+// Register makes a database type available to the Collector. Like database/sql.Register
+// it is meant to be called from init, and panics on a missing constructor or on a name
+// or alias that is already taken.
+func Register(db Database) {
+	if db.Name == "" {
+		panic("registry: Register with empty database name")
+	}
+	if db.NewAdapter == nil || db.NewNormaliser == nil {
+		panic(fmt.Sprintf("registry: Register %s without an adapter and normaliser", db.Name))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	types := append([]string{db.Name}, db.Aliases...)
+	for _, dbType := range types {
+		if _, taken := databases[dbType]; taken {
+			panic(fmt.Sprintf("registry: Register called twice for database type %s", dbType))
+		}
+	}
+
+	entry := db
+	for _, dbType := range types {
+		databases[dbType] = &entry
+	}
+}
+
+// Lookup returns the database registered under dbType, by name or alias.
+func Lookup(dbType string) (Database, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	db, ok := databases[dbType]
+	if !ok {
+		return Database{}, false
+	}
+	return *db, true
+}
+
+// Databases returns every registered database, sorted by name.
+func Databases() []Database {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	var registered []Database
+	for dbType, db := range databases {
+		if dbType == db.Name {
+			registered = append(registered, *db)
+		}
+	}
+	sort.Slice(registered, func(i, j int) bool { return registered[i].Name < registered[j].Name })
+	return registered
+}
+
+// NewAdapter creates the adapter for dbType, or returns adapter.ErrUnsupportedDatabase.
+func NewAdapter(dbType, connectionString, databaseID string) (adapter.MetricAdapter, error) {
+	db, ok := Lookup(dbType)
+	if !ok {
+		return nil, adapter.ErrUnsupportedDatabase
+	}
+	return db.NewAdapter(connectionString, databaseID), nil
+}
+
+// NewNormaliser creates the normaliser for dbType, or returns nil if it isn't registered.
+func NewNormaliser(dbType string) normaliser.Normaliser {
+	db, ok := Lookup(dbType)
+	if !ok {
+		return nil
+	}
+	return db.NewNormaliser()
+}
@@ -0,0 +1,144 @@
+// Package schedule paces metric collection. Collecting is itself load on the database, so
+// each database's interval stretches while it is unhealthy or slow to answer the metric
+// queries, and shrinks back once it recovers.
+package schedule
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Settings configures an adaptive collection interval.
+type Settings struct {
+	// Base is the interval while the database is healthy; collections are only ever
+	// considered on Base ticks, so stretched intervals are multiples of it
+	Base time.Duration
+
+	// Max caps how far the interval stretches; at or below Base it never stretches
+	Max time.Duration
+
+	// HealthThreshold is the HealthScore below which the interval stretches (0 disables)
+	HealthThreshold float64
+
+	// Budget is how long collecting may take before the interval stretches (0 disables)
+	Budget time.Duration
+}
+
+// Stats is one database's collection pacing, as shown on /health.
+type Stats struct {
+	IntervalSeconds     float64 `json:"interval_seconds"`
+	BaseIntervalSeconds float64 `json:"base_interval_seconds"`
+	// Why the interval is stretched; empty at the base interval
+	Reason           string  `json:"reason,omitempty"`
+	LastCollectionMs float64 `json:"last_collection_ms"`
+}
+
+// Interval tracks one database's adaptive collection interval. The interval doubles after
+// each collection that finds the database under load, up to Max, and halves after each
+// one that doesn't, down to Base.
+type Interval struct {
+	settings  Settings
+	maxFactor int
+
+	mu sync.Mutex
+	// The interval is factor Base ticks long; ticks counts those since the last collection
+	factor       int
+	ticks        int
+	reason       string
+	lastDuration time.Duration
+}
+
+// NewInterval starts at the base interval, with the first collection due on the first tick.
+func NewInterval(settings Settings) *Interval {
+	maxFactor := 1
+	if settings.Base > 0 && settings.Max > settings.Base {
+		maxFactor = int(settings.Max / settings.Base)
+	}
+
+	return &Interval{
+		settings:  settings,
+		maxFactor: maxFactor,
+		factor:    1,
+		ticks:     1,
+	}
+}
+
+// Tick is called once per Base interval and reports whether a collection is due.
+func (i *Interval) Tick() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.ticks < i.factor {
+		i.ticks++
+		return false
+	}
+	i.ticks = 1
+	return true
+}
+
+// Observe records a finished collection and adjusts the interval for the next one. health
+// is the snapshot's HealthScore, ignored when healthKnown is false because the collection
+// failed; took is how long the metric queries ran. Returns whether the interval changed.
+func (i *Interval) Observe(health float64, healthKnown bool, took time.Duration) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.lastDuration = took
+
+	reason := i.loadReason(health, healthKnown, took)
+	previous := i.factor
+	if reason != "" {
+		i.factor = min(i.factor*2, i.maxFactor)
+	} else {
+		i.factor = max(i.factor/2, 1)
+	}
+
+	if i.factor == 1 {
+		i.reason = ""
+	} else if reason != "" {
+		i.reason = reason
+	}
+
+	return i.factor != previous
+}
+
+// loadReason says why the database counts as under load, or returns "" when it doesn't.
+func (i *Interval) loadReason(health float64, healthKnown bool, took time.Duration) string {
+	if i.settings.Budget > 0 && took > i.settings.Budget {
+		return fmt.Sprintf("collection took %s, over the %s budget", took.Round(time.Millisecond), i.settings.Budget)
+	}
+	if healthKnown && i.settings.HealthThreshold > 0 && health < i.settings.HealthThreshold {
+		return fmt.Sprintf("health score %.2f below %.2f", health, i.settings.HealthThreshold)
+	}
+	return ""
+}
+
+// Current returns the interval the database is being collected at.
+func (i *Interval) Current() time.Duration {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return time.Duration(i.factor) * i.settings.Base
+}
+
+// Reason returns why the interval is stretched, or "" at the base interval.
+func (i *Interval) Reason() string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.reason
+}
+
+// Stats returns the interval's state for /health.
+func (i *Interval) Stats() Stats {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return Stats{
+		IntervalSeconds:     (time.Duration(i.factor) * i.settings.Base).Seconds(),
+		BaseIntervalSeconds: i.settings.Base.Seconds(),
+		Reason:              i.reason,
+		LastCollectionMs:    float64(i.lastDuration) / float64(time.Millisecond),
+	}
+}
@@ -0,0 +1,249 @@
+// Package spool keeps snapshots the Analyser has not received in a bounded file, so an
+// outage or a Collector restart doesn't lose them.
+package spool
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/metrics"
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultMaxSnapshots is how many snapshots a spool holds before dropping the oldest.
+const DefaultMaxSnapshots = 500
+
+// maxRecordSize guards against reading a corrupt length prefix as a huge allocation.
+const maxRecordSize = 16 << 20
+
+// Spool is a ring buffer of snapshots backed by a file of length-prefixed protobuf records,
+// oldest first. Appends go to the end of the file; the file is rewritten when snapshots are
+// removed, or once dropped snapshots make up half of it.
+type Spool struct {
+	path         string
+	maxSnapshots int
+
+	mu          sync.Mutex
+	snapshots   []*pb.MetricSnapshot
+	fileRecords int // records in the file, including dropped ones not yet rewritten away
+	file        *os.File
+}
+
+// Open loads the spool at path, creating it if needed. A record cut short by a crash ends
+// the spool; everything before it is kept.
+func Open(path string, maxSnapshots int) (*Spool, error) {
+	if maxSnapshots < 1 {
+		maxSnapshots = DefaultMaxSnapshots
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory: %w", err)
+	}
+
+	s := &Spool{path: path, maxSnapshots: maxSnapshots}
+
+	snapshots, err := readRecords(path)
+	if err != nil {
+		return nil, err
+	}
+	if overflow := len(snapshots) - maxSnapshots; overflow > 0 {
+		snapshots = snapshots[overflow:]
+	}
+	s.snapshots = snapshots
+
+	// Rewriting drops a torn record and any overflow before appending resumes
+	if err := s.rewriteLocked(); err != nil {
+		return nil, err
+	}
+
+	if len(snapshots) > 0 {
+		log.Printf("Loaded %d spooled snapshots from %s", len(snapshots), path)
+	}
+
+	return s, nil
+}
+
+func readRecords(path string) ([]*pb.MetricSnapshot, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open spool: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var snapshots []*pb.MetricSnapshot
+	for {
+		var size uint32
+		if err := binary.Read(reader, binary.BigEndian, &size); err != nil {
+			if !errors.Is(err, io.EOF) {
+				slog.Warn("Spool ends with a partial record, discarding it", "path", path)
+			}
+			return snapshots, nil
+		}
+		if size > maxRecordSize {
+			slog.Warn("Spool has a corrupt record, discarding it and what follows", "path", path)
+			return snapshots, nil
+		}
+
+		data := make([]byte, size)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			slog.Warn("Spool ends with a partial record, discarding it", "path", path)
+			return snapshots, nil
+		}
+
+		snapshot := &pb.MetricSnapshot{}
+		if err := proto.Unmarshal(data, snapshot); err != nil {
+			slog.Warn("Spool has an unreadable record, discarding it and what follows", "path", path)
+			return snapshots, nil
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+}
+
+// Append adds a snapshot to the end of the spool, dropping the oldest once it is full.
+func (s *Spool) Append(snapshot *pb.MetricSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.snapshots = append(s.snapshots, snapshot)
+	if overflow := len(s.snapshots) - s.maxSnapshots; overflow > 0 {
+		s.snapshots = s.snapshots[overflow:]
+		metrics.AnalyserDroppedSnapshots.Add(float64(overflow))
+	}
+
+	if s.file == nil || s.fileRecords >= 2*s.maxSnapshots {
+		return s.rewriteLocked()
+	}
+
+	record, err := encodeRecord(snapshot)
+	if err != nil {
+		return err
+	}
+	if _, err := s.file.Write(record); err != nil {
+		return fmt.Errorf("failed to write spool: %w", err)
+	}
+	s.fileRecords++
+
+	return nil
+}
+
+// Peek returns the spooled snapshots from offset on, oldest first.
+func (s *Spool) Peek(offset int) []*pb.MetricSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if offset >= len(s.snapshots) {
+		return nil
+	}
+	return append([]*pb.MetricSnapshot(nil), s.snapshots[offset:]...)
+}
+
+// Remove drops the n oldest snapshots, once the Analyser has acknowledged them.
+func (s *Spool) Remove(n int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if n <= 0 {
+		return nil
+	}
+	n = min(n, len(s.snapshots))
+	s.snapshots = s.snapshots[n:]
+
+	return s.rewriteLocked()
+}
+
+// Len returns how many snapshots are spooled.
+func (s *Spool) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.snapshots)
+}
+
+// Close closes the spool file. What it holds is loaded again by the next Open.
+func (s *Spool) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// rewriteLocked replaces the file with the snapshots currently held, via a temporary file
+// so a crash leaves either the old spool or the new one.
+func (s *Spool) rewriteLocked() error {
+	if s.file != nil {
+		s.file.Close()
+		s.file = nil
+	}
+
+	tmpPath := s.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create spool: %w", err)
+	}
+
+	writer := bufio.NewWriter(tmp)
+	for _, snapshot := range s.snapshots {
+		record, err := encodeRecord(snapshot)
+		if err != nil {
+			tmp.Close()
+			return err
+		}
+		if _, err := writer.Write(record); err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to write spool: %w", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write spool: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync spool: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write spool: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("failed to replace spool: %w", err)
+	}
+
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open spool: %w", err)
+	}
+	s.file = file
+	s.fileRecords = len(s.snapshots)
+
+	return nil
+}
+
+func encodeRecord(snapshot *pb.MetricSnapshot) ([]byte, error) {
+	data, err := proto.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	record := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(record, uint32(len(data)))
+	copy(record[4:], data)
+	return record, nil
+}
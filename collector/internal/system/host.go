@@ -0,0 +1,391 @@
+package system
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/adapter"
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+// Host metrics sources selectable with HOST_METRICS_SOURCE.
+const (
+	HostSourceNone         = "none"
+	HostSourceProc         = "proc"
+	HostSourceNodeExporter = "node_exporter"
+)
+
+// Extended metric keys for host metrics. Only the ones a source actually read are set.
+const (
+	HostLoad1m               = "host.load_1m"
+	HostLoad5m               = "host.load_5m"
+	HostLoad15m              = "host.load_15m"
+	HostMemoryAvailableBytes = "host.memory_available_bytes"
+	HostMemoryTotalBytes     = "host.memory_total_bytes"
+	HostDiskTotalBytes       = "host.disk_total_bytes"
+	HostDiskFreeBytes        = "host.disk_free_bytes"
+)
+
+// nodeExporterTimeout bounds one scrape of node_exporter.
+const nodeExporterTimeout = 5 * time.Second
+
+// HostMetrics describes the machine a database runs on. Fields are nil when the source
+// could not read them, so a missing value is never mistaken for zero.
+type HostMetrics struct {
+	Load1m               *float64
+	Load5m               *float64
+	Load15m              *float64
+	MemoryAvailableBytes *int64
+	MemoryTotalBytes     *int64
+
+	// DiskTotalBytes and DiskFreeBytes are for the filesystem holding the database's data
+	DiskTotalBytes *int64
+	DiskFreeBytes  *int64
+}
+
+// HostSource reads metrics about the database host.
+type HostSource interface {
+	Collect(ctx context.Context) (*HostMetrics, error)
+}
+
+// NewHostSource creates the source named by HOST_METRICS_SOURCE. It returns nil for
+// "none" or an empty name. dataPath is where the database keeps its data; its filesystem
+// is the one reported as the disk.
+func NewHostSource(name, procPath, nodeExporterURL, dataPath string) (HostSource, error) {
+	switch name {
+	case "", HostSourceNone:
+		return nil, nil
+	case HostSourceProc:
+		return NewProcSource(procPath, dataPath), nil
+	case HostSourceNodeExporter:
+		if nodeExporterURL == "" {
+			return nil, fmt.Errorf("NODE_EXPORTER_URL is required for the node_exporter host metrics source")
+		}
+		return NewNodeExporterSource(nodeExporterURL, dataPath), nil
+	default:
+		return nil, fmt.Errorf("unknown host metrics source %q", name)
+	}
+}
+
+// ToExtendedMetrics converts the host metrics that were read to extended metric keys.
+func (m *HostMetrics) ToExtendedMetrics() map[string]float64 {
+	extended := make(map[string]float64)
+
+	setFloat := func(key string, v *float64) {
+		if v != nil {
+			extended[key] = *v
+		}
+	}
+	setInt := func(key string, v *int64) {
+		if v != nil {
+			extended[key] = float64(*v)
+		}
+	}
+
+	setFloat(HostLoad1m, m.Load1m)
+	setFloat(HostLoad5m, m.Load5m)
+	setFloat(HostLoad15m, m.Load15m)
+	setInt(HostMemoryAvailableBytes, m.MemoryAvailableBytes)
+	setInt(HostMemoryTotalBytes, m.MemoryTotalBytes)
+	setInt(HostDiskTotalBytes, m.DiskTotalBytes)
+	setInt(HostDiskFreeBytes, m.DiskFreeBytes)
+
+	return extended
+}
+
+// Apply adds the host metrics to a database's raw metrics. The data disk becomes the
+// storage total and free space, so storage health reflects how full the disk is.
+func (m *HostMetrics) Apply(raw *adapter.RawMetrics) {
+	for key, value := range m.ToExtendedMetrics() {
+		raw.ExtendedMetrics[key] = value
+	}
+
+	if m.DiskTotalBytes == nil {
+		return
+	}
+	if raw.Storage == nil {
+		raw.Storage = &adapter.StorageMetrics{}
+	}
+	raw.Storage.TotalSizeBytes = m.DiskTotalBytes
+	raw.Storage.FreeSpaceBytes = m.DiskFreeBytes
+}
+
+// ProcSource reads host metrics from /proc and the data directory's filesystem, for a
+// Collector running on the database host. In a container, mount the host's /proc and data
+// directory and point ProcPath and DataPath at them.
+type ProcSource struct {
+	ProcPath string
+	DataPath string
+}
+
+// NewProcSource creates a ProcSource, defaulting to /proc and the root filesystem.
+func NewProcSource(procPath, dataPath string) *ProcSource {
+	if procPath == "" {
+		procPath = "/proc"
+	}
+	if dataPath == "" {
+		dataPath = "/"
+	}
+	return &ProcSource{ProcPath: procPath, DataPath: dataPath}
+}
+
+// Collect reads load averages, memory and disk usage. A file that can't be read leaves its
+// metrics unset; an error is only returned when nothing could be read.
+func (s *ProcSource) Collect(ctx context.Context) (*HostMetrics, error) {
+	m := &HostMetrics{}
+	var errs []string
+
+	if err := s.readLoadAvg(m); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if err := s.readMemInfo(m); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	usage, err := disk.UsageWithContext(ctx, s.DataPath)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("disk usage of %s: %v", s.DataPath, err))
+	} else {
+		total := int64(usage.Total)
+		free := int64(usage.Free)
+		m.DiskTotalBytes = &total
+		m.DiskFreeBytes = &free
+	}
+
+	if len(errs) == 3 {
+		return nil, fmt.Errorf("no host metrics read: %s", strings.Join(errs, "; "))
+	}
+	return m, nil
+}
+
+func (s *ProcSource) readLoadAvg(m *HostMetrics) error {
+	data, err := os.ReadFile(filepath.Join(s.ProcPath, "loadavg"))
+	if err != nil {
+		return fmt.Errorf("read loadavg: %w", err)
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) < 3 {
+		return fmt.Errorf("malformed loadavg: %q", string(data))
+	}
+
+	loads := make([]float64, 3)
+	for i := range loads {
+		loads[i], err = strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return fmt.Errorf("malformed loadavg: %w", err)
+		}
+	}
+
+	m.Load1m, m.Load5m, m.Load15m = &loads[0], &loads[1], &loads[2]
+	return nil
+}
+
+func (s *ProcSource) readMemInfo(m *HostMetrics) error {
+	f, err := os.Open(filepath.Join(s.ProcPath, "meminfo"))
+	if err != nil {
+		return fmt.Errorf("read meminfo: %w", err)
+	}
+	defer f.Close()
+
+	// Lines look like "MemAvailable:   12345678 kB"
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		kb, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		bytes := kb * 1024
+
+		switch fields[0] {
+		case "MemTotal:":
+			m.MemoryTotalBytes = &bytes
+		case "MemAvailable:":
+			m.MemoryAvailableBytes = &bytes
+		}
+	}
+	return scanner.Err()
+}
+
+// NodeExporterSource scrapes host metrics from a Prometheus node_exporter, for a database
+// host the Collector does not run on.
+type NodeExporterSource struct {
+	URL      string
+	DataPath string
+
+	client *http.Client
+}
+
+// NewNodeExporterSource creates a source scraping url, which is usually
+// http://<host>:9100/metrics.
+func NewNodeExporterSource(url, dataPath string) *NodeExporterSource {
+	if dataPath == "" {
+		dataPath = "/"
+	}
+	return &NodeExporterSource{
+		URL:      url,
+		DataPath: dataPath,
+		client:   &http.Client{Timeout: nodeExporterTimeout},
+	}
+}
+
+// Collect scrapes node_exporter once. The disk reported is the filesystem mounted at the
+// longest mountpoint containing DataPath.
+func (s *NodeExporterSource) Collect(ctx context.Context) (*HostMetrics, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("scrape node_exporter: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("scrape node_exporter: %s", resp.Status)
+	}
+
+	return ParseNodeExporter(resp.Body, s.DataPath)
+}
+
+// ParseNodeExporter reads the host metrics out of node_exporter's text exposition format.
+func ParseNodeExporter(r io.Reader, dataPath string) (*HostMetrics, error) {
+	m := &HostMetrics{}
+
+	// Filesystem sizes by mountpoint, matched to dataPath once all are read
+	sizes := make(map[string]float64)
+	avail := make(map[string]float64)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, labels, value, ok := parseSample(line)
+		if !ok {
+			continue
+		}
+
+		switch name {
+		case "node_load1":
+			m.Load1m = &value
+		case "node_load5":
+			m.Load5m = &value
+		case "node_load15":
+			m.Load15m = &value
+		case "node_memory_MemAvailable_bytes":
+			m.MemoryAvailableBytes = int64Ptr(value)
+		case "node_memory_MemTotal_bytes":
+			m.MemoryTotalBytes = int64Ptr(value)
+		case "node_filesystem_size_bytes":
+			sizes[labels["mountpoint"]] = value
+		case "node_filesystem_avail_bytes":
+			avail[labels["mountpoint"]] = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read node_exporter metrics: %w", err)
+	}
+
+	if mount, ok := dataMountpoint(sizes, dataPath); ok {
+		m.DiskTotalBytes = int64Ptr(sizes[mount])
+		if free, ok := avail[mount]; ok {
+			m.DiskFreeBytes = int64Ptr(free)
+		}
+	}
+
+	return m, nil
+}
+
+// dataMountpoint picks the mountpoint dataPath lives on: the longest one that contains it.
+func dataMountpoint(sizes map[string]float64, dataPath string) (string, bool) {
+	best, found := "", false
+	for mount := range sizes {
+		if mount == "" || !pathWithin(dataPath, mount) {
+			continue
+		}
+		if !found || len(mount) > len(best) {
+			best, found = mount, true
+		}
+	}
+	return best, found
+}
+
+func pathWithin(path, mount string) bool {
+	if mount == "/" || path == mount {
+		return true
+	}
+	return strings.HasPrefix(path, strings.TrimSuffix(mount, "/")+"/")
+}
+
+// parseSample splits a sample line such as
+// node_filesystem_avail_bytes{device="/dev/sda1",mountpoint="/"} 1.2e+10
+// into its name, labels and value. A trailing timestamp is ignored.
+func parseSample(line string) (string, map[string]string, float64, bool) {
+	var name, rest string
+	labels := map[string]string{}
+
+	if open := strings.IndexByte(line, '{'); open >= 0 {
+		end := strings.LastIndexByte(line, '}')
+		if end < open {
+			return "", nil, 0, false
+		}
+		name = line[:open]
+		labels = parseLabels(line[open+1 : end])
+		rest = line[end+1:]
+	} else {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return "", nil, 0, false
+		}
+		name = fields[0]
+		rest = strings.Join(fields[1:], " ")
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return "", nil, 0, false
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return "", nil, 0, false
+	}
+	return name, labels, value, true
+}
+
+// parseLabels reads name="value" pairs. node_exporter label values don't contain quotes or
+// commas for the labels read here, so escapes are not handled.
+func parseLabels(s string) map[string]string {
+	labels := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+		labels[key] = strings.Trim(value, `"`)
+	}
+	return labels
+}
+
+func int64Ptr(v float64) *int64 {
+	i := int64(v)
+	return &i
+}
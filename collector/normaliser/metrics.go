@@ -1,6 +1,15 @@
 // Package normaliser converts raw database metrics into normalised health scores.
 package normaliser
 
+import "time"
+
+// LabelDeploymentContext says where the database runs: docker, local, remote or cloud.
+const LabelDeploymentContext = "deployment.context"
+
+// LabelContainerName names the Docker container the database runs in, when it is known to
+// be containerized. Detectors use it to choose fixes that restart the database.
+const LabelContainerName = "deployment.container"
+
 // NormalisedMetrics contains processed metrics with health scores.
 // This structure aligns with the MetricSnapshot proto message.
 type NormalisedMetrics struct {
@@ -9,6 +18,19 @@ type NormalisedMetrics struct {
 	DatabaseType string `json:"database_type"`
 	Timestamp    int64  `json:"timestamp"`
 
+	// CollectedAt is RawMetrics.CollectedAt, kept to measure the next interval against
+	CollectedAt time.Time `json:"-"`
+
+	// ElapsedSeconds is the time since the previous collection on the monotonic clock
+	ElapsedSeconds float64 `json:"elapsed_seconds"`
+
+	// CollectionIntervalSeconds is the interval the database is being collected at, longer
+	// than configured while the Collector backs off a database under load
+	CollectionIntervalSeconds float64 `json:"collection_interval_seconds"`
+
+	// CorrelationID ties this snapshot to the detections and actions it leads to
+	CorrelationID string `json:"correlation_id,omitempty"`
+
 	// Normalised health scores (0.0 - 1.0)
 	HealthScore      float64 `json:"health_score"`
 	ConnectionHealth float64 `json:"connection_health"`
@@ -26,6 +48,11 @@ type NormalisedMetrics struct {
 	MetricDeltas     map[string]float64 `json:"metric_deltas"`
 	TimeDeltaSeconds float64            `json:"time_delta_seconds"`
 
+	// DeltasReliable is false when MetricDeltas can't be trusted: there was no previous
+	// collection, the interval was too short to measure, or a counter was reset. Detectors
+	// must check it rather than read a zero delta as "nothing happened"
+	DeltasReliable bool `json:"deltas_reliable"`
+
 	// Database-specific extended data
 	ExtendedMetrics map[string]float64 `json:"extended_metrics"`
 	Labels          map[string]string  `json:"labels"`
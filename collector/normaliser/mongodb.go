@@ -11,6 +11,10 @@ type MongoDBNormaliser struct {
 	previousMetrics map[string]*NormalisedMetrics
 }
 
+func init() {
+	Register("mongodb", func() Normaliser { return NewMongoDBNormaliser() })
+}
+
 // NewMongoDBNormaliser creates a new MongoDB normaliser.
 func NewMongoDBNormaliser() *MongoDBNormaliser {
 	return &MongoDBNormaliser{
@@ -25,6 +29,7 @@ func (n *MongoDBNormaliser) Normalise(raw *adapter.RawMetrics) (*NormalisedMetri
 		DatabaseID:       raw.DatabaseID,
 		DatabaseType:     raw.DatabaseType,
 		Timestamp:        raw.Timestamp,
+		CollectedAt:      raw.CollectedAt,
 		Measurements:     Measurements{},
 		MetricDeltas:     make(map[string]float64),
 		TimeDeltaSeconds: 0,
@@ -46,6 +51,7 @@ func (n *MongoDBNormaliser) Normalise(raw *adapter.RawMetrics) (*NormalisedMetri
 		}
 
 		normalised.Measurements.ActiveConnections = raw.Connections.Active
+		normalised.Measurements.IdleConnections = raw.Connections.Idle
 		normalised.Measurements.MaxConnections = raw.Connections.Max
 
 		healthScores = append(healthScores, normalised.ConnectionHealth)
@@ -73,8 +79,14 @@ func (n *MongoDBNormaliser) Normalise(raw *adapter.RawMetrics) (*NormalisedMetri
 		normalised.QueryHealth = 1.0
 	}
 
-	// Storage health: MongoDB doesn't report total size the same way
-	normalised.StorageHealth = 1.0
+	// Storage health: scored from the filesystem sizes dbStats reports (3.6+), or from
+	// host metrics when they measured the data disk
+	if health, ok := storageHealth(raw.Storage, &normalised.Measurements); ok {
+		normalised.StorageHealth = health
+		healthScores = append(healthScores, normalised.StorageHealth)
+	} else {
+		normalised.StorageHealth = 1.0
+	}
 
 	// Cache health: WiredTiger cache hit rate
 	if raw.Cache != nil && raw.Cache.HitRate != nil {
@@ -105,6 +117,8 @@ func (n *MongoDBNormaliser) Normalise(raw *adapter.RawMetrics) (*NormalisedMetri
 		normalised.Labels = raw.Labels
 	}
 
+	normalised.AvailableMetrics = availableMetrics(normalised.Measurements, normalised.ExtendedMetrics)
+
 	// Calculate deltas from previous collection
 	n.calculateDeltas(normalised)
 	n.previousMetrics[normalised.DatabaseID] = normalised
@@ -118,27 +132,17 @@ func (n *MongoDBNormaliser) calculateDeltas(current *NormalisedMetrics) {
 
 	if !exists {
 		current.TimeDeltaSeconds = 0
+		current.DeltasReliable = false
 		current.MetricDeltas = make(map[string]float64)
 		return
 	}
 
-	timeDelta := float64(current.Timestamp - previous.Timestamp)
-	if timeDelta <= 0 {
-		current.TimeDeltaSeconds = 0
-		return
-	}
-	current.TimeDeltaSeconds = timeDelta
+	measureInterval(current, previous)
+	storageDelta(current, previous)
 
 	// Sequential scans (collection scans) delta
 	if current.Measurements.SequentialScans != nil && previous.Measurements.SequentialScans != nil {
-		currentVal := float64(*current.Measurements.SequentialScans)
-		previousVal := float64(*previous.Measurements.SequentialScans)
-		delta := currentVal - previousVal
-
-		if delta < 0 {
-			delta = 0
-		}
-
-		current.MetricDeltas["sequential_scans"] = delta
+		current.MetricDeltas["sequential_scans"] = counterDelta(current,
+			float64(*current.Measurements.SequentialScans), float64(*previous.Measurements.SequentialScans))
 	}
 }
@@ -12,6 +12,10 @@ type MySQLNormaliser struct {
 	previousMetrics map[string]*NormalisedMetrics
 }
 
+func init() {
+	Register("mysql", func() Normaliser { return NewMySQLNormaliser() })
+}
+
 // NewMySQLNormaliser creates a new MySQL normaliser.
 func NewMySQLNormaliser() *MySQLNormaliser {
 	return &MySQLNormaliser{
@@ -25,6 +29,7 @@ func (n *MySQLNormaliser) Normalise(raw *adapter.RawMetrics) (*NormalisedMetrics
 		DatabaseID:       raw.DatabaseID,
 		DatabaseType:     raw.DatabaseType,
 		Timestamp:        raw.Timestamp,
+		CollectedAt:      raw.CollectedAt,
 		Measurements:     Measurements{},
 		MetricDeltas:     make(map[string]float64),
 		TimeDeltaSeconds: 0,
@@ -105,22 +110,10 @@ func (n *MySQLNormaliser) Normalise(raw *adapter.RawMetrics) (*NormalisedMetrics
 		normalised.QueryHealth = 1.0
 	}
 
-	// Storage health: based on used/total ratio
-	if raw.Storage != nil && raw.Storage.UsedSizeBytes != nil && raw.Storage.TotalSizeBytes != nil {
-		used := float64(*raw.Storage.UsedSizeBytes)
-		total := float64(*raw.Storage.TotalSizeBytes)
-
-		if total > 0 {
-			normalised.StorageHealth = 1.0 - (used / total)
-
-			normalised.Measurements.UsedStorageBytes = raw.Storage.UsedSizeBytes
-			normalised.Measurements.TotalStorageBytes = raw.Storage.TotalSizeBytes
-			normalised.Measurements.FreeStorageBytes = raw.Storage.FreeSpaceBytes
-
-			healthScores = append(healthScores, normalised.StorageHealth)
-		} else {
-			normalised.StorageHealth = 1.0
-		}
+	// Storage health: free share of the data disk, when host metrics measured it
+	if health, ok := storageHealth(raw.Storage, &normalised.Measurements); ok {
+		normalised.StorageHealth = health
+		healthScores = append(healthScores, normalised.StorageHealth)
 	} else {
 		normalised.StorageHealth = 1.0
 	}
@@ -158,6 +151,8 @@ func (n *MySQLNormaliser) Normalise(raw *adapter.RawMetrics) (*NormalisedMetrics
 		normalised.Labels = raw.Labels
 	}
 
+	normalised.AvailableMetrics = availableMetrics(normalised.Measurements, normalised.ExtendedMetrics)
+
 	// Calculate deltas from previous collection
 	n.calculateDeltas(normalised)
 	n.previousMetrics[normalised.DatabaseID] = normalised
@@ -171,28 +166,18 @@ func (n *MySQLNormaliser) calculateDeltas(current *NormalisedMetrics) {
 
 	if !exists {
 		current.TimeDeltaSeconds = 0
+		current.DeltasReliable = false
 		current.MetricDeltas = make(map[string]float64)
 		return
 	}
 
-	timeDelta := float64(current.Timestamp - previous.Timestamp)
-	if timeDelta <= 0 {
-		current.TimeDeltaSeconds = 0
-		return
-	}
-	current.TimeDeltaSeconds = timeDelta
+	measureInterval(current, previous)
+	storageDelta(current, previous)
 
 	// Sequential scans delta
 	if current.Measurements.SequentialScans != nil && previous.Measurements.SequentialScans != nil {
-		currentVal := float64(*current.Measurements.SequentialScans)
-		previousVal := float64(*previous.Measurements.SequentialScans)
-		delta := currentVal - previousVal
-
-		if delta < 0 {
-			delta = 0
-		}
-
-		current.MetricDeltas["sequential_scans"] = delta
+		current.MetricDeltas["sequential_scans"] = counterDelta(current,
+			float64(*current.Measurements.SequentialScans), float64(*previous.Measurements.SequentialScans))
 	}
 
 	// Slow query count delta
@@ -210,14 +195,7 @@ func (n *MySQLNormaliser) calculateDeltas(current *NormalisedMetrics) {
 
 	// Cache miss count delta
 	if current.Measurements.CacheMissCount != nil && previous.Measurements.CacheMissCount != nil {
-		currentVal := float64(*current.Measurements.CacheMissCount)
-		previousVal := float64(*previous.Measurements.CacheMissCount)
-		delta := currentVal - previousVal
-
-		if delta < 0 {
-			delta = 0
-		}
-
-		current.MetricDeltas["cache_miss_count"] = delta
+		current.MetricDeltas["cache_miss_count"] = counterDelta(current,
+			float64(*current.Measurements.CacheMissCount), float64(*previous.Measurements.CacheMissCount))
 	}
 }
@@ -1,24 +1,134 @@
 // Package normaliser converts raw database metrics into normalised health scores.
 package normaliser
 
-import "github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/adapter"
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/adapter"
+)
+
+// MinDeltaInterval is the shortest interval between collections that deltas are trusted
+// over. Shorter intervals are clamped to it so rates don't blow up, and flagged unreliable.
+const MinDeltaInterval = time.Second
 
 // Normaliser defines the interface for converting raw metrics to normalised form.
 type Normaliser interface {
 	Normalise(raw *adapter.RawMetrics) (*NormalisedMetrics, error)
 }
 
-// NewNormaliser creates a Normaliser for the specified database type.
-// Returns nil for unsupported database types.
-func NewNormaliser(databaseType string) Normaliser {
-	switch databaseType {
-	case "postgres", "postgresql":
-		return NewPostgresNormaliser()
-	case "mysql", "mariadb":
-		return NewMySQLNormaliser()
-	case "mongo", "mongodb":
-		return NewMongoDBNormaliser()
+// hostMetricPrefix marks the extended metrics read from the database host.
+const hostMetricPrefix = "host."
+
+// availableMetrics lists the measurements collected this cycle, by their JSON names, and
+// the host metrics that were read, so consumers can tell a missing metric from a zero one.
+func availableMetrics(m Measurements, extended map[string]float64) []string {
+	candidates := []struct {
+		name    string
+		present bool
+	}{
+		{"active_connections", m.ActiveConnections != nil},
+		{"idle_connections", m.IdleConnections != nil},
+		{"max_connections", m.MaxConnections != nil},
+		{"waiting_connections", m.WaitingConnections != nil},
+		{"avg_query_latency_ms", m.AvgQueryLatencyMs != nil},
+		{"p50_query_latency_ms", m.P50QueryLatencyMs != nil},
+		{"p95_query_latency_ms", m.P95QueryLatencyMs != nil},
+		{"p99_query_latency_ms", m.P99QueryLatencyMs != nil},
+		{"slow_query_count", m.SlowQueryCount != nil},
+		{"sequential_scans", m.SequentialScans != nil},
+		{"used_storage_bytes", m.UsedStorageBytes != nil},
+		{"total_storage_bytes", m.TotalStorageBytes != nil},
+		{"free_storage_bytes", m.FreeStorageBytes != nil},
+		{"cache_hit_rate", m.CacheHitRate != nil},
+		{"cache_hit_count", m.CacheHitCount != nil},
+		{"cache_miss_count", m.CacheMissCount != nil},
+	}
+
+	available := []string{}
+	for _, c := range candidates {
+		if c.present {
+			available = append(available, c.name)
+		}
+	}
+
+	var host []string
+	for key := range extended {
+		if strings.HasPrefix(key, hostMetricPrefix) {
+			host = append(host, key)
+		}
+	}
+	sort.Strings(host)
+
+	return append(available, host...)
+}
+
+// storageHealth scores storage from 0 (full) to 1 (empty) and fills in the storage
+// measurements that were collected. With the data disk measured by host metrics the score
+// is its free share; otherwise it is the database's share of a known total. It reports
+// false when neither can be worked out.
+func storageHealth(storage *adapter.StorageMetrics, m *Measurements) (float64, bool) {
+	if storage == nil {
+		return 0, false
+	}
+
+	m.UsedStorageBytes = storage.UsedSizeBytes
+	m.TotalStorageBytes = storage.TotalSizeBytes
+	m.FreeStorageBytes = storage.FreeSpaceBytes
+
+	if storage.TotalSizeBytes == nil || *storage.TotalSizeBytes <= 0 {
+		return 0, false
+	}
+	total := float64(*storage.TotalSizeBytes)
+
+	switch {
+	case storage.FreeSpaceBytes != nil:
+		return clampUnit(float64(*storage.FreeSpaceBytes) / total), true
+	case storage.UsedSizeBytes != nil:
+		return clampUnit(1.0 - float64(*storage.UsedSizeBytes)/total), true
 	default:
-		return nil
+		return 0, false
+	}
+}
+
+func clampUnit(v float64) float64 {
+	return min(max(v, 0), 1)
+}
+
+// measureInterval sets the interval since the previous collection and whether deltas over
+// it are reliable. The monotonic clock is used when both collections recorded it, so an
+// NTP step between them doesn't distort the interval; otherwise the wall-clock timestamps
+// are the only measure.
+func measureInterval(current, previous *NormalisedMetrics) {
+	var interval float64
+	if !current.CollectedAt.IsZero() && !previous.CollectedAt.IsZero() {
+		interval = current.CollectedAt.Sub(previous.CollectedAt).Seconds()
+		current.ElapsedSeconds = max(interval, 0)
+	} else {
+		interval = float64(current.Timestamp - previous.Timestamp)
+	}
+
+	minimum := MinDeltaInterval.Seconds()
+	current.TimeDeltaSeconds = max(interval, minimum)
+	current.DeltasReliable = interval >= minimum
+}
+
+// storageDelta records how much the database's used storage grew since the previous
+// collection. Storage is a gauge, so a shrink - after VACUUM FULL, say - is a negative delta.
+func storageDelta(current, previous *NormalisedMetrics) {
+	if current.Measurements.UsedStorageBytes != nil && previous.Measurements.UsedStorageBytes != nil {
+		current.MetricDeltas["used_storage_bytes"] = float64(*current.Measurements.UsedStorageBytes - *previous.Measurements.UsedStorageBytes)
+	}
+}
+
+// counterDelta returns how much a cumulative counter grew. A counter that went backwards
+// was reset - the database restarted or its statistics were cleared - so everything it has
+// counted since happened this cycle, and the deltas are flagged unreliable.
+func counterDelta(current *NormalisedMetrics, currentVal, previousVal float64) float64 {
+	if currentVal < previousVal {
+		current.DeltasReliable = false
+		return currentVal
 	}
+	return currentVal - previousVal
 }
@@ -3,6 +3,7 @@ package normaliser
 
 import (
 	"math"
+	"strings"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/adapter"
 )
@@ -12,6 +13,10 @@ type PostgresNormaliser struct {
 	previousMetrics map[string]*NormalisedMetrics
 }
 
+func init() {
+	Register("postgres", func() Normaliser { return NewPostgresNormaliser() })
+}
+
 // NewPostgresNormaliser creates a new PostgreSQL normaliser.
 func NewPostgresNormaliser() *PostgresNormaliser {
 	return &PostgresNormaliser{
@@ -26,6 +31,7 @@ func (n *PostgresNormaliser) Normalise(raw *adapter.RawMetrics) (*NormalisedMetr
 		DatabaseID:       raw.DatabaseID,
 		DatabaseType:     raw.DatabaseType,
 		Timestamp:        raw.Timestamp,
+		CollectedAt:      raw.CollectedAt,
 		Measurements:     Measurements{},
 		MetricDeltas:     make(map[string]float64),
 		TimeDeltaSeconds: 0,
@@ -108,31 +114,24 @@ func (n *PostgresNormaliser) Normalise(raw *adapter.RawMetrics) (*NormalisedMetr
 		normalised.QueryHealth = 1.0
 	}
 
-	// Storage health: based on used/total ratio
-	if raw.Storage != nil && raw.Storage.UsedSizeBytes != nil && raw.Storage.TotalSizeBytes != nil {
-		used := float64(*raw.Storage.UsedSizeBytes)
-		total := float64(*raw.Storage.TotalSizeBytes)
-
-		if total > 0 {
-			normalised.StorageHealth = 1.0 - (used / total)
-
-			normalised.Measurements.UsedStorageBytes = raw.Storage.UsedSizeBytes
-			normalised.Measurements.TotalStorageBytes = raw.Storage.TotalSizeBytes
-			normalised.Measurements.FreeStorageBytes = raw.Storage.FreeSpaceBytes
-
-			healthScores = append(healthScores, normalised.StorageHealth)
-		} else {
-			normalised.StorageHealth = 1.0
-		}
+	// Storage health: free share of the data disk, when host metrics measured it
+	if health, ok := storageHealth(raw.Storage, &normalised.Measurements); ok {
+		normalised.StorageHealth = health
+		healthScores = append(healthScores, normalised.StorageHealth)
 	} else {
 		normalised.StorageHealth = 1.0
 	}
 
-	// Cache health: direct hit rate (already 0.0-1.0)
+	// Cache health: hit rate over the last cycle when there is one, so a long-lived
+	// database isn't dominated by historical stats; otherwise the lifetime rate
 	if raw.Cache != nil && raw.Cache.HitRate != nil {
-		normalised.CacheHealth = *raw.Cache.HitRate
+		hitRate := *raw.Cache.HitRate
+		if cycleRate, ok := n.cycleCacheHitRate(raw.DatabaseID, raw.Cache); ok {
+			hitRate = cycleRate
+		}
+		normalised.CacheHealth = hitRate
 
-		normalised.Measurements.CacheHitRate = raw.Cache.HitRate
+		normalised.Measurements.CacheHitRate = &hitRate
 		normalised.Measurements.CacheHitCount = raw.Cache.HitCount
 		normalised.Measurements.CacheMissCount = raw.Cache.MissCount
 
@@ -161,6 +160,8 @@ func (n *PostgresNormaliser) Normalise(raw *adapter.RawMetrics) (*NormalisedMetr
 		normalised.Labels = raw.Labels
 	}
 
+	normalised.AvailableMetrics = availableMetrics(normalised.Measurements, normalised.ExtendedMetrics)
+
 	// Calculate deltas from previous collection
 	n.calculateDeltas(normalised)
 	n.previousMetrics[normalised.DatabaseID] = normalised
@@ -168,34 +169,43 @@ func (n *PostgresNormaliser) Normalise(raw *adapter.RawMetrics) (*NormalisedMetr
 	return normalised, nil
 }
 
+// cycleCacheHitRate returns the hit rate from blocks accessed since the previous
+// collection. ok is false without a previous sample, after a counter reset, or when
+// nothing was read this cycle.
+func (n *PostgresNormaliser) cycleCacheHitRate(databaseID string, cache *adapter.CacheMetrics) (float64, bool) {
+	previous, exists := n.previousMetrics[databaseID]
+	if !exists || cache.HitCount == nil || cache.MissCount == nil ||
+		previous.Measurements.CacheHitCount == nil || previous.Measurements.CacheMissCount == nil {
+		return 0, false
+	}
+
+	hits := *cache.HitCount - *previous.Measurements.CacheHitCount
+	misses := *cache.MissCount - *previous.Measurements.CacheMissCount
+	if hits < 0 || misses < 0 || hits+misses == 0 {
+		return 0, false
+	}
+
+	return adapter.CacheHitRate(hits, misses), true
+}
+
 // calculateDeltas computes metric changes between collection cycles.
 func (n *PostgresNormaliser) calculateDeltas(current *NormalisedMetrics) {
 	previous, exists := n.previousMetrics[current.DatabaseID]
 
 	if !exists {
 		current.TimeDeltaSeconds = 0
+		current.DeltasReliable = false
 		current.MetricDeltas = make(map[string]float64)
 		return
 	}
 
-	timeDelta := float64(current.Timestamp - previous.Timestamp)
-	if timeDelta <= 0 {
-		current.TimeDeltaSeconds = 0
-		return
-	}
-	current.TimeDeltaSeconds = timeDelta
+	measureInterval(current, previous)
+	storageDelta(current, previous)
 
 	// Sequential scans delta
 	if current.Measurements.SequentialScans != nil && previous.Measurements.SequentialScans != nil {
-		currentVal := float64(*current.Measurements.SequentialScans)
-		previousVal := float64(*previous.Measurements.SequentialScans)
-		delta := currentVal - previousVal
-
-		if delta < 0 {
-			delta = 0
-		}
-
-		current.MetricDeltas["sequential_scans"] = delta
+		current.MetricDeltas["sequential_scans"] = counterDelta(current,
+			float64(*current.Measurements.SequentialScans), float64(*previous.Measurements.SequentialScans))
 	}
 
 	// Slow query count delta
@@ -213,14 +223,75 @@ func (n *PostgresNormaliser) calculateDeltas(current *NormalisedMetrics) {
 
 	// Cache miss count delta
 	if current.Measurements.CacheMissCount != nil && previous.Measurements.CacheMissCount != nil {
-		currentVal := float64(*current.Measurements.CacheMissCount)
-		previousVal := float64(*previous.Measurements.CacheMissCount)
-		delta := currentVal - previousVal
+		current.MetricDeltas["cache_miss_count"] = counterDelta(current,
+			float64(*current.Measurements.CacheMissCount), float64(*previous.Measurements.CacheMissCount))
+	}
 
-		if delta < 0 {
-			delta = 0
+	// Cache hit count delta
+	if current.Measurements.CacheHitCount != nil && previous.Measurements.CacheHitCount != nil {
+		current.MetricDeltas["cache_hit_count"] = counterDelta(current,
+			float64(*current.Measurements.CacheHitCount), float64(*previous.Measurements.CacheHitCount))
+	}
+
+	// Deadlock delta (pg_stat_database.deadlocks is cumulative)
+	currentDeadlocks, hasCurrent := current.ExtendedMetrics["pg.deadlocks"]
+	previousDeadlocks, hasPrevious := previous.ExtendedMetrics["pg.deadlocks"]
+	if hasCurrent && hasPrevious {
+		current.MetricDeltas["deadlocks"] = counterDelta(current, currentDeadlocks, previousDeadlocks)
+	}
+
+	// Sessions established and transactions finished (pg_stat_database counters), whose
+	// rates show connection churn that a snapshot of open connections misses, and temp
+	// files written by queries spilling past work_mem
+	for key, name := range map[string]string{
+		"pg.sessions_total": "sessions",
+		"pg.xact_total":     "transactions",
+		"pg.temp_files":     "temp_files",
+		"pg.temp_bytes":     "temp_bytes",
+	} {
+		currentVal, hasCurrent := current.ExtendedMetrics[key]
+		previousVal, hasPrevious := previous.ExtendedMetrics[key]
+		if hasCurrent && hasPrevious {
+			current.MetricDeltas[name] = counterDelta(current, currentVal, previousVal)
+		}
+	}
+
+	// Per-table dead tuple deltas (gauge - negative deltas mean VACUUM reclaimed tuples)
+	for key, currentVal := range current.ExtendedMetrics {
+		if !strings.HasPrefix(key, "pg.table.") || !strings.HasSuffix(key, ".dead_tuples") {
+			continue
+		}
+
+		if previousVal, ok := previous.ExtendedMetrics[key]; ok {
+			current.MetricDeltas[key] = currentVal - previousVal
+		}
+	}
+
+	// Per-table sequential scan deltas (seq_scan is cumulative)
+	for key, currentVal := range current.ExtendedMetrics {
+		if !strings.HasPrefix(key, "pg.table.") || !strings.HasSuffix(key, ".seq_scans") {
+			continue
+		}
+
+		previousVal, ok := previous.ExtendedMetrics[key]
+		if !ok {
+			continue
+		}
+
+		current.MetricDeltas[key] = counterDelta(current, currentVal, previousVal)
+	}
+
+	// Per-index scan deltas (idx_scan is cumulative)
+	for key, currentVal := range current.ExtendedMetrics {
+		if !strings.HasPrefix(key, "pg.index.") || !strings.HasSuffix(key, ".idx_scans") {
+			continue
+		}
+
+		previousVal, ok := previous.ExtendedMetrics[key]
+		if !ok {
+			continue
 		}
 
-		current.MetricDeltas["cache_miss_count"] = delta
+		current.MetricDeltas[key] = counterDelta(current, currentVal, previousVal)
 	}
 }
@@ -0,0 +1,15 @@
+package normaliser
+
+// Normaliser constructors by canonical database type
+var constructors = make(map[string]func() Normaliser)
+
+// Register adds the normaliser for a database type. Each normaliser calls it from its own
+// init, under the name its adapter's driver registers.
+func Register(dbType string, newNormaliser func() Normaliser) {
+	constructors[dbType] = newNormaliser
+}
+
+// Constructor returns the normaliser constructor registered for dbType, or nil.
+func Constructor(dbType string) func() Normaliser {
+	return constructors[dbType]
+}
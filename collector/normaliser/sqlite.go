@@ -0,0 +1,69 @@
+package normaliser
+
+import (
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/adapter"
+)
+
+// SQLiteNormaliser converts raw SQLite metrics to normalised health scores. SQLite has no
+// connection pool and keeps no cache or scan counters the Collector can read, so only
+// storage is scored, and only when host metrics measured the disk holding the file.
+type SQLiteNormaliser struct {
+	previousMetrics map[string]*NormalisedMetrics
+}
+
+func init() {
+	Register("sqlite", func() Normaliser { return NewSQLiteNormaliser() })
+}
+
+// NewSQLiteNormaliser creates a new SQLite normaliser.
+func NewSQLiteNormaliser() *SQLiteNormaliser {
+	return &SQLiteNormaliser{
+		previousMetrics: make(map[string]*NormalisedMetrics),
+	}
+}
+
+// Normalise converts raw SQLite metrics to normalised health scores.
+func (n *SQLiteNormaliser) Normalise(raw *adapter.RawMetrics) (*NormalisedMetrics, error) {
+	normalised := &NormalisedMetrics{
+		DatabaseID:       raw.DatabaseID,
+		DatabaseType:     raw.DatabaseType,
+		Timestamp:        raw.Timestamp,
+		CollectedAt:      raw.CollectedAt,
+		Measurements:     Measurements{},
+		MetricDeltas:     make(map[string]float64),
+		TimeDeltaSeconds: 0,
+		ExtendedMetrics:  make(map[string]float64),
+		Labels:           make(map[string]string),
+
+		// Nothing to measure, so nothing to penalise
+		ConnectionHealth: 1.0,
+		QueryHealth:      1.0,
+		CacheHealth:      1.0,
+		StorageHealth:    1.0,
+		HealthScore:      1.0,
+	}
+
+	if health, ok := storageHealth(raw.Storage, &normalised.Measurements); ok {
+		normalised.StorageHealth = health
+		normalised.HealthScore = health
+	}
+
+	if raw.ExtendedMetrics != nil {
+		normalised.ExtendedMetrics = raw.ExtendedMetrics
+	}
+
+	if raw.Labels != nil {
+		normalised.Labels = raw.Labels
+	}
+
+	normalised.AvailableMetrics = availableMetrics(normalised.Measurements, normalised.ExtendedMetrics)
+
+	// The file's growth is the only delta there is
+	if previous, exists := n.previousMetrics[normalised.DatabaseID]; exists {
+		measureInterval(normalised, previous)
+		storageDelta(normalised, previous)
+	}
+	n.previousMetrics[normalised.DatabaseID] = normalised
+
+	return normalised, nil
+}
@@ -0,0 +1,92 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/adapter"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheHitRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		hits     int64
+		misses   int64
+		expected float64
+	}{
+		{"mostly hits", 1000, 50, 1000.0 / 1050.0},
+		{"mostly misses", 50, 1000, 50.0 / 1050.0},
+		{"all hits", 500, 0, 1.0},
+		{"all misses", 0, 500, 0.0},
+		{"no block accesses", 0, 0, 1.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate := adapter.CacheHitRate(tt.hits, tt.misses)
+
+			assert.InDelta(t, tt.expected, rate, 1e-9)
+			assert.GreaterOrEqual(t, rate, 0.0)
+			assert.LessOrEqual(t, rate, 1.0)
+		})
+	}
+}
+
+func cacheSnapshot(timestamp, hits, misses int64) *adapter.RawMetrics {
+	rate := adapter.CacheHitRate(hits, misses)
+	return &adapter.RawMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "postgres",
+		Timestamp:    timestamp,
+		Cache: &adapter.CacheMetrics{
+			HitRate:   &rate,
+			HitCount:  &hits,
+			MissCount: &misses,
+		},
+	}
+}
+
+func TestPostgresNormaliser_CacheHealthFromLifetimeCounters(t *testing.T) {
+	n := normaliser.NewPostgresNormaliser()
+
+	// blks_hit=1000, blks_read=50 is a healthy ~95% hit rate
+	normalised, err := n.Normalise(cacheSnapshot(100, 1000, 50))
+	require.NoError(t, err)
+
+	assert.InDelta(t, 0.952, normalised.CacheHealth, 0.001)
+	require.NotNil(t, normalised.Measurements.CacheHitRate)
+	assert.InDelta(t, 0.952, *normalised.Measurements.CacheHitRate, 0.001)
+	assert.Equal(t, int64(1000), *normalised.Measurements.CacheHitCount)
+	assert.Equal(t, int64(50), *normalised.Measurements.CacheMissCount)
+}
+
+func TestPostgresNormaliser_CacheHealthUsesCycleDeltas(t *testing.T) {
+	n := normaliser.NewPostgresNormaliser()
+
+	_, err := n.Normalise(cacheSnapshot(100, 1_000_000, 1_000))
+	require.NoError(t, err)
+
+	// This cycle: 500 hits, 500 misses - the lifetime rate would still read ~99.8%
+	normalised, err := n.Normalise(cacheSnapshot(110, 1_000_500, 1_500))
+	require.NoError(t, err)
+
+	assert.InDelta(t, 0.5, normalised.CacheHealth, 1e-9)
+	assert.InDelta(t, 0.5, *normalised.Measurements.CacheHitRate, 1e-9)
+	assert.Equal(t, 500.0, normalised.MetricDeltas["cache_hit_count"])
+	assert.Equal(t, 500.0, normalised.MetricDeltas["cache_miss_count"])
+}
+
+func TestPostgresNormaliser_CacheHealthFallsBackOnIdleCycle(t *testing.T) {
+	n := normaliser.NewPostgresNormaliser()
+
+	_, err := n.Normalise(cacheSnapshot(100, 900, 100))
+	require.NoError(t, err)
+
+	// No blocks accessed since the last cycle - keep the lifetime rate
+	normalised, err := n.Normalise(cacheSnapshot(110, 900, 100))
+	require.NoError(t, err)
+
+	assert.InDelta(t, 0.9, normalised.CacheHealth, 1e-9)
+}
@@ -0,0 +1,145 @@
+package unit
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/config"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/health"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/schedule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestInterval() *schedule.Interval {
+	return schedule.NewInterval(schedule.Settings{
+		Base:            10 * time.Second,
+		Max:             40 * time.Second,
+		HealthThreshold: 0.5,
+		Budget:          2 * time.Second,
+	})
+}
+
+// dueTicks counts how many of n ticks a collection falls on.
+func dueTicks(interval *schedule.Interval, n int) int {
+	due := 0
+	for range n {
+		if interval.Tick() {
+			due++
+		}
+	}
+	return due
+}
+
+func TestInterval_StartsAtBase(t *testing.T) {
+	interval := newTestInterval()
+
+	assert.Equal(t, 10*time.Second, interval.Current())
+	assert.Equal(t, 4, dueTicks(interval, 4), "every tick is due at the base interval")
+	assert.Empty(t, interval.Reason())
+}
+
+func TestInterval_StretchesWhileUnhealthy(t *testing.T) {
+	interval := newTestInterval()
+
+	assert.True(t, interval.Observe(0.3, true, 100*time.Millisecond))
+	assert.Equal(t, 20*time.Second, interval.Current())
+	assert.Contains(t, interval.Reason(), "health score 0.30 below 0.50")
+
+	interval.Observe(0.3, true, 100*time.Millisecond)
+	assert.Equal(t, 40*time.Second, interval.Current())
+
+	// Capped at Max
+	assert.False(t, interval.Observe(0.3, true, 100*time.Millisecond))
+	assert.Equal(t, 40*time.Second, interval.Current())
+
+	interval.Tick()
+	assert.Equal(t, 2, dueTicks(interval, 8), "one collection every 4 ticks")
+}
+
+func TestInterval_StretchesWhenQueriesOverBudget(t *testing.T) {
+	interval := newTestInterval()
+
+	// A failed collection's health is unknown, but its queries still count
+	assert.True(t, interval.Observe(0, false, 3*time.Second))
+	assert.Equal(t, 20*time.Second, interval.Current())
+	assert.Contains(t, interval.Reason(), "over the 2s budget")
+
+	stats := interval.Stats()
+	assert.Equal(t, 20.0, stats.IntervalSeconds)
+	assert.Equal(t, 10.0, stats.BaseIntervalSeconds)
+	assert.Equal(t, 3000.0, stats.LastCollectionMs)
+}
+
+func TestInterval_ShrinksBackOnRecovery(t *testing.T) {
+	interval := newTestInterval()
+	interval.Observe(0.2, true, 0)
+	interval.Observe(0.2, true, 0)
+	require.Equal(t, 40*time.Second, interval.Current())
+
+	assert.True(t, interval.Observe(0.9, true, 100*time.Millisecond))
+	assert.Equal(t, 20*time.Second, interval.Current())
+	assert.NotEmpty(t, interval.Reason(), "still stretched, for the reason it was stretched")
+
+	assert.True(t, interval.Observe(0.9, true, 100*time.Millisecond))
+	assert.Equal(t, 10*time.Second, interval.Current())
+	assert.Empty(t, interval.Reason())
+
+	assert.False(t, interval.Observe(0.9, true, 100*time.Millisecond))
+}
+
+func TestInterval_FixedWhenMaxIsBase(t *testing.T) {
+	interval := schedule.NewInterval(schedule.Settings{Base: 10 * time.Second, Max: 10 * time.Second, HealthThreshold: 0.5})
+
+	assert.False(t, interval.Observe(0.1, true, time.Minute))
+	assert.Equal(t, 10*time.Second, interval.Current())
+}
+
+func TestInterval_DisabledTriggers(t *testing.T) {
+	interval := schedule.NewInterval(schedule.Settings{Base: 10 * time.Second, Max: time.Minute})
+
+	assert.False(t, interval.Observe(0.1, true, time.Minute))
+	assert.Equal(t, 10*time.Second, interval.Current())
+}
+
+func TestHealthServer_ReportsCollectionIntervals(t *testing.T) {
+	s := health.NewServer("collector", "0")
+	interval := newTestInterval()
+	interval.Observe(0.3, true, 0)
+	s.SetCollectionStats(func() map[string]schedule.Stats {
+		return map[string]schedule.Stats{"db-1": interval.Stats()}
+	})
+	startHealthServer(t, s)
+
+	_, body := getHealth(t, s)
+
+	assert.Equal(t, "healthy", body.Status, "backing off is not a health problem")
+	require.Contains(t, body.Collection, "db-1")
+	assert.Equal(t, 20.0, body.Collection["db-1"].IntervalSeconds)
+	assert.Contains(t, body.Collection["db-1"].Reason, "health score")
+}
+
+func TestConfig_Load_CollectionBackoffDefaults(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("ANALYSER_ADDRESS", "localhost:50051")
+	os.Setenv("KNOWLEDGE_ADDRESS", "localhost:50053")
+	defer os.Clearenv()
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 60*time.Second, cfg.CollectionMaxInterval)
+	assert.Equal(t, 0.5, cfg.CollectionBackoffHealth)
+	assert.Equal(t, 2*time.Second, cfg.CollectionBudget)
+
+	// A longer base interval is never shortened by the default maximum
+	os.Setenv("COLLECTION_INTERVAL", "2m")
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, cfg.CollectionMaxInterval)
+
+	os.Setenv("COLLECTION_MAX_INTERVAL", "1m")
+	_, err = config.Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "COLLECTION_MAX_INTERVAL")
+}
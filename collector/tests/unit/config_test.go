@@ -71,6 +71,7 @@ func TestConfig_Validate_ValidConfig(t *testing.T) {
 		KnowledgeAddress:   "localhost:50053",
 		CollectionInterval: 30 * time.Second,
 		SyncInterval:       30 * time.Second,
+		IndexCandidates:    5,
 	}
 
 	err := cfg.Validate()
@@ -96,6 +97,7 @@ func TestConfig_Load_WithDefaults(t *testing.T) {
 	assert.Equal(t, "localhost:50053", cfg.KnowledgeAddress)
 	assert.Equal(t, 10*time.Second, cfg.CollectionInterval) // Default
 	assert.Equal(t, 30*time.Second, cfg.SyncInterval)       // Default
+	assert.Equal(t, 5, cfg.IndexCandidates)                 // Default
 }
 
 func TestConfig_Load_CustomIntervals(t *testing.T) {
@@ -162,3 +164,124 @@ func TestConfig_Load_MetricsPublishingFlag(t *testing.T) {
 	assert.NoError(t, err)
 	assert.False(t, cfg.EnableMetricsPublishing)
 }
+
+func TestConfig_Load_DatabasesCommaSeparated(t *testing.T) {
+	os.Clearenv()
+
+	os.Setenv("ANALYSER_ADDRESS", "localhost:50051")
+	os.Setenv("KNOWLEDGE_ADDRESS", "localhost:50053")
+	os.Setenv("DATABASES", "orders|postgres|postgres://localhost:5432/orders|Orders, users|mysql|user:pass@tcp(localhost:3306)/users")
+
+	defer os.Clearenv()
+
+	cfg, err := config.Load()
+
+	assert.NoError(t, err)
+	assert.Len(t, cfg.Databases, 2)
+	assert.Equal(t, "orders", cfg.Databases[0].ID)
+	assert.Equal(t, "postgres", cfg.Databases[0].Type)
+	assert.Equal(t, "postgres://localhost:5432/orders", cfg.Databases[0].ConnectionString)
+	assert.Equal(t, "Orders", cfg.Databases[0].Name)
+	assert.Equal(t, "users", cfg.Databases[1].ID)
+	assert.Equal(t, "users", cfg.Databases[1].Name) // Defaults to ID
+}
+
+func TestConfig_Load_DatabasesJSON(t *testing.T) {
+	os.Clearenv()
+
+	os.Setenv("ANALYSER_ADDRESS", "localhost:50051")
+	os.Setenv("KNOWLEDGE_ADDRESS", "localhost:50053")
+	os.Setenv("DATABASES", `[{"id":"orders","type":"postgres","connection_string":"postgres://localhost:5432/orders?sslmode=disable","name":"Orders"}]`)
+
+	defer os.Clearenv()
+
+	cfg, err := config.Load()
+
+	assert.NoError(t, err)
+	assert.Len(t, cfg.Databases, 1)
+	assert.Equal(t, "postgres://localhost:5432/orders?sslmode=disable", cfg.Databases[0].ConnectionString)
+}
+
+func TestConfig_Load_DatabasesInvalid(t *testing.T) {
+	os.Clearenv()
+
+	os.Setenv("ANALYSER_ADDRESS", "localhost:50051")
+	os.Setenv("KNOWLEDGE_ADDRESS", "localhost:50053")
+	os.Setenv("DATABASES", "orders|postgres")
+
+	defer os.Clearenv()
+
+	cfg, err := config.Load()
+
+	assert.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "DATABASES")
+}
+
+func TestConfig_Load_DatabasesDuplicateID(t *testing.T) {
+	os.Clearenv()
+
+	os.Setenv("ANALYSER_ADDRESS", "localhost:50051")
+	os.Setenv("KNOWLEDGE_ADDRESS", "localhost:50053")
+	os.Setenv("DATABASES", "orders|postgres|postgres://a/orders,orders|postgres|postgres://b/orders")
+
+	defer os.Clearenv()
+
+	cfg, err := config.Load()
+
+	assert.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "duplicate")
+}
+
+func TestConfig_Load_LegacySingleDatabase(t *testing.T) {
+	os.Clearenv()
+
+	os.Setenv("ANALYSER_ADDRESS", "localhost:50051")
+	os.Setenv("KNOWLEDGE_ADDRESS", "localhost:50053")
+	os.Setenv("DB_CONNECTION_STRING", "postgres://localhost:5432/testdb")
+	os.Setenv("DATABASE_ID", "docker-test-db")
+
+	defer os.Clearenv()
+
+	cfg, err := config.Load()
+
+	assert.NoError(t, err)
+	assert.Len(t, cfg.Databases, 1)
+	assert.Equal(t, "docker-test-db", cfg.Databases[0].ID)
+	assert.Equal(t, "postgres", cfg.Databases[0].Type)
+}
+
+func TestConfig_Load_DatabasesContainer(t *testing.T) {
+	os.Clearenv()
+
+	os.Setenv("ANALYSER_ADDRESS", "localhost:50051")
+	os.Setenv("KNOWLEDGE_ADDRESS", "localhost:50053")
+	os.Setenv("DATABASES", "orders|postgres|postgres://localhost:5432/orders|Orders|orders-postgres,users|postgres|postgres://localhost:5433/users")
+
+	defer os.Clearenv()
+
+	cfg, err := config.Load()
+
+	assert.NoError(t, err)
+	assert.Len(t, cfg.Databases, 2)
+	assert.Equal(t, "orders-postgres", cfg.Databases[0].Container)
+	assert.Empty(t, cfg.Databases[1].Container)
+}
+
+func TestConfig_Load_LegacySingleDatabaseContainer(t *testing.T) {
+	os.Clearenv()
+
+	os.Setenv("ANALYSER_ADDRESS", "localhost:50051")
+	os.Setenv("KNOWLEDGE_ADDRESS", "localhost:50053")
+	os.Setenv("DB_CONNECTION_STRING", "postgres://localhost:5432/testdb")
+	os.Setenv("DB_CONTAINER", "startupmonkey-postgres")
+
+	defer os.Clearenv()
+
+	cfg, err := config.Load()
+
+	assert.NoError(t, err)
+	assert.Len(t, cfg.Databases, 1)
+	assert.Equal(t, "startupmonkey-postgres", cfg.Databases[0].Container)
+}
@@ -0,0 +1,122 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/adapter"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seqScanSnapshot(timestamp int64, collectedAt time.Time, seqScans int32, tableScans float64) *adapter.RawMetrics {
+	return &adapter.RawMetrics{
+		DatabaseID:      "test-db",
+		DatabaseType:    "postgres",
+		Timestamp:       timestamp,
+		CollectedAt:     collectedAt,
+		Queries:         &adapter.QueryMetrics{SequentialScans: &seqScans},
+		ExtendedMetrics: map[string]float64{"pg.table.orders.seq_scans": tableScans},
+		Labels:          map[string]string{},
+	}
+}
+
+func TestPostgresNormaliser_FirstSnapshotHasNoReliableDeltas(t *testing.T) {
+	n := normaliser.NewPostgresNormaliser()
+
+	normalised, err := n.Normalise(seqScanSnapshot(100, time.Now(), 10, 10))
+	require.NoError(t, err)
+
+	assert.False(t, normalised.DeltasReliable)
+	assert.Zero(t, normalised.TimeDeltaSeconds)
+	assert.Empty(t, normalised.MetricDeltas)
+}
+
+func TestPostgresNormaliser_BackwardsClockUsesMonotonicInterval(t *testing.T) {
+	n := normaliser.NewPostgresNormaliser()
+	start := time.Now()
+
+	_, err := n.Normalise(seqScanSnapshot(1000, start, 100, 100))
+	require.NoError(t, err)
+
+	// NTP stepped the wall clock back a minute between collections 10s apart
+	normalised, err := n.Normalise(seqScanSnapshot(950, start.Add(10*time.Second), 160, 160))
+	require.NoError(t, err)
+
+	assert.True(t, normalised.DeltasReliable)
+	assert.InDelta(t, 10.0, normalised.ElapsedSeconds, 0.001)
+	assert.InDelta(t, 10.0, normalised.TimeDeltaSeconds, 0.001)
+	assert.Equal(t, 60.0, normalised.MetricDeltas["sequential_scans"])
+	assert.Equal(t, 60.0, normalised.MetricDeltas["pg.table.orders.seq_scans"])
+}
+
+func TestPostgresNormaliser_BackwardsClockWithoutMonotonicIsClampedAndFlagged(t *testing.T) {
+	n := normaliser.NewPostgresNormaliser()
+
+	_, err := n.Normalise(seqScanSnapshot(1000, time.Time{}, 100, 100))
+	require.NoError(t, err)
+
+	normalised, err := n.Normalise(seqScanSnapshot(950, time.Time{}, 160, 160))
+	require.NoError(t, err)
+
+	assert.False(t, normalised.DeltasReliable)
+	assert.Equal(t, normaliser.MinDeltaInterval.Seconds(), normalised.TimeDeltaSeconds, "A negative interval is clamped, never used")
+	assert.Equal(t, 60.0, normalised.MetricDeltas["sequential_scans"], "Deltas are still reported, just flagged")
+}
+
+func TestPostgresNormaliser_SameSecondSnapshotsAreFlagged(t *testing.T) {
+	n := normaliser.NewPostgresNormaliser()
+	start := time.Now()
+
+	_, err := n.Normalise(seqScanSnapshot(1000, start, 100, 100))
+	require.NoError(t, err)
+
+	normalised, err := n.Normalise(seqScanSnapshot(1000, start.Add(200*time.Millisecond), 101, 101))
+	require.NoError(t, err)
+
+	assert.False(t, normalised.DeltasReliable)
+	assert.InDelta(t, 0.2, normalised.ElapsedSeconds, 0.001)
+	assert.Equal(t, normaliser.MinDeltaInterval.Seconds(), normalised.TimeDeltaSeconds)
+	assert.Equal(t, 1.0, normalised.MetricDeltas["sequential_scans"])
+}
+
+func TestPostgresNormaliser_CounterResetAfterRestart(t *testing.T) {
+	n := normaliser.NewPostgresNormaliser()
+	start := time.Now()
+
+	_, err := n.Normalise(seqScanSnapshot(1000, start, 5000, 4000))
+	require.NoError(t, err)
+
+	// The database restarted: seq_scan counters start again from zero
+	normalised, err := n.Normalise(seqScanSnapshot(1010, start.Add(10*time.Second), 30, 25))
+	require.NoError(t, err)
+
+	assert.False(t, normalised.DeltasReliable)
+	assert.Equal(t, 30.0, normalised.MetricDeltas["sequential_scans"], "Everything counted since the reset happened this cycle")
+	assert.Equal(t, 25.0, normalised.MetricDeltas["pg.table.orders.seq_scans"])
+
+	next, err := n.Normalise(seqScanSnapshot(1020, start.Add(20*time.Second), 90, 70))
+	require.NoError(t, err)
+
+	assert.True(t, next.DeltasReliable, "The cycle after a reset is reliable again")
+	assert.Equal(t, 60.0, next.MetricDeltas["sequential_scans"])
+}
+
+func TestMySQLNormaliser_CounterResetIsFlagged(t *testing.T) {
+	n := normaliser.NewMySQLNormaliser()
+	start := time.Now()
+
+	raw := seqScanSnapshot(1000, start, 500, 0)
+	raw.DatabaseType = "mysql"
+	_, err := n.Normalise(raw)
+	require.NoError(t, err)
+
+	raw = seqScanSnapshot(1010, start.Add(10*time.Second), 3, 0)
+	raw.DatabaseType = "mysql"
+	normalised, err := n.Normalise(raw)
+	require.NoError(t, err)
+
+	assert.False(t, normalised.DeltasReliable)
+	assert.Equal(t, 3.0, normalised.MetricDeltas["sequential_scans"])
+}
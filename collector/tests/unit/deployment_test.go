@@ -0,0 +1,115 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/deployment"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/knowledge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeContainerLookup resolves hosts from a fixed map.
+type fakeContainerLookup struct {
+	containers map[string]string // host -> container name
+	err        error
+}
+
+func (f *fakeContainerLookup) FindContainer(ctx context.Context, host string) (string, error) {
+	return f.containers[host], f.err
+}
+
+func TestDeploymentDetect(t *testing.T) {
+	lookup := &fakeContainerLookup{containers: map[string]string{
+		"orders-db":  "orders-postgres",
+		"172.18.0.5": "orders-postgres",
+	}}
+	detector := deployment.NewDetector(lookup)
+
+	tests := []struct {
+		name     string
+		host     string
+		expected deployment.Context
+	}{
+		{"localhost", "localhost", deployment.Context{Kind: deployment.KindLocal}},
+		{"loopback IPv4", "127.0.0.1", deployment.Context{Kind: deployment.KindLocal}},
+		{"loopback IPv6", "::1", deployment.Context{Kind: deployment.KindLocal}},
+		{"container alias", "orders-db", deployment.Context{Kind: deployment.KindDocker, Container: "orders-postgres"}},
+		{"container IP", "172.18.0.5", deployment.Context{Kind: deployment.KindDocker, Container: "orders-postgres"}},
+		{"private IP", "10.0.3.7", deployment.Context{Kind: deployment.KindRemote}},
+		{"bare network alias", "postgres", deployment.Context{Kind: deployment.KindDocker}},
+		{"RDS", "shop.abc123.eu-west-1.rds.amazonaws.com", deployment.Context{Kind: deployment.KindCloud, Provider: "aws-rds"}},
+		{"Supabase", "db.xyz.supabase.co", deployment.Context{Kind: deployment.KindCloud, Provider: "supabase"}},
+		{"other hostname", "db.internal.example.com", deployment.Context{Kind: deployment.KindRemote}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, detector.Detect(context.Background(), tt.host, ""))
+		})
+	}
+}
+
+func TestDeploymentDetect_ConfiguredContainerWins(t *testing.T) {
+	detector := deployment.NewDetector(nil)
+
+	detected := detector.Detect(context.Background(), "localhost", "startupmonkey-postgres")
+
+	assert.Equal(t, deployment.KindDocker, detected.Kind)
+	assert.Equal(t, "startupmonkey-postgres", detected.Container)
+	assert.Equal(t, map[string]string{
+		deployment.MetadataContext:   "docker",
+		deployment.MetadataContainer: "startupmonkey-postgres",
+	}, detected.Metadata())
+}
+
+func TestDeploymentDetect_LookupFailureFallsBack(t *testing.T) {
+	detector := deployment.NewDetector(&fakeContainerLookup{err: errors.New("permission denied")})
+
+	detected := detector.Detect(context.Background(), "db.internal.example.com", "")
+
+	assert.Equal(t, deployment.Context{Kind: deployment.KindRemote}, detected)
+}
+
+func TestDockerLookup_FindsContainerByAlias(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/containers/json", r.URL.Path)
+		w.Write([]byte(`[{"Names":["/orders-postgres"],"NetworkSettings":{"Networks":{"shop":{"IPAddress":"172.18.0.5","Aliases":["orders-db"]}}}}]`))
+	})}
+	go server.Serve(listener)
+	defer server.Close()
+
+	t.Setenv("DOCKER_HOST", "unix://"+socket)
+	lookup := deployment.NewDockerLookup()
+	require.NotNil(t, lookup)
+
+	name, err := lookup.FindContainer(context.Background(), "orders-db")
+	require.NoError(t, err)
+	assert.Equal(t, "orders-postgres", name)
+
+	name, err = lookup.FindContainer(context.Background(), "billing-db")
+	require.NoError(t, err)
+	assert.Empty(t, name)
+}
+
+func TestDockerLookup_UnavailableWithoutSocket(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "unix://"+filepath.Join(t.TempDir(), "missing.sock"))
+
+	assert.Nil(t, deployment.NewDockerLookup())
+}
+
+func TestParseConnectionString_MySQLDSN(t *testing.T) {
+	host, port := knowledge.ParseConnectionString("user:pass@tcp(orders-db:3307)/orders", "mysql")
+
+	assert.Equal(t, "orders-db", host)
+	assert.Equal(t, int32(3307), port)
+}
@@ -1,25 +1,31 @@
 package unit
 
 import (
-	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
 	"testing"
 	"time"
 
 	grpcclient "github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/grpc"
 	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/transport"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 )
 
 func TestNewMetricsClient(t *testing.T) {
 	address := "localhost:50051"
 
-	client := grpcclient.NewMetricsClient(address)
+	client := grpcclient.NewMetricsClient(address, transport.TLSConfig{})
 
 	assert.NotNil(t, client)
 }
 
 func TestMetricsClient_Connect(t *testing.T) {
-	client := grpcclient.NewMetricsClient("localhost:50051")
+	client := grpcclient.NewMetricsClient("localhost:50051", transport.TLSConfig{})
 
 	err := client.Connect()
 
@@ -29,7 +35,7 @@ func TestMetricsClient_Connect(t *testing.T) {
 }
 
 func TestMetricsClient_Connect_InvalidAddress(t *testing.T) {
-	client := grpcclient.NewMetricsClient("")
+	client := grpcclient.NewMetricsClient("", transport.TLSConfig{})
 
 	err := client.Connect()
 
@@ -37,7 +43,7 @@ func TestMetricsClient_Connect_InvalidAddress(t *testing.T) {
 }
 
 func TestMetricsClient_Close_SafeWhenNotConnected(t *testing.T) {
-	client := grpcclient.NewMetricsClient("localhost:50051")
+	client := grpcclient.NewMetricsClient("localhost:50051", transport.TLSConfig{})
 
 	// Close should not error if not connected
 	err := client.Close()
@@ -48,7 +54,7 @@ func TestMetricsClient_Close_SafeWhenNotConnected(t *testing.T) {
 }
 
 func TestMetricsClient_Close_AfterConnect(t *testing.T) {
-	client := grpcclient.NewMetricsClient("localhost:50051")
+	client := grpcclient.NewMetricsClient("localhost:50051", transport.TLSConfig{})
 
 	err := client.Connect()
 	assert.NoError(t, err)
@@ -57,22 +63,222 @@ func TestMetricsClient_Close_AfterConnect(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-func TestMetricsClient_StreamMetrics_NotConnected(t *testing.T) {
-	client := grpcclient.NewMetricsClient("localhost:50051")
+func TestMetricsClient_Send_NotConnected(t *testing.T) {
+	client := grpcclient.NewMetricsClient("localhost:50051", transport.TLSConfig{})
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	err := client.Send(&pb.MetricSnapshot{
+		DatabaseId: "test-db",
+		Timestamp:  time.Now().Unix(),
+	})
 
-	metrics := []*pb.MetricSnapshot{
-		{
-			DatabaseId: "test-db",
-			Timestamp:  time.Now().Unix(),
-		},
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not connected")
+}
+
+// fakeAnalyser records every snapshot it receives and, unless holdAcks is set, acknowledges
+// each one straight away, reporting those from databases in rejectIDs as rejected.
+type fakeAnalyser struct {
+	pb.UnimplementedMetricsServiceServer
+	holdAcks  bool
+	rejectIDs map[string]bool
+
+	mu       sync.Mutex
+	received []string
+	replayed []string
+	streams  int
+}
+
+func (f *fakeAnalyser) StreamMetrics(stream pb.MetricsService_StreamMetricsServer) error {
+	f.mu.Lock()
+	f.streams++
+	f.mu.Unlock()
+
+	count := int64(0)
+	for {
+		snapshot, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		count++
+		f.mu.Lock()
+		f.received = append(f.received, snapshot.DatabaseId)
+		if snapshot.Replayed {
+			f.replayed = append(f.replayed, snapshot.DatabaseId)
+		}
+		f.mu.Unlock()
+
+		if f.holdAcks {
+			continue
+		}
+
+		ack := &pb.MetricsAck{TotalMetrics: count, AckedSequence: snapshot.Sequence, Accepted: 1}
+		if f.rejectIDs[snapshot.DatabaseId] {
+			ack.Accepted, ack.Rejected = 0, 1
+			ack.Rejections = []*pb.SnapshotRejection{
+				{Sequence: snapshot.Sequence, DatabaseId: snapshot.DatabaseId, Reason: "rate_limited"},
+			}
+		}
+		if err := stream.Send(ack); err != nil {
+			return err
+		}
 	}
+}
 
-	ack, err := client.StreamMetrics(ctx, metrics)
+func (f *fakeAnalyser) snapshots() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.received...)
+}
 
-	assert.Error(t, err)
-	assert.Nil(t, ack)
-	assert.Contains(t, err.Error(), "not connected")
+func (f *fakeAnalyser) replays() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.replayed...)
+}
+
+func (f *fakeAnalyser) streamCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.streams
+}
+
+func startFakeAnalyser(t *testing.T, addr string, analyser *fakeAnalyser) *grpc.Server {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", addr)
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	pb.RegisterMetricsServiceServer(server, analyser)
+	go server.Serve(lis)
+
+	return server
+}
+
+func freeAddress(t *testing.T) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	lis.Close()
+
+	return addr
+}
+
+func snapshotFor(id string) *pb.MetricSnapshot {
+	return &pb.MetricSnapshot{DatabaseId: id, Timestamp: time.Now().Unix()}
+}
+
+func TestMetricsClient_SendsEverySnapshotOverOneStream(t *testing.T) {
+	addr := freeAddress(t)
+	analyser := &fakeAnalyser{}
+	server := startFakeAnalyser(t, addr, analyser)
+	defer server.Stop()
+
+	client := grpcclient.NewMetricsClient(addr, transport.TLSConfig{})
+	require.NoError(t, client.Connect())
+	defer client.Close()
+
+	for _, id := range []string{"a", "b", "c"} {
+		require.NoError(t, client.Send(snapshotFor(id)))
+	}
+
+	assert.Eventually(t, func() bool {
+		return client.Stats().BufferedSnapshots == 0
+	}, 2*time.Second, 5*time.Millisecond, "every snapshot should be acknowledged")
+
+	assert.Equal(t, []string{"a", "b", "c"}, analyser.snapshots())
+	assert.Equal(t, 1, analyser.streamCount())
+	assert.Equal(t, uint64(3), client.Stats().LastAckedSequence)
+}
+
+func TestMetricsClient_ResendsUnacknowledgedSnapshotsAfterReconnect(t *testing.T) {
+	addr := freeAddress(t)
+	first := &fakeAnalyser{holdAcks: true}
+	server := startFakeAnalyser(t, addr, first)
+
+	client := grpcclient.NewMetricsClient(addr, transport.TLSConfig{})
+	client.SetMaxBackoff(10 * time.Millisecond)
+	require.NoError(t, client.Connect())
+	defer client.Close()
+
+	require.NoError(t, client.Send(snapshotFor("a")))
+	require.NoError(t, client.Send(snapshotFor("b")))
+	assert.Eventually(t, func() bool { return len(first.snapshots()) == 2 }, 2*time.Second, 5*time.Millisecond)
+
+	// Analyser goes away before acknowledging anything
+	server.Stop()
+	assert.Eventually(t, func() bool { return !client.Stats().Connected }, 2*time.Second, 5*time.Millisecond)
+
+	err := client.Send(snapshotFor("c"))
+	assert.True(t, errors.Is(err, grpcclient.ErrSnapshotsBuffered), "expected buffered error, got %v", err)
+	assert.Equal(t, 3, client.Stats().BufferedSnapshots)
+
+	// Analyser comes back on the same address
+	second := &fakeAnalyser{}
+	server = startFakeAnalyser(t, addr, second)
+	defer server.Stop()
+	time.Sleep(20 * time.Millisecond)
+
+	require.NoError(t, client.Send(snapshotFor("d")))
+
+	assert.Eventually(t, func() bool {
+		return client.Stats().BufferedSnapshots == 0
+	}, 2*time.Second, 5*time.Millisecond)
+
+	assert.Equal(t, []string{"a", "b", "c", "d"}, second.snapshots())
+
+	stats := client.Stats()
+	assert.True(t, stats.Connected)
+	assert.Equal(t, int64(1), stats.Reconnects)
+}
+
+func TestMetricsClient_BufferDropsOldestWhenFull(t *testing.T) {
+	addr := freeAddress(t) // nothing listening - every attempt fails
+
+	client := grpcclient.NewMetricsClient(addr, transport.TLSConfig{})
+	client.SetBufferSize(2)
+	client.SetMaxBackoff(time.Millisecond)
+	require.NoError(t, client.Connect())
+	defer client.Close()
+
+	for _, id := range []string{"a", "b", "c"} {
+		err := client.Send(snapshotFor(id))
+		assert.True(t, errors.Is(err, grpcclient.ErrSnapshotsBuffered), "expected buffered error, got %v", err)
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	stats := client.Stats()
+	assert.False(t, stats.Connected)
+	assert.Equal(t, 2, stats.BufferedSnapshots)
+	assert.Equal(t, int64(1), stats.DroppedSnapshots)
+}
+
+func TestMetricsClient_CountsRejectedSnapshots(t *testing.T) {
+	addr := freeAddress(t)
+	analyser := &fakeAnalyser{rejectIDs: map[string]bool{"noisy": true}}
+	server := startFakeAnalyser(t, addr, analyser)
+	defer server.Stop()
+
+	client := grpcclient.NewMetricsClient(addr, transport.TLSConfig{})
+	require.NoError(t, client.Connect())
+	defer client.Close()
+
+	for _, id := range []string{"noisy", "quiet", "noisy"} {
+		require.NoError(t, client.Send(snapshotFor(id)))
+	}
+
+	// Rejected snapshots are acknowledged, so they leave the buffer rather than being resent
+	assert.Eventually(t, func() bool {
+		return client.Stats().BufferedSnapshots == 0
+	}, 2*time.Second, 5*time.Millisecond)
+
+	stats := client.Stats()
+	assert.Equal(t, int64(2), stats.RejectedSnapshots)
+	assert.Equal(t, uint64(3), stats.LastAckedSequence)
 }
@@ -0,0 +1,95 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	grpcclient "github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/grpc"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getHealth(t *testing.T, s *health.Server) (int, health.HealthResponse) {
+	t.Helper()
+
+	resp, err := http.Get("http://" + s.Addr() + "/health")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var body health.HealthResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return resp.StatusCode, body
+}
+
+func startHealthServer(t *testing.T, s *health.Server) {
+	t.Helper()
+
+	require.NoError(t, s.Start())
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		s.Shutdown(ctx)
+	})
+}
+
+func TestHealthServer_ReportsDependencies(t *testing.T) {
+	s := health.NewServer("collector", "0")
+	s.AddCheck("knowledge", true, func(ctx context.Context) error { return nil })
+	s.AddCheck("databases", false, func(ctx context.Context) error {
+		return errors.New("1 of 2 unreachable: db-2: connection refused")
+	})
+	startHealthServer(t, s)
+
+	code, body := getHealth(t, s)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "degraded", body.Status)
+	assert.Equal(t, "up", body.Dependencies["knowledge"].Status)
+	assert.Equal(t, "down", body.Dependencies["databases"].Status)
+	assert.Contains(t, body.Dependencies["databases"].Error, "db-2")
+}
+
+func TestHealthServer_KnowledgeDownIsUnhealthy(t *testing.T) {
+	s := health.NewServer("collector", "0")
+	s.AddCheck("knowledge", true, func(ctx context.Context) error { return errors.New("not connected") })
+	startHealthServer(t, s)
+
+	code, body := getHealth(t, s)
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "unhealthy", body.Status)
+}
+
+func TestHealthServer_AnalyserLinkDownIsDegraded(t *testing.T) {
+	s := health.NewServer("collector", "0")
+	s.SetAnalyserStats(func() grpcclient.StreamStats {
+		return grpcclient.StreamStats{Connected: false, BufferedSnapshots: 3}
+	})
+	s.SetUnavailableFeatures([]string{"pg_stat_statements"})
+	startHealthServer(t, s)
+
+	code, body := getHealth(t, s)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "degraded", body.Status)
+	require.NotNil(t, body.Analyser)
+	assert.Equal(t, 3, body.Analyser.BufferedSnapshots)
+	assert.Equal(t, []string{"pg_stat_statements"}, body.UnavailableFeatures)
+}
+
+func TestHealthServer_StartFailsWhenPortInUse(t *testing.T) {
+	taken, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	defer taken.Close()
+
+	_, port, err := net.SplitHostPort(taken.Addr().String())
+	require.NoError(t, err)
+
+	assert.Error(t, health.NewServer("collector", port).Start())
+}
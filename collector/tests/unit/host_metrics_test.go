@@ -0,0 +1,197 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/adapter"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/config"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/system"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const nodeExporterSample = `# HELP node_load1 1m load average.
+# TYPE node_load1 gauge
+node_load1 3.5
+node_load5 2.25
+node_load15 1
+node_memory_MemAvailable_bytes 2.147483648e+09
+node_memory_MemTotal_bytes 8.589934592e+09
+node_filesystem_avail_bytes{device="/dev/sda1",fstype="ext4",mountpoint="/"} 5e+10
+node_filesystem_size_bytes{device="/dev/sda1",fstype="ext4",mountpoint="/"} 1e+11
+node_filesystem_avail_bytes{device="/dev/sdb1",fstype="xfs",mountpoint="/var/lib/postgresql"} 1e+09
+node_filesystem_size_bytes{device="/dev/sdb1",fstype="xfs",mountpoint="/var/lib/postgresql"} 2e+10
+`
+
+func TestParseNodeExporter_PicksDataMount(t *testing.T) {
+	m, err := system.ParseNodeExporter(strings.NewReader(nodeExporterSample), "/var/lib/postgresql/data")
+	require.NoError(t, err)
+
+	require.NotNil(t, m.Load1m)
+	assert.Equal(t, 3.5, *m.Load1m)
+	require.NotNil(t, m.MemoryAvailableBytes)
+	assert.Equal(t, int64(2147483648), *m.MemoryAvailableBytes)
+	require.NotNil(t, m.DiskTotalBytes)
+	assert.Equal(t, int64(2e10), *m.DiskTotalBytes, "the data directory's own mount, not /")
+	require.NotNil(t, m.DiskFreeBytes)
+	assert.Equal(t, int64(1e9), *m.DiskFreeBytes)
+}
+
+func TestParseNodeExporter_FallsBackToRootMount(t *testing.T) {
+	m, err := system.ParseNodeExporter(strings.NewReader(nodeExporterSample), "/srv/mysql")
+	require.NoError(t, err)
+
+	require.NotNil(t, m.DiskTotalBytes)
+	assert.Equal(t, int64(1e11), *m.DiskTotalBytes)
+}
+
+func TestParseNodeExporter_MissingMetricsStayNil(t *testing.T) {
+	m, err := system.ParseNodeExporter(strings.NewReader("node_load1 0.5\n"), "/")
+	require.NoError(t, err)
+
+	assert.NotNil(t, m.Load1m)
+	assert.Nil(t, m.MemoryAvailableBytes)
+	assert.Nil(t, m.DiskTotalBytes)
+	assert.Equal(t, map[string]float64{system.HostLoad1m: 0.5}, m.ToExtendedMetrics())
+}
+
+func TestNodeExporterSource_Collect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(nodeExporterSample))
+	}))
+	defer server.Close()
+
+	source := system.NewNodeExporterSource(server.URL, "/var/lib/postgresql/data")
+	m, err := source.Collect(context.Background())
+	require.NoError(t, err)
+
+	require.NotNil(t, m.DiskFreeBytes)
+	assert.Equal(t, int64(1e9), *m.DiskFreeBytes)
+}
+
+func TestNodeExporterSource_CollectFailsOnBadStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := system.NewNodeExporterSource(server.URL, "/").Collect(context.Background())
+	assert.Error(t, err)
+}
+
+func TestProcSource_Collect(t *testing.T) {
+	procDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(procDir, "loadavg"), []byte("0.75 0.50 0.25 1/123 4567\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(procDir, "meminfo"), []byte(
+		"MemTotal:        8000000 kB\nMemFree:          500000 kB\nMemAvailable:    2000000 kB\n"), 0o644))
+
+	source := system.NewProcSource(procDir, t.TempDir())
+	m, err := source.Collect(context.Background())
+	require.NoError(t, err)
+
+	require.NotNil(t, m.Load15m)
+	assert.Equal(t, 0.25, *m.Load15m)
+	require.NotNil(t, m.MemoryTotalBytes)
+	assert.Equal(t, int64(8000000*1024), *m.MemoryTotalBytes)
+	require.NotNil(t, m.MemoryAvailableBytes)
+	assert.Equal(t, int64(2000000*1024), *m.MemoryAvailableBytes)
+	require.NotNil(t, m.DiskTotalBytes)
+	assert.Positive(t, *m.DiskTotalBytes)
+	require.NotNil(t, m.DiskFreeBytes)
+}
+
+func TestProcSource_CollectFailsWhenNothingReadable(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	_, err := system.NewProcSource(missing, missing).Collect(context.Background())
+	assert.Error(t, err)
+}
+
+func TestNewHostSource(t *testing.T) {
+	source, err := system.NewHostSource("none", "", "", "")
+	require.NoError(t, err)
+	assert.Nil(t, source)
+
+	source, err = system.NewHostSource("proc", "", "", "")
+	require.NoError(t, err)
+	assert.IsType(t, &system.ProcSource{}, source)
+
+	_, err = system.NewHostSource("node_exporter", "", "", "")
+	assert.Error(t, err)
+
+	_, err = system.NewHostSource("snmp", "", "", "")
+	assert.Error(t, err)
+}
+
+func TestHostMetrics_DriveStorageHealth(t *testing.T) {
+	dbSize := int64(5e9)
+	raw := adapter.NewRawMetrics("testdb", "postgres")
+	raw.Storage = &adapter.StorageMetrics{UsedSizeBytes: &dbSize}
+
+	total, free := int64(100e9), int64(5e9)
+	host := &system.HostMetrics{DiskTotalBytes: &total, DiskFreeBytes: &free}
+	host.Apply(raw)
+
+	normalised, err := normaliser.NewPostgresNormaliser().Normalise(raw)
+	require.NoError(t, err)
+
+	// A 95% full disk, even though the database itself is only 5% of it
+	assert.InDelta(t, 0.05, normalised.StorageHealth, 1e-9)
+	assert.Equal(t, &free, normalised.Measurements.FreeStorageBytes)
+	assert.Contains(t, normalised.AvailableMetrics, "total_storage_bytes")
+	assert.Contains(t, normalised.AvailableMetrics, "free_storage_bytes")
+	assert.Contains(t, normalised.AvailableMetrics, system.HostDiskFreeBytes)
+	assert.Equal(t, float64(free), normalised.ExtendedMetrics[system.HostDiskFreeBytes])
+}
+
+func TestHostMetrics_AbsentLeavesStorageUnscored(t *testing.T) {
+	dbSize := int64(5e9)
+	raw := adapter.NewRawMetrics("testdb", "mysql")
+	raw.Storage = &adapter.StorageMetrics{UsedSizeBytes: &dbSize}
+
+	normalised, err := normaliser.NewMySQLNormaliser().Normalise(raw)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1.0, normalised.StorageHealth)
+	assert.Contains(t, normalised.AvailableMetrics, "used_storage_bytes")
+	assert.NotContains(t, normalised.AvailableMetrics, "total_storage_bytes")
+	assert.NotContains(t, normalised.AvailableMetrics, "free_storage_bytes")
+	for _, name := range normalised.AvailableMetrics {
+		assert.False(t, strings.HasPrefix(name, "host."), "no host metrics were collected: %s", name)
+	}
+}
+
+func TestConfig_Validate_HostMetricsSource(t *testing.T) {
+	base := config.Config{
+		AnalyserAddress:    "localhost:50051",
+		KnowledgeAddress:   "localhost:50053",
+		CollectionInterval: 10 * time.Second,
+		SyncInterval:       30 * time.Second,
+		IndexCandidates:    5,
+	}
+
+	cfg := base
+	cfg.HostMetricsSource = "proc"
+	assert.NoError(t, cfg.Validate())
+
+	cfg = base
+	cfg.HostMetricsSource = "node_exporter"
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "NODE_EXPORTER_URL")
+
+	cfg.NodeExporterURL = "http://db-host:9100/metrics"
+	assert.NoError(t, cfg.Validate())
+
+	cfg = base
+	cfg.HostMetricsSource = "snmp"
+	assert.Error(t, cfg.Validate())
+}
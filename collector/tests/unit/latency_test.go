@@ -0,0 +1,90 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/adapter"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummariseLatency_WeightsByCalls(t *testing.T) {
+	statements := []adapter.StatementLatency{
+		{Query: "SELECT fast", Calls: 900, MeanExecMs: 2},
+		{Query: "SELECT medium", Calls: 85, MeanExecMs: 50},
+		{Query: "SELECT slow", Calls: 15, MeanExecMs: 800},
+	}
+
+	summary := adapter.SummariseLatency(statements)
+	require.NotNil(t, summary)
+
+	// (900*2 + 85*50 + 15*800) / 1000
+	assert.InDelta(t, 18.05, summary.AvgMs, 1e-9)
+	assert.Equal(t, 2.0, summary.P50Ms)
+	assert.Equal(t, 50.0, summary.P95Ms, "calls 901-985 cover the 95th percentile")
+	assert.Equal(t, 800.0, summary.P99Ms)
+}
+
+func TestSummariseLatency_NoCalls(t *testing.T) {
+	assert.Nil(t, adapter.SummariseLatency(nil))
+	assert.Nil(t, adapter.SummariseLatency([]adapter.StatementLatency{{Query: "SELECT 1", Calls: 0, MeanExecMs: 5}}))
+}
+
+func TestWorstStatements(t *testing.T) {
+	statements := []adapter.StatementLatency{
+		{Query: "a", Calls: 5, MeanExecMs: 150},
+		{Query: "b", Calls: 5, MeanExecMs: 20},
+		{Query: "c", Calls: 5, MeanExecMs: 900},
+		{Query: "d", Calls: 5, MeanExecMs: 300},
+	}
+
+	worst := adapter.WorstStatements(statements, 100, 2)
+
+	require.Len(t, worst, 2)
+	assert.Equal(t, "c", worst[0].Query)
+	assert.Equal(t, "d", worst[1].Query)
+}
+
+func TestPostgresNormaliser_PassesLatencyAndSlowQueries(t *testing.T) {
+	avg, p95 := 40.0, 250.0
+	raw := &adapter.RawMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "postgres",
+		Timestamp:    100,
+		Queries: &adapter.QueryMetrics{
+			AvgLatencyMs: &avg,
+			P95LatencyMs: &p95,
+			SlowQueries: []adapter.SlowQuery{
+				{Query: "SELECT slow", DurationMs: 250, Source: "pg_stat_statements"},
+			},
+		},
+	}
+
+	normalised, err := normaliser.NewPostgresNormaliser().Normalise(raw)
+	require.NoError(t, err)
+
+	require.NotNil(t, normalised.Measurements.P95QueryLatencyMs)
+	assert.Equal(t, 250.0, *normalised.Measurements.P95QueryLatencyMs)
+	require.NotNil(t, normalised.Measurements.SlowQueryCount)
+	assert.Equal(t, int32(1), *normalised.Measurements.SlowQueryCount)
+	assert.Contains(t, normalised.AvailableMetrics, "p95_query_latency_ms")
+	assert.Contains(t, normalised.AvailableMetrics, "slow_query_count")
+}
+
+func TestPostgresNormaliser_AvailableMetricsOmitsMissingLatency(t *testing.T) {
+	seqScans := int32(10)
+	raw := &adapter.RawMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "postgres",
+		Timestamp:    100,
+		Queries:      &adapter.QueryMetrics{SequentialScans: &seqScans},
+	}
+
+	normalised, err := normaliser.NewPostgresNormaliser().Normalise(raw)
+	require.NoError(t, err)
+
+	assert.Contains(t, normalised.AvailableMetrics, "sequential_scans")
+	assert.NotContains(t, normalised.AvailableMetrics, "p95_query_latency_ms")
+	assert.NotContains(t, normalised.AvailableMetrics, "avg_query_latency_ms")
+}
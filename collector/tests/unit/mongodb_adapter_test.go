@@ -0,0 +1,137 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/adapter"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// The driver decodes numbers as int32, int64 or double depending on the value's size on the
+// server, so these replies mix all three as a real server does.
+func mongoServerStatus() bson.M {
+	return bson.M{
+		"connections": bson.M{"current": int32(40), "available": int32(760), "active": int32(12)},
+		"wiredTiger": bson.M{
+			"cache": bson.M{
+				"pages read into cache":          int64(50),
+				"pages requested from the cache": int64(1000),
+				"maximum bytes configured":       float64(268435456),
+				"bytes currently in the cache":   int64(1048576),
+				"bytes read into cache":          int32(409600),
+			},
+		},
+		"opcounters": bson.M{"query": int64(900), "insert": int32(15)},
+	}
+}
+
+func TestApplyMongoServerStatus(t *testing.T) {
+	metrics := adapter.NewRawMetrics("mongo-1", "mongodb")
+
+	adapter.ApplyMongoServerStatus(metrics, mongoServerStatus())
+
+	require.NotNil(t, metrics.Connections)
+	assert.Equal(t, int32(12), *metrics.Connections.Active)
+	assert.Equal(t, int32(28), *metrics.Connections.Idle)
+	assert.Equal(t, int32(800), *metrics.Connections.Max)
+
+	require.NotNil(t, metrics.Cache)
+	assert.InDelta(t, 0.95, *metrics.Cache.HitRate, 0.0001)
+	assert.Equal(t, int64(268435456), *metrics.Cache.CacheSizeBytes)
+	assert.Equal(t, 409600.0, metrics.ExtendedMetrics["mongodb.cache_bytes_read_into_cache"])
+	assert.Equal(t, 15.0, metrics.ExtendedMetrics["mongodb.opcounters_insert"])
+}
+
+func TestApplyMongoServerStatus_OlderServerWithoutActiveCount(t *testing.T) {
+	metrics := adapter.NewRawMetrics("mongo-1", "mongodb")
+
+	adapter.ApplyMongoServerStatus(metrics, bson.M{
+		"connections": bson.M{"current": int32(5), "available": int32(95)},
+	})
+
+	require.NotNil(t, metrics.Connections)
+	assert.Equal(t, int32(5), *metrics.Connections.Active)
+	assert.Nil(t, metrics.Connections.Idle)
+	assert.Equal(t, int32(100), *metrics.Connections.Max)
+	assert.Nil(t, metrics.Cache, "no WiredTiger section means no cache metrics")
+}
+
+func TestApplyMongoDBStats(t *testing.T) {
+	metrics := adapter.NewRawMetrics("mongo-1", "mongodb")
+
+	adapter.ApplyMongoDBStats(metrics, bson.M{
+		"dataSize":    float64(3000),
+		"storageSize": int32(2048),
+		"indexSize":   int32(512),
+		"fsTotalSize": float64(10000),
+		"fsUsedSize":  float64(7500),
+	})
+
+	require.NotNil(t, metrics.Storage)
+	assert.Equal(t, int64(2048), *metrics.Storage.UsedSizeBytes)
+	assert.Equal(t, int64(512), *metrics.Storage.IndexSizeBytes)
+	assert.Equal(t, int64(10000), *metrics.Storage.TotalSizeBytes)
+	assert.Equal(t, int64(2500), *metrics.Storage.FreeSpaceBytes)
+	assert.Equal(t, 3000.0, metrics.ExtendedMetrics["mongodb.database_size_bytes"])
+}
+
+func TestApplyMongoDBStats_Empty(t *testing.T) {
+	metrics := adapter.NewRawMetrics("mongo-1", "mongodb")
+
+	adapter.ApplyMongoDBStats(metrics, bson.M{})
+
+	assert.Nil(t, metrics.Storage)
+}
+
+func TestApplyMongoCurrentOp(t *testing.T) {
+	metrics := adapter.NewRawMetrics("mongo-1", "mongodb")
+
+	adapter.ApplyMongoCurrentOp(metrics, []bson.M{
+		{"opid": int32(101), "desc": "conn12", "op": "query", "ns": "shop.orders", "secs_running": int64(45)},
+		{"opid": int32(102), "desc": "conn15", "op": "update", "ns": "shop.carts", "secs_running": int32(12)},
+		{"opid": int32(103), "desc": "conn16", "op": "query", "ns": "shop.users", "secs_running": int32(2)},
+		{"opid": int32(1), "desc": "ReplBatcher", "op": "none", "secs_running": int64(86400)},
+	})
+
+	assert.Equal(t, 2.0, metrics.ExtendedMetrics["mongodb.long_running_op_count"])
+	assert.Equal(t, 45.0, metrics.ExtendedMetrics["mongodb.longest_op_duration_secs"])
+	assert.Equal(t, "101", metrics.Labels["mongodb.longest_op_id"])
+	assert.Equal(t, "query", metrics.Labels["mongodb.longest_op_type"])
+	assert.Equal(t, "shop.orders", metrics.Labels["mongodb.longest_op_ns"])
+}
+
+func TestApplyMongoCurrentOp_None(t *testing.T) {
+	metrics := adapter.NewRawMetrics("mongo-1", "mongodb")
+
+	adapter.ApplyMongoCurrentOp(metrics, nil)
+
+	assert.Equal(t, 0.0, metrics.ExtendedMetrics["mongodb.long_running_op_count"])
+	assert.NotContains(t, metrics.ExtendedMetrics, "mongodb.longest_op_duration_secs")
+}
+
+func TestMongoDBNormaliser_AvailableMetricsFollowCollectedFields(t *testing.T) {
+	metrics := adapter.NewRawMetrics("mongo-1", "mongodb")
+	adapter.ApplyMongoServerStatus(metrics, mongoServerStatus())
+
+	normalised, err := normaliser.NewMongoDBNormaliser().Normalise(metrics)
+
+	require.NoError(t, err)
+	assert.Contains(t, normalised.AvailableMetrics, "active_connections")
+	assert.Contains(t, normalised.AvailableMetrics, "idle_connections")
+	assert.Contains(t, normalised.AvailableMetrics, "cache_hit_rate")
+	assert.NotContains(t, normalised.AvailableMetrics, "used_storage_bytes", "dbStats was not collected")
+	assert.NotContains(t, normalised.AvailableMetrics, "sequential_scans")
+}
+
+func TestMongoDBAdapter_NotConnected(t *testing.T) {
+	mongoAdapter := adapter.NewMongoDBAdapter("mongodb://localhost:27017/shop", "mongo-1")
+
+	metrics, err := mongoAdapter.CollectMetrics()
+	assert.Nil(t, metrics)
+	assert.Equal(t, adapter.ErrNotConnected, err)
+	assert.Equal(t, adapter.ErrNotConnected, mongoAdapter.HealthCheck())
+	assert.NoError(t, mongoAdapter.Close())
+}
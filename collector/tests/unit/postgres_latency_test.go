@@ -0,0 +1,217 @@
+package unit
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/adapter"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePgPool answers the adapter's queries with canned results, picked by a substring of
+// the SQL. Queries it has no answer for return no rows, and rows it has no values for scan
+// as zero values.
+type fakePgPool struct {
+	rows    map[string][][]any // Query results
+	row     map[string][]any   // QueryRow results
+	errs    map[string]error
+	queries []string
+}
+
+func newFakePgPool() *fakePgPool {
+	return &fakePgPool{
+		rows: map[string][][]any{},
+		row: map[string][]any{
+			"max_connections":    {"100"},
+			"server_version_num": {160000},
+		},
+		errs: map[string]error{},
+	}
+}
+
+func (f *fakePgPool) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	f.queries = append(f.queries, sql)
+	for match, err := range f.errs {
+		if strings.Contains(sql, match) {
+			return nil, err
+		}
+	}
+	for match, rows := range f.rows {
+		if strings.Contains(sql, match) {
+			return &fakePgRows{rows: rows}, nil
+		}
+	}
+	return &fakePgRows{}, nil
+}
+
+func (f *fakePgPool) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	f.queries = append(f.queries, sql)
+	for match, err := range f.errs {
+		if strings.Contains(sql, match) {
+			return fakePgRow{err: err}
+		}
+	}
+	for match, values := range f.row {
+		if strings.Contains(sql, match) {
+			return fakePgRow{values: values}
+		}
+	}
+	return fakePgRow{}
+}
+
+func (f *fakePgPool) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	f.queries = append(f.queries, sql)
+	return pgconn.CommandTag{}, nil
+}
+
+func (f *fakePgPool) Ping(ctx context.Context) error { return nil }
+func (f *fakePgPool) Close()                         {}
+
+// queriesContaining returns the queries sent so far that contain substr.
+func (f *fakePgPool) queriesContaining(substr string) []string {
+	var matched []string
+	for _, q := range f.queries {
+		if strings.Contains(q, substr) {
+			matched = append(matched, q)
+		}
+	}
+	return matched
+}
+
+type fakePgRow struct {
+	values []any
+	err    error
+}
+
+func (r fakePgRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	scanValues(dest, r.values)
+	return nil
+}
+
+type fakePgRows struct {
+	rows [][]any
+	next int
+}
+
+func (r *fakePgRows) Next() bool {
+	if r.next >= len(r.rows) {
+		return false
+	}
+	r.next++
+	return true
+}
+
+func (r *fakePgRows) Scan(dest ...any) error {
+	scanValues(dest, r.rows[r.next-1])
+	return nil
+}
+
+func (r *fakePgRows) Values() ([]any, error)                       { return r.rows[r.next-1], nil }
+func (r *fakePgRows) Close()                                       {}
+func (r *fakePgRows) Err() error                                   { return nil }
+func (r *fakePgRows) CommandTag() pgconn.CommandTag                { return pgconn.CommandTag{} }
+func (r *fakePgRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *fakePgRows) RawValues() [][]byte                          { return nil }
+func (r *fakePgRows) Conn() *pgx.Conn                              { return nil }
+
+func scanValues(dest []any, values []any) {
+	for i := range min(len(dest), len(values)) {
+		reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(values[i]))
+	}
+}
+
+func TestPostgresAdapter_CollectsLatencyFromPgStatStatements(t *testing.T) {
+	pool := newFakePgPool()
+	pool.row["pg_extension"] = []any{true}
+	pool.rows["FROM pg_stat_statements"] = [][]any{
+		{"SELECT fast", int64(900), 2.0},
+		{"SELECT medium", int64(85), 50.0},
+		{"SELECT slow", int64(15), 800.0},
+	}
+
+	pg := adapter.NewPostgresAdapterWithPool(pool, "test-db")
+	require.NoError(t, pg.Connect())
+
+	metrics, err := pg.CollectMetrics()
+	require.NoError(t, err)
+
+	require.NotNil(t, metrics.Queries.AvgLatencyMs)
+	assert.InDelta(t, 18.05, *metrics.Queries.AvgLatencyMs, 1e-9)
+	assert.Equal(t, 2.0, *metrics.Queries.P50LatencyMs)
+	assert.Equal(t, 50.0, *metrics.Queries.P95LatencyMs)
+	assert.Equal(t, 800.0, *metrics.Queries.P99LatencyMs)
+	require.Len(t, metrics.Queries.SlowQueries, 1)
+	assert.Equal(t, "SELECT slow", metrics.Queries.SlowQueries[0].Query)
+	assert.Equal(t, "available", metrics.Labels["pg.stat_statements"])
+
+	statements := pool.queriesContaining("FROM pg_stat_statements")
+	require.Len(t, statements, 1)
+	assert.Contains(t, statements[0], "mean_exec_time")
+}
+
+func TestPostgresAdapter_LatencyUsesPre13Columns(t *testing.T) {
+	pool := newFakePgPool()
+	pool.row["pg_extension"] = []any{true}
+	pool.row["server_version_num"] = []any{120015}
+	pool.rows["FROM pg_stat_statements"] = [][]any{{"SELECT 1", int64(10), 4.0}}
+
+	pg := adapter.NewPostgresAdapterWithPool(pool, "test-db")
+	require.NoError(t, pg.Connect())
+
+	metrics, err := pg.CollectMetrics()
+	require.NoError(t, err)
+	require.NotNil(t, metrics.Queries.AvgLatencyMs)
+	assert.Equal(t, 4.0, *metrics.Queries.AvgLatencyMs)
+
+	statements := pool.queriesContaining("FROM pg_stat_statements")
+	require.Len(t, statements, 1)
+	assert.Contains(t, statements[0], "mean_time")
+	assert.NotContains(t, statements[0], "mean_exec_time")
+}
+
+func TestPostgresAdapter_LatencyWithoutExtension(t *testing.T) {
+	pool := newFakePgPool()
+	pool.row["pg_extension"] = []any{false}
+	pool.row["shared_preload_libraries"] = []any{""}
+
+	pg := adapter.NewPostgresAdapterWithPool(pool, "test-db")
+	require.NoError(t, pg.Connect())
+
+	for range 2 {
+		metrics, err := pg.CollectMetrics()
+		require.NoError(t, err, "a missing extension must not fail collection")
+
+		assert.Nil(t, metrics.Queries.AvgLatencyMs)
+		assert.Nil(t, metrics.Queries.P95LatencyMs)
+		assert.Equal(t, "unavailable", metrics.Labels["pg.stat_statements"])
+	}
+
+	assert.Len(t, pool.queriesContaining("pg_extension"), 1, "extension is only looked for again after the recheck interval")
+	assert.Empty(t, pool.queriesContaining("FROM pg_stat_statements"))
+}
+
+func TestPostgresAdapter_LatencyExtensionDropped(t *testing.T) {
+	pool := newFakePgPool()
+	pool.row["pg_extension"] = []any{true}
+	pool.errs["FROM pg_stat_statements"] = &pgconn.PgError{Code: "42P01", Message: `relation "pg_stat_statements" does not exist`}
+
+	pg := adapter.NewPostgresAdapterWithPool(pool, "test-db")
+	require.NoError(t, pg.Connect())
+
+	metrics, err := pg.CollectMetrics()
+	require.NoError(t, err)
+	assert.Nil(t, metrics.Queries.AvgLatencyMs)
+	assert.Equal(t, "unavailable", metrics.Labels["pg.stat_statements"])
+
+	_, err = pg.CollectMetrics()
+	require.NoError(t, err)
+	assert.Len(t, pool.queriesContaining("FROM pg_stat_statements"), 1, "dropped extension is not queried every cycle")
+}
@@ -0,0 +1,80 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/adapter"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresAdapter_CollectsLockWaits(t *testing.T) {
+	pool := newFakePgPool()
+	pool.row["wait_event_type = 'Lock'"] = []any{int32(3)}
+	pool.rows["pg_blocking_pids"] = [][]any{
+		{int32(200), "app", "UPDATE orders SET total = 0", "orders", 42.5, []int32{100, 101}},
+		{int32(201), "app", "UPDATE orders SET total = 1", "orders", 3.0, []int32{200}},
+	}
+	pool.row["WHERE pid = $1"] = []any{int32(100), "batch", "flyway", "ALTER TABLE orders ADD COLUMN note text", "idle in transaction"}
+
+	pg := adapter.NewPostgresAdapterWithPool(pool, "test-db")
+	require.NoError(t, pg.Connect())
+
+	metrics, err := pg.CollectMetrics()
+	require.NoError(t, err)
+
+	require.NotNil(t, metrics.Connections.Waiting)
+	assert.Equal(t, int32(3), *metrics.Connections.Waiting)
+	assert.Equal(t, 3.0, metrics.ExtendedMetrics["pg.blocked_count"])
+	assert.Equal(t, 42.5, metrics.ExtendedMetrics["pg.lock_wait_secs"])
+	assert.Equal(t, "200", metrics.Labels["pg.lock_wait_pid"])
+	assert.Equal(t, "orders", metrics.Labels["pg.lock_wait_relation"])
+	assert.Equal(t, "100,101", metrics.Labels["pg.lock_wait_blocking_pids"])
+	assert.Equal(t, "100", metrics.Labels["pg.blocking_pid"])
+	assert.Equal(t, "flyway", metrics.Labels["pg.lock_blocker_application"])
+	assert.Equal(t, "idle in transaction", metrics.Labels["pg.lock_blocker_state"])
+}
+
+func TestPostgresAdapter_LockWaitTimedFromWaitStart(t *testing.T) {
+	for _, tc := range []struct {
+		version   int
+		waitStart bool
+	}{
+		{version: 160002, waitStart: true},
+		{version: 130014, waitStart: false},
+	} {
+		pool := newFakePgPool()
+		pool.row["server_version_num"] = []any{tc.version}
+
+		pg := adapter.NewPostgresAdapterWithPool(pool, "test-db")
+		require.NoError(t, pg.Connect())
+
+		_, err := pg.CollectMetrics()
+		require.NoError(t, err)
+
+		queries := pool.queriesContaining("pg_blocking_pids")
+		require.Len(t, queries, 1)
+		if tc.waitStart {
+			assert.Contains(t, queries[0], "l.waitstart", "version %d", tc.version)
+		} else {
+			assert.NotContains(t, queries[0], "waitstart", "version %d", tc.version)
+		}
+	}
+}
+
+func TestPostgresAdapter_LockWaitsWithoutPermission(t *testing.T) {
+	pool := newFakePgPool()
+	pool.errs["wait_event_type = 'Lock'"] = &pgconn.PgError{Code: "42501", Message: "permission denied for view pg_stat_activity"}
+
+	pg := adapter.NewPostgresAdapterWithPool(pool, "test-db")
+	require.NoError(t, pg.Connect())
+
+	metrics, err := pg.CollectMetrics()
+	require.NoError(t, err, "lock wait queries failing must not fail collection")
+
+	assert.Nil(t, metrics.Connections.Waiting)
+	assert.NotNil(t, metrics.Connections.Active)
+	assert.NotContains(t, metrics.ExtendedMetrics, "pg.blocked_count")
+	assert.NotContains(t, metrics.Labels, "pg.lock_wait_pid")
+}
@@ -0,0 +1,51 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/adapter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresAdapter_CollectsReplicationLag(t *testing.T) {
+	pool := newFakePgPool()
+	pool.rows["FROM pg_stat_replication"] = [][]any{
+		{"replica-1", "10.0.0.5", "streaming", 2.0, 12.5, int64(4 << 20)},
+		// Idle since its last catch-up, so no replay_lag, but still behind in WAL
+		{"replica-2", "10.0.0.6", "streaming", 0.0, 0.0, int64(300 << 20)},
+	}
+
+	pg := adapter.NewPostgresAdapterWithPool(pool, "test-db")
+	require.NoError(t, pg.Connect())
+
+	metrics, err := pg.CollectMetrics()
+	require.NoError(t, err)
+
+	assert.Equal(t, 2.0, metrics.ExtendedMetrics["pg.replica_count"])
+	assert.Equal(t, "replica-1", metrics.Labels["pg.worst_lag_replica"])
+	assert.Equal(t, "10.0.0.5", metrics.Labels["pg.worst_lag_replica_addr"])
+	assert.Equal(t, 12.5, metrics.ExtendedMetrics["pg.max_replication_lag_secs"])
+	assert.Equal(t, 2.0, metrics.ExtendedMetrics["pg.max_replication_write_lag_secs"])
+	assert.Equal(t, float64(300<<20), metrics.ExtendedMetrics["pg.max_replication_lag_bytes"])
+	assert.Equal(t, "replica-2", metrics.Labels["pg.worst_bytes_lag_replica"])
+	assert.Equal(t, "10.0.0.6", metrics.Labels["pg.worst_bytes_lag_replica_addr"])
+	assert.Equal(t, float64(4<<20), metrics.ExtendedMetrics["pg.replica.replica-1.replay_lag_bytes"])
+	assert.Equal(t, "streaming", metrics.Labels["pg.replica.replica-2.state"])
+}
+
+func TestPostgresAdapter_NoReplicas(t *testing.T) {
+	pool := newFakePgPool()
+
+	pg := adapter.NewPostgresAdapterWithPool(pool, "test-db")
+	require.NoError(t, pg.Connect())
+
+	metrics, err := pg.CollectMetrics()
+	require.NoError(t, err)
+
+	assert.Equal(t, 0.0, metrics.ExtendedMetrics["pg.replica_count"])
+	assert.NotContains(t, metrics.ExtendedMetrics, "pg.max_replication_lag_secs")
+	assert.NotContains(t, metrics.ExtendedMetrics, "pg.max_replication_lag_bytes")
+	assert.NotContains(t, metrics.Labels, "pg.worst_lag_replica")
+	assert.NotContains(t, metrics.Labels, "pg.worst_bytes_lag_replica")
+}
@@ -0,0 +1,98 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/adapter"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresAdapter_CollectsSessionStats(t *testing.T) {
+	pool := newFakePgPool()
+	sessions := int64(1200)
+	pool.row["xact_rollback"] = []any{&sessions, int64(54000)}
+
+	pg := adapter.NewPostgresAdapterWithPool(pool, "test-db")
+	require.NoError(t, pg.Connect())
+
+	metrics, err := pg.CollectMetrics()
+	require.NoError(t, err)
+
+	assert.Equal(t, 1200.0, metrics.ExtendedMetrics["pg.sessions_total"])
+	assert.Equal(t, 54000.0, metrics.ExtendedMetrics["pg.xact_total"])
+}
+
+func TestPostgresAdapter_SessionsNotCountedBeforePG14(t *testing.T) {
+	pool := newFakePgPool()
+	pool.row["server_version_num"] = []any{130014}
+	pool.row["xact_rollback"] = []any{(*int64)(nil), int64(54000)}
+
+	pg := adapter.NewPostgresAdapterWithPool(pool, "test-db")
+	require.NoError(t, pg.Connect())
+
+	metrics, err := pg.CollectMetrics()
+	require.NoError(t, err)
+
+	queries := pool.queriesContaining("xact_rollback")
+	require.Len(t, queries, 1)
+	assert.NotContains(t, queries[0], "sessions", "pg_stat_database.sessions is new in PostgreSQL 14")
+	assert.NotContains(t, metrics.ExtendedMetrics, "pg.sessions_total")
+	assert.Equal(t, 54000.0, metrics.ExtendedMetrics["pg.xact_total"])
+	assert.Contains(t, pg.GetUnavailableFeatures(), "session_stats", "connection churn cannot be detected before PostgreSQL 14")
+}
+
+func TestPostgresAdapter_SessionStatsAvailableFromPG14(t *testing.T) {
+	pool := newFakePgPool()
+
+	pg := adapter.NewPostgresAdapterWithPool(pool, "test-db")
+	require.NoError(t, pg.Connect())
+
+	assert.NotContains(t, pg.GetUnavailableFeatures(), "session_stats")
+}
+
+func sessionsSnapshot(collectedAt time.Time, sessions, xacts float64) *adapter.RawMetrics {
+	return &adapter.RawMetrics{
+		DatabaseID:   "test-db",
+		DatabaseType: "postgres",
+		Timestamp:    collectedAt.Unix(),
+		CollectedAt:  collectedAt,
+		ExtendedMetrics: map[string]float64{
+			"pg.sessions_total": sessions,
+			"pg.xact_total":     xacts,
+		},
+		Labels: map[string]string{},
+	}
+}
+
+func TestPostgresNormaliser_SessionDeltas(t *testing.T) {
+	n := normaliser.NewPostgresNormaliser()
+	start := time.Now()
+
+	_, err := n.Normalise(sessionsSnapshot(start, 1000, 50000))
+	require.NoError(t, err)
+
+	normalised, err := n.Normalise(sessionsSnapshot(start.Add(30*time.Second), 1600, 50900))
+	require.NoError(t, err)
+
+	assert.True(t, normalised.DeltasReliable)
+	assert.Equal(t, 600.0, normalised.MetricDeltas["sessions"])
+	assert.Equal(t, 900.0, normalised.MetricDeltas["transactions"])
+}
+
+func TestPostgresNormaliser_SessionCounterResetIsUnreliable(t *testing.T) {
+	n := normaliser.NewPostgresNormaliser()
+	start := time.Now()
+
+	_, err := n.Normalise(sessionsSnapshot(start, 1000, 50000))
+	require.NoError(t, err)
+
+	// pg_stat_reset() cleared the counters between collections
+	normalised, err := n.Normalise(sessionsSnapshot(start.Add(30*time.Second), 40, 200))
+	require.NoError(t, err)
+
+	assert.False(t, normalised.DeltasReliable)
+	assert.Equal(t, 40.0, normalised.MetricDeltas["sessions"])
+}
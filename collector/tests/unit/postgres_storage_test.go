@@ -0,0 +1,106 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/adapter"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresAdapter_CollectsTablespaceSize(t *testing.T) {
+	pool := newFakePgPool()
+	pool.row["pg_database_size"] = []any{int64(40 << 20)}
+	pool.row["pg_tablespace_size"] = []any{int64(120 << 20)}
+
+	pg := adapter.NewPostgresAdapterWithPool(pool, "test-db")
+	require.NoError(t, pg.Connect())
+
+	metrics, err := pg.CollectMetrics()
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(120<<20), metrics.ExtendedMetrics["pg.tablespaces_size_bytes"])
+	require.NotNil(t, metrics.Storage.UsedSizeBytes)
+	assert.Equal(t, int64(40<<20), *metrics.Storage.UsedSizeBytes)
+	assert.Nil(t, metrics.Storage.TotalSizeBytes, "Disk size is unknown without a disk usage query")
+	assert.Empty(t, pool.queriesContaining("disk_usage"))
+}
+
+func TestPostgresAdapter_DiskUsageQuery(t *testing.T) {
+	pool := newFakePgPool()
+	pool.row["monitoring.disk_usage()"] = []any{int64(100 << 30), int64(25 << 30)}
+
+	pg := adapter.NewPostgresAdapterWithPool(pool, "test-db")
+	pg.SetDiskUsageQuery("SELECT total_bytes, free_bytes FROM monitoring.disk_usage()")
+	require.NoError(t, pg.Connect())
+
+	metrics, err := pg.CollectMetrics()
+	require.NoError(t, err)
+
+	require.NotNil(t, metrics.Storage.TotalSizeBytes)
+	require.NotNil(t, metrics.Storage.FreeSpaceBytes)
+	assert.Equal(t, int64(100<<30), *metrics.Storage.TotalSizeBytes)
+	assert.Equal(t, int64(25<<30), *metrics.Storage.FreeSpaceBytes)
+}
+
+func TestPostgresAdapter_DiskUsageQueryFailureIsTolerated(t *testing.T) {
+	for name, setup := range map[string]func(*fakePgPool){
+		"error":        func(p *fakePgPool) { p.errs["monitoring.disk_usage()"] = errors.New("function does not exist") },
+		"free > total": func(p *fakePgPool) { p.row["monitoring.disk_usage()"] = []any{int64(10), int64(20)} },
+	} {
+		pool := newFakePgPool()
+		setup(pool)
+
+		pg := adapter.NewPostgresAdapterWithPool(pool, "test-db")
+		pg.SetDiskUsageQuery("SELECT total_bytes, free_bytes FROM monitoring.disk_usage()")
+		require.NoError(t, pg.Connect())
+
+		metrics, err := pg.CollectMetrics()
+		require.NoError(t, err, name)
+
+		assert.Nil(t, metrics.Storage.TotalSizeBytes, name)
+		assert.Nil(t, metrics.Storage.FreeSpaceBytes, name)
+		assert.NotNil(t, metrics.Storage.UsedSizeBytes, name)
+	}
+}
+
+func storageSnapshot(timestamp int64, collectedAt time.Time, usedBytes int64) *adapter.RawMetrics {
+	return &adapter.RawMetrics{
+		DatabaseID:      "test-db",
+		DatabaseType:    "postgres",
+		Timestamp:       timestamp,
+		CollectedAt:     collectedAt,
+		Storage:         &adapter.StorageMetrics{UsedSizeBytes: &usedBytes},
+		ExtendedMetrics: map[string]float64{},
+		Labels:          map[string]string{},
+	}
+}
+
+func TestNormalisers_UsedStorageDelta(t *testing.T) {
+	normalisers := map[string]normaliser.Normaliser{
+		"postgres": normaliser.NewPostgresNormaliser(),
+		"mysql":    normaliser.NewMySQLNormaliser(),
+		"mongodb":  normaliser.NewMongoDBNormaliser(),
+		"sqlite":   normaliser.NewSQLiteNormaliser(),
+	}
+	start := time.Now()
+
+	for name, n := range normalisers {
+		first, err := n.Normalise(storageSnapshot(1000, start, 500<<20))
+		require.NoError(t, err, name)
+		assert.NotContains(t, first.MetricDeltas, "used_storage_bytes", name)
+
+		grown, err := n.Normalise(storageSnapshot(1030, start.Add(30*time.Second), 530<<20))
+		require.NoError(t, err, name)
+		assert.True(t, grown.DeltasReliable, name)
+		assert.Equal(t, float64(30<<20), grown.MetricDeltas["used_storage_bytes"], name)
+
+		// A shrink, e.g. after VACUUM FULL, is kept as a negative delta
+		shrunk, err := n.Normalise(storageSnapshot(1060, start.Add(60*time.Second), 400<<20))
+		require.NoError(t, err, name)
+		assert.Equal(t, -float64(130<<20), shrunk.MetricDeltas["used_storage_bytes"], name)
+	}
+}
@@ -0,0 +1,45 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/adapter"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresAdapter_CollectsTempFileStats(t *testing.T) {
+	pool := newFakePgPool()
+	pool.row["temp_bytes"] = []any{int64(42), int64(900 << 20)}
+
+	pg := adapter.NewPostgresAdapterWithPool(pool, "test-db")
+	require.NoError(t, pg.Connect())
+
+	metrics, err := pg.CollectMetrics()
+	require.NoError(t, err)
+
+	assert.Equal(t, 42.0, metrics.ExtendedMetrics["pg.temp_files"])
+	assert.Equal(t, float64(900<<20), metrics.ExtendedMetrics["pg.temp_bytes"])
+}
+
+func TestPostgresNormaliser_TempFileDeltas(t *testing.T) {
+	n := normaliser.NewPostgresNormaliser()
+	start := time.Now()
+
+	first := sessionsSnapshot(start, 1000, 50000)
+	first.ExtendedMetrics["pg.temp_files"] = 10
+	first.ExtendedMetrics["pg.temp_bytes"] = 100 << 20
+	_, err := n.Normalise(first)
+	require.NoError(t, err)
+
+	second := sessionsSnapshot(start.Add(30*time.Second), 1010, 50100)
+	second.ExtendedMetrics["pg.temp_files"] = 16
+	second.ExtendedMetrics["pg.temp_bytes"] = 400 << 20
+	normalised, err := n.Normalise(second)
+	require.NoError(t, err)
+
+	assert.Equal(t, 6.0, normalised.MetricDeltas["temp_files"])
+	assert.Equal(t, float64(300<<20), normalised.MetricDeltas["temp_bytes"])
+}
@@ -0,0 +1,83 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/adapter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractQueryColumns_EqualityAndRange(t *testing.T) {
+	cols := adapter.ExtractQueryColumns(
+		"SELECT * FROM orders WHERE customer_id = $1 AND created_at > $2 AND status IN ($3, $4)",
+		"orders",
+	)
+
+	assert.Equal(t, []string{"customer_id", "status"}, cols.Equality)
+	assert.Equal(t, []string{"created_at"}, cols.Range)
+	assert.Equal(t, []string{"customer_id", "status", "created_at"}, cols.IndexColumns())
+}
+
+func TestExtractQueryColumns_OrderBy(t *testing.T) {
+	cols := adapter.ExtractQueryColumns(
+		"SELECT id, total FROM orders WHERE customer_id = $1 ORDER BY created_at DESC LIMIT $2",
+		"orders",
+	)
+
+	assert.Equal(t, []string{"customer_id"}, cols.Equality)
+	assert.Equal(t, []string{"created_at"}, cols.OrderBy)
+	assert.Equal(t, []string{"customer_id", "created_at"}, cols.IndexColumns())
+}
+
+func TestExtractQueryColumns_JoinWithAliases(t *testing.T) {
+	query := `SELECT o.id, c.name
+		FROM orders o
+		JOIN customers AS c ON c.id = o.customer_id
+		WHERE c.country = $1 AND o.placed_at >= $2
+		ORDER BY o.placed_at`
+
+	orders := adapter.ExtractQueryColumns(query, "orders")
+	assert.Equal(t, []string{"customer_id"}, orders.Equality)
+	assert.Equal(t, []string{"placed_at"}, orders.Range)
+	assert.Equal(t, []string{"placed_at"}, orders.OrderBy)
+
+	customers := adapter.ExtractQueryColumns(query, "customers")
+	assert.Equal(t, []string{"id", "country"}, customers.Equality)
+	assert.Empty(t, customers.Range)
+}
+
+func TestExtractQueryColumns_MixedCaseAndKeywords(t *testing.T) {
+	cols := adapter.ExtractQueryColumns(
+		"select * From Users where LOWER(email) = $1 AND Tenant_ID = $2 and Deleted_At IS NULL Limit $3",
+		"users",
+	)
+
+	// Function calls are not plain columns; LIMIT is never mistaken for a column
+	assert.Equal(t, []string{"tenant_id", "deleted_at"}, cols.Equality)
+	assert.Empty(t, cols.Range)
+}
+
+func TestExtractQueryColumns_OtherTableIgnored(t *testing.T) {
+	cols := adapter.ExtractQueryColumns("SELECT * FROM products WHERE sku = $1", "orders")
+
+	assert.Empty(t, cols.IndexColumns())
+}
+
+func TestRecommendIndexColumns_WeightsByTotalTime(t *testing.T) {
+	queries := []adapter.WeightedQuery{
+		// Frequent but fast
+		{Query: "SELECT * FROM orders WHERE status = $1", Calls: 100, MeanExecTimeMs: 1},
+		// Less frequent but much slower
+		{Query: "SELECT * FROM orders WHERE customer_id = $1 ORDER BY created_at", Calls: 20, MeanExecTimeMs: 50},
+	}
+
+	assert.Equal(t, []string{"customer_id", "created_at"}, adapter.RecommendIndexColumns(queries, "orders"))
+}
+
+func TestRecommendIndexColumns_NoCandidates(t *testing.T) {
+	queries := []adapter.WeightedQuery{
+		{Query: "SELECT count(*) FROM orders", Calls: 10, MeanExecTimeMs: 100},
+	}
+
+	assert.Nil(t, adapter.RecommendIndexColumns(queries, "orders"))
+}
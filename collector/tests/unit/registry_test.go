@@ -0,0 +1,205 @@
+package unit
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/adapter"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/config"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/deployment"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/orchestrator"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/registry"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestRegistry_BuiltinDatabases(t *testing.T) {
+	var names []string
+	for _, db := range registry.Databases() {
+		names = append(names, db.Name)
+	}
+	assert.Subset(t, names, []string{"mongodb", "mysql", "postgres", "sqlite"})
+
+	db, ok := registry.Lookup("postgresql")
+	require.True(t, ok)
+	assert.Equal(t, "postgres", db.Name)
+	assert.True(t, db.Capabilities.Latency)
+
+	for _, dbType := range []string{"postgres", "mariadb", "mongo", "sqlite3"} {
+		assert.NotNil(t, registry.NewNormaliser(dbType), dbType)
+	}
+	assert.Nil(t, registry.NewNormaliser("unsupported-db"))
+}
+
+func TestRegistry_RegisterRejectsTakenName(t *testing.T) {
+	assert.Panics(t, func() {
+		registry.Register(registry.Database{
+			Name:          "postgresql",
+			NewAdapter:    func(string, string) adapter.MetricAdapter { return nil },
+			NewNormaliser: func() normaliser.Normaliser { return nil },
+		})
+	})
+	assert.Panics(t, func() {
+		registry.Register(registry.Database{Name: "no-constructors"})
+	})
+}
+
+func TestCapabilities_Families(t *testing.T) {
+	caps := registry.Capabilities{Connections: true, TableStats: true, Storage: true}
+
+	assert.Equal(t, []string{"connections", "table_stats", "storage"}, caps.Families())
+	assert.Empty(t, registry.Capabilities{}.Families())
+}
+
+// fakeDBAdapter reports fixed connection counts and records how the orchestrator drives it.
+type fakeDBAdapter struct {
+	databaseID string
+
+	mu        sync.Mutex
+	connected bool
+	collected int
+}
+
+func (a *fakeDBAdapter) Connect() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.connected = true
+	return nil
+}
+
+func (a *fakeDBAdapter) CollectMetrics() (*adapter.RawMetrics, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.collected++
+
+	active, maxConn := int32(3), int32(10)
+	metrics := adapter.NewRawMetrics(a.databaseID, "fakedb")
+	metrics.Connections = &adapter.ConnectionMetrics{Active: &active, Max: &maxConn}
+	return metrics, nil
+}
+
+func (a *fakeDBAdapter) isConnected() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.connected
+}
+
+func (a *fakeDBAdapter) collections() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.collected
+}
+
+func (a *fakeDBAdapter) Close() error                     { return nil }
+func (a *fakeDBAdapter) HealthCheck() error               { return nil }
+func (a *fakeDBAdapter) GetUnavailableFeatures() []string { return nil }
+
+// fakeDBNormaliser counts the snapshots it normalises.
+type fakeDBNormaliser struct {
+	mu         sync.Mutex
+	normalised int
+}
+
+func (n *fakeDBNormaliser) Normalise(raw *adapter.RawMetrics) (*normaliser.NormalisedMetrics, error) {
+	n.mu.Lock()
+	n.normalised++
+	n.mu.Unlock()
+	return normaliser.NewMySQLNormaliser().Normalise(raw)
+}
+
+func (n *fakeDBNormaliser) count() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.normalised
+}
+
+var (
+	registerFakeDB  sync.Once
+	fakeDBAdapters  = make(chan *fakeDBAdapter, 10)
+	fakeDBNormalise = &fakeDBNormaliser{}
+)
+
+// fakeKnowledge lists a single enabled database of the fake type.
+type fakeKnowledge struct {
+	pb.UnimplementedKnowledgeServiceServer
+}
+
+func (k *fakeKnowledge) ListDatabases(ctx context.Context, req *pb.ListDatabasesRequest) (*pb.DatabaseListResponse, error) {
+	return &pb.DatabaseListResponse{Databases: []*pb.RegisteredDatabase{{
+		DatabaseId:       "fake-1",
+		DatabaseType:     "fakedb",
+		DatabaseName:     "fake",
+		ConnectionString: "fake://localhost/fake",
+		Enabled:          true,
+		Metadata:         map[string]string{deployment.MetadataContext: "local"},
+	}}}, nil
+}
+
+func TestOrchestrator_CollectsFromRegisteredAdapter(t *testing.T) {
+	registerFakeDB.Do(func() {
+		registry.Register(registry.Database{
+			Name:         "fakedb",
+			Capabilities: registry.Capabilities{Connections: true},
+			NewAdapter: func(connectionString, databaseID string) adapter.MetricAdapter {
+				a := &fakeDBAdapter{databaseID: databaseID}
+				fakeDBAdapters <- a
+				return a
+			},
+			NewNormaliser: func() normaliser.Normaliser { return fakeDBNormalise },
+		})
+	})
+
+	knowledgeAddr := freeAddress(t)
+	lis, err := net.Listen("tcp", knowledgeAddr)
+	require.NoError(t, err)
+	knowledgeServer := grpc.NewServer()
+	pb.RegisterKnowledgeServiceServer(knowledgeServer, &fakeKnowledge{})
+	go knowledgeServer.Serve(lis)
+	defer knowledgeServer.Stop()
+
+	analyserAddr := freeAddress(t)
+	analyser := &fakeAnalyser{}
+	analyserServer := startFakeAnalyser(t, analyserAddr, analyser)
+	defer analyserServer.Stop()
+
+	cfg := &config.Config{
+		AnalyserAddress:    analyserAddr,
+		KnowledgeAddress:   knowledgeAddr,
+		CollectionInterval: time.Second,
+		SyncInterval:       30 * time.Second,
+	}
+	orch := orchestrator.NewOrchestrator(cfg)
+	defer orch.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	require.NoError(t, orch.Start(ctx))
+	go orch.Run(ctx)
+
+	var fake *fakeDBAdapter
+	select {
+	case fake = <-fakeDBAdapters:
+	case <-ctx.Done():
+		t.Fatal("Expected the orchestrator to create the registered adapter")
+	}
+
+	assert.Eventually(t, func() bool {
+		for _, id := range analyser.snapshots() {
+			if id == "fake-1" {
+				return true
+			}
+		}
+		return false
+	}, 10*time.Second, 50*time.Millisecond, "snapshot from the fake adapter should reach the Analyser")
+
+	assert.True(t, fake.isConnected())
+	assert.Positive(t, fake.collections())
+	assert.Positive(t, fakeDBNormalise.count(), "the registered normaliser should be used")
+}
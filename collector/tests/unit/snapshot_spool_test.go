@@ -0,0 +1,129 @@
+package unit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	grpcclient "github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/grpc"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/spool"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func spooledIDs(s *spool.Spool) []string {
+	var ids []string
+	for _, snapshot := range s.Peek(0) {
+		ids = append(ids, snapshot.DatabaseId)
+	}
+	return ids
+}
+
+func TestSpool_SurvivesReopenAndDropsOldest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spool", "snapshots.spool")
+
+	s, err := spool.Open(path, 3)
+	require.NoError(t, err)
+	for _, id := range []string{"a", "b", "c", "d"} {
+		require.NoError(t, s.Append(snapshotFor(id)))
+	}
+	assert.Equal(t, []string{"b", "c", "d"}, spooledIDs(s))
+	require.NoError(t, s.Close())
+
+	// A restarted Collector finds the same snapshots, with their original timestamps
+	s, err = spool.Open(path, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "c", "d"}, spooledIDs(s))
+
+	require.NoError(t, s.Remove(2))
+	assert.Equal(t, []string{"d"}, spooledIDs(s))
+	require.NoError(t, s.Close())
+
+	s, err = spool.Open(path, 3)
+	require.NoError(t, err)
+	defer s.Close()
+	assert.Equal(t, []string{"d"}, spooledIDs(s))
+}
+
+func TestSpool_DiscardsRecordTornByCrash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshots.spool")
+
+	s, err := spool.Open(path, 10)
+	require.NoError(t, err)
+	require.NoError(t, s.Append(snapshotFor("a")))
+	require.NoError(t, s.Append(snapshotFor("b")))
+	require.NoError(t, s.Close())
+
+	// A write cut short leaves a length prefix with too few bytes after it
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	require.NoError(t, err)
+	_, err = file.Write([]byte{0, 0, 0, 50, 1, 2})
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	s, err = spool.Open(path, 10)
+	require.NoError(t, err)
+	defer s.Close()
+	assert.Equal(t, []string{"a", "b"}, spooledIDs(s))
+
+	require.NoError(t, s.Append(snapshotFor("c")))
+	assert.Equal(t, []string{"a", "b", "c"}, spooledIDs(s))
+}
+
+func TestMetricsClient_ReplaysSpoolAfterRestart(t *testing.T) {
+	addr := freeAddress(t) // Analyser down to begin with
+	path := filepath.Join(t.TempDir(), "snapshots.spool")
+
+	s, err := spool.Open(path, 10)
+	require.NoError(t, err)
+
+	client := grpcclient.NewMetricsClient(addr, transport.TLSConfig{})
+	client.SetMaxBackoff(time.Millisecond)
+	client.SetSpool(s)
+	require.NoError(t, client.Connect())
+
+	for _, id := range []string{"a", "b"} {
+		err := client.Send(snapshotFor(id))
+		assert.True(t, errors.Is(err, grpcclient.ErrSnapshotsBuffered), "expected buffered error, got %v", err)
+		time.Sleep(2 * time.Millisecond)
+	}
+	assert.Equal(t, 2, client.Stats().SpooledSnapshots)
+
+	// The Collector restarts before the Analyser comes back
+	require.NoError(t, client.Close())
+	require.NoError(t, s.Close())
+
+	analyser := &fakeAnalyser{}
+	server := startFakeAnalyser(t, addr, analyser)
+	defer server.Stop()
+
+	s, err = spool.Open(path, 10)
+	require.NoError(t, err)
+	defer s.Close()
+
+	client = grpcclient.NewMetricsClient(addr, transport.TLSConfig{})
+	client.SetSpool(s)
+	require.NoError(t, client.Connect())
+	defer client.Close()
+
+	// Connecting replays the spool; a new snapshot queues behind it until it is empty
+	require.NoError(t, client.Send(snapshotFor("c")))
+	assert.Eventually(t, func() bool {
+		return client.Stats().SpooledSnapshots == 0 && client.Stats().BufferedSnapshots == 0
+	}, 2*time.Second, 5*time.Millisecond)
+
+	require.NoError(t, client.Send(snapshotFor("d")))
+	assert.Eventually(t, func() bool {
+		return len(analyser.snapshots()) == 4
+	}, 2*time.Second, 5*time.Millisecond)
+
+	assert.Equal(t, []string{"a", "b", "c", "d"}, analyser.snapshots(), "snapshots arrive in the order collected")
+	// c is replayed only if it was sent before a and b were acknowledged
+	replays := analyser.replays()
+	require.GreaterOrEqual(t, len(replays), 2)
+	assert.Equal(t, []string{"a", "b"}, replays[:2])
+	assert.NotContains(t, replays, "d", "snapshots sent once the spool is empty are live")
+}
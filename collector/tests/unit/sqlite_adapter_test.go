@@ -0,0 +1,142 @@
+package unit
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/adapter"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// newSQLiteFile creates a database file in a temp directory and runs statements on it.
+func newSQLiteFile(t *testing.T, statements ...string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "app.db")
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	for _, stmt := range statements {
+		_, err := db.Exec(stmt)
+		require.NoError(t, err, stmt)
+	}
+	return path
+}
+
+func TestSQLiteAdapter_CollectMetrics(t *testing.T) {
+	path := newSQLiteFile(t,
+		"PRAGMA page_size = 4096",
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT)",
+		"CREATE INDEX idx_users_email ON users (email)",
+		"CREATE TABLE events (id INTEGER PRIMARY KEY, user_id INTEGER, kind TEXT)",
+		"CREATE TABLE audit (id INTEGER PRIMARY KEY, note TEXT)",
+		"INSERT INTO events (user_id, kind) VALUES (1, 'login'), (2, 'login'), (1, 'logout')",
+		"ANALYZE",
+	)
+
+	sqliteAdapter := adapter.NewSQLiteAdapter("sqlite://"+path, "sqlite-1")
+	require.NoError(t, sqliteAdapter.Connect())
+	defer sqliteAdapter.Close()
+
+	metrics, err := sqliteAdapter.CollectMetrics()
+	require.NoError(t, err)
+
+	assert.Equal(t, "sqlite", metrics.DatabaseType)
+	assert.Nil(t, metrics.Connections, "SQLite has no connection pool to report")
+	assert.Nil(t, metrics.Queries)
+
+	require.NotNil(t, metrics.Storage)
+	pageCount := metrics.ExtendedMetrics["sqlite.page_count"]
+	assert.Greater(t, pageCount, 0.0)
+	assert.Equal(t, int64(pageCount)*4096, *metrics.Storage.UsedSizeBytes)
+	assert.Contains(t, metrics.ExtendedMetrics, "sqlite.freelist_count")
+
+	require.NotNil(t, metrics.Cache)
+	assert.Nil(t, metrics.Cache.HitRate)
+	assert.Greater(t, *metrics.Cache.CacheSizeBytes, int64(0))
+
+	assert.Equal(t, 2.0, metrics.ExtendedMetrics["sqlite.unindexed_table_count"])
+	assert.Equal(t, 3.0, metrics.ExtendedMetrics["sqlite.table.events.rows"])
+	assert.Equal(t, "events", metrics.Labels["sqlite.largest_unindexed_table"])
+	assert.NotEmpty(t, metrics.Labels["sqlite.journal_mode"])
+
+	assert.NoError(t, sqliteAdapter.HealthCheck())
+}
+
+func TestSQLiteAdapter_OpensReadOnly(t *testing.T) {
+	path := newSQLiteFile(t, "CREATE TABLE notes (id INTEGER PRIMARY KEY)")
+
+	sqliteAdapter := adapter.NewSQLiteAdapter("file:"+path+"?cache=shared", "sqlite-1")
+	require.NoError(t, sqliteAdapter.Connect())
+	require.NoError(t, sqliteAdapter.Close())
+
+	// Collecting must not leave a journal or WAL behind in the application's directory
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestSQLiteAdapter_Connect_MissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.db")
+	sqliteAdapter := adapter.NewSQLiteAdapter(path, "sqlite-1")
+
+	err := sqliteAdapter.Connect()
+
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	_, statErr := os.Stat(path)
+	assert.True(t, os.IsNotExist(statErr), "Connect must not create the file")
+}
+
+func TestSQLiteAdapter_Connect_NotADatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	require.NoError(t, os.WriteFile(path, []byte("definitely not a SQLite database file, just some text"), 0o644))
+
+	err := adapter.NewSQLiteAdapter(path, "sqlite-1").Connect()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read SQLite database")
+}
+
+func TestSQLiteAdapter_NotConnected(t *testing.T) {
+	sqliteAdapter := adapter.NewSQLiteAdapter("/tmp/app.db", "sqlite-1")
+
+	metrics, err := sqliteAdapter.CollectMetrics()
+	assert.Nil(t, metrics)
+	assert.Equal(t, adapter.ErrNotConnected, err)
+	assert.Equal(t, adapter.ErrNotConnected, sqliteAdapter.HealthCheck())
+	assert.NoError(t, sqliteAdapter.Close())
+}
+
+func TestNewAdapter_SQLite(t *testing.T) {
+	a, err := registry.NewAdapter("sqlite", "/var/lib/app/app.db", "test-db")
+
+	assert.NoError(t, err)
+	assert.IsType(t, &adapter.SQLiteAdapter{}, a)
+}
+
+func TestSQLiteNormaliser_ConnectionHealthDefaultsToHealthy(t *testing.T) {
+	path := newSQLiteFile(t, "CREATE TABLE notes (id INTEGER PRIMARY KEY)")
+	sqliteAdapter := adapter.NewSQLiteAdapter(path, "sqlite-1")
+	require.NoError(t, sqliteAdapter.Connect())
+	defer sqliteAdapter.Close()
+
+	raw, err := sqliteAdapter.CollectMetrics()
+	require.NoError(t, err)
+
+	n := registry.NewNormaliser("sqlite")
+	require.NotNil(t, n)
+	normalised, err := n.Normalise(raw)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1.0, normalised.ConnectionHealth)
+	assert.Equal(t, 1.0, normalised.HealthScore)
+	assert.NotContains(t, normalised.AvailableMetrics, "active_connections")
+	assert.NotContains(t, normalised.AvailableMetrics, "cache_hit_rate")
+	assert.Contains(t, normalised.AvailableMetrics, "used_storage_bytes")
+}
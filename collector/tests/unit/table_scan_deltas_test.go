@@ -0,0 +1,38 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/internal/adapter"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/collector/normaliser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tableScanSnapshot(timestamp int64, seqScans map[string]float64) *adapter.RawMetrics {
+	extended := map[string]float64{}
+	for table, scans := range seqScans {
+		extended["pg.table."+table+".seq_scans"] = scans
+	}
+	return &adapter.RawMetrics{
+		DatabaseID:      "test-db",
+		DatabaseType:    "postgres",
+		Timestamp:       timestamp,
+		ExtendedMetrics: extended,
+		Labels:          map[string]string{"pg.seq_scan_candidate_tables": "orders,users"},
+	}
+}
+
+func TestPostgresNormaliser_PerTableSeqScanDeltas(t *testing.T) {
+	n := normaliser.NewPostgresNormaliser()
+
+	_, err := n.Normalise(tableScanSnapshot(100, map[string]float64{"orders": 1000, "users": 500}))
+	require.NoError(t, err)
+
+	normalised, err := n.Normalise(tableScanSnapshot(110, map[string]float64{"orders": 1300, "users": 20}))
+	require.NoError(t, err)
+
+	assert.Equal(t, 300.0, normalised.MetricDeltas["pg.table.orders.seq_scans"])
+	assert.Equal(t, 20.0, normalised.MetricDeltas["pg.table.users.seq_scans"], "A counter reset counts every scan since")
+	assert.Equal(t, "orders,users", normalised.Labels["pg.seq_scan_candidate_tables"])
+}
@@ -4,7 +4,7 @@ import { Card, CardHeader, CardTitle, CardContent, CardDescription } from "@/com
 import { Badge } from "@/components/ui/badge";
 import { Alert, AlertDescription } from "@/components/ui/alert";
 import { Button } from "@/components/ui/button";
-import { CheckCircle, Clock, Loader2, XCircle, Wrench, Undo2, AlertTriangle, ExternalLink, Eye, ThumbsUp, ThumbsDown } from "lucide-react";
+import { Ban, CalendarClock, CheckCircle, Clock, Loader2, XCircle, Wrench, Undo2, AlertTriangle, ExternalLink, Eye, ThumbsUp, ThumbsDown } from "lucide-react";
 import { toast } from "sonner";
 import { useActions } from "@/hooks/useActions";
 import { useDatabase } from "@/components/providers/DatabaseProvider";
@@ -60,8 +60,8 @@ export default function ActionsPage() {
         );
     }
 
-    // Calculate stats (exclude rolled_back and rejected actions from main counts)
-    const activeActions = actions.filter(a => a.status !== 'rolled_back' && a.status !== 'rejected');
+    // Calculate stats (exclude rolled_back, rejected and refused actions from main counts)
+    const activeActions = actions.filter(a => a.status !== 'rolled_back' && a.status !== 'rejected' && a.status !== 'refused');
     const suggestedCount = activeActions.filter(a => a.status === 'suggested').length;
     const pendingApprovalCount = activeActions.filter(a => a.status === 'pending_approval').length;
     const queuedCount = activeActions.filter(a => a.status === 'queued').length;
@@ -170,14 +170,28 @@ function ActionCard({ action, showDatabaseBadge = false }: { action: ActionResul
     const [isApproving, setIsApproving] = useState(false);
     const [isRejecting, setIsRejecting] = useState(false);
 
-    const handleRollback = async () => {
+    const handleRollback = async (force = false) => {
         setIsRollingBack(true);
     
         try {
-            const response = await fetch(`/api/actions/${action.action_id}/rollback`, {
+            const query = force ? '?force=true' : '';
+            const response = await fetch(`/api/actions/${action.action_id}/rollback${query}`, {
                 method: 'POST',
             });
 
+            if (response.status === 409) {
+                // Refused by the safety checks: something still depends on the change
+                const error = await response.json();
+                toast.warning('Rollback refused', {
+                    description: error.error,
+                    action: {
+                        label: 'Force rollback',
+                        onClick: () => handleRollback(true),
+                    },
+                });
+                return;
+            }
+
             if (!response.ok) {
                 const error = await response.json();
                 throw new Error(error.error || 'Rollback failed');
@@ -261,11 +275,21 @@ function ActionCard({ action, showDatabaseBadge = false }: { action: ActionResul
             icon: <ThumbsDown className="h-5 w-5 text-gray-600" />,
             bgClass: 'bg-gray-50 dark:bg-gray-950/20 border-gray-200 dark:border-gray-800'
         },
+        refused: {
+            variant: 'secondary' as const,
+            icon: <Ban className="h-5 w-5 text-gray-600" />,
+            bgClass: 'bg-gray-50 dark:bg-gray-950/20 border-gray-200 dark:border-gray-800'
+        },
         queued: {
             variant: 'secondary' as const,
             icon: <Clock className="h-5 w-5 text-blue-600" />,
             bgClass: 'bg-blue-50 dark:bg-blue-950/20 border-blue-200 dark:border-blue-900'
         },
+        scheduled: {
+            variant: 'secondary' as const,
+            icon: <CalendarClock className="h-5 w-5 text-indigo-600" />,
+            bgClass: 'bg-indigo-50 dark:bg-indigo-950/20 border-indigo-200 dark:border-indigo-900'
+        },
         executing: {
             variant: 'default' as const,
             icon: <Loader2 className="h-5 w-5 text-yellow-600 animate-spin" />,
@@ -285,15 +309,30 @@ function ActionCard({ action, showDatabaseBadge = false }: { action: ActionResul
             variant: 'secondary' as const,
             icon: <Undo2 className="h-5 w-5 text-purple-600" />,
             bgClass: 'bg-purple-50 dark:bg-purple-950/20 border-purple-200 dark:border-purple-900'
+        },
+        regressed: {
+            variant: 'destructive' as const,
+            icon: <AlertTriangle className="h-5 w-5 text-orange-600" />,
+            bgClass: 'bg-orange-50 dark:bg-orange-950/20 border-orange-200 dark:border-orange-900'
+        },
+        rollback_failed: {
+            variant: 'destructive' as const,
+            icon: <AlertTriangle className="h-5 w-5 text-red-600" />,
+            bgClass: 'bg-red-50 dark:bg-red-950/20 border-red-200 dark:border-red-900'
+        },
+        pending_implementation: {
+            variant: 'secondary' as const,
+            icon: <Wrench className="h-5 w-5 text-slate-600" />,
+            bgClass: 'bg-slate-50 dark:bg-slate-950/20 border-slate-200 dark:border-slate-800'
         }
     };
 
     const config = statusConfig[action.status as keyof typeof statusConfig] || statusConfig.queued;
 
-    const isRecommendation = action.action_type === 'recommendation' || action.action_type === 'cache_optimization_recommendation';
+    const isRecommendation = action.action_type === 'recommendation' || action.action_type === 'cache_optimization_recommendation' || action.action_type === 'report_slow_queries';
     const isConfigTuning = action.action_type === 'tune_config_high_latency';
     const isPendingApproval = action.status === 'pending_approval';
-    const canRollback = action.status === 'completed' && action.can_rollback && !isRecommendation;
+    const canRollback = (action.status === 'completed' || action.status === 'regressed' || action.status === 'rollback_failed') && action.can_rollback && !isRecommendation;
 
     return (
         <Card className={config.bgClass}>
@@ -363,7 +402,7 @@ function ActionCard({ action, showDatabaseBadge = false }: { action: ActionResul
                             <Button
                                 variant="outline"
                                 size="sm"
-                                onClick={handleRollback}
+                                onClick={() => handleRollback()}
                                 disabled={isRollingBack}
                             >
                                 {isRollingBack ? (
@@ -387,8 +426,30 @@ function ActionCard({ action, showDatabaseBadge = false }: { action: ActionResul
                     </div>
                 )}
 
+                {/* Execution policy hint */}
+                {(action.status === 'pending_approval' || action.status === 'suggested') && action.policy_rule && action.policy_rule !== 'execution_mode=observe' && (
+                    <Alert className="bg-amber-100 dark:bg-amber-900 border-amber-300">
+                        <ThumbsUp className="h-4 w-4" />
+                        <AlertDescription className="text-xs">
+                            {action.status === 'pending_approval'
+                                ? `Waiting for approval because of execution policy rule ${action.policy_rule}.`
+                                : `Suggested only because of execution policy rule ${action.policy_rule}.`}
+                        </AlertDescription>
+                    </Alert>
+                )}
+
+                {/* Database policy hint */}
+                {action.status === 'refused' && action.policy_rule && (
+                    <Alert className="bg-gray-100 dark:bg-gray-900 border-gray-300">
+                        <Ban className="h-4 w-4" />
+                        <AlertDescription className="text-xs">
+                            Not run: the database policy forbids {action.action_type} on {action.database_id} ({action.policy_rule}).
+                        </AlertDescription>
+                    </Alert>
+                )}
+
                 {/* Observe mode hint */}
-                {action.status === 'suggested' && (
+                {action.status === 'suggested' && (!action.policy_rule || action.policy_rule === 'execution_mode=observe') && (
                     <Alert className="bg-slate-100 dark:bg-slate-900 border-slate-300">
                         <Eye className="h-4 w-4" />
                         <AlertDescription className="text-xs">
@@ -397,13 +458,40 @@ function ActionCard({ action, showDatabaseBadge = false }: { action: ActionResul
                     </Alert>
                 )}
 
+                {/* Maintenance window hint */}
+                {action.status === 'scheduled' && action.scheduled_for && (
+                    <Alert className="bg-indigo-100 dark:bg-indigo-900 border-indigo-300">
+                        <CalendarClock className="h-4 w-4" />
+                        <AlertDescription className="text-xs">
+                            Held for the maintenance window: runs at {new Date(action.scheduled_for).toLocaleString()}.
+                        </AlertDescription>
+                    </Alert>
+                )}
+
                 {/* Error (if failed) */}
                 {action.error && (
                     <div className="border-l-2 border-red-500 pl-4">
-                        <h4 className="text-sm font-semibold mb-1 text-red-600">Error</h4>
+                        <h4 className="text-sm font-semibold mb-1 text-red-600">
+                            Error{action.error_code && ` (${action.error_code})`}
+                        </h4>
                         <p className="text-sm text-muted-foreground">
                             {action.error}
                         </p>
+                        {action.attempts !== undefined && (action.status === 'queued' || action.attempts > 1) && (
+                            <p className="text-xs text-muted-foreground mt-1">
+                                {action.status === 'queued' ? `Attempt ${action.attempts} failed, retrying` : `Failed after ${action.attempts} attempts`}
+                            </p>
+                        )}
+                    </div>
+                )}
+
+                {/* Rollback error (if rollback failed) */}
+                {action.rollback_error && (
+                    <div className="border-l-2 border-red-500 pl-4">
+                        <h4 className="text-sm font-semibold mb-1 text-red-600">Rollback Error</h4>
+                        <p className="text-sm text-muted-foreground">
+                            {action.rollback_error}
+                        </p>
                     </div>
                 )}
 
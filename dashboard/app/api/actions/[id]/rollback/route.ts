@@ -1,4 +1,5 @@
 import { NextRequest, NextResponse } from "next/server";
+import { executorHeaders } from "@/lib/executor";
 
 const EXECUTOR_URL = process.env.EXECUTOR_URL || "http://localhost:8084";
 
@@ -9,10 +10,14 @@ export async function POST(
     try {
         const { id } = await params;
 
-        console.log(`Rolling back action: ${id}`);
+        const force = request.nextUrl.searchParams.get("force") === "true";
 
-        const response = await fetch(`${EXECUTOR_URL}/api/actions/${id}/rollback`, {
+        console.log(`Rolling back action: ${id}${force ? " (forced)" : ""}`);
+
+        const query = force ? "?force=true" : "";
+        const response = await fetch(`${EXECUTOR_URL}/api/actions/${id}/rollback${query}`, {
             method: "POST",
+            headers: executorHeaders(),
         });
 
         if (!response.ok) {
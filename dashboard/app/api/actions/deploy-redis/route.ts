@@ -1,4 +1,5 @@
 import { NextRequest, NextResponse } from "next/server";
+import { executorHeaders } from "@/lib/executor";
 
 export const dynamic = 'force-dynamic';
 
@@ -10,7 +11,7 @@ export async function POST(request: NextRequest) {
 
         const response = await fetch(`${EXECUTOR_URL}/api/deploy-redis`, {
             method: 'POST',
-            headers: { 'Content-Type': 'application/json' },
+            headers: executorHeaders({ 'Content-Type': 'application/json' }),
             body: JSON.stringify(body),
         });
 
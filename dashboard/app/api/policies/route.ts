@@ -0,0 +1,66 @@
+import { NextRequest, NextResponse } from "next/server";
+import { executorHeaders } from "@/lib/executor";
+
+export const dynamic = 'force-dynamic';
+
+const EXECUTOR_URL = process.env.EXECUTOR_URL || "http://localhost:8084";
+
+// Database policies live in Knowledge; the Executor exposes them over HTTP, applies a change
+// to its next action and the Analyser within its policy cache TTL.
+export async function GET(request: NextRequest) {
+    try {
+        const databaseId = request.nextUrl.searchParams.get("database_id");
+        const query = databaseId ? `?database_id=${encodeURIComponent(databaseId)}` : "";
+
+        const response = await fetch(`${EXECUTOR_URL}/api/policies${query}`, {
+            headers: executorHeaders(),
+            cache: "no-store",
+        });
+
+        if (!response.ok) {
+            const error = await response.text();
+            return NextResponse.json(
+                { error: error || "Failed to fetch policies" },
+                { status: response.status }
+            );
+        }
+
+        const data = await response.json();
+        return NextResponse.json(data);
+    } catch (error) {
+        console.error("Failed to fetch policies:", error);
+        return NextResponse.json(
+            { error: "Failed to fetch policies" },
+            { status: 500 }
+        );
+    }
+}
+
+export async function PUT(request: NextRequest) {
+    try {
+        const body = await request.json();
+
+        const response = await fetch(`${EXECUTOR_URL}/api/policies`, {
+            method: "PUT",
+            headers: executorHeaders({ "Content-Type": "application/json" }),
+            body: JSON.stringify(body),
+        });
+
+        if (!response.ok) {
+            const error = await response.text();
+            return NextResponse.json(
+                { error: error || "Failed to save policies" },
+                { status: response.status }
+            );
+        }
+
+        const data = await response.json();
+        return NextResponse.json(data);
+    } catch (error) {
+        console.error("Failed to save policies:", error);
+        return NextResponse.json(
+            { error: "Failed to save policies" },
+            { status: 500 }
+        );
+    }
+}
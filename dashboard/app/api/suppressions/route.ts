@@ -0,0 +1,94 @@
+import { NextRequest, NextResponse } from "next/server";
+import { executorHeaders } from "@/lib/executor";
+
+export const dynamic = 'force-dynamic';
+
+const EXECUTOR_URL = process.env.EXECUTOR_URL || "http://localhost:8084";
+
+// Suppressions live in Knowledge; the Analyser skips suppressed detection keys until
+// the suppression expires.
+export async function GET() {
+    try {
+        const response = await fetch(`${EXECUTOR_URL}/api/suppressions`, {
+            headers: executorHeaders(),
+            cache: "no-store",
+        });
+
+        if (!response.ok) {
+            const error = await response.text();
+            return NextResponse.json(
+                { error: error || "Failed to fetch suppressions" },
+                { status: response.status }
+            );
+        }
+
+        const data = await response.json();
+        return NextResponse.json(data);
+    } catch (error) {
+        console.error("Failed to fetch suppressions:", error);
+        return NextResponse.json(
+            { error: "Failed to fetch suppressions" },
+            { status: 500 }
+        );
+    }
+}
+
+export async function POST(request: NextRequest) {
+    try {
+        const body = await request.json();
+
+        const response = await fetch(`${EXECUTOR_URL}/api/suppressions`, {
+            method: "POST",
+            headers: executorHeaders({ "Content-Type": "application/json" }),
+            body: JSON.stringify(body),
+        });
+
+        if (!response.ok) {
+            const error = await response.text();
+            return NextResponse.json(
+                { error: error || "Failed to suppress detection" },
+                { status: response.status }
+            );
+        }
+
+        const data = await response.json();
+        return NextResponse.json(data, { status: response.status });
+    } catch (error) {
+        console.error("Failed to suppress detection:", error);
+        return NextResponse.json(
+            { error: "Failed to suppress detection" },
+            { status: 500 }
+        );
+    }
+}
+
+export async function DELETE(request: NextRequest) {
+    try {
+        const key = request.nextUrl.searchParams.get("key");
+        if (!key) {
+            return NextResponse.json({ error: "key is required" }, { status: 400 });
+        }
+
+        const response = await fetch(`${EXECUTOR_URL}/api/suppressions?key=${encodeURIComponent(key)}`, {
+            method: "DELETE",
+            headers: executorHeaders(),
+        });
+
+        if (!response.ok) {
+            const error = await response.text();
+            return NextResponse.json(
+                { error: error || "Failed to lift suppression" },
+                { status: response.status }
+            );
+        }
+
+        const data = await response.json();
+        return NextResponse.json(data);
+    } catch (error) {
+        console.error("Failed to lift suppression:", error);
+        return NextResponse.json(
+            { error: "Failed to lift suppression" },
+            { status: 500 }
+        );
+    }
+}
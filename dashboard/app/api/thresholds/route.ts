@@ -0,0 +1,66 @@
+import { NextRequest, NextResponse } from "next/server";
+import { executorHeaders } from "@/lib/executor";
+
+export const dynamic = 'force-dynamic';
+
+const EXECUTOR_URL = process.env.EXECUTOR_URL || "http://localhost:8084";
+
+// Threshold overrides live in Knowledge; the Executor exposes them over HTTP and the
+// Analyser picks up changes on its next refresh.
+export async function GET(request: NextRequest) {
+    try {
+        const databaseId = request.nextUrl.searchParams.get("database_id");
+        const query = databaseId ? `?database_id=${encodeURIComponent(databaseId)}` : "";
+
+        const response = await fetch(`${EXECUTOR_URL}/api/thresholds${query}`, {
+            headers: executorHeaders(),
+            cache: "no-store",
+        });
+
+        if (!response.ok) {
+            const error = await response.text();
+            return NextResponse.json(
+                { error: error || "Failed to fetch thresholds" },
+                { status: response.status }
+            );
+        }
+
+        const data = await response.json();
+        return NextResponse.json(data);
+    } catch (error) {
+        console.error("Failed to fetch thresholds:", error);
+        return NextResponse.json(
+            { error: "Failed to fetch thresholds" },
+            { status: 500 }
+        );
+    }
+}
+
+export async function PUT(request: NextRequest) {
+    try {
+        const body = await request.json();
+
+        const response = await fetch(`${EXECUTOR_URL}/api/thresholds`, {
+            method: "PUT",
+            headers: executorHeaders({ "Content-Type": "application/json" }),
+            body: JSON.stringify(body),
+        });
+
+        if (!response.ok) {
+            const error = await response.text();
+            return NextResponse.json(
+                { error: error || "Failed to save thresholds" },
+                { status: response.status }
+            );
+        }
+
+        const data = await response.json();
+        return NextResponse.json(data);
+    } catch (error) {
+        console.error("Failed to save thresholds:", error);
+        return NextResponse.json(
+            { error: "Failed to save thresholds" },
+            { status: 500 }
+        );
+    }
+}
@@ -66,10 +66,13 @@ const DEFAULT_WEBHOOK: WebhookConfig = {
 
 const WEBHOOK_EVENTS = [
     { id: "detection.created", label: "Detection Created" },
+    { id: "detection.unactionable", label: "Detection Unactionable" },
     { id: "action.queued", label: "Action Queued" },
     { id: "action.completed", label: "Action Completed" },
     { id: "action.failed", label: "Action Failed" },
     { id: "action.rolledback", label: "Action Rolled Back" },
+    { id: "action.rollback_failed", label: "Action Rollback Failed" },
+    { id: "action.regressed", label: "Action Regressed" },
 ];
 
 const CONNECTION_STRING_PLACEHOLDERS: Record<string, string> = {
@@ -552,7 +555,9 @@ export default function SettingsPage() {
                             <Input
                                 id="dialog-conn-string"
                                 type="password"
-                                placeholder={CONNECTION_STRING_PLACEHOLDERS[databaseForm.database_type] || CONNECTION_STRING_PLACEHOLDERS.postgres}
+                                placeholder={editingDatabase
+                                    ? "Leave blank to keep the current connection string"
+                                    : CONNECTION_STRING_PLACEHOLDERS[databaseForm.database_type] || CONNECTION_STRING_PLACEHOLDERS.postgres}
                                 value={databaseForm.connection_string}
                                 onChange={(e) =>
                                     setDatabaseForm((prev) => ({ ...prev, connection_string: e.target.value }))
@@ -578,7 +583,7 @@ export default function SettingsPage() {
                         </Button>
                         <Button
                             onClick={handleSaveDatabase}
-                            disabled={databaseSaving || !databaseForm.database_name || !databaseForm.connection_string}
+                            disabled={databaseSaving || !databaseForm.database_name || (!editingDatabase && !databaseForm.connection_string)}
                         >
                             {databaseSaving && <Loader2 className="w-4 h-4 mr-2 animate-spin" />}
                             {editingDatabase ? "Update" : "Add"}
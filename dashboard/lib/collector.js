@@ -4,6 +4,7 @@ const express = require('express');
 const grpc = require('@grpc/grpc-js');
 const protoLoader = require('@grpc/proto-loader');
 const path = require('path');
+const fs = require('fs');
 
 const metricsStore = require('../stores/metricsStore');
 const detectionsStore = require('../stores/detectionsStore');
@@ -27,11 +28,31 @@ let knowledgeClient = null;
 // Webhook configuration cache
 let webhookConfig = null;
 
+// Mutual TLS with the same GRPC_TLS_* files the Go services use; plaintext when unset
+function knowledgeCredentials() {
+    const { GRPC_TLS_CERT, GRPC_TLS_KEY, GRPC_TLS_CA } = process.env;
+    if (!GRPC_TLS_CERT && !GRPC_TLS_KEY && !GRPC_TLS_CA) {
+        return { credentials: grpc.credentials.createInsecure(), options: {} };
+    }
+
+    const credentials = grpc.credentials.createSsl(
+        fs.readFileSync(GRPC_TLS_CA),
+        fs.readFileSync(GRPC_TLS_KEY),
+        fs.readFileSync(GRPC_TLS_CERT)
+    );
+    const options = process.env.GRPC_TLS_SERVER_NAME
+        ? { 'grpc.ssl_target_name_override': process.env.GRPC_TLS_SERVER_NAME }
+        : {};
+    return { credentials, options };
+}
+
 function connectKnowledge() {
     const knowledgeAddr = process.env.KNOWLEDGE_ADDRESS || 'localhost:50053';
+    const { credentials, options } = knowledgeCredentials();
     knowledgeClient = new knowledgeProto.KnowledgeService(
         knowledgeAddr,
-        grpc.credentials.createInsecure()
+        credentials,
+        options
     );
     console.log('Knowledge client created for', knowledgeAddr);
 }
@@ -150,6 +171,36 @@ async function startCollector() {
             }
         })();
 
+        // Subscribe to detections the Executor could not act on
+        const failedSub = nc.subscribe('detections.failed');
+        (async () => {
+            for await (const msg of failedSub) {
+                try {
+                    const data = JSON.parse(sc.decode(msg.data));
+                    detectionsStore.markUnactionable(data.detection, data.reason);
+                    console.log('Detection unactionable:', data.detection?.id, data.reason);
+
+                    sendWebhook('detection.unactionable', data);
+                } catch (err) {
+                    console.error('Error processing failed detection:', err);
+                }
+            }
+        })();
+
+        // Subscribe to deployed fixes the Executor's watchdog found failing
+        const regressionSub = nc.subscribe('detections.regression');
+        (async () => {
+            for await (const msg of regressionSub) {
+                try {
+                    const data = JSON.parse(sc.decode(msg.data));
+                    detectionsStore.add(data);
+                    console.log('Stored regression:', data.title);
+                } catch (err) {
+                    console.error('Error processing regression:', err);
+                }
+            }
+        })();
+
         // Subscribe to actions
         const actionsSub = nc.subscribe('actions.status');
         (async () => {
@@ -168,6 +219,10 @@ async function startCollector() {
                         sendWebhook('action.failed', data);
                     } else if (status === 'rolled_back' || status === 'rolledback') {
                         sendWebhook('action.rolledback', data);
+                    } else if (status === 'rollback_failed') {
+                        sendWebhook('action.rollback_failed', data);
+                    } else if (status === 'regressed') {
+                        sendWebhook('action.regressed', data);
                     }
                 } catch (err) {
                     console.error('Error processing action:', err);
@@ -175,6 +230,19 @@ async function startCollector() {
             }
         })();
 
+        // Subscribe to progress of long-running actions (e.g. index builds)
+        const progressSub = nc.subscribe('actions.status.progress');
+        (async () => {
+            for await (const msg of progressSub) {
+                try {
+                    const data = JSON.parse(sc.decode(msg.data));
+                    actionsStore.addOrUpdate(data);
+                } catch (err) {
+                    console.error('Error processing action progress:', err);
+                }
+            }
+        })();
+
         console.log('Subscribed to all topics');
         
     } catch (err) {
@@ -298,7 +366,8 @@ app.get('/databases', (req, res) => {
 
     const enabledOnly = req.query.enabled_only === 'true';
     
-    knowledgeClient.listDatabases({ enabled_only: enabledOnly }, (err, response) => {
+    // The dashboard never needs credentials - Knowledge leaves connection strings out
+    knowledgeClient.listDatabases({ enabled_only: enabledOnly, redact: true }, (err, response) => {
         if (err) {
             console.error('Failed to list databases:', err);
             return res.status(500).json({ error: err.message });
@@ -315,7 +384,7 @@ app.get('/databases/:id', (req, res) => {
 
     const { id } = req.params;
 
-    knowledgeClient.getDatabase({ database_id: id }, (err, response) => {
+    knowledgeClient.getDatabase({ database_id: id, redact: true }, (err, response) => {
         if (err) {
             console.error('Failed to get database:', err);
             return res.status(500).json({ error: err.message });
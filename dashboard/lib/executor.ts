@@ -0,0 +1,9 @@
+// Headers for requests to the Executor HTTP API. When EXECUTOR_API_TOKEN is set the
+// Executor rejects requests without a matching bearer token.
+export function executorHeaders(headers: Record<string, string> = {}): Record<string, string> {
+    const token = process.env.EXECUTOR_API_TOKEN;
+    if (token) {
+        return { ...headers, Authorization: `Bearer ${token}` };
+    }
+    return headers;
+}
@@ -5,8 +5,14 @@ class DetectionsStore {
     }
 
     add(detection) {
-        const exists = this.detections.some(d => d.id === detection.id);
-        if (!exists) {
+        const existing = this.detections.find(d => d.id === detection.id);
+        if (existing) {
+            // Escalations re-use the original detection ID with a higher severity
+            if (detection.escalated) {
+                existing.severity = detection.severity;
+                existing.escalated = true;
+            }
+        } else {
             this.detections.unshift(detection);
             if (this.detections.length > this.maxSize) {
                 this.detections.pop();
@@ -14,6 +20,16 @@ class DetectionsStore {
         }
     }
 
+    markUnactionable(detection, reason) {
+        const existing = this.detections.find(d => d.id === detection.id);
+        if (existing) {
+            existing.state = 'unactionable';
+            existing.failure_reason = reason;
+        } else {
+            this.add({ ...detection, state: 'unactionable', failure_reason: reason });
+        }
+    }
+
     getAll(databaseId = null) {
         if (!databaseId) {
             return this.detections;
@@ -1,4 +1,4 @@
-export type ActionStatus = 'queued' | 'executing' | 'completed' | 'failed' | 'rolled_back' | 'suggested' | 'pending_approval' | 'rejected';
+export type ActionStatus = 'queued' | 'scheduled' | 'executing' | 'completed' | 'failed' | 'rolled_back' | 'rollback_failed' | 'regressed' | 'suggested' | 'pending_approval' | 'rejected' | 'refused' | 'pending_implementation';
 
 export interface ActionResult {
     action_id: string;
@@ -9,11 +9,27 @@ export interface ActionResult {
     message: string;
     created_at: string;
     started?: string;
+    scheduled_for?: string;
     completed?: string;
     execution_time_ms: number;
     changes?: Record<string, string | number | boolean>;
     error?: string;
+    // Why the action failed, e.g. PERMISSION_DENIED; TIMEOUT, CONFLICT and DEPENDENCY_UNAVAILABLE are retried
+    error_code?: string;
+    attempts?: number;
     can_rollback: boolean;
     rolledback: boolean;
     rollback_error?: string;
-}
\ No newline at end of file
+    policy_decision?: 'auto' | 'approve' | 'notify' | 'deny';
+    policy_rule?: string;
+}
+// What StartupMonkey may do to one database, served by /api/policies. Denied action types
+// win over allowed ones; an empty allow list allows every type.
+export interface DatabasePolicy {
+    database_id: string;
+    allowed_action_types: string[];
+    denied_action_types: string[];
+    ignored_detectors: string[];
+    reason?: string;
+    updated_at?: number;
+}
@@ -2,6 +2,7 @@ export interface Metrics {
     database_id: string;
     database_type: string;
     timestamp: number;
+    elapsed_seconds?: number;
     health_score: number;
     connection_health: number;
     query_health: number;
@@ -11,6 +12,7 @@ export interface Metrics {
     measurements: Measurements;
     metric_deltas?: Record<string, number>;
     time_delta_seconds?: number;
+    deltas_reliable?: boolean;
     extended_metrics: Record<string, number>;
     labels: Record<string, string>;
 }
@@ -3,13 +3,14 @@ package main
 import (
 	"context"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/config"
-	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/health"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/orchestrator"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/logging"
 )
 
 // main is the entry point for the Executor service.
@@ -25,10 +26,10 @@ import (
 // Lifecycle:
 //  1. Load configuration from environment variables
 //  2. Initialize orchestrator with detection handler and service connections
-//  3. Start health check server (port 8082)
+//  3. Start health check server (port 8082); a port that can't be bound fails startup
 //  4. Start HTTP server (rollback API) and gRPC server (status API)
-//  5. Listen for shutdown signals (SIGINT, SIGTERM)
-//  6. Gracefully close all connections on shutdown
+//  5. Listen for shutdown signals (SIGINT, SIGTERM); SIGHUP reloads configuration
+//  6. On shutdown, stop intake, let running actions finish, then close connections
 func main() {
 	log.Printf("StartupMonkey Executor starting...")
 
@@ -38,6 +39,8 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	logging.Setup("executor", cfg.LogLevel)
+
 	log.Printf("Configuration loaded successfully")
 	log.Printf("  gRPC Port: %s", cfg.GRPCPort)
 	log.Printf("  HTTP Port: %s", cfg.HTTPPort)
@@ -64,14 +67,20 @@ func main() {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
-	// Start health check HTTP server for container orchestration
-	// Exposes /health endpoint on configured port (default: 8082)
-	health.StartHealthCheckServer(cfg.HealthPort)
-
 	// Start HTTP and gRPC servers in background goroutine
 	go func() {
 		if err := orch.Run(ctx); err != nil && err != context.Canceled {
-			log.Printf("Orchestrator error: %v", err)
+			slog.Error("Orchestrator error", "error", err)
+		}
+	}()
+
+	// Reload configuration on SIGHUP without aborting in-flight actions
+	reloadChan := make(chan os.Signal, 1)
+	signal.Notify(reloadChan, syscall.SIGHUP)
+	go func() {
+		for range reloadChan {
+			log.Printf("SIGHUP received, reloading configuration...")
+			orch.ReloadConfig()
 		}
 	}()
 
@@ -84,7 +93,7 @@ func main() {
 
 	// Close all connections and cleanup resources
 	if err := orch.Stop(); err != nil {
-		log.Printf("Error during shutdown: %v", err)
+		slog.Error("Error during shutdown", "error", err)
 	}
 
 	log.Printf("Executor stopped successfully")
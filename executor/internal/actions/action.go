@@ -2,6 +2,7 @@ package actions
 
 import (
 	"context"
+	"time"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
 )
@@ -12,3 +13,40 @@ type Action interface {
 	Validate(ctx context.Context) error
 	GetMetadata() *models.ActionMetadata
 }
+
+// Restorable is implemented by actions that can regain rollback capability after an
+// Executor restart, using the Changes recorded in Knowledge when the action completed.
+type Restorable interface {
+	// Restore marks the action as executed from its recorded changes.
+	// Returns false if the artifact (index, container, config) no longer exists.
+	Restore(ctx context.Context, changes map[string]interface{}) (bool, error)
+}
+
+// ProgressReporter is implemented by long-running actions that can report progress
+// while they execute. The handler sets the callback before calling Execute.
+type ProgressReporter interface {
+	SetProgressFunc(fn func(progress *models.ActionProgress))
+}
+
+// WindowBound is implemented by actions that may have to wait for the maintenance window
+// even when their detection's severity or an operator would otherwise run them at once.
+type WindowBound interface {
+	RequiresMaintenanceWindow() bool
+}
+
+// RetryPreparer is implemented by actions whose failed Execute can leave work behind, e.g.
+// an INVALID index from an interrupted concurrent build. The handler calls PrepareRetry
+// before running the action again, and does not retry it if PrepareRetry fails.
+type RetryPreparer interface {
+	PrepareRetry(ctx context.Context) error
+}
+
+// cleanupTimeout bounds undoing a half-finished action, e.g. removing a container that
+// never became ready.
+const cleanupTimeout = 30 * time.Second
+
+// cleanupContext returns a context for undoing a failed Execute. It outlives ctx, whose
+// deadline may be what made Execute fail, but is still bounded by cleanupTimeout.
+func cleanupContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), cleanupTimeout)
+}
@@ -3,12 +3,16 @@ package actions
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/database"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
 )
 
+// defaultProgressInterval is how often index build progress is polled.
+const defaultProgressInterval = 5 * time.Second
+
 type CreateIndexAction struct {
 	metadata     *models.ActionMetadata
 	adapter      database.DatabaseAdapter
@@ -16,11 +20,18 @@ type CreateIndexAction struct {
 	columnNames  []string
 	indexName    string
 	unique       bool
+	buildStarted bool // CreateIndex was issued, so a failure may have left an invalid index
 	indexCreated bool
+
+	onProgress       func(progress *models.ActionProgress)
+	progressInterval time.Duration
 }
 
 func NewCreateIndexAction(metadata *models.ActionMetadata, adapter database.DatabaseAdapter, tableName string, columnNames []string, unique bool) *CreateIndexAction {
-	indexName := fmt.Sprintf("idx_%s_%s_%s", metadata.DatabaseID, tableName, columnNames[0])
+	indexName := fmt.Sprintf("idx_%s_%s_%s", metadata.DatabaseID, tableName, strings.Join(columnNames, "_"))
+	if namer, ok := adapter.(database.IndexNamer); ok {
+		indexName = namer.IndexName(tableName, columnNames)
+	}
 
 	return &CreateIndexAction{
 		metadata:    metadata,
@@ -29,6 +40,66 @@ func NewCreateIndexAction(metadata *models.ActionMetadata, adapter database.Data
 		columnNames: columnNames,
 		indexName:   indexName,
 		unique:      unique,
+
+		progressInterval: defaultProgressInterval,
+	}
+}
+
+// SetProgressFunc registers a callback for index build progress while Execute runs.
+func (a *CreateIndexAction) SetProgressFunc(fn func(progress *models.ActionProgress)) {
+	a.onProgress = fn
+}
+
+// SetProgressInterval overrides how often build progress is polled.
+func (a *CreateIndexAction) SetProgressInterval(interval time.Duration) {
+	a.progressInterval = interval
+}
+
+// startProgressPolling polls the adapter for build progress until the returned stop
+// function is called or ctx is cancelled. stop waits for the poller to exit, so no
+// progress is reported after it returns.
+func (a *CreateIndexAction) startProgressPolling(ctx context.Context) (stop func()) {
+	reporter, ok := a.adapter.(database.IndexProgressReporter)
+	if !ok || a.onProgress == nil || a.progressInterval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	exited := make(chan struct{})
+
+	go func() {
+		defer close(exited)
+
+		ticker := time.NewTicker(a.progressInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			case <-ticker.C:
+				progress, err := reporter.GetIndexProgress(ctx, a.tableName)
+				if err != nil || progress == nil {
+					continue
+				}
+
+				a.onProgress(&models.ActionProgress{
+					Phase:       progress.Phase,
+					BlocksDone:  progress.BlocksDone,
+					BlocksTotal: progress.BlocksTotal,
+					TuplesDone:  progress.TuplesDone,
+					TuplesTotal: progress.TuplesTotal,
+					UpdatedAt:   time.Now(),
+				})
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-exited
 	}
 }
 
@@ -50,7 +121,28 @@ func (a *CreateIndexAction) Validate(ctx context.Context) error {
 		return database.ErrActionNotSupported
 	}
 
-	exists, err := a.adapter.IndexExists(ctx, a.indexName)
+	// Column names come from query text parsing and may be wrong - check them against the schema
+	tableExists, err := a.adapter.TableExists(ctx, a.tableName)
+	if err != nil {
+		return fmt.Errorf("failed to check table existence: %w", err)
+	}
+
+	if !tableExists {
+		return fmt.Errorf("table '%s' does not exist", a.tableName)
+	}
+
+	for _, column := range a.columnNames {
+		columnExists, err := a.adapter.ColumnExists(ctx, a.tableName, column)
+		if err != nil {
+			return fmt.Errorf("failed to check column existence: %w", err)
+		}
+
+		if !columnExists {
+			return fmt.Errorf("column '%s' does not exist on table %s", column, a.tableName)
+		}
+	}
+
+	exists, err := a.indexExists(ctx, a.indexName)
 	if err != nil {
 		return fmt.Errorf("failed to check index existance: %w", err)
 	}
@@ -76,6 +168,7 @@ func (a *CreateIndexAction) Execute(ctx context.Context) (*models.ActionResult,
 			Status:          models.StatusFailed,
 			Message:         "Validation error",
 			Error:           err.Error(),
+			ErrorCode:       models.ClassifyValidationError(err),
 			CreatedAt:       a.metadata.CreatedAt,
 			Started:         &started,
 			Completed:       nil,
@@ -94,7 +187,10 @@ func (a *CreateIndexAction) Execute(ctx context.Context) (*models.ActionResult,
 		Concurrent:  caps.SupportsConcurrentIndexes,
 	}
 
+	a.buildStarted = true
+	stopProgress := a.startProgressPolling(ctx)
 	err := a.adapter.CreateIndex(ctx, params)
+	stopProgress()
 	if err != nil {
 		return &models.ActionResult{
 			ActionID:        a.metadata.ActionID,
@@ -103,6 +199,7 @@ func (a *CreateIndexAction) Execute(ctx context.Context) (*models.ActionResult,
 			Status:          models.StatusFailed,
 			Message:         "Index creation failed",
 			Error:           err.Error(),
+			ErrorCode:       models.ClassifyError(err),
 			CreatedAt:       a.metadata.CreatedAt,
 			Started:         &started,
 			ExecutionTimeMs: int64(time.Since(startTime).Milliseconds()),
@@ -113,6 +210,21 @@ func (a *CreateIndexAction) Execute(ctx context.Context) (*models.ActionResult,
 	a.indexCreated = true
 
 	completed := time.Now()
+	changes := map[string]interface{}{
+		"index_name":   a.indexName,
+		"table_name":   a.tableName,
+		"column_names": a.columnNames,
+		"unique":       a.unique,
+		"concurrent":   params.Concurrent,
+		"duration_ms":  completed.Sub(started).Milliseconds(),
+	}
+
+	if reporter, ok := a.adapter.(database.IndexProgressReporter); ok {
+		if size, err := reporter.GetIndexSize(ctx, a.indexName); err == nil {
+			changes["index_size_bytes"] = size
+		}
+	}
+
 	return &models.ActionResult{
 		ActionID:        a.metadata.ActionID,
 		ActionType:      a.metadata.ActionType,
@@ -123,15 +235,9 @@ func (a *CreateIndexAction) Execute(ctx context.Context) (*models.ActionResult,
 		Started:         &started,
 		Completed:       &completed,
 		ExecutionTimeMs: int64(time.Since(startTime).Milliseconds()),
-		Changes: map[string]interface{}{
-			"index_name":   a.indexName,
-			"table_name":   a.tableName,
-			"column_names": a.columnNames,
-			"unique":       a.unique,
-			"concurrent":   params.Concurrent,
-		},
-		CanRollback: true,
-		Rolledback:  false,
+		Changes:         changes,
+		CanRollback:     true,
+		Rolledback:      false,
 	}, nil
 }
 
@@ -140,7 +246,7 @@ func (a *CreateIndexAction) Rollback(ctx context.Context) error {
 		return nil
 	}
 
-	exists, err := a.adapter.IndexExists(ctx, a.indexName)
+	exists, err := a.indexExists(ctx, a.indexName)
 	if err != nil {
 		return fmt.Errorf("failed to check index: %w", err)
 	}
@@ -149,7 +255,7 @@ func (a *CreateIndexAction) Rollback(ctx context.Context) error {
 		return nil
 	}
 
-	err = a.adapter.DropIndex(ctx, a.indexName)
+	err = a.dropIndex(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to drop index: %w", err)
 	}
@@ -157,3 +263,88 @@ func (a *CreateIndexAction) Rollback(ctx context.Context) error {
 	a.indexCreated = false
 	return nil
 }
+
+// PrepareRetry drops what a failed build left behind. A concurrent build that is interrupted
+// leaves an INVALID index under the action's name, which would make the retry fail validation.
+func (a *CreateIndexAction) PrepareRetry(ctx context.Context) error {
+	if !a.buildStarted || a.indexCreated {
+		return nil
+	}
+
+	exists, err := a.indexExists(ctx, a.indexName)
+	if err != nil {
+		return fmt.Errorf("failed to check for a partly built index: %w", err)
+	}
+
+	if exists {
+		if err := a.dropIndex(ctx); err != nil {
+			return fmt.Errorf("failed to drop partly built index %s: %w", a.indexName, err)
+		}
+	}
+
+	a.buildStarted = false
+	return nil
+}
+
+// CheckRollback refuses to drop an index that has been scanned IndexScanThreshold times
+// since it was created: queries now rely on it and would fall back to sequential scans.
+// Adapters that don't track index usage are not checked.
+func (a *CreateIndexAction) CheckRollback(ctx context.Context, safety RollbackSafety) error {
+	reporter, ok := a.adapter.(database.IndexUsageReporter)
+	if !a.indexCreated || !ok || safety.IndexScanThreshold <= 0 {
+		return nil
+	}
+
+	scans, err := reporter.GetIndexScans(ctx, a.tableName, a.indexName)
+	if err != nil {
+		return fmt.Errorf("%w: cannot check whether index %s is in use: %v", ErrRollbackUnsafe, a.indexName, err)
+	}
+
+	if scans >= safety.IndexScanThreshold {
+		return fmt.Errorf("%w: index %s has been scanned %d times since it was created; queries rely on it and dropping it may slow them down",
+			ErrRollbackUnsafe, a.indexName, scans)
+	}
+
+	return nil
+}
+
+// Restore re-attaches to an index created before an Executor restart.
+func (a *CreateIndexAction) Restore(ctx context.Context, changes map[string]interface{}) (bool, error) {
+	indexName, ok := changes["index_name"].(string)
+	if !ok || indexName == "" {
+		return false, fmt.Errorf("recorded changes missing index_name")
+	}
+
+	if tableName, ok := changes["table_name"].(string); ok && tableName != "" {
+		a.tableName = tableName
+	}
+
+	exists, err := a.indexExists(ctx, indexName)
+	if err != nil {
+		return false, fmt.Errorf("failed to check index: %w", err)
+	}
+
+	if !exists {
+		return false, nil
+	}
+
+	a.indexName = indexName
+	a.indexCreated = true
+
+	return true, nil
+}
+
+// indexExists checks the action's table when index names are only unique per table.
+func (a *CreateIndexAction) indexExists(ctx context.Context, indexName string) (bool, error) {
+	if scoped, ok := a.adapter.(database.TableIndexManager); ok {
+		return scoped.IndexExistsOnTable(ctx, a.tableName, indexName)
+	}
+	return a.adapter.IndexExists(ctx, indexName)
+}
+
+func (a *CreateIndexAction) dropIndex(ctx context.Context) error {
+	if scoped, ok := a.adapter.(database.TableIndexManager); ok {
+		return scoped.DropIndexFromTable(ctx, a.tableName, a.indexName)
+	}
+	return a.adapter.DropIndex(ctx, a.indexName)
+}
@@ -2,21 +2,93 @@ package actions
 
 import (
 	"context"
-	"crypto/md5"
 	"fmt"
 	"log"
-	"os"
-	"path/filepath"
+	"log/slog"
+	"net"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/database"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/docker"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
 	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
 	dockertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/go-connections/nat"
+	"github.com/jackc/pgx/v5"
 )
 
+// pgbouncerContainerPort is the port PgBouncer listens on inside its container.
+const pgbouncerContainerPort = "6432/tcp"
+
+// pgbouncerConfigPath is where the config volume is mounted inside the container.
+const pgbouncerConfigPath = "/etc/pgbouncer"
+
+// maxPortProbe bounds how far past the preferred host port we look for a free one.
+const maxPortProbe = 100
+
+// PgBouncerSettings configures a PgBouncer deployment. Defaults come from Executor
+// config; detection ActionMetadata can override each field.
+type PgBouncerSettings struct {
+	HostPort        int
+	PoolMode        string
+	DefaultPoolSize int
+	MaxClientConn   int
+	ReservePoolSize int
+	Image           string
+}
+
+// DefaultPgBouncerSettings returns the settings used when nothing is configured.
+func DefaultPgBouncerSettings() PgBouncerSettings {
+	return PgBouncerSettings{
+		HostPort:        6432,
+		PoolMode:        "transaction",
+		DefaultPoolSize: 20,
+		MaxClientConn:   100,
+		ReservePoolSize: 5,
+		Image:           "pgbouncer/pgbouncer:latest",
+	}
+}
+
+// withOverrides applies ActionMetadata overrides on top of the settings.
+func (s PgBouncerSettings) withOverrides(params map[string]interface{}) PgBouncerSettings {
+	if port, ok := intFromParam(params["pgbouncer_port"]); ok && port > 0 {
+		s.HostPort = port
+	}
+	if mode, ok := params["pool_mode"].(string); ok && mode != "" {
+		s.PoolMode = mode
+	}
+	if size, ok := intFromParam(params["default_pool_size"]); ok && size > 0 {
+		s.DefaultPoolSize = size
+	}
+	if conns, ok := intFromParam(params["max_client_conn"]); ok && conns > 0 {
+		s.MaxClientConn = conns
+	}
+	if image, ok := params["image"].(string); ok && image != "" {
+		s.Image = image
+	}
+	return s
+}
+
+// intFromParam reads an integer from metadata, which decodes from JSON as float64.
+func intFromParam(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	case string:
+		n, err := strconv.Atoi(v)
+		return n, err == nil
+	}
+	return 0, false
+}
+
 type DeployPgBouncerAction struct {
 	actionID      string
 	detectionID   string
@@ -24,10 +96,32 @@ type DeployPgBouncerAction struct {
 	databaseType  string
 	containerName string
 	containerID   string
+	configVolume  string // named volume holding userlist.txt
+	hostPort      int
 	deployed      bool
 
+	authType    string // PGBOUNCER_AUTH_TYPE used for the deployment
+	authSource  string // pg_authid or generated
+	networkMode string // how PgBouncer reaches the database: host, docker_network or remote
+	network     string // Docker network PgBouncer joined (docker_network mode only)
+
+	settings PgBouncerSettings
+
+	// Opens the monitored database to inspect how its roles authenticate
+	connectDatabase func(ctx context.Context, connectionString string) (database.DatabaseAdapter, error)
+
+	// Readiness probe run through the deployed PgBouncer before reporting success
+	newProbe      func(connectionString string) docker.ReadinessProbe
+	probeSettings docker.ProbeSettings
+
+	// Counts the clients connected through PgBouncer before a rollback removes it
+	countClients func(ctx context.Context, connectionString string) (int, error)
+
+	// Memory and CPU caps for the container
+	limits docker.ResourceLimits
+
 	// Docker client
-	dockerClient *docker.Client
+	dockerClient docker.Runtime
 
 	// Knowledge client for fetching connection string
 	knowledgeClient pb.KnowledgeServiceClient
@@ -36,26 +130,165 @@ type DeployPgBouncerAction struct {
 	deploymentDetails map[string]interface{}
 }
 
-func NewDeployPgBouncerAction(actionID string, detectionID, databaseID, databaseType string, knowledgeClient pb.KnowledgeServiceClient, params map[string]interface{}) (*DeployPgBouncerAction, error) {
-	containerName := fmt.Sprintf("pgbouncer-%s", databaseID)
-
+func NewDeployPgBouncerAction(actionID string, detectionID, databaseID, databaseType string, knowledgeClient pb.KnowledgeServiceClient, defaults PgBouncerSettings, params map[string]interface{}) (*DeployPgBouncerAction, error) {
 	// Create Docker client
 	dockerClient, err := docker.NewClient()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Docker client: %w", err)
 	}
 
+	return NewDeployPgBouncerActionWithRuntime(actionID, detectionID, databaseID, databaseType, dockerClient, knowledgeClient, defaults, params), nil
+}
+
+// NewDeployPgBouncerActionWithRuntime creates the action with a caller-supplied container runtime.
+func NewDeployPgBouncerActionWithRuntime(actionID string, detectionID, databaseID, databaseType string, dockerClient docker.Runtime, knowledgeClient pb.KnowledgeServiceClient, defaults PgBouncerSettings, params map[string]interface{}) *DeployPgBouncerAction {
+	containerName := fmt.Sprintf("pgbouncer-%s", databaseID)
+
 	return &DeployPgBouncerAction{
-		actionID:          actionID,
-		detectionID:       detectionID,
-		databaseID:        databaseID,
-		databaseType:      databaseType,
-		containerName:     containerName,
+		actionID:      actionID,
+		detectionID:   detectionID,
+		databaseID:    databaseID,
+		databaseType:  databaseType,
+		containerName: containerName,
+		configVolume:  fmt.Sprintf("pgbouncer-config-%s", databaseID),
+		settings:      defaults.withOverrides(params),
+		connectDatabase: func(ctx context.Context, connectionString string) (database.DatabaseAdapter, error) {
+			return database.NewAdapter(ctx, "postgres", connectionString, databaseID)
+		},
+		newProbe:          docker.PgBouncerProbe,
+		probeSettings:     docker.DefaultProbeSettings(),
+		countClients:      docker.PgBouncerClientCount,
 		dockerClient:      dockerClient,
 		knowledgeClient:   knowledgeClient,
 		deployed:          false,
 		deploymentDetails: make(map[string]interface{}),
-	}, nil
+	}
+}
+
+// Deployment contexts for reaching the database from the PgBouncer container.
+const (
+	networkModeHost   = "host"           // database on the Docker host, reached via host.docker.internal
+	networkModeDocker = "docker_network" // database is a container on a network PgBouncer joins
+	networkModeRemote = "remote"         // database elsewhere, host used unchanged
+)
+
+type databaseTarget struct {
+	mode    string
+	host    string
+	network string
+}
+
+// Knowledge database metadata the Collector records about where the database runs.
+const (
+	metadataDeploymentContext = "deployment_context"
+	metadataDockerContainer   = "docker_container"
+)
+
+// resolveDatabaseHost decides how PgBouncer should address the database host. The
+// deployment context the Collector registered is used when present; otherwise only
+// genuine localhost is rewritten to host.docker.internal.
+func (a *DeployPgBouncerAction) resolveDatabaseHost(ctx context.Context, host string, metadata map[string]string) (databaseTarget, error) {
+	switch metadata[metadataDeploymentContext] {
+	case "local":
+		return databaseTarget{mode: networkModeHost, host: "host.docker.internal"}, nil
+	case "remote", "cloud":
+		return databaseTarget{mode: networkModeRemote, host: host}, nil
+	case "docker":
+		// The container name is exact; the connection string host may only be an alias
+		if container := metadata[metadataDockerContainer]; container != "" {
+			return a.findDatabaseContainer(ctx, container, host)
+		}
+		return a.findDatabaseContainer(ctx, host, host)
+	}
+
+	if host == "localhost" || host == "127.0.0.1" || host == "::1" {
+		return databaseTarget{mode: networkModeHost, host: "host.docker.internal"}, nil
+	}
+
+	return a.findDatabaseContainer(ctx, host, host)
+}
+
+// findDatabaseContainer looks up the container name refers to. If there is none, the
+// database is treated as remote and addressed by host.
+func (a *DeployPgBouncerAction) findDatabaseContainer(ctx context.Context, name, host string) (databaseTarget, error) {
+	endpoint, err := a.dockerClient.FindContainer(ctx, name)
+	if err != nil {
+		return databaseTarget{}, fmt.Errorf("failed to look up database container: %w", err)
+	}
+
+	if endpoint == nil {
+		return databaseTarget{mode: networkModeRemote, host: host}, nil
+	}
+
+	// The default bridge has no name resolution - address the database by IP
+	if endpoint.IsDefaultBridge() {
+		return databaseTarget{mode: networkModeDocker, host: endpoint.IPAddress, network: endpoint.Network}, nil
+	}
+
+	return databaseTarget{mode: networkModeDocker, host: endpoint.Name, network: endpoint.Network}, nil
+}
+
+// SetDatabaseConnector overrides how the action connects to the monitored database
+// to inspect password storage.
+func (a *DeployPgBouncerAction) SetDatabaseConnector(connect func(ctx context.Context, connectionString string) (database.DatabaseAdapter, error)) {
+	a.connectDatabase = connect
+}
+
+// SetReadinessProbe overrides the probe run through PgBouncer after it starts.
+func (a *DeployPgBouncerAction) SetReadinessProbe(newProbe func(connectionString string) docker.ReadinessProbe) {
+	a.newProbe = newProbe
+}
+
+// SetClientCounter overrides how connected clients are counted before a rollback.
+func (a *DeployPgBouncerAction) SetClientCounter(count func(ctx context.Context, connectionString string) (int, error)) {
+	a.countClients = count
+}
+
+// SetProbeSettings configures where and for how long the readiness probe runs.
+func (a *DeployPgBouncerAction) SetProbeSettings(settings docker.ProbeSettings) {
+	a.probeSettings = settings
+}
+
+// SetResourceLimits caps the memory and CPU of the deployed container.
+func (a *DeployPgBouncerAction) SetResourceLimits(limits docker.ResourceLimits) {
+	a.limits = limits
+}
+
+// resolveAuth inspects the database to decide how PgBouncer authenticates the user.
+func (a *DeployPgBouncerAction) resolveAuth(ctx context.Context, connectionString, user, password string) (pgbouncerAuth, error) {
+	var inspector database.PasswordAuthInspector
+
+	if a.connectDatabase != nil {
+		adapter, err := a.connectDatabase(ctx, connectionString)
+		if err != nil {
+			slog.Warn("Could not connect to database to inspect password storage", "error", err)
+		} else {
+			defer adapter.Close()
+			inspector, _ = adapter.(database.PasswordAuthInspector)
+		}
+	}
+
+	return resolvePgBouncerAuth(ctx, inspector, user, password)
+}
+
+// selectHostPort returns the configured host port, or the next one not already bound
+// by another container.
+func (a *DeployPgBouncerAction) selectHostPort(ctx context.Context) (int, error) {
+	used, err := a.dockerClient.UsedHostPorts(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check host ports in use: %w", err)
+	}
+
+	for port := a.settings.HostPort; port < a.settings.HostPort+maxPortProbe; port++ {
+		if !used[port] {
+			if port != a.settings.HostPort {
+				log.Printf("Host port %d is in use, using %d for PgBouncer", a.settings.HostPort, port)
+			}
+			return port, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no free host port in range %d-%d", a.settings.HostPort, a.settings.HostPort+maxPortProbe-1)
 }
 
 func (a *DeployPgBouncerAction) Execute(ctx context.Context) (*models.ActionResult, error) {
@@ -67,6 +300,8 @@ func (a *DeployPgBouncerAction) Execute(ctx context.Context) (*models.ActionResu
 
 	var containerID string
 	var message string
+	var probeConnStr string
+	dbPort := "5432"
 
 	// Check if container already exists
 	exists, existingID, err := a.dockerClient.ContainerExists(ctx, a.containerName)
@@ -75,6 +310,14 @@ func (a *DeployPgBouncerAction) Execute(ctx context.Context) (*models.ActionResu
 	}
 
 	if exists {
+		// Report the port the existing container was actually given
+		hostPort, err := a.dockerClient.HostPort(ctx, existingID, pgbouncerContainerPort)
+		if err != nil {
+			slog.Warn("Could not read PgBouncer host port, assuming the configured port", "host_port", a.settings.HostPort, "error", err)
+			hostPort = a.settings.HostPort
+		}
+		a.hostPort = hostPort
+
 		// Container exists - check if it's running
 		isRunning, err := a.dockerClient.IsContainerRunning(ctx, existingID)
 		if err != nil {
@@ -82,7 +325,7 @@ func (a *DeployPgBouncerAction) Execute(ctx context.Context) (*models.ActionResu
 		}
 
 		if isRunning {
-			log.Printf("PgBouncer is already running on port 6432")
+			log.Printf("PgBouncer is already running on port %d", a.hostPort)
 
 			endTime := time.Now()
 			return &models.ActionResult{
@@ -99,8 +342,9 @@ func (a *DeployPgBouncerAction) Execute(ctx context.Context) (*models.ActionResu
 				Changes: map[string]interface{}{
 					"container_name": a.containerName,
 					"container_id":   existingID,
-					"pgbouncer_port": 6432,
-					"instruction":    "PgBouncer already deployed",
+					"pgbouncer_port": a.hostPort,
+					"config_volume":  a.configVolume,
+					"instruction":    fmt.Sprintf("PgBouncer already deployed on port %d", a.hostPort),
 				},
 				CanRollback: true,
 				Rolledback:  false,
@@ -116,15 +360,22 @@ func (a *DeployPgBouncerAction) Execute(ctx context.Context) (*models.ActionResu
 
 		containerID = existingID
 		a.containerID = existingID
-		message = fmt.Sprintf("PgBouncer container '%s' restarted on port 6432", a.containerName)
+		message = fmt.Sprintf("PgBouncer container '%s' restarted on port %d", a.containerName, a.hostPort)
 
 	} else {
 		// Container doesn't exist - create new one
 		log.Printf("Deploying new PgBouncer container...")
 
+		// Pick a free host port before creating anything - a bind failure only shows up at start
+		hostPort, err := a.selectHostPort(ctx)
+		if err != nil {
+			return nil, err
+		}
+		a.hostPort = hostPort
+
 		// Fetch database connection string from Knowledge
 		log.Printf("Fetching database connection info from Knowledge...")
-		dbCtx, dbCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		dbCtx, dbCancel := context.WithTimeout(ctx, 5*time.Second)
 		defer dbCancel()
 
 		dbResp, err := a.knowledgeClient.GetDatabase(dbCtx, &pb.GetDatabaseRequest{
@@ -166,65 +417,81 @@ func (a *DeployPgBouncerAction) Execute(ctx context.Context) (*models.ActionResu
 			return nil, fmt.Errorf("invalid connection string format")
 		}
 
-		hostPort := strings.Split(hostDB[0], ":")
-		if len(hostPort) != 2 {
+		hostPortParts := strings.Split(hostDB[0], ":")
+		if len(hostPortParts) != 2 {
 			return nil, fmt.Errorf("invalid connection string format")
 		}
 
-		host := hostPort[0]
-		port := hostPort[1]
+		host := hostPortParts[0]
+		dbPort = hostPortParts[1]
 		dbname := hostDB[1]
 
-		// TODO: This assumes database is on the host machine (local development)
-		// In production, the database might be:
-		// - Remote server (use actual hostname/IP from connection string)
-		// - Same Docker network (use container name)
-		// - Cloud service (use cloud endpoint)
-		// Need to detect deployment context and handle accordingly
-		if host == "localhost" || host == "127.0.0.1" {
-			host = "host.docker.internal"
-			log.Printf("Replaced localhost with host.docker.internal for Docker networking")
+		// Work out how the PgBouncer container can reach the database
+		target, err := a.resolveDatabaseHost(ctx, host, dbResp.Metadata)
+		if err != nil {
+			return nil, err
 		}
+		host = target.host
+		a.networkMode = target.mode
+		a.network = target.network
+		log.Printf("Database reachable via %s mode (host: %s)", target.mode, host)
 
-		// Generate userlist.txt file for authentication
-		configDir, err := generateUserlistFile(user, password)
+		// Match the server's password storage - an md5 userlist fails against SCRAM roles
+		auth, err := a.resolveAuth(ctx, dbResp.ConnectionString, user, password)
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate userlist.txt: %w", err)
+			return nil, fmt.Errorf("failed to prepare PgBouncer authentication: %w", err)
 		}
-
-		defaultPoolSize := 20
-		maxClientConn := 100
-		reservePoolSize := 5
+		a.authType = auth.authType
+		a.authSource = auth.source
+		log.Printf("PgBouncer auth type: %s (secret from %s)", auth.authType, auth.source)
+
+		// Probe as the application would connect, proving auth works end to end
+		probeURL := &url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(user, password),
+			Host:     net.JoinHostPort(a.probeSettings.Host, strconv.Itoa(a.hostPort)),
+			Path:     "/" + dbname,
+			RawQuery: "sslmode=disable",
+		}
+		probeConnStr = probeURL.String()
 
 		// Pull image
-		log.Printf("Pulling PgBouncer image...")
-		if err := a.dockerClient.PullImage(ctx, "pgbouncer/pgbouncer:latest"); err != nil {
+		log.Printf("Pulling PgBouncer image %s...", a.settings.Image)
+		if err := a.dockerClient.PullImage(ctx, a.settings.Image); err != nil {
 			return nil, fmt.Errorf("failed to pull PgBouncer image: %w", err)
 		}
 
+		// userlist.txt lives in a named volume rather than a host bind mount - a host path
+		// only exists when the Executor shares the Docker host's filesystem
+		if err := a.dockerClient.CreateVolume(ctx, a.configVolume); err != nil {
+			return nil, err
+		}
+
 		// Create container
 		log.Printf("Creating PgBouncer container: %s", a.containerName)
 
 		portBindings := nat.PortMap{
-			"6432/tcp": []nat.PortBinding{
+			pgbouncerContainerPort: []nat.PortBinding{
 				{
 					HostIP:   "0.0.0.0",
-					HostPort: "6432",
+					HostPort: strconv.Itoa(a.hostPort),
 				},
 			},
 		}
 
 		env := []string{
 			fmt.Sprintf("DATABASES_HOST=%s", host),
-			fmt.Sprintf("DATABASES_PORT=%s", port),
+			fmt.Sprintf("DATABASES_PORT=%s", dbPort),
 			fmt.Sprintf("DATABASES_USER=%s", user),
 			fmt.Sprintf("DATABASES_DBNAME=%s", dbname),
-			"PGBOUNCER_POOL_MODE=transaction",
-			fmt.Sprintf("PGBOUNCER_DEFAULT_POOL_SIZE=%d", defaultPoolSize),
-			fmt.Sprintf("PGBOUNCER_MAX_CLIENT_CONN=%d", maxClientConn),
-			fmt.Sprintf("PGBOUNCER_RESERVE_POOL_SIZE=%d", reservePoolSize),
-			"PGBOUNCER_AUTH_TYPE=md5",
-			"PGBOUNCER_AUTH_FILE=/etc/pgbouncer/userlist.txt",
+			fmt.Sprintf("PGBOUNCER_POOL_MODE=%s", a.settings.PoolMode),
+			fmt.Sprintf("PGBOUNCER_DEFAULT_POOL_SIZE=%d", a.settings.DefaultPoolSize),
+			fmt.Sprintf("PGBOUNCER_MAX_CLIENT_CONN=%d", a.settings.MaxClientConn),
+			fmt.Sprintf("PGBOUNCER_RESERVE_POOL_SIZE=%d", a.settings.ReservePoolSize),
+			fmt.Sprintf("PGBOUNCER_AUTH_TYPE=%s", auth.authType),
+			fmt.Sprintf("PGBOUNCER_AUTH_FILE=%s/userlist.txt", pgbouncerConfigPath),
+			// Lets the same user run SHOW CLIENTS before a rollback removes the pooler
+			fmt.Sprintf("PGBOUNCER_STATS_USERS=%s", user),
 		}
 
 		if password != "" {
@@ -232,25 +499,41 @@ func (a *DeployPgBouncerAction) Execute(ctx context.Context) (*models.ActionResu
 		}
 
 		containerConfig := &dockertypes.Config{
-			Image: "pgbouncer/pgbouncer:latest",
+			Image: a.settings.Image,
 			Env:   env,
 			ExposedPorts: nat.PortSet{
-				"6432/tcp": struct{}{},
+				pgbouncerContainerPort: struct{}{},
 			},
 		}
 
 		hostConfig := &dockertypes.HostConfig{
 			PortBindings: portBindings,
+			Resources:    a.limits.Resources(),
 			RestartPolicy: dockertypes.RestartPolicy{
 				Name: "unless-stopped",
 			},
-			Binds: []string{
-				fmt.Sprintf("%s:/etc/pgbouncer", configDir),
+			Mounts: []mount.Mount{
+				{
+					Type:   mount.TypeVolume,
+					Source: a.configVolume,
+					Target: pgbouncerConfigPath,
+				},
 			},
 		}
 
+		switch target.mode {
+		case networkModeDocker:
+			hostConfig.NetworkMode = dockertypes.NetworkMode(target.network)
+		case networkModeHost:
+			// Docker Desktop provides host.docker.internal; on Linux it must be mapped explicitly
+			hostConfig.ExtraHosts = []string{"host.docker.internal:host-gateway"}
+		}
+
 		newContainerID, err := a.dockerClient.CreateContainer(ctx, containerConfig, hostConfig, a.containerName)
 		if err != nil {
+			cleanupCtx, cancel := cleanupContext(ctx)
+			defer cancel()
+			a.removeConfigVolume(cleanupCtx)
 			return nil, fmt.Errorf("failed to create container: %w", err)
 		}
 
@@ -258,20 +541,28 @@ func (a *DeployPgBouncerAction) Execute(ctx context.Context) (*models.ActionResu
 		a.containerID = containerID
 		log.Printf("Container created: %s", containerID[:12])
 
+		// Write userlist.txt into the volume before PgBouncer starts and reads it
+		if err := a.dockerClient.CopyFileToContainer(ctx, containerID, pgbouncerConfigPath, "userlist.txt", userlistContent(user, auth.secret), 0644); err != nil {
+			cleanupCtx, cancel := cleanupContext(ctx)
+			defer cancel()
+			a.dockerClient.RemoveContainer(cleanupCtx, containerID)
+			a.removeConfigVolume(cleanupCtx)
+			return nil, fmt.Errorf("failed to write userlist.txt: %w", err)
+		}
+
 		// Start container
 		log.Printf("Starting PgBouncer container...")
 		if err := a.dockerClient.StartContainer(ctx, containerID); err != nil {
-			a.dockerClient.RemoveContainer(ctx, containerID)
+			cleanupCtx, cancel := cleanupContext(ctx)
+			defer cancel()
+			a.dockerClient.RemoveContainer(cleanupCtx, containerID)
+			a.removeConfigVolume(cleanupCtx)
 			return nil, fmt.Errorf("failed to start container: %w", err)
 		}
 
-		message = fmt.Sprintf("PgBouncer deployed as container '%s' on port 6432", a.containerName)
+		message = fmt.Sprintf("PgBouncer deployed as container '%s' on port %d", a.containerName, a.hostPort)
 	}
 
-	// Verify running
-	log.Printf("Verifying PgBouncer is running...")
-	time.Sleep(3 * time.Second)
-
 	isRunning, err := a.dockerClient.IsContainerRunning(ctx, containerID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check container status: %w", err)
@@ -281,7 +572,51 @@ func (a *DeployPgBouncerAction) Execute(ctx context.Context) (*models.ActionResu
 		return nil, fmt.Errorf("container started but is not running - check logs with: docker logs %s", a.containerName)
 	}
 
-	log.Printf("PgBouncer is running on port 6432")
+	// A running container can still be crash-looping or rejecting logins - wait until it answers.
+	// A restarted container has no credentials to hand, so only its port is checked.
+	var probe docker.ReadinessProbe
+	if probeConnStr != "" {
+		probe = a.newProbe(probeConnStr)
+	} else {
+		probe = docker.TCPProbe(net.JoinHostPort(a.probeSettings.Host, strconv.Itoa(a.hostPort)))
+	}
+
+	log.Printf("Waiting for PgBouncer to accept connections on port %d...", a.hostPort)
+	probeResult := docker.WaitForReady(ctx, probe, a.probeSettings.Timeout)
+
+	if !probeResult.Passed {
+		slog.Warn("PgBouncer readiness probe failed", "attempts", probeResult.Attempts, "error", probeResult.LastError)
+
+		a.deployed = true
+		cleanupCtx, cancel := cleanupContext(ctx)
+		defer cancel()
+		if err := a.Rollback(cleanupCtx); err != nil {
+			slog.Warn("Failed to remove PgBouncer after failed readiness probe", "error", err)
+		}
+
+		endTime := time.Now()
+		return &models.ActionResult{
+			ActionID:        a.actionID,
+			DetectionID:     a.detectionID,
+			ActionType:      "deploy_pgbouncer",
+			DatabaseID:      a.databaseID,
+			Status:          models.StatusFailed,
+			Message:         "PgBouncer did not become ready and was removed",
+			Error:           fmt.Sprintf("readiness probe on port %d failed: %s", a.hostPort, probeResult.LastError),
+			ErrorCode:       models.ErrorCodeTimeout, // Removed again, so a retry starts from scratch
+			CreatedAt:       startTime,
+			Started:         &startTime,
+			Completed:       &endTime,
+			ExecutionTimeMs: endTime.Sub(startTime).Milliseconds(),
+			Changes: map[string]interface{}{
+				"container_name":  a.containerName,
+				"pgbouncer_port":  a.hostPort,
+				"readiness_probe": probeResult.Changes(),
+			},
+		}, nil
+	}
+
+	log.Printf("PgBouncer is ready on port %d (%s)", a.hostPort, probeResult.Detail)
 
 	endTime := time.Now()
 	executionTimeMs := endTime.Sub(startTime).Milliseconds()
@@ -300,10 +635,19 @@ func (a *DeployPgBouncerAction) Execute(ctx context.Context) (*models.ActionResu
 		Changes: map[string]interface{}{
 			"container_name":  a.containerName,
 			"container_id":    containerID,
-			"pgbouncer_port":  6432,
-			"pool_size":       20,
-			"max_client_conn": 100,
-			"instruction":     "Update your app's DB_CONNECTION_STRING to use port 6432 instead of 5432",
+			"config_volume":   a.configVolume,
+			"pgbouncer_port":  a.hostPort,
+			"pool_mode":       a.settings.PoolMode,
+			"pool_size":       a.settings.DefaultPoolSize,
+			"max_client_conn": a.settings.MaxClientConn,
+			"image":           a.settings.Image,
+			"auth_type":       a.authType,
+			"auth_source":     a.authSource,
+			"readiness_probe": probeResult.Changes(),
+			"resource_limits": a.limits.Changes(),
+			"network_mode":    a.networkMode,
+			"network":         a.network,
+			"instruction":     fmt.Sprintf("Update your app's DB_CONNECTION_STRING to use port %d instead of %s", a.hostPort, dbPort),
 		},
 		CanRollback: true,
 		Rolledback:  false,
@@ -324,7 +668,7 @@ func (a *DeployPgBouncerAction) Rollback(ctx context.Context) error {
 
 	// Stop the container
 	if err := a.dockerClient.StopContainer(ctx, a.containerID); err != nil {
-		log.Printf("Warning: failed to stop container: %v", err)
+		slog.Warn("Failed to stop container", "error", err)
 	}
 
 	// Remove the container
@@ -334,10 +678,92 @@ func (a *DeployPgBouncerAction) Rollback(ctx context.Context) error {
 
 	log.Printf("PgBouncer container removed: %s", a.containerName)
 
+	a.removeConfigVolume(ctx)
+
 	a.deployed = false
 	return nil
 }
 
+// removeConfigVolume deletes the volume holding userlist.txt. The container using it must
+// already be removed.
+func (a *DeployPgBouncerAction) removeConfigVolume(ctx context.Context) {
+	if a.configVolume == "" {
+		return
+	}
+	if err := a.dockerClient.RemoveVolume(ctx, a.configVolume); err != nil {
+		slog.Warn("Failed to remove PgBouncer config volume", "config_volume", a.configVolume, "error", err)
+	}
+}
+
+// CheckRollback refuses to remove PgBouncer while clients are connected through it: the
+// application has been pointed at the pooler. The admin console is reached with the
+// database's credentials from Knowledge.
+func (a *DeployPgBouncerAction) CheckRollback(ctx context.Context, safety RollbackSafety) error {
+	if !a.deployed {
+		return nil
+	}
+
+	dbResp, err := a.knowledgeClient.GetDatabase(ctx, &pb.GetDatabaseRequest{DatabaseId: a.databaseID})
+	if err != nil {
+		return fmt.Errorf("%w: cannot fetch credentials to check PgBouncer clients: %v", ErrRollbackUnsafe, err)
+	}
+	if !dbResp.Found {
+		return fmt.Errorf("%w: database %s not found in Knowledge, cannot check PgBouncer clients", ErrRollbackUnsafe, a.databaseID)
+	}
+
+	config, err := pgx.ParseConfig(dbResp.ConnectionString)
+	if err != nil {
+		return fmt.Errorf("%w: cannot read database credentials: %v", ErrRollbackUnsafe, err)
+	}
+
+	adminURL := &url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(config.User, config.Password),
+		Host:     net.JoinHostPort(a.probeSettings.Host, strconv.Itoa(a.hostPort)),
+		Path:     "/pgbouncer",
+		RawQuery: "sslmode=disable",
+	}
+
+	clients, err := a.countClients(ctx, adminURL.String())
+	if err != nil {
+		return fmt.Errorf("%w: cannot check for clients connected to PgBouncer on port %d: %v", ErrRollbackUnsafe, a.hostPort, err)
+	}
+
+	if clients > 0 {
+		return fmt.Errorf("%w: %d clients are connected through PgBouncer container %s; removing it would cut them off",
+			ErrRollbackUnsafe, clients, a.containerName)
+	}
+
+	return nil
+}
+
+// Restore re-attaches to a container deployed before an Executor restart.
+func (a *DeployPgBouncerAction) Restore(ctx context.Context, changes map[string]interface{}) (bool, error) {
+	if name, ok := changes["container_name"].(string); ok && name != "" {
+		a.containerName = name
+	}
+	if volume, ok := changes["config_volume"].(string); ok && volume != "" {
+		a.configVolume = volume
+	}
+	if port, ok := intFromParam(changes["pgbouncer_port"]); ok {
+		a.hostPort = port
+	}
+
+	exists, containerID, err := a.dockerClient.ContainerExists(ctx, a.containerName)
+	if err != nil {
+		return false, fmt.Errorf("failed to check container: %w", err)
+	}
+
+	if !exists {
+		return false, nil
+	}
+
+	a.containerID = containerID
+	a.deployed = true
+
+	return true, nil
+}
+
 func (a *DeployPgBouncerAction) Validate(ctx context.Context) error {
 	// Check Docker is available
 	if err := a.dockerClient.IsAvailable(ctx); err != nil {
@@ -368,28 +794,3 @@ func (a *DeployPgBouncerAction) GetMetadata() *models.ActionMetadata {
 		CreatedAt:    time.Now(),
 	}
 }
-
-// generateUserlistFile creates a userlist.txt file for PgBouncer authentication
-func generateUserlistFile(user, password string) (string, error) {
-	// Create temp directory for PgBouncer config
-	configDir := "/tmp/pgbouncer-config"
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create config directory: %w", err)
-	}
-
-	userlistPath := filepath.Join(configDir, "userlist.txt")
-
-	// Generate MD5 hash: "md5" + md5(password + username)
-	hash := md5.Sum([]byte(password + user))
-	md5Hash := fmt.Sprintf("md5%x", hash)
-
-	// Write userlist.txt in format: "username" "md5hash"
-	content := fmt.Sprintf("\"%s\" \"%s\"\n", user, md5Hash)
-
-	if err := os.WriteFile(userlistPath, []byte(content), 0644); err != nil {
-		return "", fmt.Errorf("failed to write userlist.txt: %w", err)
-	}
-
-	log.Printf("Generated userlist.txt at: %s", userlistPath)
-	return configDir, nil
-}
@@ -4,14 +4,21 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
+	"net"
+	"strconv"
 	"time"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/docker"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
 	dockertypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
 )
 
+// redisImage is the image deployed for the cache layer.
+const redisImage = "redis:7-alpine"
+
 // DeployRedisAction deploys a Redis container for application-level caching.
 // This is an ADVANCED action that requires the user to modify their application code.
 type DeployRedisAction struct {
@@ -23,7 +30,17 @@ type DeployRedisAction struct {
 	deployed      bool
 
 	// Docker client
-	dockerClient *docker.Client
+	dockerClient docker.Runtime
+
+	// Readiness probe run against the published port before reporting success
+	newProbe      func(addr string) docker.ReadinessProbe
+	probeSettings docker.ProbeSettings
+
+	// Counts the clients connected to Redis before a rollback removes it
+	countClients func(ctx context.Context, addr string) (int, error)
+
+	// Memory and CPU caps for the container
+	limits docker.ResourceLimits
 
 	// Redis configuration
 	port           string
@@ -41,28 +58,26 @@ func NewDeployRedisAction(
 	databaseID string,
 	params map[string]interface{},
 ) (*DeployRedisAction, error) {
-	containerName := fmt.Sprintf("redis-%s", databaseID)
-
 	// Create Docker client
 	dockerClient, err := docker.NewClient()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Docker client: %w", err)
 	}
 
-	// Parse configuration from params (with defaults)
-	port := "6379"
-	maxMemory := "256mb"
-	evictionPolicy := "allkeys-lru"
+	return NewDeployRedisActionWithRuntime(actionID, detectionID, databaseID, dockerClient, params), nil
+}
 
-	if p, ok := params["port"].(string); ok && p != "" {
-		port = p
-	}
-	if m, ok := params["max_memory"].(string); ok && m != "" {
-		maxMemory = m
-	}
-	if e, ok := params["eviction_policy"].(string); ok && e != "" {
-		evictionPolicy = e
-	}
+// NewDeployRedisActionWithRuntime creates the action with a caller-supplied container runtime.
+func NewDeployRedisActionWithRuntime(
+	actionID string,
+	detectionID string,
+	databaseID string,
+	dockerClient docker.Runtime,
+	params map[string]interface{},
+) *DeployRedisAction {
+	containerName := fmt.Sprintf("redis-%s", databaseID)
+
+	port, maxMemory, evictionPolicy := redisSettings(params)
 
 	return &DeployRedisAction{
 		actionID:          actionID,
@@ -73,9 +88,32 @@ func NewDeployRedisAction(
 		port:              port,
 		maxMemory:         maxMemory,
 		evictionPolicy:    evictionPolicy,
+		newProbe:          docker.RedisPingProbe,
+		probeSettings:     docker.DefaultProbeSettings(),
+		countClients:      docker.RedisClientCount,
 		deployed:          false,
 		deploymentDetails: make(map[string]interface{}),
-	}, nil
+	}
+}
+
+// SetReadinessProbe overrides the probe run against Redis after it starts.
+func (a *DeployRedisAction) SetReadinessProbe(newProbe func(addr string) docker.ReadinessProbe) {
+	a.newProbe = newProbe
+}
+
+// SetClientCounter overrides how connected clients are counted before a rollback.
+func (a *DeployRedisAction) SetClientCounter(count func(ctx context.Context, addr string) (int, error)) {
+	a.countClients = count
+}
+
+// SetProbeSettings configures where and for how long the readiness probe runs.
+func (a *DeployRedisAction) SetProbeSettings(settings docker.ProbeSettings) {
+	a.probeSettings = settings
+}
+
+// SetResourceLimits caps the memory and CPU of the deployed container.
+func (a *DeployRedisAction) SetResourceLimits(limits docker.ResourceLimits) {
+	a.limits = limits
 }
 
 func (a *DeployRedisAction) Execute(ctx context.Context) (*models.ActionResult, error) {
@@ -145,7 +183,7 @@ func (a *DeployRedisAction) Execute(ctx context.Context) (*models.ActionResult,
 
 		// Pull Redis image
 		log.Printf("Pulling Redis image...")
-		if err := a.dockerClient.PullImage(ctx, "redis:7-alpine"); err != nil {
+		if err := a.dockerClient.PullImage(ctx, redisImage); err != nil {
 			return nil, fmt.Errorf("failed to pull Redis image: %w", err)
 		}
 
@@ -170,15 +208,17 @@ func (a *DeployRedisAction) Execute(ctx context.Context) (*models.ActionResult,
 		}
 
 		containerConfig := &dockertypes.Config{
-			Image: "redis:7-alpine",
+			Image: redisImage,
 			Cmd:   cmd,
 			ExposedPorts: nat.PortSet{
 				"6379/tcp": struct{}{},
 			},
 		}
 
+		// maxmemory bounds the dataset, not fragmentation; the container limit bounds both
 		hostConfig := &dockertypes.HostConfig{
 			PortBindings: portBindings,
+			Resources:    a.limits.Resources(),
 			RestartPolicy: dockertypes.RestartPolicy{
 				Name: "unless-stopped",
 			},
@@ -196,17 +236,15 @@ func (a *DeployRedisAction) Execute(ctx context.Context) (*models.ActionResult,
 		// Start container
 		log.Printf("Starting Redis container...")
 		if err := a.dockerClient.StartContainer(ctx, containerID); err != nil {
-			a.dockerClient.RemoveContainer(ctx, containerID)
+			cleanupCtx, cancel := cleanupContext(ctx)
+			defer cancel()
+			a.dockerClient.RemoveContainer(cleanupCtx, containerID)
 			return nil, fmt.Errorf("failed to start container: %w", err)
 		}
 
 		message = fmt.Sprintf("Redis deployed as container '%s' on port %s", a.containerName, a.port)
 	}
 
-	// Verify running
-	log.Printf("Verifying Redis is running...")
-	time.Sleep(2 * time.Second)
-
 	isRunning, err := a.dockerClient.IsContainerRunning(ctx, containerID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check container status: %w", err)
@@ -216,7 +254,43 @@ func (a *DeployRedisAction) Execute(ctx context.Context) (*models.ActionResult,
 		return nil, fmt.Errorf("container started but is not running - check logs with: docker logs %s", a.containerName)
 	}
 
-	log.Printf("Redis is running on port %s", a.port)
+	// A running container can still be crash-looping on bad config - wait until Redis answers PING
+	log.Printf("Waiting for Redis to answer on port %s...", a.port)
+	probeResult := docker.WaitForReady(ctx, a.newProbe(net.JoinHostPort(a.probeSettings.Host, a.port)), a.probeSettings.Timeout)
+
+	if !probeResult.Passed {
+		slog.Warn("Redis readiness probe failed", "attempts", probeResult.Attempts, "error", probeResult.LastError)
+
+		a.deployed = true
+		cleanupCtx, cancel := cleanupContext(ctx)
+		defer cancel()
+		if err := a.Rollback(cleanupCtx); err != nil {
+			slog.Warn("Failed to remove Redis after failed readiness probe", "error", err)
+		}
+
+		endTime := time.Now()
+		return &models.ActionResult{
+			ActionID:        a.actionID,
+			DetectionID:     a.detectionID,
+			ActionType:      "deploy_redis",
+			DatabaseID:      a.databaseID,
+			Status:          models.StatusFailed,
+			Message:         "Redis did not become ready and was removed",
+			Error:           fmt.Sprintf("readiness probe on port %s failed: %s", a.port, probeResult.LastError),
+			ErrorCode:       models.ErrorCodeTimeout, // Removed again, so a retry starts from scratch
+			CreatedAt:       startTime,
+			Started:         &startTime,
+			Completed:       &endTime,
+			ExecutionTimeMs: endTime.Sub(startTime).Milliseconds(),
+			Changes: map[string]interface{}{
+				"container_name":  a.containerName,
+				"redis_port":      a.port,
+				"readiness_probe": probeResult.Changes(),
+			},
+		}, nil
+	}
+
+	log.Printf("Redis is ready on port %s", a.port)
 
 	endTime := time.Now()
 	executionTimeMs := endTime.Sub(startTime).Milliseconds()
@@ -238,9 +312,11 @@ func (a *DeployRedisAction) Execute(ctx context.Context) (*models.ActionResult,
 			"redis_port":           a.port,
 			"max_memory":           a.maxMemory,
 			"eviction_policy":      a.evictionPolicy,
+			"resource_limits":      a.limits.Changes(),
 			"connection_string":    fmt.Sprintf("redis://localhost:%s", a.port),
 			"instruction":          "Update your application to use Redis for caching. See integration guide in Dashboard.",
 			"requires_code_change": true,
+			"readiness_probe":      probeResult.Changes(),
 		},
 		CanRollback: true,
 		Rolledback:  false,
@@ -261,7 +337,7 @@ func (a *DeployRedisAction) Rollback(ctx context.Context) error {
 
 	// Stop the container
 	if err := a.dockerClient.StopContainer(ctx, a.containerID); err != nil {
-		log.Printf("Warning: failed to stop container: %v", err)
+		slog.Warn("Failed to stop container", "error", err)
 	}
 
 	// Remove the container
@@ -275,6 +351,51 @@ func (a *DeployRedisAction) Rollback(ctx context.Context) error {
 	return nil
 }
 
+// CheckRollback refuses to remove Redis while clients are connected to it: the
+// application has started using it as its cache.
+func (a *DeployRedisAction) CheckRollback(ctx context.Context, safety RollbackSafety) error {
+	if !a.deployed {
+		return nil
+	}
+
+	addr := net.JoinHostPort(a.probeSettings.Host, a.port)
+	clients, err := a.countClients(ctx, addr)
+	if err != nil {
+		return fmt.Errorf("%w: cannot check for clients connected to Redis at %s: %v", ErrRollbackUnsafe, addr, err)
+	}
+
+	if clients > 0 {
+		return fmt.Errorf("%w: %d clients are connected to Redis container %s; removing it would break them",
+			ErrRollbackUnsafe, clients, a.containerName)
+	}
+
+	return nil
+}
+
+// Restore re-attaches to a container deployed before an Executor restart.
+func (a *DeployRedisAction) Restore(ctx context.Context, changes map[string]interface{}) (bool, error) {
+	if name, ok := changes["container_name"].(string); ok && name != "" {
+		a.containerName = name
+	}
+	if port, ok := intFromParam(changes["redis_port"]); ok && port > 0 {
+		a.port = strconv.Itoa(port)
+	}
+
+	exists, containerID, err := a.dockerClient.ContainerExists(ctx, a.containerName)
+	if err != nil {
+		return false, fmt.Errorf("failed to check container: %w", err)
+	}
+
+	if !exists {
+		return false, nil
+	}
+
+	a.containerID = containerID
+	a.deployed = true
+
+	return true, nil
+}
+
 func (a *DeployRedisAction) Validate(ctx context.Context) error {
 	// Check Docker is available
 	if err := a.dockerClient.IsAvailable(ctx); err != nil {
@@ -285,6 +406,18 @@ func (a *DeployRedisAction) Validate(ctx context.Context) error {
 	// This is a basic check - full port scanning would be more robust
 	// but Docker will fail to bind if port is in use anyway
 
+	// Redis would be OOM-killed before it ever started evicting
+	if a.limits.MemoryBytes > 0 {
+		maxMemory, err := units.RAMInBytes(a.maxMemory)
+		if err != nil {
+			return fmt.Errorf("invalid Redis maxmemory %q: %w", a.maxMemory, err)
+		}
+		if maxMemory >= a.limits.MemoryBytes {
+			return fmt.Errorf("Redis maxmemory %s must be below the container memory limit of %s",
+				a.maxMemory, units.BytesSize(float64(a.limits.MemoryBytes)))
+		}
+	}
+
 	return nil
 }
 
@@ -297,3 +430,21 @@ func (a *DeployRedisAction) GetMetadata() *models.ActionMetadata {
 		CreatedAt:    time.Now(),
 	}
 }
+
+// redisSettings reads the port, memory limit and eviction policy from params, with defaults.
+func redisSettings(params map[string]interface{}) (port, maxMemory, evictionPolicy string) {
+	port = "6379"
+	maxMemory = "256mb"
+	evictionPolicy = "allkeys-lru"
+
+	if p, ok := params["port"].(string); ok && p != "" {
+		port = p
+	}
+	if m, ok := params["max_memory"].(string); ok && m != "" {
+		maxMemory = m
+	}
+	if e, ok := params["eviction_policy"].(string); ok && e != "" {
+		evictionPolicy = e
+	}
+	return port, maxMemory, evictionPolicy
+}
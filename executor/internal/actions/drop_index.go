@@ -0,0 +1,191 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/database"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+)
+
+// DropIndexAction removes an unused index. The index definition is captured before the
+// drop so Rollback can re-create it exactly.
+type DropIndexAction struct {
+	metadata   *models.ActionMetadata
+	adapter    database.DatabaseAdapter
+	indexName  string
+	tableName  string
+	definition string
+	dropped    bool
+}
+
+func NewDropIndexAction(metadata *models.ActionMetadata, adapter database.DatabaseAdapter, indexName, tableName string) *DropIndexAction {
+	return &DropIndexAction{
+		metadata:  metadata,
+		adapter:   adapter,
+		indexName: indexName,
+		tableName: tableName,
+	}
+}
+
+func (a *DropIndexAction) GetMetadata() *models.ActionMetadata {
+	return a.metadata
+}
+
+func (a *DropIndexAction) Validate(ctx context.Context) error {
+	caps := a.adapter.GetCapabilities()
+	if !caps.SupportsIndexes {
+		return database.ErrActionNotSupported
+	}
+
+	// Never drop an index we cannot put back
+	if _, ok := a.adapter.(database.IndexDefinitionManager); !ok {
+		return database.ErrActionNotSupported
+	}
+
+	if a.indexName == "" {
+		return fmt.Errorf("index name is required")
+	}
+
+	exists, err := a.adapter.IndexExists(ctx, a.indexName)
+	if err != nil {
+		return fmt.Errorf("failed to check index existance: %w", err)
+	}
+
+	if !exists {
+		return fmt.Errorf("index '%s' does not exist", a.indexName)
+	}
+
+	return nil
+}
+
+func (a *DropIndexAction) Execute(ctx context.Context) (*models.ActionResult, error) {
+	startTime := time.Now()
+	started := time.Now()
+
+	failed := func(message string, err error) *models.ActionResult {
+		return &models.ActionResult{
+			ActionID:        a.metadata.ActionID,
+			ActionType:      a.metadata.ActionType,
+			DatabaseID:      a.metadata.DatabaseID,
+			Status:          models.StatusFailed,
+			Message:         message,
+			Error:           err.Error(),
+			ErrorCode:       models.ClassifyError(err),
+			CreatedAt:       a.metadata.CreatedAt,
+			Started:         &started,
+			ExecutionTimeMs: int64(time.Since(startTime).Milliseconds()),
+			CanRollback:     false,
+		}
+	}
+
+	if err := a.Validate(ctx); err != nil {
+		result := failed("Validation error", err)
+		result.ErrorCode = models.ClassifyValidationError(err)
+		return result, nil
+	}
+
+	manager := a.adapter.(database.IndexDefinitionManager)
+
+	definition, err := manager.GetIndexDefinition(ctx, a.indexName)
+	if err != nil {
+		return failed("Failed to capture index definition", err), nil
+	}
+	if definition == "" {
+		return failed("Failed to capture index definition", fmt.Errorf("no definition found for index '%s'", a.indexName)), nil
+	}
+
+	var sizeBytes int64 = -1
+	if reporter, ok := a.adapter.(database.IndexProgressReporter); ok {
+		if size, err := reporter.GetIndexSize(ctx, a.indexName); err == nil {
+			sizeBytes = size
+		}
+	}
+
+	if err := a.adapter.DropIndex(ctx, a.indexName); err != nil {
+		return failed("Index drop failed", err), nil
+	}
+
+	a.definition = definition
+	a.dropped = true
+
+	completed := time.Now()
+	changes := map[string]interface{}{
+		"index_name":       a.indexName,
+		"table_name":       a.tableName,
+		"index_definition": definition,
+	}
+	if sizeBytes >= 0 {
+		changes["index_size_bytes"] = sizeBytes
+	}
+
+	return &models.ActionResult{
+		ActionID:        a.metadata.ActionID,
+		ActionType:      a.metadata.ActionType,
+		DatabaseID:      a.metadata.DatabaseID,
+		Status:          models.StatusCompleted,
+		Message:         fmt.Sprintf("Index %s dropped successfully", a.indexName),
+		CreatedAt:       a.metadata.CreatedAt,
+		Started:         &started,
+		Completed:       &completed,
+		ExecutionTimeMs: int64(time.Since(startTime).Milliseconds()),
+		Changes:         changes,
+		CanRollback:     true,
+		Rolledback:      false,
+	}, nil
+}
+
+// Rollback re-creates the dropped index from its captured definition.
+func (a *DropIndexAction) Rollback(ctx context.Context) error {
+	if !a.dropped {
+		return nil
+	}
+
+	exists, err := a.adapter.IndexExists(ctx, a.indexName)
+	if err != nil {
+		return fmt.Errorf("failed to check index: %w", err)
+	}
+
+	if !exists {
+		manager, ok := a.adapter.(database.IndexDefinitionManager)
+		if !ok {
+			return database.ErrActionNotSupported
+		}
+
+		if err := manager.RestoreIndex(ctx, a.definition); err != nil {
+			return fmt.Errorf("failed to re-create index: %w", err)
+		}
+	}
+
+	a.dropped = false
+	return nil
+}
+
+// Restore re-attaches to an index dropped before an Executor restart. There is
+// nothing to roll back if the index has since been re-created.
+func (a *DropIndexAction) Restore(ctx context.Context, changes map[string]interface{}) (bool, error) {
+	indexName, _ := changes["index_name"].(string)
+	definition, _ := changes["index_definition"].(string)
+	if indexName == "" || definition == "" {
+		return false, fmt.Errorf("recorded changes missing index_name or index_definition")
+	}
+
+	exists, err := a.adapter.IndexExists(ctx, indexName)
+	if err != nil {
+		return false, fmt.Errorf("failed to check index: %w", err)
+	}
+
+	if exists {
+		return false, nil
+	}
+
+	a.indexName = indexName
+	a.definition = definition
+	if tableName, ok := changes["table_name"].(string); ok && tableName != "" {
+		a.tableName = tableName
+	}
+	a.dropped = true
+
+	return true, nil
+}
@@ -19,7 +19,7 @@ func NewFutureFixAction(id, actionType, dbID, reason string) *FutureFix {
 			ActionID:   id,
 			ActionType: actionType,
 			DatabaseID: dbID,
-			CreatedAt:  time.Time{},
+			CreatedAt:  time.Now(),
 		},
 		actionType: actionType,
 		reason:     reason,
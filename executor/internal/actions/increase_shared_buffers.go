@@ -0,0 +1,521 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/database"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/docker"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+)
+
+// shared_buffers is doubled on each run, within these bounds (in kB, Postgres' unit for SHOW)
+const (
+	minSharedBuffersKB = 256 * 1024
+	maxSharedBuffersKB = 8 * 1024 * 1024
+
+	// Postgres' guidance is a quarter of the memory available; the rest is left to the OS
+	// cache, connections and work_mem
+	sharedBuffersMemoryShare = 4
+)
+
+// IncreaseSharedBuffersAction raises shared_buffers on a Postgres running in a Docker
+// container. shared_buffers only takes effect on restart, so the value is staged with
+// ALTER SYSTEM and the container is restarted.
+type IncreaseSharedBuffersAction struct {
+	actionID      string
+	detectionID   string
+	databaseID    string
+	containerName string
+
+	adapter      database.DatabaseAdapter
+	dockerClient docker.Runtime
+
+	// Readiness probe run after each restart
+	probeTimeout time.Duration
+
+	// Recorded for rollback
+	originalValue     string
+	originalPersisted bool
+	newValue          string
+	applied           bool
+
+	// postgresql.auto.conf before the change, written back if Postgres cannot start
+	dataDirectory string
+	autoConf      *string
+}
+
+// NewIncreaseSharedBuffersAction creates the action for the database in containerName.
+func NewIncreaseSharedBuffersAction(
+	actionID string,
+	detectionID string,
+	databaseID string,
+	containerName string,
+	adapter database.DatabaseAdapter,
+) (*IncreaseSharedBuffersAction, error) {
+	dockerClient, err := docker.NewClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Docker client: %w", err)
+	}
+
+	return NewIncreaseSharedBuffersActionWithRuntime(actionID, detectionID, databaseID, containerName, adapter, dockerClient), nil
+}
+
+// NewIncreaseSharedBuffersActionWithRuntime creates the action with a caller-supplied container runtime.
+func NewIncreaseSharedBuffersActionWithRuntime(
+	actionID string,
+	detectionID string,
+	databaseID string,
+	containerName string,
+	adapter database.DatabaseAdapter,
+	dockerClient docker.Runtime,
+) *IncreaseSharedBuffersAction {
+	return &IncreaseSharedBuffersAction{
+		actionID:      actionID,
+		detectionID:   detectionID,
+		databaseID:    databaseID,
+		containerName: containerName,
+		adapter:       adapter,
+		dockerClient:  dockerClient,
+		probeTimeout:  docker.DefaultProbeSettings().Timeout,
+	}
+}
+
+// SetProbeSettings configures how long to wait for Postgres after a restart.
+func (a *IncreaseSharedBuffersAction) SetProbeSettings(settings docker.ProbeSettings) {
+	a.probeTimeout = settings.Timeout
+}
+
+func (a *IncreaseSharedBuffersAction) Execute(ctx context.Context) (*models.ActionResult, error) {
+	startTime := time.Now()
+
+	persistence, ok := a.adapter.(database.PersistedConfigManager)
+	if !ok {
+		return nil, fmt.Errorf("database adapter cannot persist config changes")
+	}
+
+	current, err := a.currentSharedBuffers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	currentKB, err := parseMemoryKB(current)
+	if err != nil {
+		return nil, fmt.Errorf("cannot interpret shared_buffers %q: %w", current, err)
+	}
+
+	containerID, err := a.findContainer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limitKB, err := a.sharedBuffersLimitKB(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+
+	targetKB := min(max(currentKB*2, minSharedBuffersKB), limitKB)
+	if targetKB <= currentKB {
+		endTime := time.Now()
+		return &models.ActionResult{
+			ActionID:        a.actionID,
+			DetectionID:     a.detectionID,
+			ActionType:      "increase_cache_size",
+			DatabaseID:      a.databaseID,
+			Status:          models.StatusCompleted,
+			Message:         fmt.Sprintf("shared_buffers is already %s, at or above the %s limit (no action needed)", current, formatMemoryKB(limitKB)),
+			CreatedAt:       startTime,
+			Started:         &startTime,
+			Completed:       &endTime,
+			ExecutionTimeMs: endTime.Sub(startTime).Milliseconds(),
+			Changes: map[string]interface{}{
+				"container_name":          a.containerName,
+				"original_shared_buffers": current,
+			},
+		}, nil
+	}
+
+	persisted, err := persistence.IsConfigPersisted(ctx, "shared_buffers")
+	if err != nil {
+		return nil, err
+	}
+
+	if dataDirectory, content, err := persistence.ReadPersistedConfigFile(ctx); err != nil {
+		slog.Warn("Could not save postgresql.auto.conf, a failed restart can only be undone over SQL", "error", err)
+	} else {
+		a.dataDirectory = dataDirectory
+		a.autoConf = &content
+	}
+
+	a.originalValue = current
+	a.originalPersisted = persisted
+	a.newValue = formatMemoryKB(targetKB)
+
+	log.Printf("Increasing shared_buffers on %s from %s to %s", a.databaseID, a.originalValue, a.newValue)
+
+	if err := a.adapter.SetConfig(ctx, map[string]string{"shared_buffers": a.newValue}); err != nil {
+		return nil, fmt.Errorf("failed to stage shared_buffers: %w", err)
+	}
+	a.applied = true
+
+	log.Printf("Restarting container %s to apply shared_buffers", a.containerName)
+	if err := a.dockerClient.RestartContainer(ctx, containerID); err != nil {
+		// Nothing took effect yet - drop the staged value so a later restart does not pick it up
+		cleanupCtx, cancel := cleanupContext(ctx)
+		defer cancel()
+		if rollbackErr := a.unstage(cleanupCtx, persistence); rollbackErr != nil {
+			slog.Warn("Failed to remove staged shared_buffers", "error", rollbackErr)
+		}
+		a.applied = false
+		return nil, err
+	}
+
+	probeResult := docker.WaitForReady(ctx, a.expectSharedBuffers(targetKB), a.probeTimeout)
+
+	changes := map[string]interface{}{
+		"container_name":                 a.containerName,
+		"original_shared_buffers":        a.originalValue,
+		"original_set_with_alter_system": a.originalPersisted,
+		"new_shared_buffers":             a.newValue,
+		"readiness_probe":                probeResult.Changes(),
+	}
+
+	if !probeResult.Passed {
+		slog.Error("Postgres did not come back with new shared_buffers", "shared_buffers", a.newValue, "error", probeResult.LastError)
+
+		cleanupCtx, cancel := cleanupContext(ctx)
+		defer cancel()
+		rollbackErr := a.Rollback(cleanupCtx)
+		if rollbackErr != nil {
+			// Postgres that cannot start cannot take ALTER SYSTEM either
+			slog.Warn("Failed to restore shared_buffers over SQL, rewriting postgresql.auto.conf", "error", rollbackErr)
+			if err := a.restoreAutoConf(cleanupCtx, containerID); err != nil {
+				rollbackErr = fmt.Errorf("failed to restore shared_buffers %s: %w", a.originalValue, errors.Join(rollbackErr, err))
+			} else {
+				rollbackErr = nil
+			}
+		}
+
+		result := &models.ActionResult{
+			ActionID:    a.actionID,
+			DetectionID: a.detectionID,
+			ActionType:  "increase_cache_size",
+			DatabaseID:  a.databaseID,
+			Status:      models.StatusFailed,
+			Message:     fmt.Sprintf("Postgres did not become ready with the new shared_buffers and was restored to %s", a.originalValue),
+			Error:       fmt.Sprintf("readiness probe after restart failed: %s", probeResult.LastError),
+			// Most likely more memory than the container has; retrying would only restart it again
+			ErrorCode:   models.ErrorCodeInvalidRequest,
+			CreatedAt:   startTime,
+			Started:     &startTime,
+			Changes:     changes,
+			CanRollback: a.applied,
+		}
+		if rollbackErr != nil {
+			result.Message = fmt.Sprintf("Postgres did not become ready with the new shared_buffers and could not be restored to %s", a.originalValue)
+			result.Error = fmt.Sprintf("%s; %v", result.Error, rollbackErr)
+		}
+
+		endTime := time.Now()
+		result.Completed = &endTime
+		result.ExecutionTimeMs = endTime.Sub(startTime).Milliseconds()
+		return result, rollbackErr
+	}
+
+	log.Printf("shared_buffers on %s is now %s", a.databaseID, a.newValue)
+
+	endTime := time.Now()
+	return &models.ActionResult{
+		ActionID:        a.actionID,
+		DetectionID:     a.detectionID,
+		ActionType:      "increase_cache_size",
+		DatabaseID:      a.databaseID,
+		Status:          models.StatusCompleted,
+		Message:         fmt.Sprintf("Increased shared_buffers from %s to %s and restarted container '%s'", a.originalValue, a.newValue, a.containerName),
+		CreatedAt:       startTime,
+		Started:         &startTime,
+		Completed:       &endTime,
+		ExecutionTimeMs: endTime.Sub(startTime).Milliseconds(),
+		Changes:         changes,
+		CanRollback:     true,
+	}, nil
+}
+
+// Rollback restores the original shared_buffers and restarts the container again.
+func (a *IncreaseSharedBuffersAction) Rollback(ctx context.Context) error {
+	if !a.applied {
+		return fmt.Errorf("shared_buffers was not changed, cannot rollback")
+	}
+
+	persistence, ok := a.adapter.(database.PersistedConfigManager)
+	if !ok {
+		return fmt.Errorf("database adapter cannot persist config changes")
+	}
+
+	if err := a.unstage(ctx, persistence); err != nil {
+		return err
+	}
+
+	containerID, err := a.findContainer(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Restarting container %s to restore shared_buffers %s", a.containerName, a.originalValue)
+	return a.restartWithOriginal(ctx, containerID)
+}
+
+// restartWithOriginal restarts the container once the original value is back in place and
+// waits for Postgres to report it.
+func (a *IncreaseSharedBuffersAction) restartWithOriginal(ctx context.Context, containerID string) error {
+	if err := a.dockerClient.RestartContainer(ctx, containerID); err != nil {
+		return err
+	}
+
+	originalKB, err := parseMemoryKB(a.originalValue)
+	if err != nil {
+		return fmt.Errorf("cannot interpret original shared_buffers %q: %w", a.originalValue, err)
+	}
+
+	probeResult := docker.WaitForReady(ctx, a.expectSharedBuffers(originalKB), a.probeTimeout)
+	if !probeResult.Passed {
+		return fmt.Errorf("postgres did not come back with shared_buffers %s: %s", a.originalValue, probeResult.LastError)
+	}
+
+	log.Printf("shared_buffers on %s restored to %s", a.databaseID, a.originalValue)
+
+	a.applied = false
+	return nil
+}
+
+// restoreAutoConf writes the postgresql.auto.conf saved before the change back into the
+// container and restarts it, for when Postgres is down and cannot be reached over SQL.
+// The copy is owned by root, so it is left readable for the postgres user.
+func (a *IncreaseSharedBuffersAction) restoreAutoConf(ctx context.Context, containerID string) error {
+	if a.autoConf == nil {
+		return fmt.Errorf("postgresql.auto.conf was not saved before the change")
+	}
+
+	if err := a.dockerClient.CopyFileToContainer(ctx, containerID, a.dataDirectory, "postgresql.auto.conf", []byte(*a.autoConf), 0644); err != nil {
+		return err
+	}
+
+	log.Printf("Restarting container %s with the saved postgresql.auto.conf", a.containerName)
+	return a.restartWithOriginal(ctx, containerID)
+}
+
+// sharedBuffersLimitKB caps shared_buffers at a share of the memory the container may use,
+// so doubling it cannot push Postgres past its memory limit.
+func (a *IncreaseSharedBuffersAction) sharedBuffersLimitKB(ctx context.Context, containerID string) (int64, error) {
+	state, err := a.dockerClient.InspectState(ctx, containerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check memory available to %s: %w", a.containerName, err)
+	}
+
+	if state.MemoryLimit <= 0 {
+		return maxSharedBuffersKB, nil
+	}
+	return min(state.MemoryLimit/1024/sharedBuffersMemoryShare, maxSharedBuffersKB), nil
+}
+
+// unstage puts the ALTER SYSTEM state back the way it was before Execute.
+func (a *IncreaseSharedBuffersAction) unstage(ctx context.Context, persistence database.PersistedConfigManager) error {
+	if a.originalPersisted {
+		if err := a.adapter.SetConfig(ctx, map[string]string{"shared_buffers": a.originalValue}); err != nil {
+			return fmt.Errorf("failed to restore shared_buffers: %w", err)
+		}
+		return nil
+	}
+
+	if err := persistence.ResetConfig(ctx, []string{"shared_buffers"}); err != nil {
+		return fmt.Errorf("failed to reset shared_buffers: %w", err)
+	}
+	return nil
+}
+
+// Restore re-attaches to a shared_buffers change made before an Executor restart.
+// Returns false if shared_buffers has since been changed by someone else.
+func (a *IncreaseSharedBuffersAction) Restore(ctx context.Context, changes map[string]interface{}) (bool, error) {
+	original, _ := changes["original_shared_buffers"].(string)
+	applied, _ := changes["new_shared_buffers"].(string)
+	if original == "" || applied == "" {
+		return false, fmt.Errorf("recorded changes missing original_shared_buffers or new_shared_buffers")
+	}
+
+	if name, ok := changes["container_name"].(string); ok && name != "" {
+		a.containerName = name
+	}
+
+	exists, _, err := a.dockerClient.ContainerExists(ctx, a.containerName)
+	if err != nil {
+		return false, fmt.Errorf("failed to check container: %w", err)
+	}
+	if !exists {
+		return false, nil
+	}
+
+	current, err := a.currentSharedBuffers(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if !sameMemory(current, applied) {
+		return false, nil
+	}
+
+	a.originalValue = original
+	a.originalPersisted, _ = changes["original_set_with_alter_system"].(bool)
+	a.newValue = applied
+	a.applied = true
+
+	return true, nil
+}
+
+func (a *IncreaseSharedBuffersAction) Validate(ctx context.Context) error {
+	if a.containerName == "" {
+		return fmt.Errorf("database %s is not running in a known Docker container", a.databaseID)
+	}
+
+	caps := a.adapter.GetCapabilities()
+	if !caps.SupportsConfigTuning {
+		return fmt.Errorf("database does not support config tuning")
+	}
+
+	if _, ok := a.adapter.(database.PersistedConfigManager); !ok {
+		return fmt.Errorf("database adapter cannot persist config changes")
+	}
+
+	if err := a.dockerClient.IsAvailable(ctx); err != nil {
+		return fmt.Errorf("docker not available: %w", err)
+	}
+
+	containerID, err := a.findContainer(ctx)
+	if err != nil {
+		return err
+	}
+
+	running, err := a.dockerClient.IsContainerRunning(ctx, containerID)
+	if err != nil {
+		return fmt.Errorf("failed to check container status: %w", err)
+	}
+	if !running {
+		return fmt.Errorf("container %s is not running", a.containerName)
+	}
+
+	return nil
+}
+
+func (a *IncreaseSharedBuffersAction) GetMetadata() *models.ActionMetadata {
+	return &models.ActionMetadata{
+		ActionID:     a.actionID,
+		ActionType:   "increase_cache_size",
+		DatabaseID:   a.databaseID,
+		DatabaseType: "postgres",
+		CreatedAt:    time.Now(),
+	}
+}
+
+func (a *IncreaseSharedBuffersAction) findContainer(ctx context.Context) (string, error) {
+	exists, containerID, err := a.dockerClient.ContainerExists(ctx, a.containerName)
+	if err != nil {
+		return "", fmt.Errorf("failed to check container: %w", err)
+	}
+	if !exists {
+		return "", fmt.Errorf("container %s not found", a.containerName)
+	}
+	return containerID, nil
+}
+
+func (a *IncreaseSharedBuffersAction) currentSharedBuffers(ctx context.Context) (string, error) {
+	config, err := a.adapter.GetCurrentConfig(ctx, []string{"shared_buffers"})
+	if err != nil {
+		return "", fmt.Errorf("failed to get current shared_buffers: %w", err)
+	}
+
+	value := config["shared_buffers"]
+	if value == "" {
+		return "", fmt.Errorf("database did not report shared_buffers")
+	}
+	return value, nil
+}
+
+// expectSharedBuffers is a readiness probe that passes once Postgres answers with the
+// expected shared_buffers. Connections dropped by the restart fail the first attempts.
+func (a *IncreaseSharedBuffersAction) expectSharedBuffers(expectedKB int64) docker.ReadinessProbe {
+	return func(ctx context.Context) (string, error) {
+		current, err := a.currentSharedBuffers(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		currentKB, err := parseMemoryKB(current)
+		if err != nil {
+			return "", fmt.Errorf("cannot interpret shared_buffers %q: %w", current, err)
+		}
+		if currentKB != expectedKB {
+			return "", fmt.Errorf("shared_buffers is %s, expected %s", current, formatMemoryKB(expectedKB))
+		}
+
+		return "shared_buffers = " + current, nil
+	}
+}
+
+// parseMemoryKB converts a Postgres memory setting such as "128MB" to kB.
+// A bare number is in 8kB pages, the unit of shared_buffers.
+func parseMemoryKB(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+
+	units := []struct {
+		suffix string
+		kb     int64
+	}{
+		{"TB", 1024 * 1024 * 1024},
+		{"GB", 1024 * 1024},
+		{"MB", 1024},
+		{"kB", 1},
+	}
+
+	multiplier := int64(8)
+	for _, unit := range units {
+		if strings.HasSuffix(value, unit.suffix) {
+			value = strings.TrimSuffix(value, unit.suffix)
+			multiplier = unit.kb
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("must be positive")
+	}
+
+	return n * multiplier, nil
+}
+
+// formatMemoryKB renders kB in the largest unit that divides it exactly, as SHOW does.
+func formatMemoryKB(kb int64) string {
+	switch {
+	case kb%(1024*1024) == 0:
+		return fmt.Sprintf("%dGB", kb/(1024*1024))
+	case kb%1024 == 0:
+		return fmt.Sprintf("%dMB", kb/1024)
+	default:
+		return fmt.Sprintf("%dkB", kb)
+	}
+}
+
+// sameMemory compares two memory settings regardless of unit.
+func sameMemory(a, b string) bool {
+	aKB, errA := parseMemoryKB(a)
+	bKB, errB := parseMemoryKB(b)
+	return errA == nil && errB == nil && aKB == bKB
+}
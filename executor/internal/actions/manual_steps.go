@@ -0,0 +1,65 @@
+package actions
+
+import (
+	"fmt"
+)
+
+// ContainerManualSteps describes how to do by hand what a container-backed action would
+// have done, for when the Executor cannot reach Docker. reason says why it could not.
+// The second result is false for action types that do not use containers.
+func ContainerManualSteps(actionType, databaseID string, pgBouncer PgBouncerSettings, params map[string]interface{}, reason string) (Recommendation, bool) {
+	note := fmt.Sprintf("The Executor could not reach Docker (%s), so this was not applied automatically.", reason)
+
+	switch actionType {
+	case "deploy_connection_pooler":
+		settings := pgBouncer.withOverrides(params)
+		return Recommendation{
+			Title:       "Deploy PgBouncer Connection Pooler",
+			Description: "Put PgBouncer in front of the database to pool connections. " + note,
+			RiskLevel:   "medium",
+			Steps: []string{
+				fmt.Sprintf("Run PgBouncer next to the database, e.g. docker run -d --name pgbouncer-%s -p %d:6432 %s", databaseID, settings.HostPort, settings.Image),
+				fmt.Sprintf("Configure pool_mode = %s, default_pool_size = %d, max_client_conn = %d", settings.PoolMode, settings.DefaultPoolSize, settings.MaxClientConn),
+				"Use the same auth_type and password hashes as the database so clients authenticate unchanged",
+				fmt.Sprintf("Point the application's connection string at port %d", settings.HostPort),
+				"Watch active connections in the Dashboard to confirm they are pooled",
+			},
+			RequiresCodeChange:   true,
+			DeployableActionType: actionType,
+		}, true
+
+	case "deploy_redis":
+		port, maxMemory, evictionPolicy := redisSettings(params)
+		return Recommendation{
+			Title:       "Deploy Redis Cache Layer",
+			Description: "Cache frequent reads in Redis to take load off the database. " + note,
+			RiskLevel:   "advanced",
+			Steps: []string{
+				fmt.Sprintf("Run Redis, e.g. docker run -d --name redis-%s -p %s:6379 %s redis-server --maxmemory %s --maxmemory-policy %s",
+					databaseID, port, redisImage, maxMemory, evictionPolicy),
+				fmt.Sprintf("Check it answers with redis-cli -p %s ping", port),
+				fmt.Sprintf("Cache hot queries in the application using redis://localhost:%s", port),
+			},
+			RequiresCodeChange:   true,
+			DeployableActionType: actionType,
+		}, true
+
+	case "increase_cache_size":
+		return Recommendation{
+			Title:       "Increase PostgreSQL shared_buffers",
+			Description: "A larger buffer cache keeps more of the working set in memory. " + note,
+			RiskLevel:   "safe",
+			Steps: []string{
+				"Check the current value with SHOW shared_buffers",
+				fmt.Sprintf("Double it, up to %s: ALTER SYSTEM SET shared_buffers = '<new value>'", formatMemoryKB(maxSharedBuffersKB)),
+				"Restart PostgreSQL - shared_buffers only changes on restart",
+				"Monitor the cache hit rate in the Dashboard",
+			},
+			RequiresRestart:      true,
+			DeployableActionType: actionType,
+		}, true
+
+	default:
+		return Recommendation{}, false
+	}
+}
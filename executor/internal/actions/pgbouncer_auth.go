@@ -0,0 +1,73 @@
+package actions
+
+import (
+	"context"
+	"crypto/md5"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/database"
+)
+
+const (
+	authTypeMD5   = "md5"
+	authTypeSCRAM = "scram-sha-256"
+)
+
+// pgbouncerAuth is what goes in userlist.txt and PGBOUNCER_AUTH_TYPE.
+type pgbouncerAuth struct {
+	authType string
+	secret   string
+	source   string // where the secret came from, for logs and Changes
+}
+
+// resolvePgBouncerAuth picks the auth type and userlist secret for user. The role's own
+// hash from pg_authid is used when readable, since it always matches the server. Otherwise
+// an md5 server gets the md5 hash, which is deterministic, and a SCRAM server gets the
+// plaintext password. Without an inspector SCRAM is assumed, the default since PostgreSQL 14.
+func resolvePgBouncerAuth(ctx context.Context, inspector database.PasswordAuthInspector, user, password string) (pgbouncerAuth, error) {
+	encryption := authTypeSCRAM
+
+	if inspector != nil {
+		hash, err := inspector.GetRolePasswordHash(ctx, user)
+		if err != nil {
+			slog.Warn("Could not read password hash", "user", user, "error", err)
+		}
+
+		switch {
+		case strings.HasPrefix(hash, "SCRAM-SHA-256$"):
+			return pgbouncerAuth{authType: authTypeSCRAM, secret: hash, source: "pg_authid"}, nil
+		case strings.HasPrefix(hash, "md5"):
+			return pgbouncerAuth{authType: authTypeMD5, secret: hash, source: "pg_authid"}, nil
+		}
+
+		if setting, err := inspector.GetPasswordEncryption(ctx); err != nil {
+			slog.Warn("Could not read password_encryption, assuming the default", "encryption", encryption, "error", err)
+		} else if setting == authTypeMD5 {
+			encryption = authTypeMD5
+		}
+	}
+
+	if encryption == authTypeMD5 {
+		return pgbouncerAuth{authType: authTypeMD5, secret: md5Secret(user, password), source: "generated"}, nil
+	}
+
+	// A verifier generated here would carry its own salt, which never matches the one the
+	// server stored, so PgBouncer could not log in with it. PgBouncer derives SCRAM proofs
+	// itself from a plaintext entry.
+	return pgbouncerAuth{authType: authTypeSCRAM, secret: password, source: "plaintext"}, nil
+}
+
+// md5Secret returns PostgreSQL's md5 password hash: "md5" + md5(password + username).
+func md5Secret(user, password string) string {
+	hash := md5.Sum([]byte(password + user))
+	return fmt.Sprintf("md5%x", hash)
+}
+
+// userlistContent renders userlist.txt for PgBouncer authentication. Double quotes inside a
+// field are escaped by doubling them, as PgBouncer expects.
+func userlistContent(user, secret string) []byte {
+	quote := func(s string) string { return strings.ReplaceAll(s, `"`, `""`) }
+	return []byte(fmt.Sprintf("\"%s\" \"%s\"\n", quote(user), quote(secret)))
+}
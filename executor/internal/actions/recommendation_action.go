@@ -18,6 +18,9 @@ type RecommendationAction struct {
 
 	// Recommendations with risk levels
 	recommendations []Recommendation
+
+	// Action type the recommendation stands in for when it could not run; empty otherwise
+	replaces string
 }
 
 // Recommendation represents a single optimization suggestion with risk assessment.
@@ -52,6 +55,26 @@ func NewRecommendationAction(
 	}
 }
 
+// NewManualStepsAction creates a recommendation standing in for an action of type replaces
+// that cannot run here, e.g. a container deployment without Docker.
+func NewManualStepsAction(
+	actionID string,
+	detectionID string,
+	databaseID string,
+	databaseType string,
+	replaces string,
+	recommendation Recommendation,
+) *RecommendationAction {
+	return &RecommendationAction{
+		actionID:        actionID,
+		detectionID:     detectionID,
+		databaseID:      databaseID,
+		databaseType:    databaseType,
+		recommendations: []Recommendation{recommendation},
+		replaces:        replaces,
+	}
+}
+
 // Execute generates and stores recommendations without making any changes.
 func (a *RecommendationAction) Execute(ctx context.Context) (*models.ActionResult, error) {
 	startTime := time.Now()
@@ -75,6 +98,11 @@ func (a *RecommendationAction) Execute(ctx context.Context) (*models.ActionResul
 		CanRollback: false, // Nothing to rollback - no changes made
 	}
 
+	if a.replaces != "" {
+		result.Message = fmt.Sprintf("Manual steps for %s on %s", a.replaces, a.databaseID)
+		result.Changes["replaces_action_type"] = a.replaces
+	}
+
 	return result, nil
 }
 
@@ -0,0 +1,268 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/database"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+)
+
+// Defaults for SlowQueryOptions, used when the detection does not set them.
+const (
+	DefaultSlowQueryThresholdMs = 500.0
+	DefaultSlowQueryLimit       = 20
+)
+
+// SlowQueryOptions selects which slow queries ReportSlowQueriesAction reports on.
+type SlowQueryOptions struct {
+	ThresholdMs float64 // Mean execution time above which a query counts as slow
+	Limit       int     // Slow queries fetched before grouping
+	Explain     bool    // Whether to EXPLAIN the worst query, where the adapter can
+}
+
+// DefaultSlowQueryOptions returns the options used for detections that set none.
+func DefaultSlowQueryOptions() SlowQueryOptions {
+	return SlowQueryOptions{
+		ThresholdMs: DefaultSlowQueryThresholdMs,
+		Limit:       DefaultSlowQueryLimit,
+		Explain:     true,
+	}
+}
+
+// SlowQueryGroup is the queries sharing a fingerprint, e.g. the same statement with
+// different IN-list lengths, with the fix they most likely need.
+type SlowQueryGroup struct {
+	Fingerprint string `json:"fingerprint"`
+	// Example is the slowest query in the group
+	Example            string  `json:"example"`
+	Variants           int     `json:"variants"`
+	Calls              int64   `json:"calls"`
+	MaxExecutionTimeMs float64 `json:"max_execution_time_ms"`
+	// TotalTimeMs is mean time times calls, summed over the group; groups are ranked by it
+	TotalTimeMs    float64 `json:"total_time_ms"`
+	IssueType      string  `json:"issue_type"`
+	Recommendation string  `json:"recommendation"`
+	// IndexCandidates are the filtered sequential scans EXPLAIN found, if it ran
+	IndexCandidates []database.PlannedScan `json:"index_candidates,omitempty"`
+}
+
+// ReportSlowQueriesAction reports the database's slow queries, grouped by fingerprint
+// and annotated with their likely fix. It changes nothing, so it also serves databases
+// whose config cannot be tuned, such as managed databases or read-only users.
+type ReportSlowQueriesAction struct {
+	metadata *models.ActionMetadata
+	adapter  database.DatabaseAdapter
+	options  SlowQueryOptions
+
+	// Action type the report stands in for when that could not run here; empty otherwise
+	replaces string
+}
+
+func NewReportSlowQueriesAction(
+	metadata *models.ActionMetadata,
+	adapter database.DatabaseAdapter,
+	options SlowQueryOptions,
+) *ReportSlowQueriesAction {
+	if options.ThresholdMs <= 0 {
+		options.ThresholdMs = DefaultSlowQueryThresholdMs
+	}
+	if options.Limit <= 0 {
+		options.Limit = DefaultSlowQueryLimit
+	}
+
+	return &ReportSlowQueriesAction{
+		metadata: metadata,
+		adapter:  adapter,
+		options:  options,
+	}
+}
+
+// SetReplaces records that the report stands in for an action of type actionType.
+func (a *ReportSlowQueriesAction) SetReplaces(actionType string) {
+	a.replaces = actionType
+}
+
+func (a *ReportSlowQueriesAction) GetMetadata() *models.ActionMetadata {
+	return a.metadata
+}
+
+func (a *ReportSlowQueriesAction) Validate(ctx context.Context) error {
+	if a.adapter == nil {
+		return fmt.Errorf("no database adapter")
+	}
+	return nil
+}
+
+// Execute reads the slow queries and completes with the report; it never changes the database.
+func (a *ReportSlowQueriesAction) Execute(ctx context.Context) (*models.ActionResult, error) {
+	startTime := time.Now()
+
+	slowQueries, err := a.adapter.GetSlowQueries(ctx, a.options.ThresholdMs, a.options.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve slow queries: %w", err)
+	}
+
+	groups := GroupSlowQueries(slowQueries)
+
+	changes := map[string]interface{}{
+		"slow_query_report":  groups,
+		"threshold_ms":       a.options.ThresholdMs,
+		"optimization_guide": optimizationGuide(a.metadata.DatabaseType),
+		"database_type":      a.metadata.DatabaseType,
+	}
+
+	if len(groups) > 0 && a.options.Explain {
+		if explainErr := a.explainWorst(ctx, &groups[0]); explainErr != nil {
+			changes["explain_error"] = explainErr.Error()
+		}
+	}
+	changes["recommendations"] = slowQueryRecommendations(groups)
+
+	message := fmt.Sprintf("Found %d slow queries in %d groups over %.0fms", len(slowQueries), len(groups), a.options.ThresholdMs)
+	if a.replaces != "" {
+		changes["replaces_action_type"] = a.replaces
+		message = fmt.Sprintf("%s (%s is not supported on %s)", message, a.replaces, a.metadata.DatabaseID)
+	}
+
+	completed := time.Now()
+	return &models.ActionResult{
+		ActionID:        a.metadata.ActionID,
+		ActionType:      a.metadata.ActionType,
+		DatabaseID:      a.metadata.DatabaseID,
+		Status:          models.StatusCompleted,
+		Message:         message,
+		Changes:         changes,
+		CreatedAt:       a.metadata.CreatedAt,
+		Started:         &startTime,
+		Completed:       &completed,
+		ExecutionTimeMs: completed.Sub(startTime).Milliseconds(),
+		CanRollback:     false, // Nothing to rollback - no changes made
+	}, nil
+}
+
+// explainWorst annotates the worst group with the filtered sequential scans in its plan.
+// A query the adapter cannot or may not explain leaves the group's annotation as it was.
+func (a *ReportSlowQueriesAction) explainWorst(ctx context.Context, group *SlowQueryGroup) error {
+	explainer, ok := a.adapter.(database.QueryExplainer)
+	if !ok {
+		return nil
+	}
+
+	plan, err := explainer.ExplainQuery(ctx, group.Example)
+	if errors.Is(err, database.ErrActionNotSupported) {
+		return nil
+	}
+	if err != nil {
+		slog.Warn("Could not EXPLAIN slow query", "database_id", a.metadata.DatabaseID, "error", err)
+		return err
+	}
+
+	var candidates []string
+	for _, scan := range plan.SeqScans {
+		if len(scan.Columns) == 0 {
+			continue
+		}
+		group.IndexCandidates = append(group.IndexCandidates, scan)
+		candidates = append(candidates, fmt.Sprintf("%s (%s)", scan.Table, strings.Join(scan.Columns, ", ")))
+	}
+
+	if len(candidates) > 0 {
+		group.IssueType = "missing_index"
+		group.Recommendation = "Sequential scan on a filtered table - add an index on " + strings.Join(candidates, " and ")
+	}
+
+	return nil
+}
+
+func (a *ReportSlowQueriesAction) Rollback(ctx context.Context) error {
+	return fmt.Errorf("slow query reports cannot be rolled back (no changes were made)")
+}
+
+var (
+	fingerprintLiteral   = regexp.MustCompile(`'(?:[^']|'')*'`)
+	fingerprintValue     = regexp.MustCompile(`\$\d+|\?|\b\d+(?:\.\d+)?\b`)
+	fingerprintValueList = regexp.MustCompile(`\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+)
+
+// FingerprintQuery normalises a query so statements differing only in their values,
+// placeholder style, IN-list length, case or whitespace share a fingerprint.
+func FingerprintQuery(query string) string {
+	fingerprint := strings.ToLower(query)
+	fingerprint = fingerprintLiteral.ReplaceAllString(fingerprint, "?")
+	fingerprint = fingerprintValue.ReplaceAllString(fingerprint, "?")
+	fingerprint = strings.Join(strings.Fields(fingerprint), " ")
+	fingerprint = fingerprintValueList.ReplaceAllString(fingerprint, "(?)")
+	return strings.TrimSuffix(fingerprint, ";")
+}
+
+// GroupSlowQueries groups slow queries by fingerprint, worst group first. Each group
+// takes its issue and recommendation from its slowest query.
+func GroupSlowQueries(slowQueries []database.SlowQuery) []SlowQueryGroup {
+	groups := make([]SlowQueryGroup, 0, len(slowQueries))
+	index := make(map[string]int)
+
+	for _, query := range slowQueries {
+		fingerprint := FingerprintQuery(query.QueryPattern)
+
+		i, ok := index[fingerprint]
+		if !ok {
+			i = len(groups)
+			index[fingerprint] = i
+			groups = append(groups, SlowQueryGroup{Fingerprint: fingerprint})
+		}
+		group := &groups[i]
+
+		group.Variants++
+		group.Calls += int64(query.CallCount)
+		group.TotalTimeMs += query.ExecutionTimeMs * float64(query.CallCount)
+		if query.ExecutionTimeMs > group.MaxExecutionTimeMs || group.Example == "" {
+			group.MaxExecutionTimeMs = query.ExecutionTimeMs
+			group.Example = query.QueryPattern
+			group.IssueType = query.IssueType
+			group.Recommendation = query.Recommendation
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].TotalTimeMs > groups[j].TotalTimeMs
+	})
+
+	return groups
+}
+
+// slowQueryRecommendations turns each group into a recommendation. Missing indexes are
+// safe to fix from StartupMonkey; anything else needs the query itself changed.
+func slowQueryRecommendations(groups []SlowQueryGroup) []Recommendation {
+	recommendations := make([]Recommendation, 0, len(groups))
+
+	for _, group := range groups {
+		rec := Recommendation{
+			Title:              fmt.Sprintf("%s: %.0fms, %d calls", strings.ReplaceAll(group.IssueType, "_", " "), group.MaxExecutionTimeMs, group.Calls),
+			Description:        fmt.Sprintf("%s. Query: %s", group.Recommendation, group.Example),
+			RiskLevel:          "medium",
+			RequiresCodeChange: true,
+			Steps:              []string{"Run EXPLAIN ANALYZE on the query", group.Recommendation},
+		}
+
+		if len(group.IndexCandidates) > 0 {
+			rec.RiskLevel = "safe"
+			rec.RequiresCodeChange = false
+			rec.DeployableActionType = "create_index"
+			rec.Steps = nil
+			for _, scan := range group.IndexCandidates {
+				rec.Steps = append(rec.Steps, fmt.Sprintf("CREATE INDEX CONCURRENTLY ON %s (%s)", scan.Table, strings.Join(scan.Columns, ", ")))
+			}
+		}
+
+		recommendations = append(recommendations, rec)
+	}
+
+	return recommendations
+}
@@ -0,0 +1,31 @@
+package actions
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrRollbackUnsafe marks a rollback refused because something has come to depend on the
+// change since it was applied. The rollback can still be forced.
+var ErrRollbackUnsafe = errors.New("rollback refused")
+
+// RollbackSafety configures the checks made before an action is rolled back.
+type RollbackSafety struct {
+	// IndexScanThreshold is how many scans of an index created by an action make dropping
+	// it unsafe; 0 disables the check
+	IndexScanThreshold int64
+}
+
+// DefaultRollbackSafety refuses to drop an index scanned 100 times since it was created.
+func DefaultRollbackSafety() RollbackSafety {
+	return RollbackSafety{IndexScanThreshold: 100}
+}
+
+// RollbackChecker is implemented by actions whose rollback could cause an outage once the
+// change is in use, e.g. dropping an index queries rely on or removing a pooler that
+// clients connect through.
+type RollbackChecker interface {
+	// CheckRollback returns an error wrapping ErrRollbackUnsafe, with the reason, when the
+	// rollback should be refused. Not being able to check counts as unsafe.
+	CheckRollback(ctx context.Context, safety RollbackSafety) error
+}
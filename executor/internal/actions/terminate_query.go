@@ -2,19 +2,41 @@ package actions
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path"
+	"strings"
 	"time"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/database"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
 )
 
+// TerminateSettings decides which sessions may be terminated and how. Patterns are
+// case-insensitive globs such as "*migrat*". Superuser sessions are never terminated.
+type TerminateSettings struct {
+	CancelFirst       bool     // Try pg_cancel_backend before terminating, even when idle in transaction
+	AllowApplications []string // When set, only sessions from these applications are terminated
+	AllowUsers        []string // When set, only sessions of these users are terminated
+	DenyApplications  []string
+	DenyUsers         []string
+}
+
+// DefaultTerminateSettings returns the settings used when nothing is configured.
+func DefaultTerminateSettings() TerminateSettings {
+	return TerminateSettings{
+		DenyApplications: []string{"*migrat*", "flyway*", "liquibase*"},
+	}
+}
+
 type TerminateQueryAction struct {
 	metadata *models.ActionMetadata
 	adapter  database.DatabaseAdapter
 	pid      int32
 	username string
+	query    string // Query text captured at detection time, used to detect recycled PIDs
 	graceful bool
+	settings TerminateSettings
 }
 
 func NewTerminateQueryAction(
@@ -22,14 +44,18 @@ func NewTerminateQueryAction(
 	adapter database.DatabaseAdapter,
 	pid int32,
 	username string,
+	query string,
 	graceful bool,
+	settings TerminateSettings,
 ) *TerminateQueryAction {
 	return &TerminateQueryAction{
 		metadata: metadata,
 		adapter:  adapter,
 		pid:      pid,
 		username: username,
+		query:    query,
 		graceful: graceful,
+		settings: settings,
 	}
 }
 
@@ -62,6 +88,7 @@ func (a *TerminateQueryAction) Execute(ctx context.Context) (*models.ActionResul
 			Status:          models.StatusFailed,
 			Message:         "Validation error",
 			Error:           err.Error(),
+			ErrorCode:       models.ClassifyValidationError(err),
 			CreatedAt:       a.metadata.CreatedAt,
 			Started:         &started,
 			ExecutionTimeMs: int64(time.Since(startTime).Milliseconds()),
@@ -69,35 +96,100 @@ func (a *TerminateQueryAction) Execute(ctx context.Context) (*models.ActionResul
 		}, nil
 	}
 
-	method := "pg_cancel_backend"
-	if !a.graceful {
-		method = "pg_terminate_backend"
+	// PIDs are recycled - make sure the backend is still the session that was detected
+	backend, staleReason, err := a.verifyBackend(ctx)
+	if err != nil {
+		return &models.ActionResult{
+			ActionID:        a.metadata.ActionID,
+			ActionType:      a.metadata.ActionType,
+			DatabaseID:      a.metadata.DatabaseID,
+			Status:          models.StatusFailed,
+			Message:         "Could not verify backend before termination",
+			Error:           err.Error(),
+			ErrorCode:       models.ClassifyError(err),
+			CreatedAt:       a.metadata.CreatedAt,
+			Started:         &started,
+			ExecutionTimeMs: int64(time.Since(startTime).Milliseconds()),
+			CanRollback:     false,
+		}, nil
 	}
 
-	err := a.adapter.TerminateQuery(ctx, a.pid, a.graceful)
-	if err != nil {
-		// If graceful failed, try forceful
-		if a.graceful {
-			err = a.adapter.TerminateQuery(ctx, a.pid, false)
-			if err == nil {
-				method = "pg_terminate_backend (fallback)"
-			}
+	if staleReason != "" {
+		completed := time.Now()
+		changes := map[string]interface{}{
+			"pid":        a.pid,
+			"username":   a.username,
+			"terminated": false,
+			"reason":     staleReason,
 		}
-
-		if err != nil {
-			return &models.ActionResult{
-				ActionID:        a.metadata.ActionID,
-				ActionType:      a.metadata.ActionType,
-				DatabaseID:      a.metadata.DatabaseID,
-				Status:          models.StatusFailed,
-				Message:         "Query termination failed",
-				Error:           err.Error(),
-				CreatedAt:       a.metadata.CreatedAt,
-				Started:         &started,
-				ExecutionTimeMs: int64(time.Since(startTime).Milliseconds()),
-				CanRollback:     false,
-			}, nil
+		if backend != nil {
+			changes["current_username"] = backend.Username
+			changes["current_query"] = backend.Query
+			changes["current_state"] = backend.State
 		}
+
+		return &models.ActionResult{
+			ActionID:        a.metadata.ActionID,
+			ActionType:      a.metadata.ActionType,
+			DatabaseID:      a.metadata.DatabaseID,
+			Status:          models.StatusCompleted,
+			Message:         fmt.Sprintf("Stale PID %d, nothing terminated: %s", a.pid, staleReason),
+			CreatedAt:       a.metadata.CreatedAt,
+			Started:         &started,
+			Completed:       &completed,
+			ExecutionTimeMs: int64(time.Since(startTime).Milliseconds()),
+			Changes:         changes,
+			CanRollback:     false,
+		}, nil
+	}
+
+	// Keep a record of the session, since terminating it loses its state for good
+	session := sessionState(backend)
+
+	if reason := a.protectedReason(backend); reason != "" {
+		completed := time.Now()
+		return &models.ActionResult{
+			ActionID:        a.metadata.ActionID,
+			ActionType:      a.metadata.ActionType,
+			DatabaseID:      a.metadata.DatabaseID,
+			Status:          models.StatusCompleted,
+			Message:         fmt.Sprintf("Termination refused for PID %d: %s", a.pid, reason),
+			CreatedAt:       a.metadata.CreatedAt,
+			Started:         &started,
+			Completed:       &completed,
+			ExecutionTimeMs: int64(time.Since(startTime).Milliseconds()),
+			Changes: map[string]interface{}{
+				"pid":        a.pid,
+				"username":   a.username,
+				"terminated": false,
+				"reason":     reason,
+				"session":    session,
+			},
+			CanRollback: false,
+		}, nil
+	}
+
+	method, err := a.terminate(ctx)
+	if err != nil {
+		return &models.ActionResult{
+			ActionID:        a.metadata.ActionID,
+			ActionType:      a.metadata.ActionType,
+			DatabaseID:      a.metadata.DatabaseID,
+			Status:          models.StatusFailed,
+			Message:         "Query termination failed",
+			Error:           err.Error(),
+			ErrorCode:       models.ClassifyError(err),
+			CreatedAt:       a.metadata.CreatedAt,
+			Started:         &started,
+			ExecutionTimeMs: int64(time.Since(startTime).Milliseconds()),
+			Changes: map[string]interface{}{
+				"pid":        a.pid,
+				"username":   a.username,
+				"terminated": false,
+				"session":    session,
+			},
+			CanRollback: false,
+		}, nil
 	}
 
 	completed := time.Now()
@@ -113,15 +205,130 @@ func (a *TerminateQueryAction) Execute(ctx context.Context) (*models.ActionResul
 		Completed:       &completed,
 		ExecutionTimeMs: int64(time.Since(startTime).Milliseconds()),
 		Changes: map[string]interface{}{
-			"pid":      a.pid,
-			"username": a.username,
-			"method":   method,
-			"graceful": a.graceful,
+			"pid":        a.pid,
+			"username":   a.username,
+			"method":     method,
+			"graceful":   a.graceful,
+			"terminated": true,
+			"session":    session,
 		},
 		CanRollback: false, // Cannot un-terminate a query
 	}, nil
 }
 
+// terminate ends the session's work and returns the method that did it. A graceful action
+// stops at a successful cancel. With CancelFirst, a non-graceful action cancels first and
+// terminates only if the session is still idle in transaction, since cancelling does not
+// end an idle transaction.
+func (a *TerminateQueryAction) terminate(ctx context.Context) (string, error) {
+	if !a.graceful && !a.settings.CancelFirst {
+		if err := a.adapter.TerminateQuery(ctx, a.pid, false); err != nil {
+			return "", err
+		}
+		return "pg_terminate_backend", nil
+	}
+
+	if err := a.adapter.TerminateQuery(ctx, a.pid, true); err == nil {
+		if a.graceful || a.sessionEnded(ctx) {
+			return "pg_cancel_backend", nil
+		}
+	}
+
+	if err := a.adapter.TerminateQuery(ctx, a.pid, false); err != nil {
+		return "", err
+	}
+	return "pg_terminate_backend (fallback)", nil
+}
+
+// sessionEnded reports whether the backend is gone or no longer idle in transaction.
+func (a *TerminateQueryAction) sessionEnded(ctx context.Context) bool {
+	backend, err := a.adapter.GetBackendInfo(ctx, a.pid)
+	if err != nil {
+		return errors.Is(err, database.ErrBackendNotFound)
+	}
+	return !strings.HasPrefix(backend.State, "idle in transaction")
+}
+
+// protectedReason returns why the session must not be terminated, or "" if it may be.
+func (a *TerminateQueryAction) protectedReason(backend *database.BackendInfo) string {
+	if backend.IsSuperuser {
+		return fmt.Sprintf("session belongs to superuser '%s'", backend.Username)
+	}
+
+	if backend.ApplicationName != "" && matchesAnyPattern(backend.ApplicationName, a.settings.DenyApplications) {
+		return fmt.Sprintf("application '%s' is on the denylist", backend.ApplicationName)
+	}
+	if matchesAnyPattern(backend.Username, a.settings.DenyUsers) {
+		return fmt.Sprintf("user '%s' is on the denylist", backend.Username)
+	}
+
+	if len(a.settings.AllowApplications) > 0 && !matchesAnyPattern(backend.ApplicationName, a.settings.AllowApplications) {
+		return fmt.Sprintf("application '%s' is not on the allowlist", backend.ApplicationName)
+	}
+	if len(a.settings.AllowUsers) > 0 && !matchesAnyPattern(backend.Username, a.settings.AllowUsers) {
+		return fmt.Sprintf("user '%s' is not on the allowlist", backend.Username)
+	}
+
+	return ""
+}
+
+// sessionState captures what the backend was doing for the action's Changes.
+func sessionState(backend *database.BackendInfo) map[string]interface{} {
+	state := map[string]interface{}{
+		"username":         backend.Username,
+		"state":            backend.State,
+		"query":            backend.Query,
+		"application_name": backend.ApplicationName,
+		"client_addr":      backend.ClientAddr,
+		"is_superuser":     backend.IsSuperuser,
+	}
+	if backend.XactStart != nil {
+		state["xact_start"] = backend.XactStart.UTC().Format(time.RFC3339)
+	}
+	return state
+}
+
+// matchesAnyPattern reports whether value matches one of the case-insensitive glob patterns.
+func matchesAnyPattern(value string, patterns []string) bool {
+	value = strings.ToLower(value)
+	for _, pattern := range patterns {
+		if matched, err := path.Match(strings.ToLower(pattern), value); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyBackend compares what the PID is currently doing against the detection.
+// Returns a non-empty reason if the PID no longer belongs to the detected session.
+func (a *TerminateQueryAction) verifyBackend(ctx context.Context) (*database.BackendInfo, string, error) {
+	backend, err := a.adapter.GetBackendInfo(ctx, a.pid)
+	if err != nil {
+		if errors.Is(err, database.ErrBackendNotFound) {
+			return nil, "backend no longer exists", nil
+		}
+		return nil, "", err
+	}
+
+	if a.username != "" && a.username != "unknown" && backend.Username != a.username {
+		return backend, fmt.Sprintf("PID now belongs to user '%s', expected '%s'", backend.Username, a.username), nil
+	}
+
+	if a.query != "" && !queryMatches(a.query, backend.Query) {
+		return backend, "PID is running a different query than the one detected", nil
+	}
+
+	return backend, "", nil
+}
+
+// queryMatches compares query text, allowing for the collector truncating captured queries.
+func queryMatches(expected, current string) bool {
+	expected = strings.Join(strings.Fields(expected), " ")
+	current = strings.Join(strings.Fields(current), " ")
+
+	return strings.HasPrefix(current, expected)
+}
+
 func (a *TerminateQueryAction) Rollback(ctx context.Context) error {
 	// Cannot rollback query termination
 	return nil
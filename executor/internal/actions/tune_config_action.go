@@ -4,20 +4,31 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
+	"math"
 	"strings"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/database"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
 )
 
+// work_mem is sized to fit the average temp file spill, within these bounds (in kB). It is
+// allowed per sort or hash in every connection, so the ceiling stays far below RAM.
+const (
+	defaultWorkMemKB = 16 * 1024
+	maxWorkMemKB     = 256 * 1024
+)
+
 type TuneConfigAction struct {
 	actionID       string
 	detectionID    string
 	databaseID     string
 	databaseType   string
 	adapter        database.DatabaseAdapter
+	avgSpillBytes  float64 // Average temp file written per spill; 0 without spill evidence
 	originalConfig map[string]string
 	appliedChanges map[string]string
+	setAttempted   bool // SetConfig was called, so a failure may have applied some changes
 }
 
 // NewTuneConfigAction creates a new config tuning action with injected adapter
@@ -43,10 +54,16 @@ func NewTuneConfigAction(
 	}, nil
 }
 
+// SetTempSpill sizes work_mem from the average temp file queries wrote when they spilled,
+// instead of the fixed bump from the default.
+func (a *TuneConfigAction) SetTempSpill(avgSpillBytes float64) {
+	a.avgSpillBytes = avgSpillBytes
+}
+
 func (a *TuneConfigAction) Execute(ctx context.Context) (*models.ActionResult, error) {
 	log.Printf("Tuning configuration for database: %s", a.databaseID)
 
-	// Parameters to tune (PostgreSQL-specific for now)
+	// Parameters to tune, by their Postgres names; other adapters translate or skip them
 	parameters := []string{"work_mem", "effective_cache_size", "random_page_cost"}
 
 	// 1. Get current configuration
@@ -64,6 +81,7 @@ func (a *TuneConfigAction) Execute(ctx context.Context) (*models.ActionResult, e
 	// 3. Apply configuration changes (only if there are changes)
 	changesMade := len(newConfig) > 0
 	if changesMade {
+		a.setAttempted = true
 		err = a.adapter.SetConfig(ctx, newConfig)
 		if err != nil {
 			return nil, fmt.Errorf("failed to apply config changes: %w", err)
@@ -78,12 +96,12 @@ func (a *TuneConfigAction) Execute(ctx context.Context) (*models.ActionResult, e
 	// 4. Get slow queries for educational component
 	slowQueries, err := a.adapter.GetSlowQueries(ctx, 500.0, 5)
 	if err != nil {
-		log.Printf("Warning: failed to retrieve slow queries: %v", err)
+		slog.Warn("Failed to retrieve slow queries", "error", err)
 		slowQueries = []database.SlowQuery{}
 	}
 
 	// 5. Build optimization guide
-	guide := a.getOptimizationGuide()
+	guide := optimizationGuide(a.databaseType)
 
 	// 6. Build result
 	changes := map[string]interface{}{
@@ -93,6 +111,9 @@ func (a *TuneConfigAction) Execute(ctx context.Context) (*models.ActionResult, e
 		"optimization_guide": guide,
 		"database_type":      a.databaseType,
 	}
+	if a.avgSpillBytes > 0 {
+		changes["avg_spill_bytes"] = a.avgSpillBytes
+	}
 
 	// Build message based on whether changes were made
 	var message string
@@ -130,6 +151,74 @@ func (a *TuneConfigAction) Rollback(ctx context.Context) error {
 	return nil
 }
 
+// PrepareRetry puts back the original configuration after a failed SetConfig, so the retry
+// reads and records the real original instead of a half-applied one.
+func (a *TuneConfigAction) PrepareRetry(ctx context.Context) error {
+	if !a.setAttempted || a.originalConfig == nil {
+		return nil
+	}
+
+	if err := a.adapter.SetConfig(ctx, a.originalConfig); err != nil {
+		return fmt.Errorf("failed to restore original config: %w", err)
+	}
+
+	a.setAttempted = false
+	a.appliedChanges = nil
+	return nil
+}
+
+// Restore re-attaches to config changes applied before an Executor restart.
+// Returns false if the applied values have since been changed by someone else.
+func (a *TuneConfigAction) Restore(ctx context.Context, changes map[string]interface{}) (bool, error) {
+	original := toStringMap(changes["original_config"])
+	applied := toStringMap(changes["config_changes"])
+
+	if len(original) == 0 || len(applied) == 0 {
+		return false, fmt.Errorf("recorded changes missing original_config or config_changes")
+	}
+
+	parameters := make([]string, 0, len(applied))
+	for param := range applied {
+		parameters = append(parameters, param)
+	}
+
+	current, err := a.adapter.GetCurrentConfig(ctx, parameters)
+	if err != nil {
+		return false, fmt.Errorf("failed to get current config: %w", err)
+	}
+
+	for param, value := range applied {
+		if current[param] != value {
+			return false, nil
+		}
+	}
+
+	a.originalConfig = original
+	a.appliedChanges = applied
+
+	return true, nil
+}
+
+// toStringMap converts a decoded JSON object into a string map.
+func toStringMap(value interface{}) map[string]string {
+	result := make(map[string]string)
+
+	switch m := value.(type) {
+	case map[string]string:
+		for k, v := range m {
+			result[k] = v
+		}
+	case map[string]interface{}:
+		for k, v := range m {
+			if str, ok := v.(string); ok {
+				result[k] = str
+			}
+		}
+	}
+
+	return result
+}
+
 func (a *TuneConfigAction) Validate(ctx context.Context) error {
 	caps := a.adapter.GetCapabilities()
 
@@ -157,9 +246,8 @@ func (a *TuneConfigAction) GetMetadata() *models.ActionMetadata {
 func (a *TuneConfigAction) calculateOptimalConfig(current map[string]string) map[string]string {
 	optimal := make(map[string]string)
 
-	// work_mem: Increase from default 4MB to 16MB
-	if currentVal := current["work_mem"]; currentVal == "4MB" || currentVal == "4096kB" {
-		optimal["work_mem"] = "16MB"
+	if workMem, ok := a.optimalWorkMem(current["work_mem"]); ok {
+		optimal["work_mem"] = workMem
 	}
 
 	// effective_cache_size: Increase if currently at default (4GB or less)
@@ -179,8 +267,39 @@ func (a *TuneConfigAction) calculateOptimalConfig(current map[string]string) map
 	return optimal
 }
 
-// Helper: Get database-specific optimization guide
-func (a *TuneConfigAction) getOptimizationGuide() map[string]interface{} {
+// optimalWorkMem returns the work_mem to set, if it should change. With spill evidence,
+// work_mem grows by the average spill - what the spilling sort or hash needed beyond it -
+// rounded up to a power of two; without, it is only raised from the 4MB default to 16MB.
+func (a *TuneConfigAction) optimalWorkMem(currentVal string) (string, bool) {
+	if a.avgSpillBytes <= 0 {
+		if currentVal == "4MB" || currentVal == "4096kB" {
+			return formatMemoryKB(defaultWorkMemKB), true
+		}
+		return "", false
+	}
+
+	currentKB, err := parseMemoryKB(currentVal)
+	if err != nil {
+		slog.Warn("Cannot size work_mem", "work_mem", currentVal, "error", err)
+		return "", false
+	}
+
+	neededKB := currentKB + int64(math.Ceil(a.avgSpillBytes/1024))
+	targetKB := int64(1024)
+	for targetKB < neededKB && targetKB < maxWorkMemKB {
+		targetKB *= 2
+	}
+	targetKB = min(targetKB, maxWorkMemKB)
+
+	if targetKB <= currentKB {
+		return "", false
+	}
+
+	return formatMemoryKB(targetKB), true
+}
+
+// optimizationGuide returns the query optimization guide for a database type.
+func optimizationGuide(databaseType string) map[string]interface{} {
 	guides := map[string]map[string]interface{}{
 		"postgres": {
 			"title":  "PostgreSQL Query Optimization Guide",
@@ -205,7 +324,7 @@ func (a *TuneConfigAction) getOptimizationGuide() map[string]interface{} {
 		},
 	}
 
-	guide, exists := guides[a.databaseType]
+	guide, exists := guides[databaseType]
 	if !exists {
 		return guides["postgres"]
 	}
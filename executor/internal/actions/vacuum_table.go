@@ -3,27 +3,66 @@ package actions
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/database"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
 )
 
+// Vacuum modes, chosen by the TableBloatDetector from the bloat ratio and table size.
+const (
+	// VacuumModeAnalyze runs VACUUM ANALYZE, marking dead tuples reusable without blocking.
+	VacuumModeAnalyze = "analyze"
+	// VacuumModeFull rewrites the table with VACUUM FULL, returning space to the OS. It
+	// holds an exclusive lock throughout, so it only runs inside the maintenance window.
+	VacuumModeFull = "full"
+	// VacuumModeTuneAutovacuum lowers the table's autovacuum_vacuum_scale_factor so
+	// autovacuum keeps up with it, rather than cleaning up once.
+	VacuumModeTuneAutovacuum = "tune_autovacuum"
+)
+
+// autovacuumScaleFactorOption is the storage parameter tune_autovacuum sets.
+const autovacuumScaleFactorOption = "autovacuum_vacuum_scale_factor"
+
+// DefaultAutovacuumScaleFactor is used when tune_autovacuum is requested without a value.
+const DefaultAutovacuumScaleFactor = 0.05
+
+// VacuumOptions selects how VacuumTableAction deals with a bloated table.
+type VacuumOptions struct {
+	Mode                  string  // One of the VacuumMode constants; empty means analyze
+	AutovacuumScaleFactor float64 // For tune_autovacuum; 0 uses DefaultAutovacuumScaleFactor
+}
+
 type VacuumTableAction struct {
 	metadata  *models.ActionMetadata
 	adapter   database.DatabaseAdapter
 	tableName string
+	options   VacuumOptions
+
+	// tune_autovacuum state, for Rollback
+	previousScaleFactor string // "" if the table used the server default
+	tuned               bool
 }
 
 func NewVacuumTableAction(
 	metadata *models.ActionMetadata,
 	adapter database.DatabaseAdapter,
 	tableName string,
+	options VacuumOptions,
 ) *VacuumTableAction {
+	if options.Mode == "" {
+		options.Mode = VacuumModeAnalyze
+	}
+	if options.AutovacuumScaleFactor <= 0 {
+		options.AutovacuumScaleFactor = DefaultAutovacuumScaleFactor
+	}
+
 	return &VacuumTableAction{
 		metadata:  metadata,
 		adapter:   adapter,
 		tableName: tableName,
+		options:   options,
 	}
 }
 
@@ -31,6 +70,17 @@ func (a *VacuumTableAction) GetMetadata() *models.ActionMetadata {
 	return a.metadata
 }
 
+// Mode returns the vacuum mode the action runs.
+func (a *VacuumTableAction) Mode() string {
+	return a.options.Mode
+}
+
+// RequiresMaintenanceWindow reports whether the action locks the table for long enough
+// that it must wait for the maintenance window, whatever its severity.
+func (a *VacuumTableAction) RequiresMaintenanceWindow() bool {
+	return a.options.Mode == VacuumModeFull
+}
+
 func (a *VacuumTableAction) Validate(ctx context.Context) error {
 	caps := a.adapter.GetCapabilities()
 	if !caps.SupportsVacuum {
@@ -41,6 +91,20 @@ func (a *VacuumTableAction) Validate(ctx context.Context) error {
 		return fmt.Errorf("table name is required")
 	}
 
+	switch a.options.Mode {
+	case VacuumModeAnalyze:
+	case VacuumModeFull, VacuumModeTuneAutovacuum:
+		if _, ok := a.adapter.(database.TableMaintenanceManager); !ok {
+			return database.ErrActionNotSupported
+		}
+	default:
+		return fmt.Errorf("unknown vacuum mode %q", a.options.Mode)
+	}
+
+	if a.options.Mode == VacuumModeTuneAutovacuum && a.options.AutovacuumScaleFactor > 1 {
+		return fmt.Errorf("autovacuum scale factor must be between 0 and 1, got %g", a.options.AutovacuumScaleFactor)
+	}
+
 	return nil
 }
 
@@ -48,21 +112,68 @@ func (a *VacuumTableAction) Execute(ctx context.Context) (*models.ActionResult,
 	startTime := time.Now()
 	started := time.Now()
 
-	if err := a.Validate(ctx); err != nil {
+	failed := func(message string, err error) *models.ActionResult {
 		return &models.ActionResult{
 			ActionID:        a.metadata.ActionID,
 			ActionType:      a.metadata.ActionType,
 			DatabaseID:      a.metadata.DatabaseID,
 			Status:          models.StatusFailed,
-			Message:         "Validation error",
+			Message:         message,
 			Error:           err.Error(),
+			ErrorCode:       models.ClassifyError(err),
 			CreatedAt:       a.metadata.CreatedAt,
 			Started:         &started,
 			ExecutionTimeMs: int64(time.Since(startTime).Milliseconds()),
 			CanRollback:     false,
-		}, nil
+		}
+	}
+
+	if err := a.Validate(ctx); err != nil {
+		result := failed("Validation error", err)
+		result.ErrorCode = models.ClassifyValidationError(err)
+		return result, nil
+	}
+
+	var message string
+	var changes map[string]interface{}
+	var err error
+
+	switch a.options.Mode {
+	case VacuumModeFull:
+		message, changes, err = a.vacuumFull(ctx)
+		if err != nil {
+			return failed("VACUUM FULL failed", err), nil
+		}
+	case VacuumModeTuneAutovacuum:
+		message, changes, err = a.tuneAutovacuum(ctx)
+		if err != nil {
+			return failed("Autovacuum tuning failed", err), nil
+		}
+	default:
+		message, changes, err = a.vacuumAnalyze(ctx)
+		if err != nil {
+			return failed("VACUUM failed", err), nil
+		}
 	}
 
+	completed := time.Now()
+
+	return &models.ActionResult{
+		ActionID:        a.metadata.ActionID,
+		ActionType:      a.metadata.ActionType,
+		DatabaseID:      a.metadata.DatabaseID,
+		Status:          models.StatusCompleted,
+		Message:         message,
+		CreatedAt:       a.metadata.CreatedAt,
+		Started:         &started,
+		Completed:       &completed,
+		ExecutionTimeMs: int64(time.Since(startTime).Milliseconds()),
+		Changes:         changes,
+		CanRollback:     a.tuned, // VACUUM is non-reversible (but also non-destructive)
+	}, nil
+}
+
+func (a *VacuumTableAction) vacuumAnalyze(ctx context.Context) (string, map[string]interface{}, error) {
 	// Get bloat stats before vacuum
 	deadTuplesBefore, err := a.adapter.GetDeadTuples(ctx, a.tableName)
 	if err != nil {
@@ -70,21 +181,8 @@ func (a *VacuumTableAction) Execute(ctx context.Context) (*models.ActionResult,
 		deadTuplesBefore = -1
 	}
 
-	// Execute VACUUM ANALYZE
-	err = a.adapter.VacuumTable(ctx, a.tableName)
-	if err != nil {
-		return &models.ActionResult{
-			ActionID:        a.metadata.ActionID,
-			ActionType:      a.metadata.ActionType,
-			DatabaseID:      a.metadata.DatabaseID,
-			Status:          models.StatusFailed,
-			Message:         "VACUUM failed",
-			Error:           err.Error(),
-			CreatedAt:       a.metadata.CreatedAt,
-			Started:         &started,
-			ExecutionTimeMs: int64(time.Since(startTime).Milliseconds()),
-			CanRollback:     false,
-		}, nil
+	if err := a.adapter.VacuumTable(ctx, a.tableName); err != nil {
+		return "", nil, err
 	}
 
 	// Get bloat stats after vacuum
@@ -93,40 +191,168 @@ func (a *VacuumTableAction) Execute(ctx context.Context) (*models.ActionResult,
 		deadTuplesAfter = -1
 	}
 
-	completed := time.Now()
-
 	changes := map[string]interface{}{
 		"table_name": a.tableName,
+		"mode":       VacuumModeAnalyze,
 		"operation":  "VACUUM ANALYZE",
 	}
+	recordTupleStats(changes, deadTuplesBefore, deadTuplesAfter)
+
+	return fmt.Sprintf("VACUUM ANALYZE completed on table '%s'", a.tableName), changes, nil
+}
+
+func (a *VacuumTableAction) vacuumFull(ctx context.Context) (string, map[string]interface{}, error) {
+	manager := a.adapter.(database.TableMaintenanceManager)
 
-	if deadTuplesBefore >= 0 {
-		changes["dead_tuples_before"] = deadTuplesBefore
+	deadTuplesBefore, err := a.adapter.GetDeadTuples(ctx, a.tableName)
+	if err != nil {
+		deadTuplesBefore = -1
 	}
-	if deadTuplesAfter >= 0 {
-		changes["dead_tuples_after"] = deadTuplesAfter
+	sizeBefore, err := manager.GetTableSize(ctx, a.tableName)
+	if err != nil {
+		sizeBefore = -1
 	}
-	if deadTuplesBefore >= 0 && deadTuplesAfter >= 0 {
-		changes["tuples_reclaimed"] = deadTuplesBefore - deadTuplesAfter
+
+	if err := manager.VacuumTableFull(ctx, a.tableName); err != nil {
+		return "", nil, err
 	}
 
-	return &models.ActionResult{
-		ActionID:        a.metadata.ActionID,
-		ActionType:      a.metadata.ActionType,
-		DatabaseID:      a.metadata.DatabaseID,
-		Status:          models.StatusCompleted,
-		Message:         fmt.Sprintf("VACUUM ANALYZE completed on table '%s'", a.tableName),
-		CreatedAt:       a.metadata.CreatedAt,
-		Started:         &started,
-		Completed:       &completed,
-		ExecutionTimeMs: int64(time.Since(startTime).Milliseconds()),
-		Changes:         changes,
-		CanRollback:     false, // VACUUM is non-reversible (but also non-destructive)
-	}, nil
+	deadTuplesAfter, err := a.adapter.GetDeadTuples(ctx, a.tableName)
+	if err != nil {
+		deadTuplesAfter = -1
+	}
+	sizeAfter, err := manager.GetTableSize(ctx, a.tableName)
+	if err != nil {
+		sizeAfter = -1
+	}
+
+	warning := fmt.Sprintf("VACUUM FULL rewrote table '%s' under an ACCESS EXCLUSIVE lock: reads and writes "+
+		"on it were blocked until it finished, and the rewrite needed free disk space for a full copy", a.tableName)
+	if sizeBefore >= 0 {
+		warning += fmt.Sprintf(" (up to %d bytes)", sizeBefore)
+	}
+
+	changes := map[string]interface{}{
+		"table_name": a.tableName,
+		"mode":       VacuumModeFull,
+		"operation":  "VACUUM FULL ANALYZE",
+		"warning":    warning,
+	}
+	recordTupleStats(changes, deadTuplesBefore, deadTuplesAfter)
+
+	if sizeBefore >= 0 {
+		changes["size_bytes_before"] = sizeBefore
+	}
+	if sizeAfter >= 0 {
+		changes["size_bytes_after"] = sizeAfter
+	}
+	if sizeBefore >= 0 && sizeAfter >= 0 {
+		changes["bytes_reclaimed"] = sizeBefore - sizeAfter
+	}
+
+	return fmt.Sprintf("VACUUM FULL completed on table '%s'", a.tableName), changes, nil
+}
+
+func (a *VacuumTableAction) tuneAutovacuum(ctx context.Context) (string, map[string]interface{}, error) {
+	manager := a.adapter.(database.TableMaintenanceManager)
+
+	current, err := manager.GetTableOptions(ctx, a.tableName, []string{autovacuumScaleFactorOption})
+	if err != nil {
+		return "", nil, err
+	}
+	previous := current[autovacuumScaleFactorOption]
+
+	value := strconv.FormatFloat(a.options.AutovacuumScaleFactor, 'f', -1, 64)
+	if err := manager.SetTableOptions(ctx, a.tableName, map[string]string{autovacuumScaleFactorOption: value}); err != nil {
+		return "", nil, err
+	}
+
+	a.previousScaleFactor = previous
+	a.tuned = true
+
+	changes := map[string]interface{}{
+		"table_name":                a.tableName,
+		"mode":                      VacuumModeTuneAutovacuum,
+		"operation":                 fmt.Sprintf("ALTER TABLE %s SET (%s = %s)", a.tableName, autovacuumScaleFactorOption, value),
+		autovacuumScaleFactorOption: value,
+		"previous_" + autovacuumScaleFactorOption: previous, // "" means the server default
+	}
+
+	return fmt.Sprintf("Set %s = %s on table '%s'; autovacuum will clean it up on its next run",
+		autovacuumScaleFactorOption, value, a.tableName), changes, nil
+}
+
+// recordTupleStats adds dead tuple counts to changes, skipping any that couldn't be read (-1).
+func recordTupleStats(changes map[string]interface{}, before, after int64) {
+	if before >= 0 {
+		changes["dead_tuples_before"] = before
+	}
+	if after >= 0 {
+		changes["dead_tuples_after"] = after
+	}
+	if before >= 0 && after >= 0 {
+		changes["tuples_reclaimed"] = before - after
+	}
 }
 
+// Rollback restores the table's previous autovacuum_vacuum_scale_factor after
+// tune_autovacuum. VACUUM itself cannot be rolled back, but it's also non-destructive
+// so no action is needed for the other modes.
 func (a *VacuumTableAction) Rollback(ctx context.Context) error {
-	// VACUUM cannot be rolled back, but it's also non-destructive
-	// so no action needed
+	if !a.tuned {
+		return nil
+	}
+
+	manager, ok := a.adapter.(database.TableMaintenanceManager)
+	if !ok {
+		return database.ErrActionNotSupported
+	}
+
+	var err error
+	if a.previousScaleFactor == "" {
+		err = manager.ResetTableOptions(ctx, a.tableName, []string{autovacuumScaleFactorOption})
+	} else {
+		err = manager.SetTableOptions(ctx, a.tableName, map[string]string{autovacuumScaleFactorOption: a.previousScaleFactor})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to restore %s: %w", autovacuumScaleFactorOption, err)
+	}
+
+	a.tuned = false
 	return nil
 }
+
+// Restore re-attaches to autovacuum tuning applied before an Executor restart. There is
+// nothing to roll back if the table's setting has since been changed again.
+func (a *VacuumTableAction) Restore(ctx context.Context, changes map[string]interface{}) (bool, error) {
+	mode, _ := changes["mode"].(string)
+	if mode != VacuumModeTuneAutovacuum {
+		return false, nil
+	}
+
+	tableName, _ := changes["table_name"].(string)
+	applied, _ := changes[autovacuumScaleFactorOption].(string)
+	if tableName == "" || applied == "" {
+		return false, fmt.Errorf("recorded changes missing table_name or %s", autovacuumScaleFactorOption)
+	}
+
+	manager, ok := a.adapter.(database.TableMaintenanceManager)
+	if !ok {
+		return false, database.ErrActionNotSupported
+	}
+
+	current, err := manager.GetTableOptions(ctx, tableName, []string{autovacuumScaleFactorOption})
+	if err != nil {
+		return false, err
+	}
+	if current[autovacuumScaleFactorOption] != applied {
+		return false, nil
+	}
+
+	a.tableName = tableName
+	a.options.Mode = VacuumModeTuneAutovacuum
+	a.previousScaleFactor, _ = changes["previous_"+autovacuumScaleFactorOption].(string)
+	a.tuned = true
+
+	return true, nil
+}
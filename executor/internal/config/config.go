@@ -2,10 +2,15 @@ package config
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
+	"path"
+	"strings"
 
-	"github.com/joho/godotenv"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/docker"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/logging"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/transport"
 )
 
 // Config holds all configuration for the Executor service.
@@ -17,37 +22,111 @@ type Config struct {
 	NatsURL          string
 	KnowledgeAddress string
 
+	// Mutual TLS for gRPC links (GRPC_TLS_*); plaintext when unset
+	GRPCTLS transport.TLSConfig
+
+	// Log verbosity (LOG_LEVEL: debug, info, warn, error)
+	LogLevel slog.Level
+
+	// Bearer token required by the HTTP API; empty leaves it open
+	APIToken string
+
+	// Use JetStream durable consumers (false falls back to core NATS)
+	NatsJetStream bool
+
 	// Action execution settings
 	MaxConcurrentActions int
 	ActionTimeout        int // seconds
+	ShutdownGracePeriod  int // seconds to let running actions finish on shutdown
+
+	// Retries of actions failing with a transient error code (timeout, conflict, dependency unavailable)
+	ActionMaxAttempts  int // executions in total, the first included; 1 disables retries
+	ActionRetryBackoff int // seconds before the first retry, doubled for each one after
+
+	// Per-database rate limiting of mutating actions
+	ActionCooldown    int // seconds between mutating actions on the same database (0 disables)
+	MaxActionsPerHour int // mutating actions per database per hour (0 disables)
+
+	// Maintenance window for mutating actions, e.g. "02:00-05:00"; empty runs them anytime
+	MaintenanceWindow           string
+	MaintenanceTimezone         string   // IANA timezone the window is in
+	MaintenanceBypassSeverities []string // detection severities that run outside the window
+
+	// Chat notifications (Slack-compatible incoming webhook); empty URL disables them
+	NotifyWebhookURL    string
+	NotifyEvents        []string // event types to post, e.g. "action.failed"
+	NotifyRatePerMinute int      // messages per minute before the rest are dropped (0 disables)
+
+	// PgBouncer deployment defaults (detection metadata can override)
+	PgBouncerHostPort        int
+	PgBouncerPoolMode        string
+	PgBouncerDefaultPoolSize int
+	PgBouncerMaxClientConn   int
+	PgBouncerImage           string
+
+	// MongoDB profiler threshold set when the Executor connects, so slow operations are
+	// recorded in system.profile (0 leaves the profiler as it is)
+	MongoProfileSlowMs int
+
+	// How long a database's policy is used before it is re-read from Knowledge
+	PolicyCacheSeconds int
+
+	// Docker engine used by container-backed actions; empty host means the local socket.
+	// Read from the standard DOCKER_HOST, DOCKER_TLS_VERIFY and DOCKER_CERT_PATH variables
+	DockerHost      string
+	DockerTLSVerify bool
+	DockerCertPath  string // directory holding ca.pem, cert.pem and key.pem
+
+	// Readiness probes run against deployed containers (Redis, PgBouncer)
+	ContainerProbeHost    string // host the Executor dials to reach published ports
+	ContainerProbeTimeout int    // seconds to keep retrying before the action fails
+
+	// Memory and CPU limits for deployed containers, as docker run's --memory and --cpus
+	// take them ("512m", "0.5"); empty or "0" leaves the resource unlimited
+	RedisMemoryLimit     string
+	RedisCPULimit        string
+	PgBouncerMemoryLimit string
+	PgBouncerCPULimit    string
+
+	// Watchdog over deployed containers: a container that restarts repeatedly marks its
+	// action regressed and, with ContainerWatchdogRollback, rolls it back
+	ContainerWatchdogInterval int // seconds between checks (0 disables the watchdog)
+	ContainerRestartThreshold int // restarts since deployment that count as a regression
+	ContainerWatchdogRollback bool
+
+	// Sessions terminate_query may end, as glob patterns; superuser sessions are always refused
+	TerminateCancelFirst       bool     // try pg_cancel_backend before terminating idle transactions
+	TerminateAllowApplications []string // when set, only these applications are terminated
+	TerminateAllowUsers        []string // when set, only these users are terminated
+	TerminateDenyApplications  []string
+	TerminateDenyUsers         []string
+
+	// Scans of an action's index since it was created that make dropping it on rollback
+	// unsafe without force (0 disables the check)
+	RollbackIndexScanThreshold int
 
 	// Feature flags
 	EnableAutoExecution bool
+
+	// Per-action-type approval overrides, e.g. vacuum_table=auto,create_index=approval
+	ApprovalOverrides map[string]string
+
+	// Behaviour per detection severity, e.g. info=auto,warning=auto,critical=approve
+	ExecutionPolicy map[string]string
 }
 
+// DefaultExecutionPolicy runs info and warning fixes automatically and holds critical ones
+// for a human.
+const DefaultExecutionPolicy = "info=auto,warning=auto,critical=approve"
+
 // Load reads configuration from environment variables and .env file.
 func Load() (*Config, error) {
-	// Try multiple .env locations
-	envPaths := []string{
-		".env",
-		"../.env",
-		"../../.env", // Executor is nested deeper
-		"/app/.env",  // Docker
-	}
-
-	envLoaded := false
-	for _, path := range envPaths {
-		if err := godotenv.Load(path); err == nil {
-			log.Printf("Loaded config from: %s", path)
-			envLoaded = true
-			break
-		}
-	}
-
-	if !envLoaded {
-		log.Printf("No .env file found, using environment variables")
-	}
+	loadEnvFile()
+	return fromEnv()
+}
 
+// fromEnv builds and validates a Config from the current environment.
+func fromEnv() (*Config, error) {
 	config := &Config{
 		// Service addresses with defaults
 		GRPCPort:         getEnvOrDefault("GRPC_PORT", "50052"),
@@ -55,15 +134,96 @@ func Load() (*Config, error) {
 		HealthPort:       getEnvOrDefault("HEALTH_PORT", "8082"),
 		NatsURL:          getEnvOrDefault("NATS_URL", "nats://localhost:4222"),
 		KnowledgeAddress: getEnvOrDefault("KNOWLEDGE_ADDRESS", "localhost:50053"),
+		GRPCTLS:          transport.TLSConfigFromEnv(),
+		APIToken:         os.Getenv("EXECUTOR_API_TOKEN"),
+		NatsJetStream:    getEnvOrDefault("NATS_JETSTREAM", "true") == "true",
 
 		// Action execution settings
 		MaxConcurrentActions: parseIntOrDefault("MAX_CONCURRENT_ACTIONS", 10),
 		ActionTimeout:        parseIntOrDefault("ACTION_TIMEOUT_SECONDS", 300), // 5 minutes
+		ShutdownGracePeriod:  parseIntOrDefault("SHUTDOWN_GRACE_PERIOD_SECONDS", 30),
+		ActionMaxAttempts:    parseIntOrDefault("ACTION_MAX_ATTEMPTS", 3),
+		ActionRetryBackoff:   parseIntOrDefault("ACTION_RETRY_BACKOFF_SECONDS", 30),
+
+		// Per-database rate limiting
+		ActionCooldown:    parseIntOrDefault("ACTION_COOLDOWN_SECONDS", 120), // 2 minutes
+		MaxActionsPerHour: parseIntOrDefault("MAX_ACTIONS_PER_HOUR", 10),
+
+		// Maintenance window
+		MaintenanceWindow:           strings.TrimSpace(os.Getenv("MAINTENANCE_WINDOW")),
+		MaintenanceTimezone:         getEnvOrDefault("MAINTENANCE_TIMEZONE", "UTC"),
+		MaintenanceBypassSeverities: parseList(getEnvOrDefault("MAINTENANCE_BYPASS_SEVERITIES", "critical")),
+
+		// Chat notifications
+		NotifyWebhookURL:    os.Getenv("NOTIFY_WEBHOOK_URL"),
+		NotifyEvents:        parseList(getEnvOrDefault("NOTIFY_EVENTS", "detection.critical,action.completed,action.failed,action.rolledback,action.suggested,action.regressed")),
+		NotifyRatePerMinute: parseIntOrDefault("NOTIFY_RATE_LIMIT_PER_MINUTE", 10),
+
+		// PgBouncer deployment defaults
+		PgBouncerHostPort:        parseIntOrDefault("PGBOUNCER_HOST_PORT", 6432),
+		PgBouncerPoolMode:        getEnvOrDefault("PGBOUNCER_POOL_MODE", "transaction"),
+		PgBouncerDefaultPoolSize: parseIntOrDefault("PGBOUNCER_DEFAULT_POOL_SIZE", 20),
+		PgBouncerMaxClientConn:   parseIntOrDefault("PGBOUNCER_MAX_CLIENT_CONN", 100),
+		PgBouncerImage:           getEnvOrDefault("PGBOUNCER_IMAGE", "pgbouncer/pgbouncer:latest"),
+
+		// MongoDB profiling
+		MongoProfileSlowMs: parseIntOrDefault("MONGO_PROFILE_SLOWMS", 100),
+
+		// Database policies
+		PolicyCacheSeconds: parseIntOrDefault("POLICY_CACHE_SECS", 15),
+
+		// Docker engine
+		DockerHost:      os.Getenv("DOCKER_HOST"),
+		DockerTLSVerify: os.Getenv("DOCKER_TLS_VERIFY") != "", // any value enables it, as with the Docker CLI
+		DockerCertPath:  os.Getenv("DOCKER_CERT_PATH"),
+
+		// Container readiness probes
+		ContainerProbeHost:    getEnvOrDefault("CONTAINER_PROBE_HOST", "localhost"),
+		ContainerProbeTimeout: parseIntOrDefault("CONTAINER_PROBE_TIMEOUT_SECONDS", 30),
+
+		// Container resource limits
+		RedisMemoryLimit:     getEnvOrDefault("REDIS_MEMORY_LIMIT", "512m"),
+		RedisCPULimit:        getEnvOrDefault("REDIS_CPU_LIMIT", "1"),
+		PgBouncerMemoryLimit: getEnvOrDefault("PGBOUNCER_MEMORY_LIMIT", "128m"),
+		PgBouncerCPULimit:    getEnvOrDefault("PGBOUNCER_CPU_LIMIT", "0.5"),
+
+		// Deployed container watchdog
+		ContainerWatchdogInterval: parseIntOrDefault("CONTAINER_WATCHDOG_INTERVAL_SECONDS", 30),
+		ContainerRestartThreshold: parseIntOrDefault("CONTAINER_RESTART_THRESHOLD", 3),
+		ContainerWatchdogRollback: getEnvOrDefault("CONTAINER_WATCHDOG_ROLLBACK", "true") == "true",
+
+		// Session termination safety
+		TerminateCancelFirst:       getEnvOrDefault("TERMINATE_CANCEL_FIRST", "false") == "true",
+		TerminateAllowApplications: parseList(os.Getenv("TERMINATE_ALLOW_APPLICATIONS")),
+		TerminateAllowUsers:        parseList(os.Getenv("TERMINATE_ALLOW_USERS")),
+		TerminateDenyApplications:  parseList(getEnvOrDefault("TERMINATE_DENY_APPLICATIONS", "*migrat*,flyway*,liquibase*")),
+		TerminateDenyUsers:         parseList(os.Getenv("TERMINATE_DENY_USERS")),
+
+		// Rollback safety
+		RollbackIndexScanThreshold: parseIntOrDefault("ROLLBACK_INDEX_SCAN_THRESHOLD", 100),
 
 		// Feature flags
 		EnableAutoExecution: getEnvOrDefault("ENABLE_AUTO_EXECUTION", "true") == "true",
 	}
 
+	overrides, err := parseApprovalOverrides(os.Getenv("ACTION_APPROVAL_OVERRIDES"))
+	if err != nil {
+		return nil, err
+	}
+	config.ApprovalOverrides = overrides
+
+	policy, err := parseExecutionPolicy(getEnvOrDefault("EXECUTION_POLICY", DefaultExecutionPolicy))
+	if err != nil {
+		return nil, err
+	}
+	config.ExecutionPolicy = policy
+
+	logLevel, err := logging.ParseLevel(os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		return nil, err
+	}
+	config.LogLevel = logLevel
+
 	if err := config.Validate(); err != nil {
 		return nil, err
 	}
@@ -73,6 +233,10 @@ func Load() (*Config, error) {
 
 // Validate checks that required configuration is present.
 func (c *Config) Validate() error {
+	if err := c.GRPCTLS.Validate(); err != nil {
+		return err
+	}
+
 	if c.GRPCPort == "" {
 		return fmt.Errorf("GRPC_PORT is required")
 	}
@@ -93,9 +257,108 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("ACTION_TIMEOUT_SECONDS must be at least 1")
 	}
 
+	if c.ShutdownGracePeriod < 0 {
+		return fmt.Errorf("SHUTDOWN_GRACE_PERIOD_SECONDS must not be negative")
+	}
+
+	if c.ActionMaxAttempts < 1 {
+		return fmt.Errorf("ACTION_MAX_ATTEMPTS must be at least 1")
+	}
+
+	if c.ActionRetryBackoff < 1 {
+		return fmt.Errorf("ACTION_RETRY_BACKOFF_SECONDS must be at least 1")
+	}
+
+	if c.ActionCooldown < 0 {
+		return fmt.Errorf("ACTION_COOLDOWN_SECONDS must not be negative")
+	}
+
+	if c.MaxActionsPerHour < 0 {
+		return fmt.Errorf("MAX_ACTIONS_PER_HOUR must not be negative")
+	}
+
+	if c.PgBouncerHostPort < 1 || c.PgBouncerHostPort > 65535 {
+		return fmt.Errorf("PGBOUNCER_HOST_PORT must be between 1 and 65535")
+	}
+
+	switch c.PgBouncerPoolMode {
+	case "session", "transaction", "statement":
+	default:
+		return fmt.Errorf("PGBOUNCER_POOL_MODE must be session, transaction or statement")
+	}
+
+	if c.PgBouncerDefaultPoolSize < 1 || c.PgBouncerMaxClientConn < 1 {
+		return fmt.Errorf("PGBOUNCER_DEFAULT_POOL_SIZE and PGBOUNCER_MAX_CLIENT_CONN must be at least 1")
+	}
+
+	if c.MongoProfileSlowMs < 0 {
+		return fmt.Errorf("MONGO_PROFILE_SLOWMS must not be negative")
+	}
+
+	if c.PolicyCacheSeconds < 1 {
+		return fmt.Errorf("POLICY_CACHE_SECS must be at least 1")
+	}
+
+	if err := c.Docker().Validate(); err != nil {
+		return fmt.Errorf("invalid DOCKER_HOST/DOCKER_TLS_VERIFY/DOCKER_CERT_PATH: %w", err)
+	}
+
+	if c.ContainerProbeTimeout < 1 {
+		return fmt.Errorf("CONTAINER_PROBE_TIMEOUT_SECONDS must be at least 1")
+	}
+
+	if _, err := docker.ParseResourceLimits(c.RedisMemoryLimit, c.RedisCPULimit); err != nil {
+		return fmt.Errorf("invalid REDIS_MEMORY_LIMIT/REDIS_CPU_LIMIT: %w", err)
+	}
+
+	if _, err := docker.ParseResourceLimits(c.PgBouncerMemoryLimit, c.PgBouncerCPULimit); err != nil {
+		return fmt.Errorf("invalid PGBOUNCER_MEMORY_LIMIT/PGBOUNCER_CPU_LIMIT: %w", err)
+	}
+
+	if c.ContainerWatchdogInterval < 0 {
+		return fmt.Errorf("CONTAINER_WATCHDOG_INTERVAL_SECONDS must not be negative")
+	}
+
+	if c.ContainerRestartThreshold < 1 {
+		return fmt.Errorf("CONTAINER_RESTART_THRESHOLD must be at least 1")
+	}
+
+	if c.RollbackIndexScanThreshold < 0 {
+		return fmt.Errorf("ROLLBACK_INDEX_SCAN_THRESHOLD must not be negative")
+	}
+
+	for _, patterns := range [][]string{c.TerminateAllowApplications, c.TerminateAllowUsers, c.TerminateDenyApplications, c.TerminateDenyUsers} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid terminate allow/deny pattern %q: %w", pattern, err)
+			}
+		}
+	}
+
 	return nil
 }
 
+// Docker returns the settings for connecting to the Docker engine.
+func (c *Config) Docker() docker.Config {
+	return docker.Config{
+		Host:      c.DockerHost,
+		TLSVerify: c.DockerTLSVerify,
+		CertPath:  c.DockerCertPath,
+	}
+}
+
+// RedisLimits returns the resource limits for deployed Redis containers.
+func (c *Config) RedisLimits() docker.ResourceLimits {
+	limits, _ := docker.ParseResourceLimits(c.RedisMemoryLimit, c.RedisCPULimit) // checked by Validate
+	return limits
+}
+
+// PgBouncerLimits returns the resource limits for deployed PgBouncer containers.
+func (c *Config) PgBouncerLimits() docker.ResourceLimits {
+	limits, _ := docker.ParseResourceLimits(c.PgBouncerMemoryLimit, c.PgBouncerCPULimit) // checked by Validate
+	return limits
+}
+
 // Helper functions
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -104,6 +367,63 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+// parseApprovalOverrides parses a comma-separated list of action_type=auto|approval pairs.
+func parseApprovalOverrides(value string) (map[string]string, error) {
+	overrides := map[string]string{}
+	if strings.TrimSpace(value) == "" {
+		return overrides, nil
+	}
+
+	for _, entry := range strings.Split(value, ",") {
+		actionType, mode, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		actionType = strings.TrimSpace(actionType)
+		mode = strings.TrimSpace(mode)
+		if !ok || actionType == "" {
+			return nil, fmt.Errorf("ACTION_APPROVAL_OVERRIDES entry %q must be action_type=auto|approval", entry)
+		}
+		if mode != models.ApprovalAuto && mode != models.ApprovalRequired {
+			return nil, fmt.Errorf("ACTION_APPROVAL_OVERRIDES mode for %s must be auto or approval, got %q", actionType, mode)
+		}
+		overrides[actionType] = mode
+	}
+
+	return overrides, nil
+}
+
+// parseExecutionPolicy parses a comma-separated list of severity=auto|approve|notify pairs.
+func parseExecutionPolicy(value string) (map[string]string, error) {
+	policy := map[string]string{}
+
+	for _, entry := range parseList(value) {
+		severity, behaviour, ok := strings.Cut(entry, "=")
+		severity = strings.ToLower(strings.TrimSpace(severity))
+		behaviour = strings.TrimSpace(behaviour)
+		if !ok || severity == "" {
+			return nil, fmt.Errorf("EXECUTION_POLICY entry %q must be severity=auto|approve|notify", entry)
+		}
+		switch behaviour {
+		case models.PolicyAuto, models.PolicyApprove, models.PolicyNotify:
+		default:
+			return nil, fmt.Errorf("EXECUTION_POLICY behaviour for %s must be auto, approve or notify, got %q", severity, behaviour)
+		}
+		policy[severity] = behaviour
+	}
+
+	return policy, nil
+}
+
+// parseList splits a comma-separated list; "none" yields an empty list.
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" && item != "none" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func parseIntOrDefault(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
 		var result int
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/joho/godotenv"
+)
+
+// envPaths are the .env locations tried in order; the first one found is used.
+var envPaths = []string{
+	".env",
+	"../.env",
+	"../../.env", // Executor is nested deeper
+	"/app/.env",  // Docker
+}
+
+// runtimeSettings are the Config fields a reload applies without a restart. Changes to
+// any other field are rejected until the Executor restarts.
+var runtimeSettings = map[string]bool{
+	"MaxConcurrentActions":       true,
+	"ActionTimeout":              true,
+	"ShutdownGracePeriod":        true,
+	"ActionMaxAttempts":          true,
+	"ActionRetryBackoff":         true,
+	"ActionCooldown":             true,
+	"MaxActionsPerHour":          true,
+	"ContainerProbeTimeout":      true,
+	"RedisMemoryLimit":           true,
+	"RedisCPULimit":              true,
+	"PgBouncerMemoryLimit":       true,
+	"PgBouncerCPULimit":          true,
+	"RollbackIndexScanThreshold": true,
+	"EnableAutoExecution":        true,
+	"ApprovalOverrides":          true,
+	"ExecutionPolicy":            true,
+}
+
+// secretSettings are never written to logs or API responses.
+var secretSettings = map[string]bool{
+	"APIToken":         true,
+	"NotifyWebhookURL": true,
+}
+
+var (
+	envMu sync.Mutex
+
+	// processEnv holds the variables set before any .env file was read; the file never
+	// overrides them, on load or reload
+	processEnv map[string]bool
+
+	// fileEnv holds the variables last set from the .env file, so a reload can unset
+	// the ones removed from it
+	fileEnv = map[string]bool{}
+)
+
+// Change is one setting that differs between two configs.
+type Change struct {
+	Setting string `json:"setting"`
+	Old     string `json:"old"`
+	New     string `json:"new"`
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Setting, c.Old, c.New)
+}
+
+// ReloadResult describes what a reload changed.
+type ReloadResult struct {
+	Applied  []Change `json:"applied"`
+	Rejected []Change `json:"rejected"` // need a restart to take effect
+}
+
+// loadEnvFile sets variables from the first .env file found, leaving variables from the
+// process environment untouched.
+func loadEnvFile() {
+	envMu.Lock()
+	defer envMu.Unlock()
+
+	if processEnv == nil {
+		processEnv = map[string]bool{}
+		for _, entry := range os.Environ() {
+			key, _, _ := strings.Cut(entry, "=")
+			processEnv[key] = true
+		}
+	}
+
+	for _, path := range envPaths {
+		values, err := godotenv.Read(path)
+		if err != nil {
+			continue
+		}
+
+		log.Printf("Loaded config from: %s", path)
+
+		for key := range fileEnv {
+			if _, ok := values[key]; !ok {
+				os.Unsetenv(key)
+			}
+		}
+
+		fileEnv = map[string]bool{}
+		for key, value := range values {
+			if processEnv[key] {
+				continue
+			}
+			os.Setenv(key, value)
+			fileEnv[key] = true
+		}
+		return
+	}
+
+	log.Printf("No .env file found, using environment variables")
+}
+
+// Reload re-reads the environment and .env file. It returns current with the runtime
+// settings updated; other settings keep their current value and are reported as rejected.
+// current is not modified.
+func Reload(current *Config) (*Config, *ReloadResult, error) {
+	loadEnvFile()
+
+	next, err := fromEnv()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updated := *current
+	result := &ReloadResult{}
+
+	target := reflect.ValueOf(&updated).Elem()
+	source := reflect.ValueOf(next).Elem()
+
+	for _, change := range Diff(current, next) {
+		if !runtimeSettings[change.Setting] {
+			result.Rejected = append(result.Rejected, change)
+			continue
+		}
+		target.FieldByName(change.Setting).Set(source.FieldByName(change.Setting))
+		result.Applied = append(result.Applied, change)
+	}
+
+	return &updated, result, nil
+}
+
+// Diff lists the settings that differ between old and next, by Config field name.
+func Diff(old, next *Config) []Change {
+	oldValue := reflect.ValueOf(old).Elem()
+	newValue := reflect.ValueOf(next).Elem()
+	fields := oldValue.Type()
+
+	var changes []Change
+	for i := 0; i < fields.NumField(); i++ {
+		name := fields.Field(i).Name
+		before, after := oldValue.Field(i).Interface(), newValue.Field(i).Interface()
+		if reflect.DeepEqual(before, after) {
+			continue
+		}
+
+		change := Change{Setting: name, Old: formatSetting(before), New: formatSetting(after)}
+		if secretSettings[name] {
+			change.Old, change.New = "[redacted]", "[redacted]"
+		}
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+// formatSetting renders a setting for a diff; maps are sorted so output is stable.
+func formatSetting(value interface{}) string {
+	m, ok := value.(map[string]string)
+	if !ok {
+		return fmt.Sprintf("%v", value)
+	}
+
+	pairs := make([]string, 0, len(m))
+	for key, v := range m {
+		pairs = append(pairs, key+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -3,22 +3,118 @@ package database
 import (
 	"context"
 	"fmt"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
 )
 
 type DatabaseAdapter interface {
 	CreateIndex(ctx context.Context, params IndexParams) error
 	DropIndex(ctx context.Context, indexName string) error
 	IndexExists(ctx context.Context, indexName string) (bool, error)
+	TableExists(ctx context.Context, tableName string) (bool, error)
+	ColumnExists(ctx context.Context, tableName, columnName string) (bool, error)
 	GetCurrentConfig(ctx context.Context, parameters []string) (map[string]string, error)
 	SetConfig(ctx context.Context, changes map[string]string) error
 	GetSlowQueries(ctx context.Context, thresholdMs float64, limit int) ([]SlowQuery, error)
 	VacuumTable(ctx context.Context, tableName string) error
 	GetDeadTuples(ctx context.Context, tableName string) (int64, error)
 	TerminateQuery(ctx context.Context, pid int32, graceful bool) error
+	GetBackendInfo(ctx context.Context, pid int32) (*BackendInfo, error)
 	GetCapabilities() Capabilities
 	Close() error
 }
 
+// IndexProgressReporter is implemented by adapters that can observe an index build while it runs.
+type IndexProgressReporter interface {
+	// GetIndexProgress returns nil if no index build is running on the table.
+	GetIndexProgress(ctx context.Context, tableName string) (*IndexProgress, error)
+	GetIndexSize(ctx context.Context, indexName string) (int64, error)
+}
+
+// IndexUsageReporter is implemented by adapters that track how often each index is scanned,
+// so an index queries have come to rely on is not dropped by a rollback.
+type IndexUsageReporter interface {
+	// GetIndexScans returns the scans of the index since statistics were last reset, or 0
+	// if the index does not exist.
+	GetIndexScans(ctx context.Context, tableName, indexName string) (int64, error)
+}
+
+// IndexDefinitionManager is implemented by adapters that can capture an index's DDL
+// before dropping it and replay that DDL to re-create it.
+type IndexDefinitionManager interface {
+	// GetIndexDefinition returns "" if the index does not exist.
+	GetIndexDefinition(ctx context.Context, indexName string) (string, error)
+	RestoreIndex(ctx context.Context, definition string) error
+}
+
+// PasswordAuthInspector is implemented by adapters that can report how a role's
+// password is stored, so a proxy in front of the database can authenticate the same way.
+type PasswordAuthInspector interface {
+	// GetRolePasswordHash returns the stored hash ("md5..." or "SCRAM-SHA-256$..."),
+	// or "" if the connected role is not allowed to read it.
+	GetRolePasswordHash(ctx context.Context, role string) (string, error)
+	GetPasswordEncryption(ctx context.Context) (string, error)
+}
+
+// PersistedConfigManager is implemented by adapters that persist settings with ALTER SYSTEM,
+// so a setting that only takes effect on restart can be staged and later undone.
+type PersistedConfigManager interface {
+	// IsConfigPersisted reports whether a parameter is currently overridden by ALTER SYSTEM.
+	IsConfigPersisted(ctx context.Context, parameter string) (bool, error)
+	// ResetConfig removes the ALTER SYSTEM overrides, falling back to the config file.
+	ResetConfig(ctx context.Context, parameters []string) error
+	// ReadPersistedConfigFile returns the data directory and the contents of
+	// postgresql.auto.conf, so the file can be put back while the server is down.
+	ReadPersistedConfigFile(ctx context.Context) (dataDirectory string, content string, err error)
+}
+
+// TableMaintenanceManager is implemented by adapters that can rewrite a table with
+// VACUUM FULL and tune per-table storage parameters (reloptions) such as autovacuum settings.
+type TableMaintenanceManager interface {
+	// VacuumTableFull rewrites the table under an exclusive lock, returning its space to the OS.
+	VacuumTableFull(ctx context.Context, tableName string) error
+	// GetTableSize returns the table's on-disk size in bytes, including indexes and TOAST.
+	GetTableSize(ctx context.Context, tableName string) (int64, error)
+	// GetTableOptions returns the named storage parameters set on the table. Parameters
+	// left at their default are absent from the result.
+	GetTableOptions(ctx context.Context, tableName string, options []string) (map[string]string, error)
+	SetTableOptions(ctx context.Context, tableName string, options map[string]string) error
+	// ResetTableOptions returns the storage parameters to their defaults.
+	ResetTableOptions(ctx context.Context, tableName string, options []string) error
+}
+
+// IndexNamer is implemented by adapters whose databases have their own index naming
+// convention, used instead of the idx_<database>_<table>_<columns> default.
+type IndexNamer interface {
+	IndexName(tableName string, columnNames []string) string
+}
+
+// TableIndexManager is implemented by adapters where index names are only unique within
+// a table, so checking for or dropping an index must name the table too.
+type TableIndexManager interface {
+	IndexExistsOnTable(ctx context.Context, tableName, indexName string) (bool, error)
+	DropIndexFromTable(ctx context.Context, tableName, indexName string) error
+}
+
+// QueryExplainer is implemented by adapters that can plan a slow query without running
+// it, so a report can name the tables it scans in full.
+type QueryExplainer interface {
+	// ExplainQuery plans a query as GetSlowQueries reports it, with "?" or $n in place of
+	// its values. Returns ErrActionNotSupported for queries it will not explain, such as
+	// writes or patterns truncated by GetSlowQueries.
+	ExplainQuery(ctx context.Context, query string) (*QueryPlan, error)
+}
+
+// IndexProgress is a snapshot of an in-flight index build.
+type IndexProgress struct {
+	Phase       string `json:"phase"`
+	BlocksDone  int64  `json:"blocks_done"`
+	BlocksTotal int64  `json:"blocks_total"`
+	TuplesDone  int64  `json:"tuples_done"`
+	TuplesTotal int64  `json:"tuples_total"`
+}
+
 type SlowQuery struct {
 	QueryPattern    string  `json:"query_pattern"`
 	ExecutionTimeMs float64 `json:"execution_time_ms"`
@@ -27,6 +123,32 @@ type SlowQuery struct {
 	Recommendation  string  `json:"recommendation"`
 }
 
+// QueryPlan is the part of a query's plan that points at missing indexes.
+type QueryPlan struct {
+	TotalCost float64       `json:"total_cost"`
+	SeqScans  []PlannedScan `json:"seq_scans"`
+}
+
+// PlannedScan is a sequential scan in a query plan.
+type PlannedScan struct {
+	Table  string `json:"table"`
+	Filter string `json:"filter,omitempty"`
+	// Columns the scan filters on; empty if it reads the whole table
+	Columns []string `json:"columns,omitempty"`
+}
+
+// BackendInfo describes what a backend (connection/operation) is currently doing.
+type BackendInfo struct {
+	PID             int32      `json:"pid"`
+	Username        string     `json:"username"`
+	Query           string     `json:"query"`
+	State           string     `json:"state"`
+	ApplicationName string     `json:"application_name"`
+	ClientAddr      string     `json:"client_addr"`
+	XactStart       *time.Time `json:"xact_start,omitempty"` // nil outside a transaction
+	IsSuperuser     bool       `json:"is_superuser"`
+}
+
 type IndexParams struct {
 	TableName   string   `json:"table_name"`
 	ColumnNames []string `json:"column_names"`
@@ -46,7 +168,10 @@ type Capabilities struct {
 	SupportsQueryTermination     bool `json:"supports_query_termination"`
 }
 
+// Adapter errors carry their ErrorCode, so actions failing with them are classified
+// without a driver error to go on.
 var (
-	ErrActionNotSupported = fmt.Errorf("action not supported by this database")
-	ErrIndexAlreadyExists = fmt.Errorf("index already exists")
+	ErrActionNotSupported = models.WithErrorCode(models.ErrorCodeUnsupported, fmt.Errorf("action not supported by this database"))
+	ErrIndexAlreadyExists = models.WithErrorCode(models.ErrorCodeAlreadyExists, fmt.Errorf("index already exists"))
+	ErrBackendNotFound    = models.WithErrorCode(models.ErrorCodeObjectNotFound, fmt.Errorf("backend not found"))
 )
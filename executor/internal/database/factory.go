@@ -3,19 +3,125 @@ package database
 import (
 	"context"
 	"fmt"
+	"log"
+	"log/slog"
 	"strings"
+	"sync"
 )
 
+// AdapterFactory opens an adapter for a database. NewAdapter is the production factory.
+type AdapterFactory func(ctx context.Context, databaseType, connectionString, databaseID string) (DatabaseAdapter, error)
+
+// AdapterOptions tunes how adapters are opened.
+type AdapterOptions struct {
+	// MongoProfileSlowMs is the slowms the MongoDB profiler is set to on connect; 0 leaves it alone
+	MongoProfileSlowMs int
+}
+
+// DefaultAdapterOptions returns the options NewAdapter uses.
+func DefaultAdapterOptions() AdapterOptions {
+	return AdapterOptions{MongoProfileSlowMs: DefaultMongoProfileSlowMs}
+}
+
 // NewAdapter creates the appropriate database adapter based on database type.
 func NewAdapter(ctx context.Context, databaseType, connectionString, databaseID string) (DatabaseAdapter, error) {
-	switch strings.ToLower(databaseType) {
-	case "postgres", "postgresql":
-		return NewPostgresAdapter(ctx, connectionString, databaseID)
-	case "mysql", "mariadb":
-		return NewMySQLAdapter(ctx, connectionString, databaseID)
-	case "mongo", "mongodb":
-		return NewMongoDBAdapter(ctx, connectionString, databaseID)
-	default:
-		return nil, fmt.Errorf("unsupported database type: %s", databaseType)
+	return NewAdapterFactory(DefaultAdapterOptions())(ctx, databaseType, connectionString, databaseID)
+}
+
+// NewAdapterFactory returns a factory that opens adapters with opts.
+func NewAdapterFactory(opts AdapterOptions) AdapterFactory {
+	return func(ctx context.Context, databaseType, connectionString, databaseID string) (DatabaseAdapter, error) {
+		switch strings.ToLower(databaseType) {
+		case "postgres", "postgresql":
+			return NewPostgresAdapter(ctx, connectionString, databaseID)
+		case "mysql", "mariadb":
+			return NewMySQLAdapter(ctx, connectionString, databaseID)
+		case "mongo", "mongodb":
+			// The database to use is named in the connection string, not by the database ID
+			return NewMongoDBAdapter(ctx, connectionString, "", opts.MongoProfileSlowMs)
+		default:
+			return nil, fmt.Errorf("unsupported database type: %s", databaseType)
+		}
+	}
+}
+
+// AdapterCache shares one adapter (and so one connection pool) per database ID
+// across every action run against that database. Adapters stay open until Close.
+type AdapterCache struct {
+	factory  AdapterFactory
+	mu       sync.Mutex
+	adapters map[string]*cachedAdapter
+	closed   bool
+}
+
+type cachedAdapter struct {
+	adapter          DatabaseAdapter
+	databaseType     string
+	connectionString string
+}
+
+// NewAdapterCache creates a cache that opens adapters with factory.
+func NewAdapterCache(factory AdapterFactory) *AdapterCache {
+	if factory == nil {
+		factory = NewAdapter
+	}
+	return &AdapterCache{
+		factory:  factory,
+		adapters: map[string]*cachedAdapter{},
+	}
+}
+
+// Get returns the cached adapter for databaseID, opening one on first use. If the
+// database was re-registered with a different type or connection string, the old
+// adapter is closed and replaced.
+func (c *AdapterCache) Get(ctx context.Context, databaseType, connectionString, databaseID string) (DatabaseAdapter, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, fmt.Errorf("adapter cache closed")
+	}
+
+	cached, ok := c.adapters[databaseID]
+	if ok && strings.EqualFold(cached.databaseType, databaseType) && cached.connectionString == connectionString {
+		return cached.adapter, nil
+	}
+
+	adapter, err := c.factory(ctx, databaseType, connectionString, databaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		// Actions still holding the old adapter finish against it; pools drain on close
+		log.Printf("Connection details changed for database %s - replacing adapter", databaseID)
+		go closeAdapter(databaseID, cached.adapter)
+	}
+
+	c.adapters[databaseID] = &cachedAdapter{
+		adapter:          adapter,
+		databaseType:     databaseType,
+		connectionString: connectionString,
+	}
+
+	return adapter, nil
+}
+
+// Close closes every cached adapter. Get fails once the cache is closed.
+func (c *AdapterCache) Close() {
+	c.mu.Lock()
+	c.closed = true
+	adapters := c.adapters
+	c.adapters = map[string]*cachedAdapter{}
+	c.mu.Unlock()
+
+	for databaseID, cached := range adapters {
+		closeAdapter(databaseID, cached.adapter)
+	}
+}
+
+func closeAdapter(databaseID string, adapter DatabaseAdapter) {
+	if err := adapter.Close(); err != nil {
+		slog.Warn("Failed to close adapter for database", "database_id", databaseID, "error", err)
 	}
 }
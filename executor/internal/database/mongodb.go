@@ -3,6 +3,8 @@ package database
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"slices"
 	"strings"
 	"time"
 
@@ -11,13 +13,50 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// DefaultMongoProfileSlowMs is the slowms the profiler is set to when the adapter connects.
+const DefaultMongoProfileSlowMs = 100
+
+// MongoCollections is the part of a MongoDB database the adapter's index and profiling
+// operations use. The driver-backed implementation talks to the server; tests substitute
+// a fake.
+type MongoCollections interface {
+	CollectionNames(ctx context.Context) ([]string, error)
+	ListIndexes(ctx context.Context, collection string) ([]MongoIndex, error)
+	CreateIndex(ctx context.Context, collection string, index MongoIndex) error
+	DropIndex(ctx context.Context, collection, name string) error
+	// SetProfilingLevel sets the database profiler level (0 off, 1 slow operations, 2 all).
+	SetProfilingLevel(ctx context.Context, level, slowMs int) error
+	// SlowOperations returns profiled operations slower than thresholdMs, slowest first.
+	SlowOperations(ctx context.Context, thresholdMs float64, limit int) ([]MongoProfileEntry, error)
+}
+
+// MongoIndex is an ascending index on one or more fields of a collection.
+type MongoIndex struct {
+	Name   string
+	Keys   []string
+	Unique bool
+}
+
+// MongoProfileEntry is one operation recorded in system.profile.
+type MongoProfileEntry struct {
+	Namespace   string // "<database>.<collection>"
+	Operation   string // query, update, command...
+	Command     string
+	Millis      float64
+	PlanSummary string // e.g. "COLLSCAN" or "IXSCAN { user_id: 1 }"
+}
+
 type MongoDBAdapter struct {
 	client       *mongo.Client
 	database     *mongo.Database
 	databaseName string
+	collections  MongoCollections
 }
 
-func NewMongoDBAdapter(ctx context.Context, connectionString, databaseName string) (*MongoDBAdapter, error) {
+// NewMongoDBAdapter connects to the database named in the connection string (or
+// databaseName, if given) and sets the profiler to record operations slower than
+// profileSlowMs so GetSlowQueries has something to read; 0 leaves the profiler alone.
+func NewMongoDBAdapter(ctx context.Context, connectionString, databaseName string, profileSlowMs int) (*MongoDBAdapter, error) {
 	// Extract database name from connection string if not provided
 	dbName := databaseName
 	if dbName == "" {
@@ -40,11 +79,31 @@ func NewMongoDBAdapter(ctx context.Context, connectionString, databaseName strin
 		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
 	}
 
-	return &MongoDBAdapter{
+	database := client.Database(dbName)
+	adapter := &MongoDBAdapter{
 		client:       client,
-		database:     client.Database(dbName),
+		database:     database,
 		databaseName: dbName,
-	}, nil
+		collections:  &driverCollections{database: database},
+	}
+
+	// Without the profiler there are no slow queries to report, but indexes still work
+	if profileSlowMs > 0 {
+		if err := adapter.collections.SetProfilingLevel(ctx, 1, profileSlowMs); err != nil {
+			slog.Warn("Failed to enable profiling on MongoDB database", "db_name", dbName, "error", err)
+		}
+	}
+
+	return adapter, nil
+}
+
+// NewMongoDBAdapterWithCollections creates an adapter whose index and profiling operations
+// go through collections. Operations needing a live client are unavailable.
+func NewMongoDBAdapterWithCollections(databaseName string, collections MongoCollections) *MongoDBAdapter {
+	return &MongoDBAdapter{
+		databaseName: databaseName,
+		collections:  collections,
+	}
 }
 
 func extractMongoDBNameFromConnStr(connStr string) string {
@@ -52,14 +111,25 @@ func extractMongoDBNameFromConnStr(connStr string) string {
 		connStr = connStr[:idx]
 	}
 	parts := strings.Split(connStr, "/")
-	if len(parts) >= 4 {
+	if len(parts) >= 4 && parts[3] != "" {
 		return parts[3]
 	}
 	return "test"
 }
 
+// IndexName follows MongoDB's own convention, e.g. user_id_1_created_at_1 for an
+// ascending compound index.
+func (m *MongoDBAdapter) IndexName(tableName string, columnNames []string) string {
+	parts := make([]string, 0, len(columnNames))
+	for _, column := range columnNames {
+		parts = append(parts, column+"_1")
+	}
+	return strings.Join(parts, "_")
+}
+
+// CreateIndex builds the index in the background, so reads and writes carry on meanwhile.
 func (m *MongoDBAdapter) CreateIndex(ctx context.Context, params IndexParams) error {
-	exists, err := m.IndexExists(ctx, params.IndexName)
+	exists, err := m.IndexExistsOnTable(ctx, params.TableName, params.IndexName)
 	if err != nil {
 		return fmt.Errorf("failed to check index existence: %w", err)
 	}
@@ -68,23 +138,11 @@ func (m *MongoDBAdapter) CreateIndex(ctx context.Context, params IndexParams) er
 		return ErrIndexAlreadyExists
 	}
 
-	collection := m.database.Collection(params.TableName)
-
-	// Build index keys
-	keys := bson.D{}
-	for _, col := range params.ColumnNames {
-		keys = append(keys, bson.E{Key: col, Value: 1})
-	}
-
-	indexModel := mongo.IndexModel{
-		Keys: keys,
-		Options: options.Index().
-			SetName(params.IndexName).
-			SetUnique(params.Unique).
-			SetBackground(true), // Non-blocking index creation
-	}
-
-	_, err = collection.Indexes().CreateOne(ctx, indexModel)
+	err = m.collections.CreateIndex(ctx, params.TableName, MongoIndex{
+		Name:   params.IndexName,
+		Keys:   params.ColumnNames,
+		Unique: params.Unique,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to create index: %w", err)
 	}
@@ -92,8 +150,9 @@ func (m *MongoDBAdapter) CreateIndex(ctx context.Context, params IndexParams) er
 	return nil
 }
 
+// DropIndex drops the first index called indexName. Index names are only unique within a
+// collection, so prefer DropIndexFromTable when the collection is known.
 func (m *MongoDBAdapter) DropIndex(ctx context.Context, indexName string) error {
-	// Find which collection has this index
 	collName, err := m.findCollectionForIndex(ctx, indexName)
 	if err != nil {
 		return fmt.Errorf("failed to find collection for index: %w", err)
@@ -104,17 +163,19 @@ func (m *MongoDBAdapter) DropIndex(ctx context.Context, indexName string) error
 		return nil
 	}
 
-	collection := m.database.Collection(collName)
-	_, err = collection.Indexes().DropOne(ctx, indexName)
-	if err != nil {
+	return m.DropIndexFromTable(ctx, collName, indexName)
+}
+
+// DropIndexFromTable drops indexName from the collection tableName.
+func (m *MongoDBAdapter) DropIndexFromTable(ctx context.Context, tableName, indexName string) error {
+	if err := m.collections.DropIndex(ctx, tableName, indexName); err != nil {
 		return fmt.Errorf("failed to drop index: %w", err)
 	}
-
 	return nil
 }
 
 func (m *MongoDBAdapter) findCollectionForIndex(ctx context.Context, indexName string) (string, error) {
-	collections, err := m.database.ListCollectionNames(ctx, bson.M{})
+	collections, err := m.collections.CollectionNames(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -124,27 +185,19 @@ func (m *MongoDBAdapter) findCollectionForIndex(ctx context.Context, indexName s
 			continue
 		}
 
-		cursor, err := m.database.Collection(collName).Indexes().List(ctx)
+		exists, err := m.IndexExistsOnTable(ctx, collName, indexName)
 		if err != nil {
 			continue
 		}
-
-		for cursor.Next(ctx) {
-			var idx bson.M
-			if err := cursor.Decode(&idx); err != nil {
-				continue
-			}
-			if name, ok := idx["name"].(string); ok && name == indexName {
-				cursor.Close(ctx)
-				return collName, nil
-			}
+		if exists {
+			return collName, nil
 		}
-		cursor.Close(ctx)
 	}
 
 	return "", nil
 }
 
+// IndexExists reports whether any collection has an index called indexName.
 func (m *MongoDBAdapter) IndexExists(ctx context.Context, indexName string) (bool, error) {
 	collName, err := m.findCollectionForIndex(ctx, indexName)
 	if err != nil {
@@ -153,6 +206,34 @@ func (m *MongoDBAdapter) IndexExists(ctx context.Context, indexName string) (boo
 	return collName != "", nil
 }
 
+// IndexExistsOnTable reports whether the collection tableName has an index called indexName.
+func (m *MongoDBAdapter) IndexExistsOnTable(ctx context.Context, tableName, indexName string) (bool, error) {
+	indexes, err := m.collections.ListIndexes(ctx, tableName)
+	if err != nil {
+		return false, err
+	}
+
+	for _, index := range indexes {
+		if index.Name == indexName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MongoDBAdapter) TableExists(ctx context.Context, tableName string) (bool, error) {
+	collections, err := m.collections.CollectionNames(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to check collection existence: %w", err)
+	}
+	return slices.Contains(collections, tableName), nil
+}
+
+// ColumnExists always reports true - collections are schemaless, so any field can be indexed.
+func (m *MongoDBAdapter) ColumnExists(ctx context.Context, tableName, columnName string) (bool, error) {
+	return true, nil
+}
+
 func (m *MongoDBAdapter) GetCurrentConfig(ctx context.Context, parameters []string) (map[string]string, error) {
 	config := make(map[string]string)
 
@@ -173,84 +254,48 @@ func (m *MongoDBAdapter) GetCurrentConfig(ctx context.Context, parameters []stri
 	return config, nil
 }
 
+// SetConfig is not supported: the config tuning actions set SQL server parameters,
+// which have no MongoDB equivalent.
 func (m *MongoDBAdapter) SetConfig(ctx context.Context, changes map[string]string) error {
-	for param, value := range changes {
-		cmd := bson.D{
-			{Key: "setParameter", Value: 1},
-			{Key: param, Value: value},
-		}
-		err := m.database.RunCommand(ctx, cmd).Err()
-		if err != nil {
-			return fmt.Errorf("failed to set %s to %s: %w", param, value, err)
-		}
-	}
-
-	return nil
+	return ErrActionNotSupported
 }
 
+// GetSlowQueries reads system.profile, so it only finds operations slower than the
+// profiler's slowms. Collection scans are reported as missing indexes.
 func (m *MongoDBAdapter) GetSlowQueries(ctx context.Context, thresholdMs float64, limit int) ([]SlowQuery, error) {
-	// Query from system.profile
-	profileColl := m.database.Collection("system.profile")
-
-	filter := bson.M{
-		"millis": bson.M{"$gt": thresholdMs},
-	}
-
-	opts := options.Find().
-		SetSort(bson.D{{Key: "millis", Value: -1}}).
-		SetLimit(int64(limit))
-
-	cursor, err := profileColl.Find(ctx, filter, opts)
+	entries, err := m.collections.SlowOperations(ctx, thresholdMs, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query slow queries: %w", err)
 	}
-	defer cursor.Close(ctx)
 
-	var slowQueries []SlowQuery
-
-	for cursor.Next(ctx) {
-		var doc bson.M
-		if err := cursor.Decode(&doc); err != nil {
-			continue
-		}
-
-		queryPattern := ""
-		if command, ok := doc["command"].(bson.M); ok {
-			queryPattern = fmt.Sprintf("%v", command)
-			if len(queryPattern) > 200 {
-				queryPattern = queryPattern[:200] + "..."
-			}
+	slowQueries := make([]SlowQuery, 0, len(entries))
+	for _, entry := range entries {
+		queryPattern := strings.TrimSpace(fmt.Sprintf("%s %s %s", entry.Operation, entry.Namespace, entry.Command))
+		if len(queryPattern) > 200 {
+			queryPattern = queryPattern[:200] + "..."
 		}
 
-		millis := 0.0
-		if ms, ok := doc["millis"].(int32); ok {
-			millis = float64(ms)
-		} else if ms, ok := doc["millis"].(int64); ok {
-			millis = float64(ms)
-		}
-
-		slowQueries = append(slowQueries, SlowQuery{
+		slowQuery := SlowQuery{
 			QueryPattern:    queryPattern,
-			ExecutionTimeMs: millis,
+			ExecutionTimeMs: entry.Millis,
 			CallCount:       1,
 			IssueType:       "slow_query",
 			Recommendation:  "Review query and add appropriate indexes",
-		})
+		}
+		if strings.HasPrefix(entry.PlanSummary, "COLLSCAN") {
+			slowQuery.IssueType = "missing_index"
+			slowQuery.Recommendation = "Query scans the whole collection - index the fields it filters on"
+		}
+
+		slowQueries = append(slowQueries, slowQuery)
 	}
 
 	return slowQueries, nil
 }
 
+// VacuumTable is not supported: compact blocks the collection and is not a vacuum.
 func (m *MongoDBAdapter) VacuumTable(ctx context.Context, tableName string) error {
-	// MongoDB uses compact command instead of vacuum
-	err := m.database.RunCommand(ctx, bson.D{
-		{Key: "compact", Value: tableName},
-	}).Err()
-	if err != nil {
-		return fmt.Errorf("failed to compact collection %s: %w", tableName, err)
-	}
-
-	return nil
+	return ErrActionNotSupported
 }
 
 func (m *MongoDBAdapter) GetDeadTuples(ctx context.Context, tableName string) (int64, error) {
@@ -276,6 +321,51 @@ func (m *MongoDBAdapter) GetDeadTuples(ctx context.Context, tableName string) (i
 	return 0, nil
 }
 
+func (m *MongoDBAdapter) GetBackendInfo(ctx context.Context, pid int32) (*BackendInfo, error) {
+	// currentOp must run against the admin database
+	var result bson.M
+	err := m.client.Database("admin").RunCommand(ctx, bson.D{
+		{Key: "currentOp", Value: 1},
+		{Key: "opid", Value: pid},
+	}).Decode(&result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get operation info for %d: %w", pid, err)
+	}
+
+	inprog, ok := result["inprog"].(bson.A)
+	if !ok || len(inprog) == 0 {
+		return nil, ErrBackendNotFound
+	}
+
+	op, ok := inprog[0].(bson.M)
+	if !ok {
+		return nil, ErrBackendNotFound
+	}
+
+	info := &BackendInfo{PID: pid}
+	if desc, ok := op["desc"].(string); ok {
+		info.State = desc
+	}
+	if command, ok := op["command"]; ok {
+		info.Query = fmt.Sprintf("%v", command)
+	}
+	if users, ok := op["effectiveUsers"].(bson.A); ok && len(users) > 0 {
+		if user, ok := users[0].(bson.M); ok {
+			if name, ok := user["user"].(string); ok {
+				info.Username = name
+			}
+		}
+	}
+	if appName, ok := op["appName"].(string); ok {
+		info.ApplicationName = appName
+	}
+	if client, ok := op["client"].(string); ok {
+		info.ClientAddr = client
+	}
+
+	return info, nil
+}
+
 func (m *MongoDBAdapter) TerminateQuery(ctx context.Context, pid int32, graceful bool) error {
 	// MongoDB uses killOp
 	err := m.database.RunCommand(ctx, bson.D{
@@ -292,12 +382,12 @@ func (m *MongoDBAdapter) TerminateQuery(ctx context.Context, pid int32, graceful
 func (m *MongoDBAdapter) GetCapabilities() Capabilities {
 	return Capabilities{
 		SupportsIndexes:              true,
-		SupportsConcurrentIndexes:    false, // MongoDB builds in background but not "concurrent" like Postgres
+		SupportsConcurrentIndexes:    true, // Background builds don't block reads or writes
 		SupportsUniqueIndex:          true,
 		SupportsMultiColumnIndex:     true, // Compound indexes
-		SupportsConfigTuning:         true,
-		SupportsRuntimeConfigChanges: true,
-		SupportsVacuum:               true, // Via compact command
+		SupportsConfigTuning:         false,
+		SupportsRuntimeConfigChanges: false,
+		SupportsVacuum:               false,
 		SupportsQueryTermination:     true,
 	}
 }
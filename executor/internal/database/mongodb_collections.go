@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// driverCollections implements MongoCollections with the driver's index view and
+// the profile command.
+type driverCollections struct {
+	database *mongo.Database
+}
+
+func (d *driverCollections) CollectionNames(ctx context.Context) ([]string, error) {
+	return d.database.ListCollectionNames(ctx, bson.M{})
+}
+
+func (d *driverCollections) ListIndexes(ctx context.Context, collection string) ([]MongoIndex, error) {
+	cursor, err := d.database.Collection(collection).Indexes().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var indexes []MongoIndex
+	for cursor.Next(ctx) {
+		var spec struct {
+			Name   string `bson:"name"`
+			Key    bson.D `bson:"key"`
+			Unique bool   `bson:"unique"`
+		}
+		if err := cursor.Decode(&spec); err != nil {
+			continue
+		}
+
+		index := MongoIndex{Name: spec.Name, Unique: spec.Unique}
+		for _, key := range spec.Key {
+			index.Keys = append(index.Keys, key.Key)
+		}
+		indexes = append(indexes, index)
+	}
+
+	return indexes, cursor.Err()
+}
+
+func (d *driverCollections) CreateIndex(ctx context.Context, collection string, index MongoIndex) error {
+	keys := bson.D{}
+	for _, field := range index.Keys {
+		keys = append(keys, bson.E{Key: field, Value: 1})
+	}
+
+	_, err := d.database.Collection(collection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: keys,
+		Options: options.Index().
+			SetName(index.Name).
+			SetUnique(index.Unique).
+			SetBackground(true), // Ignored from 4.2, where every build only locks briefly
+	})
+	return err
+}
+
+func (d *driverCollections) DropIndex(ctx context.Context, collection, name string) error {
+	_, err := d.database.Collection(collection).Indexes().DropOne(ctx, name)
+	return err
+}
+
+func (d *driverCollections) SetProfilingLevel(ctx context.Context, level, slowMs int) error {
+	return d.database.RunCommand(ctx, bson.D{
+		{Key: "profile", Value: level},
+		{Key: "slowms", Value: slowMs},
+	}).Err()
+}
+
+func (d *driverCollections) SlowOperations(ctx context.Context, thresholdMs float64, limit int) ([]MongoProfileEntry, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "millis", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := d.database.Collection("system.profile").Find(ctx, bson.M{"millis": bson.M{"$gt": thresholdMs}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []MongoProfileEntry
+	for cursor.Next(ctx) {
+		var doc struct {
+			Namespace   string   `bson:"ns"`
+			Operation   string   `bson:"op"`
+			Command     bson.Raw `bson:"command"`
+			Millis      int64    `bson:"millis"`
+			PlanSummary string   `bson:"planSummary"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			continue
+		}
+
+		entry := MongoProfileEntry{
+			Namespace:   doc.Namespace,
+			Operation:   doc.Operation,
+			Millis:      float64(doc.Millis),
+			PlanSummary: doc.PlanSummary,
+		}
+		if doc.Command != nil {
+			entry.Command = doc.Command.String()
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, cursor.Err()
+}
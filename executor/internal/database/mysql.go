@@ -5,15 +5,27 @@ import (
 	"database/sql"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 )
 
+// mysqlConfigEquivalents maps the Postgres parameters TuneConfigAction works with to the
+// closest MySQL variables. Parameters without an equivalent are skipped.
+var mysqlConfigEquivalents = map[string]string{
+	"work_mem": "sort_buffer_size",
+}
+
+// mysqlVariableName guards variable names interpolated into SHOW/SET statements.
+var mysqlVariableName = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
 type MySQLAdapter struct {
 	db           *sql.DB
 	databaseName string
+	version      string
+	onlineDDL    bool // ALTER TABLE ... ALGORITHM=INPLACE, LOCK=NONE (MySQL 5.6+, MariaDB 10.0+)
 }
 
 func NewMySQLAdapter(ctx context.Context, connectionString, databaseName string) (*MySQLAdapter, error) {
@@ -34,12 +46,34 @@ func NewMySQLAdapter(ctx context.Context, connectionString, databaseName string)
 		return nil, fmt.Errorf("failed to ping mysql: %w", err)
 	}
 
+	var version string
+	if err := db.QueryRowContext(ctx, "SELECT VERSION()").Scan(&version); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to get mysql version: %w", err)
+	}
+
 	return &MySQLAdapter{
 		db:           db,
 		databaseName: databaseName,
+		version:      version,
+		onlineDDL:    SupportsOnlineDDL(version),
 	}, nil
 }
 
+// SupportsOnlineDDL reports whether a server version ("8.0.36", "10.11.6-MariaDB") can add
+// an index without blocking writes.
+func SupportsOnlineDDL(version string) bool {
+	var major, minor int
+	if _, err := fmt.Sscanf(version, "%d.%d", &major, &minor); err != nil {
+		return false
+	}
+
+	if strings.Contains(strings.ToLower(version), "mariadb") {
+		return major >= 10
+	}
+	return major > 5 || (major == 5 && minor >= 6)
+}
+
 // convertMySQLConnectionString converts URL format to MySQL DSN format.
 func convertMySQLConnectionString(connStr string) string {
 	if strings.HasPrefix(connStr, "mysql://") {
@@ -88,23 +122,23 @@ func (m *MySQLAdapter) CreateIndex(ctx context.Context, params IndexParams) erro
 		indexType = "UNIQUE INDEX"
 	}
 
-	// MySQL doesn't support CONCURRENTLY, but we can use ALGORITHM=INPLACE LOCK=NONE for online DDL
-	query := fmt.Sprintf(
-		"ALTER TABLE %s ADD %s %s (%s) ALGORITHM=INPLACE, LOCK=NONE",
-		params.TableName, indexType, params.IndexName, columns,
-	)
-
-	_, err = m.db.ExecContext(ctx, query)
-	if err != nil {
-		// Fallback to standard CREATE INDEX if online DDL fails
+	// MySQL has no CONCURRENTLY; online DDL is the equivalent. Never fall back to a blocking
+	// build when the caller asked for a non-blocking one.
+	var query string
+	if params.Concurrent {
+		if !m.onlineDDL {
+			return fmt.Errorf("mysql %s does not support online index builds: %w", m.version, ErrActionNotSupported)
+		}
 		query = fmt.Sprintf(
-			"CREATE %s %s ON %s (%s)",
-			indexType, params.IndexName, params.TableName, columns,
+			"ALTER TABLE %s ADD %s %s (%s), ALGORITHM=INPLACE, LOCK=NONE",
+			params.TableName, indexType, params.IndexName, columns,
 		)
-		_, err = m.db.ExecContext(ctx, query)
-		if err != nil {
-			return fmt.Errorf("failed to create index: %w", err)
-		}
+	} else {
+		query = fmt.Sprintf("CREATE %s %s ON %s (%s)", indexType, params.IndexName, params.TableName, columns)
+	}
+
+	if _, err := m.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create index: %w", err)
 	}
 
 	return nil
@@ -170,12 +204,53 @@ func (m *MySQLAdapter) IndexExists(ctx context.Context, indexName string) (bool,
 	return count > 0, nil
 }
 
+func (m *MySQLAdapter) TableExists(ctx context.Context, tableName string) (bool, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA = DATABASE()
+		AND TABLE_NAME = ?
+	`
+
+	var count int
+	if err := m.db.QueryRowContext(ctx, query, tableName).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check table existence: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+func (m *MySQLAdapter) ColumnExists(ctx context.Context, tableName, columnName string) (bool, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = DATABASE()
+		AND TABLE_NAME = ?
+		AND COLUMN_NAME = ?
+	`
+
+	var count int
+	if err := m.db.QueryRowContext(ctx, query, tableName, columnName).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check column existence: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// GetCurrentConfig reads MySQL variables. Postgres parameter names are translated to their
+// MySQL equivalents and sizes reported in Postgres units ("16MB"), so TuneConfigAction can
+// compare and restore them unchanged; parameters without an equivalent are left out.
 func (m *MySQLAdapter) GetCurrentConfig(ctx context.Context, parameters []string) (map[string]string, error) {
 	config := make(map[string]string)
 
 	for _, param := range parameters {
+		variable, mapped := MySQLVariable(param)
+		if variable == "" {
+			continue
+		}
+
 		var varName, value string
-		query := fmt.Sprintf("SHOW VARIABLES LIKE '%s'", param)
+		query := fmt.Sprintf("SHOW GLOBAL VARIABLES LIKE '%s'", variable)
 		err := m.db.QueryRowContext(ctx, query).Scan(&varName, &value)
 		if err != nil {
 			if err == sql.ErrNoRows {
@@ -183,24 +258,107 @@ func (m *MySQLAdapter) GetCurrentConfig(ctx context.Context, parameters []string
 			}
 			return nil, fmt.Errorf("failed to get config for %s: %w", param, err)
 		}
+
+		if mapped {
+			value = FormatMySQLSize(value)
+		}
 		config[param] = value
 	}
 
 	return config, nil
 }
 
+// SetConfig applies changes with SET GLOBAL, translating Postgres parameter names and sizes
+// the same way GetCurrentConfig does. Changes do not survive a server restart.
 func (m *MySQLAdapter) SetConfig(ctx context.Context, changes map[string]string) error {
 	for param, value := range changes {
-		query := fmt.Sprintf("SET GLOBAL %s = '%s'", param, value)
-		_, err := m.db.ExecContext(ctx, query)
-		if err != nil {
-			return fmt.Errorf("failed to set %s to %s: %w", param, value, err)
+		variable, mapped := MySQLVariable(param)
+		if variable == "" {
+			return fmt.Errorf("no mysql equivalent for %s: %w", param, ErrActionNotSupported)
+		}
+
+		setting := value
+		if mapped {
+			bytes, err := ParseMySQLSize(value)
+			if err != nil {
+				return fmt.Errorf("invalid value for %s: %w", param, err)
+			}
+			setting = strconv.FormatInt(bytes, 10)
+		} else if _, err := strconv.ParseFloat(value, 64); err != nil {
+			setting = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+		}
+
+		query := fmt.Sprintf("SET GLOBAL %s = %s", variable, setting)
+		if _, err := m.db.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to set %s to %s: %w", variable, value, err)
 		}
 	}
 
 	return nil
 }
 
+// MySQLVariable returns the MySQL variable for a parameter and whether it was translated from
+// a Postgres name. It returns "" for Postgres-only parameters and invalid names.
+func MySQLVariable(param string) (string, bool) {
+	if variable, ok := mysqlConfigEquivalents[param]; ok {
+		return variable, true
+	}
+	if _, postgresOnly := postgresOnlyParameters[param]; postgresOnly {
+		return "", false
+	}
+	if !mysqlVariableName.MatchString(param) {
+		return "", false
+	}
+	return param, false
+}
+
+// postgresOnlyParameters are tuned on Postgres but have no MySQL counterpart.
+// effective_cache_size is only a planner hint, whereas innodb_buffer_pool_size allocates
+// the memory it names, so a size picked for Postgres could run the server out of memory.
+var postgresOnlyParameters = map[string]struct{}{
+	"random_page_cost":     {},
+	"effective_cache_size": {},
+}
+
+// FormatMySQLSize renders a byte count in Postgres units ("262144" -> "256kB").
+func FormatMySQLSize(value string) string {
+	bytes, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || bytes == 0 {
+		return value
+	}
+
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"kB", 1 << 10}} {
+		if bytes%unit.size == 0 {
+			return fmt.Sprintf("%d%s", bytes/unit.size, unit.suffix)
+		}
+	}
+
+	return value
+}
+
+// ParseMySQLSize converts a Postgres-style size ("16MB", "256kB", "4096") to bytes.
+func ParseMySQLSize(value string) (int64, error) {
+	units := map[string]int64{"GB": 1 << 30, "MB": 1 << 20, "kB": 1 << 10}
+
+	number, multiplier := value, int64(1)
+	for suffix, size := range units {
+		if strings.HasSuffix(value, suffix) {
+			number, multiplier = strings.TrimSuffix(value, suffix), size
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(strings.TrimSpace(number), 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%q is not a size", value)
+	}
+
+	return n * multiplier, nil
+}
+
 func (m *MySQLAdapter) GetSlowQueries(ctx context.Context, thresholdMs float64, limit int) ([]SlowQuery, error) {
 	// Query from performance_schema if available
 	query := `
@@ -282,6 +440,25 @@ func (m *MySQLAdapter) GetDeadTuples(ctx context.Context, tableName string) (int
 	return dataFree, nil
 }
 
+func (m *MySQLAdapter) GetBackendInfo(ctx context.Context, pid int32) (*BackendInfo, error) {
+	query := `
+		SELECT ID, COALESCE(USER, ''), COALESCE(INFO, ''), COALESCE(COMMAND, ''), COALESCE(HOST, '')
+		FROM information_schema.PROCESSLIST
+		WHERE ID = ?
+	`
+
+	info := &BackendInfo{}
+	err := m.db.QueryRowContext(ctx, query, pid).Scan(&info.PID, &info.Username, &info.Query, &info.State, &info.ClientAddr)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrBackendNotFound
+		}
+		return nil, fmt.Errorf("failed to get backend info for ID %d: %w", pid, err)
+	}
+
+	return info, nil
+}
+
 func (m *MySQLAdapter) TerminateQuery(ctx context.Context, pid int32, graceful bool) error {
 	// MySQL uses KILL command
 	// KILL QUERY only terminates the query, KILL terminates the connection
@@ -303,7 +480,7 @@ func (m *MySQLAdapter) TerminateQuery(ctx context.Context, pid int32, graceful b
 func (m *MySQLAdapter) GetCapabilities() Capabilities {
 	return Capabilities{
 		SupportsIndexes:              true,
-		SupportsConcurrentIndexes:    false, // MySQL doesn't have CONCURRENTLY
+		SupportsConcurrentIndexes:    m.onlineDDL, // online DDL rather than CONCURRENTLY
 		SupportsUniqueIndex:          true,
 		SupportsMultiColumnIndex:     true,
 		SupportsConfigTuning:         true,
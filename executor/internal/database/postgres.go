@@ -2,20 +2,45 @@ package database
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+const (
+	// executorApplicationName tags Executor connections in pg_stat_activity
+	executorApplicationName = "startupmonkey-executor"
+	// executorMaxConns caps the pool unless the connection string sets pool_max_conns;
+	// actions against one database share it, so it need not grow with the host's CPUs
+	executorMaxConns = 4
+)
+
 type PostgresAdapter struct {
 	pool         *pgxpool.Pool
 	databaseName string
 }
 
 func NewPostgresAdapter(ctx context.Context, connectionString, databaseName string) (*PostgresAdapter, error) {
-	pool, err := pgxpool.New(ctx, connectionString)
+	config, err := pgxpool.ParseConfig(connectionString)
+	if err != nil {
+		return nil, fmt.Errorf("invalid connection string: %w", err)
+	}
+
+	if !strings.Contains(connectionString, "pool_max_conns") {
+		config.MaxConns = executorMaxConns
+	}
+	if _, ok := config.ConnConfig.RuntimeParams["application_name"]; !ok {
+		config.ConnConfig.RuntimeParams["application_name"] = executorApplicationName
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create connection pool: %w", err)
 	}
@@ -95,6 +120,172 @@ func (p *PostgresAdapter) IndexExists(ctx context.Context, indexName string) (bo
 	return exists, nil
 }
 
+// GetIndexProgress reads pg_stat_progress_create_index for a build running on tableName.
+func (p *PostgresAdapter) GetIndexProgress(ctx context.Context, tableName string) (*IndexProgress, error) {
+	query := `
+		SELECT phase, blocks_done, blocks_total, tuples_done, tuples_total
+		FROM pg_stat_progress_create_index
+		WHERE relid = to_regclass($1)::oid
+		LIMIT 1
+	`
+
+	progress := &IndexProgress{}
+	err := p.pool.QueryRow(ctx, query, tableName).Scan(
+		&progress.Phase, &progress.BlocksDone, &progress.BlocksTotal, &progress.TuplesDone, &progress.TuplesTotal,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get index progress: %w", err)
+	}
+
+	return progress, nil
+}
+
+// GetIndexSize returns the on-disk size of an index in bytes.
+func (p *PostgresAdapter) GetIndexSize(ctx context.Context, indexName string) (int64, error) {
+	var size int64
+	err := p.pool.QueryRow(ctx, "SELECT COALESCE(pg_relation_size(to_regclass($1)), 0)", indexName).Scan(&size)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get index size: %w", err)
+	}
+
+	return size, nil
+}
+
+// GetIndexScans reads idx_scan for an index from pg_stat_user_indexes.
+func (p *PostgresAdapter) GetIndexScans(ctx context.Context, tableName, indexName string) (int64, error) {
+	var scans int64
+	err := p.pool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(idx_scan), 0)
+		FROM pg_stat_user_indexes
+		WHERE relname = $1 AND indexrelname = $2`, tableName, indexName).Scan(&scans)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get index scans: %w", err)
+	}
+
+	return scans, nil
+}
+
+// GetIndexDefinition returns the CREATE INDEX statement for an index from pg_get_indexdef.
+func (p *PostgresAdapter) GetIndexDefinition(ctx context.Context, indexName string) (string, error) {
+	var definition *string
+	err := p.pool.QueryRow(ctx, "SELECT pg_get_indexdef(to_regclass($1))", indexName).Scan(&definition)
+	if err != nil {
+		return "", fmt.Errorf("failed to get index definition: %w", err)
+	}
+
+	if definition == nil {
+		return "", nil
+	}
+
+	return *definition, nil
+}
+
+// RestoreIndex re-creates an index from a definition captured by GetIndexDefinition.
+// The build runs concurrently so re-creating it does not block writes to the table.
+func (p *PostgresAdapter) RestoreIndex(ctx context.Context, definition string) error {
+	if !strings.HasPrefix(definition, "CREATE ") {
+		return fmt.Errorf("not an index definition: %s", definition)
+	}
+
+	query := definition
+	if !strings.Contains(query, " CONCURRENTLY ") {
+		query = strings.Replace(query, " INDEX ", " INDEX CONCURRENTLY IF NOT EXISTS ", 1)
+	}
+
+	_, err := p.pool.Exec(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to restore index: %w", err)
+	}
+
+	return nil
+}
+
+// GetRolePasswordHash reads the role's stored password hash from pg_authid. Only
+// superusers can read pg_authid; other roles get "" rather than an error.
+func (p *PostgresAdapter) GetRolePasswordHash(ctx context.Context, role string) (string, error) {
+	var hash *string
+	err := p.pool.QueryRow(ctx, "SELECT rolpassword FROM pg_authid WHERE rolname = $1", role).Scan(&hash)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "42501" {
+			return "", nil
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read role password hash: %w", err)
+	}
+
+	if hash == nil {
+		return "", nil
+	}
+
+	return *hash, nil
+}
+
+// GetPasswordEncryption returns the server's password_encryption setting (md5 or scram-sha-256).
+func (p *PostgresAdapter) GetPasswordEncryption(ctx context.Context) (string, error) {
+	var encryption string
+	if err := p.pool.QueryRow(ctx, "SHOW password_encryption").Scan(&encryption); err != nil {
+		return "", fmt.Errorf("failed to read password_encryption: %w", err)
+	}
+
+	return encryption, nil
+}
+
+// TableExists checks the search path (or the given schema for schema.table names).
+// Unquoted identifiers are folded to lower case, matching how CreateIndex uses them.
+func (p *PostgresAdapter) TableExists(ctx context.Context, tableName string) (bool, error) {
+	schema, table := splitQualifiedName(tableName)
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM information_schema.tables
+			WHERE table_name = $1
+			AND (($2 = '' AND table_schema = ANY (current_schemas(false))) OR table_schema = $2)
+		)
+	`
+
+	var exists bool
+	if err := p.pool.QueryRow(ctx, query, table, schema).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check table existence: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (p *PostgresAdapter) ColumnExists(ctx context.Context, tableName, columnName string) (bool, error) {
+	schema, table := splitQualifiedName(tableName)
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM information_schema.columns
+			WHERE table_name = $1
+			AND column_name = $3
+			AND (($2 = '' AND table_schema = ANY (current_schemas(false))) OR table_schema = $2)
+		)
+	`
+
+	var exists bool
+	if err := p.pool.QueryRow(ctx, query, table, schema, strings.ToLower(columnName)).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check column existence: %w", err)
+	}
+
+	return exists, nil
+}
+
+// splitQualifiedName splits "schema.table" into its lower-cased parts; schema is empty if not given.
+func splitQualifiedName(name string) (string, string) {
+	name = strings.ToLower(name)
+	if schema, table, ok := strings.Cut(name, "."); ok {
+		return schema, table
+	}
+	return "", name
+}
+
 func (p *PostgresAdapter) GetCurrentConfig(ctx context.Context, parameters []string) (map[string]string, error) {
 	config := make(map[string]string)
 
@@ -129,6 +320,48 @@ func (p *PostgresAdapter) SetConfig(ctx context.Context, changes map[string]stri
 	return nil
 }
 
+// IsConfigPersisted checks postgresql.auto.conf, which ALTER SYSTEM writes, for the parameter.
+func (p *PostgresAdapter) IsConfigPersisted(ctx context.Context, parameter string) (bool, error) {
+	var persisted bool
+	err := p.pool.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM pg_file_settings
+			WHERE name = $1 AND sourcefile LIKE '%postgresql.auto.conf'
+		)`, parameter).Scan(&persisted)
+	if err != nil {
+		return false, fmt.Errorf("failed to check persisted config for %s: %w", parameter, err)
+	}
+	return persisted, nil
+}
+
+func (p *PostgresAdapter) ResetConfig(ctx context.Context, parameters []string) error {
+	for _, param := range parameters {
+		query := fmt.Sprintf("ALTER SYSTEM RESET %s", pgx.Identifier{param}.Sanitize())
+		if _, err := p.pool.Exec(ctx, query); err != nil {
+			return fmt.Errorf("failed to reset %s: %w", param, err)
+		}
+	}
+
+	if _, err := p.pool.Exec(ctx, "SELECT pg_reload_conf()"); err != nil {
+		return fmt.Errorf("failed to reload config: %w", err)
+	}
+
+	return nil
+}
+
+// ReadPersistedConfigFile reads postgresql.auto.conf with pg_read_file, which like ALTER
+// SYSTEM needs superuser or pg_read_server_files.
+func (p *PostgresAdapter) ReadPersistedConfigFile(ctx context.Context) (string, string, error) {
+	var dataDirectory, content string
+	if err := p.pool.QueryRow(ctx, "SHOW data_directory").Scan(&dataDirectory); err != nil {
+		return "", "", fmt.Errorf("failed to get data directory: %w", err)
+	}
+	if err := p.pool.QueryRow(ctx, "SELECT pg_read_file('postgresql.auto.conf')").Scan(&content); err != nil {
+		return "", "", fmt.Errorf("failed to read postgresql.auto.conf: %w", err)
+	}
+	return dataDirectory, content, nil
+}
+
 func (p *PostgresAdapter) GetSlowQueries(ctx context.Context, thresholdMs float64, limit int) ([]SlowQuery, error) {
 	query := `
 		SELECT 
@@ -173,6 +406,124 @@ func (p *PostgresAdapter) GetSlowQueries(ctx context.Context, thresholdMs float6
 	return slowQueries, nil
 }
 
+// ExplainQuery plans a SELECT with EXPLAIN (GENERIC_PLAN), which needs Postgres 16 and
+// never runs the query. The simple protocol is used so the server sees the $n placeholders
+// as parameters rather than pgx expecting values for them.
+func (p *PostgresAdapter) ExplainQuery(ctx context.Context, query string) (*QueryPlan, error) {
+	query = strings.TrimSpace(query)
+	lower := strings.ToLower(query)
+	if !(strings.HasPrefix(lower, "select") || strings.HasPrefix(lower, "with")) ||
+		strings.HasSuffix(query, "...") || strings.Contains(strings.TrimSuffix(query, ";"), ";") {
+		return nil, ErrActionNotSupported
+	}
+
+	conn, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	results, err := conn.Conn().PgConn().Exec(ctx, "EXPLAIN (GENERIC_PLAN, FORMAT JSON) "+numberPlaceholders(query)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain query: %w", err)
+	}
+	if len(results) == 0 || len(results[0].Rows) == 0 || len(results[0].Rows[0]) == 0 {
+		return nil, fmt.Errorf("EXPLAIN returned no plan")
+	}
+
+	return PlanFromExplainJSON(results[0].Rows[0][0])
+}
+
+// explainNode is a node of EXPLAIN (FORMAT JSON) output.
+type explainNode struct {
+	NodeType  string        `json:"Node Type"`
+	Relation  string        `json:"Relation Name"`
+	Filter    string        `json:"Filter"`
+	TotalCost float64       `json:"Total Cost"`
+	Plans     []explainNode `json:"Plans"`
+}
+
+// PlanFromExplainJSON summarises EXPLAIN (FORMAT JSON) output as a QueryPlan.
+func PlanFromExplainJSON(data []byte) (*QueryPlan, error) {
+	var explained []struct {
+		Plan explainNode `json:"Plan"`
+	}
+	if err := json.Unmarshal(data, &explained); err != nil {
+		return nil, fmt.Errorf("failed to parse query plan: %w", err)
+	}
+	if len(explained) == 0 {
+		return nil, fmt.Errorf("EXPLAIN returned no plan")
+	}
+
+	plan := &QueryPlan{TotalCost: explained[0].Plan.TotalCost}
+
+	var walk func(node explainNode)
+	walk = func(node explainNode) {
+		if node.NodeType == "Seq Scan" {
+			plan.SeqScans = append(plan.SeqScans, PlannedScan{
+				Table:   node.Relation,
+				Filter:  node.Filter,
+				Columns: filterColumns(node.Filter),
+			})
+		}
+		for _, child := range node.Plans {
+			walk(child)
+		}
+	}
+	walk(explained[0].Plan)
+
+	return plan, nil
+}
+
+var (
+	placeholderPattern  = regexp.MustCompile(`\$(\d+)`)
+	filterColumnPattern = regexp.MustCompile(`([a-z_][a-z0-9_]*)\)?(?:::[a-z ]+?)?\s*(?:=|<>|!=|<=|>=|<|>|~~\*?|!~~)`)
+)
+
+// numberPlaceholders replaces the "?" sanitiseQuery leaves for values with $n parameters
+// numbered after the ones pg_stat_statements already put there.
+func numberPlaceholders(query string) string {
+	next := 1
+	for _, match := range placeholderPattern.FindAllStringSubmatch(query, -1) {
+		if n, err := strconv.Atoi(match[1]); err == nil && n >= next {
+			next = n + 1
+		}
+	}
+
+	var b strings.Builder
+	for _, r := range query {
+		if r == '?' {
+			fmt.Fprintf(&b, "$%d", next)
+			next++
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// filterColumns returns the columns a plan's Filter compares, e.g. customer_id and status
+// in "((customer_id = $1) AND ((status)::text = 'open'::text))". Casts are not columns.
+func filterColumns(filter string) []string {
+	var columns []string
+	seen := make(map[string]bool)
+
+	for _, match := range filterColumnPattern.FindAllStringSubmatchIndex(filter, -1) {
+		start, end := match[2], match[3]
+		if strings.HasSuffix(filter[:start], "::") {
+			continue
+		}
+		column := filter[start:end]
+		if column == "and" || column == "or" || column == "not" || seen[column] {
+			continue
+		}
+		seen[column] = true
+		columns = append(columns, column)
+	}
+
+	return columns
+}
+
 func (p *PostgresAdapter) GetCapabilities() Capabilities {
 	return Capabilities{
 		SupportsIndexes:              true,
@@ -253,6 +604,81 @@ func (p *PostgresAdapter) VacuumTable(ctx context.Context, tableName string) err
 	return nil
 }
 
+func (p *PostgresAdapter) VacuumTableFull(ctx context.Context, tableName string) error {
+	query := fmt.Sprintf("VACUUM FULL ANALYZE %s", tableName)
+
+	if _, err := p.pool.Exec(ctx, query); err != nil {
+		return fmt.Errorf("failed to vacuum full table %s: %w", tableName, err)
+	}
+
+	return nil
+}
+
+func (p *PostgresAdapter) GetTableSize(ctx context.Context, tableName string) (int64, error) {
+	var size int64
+	err := p.pool.QueryRow(ctx, "SELECT pg_total_relation_size($1::regclass)", tableName).Scan(&size)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get size of %s: %w", tableName, err)
+	}
+
+	return size, nil
+}
+
+// GetTableOptions reads the table's reloptions, which pg_class stores as "name=value" strings.
+func (p *PostgresAdapter) GetTableOptions(ctx context.Context, tableName string, options []string) (map[string]string, error) {
+	var reloptions []string
+	err := p.pool.QueryRow(ctx, "SELECT reloptions FROM pg_class WHERE oid = $1::regclass", tableName).Scan(&reloptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage parameters for %s: %w", tableName, err)
+	}
+
+	wanted := make(map[string]bool, len(options))
+	for _, option := range options {
+		wanted[option] = true
+	}
+
+	values := make(map[string]string)
+	for _, reloption := range reloptions {
+		name, value, ok := strings.Cut(reloption, "=")
+		if ok && wanted[name] {
+			values[name] = value
+		}
+	}
+
+	return values, nil
+}
+
+func (p *PostgresAdapter) SetTableOptions(ctx context.Context, tableName string, options map[string]string) error {
+	if len(options) == 0 {
+		return nil
+	}
+
+	settings := make([]string, 0, len(options))
+	for name, value := range options {
+		settings = append(settings, fmt.Sprintf("%s = %s", name, quoteLiteral(value)))
+	}
+
+	query := fmt.Sprintf("ALTER TABLE %s SET (%s)", tableName, strings.Join(settings, ", "))
+	if _, err := p.pool.Exec(ctx, query); err != nil {
+		return fmt.Errorf("failed to set storage parameters on %s: %w", tableName, err)
+	}
+
+	return nil
+}
+
+func (p *PostgresAdapter) ResetTableOptions(ctx context.Context, tableName string, options []string) error {
+	if len(options) == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf("ALTER TABLE %s RESET (%s)", tableName, strings.Join(options, ", "))
+	if _, err := p.pool.Exec(ctx, query); err != nil {
+		return fmt.Errorf("failed to reset storage parameters on %s: %w", tableName, err)
+	}
+
+	return nil
+}
+
 func (p *PostgresAdapter) GetDeadTuples(ctx context.Context, tableName string) (int64, error) {
 	query := `
 		SELECT n_dead_tup 
@@ -269,6 +695,35 @@ func (p *PostgresAdapter) GetDeadTuples(ctx context.Context, tableName string) (
 	return deadTuples, nil
 }
 
+func (p *PostgresAdapter) GetBackendInfo(ctx context.Context, pid int32) (*BackendInfo, error) {
+	query := `
+		SELECT 
+			a.pid,
+			COALESCE(a.usename, ''),
+			COALESCE(a.query, ''),
+			COALESCE(a.state, ''),
+			COALESCE(a.application_name, ''),
+			COALESCE(host(a.client_addr), ''),
+			a.xact_start,
+			COALESCE(r.rolsuper, false)
+		FROM pg_stat_activity a
+		LEFT JOIN pg_roles r ON r.oid = a.usesysid
+		WHERE a.pid = $1
+	`
+
+	info := &BackendInfo{}
+	err := p.pool.QueryRow(ctx, query, pid).Scan(&info.PID, &info.Username, &info.Query, &info.State,
+		&info.ApplicationName, &info.ClientAddr, &info.XactStart, &info.IsSuperuser)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrBackendNotFound
+		}
+		return nil, fmt.Errorf("failed to get backend info for PID %d: %w", pid, err)
+	}
+
+	return info, nil
+}
+
 func (p *PostgresAdapter) TerminateQuery(ctx context.Context, pid int32, graceful bool) error {
 	var success bool
 	var query string
@@ -292,3 +747,8 @@ func (p *PostgresAdapter) TerminateQuery(ctx context.Context, pid int32, gracefu
 
 	return nil
 }
+
+// quoteLiteral quotes a value as a SQL string literal, for statements that take no parameters.
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -1,22 +1,116 @@
 package docker
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-connections/tlsconfig"
 )
 
+// Runtime is the set of container operations actions depend on. *Client implements it;
+// tests substitute a fake.
+type Runtime interface {
+	IsAvailable(ctx context.Context) error
+	PullImage(ctx context.Context, imageName string) error
+	CreateContainer(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, containerName string) (string, error)
+	StartContainer(ctx context.Context, containerID string) error
+	StopContainer(ctx context.Context, containerID string) error
+	RestartContainer(ctx context.Context, containerID string) error
+	RemoveContainer(ctx context.Context, containerID string) error
+	ContainerExists(ctx context.Context, containerName string) (bool, string, error)
+	IsContainerRunning(ctx context.Context, containerID string) (bool, error)
+	InspectState(ctx context.Context, containerID string) (*ContainerState, error)
+	UsedHostPorts(ctx context.Context) (map[int]bool, error)
+	HostPort(ctx context.Context, containerID, containerPort string) (int, error)
+	FindContainer(ctx context.Context, host string) (*ContainerEndpoint, error)
+	CreateVolume(ctx context.Context, name string) error
+	RemoveVolume(ctx context.Context, name string) error
+	CopyFileToContainer(ctx context.Context, containerID, dstDir, fileName string, content []byte, mode int64) error
+}
+
+// ContainerEndpoint is a running container reachable on a Docker network.
+type ContainerEndpoint struct {
+	Name      string
+	Network   string
+	IPAddress string
+}
+
+// IsDefaultBridge reports whether the endpoint is on Docker's default bridge network,
+// where containers cannot resolve each other by name.
+func (e *ContainerEndpoint) IsDefaultBridge() bool {
+	return e.Network == "bridge"
+}
+
 type Client struct {
 	cli *client.Client
 }
 
+// Config selects the Docker engine. An empty Host falls back to DOCKER_HOST and then the
+// local socket; a CertPath holding ca.pem, cert.pem and key.pem enables TLS for remote engines.
+type Config struct {
+	Host      string // e.g. unix:///var/run/docker.sock or tcp://docker-host:2376
+	TLSVerify bool   // verify the engine's certificate against ca.pem
+	CertPath  string
+}
+
+// Validate checks the host scheme and that TLS verification has certificates to verify with.
+func (c Config) Validate() error {
+	if c.Host != "" {
+		if _, err := client.ParseHostURL(c.Host); err != nil {
+			return fmt.Errorf("invalid Docker host %q: %w", c.Host, err)
+		}
+	}
+	if c.TLSVerify && c.CertPath == "" {
+		return fmt.Errorf("Docker TLS verification requires a certificate path")
+	}
+	return nil
+}
+
+// NewClient connects to the engine described by the DOCKER_* environment.
 func NewClient() (*Client, error) {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	return NewClientWithConfig(Config{})
+}
+
+// NewClientWithConfig connects to the engine in cfg, using the DOCKER_* environment for
+// anything cfg leaves unset. It does not contact the engine; see IsAvailable.
+func NewClientWithConfig(cfg Config) (*Client, error) {
+	opts := []client.Opt{client.FromEnv, client.WithAPIVersionNegotiation()}
+
+	// The TLS transport must be in place before WithHost configures it for the host
+	if cfg.CertPath != "" {
+		tlsc, err := tlsconfig.Client(tlsconfig.Options{
+			CAFile:             filepath.Join(cfg.CertPath, "ca.pem"),
+			CertFile:           filepath.Join(cfg.CertPath, "cert.pem"),
+			KeyFile:            filepath.Join(cfg.CertPath, "key.pem"),
+			InsecureSkipVerify: !cfg.TLSVerify,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load Docker TLS certificates: %w", err)
+		}
+		opts = append(opts, client.WithHTTPClient(&http.Client{
+			Transport:     &http.Transport{TLSClientConfig: tlsc},
+			CheckRedirect: client.CheckRedirect,
+		}))
+	}
+	if cfg.Host != "" {
+		opts = append(opts, client.WithHost(cfg.Host))
+	}
+
+	cli, err := client.NewClientWithOpts(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Docker client: %w", err)
 	}
@@ -24,6 +118,11 @@ func NewClient() (*Client, error) {
 	return &Client{cli: cli}, nil
 }
 
+// Host returns the engine address the client talks to.
+func (c *Client) Host() string {
+	return c.cli.DaemonHost()
+}
+
 func (c *Client) IsAvailable(ctx context.Context) error {
 	_, err := c.cli.Ping(ctx)
 	if err != nil {
@@ -39,8 +138,10 @@ func (c *Client) PullImage(ctx context.Context, imageName string) error {
 	}
 	defer out.Close()
 
-	// Consume the output to ensure pull completes
-	io.Copy(os.Stdout, out)
+	// Consume the output to ensure pull completes; a cancelled ctx aborts the read
+	if _, err := io.Copy(os.Stdout, out); err != nil {
+		return fmt.Errorf("failed to pull image %s: %w", imageName, err)
+	}
 	return nil
 }
 
@@ -67,6 +168,15 @@ func (c *Client) StopContainer(ctx context.Context, containerID string) error {
 	return nil
 }
 
+// RestartContainer stops and starts a container, giving its process time to shut down cleanly.
+func (c *Client) RestartContainer(ctx context.Context, containerID string) error {
+	timeout := 30 // seconds - Postgres checkpoints on shutdown
+	if err := c.cli.ContainerRestart(ctx, containerID, container.StopOptions{Timeout: &timeout}); err != nil {
+		return fmt.Errorf("failed to restart container: %w", err)
+	}
+	return nil
+}
+
 func (c *Client) RemoveContainer(ctx context.Context, containerID string) error {
 	if err := c.cli.ContainerRemove(ctx, containerID, types.ContainerRemoveOptions{Force: true}); err != nil {
 		return fmt.Errorf("failed to remove container: %w", err)
@@ -74,6 +184,50 @@ func (c *Client) RemoveContainer(ctx context.Context, containerID string) error
 	return nil
 }
 
+// CreateVolume creates a named volume. Creating a volume that already exists is a no-op.
+func (c *Client) CreateVolume(ctx context.Context, name string) error {
+	if _, err := c.cli.VolumeCreate(ctx, volume.CreateOptions{Name: name}); err != nil {
+		return fmt.Errorf("failed to create volume %s: %w", name, err)
+	}
+	return nil
+}
+
+func (c *Client) RemoveVolume(ctx context.Context, name string) error {
+	if err := c.cli.VolumeRemove(ctx, name, true); err != nil {
+		return fmt.Errorf("failed to remove volume %s: %w", name, err)
+	}
+	return nil
+}
+
+// CopyFileToContainer writes a single file into dstDir of a container. It works on created
+// but not yet started containers, including into mounted volumes, and goes through the
+// Docker API so it does not depend on the Executor sharing a filesystem with the host.
+func (c *Client) CopyFileToContainer(ctx context.Context, containerID, dstDir, fileName string, content []byte, mode int64) error {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	header := &tar.Header{
+		Name:    fileName,
+		Mode:    mode,
+		Size:    int64(len(content)),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to build archive for %s: %w", fileName, err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		return fmt.Errorf("failed to build archive for %s: %w", fileName, err)
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to build archive for %s: %w", fileName, err)
+	}
+
+	if err := c.cli.CopyToContainer(ctx, containerID, dstDir, &buf, types.CopyToContainerOptions{}); err != nil {
+		return fmt.Errorf("failed to copy %s into container: %w", fileName, err)
+	}
+	return nil
+}
+
 func (c *Client) ContainerExists(ctx context.Context, containerName string) (bool, string, error) {
 	containers, err := c.cli.ContainerList(ctx, types.ContainerListOptions{All: true})
 	if err != nil {
@@ -107,3 +261,158 @@ func (c *Client) IsContainerRunning(ctx context.Context, containerID string) (bo
 
 	return inspect.State.Running, nil
 }
+
+// ContainerState is the part of a container's inspect output the Executor watches after
+// deploying it.
+type ContainerState struct {
+	Running      bool
+	Restarting   bool
+	RestartCount int  // restarts by the restart policy since the container was created
+	OOMKilled    bool // the last exit was the kernel killing it for exceeding its memory limit
+	ExitCode     int
+	MemoryLimit  int64 // bytes: the container's limit, or the engine host's memory if unlimited; 0 if unknown
+}
+
+// InspectState reports whether a container is running, how often it has restarted and how
+// much memory it may use.
+func (c *Client) InspectState(ctx context.Context, containerID string) (*ContainerState, error) {
+	inspect, err := c.cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	state := &ContainerState{RestartCount: inspect.RestartCount}
+	if inspect.State != nil {
+		state.Running = inspect.State.Running
+		state.Restarting = inspect.State.Restarting
+		state.OOMKilled = inspect.State.OOMKilled
+		state.ExitCode = inspect.State.ExitCode
+	}
+
+	if inspect.HostConfig != nil && inspect.HostConfig.Memory > 0 {
+		state.MemoryLimit = inspect.HostConfig.Memory
+	} else if info, err := c.cli.Info(ctx); err == nil {
+		state.MemoryLimit = info.MemTotal
+	}
+	return state, nil
+}
+
+// UsedHostPorts returns the host ports bound by any container, running or stopped.
+// Stopped containers are included because they claim their ports again when started.
+func (c *Client) UsedHostPorts(ctx context.Context) (map[int]bool, error) {
+	containers, err := c.cli.ContainerList(ctx, types.ContainerListOptions{All: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	used := make(map[int]bool)
+	for _, ctr := range containers {
+		for _, port := range ctr.Ports {
+			if port.PublicPort != 0 {
+				used[int(port.PublicPort)] = true
+			}
+		}
+
+		// Ports are only listed for running containers - read stopped ones from their config
+		if ctr.State == "running" {
+			continue
+		}
+
+		inspect, err := c.cli.ContainerInspect(ctx, ctr.ID)
+		if err != nil || inspect.HostConfig == nil {
+			continue
+		}
+
+		for _, bindings := range inspect.HostConfig.PortBindings {
+			for _, binding := range bindings {
+				if port, err := strconv.Atoi(binding.HostPort); err == nil {
+					used[port] = true
+				}
+			}
+		}
+	}
+
+	return used, nil
+}
+
+// HostPort returns the host port a container's port (e.g. "6432/tcp") is bound to.
+func (c *Client) HostPort(ctx context.Context, containerID, containerPort string) (int, error) {
+	inspect, err := c.cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	if inspect.HostConfig == nil {
+		return 0, fmt.Errorf("container %s has no host config", containerID)
+	}
+
+	for _, binding := range inspect.HostConfig.PortBindings[nat.Port(containerPort)] {
+		if port, err := strconv.Atoi(binding.HostPort); err == nil {
+			return port, nil
+		}
+	}
+
+	return 0, fmt.Errorf("container %s does not publish %s", containerID, containerPort)
+}
+
+// FindContainer returns the running container that host refers to - by container name,
+// network alias or IP address - or nil if host is not a container the daemon knows.
+// User-defined networks are preferred since they resolve container names.
+func (c *Client) FindContainer(ctx context.Context, host string) (*ContainerEndpoint, error) {
+	containers, err := c.cli.ContainerList(ctx, types.ContainerListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	for _, ctr := range containers {
+		if ctr.NetworkSettings == nil {
+			continue
+		}
+
+		name := ""
+		matched := false
+		for _, n := range ctr.Names {
+			n = strings.TrimPrefix(n, "/")
+			if name == "" {
+				name = n
+			}
+			if n == host {
+				matched = true
+			}
+		}
+
+		var found *ContainerEndpoint
+		for networkName, endpoint := range ctr.NetworkSettings.Networks {
+			if endpoint == nil {
+				continue
+			}
+
+			onNetwork := matched || endpoint.IPAddress == host ||
+				containsString(endpoint.Aliases, host) || containsString(endpoint.DNSNames, host)
+			if !onNetwork {
+				continue
+			}
+
+			candidate := &ContainerEndpoint{Name: name, Network: networkName, IPAddress: endpoint.IPAddress}
+			if found == nil || (found.IsDefaultBridge() && !candidate.IsDefaultBridge()) ||
+				(found.IsDefaultBridge() == candidate.IsDefaultBridge() && candidate.Network < found.Network) {
+				found = candidate
+			}
+		}
+
+		if found != nil {
+			return found, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
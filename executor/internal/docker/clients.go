@@ -0,0 +1,114 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// pgbouncerAdminDatabase is PgBouncer's admin console, where SHOW CLIENTS runs.
+const pgbouncerAdminDatabase = "pgbouncer"
+
+// PgBouncerClientCount connects to PgBouncer's admin console and counts the clients
+// connected to its pooled databases. Connections to the console itself, including this one,
+// are not counted. The user in connString must be listed in PgBouncer's admin_users or
+// stats_users.
+func PgBouncerClientCount(ctx context.Context, connString string) (int, error) {
+	config, err := pgx.ParseConfig(connString)
+	if err != nil {
+		return 0, fmt.Errorf("invalid connection string: %w", err)
+	}
+	config.Database = pgbouncerAdminDatabase
+	config.ConnectTimeout = probeDialTimeout
+	// The admin console only speaks the simple query protocol
+	config.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+
+	conn, err := pgx.ConnectConfig(ctx, config)
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect to the PgBouncer admin console: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	rows, err := conn.Query(ctx, "SHOW CLIENTS")
+	if err != nil {
+		return 0, fmt.Errorf("SHOW CLIENTS failed: %w", err)
+	}
+	defer rows.Close()
+
+	databaseColumn := -1
+	for i, field := range rows.FieldDescriptions() {
+		if field.Name == "database" {
+			databaseColumn = i
+		}
+	}
+	if databaseColumn < 0 {
+		return 0, fmt.Errorf("SHOW CLIENTS returned no database column")
+	}
+
+	clients := 0
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			return 0, fmt.Errorf("failed to read SHOW CLIENTS: %w", err)
+		}
+		if db, _ := values[databaseColumn].(string); db != pgbouncerAdminDatabase {
+			clients++
+		}
+	}
+	return clients, rows.Err()
+}
+
+// RedisClientCount sends CLIENT LIST to the Redis at addr and counts the connected clients
+// other than this one.
+func RedisClientCount(ctx context.Context, addr string) (int, error) {
+	dialer := net.Dialer{Timeout: probeDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(probeDialTimeout))
+
+	if _, err := conn.Write([]byte("*2\r\n$6\r\nCLIENT\r\n$4\r\nLIST\r\n")); err != nil {
+		return 0, fmt.Errorf("failed to send CLIENT LIST: %w", err)
+	}
+
+	// The reply is a bulk string with one line per client: $<length>\r\n<lines>\r\n
+	reader := bufio.NewReader(conn)
+	header, err := reader.ReadString('\n')
+	if err != nil {
+		return 0, fmt.Errorf("no reply to CLIENT LIST: %w", err)
+	}
+	header = strings.TrimSpace(header)
+	if !strings.HasPrefix(header, "$") {
+		return 0, fmt.Errorf("unexpected reply to CLIENT LIST: %q", header)
+	}
+
+	length, err := strconv.Atoi(header[1:])
+	if err != nil || length < 0 {
+		return 0, fmt.Errorf("unexpected reply to CLIENT LIST: %q", header)
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return 0, fmt.Errorf("failed to read CLIENT LIST: %w", err)
+	}
+
+	clients := 0
+	for _, line := range strings.Split(string(body), "\n") {
+		if strings.TrimSpace(line) != "" {
+			clients++
+		}
+	}
+
+	// This connection is always in the list
+	return max(clients-1, 0), nil
+}
@@ -0,0 +1,74 @@
+package docker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-units"
+)
+
+// ResourceLimits caps the memory and CPU of a container the Executor deploys. A zero
+// field leaves that resource unlimited.
+type ResourceLimits struct {
+	MemoryBytes int64
+	NanoCPUs    int64 // CPUs in units of 1e-9, as Docker's --cpus flag sets them
+}
+
+// ParseResourceLimits reads a memory size such as "512m" or "1g" and a CPU count such
+// as "0.5", the same forms docker run's --memory and --cpus flags accept. Empty values
+// and "0" leave the resource unlimited.
+func ParseResourceLimits(memory, cpus string) (ResourceLimits, error) {
+	var limits ResourceLimits
+
+	if memory = strings.TrimSpace(memory); memory != "" && memory != "0" {
+		bytes, err := units.RAMInBytes(memory)
+		if err != nil || bytes <= 0 {
+			return ResourceLimits{}, fmt.Errorf("invalid memory limit %q: want a size such as 512m or 1g", memory)
+		}
+		limits.MemoryBytes = bytes
+	}
+
+	if cpus = strings.TrimSpace(cpus); cpus != "" && cpus != "0" {
+		count, err := strconv.ParseFloat(cpus, 64)
+		if err != nil || count <= 0 {
+			return ResourceLimits{}, fmt.Errorf("invalid CPU limit %q: want a number of CPUs such as 0.5", cpus)
+		}
+		limits.NanoCPUs = int64(count * 1e9)
+	}
+
+	return limits, nil
+}
+
+// Resources renders the limits for a container's HostConfig.
+func (l ResourceLimits) Resources() container.Resources {
+	return container.Resources{
+		Memory:   l.MemoryBytes,
+		NanoCPUs: l.NanoCPUs,
+	}
+}
+
+// Changes renders the limits for an action's Changes map, omitting unlimited resources.
+func (l ResourceLimits) Changes() map[string]interface{} {
+	changes := map[string]interface{}{}
+	if l.MemoryBytes > 0 {
+		changes["memory_bytes"] = l.MemoryBytes
+	}
+	if l.NanoCPUs > 0 {
+		changes["cpus"] = float64(l.NanoCPUs) / 1e9
+	}
+	return changes
+}
+
+// String describes the limits in docker run's terms, e.g. "memory=512MiB cpus=1".
+func (l ResourceLimits) String() string {
+	memory, cpus := "unlimited", "unlimited"
+	if l.MemoryBytes > 0 {
+		memory = units.BytesSize(float64(l.MemoryBytes))
+	}
+	if l.NanoCPUs > 0 {
+		cpus = strconv.FormatFloat(float64(l.NanoCPUs)/1e9, 'f', -1, 64)
+	}
+	return fmt.Sprintf("memory=%s cpus=%s", memory, cpus)
+}
@@ -0,0 +1,176 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	probeInitialBackoff = 250 * time.Millisecond
+	probeMaxBackoff     = 5 * time.Second
+	probeDialTimeout    = 3 * time.Second
+)
+
+// ReadinessProbe checks that the service inside a container answers requests - a container
+// can be "running" while the process inside crash-loops on bad config. It returns a short
+// description of what answered.
+type ReadinessProbe func(ctx context.Context) (string, error)
+
+// ProbeSettings controls how deployed containers are probed.
+type ProbeSettings struct {
+	Host    string        // host the Executor dials to reach published ports
+	Timeout time.Duration // how long to keep retrying before giving up
+}
+
+// DefaultProbeSettings probes localhost for up to 30 seconds.
+func DefaultProbeSettings() ProbeSettings {
+	return ProbeSettings{
+		Host:    "localhost",
+		Timeout: 30 * time.Second,
+	}
+}
+
+// ProbeResult summarises a WaitForReady run.
+type ProbeResult struct {
+	Passed    bool
+	Attempts  int
+	Elapsed   time.Duration
+	Detail    string // what the service answered on success
+	LastError string // why the final attempt failed
+}
+
+// Changes renders the result for an action's Changes map.
+func (r ProbeResult) Changes() map[string]interface{} {
+	changes := map[string]interface{}{
+		"passed":     r.Passed,
+		"attempts":   r.Attempts,
+		"elapsed_ms": r.Elapsed.Milliseconds(),
+	}
+	if r.Detail != "" {
+		changes["detail"] = r.Detail
+	}
+	if r.LastError != "" {
+		changes["last_error"] = r.LastError
+	}
+	return changes
+}
+
+// WaitForReady runs probe until it passes, retrying with exponential backoff until timeout
+// elapses. The probe always runs at least once.
+func WaitForReady(ctx context.Context, probe ReadinessProbe, timeout time.Duration) ProbeResult {
+	start := time.Now()
+	deadline := start.Add(timeout)
+	backoff := probeInitialBackoff
+
+	var result ProbeResult
+	for {
+		result.Attempts++
+
+		detail, err := probe(ctx)
+		if err == nil {
+			result.Passed = true
+			result.Detail = detail
+			result.LastError = ""
+			break
+		}
+		result.LastError = err.Error()
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			break
+		}
+
+		wait := min(backoff, remaining)
+		backoff = min(backoff*2, probeMaxBackoff)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			result.LastError = ctx.Err().Error()
+			result.Elapsed = time.Since(start)
+			return result
+		case <-timer.C:
+		}
+	}
+
+	result.Elapsed = time.Since(start)
+	return result
+}
+
+// RedisPingProbe dials addr and expects +PONG in reply to PING.
+func RedisPingProbe(addr string) ReadinessProbe {
+	return func(ctx context.Context) (string, error) {
+		dialer := net.Dialer{Timeout: probeDialTimeout}
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return "", err
+		}
+		defer conn.Close()
+
+		conn.SetDeadline(time.Now().Add(probeDialTimeout))
+
+		if _, err := conn.Write([]byte("*1\r\n$4\r\nPING\r\n")); err != nil {
+			return "", fmt.Errorf("failed to send PING: %w", err)
+		}
+
+		reply, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			return "", fmt.Errorf("no reply to PING: %w", err)
+		}
+
+		reply = strings.TrimSpace(reply)
+		if reply != "+PONG" {
+			return "", fmt.Errorf("unexpected reply to PING: %q", reply)
+		}
+
+		return "PONG", nil
+	}
+}
+
+// PgBouncerProbe connects through the pooler and asks the backend for its version. PgBouncer's
+// own SHOW VERSION only exists on its admin console, which application users cannot reach;
+// querying through the pool also proves auth and the route to the database work.
+func PgBouncerProbe(connString string) ReadinessProbe {
+	return func(ctx context.Context) (string, error) {
+		config, err := pgx.ParseConfig(connString)
+		if err != nil {
+			return "", fmt.Errorf("invalid connection string: %w", err)
+		}
+		config.ConnectTimeout = probeDialTimeout
+		// Prepared statements break under transaction pooling
+		config.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+
+		conn, err := pgx.ConnectConfig(ctx, config)
+		if err != nil {
+			return "", err
+		}
+		defer conn.Close(context.Background())
+
+		var version string
+		if err := conn.QueryRow(ctx, "SHOW server_version").Scan(&version); err != nil {
+			return "", fmt.Errorf("query through PgBouncer failed: %w", err)
+		}
+
+		return "PostgreSQL " + version, nil
+	}
+}
+
+// TCPProbe only checks that addr accepts connections.
+func TCPProbe(addr string) ReadinessProbe {
+	return func(ctx context.Context) (string, error) {
+		dialer := net.Dialer{Timeout: probeDialTimeout}
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return "", err
+		}
+		conn.Close()
+		return "accepting connections", nil
+	}
+}
@@ -2,14 +2,19 @@ package eventbus
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/logging"
 	"github.com/nats-io/nats.go"
 )
 
+// ActionCompletedEvent is published to "actions.completed" when an action's outcome settles:
+// completed, failed or rolled back. Solution is only set for completed actions.
 type ActionCompletedEvent struct {
 	ActionID     string `json:"action_id"`
 	DetectionID  string `json:"detection_id"`
@@ -20,13 +25,29 @@ type ActionCompletedEvent struct {
 	Solution     string `json:"solution"`
 	Message      string `json:"message"`
 	Timestamp    int64  `json:"timestamp"`
+
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+// DetectionFailedEvent is published to "detections.failed" when the Executor cannot act on a detection.
+type DetectionFailedEvent struct {
+	Detection *models.Detection `json:"detection"`
+	Reason    string            `json:"reason"`
+	Timestamp int64             `json:"timestamp"`
 }
 
+// ErrDeadLettered marks a detection that was routed to "detections.failed".
+// Redelivering it would fail the same way, so subscribers acknowledge it.
+var ErrDeadLettered = errors.New("detection dead-lettered")
+
 type Publisher struct {
 	conn *nats.Conn
+	js   nats.JetStreamContext // nil when running on core NATS
 }
 
-func NewPublisher(natsURL string) (*Publisher, error) {
+// NewPublisher connects to NATS. With useJetStream, action events are persisted
+// to the ACTIONS stream so consumers that are down do not miss them.
+func NewPublisher(natsURL string, useJetStream bool) (*Publisher, error) {
 	conn, err := nats.Connect(natsURL,
 		nats.RetryOnFailedConnect(true),
 		nats.MaxReconnects(10),
@@ -38,9 +59,35 @@ func NewPublisher(natsURL string) (*Publisher, error) {
 
 	log.Printf("Executor Pub connected to NATS: %s", natsURL)
 
-	return &Publisher{
+	publisher := &Publisher{
 		conn: conn,
-	}, nil
+	}
+
+	if useJetStream {
+		js, err := conn.JetStream()
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+		}
+
+		if err := ensureStreams(js); err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		publisher.js = js
+	}
+
+	return publisher, nil
+}
+
+// publish sends to JetStream when enabled, otherwise to core NATS.
+func (p *Publisher) publish(subject string, data []byte) error {
+	if p.js != nil {
+		_, err := p.js.Publish(subject, data)
+		return err
+	}
+	return p.conn.Publish(subject, data)
 }
 
 func (p *Publisher) PublishActionStatus(result *models.ActionResult) error {
@@ -49,7 +96,7 @@ func (p *Publisher) PublishActionStatus(result *models.ActionResult) error {
 		return err
 	}
 
-	if err := p.conn.Publish("actions.status", data); err != nil {
+	if err := p.publish("actions.status", data); err != nil {
 		return err
 	}
 
@@ -58,9 +105,32 @@ func (p *Publisher) PublishActionStatus(result *models.ActionResult) error {
 	return nil
 }
 
+// PublishActionProgress publishes progress of a running action to "actions.status.progress".
+// Progress is transient, so it goes over core NATS rather than the durable ACTIONS stream.
+func (p *Publisher) PublishActionProgress(result *models.ActionResult) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal action progress: %w", err)
+	}
+
+	if err := p.conn.Publish("actions.status.progress", data); err != nil {
+		return fmt.Errorf("failed to publish to actions.status.progress: %w", err)
+	}
+
+	return nil
+}
+
 func (p *Publisher) PublishActionCompleted(result *models.ActionResult, detection *models.Detection) error {
-	solution := generateSolution(result, detection)
+	return p.publishActionOutcome(result, detection, generateSolution(result, detection))
+}
+
+// PublishActionUndone publishes a failed or rolled back action to "actions.completed", so the
+// Analyser reopens its detection instead of waiting for a fix that is not in place.
+func (p *Publisher) PublishActionUndone(result *models.ActionResult, detection *models.Detection) error {
+	return p.publishActionOutcome(result, detection, "")
+}
 
+func (p *Publisher) publishActionOutcome(result *models.ActionResult, detection *models.Detection, solution string) error {
 	event := ActionCompletedEvent{
 		ActionID:     result.ActionID,
 		DetectionID:  detection.DetectionID,
@@ -71,6 +141,8 @@ func (p *Publisher) PublishActionCompleted(result *models.ActionResult, detectio
 		Solution:     solution,
 		Message:      result.Message,
 		Timestamp:    time.Now().Unix(),
+
+		CorrelationID: detection.CorrelationID,
 	}
 
 	data, err := json.Marshal(event)
@@ -78,11 +150,54 @@ func (p *Publisher) PublishActionCompleted(result *models.ActionResult, detectio
 		return fmt.Errorf("failed to marshal action completion: %w", err)
 	}
 
-	if err := p.conn.Publish("actions.completed", data); err != nil {
+	if err := p.publish("actions.completed", data); err != nil {
 		return fmt.Errorf("failed to published data to actions.completed: %w", err)
 	}
 
-	log.Printf("Published completed action: %s -> %s", result.ActionID, solution)
+	logging.With(detection.CorrelationID).Info("Published action outcome", "action_id", result.ActionID, "status", result.Status, "solution", solution)
+
+	return nil
+}
+
+// RegressionSubject carries detections the Executor raises about its own fixes failing,
+// e.g. a container it deployed that keeps restarting.
+const RegressionSubject = "detections.regression"
+
+// PublishRegression publishes a detection about a regressed action to "detections.regression".
+// Knowledge keeps the regressed action status durably, so core NATS is enough.
+func (p *Publisher) PublishRegression(detection *models.Detection) error {
+	data, err := json.Marshal(detection)
+	if err != nil {
+		return fmt.Errorf("failed to marshal regression: %w", err)
+	}
+
+	if err := p.conn.Publish(RegressionSubject, data); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", RegressionSubject, err)
+	}
+
+	log.Printf("Published regression detection: %s", detection.Title)
+
+	return nil
+}
+
+// PublishDetectionFailed publishes the original detection and failure reason to "detections.failed".
+func (p *Publisher) PublishDetectionFailed(detection *models.Detection, reason string) error {
+	event := DetectionFailedEvent{
+		Detection: detection,
+		Reason:    reason,
+		Timestamp: time.Now().Unix(),
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal detection failure: %w", err)
+	}
+
+	if err := p.publish("detections.failed", data); err != nil {
+		return fmt.Errorf("failed to publish to detections.failed: %w", err)
+	}
+
+	log.Printf("Published dead-lettered detection: %s (%s)", detection.DetectionID, reason)
 
 	return nil
 }
@@ -100,6 +215,14 @@ func generateSolution(result *models.ActionResult, detection *models.Detection)
 			if c, ok := detection.ActionMetaData["column_name"].(string); ok {
 				columnName = c
 			}
+			// Multi-column indexes name every column in order
+			if cols, ok := detection.ActionMetaData["column_names"].([]interface{}); ok && len(cols) > 1 {
+				names := make([]string, 0, len(cols))
+				for _, col := range cols {
+					names = append(names, fmt.Sprint(col))
+				}
+				columnName = strings.Join(names, "_")
+			}
 		}
 
 		if tableName != "" && columnName != "" {
@@ -108,6 +231,14 @@ func generateSolution(result *models.ActionResult, detection *models.Detection)
 		}
 		return "index_created:unknown"
 
+	case "drop_index":
+		if detection.ActionMetaData != nil {
+			if indexName, ok := detection.ActionMetaData["index_name"].(string); ok && indexName != "" {
+				return fmt.Sprintf("index_dropped:%s", indexName)
+			}
+		}
+		return "index_dropped:unknown"
+
 	case "deploy_pgbouncer":
 		return "pgbouncer_deployed"
 
@@ -2,10 +2,15 @@ package eventbus
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"log/slog"
+	"sync/atomic"
 	"time"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/logging"
 	"github.com/nats-io/nats.go"
 )
 
@@ -23,7 +28,7 @@ type RollbackRequest struct {
 }
 
 type RollbackProcessor interface {
-	RollbackAction(actionID string) (*models.ActionResult, error)
+	RollbackFailedVerification(actionID, reason string) (*models.ActionResult, error)
 }
 
 type ApprovalRequest struct {
@@ -37,6 +42,7 @@ type ApprovalProcessor interface {
 
 type Subscriber struct {
 	conn              *nats.Conn
+	js                nats.JetStreamContext // nil when running on core NATS
 	detectionSub      *nats.Subscription
 	rollbackSub       *nats.Subscription
 	approveSub        *nats.Subscription
@@ -44,9 +50,12 @@ type Subscriber struct {
 	processor         DetectionProcessor
 	rollbackProcessor RollbackProcessor
 	approvalProcessor ApprovalProcessor
+	stopping          atomic.Bool // set by Drain; buffered messages are released, not handled
 }
 
-func NewSubscriber(natsURL string, processor DetectionProcessor, rollbackProcessor RollbackProcessor, approvalProcessor ApprovalProcessor) (*Subscriber, error) {
+// NewSubscriber connects to NATS. With useJetStream, detections are consumed from a
+// durable JetStream consumer so nothing published while the Executor is down is lost.
+func NewSubscriber(natsURL string, useJetStream bool, processor DetectionProcessor, rollbackProcessor RollbackProcessor, approvalProcessor ApprovalProcessor) (*Subscriber, error) {
 	conn, err := nats.Connect(natsURL,
 		nats.RetryOnFailedConnect(true),
 		nats.MaxReconnects(10),
@@ -59,12 +68,39 @@ func NewSubscriber(natsURL string, processor DetectionProcessor, rollbackProcess
 
 	log.Printf("Connected to NATS at %s", natsURL)
 
-	return &Subscriber{
+	subscriber := &Subscriber{
 		conn:              conn,
 		processor:         processor,
 		rollbackProcessor: rollbackProcessor,
 		approvalProcessor: approvalProcessor,
-	}, nil
+	}
+
+	if useJetStream {
+		js, err := conn.JetStream()
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+		}
+
+		if err := ensureStreams(js); err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		if err := ensureConsumer(js, detectionsStream, detectionsConsumer, "detections"); err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		if err := ensureConsumer(js, actionsStream, rollbacksConsumer, RollbackRequestSubject); err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		subscriber.js = js
+	}
+
+	return subscriber, nil
 }
 
 func (s *Subscriber) Start() error {
@@ -72,24 +108,36 @@ func (s *Subscriber) Start() error {
 
 	// Detection subscription
 	log.Printf("Subscribing to 'detections'")
-	s.detectionSub, err = s.conn.Subscribe("detections", func(msg *nats.Msg) {
-		s.handleDetectionMessage(msg)
-	})
+	if s.js != nil {
+		s.detectionSub, err = s.js.Subscribe("detections", func(msg *nats.Msg) {
+			s.handleDetectionMessage(msg)
+		}, nats.Bind(detectionsStream, detectionsConsumer), nats.ManualAck())
+	} else {
+		s.detectionSub, err = s.conn.Subscribe("detections", func(msg *nats.Msg) {
+			s.handleDetectionMessage(msg)
+		})
+	}
 	if err != nil {
 		return err
 	}
-	log.Printf("Subscribed to 'detections'")
+	log.Printf("Subscribed to 'detections' (jetstream=%v)", s.js != nil)
 
 	// Rollback subscription
 	if s.rollbackProcessor != nil {
-		log.Printf("Subscribing to 'rollback.requested'")
-		s.rollbackSub, err = s.conn.Subscribe("rollback.requested", func(msg *nats.Msg) {
-			s.handleRollbackMessage(msg)
-		})
+		log.Printf("Subscribing to '%s'", RollbackRequestSubject)
+		if s.js != nil {
+			s.rollbackSub, err = s.js.Subscribe(RollbackRequestSubject, func(msg *nats.Msg) {
+				s.handleRollbackMessage(msg)
+			}, nats.Bind(actionsStream, rollbacksConsumer), nats.ManualAck())
+		} else {
+			s.rollbackSub, err = s.conn.Subscribe(RollbackRequestSubject, func(msg *nats.Msg) {
+				s.handleRollbackMessage(msg)
+			})
+		}
 		if err != nil {
 			return err
 		}
-		log.Printf("Subscribed to 'rollback.requested'")
+		log.Printf("Subscribed to '%s' (jetstream=%v)", RollbackRequestSubject, s.js != nil)
 	}
 
 	// Approval subscriptions
@@ -117,39 +165,69 @@ func (s *Subscriber) Start() error {
 }
 
 func (s *Subscriber) handleDetectionMessage(msg *nats.Msg) {
-	log.Printf("Received detection from event bus (%d bytes)", len(msg.Data))
+	if s.stopping.Load() {
+		s.release(msg)
+		return
+	}
+
+	slog.Debug("Received detection from event bus", "bytes", len(msg.Data))
 
 	var detection models.Detection
 	if err := json.Unmarshal(msg.Data, &detection); err != nil {
-		log.Printf("Failed to unmarshal detection: %v", err)
+		slog.Error("Failed to unmarshal detection", "error", err)
+		s.term(msg)
 		return
 	}
 
+	logger := logging.With(detection.CorrelationID).With("detection_id", detection.DetectionID)
+
 	result, err := s.processor.HandleDetection(&detection)
+	if errors.Is(err, ErrDeadLettered) {
+		logger.Warn("Detection could not be actioned", "error", err)
+		s.term(msg)
+		return
+	}
 	if err != nil {
-		log.Printf("Failed to handle detection: %v", err)
+		logger.Error("Failed to handle detection", "error", err)
+		s.nak(msg)
 		return
 	}
 
+	s.ack(msg)
+
 	if result != nil {
-		log.Printf("Detection processed successfully (Action ID: %s)", result.ActionID)
+		logger.Info("Detection processed", "action_id", result.ActionID)
 	}
 }
 
 func (s *Subscriber) handleRollbackMessage(msg *nats.Msg) {
+	if s.stopping.Load() {
+		s.release(msg)
+		return
+	}
+
 	log.Printf("Received rollback request from event bus (%d bytes)", len(msg.Data))
 
 	var request RollbackRequest
 	if err := json.Unmarshal(msg.Data, &request); err != nil {
-		log.Printf("Failed to unmarshal rollback request: %v", err)
+		slog.Error("Failed to unmarshal rollback request", "error", err)
+		s.term(msg)
+		return
+	}
+	if request.ActionID == "" {
+		log.Printf("Ignoring rollback request without an action_id")
+		s.term(msg)
 		return
 	}
 
 	log.Printf("Processing autonomous rollback: action=%s reason=%s", request.ActionID, request.Reason)
 
-	result, err := s.rollbackProcessor.RollbackAction(request.ActionID)
+	// A failed rollback is not redelivered: retrying against a database in an unknown
+	// state could do more harm, so it is left for the operator
+	result, err := s.rollbackProcessor.RollbackFailedVerification(request.ActionID, request.Reason)
+	s.ack(msg)
 	if err != nil {
-		log.Printf("Autonomous rollback failed: %v", err)
+		slog.Error("Autonomous rollback failed", "error", err)
 		return
 	}
 
@@ -157,11 +235,16 @@ func (s *Subscriber) handleRollbackMessage(msg *nats.Msg) {
 }
 
 func (s *Subscriber) handleApproveMessage(msg *nats.Msg) {
+	if s.stopping.Load() {
+		log.Printf("Ignoring approval request received during shutdown")
+		return
+	}
+
 	log.Printf("Received approval request from event bus (%d bytes)", len(msg.Data))
 
 	var request ApprovalRequest
 	if err := json.Unmarshal(msg.Data, &request); err != nil {
-		log.Printf("Failed to unmarshal approval request: %v", err)
+		slog.Error("Failed to unmarshal approval request", "error", err)
 		return
 	}
 
@@ -169,7 +252,7 @@ func (s *Subscriber) handleApproveMessage(msg *nats.Msg) {
 
 	result, err := s.approvalProcessor.ApproveAction(request.ActionID)
 	if err != nil {
-		log.Printf("Action approval failed: %v", err)
+		slog.Error("Action approval failed", "error", err)
 		return
 	}
 
@@ -177,11 +260,16 @@ func (s *Subscriber) handleApproveMessage(msg *nats.Msg) {
 }
 
 func (s *Subscriber) handleRejectMessage(msg *nats.Msg) {
+	if s.stopping.Load() {
+		log.Printf("Ignoring rejection request received during shutdown")
+		return
+	}
+
 	log.Printf("Received rejection request from event bus (%d bytes)", len(msg.Data))
 
 	var request ApprovalRequest
 	if err := json.Unmarshal(msg.Data, &request); err != nil {
-		log.Printf("Failed to unmarshal rejection request: %v", err)
+		slog.Error("Failed to unmarshal rejection request", "error", err)
 		return
 	}
 
@@ -189,13 +277,40 @@ func (s *Subscriber) handleRejectMessage(msg *nats.Msg) {
 
 	result, err := s.approvalProcessor.RejectAction(request.ActionID)
 	if err != nil {
-		log.Printf("Action rejection failed: %v", err)
+		slog.Error("Action rejection failed", "error", err)
 		return
 	}
 
 	log.Printf("Action rejected: %s -> %s", request.ActionID, result.Status)
 }
 
+// Drain stops intake: no further messages are delivered, those already buffered go back
+// to JetStream for redelivery, and handlers already running are waited for, up to timeout,
+// before the connection closes.
+func (s *Subscriber) Drain(timeout time.Duration) {
+	s.stopping.Store(true)
+	if s.conn == nil {
+		return
+	}
+
+	closed := make(chan struct{})
+	s.conn.SetClosedHandler(func(*nats.Conn) { close(closed) })
+
+	if err := s.conn.Drain(); err != nil {
+		slog.Error("Failed to drain NATS subscriptions", "error", err)
+		s.conn.Close()
+		return
+	}
+
+	select {
+	case <-closed:
+		log.Printf("NATS subscriptions drained")
+	case <-time.After(timeout):
+		log.Printf("NATS subscriptions not drained within %s, closing", timeout)
+		s.conn.Close()
+	}
+}
+
 func (s *Subscriber) Close() {
 	if s.detectionSub != nil {
 		s.detectionSub.Unsubscribe()
@@ -2,10 +2,16 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"log"
+	"strconv"
 	"time"
 
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/handler"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
 	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var startTime time.Time
@@ -14,12 +20,16 @@ func init() {
 	startTime = time.Now()
 }
 
+// ExecutorServer serves action status and rollback over gRPC, mirroring the HTTP API.
 type ExecutorServer struct {
 	pb.UnimplementedExecutorServiceServer
+	detectionHandler *handler.DetectionHandler
 }
 
-func NewExecutorSever() *ExecutorServer {
-	return &ExecutorServer{}
+func NewExecutorSever(dh *handler.DetectionHandler) *ExecutorServer {
+	return &ExecutorServer{
+		detectionHandler: dh,
+	}
 }
 
 func (s *ExecutorServer) HealthCheck(ctx context.Context, req *pb.HealthRequest) (*pb.HealthResponse, error) {
@@ -32,19 +42,123 @@ func (s *ExecutorServer) HealthCheck(ctx context.Context, req *pb.HealthRequest)
 		UptimeSeconds: int64(uptime),
 	}, nil
 }
+
+// GetActionStatus returns the current state of an action run by this Executor.
 func (s *ExecutorServer) GetActionStatus(ctx context.Context, req *pb.ActionStatusRequest) (*pb.ActionStatusResponse, error) {
-	// TODO: Wire to handler
-	return &pb.ActionStatusResponse{
-		ActionId: req.ActionId,
-		Status:   "TODO",
-		Message:  "TODO",
-	}, nil
+	if s.detectionHandler == nil {
+		return nil, status.Error(codes.Unavailable, "executor not ready")
+	}
+	if req.ActionId == "" {
+		return nil, status.Error(codes.InvalidArgument, "action_id is required")
+	}
+
+	result, err := s.detectionHandler.GetActionStatus(req.ActionId)
+	if err != nil {
+		return nil, actionError(err)
+	}
+
+	return actionToProto(result), nil
 }
 
+// ListPendingActions returns every action matching the status filter, unpaged.
 func (s *ExecutorServer) ListPendingActions(ctx context.Context, req *pb.ListRequest) (*pb.ActionList, error) {
-	// TODO: Wire to handler
-	return &pb.ActionList{
-		Actions:    []*pb.ActionStatusResponse{},
-		TotalCount: 0,
-	}, nil
+	return s.ListActions(ctx, &pb.ListActionsRequest{StatusFilter: req.StatusFilter})
+}
+
+// ListActions returns actions matching the status filter, queued actions first in execution
+// order and then newest first. The cursor is the offset of the next page.
+func (s *ExecutorServer) ListActions(ctx context.Context, req *pb.ListActionsRequest) (*pb.ActionList, error) {
+	if s.detectionHandler == nil {
+		return nil, status.Error(codes.Unavailable, "executor not ready")
+	}
+	if req.Limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+
+	start := 0
+	if req.Cursor != "" {
+		offset, err := strconv.Atoi(req.Cursor)
+		if err != nil || offset < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid cursor: %q", req.Cursor)
+		}
+		start = offset
+	}
+
+	results, err := s.detectionHandler.ListPendingActions(req.StatusFilter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	end := len(results)
+	if start > end {
+		start = end
+	}
+	if req.Limit > 0 && start+int(req.Limit) < end {
+		end = start + int(req.Limit)
+	}
+
+	list := &pb.ActionList{
+		Actions:    make([]*pb.ActionStatusResponse, 0, end-start),
+		TotalCount: int32(len(results)),
+	}
+	for _, result := range results[start:end] {
+		list.Actions = append(list.Actions, actionToProto(result))
+	}
+	if end < len(results) {
+		list.NextCursor = strconv.Itoa(end)
+	}
+
+	return list, nil
+}
+
+// RollbackAction reverts a completed action. A rollback that was attempted but failed
+// returns the action, in status rollback_failed, rather than an error, as does one refused
+// by the safety checks, with the reason in its message.
+func (s *ExecutorServer) RollbackAction(ctx context.Context, req *pb.RollbackActionRequest) (*pb.ActionStatusResponse, error) {
+	if s.detectionHandler == nil {
+		return nil, status.Error(codes.Unavailable, "executor not ready")
+	}
+	if req.ActionId == "" {
+		return nil, status.Error(codes.InvalidArgument, "action_id is required")
+	}
+
+	log.Printf("rollback request on action: %s", req.ActionId)
+
+	result, err := s.detectionHandler.RollbackAction(req.ActionId)
+	if result != nil {
+		return actionToProto(result), nil
+	}
+
+	return nil, actionError(err)
+}
+
+// actionError maps a DetectionHandler error to a gRPC status.
+func actionError(err error) error {
+	if errors.Is(err, handler.ErrActionNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return status.Error(codes.FailedPrecondition, err.Error())
+}
+
+func actionToProto(result *models.ActionResult) *pb.ActionStatusResponse {
+	resp := &pb.ActionStatusResponse{
+		ActionId:        result.ActionID,
+		Status:          result.Status,
+		Message:         result.Message,
+		ActionType:      result.ActionType,
+		DatabaseId:      result.DatabaseID,
+		CreatedAt:       result.CreatedAt.Unix(),
+		DetectionId:     result.DetectionID,
+		Error:           result.Error,
+		CanRollback:     result.CanRollback,
+		RollbackError:   result.RollbackError,
+		ExecutionTimeMs: result.ExecutionTimeMs,
+		QueuePosition:   int32(result.QueuePosition),
+	}
+
+	if result.Completed != nil {
+		resp.CompletedAt = result.Completed.Unix()
+	}
+
+	return resp
 }
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+)
+
+// knownStatuses are the statuses read from Knowledge when listing actions without a filter.
+var knownStatuses = []string{
+	models.StatusQueued,
+	models.StatusSuggested,
+	models.StatusPendingApproval,
+	models.StatusApproved,
+	models.StatusScheduled,
+	models.StatusRejected,
+	models.StatusExecuting,
+	models.StatusCompleted,
+	models.StatusFailed,
+	models.StatusPendingImplementation,
+	models.StatusRolledBack,
+	models.StatusRollbackFailed,
+}
+
+// ActionFilter narrows ListActions; empty fields match every action.
+type ActionFilter struct {
+	Status     string
+	DatabaseID string
+}
+
+func (f ActionFilter) matches(result *models.ActionResult) bool {
+	return (f.Status == "" || result.Status == f.Status) &&
+		(f.DatabaseID == "" || result.DatabaseID == f.DatabaseID)
+}
+
+// GetAction returns an action by ID, from this process if it ran the action and from
+// Knowledge otherwise. Actions neither knows about return ErrActionNotFound.
+func (h *DetectionHandler) GetAction(ctx context.Context, actionID string) (*models.ActionResult, error) {
+	if result, err := h.GetActionStatus(actionID); err == nil {
+		return result, nil
+	}
+
+	return h.loadActionFromKnowledge(ctx, actionID)
+}
+
+// ListActions returns the actions matching filter, merging those this process knows about
+// with those recorded in Knowledge by earlier processes. The in-memory copy of an action
+// wins, as it is the most current. If Knowledge can't be read, only in-memory actions are
+// listed. Ordered as ListPendingActions.
+func (h *DetectionHandler) ListActions(ctx context.Context, filter ActionFilter) ([]*models.ActionResult, error) {
+	h.mu.RLock()
+	seen := make(map[string]bool, len(h.actions))
+	var results []*models.ActionResult
+	for id, action := range h.actions {
+		seen[id] = true
+		if filter.matches(action) {
+			results = append(results, action)
+		}
+	}
+	h.mu.RUnlock()
+
+	if h.knowledgeClient != nil {
+		statuses := knownStatuses
+		if filter.Status != "" {
+			statuses = []string{filter.Status}
+		}
+
+		records, err := h.knowledgeClient.ListActionsByStatus(ctx, statuses...)
+		if err != nil {
+			slog.Warn("Listing only in-memory actions, Knowledge unavailable", "error", err)
+		}
+
+		for _, record := range records {
+			if seen[record.Id] {
+				continue
+			}
+			seen[record.Id] = true
+
+			result, err := resultWithChanges(record)
+			if err != nil {
+				slog.Warn("Skipping action from Knowledge", "action_id", record.Id, "error", err)
+				continue
+			}
+			if filter.matches(result) {
+				results = append(results, result)
+			}
+		}
+	}
+
+	sortActions(results)
+
+	return results, nil
+}
+
+// sortActions orders queued actions first in execution order, then newest first. Ties fall
+// back to the action ID so the order is stable across calls and pages.
+func sortActions(results []*models.ActionResult) {
+	sort.Slice(results, func(i, j int) bool {
+		pi, pj := results[i].QueuePosition, results[j].QueuePosition
+		if (pi > 0) != (pj > 0) {
+			return pi > 0
+		}
+		if pi > 0 {
+			return pi < pj
+		}
+		if !results[i].CreatedAt.Equal(results[j].CreatedAt) {
+			return results[i].CreatedAt.After(results[j].CreatedAt)
+		}
+		return results[i].ActionID < results[j].ActionID
+	})
+}
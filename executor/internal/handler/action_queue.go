@@ -0,0 +1,417 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/actions"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/logging"
+)
+
+// ErrShuttingDown is returned when an action is submitted after Shutdown has been called.
+var ErrShuttingDown = models.WithErrorCode(models.ErrorCodeCancelled, errors.New("executor is shutting down"))
+
+// severityPriority ranks detections in the queue; higher runs first. Unknown
+// severities rank with info.
+var severityPriority = map[string]int{
+	"info":     1,
+	"warning":  2,
+	"critical": 3,
+}
+
+const (
+	// operatorPriorityBoost lifts actions an operator asked for, since someone is waiting on them
+	operatorPriorityBoost = 1
+
+	// priorityAgingInterval is how long an action waits before it gains a priority level,
+	// so low-severity actions still run under a steady stream of critical ones
+	priorityAgingInterval = 5 * time.Minute
+)
+
+// queuedAction is an action waiting for a free worker.
+type queuedAction struct {
+	action     actions.Action
+	detection  *models.Detection
+	enqueuedAt time.Time
+
+	// Set when the action is held back by the per-database cooldown
+	notBefore  time.Time
+	waitReason string
+
+	// Approved or deployed from the Dashboard; never held for the maintenance window
+	operatorRequested bool
+
+	// Executions that failed with a transient error code so far
+	attempt int
+}
+
+// QueueEntry describes an action waiting for a worker, in execution order.
+type QueueEntry struct {
+	Position          int       `json:"position"`
+	ActionID          string    `json:"action_id"`
+	ActionType        string    `json:"action_type"`
+	DatabaseID        string    `json:"database_id"`
+	Severity          string    `json:"severity,omitempty"`
+	Priority          int       `json:"priority"`
+	QueuedAt          time.Time `json:"queued_at"`
+	WaitReason        string    `json:"wait_reason,omitempty"`
+	OperatorRequested bool      `json:"operator_requested"`
+}
+
+// priority is the action's severity rank, boosted for operator requests, plus one level
+// per priorityAgingInterval waited.
+func (q *queuedAction) priority(now time.Time) int {
+	base := severityPriority["info"]
+	if q.detection != nil {
+		if rank, ok := severityPriority[strings.ToLower(q.detection.Severity)]; ok {
+			base = rank
+		}
+	}
+	if q.operatorRequested {
+		base += operatorPriorityBoost
+	}
+
+	return base + int(now.Sub(q.enqueuedAt)/priorityAgingInterval)
+}
+
+// sortQueue orders the queue by priority, oldest first within a priority. Caller must
+// hold queueMu.
+func (h *DetectionHandler) sortQueue(now time.Time) {
+	sort.SliceStable(h.queue, func(i, j int) bool {
+		pi, pj := h.queue[i].priority(now), h.queue[j].priority(now)
+		if pi != pj {
+			return pi > pj
+		}
+		return h.queue[i].enqueuedAt.Before(h.queue[j].enqueuedAt)
+	})
+}
+
+// startWorkers launches workers until the pool has maxConcurrent of them. At most
+// maxConcurrent actions execute at once; the rest wait in StatusQueued. Caller must hold
+// queueMu.
+func (h *DetectionHandler) startWorkers() {
+	for ; h.workerCount < h.maxConcurrent; h.workerCount++ {
+		h.workers.Add(1)
+		go h.worker()
+	}
+}
+
+// SetMaxConcurrent resizes the worker pool. Growing starts workers immediately; when
+// shrinking, surplus workers exit once their current action finishes, so nothing running
+// is interrupted.
+func (h *DetectionHandler) SetMaxConcurrent(maxConcurrent int) {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+
+	h.queueMu.Lock()
+	defer h.queueMu.Unlock()
+
+	h.maxConcurrent = maxConcurrent
+	if h.closed {
+		return
+	}
+	h.startWorkers()
+
+	// Wake idle workers so surplus ones can exit
+	h.queueCond.Broadcast()
+}
+
+// MaxConcurrent returns how many actions may execute at once.
+func (h *DetectionHandler) MaxConcurrent() int {
+	h.queueMu.Lock()
+	defer h.queueMu.Unlock()
+
+	return h.maxConcurrent
+}
+
+func (h *DetectionHandler) worker() {
+	defer h.workers.Done()
+
+	for {
+		h.queueMu.Lock()
+		var next *queuedAction
+		for !h.closed && !h.surplusWorker() {
+			if next = h.popReady(time.Now()); next != nil {
+				break
+			}
+			h.queueCond.Wait()
+		}
+		if h.closed || next == nil {
+			// Closed, or the pool shrank and this worker exits
+			h.workerCount--
+			h.queueMu.Unlock()
+			return
+		}
+		h.queueMu.Unlock()
+
+		if h.holdForMaintenance(next) {
+			continue
+		}
+
+		if wait, reason := h.reserveActionSlot(next.action); wait > 0 {
+			h.deferAction(next, wait, reason)
+			continue
+		}
+
+		next.waitReason = ""
+		h.refreshQueuePositions()
+		h.runWithTimeout(next)
+	}
+}
+
+// surplusWorker reports whether the pool has more workers than maxConcurrent. Caller must
+// hold queueMu.
+func (h *DetectionHandler) surplusWorker() bool {
+	return h.workerCount > h.maxConcurrent
+}
+
+// popReady removes and returns the highest-priority queued action that is not held back
+// by a cooldown, or nil if none is ready. Caller must hold queueMu.
+func (h *DetectionHandler) popReady(now time.Time) *queuedAction {
+	h.sortQueue(now)
+	for i, q := range h.queue {
+		if q.notBefore.After(now) {
+			continue
+		}
+		h.queue = append(h.queue[:i], h.queue[i+1:]...)
+		return q
+	}
+	return nil
+}
+
+// deferAction puts an action blocked by the cooldown back in the queue and wakes the
+// workers once the wait has passed. The action stays queued so it is never dropped.
+func (h *DetectionHandler) deferAction(q *queuedAction, wait time.Duration, reason string) {
+	q.notBefore = time.Now().Add(wait)
+	q.waitReason = fmt.Sprintf("waiting %s for %s", wait.Round(time.Second), reason)
+
+	h.queueMu.Lock()
+	if h.closed {
+		h.queueMu.Unlock()
+		return
+	}
+	h.queue = append(h.queue, q)
+	h.queueMu.Unlock()
+
+	log.Printf("Action %s deferred: %s", q.action.GetMetadata().ActionID, q.waitReason)
+
+	time.AfterFunc(wait, func() {
+		h.queueMu.Lock()
+		h.queueCond.Broadcast()
+		h.queueMu.Unlock()
+	})
+
+	h.refreshQueuePositions()
+
+	// Surface the reason for the delay on the Dashboard
+	result, err := h.GetActionStatus(q.action.GetMetadata().ActionID)
+	if err != nil {
+		return
+	}
+	h.updateActionStatusInKnowledge(context.Background(), result)
+
+	if h.natsPublisher != nil {
+		if err := h.natsPublisher.PublishActionStatus(result); err != nil {
+			slog.Warn("Failed to publish action status to event bus", "error", err)
+		}
+	}
+}
+
+// enqueueAction adds an action to the execution queue.
+func (h *DetectionHandler) enqueueAction(action actions.Action, detection *models.Detection) error {
+	return h.enqueue(&queuedAction{action: action, detection: detection})
+}
+
+// enqueueOperatorAction queues an action an operator asked for explicitly (approval or a
+// Dashboard deployment), so it runs outside the maintenance window too.
+func (h *DetectionHandler) enqueueOperatorAction(action actions.Action, detection *models.Detection) error {
+	return h.enqueue(&queuedAction{action: action, detection: detection, operatorRequested: true})
+}
+
+func (h *DetectionHandler) enqueue(q *queuedAction) error {
+	if q.action == nil {
+		return fmt.Errorf("cannot enqueue nil action for detection %s", q.detection.DetectionID)
+	}
+
+	h.queueMu.Lock()
+	if h.closed {
+		h.queueMu.Unlock()
+		return ErrShuttingDown
+	}
+	if q.enqueuedAt.IsZero() {
+		q.enqueuedAt = time.Now()
+	}
+	h.queue = append(h.queue, q)
+	h.queueCond.Signal()
+	h.queueMu.Unlock()
+
+	h.refreshQueuePositions()
+	return nil
+}
+
+// refreshQueuePositions updates the queue position and priority shown on each waiting action.
+func (h *DetectionHandler) refreshQueuePositions() {
+	entries := h.QueuedActions()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, entry := range entries {
+		if result, ok := h.actions[entry.ActionID]; ok && result.Status == models.StatusQueued {
+			// Copy so callers holding the previous result never see a partial update
+			updated := *result
+			updated.QueuePosition = entry.Position
+			updated.Priority = entry.Priority
+			updated.Message = fmt.Sprintf("Action queued: %s (position %d)", result.ActionType, entry.Position)
+			if entry.WaitReason != "" {
+				updated.Message = fmt.Sprintf("%s - %s", updated.Message, entry.WaitReason)
+			}
+			h.actions[entry.ActionID] = &updated
+		}
+	}
+}
+
+// QueuedActions returns the actions waiting for a worker, in the order they will run
+// (actions held back by a cooldown are skipped until it passes).
+func (h *DetectionHandler) QueuedActions() []QueueEntry {
+	now := time.Now()
+
+	h.queueMu.Lock()
+	defer h.queueMu.Unlock()
+
+	h.sortQueue(now)
+
+	entries := make([]QueueEntry, 0, len(h.queue))
+	for i, q := range h.queue {
+		metadata := q.action.GetMetadata()
+		entry := QueueEntry{
+			Position:          i + 1,
+			ActionID:          metadata.ActionID,
+			ActionType:        metadata.ActionType,
+			DatabaseID:        metadata.DatabaseID,
+			Priority:          q.priority(now),
+			QueuedAt:          q.enqueuedAt,
+			WaitReason:        q.waitReason,
+			OperatorRequested: q.operatorRequested,
+		}
+		if q.detection != nil {
+			entry.Severity = q.detection.Severity
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// runWithTimeout executes a single action under the configured action timeout.
+func (h *DetectionHandler) runWithTimeout(q *queuedAction) {
+	ctx, cancel := context.WithTimeout(h.baseCtx, h.ActionTimeout())
+	defer cancel()
+
+	h.executeAction(ctx, q)
+}
+
+// isClosed reports whether Shutdown has been called.
+func (h *DetectionHandler) isClosed() bool {
+	h.queueMu.Lock()
+	defer h.queueMu.Unlock()
+	return h.closed
+}
+
+// Shutdown stops accepting new actions, fails actions that never started, and waits
+// up to gracePeriod for running actions to finish. Actions still running after the
+// grace period have their context cancelled. Database adapters are closed last.
+func (h *DetectionHandler) Shutdown(gracePeriod time.Duration) {
+	h.queueMu.Lock()
+	if h.closed {
+		h.queueMu.Unlock()
+		return
+	}
+	h.closed = true
+	defer h.cancelBase()
+	abandoned := h.queue
+	h.queue = nil
+	scheduled := len(h.scheduled)
+	h.scheduled = nil
+	h.queueCond.Broadcast()
+	h.queueMu.Unlock()
+
+	// Scheduled actions stay scheduled in Knowledge; Reconcile parks them again on restart
+	log.Printf("Action queue closed (%d queued actions abandoned, %d scheduled actions left for the next start)", len(abandoned), scheduled)
+
+	for _, q := range abandoned {
+		metadata := q.action.GetMetadata()
+		h.finishResult(context.Background(), &models.ActionResult{
+			ActionID:    metadata.ActionID,
+			DetectionID: q.detection.DetectionID,
+			ActionType:  metadata.ActionType,
+			DatabaseID:  metadata.DatabaseID,
+			Status:      models.StatusFailed,
+			Message:     "Executor shut down before action started",
+			Error:       ErrShuttingDown.Error(),
+			ErrorCode:   models.ErrorCodeCancelled,
+			Attempts:    q.attempt,
+			CreatedAt:   metadata.CreatedAt,
+		})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		h.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Printf("All running actions finished")
+	case <-time.After(gracePeriod):
+		log.Printf("Grace period (%s) expired - cancelling running actions", gracePeriod)
+		h.cancelBase()
+		<-done
+	}
+
+	h.adapters.Close()
+}
+
+// finishResult stores a result and propagates it to Knowledge and NATS.
+func (h *DetectionHandler) finishResult(ctx context.Context, result *models.ActionResult) {
+	h.storeAction(result)
+	h.updateActionStatusInKnowledge(ctx, result)
+
+	if h.natsPublisher != nil {
+		if err := h.natsPublisher.PublishActionStatus(result); err != nil {
+			slog.Warn("Failed to publish action status to event bus", "error", err)
+		}
+	}
+
+	if result.Status == models.StatusFailed {
+		h.publishUndone(result, nil)
+	}
+}
+
+// publishUndone tells the Analyser that the fix for a detection failed or was rolled back, so
+// it reopens the detection. Without the detection at hand it is identified from the result.
+func (h *DetectionHandler) publishUndone(result *models.ActionResult, detection *models.Detection) {
+	if h.natsPublisher == nil || result.DetectionID == "" {
+		return
+	}
+	if detection == nil {
+		detection = &models.Detection{
+			DetectionID:   result.DetectionID,
+			DatabaseID:    result.DatabaseID,
+			CorrelationID: result.CorrelationID,
+		}
+	}
+
+	if err := h.natsPublisher.PublishActionUndone(result, detection); err != nil {
+		logging.With(result.CorrelationID).Warn("Failed to publish outcome of action", "status", result.Status, "action_id", result.ActionID, "error", err)
+	}
+}
@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/docker"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/detectionkey"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/logging"
+)
+
+// ContainerRestartDetector names the detections the watchdog raises about deployed
+// containers that keep restarting.
+const ContainerRestartDetector = "container_restart_loop"
+
+// watchdogInspectTimeout bounds one docker inspect of a watched container.
+const watchdogInspectTimeout = 10 * time.Second
+
+// watchedActionTypes deploy long-lived containers the watchdog keeps an eye on.
+var watchedActionTypes = map[string]bool{
+	"deploy_connection_pooler": true,
+	"deploy_pgbouncer":         true,
+	"deploy_redis":             true,
+}
+
+// WatchdogSettings configures the watchdog over deployed containers.
+type WatchdogSettings struct {
+	RestartThreshold int  // restarts since the container was first watched that count as a regression
+	Rollback         bool // roll a regressed action back rather than leave it to the operator
+}
+
+// DefaultWatchdogSettings flags a container after three restarts and rolls its action back.
+func DefaultWatchdogSettings() WatchdogSettings {
+	return WatchdogSettings{RestartThreshold: 3, Rollback: true}
+}
+
+// SetWatchdogSettings configures how the watchdog treats restarting containers.
+func (h *DetectionHandler) SetWatchdogSettings(settings WatchdogSettings) {
+	h.settingsMu.Lock()
+	defer h.settingsMu.Unlock()
+
+	h.watchdogSettings = settings
+}
+
+func (h *DetectionHandler) currentWatchdogSettings() WatchdogSettings {
+	h.settingsMu.RLock()
+	defer h.settingsMu.RUnlock()
+
+	return h.watchdogSettings
+}
+
+// WatchDeployedContainers checks deployed containers every interval until Shutdown.
+func (h *DetectionHandler) WatchDeployedContainers(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.baseCtx.Done():
+			return
+		case <-ticker.C:
+			h.CheckDeployedContainers(h.baseCtx)
+		}
+	}
+}
+
+// CheckDeployedContainers inspects the container of every completed deploy action - those
+// from a previous Executor process are restored from Knowledge by Reconcile - and flags the
+// ones that restarted RestartThreshold times since they were first watched. A flagged action
+// is marked regressed, announced on "detections.regression" and, with Rollback set, rolled
+// back. It returns the IDs of the actions it flagged.
+func (h *DetectionHandler) CheckDeployedContainers(ctx context.Context) []string {
+	watched := h.watchedContainers()
+	if len(watched) == 0 {
+		return nil
+	}
+
+	// Only the engine found by the startup probe is watched; without one there is nothing to inspect
+	h.settingsMu.RLock()
+	runtime := h.dockerRuntime
+	h.settingsMu.RUnlock()
+	if runtime == nil {
+		return nil
+	}
+
+	settings := h.currentWatchdogSettings()
+	var regressed []string
+
+	for _, result := range watched {
+		containerID, _ := result.Changes["container_id"].(string)
+
+		inspectCtx, cancel := context.WithTimeout(ctx, watchdogInspectTimeout)
+		state, err := runtime.InspectState(inspectCtx, containerID)
+		cancel()
+		if err != nil {
+			logging.With(result.CorrelationID).Warn("Watchdog: cannot inspect container of action", "action_id", result.ActionID, "error", err)
+			continue
+		}
+
+		restarts := h.restartsSinceWatched(result.ActionID, state.RestartCount)
+		if restarts < settings.RestartThreshold {
+			continue
+		}
+
+		h.reportRegression(ctx, result, state, restarts, settings)
+		regressed = append(regressed, result.ActionID)
+	}
+
+	return regressed
+}
+
+// watchedContainers returns the completed deploy actions that record their container.
+func (h *DetectionHandler) watchedContainers() []*models.ActionResult {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var watched []*models.ActionResult
+	for _, result := range h.actions {
+		if result.Status != models.StatusCompleted || !watchedActionTypes[result.ActionType] {
+			continue
+		}
+		if containerID, _ := result.Changes["container_id"].(string); containerID == "" {
+			continue
+		}
+		watched = append(watched, result)
+	}
+	return watched
+}
+
+// restartsSinceWatched returns how often an action's container restarted since the watchdog
+// first saw it. Restarts before then, e.g. while the Executor was down, are not counted.
+func (h *DetectionHandler) restartsSinceWatched(actionID string, restartCount int) int {
+	h.watchMu.Lock()
+	defer h.watchMu.Unlock()
+
+	baseline, seen := h.restartBaselines[actionID]
+	if !seen || restartCount < baseline {
+		// A count below the baseline means the container was recreated
+		h.restartBaselines[actionID] = restartCount
+		return 0
+	}
+	return restartCount - baseline
+}
+
+// reportRegression marks an action regressed, publishes a detection about it and rolls it
+// back when settings allow.
+func (h *DetectionHandler) reportRegression(ctx context.Context, result *models.ActionResult, state *docker.ContainerState, restarts int, settings WatchdogSettings) {
+	containerName, _ := result.Changes["container_name"].(string)
+
+	updated := *result
+	updated.Changes = maps.Clone(result.Changes)
+	updated.Changes["restart_count"] = state.RestartCount
+	updated.Changes["oom_killed"] = state.OOMKilled
+	updated.Status = models.StatusRegressed
+	updated.Message = fmt.Sprintf("Container %s restarted %d times since it was deployed - the fix is failing", containerName, restarts)
+	if state.OOMKilled {
+		updated.Message += " (killed for exceeding its memory limit)"
+	}
+
+	h.storeAction(&updated)
+	h.updateActionStatusInKnowledgeFrom(ctx, &updated, models.SourceContainerWatchdog)
+
+	detection := restartLoopDetection(&updated, containerName, state, restarts, settings)
+	if h.natsPublisher != nil {
+		if err := h.natsPublisher.PublishActionStatus(&updated); err != nil {
+			logging.With(updated.CorrelationID).Warn("Failed to publish regressed status", "action_id", updated.ActionID, "error", err)
+		}
+		if err := h.natsPublisher.PublishRegression(detection); err != nil {
+			logging.With(updated.CorrelationID).Warn("Failed to publish regression", "action_id", updated.ActionID, "error", err)
+		}
+	}
+
+	logging.With(updated.CorrelationID).Warn("Watchdog: action regressed", "action_id", updated.ActionID, "reason", updated.Message)
+	h.notifyAction(&updated, detection)
+
+	h.watchMu.Lock()
+	delete(h.restartBaselines, updated.ActionID)
+	h.watchMu.Unlock()
+
+	if !settings.Rollback || !updated.CanRollback {
+		return
+	}
+
+	// A container stuck restarting has no clients worth protecting, so skip the safety checks
+	reason := fmt.Sprintf("container %s restarted %d times", containerName, restarts)
+	if _, err := h.rollback(updated.ActionID, models.SourceContainerWatchdog, reason, true); err != nil {
+		logging.With(updated.CorrelationID).Error("Watchdog: rollback of regressed action failed", "action_id", updated.ActionID, "error", err)
+	}
+}
+
+// restartLoopDetection describes a regressed action the way the Analyser describes issues,
+// so the Dashboard can list it with other detections.
+func restartLoopDetection(result *models.ActionResult, containerName string, state *docker.ContainerState, restarts int, settings WatchdogSettings) *models.Detection {
+	recommendation := "Inspect the container logs, fix its configuration and roll the action back if it keeps failing."
+	if settings.Rollback && result.CanRollback {
+		recommendation = "The Executor is rolling the action back. Inspect the container logs before trying it again."
+	}
+
+	return &models.Detection{
+		DetectionID:  fmt.Sprintf("%s-regression-%d", result.ActionID, state.RestartCount),
+		Key:          detectionkey.Build(result.DatabaseID, ContainerRestartDetector, containerName),
+		DetectorName: ContainerRestartDetector,
+		Category:     "availability",
+		Severity:     "critical",
+		DatabaseID:   result.DatabaseID,
+		Title:        fmt.Sprintf("Container '%s' deployed by %s keeps restarting", containerName, result.ActionType),
+		Description: fmt.Sprintf(
+			"Container '%s' restarted %d times since the Executor started watching it. The fix applied by action %s is failing.",
+			containerName, restarts, result.ActionID,
+		),
+		Recommendation: recommendation,
+		ActionType:     result.ActionType,
+		ActionMetaData: map[string]interface{}{
+			"action_id":      result.ActionID,
+			"container_name": containerName,
+		},
+		Evidence: map[string]interface{}{
+			"restart_count":     state.RestartCount,
+			"restarts":          restarts,
+			"restart_threshold": settings.RestartThreshold,
+			"oom_killed":        state.OOMKilled,
+			"exit_code":         state.ExitCode,
+			"running":           state.Running,
+		},
+		Timestamp:     time.Now().Unix(),
+		Value:         float64(restarts),
+		CorrelationID: result.CorrelationID,
+	}
+}
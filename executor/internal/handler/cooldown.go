@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/actions"
+)
+
+// rateWindow is the window the per-hour action cap is measured over.
+const rateWindow = time.Hour
+
+// ActionLimiter spaces out mutating actions on the same database: at least cooldown
+// between action starts, and at most maxPerHour starts in any rolling hour.
+type ActionLimiter struct {
+	cooldown   time.Duration
+	maxPerHour int
+
+	mu      sync.Mutex
+	started map[string][]time.Time // database ID -> start times within rateWindow, oldest first
+	seeded  map[string]bool        // databases whose history has been loaded from Knowledge
+}
+
+// NewActionLimiter creates a limiter. A zero cooldown or maxPerHour disables that limit.
+func NewActionLimiter(cooldown time.Duration, maxPerHour int) *ActionLimiter {
+	return &ActionLimiter{
+		cooldown:   cooldown,
+		maxPerHour: maxPerHour,
+		started:    map[string][]time.Time{},
+		seeded:     map[string]bool{},
+	}
+}
+
+// SetLimits changes the cooldown and hourly cap. Start times already recorded still count
+// against the new limits.
+func (l *ActionLimiter) SetLimits(cooldown time.Duration, maxPerHour int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.cooldown = cooldown
+	l.maxPerHour = maxPerHour
+}
+
+// Seed merges start times recorded before this process started (e.g. loaded from Knowledge).
+func (l *ActionLimiter) Seed(databaseID string, startedAt []time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.seeded[databaseID] {
+		return
+	}
+	l.seeded[databaseID] = true
+
+	history := append(append([]time.Time{}, startedAt...), l.started[databaseID]...)
+	sort.Slice(history, func(i, j int) bool { return history[i].Before(history[j]) })
+	l.started[databaseID] = history
+}
+
+func (l *ActionLimiter) isSeeded(databaseID string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.seeded[databaseID]
+}
+
+// Reserve claims a slot for an action starting on databaseID at now. If the action must
+// wait, nothing is claimed and the remaining wait and the reason are returned.
+func (l *ActionLimiter) Reserve(databaseID string, now time.Time) (time.Duration, string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Drop start times that have left the rate window
+	history := l.started[databaseID]
+	for len(history) > 0 && now.Sub(history[0]) >= rateWindow {
+		history = history[1:]
+	}
+	l.started[databaseID] = history
+
+	if l.cooldown > 0 && len(history) > 0 {
+		last := history[len(history)-1]
+		if wait := last.Add(l.cooldown).Sub(now); wait > 0 {
+			return wait, fmt.Sprintf("cooldown of %s between actions on %s", l.cooldown, databaseID)
+		}
+	}
+
+	if l.maxPerHour > 0 && len(history) >= l.maxPerHour {
+		wait := history[len(history)-l.maxPerHour].Add(rateWindow).Sub(now)
+		return wait, fmt.Sprintf("limit of %d actions per hour reached on %s", l.maxPerHour, databaseID)
+	}
+
+	l.started[databaseID] = append(history, now)
+	return 0, ""
+}
+
+// SetActionLimiter enables per-database cooldown and rate limiting of mutating actions.
+func (h *DetectionHandler) SetActionLimiter(limiter *ActionLimiter) {
+	h.limiter = limiter
+}
+
+// reserveActionSlot checks the limiter for a queued action. Recommendations and other
+// non-mutating actions are never delayed. Start times are recorded in Knowledge so the
+// cooldown survives an Executor restart.
+func (h *DetectionHandler) reserveActionSlot(action actions.Action) (time.Duration, string) {
+	if h.limiter == nil || !isMutating(action) {
+		return 0, ""
+	}
+
+	metadata := action.GetMetadata()
+	ctx := context.Background()
+	now := time.Now()
+
+	if h.knowledgeClient != nil && !h.limiter.isSeeded(metadata.DatabaseID) {
+		startedAt, err := h.knowledgeClient.GetRecentDatabaseActions(ctx, metadata.DatabaseID, now.Add(-rateWindow))
+		if err != nil {
+			slog.Warn("Failed to load recent actions", "database_id", metadata.DatabaseID, "error", err)
+		} else {
+			h.limiter.Seed(metadata.DatabaseID, startedAt)
+		}
+	}
+
+	wait, reason := h.limiter.Reserve(metadata.DatabaseID, now)
+	if wait > 0 {
+		return wait, reason
+	}
+
+	if h.knowledgeClient != nil {
+		if err := h.knowledgeClient.RecordDatabaseAction(ctx, metadata.DatabaseID, metadata.ActionID, now); err != nil {
+			slog.Warn("Failed to record action start in knowledge", "error", err)
+		}
+	}
+
+	return 0, ""
+}
+
+// isMutating reports whether an action changes the database or its infrastructure.
+func isMutating(action actions.Action) bool {
+	switch action.(type) {
+	case *actions.RecommendationAction, *actions.FutureFix, *actions.ReportSlowQueriesAction:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/policy"
+)
+
+// ErrActionRefused is returned when approving an action its database's policy now forbids.
+var ErrActionRefused = errors.New("action refused by database policy")
+
+// SetPolicyCacheTTL changes how long a database's policy is used before it is read from
+// Knowledge again. Without Knowledge no policies apply.
+func (h *DetectionHandler) SetPolicyCacheTTL(ttl time.Duration) {
+	if h.knowledgeClient == nil {
+		return
+	}
+
+	h.settingsMu.Lock()
+	defer h.settingsMu.Unlock()
+
+	h.policies = policy.NewCache(h.knowledgeClient.GetDatabasePolicy, ttl)
+}
+
+// InvalidateDatabasePolicy drops a database's cached policy, so a change made through this
+// Executor applies to the next action rather than after the cache expires.
+func (h *DetectionHandler) InvalidateDatabasePolicy(databaseID string) {
+	h.settingsMu.RLock()
+	defer h.settingsMu.RUnlock()
+
+	if h.policies != nil {
+		h.policies.Invalidate(databaseID)
+	}
+}
+
+// checkDatabasePolicy returns a deny decision, and the reason operators gave, when the
+// database's policy forbids the action type. If the policy can't be read the last one read
+// still applies; a database whose policy was never read is unrestricted.
+func (h *DetectionHandler) checkDatabasePolicy(ctx context.Context, logger *slog.Logger, databaseID, actionType string) (ExecutionDecision, string, bool) {
+	h.settingsMu.RLock()
+	cache := h.policies
+	h.settingsMu.RUnlock()
+
+	if cache == nil {
+		return ExecutionDecision{}, "", false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	p, err := cache.Get(ctx, databaseID)
+	if err != nil {
+		logger.Warn("Failed to read database policy, using the last one read", "error", err)
+	}
+
+	if allowed, rule := policy.ActionAllowed(p, actionType); !allowed {
+		return ExecutionDecision{models.PolicyDeny, rule}, p.Reason, true
+	}
+	return ExecutionDecision{}, "", false
+}
+
+// refuseAction records an action the database's policy forbids, without creating it, so
+// the Dashboard shows what was not done and why.
+func (h *DetectionHandler) refuseAction(ctx context.Context, logger *slog.Logger, detection *models.Detection, actionID string, decision ExecutionDecision, reason string) *models.ActionResult {
+	status, message := refusedStatus(decision, detection.ActionType, reason)
+
+	now := time.Now()
+	result := &models.ActionResult{
+		ActionID:    actionID,
+		DetectionID: detection.DetectionID,
+		ActionType:  detection.ActionType,
+		DatabaseID:  detection.DatabaseID,
+		Status:      status,
+		Message:     message,
+		CreatedAt:   now,
+		Completed:   &now,
+
+		CorrelationID: detection.CorrelationID,
+
+		PolicyDecision: decision.Behaviour,
+		PolicyRule:     decision.Rule,
+	}
+
+	if h.knowledgeClient != nil {
+		if err := h.registerActionWithKnowledge(ctx, detection, result, models.DetectorSource(detection.DetectorName)); err != nil {
+			logger.Warn("Failed to register refused action with Knowledge", "error", err)
+		}
+	}
+
+	h.storeAction(result)
+
+	if h.natsPublisher != nil {
+		if err := h.natsPublisher.PublishActionStatus(result); err != nil {
+			logger.Warn("Failed to publish action status to event bus", "error", err)
+		}
+	}
+
+	logger.Warn("Action refused by database policy", "policy_rule", decision.Rule, "reason", reason)
+	return result
+}
+
+// refusedStatus returns the status and message of an action refused under decision, with
+// the reason operators gave for the policy.
+func refusedStatus(decision ExecutionDecision, actionType, reason string) (string, string) {
+	status, message := decision.initialStatus(actionType)
+	if reason != "" {
+		message = fmt.Sprintf("%s: %s", message, reason)
+	}
+	return status, message
+}
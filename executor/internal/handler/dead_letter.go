@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+)
+
+// deadLetter records a detection the Executor cannot act on, so it is surfaced as
+// unactionable instead of staying active in Knowledge with no action ever taken.
+func (h *DetectionHandler) deadLetter(ctx context.Context, detection *models.Detection, cause error) {
+	reason := cause.Error()
+
+	if h.natsPublisher != nil {
+		if err := h.natsPublisher.PublishDetectionFailed(detection, reason); err != nil {
+			slog.Warn("Failed to publish dead-lettered detection", "error", err)
+		}
+	}
+
+	if h.knowledgeClient != nil && detection.DetectionID != "" {
+		if err := h.knowledgeClient.MarkDetectionUnactionable(ctx, detection.DetectionID, reason); err != nil {
+			slog.Warn("Failed to mark detection unactionable in Knowledge", "error", err)
+		}
+	}
+}
+
+// acknowledgeDetection parks a detection whose fix is not implemented yet, so Knowledge stops
+// listing it as an open problem while still suppressing duplicates.
+func (h *DetectionHandler) acknowledgeDetection(ctx context.Context, detection *models.Detection, reason string) {
+	if h.knowledgeClient == nil || detection.DetectionID == "" {
+		return
+	}
+
+	if err := h.knowledgeClient.MarkDetectionAcknowledged(ctx, detection.DetectionID, reason); err != nil {
+		slog.Warn("Failed to acknowledge detection in Knowledge", "error", err)
+	}
+}
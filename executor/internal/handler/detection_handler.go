@@ -2,82 +2,239 @@ package handler
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
+	"maps"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/actions"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/database"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/docker"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/eventbus"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/knowledge"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/metrics"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
 	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/detectionkey"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/logging"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/policy"
 )
 
+// ErrActionNotFound is returned for an action neither this Executor nor Knowledge knows about.
+var ErrActionNotFound = errors.New("action not found")
+
+// detectionDismissed is recorded as the solution when a user rejects an action.
+const detectionDismissed = "dismissed"
+
+// metadataDockerContainer is the Knowledge database metadata key naming the Docker
+// container the database runs in, set by the Collector.
+const metadataDockerContainer = "docker_container"
+
+// approvalRequiredByDefault lists destructive action types, and those that restart the
+// database, that wait for approval even in autonomous mode, unless ACTION_APPROVAL_OVERRIDES
+// sets them to "auto".
+var approvalRequiredByDefault = map[string]bool{
+	"drop_index":          true,
+	"increase_cache_size": true,
+}
+
 type DetectionHandler struct {
 	actions         map[string]*models.ActionResult
 	actionObjects   map[string]actions.Action
 	mu              sync.RWMutex
+	rollbackMu      sync.Mutex // serialises rollbacks so a duplicate request sees the first one's outcome
 	natsPublisher   *eventbus.Publisher
 	knowledgeClient *knowledge.Client
+
+	// Execution queue - bounded worker pool. maxConcurrent and workerCount are guarded
+	// by queueMu so the pool can be resized while actions run
+	maxConcurrent int
+	workerCount   int
+	queue         []*queuedAction
+	queueMu       sync.Mutex
+	queueCond     *sync.Cond
+	closed        bool
+	workers       sync.WaitGroup
+	baseCtx       context.Context
+	cancelBase    context.CancelFunc
+
+	// Per-database cooldown; nil means actions are never delayed
+	limiter *ActionLimiter
+
+	// Settings that can be changed by a config reload while actions run
+	settingsMu    sync.RWMutex
+	actionTimeout time.Duration
+
+	// Approval policy: autoExecute=false holds autonomous actions for approval;
+	// approvalOverrides maps action types to "auto" or "approval"
+	autoExecute       bool
+	approvalOverrides map[string]string
+	executionPolicy   map[string]string // detection severity -> auto, approve or notify
+
+	// Maintenance window for mutating actions; nil runs them anytime. Actions waiting for
+	// the window are parked in scheduled (guarded by queueMu)
+	maintenanceWindow *MaintenanceWindow
+	maintenanceBypass map[string]bool // severities that run outside the window
+	scheduled         []*queuedAction
+
+	// Told about critical detections and finished actions; nil sends no notifications
+	notifier Notifier
+
+	// One adapter per database, shared by every action and closed on Shutdown
+	adapters *database.AdapterCache
+
+	// Defaults for PgBouncer deployments; detection metadata can override them
+	pgBouncerDefaults actions.PgBouncerSettings
+	probeSettings     docker.ProbeSettings // guarded by settingsMu
+
+	// Memory and CPU limits of deployed Redis and PgBouncer containers, guarded by settingsMu
+	redisLimits     docker.ResourceLimits
+	pgBouncerLimits docker.ResourceLimits
+
+	// Watchdog over deployed containers: settings guarded by settingsMu, and each watched
+	// action's restart count when first seen, guarded by watchMu
+	watchdogSettings WatchdogSettings
+	watchMu          sync.Mutex
+	restartBaselines map[string]int
+
+	// Container runtime shared by container-backed actions, guarded by settingsMu. Until
+	// dockerProbed is set each action connects on its own; dockerUnavailable says why
+	// Docker could not be reached, turning those actions into manual steps
+	dockerProbed      bool
+	dockerRuntime     docker.Runtime
+	dockerUnavailable error
+
+	// Which sessions terminate_query may end, and whether it cancels first
+	terminateSettings actions.TerminateSettings
+
+	// Checks made before a rollback that isn't forced, guarded by settingsMu
+	rollbackSafety actions.RollbackSafety
+
+	// Per-database policies read from Knowledge, guarded by settingsMu; nil applies none
+	policies *policy.Cache
+
+	// How actions failing with a transient error code are retried, guarded by settingsMu
+	retryPolicy RetryPolicy
 }
 
-func NewDetectionHandler(natsPublisher *eventbus.Publisher, knowledgeClient *knowledge.Client) *DetectionHandler {
-	return &DetectionHandler{
-		actions:         map[string]*models.ActionResult{},
-		actionObjects:   map[string]actions.Action{},
-		natsPublisher:   natsPublisher,
-		knowledgeClient: knowledgeClient,
+// NewDetectionHandler creates a handler that executes at most maxConcurrent actions at once,
+// each bounded by actionTimeout.
+func NewDetectionHandler(natsPublisher *eventbus.Publisher, knowledgeClient *knowledge.Client, maxConcurrent int, actionTimeout time.Duration) *DetectionHandler {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
 	}
+
+	baseCtx, cancel := context.WithCancel(context.Background())
+
+	h := &DetectionHandler{
+		actions:           map[string]*models.ActionResult{},
+		actionObjects:     map[string]actions.Action{},
+		natsPublisher:     natsPublisher,
+		knowledgeClient:   knowledgeClient,
+		maxConcurrent:     maxConcurrent,
+		actionTimeout:     actionTimeout,
+		baseCtx:           baseCtx,
+		cancelBase:        cancel,
+		autoExecute:       true,
+		adapters:          database.NewAdapterCache(database.NewAdapter),
+		pgBouncerDefaults: actions.DefaultPgBouncerSettings(),
+		probeSettings:     docker.DefaultProbeSettings(),
+		watchdogSettings:  DefaultWatchdogSettings(),
+		restartBaselines:  map[string]int{},
+		terminateSettings: actions.DefaultTerminateSettings(),
+		rollbackSafety:    actions.DefaultRollbackSafety(),
+		retryPolicy:       DefaultRetryPolicy(),
+	}
+	if knowledgeClient != nil {
+		h.policies = policy.NewCache(knowledgeClient.GetDatabasePolicy, policy.DefaultCacheTTL)
+	}
+	h.queueCond = sync.NewCond(&h.queueMu)
+	h.queueMu.Lock()
+	h.startWorkers()
+	h.queueMu.Unlock()
+
+	return h
 }
 
 func (h *DetectionHandler) HandleDetection(detection *models.Detection) (*models.ActionResult, error) {
-	log.Printf("	Anomaly detected: [%s] - %s", detection.Severity, detection.Title)
-	log.Printf("	Detector: %s", detection.DetectorName)
-	log.Printf("	Action Type: %s", detection.ActionType)
-	log.Printf("	Database: %s", detection.DatabaseID)
+	logger := logging.With(detection.CorrelationID).With(
+		"detection_id", detection.DetectionID,
+		"database_id", detection.DatabaseID,
+		"action_type", detection.ActionType)
+
+	// After Shutdown nothing new is registered: the caller hands the detection back for redelivery
+	if h.isClosed() {
+		return nil, ErrShuttingDown
+	}
 
-	ctx := context.Background()
+	ctx := logging.WithCorrelationID(context.Background(), detection.CorrelationID)
+
+	// Decide whether the action runs, waits for approval or is only suggested. The
+	// database's policy can forbid it outright, whatever the execution policy says
+	decision := h.decideExecution(h.getExecutionMode(ctx), detection)
+	refusal, refusalReason, refused := h.checkDatabasePolicy(ctx, logger, detection.DatabaseID, detection.ActionType)
+	if refused {
+		decision = refusal
+	}
 
-	// Check execution mode
-	executionMode := h.getExecutionMode(ctx)
-	log.Printf("	Execution Mode: %s", executionMode)
+	logger.Info("Anomaly detected",
+		"severity", detection.Severity,
+		"title", detection.Title,
+		"detector", detection.DetectorName,
+		"escalated", detection.Escalated,
+		"updated", detection.Updated,
+		"policy_decision", decision.Behaviour,
+		"policy_rule", decision.Rule)
+
+	// A re-published detection keeps its ID, so the duplicate checks below would drop it
+	if detection.Escalated || detection.Updated {
+		if result, handled := h.handleRepublished(ctx, logger, detection, decision, refused, refusalReason); handled {
+			return result, nil
+		}
+	}
 
 	if h.knowledgeClient != nil {
 		if isDuplicate, err := h.checkForDuplicateActions(ctx, detection); err != nil {
-			log.Printf("warning failed to check duplicate actions: %v", err)
+			logger.Warn("Failed to check for duplicate actions", "error", err)
 		} else if isDuplicate {
-			log.Printf("Action already pending for detection, skipping")
+			logger.Info("Action already pending for detection, skipping")
 			return nil, nil
 		}
 	}
 
-	actionID := generateActionID()
+	actionID := generateActionID(detection)
+	logger = logger.With("action_id", actionID)
+
+	// Redelivered detections map to the same action ID - never run the same action twice
+	if h.actionExists(ctx, actionID) {
+		logger.Info("Action already exists for detection, skipping")
+		return nil, nil
+	}
+
+	h.notifyDetection(detection)
+
+	if refused {
+		return h.refuseAction(ctx, logger.With("policy_decision", decision.Behaviour), detection, actionID, decision, refusalReason), nil
+	}
 
 	action, err := h.createAction(detection, actionID)
 	if err != nil {
-		log.Printf("failed to create action: %v", err)
-		return nil, err
+		logger.Error("Failed to create action", "error", err)
+		h.deadLetter(ctx, detection, err)
+		return nil, fmt.Errorf("%w: %v", eventbus.ErrDeadLettered, err)
 	}
 
 	h.storeActionObject(actionID, action)
 
-	// Determine initial status based on execution mode
-	var initialStatus string
-	var message string
-
-	switch executionMode {
-	case models.ModeObserve:
-		initialStatus = models.StatusSuggested
-		message = fmt.Sprintf("Suggested action: %s (observe mode)", detection.ActionType)
-	case models.ModeApproval:
-		initialStatus = models.StatusPendingApproval
-		message = fmt.Sprintf("Action pending approval: %s", detection.ActionType)
-	default: // autonomous
-		initialStatus = models.StatusQueued
-		message = fmt.Sprintf("Action queued: %s", detection.ActionType)
-	}
+	initialStatus, message := decision.initialStatus(detection.ActionType)
 
 	result := &models.ActionResult{
 		ActionID:    actionID,
@@ -87,13 +244,18 @@ func (h *DetectionHandler) HandleDetection(detection *models.Detection) (*models
 		Status:      initialStatus,
 		Message:     message,
 		CreatedAt:   time.Now(),
+
+		CorrelationID: detection.CorrelationID,
+
+		PolicyDecision: decision.Behaviour,
+		PolicyRule:     decision.Rule,
 	}
 
 	if h.knowledgeClient != nil {
-		if err := h.registerActionWithKnowledge(ctx, detection, result); err != nil {
-			log.Printf("warning failed to register action with knowledge: %v", err)
+		if err := h.registerActionWithKnowledge(ctx, detection, result, models.DetectorSource(detection.DetectorName)); err != nil {
+			logger.Warn("Failed to register action with Knowledge", "error", err)
 		} else {
-			log.Printf("Action registered with knowledge")
+			logger.Debug("Action registered with Knowledge")
 		}
 	}
 
@@ -101,20 +263,96 @@ func (h *DetectionHandler) HandleDetection(detection *models.Detection) (*models
 
 	if h.natsPublisher != nil {
 		if err := h.natsPublisher.PublishActionStatus(result); err != nil {
-			log.Printf("Warning: failed to publish action status to event bus: %v", err)
+			logger.Warn("Failed to publish action status to event bus", "error", err)
 		}
 	}
 
-	log.Printf("Action %s: %s (ID: %s)", initialStatus, detection.ActionType, result.ActionID)
+	logger.Info("Action created", "status", initialStatus)
 
-	// Only execute immediately in autonomous mode
-	if executionMode == models.ModeAutonomous {
-		go h.executeAction(action, detection)
+	switch decision.Behaviour {
+	case models.PolicyAuto:
+		if err := h.enqueueAction(action, detection); err != nil {
+			return nil, err
+		}
+	case models.PolicyNotify:
+		// Observe mode suggests every action; only a notify policy is worth a message
+		if decision.Rule != ruleObserveMode {
+			h.notifyAction(result, detection)
+		}
 	}
 
 	return result, nil
 }
 
+// handleRepublished reacts to a detection the Analyser re-published at a higher severity or
+// with a significantly changed value. The detection is announced again and an action still
+// held by the policy is decided afresh, so an escalation can queue it or ask for approval.
+// Actions that already ran are left alone. It returns false when no action exists yet, in
+// which case the detection is handled as a new one.
+func (h *DetectionHandler) handleRepublished(ctx context.Context, logger *slog.Logger, detection *models.Detection, decision ExecutionDecision, refused bool, refusalReason string) (*models.ActionResult, bool) {
+	actionID := generateActionID(detection)
+	existing, err := h.GetAction(ctx, actionID)
+	if err != nil {
+		return nil, false
+	}
+	logger = logger.With("action_id", actionID)
+
+	h.notifyDetection(detection)
+
+	if existing.Status != models.StatusSuggested && existing.Status != models.StatusPendingApproval {
+		logger.Info("Detection re-published for an action that is no longer held by policy", "status", existing.Status)
+		return existing, true
+	}
+
+	status, message := decision.initialStatus(detection.ActionType)
+	if refused {
+		status, message = refusedStatus(decision, detection.ActionType, refusalReason)
+	}
+	if status == existing.Status {
+		logger.Info("Detection re-published, policy decision unchanged", "status", status)
+		return existing, true
+	}
+
+	action, err := h.getActionObject(actionID)
+	if err != nil && status == models.StatusQueued {
+		// Held actions rebuilt from Knowledge have no action object; leave them for an operator
+		logger.Warn("Cannot queue re-published action", "error", err)
+		return existing, true
+	}
+
+	result := copyActionResult(existing)
+	result.Status = status
+	result.Message = message
+	result.PolicyDecision = decision.Behaviour
+	result.PolicyRule = decision.Rule
+	h.storeAction(result)
+	h.updateActionStatusInKnowledge(ctx, result)
+
+	if h.natsPublisher != nil {
+		if err := h.natsPublisher.PublishActionStatus(result); err != nil {
+			logger.Warn("Failed to publish action status to event bus", "error", err)
+		}
+	}
+
+	logger.Info("Action re-decided for re-published detection",
+		"from", existing.Status,
+		"to", status,
+		"policy_rule", decision.Rule)
+
+	switch status {
+	case models.StatusQueued:
+		if err := h.enqueueAction(action, detection); err != nil {
+			logger.Warn("Failed to queue re-published action", "error", err)
+		}
+	case models.StatusSuggested:
+		if decision.Rule != ruleObserveMode {
+			h.notifyAction(result, detection)
+		}
+	}
+
+	return result, true
+}
+
 func (h *DetectionHandler) getExecutionMode(ctx context.Context) string {
 	if h.knowledgeClient == nil {
 		return models.ModeAutonomous // Default if no Knowledge client
@@ -122,6 +360,98 @@ func (h *DetectionHandler) getExecutionMode(ctx context.Context) string {
 	return h.knowledgeClient.GetExecutionMode(ctx)
 }
 
+// SetApprovalPolicy configures which actions need approval before they run.
+// With autoExecute false, actions that would run autonomously wait for approval instead.
+// overrides forces an action type to run automatically ("auto") or wait for approval ("approval").
+func (h *DetectionHandler) SetApprovalPolicy(autoExecute bool, overrides map[string]string) {
+	h.settingsMu.Lock()
+	defer h.settingsMu.Unlock()
+
+	h.autoExecute = autoExecute
+	h.approvalOverrides = overrides
+}
+
+// SetActionTimeout changes the timeout for actions that start from now on; running
+// actions keep the timeout they started with.
+func (h *DetectionHandler) SetActionTimeout(timeout time.Duration) {
+	h.settingsMu.Lock()
+	defer h.settingsMu.Unlock()
+
+	h.actionTimeout = timeout
+}
+
+// ActionTimeout returns the timeout applied to actions and rollbacks that start now.
+func (h *DetectionHandler) ActionTimeout() time.Duration {
+	h.settingsMu.RLock()
+	defer h.settingsMu.RUnlock()
+
+	return h.actionTimeout
+}
+
+// SetPgBouncerDefaults configures the settings used for PgBouncer deployments.
+func (h *DetectionHandler) SetPgBouncerDefaults(settings actions.PgBouncerSettings) {
+	h.pgBouncerDefaults = settings
+}
+
+// SetTerminateSettings configures the allow/deny lists and cancel behaviour of terminate_query.
+func (h *DetectionHandler) SetTerminateSettings(settings actions.TerminateSettings) {
+	h.terminateSettings = settings
+}
+
+// SetRollbackSafety configures the checks that can refuse a rollback.
+func (h *DetectionHandler) SetRollbackSafety(safety actions.RollbackSafety) {
+	h.settingsMu.Lock()
+	defer h.settingsMu.Unlock()
+
+	h.rollbackSafety = safety
+}
+
+func (h *DetectionHandler) currentRollbackSafety() actions.RollbackSafety {
+	h.settingsMu.RLock()
+	defer h.settingsMu.RUnlock()
+
+	return h.rollbackSafety
+}
+
+// SetProbeSettings configures the readiness probes run against deployed containers.
+func (h *DetectionHandler) SetProbeSettings(settings docker.ProbeSettings) {
+	h.settingsMu.Lock()
+	defer h.settingsMu.Unlock()
+
+	h.probeSettings = settings
+}
+
+func (h *DetectionHandler) currentProbeSettings() docker.ProbeSettings {
+	h.settingsMu.RLock()
+	defer h.settingsMu.RUnlock()
+
+	return h.probeSettings
+}
+
+// SetResourceLimits configures the memory and CPU limits of deployed Redis and PgBouncer
+// containers. Containers already deployed keep the limits they were created with.
+func (h *DetectionHandler) SetResourceLimits(redis, pgBouncer docker.ResourceLimits) {
+	h.settingsMu.Lock()
+	defer h.settingsMu.Unlock()
+
+	h.redisLimits = redis
+	h.pgBouncerLimits = pgBouncer
+}
+
+func (h *DetectionHandler) currentResourceLimits() (redis, pgBouncer docker.ResourceLimits) {
+	h.settingsMu.RLock()
+	defer h.settingsMu.RUnlock()
+
+	return h.redisLimits, h.pgBouncerLimits
+}
+
+// SetAdapterFactory replaces how database adapters are opened. Call it before handling
+// detections; adapters opened by the previous factory are closed.
+func (h *DetectionHandler) SetAdapterFactory(factory database.AdapterFactory) {
+	h.adapters.Close()
+	h.adapters = database.NewAdapterCache(factory)
+}
+
 // ApproveAction approves a pending action and executes it
 func (h *DetectionHandler) ApproveAction(actionID string) (*models.ActionResult, error) {
 	result, err := h.GetActionStatus(actionID)
@@ -138,19 +468,38 @@ func (h *DetectionHandler) ApproveAction(actionID string) (*models.ActionResult,
 		return nil, fmt.Errorf("action object not found: %w", err)
 	}
 
+	ctx := context.Background()
+
+	// The database's policy may have changed while the action waited
+	if refusal, reason, refused := h.checkDatabasePolicy(ctx, slog.Default(), result.DatabaseID, result.ActionType); refused {
+		status, message := refusedStatus(refusal, result.ActionType, reason)
+		result.Status = status
+		result.Message = message
+		result.PolicyDecision = refusal.Behaviour
+		result.PolicyRule = refusal.Rule
+		h.storeAction(result)
+		h.updateActionStatusInKnowledgeFrom(ctx, result, models.SourceManualApproval)
+
+		if h.natsPublisher != nil {
+			h.natsPublisher.PublishActionStatus(result)
+		}
+
+		logging.With(result.CorrelationID).Info("Action refused on approval", "action_id", actionID, "reason", message)
+		return nil, fmt.Errorf("%w: %s", ErrActionRefused, message)
+	}
+
 	// Update status to approved/queued
 	result.Status = models.StatusQueued
 	result.Message = "Action approved by user"
 	h.storeAction(result)
 
-	ctx := context.Background()
-	h.updateActionStatusInKnowledge(ctx, result)
+	h.updateActionStatusInKnowledgeFrom(ctx, result, models.SourceManualApproval)
 
 	if h.natsPublisher != nil {
 		h.natsPublisher.PublishActionStatus(result)
 	}
 
-	log.Printf("Action approved: %s", actionID)
+	logging.With(result.CorrelationID).Info("Action approved", "action_id", actionID)
 
 	// Create detection from stored result for executeAction
 	detection := &models.Detection{
@@ -159,8 +508,10 @@ func (h *DetectionHandler) ApproveAction(actionID string) (*models.ActionResult,
 		DatabaseID:  result.DatabaseID,
 	}
 
-	// Execute the action
-	go h.executeAction(action, detection)
+	// Queue the action for execution; the operator chose to run it now
+	if err := h.enqueueOperatorAction(action, detection); err != nil {
+		return nil, err
+	}
 
 	return result, nil
 }
@@ -181,13 +532,20 @@ func (h *DetectionHandler) RejectAction(actionID string) (*models.ActionResult,
 	h.storeAction(result)
 
 	ctx := context.Background()
-	h.updateActionStatusInKnowledge(ctx, result)
+	h.updateActionStatusInKnowledgeFrom(ctx, result, models.SourceManualApproval)
 
 	if h.natsPublisher != nil {
 		h.natsPublisher.PublishActionStatus(result)
 	}
 
-	log.Printf("Action rejected: %s", actionID)
+	// The user dismissed the issue - resolve the detection so it stops showing as active
+	if h.knowledgeClient != nil && result.DetectionID != "" {
+		if err := h.knowledgeClient.MarkDetectionResolved(ctx, result.DetectionID, detectionDismissed); err != nil {
+			logging.With(result.CorrelationID).Warn("Failed to resolve rejected detection", "detection_id", result.DetectionID, "error", err)
+		}
+	}
+
+	logging.With(result.CorrelationID).Info("Action rejected", "action_id", actionID)
 
 	return result, nil
 }
@@ -195,7 +553,8 @@ func (h *DetectionHandler) RejectAction(actionID string) (*models.ActionResult,
 func (h *DetectionHandler) createAction(detection *models.Detection, actionID string) (actions.Action, error) {
 	ctx := context.Background()
 
-	// Get database type from detection metadata (set by detector)
+	// Detection metadata carries the type the detector saw; Knowledge's registration wins
+	// once an action needs a connection
 	databaseType := getStringFromMap(detection.ActionMetaData, "database_type", "postgres")
 
 	metadata := &models.ActionMetadata{
@@ -206,41 +565,52 @@ func (h *DetectionHandler) createAction(detection *models.Detection, actionID st
 		CreatedAt:    time.Now(),
 	}
 
+	if fallback, ok := h.manualStepsFallback(detection, actionID, metadata.DatabaseType); ok {
+		return fallback, nil
+	}
+
 	switch detection.ActionType {
 	case "create_index":
-		if h.knowledgeClient == nil {
-			return nil, fmt.Errorf("knowledge client not available - cannot fetch database connection")
+		adapter, err := h.adapterFor(ctx, metadata)
+		if err != nil {
+			return nil, err
 		}
 
-		dbResp, err := h.knowledgeClient.GetServiceClient().GetDatabase(ctx, &pb.GetDatabaseRequest{
-			DatabaseId: detection.DatabaseID,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch database connection from Knowledge: %w", err)
+		tableName, ok := detection.ActionMetaData["table_name"].(string)
+		if !ok {
+			return nil, fmt.Errorf("missing table_name in detection metadata")
 		}
 
-		if !dbResp.Found {
-			return nil, fmt.Errorf("database not found in Knowledge: %s", detection.DatabaseID)
+		// Prefer the ordered multi-column list; older detections only carry column_name
+		columns := columnNames(detection.ActionMetaData["column_names"])
+		if len(columns) == 0 {
+			columnName, ok := detection.ActionMetaData["column_name"].(string)
+			if !ok {
+				return nil, fmt.Errorf("missing column_names in detection metadata")
+			}
+			columns = []string{columnName}
 		}
 
-		adapter, err := database.NewAdapter(ctx, databaseType, dbResp.ConnectionString, detection.DatabaseID)
+		return actions.NewCreateIndexAction(metadata, adapter, tableName, columns, false), nil
+
+	case "drop_index":
+		adapter, err := h.adapterFor(ctx, metadata)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create database adapter: %w", err)
+			return nil, err
 		}
 
-		tableName, ok := detection.ActionMetaData["table_name"].(string)
+		indexName, ok := detection.ActionMetaData["index_name"].(string)
 		if !ok {
-			return nil, fmt.Errorf("missing table_name in detection metadata")
+			return nil, fmt.Errorf("missing index_name in detection metadata")
 		}
 
-		columnName, ok := detection.ActionMetaData["column_name"].(string)
-		if !ok {
-			return nil, fmt.Errorf("missing column_name in detection metadata")
-		}
+		tableName := getStringFromMap(detection.ActionMetaData, "table_name", "")
 
-		return actions.NewCreateIndexAction(metadata, adapter, tableName, []string{columnName}, false), nil
+		return actions.NewDropIndexAction(metadata, adapter, indexName, tableName), nil
 
-	case "cache_optimization_recommendation":
+	case "cache_optimization_recommendation", "deadlock_investigation_recommendation", "idle_connection_cleanup",
+		"enable_query_stats_recommendation", "idle_transaction_recommendation", "replication_lag_recommendation",
+		"storage_growth_recommendation":
 		// Create recommendation action with safe and advanced options
 		return actions.NewRecommendationAction(
 			actionID,
@@ -252,62 +622,121 @@ func (h *DetectionHandler) createAction(detection *models.Detection, actionID st
 
 	// TODO: This is only implemented for PgBouncer, Analyser sends deploy_connection_pooler as a detection, make this choose based on DB later
 	case "deploy_connection_pooler":
-		action, err := actions.NewDeployPgBouncerAction(
+		runtime, err := h.ContainerRuntime()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create PgBouncer action: %w", err)
+		}
+		action := actions.NewDeployPgBouncerActionWithRuntime(
 			actionID,
 			detection.DetectionID,
 			detection.DatabaseID,
 			"postgres",
+			runtime,
 			h.knowledgeClient.GetServiceClient(),
+			h.pgBouncerDefaults,
 			detection.ActionMetaData,
 		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create PgBouncer action: %w", err)
-		}
+		action.SetProbeSettings(h.currentProbeSettings())
+		_, limits := h.currentResourceLimits()
+		action.SetResourceLimits(limits)
 		return action, nil
 
 	case "deploy_redis":
 		// Deploy Redis cache layer (advanced - requires code changes)
-		action, err := actions.NewDeployRedisAction(
+		runtime, err := h.ContainerRuntime()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Redis action: %w", err)
+		}
+		action := actions.NewDeployRedisActionWithRuntime(
 			actionID,
 			detection.DetectionID,
 			detection.DatabaseID,
+			runtime,
 			detection.ActionMetaData,
 		)
+		action.SetProbeSettings(h.currentProbeSettings())
+		limits, _ := h.currentResourceLimits()
+		action.SetResourceLimits(limits)
+		return action, nil
+
+	case "increase_cache_size":
+		dbResp, err := h.lookupDatabase(ctx, metadata)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create Redis action: %w", err)
+			return nil, err
 		}
-		return action, nil
 
-	case "tune_config_high_latency":
-		if h.knowledgeClient == nil {
-			return nil, fmt.Errorf("knowledge client not available - cannot fetch database connection")
+		// Only databases the Collector registered as containerized can be restarted
+		containerName := dbResp.Metadata[metadataDockerContainer]
+		if containerName == "" {
+			return nil, fmt.Errorf("database %s is not docker-managed - increase shared_buffers manually", detection.DatabaseID)
 		}
 
-		dbResp, err := h.knowledgeClient.GetServiceClient().GetDatabase(ctx, &pb.GetDatabaseRequest{
-			DatabaseId: detection.DatabaseID,
-		})
+		adapter, err := h.adapterForDatabase(ctx, metadata, dbResp)
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch database connection from Knowledge: %w", err)
+			return nil, err
 		}
 
-		if !dbResp.Found {
-			return nil, fmt.Errorf("database not found in Knowledge: %s", detection.DatabaseID)
+		runtime, err := h.ContainerRuntime()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create shared_buffers action: %w", err)
 		}
+		action := actions.NewIncreaseSharedBuffersActionWithRuntime(
+			actionID,
+			detection.DetectionID,
+			detection.DatabaseID,
+			containerName,
+			adapter,
+			runtime,
+		)
+		action.SetProbeSettings(h.currentProbeSettings())
+		return action, nil
 
-		adapter, err := database.NewAdapter(ctx, databaseType, dbResp.ConnectionString, detection.DatabaseID)
+	case "tune_config_high_latency":
+		adapter, err := h.adapterFor(ctx, metadata)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create adapter: %w", err)
+			return nil, err
+		}
+
+		// Where config can't be changed at runtime, the detector's fallback still reports
+		// the slow queries rather than failing validation
+		caps := adapter.GetCapabilities()
+		fallback := getStringFromMap(detection.ActionMetaData, "fallback_action_type", "")
+		if fallback == "report_slow_queries" && (!caps.SupportsConfigTuning || !caps.SupportsRuntimeConfigChanges) {
+			log.Printf("%s does not support runtime config tuning, reporting slow queries instead", detection.DatabaseID)
+			metadata.ActionType = fallback
+			action := actions.NewReportSlowQueriesAction(metadata, adapter, slowQueryOptions(detection.ActionMetaData))
+			action.SetReplaces(detection.ActionType)
+			return action, nil
 		}
 
-		return actions.NewTuneConfigAction(
+		action, err := actions.NewTuneConfigAction(
 			actionID,
 			detection.DetectionID,
 			detection.DatabaseID,
-			databaseType,
+			metadata.DatabaseType,
 			adapter,
 		)
+		if err != nil {
+			return nil, err
+		}
+
+		// A temp file spill detection says how much more memory the spilling queries needed
+		if tune, ok := action.(*actions.TuneConfigAction); ok {
+			if avgSpill := getFloatFromMap(detection.ActionMetaData, "avg_spill_bytes", 0); avgSpill > 0 {
+				tune.SetTempSpill(avgSpill)
+			}
+		}
+		return action, nil
 
-	case "optimise_queries":
+	case "report_slow_queries":
+		adapter, err := h.adapterFor(ctx, metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		return actions.NewReportSlowQueriesAction(metadata, adapter, slowQueryOptions(detection.ActionMetaData)), nil
+
+	case "optimise_queries", "investigate_replication_lag":
 		return actions.NewFutureFixAction(
 			actionID,
 			detection.ActionType,
@@ -316,24 +745,9 @@ func (h *DetectionHandler) createAction(detection *models.Detection, actionID st
 		), nil
 
 	case "vacuum_table":
-		if h.knowledgeClient == nil {
-			return nil, fmt.Errorf("knowledge client not available - cannot fetch database connection")
-		}
-
-		dbResp, err := h.knowledgeClient.GetServiceClient().GetDatabase(ctx, &pb.GetDatabaseRequest{
-			DatabaseId: detection.DatabaseID,
-		})
+		adapter, err := h.adapterFor(ctx, metadata)
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch database connection from Knowledge: %w", err)
-		}
-
-		if !dbResp.Found {
-			return nil, fmt.Errorf("database not found in Knowledge: %s", detection.DatabaseID)
-		}
-
-		adapter, err := database.NewAdapter(ctx, databaseType, dbResp.ConnectionString, detection.DatabaseID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create database adapter: %w", err)
+			return nil, err
 		}
 
 		tableName, ok := detection.ActionMetaData["table_name"].(string)
@@ -341,27 +755,23 @@ func (h *DetectionHandler) createAction(detection *models.Detection, actionID st
 			return nil, fmt.Errorf("missing table_name in detection metadata")
 		}
 
-		return actions.NewVacuumTableAction(metadata, adapter, tableName), nil
-
-	case "terminate_query":
-		if h.knowledgeClient == nil {
-			return nil, fmt.Errorf("knowledge client not available - cannot fetch database connection")
+		options := actions.VacuumOptions{
+			Mode:                  getStringFromMap(detection.ActionMetaData, "mode", actions.VacuumModeAnalyze),
+			AutovacuumScaleFactor: getFloatFromMap(detection.ActionMetaData, "autovacuum_vacuum_scale_factor", 0),
 		}
 
-		dbResp, err := h.knowledgeClient.GetServiceClient().GetDatabase(ctx, &pb.GetDatabaseRequest{
-			DatabaseId: detection.DatabaseID,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch database connection from Knowledge: %w", err)
+		// VACUUM FULL blocks the table, so it only ever runs inside the maintenance window
+		if options.Mode == actions.VacuumModeFull && h.maintenanceWindow == nil {
+			log.Printf("VACUUM FULL on %s needs a maintenance window (MAINTENANCE_WINDOW unset), running VACUUM ANALYZE instead", tableName)
+			options.Mode = actions.VacuumModeAnalyze
 		}
 
-		if !dbResp.Found {
-			return nil, fmt.Errorf("database not found in Knowledge: %s", detection.DatabaseID)
-		}
+		return actions.NewVacuumTableAction(metadata, adapter, tableName, options), nil
 
-		adapter, err := database.NewAdapter(ctx, databaseType, dbResp.ConnectionString, detection.DatabaseID)
+	case "terminate_query":
+		adapter, err := h.adapterFor(ctx, metadata)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create database adapter: %w", err)
+			return nil, err
 		}
 
 		pidStr, ok := detection.ActionMetaData["pid"].(string)
@@ -374,29 +784,137 @@ func (h *DetectionHandler) createAction(detection *models.Detection, actionID st
 			return nil, fmt.Errorf("invalid pid format: %s", pidStr)
 		}
 
+		// The query text captured at detection time is compared with what the PID runs now,
+		// so a PID recycled for another session is not terminated
 		username := getStringFromMap(detection.ActionMetaData, "username", "unknown")
+		query := getStringFromMap(detection.ActionMetaData, "query", "")
 		graceful := true
 		if g, ok := detection.ActionMetaData["graceful"].(bool); ok {
 			graceful = g
 		}
 
-		return actions.NewTerminateQueryAction(metadata, adapter, pid, username, graceful), nil
+		return actions.NewTerminateQueryAction(metadata, adapter, pid, username, query, graceful, h.terminateSettings), nil
 
 	default:
 		return nil, fmt.Errorf("action type not implemented yet: %s", detection.ActionType)
 	}
 }
 
-func (h *DetectionHandler) executeAction(action actions.Action, detection *models.Detection) {
+// adapterFor returns the shared adapter for the action's database, looking up the
+// connection string and database type in Knowledge. metadata.DatabaseType is updated
+// to the registered type.
+func (h *DetectionHandler) adapterFor(ctx context.Context, metadata *models.ActionMetadata) (database.DatabaseAdapter, error) {
+	dbResp, err := h.lookupDatabase(ctx, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.adapterForDatabase(ctx, metadata, dbResp)
+}
+
+// lookupDatabase fetches the action's database registration from Knowledge.
+func (h *DetectionHandler) lookupDatabase(ctx context.Context, metadata *models.ActionMetadata) (*pb.GetDatabaseResponse, error) {
+	if h.knowledgeClient == nil {
+		return nil, fmt.Errorf("knowledge client not available - cannot fetch database connection")
+	}
+
+	dbResp, err := h.knowledgeClient.GetServiceClient().GetDatabase(ctx, &pb.GetDatabaseRequest{
+		DatabaseId: metadata.DatabaseID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch database connection from Knowledge: %w", err)
+	}
+
+	if !dbResp.Found {
+		return nil, fmt.Errorf("database not found in Knowledge: %s", metadata.DatabaseID)
+	}
+
+	return dbResp, nil
+}
+
+// adapterForDatabase returns the shared adapter for a database registration.
+func (h *DetectionHandler) adapterForDatabase(ctx context.Context, metadata *models.ActionMetadata, dbResp *pb.GetDatabaseResponse) (database.DatabaseAdapter, error) {
+	if dbResp.DatabaseType != "" {
+		metadata.DatabaseType = dbResp.DatabaseType
+	}
+
+	adapter, err := h.adapters.Get(ctx, metadata.DatabaseType, dbResp.ConnectionString, metadata.DatabaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create database adapter: %w", err)
+	}
+
+	return adapter, nil
+}
+
+// executeAction runs a queued action under ctx (which carries the action timeout).
+// Status updates use their own context so a timed-out action can still be recorded.
+// Failures with a transient error code go back in the queue until the retry policy's
+// attempts run out.
+func (h *DetectionHandler) executeAction(ctx context.Context, q *queuedAction) {
+	action, detection := q.action, q.detection
 	if action == nil {
-		log.Printf("Warning: executeAction called with nil action for detection %s", detection.DetectionID)
+		slog.Warn("executeAction called with nil action", "detection_id", detection.DetectionID)
 		return
 	}
 
-	ctx := context.Background()
+	statusCtx := logging.WithCorrelationID(context.Background(), detection.CorrelationID)
 	metadata := action.GetMetadata()
 
-	log.Printf("\tExecuting Action: %s (ID: %s)", metadata.ActionType, metadata.ActionID)
+	logger := logging.With(detection.CorrelationID).With(
+		"action_id", metadata.ActionID,
+		"action_type", metadata.ActionType,
+		"database_id", metadata.DatabaseID)
+	logger.Info("Executing action")
+
+	// Queued and scheduled actions were allowed when created; the policy may have changed since
+	if refusal, reason, refused := h.checkDatabasePolicy(statusCtx, logger, metadata.DatabaseID, metadata.ActionType); refused {
+		status, message := refusedStatus(refusal, metadata.ActionType, reason)
+		logger.Warn("Action refused by database policy", "policy_rule", refusal.Rule, "reason", reason)
+		now := time.Now()
+		h.finishResult(statusCtx, &models.ActionResult{
+			ActionID:    metadata.ActionID,
+			DetectionID: detection.DetectionID,
+			ActionType:  metadata.ActionType,
+			DatabaseID:  metadata.DatabaseID,
+			Status:      status,
+			Message:     message,
+			CreatedAt:   metadata.CreatedAt,
+			Completed:   &now,
+
+			CorrelationID: detection.CorrelationID,
+
+			PolicyDecision: refusal.Behaviour,
+			PolicyRule:     refusal.Rule,
+		})
+		return
+	}
+
+	// Validate before reporting "executing" so a bad action fails with a clear reason
+	if err := action.Validate(ctx); err != nil {
+		logger.Error("Action validation failed", "error", err)
+		failed := &models.ActionResult{
+			ActionID:    metadata.ActionID,
+			DetectionID: detection.DetectionID,
+			ActionType:  metadata.ActionType,
+			DatabaseID:  metadata.DatabaseID,
+			Status:      models.StatusFailed,
+			Message:     fmt.Sprintf("Validation failed: %v", err),
+			Error:       err.Error(),
+			ErrorCode:   models.ClassifyValidationError(err),
+			Attempts:    q.attempt + 1,
+			CreatedAt:   metadata.CreatedAt,
+
+			CorrelationID: detection.CorrelationID,
+		}
+		if h.retryFailed(q, failed) {
+			return
+		}
+		metrics.ActionsExecuted.WithLabelValues(metadata.ActionType, models.StatusFailed).Inc()
+		metrics.ActionsFailed.WithLabelValues(metadata.ActionType).Inc()
+		h.finishResult(statusCtx, failed)
+		h.notifyAction(failed, detection)
+		return
+	}
 
 	executingResult := &models.ActionResult{
 		ActionID:    metadata.ActionID,
@@ -406,6 +924,8 @@ func (h *DetectionHandler) executeAction(action actions.Action, detection *model
 		Status:      models.StatusExecuting,
 		Message:     "Action executing",
 		CreatedAt:   metadata.CreatedAt,
+
+		CorrelationID: detection.CorrelationID,
 	}
 	h.storeAction(executingResult)
 
@@ -413,11 +933,31 @@ func (h *DetectionHandler) executeAction(action actions.Action, detection *model
 		h.natsPublisher.PublishActionStatus(executingResult)
 	}
 
-	h.updateActionStatusInKnowledge(ctx, executingResult)
+	h.updateActionStatusInKnowledge(statusCtx, executingResult)
+
+	if reporter, ok := action.(actions.ProgressReporter); ok {
+		reporter.SetProgressFunc(func(progress *models.ActionProgress) {
+			h.reportProgress(statusCtx, executingResult, progress)
+		})
+	}
 
+	metrics.ActionsInFlight.Inc()
+	execStart := time.Now()
 	result, err := action.Execute(ctx)
-	if err != nil {
-		log.Printf("Action execution failed: %v", err)
+	metrics.ActionDuration.WithLabelValues(metadata.ActionType).Observe(time.Since(execStart).Seconds())
+	metrics.ActionsInFlight.Dec()
+	if err != nil && result != nil {
+		// The action reported what it did before failing; keep its changes
+		logger.Error("Action execution failed", "error", err)
+		result.Status = models.StatusFailed
+		if result.Error == "" {
+			result.Error = err.Error()
+		}
+		if result.ErrorCode == "" {
+			result.ErrorCode = models.ClassifyError(err)
+		}
+	} else if err != nil {
+		logger.Error("Action execution failed", "error", err)
 		result = &models.ActionResult{
 			ActionID:    metadata.ActionID,
 			DetectionID: detection.DetectionID,
@@ -426,36 +966,77 @@ func (h *DetectionHandler) executeAction(action actions.Action, detection *model
 			Status:      models.StatusFailed,
 			Message:     "Execution error",
 			Error:       err.Error(),
+			ErrorCode:   models.ClassifyError(err),
 			CreatedAt:   metadata.CreatedAt,
 		}
 	}
 
+	result.CorrelationID = detection.CorrelationID
+	if result.DetectionID == "" {
+		result.DetectionID = detection.DetectionID
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) && result.Status != models.StatusCompleted {
+		result.Status = models.StatusFailed
+		result.Message = "Action timed out"
+		result.Error = fmt.Sprintf("action timed out after %s", h.ActionTimeout())
+		result.ErrorCode = models.ErrorCodeTimeout
+	} else if errors.Is(ctx.Err(), context.Canceled) && result.Status != models.StatusCompleted {
+		result.Status = models.StatusFailed
+		result.Message = "Action cancelled during shutdown"
+		result.Error = ErrShuttingDown.Error()
+		result.ErrorCode = models.ErrorCodeCancelled
+	}
+
+	result.Attempts = q.attempt + 1
+	if result.Status == models.StatusFailed {
+		if result.ErrorCode == "" {
+			result.ErrorCode = models.ErrorCodeInternal
+		}
+		if h.retryFailed(q, result) {
+			return
+		}
+	}
+
 	h.storeAction(result)
 
-	h.updateActionStatusInKnowledge(ctx, result)
+	metrics.ActionsExecuted.WithLabelValues(metadata.ActionType, result.Status).Inc()
+	if result.Status == models.StatusFailed {
+		metrics.ActionsFailed.WithLabelValues(metadata.ActionType).Inc()
+	}
+
+	h.updateActionStatusInKnowledge(statusCtx, result)
+
+	if result.Status == models.StatusPendingImplementation {
+		h.acknowledgeDetection(statusCtx, detection, result.Message)
+	}
 
 	if h.natsPublisher != nil {
 		if err := h.natsPublisher.PublishActionStatus(result); err != nil {
-			log.Printf("Warning: failed to publish action status to event bus: %v", err)
+			logger.Warn("Failed to publish action status to event bus", "error", err)
 		}
 
 		if result.Status == models.StatusCompleted {
 			if err := h.natsPublisher.PublishActionCompleted(result, detection); err != nil {
-				log.Printf("Warning: failed to publish action completion: %v", err)
+				logger.Warn("Failed to publish action completion", "error", err)
 			}
 		}
 	}
 
+	if result.Status == models.StatusFailed {
+		h.publishUndone(result, detection)
+	}
+
+	h.notifyAction(result, detection)
+
 	switch result.Status {
 	case models.StatusCompleted:
-		log.Printf("\tAction Completed: %s (ID: %s)", metadata.ActionType, metadata.ActionID)
-		log.Printf("\tChanges: %v", result.Changes)
+		logger.Info("Action completed", "execution_time_ms", result.ExecutionTimeMs)
+		logger.Debug("Action changes", "changes", result.Changes)
 	case models.StatusPendingImplementation:
-		log.Printf("\t⏸Action Pending Implementation: %s (ID: %s)", metadata.ActionType, metadata.ActionID)
-		log.Printf("\tReason: %s", result.Message)
+		logger.Info("Action pending implementation", "reason", result.Message)
 	default:
-		log.Printf("\tAction Failed: %s (ID: %s)", metadata.ActionType, metadata.ActionID)
-		log.Printf("\tError: %s", result.Error)
+		logger.Error("Action failed", "error", result.Error)
 	}
 }
 
@@ -465,7 +1046,7 @@ func (h *DetectionHandler) GetActionStatus(actionID string) (*models.ActionResul
 
 	action, exists := h.actions[actionID]
 	if !exists {
-		return nil, fmt.Errorf("action not found: %s", actionID)
+		return nil, fmt.Errorf("%w: %s", ErrActionNotFound, actionID)
 	}
 
 	return action, nil
@@ -484,54 +1065,186 @@ func (h *DetectionHandler) ListPendingActions(statusFilter string) ([]*models.Ac
 		results = append(results, action)
 	}
 
+	sortActions(results)
+
 	log.Printf("Listed %d actions (filter: %s)", len(results), statusFilter)
 
 	return results, nil
 }
 
+// RollbackAction reverts a completed action at a user's request. Repeating the request for
+// an action that is already rolled back returns its result without error. A rollback the
+// safety checks find unsafe is refused with an error wrapping actions.ErrRollbackUnsafe.
 func (h *DetectionHandler) RollbackAction(actionID string) (*models.ActionResult, error) {
+	return h.rollback(actionID, models.SourceManualRollback, "", false)
+}
+
+// ForceRollbackAction reverts a completed action at a user's request without the safety
+// checks, for when the user knows nothing depends on the change any more.
+func (h *DetectionHandler) ForceRollbackAction(actionID string) (*models.ActionResult, error) {
+	return h.rollback(actionID, models.SourceManualRollback, "", true)
+}
+
+// RollbackFailedVerification reverts an action the Analyser found did not fix its issue.
+// Redelivered requests are harmless: an action already rolled back is left as it is.
+func (h *DetectionHandler) RollbackFailedVerification(actionID, reason string) (*models.ActionResult, error) {
+	return h.rollback(actionID, models.SourceVerificationRollback, reason, false)
+}
+
+func (h *DetectionHandler) rollback(actionID, source, reason string, force bool) (*models.ActionResult, error) {
+	ctx := context.Background()
+
+	h.rollbackMu.Lock()
+	defer h.rollbackMu.Unlock()
+
 	result, err := h.GetActionStatus(actionID)
 	if err != nil {
-		return nil, fmt.Errorf("action not found: %w", err)
+		// Actions from a previous Executor process are only known to Knowledge
+		result, err = h.loadActionFromKnowledge(ctx, actionID)
+		if err != nil {
+			return nil, err
+		}
 	}
 
+	if result.Status == models.StatusRolledBack {
+		logging.With(result.CorrelationID).Info("Action already rolled back, ignoring duplicate rollback request", "action_id", actionID)
+		return result, nil
+	}
+
+	// The stored result is shared with the worker and status readers; update a copy and store it back
+	result = copyActionResult(result)
+
 	if !result.CanRollback {
 		return nil, fmt.Errorf("action does not support rollback")
 	}
 
-	if result.Status != models.StatusCompleted {
+	// A regressed change is still in place, and a failed rollback may be retried once the cause is fixed
+	switch result.Status {
+	case models.StatusCompleted, models.StatusRegressed, models.StatusRollbackFailed:
+	default:
 		return nil, fmt.Errorf("can only rollback completed actions, current status: %s", result.Status)
 	}
 
 	action, err := h.getActionObject(actionID)
 	if err != nil {
-		return nil, fmt.Errorf("action object not found: %w", err)
+		action, err = h.reconstructForRollback(ctx, result)
+		if err != nil {
+			return h.markRollbackFailed(ctx, result, source, err)
+		}
+		h.storeActionObject(actionID, action)
 	}
 
-	ctx := context.Background()
-	err = action.Rollback(ctx)
+	// Rollbacks get the same deadline as actions; status updates keep the unbounded ctx
+	timeout := h.ActionTimeout()
+	rollbackCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if checker, ok := action.(actions.RollbackChecker); ok && !force {
+		if err := checker.CheckRollback(rollbackCtx, h.currentRollbackSafety()); err != nil {
+			return h.refuseRollback(ctx, result, source, err)
+		}
+	}
+
+	rollbackStart := time.Now()
+	err = action.Rollback(rollbackCtx)
+	metrics.RollbackDuration.WithLabelValues(result.ActionType).Observe(time.Since(rollbackStart).Seconds())
 	if err != nil {
-		return nil, fmt.Errorf("rollback failed: %w", err)
+		metrics.ActionsRolledBack.WithLabelValues(result.ActionType, "failed").Inc()
+		if errors.Is(rollbackCtx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("rollback timed out after %s: %w", timeout, err)
+		} else {
+			err = fmt.Errorf("rollback failed: %w", err)
+		}
+		return h.markRollbackFailed(ctx, result, source, err)
 	}
+	metrics.ActionsRolledBack.WithLabelValues(result.ActionType, "success").Inc()
 
 	result.Status = models.StatusRolledBack
 	result.Rolledback = true
+	result.RollbackError = ""
+	if result.Changes == nil {
+		result.Changes = map[string]interface{}{}
+	}
+	delete(result.Changes, "rollback_refusal")
+	if force {
+		result.Changes["rollback_forced"] = true
+	}
 	result.Message = "Action rolled back successfully"
+	if reason != "" {
+		result.Message = fmt.Sprintf("Action rolled back: %s", reason)
+	}
 	h.storeAction(result)
 
-	if h.knowledgeClient != nil {
-		h.updateActionStatusInKnowledge(ctx, result)
-	}
+	h.updateActionStatusInKnowledgeFrom(ctx, result, source)
 
 	if h.natsPublisher != nil {
 		h.natsPublisher.PublishActionStatus(result)
 	}
+	h.publishUndone(result, nil)
+
+	logging.With(result.CorrelationID).Info("Action rolled back", "action_id", actionID)
 
-	log.Printf("Action rolled back: %s", actionID)
+	h.notifyAction(result, nil)
 
 	return result, nil
 }
 
+// refuseRollback records why a rollback was refused on the action, which stays in place with
+// its status unchanged, and returns err.
+func (h *DetectionHandler) refuseRollback(ctx context.Context, result *models.ActionResult, source string, err error) (*models.ActionResult, error) {
+	metrics.ActionsRolledBack.WithLabelValues(result.ActionType, "refused").Inc()
+
+	if result.Changes == nil {
+		result.Changes = map[string]interface{}{}
+	}
+	result.Changes["rollback_refusal"] = err.Error()
+	reasonText := strings.TrimPrefix(err.Error(), actions.ErrRollbackUnsafe.Error()+": ")
+	result.Message = fmt.Sprintf("Rollback refused: %s. Force the rollback to proceed anyway", reasonText)
+	h.storeAction(result)
+
+	h.updateActionStatusInKnowledgeFrom(ctx, result, source)
+
+	if h.natsPublisher != nil {
+		if pubErr := h.natsPublisher.PublishActionStatus(result); pubErr != nil {
+			logging.With(result.CorrelationID).Warn("Failed to publish rollback refusal", "action_id", result.ActionID, "error", pubErr)
+		}
+	}
+
+	logging.With(result.CorrelationID).Warn("Rollback refused", "action_id", result.ActionID, "error", err)
+
+	return result, err
+}
+
+// markRollbackFailed records and publishes a failed rollback, so the action no longer
+// reads as a healthy completed change, and returns err.
+func (h *DetectionHandler) markRollbackFailed(ctx context.Context, result *models.ActionResult, source string, err error) (*models.ActionResult, error) {
+	result.Status = models.StatusRollbackFailed
+	result.RollbackError = err.Error()
+	result.Message = fmt.Sprintf("Rollback failed: %v", err)
+	h.storeAction(result)
+
+	h.updateActionStatusInKnowledgeFrom(ctx, result, source)
+
+	if h.natsPublisher != nil {
+		if pubErr := h.natsPublisher.PublishActionStatus(result); pubErr != nil {
+			logging.With(result.CorrelationID).Warn("Failed to publish rollback failure", "action_id", result.ActionID, "error", pubErr)
+		}
+	}
+
+	logging.With(result.CorrelationID).Error("Rollback failed", "action_id", result.ActionID, "error", err)
+
+	h.notifyAction(result, nil)
+
+	return result, err
+}
+
+// copyActionResult returns a copy of result that can be changed without touching the stored one.
+func copyActionResult(result *models.ActionResult) *models.ActionResult {
+	copied := *result
+	copied.Changes = maps.Clone(result.Changes)
+	return &copied
+}
+
 func (h *DetectionHandler) storeAction(action *models.ActionResult) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
@@ -539,10 +1252,39 @@ func (h *DetectionHandler) storeAction(action *models.ActionResult) {
 	h.actions[action.ActionID] = action
 }
 
-func generateActionID() string {
-	return fmt.Sprintf("action-%d", time.Now().UnixNano())
+// generateActionID derives a stable action ID from the detection, so a detection
+// redelivered after an Executor restart maps to the action already recorded for it.
+func generateActionID(detection *models.Detection) string {
+	if detection.DetectionID == "" {
+		return fmt.Sprintf("action-%d", time.Now().UnixNano())
+	}
+
+	sum := sha256.Sum256([]byte(detection.DetectionID + ":" + detection.ActionType))
+	return "action-" + hex.EncodeToString(sum[:8])
+}
+
+// actionExists reports whether an action ID is already known locally or in Knowledge.
+func (h *DetectionHandler) actionExists(ctx context.Context, actionID string) bool {
+	if _, err := h.GetActionStatus(actionID); err == nil {
+		return true
+	}
+
+	if h.knowledgeClient == nil {
+		return false
+	}
+
+	_, found, err := h.knowledgeClient.GetAction(ctx, actionID)
+	if err != nil {
+		slog.Warn("Failed to check for existing action", "action_id", actionID, "error", err)
+		return false
+	}
+
+	return found
 }
 
+// checkForDuplicateActions reports whether an action is already pending for the detection's
+// issue. A re-published detection has a new ID but the same key, so keys are compared;
+// actions registered before keys were persisted fall back to the detection ID.
 func (h *DetectionHandler) checkForDuplicateActions(ctx context.Context, detection *models.Detection) (bool, error) {
 	pendingActions, err := h.knowledgeClient.GetPendingActions(ctx, detection.DatabaseID)
 	if err != nil {
@@ -553,41 +1295,116 @@ func (h *DetectionHandler) checkForDuplicateActions(ctx context.Context, detecti
 		if pending.DetectionId == detection.DetectionID {
 			return true, nil
 		}
+
+		var state persistedActionState
+		if pending.ActionState == "" || json.Unmarshal([]byte(pending.ActionState), &state) != nil {
+			continue
+		}
+		if detectionkey.SameIssue(state.DetectionKey, detection.Key) {
+			return true, nil
+		}
 	}
 
 	return false, nil
 }
 
-func (h *DetectionHandler) registerActionWithKnowledge(ctx context.Context, detection *models.Detection, result *models.ActionResult) error {
+// registerActionWithKnowledge records a new action; source says what triggered it (see models.Source*).
+func (h *DetectionHandler) registerActionWithKnowledge(ctx context.Context, detection *models.Detection, result *models.ActionResult, source string) error {
+	state, err := json.Marshal(persistedActionState{
+		DetectionID:    detection.DetectionID,
+		DetectionKey:   detection.Key,
+		ActionType:     detection.ActionType,
+		DatabaseID:     detection.DatabaseID,
+		Severity:       detection.Severity,
+		ActionMetadata: detection.ActionMetaData,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode action state: %w", err)
+	}
+
 	return h.knowledgeClient.RegisterAction(ctx, &pb.RegisterActionRequest{
 		Id:          result.ActionID,
 		DetectionId: detection.DetectionID,
 		ActionType:  result.ActionType,
 		DatabaseId:  result.DatabaseID,
 		CreatedAt:   result.CreatedAt.Unix(),
+		Status:      result.Status,
+		ActionState: string(state),
+		Source:      source,
+
+		PolicyDecision: result.PolicyDecision,
+		PolicyRule:     result.PolicyRule,
 	})
 }
 
 func (h *DetectionHandler) updateActionStatusInKnowledge(ctx context.Context, result *models.ActionResult) {
+	h.updateActionStatusInKnowledgeFrom(ctx, result, "")
+}
+
+// updateActionStatusInKnowledgeFrom records a status change caused by source rather than
+// by whatever triggered the action, e.g. a user approving it or the Analyser rolling it back.
+func (h *DetectionHandler) updateActionStatusInKnowledgeFrom(ctx context.Context, result *models.ActionResult, source string) {
 	if h.knowledgeClient == nil {
 		return
 	}
 
-	err := h.knowledgeClient.UpdateActionStatus(ctx, &pb.UpdateActionRequest{
-		ActionId:  result.ActionID,
-		Status:    string(result.Status),
-		Message:   result.Message,
-		Error:     result.Error,
-		Timestamp: time.Now().Unix(),
-	})
+	var changes string
+	if len(result.Changes) > 0 {
+		if data, err := json.Marshal(result.Changes); err != nil {
+			logging.With(result.CorrelationID).Warn("Failed to encode changes for action", "action_id", result.ActionID, "error", err)
+		} else {
+			changes = string(data)
+		}
+	}
+
+	req := &pb.UpdateActionRequest{
+		ActionId:    result.ActionID,
+		Status:      string(result.Status),
+		Message:     result.Message,
+		Error:       result.Error,
+		ErrorCode:   string(result.ErrorCode),
+		Timestamp:   time.Now().Unix(),
+		Changes:     changes,
+		CanRollback: result.CanRollback && !result.Rolledback,
+		Source:      source,
+	}
+
+	if result.Progress != nil {
+		req.Progress = &pb.ActionProgress{
+			Phase:       result.Progress.Phase,
+			BlocksDone:  result.Progress.BlocksDone,
+			BlocksTotal: result.Progress.BlocksTotal,
+			TuplesDone:  result.Progress.TuplesDone,
+			TuplesTotal: result.Progress.TuplesTotal,
+			UpdatedAt:   result.Progress.UpdatedAt.Unix(),
+		}
+	}
+
+	err := h.knowledgeClient.UpdateActionStatus(ctx, req)
 
 	if err != nil {
-		log.Printf("warning failed to update action in knowledge: %v", err)
+		logging.With(result.CorrelationID).Warn("Failed to update action in knowledge", "action_id", result.ActionID, "error", err)
 	} else {
-		log.Printf("Action updated in knowledge: %s -> %s", result.ActionID, result.Status)
+		logging.With(result.CorrelationID).Info("Action updated in knowledge", "action_id", result.ActionID, "status", result.Status)
 	}
 }
 
+// reportProgress records progress of an executing action and publishes it to the Dashboard.
+func (h *DetectionHandler) reportProgress(ctx context.Context, executing *models.ActionResult, progress *models.ActionProgress) {
+	updated := *executing
+	updated.Progress = progress
+	updated.Message = fmt.Sprintf("Action executing: %s (%.0f%%)", progress.Phase, progress.Percent())
+	h.storeAction(&updated)
+
+	if h.natsPublisher != nil {
+		if err := h.natsPublisher.PublishActionProgress(&updated); err != nil {
+			slog.Warn("Failed to publish action progress", "error", err)
+		}
+	}
+
+	h.updateActionStatusInKnowledge(ctx, &updated)
+}
+
 func (h *DetectionHandler) storeActionObject(actionID string, action actions.Action) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
@@ -610,7 +1427,7 @@ func (h *DetectionHandler) getActionObject(actionID string) (actions.Action, err
 // Used for user-triggered actions from Dashboard (e.g., manual Redis deployment).
 func (h *DetectionHandler) ExecuteActionDirectly(action actions.Action, detection *models.Detection) {
 	if action == nil {
-		log.Printf("Warning: ExecuteActionDirectly called with nil action")
+		slog.Warn("ExecuteActionDirectly called with nil action")
 		return
 	}
 
@@ -634,8 +1451,8 @@ func (h *DetectionHandler) ExecuteActionDirectly(action actions.Action, detectio
 
 	// Register with Knowledge if available
 	if h.knowledgeClient != nil {
-		if err := h.registerActionWithKnowledge(context.Background(), detection, result); err != nil {
-			log.Printf("Warning: failed to register action with Knowledge: %v", err)
+		if err := h.registerActionWithKnowledge(context.Background(), detection, result, models.SourceManual); err != nil {
+			slog.Warn("Failed to register action with Knowledge", "error", err)
 		}
 	}
 
@@ -644,8 +1461,14 @@ func (h *DetectionHandler) ExecuteActionDirectly(action actions.Action, detectio
 		h.natsPublisher.PublishActionStatus(result)
 	}
 
-	// Execute action (reuse existing executeAction method)
-	h.executeAction(action, detection)
+	// Queue action for execution
+	if err := h.enqueueOperatorAction(action, detection); err != nil {
+		slog.Warn("Failed to queue action", "action_id", actionID, "error", err)
+		result.Status = models.StatusFailed
+		result.Error = err.Error()
+		result.ErrorCode = models.ClassifyError(err)
+		h.finishResult(context.Background(), result)
+	}
 }
 
 // Helper function to safely get string from map with default value
@@ -657,3 +1480,29 @@ func getStringFromMap(m map[string]interface{}, key string, defaultValue string)
 	}
 	return defaultValue
 }
+
+// slowQueryOptions reads a slow query report's threshold, limit and whether to EXPLAIN
+// from detection metadata, defaulting whatever is unset.
+func slowQueryOptions(m map[string]interface{}) actions.SlowQueryOptions {
+	options := actions.DefaultSlowQueryOptions()
+	options.ThresholdMs = getFloatFromMap(m, "slow_query_threshold_ms", options.ThresholdMs)
+	options.Limit = int(getFloatFromMap(m, "slow_query_limit", float64(options.Limit)))
+	if explain, ok := m["explain"].(bool); ok {
+		options.Explain = explain
+	}
+	return options
+}
+
+// getFloatFromMap reads a number from detection metadata, which decodes from JSON as
+// float64 but may also arrive as a string.
+func getFloatFromMap(m map[string]interface{}, key string, defaultValue float64) float64 {
+	switch val := m[key].(type) {
+	case float64:
+		return val
+	case string:
+		if f, err := strconv.ParseFloat(val, 64); err == nil {
+			return f
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"log"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/actions"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/docker"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+)
+
+// containerActionTypes need a Docker engine to run.
+var containerActionTypes = map[string]bool{
+	"deploy_connection_pooler": true,
+	"deploy_pgbouncer":         true,
+	"deploy_redis":             true,
+	"increase_cache_size":      true,
+}
+
+// SetDocker sets the container runtime shared by container-backed actions, as found by the
+// startup probe. A non-nil unavailable marks Docker unreachable: those actions then become
+// recommendations with manual steps rather than failing. Until SetDocker is called, each
+// action connects on its own using the DOCKER_* environment.
+func (h *DetectionHandler) SetDocker(runtime docker.Runtime, unavailable error) {
+	h.settingsMu.Lock()
+	defer h.settingsMu.Unlock()
+
+	h.dockerProbed = true
+	h.dockerRuntime = runtime
+	h.dockerUnavailable = unavailable
+}
+
+// ContainerRuntime returns the runtime for a container-backed action, or why Docker is unavailable.
+func (h *DetectionHandler) ContainerRuntime() (docker.Runtime, error) {
+	h.settingsMu.RLock()
+	probed, runtime, unavailable := h.dockerProbed, h.dockerRuntime, h.dockerUnavailable
+	h.settingsMu.RUnlock()
+
+	if !probed {
+		return docker.NewClient()
+	}
+	if unavailable != nil {
+		return nil, unavailable
+	}
+	return runtime, nil
+}
+
+// dockerUnavailableFor returns why an action of actionType cannot run, or nil if it either
+// does not need Docker or Docker is reachable.
+func (h *DetectionHandler) dockerUnavailableFor(actionType string) error {
+	if !containerActionTypes[actionType] {
+		return nil
+	}
+
+	h.settingsMu.RLock()
+	defer h.settingsMu.RUnlock()
+	return h.dockerUnavailable
+}
+
+// manualStepsFallback replaces a container-backed action with a recommendation when Docker
+// is unavailable. The second result is false when the action can run as usual.
+func (h *DetectionHandler) manualStepsFallback(detection *models.Detection, actionID, databaseType string) (actions.Action, bool) {
+	unavailable := h.dockerUnavailableFor(detection.ActionType)
+	if unavailable == nil {
+		return nil, false
+	}
+
+	actionType := detection.ActionType
+	if actionType == "deploy_pgbouncer" {
+		actionType = "deploy_connection_pooler"
+	}
+
+	recommendation, ok := actions.ContainerManualSteps(actionType, detection.DatabaseID, h.pgBouncerDefaults, detection.ActionMetaData, unavailable.Error())
+	if !ok {
+		return nil, false
+	}
+
+	log.Printf("Docker unavailable - %s for %s becomes manual steps", detection.ActionType, detection.DatabaseID)
+	return actions.NewManualStepsAction(actionID, detection.DetectionID, detection.DatabaseID, databaseType, actionType, recommendation), true
+}
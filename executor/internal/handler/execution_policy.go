@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+)
+
+// ruleObserveMode is the rule recorded when the system execution mode only observes.
+const ruleObserveMode = "execution_mode=observe"
+
+// ExecutionDecision is how the policy handles a detection's action, and the rule that
+// decided it, e.g. "severity:critical=approve". It is recorded on the action so the
+// Dashboard can explain why an action is waiting.
+type ExecutionDecision struct {
+	Behaviour string // models.PolicyAuto, PolicyApprove, PolicyNotify or PolicyDeny
+	Rule      string
+}
+
+// SetExecutionPolicy maps detection severities to how their actions are handled: "auto"
+// queues them, "approve" waits for a user and "notify" only suggests them. Severities
+// without an entry run automatically, subject to the approval policy.
+func (h *DetectionHandler) SetExecutionPolicy(policy map[string]string) {
+	normalised := make(map[string]string, len(policy))
+	for severity, behaviour := range policy {
+		normalised[strings.ToLower(severity)] = behaviour
+	}
+
+	h.settingsMu.Lock()
+	defer h.settingsMu.Unlock()
+
+	h.executionPolicy = normalised
+}
+
+// decideExecution applies, in order: the system execution mode when it is observe, the
+// per-action-type approval overrides, the severity policy when it asks for approval or
+// notification, and finally the action types that always need approval, ENABLE_AUTO_EXECUTION
+// and the system execution mode.
+func (h *DetectionHandler) decideExecution(mode string, detection *models.Detection) ExecutionDecision {
+	// Nothing runs while the system only observes
+	if mode == models.ModeObserve {
+		return ExecutionDecision{models.PolicyNotify, ruleObserveMode}
+	}
+
+	h.settingsMu.RLock()
+	defer h.settingsMu.RUnlock()
+
+	actionType := detection.ActionType
+	switch h.approvalOverrides[actionType] {
+	case models.ApprovalAuto:
+		return ExecutionDecision{models.PolicyAuto, fmt.Sprintf("action_override:%s=%s", actionType, models.ApprovalAuto)}
+	case models.ApprovalRequired:
+		return ExecutionDecision{models.PolicyApprove, fmt.Sprintf("action_override:%s=%s", actionType, models.ApprovalRequired)}
+	}
+
+	severity := strings.ToLower(detection.Severity)
+	behaviour, matched := h.executionPolicy[severity]
+	severityRule := fmt.Sprintf("severity:%s=%s", severity, behaviour)
+	if matched && behaviour != models.PolicyAuto {
+		return ExecutionDecision{behaviour, severityRule}
+	}
+
+	switch {
+	case approvalRequiredByDefault[actionType]:
+		return ExecutionDecision{models.PolicyApprove, "approval_required:" + actionType}
+	case !h.autoExecute:
+		return ExecutionDecision{models.PolicyApprove, "auto_execution_disabled"}
+	case mode == models.ModeApproval:
+		return ExecutionDecision{models.PolicyApprove, "execution_mode=approval"}
+	case matched:
+		return ExecutionDecision{models.PolicyAuto, severityRule}
+	default:
+		return ExecutionDecision{models.PolicyAuto, "default"}
+	}
+}
+
+// initialStatus returns the status and message an action starts with under the decision.
+func (d ExecutionDecision) initialStatus(actionType string) (string, string) {
+	switch d.Behaviour {
+	case models.PolicyNotify:
+		if d.Rule == ruleObserveMode {
+			return models.StatusSuggested, fmt.Sprintf("Suggested action: %s (observe mode)", actionType)
+		}
+		return models.StatusSuggested, fmt.Sprintf("Suggested action: %s (policy %s)", actionType, d.Rule)
+	case models.PolicyApprove:
+		return models.StatusPendingApproval, fmt.Sprintf("Action pending approval: %s (policy %s)", actionType, d.Rule)
+	case models.PolicyDeny:
+		return models.StatusRefused, fmt.Sprintf("Action refused: %s (policy %s)", actionType, d.Rule)
+	default:
+		return models.StatusQueued, fmt.Sprintf("Action queued: %s", actionType)
+	}
+}
@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/actions"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+)
+
+// MaintenanceWindow is the daily period in which mutating actions may run, e.g. 02:00-05:00
+// in a given timezone. A window whose end is before its start wraps past midnight.
+type MaintenanceWindow struct {
+	startHour, startMinute int
+	endHour, endMinute     int
+	location               *time.Location
+}
+
+// ParseMaintenanceWindow parses a "HH:MM-HH:MM" window in the named IANA timezone
+// (empty means UTC).
+func ParseMaintenanceWindow(spec, timezone string) (*MaintenanceWindow, error) {
+	startSpec, endSpec, ok := strings.Cut(spec, "-")
+	if !ok {
+		return nil, fmt.Errorf("maintenance window %q must be HH:MM-HH:MM", spec)
+	}
+
+	w := &MaintenanceWindow{location: time.UTC}
+
+	var err error
+	if w.startHour, w.startMinute, err = parseClock(startSpec); err != nil {
+		return nil, fmt.Errorf("maintenance window start: %w", err)
+	}
+	if w.endHour, w.endMinute, err = parseClock(endSpec); err != nil {
+		return nil, fmt.Errorf("maintenance window end: %w", err)
+	}
+	if w.startHour == w.endHour && w.startMinute == w.endMinute {
+		return nil, fmt.Errorf("maintenance window %q is empty", spec)
+	}
+
+	if timezone != "" {
+		if w.location, err = time.LoadLocation(timezone); err != nil {
+			return nil, fmt.Errorf("maintenance window timezone: %w", err)
+		}
+	}
+
+	return w, nil
+}
+
+func parseClock(value string) (int, int, error) {
+	clock, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, 0, fmt.Errorf("%q is not HH:MM", value)
+	}
+	return clock.Hour(), clock.Minute(), nil
+}
+
+func (w *MaintenanceWindow) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d %s", w.startHour, w.startMinute, w.endHour, w.endMinute, w.location)
+}
+
+// Contains reports whether t falls inside the window.
+func (w *MaintenanceWindow) Contains(t time.Time) bool {
+	local := t.In(w.location)
+	minutes := local.Hour()*60 + local.Minute()
+	start := w.startHour*60 + w.startMinute
+	end := w.endHour*60 + w.endMinute
+
+	if start < end {
+		return minutes >= start && minutes < end
+	}
+	return minutes >= start || minutes < end
+}
+
+// NextOpen returns when the window next opens after t, or t itself if it is open.
+func (w *MaintenanceWindow) NextOpen(t time.Time) time.Time {
+	if w.Contains(t) {
+		return t
+	}
+	return w.next(t, w.startHour, w.startMinute)
+}
+
+// NextClose returns when the window next closes after t, or t itself if it is closed.
+func (w *MaintenanceWindow) NextClose(t time.Time) time.Time {
+	if !w.Contains(t) {
+		return t
+	}
+	return w.next(t, w.endHour, w.endMinute)
+}
+
+// next returns the first hour:minute in the window's timezone strictly after t.
+func (w *MaintenanceWindow) next(t time.Time, hour, minute int) time.Time {
+	local := t.In(w.location)
+	candidate := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, w.location)
+	if !candidate.After(t) {
+		candidate = time.Date(local.Year(), local.Month(), local.Day()+1, hour, minute, 0, 0, w.location)
+	}
+	return candidate
+}
+
+// SetMaintenanceWindow limits mutating actions to the window; recommendation-only actions
+// run anytime. Detections with a severity in bypassSeverities (e.g. "critical") run
+// immediately. Actions arriving outside the window are parked as scheduled and released
+// when it opens. A nil window runs actions anytime.
+func (h *DetectionHandler) SetMaintenanceWindow(window *MaintenanceWindow, bypassSeverities []string) {
+	h.maintenanceWindow = window
+	h.maintenanceBypass = make(map[string]bool, len(bypassSeverities))
+	for _, severity := range bypassSeverities {
+		h.maintenanceBypass[strings.ToLower(severity)] = true
+	}
+
+	if window != nil {
+		go h.runMaintenanceScheduler(window)
+	}
+}
+
+// holdForMaintenance parks an action that must wait for the maintenance window and reports
+// whether it did. Actions an operator asked for explicitly are never held, unless the
+// action itself requires the window (e.g. VACUUM FULL).
+func (h *DetectionHandler) holdForMaintenance(q *queuedAction) bool {
+	if h.maintenanceWindow == nil || !isMutating(q.action) {
+		return false
+	}
+
+	windowBound := requiresMaintenanceWindow(q.action)
+	if q.operatorRequested && !windowBound {
+		return false
+	}
+
+	now := time.Now()
+	if h.maintenanceWindow.Contains(now) {
+		return false
+	}
+
+	if !windowBound && q.detection != nil && h.maintenanceBypass[strings.ToLower(q.detection.Severity)] {
+		log.Printf("Action %s bypasses maintenance window (%s severity)", q.action.GetMetadata().ActionID, q.detection.Severity)
+		return false
+	}
+
+	h.scheduleAction(q, h.maintenanceWindow.NextOpen(now))
+	return true
+}
+
+// requiresMaintenanceWindow reports whether an action may only run inside the window.
+func requiresMaintenanceWindow(action actions.Action) bool {
+	bound, ok := action.(actions.WindowBound)
+	return ok && bound.RequiresMaintenanceWindow()
+}
+
+// scheduleAction parks an action until the maintenance window opens and records the
+// planned execution time on its status.
+func (h *DetectionHandler) scheduleAction(q *queuedAction, at time.Time) {
+	actionID := q.action.GetMetadata().ActionID
+	log.Printf("Action %s scheduled for %s (maintenance window %s)", actionID, at.Format(time.RFC3339), h.maintenanceWindow)
+
+	if result, err := h.GetActionStatus(actionID); err == nil {
+		scheduled := *result
+		scheduled.Status = models.StatusScheduled
+		scheduled.ScheduledFor = &at
+		scheduled.QueuePosition = 0
+		scheduled.Message = fmt.Sprintf("Scheduled for %s (maintenance window %s)", at.Format(time.RFC3339), h.maintenanceWindow)
+		h.finishResult(context.Background(), &scheduled)
+	}
+
+	h.queueMu.Lock()
+	if h.closed {
+		h.queueMu.Unlock()
+		return
+	}
+	h.scheduled = append(h.scheduled, q)
+	h.queueMu.Unlock()
+
+	h.refreshQueuePositions()
+
+	// The window may have opened while the action was being parked
+	if h.maintenanceWindow.Contains(time.Now()) {
+		h.releaseScheduled()
+	}
+}
+
+// runMaintenanceScheduler releases parked actions whenever the window opens.
+func (h *DetectionHandler) runMaintenanceScheduler(window *MaintenanceWindow) {
+	for {
+		now := time.Now()
+
+		// While open, nothing new is parked; sleep until the window closes
+		wait := window.NextOpen(now).Sub(now)
+		if window.Contains(now) {
+			h.releaseScheduled()
+			wait = window.NextClose(now).Sub(now)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-h.baseCtx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// releaseScheduled moves every parked action back onto the execution queue.
+func (h *DetectionHandler) releaseScheduled() {
+	h.queueMu.Lock()
+	released := h.scheduled
+	h.scheduled = nil
+	h.queueMu.Unlock()
+
+	if len(released) == 0 {
+		return
+	}
+
+	log.Printf("Maintenance window open - releasing %d scheduled actions", len(released))
+
+	// Mark them queued before a worker can pick them up and report executing
+	for _, q := range released {
+		result, err := h.GetActionStatus(q.action.GetMetadata().ActionID)
+		if err != nil {
+			continue
+		}
+
+		queued := *result
+		queued.Status = models.StatusQueued
+		queued.ScheduledFor = nil
+		queued.Message = fmt.Sprintf("Action queued: %s (maintenance window open)", result.ActionType)
+		h.finishResult(context.Background(), &queued)
+	}
+
+	h.queueMu.Lock()
+	if h.closed {
+		h.queueMu.Unlock()
+		return
+	}
+	// Time parked for the window doesn't count towards priority aging
+	now := time.Now()
+	for _, q := range released {
+		q.enqueuedAt = now
+	}
+	h.queue = append(h.queue, released...)
+	h.queueCond.Broadcast()
+	h.queueMu.Unlock()
+
+	h.refreshQueuePositions()
+}
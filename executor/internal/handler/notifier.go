@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+)
+
+// Notifier is told about new detections and finished actions, e.g. to post them to Slack.
+// It decides itself which are worth a message and must not block.
+type Notifier interface {
+	DetectionRaised(detection *models.Detection)
+	// ActionFinished receives completed, failed, regressed and rolled back actions, and
+	// actions the execution policy only suggests. detection is nil when it is no longer known (e.g. for
+	// rollbacks).
+	ActionFinished(result *models.ActionResult, detection *models.Detection)
+}
+
+// SetNotifier sends detection and action notifications to n.
+func (h *DetectionHandler) SetNotifier(n Notifier) {
+	h.notifier = n
+}
+
+func (h *DetectionHandler) notifyDetection(detection *models.Detection) {
+	if h.notifier != nil {
+		h.notifier.DetectionRaised(detection)
+	}
+}
+
+func (h *DetectionHandler) notifyAction(result *models.ActionResult, detection *models.Detection) {
+	if h.notifier != nil {
+		h.notifier.ActionFinished(result, detection)
+	}
+}
@@ -0,0 +1,357 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/actions"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+)
+
+// ErrInsufficientRollbackState is returned when the changes recorded for an action
+// do not identify the artifact a rollback would have to remove.
+var ErrInsufficientRollbackState = errors.New("recorded changes insufficient to reconstruct action for rollback")
+
+// persistedActionState is stored in Knowledge when an action is registered,
+// so the action can be rebuilt after an Executor restart.
+type persistedActionState struct {
+	DetectionID    string                 `json:"detection_id"`
+	DetectionKey   string                 `json:"detection_key,omitempty"` // Names the issue, so re-published detections match
+	ActionType     string                 `json:"action_type"`
+	DatabaseID     string                 `json:"database_id"`
+	Severity       string                 `json:"severity,omitempty"` // Queue priority when a scheduled action is restored
+	ActionMetadata map[string]interface{} `json:"action_metadata"`
+}
+
+// Reconcile restores handler state from Knowledge after a restart:
+//   - queued/executing actions were abandoned by the previous process and are marked failed
+//   - pending_approval actions are rebuilt so they can still be approved
+//   - scheduled actions are rebuilt and queued, waiting for the maintenance window again
+//   - completed (or regressed) rollback-capable actions are rebuilt if their artifacts still exist
+func (h *DetectionHandler) Reconcile(ctx context.Context) error {
+	if h.knowledgeClient == nil {
+		return fmt.Errorf("knowledge client not available")
+	}
+
+	abandoned, err := h.knowledgeClient.ListActionsByStatus(ctx, models.StatusQueued, models.StatusExecuting)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range abandoned {
+		// Actions queued by this process since startup are not abandoned
+		if _, err := h.GetActionStatus(record.Id); err == nil {
+			continue
+		}
+
+		result := resultFromRecord(record)
+		result.Status = models.StatusFailed
+		result.Message = "Action abandoned by Executor restart"
+		result.Error = fmt.Sprintf("executor restarted while action was %s", record.Status)
+		result.ErrorCode = models.ErrorCodeCancelled
+		h.finishResult(ctx, result)
+
+		log.Printf("Reconcile: marked abandoned action %s (%s) as failed", record.Id, record.ActionType)
+	}
+
+	pending, err := h.knowledgeClient.ListActionsByStatus(ctx, models.StatusPendingApproval)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range pending {
+		action, _, err := h.rebuildAction(record)
+		if err != nil {
+			slog.Warn("Reconcile: cannot rebuild pending action", "action_id", record.Id, "error", err)
+			continue
+		}
+
+		h.storeActionObject(record.Id, action)
+		h.storeAction(resultFromRecord(record))
+		log.Printf("Reconcile: restored pending approval action %s (%s)", record.Id, record.ActionType)
+	}
+
+	scheduled, err := h.knowledgeClient.ListActionsByStatus(ctx, models.StatusScheduled)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range scheduled {
+		action, detection, err := h.rebuildAction(record)
+		if err != nil {
+			slog.Warn("Reconcile: cannot rebuild scheduled action", "action_id", record.Id, "error", err)
+			continue
+		}
+
+		h.storeActionObject(record.Id, action)
+		h.storeAction(resultFromRecord(record))
+
+		// The worker parks it again if the window is still closed
+		if err := h.enqueueAction(action, detection); err != nil {
+			slog.Warn("Reconcile: cannot queue scheduled action", "action_id", record.Id, "error", err)
+			continue
+		}
+		log.Printf("Reconcile: restored scheduled action %s (%s)", record.Id, record.ActionType)
+	}
+
+	// Regressed actions are restored too, so they can still be rolled back
+	completed, err := h.knowledgeClient.ListActionsByStatus(ctx, models.StatusCompleted, models.StatusRegressed)
+	if err != nil {
+		return err
+	}
+
+	restored := 0
+	for _, record := range completed {
+		if !record.CanRollback {
+			continue
+		}
+
+		if ok, err := h.restoreCompletedAction(ctx, record); err != nil {
+			slog.Warn("Reconcile: cannot restore action", "action_id", record.Id, "error", err)
+		} else if ok {
+			restored++
+		}
+	}
+
+	log.Printf("Reconcile complete: %d abandoned, %d pending approval, %d scheduled, %d rollback-capable restored",
+		len(abandoned), len(pending), len(scheduled), restored)
+
+	return nil
+}
+
+// restoreCompletedAction rebuilds a completed action and re-attaches it to its artifact.
+func (h *DetectionHandler) restoreCompletedAction(ctx context.Context, record *pb.Action) (bool, error) {
+	action, _, err := h.rebuildAction(record)
+	if err != nil {
+		return false, err
+	}
+
+	restorable, ok := action.(actions.Restorable)
+	if !ok {
+		return false, fmt.Errorf("action type %s cannot be restored", record.ActionType)
+	}
+
+	changes := make(map[string]interface{})
+	if record.Changes != "" {
+		if err := json.Unmarshal([]byte(record.Changes), &changes); err != nil {
+			return false, fmt.Errorf("failed to decode recorded changes: %w", err)
+		}
+	}
+
+	exists, err := restorable.Restore(ctx, changes)
+	if err != nil {
+		return false, err
+	}
+
+	if !exists {
+		slog.Warn("Reconcile: artifact no longer exists, rollback unavailable", "action_id", record.Id)
+		return false, nil
+	}
+
+	result := resultFromRecord(record)
+	result.Changes = changes
+	result.CanRollback = true
+
+	h.storeActionObject(record.Id, action)
+	h.storeAction(result)
+
+	log.Printf("Reconcile: restored rollback-capable action %s (%s)", record.Id, record.ActionType)
+	return true, nil
+}
+
+// rebuildAction recreates an action object, and the detection it came from, from the
+// state persisted at registration.
+func (h *DetectionHandler) rebuildAction(record *pb.Action) (actions.Action, *models.Detection, error) {
+	if record.ActionState == "" {
+		return nil, nil, fmt.Errorf("no persisted state for action %s", record.Id)
+	}
+
+	var state persistedActionState
+	if err := json.Unmarshal([]byte(record.ActionState), &state); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode persisted state: %w", err)
+	}
+
+	detection := &models.Detection{
+		DetectionID:    state.DetectionID,
+		ActionType:     state.ActionType,
+		DatabaseID:     state.DatabaseID,
+		Severity:       state.Severity,
+		ActionMetaData: state.ActionMetadata,
+	}
+	if detection.ActionMetaData == nil {
+		detection.ActionMetaData = map[string]interface{}{}
+	}
+
+	action, err := h.createAction(detection, record.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return action, detection, nil
+}
+
+// resultFromRecord converts a Knowledge action record into an ActionResult.
+func resultFromRecord(record *pb.Action) *models.ActionResult {
+	result := &models.ActionResult{
+		ActionID:    record.Id,
+		DetectionID: record.DetectionId,
+		ActionType:  record.ActionType,
+		DatabaseID:  record.DatabaseId,
+		Status:      record.Status,
+		Message:     record.Message,
+		Error:       record.Error,
+		ErrorCode:   models.ErrorCode(record.ErrorCode),
+		CreatedAt:   time.Unix(record.CreatedAt, 0),
+		CanRollback: record.CanRollback,
+
+		PolicyDecision: record.PolicyDecision,
+		PolicyRule:     record.PolicyRule,
+	}
+
+	if record.CompletedAt > 0 {
+		completed := time.Unix(record.CompletedAt, 0)
+		result.Completed = &completed
+	}
+
+	return result
+}
+
+// loadActionFromKnowledge fetches an action this process has no record of.
+func (h *DetectionHandler) loadActionFromKnowledge(ctx context.Context, actionID string) (*models.ActionResult, error) {
+	if h.knowledgeClient == nil {
+		return nil, fmt.Errorf("%w: %s", ErrActionNotFound, actionID)
+	}
+
+	record, found, err := h.knowledgeClient.GetAction(ctx, actionID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrActionNotFound, actionID)
+	}
+
+	return resultWithChanges(record)
+}
+
+// resultWithChanges converts a Knowledge action record into an ActionResult, including the
+// changes it recorded on completion.
+func resultWithChanges(record *pb.Action) (*models.ActionResult, error) {
+	result := resultFromRecord(record)
+	if record.Changes != "" {
+		changes := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(record.Changes), &changes); err != nil {
+			return nil, fmt.Errorf("failed to decode recorded changes: %w", err)
+		}
+		result.Changes = changes
+	}
+
+	return result, nil
+}
+
+// reconstructForRollback rebuilds a rollback-capable action from the changes it
+// recorded on completion, for actions created before the current process started.
+func (h *DetectionHandler) reconstructForRollback(ctx context.Context, result *models.ActionResult) (actions.Action, error) {
+	detection := &models.Detection{
+		DetectionID:    result.DetectionID,
+		ActionType:     result.ActionType,
+		DatabaseID:     result.DatabaseID,
+		ActionMetaData: map[string]interface{}{},
+	}
+
+	// A container-backed action would otherwise come back as manual steps, which can't be rolled back
+	if unavailable := h.dockerUnavailableFor(result.ActionType); unavailable != nil {
+		return nil, fmt.Errorf("%s needs Docker, which is unavailable: %w", result.ActionType, unavailable)
+	}
+
+	switch result.ActionType {
+	case "create_index":
+		indexName, _ := result.Changes["index_name"].(string)
+		tableName, _ := result.Changes["table_name"].(string)
+		if indexName == "" || tableName == "" {
+			return nil, fmt.Errorf("%w: create_index requires index_name and table_name", ErrInsufficientRollbackState)
+		}
+
+		detection.ActionMetaData["table_name"] = tableName
+		detection.ActionMetaData["column_names"] = columnNames(result.Changes["column_names"])
+
+	case "drop_index":
+		indexName, _ := result.Changes["index_name"].(string)
+		if indexName == "" {
+			return nil, fmt.Errorf("%w: drop_index requires index_name", ErrInsufficientRollbackState)
+		}
+
+		detection.ActionMetaData["index_name"] = indexName
+		detection.ActionMetaData["table_name"] = result.Changes["table_name"]
+
+	case "vacuum_table":
+		tableName, _ := result.Changes["table_name"].(string)
+		if mode, _ := result.Changes["mode"].(string); mode != actions.VacuumModeTuneAutovacuum || tableName == "" {
+			return nil, fmt.Errorf("%w: vacuum_table requires table_name and mode %s", ErrInsufficientRollbackState, actions.VacuumModeTuneAutovacuum)
+		}
+
+		detection.ActionMetaData["table_name"] = tableName
+		detection.ActionMetaData["mode"] = actions.VacuumModeTuneAutovacuum
+
+	case "increase_cache_size":
+		if original, _ := result.Changes["original_shared_buffers"].(string); original == "" {
+			return nil, fmt.Errorf("%w: increase_cache_size requires original_shared_buffers", ErrInsufficientRollbackState)
+		}
+
+	case "deploy_connection_pooler", "deploy_pgbouncer", "deploy_redis":
+		if name, _ := result.Changes["container_name"].(string); name == "" {
+			return nil, fmt.Errorf("%w: %s requires container_name", ErrInsufficientRollbackState, result.ActionType)
+		}
+
+	default:
+		return nil, fmt.Errorf("%w: action type %s cannot be reconstructed", ErrInsufficientRollbackState, result.ActionType)
+	}
+
+	if detection.ActionType == "deploy_pgbouncer" {
+		detection.ActionType = "deploy_connection_pooler"
+	}
+
+	action, err := h.createAction(detection, result.ActionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconstruct action: %w", err)
+	}
+
+	restorable, ok := action.(actions.Restorable)
+	if !ok {
+		return nil, fmt.Errorf("%w: action type %s cannot be restored", ErrInsufficientRollbackState, result.ActionType)
+	}
+
+	exists, err := restorable.Restore(ctx, result.Changes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconstruct action: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("nothing to roll back: artifact for action %s no longer exists", result.ActionID)
+	}
+
+	log.Printf("Reconstructed action %s (%s) from recorded changes", result.ActionID, result.ActionType)
+	return action, nil
+}
+
+// columnNames converts a column list from action metadata or recorded changes, which
+// decode from JSON as []interface{}.
+func columnNames(value interface{}) []string {
+	switch cols := value.(type) {
+	case []string:
+		return cols
+	case []interface{}:
+		names := make([]string, 0, len(cols))
+		for _, col := range cols {
+			if name, ok := col.(string); ok && name != "" {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/actions"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/metrics"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/logging"
+)
+
+// maxRetryBackoff caps the wait between attempts however many there are.
+const maxRetryBackoff = 10 * time.Minute
+
+// RetryPolicy bounds how actions failing with a transient error code are retried.
+type RetryPolicy struct {
+	MaxAttempts int           // executions in total, the first one included; 1 never retries
+	Backoff     time.Duration // wait before the first retry, doubled for each one after
+}
+
+// DefaultRetryPolicy tries an action three times, 30s and then 1m apart.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 3, Backoff: 30 * time.Second}
+}
+
+// backoff returns the wait before retry number retry (1 for the first retry).
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	wait := p.Backoff
+	for i := 1; i < retry && wait < maxRetryBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, maxRetryBackoff)
+}
+
+// SetRetryPolicy changes how failed actions are retried. Actions already waiting for a
+// retry keep their backoff.
+func (h *DetectionHandler) SetRetryPolicy(policy RetryPolicy) {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+
+	h.settingsMu.Lock()
+	defer h.settingsMu.Unlock()
+
+	h.retryPolicy = policy
+}
+
+func (h *DetectionHandler) currentRetryPolicy() RetryPolicy {
+	h.settingsMu.RLock()
+	defer h.settingsMu.RUnlock()
+
+	return h.retryPolicy
+}
+
+// retryFailed puts an action whose execution failed with a transient error code back in
+// the queue after a backoff, and reports whether it did. Permanent failures, the last
+// attempt, failures during shutdown and actions whose partial work cannot be undone are
+// left to fail.
+func (h *DetectionHandler) retryFailed(q *queuedAction, result *models.ActionResult) bool {
+	policy := h.currentRetryPolicy()
+	if !result.ErrorCode.Transient() || q.attempt+1 >= policy.MaxAttempts || h.isClosed() {
+		return false
+	}
+
+	if err := h.prepareRetry(q.action); err != nil {
+		logging.With(result.CorrelationID).Warn("Action failed and will not be retried", "action_id", result.ActionID, "error_code", result.ErrorCode, "error", err)
+		result.Error = fmt.Sprintf("%s; not retried: %v", result.Error, err)
+		return false
+	}
+
+	q.attempt++
+	wait := policy.backoff(q.attempt)
+
+	// The failed attempt's error stays on the action while it waits
+	retrying := *result
+	retrying.Status = models.StatusQueued
+	retrying.Completed = nil
+	h.storeAction(&retrying)
+
+	metrics.ActionRetries.WithLabelValues(result.ActionType, string(result.ErrorCode)).Inc()
+	logging.With(result.CorrelationID).Warn("Action failed, retrying",
+		"action_id", result.ActionID, "error_code", result.ErrorCode, "attempt", q.attempt,
+		"max_attempts", policy.MaxAttempts, "retry_in", wait, "error", result.Error)
+
+	h.deferAction(q, wait, fmt.Sprintf("attempt %d of %d after %s", q.attempt+1, policy.MaxAttempts, result.ErrorCode))
+	return true
+}
+
+// prepareRetry undoes what a failed attempt left behind, for actions that can leave any.
+// It gets a fresh deadline, since running out of time may be why the attempt failed.
+func (h *DetectionHandler) prepareRetry(action actions.Action) error {
+	preparer, ok := action.(actions.RetryPreparer)
+	if !ok {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ActionTimeout())
+	defer cancel()
+
+	return preparer.PrepareRetry(ctx)
+}
@@ -2,36 +2,118 @@ package http
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/actions"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/config"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/handler"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
 )
 
 type Server struct {
 	detectionHandler *handler.DetectionHandler
-	httpServer       *http.Server // Store server instance for graceful shutdown
+	thresholds       ThresholdStore   // nil when Knowledge is unavailable
+	suppressions     SuppressionStore // nil when Knowledge is unavailable
+	policies         PolicyStore      // nil when Knowledge is unavailable
+	configReloader   ConfigReloader   // nil disables /api/config/reload
+	httpServer       *http.Server     // Store server instance for graceful shutdown
+	apiToken         string           // Bearer token required on every request; empty disables the check
+	draining         atomic.Bool      // set during shutdown; only reads are served
 }
 
-func NewServer(dh *handler.DetectionHandler) *Server {
+// ThresholdStore reads and writes the detection threshold overrides held by Knowledge.
+type ThresholdStore interface {
+	GetThresholds(ctx context.Context, databaseID string) ([]*pb.ThresholdSet, error)
+	SetThresholds(ctx context.Context, databaseID string, values map[string]float64) error
+}
+
+// SuppressionStore creates and lists the detection suppressions held by Knowledge.
+type SuppressionStore interface {
+	SuppressDetectionKey(ctx context.Context, key string, duration time.Duration, reason string) error
+	ListSuppressions(ctx context.Context) ([]*pb.Suppression, error)
+}
+
+// PolicyStore reads and writes the per-database policies held by Knowledge.
+type PolicyStore interface {
+	GetDatabasePolicy(ctx context.Context, databaseID string) (*pb.DatabasePolicy, error)
+	ListDatabasePolicies(ctx context.Context) ([]*pb.DatabasePolicy, error)
+	SetDatabasePolicy(ctx context.Context, policy *pb.DatabasePolicy) error
+}
+
+// ConfigReloader re-reads the Executor's configuration and applies what it can at runtime.
+type ConfigReloader interface {
+	ReloadConfig() (*config.ReloadResult, error)
+}
+
+func NewServer(dh *handler.DetectionHandler, apiToken string) *Server {
 	return &Server{
 		detectionHandler: dh,
+		apiToken:         apiToken,
 	}
 }
 
+// SetThresholdStore enables the /api/thresholds endpoints.
+func (s *Server) SetThresholdStore(store ThresholdStore) {
+	s.thresholds = store
+}
+
+// SetSuppressionStore enables the /api/suppressions endpoints.
+func (s *Server) SetSuppressionStore(store SuppressionStore) {
+	s.suppressions = store
+}
+
+// SetPolicyStore enables the /api/policies endpoints.
+func (s *Server) SetPolicyStore(store PolicyStore) {
+	s.policies = store
+}
+
+// SetConfigReloader enables the /api/config/reload endpoint.
+func (s *Server) SetConfigReloader(reloader ConfigReloader) {
+	s.configReloader = reloader
+}
+
 func (s *Server) Start(addr string) error {
+	// Store server instance for graceful shutdown
+	s.httpServer = &http.Server{
+		Addr:    addr,
+		Handler: s.Handler(),
+	}
+
+	log.Printf("HTTP Server listening on: %s", addr)
+	return s.httpServer.ListenAndServe()
+}
+
+// Handler returns the API routes wrapped in CORS and token checks.
+func (s *Server) Handler() http.Handler {
 	mux := http.NewServeMux()
 
-	// Rollback endpoint
+	// Action list, filtered by status and database and paged
+	mux.HandleFunc("/api/actions", func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("Received actions request: %s %s", r.Method, r.URL.Path)
+		s.handleListActions(w, r)
+	})
+
+	// Action endpoints: GET /api/actions/{id}, /changes; POST /rollback[?force=true], /approve, /reject
 	mux.HandleFunc("/api/actions/", func(w http.ResponseWriter, r *http.Request) {
 		log.Printf("Received request: %s %s", r.Method, r.URL.Path)
-		s.handleRollback(w, r)
+		s.handleActionRequest(w, r)
+	})
+
+	// Queued actions in the order the workers will pick them up
+	mux.HandleFunc("/api/actions/queue", func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("Received queue request: %s %s", r.Method, r.URL.Path)
+		s.handleActionQueue(w, r)
 	})
 
 	// Deploy Redis endpoint
@@ -40,14 +122,51 @@ func (s *Server) Start(addr string) error {
 		s.handleDeployRedis(w, r)
 	})
 
-	// Store server instance for graceful shutdown
-	s.httpServer = &http.Server{
-		Addr:    addr,
-		Handler: s.enableCORS(mux),
-	}
+	// Detection threshold overrides, read by the Analyser from Knowledge
+	mux.HandleFunc("/api/thresholds", func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("Received thresholds request: %s %s", r.Method, r.URL.Path)
+		s.handleThresholds(w, r)
+	})
 
-	log.Printf("HTTP Server listening on: %s", addr)
-	return s.httpServer.ListenAndServe()
+	// Detection suppressions, consulted by the Analyser before publishing
+	mux.HandleFunc("/api/suppressions", func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("Received suppressions request: %s %s", r.Method, r.URL.Path)
+		s.handleSuppressions(w, r)
+	})
+
+	// Per-database policies: allowed and denied action types, ignored detectors
+	mux.HandleFunc("/api/policies", func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("Received policies request: %s %s", r.Method, r.URL.Path)
+		s.handlePolicies(w, r)
+	})
+
+	// Re-read configuration without restarting (same as SIGHUP)
+	mux.HandleFunc("/api/config/reload", func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("Received config reload request: %s %s", r.Method, r.URL.Path)
+		s.handleConfigReload(w, r)
+	})
+
+	return s.enableCORS(s.requireToken(s.rejectChangesWhileDraining(mux)))
+}
+
+// StopAcceptingChanges makes the API refuse requests that change state, while reads keep
+// working so the Dashboard can watch running actions finish during shutdown.
+func (s *Server) StopAcceptingChanges() {
+	s.draining.Store(true)
+}
+
+// rejectChangesWhileDraining answers anything but GET with 503 once StopAcceptingChanges
+// has been called.
+func (s *Server) rejectChangesWhileDraining(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.draining.Load() && r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Retry-After", "30")
+			http.Error(w, "Executor is shutting down", http.StatusServiceUnavailable)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
 }
 
 // Stop gracefully shuts down the HTTP server with a timeout.
@@ -70,22 +189,192 @@ func (s *Server) Stop() error {
 	return nil
 }
 
-func (s *Server) handleRollback(w http.ResponseWriter, r *http.Request) {
+// Page sizes for GET /api/actions
+const (
+	defaultActionPageSize = 50
+	maxActionPageSize     = 500
+)
+
+// ActionList is one page of actions as returned by GET /api/actions.
+type ActionList struct {
+	Actions    []*models.ActionResult `json:"actions"`               // Queued actions in execution order, then newest first
+	TotalCount int                    `json:"total_count"`           // Actions matching the filters across every page
+	NextCursor string                 `json:"next_cursor,omitempty"` // Passed as cursor for the next page; empty on the last
+}
+
+// ActionChanges is what an action changed, as returned by GET /api/actions/{id}/changes.
+type ActionChanges struct {
+	ActionID    string                 `json:"action_id"`
+	ActionType  string                 `json:"action_type"`
+	Status      string                 `json:"status"`
+	CanRollback bool                   `json:"can_rollback"`
+	Changes     map[string]interface{} `json:"changes"` // Empty, never null, for actions that changed nothing
+}
+
+// handleListActions serves GET /api/actions[?status=&database_id=&cursor=&limit=]. Actions
+// from earlier Executor processes are read from Knowledge. The cursor is the offset of the
+// next page.
+func (s *Server) handleListActions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.detectionHandler == nil {
+		http.Error(w, "Executor not ready", http.StatusServiceUnavailable)
+		return
+	}
+
+	query := r.URL.Query()
+
+	limit := defaultActionPageSize
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > maxActionPageSize {
+			http.Error(w, fmt.Sprintf("limit must be between 1 and %d", maxActionPageSize), http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	start := 0
+	if cursor := query.Get("cursor"); cursor != "" {
+		offset, err := strconv.Atoi(cursor)
+		if err != nil || offset < 0 {
+			http.Error(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+		start = offset
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	results, err := s.detectionHandler.ListActions(ctx, handler.ActionFilter{
+		Status:     query.Get("status"),
+		DatabaseID: query.Get("database_id"),
+	})
+	if err != nil {
+		slog.Error("Failed to list actions", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	end := len(results)
+	if start > end {
+		start = end
+	}
+	if start+limit < end {
+		end = start + limit
+	}
+
+	response := ActionList{
+		Actions:    append([]*models.ActionResult{}, results[start:end]...),
+		TotalCount: len(results),
+	}
+	if end < len(results) {
+		response.NextCursor = strconv.Itoa(end)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleActionDetail serves GET /api/actions/{id} with the full action, and
+// GET /api/actions/{id}/changes with only what it changed.
+func (s *Server) handleActionDetail(w http.ResponseWriter, r *http.Request, actionID string, changesOnly bool) {
+	if s.detectionHandler == nil {
+		http.Error(w, "Executor not ready", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	result, err := s.detectionHandler.GetAction(ctx, actionID)
+	if errors.Is(err, handler.ErrActionNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to get action", "action_id", actionID, "error", err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !changesOnly {
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	changes := result.Changes
+	if changes == nil {
+		changes = map[string]interface{}{}
+	}
+	json.NewEncoder(w).Encode(ActionChanges{
+		ActionID:    result.ActionID,
+		ActionType:  result.ActionType,
+		Status:      result.Status,
+		CanRollback: result.CanRollback,
+		Changes:     changes,
+	})
+}
+
+// handleActionRequest routes GET /api/actions/{id}[/changes] to handleActionDetail and
+// POST /api/actions/{id}/{operation} to the detection handler.
+func (s *Server) handleActionRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		parts := strings.Split(strings.TrimSuffix(r.URL.Path, "/"), "/")
+		switch {
+		case len(parts) == 4 && parts[3] != "":
+			s.handleActionDetail(w, r, parts[3], false)
+		case len(parts) == 5 && parts[3] != "" && parts[4] == "changes":
+			s.handleActionDetail(w, r, parts[3], true)
+		case len(parts) == 5 && (parts[4] == "rollback" || parts[4] == "approve" || parts[4] == "reject"):
+			http.Error(w, "Method not supported", http.StatusMethodNotAllowed)
+		default:
+			http.NotFound(w, r)
+		}
+		return
+	}
+
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not supported", http.StatusMethodNotAllowed)
 		return
 	}
 
 	parts := strings.Split(r.URL.Path, "/")
-	if len(parts) < 5 || parts[4] != "rollback" {
+	if len(parts) < 5 || parts[3] == "" {
 		http.Error(w, "Invalid path", http.StatusBadRequest)
 		return
 	}
 	actionID := parts[3]
 
-	log.Printf("Rollback request on action: %s", actionID)
+	var operation func(string) (*models.ActionResult, error)
+	switch parts[4] {
+	case "rollback":
+		operation = s.detectionHandler.RollbackAction
+		if r.URL.Query().Get("force") == "true" {
+			operation = s.detectionHandler.ForceRollbackAction
+		}
+	case "approve":
+		operation = s.detectionHandler.ApproveAction
+	case "reject":
+		operation = s.detectionHandler.RejectAction
+	default:
+		http.Error(w, "Invalid path", http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("%s request on action: %s", parts[4], actionID)
 
-	result, err := s.detectionHandler.RollbackAction(actionID)
+	result, err := operation(actionID)
+	if errors.Is(err, actions.ErrRollbackUnsafe) {
+		// The caller can retry with force=true once they've read the reason
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -95,6 +384,22 @@ func (s *Server) handleRollback(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(result)
 }
 
+// handleActionQueue serves GET /api/actions/queue with queued actions and their priorities.
+func (s *Server) handleActionQueue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.detectionHandler == nil {
+		http.Error(w, "Executor not ready", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"queue": s.detectionHandler.QueuedActions()})
+}
+
 // DeployRedisRequest represents the JSON payload for Redis deployment
 type DeployRedisRequest struct {
 	DatabaseID     string `json:"database_id"`
@@ -112,7 +417,7 @@ func (s *Server) handleDeployRedis(w http.ResponseWriter, r *http.Request) {
 	// Parse request body
 	var req DeployRedisRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Printf("Failed to parse deploy request: %v", err)
+		slog.Error("Failed to parse deploy request", "error", err)
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
@@ -122,6 +427,11 @@ func (s *Server) handleDeployRedis(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if s.detectionHandler == nil {
+		http.Error(w, "Executor not ready", http.StatusServiceUnavailable)
+		return
+	}
+
 	log.Printf("Deploy Redis request for database: %s", req.DatabaseID)
 
 	// Generate action ID
@@ -135,18 +445,21 @@ func (s *Server) handleDeployRedis(w http.ResponseWriter, r *http.Request) {
 		"eviction_policy": req.EvictionPolicy,
 	}
 
-	// Create DeployRedisAction
-	action, err := actions.NewDeployRedisAction(
+	// A deployment the user asked for is refused outright rather than turned into manual steps
+	runtime, err := s.detectionHandler.ContainerRuntime()
+	if err != nil {
+		slog.Error("Failed to create Redis action", "error", err)
+		http.Error(w, fmt.Sprintf("Docker unavailable: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	action := actions.NewDeployRedisActionWithRuntime(
 		actionID,
 		detectionID,
 		req.DatabaseID,
+		runtime,
 		params,
 	)
-	if err != nil {
-		log.Printf("Failed to create Redis action: %v", err)
-		http.Error(w, fmt.Sprintf("Failed to create action: %v", err), http.StatusInternalServerError)
-		return
-	}
 
 	// Create detection for logging/tracking
 	detection := &models.Detection{
@@ -186,11 +499,323 @@ func (s *Server) handleDeployRedis(w http.ResponseWriter, r *http.Request) {
 	log.Printf("Redis deployment queued: action_id=%s, database_id=%s", actionID, req.DatabaseID)
 }
 
+// ThresholdsRequest replaces the threshold overrides for one database, or the global
+// default when DatabaseID is empty. Empty values clear the overrides.
+type ThresholdsRequest struct {
+	DatabaseID string             `json:"database_id"`
+	Values     map[string]float64 `json:"values"`
+}
+
+// ThresholdSet is one scope's overrides as returned by GET /api/thresholds.
+type ThresholdSet struct {
+	DatabaseID string             `json:"database_id"`
+	Values     map[string]float64 `json:"values"`
+	UpdatedAt  int64              `json:"updated_at"`
+}
+
+// handleThresholds serves GET /api/thresholds[?database_id=] and PUT /api/thresholds.
+// The Analyser picks up changes on its next refresh.
+func (s *Server) handleThresholds(w http.ResponseWriter, r *http.Request) {
+	if s.thresholds == nil {
+		http.Error(w, "Knowledge service unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	switch r.Method {
+	case http.MethodGet:
+		sets, err := s.thresholds.GetThresholds(ctx, r.URL.Query().Get("database_id"))
+		if err != nil {
+			slog.Error("Failed to get thresholds", "error", err)
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		response := make([]ThresholdSet, 0, len(sets))
+		for _, set := range sets {
+			response = append(response, ThresholdSet{
+				DatabaseID: set.DatabaseId,
+				Values:     set.Values,
+				UpdatedAt:  set.UpdatedAt,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"sets": response})
+
+	case http.MethodPut:
+		var req ThresholdsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if err := s.thresholds.SetThresholds(ctx, req.DatabaseID, req.Values); err != nil {
+			slog.Error("Failed to set thresholds", "error", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		log.Printf("Thresholds updated for database %q (%d overrides)", req.DatabaseID, len(req.Values))
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":     true,
+			"database_id": req.DatabaseID,
+		})
+
+	default:
+		http.Error(w, "Method not supported", http.StatusMethodNotAllowed)
+	}
+}
+
+// DatabasePolicy is one database's policy as sent to and returned by /api/policies.
+// PUT replaces the whole policy; empty lists clear it.
+type DatabasePolicy struct {
+	DatabaseID         string   `json:"database_id"`
+	AllowedActionTypes []string `json:"allowed_action_types"`
+	DeniedActionTypes  []string `json:"denied_action_types"`
+	IgnoredDetectors   []string `json:"ignored_detectors"`
+	Reason             string   `json:"reason,omitempty"`
+	UpdatedAt          int64    `json:"updated_at,omitempty"`
+}
+
+func toDatabasePolicy(p *pb.DatabasePolicy) DatabasePolicy {
+	return DatabasePolicy{
+		DatabaseID:         p.DatabaseId,
+		AllowedActionTypes: nonNil(p.AllowedActionTypes),
+		DeniedActionTypes:  nonNil(p.DeniedActionTypes),
+		IgnoredDetectors:   nonNil(p.IgnoredDetectors),
+		Reason:             p.Reason,
+		UpdatedAt:          p.UpdatedAt,
+	}
+}
+
+// nonNil keeps empty lists as [] rather than null in responses.
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// handlePolicies serves GET /api/policies[?database_id=] and PUT /api/policies. The
+// Analyser and other Executors pick up changes when their cached copy expires; this
+// Executor applies them to the next action.
+func (s *Server) handlePolicies(w http.ResponseWriter, r *http.Request) {
+	if s.policies == nil {
+		http.Error(w, "Knowledge service unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	switch r.Method {
+	case http.MethodGet:
+		if databaseID := r.URL.Query().Get("database_id"); databaseID != "" {
+			p, err := s.policies.GetDatabasePolicy(ctx, databaseID)
+			if err != nil {
+				slog.Error("Failed to get policy", "error", err)
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(toDatabasePolicy(p))
+			return
+		}
+
+		policies, err := s.policies.ListDatabasePolicies(ctx)
+		if err != nil {
+			slog.Error("Failed to list policies", "error", err)
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		response := make([]DatabasePolicy, 0, len(policies))
+		for _, p := range policies {
+			response = append(response, toDatabasePolicy(p))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"policies": response})
+
+	case http.MethodPut:
+		var req DatabasePolicy
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if err := s.policies.SetDatabasePolicy(ctx, &pb.DatabasePolicy{
+			DatabaseId:         req.DatabaseID,
+			AllowedActionTypes: req.AllowedActionTypes,
+			DeniedActionTypes:  req.DeniedActionTypes,
+			IgnoredDetectors:   req.IgnoredDetectors,
+			Reason:             req.Reason,
+		}); err != nil {
+			slog.Error("Failed to set policy", "error", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if s.detectionHandler != nil {
+			s.detectionHandler.InvalidateDatabasePolicy(req.DatabaseID)
+		}
+
+		log.Printf("Policy updated for database %q (allowed: %v, denied: %v, ignored detectors: %v)",
+			req.DatabaseID, req.AllowedActionTypes, req.DeniedActionTypes, req.IgnoredDetectors)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":     true,
+			"database_id": req.DatabaseID,
+		})
+
+	default:
+		http.Error(w, "Method not supported", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleConfigReload serves POST /api/config/reload. The response lists the settings that
+// were applied and those rejected because they need a restart.
+func (s *Server) handleConfigReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.configReloader == nil {
+		http.Error(w, "Config reload unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	result, err := s.configReloader.ReloadConfig()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":  true,
+		"applied":  result.Applied,
+		"rejected": result.Rejected,
+	})
+}
+
+// SuppressionRequest snoozes a detection key for DurationSecs. The reason is required so
+// operators remember why the detection was accepted.
+type SuppressionRequest struct {
+	Key          string `json:"key"`
+	DurationSecs int64  `json:"duration_secs"`
+	Reason       string `json:"reason"`
+}
+
+// Suppression is one active suppression as returned by GET /api/suppressions.
+type Suppression struct {
+	Key       string `json:"key"`
+	Reason    string `json:"reason"`
+	CreatedAt int64  `json:"created_at"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// handleSuppressions serves GET /api/suppressions, POST /api/suppressions and
+// DELETE /api/suppressions?key=, which lifts a suppression before it expires.
+func (s *Server) handleSuppressions(w http.ResponseWriter, r *http.Request) {
+	if s.suppressions == nil {
+		http.Error(w, "Knowledge service unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	switch r.Method {
+	case http.MethodGet:
+		suppressions, err := s.suppressions.ListSuppressions(ctx)
+		if err != nil {
+			slog.Error("Failed to list suppressions", "error", err)
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		response := make([]Suppression, 0, len(suppressions))
+		for _, suppression := range suppressions {
+			response = append(response, Suppression{
+				Key:       suppression.Key,
+				Reason:    suppression.Reason,
+				CreatedAt: suppression.CreatedAt,
+				ExpiresAt: suppression.ExpiresAt,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"suppressions": response})
+
+	case http.MethodPost:
+		var req SuppressionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if req.Key == "" || req.DurationSecs <= 0 {
+			http.Error(w, "key and a positive duration_secs are required", http.StatusBadRequest)
+			return
+		}
+
+		duration := time.Duration(req.DurationSecs) * time.Second
+		if err := s.suppressions.SuppressDetectionKey(ctx, req.Key, duration, req.Reason); err != nil {
+			slog.Error("Failed to suppress detection key", "error", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		log.Printf("Detection key %s suppressed for %s: %s", req.Key, duration, req.Reason)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":    true,
+			"key":        req.Key,
+			"expires_at": time.Now().Add(duration).Unix(),
+		})
+
+	case http.MethodDelete:
+		key := r.URL.Query().Get("key")
+		if key == "" {
+			http.Error(w, "key is required", http.StatusBadRequest)
+			return
+		}
+
+		if err := s.suppressions.SuppressDetectionKey(ctx, key, 0, ""); err != nil {
+			slog.Error("Failed to lift suppression", "error", err)
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		log.Printf("Suppression lifted for detection key %s", key)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"key":     key,
+		})
+
+	default:
+		http.Error(w, "Method not supported", http.StatusMethodNotAllowed)
+	}
+}
+
 func (s *Server) enableCORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -200,3 +825,24 @@ func (s *Server) enableCORS(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// requireToken rejects requests without the shared bearer token. Nearly every endpoint
+// changes state (rollback, approve, deploy, thresholds, suppressions, policies), so none are exempt.
+func (s *Server) requireToken(next http.Handler) http.Handler {
+	if s.apiToken == "" {
+		return next
+	}
+
+	expected := []byte("Bearer " + s.apiToken)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			log.Printf("Rejected unauthenticated request: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
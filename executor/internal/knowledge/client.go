@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
+	"time"
 
 	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/transport"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 type Client struct {
@@ -15,10 +18,13 @@ type Client struct {
 	client pb.KnowledgeServiceClient
 }
 
-func NewClient(addr string) (*Client, error) {
-	conn, err := grpc.NewClient(addr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+func NewClient(addr string, tlsConfig transport.TLSConfig) (*Client, error) {
+	credentials, err := tlsConfig.DialOption()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.NewClient(addr, credentials)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to knowledge service at: %s, %w", addr, err)
 	}
@@ -57,6 +63,57 @@ func (k *Client) UpdateActionStatus(ctx context.Context, req *pb.UpdateActionReq
 	return nil
 }
 
+// MarkDetectionResolved resolves a detection with the given solution.
+func (k *Client) MarkDetectionResolved(ctx context.Context, detectionID, solution string) error {
+	resp, err := k.client.MarkDetectionResolved(ctx, &pb.ResolveDetectionRequest{
+		DetectionId: detectionID,
+		Solution:    solution,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resolve detection: %w", err)
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("knowledge rejected detection resolution: %s", resp.Message)
+	}
+
+	return nil
+}
+
+// MarkDetectionUnactionable records that the Executor could not act on a detection.
+func (k *Client) MarkDetectionUnactionable(ctx context.Context, detectionID, reason string) error {
+	resp, err := k.client.MarkDetectionUnactionable(ctx, &pb.UnactionableDetectionRequest{
+		DetectionId: detectionID,
+		Reason:      reason,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark detection unactionable: %w", err)
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("knowledge rejected unactionable update: %s", resp.Message)
+	}
+
+	return nil
+}
+
+// MarkDetectionAcknowledged parks a detection whose fix is not implemented yet.
+func (k *Client) MarkDetectionAcknowledged(ctx context.Context, detectionID, reason string) error {
+	resp, err := k.client.MarkDetectionAcknowledged(ctx, &pb.AcknowledgeDetectionRequest{
+		DetectionId: detectionID,
+		Reason:      reason,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to acknowledge detection: %w", err)
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("knowledge rejected detection acknowledgement: %s", resp.Message)
+	}
+
+	return nil
+}
+
 func (k *Client) GetPendingActions(ctx context.Context, databaseID string) ([]*pb.Action, error) {
 	resp, err := k.client.GetPendingActions(ctx, &pb.DatabaseFilterRequest{
 		DatabaseId: databaseID,
@@ -68,6 +125,66 @@ func (k *Client) GetPendingActions(ctx context.Context, databaseID string) ([]*p
 	return resp.Actions, nil
 }
 
+// GetAction fetches a single action, including its persisted state and recorded changes.
+func (k *Client) GetAction(ctx context.Context, actionID string) (*pb.Action, bool, error) {
+	resp, err := k.client.GetAction(ctx, &pb.GetActionRequest{
+		ActionId: actionID,
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get action: %w", err)
+	}
+
+	return resp.Action, resp.Found, nil
+}
+
+// ListActionsByStatus fetches all actions in any of the given statuses.
+func (k *Client) ListActionsByStatus(ctx context.Context, statuses ...string) ([]*pb.Action, error) {
+	resp, err := k.client.ListActionsByStatus(ctx, &pb.ListActionsByStatusRequest{
+		Statuses: statuses,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list actions by status: %w", err)
+	}
+
+	return resp.Actions, nil
+}
+
+// RecordDatabaseAction records that a mutating action started on a database.
+func (k *Client) RecordDatabaseAction(ctx context.Context, databaseID, actionID string, startedAt time.Time) error {
+	resp, err := k.client.RecordDatabaseAction(ctx, &pb.RecordDatabaseActionRequest{
+		DatabaseId: databaseID,
+		ActionId:   actionID,
+		StartedAt:  startedAt.Unix(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record database action: %w", err)
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("knowledge rejected database action: %s", resp.Message)
+	}
+
+	return nil
+}
+
+// GetRecentDatabaseActions fetches when mutating actions started on a database since the given time.
+func (k *Client) GetRecentDatabaseActions(ctx context.Context, databaseID string, since time.Time) ([]time.Time, error) {
+	resp, err := k.client.GetRecentDatabaseActions(ctx, &pb.RecentDatabaseActionsRequest{
+		DatabaseId: databaseID,
+		Since:      since.Unix(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent database actions: %w", err)
+	}
+
+	startedAt := make([]time.Time, 0, len(resp.StartedAt))
+	for _, ts := range resp.StartedAt {
+		startedAt = append(startedAt, time.Unix(ts, 0))
+	}
+
+	return startedAt, nil
+}
+
 // GetSystemConfig fetches the system configuration from Knowledge service.
 func (c *Client) GetSystemConfig(ctx context.Context) (*pb.SystemConfig, error) {
 	resp, err := c.client.GetSystemConfig(ctx, &pb.GetSystemConfigRequest{})
@@ -77,11 +194,104 @@ func (c *Client) GetSystemConfig(ctx context.Context) (*pb.SystemConfig, error)
 	return resp, nil
 }
 
+// GetThresholds fetches detection threshold overrides: every set, or the global default
+// and one database's set when databaseID is given.
+func (k *Client) GetThresholds(ctx context.Context, databaseID string) ([]*pb.ThresholdSet, error) {
+	resp, err := k.client.GetThresholds(ctx, &pb.GetThresholdsRequest{
+		DatabaseId: databaseID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get thresholds: %w", err)
+	}
+
+	return resp.Sets, nil
+}
+
+// SetThresholds replaces the threshold overrides for a database, or the global default when
+// databaseID is empty. Empty values clear the overrides.
+func (k *Client) SetThresholds(ctx context.Context, databaseID string, values map[string]float64) error {
+	resp, err := k.client.SetThresholds(ctx, &pb.SetThresholdsRequest{
+		DatabaseId: databaseID,
+		Values:     values,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set thresholds: %w", err)
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("knowledge rejected thresholds: %s", resp.Message)
+	}
+
+	return nil
+}
+
+// SuppressDetectionKey snoozes a detection key for duration; a zero duration lifts the suppression.
+func (c *Client) SuppressDetectionKey(ctx context.Context, key string, duration time.Duration, reason string) error {
+	resp, err := c.client.SuppressDetectionKey(ctx, &pb.SuppressDetectionKeyRequest{
+		Key:          key,
+		DurationSecs: int64(duration / time.Second),
+		Reason:       reason,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to suppress detection key: %w", err)
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("knowledge rejected suppression: %s", resp.Message)
+	}
+
+	return nil
+}
+
+// ListSuppressions fetches the suppressions that have not expired.
+func (c *Client) ListSuppressions(ctx context.Context) ([]*pb.Suppression, error) {
+	resp, err := c.client.ListSuppressions(ctx, &pb.ListSuppressionsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list suppressions: %w", err)
+	}
+
+	return resp.Suppressions, nil
+}
+
+// GetDatabasePolicy fetches a database's policy; one without a policy has empty lists.
+func (c *Client) GetDatabasePolicy(ctx context.Context, databaseID string) (*pb.DatabasePolicy, error) {
+	resp, err := c.client.GetDatabasePolicy(ctx, &pb.GetDatabasePolicyRequest{DatabaseId: databaseID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get policy: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ListDatabasePolicies fetches every stored database policy.
+func (c *Client) ListDatabasePolicies(ctx context.Context) ([]*pb.DatabasePolicy, error) {
+	resp, err := c.client.ListDatabasePolicies(ctx, &pb.ListDatabasePoliciesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list policies: %w", err)
+	}
+
+	return resp.Policies, nil
+}
+
+// SetDatabasePolicy replaces a database's policy; one with empty lists clears it.
+func (c *Client) SetDatabasePolicy(ctx context.Context, policy *pb.DatabasePolicy) error {
+	resp, err := c.client.SetDatabasePolicy(ctx, &pb.SetDatabasePolicyRequest{Policy: policy})
+	if err != nil {
+		return fmt.Errorf("failed to set policy: %w", err)
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("knowledge rejected policy: %s", resp.Message)
+	}
+
+	return nil
+}
+
 // GetExecutionMode fetches just the execution mode, with default fallback.
 func (c *Client) GetExecutionMode(ctx context.Context) string {
 	config, err := c.GetSystemConfig(ctx)
 	if err != nil {
-		log.Printf("Warning: failed to get execution mode, defaulting to autonomous: %v", err)
+		slog.Warn("Failed to get execution mode, defaulting to autonomous", "error", err)
 		return "autonomous"
 	}
 
@@ -96,6 +306,18 @@ func (k *Client) GetServiceClient() pb.KnowledgeServiceClient {
 	return k.client
 }
 
+// Ping checks that the Knowledge service is reachable and serving.
+func (k *Client) Ping(ctx context.Context) error {
+	resp, err := healthpb.NewHealthClient(k.conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		return fmt.Errorf("knowledge unreachable: %w", err)
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("knowledge is %s", resp.Status)
+	}
+	return nil
+}
+
 func (k *Client) Close() error {
 	if k.conn != nil {
 		return k.conn.Close()
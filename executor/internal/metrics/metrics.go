@@ -0,0 +1,75 @@
+// Package metrics exposes Prometheus metrics for the Executor service.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// ActionsExecuted counts finished action executions by type and final status.
+	ActionsExecuted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "executor",
+		Name:      "actions_executed_total",
+		Help:      "Number of executed actions by action type and final status.",
+	}, []string{"action_type", "status"})
+
+	// ActionsFailed counts failed action executions by type.
+	ActionsFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "executor",
+		Name:      "actions_failed_total",
+		Help:      "Number of failed actions by action type.",
+	}, []string{"action_type"})
+
+	// ActionRetries counts failed executions requeued for another attempt, by type and error code.
+	ActionRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "executor",
+		Name:      "action_retries_total",
+		Help:      "Number of action executions retried after a transient failure, by action type and error code.",
+	}, []string{"action_type", "error_code"})
+
+	// ActionsRolledBack counts rollbacks by type and outcome.
+	ActionsRolledBack = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "executor",
+		Name:      "actions_rolled_back_total",
+		Help:      "Number of action rollbacks by action type and outcome.",
+	}, []string{"action_type", "outcome"})
+
+	// ActionDuration tracks action execution time by type.
+	ActionDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "executor",
+		Name:      "action_duration_seconds",
+		Help:      "Action execution time by action type.",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60, 300},
+	}, []string{"action_type"})
+
+	// RollbackDuration tracks rollback execution time by type.
+	RollbackDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "executor",
+		Name:      "rollback_duration_seconds",
+		Help:      "Rollback execution time by action type.",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60, 300},
+	}, []string{"action_type"})
+
+	// ActionsInFlight reports actions currently executing.
+	ActionsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "executor",
+		Name:      "actions_in_flight",
+		Help:      "Number of actions currently executing.",
+	})
+)
+
+// Handler returns the HTTP handler serving metrics in Prometheus format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
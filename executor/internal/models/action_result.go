@@ -8,19 +8,51 @@ type ActionResult struct {
 	ActionType  string `json:"action_type"`
 	DatabaseID  string `json:"database_id"`
 
-	Status    string     `json:"status"`
-	Message   string     `json:"message"`
-	CreatedAt time.Time  `json:"created_at"`
-	Started   *time.Time `json:"started,omitempty"`
-	Completed *time.Time `json:"completed,omitempty"`
+	CorrelationID string `json:"correlation_id,omitempty"` // Carried from the detection, for log tracing
+
+	Status        string     `json:"status"`
+	Message       string     `json:"message"`
+	QueuePosition int        `json:"queue_position,omitempty"` // 1-based position while queued
+	Priority      int        `json:"priority,omitempty"`       // Queue priority while queued; higher runs first
+	ScheduledFor  *time.Time `json:"scheduled_for,omitempty"`  // Planned start while waiting for the maintenance window
+	CreatedAt     time.Time  `json:"created_at"`
+	Started       *time.Time `json:"started,omitempty"`
+	Completed     *time.Time `json:"completed,omitempty"`
 
 	ExecutionTimeMs int64                  `json:"execution_time_ms"`
 	Changes         map[string]interface{} `json:"changes,omitempty"`
 	Error           string                 `json:"error,omitempty"`
+	ErrorCode       ErrorCode              `json:"error_code,omitempty"` // Why it failed; see ClassifyError
+	Attempts        int                    `json:"attempts,omitempty"`   // Executions so far, counting retries of transient failures
 
 	CanRollback   bool   `json:"can_rollback"`
 	Rolledback    bool   `json:"rolledback"`
 	RollbackError string `json:"rollback_error,omitempty"`
+
+	Progress *ActionProgress `json:"progress,omitempty"` // Set while a long-running action executes
+
+	// How the execution policy handled the action, and the rule that decided it, so the
+	// Dashboard can explain why an action is waiting for approval
+	PolicyDecision string `json:"policy_decision,omitempty"`
+	PolicyRule     string `json:"policy_rule,omitempty"`
+}
+
+// ActionProgress reports how far a long-running action (e.g. an index build) has got.
+type ActionProgress struct {
+	Phase       string    `json:"phase"`
+	BlocksDone  int64     `json:"blocks_done"`
+	BlocksTotal int64     `json:"blocks_total"`
+	TuplesDone  int64     `json:"tuples_done"`
+	TuplesTotal int64     `json:"tuples_total"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Percent returns blocks done as a percentage, or 0 if the total is unknown.
+func (p *ActionProgress) Percent() float64 {
+	if p.BlocksTotal <= 0 {
+		return 0
+	}
+	return float64(p.BlocksDone) / float64(p.BlocksTotal) * 100
 }
 
 type ActionMetadata struct {
@@ -36,17 +68,52 @@ const (
 	StatusSuggested             = "suggested"        // Observe mode - recommendation only
 	StatusPendingApproval       = "pending_approval" // Approval mode - waiting for user
 	StatusApproved              = "approved"         // User approved, ready to execute
+	StatusScheduled             = "scheduled"        // Waiting for the maintenance window
 	StatusRejected              = "rejected"         // User rejected
+	StatusRefused               = "refused"          // Denied by the database's policy
 	StatusExecuting             = "executing"
 	StatusCompleted             = "completed"
 	StatusFailed                = "failed"
 	StatusPendingImplementation = "pending_implementation"
 	StatusRolledBack            = "rolled_back"
+	StatusRollbackFailed        = "rollback_failed" // Rollback attempted but the change is still in place
+	StatusRegressed             = "regressed"       // Completed, but the container it deployed keeps restarting
+)
+
+// Action sources, recorded in Knowledge so the audit trail shows what triggered each step
+const (
+	SourceManual               = "manual"                // Deployed from the Dashboard
+	SourceManualApproval       = "manual_approval"       // Approved or rejected from the Dashboard
+	SourceManualRollback       = "manual_rollback"       // Rolled back from the Dashboard
+	SourceVerificationRollback = "verification_rollback" // Analyser saw the issue persist after the action
+	SourceContainerWatchdog    = "container_watchdog"    // A deployed container kept restarting
 )
 
+// DetectorSource is the source of an action raised by an Analyser detector.
+func DetectorSource(detectorName string) string {
+	if detectorName == "" {
+		return "detector"
+	}
+	return "detector:" + detectorName
+}
+
 // Execution modes
 const (
 	ModeObserve    = "observe"    // Detect only, no execution
 	ModeApproval   = "approval"   // Detect, wait for approval
 	ModeAutonomous = "autonomous" // Detect and execute immediately
 )
+
+// Per-action-type approval overrides
+const (
+	ApprovalAuto     = "auto"     // Always execute without approval (unless observing)
+	ApprovalRequired = "approval" // Always wait for approval
+)
+
+// Execution policy behaviours, chosen per detection severity
+const (
+	PolicyAuto    = "auto"    // Queue the action for execution
+	PolicyApprove = "approve" // Wait for a user to approve it
+	PolicyNotify  = "notify"  // Only suggest it and tell operators; never execute
+	PolicyDeny    = "deny"    // The database's policy forbids the action type; never execute
+)
@@ -14,4 +14,8 @@ type Detection struct {
 	ActionMetaData map[string]interface{} `json:"action_metadata"` // Match Analyser's "action_metadata"
 	Evidence       map[string]interface{} `json:"evidence"`
 	Timestamp      int64                  `json:"timestamp"`
+	Value          float64                `json:"value"`                    // Headline measurement behind the detection
+	Escalated      bool                   `json:"escalated"`                // Re-published by the Analyser at a higher severity
+	Updated        bool                   `json:"updated"`                  // Re-published by the Analyser after its value changed significantly
+	CorrelationID  string                 `json:"correlation_id,omitempty"` // From the collection cycle that produced the detection
 }
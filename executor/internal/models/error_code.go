@@ -0,0 +1,261 @@
+package models
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorCode says why an action failed, so the Dashboard can tell "fix your grants" from
+// "will retry" from "we have a bug", and the Executor knows which failures to retry.
+type ErrorCode string
+
+const (
+	// ErrorCodePermissionDenied: the database or Docker user lacks a privilege the action needs
+	ErrorCodePermissionDenied ErrorCode = "PERMISSION_DENIED"
+	// ErrorCodeObjectNotFound: a table, column, index, backend or container is gone
+	ErrorCodeObjectNotFound ErrorCode = "OBJECT_NOT_FOUND"
+	// ErrorCodeAlreadyExists: the object the action would create is already there
+	ErrorCodeAlreadyExists ErrorCode = "ALREADY_EXISTS"
+	// ErrorCodeInvalidRequest: the detection asked for something invalid, e.g. missing metadata
+	ErrorCodeInvalidRequest ErrorCode = "INVALID_REQUEST"
+	// ErrorCodeUnsupported: the database type cannot perform the action
+	ErrorCodeUnsupported ErrorCode = "UNSUPPORTED"
+	// ErrorCodeTimeout: the action or one of its statements ran out of time
+	ErrorCodeTimeout ErrorCode = "TIMEOUT"
+	// ErrorCodeConflict: a lock, deadlock or concurrent change got in the way
+	ErrorCodeConflict ErrorCode = "CONFLICT"
+	// ErrorCodeDependencyUnavailable: the database, Docker or Knowledge could not be reached
+	ErrorCodeDependencyUnavailable ErrorCode = "DEPENDENCY_UNAVAILABLE"
+	// ErrorCodeCancelled: the Executor shut down while the action was queued or running
+	ErrorCodeCancelled ErrorCode = "CANCELLED"
+	// ErrorCodeInternal: anything not recognised, most likely a bug
+	ErrorCodeInternal ErrorCode = "INTERNAL"
+)
+
+// Transient reports whether a failure with this code may succeed if the action is retried.
+func (c ErrorCode) Transient() bool {
+	switch c {
+	case ErrorCodeTimeout, ErrorCodeConflict, ErrorCodeDependencyUnavailable:
+		return true
+	default:
+		return false
+	}
+}
+
+// Sentinel errors the classifier recognises, for failures that don't come from a driver.
+// Wrap them with %w to keep the message specific.
+var (
+	ErrInvalidRequest = errors.New("invalid request")
+	ErrUnsupported    = errors.New("not supported")
+)
+
+// classifiedError carries a code chosen where the failure happened.
+type classifiedError struct {
+	code ErrorCode
+	err  error
+}
+
+func (e *classifiedError) Error() string { return e.err.Error() }
+func (e *classifiedError) Unwrap() error { return e.err }
+
+// WithErrorCode marks err with code, overriding what ClassifyError would infer from it.
+func WithErrorCode(code ErrorCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &classifiedError{code: code, err: err}
+}
+
+// ClassifyError maps an action failure to an ErrorCode, looking through wrapped errors for
+// codes set with WithErrorCode, Postgres SQLSTATEs, MySQL error numbers, MongoDB and Docker
+// API errors, gRPC statuses and network errors. Unrecognised errors are ErrorCodeInternal.
+func ClassifyError(err error) ErrorCode {
+	if err == nil {
+		return ""
+	}
+
+	var classified *classifiedError
+	if errors.As(err, &classified) {
+		return classified.code
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return classifySQLState(pgErr.Code)
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return classifyMySQLError(mysqlErr.Number)
+	}
+
+	switch {
+	case errors.Is(err, ErrInvalidRequest):
+		return ErrorCodeInvalidRequest
+	case errors.Is(err, ErrUnsupported):
+		return ErrorCodeUnsupported
+	case errors.Is(err, context.Canceled), errdefs.IsCancelled(err):
+		return ErrorCodeCancelled
+	case errors.Is(err, context.DeadlineExceeded), errdefs.IsDeadline(err), pgconn.Timeout(err), mongo.IsTimeout(err):
+		return ErrorCodeTimeout
+	}
+
+	if code, ok := classifyDockerError(err); ok {
+		return code
+	}
+
+	if code, ok := classifyGRPCError(err); ok {
+		return code
+	}
+
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &connectErr), errors.Is(err, driver.ErrBadConn), errors.Is(err, mysql.ErrInvalidConn), mongo.IsNetworkError(err):
+		return ErrorCodeDependencyUnavailable
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return ErrorCodeTimeout
+		}
+		return ErrorCodeDependencyUnavailable
+	}
+
+	var mongoErr mongo.ServerError
+	if errors.As(err, &mongoErr) {
+		return classifyMongoError(mongoErr)
+	}
+
+	return ErrorCodeInternal
+}
+
+// ClassifyValidationError classifies an error from an action's Validate. Validation mostly
+// rejects the request itself, so errors nothing more specific is known about are
+// ErrorCodeInvalidRequest rather than ErrorCodeInternal.
+func ClassifyValidationError(err error) ErrorCode {
+	code := ClassifyError(err)
+	if code == ErrorCodeInternal {
+		return ErrorCodeInvalidRequest
+	}
+	return code
+}
+
+// classifySQLState maps a Postgres SQLSTATE, first by the exact code, then by its class.
+func classifySQLState(code string) ErrorCode {
+	switch code {
+	case "42501", "28000", "28P01": // insufficient_privilege, invalid authorization / password
+		return ErrorCodePermissionDenied
+	case "42P01", "42703", "42704", "42883", "3D000", "3F000": // undefined table, column, object, function, database, schema
+		return ErrorCodeObjectNotFound
+	case "42P07", "42710", "23505": // duplicate table / object, unique violation
+		return ErrorCodeAlreadyExists
+	case "57014": // query_canceled, raised by statement_timeout
+		return ErrorCodeTimeout
+	case "55P03", "55006", "40001", "40P01": // lock not available, object in use, serialization failure, deadlock
+		return ErrorCodeConflict
+	case "57P01", "57P02", "57P03": // admin / crash shutdown, cannot connect now
+		return ErrorCodeDependencyUnavailable
+	case "0A000": // feature_not_supported
+		return ErrorCodeUnsupported
+	}
+
+	switch {
+	case strings.HasPrefix(code, "08"), strings.HasPrefix(code, "53"): // connection exception, insufficient resources
+		return ErrorCodeDependencyUnavailable
+	case strings.HasPrefix(code, "22"), strings.HasPrefix(code, "42"): // data exception, syntax error or access rule
+		return ErrorCodeInvalidRequest
+	}
+
+	return ErrorCodeInternal
+}
+
+// classifyMySQLError maps a MySQL server error number.
+func classifyMySQLError(number uint16) ErrorCode {
+	switch number {
+	case 1044, 1045, 1142, 1143, 1227: // access denied for database, user, table, column; missing privilege
+		return ErrorCodePermissionDenied
+	case 1049, 1054, 1091, 1146: // unknown database, column; can't drop key; table doesn't exist
+		return ErrorCodeObjectNotFound
+	case 1050, 1061, 1062: // table exists, duplicate key name, duplicate entry
+		return ErrorCodeAlreadyExists
+	case 1205, 1213: // lock wait timeout, deadlock
+		return ErrorCodeConflict
+	case 3024: // query execution was interrupted, maximum statement execution time exceeded
+		return ErrorCodeTimeout
+	case 1040, 1053: // too many connections, server shutdown in progress
+		return ErrorCodeDependencyUnavailable
+	}
+	return ErrorCodeInternal
+}
+
+// classifyMongoError maps a MongoDB server error.
+func classifyMongoError(err mongo.ServerError) ErrorCode {
+	switch {
+	case err.HasErrorCode(13), err.HasErrorCode(18): // Unauthorized, AuthenticationFailed
+		return ErrorCodePermissionDenied
+	case err.HasErrorCode(26): // NamespaceNotFound
+		return ErrorCodeObjectNotFound
+	case err.HasErrorCode(85), err.HasErrorCode(86), mongo.IsDuplicateKeyError(err): // index options / key specs conflict
+		return ErrorCodeAlreadyExists
+	case err.HasErrorCode(50): // MaxTimeMSExpired
+		return ErrorCodeTimeout
+	case err.HasErrorCode(112), err.HasErrorCode(189): // WriteConflict, PrimarySteppedDown
+		return ErrorCodeConflict
+	}
+	return ErrorCodeInternal
+}
+
+// classifyDockerError maps a Docker API error, which the client types by HTTP status.
+func classifyDockerError(err error) (ErrorCode, bool) {
+	switch {
+	case client.IsErrConnectionFailed(err), errdefs.IsUnavailable(err):
+		return ErrorCodeDependencyUnavailable, true
+	case errdefs.IsNotFound(err):
+		return ErrorCodeObjectNotFound, true
+	case errdefs.IsConflict(err):
+		return ErrorCodeConflict, true
+	case errdefs.IsUnauthorized(err), errdefs.IsForbidden(err):
+		return ErrorCodePermissionDenied, true
+	case errdefs.IsInvalidParameter(err):
+		return ErrorCodeInvalidRequest, true
+	case errdefs.IsNotImplemented(err):
+		return ErrorCodeUnsupported, true
+	case errdefs.IsSystem(err):
+		return ErrorCodeInternal, true
+	}
+	return "", false
+}
+
+// classifyGRPCError maps a failed call to Knowledge.
+func classifyGRPCError(err error) (ErrorCode, bool) {
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &grpcErr) {
+		return "", false
+	}
+
+	switch grpcErr.GRPCStatus().Code() {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted:
+		return ErrorCodeDependencyUnavailable, true
+	case codes.DeadlineExceeded:
+		return ErrorCodeTimeout, true
+	case codes.NotFound:
+		return ErrorCodeObjectNotFound, true
+	case codes.PermissionDenied, codes.Unauthenticated:
+		return ErrorCodePermissionDenied, true
+	case codes.InvalidArgument, codes.FailedPrecondition:
+		return ErrorCodeInvalidRequest, true
+	case codes.Unimplemented:
+		return ErrorCodeUnsupported, true
+	}
+	return "", false
+}
@@ -0,0 +1,284 @@
+// Package notify posts critical detections and action outcomes to a chat webhook
+// (Slack-compatible JSON), so operators hear about them without watching the Dashboard.
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+)
+
+// Event types, named like the Dashboard's webhook events.
+const (
+	EventCriticalDetection    = "detection.critical"
+	EventActionCompleted      = "action.completed"
+	EventActionFailed         = "action.failed"
+	EventActionRolledBack     = "action.rolledback"
+	EventActionRollbackFailed = "action.rollback_failed"
+	EventActionSuggested      = "action.suggested"
+	EventActionRegressed      = "action.regressed"
+)
+
+const (
+	queueSize      = 100
+	sendTimeout    = 10 * time.Second
+	maxChangeLines = 10
+)
+
+// Webhook posts notifications from a background goroutine, so a slow or unreachable
+// endpoint never delays action execution. At most ratePerMinute messages are posted per
+// minute; the rest are dropped and counted in the next message that goes out.
+type Webhook struct {
+	url           string
+	events        map[string]bool
+	ratePerMinute int
+	client        *http.Client
+
+	mu          sync.Mutex
+	closed      bool
+	windowStart time.Time
+	sent        int
+	dropped     int
+
+	messages chan slackMessage
+	done     chan struct{}
+}
+
+// NewWebhook starts a notifier posting the given event types to url. ratePerMinute <= 0
+// disables rate limiting.
+func NewWebhook(url string, events []string, ratePerMinute int) *Webhook {
+	w := &Webhook{
+		url:           url,
+		events:        make(map[string]bool, len(events)),
+		ratePerMinute: ratePerMinute,
+		client:        &http.Client{Timeout: sendTimeout},
+		messages:      make(chan slackMessage, queueSize),
+		done:          make(chan struct{}),
+	}
+	for _, event := range events {
+		w.events[strings.ToLower(strings.TrimSpace(event))] = true
+	}
+
+	go w.run()
+
+	return w
+}
+
+// Close posts the notifications already queued and stops the notifier.
+func (w *Webhook) Close() {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return
+	}
+	w.closed = true
+	close(w.messages)
+	w.mu.Unlock()
+
+	<-w.done
+}
+
+// DetectionRaised posts a detection if it is critical.
+func (w *Webhook) DetectionRaised(detection *models.Detection) {
+	if !strings.EqualFold(detection.Severity, "critical") {
+		return
+	}
+
+	w.post(EventCriticalDetection, slackMessage{
+		Text: fmt.Sprintf(":rotating_light: Critical detection on *%s*: %s", detection.DatabaseID, detection.Title),
+		Attachments: []slackAttachment{{
+			Color: "danger",
+			Fields: []slackField{
+				{Title: "Database", Value: detection.DatabaseID, Short: true},
+				{Title: "Severity", Value: detection.Severity, Short: true},
+				{Title: "Recommended action", Value: detection.ActionType, Short: true},
+				{Title: "Details", Value: detection.Description},
+			},
+		}},
+	})
+}
+
+// ActionFinished posts an action that completed, failed, regressed or was rolled back, or
+// that the execution policy only suggests. detection may be nil when the action outlived the
+// detection that triggered it (e.g. a rollback).
+func (w *Webhook) ActionFinished(result *models.ActionResult, detection *models.Detection) {
+	var event, verb, color string
+	switch result.Status {
+	case models.StatusCompleted:
+		event, verb, color = EventActionCompleted, "completed", "good"
+	case models.StatusFailed:
+		event, verb, color = EventActionFailed, "failed", "danger"
+	case models.StatusRolledBack:
+		event, verb, color = EventActionRolledBack, "rolled back", "warning"
+	case models.StatusRollbackFailed:
+		event, verb, color = EventActionRollbackFailed, "rollback failed", "danger"
+	case models.StatusSuggested:
+		event, verb, color = EventActionSuggested, "suggested", "#439FE0"
+	case models.StatusRegressed:
+		event, verb, color = EventActionRegressed, "regressed", "danger"
+	default:
+		return
+	}
+
+	title := result.ActionType
+	severity := "unknown"
+	if detection != nil {
+		if detection.Title != "" {
+			title = detection.Title
+		}
+		if detection.Severity != "" {
+			severity = detection.Severity
+		}
+	}
+
+	fields := []slackField{
+		{Title: "Database", Value: result.DatabaseID, Short: true},
+		{Title: "Severity", Value: severity, Short: true},
+		{Title: "Action", Value: fmt.Sprintf("%s (%s)", result.ActionType, result.ActionID)},
+	}
+	if result.PolicyRule != "" && result.Status == models.StatusSuggested {
+		fields = append(fields, slackField{Title: "Policy", Value: result.PolicyRule})
+	}
+	if changes := summariseChanges(result.Changes); changes != "" {
+		fields = append(fields, slackField{Title: "Changes", Value: changes})
+	}
+	if result.Error != "" {
+		fields = append(fields, slackField{Title: "Error", Value: result.Error})
+	} else if result.RollbackError != "" {
+		fields = append(fields, slackField{Title: "Error", Value: result.RollbackError})
+	}
+
+	w.post(event, slackMessage{
+		Text:        fmt.Sprintf("Action %s on *%s*: %s", verb, result.DatabaseID, title),
+		Attachments: []slackAttachment{{Color: color, Fields: fields}},
+	})
+}
+
+// post queues a message if its event is enabled and the rate limit allows it.
+func (w *Webhook) post(event string, message slackMessage) {
+	if !w.events[event] {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed || !w.allow(&message) {
+		return
+	}
+
+	select {
+	case w.messages <- message:
+	default:
+		slog.Warn("Notification queue full, dropping notification", "event", event)
+	}
+}
+
+// allow applies the per-minute limit. The first message after a burst reports how many
+// were dropped. Callers hold mu.
+func (w *Webhook) allow(message *slackMessage) bool {
+	if w.ratePerMinute <= 0 {
+		return true
+	}
+
+	now := time.Now()
+	if now.Sub(w.windowStart) >= time.Minute {
+		w.windowStart = now
+		w.sent = 0
+	}
+
+	if w.sent >= w.ratePerMinute {
+		if w.dropped == 0 {
+			log.Printf("Notification rate limit reached (%d/min), dropping notifications", w.ratePerMinute)
+		}
+		w.dropped++
+		return false
+	}
+
+	w.sent++
+	if w.dropped > 0 {
+		message.Text += fmt.Sprintf("\n_%d earlier notifications were dropped by the rate limit_", w.dropped)
+		w.dropped = 0
+	}
+
+	return true
+}
+
+func (w *Webhook) run() {
+	defer close(w.done)
+
+	for message := range w.messages {
+		if err := w.send(message); err != nil {
+			slog.Warn("Failed to send notification", "error", err)
+		}
+	}
+}
+
+func (w *Webhook) send(message slackMessage) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+
+	return nil
+}
+
+// summariseChanges renders an action's changes as sorted "key: value" lines.
+func summariseChanges(changes map[string]interface{}) string {
+	if len(changes) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(changes))
+	for key := range changes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, maxChangeLines+1)
+	for i, key := range keys {
+		if i == maxChangeLines {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(keys)-maxChangeLines))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%s: %v", key, changes[key]))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// slackMessage is the incoming-webhook payload Slack (and Mattermost, Rocket.Chat, ...) accept.
+type slackMessage struct {
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments,omitempty"`
+}
+
+type slackAttachment struct {
+	Color  string       `json:"color,omitempty"`
+	Fields []slackField `json:"fields"`
+}
+
+type slackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short,omitempty"`
+}
@@ -4,23 +4,44 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"net"
+	"sync"
+	"time"
 
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/actions"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/config"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/database"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/docker"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/eventbus"
 	grpcserver "github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/grpc"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/handler"
 	httpserver "github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/http"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/knowledge"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/metrics"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/notify"
 	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/health"
 	"google.golang.org/grpc"
 )
 
+// reconcileTimeout bounds startup reconciliation of actions left by a previous process.
+const reconcileTimeout = 30 * time.Second
+
+// dockerProbeTimeout bounds the startup check for a reachable Docker engine.
+const dockerProbeTimeout = 5 * time.Second
+
+// healthStopTimeout bounds how long Stop waits for in-flight health checks.
+const healthStopTimeout = 5 * time.Second
+
+// natsDrainTimeout bounds how long Stop waits for detection handlers already running.
+const natsDrainTimeout = 10 * time.Second
+
 // Orchestrator manages the Executor service lifecycle and coordinates
 // action execution, event handling, and communication with downstream services.
 //
 // Lifecycle:
-//  1. Start() - Initializes detection handler, NATS, Knowledge, HTTP, and gRPC servers
+//  1. Start() - Initializes detection handler, NATS, Knowledge, HTTP, gRPC and health servers
 //  2. Run() - Starts all servers and blocks until context is cancelled
 //  3. Stop() - Gracefully closes all connections and resources
 //
@@ -29,20 +50,31 @@ import (
 //   - Knowledge failure: Actions proceed but not registered (no deduplication or status tracking)
 //   - HTTP server failure: Rollback API unavailable (but autonomous actions continue)
 type Orchestrator struct {
-	config *config.Config
+	config   *config.Config
+	configMu sync.Mutex // guards config against concurrent reloads
 
 	// Core components
 	detectionHandler *handler.DetectionHandler
+	actionLimiter    *handler.ActionLimiter
 
 	// Downstream service connections
 	natsPublisher   *eventbus.Publisher  // NATS publisher for action status
 	natsSubscriber  *eventbus.Subscriber // NATS subscriber for detections
 	knowledgeClient *knowledge.Client    // Knowledge service client
+	notifier        *notify.Webhook      // Chat notifications; nil when not configured
+
+	// Docker engine for container-backed actions; nil when no client could be created.
+	// dockerErr says why the engine is unreachable and is guarded by dockerMu
+	dockerClient *docker.Client
+	dockerMu     sync.Mutex
+	dockerProbed bool
+	dockerErr    error
 
 	// Servers
 	httpServer   *httpserver.Server
 	grpcServer   *grpc.Server
 	grpcListener net.Listener
+	healthServer *health.Server
 }
 
 // NewOrchestrator creates a new Orchestrator instance with the provided configuration.
@@ -80,7 +112,7 @@ func (o *Orchestrator) Start() error {
 
 	// Initialize servers
 	if err := o.initializeHTTPServer(); err != nil {
-		log.Printf("Warning: failed to initialize HTTP server: %v", err)
+		slog.Warn("Failed to initialize HTTP server", "error", err)
 		log.Printf("Rollback API will be unavailable")
 	}
 
@@ -88,6 +120,10 @@ func (o *Orchestrator) Start() error {
 		return fmt.Errorf("failed to initialize gRPC server: %w", err)
 	}
 
+	if err := o.startHealthServer(); err != nil {
+		return fmt.Errorf("failed to start health server: %w", err)
+	}
+
 	log.Printf("Executor Orchestrator started successfully")
 	return nil
 }
@@ -98,9 +134,9 @@ func (o *Orchestrator) Start() error {
 func (o *Orchestrator) connectKnowledge() {
 	log.Printf("Connecting to Knowledge service at: %s", o.config.KnowledgeAddress)
 
-	client, err := knowledge.NewClient(o.config.KnowledgeAddress)
+	client, err := knowledge.NewClient(o.config.KnowledgeAddress, o.config.GRPCTLS)
 	if err != nil {
-		log.Printf("Warning: failed to connect to Knowledge service: %v", err)
+		slog.Warn("Failed to connect to Knowledge service", "error", err)
 		log.Printf("Actions will execute but not be registered or deduplicated")
 		return
 	}
@@ -115,7 +151,7 @@ func (o *Orchestrator) connectNATS() error {
 	log.Printf("Connecting to NATS at: %s", o.config.NatsURL)
 
 	// Initialize publisher for action status
-	publisher, err := eventbus.NewPublisher(o.config.NatsURL)
+	publisher, err := eventbus.NewPublisher(o.config.NatsURL, o.config.NatsJetStream)
 	if err != nil {
 		return fmt.Errorf("failed to create NATS publisher: %w", err)
 	}
@@ -132,11 +168,86 @@ func (o *Orchestrator) connectNATS() error {
 func (o *Orchestrator) initializeDetectionHandler() error {
 	log.Printf("Initializing detection handler...")
 
-	o.detectionHandler = handler.NewDetectionHandler(o.natsPublisher, o.knowledgeClient)
-	log.Printf("Detection handler initialized")
+	o.detectionHandler = handler.NewDetectionHandler(
+		o.natsPublisher,
+		o.knowledgeClient,
+		o.config.MaxConcurrentActions,
+		time.Duration(o.config.ActionTimeout)*time.Second,
+	)
+	o.actionLimiter = handler.NewActionLimiter(
+		time.Duration(o.config.ActionCooldown)*time.Second,
+		o.config.MaxActionsPerHour,
+	)
+	o.applyRuntimeConfig(o.config)
+	if !o.config.EnableAutoExecution {
+		log.Printf("Auto execution disabled - actions wait for approval")
+	}
+	log.Printf("Execution policy by severity: %v", o.config.ExecutionPolicy)
+	pgBouncerDefaults := actions.DefaultPgBouncerSettings()
+	pgBouncerDefaults.HostPort = o.config.PgBouncerHostPort
+	pgBouncerDefaults.PoolMode = o.config.PgBouncerPoolMode
+	pgBouncerDefaults.DefaultPoolSize = o.config.PgBouncerDefaultPoolSize
+	pgBouncerDefaults.MaxClientConn = o.config.PgBouncerMaxClientConn
+	pgBouncerDefaults.Image = o.config.PgBouncerImage
+	o.detectionHandler.SetPgBouncerDefaults(pgBouncerDefaults)
+	o.detectionHandler.SetTerminateSettings(actions.TerminateSettings{
+		CancelFirst:       o.config.TerminateCancelFirst,
+		AllowApplications: o.config.TerminateAllowApplications,
+		AllowUsers:        o.config.TerminateAllowUsers,
+		DenyApplications:  o.config.TerminateDenyApplications,
+		DenyUsers:         o.config.TerminateDenyUsers,
+	})
+	o.detectionHandler.SetActionLimiter(o.actionLimiter)
+	o.detectionHandler.SetAdapterFactory(database.NewAdapterFactory(database.AdapterOptions{
+		MongoProfileSlowMs: o.config.MongoProfileSlowMs,
+	}))
+	o.detectionHandler.SetPolicyCacheTTL(time.Duration(o.config.PolicyCacheSeconds) * time.Second)
+	log.Printf("Detection handler initialized (max concurrent: %d, timeout: %ds, cooldown: %ds, max per hour: %d)",
+		o.config.MaxConcurrentActions, o.config.ActionTimeout, o.config.ActionCooldown, o.config.MaxActionsPerHour)
+
+	if o.config.NotifyWebhookURL != "" {
+		o.notifier = notify.NewWebhook(o.config.NotifyWebhookURL, o.config.NotifyEvents, o.config.NotifyRatePerMinute)
+		o.detectionHandler.SetNotifier(o.notifier)
+		log.Printf("Notifications enabled for %v (max %d/min)", o.config.NotifyEvents, o.config.NotifyRatePerMinute)
+	}
+
+	// Set before Reconcile so restored actions use the shared Docker client
+	o.probeDocker()
+
+	// Set before Reconcile so restored scheduled actions wait for the window again
+	if o.config.MaintenanceWindow != "" {
+		window, err := handler.ParseMaintenanceWindow(o.config.MaintenanceWindow, o.config.MaintenanceTimezone)
+		if err != nil {
+			return fmt.Errorf("invalid MAINTENANCE_WINDOW: %w", err)
+		}
+		o.detectionHandler.SetMaintenanceWindow(window, o.config.MaintenanceBypassSeverities)
+		log.Printf("Mutating actions limited to maintenance window %s (bypass severities: %v)",
+			window, o.config.MaintenanceBypassSeverities)
+	}
+
+	// Recover action state left behind by a previous Executor process
+	if o.knowledgeClient != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+		if err := o.detectionHandler.Reconcile(ctx); err != nil {
+			slog.Warn("Failed to reconcile actions with Knowledge", "error", err)
+		}
+		cancel()
+	}
+
+	// Started after Reconcile so containers deployed by a previous process are watched too
+	if o.config.ContainerWatchdogInterval > 0 {
+		o.detectionHandler.SetWatchdogSettings(handler.WatchdogSettings{
+			RestartThreshold: o.config.ContainerRestartThreshold,
+			Rollback:         o.config.ContainerWatchdogRollback,
+		})
+		go o.detectionHandler.WatchDeployedContainers(time.Duration(o.config.ContainerWatchdogInterval) * time.Second)
+		log.Printf("Watching deployed containers every %ds (regressed after %d restarts, rollback: %v; Redis %s, PgBouncer %s)",
+			o.config.ContainerWatchdogInterval, o.config.ContainerRestartThreshold, o.config.ContainerWatchdogRollback,
+			o.config.RedisLimits(), o.config.PgBouncerLimits())
+	}
 
 	// Now initialize NATS subscriber with the handler
-	subscriber, err := eventbus.NewSubscriber(o.config.NatsURL, o.detectionHandler, o.detectionHandler, o.detectionHandler)
+	subscriber, err := eventbus.NewSubscriber(o.config.NatsURL, o.config.NatsJetStream, o.detectionHandler, o.detectionHandler, o.detectionHandler)
 	if err != nil {
 		return fmt.Errorf("failed to create NATS subscriber: %w", err)
 	}
@@ -151,12 +262,117 @@ func (o *Orchestrator) initializeDetectionHandler() error {
 	return nil
 }
 
+// probeDocker checks once at startup whether the Docker engine is reachable. Without it,
+// container-backed actions are suggested as manual steps instead of failing.
+func (o *Orchestrator) probeDocker() {
+	client, err := docker.NewClientWithConfig(o.config.Docker())
+	if err == nil {
+		o.dockerClient = client
+
+		ctx, cancel := context.WithTimeout(context.Background(), dockerProbeTimeout)
+		err = client.IsAvailable(ctx)
+		cancel()
+	}
+
+	o.setDockerAvailability(err)
+}
+
+// setDockerAvailability tells the detection handler whether Docker is reachable, logging
+// only when that changes.
+func (o *Orchestrator) setDockerAvailability(err error) {
+	o.dockerMu.Lock()
+	changed := !o.dockerProbed || (err == nil) != (o.dockerErr == nil)
+	o.dockerProbed = true
+	o.dockerErr = err
+	o.dockerMu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	if err != nil {
+		slog.Warn("Docker unavailable", "error", err)
+		log.Printf("Container-backed actions (PgBouncer, Redis, shared_buffers) will be suggested as manual steps")
+		o.detectionHandler.SetDocker(nil, err)
+		return
+	}
+
+	log.Printf("Docker available at %s", o.dockerClient.Host())
+	o.detectionHandler.SetDocker(o.dockerClient, nil)
+}
+
+// applyRuntimeConfig pushes the settings a reload can change to the detection handler,
+// its worker pool and the action limiter. Actions already running are not affected.
+func (o *Orchestrator) applyRuntimeConfig(cfg *config.Config) {
+	o.detectionHandler.SetMaxConcurrent(cfg.MaxConcurrentActions)
+	o.detectionHandler.SetActionTimeout(time.Duration(cfg.ActionTimeout) * time.Second)
+	o.detectionHandler.SetRetryPolicy(handler.RetryPolicy{
+		MaxAttempts: cfg.ActionMaxAttempts,
+		Backoff:     time.Duration(cfg.ActionRetryBackoff) * time.Second,
+	})
+	o.detectionHandler.SetApprovalPolicy(cfg.EnableAutoExecution, cfg.ApprovalOverrides)
+	o.detectionHandler.SetExecutionPolicy(cfg.ExecutionPolicy)
+	o.detectionHandler.SetProbeSettings(docker.ProbeSettings{
+		Host:    cfg.ContainerProbeHost,
+		Timeout: time.Duration(cfg.ContainerProbeTimeout) * time.Second,
+	})
+	o.detectionHandler.SetResourceLimits(cfg.RedisLimits(), cfg.PgBouncerLimits())
+	o.detectionHandler.SetRollbackSafety(actions.RollbackSafety{
+		IndexScanThreshold: int64(cfg.RollbackIndexScanThreshold),
+	})
+	o.actionLimiter.SetLimits(time.Duration(cfg.ActionCooldown)*time.Second, cfg.MaxActionsPerHour)
+}
+
+// ReloadConfig re-reads the environment and .env file and applies the settings that are
+// safe to change at runtime (concurrency, timeouts, retries, auto-execution, approval overrides,
+// execution policy, cooldowns, rollback safety). Changes to anything else, such as ports, are rejected and logged; they take
+// effect on the next restart.
+func (o *Orchestrator) ReloadConfig() (*config.ReloadResult, error) {
+	o.configMu.Lock()
+	defer o.configMu.Unlock()
+
+	updated, result, err := config.Reload(o.config)
+	if err != nil {
+		slog.Warn("Config reload failed, keeping current config", "error", err)
+		return nil, err
+	}
+
+	for _, change := range result.Rejected {
+		log.Printf("Config reload: %s rejected - restart required", change)
+	}
+
+	if len(result.Applied) == 0 {
+		log.Printf("Config reload: no runtime settings changed")
+		return result, nil
+	}
+
+	for _, change := range result.Applied {
+		log.Printf("Config reload: %s", change)
+	}
+
+	o.config = updated
+	if o.detectionHandler != nil {
+		o.applyRuntimeConfig(updated)
+	}
+
+	return result, nil
+}
+
 // initializeHTTPServer creates the HTTP server for the rollback API.
 // This server provides REST endpoints for Dashboard to trigger action rollbacks.
 func (o *Orchestrator) initializeHTTPServer() error {
 	log.Printf("Initializing HTTP server on port: %s", o.config.HTTPPort)
 
-	o.httpServer = httpserver.NewServer(o.detectionHandler)
+	if o.config.APIToken == "" {
+		slog.Warn("EXECUTOR_API_TOKEN not set - HTTP API accepts unauthenticated requests")
+	}
+	o.httpServer = httpserver.NewServer(o.detectionHandler, o.config.APIToken)
+	o.httpServer.SetConfigReloader(o)
+	if o.knowledgeClient != nil {
+		o.httpServer.SetThresholdStore(o.knowledgeClient)
+		o.httpServer.SetSuppressionStore(o.knowledgeClient)
+		o.httpServer.SetPolicyStore(o.knowledgeClient)
+	}
 
 	log.Printf("HTTP server initialized on port %s", o.config.HTTPPort)
 	return nil
@@ -173,17 +389,56 @@ func (o *Orchestrator) initializeGRPCServer() error {
 	}
 	o.grpcListener = listener
 
-	// Create gRPC server
-	o.grpcServer = grpc.NewServer()
+	// Create gRPC server (mutual TLS when GRPC_TLS_* is configured)
+	serverOptions, err := o.config.GRPCTLS.ServerOptions()
+	if err != nil {
+		return err
+	}
+	if o.config.GRPCTLS.Enabled() {
+		log.Printf("gRPC server requires mutual TLS")
+	}
+	o.grpcServer = grpc.NewServer(serverOptions...)
 
 	// Register executor service
-	executorServer := grpcserver.NewExecutorSever()
+	executorServer := grpcserver.NewExecutorSever(o.detectionHandler)
 	pb.RegisterExecutorServiceServer(o.grpcServer, executorServer)
 
 	log.Printf("gRPC server initialized on port %s", o.config.GRPCPort)
 	return nil
 }
 
+// startHealthServer serves /health with the state of NATS (required), Knowledge and Docker.
+func (o *Orchestrator) startHealthServer() error {
+	o.healthServer = health.NewServer("executor", o.config.HealthPort, metrics.Handler())
+
+	o.healthServer.AddCheck("nats", true, func(ctx context.Context) error {
+		if o.natsPublisher == nil || !o.natsPublisher.IsConnected() {
+			return fmt.Errorf("not connected")
+		}
+		return nil
+	})
+	o.healthServer.AddCheck("knowledge", false, func(ctx context.Context) error {
+		if o.knowledgeClient == nil {
+			return fmt.Errorf("not connected")
+		}
+		return o.knowledgeClient.Ping(ctx)
+	})
+	// Docker is only needed by deployment and restart actions. A check that finds the
+	// engine back (or gone) switches those actions between running and manual steps
+	o.healthServer.AddCheck("docker", false, func(ctx context.Context) error {
+		if o.dockerClient == nil {
+			o.dockerMu.Lock()
+			defer o.dockerMu.Unlock()
+			return o.dockerErr
+		}
+		err := o.dockerClient.IsAvailable(ctx)
+		o.setDockerAvailability(err)
+		return err
+	})
+
+	return o.healthServer.Start()
+}
+
 // Run starts all servers and blocks until the context is cancelled or an error occurs.
 // Actions are received from NATS, executed autonomously, and status is published back to NATS.
 func (o *Orchestrator) Run(ctx context.Context) error {
@@ -224,15 +479,50 @@ func (o *Orchestrator) Run(ctx context.Context) error {
 	}
 }
 
-// Stop gracefully closes all connections and releases resources.
-// This method should be called during application shutdown.
+// Stop shuts down in order, so nothing is half-done when the process exits:
+//  1. Stop intake: drain the NATS subscriptions and refuse HTTP changes
+//  2. Let running actions finish within SHUTDOWN_GRACE_PERIOD_SECONDS, cancelling the rest,
+//     with every final status pushed to Knowledge and NATS
+//  3. Stop the servers, then close connections
 func (o *Orchestrator) Stop() error {
 	log.Printf("Stopping Orchestrator...")
 
+	// Stop intake: detections still buffered go back to JetStream for the next Executor
+	if o.natsSubscriber != nil {
+		log.Printf("Draining NATS subscriptions...")
+		o.natsSubscriber.Drain(natsDrainTimeout)
+	}
+	if o.httpServer != nil {
+		o.httpServer.StopAcceptingChanges()
+	}
+
+	// Drain action queue - running actions get a grace period to finish
+	if o.detectionHandler != nil {
+		log.Printf("Draining action queue...")
+		o.configMu.Lock()
+		gracePeriod := time.Duration(o.config.ShutdownGracePeriod) * time.Second
+		o.configMu.Unlock()
+		o.detectionHandler.Shutdown(gracePeriod)
+	}
+
+	// Flush notifications for actions that finished while draining
+	if o.notifier != nil {
+		o.notifier.Close()
+	}
+
+	// Stop health check server
+	if o.healthServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), healthStopTimeout)
+		if err := o.healthServer.Shutdown(ctx); err != nil {
+			slog.Error("Error stopping health server", "error", err)
+		}
+		cancel()
+	}
+
 	// Stop HTTP server gracefully
 	if o.httpServer != nil {
 		if err := o.httpServer.Stop(); err != nil {
-			log.Printf("Error stopping HTTP server: %v", err)
+			slog.Error("Error stopping HTTP server", "error", err)
 		}
 	}
 
@@ -242,20 +532,19 @@ func (o *Orchestrator) Stop() error {
 		o.grpcServer.GracefulStop()
 	}
 
-	// Close NATS subscriber
-	if o.natsSubscriber != nil {
-		o.natsSubscriber.Close()
-	}
-
 	// Close NATS publisher
 	if o.natsPublisher != nil {
 		o.natsPublisher.Close()
 	}
 
+	if o.dockerClient != nil {
+		o.dockerClient.Close()
+	}
+
 	// Close Knowledge client
 	if o.knowledgeClient != nil {
 		if err := o.knowledgeClient.Close(); err != nil {
-			log.Printf("Error closing Knowledge client: %v", err)
+			slog.Error("Error closing Knowledge client", "error", err)
 		}
 	}
 
@@ -0,0 +1,75 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionLimiter_EnforcesCooldown(t *testing.T) {
+	limiter := handler.NewActionLimiter(2*time.Minute, 0)
+	now := time.Now()
+
+	wait, _ := limiter.Reserve("db1", now)
+	assert.Zero(t, wait)
+
+	wait, reason := limiter.Reserve("db1", now.Add(30*time.Second))
+	assert.Equal(t, 90*time.Second, wait)
+	assert.Contains(t, reason, "cooldown")
+
+	// Other databases are unaffected
+	wait, _ = limiter.Reserve("db2", now.Add(30*time.Second))
+	assert.Zero(t, wait)
+
+	wait, _ = limiter.Reserve("db1", now.Add(2*time.Minute))
+	assert.Zero(t, wait)
+}
+
+func TestActionLimiter_EnforcesHourlyCap(t *testing.T) {
+	limiter := handler.NewActionLimiter(0, 2)
+	now := time.Now()
+
+	wait, _ := limiter.Reserve("db1", now)
+	assert.Zero(t, wait)
+	wait, _ = limiter.Reserve("db1", now.Add(10*time.Minute))
+	assert.Zero(t, wait)
+
+	wait, reason := limiter.Reserve("db1", now.Add(20*time.Minute))
+	assert.Equal(t, 40*time.Minute, wait)
+	assert.Contains(t, reason, "per hour")
+
+	// The first action leaves the window after an hour
+	wait, _ = limiter.Reserve("db1", now.Add(time.Hour))
+	assert.Zero(t, wait)
+}
+
+func TestActionLimiter_SeededHistoryCounts(t *testing.T) {
+	limiter := handler.NewActionLimiter(2*time.Minute, 0)
+	now := time.Now()
+
+	// An action recorded in Knowledge before a restart still enforces the cooldown
+	limiter.Seed("db1", []time.Time{now.Add(-time.Minute)})
+
+	wait, _ := limiter.Reserve("db1", now)
+	assert.Equal(t, time.Minute, wait)
+}
+
+func TestActionLimiter_SetLimitsAppliesToRecordedStarts(t *testing.T) {
+	limiter := handler.NewActionLimiter(2*time.Minute, 0)
+	now := time.Now()
+
+	wait, _ := limiter.Reserve("db1", now)
+	assert.Zero(t, wait)
+
+	limiter.SetLimits(time.Minute, 0)
+
+	wait, _ = limiter.Reserve("db1", now.Add(30*time.Second))
+	assert.Equal(t, 30*time.Second, wait, "the shorter cooldown counts from the recorded start")
+
+	limiter.SetLimits(0, 0)
+
+	wait, _ = limiter.Reserve("db1", now.Add(30*time.Second))
+	assert.Zero(t, wait)
+}
@@ -0,0 +1,123 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/handler"
+	httpserver "github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/http"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// queueBehindBlocker occupies the only worker so later actions stay queued.
+func queueBehindBlocker(t *testing.T, h *handler.DetectionHandler) *blockingAction {
+	t.Helper()
+	var running, peak int32
+	blocker := newBlockingAction("blocker", &running, &peak)
+	h.ExecuteActionDirectly(blocker, &models.Detection{DetectionID: "det-blocker", Severity: "info"})
+	waitForStatus(t, h, "blocker", models.StatusExecuting)
+	return blocker
+}
+
+func TestActionQueue_CriticalJumpsQueue(t *testing.T) {
+	var running, peak int32
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+
+	blocker := queueBehindBlocker(t, h)
+
+	info := newBlockingAction("info-1", &running, &peak)
+	warning := newBlockingAction("warning-1", &running, &peak)
+	critical := newBlockingAction("critical-1", &running, &peak)
+	h.ExecuteActionDirectly(info, &models.Detection{DetectionID: "det-info", Severity: "info"})
+	h.ExecuteActionDirectly(warning, &models.Detection{DetectionID: "det-warning", Severity: "warning"})
+	h.ExecuteActionDirectly(critical, &models.Detection{DetectionID: "det-critical", Severity: "critical"})
+
+	queue := h.QueuedActions()
+	require.Len(t, queue, 3)
+	assert.Equal(t, "critical-1", queue[0].ActionID)
+	assert.Equal(t, "warning-1", queue[1].ActionID)
+	assert.Equal(t, "info-1", queue[2].ActionID)
+	assert.Greater(t, queue[0].Priority, queue[1].Priority)
+	assert.Equal(t, 1, queue[0].Position)
+
+	pending, err := h.ListPendingActions(models.StatusQueued)
+	require.NoError(t, err)
+	require.Len(t, pending, 3)
+	assert.Equal(t, "critical-1", pending[0].ActionID, "pending list should follow queue priority")
+	assert.Equal(t, queue[0].Priority, pending[0].Priority)
+
+	// The critical action is picked up next once the worker frees up
+	close(blocker.release)
+	waitForStatus(t, h, "critical-1", models.StatusExecuting)
+
+	for _, a := range []*blockingAction{critical, warning, info} {
+		close(a.release)
+	}
+	for _, a := range []*blockingAction{critical, warning, info} {
+		waitForStatus(t, h, a.id, models.StatusCompleted)
+	}
+}
+
+func TestActionQueue_EqualSeverityRunsOldestFirst(t *testing.T) {
+	var running, peak int32
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+
+	blocker := queueBehindBlocker(t, h)
+
+	first := newBlockingAction("warning-first", &running, &peak)
+	second := newBlockingAction("warning-second", &running, &peak)
+	h.ExecuteActionDirectly(first, &models.Detection{DetectionID: "det-first", Severity: "warning"})
+	h.ExecuteActionDirectly(second, &models.Detection{DetectionID: "det-second", Severity: "warning"})
+
+	queue := h.QueuedActions()
+	require.Len(t, queue, 2)
+	assert.Equal(t, "warning-first", queue[0].ActionID)
+	assert.Equal(t, "warning-second", queue[1].ActionID)
+
+	close(blocker.release)
+	close(first.release)
+	close(second.release)
+	waitForStatus(t, h, "warning-second", models.StatusCompleted)
+}
+
+func TestHTTPServer_ActionQueue(t *testing.T) {
+	var running, peak int32
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+
+	blocker := queueBehindBlocker(t, h)
+	defer close(blocker.release)
+
+	waiting := newBlockingAction("queued-critical", &running, &peak)
+	defer close(waiting.release)
+	h.ExecuteActionDirectly(waiting, &models.Detection{DetectionID: "det-queued", Severity: "critical"})
+
+	server := httpserver.NewServer(h, "").Handler()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/actions/queue", nil)
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		Queue []handler.QueueEntry `json:"queue"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	require.Len(t, body.Queue, 1)
+	assert.Equal(t, "queued-critical", body.Queue[0].ActionID)
+	assert.Equal(t, "critical", body.Queue[0].Severity)
+	assert.Equal(t, 1, body.Queue[0].Position)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/actions/queue", nil)
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -0,0 +1,224 @@
+package unit
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/handler"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingAction runs until released or its context is cancelled.
+type blockingAction struct {
+	id      string
+	release chan struct{}
+	running *int32
+	peak    *int32
+}
+
+func newBlockingAction(id string, running, peak *int32) *blockingAction {
+	return &blockingAction{id: id, release: make(chan struct{}), running: running, peak: peak}
+}
+
+func (a *blockingAction) Execute(ctx context.Context) (*models.ActionResult, error) {
+	n := atomic.AddInt32(a.running, 1)
+	defer atomic.AddInt32(a.running, -1)
+	for {
+		p := atomic.LoadInt32(a.peak)
+		if n <= p || atomic.CompareAndSwapInt32(a.peak, p, n) {
+			break
+		}
+	}
+
+	select {
+	case <-a.release:
+		return &models.ActionResult{ActionID: a.id, ActionType: "test", Status: models.StatusCompleted}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (a *blockingAction) Rollback(ctx context.Context) error { return nil }
+func (a *blockingAction) Validate(ctx context.Context) error { return nil }
+func (a *blockingAction) GetMetadata() *models.ActionMetadata {
+	return &models.ActionMetadata{ActionID: a.id, ActionType: "test", DatabaseID: "test-db", CreatedAt: time.Now()}
+}
+
+func waitForStatus(t *testing.T, h *handler.DetectionHandler, actionID, status string) *models.ActionResult {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if result, err := h.GetActionStatus(actionID); err == nil && result.Status == status {
+			return result
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("action %s did not reach status %s", actionID, status)
+	return nil
+}
+
+func TestActionQueue_LimitsConcurrency(t *testing.T) {
+	var running, peak int32
+	h := handler.NewDetectionHandler(nil, nil, 2, time.Minute)
+	defer h.Shutdown(time.Second)
+
+	var queued []*blockingAction
+	for _, id := range []string{"a1", "a2", "a3", "a4"} {
+		a := newBlockingAction(id, &running, &peak)
+		queued = append(queued, a)
+		h.ExecuteActionDirectly(a, &models.Detection{DetectionID: "det-" + id})
+	}
+
+	waitForStatus(t, h, "a1", models.StatusExecuting)
+	waitForStatus(t, h, "a2", models.StatusExecuting)
+
+	waiting := waitForStatus(t, h, "a4", models.StatusQueued)
+	assert.Equal(t, 2, waiting.QueuePosition, "a4 should be second in the queue")
+
+	pending, err := h.ListPendingActions(models.StatusQueued)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 2)
+	assert.Equal(t, "a3", pending[0].ActionID, "queued actions should be listed in execution order")
+
+	for _, a := range queued {
+		close(a.release)
+	}
+	for _, a := range queued {
+		waitForStatus(t, h, a.id, models.StatusCompleted)
+	}
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&peak), "no more than 2 actions should run at once")
+}
+
+func TestActionQueue_TimeoutMarksFailed(t *testing.T) {
+	var running, peak int32
+	h := handler.NewDetectionHandler(nil, nil, 1, 50*time.Millisecond)
+	defer h.Shutdown(time.Second)
+	h.SetRetryPolicy(handler.RetryPolicy{MaxAttempts: 1})
+
+	a := newBlockingAction("hung", &running, &peak)
+	h.ExecuteActionDirectly(a, &models.Detection{DetectionID: "det-hung"})
+
+	result := waitForStatus(t, h, "hung", models.StatusFailed)
+	assert.Contains(t, result.Error, "timed out")
+	assert.Equal(t, models.ErrorCodeTimeout, result.ErrorCode)
+}
+
+func TestActionQueue_ShutdownRejectsNewAndFailsQueued(t *testing.T) {
+	var running, peak int32
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+
+	first := newBlockingAction("first", &running, &peak)
+	second := newBlockingAction("second", &running, &peak)
+	h.ExecuteActionDirectly(first, &models.Detection{DetectionID: "det-first"})
+	h.ExecuteActionDirectly(second, &models.Detection{DetectionID: "det-second"})
+	waitForStatus(t, h, "first", models.StatusExecuting)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(first.release)
+	}()
+	h.Shutdown(time.Second)
+
+	assert.Equal(t, models.StatusCompleted, waitForStatus(t, h, "first", models.StatusCompleted).Status,
+		"running action should finish within the grace period")
+	assert.Equal(t, models.StatusFailed, waitForStatus(t, h, "second", models.StatusFailed).Status,
+		"queued action should be failed on shutdown")
+
+	late := newBlockingAction("late", &running, &peak)
+	h.ExecuteActionDirectly(late, &models.Detection{DetectionID: "det-late"})
+	assert.Equal(t, models.StatusFailed, waitForStatus(t, h, "late", models.StatusFailed).Status)
+}
+
+func TestActionQueue_ShutdownCancelsAfterGracePeriod(t *testing.T) {
+	var running, peak int32
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+
+	stuck := newBlockingAction("stuck", &running, &peak)
+	h.ExecuteActionDirectly(stuck, &models.Detection{DetectionID: "det-stuck"})
+	waitForStatus(t, h, "stuck", models.StatusExecuting)
+
+	h.Shutdown(20 * time.Millisecond)
+
+	result := waitForStatus(t, h, "stuck", models.StatusFailed)
+	assert.Contains(t, result.Message, "shutdown")
+}
+
+func TestActionQueue_SetMaxConcurrentResizesPool(t *testing.T) {
+	var running, peak int32
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+
+	var first []*blockingAction
+	for _, id := range []string{"g1", "g2", "g3"} {
+		a := newBlockingAction(id, &running, &peak)
+		first = append(first, a)
+		h.ExecuteActionDirectly(a, &models.Detection{DetectionID: "det-" + id})
+	}
+
+	waitForStatus(t, h, "g1", models.StatusExecuting)
+	waitForStatus(t, h, "g3", models.StatusQueued)
+
+	// Growing starts workers for the waiting actions straight away
+	h.SetMaxConcurrent(3)
+	assert.Equal(t, 3, h.MaxConcurrent())
+	waitForStatus(t, h, "g2", models.StatusExecuting)
+	waitForStatus(t, h, "g3", models.StatusExecuting)
+
+	// Shrinking lets running actions finish rather than interrupting them
+	h.SetMaxConcurrent(1)
+	for _, a := range first {
+		close(a.release)
+	}
+	for _, a := range first {
+		waitForStatus(t, h, a.id, models.StatusCompleted)
+	}
+
+	var running2, peak2 int32
+	var second []*blockingAction
+	for _, id := range []string{"s1", "s2", "s3"} {
+		a := newBlockingAction(id, &running2, &peak2)
+		second = append(second, a)
+		h.ExecuteActionDirectly(a, &models.Detection{DetectionID: "det-" + id})
+	}
+
+	waitForStatus(t, h, "s1", models.StatusExecuting)
+	waitForStatus(t, h, "s3", models.StatusQueued)
+
+	for _, a := range second {
+		close(a.release)
+	}
+	for _, a := range second {
+		waitForStatus(t, h, a.id, models.StatusCompleted)
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&peak2), "only one action should run after shrinking the pool")
+}
+
+func TestActionQueue_SetActionTimeoutAppliesToNewActions(t *testing.T) {
+	var running, peak int32
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+	h.SetRetryPolicy(handler.RetryPolicy{MaxAttempts: 1})
+
+	h.SetActionTimeout(50 * time.Millisecond)
+	assert.Equal(t, 50*time.Millisecond, h.ActionTimeout())
+
+	a := newBlockingAction("short", &running, &peak)
+	h.ExecuteActionDirectly(a, &models.Detection{DetectionID: "det-short"})
+
+	result := waitForStatus(t, h, "short", models.StatusFailed)
+	assert.Contains(t, result.Error, "timed out after 50ms")
+}
+
+func TestDetectionHandler_RefusesDetectionsAfterShutdown(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	h.Shutdown(time.Second)
+
+	result, err := h.HandleDetection(&models.Detection{DetectionID: "det-after", DatabaseID: "test-db", ActionType: "create_index"})
+	assert.ErrorIs(t, err, handler.ErrShuttingDown)
+	assert.Nil(t, result)
+}
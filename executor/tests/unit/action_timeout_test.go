@@ -0,0 +1,120 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/actions"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/database"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/docker"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/handler"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockUntilDone stands in for a database or Docker call that hangs until its ctx ends,
+// closing returned once it gives up.
+func blockUntilDone(returned chan struct{}) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		defer close(returned)
+		<-ctx.Done()
+		return ctx.Err()
+	}
+}
+
+// assertReturned fails unless the blocked call gave up, i.e. no goroutine was left behind.
+func assertReturned(t *testing.T, returned chan struct{}) {
+	t.Helper()
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("blocked call never saw its context end")
+	}
+}
+
+func TestActionTimeout_SlowAdapterFailsCleanly(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, 50*time.Millisecond)
+	defer h.Shutdown(time.Second)
+	h.SetRetryPolicy(handler.RetryPolicy{MaxAttempts: 1})
+
+	returned := make(chan struct{})
+	block := blockUntilDone(returned)
+	mock := &MockDatabaseAdapter{
+		Capabilities:    database.Capabilities{SupportsIndexes: true, SupportsConcurrentIndexes: true},
+		CreateIndexFunc: func(ctx context.Context, params database.IndexParams) error { return block(ctx) },
+	}
+	metadata := &models.ActionMetadata{ActionID: "slow-index", ActionType: "create_index", DatabaseID: "test-db", CreatedAt: time.Now()}
+	action := actions.NewCreateIndexAction(metadata, mock, "posts", []string{"user_id"}, false)
+
+	h.ExecuteActionDirectly(action, &models.Detection{DetectionID: "det-slow-index"})
+
+	result := waitForStatus(t, h, "slow-index", models.StatusFailed)
+	assert.Equal(t, "Action timed out", result.Message)
+	assert.Contains(t, result.Error, "timed out after 50ms")
+	assert.Equal(t, models.ErrorCodeTimeout, result.ErrorCode)
+	assertReturned(t, returned)
+}
+
+func TestActionTimeout_SlowImagePullFailsCleanly(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, 50*time.Millisecond)
+	defer h.Shutdown(time.Second)
+	h.SetRetryPolicy(handler.RetryPolicy{MaxAttempts: 1})
+
+	returned := make(chan struct{})
+	runtime := &fakeDockerRuntime{pullFunc: blockUntilDone(returned)}
+	action, _ := newRedisAction(runtime, func(ctx context.Context) (string, error) {
+		return "PONG", nil
+	})
+
+	h.ExecuteActionDirectly(action, &models.Detection{DetectionID: "det-slow-pull"})
+
+	result := waitForStatus(t, h, "action-redis-1", models.StatusFailed)
+	assert.Contains(t, result.Error, "timed out")
+	assert.Nil(t, runtime.created, "no container should be created after the pull timed out")
+	assertReturned(t, returned)
+}
+
+func TestActionTimeout_CleanupOutlivesDeadline(t *testing.T) {
+	runtime := &fakeDockerRuntime{}
+	action, _ := newRedisAction(runtime, func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+	action.SetProbeSettings(docker.ProbeSettings{Host: "localhost", Timeout: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	result, err := action.Execute(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, models.StatusFailed, result.Status)
+	assert.NotEmpty(t, runtime.removedID, "container should be removed even though the action's deadline passed")
+}
+
+// slowRollbackAction completes immediately but its rollback hangs until ctx ends.
+type slowRollbackAction struct {
+	rollbackableAction
+	returned chan struct{}
+}
+
+func (a *slowRollbackAction) Rollback(ctx context.Context) error {
+	return blockUntilDone(a.returned)(ctx)
+}
+
+func TestActionTimeout_RollbackTimesOut(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, 50*time.Millisecond)
+	defer h.Shutdown(time.Second)
+
+	action := &slowRollbackAction{rollbackableAction: rollbackableAction{id: "slow-rb"}, returned: make(chan struct{})}
+	h.ExecuteActionDirectly(action, &models.Detection{DetectionID: "det-slow-rb"})
+	waitForStatus(t, h, "slow-rb", models.StatusCompleted)
+
+	result, err := h.RollbackAction("slow-rb")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rollback timed out after 50ms")
+	assert.Equal(t, models.StatusRollbackFailed, result.Status)
+	assertReturned(t, action.returned)
+}
@@ -0,0 +1,121 @@
+package unit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/database"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/handler"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/knowledge"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// fakeKnowledge registers every database as the given type; other calls fall through
+// to the unimplemented server, which the handler treats as non-fatal.
+type fakeKnowledge struct {
+	pb.UnimplementedKnowledgeServiceServer
+	databaseType string
+}
+
+func (f *fakeKnowledge) GetDatabase(ctx context.Context, req *pb.GetDatabaseRequest) (*pb.GetDatabaseResponse, error) {
+	return &pb.GetDatabaseResponse{
+		Found:            true,
+		DatabaseId:       req.DatabaseId,
+		DatabaseType:     f.databaseType,
+		ConnectionString: "postgres://test@localhost/" + req.DatabaseId,
+	}, nil
+}
+
+func startFakeKnowledge(t *testing.T, databaseType string) *knowledge.Client {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	pb.RegisterKnowledgeServiceServer(server, &fakeKnowledge{databaseType: databaseType})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	client, err := knowledge.NewClient(listener.Addr().String(), transport.TLSConfig{})
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+// countingFactory records every adapter it opens.
+type countingFactory struct {
+	mu       sync.Mutex
+	opened   []*MockDatabaseAdapter
+	lastType string
+}
+
+func (f *countingFactory) open(ctx context.Context, databaseType, connectionString, databaseID string) (database.DatabaseAdapter, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	adapter := &MockDatabaseAdapter{Capabilities: database.Capabilities{SupportsVacuum: true}}
+	f.opened = append(f.opened, adapter)
+	f.lastType = databaseType
+	return adapter, nil
+}
+
+func TestDetectionHandler_SharesOneAdapterPerDatabase(t *testing.T) {
+	factory := &countingFactory{}
+	h := handler.NewDetectionHandler(nil, startFakeKnowledge(t, "postgresql"), 1, time.Minute)
+	h.SetAdapterFactory(factory.open)
+
+	for i := 0; i < 50; i++ {
+		_, err := h.HandleDetection(&models.Detection{
+			DetectionID:    fmt.Sprintf("det-%d", i),
+			ActionType:     "vacuum_table",
+			DatabaseID:     "test-db",
+			ActionMetaData: map[string]interface{}{"table_name": "orders"},
+		})
+		require.NoError(t, err)
+	}
+
+	h.Shutdown(5 * time.Second)
+
+	require.Len(t, factory.opened, 1, "every action against the same database should share one adapter")
+	assert.Equal(t, "postgresql", factory.lastType, "database type should come from Knowledge")
+	assert.True(t, factory.opened[0].VacuumCalled)
+	assert.Equal(t, 1, factory.opened[0].CloseCalls, "adapter should be closed once on shutdown")
+}
+
+func TestAdapterCache_ReplacesAdapterWhenConnectionChanges(t *testing.T) {
+	factory := &countingFactory{}
+	cache := database.NewAdapterCache(factory.open)
+	ctx := context.Background()
+
+	first, err := cache.Get(ctx, "postgres", "postgres://a", "db-1")
+	require.NoError(t, err)
+	again, err := cache.Get(ctx, "postgres", "postgres://a", "db-1")
+	require.NoError(t, err)
+	assert.Same(t, first, again)
+
+	other, err := cache.Get(ctx, "postgres", "postgres://a", "db-2")
+	require.NoError(t, err)
+	assert.NotSame(t, first, other, "each database gets its own adapter")
+
+	moved, err := cache.Get(ctx, "postgres", "postgres://b", "db-1")
+	require.NoError(t, err)
+	assert.NotSame(t, first, moved)
+
+	cache.Close()
+	assert.Equal(t, 1, factory.opened[1].CloseCalls)
+	assert.Equal(t, 1, factory.opened[2].CloseCalls)
+
+	_, err = cache.Get(ctx, "postgres", "postgres://a", "db-1")
+	assert.Error(t, err, "closed cache should not open new adapters")
+}
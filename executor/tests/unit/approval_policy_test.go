@@ -0,0 +1,72 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/handler"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApprovalPolicy_AutoExecutionDisabledHoldsActions(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+
+	h.SetApprovalPolicy(false, nil)
+
+	result, err := h.HandleDetection(&models.Detection{
+		DetectionID: "det-approval-1",
+		ActionType:  "optimise_queries",
+		DatabaseID:  "test-db",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPendingApproval, result.Status)
+
+	// Nothing runs until it is approved
+	time.Sleep(20 * time.Millisecond)
+	status, err := h.GetActionStatus(result.ActionID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPendingApproval, status.Status)
+
+	approved, err := h.ApproveAction(result.ActionID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusQueued, approved.Status)
+
+	waitForStatus(t, h, result.ActionID, models.StatusPendingImplementation)
+}
+
+func TestApprovalPolicy_OverrideAutoRunsAction(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+
+	h.SetApprovalPolicy(false, map[string]string{"investigate_replication_lag": models.ApprovalAuto})
+
+	result, err := h.HandleDetection(&models.Detection{
+		DetectionID: "det-approval-2",
+		ActionType:  "investigate_replication_lag",
+		DatabaseID:  "test-db",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusQueued, result.Status)
+}
+
+func TestApprovalPolicy_OverrideRequiresApproval(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+
+	h.SetApprovalPolicy(true, map[string]string{"optimise_queries": models.ApprovalRequired})
+
+	result, err := h.HandleDetection(&models.Detection{
+		DetectionID: "det-approval-3",
+		ActionType:  "optimise_queries",
+		DatabaseID:  "test-db",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPendingApproval, result.Status)
+
+	rejected, err := h.RejectAction(result.ActionID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRejected, rejected.Status)
+}
@@ -0,0 +1,92 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func changedSettings(changes []config.Change) []string {
+	var settings []string
+	for _, change := range changes {
+		settings = append(settings, change.Setting)
+	}
+	return settings
+}
+
+func TestConfigReload_AppliesRuntimeSettingsAndRejectsPorts(t *testing.T) {
+	t.Setenv("MAX_CONCURRENT_ACTIONS", "2")
+	t.Setenv("ACTION_COOLDOWN_SECONDS", "120")
+	t.Setenv("ENABLE_AUTO_EXECUTION", "true")
+	t.Setenv("HTTP_PORT", "8084")
+
+	current, err := config.Load()
+	require.NoError(t, err)
+
+	t.Setenv("MAX_CONCURRENT_ACTIONS", "5")
+	t.Setenv("ACTION_COOLDOWN_SECONDS", "30")
+	t.Setenv("ENABLE_AUTO_EXECUTION", "false")
+	t.Setenv("HTTP_PORT", "9999")
+
+	updated, result, err := config.Reload(current)
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, updated.MaxConcurrentActions)
+	assert.Equal(t, 30, updated.ActionCooldown)
+	assert.False(t, updated.EnableAutoExecution)
+	assert.Equal(t, "8084", updated.HTTPPort, "port changes need a restart")
+
+	assert.ElementsMatch(t, []string{"MaxConcurrentActions", "ActionCooldown", "EnableAutoExecution"}, changedSettings(result.Applied))
+	assert.Equal(t, []string{"HTTPPort"}, changedSettings(result.Rejected))
+	assert.Equal(t, "8084", result.Rejected[0].Old)
+	assert.Equal(t, "9999", result.Rejected[0].New)
+
+	assert.Equal(t, 2, current.MaxConcurrentActions, "the current config is not modified")
+}
+
+func TestConfigReload_InvalidConfigIsRejected(t *testing.T) {
+	t.Setenv("MAX_CONCURRENT_ACTIONS", "2")
+
+	current, err := config.Load()
+	require.NoError(t, err)
+
+	t.Setenv("MAX_CONCURRENT_ACTIONS", "0")
+
+	_, _, err = config.Reload(current)
+	assert.Error(t, err)
+}
+
+func TestConfigDiff_RedactsSecrets(t *testing.T) {
+	t.Setenv("EXECUTOR_API_TOKEN", "old-token")
+
+	current, err := config.Load()
+	require.NoError(t, err)
+
+	t.Setenv("EXECUTOR_API_TOKEN", "new-token")
+
+	_, result, err := config.Reload(current)
+	require.NoError(t, err)
+
+	require.Len(t, result.Rejected, 1)
+	assert.Equal(t, "APIToken", result.Rejected[0].Setting)
+	assert.NotContains(t, result.Rejected[0].String(), "token")
+}
+
+func TestConfig_RetrySettings(t *testing.T) {
+	current, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 3, current.ActionMaxAttempts)
+	assert.Equal(t, 30, current.ActionRetryBackoff)
+
+	t.Setenv("ACTION_MAX_ATTEMPTS", "1")
+	updated, result, err := config.Reload(current)
+	require.NoError(t, err)
+	assert.Equal(t, 1, updated.ActionMaxAttempts)
+	assert.Equal(t, []string{"ActionMaxAttempts"}, changedSettings(result.Applied))
+
+	t.Setenv("ACTION_MAX_ATTEMPTS", "0")
+	_, _, err = config.Reload(current)
+	assert.ErrorContains(t, err, "ACTION_MAX_ATTEMPTS")
+}
@@ -0,0 +1,151 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/config"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/docker"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/handler"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deployedContainerAction completes like a deploy action, recording the container it started.
+type deployedContainerAction struct {
+	id        string
+	rollbacks int
+}
+
+func (a *deployedContainerAction) Execute(ctx context.Context) (*models.ActionResult, error) {
+	return &models.ActionResult{
+		ActionID:    a.id,
+		ActionType:  "deploy_redis",
+		DatabaseID:  "test-db",
+		Status:      models.StatusCompleted,
+		CanRollback: true,
+		Changes: map[string]interface{}{
+			"container_id":   "c0ffee0000000000",
+			"container_name": "startupmonkey-redis",
+		},
+	}, nil
+}
+
+func (a *deployedContainerAction) Rollback(ctx context.Context) error {
+	a.rollbacks++
+	return nil
+}
+
+func (a *deployedContainerAction) Validate(ctx context.Context) error { return nil }
+func (a *deployedContainerAction) GetMetadata() *models.ActionMetadata {
+	return &models.ActionMetadata{ActionID: a.id, ActionType: "deploy_redis", DatabaseID: "test-db", CreatedAt: time.Now()}
+}
+
+func newWatchedHandler(t *testing.T, runtime *fakeDockerRuntime, settings handler.WatchdogSettings) (*handler.DetectionHandler, *deployedContainerAction) {
+	t.Helper()
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	t.Cleanup(func() { h.Shutdown(time.Second) })
+	h.SetDocker(runtime, nil)
+	h.SetWatchdogSettings(settings)
+
+	action := &deployedContainerAction{id: "redis-watched"}
+	h.ExecuteActionDirectly(action, &models.Detection{DetectionID: "det-redis-watched"})
+	waitForStatus(t, h, action.id, models.StatusCompleted)
+	return h, action
+}
+
+func TestContainerWatchdog_RestartLoopRollsBack(t *testing.T) {
+	runtime := &fakeDockerRuntime{state: &docker.ContainerState{Running: true, RestartCount: 2}}
+	h, action := newWatchedHandler(t, runtime, handler.DefaultWatchdogSettings())
+
+	// Restarts before the first check are the baseline, not a regression
+	assert.Empty(t, h.CheckDeployedContainers(context.Background()))
+
+	runtime.state = &docker.ContainerState{Restarting: true, RestartCount: 4, OOMKilled: true}
+	assert.Empty(t, h.CheckDeployedContainers(context.Background()), "two restarts are below the threshold")
+
+	runtime.state = &docker.ContainerState{Restarting: true, RestartCount: 5, OOMKilled: true}
+	assert.Equal(t, []string{action.id}, h.CheckDeployedContainers(context.Background()))
+
+	result := waitForStatus(t, h, action.id, models.StatusRolledBack)
+	assert.Equal(t, 1, action.rollbacks)
+	assert.Equal(t, 5, result.Changes["restart_count"])
+	assert.Equal(t, true, result.Changes["oom_killed"])
+
+	assert.Empty(t, h.CheckDeployedContainers(context.Background()), "rolled back actions are no longer watched")
+}
+
+func TestContainerWatchdog_RegressedWithoutRollback(t *testing.T) {
+	runtime := &fakeDockerRuntime{state: &docker.ContainerState{Running: true, RestartCount: 7}}
+	h, action := newWatchedHandler(t, runtime, handler.WatchdogSettings{RestartThreshold: 1, Rollback: false})
+	assert.Empty(t, h.CheckDeployedContainers(context.Background()))
+
+	// A lower count means the container was recreated, so it becomes the new baseline
+	runtime.state = &docker.ContainerState{Running: true, RestartCount: 0}
+	assert.Empty(t, h.CheckDeployedContainers(context.Background()))
+
+	runtime.state = &docker.ContainerState{Restarting: true, RestartCount: 1}
+	assert.Equal(t, []string{action.id}, h.CheckDeployedContainers(context.Background()))
+
+	result, err := h.GetActionStatus(action.id)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRegressed, result.Status)
+	assert.Contains(t, result.Message, "restarted 1 times")
+	assert.Zero(t, action.rollbacks)
+
+	// The operator can still roll a regressed action back
+	rolledBack, err := h.RollbackAction(action.id)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRolledBack, rolledBack.Status)
+}
+
+func TestContainerWatchdog_IdleWithoutDocker(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+	seedCompletedActions(t, h, "not-a-container")
+
+	assert.Empty(t, h.CheckDeployedContainers(context.Background()))
+}
+
+func TestParseResourceLimits(t *testing.T) {
+	limits, err := docker.ParseResourceLimits("512m", "0.5")
+	require.NoError(t, err)
+	assert.Equal(t, int64(512*1024*1024), limits.MemoryBytes)
+	assert.Equal(t, int64(500_000_000), limits.NanoCPUs)
+	assert.Equal(t, "memory=512MiB cpus=0.5", limits.String())
+
+	unlimited, err := docker.ParseResourceLimits("", "0")
+	require.NoError(t, err)
+	assert.Zero(t, unlimited.Resources().Memory)
+	assert.Empty(t, unlimited.Changes())
+
+	_, err = docker.ParseResourceLimits("lots", "1")
+	assert.Error(t, err)
+	_, err = docker.ParseResourceLimits("1g", "-2")
+	assert.Error(t, err)
+}
+
+func TestConfig_ContainerLimitsAndWatchdog(t *testing.T) {
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, int64(512*1024*1024), cfg.RedisLimits().MemoryBytes)
+	assert.Equal(t, int64(5e8), cfg.PgBouncerLimits().NanoCPUs)
+	assert.Equal(t, 30, cfg.ContainerWatchdogInterval)
+	assert.True(t, cfg.ContainerWatchdogRollback)
+
+	t.Setenv("REDIS_MEMORY_LIMIT", "0")
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.Zero(t, cfg.RedisLimits().MemoryBytes, "0 leaves memory unlimited")
+
+	t.Setenv("PGBOUNCER_CPU_LIMIT", "half")
+	_, err = config.Load()
+	assert.ErrorContains(t, err, "PGBOUNCER_CPU_LIMIT")
+
+	t.Setenv("PGBOUNCER_CPU_LIMIT", "")
+	t.Setenv("CONTAINER_RESTART_THRESHOLD", "0")
+	_, err = config.Load()
+	assert.ErrorContains(t, err, "CONTAINER_RESTART_THRESHOLD")
+}
@@ -3,6 +3,7 @@ package unit
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -196,6 +197,50 @@ func TestCreateIndexAction_ValidateIndexExistsError(t *testing.T) {
 	assert.Contains(t, err.Error(), "connection failed")
 }
 
+func TestCreateIndexAction_ValidateTableMissing(t *testing.T) {
+	mock := &MockDatabaseAdapter{
+		Capabilities: database.Capabilities{SupportsIndexes: true},
+		TableMissing: true,
+	}
+
+	metadata := &models.ActionMetadata{
+		ActionID:   "test-action-8a",
+		ActionType: "create_index",
+		DatabaseID: "test-db",
+		CreatedAt:  time.Now(),
+	}
+
+	action := actions.NewCreateIndexAction(metadata, mock, "orderz", []string{"user_id"}, false)
+
+	err := action.Validate(context.Background())
+
+	assert.Error(t, err)
+	assert.Equal(t, "table 'orderz' does not exist", err.Error())
+}
+
+func TestCreateIndexAction_ValidateColumnMissing(t *testing.T) {
+	mock := &MockDatabaseAdapter{
+		Capabilities:   database.Capabilities{SupportsIndexes: true},
+		MissingColumns: map[string]bool{"limit": true},
+	}
+
+	metadata := &models.ActionMetadata{
+		ActionID:   "test-action-8b",
+		ActionType: "create_index",
+		DatabaseID: "test-db",
+		CreatedAt:  time.Now(),
+	}
+
+	action := actions.NewCreateIndexAction(metadata, mock, "orders", []string{"limit"}, false)
+
+	result, err := action.Execute(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusFailed, result.Status)
+	assert.Equal(t, "column 'limit' does not exist on table orders", result.Error)
+	assert.False(t, mock.CreateIndexCalled)
+}
+
 func TestCreateIndexAction_RollbackSuccess(t *testing.T) {
 	mock := &MockDatabaseAdapter{
 		Capabilities: database.Capabilities{SupportsIndexes: true},
@@ -365,3 +410,192 @@ func TestCreateIndexAction_ConcurrentFlagNotSet(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, false, result.Changes["concurrent"])
 }
+
+func TestCreateIndexAction_RestoreThenRollback(t *testing.T) {
+	mock := &MockDatabaseAdapter{
+		Capabilities:     database.Capabilities{SupportsIndexes: true},
+		IndexExistsValue: true,
+	}
+
+	metadata := &models.ActionMetadata{
+		ActionID:   "test-action-restore",
+		ActionType: "create_index",
+		DatabaseID: "test-db",
+		CreatedAt:  time.Now(),
+	}
+
+	// Fresh object, as rebuilt after an Executor restart
+	action := actions.NewCreateIndexAction(metadata, mock, "posts", []string{"user_id"}, false)
+
+	restored, err := action.Restore(context.Background(), map[string]interface{}{
+		"index_name": "idx_test-db_posts_user_id",
+		"table_name": "posts",
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, restored)
+
+	err = action.Rollback(context.Background())
+
+	assert.NoError(t, err)
+	assert.True(t, mock.DropIndexCalled)
+}
+
+func TestCreateIndexAction_RestoreIndexGone(t *testing.T) {
+	mock := &MockDatabaseAdapter{
+		Capabilities:     database.Capabilities{SupportsIndexes: true},
+		IndexExistsValue: false,
+	}
+
+	metadata := &models.ActionMetadata{
+		ActionID:   "test-action-restore-gone",
+		ActionType: "create_index",
+		DatabaseID: "test-db",
+		CreatedAt:  time.Now(),
+	}
+
+	action := actions.NewCreateIndexAction(metadata, mock, "posts", []string{"user_id"}, false)
+
+	restored, err := action.Restore(context.Background(), map[string]interface{}{
+		"index_name": "idx_test-db_posts_user_id",
+	})
+
+	assert.NoError(t, err)
+	assert.False(t, restored, "Restore should report a dropped index as gone")
+}
+
+func TestCreateIndexAction_RestoreMissingIndexName(t *testing.T) {
+	mock := &MockDatabaseAdapter{
+		Capabilities: database.Capabilities{SupportsIndexes: true},
+	}
+
+	metadata := &models.ActionMetadata{
+		ActionID:   "test-action-restore-missing",
+		ActionType: "create_index",
+		DatabaseID: "test-db",
+		CreatedAt:  time.Now(),
+	}
+
+	action := actions.NewCreateIndexAction(metadata, mock, "posts", []string{"user_id"}, false)
+
+	_, err := action.Restore(context.Background(), map[string]interface{}{})
+
+	assert.Error(t, err)
+}
+
+// progressMockAdapter reports index build progress while CreateIndex blocks.
+type progressMockAdapter struct {
+	*MockDatabaseAdapter
+	buildTime time.Duration
+	polls     int32
+}
+
+func (m *progressMockAdapter) CreateIndex(ctx context.Context, params database.IndexParams) error {
+	select {
+	case <-time.After(m.buildTime):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return m.MockDatabaseAdapter.CreateIndex(ctx, params)
+}
+
+func (m *progressMockAdapter) GetIndexProgress(ctx context.Context, tableName string) (*database.IndexProgress, error) {
+	n := atomic.AddInt32(&m.polls, 1)
+	return &database.IndexProgress{Phase: "building index", BlocksDone: int64(n), BlocksTotal: 10}, nil
+}
+
+func (m *progressMockAdapter) GetIndexSize(ctx context.Context, indexName string) (int64, error) {
+	return 8192, nil
+}
+
+func TestCreateIndexAction_ReportsProgress(t *testing.T) {
+	mock := &progressMockAdapter{
+		MockDatabaseAdapter: &MockDatabaseAdapter{
+			Capabilities: database.Capabilities{SupportsIndexes: true, SupportsConcurrentIndexes: true},
+		},
+		buildTime: 50 * time.Millisecond,
+	}
+
+	metadata := &models.ActionMetadata{
+		ActionID:   "test-action-progress",
+		ActionType: "create_index",
+		DatabaseID: "test-db",
+		CreatedAt:  time.Now(),
+	}
+
+	action := actions.NewCreateIndexAction(metadata, mock, "orders", []string{"customer_id"}, false)
+	action.SetProgressInterval(5 * time.Millisecond)
+
+	var reports int32
+	action.SetProgressFunc(func(progress *models.ActionProgress) {
+		atomic.AddInt32(&reports, 1)
+		assert.Equal(t, "building index", progress.Phase)
+		assert.Equal(t, int64(10), progress.BlocksTotal)
+	})
+
+	result, err := action.Execute(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, result.Status)
+	assert.Greater(t, atomic.LoadInt32(&reports), int32(0))
+	assert.Equal(t, int64(8192), result.Changes["index_size_bytes"])
+	assert.Contains(t, result.Changes, "duration_ms")
+
+	// No progress is reported once Execute has returned
+	after := atomic.LoadInt32(&reports)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, after, atomic.LoadInt32(&reports))
+}
+
+func TestCreateIndexAction_ProgressStopsOnCancel(t *testing.T) {
+	mock := &progressMockAdapter{
+		MockDatabaseAdapter: &MockDatabaseAdapter{
+			Capabilities: database.Capabilities{SupportsIndexes: true},
+		},
+		buildTime: time.Minute,
+	}
+
+	metadata := &models.ActionMetadata{
+		ActionID:   "test-action-progress-cancel",
+		ActionType: "create_index",
+		DatabaseID: "test-db",
+		CreatedAt:  time.Now(),
+	}
+
+	action := actions.NewCreateIndexAction(metadata, mock, "orders", []string{"customer_id"}, false)
+	action.SetProgressInterval(5 * time.Millisecond)
+	action.SetProgressFunc(func(progress *models.ActionProgress) {})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	result, err := action.Execute(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusFailed, result.Status)
+}
+
+func TestCreateIndexAction_MultiColumnIndexName(t *testing.T) {
+	mock := &MockDatabaseAdapter{
+		Capabilities: database.Capabilities{
+			SupportsIndexes:          true,
+			SupportsMultiColumnIndex: true,
+		},
+	}
+
+	metadata := &models.ActionMetadata{
+		ActionID:   "test-action-multi",
+		ActionType: "create_index",
+		DatabaseID: "test-db",
+		CreatedAt:  time.Now(),
+	}
+
+	action := actions.NewCreateIndexAction(metadata, mock, "orders", []string{"customer_id", "created_at"}, false)
+
+	result, err := action.Execute(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, result.Status)
+	assert.Equal(t, "idx_test-db_orders_customer_id_created_at", result.Changes["index_name"])
+	assert.Equal(t, []string{"customer_id", "created_at"}, result.Changes["column_names"])
+}
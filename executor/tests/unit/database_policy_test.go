@@ -0,0 +1,160 @@
+package unit
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/handler"
+	httpserver "github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/http"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/knowledge"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/policy"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// policyKnowledge stores database policies in memory.
+type policyKnowledge struct {
+	pb.UnimplementedKnowledgeServiceServer
+	mu       sync.Mutex
+	policies map[string]*pb.DatabasePolicy
+}
+
+func (f *policyKnowledge) GetDatabasePolicy(ctx context.Context, req *pb.GetDatabasePolicyRequest) (*pb.DatabasePolicy, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if p, ok := f.policies[req.DatabaseId]; ok {
+		return p, nil
+	}
+	return &pb.DatabasePolicy{DatabaseId: req.DatabaseId}, nil
+}
+
+func (f *policyKnowledge) SetDatabasePolicy(ctx context.Context, req *pb.SetDatabasePolicyRequest) (*pb.Response, error) {
+	if err := policy.Normalise(req.Policy); err != nil {
+		return &pb.Response{Success: false, Message: err.Error()}, nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.policies[req.Policy.DatabaseId] = req.Policy
+	return &pb.Response{Success: true}, nil
+}
+
+func newPolicyHandler(t *testing.T, policies ...*pb.DatabasePolicy) (*handler.DetectionHandler, *knowledge.Client) {
+	fake := &policyKnowledge{policies: make(map[string]*pb.DatabasePolicy)}
+	for _, p := range policies {
+		fake.policies[p.DatabaseId] = p
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	pb.RegisterKnowledgeServiceServer(server, fake)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	client, err := knowledge.NewClient(listener.Addr().String(), transport.TLSConfig{})
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	h := handler.NewDetectionHandler(nil, client, 1, time.Minute)
+	t.Cleanup(func() { h.Shutdown(time.Second) })
+	return h, client
+}
+
+func TestHandleDetection_RefusesDeniedActionType(t *testing.T) {
+	h, _ := newPolicyHandler(t, &pb.DatabasePolicy{
+		DatabaseId:        "billing",
+		DeniedActionTypes: []string{"terminate_query"},
+		Reason:            "billing jobs must never be killed",
+	})
+
+	result, err := h.HandleDetection(&models.Detection{
+		DetectionID:    "det-idle",
+		DetectorName:   "idle_transaction",
+		ActionType:     "terminate_query",
+		DatabaseID:     "billing",
+		Severity:       "critical",
+		ActionMetaData: map[string]interface{}{"pid": 4242},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	assert.Equal(t, models.StatusRefused, result.Status)
+	assert.Equal(t, models.PolicyDeny, result.PolicyDecision)
+	assert.Equal(t, policy.RuleDenied, result.PolicyRule)
+	assert.Contains(t, result.Message, "billing jobs must never be killed")
+
+	stored, err := h.GetActionStatus(result.ActionID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRefused, stored.Status, "a refused action is never queued")
+}
+
+func TestHandleDetection_RefusesTypeMissingFromAllowList(t *testing.T) {
+	h, _ := newPolicyHandler(t, &pb.DatabasePolicy{
+		DatabaseId:         "billing",
+		AllowedActionTypes: []string{"create_index"},
+	})
+
+	result, err := h.HandleDetection(&models.Detection{
+		DetectionID:  "det-lag",
+		DetectorName: "replication_lag",
+		ActionType:   "investigate_replication_lag",
+		DatabaseID:   "billing",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, models.StatusRefused, result.Status)
+	assert.Equal(t, policy.RuleNotAllowed, result.PolicyRule)
+}
+
+func TestPoliciesAPI_ChangeAppliesWithoutWaitingForCache(t *testing.T) {
+	h, client := newPolicyHandler(t)
+	server := httpserver.NewServer(h, "")
+	server.SetPolicyStore(client)
+	routes := server.Handler()
+
+	// Reads and caches the empty policy
+	allowed, err := h.HandleDetection(&models.Detection{
+		DetectionID:  "det-lag-1",
+		DetectorName: "replication_lag",
+		ActionType:   "investigate_replication_lag",
+		DatabaseID:   "billing",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, allowed)
+	assert.NotEqual(t, models.StatusRefused, allowed.Status)
+
+	rec := httptest.NewRecorder()
+	routes.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/policies",
+		strings.NewReader(`{"database_id": "billing", "denied_action_types": ["investigate_replication_lag"]}`)))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	refused, err := h.HandleDetection(&models.Detection{
+		DetectionID:  "det-lag-2",
+		DetectorName: "replication_lag",
+		ActionType:   "investigate_replication_lag",
+		DatabaseID:   "billing",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, refused)
+	assert.Equal(t, models.StatusRefused, refused.Status)
+
+	rec = httptest.NewRecorder()
+	routes.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/policies?database_id=billing", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"denied_action_types":["investigate_replication_lag"]`)
+
+	rec = httptest.NewRecorder()
+	routes.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/policies",
+		strings.NewReader(`{"database_id": "billing", "allowed_action_types": ["create_index"], "denied_action_types": ["create_index"]}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "a type both allowed and denied is rejected")
+}
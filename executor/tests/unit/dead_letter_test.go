@@ -0,0 +1,28 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/eventbus"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/handler"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleDetection_UnactionableIsDeadLettered(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+
+	// create_index needs Knowledge for the connection string and table_name in metadata
+	result, err := h.HandleDetection(&models.Detection{
+		DetectionID:    "det-dead-letter",
+		ActionType:     "create_index",
+		DatabaseID:     "test-db",
+		ActionMetaData: map[string]interface{}{},
+	})
+
+	assert.Nil(t, result)
+	assert.True(t, errors.Is(err, eventbus.ErrDeadLettered), "expected dead-letter error, got %v", err)
+}
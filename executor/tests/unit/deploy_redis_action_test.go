@@ -0,0 +1,83 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/actions"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/docker"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRedisAction(runtime *fakeDockerRuntime, probe docker.ReadinessProbe) (*actions.DeployRedisAction, *string) {
+	var probed string
+	action := actions.NewDeployRedisActionWithRuntime("action-redis-1", "det-1", "shop-db", runtime, nil)
+	action.SetProbeSettings(docker.ProbeSettings{Host: "localhost"})
+	action.SetReadinessProbe(func(addr string) docker.ReadinessProbe {
+		probed = addr
+		return probe
+	})
+	return action, &probed
+}
+
+func TestDeployRedis_ReadyAfterPing(t *testing.T) {
+	runtime := &fakeDockerRuntime{}
+	action, probed := newRedisAction(runtime, func(ctx context.Context) (string, error) {
+		return "PONG", nil
+	})
+
+	result, err := action.Execute(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, models.StatusCompleted, result.Status)
+	assert.Equal(t, "localhost:6379", *probed)
+
+	probe := result.Changes["readiness_probe"].(map[string]interface{})
+	assert.Equal(t, true, probe["passed"])
+	assert.Equal(t, "PONG", probe["detail"])
+}
+
+func TestDeployRedis_FailedProbeRemovesContainer(t *testing.T) {
+	runtime := &fakeDockerRuntime{}
+	action, _ := newRedisAction(runtime, func(ctx context.Context) (string, error) {
+		return "", errors.New("connection reset by peer")
+	})
+
+	result, err := action.Execute(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, models.StatusFailed, result.Status)
+	assert.Contains(t, result.Error, "connection reset by peer")
+	assert.Equal(t, "c0ffee0000000000", runtime.removedID)
+
+	probe := result.Changes["readiness_probe"].(map[string]interface{})
+	assert.Equal(t, false, probe["passed"])
+}
+
+func TestDeployRedis_AppliesResourceLimits(t *testing.T) {
+	runtime := &fakeDockerRuntime{}
+	action, _ := newRedisAction(runtime, func(ctx context.Context) (string, error) {
+		return "PONG", nil
+	})
+	action.SetResourceLimits(docker.ResourceLimits{MemoryBytes: 512 * 1024 * 1024, NanoCPUs: 1e9})
+
+	require.NoError(t, action.Validate(context.Background()))
+	result, err := action.Execute(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(512*1024*1024), runtime.createdHost.Memory)
+	assert.Equal(t, int64(1e9), runtime.createdHost.NanoCPUs)
+	assert.Equal(t, 1.0, result.Changes["resource_limits"].(map[string]interface{})["cpus"])
+}
+
+func TestDeployRedis_MaxMemoryMustFitTheContainer(t *testing.T) {
+	action, _ := newRedisAction(&fakeDockerRuntime{}, nil)
+	action.SetResourceLimits(docker.ResourceLimits{MemoryBytes: 256 * 1024 * 1024})
+
+	err := action.Validate(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be below the container memory limit")
+}
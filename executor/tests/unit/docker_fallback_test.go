@@ -0,0 +1,92 @@
+package unit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/actions"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/config"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/handler"
+	httpserver "github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/http"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerUnavailable_DeployRedisBecomesManualSteps(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+	h.SetDocker(nil, errors.New("Cannot connect to the Docker daemon at unix:///var/run/docker.sock"))
+
+	result, err := h.HandleDetection(&models.Detection{
+		DetectionID:    "det-redis",
+		ActionType:     "deploy_redis",
+		DatabaseID:     "test-db",
+		Severity:       "warning",
+		ActionMetaData: map[string]interface{}{"port": "6380"},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	completed := waitForStatus(t, h, result.ActionID, models.StatusCompleted)
+	assert.Equal(t, "deploy_redis", completed.Changes["replaces_action_type"])
+
+	recommendations := completed.Changes["recommendations"].([]actions.Recommendation)
+	require.Len(t, recommendations, 1)
+	assert.Contains(t, recommendations[0].Description, "could not reach Docker")
+	assert.Contains(t, recommendations[0].Steps[0], "-p 6380:6379")
+}
+
+func TestDockerUnavailable_ManualRedisDeployIsRefused(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+	h.SetDocker(nil, errors.New("docker socket not mounted"))
+	routes := httpserver.NewServer(h, "").Handler()
+
+	rec := httptest.NewRecorder()
+	routes.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/deploy-redis", strings.NewReader(`{"database_id": "test-db"}`)))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), "docker socket not mounted")
+}
+
+func TestContainerManualSteps(t *testing.T) {
+	settings := actions.DefaultPgBouncerSettings()
+	settings.HostPort = 6440
+
+	recommendation, ok := actions.ContainerManualSteps("deploy_connection_pooler", "test-db", settings, map[string]interface{}{"pool_mode": "session"}, "no engine")
+	require.True(t, ok)
+	assert.Equal(t, "deploy_connection_pooler", recommendation.DeployableActionType)
+	assert.Contains(t, recommendation.Steps[0], "-p 6440:6432")
+	assert.Contains(t, recommendation.Steps[1], "pool_mode = session")
+
+	recommendation, ok = actions.ContainerManualSteps("increase_cache_size", "test-db", settings, nil, "no engine")
+	require.True(t, ok)
+	assert.True(t, recommendation.RequiresRestart)
+
+	_, ok = actions.ContainerManualSteps("create_index", "test-db", settings, nil, "no engine")
+	assert.False(t, ok)
+}
+
+func TestConfig_DockerSettings(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "tcp://docker-host:2376")
+	t.Setenv("DOCKER_TLS_VERIFY", "1")
+	t.Setenv("DOCKER_CERT_PATH", "/etc/docker/certs")
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "tcp://docker-host:2376", cfg.Docker().Host)
+	assert.True(t, cfg.Docker().TLSVerify)
+
+	t.Setenv("DOCKER_CERT_PATH", "")
+	_, err = config.Load()
+	assert.Error(t, err, "TLS verification needs certificates")
+
+	t.Setenv("DOCKER_TLS_VERIFY", "")
+	t.Setenv("DOCKER_HOST", "docker-host:2376")
+	_, err = config.Load()
+	assert.Error(t, err, "a host without a scheme is rejected")
+}
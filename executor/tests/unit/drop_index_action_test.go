@@ -0,0 +1,183 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/actions"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/database"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const ordersStatusIndexDef = "CREATE INDEX idx_orders_status ON public.orders USING btree (status)"
+
+// indexDefinitionMockAdapter captures and replays index definitions.
+type indexDefinitionMockAdapter struct {
+	*MockDatabaseAdapter
+	definition      string
+	definitionError error
+	restored        string
+	restoreError    error
+}
+
+func (m *indexDefinitionMockAdapter) GetIndexDefinition(ctx context.Context, indexName string) (string, error) {
+	return m.definition, m.definitionError
+}
+
+func (m *indexDefinitionMockAdapter) RestoreIndex(ctx context.Context, definition string) error {
+	m.restored = definition
+	return m.restoreError
+}
+
+func (m *indexDefinitionMockAdapter) GetIndexProgress(ctx context.Context, tableName string) (*database.IndexProgress, error) {
+	return nil, nil
+}
+
+func (m *indexDefinitionMockAdapter) GetIndexSize(ctx context.Context, indexName string) (int64, error) {
+	return 52428800, nil
+}
+
+func newDropIndexMock() *indexDefinitionMockAdapter {
+	return &indexDefinitionMockAdapter{
+		MockDatabaseAdapter: &MockDatabaseAdapter{
+			Capabilities:     database.Capabilities{SupportsIndexes: true},
+			IndexExistsValue: true,
+		},
+		definition: ordersStatusIndexDef,
+	}
+}
+
+func dropIndexMetadata() *models.ActionMetadata {
+	return &models.ActionMetadata{
+		ActionID:   "test-drop-1",
+		ActionType: "drop_index",
+		DatabaseID: "test-db",
+		CreatedAt:  time.Now(),
+	}
+}
+
+func TestDropIndexAction_ExecuteCapturesDefinition(t *testing.T) {
+	mock := newDropIndexMock()
+	action := actions.NewDropIndexAction(dropIndexMetadata(), mock, "idx_orders_status", "orders")
+
+	result, err := action.Execute(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, result.Status)
+	assert.True(t, mock.DropIndexCalled)
+	assert.True(t, result.CanRollback)
+	assert.Equal(t, "idx_orders_status", result.Changes["index_name"])
+	assert.Equal(t, ordersStatusIndexDef, result.Changes["index_definition"])
+	assert.Equal(t, int64(52428800), result.Changes["index_size_bytes"])
+}
+
+func TestDropIndexAction_ValidateRequiresDefinitionSupport(t *testing.T) {
+	mock := &MockDatabaseAdapter{
+		Capabilities:     database.Capabilities{SupportsIndexes: true},
+		IndexExistsValue: true,
+	}
+	action := actions.NewDropIndexAction(dropIndexMetadata(), mock, "idx_orders_status", "orders")
+
+	err := action.Validate(context.Background())
+
+	assert.ErrorIs(t, err, database.ErrActionNotSupported)
+}
+
+func TestDropIndexAction_ValidateIndexMissing(t *testing.T) {
+	mock := newDropIndexMock()
+	mock.IndexExistsValue = false
+	action := actions.NewDropIndexAction(dropIndexMetadata(), mock, "idx_orders_status", "orders")
+
+	result, err := action.Execute(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusFailed, result.Status)
+	assert.Contains(t, result.Error, "does not exist")
+	assert.False(t, mock.DropIndexCalled)
+}
+
+func TestDropIndexAction_NoDefinitionDoesNotDrop(t *testing.T) {
+	mock := newDropIndexMock()
+	mock.definition = ""
+	action := actions.NewDropIndexAction(dropIndexMetadata(), mock, "idx_orders_status", "orders")
+
+	result, err := action.Execute(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusFailed, result.Status)
+	assert.False(t, mock.DropIndexCalled)
+}
+
+func TestDropIndexAction_RollbackRecreatesIndex(t *testing.T) {
+	mock := newDropIndexMock()
+	action := actions.NewDropIndexAction(dropIndexMetadata(), mock, "idx_orders_status", "orders")
+
+	_, err := action.Execute(context.Background())
+	assert.NoError(t, err)
+
+	mock.IndexExistsValue = false
+	err = action.Rollback(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, ordersStatusIndexDef, mock.restored)
+}
+
+func TestDropIndexAction_RollbackError(t *testing.T) {
+	mock := newDropIndexMock()
+	action := actions.NewDropIndexAction(dropIndexMetadata(), mock, "idx_orders_status", "orders")
+
+	_, err := action.Execute(context.Background())
+	assert.NoError(t, err)
+
+	mock.IndexExistsValue = false
+	mock.restoreError = errors.New("permission denied")
+	err = action.Rollback(context.Background())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to re-create index")
+}
+
+func TestDropIndexAction_RollbackNotDropped(t *testing.T) {
+	mock := newDropIndexMock()
+	action := actions.NewDropIndexAction(dropIndexMetadata(), mock, "idx_orders_status", "orders")
+
+	err := action.Rollback(context.Background())
+
+	assert.NoError(t, err)
+	assert.Empty(t, mock.restored)
+}
+
+func TestDropIndexAction_RestoreThenRollback(t *testing.T) {
+	mock := newDropIndexMock()
+	mock.IndexExistsValue = false
+	action := actions.NewDropIndexAction(dropIndexMetadata(), mock, "", "")
+
+	exists, err := action.Restore(context.Background(), map[string]interface{}{
+		"index_name":       "idx_orders_status",
+		"table_name":       "orders",
+		"index_definition": ordersStatusIndexDef,
+	})
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	err = action.Rollback(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, ordersStatusIndexDef, mock.restored)
+}
+
+func TestDropIndexAction_RestoreIndexAlreadyBack(t *testing.T) {
+	mock := newDropIndexMock()
+	action := actions.NewDropIndexAction(dropIndexMetadata(), mock, "", "")
+
+	exists, err := action.Restore(context.Background(), map[string]interface{}{
+		"index_name":       "idx_orders_status",
+		"index_definition": ordersStatusIndexDef,
+	})
+
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
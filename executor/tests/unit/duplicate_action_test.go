@@ -0,0 +1,110 @@
+package unit
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/handler"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/knowledge"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// pendingActionsKnowledge serves a fixed list of pending actions.
+type pendingActionsKnowledge struct {
+	pb.UnimplementedKnowledgeServiceServer
+	pending []*pb.Action
+}
+
+func (f *pendingActionsKnowledge) GetPendingActions(ctx context.Context, req *pb.DatabaseFilterRequest) (*pb.ActionListResponse, error) {
+	return &pb.ActionListResponse{Actions: f.pending}, nil
+}
+
+func newPendingActionsHandler(t *testing.T, pending ...*pb.Action) *handler.DetectionHandler {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	pb.RegisterKnowledgeServiceServer(server, &pendingActionsKnowledge{pending: pending})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	client, err := knowledge.NewClient(listener.Addr().String(), transport.TLSConfig{})
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	h := handler.NewDetectionHandler(nil, client, 1, time.Minute)
+	t.Cleanup(func() { h.Shutdown(time.Second) })
+	return h
+}
+
+func TestHandleDetection_SkipsRepublishedDetectionForPendingIssue(t *testing.T) {
+	h := newPendingActionsHandler(t, &pb.Action{
+		Id:          "action-pending",
+		DetectionId: "det-first",
+		ActionType:  "create_index",
+		DatabaseId:  "test-db",
+		Status:      models.StatusPendingApproval,
+		ActionState: `{"detection_id":"det-first","detection_key":"test-db:missing_index:orders.user_id","action_type":"create_index"}`,
+	})
+
+	result, err := h.HandleDetection(&models.Detection{
+		DetectionID:  "det-republished",
+		Key:          "test-db:missing_index:orders.user_id",
+		DetectorName: "missing_index",
+		ActionType:   "create_index",
+		DatabaseID:   "test-db",
+	})
+
+	require.NoError(t, err)
+	assert.Nil(t, result, "a re-published detection for a pending issue should not create a second action")
+}
+
+func TestHandleDetection_DifferentIssueIsNotDuplicate(t *testing.T) {
+	h := newPendingActionsHandler(t, &pb.Action{
+		Id:          "action-pending",
+		DetectionId: "det-first",
+		ActionType:  "investigate_replication_lag",
+		DatabaseId:  "test-db",
+		Status:      models.StatusPendingApproval,
+		ActionState: `{"detection_id":"det-first","detection_key":"test-db:replication_lag:replica-1"}`,
+	})
+
+	result, err := h.HandleDetection(&models.Detection{
+		DetectionID:  "det-other",
+		Key:          "test-db:replication_lag:replica-2",
+		DetectorName: "replication_lag",
+		ActionType:   "investigate_replication_lag",
+		DatabaseID:   "test-db",
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "investigate_replication_lag", result.ActionType)
+}
+
+func TestHandleDetection_LegacyPendingActionMatchesOnDetectionID(t *testing.T) {
+	h := newPendingActionsHandler(t, &pb.Action{
+		Id:          "action-legacy",
+		DetectionId: "det-legacy",
+		ActionType:  "create_index",
+		DatabaseId:  "test-db",
+		Status:      models.StatusPendingApproval,
+		ActionState: `{"detection_id":"det-legacy","action_type":"create_index"}`,
+	})
+
+	result, err := h.HandleDetection(&models.Detection{
+		DetectionID: "det-legacy",
+		Key:         "test-db:missing_index:orders.user_id",
+		ActionType:  "create_index",
+		DatabaseID:  "test-db",
+	})
+
+	require.NoError(t, err)
+	assert.Nil(t, result)
+}
@@ -0,0 +1,282 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/actions"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/database"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/handler"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestClassifyError_PostgresErrors(t *testing.T) {
+	cases := map[string]models.ErrorCode{
+		"42501": models.ErrorCodePermissionDenied,      // insufficient_privilege
+		"42P01": models.ErrorCodeObjectNotFound,        // undefined_table
+		"42P07": models.ErrorCodeAlreadyExists,         // duplicate_table
+		"55P03": models.ErrorCodeConflict,              // lock_not_available
+		"40P01": models.ErrorCodeConflict,              // deadlock_detected
+		"57014": models.ErrorCodeTimeout,               // query_canceled by statement_timeout
+		"08006": models.ErrorCodeDependencyUnavailable, // connection_failure
+		"53300": models.ErrorCodeDependencyUnavailable, // too_many_connections
+		"42601": models.ErrorCodeInvalidRequest,        // syntax_error
+		"XX000": models.ErrorCodeInternal,              // internal_error
+	}
+
+	for sqlState, want := range cases {
+		err := fmt.Errorf("failed to create index: %w", &pgconn.PgError{Code: sqlState, Message: "boom"})
+		assert.Equal(t, want, models.ClassifyError(err), "SQLSTATE %s", sqlState)
+	}
+}
+
+func TestClassifyError_DockerErrors(t *testing.T) {
+	assert.Equal(t, models.ErrorCodeObjectNotFound,
+		models.ClassifyError(fmt.Errorf("failed to remove container: %w", errdefs.NotFound(errors.New("No such container: startupmonkey-redis")))))
+	assert.Equal(t, models.ErrorCodeConflict,
+		models.ClassifyError(errdefs.Conflict(errors.New(`the container name "/startupmonkey-redis" is already in use`))))
+	assert.Equal(t, models.ErrorCodePermissionDenied,
+		models.ClassifyError(errdefs.Forbidden(errors.New("access denied"))))
+	assert.Equal(t, models.ErrorCodeDependencyUnavailable,
+		models.ClassifyError(fmt.Errorf("failed to pull image: %w", client.ErrorConnectionFailed("unix:///var/run/docker.sock"))))
+	assert.Equal(t, models.ErrorCodeInvalidRequest,
+		models.ClassifyError(errdefs.InvalidParameter(errors.New("invalid memory limit"))))
+}
+
+func TestClassifyError_OtherErrors(t *testing.T) {
+	cases := []struct {
+		err  error
+		want models.ErrorCode
+	}{
+		{&mysql.MySQLError{Number: 1142, Message: "INDEX command denied"}, models.ErrorCodePermissionDenied},
+		{&mysql.MySQLError{Number: 1213, Message: "Deadlock found"}, models.ErrorCodeConflict},
+		{fmt.Errorf("wait: %w", context.DeadlineExceeded), models.ErrorCodeTimeout},
+		{context.Canceled, models.ErrorCodeCancelled},
+		{fmt.Errorf("vacuum: %w", database.ErrActionNotSupported), models.ErrorCodeUnsupported},
+		{database.ErrIndexAlreadyExists, models.ErrorCodeAlreadyExists},
+		{database.ErrBackendNotFound, models.ErrorCodeObjectNotFound},
+		{handler.ErrShuttingDown, models.ErrorCodeCancelled},
+		{status.Error(codes.Unavailable, "knowledge is down"), models.ErrorCodeDependencyUnavailable},
+		{models.WithErrorCode(models.ErrorCodeConflict, errors.New("index build already running")), models.ErrorCodeConflict},
+		{errors.New("something unexpected"), models.ErrorCodeInternal},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, models.ClassifyError(tc.err), tc.err.Error())
+	}
+
+	assert.Empty(t, models.ClassifyError(nil))
+	assert.Equal(t, models.ErrorCodeInvalidRequest, models.ClassifyValidationError(errors.New("table_name is required")))
+	assert.Equal(t, models.ErrorCodePermissionDenied, models.ClassifyValidationError(&pgconn.PgError{Code: "42501"}))
+}
+
+func TestErrorCode_Transient(t *testing.T) {
+	assert.True(t, models.ErrorCodeTimeout.Transient())
+	assert.True(t, models.ErrorCodeConflict.Transient())
+	assert.True(t, models.ErrorCodeDependencyUnavailable.Transient())
+
+	assert.False(t, models.ErrorCodePermissionDenied.Transient())
+	assert.False(t, models.ErrorCodeObjectNotFound.Transient())
+	assert.False(t, models.ErrorCodeInternal.Transient())
+}
+
+func TestCreateIndexAction_FailureCarriesErrorCode(t *testing.T) {
+	mock := &MockDatabaseAdapter{
+		Capabilities:     database.Capabilities{SupportsIndexes: true},
+		CreateIndexError: &pgconn.PgError{Code: "42501", Message: "must be owner of table posts"},
+	}
+	metadata := &models.ActionMetadata{ActionID: "idx-denied", ActionType: "create_index", DatabaseID: "test-db", CreatedAt: time.Now()}
+
+	result, err := actions.NewCreateIndexAction(metadata, mock, "posts", []string{"user_id"}, false).Execute(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusFailed, result.Status)
+	assert.Equal(t, models.ErrorCodePermissionDenied, result.ErrorCode)
+}
+
+// flakyAction fails with err on its first failures executions, then completes.
+type flakyAction struct {
+	id       string
+	err      error
+	failures int
+
+	mu       sync.Mutex
+	executed int
+}
+
+func (a *flakyAction) Execute(ctx context.Context) (*models.ActionResult, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.executed++
+	if a.executed <= a.failures {
+		return nil, a.err
+	}
+	return &models.ActionResult{ActionID: a.id, ActionType: "test", Status: models.StatusCompleted}, nil
+}
+
+func (a *flakyAction) executions() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.executed
+}
+
+func (a *flakyAction) Rollback(ctx context.Context) error { return nil }
+func (a *flakyAction) Validate(ctx context.Context) error { return nil }
+func (a *flakyAction) GetMetadata() *models.ActionMetadata {
+	return &models.ActionMetadata{ActionID: a.id, ActionType: "test", DatabaseID: "test-db", CreatedAt: time.Now()}
+}
+
+func TestRetry_TransientFailureIsRetried(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+	h.SetRetryPolicy(handler.RetryPolicy{MaxAttempts: 3, Backoff: 10 * time.Millisecond})
+
+	action := &flakyAction{id: "flaky", err: &pgconn.PgError{Code: "40P01", Message: "deadlock detected"}, failures: 2}
+	h.ExecuteActionDirectly(action, &models.Detection{DetectionID: "det-flaky"})
+
+	result := waitForStatus(t, h, "flaky", models.StatusCompleted)
+	assert.Equal(t, 3, action.executions())
+	assert.Equal(t, 3, result.Attempts)
+}
+
+func TestRetry_PermanentFailureIsNotRetried(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+	h.SetRetryPolicy(handler.RetryPolicy{MaxAttempts: 3, Backoff: 10 * time.Millisecond})
+
+	action := &flakyAction{id: "denied", err: &pgconn.PgError{Code: "42501", Message: "permission denied for table posts"}, failures: 1}
+	h.ExecuteActionDirectly(action, &models.Detection{DetectionID: "det-denied"})
+
+	result := waitForStatus(t, h, "denied", models.StatusFailed)
+	assert.Equal(t, models.ErrorCodePermissionDenied, result.ErrorCode)
+	assert.Equal(t, 1, result.Attempts)
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 1, action.executions(), "permanent failures run once")
+}
+
+func TestRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+	h.SetRetryPolicy(handler.RetryPolicy{MaxAttempts: 2, Backoff: 10 * time.Millisecond})
+
+	action := &flakyAction{id: "down", err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, failures: 5}
+	h.ExecuteActionDirectly(action, &models.Detection{DetectionID: "det-down"})
+
+	result := waitForStatus(t, h, "down", models.StatusFailed)
+	assert.Equal(t, models.ErrorCodeDependencyUnavailable, result.ErrorCode)
+	assert.Equal(t, 2, result.Attempts)
+	assert.Equal(t, 2, action.executions())
+}
+
+func TestRetry_DropsInvalidIndexBeforeRetrying(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+	h.SetRetryPolicy(handler.RetryPolicy{MaxAttempts: 2, Backoff: 10 * time.Millisecond})
+
+	mock := &MockDatabaseAdapter{Capabilities: database.Capabilities{SupportsIndexes: true, SupportsConcurrentIndexes: true}}
+	builds := 0
+	mock.CreateIndexFunc = func(ctx context.Context, params database.IndexParams) error {
+		builds++
+		if builds == 1 {
+			// An interrupted concurrent build leaves an INVALID index behind
+			mock.IndexExistsValue = true
+			return &pgconn.PgError{Code: "40P01", Message: "deadlock detected"}
+		}
+		mock.IndexExistsValue = true
+		return nil
+	}
+	mock.DropIndexFunc = func(ctx context.Context, indexName string) error {
+		mock.IndexExistsValue = false
+		return nil
+	}
+
+	metadata := &models.ActionMetadata{ActionID: "idx-retry", ActionType: "create_index", DatabaseID: "test-db", CreatedAt: time.Now()}
+	h.ExecuteActionDirectly(actions.NewCreateIndexAction(metadata, mock, "posts", []string{"user_id"}, false), &models.Detection{DetectionID: "det-idx-retry"})
+
+	result := waitForStatus(t, h, "idx-retry", models.StatusCompleted)
+	assert.Equal(t, 2, result.Attempts)
+	assert.True(t, mock.DropIndexCalled, "the invalid index is dropped before the retry")
+	assert.Equal(t, 2, builds)
+}
+
+func TestRetry_NotRetriedWhenInvalidIndexCannotBeDropped(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+	h.SetRetryPolicy(handler.RetryPolicy{MaxAttempts: 3, Backoff: 10 * time.Millisecond})
+
+	mock := &MockDatabaseAdapter{
+		Capabilities:   database.Capabilities{SupportsIndexes: true, SupportsConcurrentIndexes: true},
+		DropIndexError: errors.New("permission denied"),
+	}
+	builds := 0
+	mock.CreateIndexFunc = func(ctx context.Context, params database.IndexParams) error {
+		builds++
+		mock.IndexExistsValue = true
+		return &pgconn.PgError{Code: "40P01", Message: "deadlock detected"}
+	}
+
+	metadata := &models.ActionMetadata{ActionID: "idx-stuck", ActionType: "create_index", DatabaseID: "test-db", CreatedAt: time.Now()}
+	h.ExecuteActionDirectly(actions.NewCreateIndexAction(metadata, mock, "posts", []string{"user_id"}, false), &models.Detection{DetectionID: "det-idx-stuck"})
+
+	result := waitForStatus(t, h, "idx-stuck", models.StatusFailed)
+	assert.Equal(t, models.ErrorCodeConflict, result.ErrorCode)
+	assert.Equal(t, 1, result.Attempts)
+	assert.Contains(t, result.Error, "not retried")
+	assert.Equal(t, 1, builds)
+}
+
+func TestRetry_RestoresOriginalConfigBeforeRetrying(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+	h.SetRetryPolicy(handler.RetryPolicy{MaxAttempts: 2, Backoff: 10 * time.Millisecond})
+
+	original := map[string]string{"work_mem": "4MB", "random_page_cost": "4"}
+	mock := &MockDatabaseAdapter{
+		Capabilities:           database.Capabilities{SupportsConfigTuning: true, SupportsRuntimeConfigChanges: true},
+		GetCurrentConfigResult: original,
+	}
+	var applied []map[string]string
+	mock.SetConfigFunc = func(ctx context.Context, changes map[string]string) error {
+		applied = append(applied, changes)
+		current := map[string]string{}
+		for k, v := range mock.GetCurrentConfigResult {
+			current[k] = v
+		}
+		if len(applied) == 1 {
+			// Only work_mem is applied before the failure
+			current["work_mem"] = changes["work_mem"]
+			mock.GetCurrentConfigResult = current
+			return &pgconn.PgError{Code: "40P01", Message: "deadlock detected"}
+		}
+		for k, v := range changes {
+			current[k] = v
+		}
+		mock.GetCurrentConfigResult = current
+		return nil
+	}
+
+	action, err := actions.NewTuneConfigAction("tune-retry", "det-tune-retry", "test-db", "postgres", mock)
+	require.NoError(t, err)
+	h.ExecuteActionDirectly(action, &models.Detection{DetectionID: "det-tune-retry"})
+
+	result := waitForStatus(t, h, "tune-retry", models.StatusCompleted)
+	assert.Equal(t, 2, result.Attempts)
+	require.Len(t, applied, 3, "the failed attempt is undone before the retry")
+	assert.Equal(t, original, applied[1])
+	assert.Equal(t, original, result.Changes["original_config"])
+}
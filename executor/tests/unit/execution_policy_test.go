@@ -0,0 +1,251 @@
+package unit
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/config"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/handler"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingNotifier keeps the detections and actions it is told about.
+type recordingNotifier struct {
+	mu         sync.Mutex
+	detections []*models.Detection
+	actions    []*models.ActionResult
+}
+
+func (n *recordingNotifier) DetectionRaised(detection *models.Detection) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.detections = append(n.detections, detection)
+}
+
+func (n *recordingNotifier) ActionFinished(result *models.ActionResult, detection *models.Detection) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.actions = append(n.actions, result)
+}
+
+func defaultPolicyHandler(t *testing.T) *handler.DetectionHandler {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	t.Cleanup(func() { h.Shutdown(time.Second) })
+
+	h.SetExecutionPolicy(map[string]string{
+		"info":     models.PolicyAuto,
+		"warning":  models.PolicyAuto,
+		"critical": models.PolicyApprove,
+	})
+	return h
+}
+
+func TestExecutionPolicy_CriticalWaitsForApproval(t *testing.T) {
+	h := defaultPolicyHandler(t)
+
+	result, err := h.HandleDetection(&models.Detection{
+		DetectionID: "det-policy-critical",
+		ActionType:  "optimise_queries",
+		DatabaseID:  "test-db",
+		Severity:    "Critical",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, models.StatusPendingApproval, result.Status)
+	assert.Equal(t, models.PolicyApprove, result.PolicyDecision)
+	assert.Equal(t, "severity:critical=approve", result.PolicyRule)
+	assert.Contains(t, result.Message, "severity:critical=approve")
+}
+
+func TestExecutionPolicy_InfoRunsAutomatically(t *testing.T) {
+	h := defaultPolicyHandler(t)
+
+	result, err := h.HandleDetection(&models.Detection{
+		DetectionID: "det-policy-info",
+		ActionType:  "optimise_queries",
+		DatabaseID:  "test-db",
+		Severity:    "info",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, models.StatusQueued, result.Status)
+	assert.Equal(t, models.PolicyAuto, result.PolicyDecision)
+	assert.Equal(t, "severity:info=auto", result.PolicyRule)
+	waitForStatus(t, h, result.ActionID, models.StatusPendingImplementation)
+}
+
+func TestExecutionPolicy_ActionOverrideBeatsSeverity(t *testing.T) {
+	h := defaultPolicyHandler(t)
+	h.SetApprovalPolicy(true, map[string]string{"optimise_queries": models.ApprovalAuto})
+
+	result, err := h.HandleDetection(&models.Detection{
+		DetectionID: "det-policy-override",
+		ActionType:  "optimise_queries",
+		DatabaseID:  "test-db",
+		Severity:    "critical",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, models.StatusQueued, result.Status)
+	assert.Equal(t, "action_override:optimise_queries=auto", result.PolicyRule)
+}
+
+func TestExecutionPolicy_AutoSeverityStillRespectsAutoExecutionFlag(t *testing.T) {
+	h := defaultPolicyHandler(t)
+	h.SetApprovalPolicy(false, nil)
+
+	result, err := h.HandleDetection(&models.Detection{
+		DetectionID: "det-policy-disabled",
+		ActionType:  "optimise_queries",
+		DatabaseID:  "test-db",
+		Severity:    "warning",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, models.StatusPendingApproval, result.Status)
+	assert.Equal(t, "auto_execution_disabled", result.PolicyRule)
+}
+
+func TestExecutionPolicy_NotifyOnlySuggestsAndNotifies(t *testing.T) {
+	h := defaultPolicyHandler(t)
+	h.SetExecutionPolicy(map[string]string{"warning": models.PolicyNotify})
+	notifier := &recordingNotifier{}
+	h.SetNotifier(notifier)
+
+	result, err := h.HandleDetection(&models.Detection{
+		DetectionID: "det-policy-notify",
+		ActionType:  "optimise_queries",
+		DatabaseID:  "test-db",
+		Severity:    "warning",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, models.StatusSuggested, result.Status)
+	assert.Equal(t, models.PolicyNotify, result.PolicyDecision)
+
+	// Never queued
+	time.Sleep(20 * time.Millisecond)
+	status, err := h.GetActionStatus(result.ActionID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusSuggested, status.Status)
+
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+	require.Len(t, notifier.actions, 1)
+	assert.Equal(t, result.ActionID, notifier.actions[0].ActionID)
+}
+
+func TestExecutionPolicy_EscalationReDecidesHeldAction(t *testing.T) {
+	h := defaultPolicyHandler(t)
+	h.SetExecutionPolicy(map[string]string{
+		"warning":  models.PolicyNotify,
+		"critical": models.PolicyApprove,
+	})
+	notifier := &recordingNotifier{}
+	h.SetNotifier(notifier)
+
+	detection := &models.Detection{
+		DetectionID: "det-policy-escalated",
+		ActionType:  "optimise_queries",
+		DatabaseID:  "test-db",
+		Severity:    "warning",
+	}
+	suggested, err := h.HandleDetection(detection)
+	require.NoError(t, err)
+	require.Equal(t, models.StatusSuggested, suggested.Status)
+
+	escalated := *detection
+	escalated.Severity = "critical"
+	escalated.Escalated = true
+	result, err := h.HandleDetection(&escalated)
+	require.NoError(t, err)
+
+	require.NotNil(t, result, "an escalated detection should not be dropped as a duplicate")
+	assert.Equal(t, suggested.ActionID, result.ActionID)
+	assert.Equal(t, models.StatusPendingApproval, result.Status)
+	assert.Equal(t, "severity:critical=approve", result.PolicyRule)
+
+	status, err := h.GetActionStatus(result.ActionID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPendingApproval, status.Status)
+
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+	require.Len(t, notifier.detections, 2)
+	assert.Equal(t, "critical", notifier.detections[1].Severity)
+}
+
+func TestExecutionPolicy_EscalationToAutoQueuesHeldAction(t *testing.T) {
+	h := defaultPolicyHandler(t)
+	h.SetExecutionPolicy(map[string]string{
+		"warning":  models.PolicyApprove,
+		"critical": models.PolicyAuto,
+	})
+
+	detection := &models.Detection{
+		DetectionID: "det-policy-escalated-auto",
+		ActionType:  "optimise_queries",
+		DatabaseID:  "test-db",
+		Severity:    "warning",
+	}
+	pending, err := h.HandleDetection(detection)
+	require.NoError(t, err)
+	require.Equal(t, models.StatusPendingApproval, pending.Status)
+
+	escalated := *detection
+	escalated.Severity = "critical"
+	escalated.Escalated = true
+	result, err := h.HandleDetection(&escalated)
+	require.NoError(t, err)
+
+	require.NotNil(t, result)
+	assert.Equal(t, models.StatusQueued, result.Status)
+	waitForStatus(t, h, result.ActionID, models.StatusPendingImplementation)
+}
+
+func TestExecutionPolicy_UpdatedDetectionDoesNotRerunFinishedAction(t *testing.T) {
+	h := defaultPolicyHandler(t)
+	notifier := &recordingNotifier{}
+	h.SetNotifier(notifier)
+
+	detection := &models.Detection{
+		DetectionID: "det-policy-updated",
+		ActionType:  "optimise_queries",
+		DatabaseID:  "test-db",
+		Severity:    "info",
+	}
+	first, err := h.HandleDetection(detection)
+	require.NoError(t, err)
+	waitForStatus(t, h, first.ActionID, models.StatusPendingImplementation)
+
+	updated := *detection
+	updated.Value = 42
+	updated.Updated = true
+	result, err := h.HandleDetection(&updated)
+	require.NoError(t, err)
+
+	require.NotNil(t, result)
+	assert.Equal(t, models.StatusPendingImplementation, result.Status)
+
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+	assert.Len(t, notifier.detections, 2, "the updated detection is announced again")
+}
+
+func TestExecutionPolicy_ConfigDefaultsAndValidation(t *testing.T) {
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"info": "auto", "warning": "auto", "critical": "approve"}, cfg.ExecutionPolicy)
+
+	t.Setenv("EXECUTION_POLICY", "Critical=notify, info=auto")
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"critical": "notify", "info": "auto"}, cfg.ExecutionPolicy)
+
+	t.Setenv("EXECUTION_POLICY", "critical=sometimes")
+	_, err = config.Load()
+	assert.Error(t, err)
+}
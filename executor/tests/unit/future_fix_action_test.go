@@ -2,11 +2,20 @@ package unit
 
 import (
 	"context"
+	"net"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/actions"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/handler"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/knowledge"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/transport"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 )
 
 func TestFutureFixAction_Execute(t *testing.T) {
@@ -45,3 +54,71 @@ func TestFutureFixAction_GetMetadata(t *testing.T) {
 	assert.Equal(t, "some_future_action", metadata.ActionType)
 	assert.Equal(t, "test-db", metadata.DatabaseID)
 }
+
+// acknowledgingKnowledge records status updates and acknowledged detections.
+type acknowledgingKnowledge struct {
+	pb.UnimplementedKnowledgeServiceServer
+	mu           sync.Mutex
+	statuses     map[string]string
+	acknowledged map[string]string
+}
+
+func (f *acknowledgingKnowledge) UpdateActionStatus(ctx context.Context, req *pb.UpdateActionRequest) (*pb.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.statuses[req.ActionId] = req.Status
+	return &pb.Response{Success: true}, nil
+}
+
+func (f *acknowledgingKnowledge) MarkDetectionAcknowledged(ctx context.Context, req *pb.AcknowledgeDetectionRequest) (*pb.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.acknowledged[req.DetectionId] = req.Reason
+	return &pb.Response{Success: true}, nil
+}
+
+func (f *acknowledgingKnowledge) acknowledgement(detectionID string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	reason, ok := f.acknowledged[detectionID]
+	return reason, ok
+}
+
+func TestFutureFixAction_PropagatesPendingImplementationToKnowledge(t *testing.T) {
+	fake := &acknowledgingKnowledge{statuses: map[string]string{}, acknowledged: map[string]string{}}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	pb.RegisterKnowledgeServiceServer(server, fake)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	client, err := knowledge.NewClient(listener.Addr().String(), transport.TLSConfig{})
+	require.NoError(t, err)
+	defer client.Close()
+
+	h := handler.NewDetectionHandler(nil, client, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+
+	result, err := h.HandleDetection(&models.Detection{
+		DetectionID: "det-future-fix",
+		ActionType:  "investigate_replication_lag",
+		DatabaseID:  "test-db",
+	})
+	require.NoError(t, err)
+
+	waitForStatus(t, h, result.ActionID, models.StatusPendingImplementation)
+
+	require.Eventually(t, func() bool {
+		_, ok := fake.acknowledgement("det-future-fix")
+		return ok
+	}, 2*time.Second, 5*time.Millisecond, "detection should be acknowledged in Knowledge")
+
+	reason, _ := fake.acknowledgement("det-future-fix")
+	assert.Contains(t, reason, "investigate_replication_lag")
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	assert.Equal(t, models.StatusPendingImplementation, fake.statuses[result.ActionID])
+}
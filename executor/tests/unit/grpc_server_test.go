@@ -0,0 +1,135 @@
+package unit
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	grpcserver "github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/grpc"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/handler"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startExecutorServer serves the Executor gRPC API for h over an in-memory connection.
+func startExecutorServer(t *testing.T, h *handler.DetectionHandler) pb.ExecutorServiceClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer()
+	pb.RegisterExecutorServiceServer(grpcServer, grpcserver.NewExecutorSever(h))
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewExecutorServiceClient(conn)
+}
+
+// seedCompletedActions runs rollback-capable actions with the given IDs to completion.
+func seedCompletedActions(t *testing.T, h *handler.DetectionHandler, ids ...string) {
+	t.Helper()
+	for _, id := range ids {
+		h.ExecuteActionDirectly(&rollbackableAction{id: id}, &models.Detection{DetectionID: "det-" + id})
+		waitForStatus(t, h, id, models.StatusCompleted)
+	}
+}
+
+func TestExecutorServer_GetActionStatus(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+	seedCompletedActions(t, h, "grpc-1")
+
+	client := startExecutorServer(t, h)
+
+	resp, err := client.GetActionStatus(context.Background(), &pb.ActionStatusRequest{ActionId: "grpc-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "grpc-1", resp.ActionId)
+	assert.Equal(t, models.StatusCompleted, resp.Status)
+	assert.Equal(t, "test", resp.ActionType)
+	assert.True(t, resp.CanRollback)
+
+	_, err = client.GetActionStatus(context.Background(), &pb.ActionStatusRequest{ActionId: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetActionStatus(context.Background(), &pb.ActionStatusRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestExecutorServer_ListActionsPages(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+	seedCompletedActions(t, h, "page-1", "page-2", "page-3")
+
+	client := startExecutorServer(t, h)
+
+	seen := map[string]bool{}
+	cursor := ""
+	pages := 0
+	for {
+		list, err := client.ListActions(context.Background(), &pb.ListActionsRequest{
+			StatusFilter: models.StatusCompleted,
+			Cursor:       cursor,
+			Limit:        2,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), list.TotalCount)
+		assert.LessOrEqual(t, len(list.Actions), 2)
+
+		for _, action := range list.Actions {
+			assert.False(t, seen[action.ActionId], "action %s listed twice", action.ActionId)
+			seen[action.ActionId] = true
+		}
+
+		pages++
+		if list.NextCursor == "" {
+			break
+		}
+		cursor = list.NextCursor
+	}
+
+	assert.Equal(t, 2, pages)
+	assert.Len(t, seen, 3)
+
+	// Nothing matches a status no action is in
+	list, err := client.ListActions(context.Background(), &pb.ListActionsRequest{StatusFilter: models.StatusQueued})
+	require.NoError(t, err)
+	assert.Empty(t, list.Actions)
+	assert.Empty(t, list.NextCursor)
+
+	_, err = client.ListActions(context.Background(), &pb.ListActionsRequest{Cursor: "not-a-cursor"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestExecutorServer_RollbackAction(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+	seedCompletedActions(t, h, "grpc-rb")
+
+	client := startExecutorServer(t, h)
+
+	resp, err := client.RollbackAction(context.Background(), &pb.RollbackActionRequest{ActionId: "grpc-rb"})
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRolledBack, resp.Status)
+
+	stored, err := h.GetActionStatus("grpc-rb")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRolledBack, stored.Status)
+
+	_, err = client.RollbackAction(context.Background(), &pb.RollbackActionRequest{ActionId: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
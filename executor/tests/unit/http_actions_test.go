@@ -0,0 +1,103 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/handler"
+	httpserver "github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/http"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getJSON(t *testing.T, h http.Handler, path string, out interface{}) int {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	if rec.Code == http.StatusOK && out != nil {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), out))
+	}
+	return rec.Code
+}
+
+func TestHTTPServer_ListActionsPages(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+	seedCompletedActions(t, h, "http-1", "http-2", "http-3")
+	routes := httpserver.NewServer(h, "").Handler()
+
+	seen := map[string]bool{}
+	cursor := ""
+	pages := 0
+	for {
+		var list httpserver.ActionList
+		code := getJSON(t, routes, "/api/actions?status=completed&limit=2&cursor="+cursor, &list)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 3, list.TotalCount)
+
+		for _, action := range list.Actions {
+			assert.False(t, seen[action.ActionID], "action %s listed twice", action.ActionID)
+			seen[action.ActionID] = true
+		}
+
+		pages++
+		if list.NextCursor == "" {
+			break
+		}
+		cursor = list.NextCursor
+	}
+	assert.Equal(t, 2, pages)
+	assert.Len(t, seen, 3)
+
+	var other httpserver.ActionList
+	require.Equal(t, http.StatusOK, getJSON(t, routes, "/api/actions?database_id=other-db", &other))
+	assert.NotNil(t, other.Actions, "an empty page is [] rather than null")
+	assert.Empty(t, other.Actions)
+
+	assert.Equal(t, http.StatusBadRequest, getJSON(t, routes, "/api/actions?limit=0", nil))
+	assert.Equal(t, http.StatusBadRequest, getJSON(t, routes, "/api/actions?cursor=abc", nil))
+}
+
+func TestHTTPServer_ActionDetailAndChanges(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+	seedCompletedActions(t, h, "http-detail")
+	routes := httpserver.NewServer(h, "").Handler()
+
+	var detail models.ActionResult
+	require.Equal(t, http.StatusOK, getJSON(t, routes, "/api/actions/http-detail", &detail))
+	assert.Equal(t, "http-detail", detail.ActionID)
+	assert.Equal(t, models.StatusCompleted, detail.Status)
+	assert.True(t, detail.CanRollback)
+
+	var changes httpserver.ActionChanges
+	require.Equal(t, http.StatusOK, getJSON(t, routes, "/api/actions/http-detail/changes", &changes))
+	assert.Equal(t, "http-detail", changes.ActionID)
+	assert.NotNil(t, changes.Changes)
+
+	assert.Equal(t, http.StatusNotFound, getJSON(t, routes, "/api/actions/missing", nil))
+	assert.Equal(t, http.StatusNotFound, getJSON(t, routes, "/api/actions/missing/changes", nil))
+	assert.Equal(t, http.StatusNotFound, getJSON(t, routes, "/api/actions/http-detail/unknown", nil))
+}
+
+func TestHTTPServer_RejectsChangesWhileDraining(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+	server := httpserver.NewServer(h, "")
+	routes := server.Handler()
+
+	server.StopAcceptingChanges()
+
+	rec := httptest.NewRecorder()
+	routes.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/deploy-redis", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "30", rec.Header().Get("Retry-After"))
+
+	var list httpserver.ActionList
+	assert.Equal(t, http.StatusOK, getJSON(t, routes, "/api/actions", &list), "reads still work while draining")
+}
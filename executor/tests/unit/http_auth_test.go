@@ -0,0 +1,64 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpserver "github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPServer_RejectsMissingToken(t *testing.T) {
+	handler := httpserver.NewServer(nil, "secret-token").Handler()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/actions/action-1/rollback", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+}
+
+func TestHTTPServer_RejectsWrongToken(t *testing.T) {
+	handler := httpserver.NewServer(nil, "secret-token").Handler()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/actions/action-1/rollback", nil)
+	req.Header.Set("Authorization", "Bearer wrong-token")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestHTTPServer_AcceptsValidToken(t *testing.T) {
+	handler := httpserver.NewServer(nil, "secret-token").Handler()
+
+	// GET reaches the route and is refused there, proving the token check passed
+	req := httptest.NewRequest(http.MethodGet, "/api/actions/action-1/rollback", nil)
+	req.Header.Set("Authorization", "Bearer secret-token")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestHTTPServer_NoTokenConfiguredAllowsRequests(t *testing.T) {
+	handler := httpserver.NewServer(nil, "").Handler()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/actions/action-1/rollback", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestHTTPServer_PreflightSkipsTokenCheck(t *testing.T) {
+	handler := httpserver.NewServer(nil, "secret-token").Handler()
+
+	req := httptest.NewRequest(http.MethodOptions, "/api/actions/action-1/rollback", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,68 @@
+package unit
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/config"
+	httpserver "github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeConfigReloader returns a canned reload result.
+type fakeConfigReloader struct {
+	result  *config.ReloadResult
+	err     error
+	reloads int
+}
+
+func (f *fakeConfigReloader) ReloadConfig() (*config.ReloadResult, error) {
+	f.reloads++
+	return f.result, f.err
+}
+
+func TestHTTPServer_ConfigReloadReportsChanges(t *testing.T) {
+	reloader := &fakeConfigReloader{result: &config.ReloadResult{
+		Applied:  []config.Change{{Setting: "MaxConcurrentActions", Old: "10", New: "4"}},
+		Rejected: []config.Change{{Setting: "HTTPPort", Old: "8084", New: "9000"}},
+	}}
+	server := httpserver.NewServer(nil, "")
+	server.SetConfigReloader(reloader)
+
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/config/reload", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, reloader.reloads)
+
+	var response struct {
+		Applied  []config.Change `json:"applied"`
+		Rejected []config.Change `json:"rejected"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, reloader.result.Applied, response.Applied)
+	assert.Equal(t, reloader.result.Rejected, response.Rejected)
+}
+
+func TestHTTPServer_ConfigReloadFailure(t *testing.T) {
+	reloader := &fakeConfigReloader{err: errors.New("MAX_CONCURRENT_ACTIONS must be at least 1")}
+	server := httpserver.NewServer(nil, "")
+	server.SetConfigReloader(reloader)
+
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/config/reload", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "MAX_CONCURRENT_ACTIONS")
+}
+
+func TestHTTPServer_ConfigReloadRequiresPost(t *testing.T) {
+	server := httpserver.NewServer(nil, "")
+	server.SetConfigReloader(&fakeConfigReloader{})
+
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/config/reload", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
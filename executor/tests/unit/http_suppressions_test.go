@@ -0,0 +1,77 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	httpserver "github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/http"
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSuppressionStore keeps suppressions in memory, keyed by detection key.
+type fakeSuppressionStore struct {
+	suppressions map[string]*pb.Suppression
+}
+
+func (f *fakeSuppressionStore) SuppressDetectionKey(ctx context.Context, key string, duration time.Duration, reason string) error {
+	if duration == 0 {
+		delete(f.suppressions, key)
+		return nil
+	}
+	f.suppressions[key] = &pb.Suppression{Key: key, Reason: reason, ExpiresAt: time.Now().Add(duration).Unix()}
+	return nil
+}
+
+func (f *fakeSuppressionStore) ListSuppressions(ctx context.Context) ([]*pb.Suppression, error) {
+	var suppressions []*pb.Suppression
+	for _, s := range f.suppressions {
+		suppressions = append(suppressions, s)
+	}
+	return suppressions, nil
+}
+
+func TestHTTPServer_SuppressionLifecycle(t *testing.T) {
+	store := &fakeSuppressionStore{suppressions: map[string]*pb.Suppression{}}
+	server := httpserver.NewServer(nil, "")
+	server.SetSuppressionStore(store)
+	handler := server.Handler()
+
+	body := `{"key": "staging:missing_index:events", "duration_secs": 86400, "reason": "nightly batch job"}`
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/suppressions", strings.NewReader(body)))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/suppressions", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Suppressions []httpserver.Suppression `json:"suppressions"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Suppressions, 1)
+	assert.Equal(t, "nightly batch job", response.Suppressions[0].Reason, "the reason is listed with the suppression")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/suppressions?key=staging:missing_index:events", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, store.suppressions)
+}
+
+func TestHTTPServer_SuppressionRequiresDuration(t *testing.T) {
+	server := httpserver.NewServer(nil, "")
+	server.SetSuppressionStore(&fakeSuppressionStore{suppressions: map[string]*pb.Suppression{}})
+
+	body := `{"key": "staging:missing_index:events", "reason": "forever"}`
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/suppressions", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "suppressions must expire")
+}
@@ -0,0 +1,109 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpserver "github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/http"
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeThresholdStore keeps overrides in memory, keyed by database ID ("" for global).
+type fakeThresholdStore struct {
+	sets   map[string]map[string]float64
+	setErr error
+}
+
+func (f *fakeThresholdStore) GetThresholds(ctx context.Context, databaseID string) ([]*pb.ThresholdSet, error) {
+	var sets []*pb.ThresholdSet
+	for id, values := range f.sets {
+		if databaseID != "" && id != "" && id != databaseID {
+			continue
+		}
+		sets = append(sets, &pb.ThresholdSet{DatabaseId: id, Values: values})
+	}
+	return sets, nil
+}
+
+func (f *fakeThresholdStore) SetThresholds(ctx context.Context, databaseID string, values map[string]float64) error {
+	if f.setErr != nil {
+		return f.setErr
+	}
+	f.sets[databaseID] = values
+	return nil
+}
+
+func TestHTTPServer_PutThresholdsStoresOverrides(t *testing.T) {
+	store := &fakeThresholdStore{sets: map[string]map[string]float64{}}
+	server := httpserver.NewServer(nil, "")
+	server.SetThresholdStore(store)
+
+	body := `{"database_id": "replica", "values": {"sequential_scan_delta": 5000}}`
+	req := httptest.NewRequest(http.MethodPut, "/api/thresholds", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 5000.0, store.sets["replica"]["sequential_scan_delta"])
+}
+
+func TestHTTPServer_GetThresholdsFiltersByDatabase(t *testing.T) {
+	store := &fakeThresholdStore{sets: map[string]map[string]float64{
+		"":        {"p95_latency_ms": 250},
+		"replica": {"sequential_scan_delta": 5000},
+		"primary": {"sequential_scan_delta": 50},
+	}}
+	server := httpserver.NewServer(nil, "")
+	server.SetThresholdStore(store)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/thresholds?database_id=replica", nil)
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Sets []httpserver.ThresholdSet `json:"sets"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+
+	ids := make([]string, 0, len(response.Sets))
+	for _, set := range response.Sets {
+		ids = append(ids, set.DatabaseID)
+	}
+	assert.ElementsMatch(t, []string{"", "replica"}, ids)
+}
+
+func TestHTTPServer_PutThresholdsReportsRejection(t *testing.T) {
+	store := &fakeThresholdStore{
+		sets:   map[string]map[string]float64{},
+		setErr: fmt.Errorf("knowledge rejected thresholds: invalid threshold"),
+	}
+	server := httpserver.NewServer(nil, "")
+	server.SetThresholdStore(store)
+
+	body := `{"values": {"table_bloat": -1}}`
+	req := httptest.NewRequest(http.MethodPut, "/api/thresholds", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid threshold")
+}
+
+func TestHTTPServer_ThresholdsUnavailableWithoutKnowledge(t *testing.T) {
+	handler := httpserver.NewServer(nil, "").Handler()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/thresholds", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
@@ -0,0 +1,314 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/actions"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/database"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/docker"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sharedBuffersAdapter models shared_buffers as Postgres handles it: ALTER SYSTEM stages a
+// value that only becomes current when the server restarts.
+type sharedBuffersAdapter struct {
+	MockDatabaseAdapter
+
+	fileValue string // postgresql.conf
+	autoValue string // postgresql.auto.conf, "" when not set with ALTER SYSTEM
+	running   string
+	down      bool // the server failed to start and refuses connections
+
+	autoConfErr error // reading postgresql.auto.conf is not permitted
+	resetCalled bool
+}
+
+func newSharedBuffersAdapter(fileValue, autoValue string) *sharedBuffersAdapter {
+	adapter := &sharedBuffersAdapter{fileValue: fileValue, autoValue: autoValue}
+	adapter.Capabilities = database.Capabilities{SupportsConfigTuning: true}
+	adapter.restart()
+	return adapter
+}
+
+func (a *sharedBuffersAdapter) restart() {
+	a.down = false
+	a.running = a.fileValue
+	if a.autoValue != "" {
+		a.running = a.autoValue
+	}
+}
+
+var errServerDown = errors.New("connection refused")
+
+func (a *sharedBuffersAdapter) GetCurrentConfig(ctx context.Context, parameters []string) (map[string]string, error) {
+	if a.down {
+		return nil, errServerDown
+	}
+	return map[string]string{"shared_buffers": a.running}, nil
+}
+
+func (a *sharedBuffersAdapter) SetConfig(ctx context.Context, changes map[string]string) error {
+	if a.down {
+		return errServerDown
+	}
+	a.SetConfigCalled = true
+	a.autoValue = changes["shared_buffers"]
+	return nil
+}
+
+func (a *sharedBuffersAdapter) IsConfigPersisted(ctx context.Context, parameter string) (bool, error) {
+	return a.autoValue != "", nil
+}
+
+func (a *sharedBuffersAdapter) ResetConfig(ctx context.Context, parameters []string) error {
+	if a.down {
+		return errServerDown
+	}
+	a.resetCalled = true
+	a.autoValue = ""
+	return nil
+}
+
+func (a *sharedBuffersAdapter) ReadPersistedConfigFile(ctx context.Context) (string, string, error) {
+	if a.autoConfErr != nil {
+		return "", "", a.autoConfErr
+	}
+	return "/var/lib/postgresql/data", autoConfContent(a.autoValue), nil
+}
+
+func autoConfContent(sharedBuffers string) string {
+	content := "# Do not edit this file manually!\n"
+	if sharedBuffers != "" {
+		content += "shared_buffers = '" + sharedBuffers + "'\n"
+	}
+	return content
+}
+
+// startsOnlyWith simulates Postgres failing to start with any shared_buffers but the given
+// one. A postgresql.auto.conf copied into the container replaces the staged value.
+func startsOnlyWith(adapter *sharedBuffersAdapter, runtime *fakeDockerRuntime, value string) func() {
+	return func() {
+		if content, ok := runtime.copiedFiles["/var/lib/postgresql/data/postgresql.auto.conf"]; ok {
+			adapter.autoValue = ""
+			if string(content) != autoConfContent("") {
+				adapter.autoValue = value
+			}
+		}
+		adapter.restart()
+		adapter.down = adapter.running != value
+	}
+}
+
+func newSharedBuffersAction(adapter *sharedBuffersAdapter, runtime *fakeDockerRuntime) *actions.IncreaseSharedBuffersAction {
+	if runtime.existing == nil {
+		runtime.existing = map[string]string{"orders-postgres": "c0ffee0000000000"}
+	}
+	runtime.running = true
+	if runtime.onRestart == nil {
+		runtime.onRestart = adapter.restart
+	}
+
+	action := actions.NewIncreaseSharedBuffersActionWithRuntime(
+		"action-sb-1", "det-1", "orders-db", "orders-postgres", adapter, runtime,
+	)
+	action.SetProbeSettings(docker.ProbeSettings{Timeout: 0})
+	return action
+}
+
+func TestIncreaseSharedBuffers_DoublesAndRestarts(t *testing.T) {
+	adapter := newSharedBuffersAdapter("256MB", "")
+	runtime := &fakeDockerRuntime{}
+	action := newSharedBuffersAction(adapter, runtime)
+
+	require.NoError(t, action.Validate(context.Background()))
+
+	result, err := action.Execute(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, models.StatusCompleted, result.Status)
+	assert.True(t, result.CanRollback)
+	assert.Equal(t, 1, runtime.restarts)
+	assert.Equal(t, "512MB", adapter.running)
+	assert.Equal(t, "orders-postgres", result.Changes["container_name"])
+	assert.Equal(t, "256MB", result.Changes["original_shared_buffers"])
+	assert.Equal(t, "512MB", result.Changes["new_shared_buffers"])
+	assert.Equal(t, false, result.Changes["original_set_with_alter_system"])
+}
+
+func TestIncreaseSharedBuffers_RaisesDefaultToMinimum(t *testing.T) {
+	adapter := newSharedBuffersAdapter("128MB", "")
+	action := newSharedBuffersAction(adapter, &fakeDockerRuntime{})
+
+	result, err := action.Execute(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, models.StatusCompleted, result.Status)
+	assert.Equal(t, "256MB", adapter.running)
+}
+
+func TestIncreaseSharedBuffers_NoChangeAtLimit(t *testing.T) {
+	adapter := newSharedBuffersAdapter("8GB", "")
+	runtime := &fakeDockerRuntime{}
+	action := newSharedBuffersAction(adapter, runtime)
+
+	result, err := action.Execute(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, models.StatusCompleted, result.Status)
+	assert.False(t, result.CanRollback)
+	assert.False(t, adapter.SetConfigCalled)
+	assert.Zero(t, runtime.restarts)
+}
+
+func TestIncreaseSharedBuffers_RollbackResetsAlterSystem(t *testing.T) {
+	adapter := newSharedBuffersAdapter("256MB", "")
+	runtime := &fakeDockerRuntime{}
+	action := newSharedBuffersAction(adapter, runtime)
+
+	_, err := action.Execute(context.Background())
+	require.NoError(t, err)
+
+	require.NoError(t, action.Rollback(context.Background()))
+
+	assert.True(t, adapter.resetCalled)
+	assert.Equal(t, "256MB", adapter.running)
+	assert.Equal(t, 2, runtime.restarts)
+}
+
+func TestIncreaseSharedBuffers_RollbackRestoresPreviousAlterSystemValue(t *testing.T) {
+	adapter := newSharedBuffersAdapter("128MB", "1GB")
+	action := newSharedBuffersAction(adapter, &fakeDockerRuntime{})
+
+	result, err := action.Execute(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, true, result.Changes["original_set_with_alter_system"])
+	assert.Equal(t, "2GB", adapter.running)
+
+	require.NoError(t, action.Rollback(context.Background()))
+
+	assert.False(t, adapter.resetCalled)
+	assert.Equal(t, "1GB", adapter.autoValue)
+	assert.Equal(t, "1GB", adapter.running)
+}
+
+func TestIncreaseSharedBuffers_FailedRestartUnstagesValue(t *testing.T) {
+	adapter := newSharedBuffersAdapter("256MB", "")
+	runtime := &fakeDockerRuntime{restartError: errors.New("daemon unavailable")}
+	action := newSharedBuffersAction(adapter, runtime)
+
+	result, err := action.Execute(context.Background())
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.True(t, adapter.resetCalled)
+	assert.Equal(t, "", adapter.autoValue)
+}
+
+func TestIncreaseSharedBuffers_NotReadyAfterRestartRollsBack(t *testing.T) {
+	adapter := newSharedBuffersAdapter("256MB", "")
+	runtime := &fakeDockerRuntime{}
+	// The server comes back without picking up the staged value
+	runtime.onRestart = func() {}
+	action := newSharedBuffersAction(adapter, runtime)
+
+	result, err := action.Execute(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, models.StatusFailed, result.Status)
+	assert.Contains(t, result.Error, "expected 512MB")
+	assert.True(t, adapter.resetCalled)
+	assert.Equal(t, 2, runtime.restarts)
+}
+
+func TestIncreaseSharedBuffers_ServerDownRestoresAutoConf(t *testing.T) {
+	adapter := newSharedBuffersAdapter("256MB", "")
+	runtime := &fakeDockerRuntime{}
+	runtime.onRestart = startsOnlyWith(adapter, runtime, "256MB")
+	action := newSharedBuffersAction(adapter, runtime)
+
+	result, err := action.Execute(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, models.StatusFailed, result.Status)
+	assert.Equal(t, "Postgres did not become ready with the new shared_buffers and was restored to 256MB", result.Message)
+	assert.False(t, result.CanRollback)
+	assert.Equal(t, autoConfContent(""), string(runtime.copiedFiles["/var/lib/postgresql/data/postgresql.auto.conf"]))
+	assert.False(t, adapter.down)
+	assert.Equal(t, "256MB", adapter.running)
+	assert.Equal(t, 2, runtime.restarts)
+}
+
+func TestIncreaseSharedBuffers_ServerDownWithoutSavedAutoConfReportsFailure(t *testing.T) {
+	adapter := newSharedBuffersAdapter("256MB", "")
+	adapter.autoConfErr = errors.New("permission denied for function pg_read_file")
+	runtime := &fakeDockerRuntime{}
+	runtime.onRestart = startsOnlyWith(adapter, runtime, "256MB")
+	action := newSharedBuffersAction(adapter, runtime)
+
+	result, err := action.Execute(context.Background())
+
+	require.Error(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, models.StatusFailed, result.Status)
+	assert.Equal(t, "Postgres did not become ready with the new shared_buffers and could not be restored to 256MB", result.Message)
+	assert.Contains(t, result.Error, "postgresql.auto.conf was not saved")
+	assert.True(t, result.CanRollback)
+	assert.True(t, adapter.down)
+}
+
+func TestIncreaseSharedBuffers_CappedByContainerMemory(t *testing.T) {
+	adapter := newSharedBuffersAdapter("256MB", "")
+	runtime := &fakeDockerRuntime{state: &docker.ContainerState{Running: true, MemoryLimit: 1 << 30}}
+	action := newSharedBuffersAction(adapter, runtime)
+
+	result, err := action.Execute(context.Background())
+	require.NoError(t, err)
+
+	// A quarter of the 1GB limit is already in use
+	assert.Equal(t, models.StatusCompleted, result.Status)
+	assert.Contains(t, result.Message, "256MB limit")
+	assert.False(t, adapter.SetConfigCalled)
+	assert.Zero(t, runtime.restarts)
+
+	adapter = newSharedBuffersAdapter("256MB", "")
+	runtime = &fakeDockerRuntime{state: &docker.ContainerState{Running: true, MemoryLimit: 3 << 30}}
+	result, err = newSharedBuffersAction(adapter, runtime).Execute(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, models.StatusCompleted, result.Status)
+	assert.Equal(t, "512MB", adapter.running)
+}
+
+func TestIncreaseSharedBuffers_ValidateRequiresRunningContainer(t *testing.T) {
+	adapter := newSharedBuffersAdapter("256MB", "")
+	runtime := &fakeDockerRuntime{existing: map[string]string{}}
+	action := newSharedBuffersAction(adapter, runtime)
+
+	err := action.Validate(context.Background())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "container orders-postgres not found")
+}
+
+func TestIncreaseSharedBuffers_RestoreChecksAppliedValue(t *testing.T) {
+	changes := map[string]interface{}{
+		"container_name":                 "orders-postgres",
+		"original_shared_buffers":        "256MB",
+		"new_shared_buffers":             "512MB",
+		"original_set_with_alter_system": false,
+	}
+
+	applied := newSharedBuffersAdapter("256MB", "512MB")
+	ok, err := newSharedBuffersAction(applied, &fakeDockerRuntime{}).Restore(context.Background(), changes)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	changedSince := newSharedBuffersAdapter("256MB", "1GB")
+	ok, err = newSharedBuffersAction(changedSince, &fakeDockerRuntime{}).Restore(context.Background(), changes)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,100 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/actions"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/database"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/handler"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceWindow_ParseRejectsInvalidSpecs(t *testing.T) {
+	for _, spec := range []string{"", "02:00", "25:00-03:00", "02:00-3pm", "02:00-02:00"} {
+		_, err := handler.ParseMaintenanceWindow(spec, "UTC")
+		assert.Error(t, err, "spec %q should be rejected", spec)
+	}
+
+	_, err := handler.ParseMaintenanceWindow("02:00-05:00", "Mars/Olympus_Mons")
+	assert.Error(t, err, "unknown timezones should be rejected")
+}
+
+func TestMaintenanceWindow_Contains(t *testing.T) {
+	window, err := handler.ParseMaintenanceWindow("02:00-05:00", "")
+	require.NoError(t, err)
+
+	day := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	assert.False(t, window.Contains(day.Add(time.Hour+59*time.Minute)))
+	assert.True(t, window.Contains(day.Add(2*time.Hour)), "start is inclusive")
+	assert.True(t, window.Contains(day.Add(4*time.Hour+59*time.Minute)))
+	assert.False(t, window.Contains(day.Add(5*time.Hour)), "end is exclusive")
+}
+
+func TestMaintenanceWindow_WrapsPastMidnight(t *testing.T) {
+	window, err := handler.ParseMaintenanceWindow("22:00-04:00", "UTC")
+	require.NoError(t, err)
+
+	day := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	assert.True(t, window.Contains(day.Add(23*time.Hour)))
+	assert.True(t, window.Contains(day.Add(3*time.Hour)))
+	assert.False(t, window.Contains(day.Add(12*time.Hour)))
+
+	assert.Equal(t, day.Add(22*time.Hour), window.NextOpen(day.Add(12*time.Hour)))
+	assert.Equal(t, day.Add(28*time.Hour), window.NextClose(day.Add(23*time.Hour)), "closes the next morning")
+}
+
+func TestMaintenanceWindow_NextOpenUsesTimezone(t *testing.T) {
+	window, err := handler.ParseMaintenanceWindow("02:00-05:00", "America/New_York")
+	require.NoError(t, err)
+
+	// 12:00 UTC is 08:00 in New York (EDT); the window opens at 02:00 EDT the next day
+	now := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	assert.False(t, window.Contains(now))
+	assert.Equal(t, time.Date(2024, 7, 2, 6, 0, 0, 0, time.UTC), window.NextOpen(now).UTC())
+
+	inside := time.Date(2024, 7, 1, 7, 0, 0, 0, time.UTC)
+	assert.Equal(t, inside, window.NextOpen(inside), "an open window opens now")
+}
+
+func TestMaintenanceWindow_OperatorActionsIgnoreWindow(t *testing.T) {
+	var running, peak int32
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+
+	// A window that is closed right now
+	now := time.Now().UTC()
+	closed := now.Add(2*time.Hour).Format("15:04") + "-" + now.Add(3*time.Hour).Format("15:04")
+	window, err := handler.ParseMaintenanceWindow(closed, "UTC")
+	require.NoError(t, err)
+	h.SetMaintenanceWindow(window, nil)
+
+	a := newBlockingAction("manual", &running, &peak)
+	h.ExecuteActionDirectly(a, &models.Detection{DetectionID: "det-manual", Severity: "low"})
+
+	waitForStatus(t, h, "manual", models.StatusExecuting)
+	close(a.release)
+	waitForStatus(t, h, "manual", models.StatusCompleted)
+}
+
+func TestMaintenanceWindow_VacuumFullWaitsEvenWhenOperatorRequested(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+
+	now := time.Now().UTC()
+	closed := now.Add(2*time.Hour).Format("15:04") + "-" + now.Add(3*time.Hour).Format("15:04")
+	window, err := handler.ParseMaintenanceWindow(closed, "UTC")
+	require.NoError(t, err)
+	h.SetMaintenanceWindow(window, []string{"critical"})
+
+	mock := &MockDatabaseAdapter{Capabilities: database.Capabilities{SupportsVacuum: true}}
+	metadata := &models.ActionMetadata{ActionID: "vacuum-full", ActionType: "vacuum_table", DatabaseID: "test-db", CreatedAt: time.Now()}
+	action := actions.NewVacuumTableAction(metadata, mock, "posts", actions.VacuumOptions{Mode: actions.VacuumModeFull})
+
+	h.ExecuteActionDirectly(action, &models.Detection{DetectionID: "det-bloat", Severity: "critical"})
+
+	waitForStatus(t, h, "vacuum-full", models.StatusScheduled)
+	assert.False(t, mock.VacuumCalled)
+}
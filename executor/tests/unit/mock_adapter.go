@@ -16,22 +16,35 @@ type MockDatabaseAdapter struct {
 	DeadTuplesError error
 
 	// Terminate
-	TerminateError error
-	TerminateFunc  func(pid int32, graceful bool) error
+	TerminateError  error
+	TerminateFunc   func(pid int32, graceful bool) error
+	TerminateCalled bool
+
+	// Backend info
+	BackendInfo      *database.BackendInfo
+	BackendInfoError error
 
 	// Index
 	CreateIndexCalled bool
 	CreateIndexError  error
+	CreateIndexFunc   func(ctx context.Context, params database.IndexParams) error
 	DropIndexCalled   bool
 	DropIndexError    error
+	DropIndexFunc     func(ctx context.Context, indexName string) error
 	IndexExistsValue  bool
 	IndexExistsError  error
 
+	// Schema
+	TableMissing     bool
+	MissingColumns   map[string]bool
+	SchemaCheckError error
+
 	// Config
 	GetCurrentConfigResult map[string]string
 	GetCurrentConfigError  error
 	SetConfigCalled        bool
 	SetConfigError         error
+	SetConfigFunc          func(ctx context.Context, changes map[string]string) error
 
 	// Slow queries
 	GetSlowQueriesResult []database.SlowQuery
@@ -39,15 +52,24 @@ type MockDatabaseAdapter struct {
 
 	// Capabilities
 	Capabilities database.Capabilities
+
+	// Lifecycle
+	CloseCalls int
 }
 
 func (m *MockDatabaseAdapter) CreateIndex(ctx context.Context, params database.IndexParams) error {
 	m.CreateIndexCalled = true
+	if m.CreateIndexFunc != nil {
+		return m.CreateIndexFunc(ctx, params)
+	}
 	return m.CreateIndexError
 }
 
 func (m *MockDatabaseAdapter) DropIndex(ctx context.Context, indexName string) error {
 	m.DropIndexCalled = true
+	if m.DropIndexFunc != nil {
+		return m.DropIndexFunc(ctx, indexName)
+	}
 	return m.DropIndexError
 }
 
@@ -58,6 +80,20 @@ func (m *MockDatabaseAdapter) IndexExists(ctx context.Context, indexName string)
 	return m.IndexExistsValue, nil
 }
 
+func (m *MockDatabaseAdapter) TableExists(ctx context.Context, tableName string) (bool, error) {
+	if m.SchemaCheckError != nil {
+		return false, m.SchemaCheckError
+	}
+	return !m.TableMissing, nil
+}
+
+func (m *MockDatabaseAdapter) ColumnExists(ctx context.Context, tableName, columnName string) (bool, error) {
+	if m.SchemaCheckError != nil {
+		return false, m.SchemaCheckError
+	}
+	return !m.MissingColumns[columnName], nil
+}
+
 func (m *MockDatabaseAdapter) GetCurrentConfig(ctx context.Context, parameters []string) (map[string]string, error) {
 	if m.GetCurrentConfigError != nil {
 		return nil, m.GetCurrentConfigError
@@ -67,6 +103,9 @@ func (m *MockDatabaseAdapter) GetCurrentConfig(ctx context.Context, parameters [
 
 func (m *MockDatabaseAdapter) SetConfig(ctx context.Context, changes map[string]string) error {
 	m.SetConfigCalled = true
+	if m.SetConfigFunc != nil {
+		return m.SetConfigFunc(ctx, changes)
+	}
 	return m.SetConfigError
 }
 
@@ -91,16 +130,28 @@ func (m *MockDatabaseAdapter) GetDeadTuples(ctx context.Context, tableName strin
 }
 
 func (m *MockDatabaseAdapter) TerminateQuery(ctx context.Context, pid int32, graceful bool) error {
+	m.TerminateCalled = true
 	if m.TerminateFunc != nil {
 		return m.TerminateFunc(pid, graceful)
 	}
 	return m.TerminateError
 }
 
+func (m *MockDatabaseAdapter) GetBackendInfo(ctx context.Context, pid int32) (*database.BackendInfo, error) {
+	if m.BackendInfoError != nil {
+		return nil, m.BackendInfoError
+	}
+	if m.BackendInfo == nil {
+		return nil, database.ErrBackendNotFound
+	}
+	return m.BackendInfo, nil
+}
+
 func (m *MockDatabaseAdapter) GetCapabilities() database.Capabilities {
 	return m.Capabilities
 }
 
 func (m *MockDatabaseAdapter) Close() error {
+	m.CloseCalls++
 	return nil
 }
@@ -0,0 +1,161 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/actions"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/database"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMongoCollections keeps indexes per collection in memory.
+type fakeMongoCollections struct {
+	indexes    map[string][]database.MongoIndex
+	profile    []database.MongoProfileEntry
+	profileMs  int
+	profileLvl int
+}
+
+func newFakeMongoCollections(collections ...string) *fakeMongoCollections {
+	f := &fakeMongoCollections{indexes: map[string][]database.MongoIndex{}}
+	for _, name := range collections {
+		f.indexes[name] = []database.MongoIndex{{Name: "_id_", Keys: []string{"_id"}}}
+	}
+	return f
+}
+
+func (f *fakeMongoCollections) CollectionNames(ctx context.Context) ([]string, error) {
+	names := make([]string, 0, len(f.indexes))
+	for name := range f.indexes {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (f *fakeMongoCollections) ListIndexes(ctx context.Context, collection string) ([]database.MongoIndex, error) {
+	return f.indexes[collection], nil
+}
+
+func (f *fakeMongoCollections) CreateIndex(ctx context.Context, collection string, index database.MongoIndex) error {
+	f.indexes[collection] = append(f.indexes[collection], index)
+	return nil
+}
+
+func (f *fakeMongoCollections) DropIndex(ctx context.Context, collection, name string) error {
+	kept := f.indexes[collection][:0]
+	for _, index := range f.indexes[collection] {
+		if index.Name != name {
+			kept = append(kept, index)
+		}
+	}
+	f.indexes[collection] = kept
+	return nil
+}
+
+func (f *fakeMongoCollections) SetProfilingLevel(ctx context.Context, level, slowMs int) error {
+	f.profileLvl, f.profileMs = level, slowMs
+	return nil
+}
+
+func (f *fakeMongoCollections) SlowOperations(ctx context.Context, thresholdMs float64, limit int) ([]database.MongoProfileEntry, error) {
+	var entries []database.MongoProfileEntry
+	for _, entry := range f.profile {
+		if entry.Millis > thresholdMs && len(entries) < limit {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func TestMongoDBAdapter_CapabilitiesAreHonest(t *testing.T) {
+	adapter := database.NewMongoDBAdapterWithCollections("shop", newFakeMongoCollections())
+	caps := adapter.GetCapabilities()
+
+	assert.True(t, caps.SupportsIndexes)
+	assert.True(t, caps.SupportsConcurrentIndexes, "background index builds")
+	assert.False(t, caps.SupportsVacuum)
+	assert.False(t, caps.SupportsConfigTuning)
+
+	assert.ErrorIs(t, adapter.VacuumTable(context.Background(), "orders"), database.ErrActionNotSupported)
+	assert.ErrorIs(t, adapter.SetConfig(context.Background(), map[string]string{"work_mem": "64MB"}), database.ErrActionNotSupported)
+}
+
+func TestMongoDBAdapter_IndexNamesFollowMongoConvention(t *testing.T) {
+	adapter := database.NewMongoDBAdapterWithCollections("shop", newFakeMongoCollections())
+
+	assert.Equal(t, "user_id_1", adapter.IndexName("orders", []string{"user_id"}))
+	assert.Equal(t, "user_id_1_created_at_1", adapter.IndexName("orders", []string{"user_id", "created_at"}))
+}
+
+func TestMongoDBAdapter_IndexesAreScopedToCollections(t *testing.T) {
+	ctx := context.Background()
+	collections := newFakeMongoCollections("orders", "invoices")
+	adapter := database.NewMongoDBAdapterWithCollections("shop", collections)
+
+	require.NoError(t, adapter.CreateIndex(ctx, database.IndexParams{TableName: "invoices", ColumnNames: []string{"user_id"}, IndexName: "user_id_1"}))
+
+	// The same name on another collection is a different index
+	exists, err := adapter.IndexExistsOnTable(ctx, "orders", "user_id_1")
+	require.NoError(t, err)
+	assert.False(t, exists)
+	require.NoError(t, adapter.CreateIndex(ctx, database.IndexParams{TableName: "orders", ColumnNames: []string{"user_id"}, IndexName: "user_id_1"}))
+
+	err = adapter.CreateIndex(ctx, database.IndexParams{TableName: "orders", ColumnNames: []string{"user_id"}, IndexName: "user_id_1"})
+	assert.ErrorIs(t, err, database.ErrIndexAlreadyExists)
+
+	require.NoError(t, adapter.DropIndexFromTable(ctx, "orders", "user_id_1"))
+	exists, _ = adapter.IndexExistsOnTable(ctx, "invoices", "user_id_1")
+	assert.True(t, exists, "dropping from orders must leave invoices alone")
+}
+
+func TestMongoDBAdapter_SlowQueriesFromProfile(t *testing.T) {
+	collections := newFakeMongoCollections("orders")
+	collections.profile = []database.MongoProfileEntry{
+		{Namespace: "shop.orders", Operation: "query", Command: `{"find": "orders", "filter": {"user_id": 42}}`, Millis: 850, PlanSummary: "COLLSCAN"},
+		{Namespace: "shop.orders", Operation: "update", Command: `{"update": "orders"}`, Millis: 320, PlanSummary: "IXSCAN { _id: 1 }"},
+		{Namespace: "shop.orders", Operation: "query", Millis: 20, PlanSummary: "IXSCAN { _id: 1 }"},
+	}
+	adapter := database.NewMongoDBAdapterWithCollections("shop", collections)
+
+	slow, err := adapter.GetSlowQueries(context.Background(), 100, 10)
+	require.NoError(t, err)
+	require.Len(t, slow, 2)
+
+	assert.Equal(t, "missing_index", slow[0].IssueType)
+	assert.Contains(t, slow[0].QueryPattern, "shop.orders")
+	assert.Equal(t, 850.0, slow[0].ExecutionTimeMs)
+	assert.Equal(t, "slow_query", slow[1].IssueType)
+}
+
+func TestCreateIndexAction_MongoDBCreatesAndRollsBack(t *testing.T) {
+	ctx := context.Background()
+	collections := newFakeMongoCollections("orders", "invoices")
+	collections.indexes["invoices"] = append(collections.indexes["invoices"], database.MongoIndex{Name: "user_id_1", Keys: []string{"user_id"}})
+	adapter := database.NewMongoDBAdapterWithCollections("shop", collections)
+
+	metadata := &models.ActionMetadata{
+		ActionID:     "action-mongo",
+		ActionType:   "create_index",
+		DatabaseID:   "mongodb-shop",
+		DatabaseType: "mongodb",
+		CreatedAt:    time.Now(),
+	}
+	action := actions.NewCreateIndexAction(metadata, adapter, "orders", []string{"user_id"}, false)
+
+	result, err := action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, models.StatusCompleted, result.Status, result.Error)
+	assert.Equal(t, "user_id_1", result.Changes["index_name"])
+	assert.Equal(t, true, result.Changes["concurrent"])
+
+	require.NoError(t, action.Rollback(ctx))
+
+	exists, _ := adapter.IndexExistsOnTable(ctx, "orders", "user_id_1")
+	assert.False(t, exists, "rollback should drop the created index")
+	exists, _ = adapter.IndexExistsOnTable(ctx, "invoices", "user_id_1")
+	assert.True(t, exists, "rollback must not touch the same-named index on another collection")
+}
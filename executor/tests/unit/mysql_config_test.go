@@ -0,0 +1,79 @@
+package unit
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupportsOnlineDDL(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"5.5.62", false},
+		{"5.6.51", true},
+		{"5.7.44-log", true},
+		{"8.0.36", true},
+		{"10.11.6-MariaDB", true},
+		{"9.5.0-MariaDB", false},
+		{"", false},
+		{"unknown", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, database.SupportsOnlineDDL(tt.version), tt.version)
+	}
+}
+
+func TestMySQLVariable(t *testing.T) {
+	variable, mapped := database.MySQLVariable("work_mem")
+	assert.Equal(t, "sort_buffer_size", variable)
+	assert.True(t, mapped)
+
+	variable, mapped = database.MySQLVariable("max_connections")
+	assert.Equal(t, "max_connections", variable)
+	assert.False(t, mapped)
+
+	for _, param := range []string{"effective_cache_size", "random_page_cost", "x; DROP TABLE t"} {
+		variable, _ = database.MySQLVariable(param)
+		assert.Empty(t, variable, param)
+	}
+}
+
+func TestMySQLSizeTranslation(t *testing.T) {
+	tests := []struct {
+		bytes string
+		size  string
+	}{
+		{"262144", "256kB"},
+		{"16777216", "16MB"},
+		{"1073741824", "1GB"},
+		{"4096", "4kB"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.size, database.FormatMySQLSize(tt.bytes))
+
+		parsed, err := database.ParseMySQLSize(tt.size)
+		require.NoError(t, err)
+		assert.Equal(t, tt.bytes, strconv.FormatInt(parsed, 10))
+	}
+
+	// Values that are not whole units are reported as they are
+	assert.Equal(t, "1000", database.FormatMySQLSize("1000"))
+	assert.Equal(t, "0", database.FormatMySQLSize("0"))
+	assert.Equal(t, "ON", database.FormatMySQLSize("ON"))
+
+	parsed, err := database.ParseMySQLSize("4096")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4096), parsed)
+
+	for _, value := range []string{"", "MB", "-1MB", "16 TB", "lots"} {
+		_, err := database.ParseMySQLSize(value)
+		assert.Error(t, err, value)
+	}
+}
@@ -0,0 +1,110 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type slackPayload struct {
+	Text        string `json:"text"`
+	Attachments []struct {
+		Fields []struct {
+			Title string `json:"title"`
+			Value string `json:"value"`
+		} `json:"fields"`
+	} `json:"attachments"`
+}
+
+func (p slackPayload) field(title string) string {
+	for _, attachment := range p.Attachments {
+		for _, f := range attachment.Fields {
+			if f.Title == title {
+				return f.Value
+			}
+		}
+	}
+	return ""
+}
+
+// webhookRecorder collects the payloads posted to it.
+type webhookRecorder struct {
+	mu       sync.Mutex
+	payloads []slackPayload
+}
+
+func newWebhookRecorder(t *testing.T) (*webhookRecorder, *httptest.Server) {
+	rec := &webhookRecorder{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload slackPayload
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		rec.mu.Lock()
+		rec.payloads = append(rec.payloads, payload)
+		rec.mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+	return rec, server
+}
+
+func (r *webhookRecorder) all() []slackPayload {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]slackPayload(nil), r.payloads...)
+}
+
+func TestWebhook_PostsCriticalDetectionsOnly(t *testing.T) {
+	rec, server := newWebhookRecorder(t)
+	w := notify.NewWebhook(server.URL, []string{notify.EventCriticalDetection}, 0)
+
+	w.DetectionRaised(&models.Detection{DatabaseID: "orders-db", Severity: "warning", Title: "Slow queries"})
+	w.DetectionRaised(&models.Detection{DatabaseID: "orders-db", Severity: "critical", Title: "Connection pool exhausted"})
+	w.Close()
+
+	payloads := rec.all()
+	require.Len(t, payloads, 1)
+	assert.Contains(t, payloads[0].Text, "Connection pool exhausted")
+	assert.Equal(t, "orders-db", payloads[0].field("Database"))
+	assert.Equal(t, "critical", payloads[0].field("Severity"))
+}
+
+func TestWebhook_ActionOutcomeIncludesChanges(t *testing.T) {
+	rec, server := newWebhookRecorder(t)
+	w := notify.NewWebhook(server.URL, []string{notify.EventActionCompleted, notify.EventActionFailed}, 0)
+
+	w.ActionFinished(&models.ActionResult{
+		ActionID:   "action-1",
+		ActionType: "create_index",
+		DatabaseID: "orders-db",
+		Status:     models.StatusCompleted,
+		Changes:    map[string]interface{}{"index_name": "orders_customer_id_idx", "table": "orders"},
+	}, &models.Detection{Title: "Missing index on orders", Severity: "warning"})
+
+	// Rollbacks are not enabled, so this is not posted
+	w.ActionFinished(&models.ActionResult{ActionID: "action-2", DatabaseID: "orders-db", Status: models.StatusRolledBack}, nil)
+	w.Close()
+
+	payloads := rec.all()
+	require.Len(t, payloads, 1)
+	assert.Contains(t, payloads[0].Text, "Missing index on orders")
+	assert.Equal(t, "warning", payloads[0].field("Severity"))
+	assert.Equal(t, "index_name: orders_customer_id_idx\ntable: orders", payloads[0].field("Changes"))
+}
+
+func TestWebhook_RateLimitDropsBursts(t *testing.T) {
+	rec, server := newWebhookRecorder(t)
+	w := notify.NewWebhook(server.URL, []string{notify.EventActionFailed}, 2)
+
+	for i := 0; i < 5; i++ {
+		w.ActionFinished(&models.ActionResult{ActionID: "action", DatabaseID: "orders-db", Status: models.StatusFailed}, nil)
+	}
+	w.Close()
+
+	assert.Len(t, rec.all(), 2, "only ratePerMinute messages go out in a minute")
+}
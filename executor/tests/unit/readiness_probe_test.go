@@ -0,0 +1,95 @@
+package unit
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveOnce accepts a single connection, reads one line and writes reply.
+func serveOnce(t *testing.T, reply string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		bufio.NewReader(conn).ReadString('\n')
+		conn.Write([]byte(reply))
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestWaitForReady_RetriesUntilProbePasses(t *testing.T) {
+	calls := 0
+	probe := func(ctx context.Context) (string, error) {
+		calls++
+		if calls < 3 {
+			return "", errors.New("connection refused")
+		}
+		return "ok", nil
+	}
+
+	result := docker.WaitForReady(context.Background(), probe, 5*time.Second)
+
+	assert.True(t, result.Passed)
+	assert.Equal(t, 3, result.Attempts)
+	assert.Equal(t, "ok", result.Detail)
+	assert.Empty(t, result.LastError)
+}
+
+func TestWaitForReady_GivesUpAtTimeout(t *testing.T) {
+	probe := func(ctx context.Context) (string, error) {
+		return "", errors.New("connection refused")
+	}
+
+	result := docker.WaitForReady(context.Background(), probe, 300*time.Millisecond)
+
+	assert.False(t, result.Passed)
+	assert.GreaterOrEqual(t, result.Attempts, 2)
+	assert.Equal(t, "connection refused", result.LastError)
+	assert.Less(t, result.Elapsed, 2*time.Second)
+}
+
+func TestWaitForReady_ZeroTimeoutProbesOnce(t *testing.T) {
+	calls := 0
+	probe := func(ctx context.Context) (string, error) {
+		calls++
+		return "", errors.New("connection refused")
+	}
+
+	result := docker.WaitForReady(context.Background(), probe, 0)
+
+	assert.False(t, result.Passed)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRedisPingProbe_AcceptsPong(t *testing.T) {
+	addr := serveOnce(t, "+PONG\r\n")
+
+	detail, err := docker.RedisPingProbe(addr)(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "PONG", detail)
+}
+
+func TestRedisPingProbe_RejectsErrorReply(t *testing.T) {
+	addr := serveOnce(t, "-LOADING Redis is loading the dataset in memory\r\n")
+
+	_, err := docker.RedisPingProbe(addr)(context.Background())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "LOADING")
+}
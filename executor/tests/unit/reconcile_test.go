@@ -0,0 +1,222 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/database"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/handler"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/knowledge"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// recordedActionKnowledge serves actions recorded by a previous Executor process and
+// keeps the statuses the handler reports back.
+type recordedActionKnowledge struct {
+	pb.UnimplementedKnowledgeServiceServer
+
+	mu       sync.Mutex
+	actions  map[string]*pb.Action
+	statuses map[string]string
+}
+
+func (k *recordedActionKnowledge) GetDatabase(ctx context.Context, req *pb.GetDatabaseRequest) (*pb.GetDatabaseResponse, error) {
+	return &pb.GetDatabaseResponse{
+		Found:            true,
+		DatabaseId:       req.DatabaseId,
+		DatabaseType:     "postgresql",
+		ConnectionString: "postgres://test@localhost/" + req.DatabaseId,
+	}, nil
+}
+
+func (k *recordedActionKnowledge) GetAction(ctx context.Context, req *pb.GetActionRequest) (*pb.GetActionResponse, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	action, ok := k.actions[req.ActionId]
+	return &pb.GetActionResponse{Found: ok, Action: action}, nil
+}
+
+func (k *recordedActionKnowledge) UpdateActionStatus(ctx context.Context, req *pb.UpdateActionRequest) (*pb.Response, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.statuses[req.ActionId] = req.Status
+	return &pb.Response{Success: true}, nil
+}
+
+func (k *recordedActionKnowledge) status(actionID string) string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.statuses[actionID]
+}
+
+// startRecordedActionKnowledge serves record as a completed, rollback-capable action.
+func startRecordedActionKnowledge(t *testing.T, record *pb.Action) (*recordedActionKnowledge, *knowledge.Client) {
+	t.Helper()
+
+	record.Status = models.StatusCompleted
+	record.CanRollback = true
+	service := &recordedActionKnowledge{
+		actions:  map[string]*pb.Action{record.Id: record},
+		statuses: make(map[string]string),
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	pb.RegisterKnowledgeServiceServer(server, service)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	client, err := knowledge.NewClient(listener.Addr().String(), transport.TLSConfig{})
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	return service, client
+}
+
+func recordedChanges(t *testing.T, changes map[string]interface{}) string {
+	t.Helper()
+
+	encoded, err := json.Marshal(changes)
+	require.NoError(t, err)
+	return string(encoded)
+}
+
+func TestRollbackAction_UnknownActionWithoutKnowledge(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+
+	_, err := h.RollbackAction("action-from-previous-process")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "action not found")
+}
+
+func TestRollbackAction_UnknownToKnowledge(t *testing.T) {
+	_, client := startRecordedActionKnowledge(t, &pb.Action{Id: "other-action", ActionType: "create_index"})
+	h := handler.NewDetectionHandler(nil, client, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+
+	_, err := h.RollbackAction("action-from-previous-process")
+
+	assert.ErrorIs(t, err, handler.ErrActionNotFound)
+}
+
+func TestRollbackAction_RebuildsCreateIndexFromKnowledge(t *testing.T) {
+	service, client := startRecordedActionKnowledge(t, &pb.Action{
+		Id:          "action-idx-1",
+		DetectionId: "det-1",
+		ActionType:  "create_index",
+		DatabaseId:  "shop-db",
+		Changes: recordedChanges(t, map[string]interface{}{
+			"index_name":   "idx_orders_customer_id",
+			"table_name":   "orders",
+			"column_names": []string{"customer_id"},
+		}),
+	})
+
+	var dropped string
+	adapter := &MockDatabaseAdapter{
+		IndexExistsValue: true,
+		DropIndexFunc: func(ctx context.Context, indexName string) error {
+			dropped = indexName
+			return nil
+		},
+	}
+
+	h := handler.NewDetectionHandler(nil, client, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+	h.SetAdapterFactory(func(ctx context.Context, databaseType, connectionString, databaseID string) (database.DatabaseAdapter, error) {
+		return adapter, nil
+	})
+
+	result, err := h.RollbackAction("action-idx-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, models.StatusRolledBack, result.Status)
+	assert.True(t, result.Rolledback)
+	assert.Equal(t, "idx_orders_customer_id", dropped, "the recorded index is dropped, not one named after the rebuilt action")
+	assert.Equal(t, models.StatusRolledBack, service.status("action-idx-1"))
+}
+
+func TestRollbackAction_RebuildsDeployFromKnowledge(t *testing.T) {
+	service, client := startRecordedActionKnowledge(t, &pb.Action{
+		Id:          "action-redis-1",
+		DetectionId: "det-1",
+		ActionType:  "deploy_redis",
+		DatabaseId:  "shop-db",
+		Changes: recordedChanges(t, map[string]interface{}{
+			"container_name": "startupmonkey-redis-shop-db",
+			"redis_port":     6380,
+		}),
+	})
+
+	runtime := &fakeDockerRuntime{
+		existing: map[string]string{"startupmonkey-redis-shop-db": "container-123"},
+		running:  true,
+	}
+
+	h := handler.NewDetectionHandler(nil, client, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+	h.SetDocker(runtime, nil)
+
+	// Forced, so the client check does not try to reach Redis
+	result, err := h.ForceRollbackAction("action-redis-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, models.StatusRolledBack, result.Status)
+	assert.Equal(t, "container-123", runtime.removedID)
+	assert.False(t, runtime.running)
+	assert.Equal(t, models.StatusRolledBack, service.status("action-redis-1"))
+}
+
+func TestRollbackAction_InsufficientRecordedState(t *testing.T) {
+	tests := []struct {
+		name       string
+		actionType string
+		changes    map[string]interface{}
+	}{
+		{"create_index without table_name", "create_index", map[string]interface{}{"index_name": "idx_orders_customer_id"}},
+		{"create_index without changes", "create_index", nil},
+		{"deploy without container_name", "deploy_redis", map[string]interface{}{"redis_port": 6380}},
+		{"action that cannot be reconstructed", "terminate_query", map[string]interface{}{"pid": 42}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := &pb.Action{Id: "action-1", DetectionId: "det-1", ActionType: tt.actionType, DatabaseId: "shop-db"}
+			if tt.changes != nil {
+				record.Changes = recordedChanges(t, tt.changes)
+			}
+			service, client := startRecordedActionKnowledge(t, record)
+
+			adapter := &MockDatabaseAdapter{IndexExistsValue: true}
+			h := handler.NewDetectionHandler(nil, client, 1, time.Minute)
+			defer h.Shutdown(time.Second)
+			h.SetAdapterFactory(func(ctx context.Context, databaseType, connectionString, databaseID string) (database.DatabaseAdapter, error) {
+				return adapter, nil
+			})
+			h.SetDocker(&fakeDockerRuntime{}, nil)
+
+			result, err := h.RollbackAction("action-1")
+
+			assert.ErrorIs(t, err, handler.ErrInsufficientRollbackState)
+			require.NotNil(t, result)
+			assert.Equal(t, models.StatusRollbackFailed, result.Status)
+			assert.False(t, adapter.DropIndexCalled)
+			assert.Equal(t, models.StatusRollbackFailed, service.status("action-1"))
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/actions"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/database"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/handler"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// explainingMockAdapter plans every query with a fixed plan.
+type explainingMockAdapter struct {
+	*MockDatabaseAdapter
+	plan       *database.QueryPlan
+	explainErr error
+	explained  []string
+}
+
+func (m *explainingMockAdapter) ExplainQuery(ctx context.Context, query string) (*database.QueryPlan, error) {
+	m.explained = append(m.explained, query)
+	return m.plan, m.explainErr
+}
+
+func slowQueryMetadata() *models.ActionMetadata {
+	return &models.ActionMetadata{
+		ActionID:     "report-1",
+		ActionType:   "report_slow_queries",
+		DatabaseID:   "test-db",
+		DatabaseType: "postgres",
+		CreatedAt:    time.Now(),
+	}
+}
+
+var ordersSlowQueries = []database.SlowQuery{
+	{QueryPattern: "SELECT * FROM orders WHERE customer_id = $1", ExecutionTimeMs: 800, CallCount: 100, IssueType: "inefficient_select", Recommendation: "Select only required columns instead of SELECT *"},
+	{QueryPattern: "SELECT * FROM users WHERE id IN ($1, $2, $3)", ExecutionTimeMs: 600, CallCount: 10, IssueType: "inefficient_select", Recommendation: "Select only required columns instead of SELECT *"},
+	{QueryPattern: "select *  from orders where customer_id = ?", ExecutionTimeMs: 1200, CallCount: 50, IssueType: "inefficient_select", Recommendation: "Select only required columns instead of SELECT *"},
+	{QueryPattern: "SELECT * FROM users WHERE id IN ($1, $2)", ExecutionTimeMs: 700, CallCount: 5, IssueType: "inefficient_select", Recommendation: "Select only required columns instead of SELECT *"},
+}
+
+func TestFingerprintQuery_IgnoresValuesAndFormatting(t *testing.T) {
+	assert.Equal(t,
+		actions.FingerprintQuery("SELECT * FROM orders WHERE id = 42 AND status = 'open'"),
+		actions.FingerprintQuery("select *\n  from orders where id = $1 and status = $2;"))
+	assert.Equal(t,
+		actions.FingerprintQuery("SELECT name FROM users WHERE id IN ($1, $2, $3)"),
+		actions.FingerprintQuery("SELECT name FROM users WHERE id IN (?)"))
+	assert.NotEqual(t,
+		actions.FingerprintQuery("SELECT * FROM orders WHERE id = $1"),
+		actions.FingerprintQuery("SELECT * FROM orders2 WHERE id = $1"))
+}
+
+func TestGroupSlowQueries_GroupsByFingerprintWorstFirst(t *testing.T) {
+	groups := actions.GroupSlowQueries(ordersSlowQueries)
+
+	require.Len(t, groups, 2)
+	assert.Equal(t, 2, groups[0].Variants)
+	assert.Equal(t, int64(150), groups[0].Calls)
+	assert.Equal(t, 1200.0, groups[0].MaxExecutionTimeMs)
+	assert.Equal(t, 140000.0, groups[0].TotalTimeMs)
+	assert.Equal(t, "select *  from orders where customer_id = ?", groups[0].Example, "the slowest variant is the example")
+
+	assert.Equal(t, 2, groups[1].Variants)
+	assert.Equal(t, int64(15), groups[1].Calls)
+}
+
+func TestReportSlowQueriesAction_ReportsWithoutChangingAnything(t *testing.T) {
+	mock := &MockDatabaseAdapter{GetSlowQueriesResult: ordersSlowQueries}
+	action := actions.NewReportSlowQueriesAction(slowQueryMetadata(), mock, actions.DefaultSlowQueryOptions())
+
+	require.NoError(t, action.Validate(context.Background()))
+	result, err := action.Execute(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, result.Status)
+	assert.Equal(t, "report_slow_queries", result.ActionType)
+	assert.False(t, result.CanRollback)
+	assert.Contains(t, result.Message, "Found 4 slow queries in 2 groups")
+	assert.False(t, mock.SetConfigCalled)
+
+	groups := result.Changes["slow_query_report"].([]actions.SlowQueryGroup)
+	assert.Len(t, groups, 2)
+	recommendations := result.Changes["recommendations"].([]actions.Recommendation)
+	require.Len(t, recommendations, 2)
+	assert.Equal(t, "medium", recommendations[0].RiskLevel)
+	assert.True(t, recommendations[0].RequiresCodeChange)
+	assert.NotContains(t, result.Changes, "explain_error", "adapters without EXPLAIN are not an error")
+
+	assert.Error(t, action.Rollback(context.Background()))
+}
+
+func TestReportSlowQueriesAction_NotLimitedOrHeldForWindow(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+
+	// The database's only slot is taken and the maintenance window is closed
+	limiter := handler.NewActionLimiter(time.Hour, 1)
+	limiter.Reserve("test-db", time.Now())
+	h.SetActionLimiter(limiter)
+
+	now := time.Now().UTC()
+	closed := now.Add(2*time.Hour).Format("15:04") + "-" + now.Add(3*time.Hour).Format("15:04")
+	window, err := handler.ParseMaintenanceWindow(closed, "UTC")
+	require.NoError(t, err)
+	h.SetMaintenanceWindow(window, nil)
+
+	mock := &MockDatabaseAdapter{GetSlowQueriesResult: ordersSlowQueries}
+	action := actions.NewReportSlowQueriesAction(slowQueryMetadata(), mock, actions.DefaultSlowQueryOptions())
+	h.ExecuteActionDirectly(action, &models.Detection{DetectionID: "det-report", Severity: "low"})
+
+	waitForStatus(t, h, "report-1", models.StatusCompleted)
+}
+
+func TestReportSlowQueriesAction_ExplainsWorstQuery(t *testing.T) {
+	mock := &explainingMockAdapter{
+		MockDatabaseAdapter: &MockDatabaseAdapter{GetSlowQueriesResult: ordersSlowQueries},
+		plan: &database.QueryPlan{SeqScans: []database.PlannedScan{
+			{Table: "orders", Filter: "(customer_id = $1)", Columns: []string{"customer_id"}},
+			{Table: "regions"},
+		}},
+	}
+	action := actions.NewReportSlowQueriesAction(slowQueryMetadata(), mock, actions.DefaultSlowQueryOptions())
+
+	result, err := action.Execute(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"select *  from orders where customer_id = ?"}, mock.explained, "only the worst query is explained")
+
+	groups := result.Changes["slow_query_report"].([]actions.SlowQueryGroup)
+	assert.Equal(t, "missing_index", groups[0].IssueType)
+	assert.Contains(t, groups[0].Recommendation, "orders (customer_id)")
+	require.Len(t, groups[0].IndexCandidates, 1, "unfiltered scans are not index candidates")
+
+	recommendations := result.Changes["recommendations"].([]actions.Recommendation)
+	assert.Equal(t, "safe", recommendations[0].RiskLevel)
+	assert.Equal(t, "create_index", recommendations[0].DeployableActionType)
+	assert.Equal(t, []string{"CREATE INDEX CONCURRENTLY ON orders (customer_id)"}, recommendations[0].Steps)
+}
+
+func TestReportSlowQueriesAction_ExplainRefused(t *testing.T) {
+	mock := &explainingMockAdapter{
+		MockDatabaseAdapter: &MockDatabaseAdapter{GetSlowQueriesResult: ordersSlowQueries},
+		explainErr:          errors.New("permission denied for table orders"),
+	}
+	options := actions.DefaultSlowQueryOptions()
+	action := actions.NewReportSlowQueriesAction(slowQueryMetadata(), mock, options)
+
+	result, err := action.Execute(context.Background())
+
+	require.NoError(t, err, "the report completes without the plan")
+	assert.Contains(t, result.Changes["explain_error"], "permission denied")
+
+	options.Explain = false
+	mock.explained = nil
+	_, err = actions.NewReportSlowQueriesAction(slowQueryMetadata(), mock, options).Execute(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, mock.explained)
+}
+
+func TestReportSlowQueriesAction_FailsWithoutSlowQueries(t *testing.T) {
+	mock := &MockDatabaseAdapter{GetSlowQueriesError: errors.New("pg_stat_statements is not installed")}
+	action := actions.NewReportSlowQueriesAction(slowQueryMetadata(), mock, actions.DefaultSlowQueryOptions())
+
+	_, err := action.Execute(context.Background())
+	assert.ErrorContains(t, err, "pg_stat_statements")
+}
+
+func TestPlanFromExplainJSON_FindsFilteredSeqScans(t *testing.T) {
+	explain := `[{"Plan": {"Node Type": "Hash Join", "Total Cost": 1234.5, "Plans": [
+		{"Node Type": "Seq Scan", "Relation Name": "orders", "Filter": "((customer_id = $1) AND ((status)::text = 'open'::text))"},
+		{"Node Type": "Hash", "Plans": [{"Node Type": "Seq Scan", "Relation Name": "regions"}]},
+		{"Node Type": "Index Scan", "Relation Name": "users", "Index Name": "users_pkey"}
+	]}}]`
+
+	plan, err := database.PlanFromExplainJSON([]byte(explain))
+
+	require.NoError(t, err)
+	assert.Equal(t, 1234.5, plan.TotalCost)
+	require.Len(t, plan.SeqScans, 2)
+	assert.Equal(t, "orders", plan.SeqScans[0].Table)
+	assert.Equal(t, []string{"customer_id", "status"}, plan.SeqScans[0].Columns)
+	assert.Equal(t, "regions", plan.SeqScans[1].Table)
+	assert.Empty(t, plan.SeqScans[1].Columns)
+}
+
+// slowQueryFactory opens adapters that cannot tune config but can report slow queries.
+func slowQueryFactory(ctx context.Context, databaseType, connectionString, databaseID string) (database.DatabaseAdapter, error) {
+	return &MockDatabaseAdapter{GetSlowQueriesResult: ordersSlowQueries}, nil
+}
+
+func TestDetectionHandler_TuneConfigFallsBackToSlowQueryReport(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, startFakeKnowledge(t, "mongodb"), 1, time.Minute)
+	defer h.Shutdown(time.Second)
+	h.SetAdapterFactory(slowQueryFactory)
+
+	result, err := h.HandleDetection(&models.Detection{
+		DetectionID: "det-latency",
+		ActionType:  "tune_config_high_latency",
+		DatabaseID:  "test-db",
+		Severity:    "warning",
+		ActionMetaData: map[string]interface{}{
+			"fallback_action_type":    "report_slow_queries",
+			"slow_query_threshold_ms": 250.0,
+		},
+	})
+	require.NoError(t, err)
+
+	completed := waitForStatus(t, h, result.ActionID, models.StatusCompleted)
+	assert.Equal(t, "report_slow_queries", completed.ActionType)
+	assert.Equal(t, "tune_config_high_latency", completed.Changes["replaces_action_type"])
+	assert.Equal(t, 250.0, completed.Changes["threshold_ms"])
+}
+
+func TestDetectionHandler_TuneConfigSizesWorkMemFromSpill(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, startFakeKnowledge(t, "postgres"), 1, time.Minute)
+	defer h.Shutdown(time.Second)
+	h.SetAdapterFactory(func(ctx context.Context, databaseType, connectionString, databaseID string) (database.DatabaseAdapter, error) {
+		return &MockDatabaseAdapter{
+			Capabilities: database.Capabilities{
+				SupportsConfigTuning:         true,
+				SupportsRuntimeConfigChanges: true,
+			},
+			GetCurrentConfigResult: map[string]string{"work_mem": "4MB"},
+		}, nil
+	})
+
+	result, err := h.HandleDetection(&models.Detection{
+		DetectionID: "det-spill",
+		ActionType:  "tune_config_high_latency",
+		DatabaseID:  "test-db",
+		Severity:    "warning",
+		ActionMetaData: map[string]interface{}{
+			"avg_spill_bytes":      float64(32 << 20),
+			"fallback_action_type": "report_slow_queries",
+		},
+	})
+	require.NoError(t, err)
+
+	completed := waitForStatus(t, h, result.ActionID, models.StatusCompleted)
+	assert.Equal(t, "tune_config_high_latency", completed.ActionType)
+	assert.Equal(t, map[string]string{"work_mem": "64MB"}, completed.Changes["config_changes"])
+}
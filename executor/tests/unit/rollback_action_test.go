@@ -0,0 +1,112 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/handler"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/executor/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rollbackableAction completes immediately and counts rollbacks.
+type rollbackableAction struct {
+	id          string
+	rollbackErr error
+	rollbacks   int
+}
+
+func (a *rollbackableAction) Execute(ctx context.Context) (*models.ActionResult, error) {
+	return &models.ActionResult{
+		ActionID:    a.id,
+		ActionType:  "test",
+		Status:      models.StatusCompleted,
+		CanRollback: true,
+	}, nil
+}
+
+func (a *rollbackableAction) Rollback(ctx context.Context) error {
+	a.rollbacks++
+	return a.rollbackErr
+}
+
+func (a *rollbackableAction) Validate(ctx context.Context) error { return nil }
+func (a *rollbackableAction) GetMetadata() *models.ActionMetadata {
+	return &models.ActionMetadata{ActionID: a.id, ActionType: "test", DatabaseID: "test-db", CreatedAt: time.Now()}
+}
+
+func TestRollbackAction_DuplicateRequestIsIgnored(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+
+	action := &rollbackableAction{id: "rb-1"}
+	h.ExecuteActionDirectly(action, &models.Detection{DetectionID: "det-rb-1"})
+	waitForStatus(t, h, "rb-1", models.StatusCompleted)
+
+	result, err := h.RollbackAction("rb-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRolledBack, result.Status)
+
+	// A redelivered request must not fail or roll back a second time
+	result, err = h.RollbackAction("rb-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRolledBack, result.Status)
+	assert.Equal(t, 1, action.rollbacks)
+}
+
+func TestRollbackAction_FailureMarksRollbackFailed(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+
+	action := &rollbackableAction{id: "rb-2", rollbackErr: errors.New("index is in use")}
+	h.ExecuteActionDirectly(action, &models.Detection{DetectionID: "det-rb-2"})
+	waitForStatus(t, h, "rb-2", models.StatusCompleted)
+
+	result, err := h.RollbackAction("rb-2")
+	require.Error(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, models.StatusRollbackFailed, result.Status)
+	assert.Contains(t, result.RollbackError, "index is in use")
+
+	stored, err := h.GetActionStatus("rb-2")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRollbackFailed, stored.Status)
+
+	// Once the cause is fixed the rollback can be retried
+	action.rollbackErr = nil
+	result, err = h.RollbackAction("rb-2")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRolledBack, result.Status)
+	assert.Empty(t, result.RollbackError)
+}
+
+func TestRollbackAction_RejectsActionNotCompleted(t *testing.T) {
+	var running, peak int32
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+
+	blocked := newBlockingAction("rb-3", &running, &peak)
+	h.ExecuteActionDirectly(blocked, &models.Detection{DetectionID: "det-rb-3"})
+	waitForStatus(t, h, "rb-3", models.StatusExecuting)
+	defer close(blocked.release)
+
+	_, err := h.RollbackAction("rb-3")
+	assert.Error(t, err)
+}
+
+func TestRollbackFailedVerification_RecordsReason(t *testing.T) {
+	h := handler.NewDetectionHandler(nil, nil, 1, time.Minute)
+	defer h.Shutdown(time.Second)
+
+	action := &rollbackableAction{id: "rb-4"}
+	h.ExecuteActionDirectly(action, &models.Detection{DetectionID: "det-rb-4"})
+	waitForStatus(t, h, "rb-4", models.StatusCompleted)
+
+	result, err := h.RollbackFailedVerification("rb-4", "Issue re-detected after action completion")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRolledBack, result.Status)
+	assert.Contains(t, result.Message, "Issue re-detected")
+}
@@ -12,9 +12,19 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+func runningBackend() *database.BackendInfo {
+	return &database.BackendInfo{
+		PID:      12345,
+		Username: "app_user",
+		Query:    "SELECT pg_sleep(600)",
+		State:    "active",
+	}
+}
+
 func TestTerminateQueryAction_ExecuteSuccess(t *testing.T) {
 	mock := &MockDatabaseAdapter{
 		Capabilities: database.Capabilities{SupportsQueryTermination: true},
+		BackendInfo:  runningBackend(),
 	}
 
 	metadata := &models.ActionMetadata{
@@ -24,7 +34,7 @@ func TestTerminateQueryAction_ExecuteSuccess(t *testing.T) {
 		CreatedAt:  time.Now(),
 	}
 
-	action := actions.NewTerminateQueryAction(metadata, mock, 12345, "app_user", true)
+	action := actions.NewTerminateQueryAction(metadata, mock, 12345, "app_user", "SELECT pg_sleep(600)", true, actions.DefaultTerminateSettings())
 
 	result, err := action.Execute(context.Background())
 
@@ -40,6 +50,7 @@ func TestTerminateQueryAction_ExecuteFailure(t *testing.T) {
 	mock := &MockDatabaseAdapter{
 		Capabilities:   database.Capabilities{SupportsQueryTermination: true},
 		TerminateError: errors.New("permission denied"),
+		BackendInfo:    runningBackend(),
 	}
 
 	metadata := &models.ActionMetadata{
@@ -49,7 +60,7 @@ func TestTerminateQueryAction_ExecuteFailure(t *testing.T) {
 		CreatedAt:  time.Now(),
 	}
 
-	action := actions.NewTerminateQueryAction(metadata, mock, 12345, "app_user", true)
+	action := actions.NewTerminateQueryAction(metadata, mock, 12345, "app_user", "SELECT pg_sleep(600)", true, actions.DefaultTerminateSettings())
 
 	result, err := action.Execute(context.Background())
 
@@ -70,6 +81,7 @@ func TestTerminateQueryAction_GracefulFallbackToForceful(t *testing.T) {
 			}
 			return nil // Forceful succeeds
 		},
+		BackendInfo: runningBackend(),
 	}
 
 	metadata := &models.ActionMetadata{
@@ -79,7 +91,7 @@ func TestTerminateQueryAction_GracefulFallbackToForceful(t *testing.T) {
 		CreatedAt:  time.Now(),
 	}
 
-	action := actions.NewTerminateQueryAction(metadata, mock, 12345, "app_user", true)
+	action := actions.NewTerminateQueryAction(metadata, mock, 12345, "app_user", "SELECT pg_sleep(600)", true, actions.DefaultTerminateSettings())
 
 	result, err := action.Execute(context.Background())
 
@@ -102,7 +114,7 @@ func TestTerminateQueryAction_ValidateNoSupport(t *testing.T) {
 		CreatedAt:  time.Now(),
 	}
 
-	action := actions.NewTerminateQueryAction(metadata, mock, 12345, "app_user", true)
+	action := actions.NewTerminateQueryAction(metadata, mock, 12345, "app_user", "SELECT pg_sleep(600)", true, actions.DefaultTerminateSettings())
 
 	err := action.Validate(context.Background())
 
@@ -122,7 +134,7 @@ func TestTerminateQueryAction_ValidateInvalidPID(t *testing.T) {
 		CreatedAt:  time.Now(),
 	}
 
-	action := actions.NewTerminateQueryAction(metadata, mock, 0, "app_user", true)
+	action := actions.NewTerminateQueryAction(metadata, mock, 0, "app_user", "SELECT pg_sleep(600)", true, actions.DefaultTerminateSettings())
 
 	err := action.Validate(context.Background())
 
@@ -142,9 +154,286 @@ func TestTerminateQueryAction_RollbackIsNoop(t *testing.T) {
 		CreatedAt:  time.Now(),
 	}
 
-	action := actions.NewTerminateQueryAction(metadata, mock, 12345, "app_user", true)
+	action := actions.NewTerminateQueryAction(metadata, mock, 12345, "app_user", "SELECT pg_sleep(600)", true, actions.DefaultTerminateSettings())
 
 	err := action.Rollback(context.Background())
 
 	assert.NoError(t, err)
 }
+
+func TestTerminateQueryAction_StalePIDDifferentQuery(t *testing.T) {
+	mock := &MockDatabaseAdapter{
+		Capabilities: database.Capabilities{SupportsQueryTermination: true},
+		BackendInfo: &database.BackendInfo{
+			PID:      12345,
+			Username: "app_user",
+			Query:    "SELECT * FROM orders WHERE id = 1",
+			State:    "active",
+		},
+	}
+
+	metadata := &models.ActionMetadata{
+		ActionID:   "test-action-7",
+		ActionType: "terminate_query",
+		DatabaseID: "test-db",
+		CreatedAt:  time.Now(),
+	}
+
+	action := actions.NewTerminateQueryAction(metadata, mock, 12345, "app_user", "SELECT pg_sleep(600)", true, actions.DefaultTerminateSettings())
+
+	result, err := action.Execute(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, result.Status)
+	assert.Contains(t, result.Message, "Stale PID")
+	assert.Equal(t, false, result.Changes["terminated"])
+	assert.False(t, mock.TerminateCalled, "Should not terminate a recycled PID")
+}
+
+func TestTerminateQueryAction_StalePIDDifferentUser(t *testing.T) {
+	mock := &MockDatabaseAdapter{
+		Capabilities: database.Capabilities{SupportsQueryTermination: true},
+		BackendInfo: &database.BackendInfo{
+			PID:      12345,
+			Username: "reporting_user",
+			Query:    "SELECT pg_sleep(600)",
+			State:    "active",
+		},
+	}
+
+	metadata := &models.ActionMetadata{
+		ActionID:   "test-action-8",
+		ActionType: "terminate_query",
+		DatabaseID: "test-db",
+		CreatedAt:  time.Now(),
+	}
+
+	action := actions.NewTerminateQueryAction(metadata, mock, 12345, "app_user", "SELECT pg_sleep(600)", true, actions.DefaultTerminateSettings())
+
+	result, err := action.Execute(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, result.Status)
+	assert.Contains(t, result.Message, "Stale PID")
+	assert.False(t, mock.TerminateCalled)
+}
+
+func TestTerminateQueryAction_BackendGone(t *testing.T) {
+	mock := &MockDatabaseAdapter{
+		Capabilities: database.Capabilities{SupportsQueryTermination: true},
+	}
+
+	metadata := &models.ActionMetadata{
+		ActionID:   "test-action-9",
+		ActionType: "terminate_query",
+		DatabaseID: "test-db",
+		CreatedAt:  time.Now(),
+	}
+
+	action := actions.NewTerminateQueryAction(metadata, mock, 12345, "app_user", "SELECT pg_sleep(600)", true, actions.DefaultTerminateSettings())
+
+	result, err := action.Execute(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, result.Status)
+	assert.Equal(t, "backend no longer exists", result.Changes["reason"])
+	assert.False(t, mock.TerminateCalled)
+}
+
+func TestTerminateQueryAction_TruncatedQueryMatches(t *testing.T) {
+	mock := &MockDatabaseAdapter{
+		Capabilities: database.Capabilities{SupportsQueryTermination: true},
+		BackendInfo: &database.BackendInfo{
+			PID:      12345,
+			Username: "app_user",
+			Query:    "SELECT *\n  FROM orders WHERE created_at > now() - interval '1 day'",
+			State:    "active",
+		},
+	}
+
+	metadata := &models.ActionMetadata{
+		ActionID:   "test-action-10",
+		ActionType: "terminate_query",
+		DatabaseID: "test-db",
+		CreatedAt:  time.Now(),
+	}
+
+	action := actions.NewTerminateQueryAction(metadata, mock, 12345, "app_user", "SELECT * FROM orders", true, actions.DefaultTerminateSettings())
+
+	result, err := action.Execute(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, result.Status)
+	assert.Equal(t, true, result.Changes["terminated"])
+	assert.True(t, mock.TerminateCalled)
+}
+
+func TestTerminateQueryAction_BackendLookupFails(t *testing.T) {
+	mock := &MockDatabaseAdapter{
+		Capabilities:     database.Capabilities{SupportsQueryTermination: true},
+		BackendInfoError: errors.New("connection refused"),
+	}
+
+	metadata := &models.ActionMetadata{
+		ActionID:   "test-action-11",
+		ActionType: "terminate_query",
+		DatabaseID: "test-db",
+		CreatedAt:  time.Now(),
+	}
+
+	action := actions.NewTerminateQueryAction(metadata, mock, 12345, "app_user", "SELECT pg_sleep(600)", true, actions.DefaultTerminateSettings())
+
+	result, err := action.Execute(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusFailed, result.Status)
+	assert.False(t, mock.TerminateCalled, "Should not terminate when the backend cannot be verified")
+}
+
+func idleTransactionBackend() *database.BackendInfo {
+	xactStart := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &database.BackendInfo{
+		PID:             12345,
+		Username:        "app_user",
+		Query:           "UPDATE orders SET status = 'paid'",
+		State:           "idle in transaction",
+		ApplicationName: "orders-api",
+		ClientAddr:      "10.0.0.7",
+		XactStart:       &xactStart,
+	}
+}
+
+func terminateMetadata(actionID string) *models.ActionMetadata {
+	return &models.ActionMetadata{
+		ActionID:   actionID,
+		ActionType: "terminate_query",
+		DatabaseID: "test-db",
+		CreatedAt:  time.Now(),
+	}
+}
+
+func TestTerminateQueryAction_CapturesSessionState(t *testing.T) {
+	mock := &MockDatabaseAdapter{
+		Capabilities: database.Capabilities{SupportsQueryTermination: true},
+		BackendInfo:  idleTransactionBackend(),
+	}
+
+	action := actions.NewTerminateQueryAction(terminateMetadata("test-action-12"), mock, 12345, "app_user",
+		"UPDATE orders SET status = 'paid'", false, actions.DefaultTerminateSettings())
+
+	result, err := action.Execute(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, result.Status)
+	assert.Equal(t, "pg_terminate_backend", result.Changes["method"])
+
+	session, ok := result.Changes["session"].(map[string]interface{})
+	assert.True(t, ok, "Session state should be captured in Changes")
+	assert.Equal(t, "2026-01-02T03:04:05Z", session["xact_start"])
+	assert.Equal(t, "UPDATE orders SET status = 'paid'", session["query"])
+	assert.Equal(t, "orders-api", session["application_name"])
+	assert.Equal(t, "10.0.0.7", session["client_addr"])
+}
+
+func TestTerminateQueryAction_RefusesProtectedSessions(t *testing.T) {
+	tests := []struct {
+		name     string
+		backend  func(*database.BackendInfo)
+		settings actions.TerminateSettings
+		reason   string
+	}{
+		{
+			name:     "superuser",
+			backend:  func(b *database.BackendInfo) { b.IsSuperuser = true },
+			settings: actions.DefaultTerminateSettings(),
+			reason:   "superuser",
+		},
+		{
+			name:     "migration application",
+			backend:  func(b *database.BackendInfo) { b.ApplicationName = "Flyway 9.22" },
+			settings: actions.DefaultTerminateSettings(),
+			reason:   "denylist",
+		},
+		{
+			name:     "denied user",
+			backend:  func(b *database.BackendInfo) {},
+			settings: actions.TerminateSettings{DenyUsers: []string{"app_*"}},
+			reason:   "denylist",
+		},
+		{
+			name:     "application not allowed",
+			backend:  func(b *database.BackendInfo) {},
+			settings: actions.TerminateSettings{AllowApplications: []string{"reporting"}},
+			reason:   "allowlist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := idleTransactionBackend()
+			tt.backend(backend)
+			mock := &MockDatabaseAdapter{
+				Capabilities: database.Capabilities{SupportsQueryTermination: true},
+				BackendInfo:  backend,
+			}
+
+			action := actions.NewTerminateQueryAction(terminateMetadata("test-action-13"), mock, 12345, "app_user",
+				"UPDATE orders SET status = 'paid'", false, tt.settings)
+
+			result, err := action.Execute(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, models.StatusCompleted, result.Status)
+			assert.Equal(t, false, result.Changes["terminated"])
+			assert.Contains(t, result.Changes["reason"], tt.reason)
+			assert.NotNil(t, result.Changes["session"])
+			assert.False(t, mock.TerminateCalled)
+		})
+	}
+}
+
+func TestTerminateQueryAction_CancelFirstThenTerminatesIdleTransaction(t *testing.T) {
+	var calls []bool
+	mock := &MockDatabaseAdapter{
+		Capabilities: database.Capabilities{SupportsQueryTermination: true},
+		TerminateFunc: func(pid int32, graceful bool) error {
+			calls = append(calls, graceful)
+			return nil
+		},
+		BackendInfo: idleTransactionBackend(), // Still idle in transaction after the cancel
+	}
+
+	settings := actions.DefaultTerminateSettings()
+	settings.CancelFirst = true
+	action := actions.NewTerminateQueryAction(terminateMetadata("test-action-14"), mock, 12345, "app_user",
+		"UPDATE orders SET status = 'paid'", false, settings)
+
+	result, err := action.Execute(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, result.Status)
+	assert.Equal(t, []bool{true, false}, calls, "Should cancel, then terminate the still-idle session")
+	assert.Contains(t, result.Changes["method"], "fallback")
+}
+
+func TestTerminateQueryAction_CancelFirstStopsWhenSessionEnds(t *testing.T) {
+	mock := &MockDatabaseAdapter{
+		Capabilities: database.Capabilities{SupportsQueryTermination: true},
+		BackendInfo:  idleTransactionBackend(),
+	}
+	mock.TerminateFunc = func(pid int32, graceful bool) error {
+		mock.BackendInfo = nil // The cancel ended the session
+		return nil
+	}
+
+	settings := actions.DefaultTerminateSettings()
+	settings.CancelFirst = true
+	action := actions.NewTerminateQueryAction(terminateMetadata("test-action-15"), mock, 12345, "app_user",
+		"UPDATE orders SET status = 'paid'", false, settings)
+
+	result, err := action.Execute(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, result.Status)
+	assert.Equal(t, "pg_cancel_backend", result.Changes["method"])
+}
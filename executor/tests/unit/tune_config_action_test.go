@@ -307,3 +307,110 @@ func TestTuneConfigAction_GetMetadata(t *testing.T) {
 	assert.Equal(t, "test-db", metadata.DatabaseID)
 	assert.Equal(t, "postgres", metadata.DatabaseType)
 }
+
+func TestTuneConfigAction_RestoreThenRollback(t *testing.T) {
+	mock := &MockDatabaseAdapter{
+		Capabilities:           database.Capabilities{SupportsConfigTuning: true},
+		GetCurrentConfigResult: map[string]string{"work_mem": "16MB"},
+	}
+
+	created, _ := actions.NewTuneConfigAction("action-1", "detection-1", "test-db", "postgres", mock)
+	action := created.(*actions.TuneConfigAction)
+
+	// Changes as decoded from JSON stored in Knowledge
+	restored, err := action.Restore(context.Background(), map[string]interface{}{
+		"original_config": map[string]interface{}{"work_mem": "4MB"},
+		"config_changes":  map[string]interface{}{"work_mem": "16MB"},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, restored)
+
+	err = action.Rollback(context.Background())
+
+	assert.NoError(t, err)
+	assert.True(t, mock.SetConfigCalled)
+}
+
+func TestTuneConfigAction_RestoreConfigChangedSince(t *testing.T) {
+	mock := &MockDatabaseAdapter{
+		Capabilities:           database.Capabilities{SupportsConfigTuning: true},
+		GetCurrentConfigResult: map[string]string{"work_mem": "64MB"},
+	}
+
+	created, _ := actions.NewTuneConfigAction("action-1", "detection-1", "test-db", "postgres", mock)
+	action := created.(*actions.TuneConfigAction)
+
+	restored, err := action.Restore(context.Background(), map[string]interface{}{
+		"original_config": map[string]interface{}{"work_mem": "4MB"},
+		"config_changes":  map[string]interface{}{"work_mem": "16MB"},
+	})
+
+	assert.NoError(t, err)
+	assert.False(t, restored, "Restore should not reclaim config someone else has since changed")
+}
+
+func TestTuneConfigAction_SizesWorkMemFromTempSpill(t *testing.T) {
+	cases := []struct {
+		name          string
+		current       string
+		avgSpillBytes float64
+		want          string // "" for no change
+	}{
+		{"default plus spill, rounded up", "4MB", 32 << 20, "64MB"},
+		{"small spill stays small", "4MB", 1 << 20, "8MB"},
+		{"grows from a raised value", "64MB", 10 << 20, "128MB"},
+		{"kB units", "4096kB", 3 << 20, "8MB"},
+		{"capped", "4MB", 1 << 30, "256MB"},
+		{"already at the cap", "256MB", 5 << 20, ""},
+		{"already above the cap", "1GB", 5 << 20, ""},
+	}
+
+	for _, tc := range cases {
+		mock := &MockDatabaseAdapter{
+			Capabilities: database.Capabilities{
+				SupportsConfigTuning:         true,
+				SupportsRuntimeConfigChanges: true,
+			},
+			GetCurrentConfigResult: map[string]string{
+				"work_mem":             tc.current,
+				"effective_cache_size": "16GB",
+				"random_page_cost":     "1.1",
+			},
+		}
+
+		action, err := actions.NewTuneConfigAction("action-1", "detection-1", "test-db", "postgres", mock)
+		assert.NoError(t, err)
+		action.(*actions.TuneConfigAction).SetTempSpill(tc.avgSpillBytes)
+
+		result, err := action.Execute(context.Background())
+		assert.NoError(t, err, tc.name)
+
+		configChanges := result.Changes["config_changes"].(map[string]string)
+		if tc.want == "" {
+			assert.NotContains(t, configChanges, "work_mem", tc.name)
+		} else {
+			assert.Equal(t, tc.want, configChanges["work_mem"], tc.name)
+			assert.Equal(t, tc.avgSpillBytes, result.Changes["avg_spill_bytes"], tc.name)
+		}
+	}
+}
+
+func TestTuneConfigAction_WithoutSpillKeepsDefaultBump(t *testing.T) {
+	mock := &MockDatabaseAdapter{
+		Capabilities: database.Capabilities{
+			SupportsConfigTuning:         true,
+			SupportsRuntimeConfigChanges: true,
+		},
+		GetCurrentConfigResult: map[string]string{"work_mem": "8MB"},
+	}
+
+	action, err := actions.NewTuneConfigAction("action-1", "detection-1", "test-db", "postgres", mock)
+	assert.NoError(t, err)
+
+	result, err := action.Execute(context.Background())
+
+	assert.NoError(t, err)
+	assert.NotContains(t, result.Changes["config_changes"], "work_mem", "Only the 4MB default is raised without spill evidence")
+	assert.NotContains(t, result.Changes, "avg_spill_bytes")
+}
@@ -25,7 +25,7 @@ func TestVacuumTableAction_ExecuteSuccess(t *testing.T) {
 		CreatedAt:  time.Now(),
 	}
 
-	action := actions.NewVacuumTableAction(metadata, mock, "posts")
+	action := actions.NewVacuumTableAction(metadata, mock, "posts", actions.VacuumOptions{})
 
 	result, err := action.Execute(context.Background())
 
@@ -50,7 +50,7 @@ func TestVacuumTableAction_ExecuteFailure(t *testing.T) {
 		CreatedAt:  time.Now(),
 	}
 
-	action := actions.NewVacuumTableAction(metadata, mock, "posts")
+	action := actions.NewVacuumTableAction(metadata, mock, "posts", actions.VacuumOptions{})
 
 	result, err := action.Execute(context.Background())
 
@@ -72,7 +72,7 @@ func TestVacuumTableAction_ValidateNoVacuumSupport(t *testing.T) {
 		CreatedAt:  time.Now(),
 	}
 
-	action := actions.NewVacuumTableAction(metadata, mock, "posts")
+	action := actions.NewVacuumTableAction(metadata, mock, "posts", actions.VacuumOptions{})
 
 	err := action.Validate(context.Background())
 
@@ -92,7 +92,7 @@ func TestVacuumTableAction_ValidateMissingTableName(t *testing.T) {
 		CreatedAt:  time.Now(),
 	}
 
-	action := actions.NewVacuumTableAction(metadata, mock, "")
+	action := actions.NewVacuumTableAction(metadata, mock, "", actions.VacuumOptions{})
 
 	err := action.Validate(context.Background())
 
@@ -112,7 +112,7 @@ func TestVacuumTableAction_RollbackIsNoop(t *testing.T) {
 		CreatedAt:  time.Now(),
 	}
 
-	action := actions.NewVacuumTableAction(metadata, mock, "posts")
+	action := actions.NewVacuumTableAction(metadata, mock, "posts", actions.VacuumOptions{})
 
 	err := action.Rollback(context.Background())
 
@@ -132,7 +132,7 @@ func TestVacuumTableAction_RecordsTupleStats(t *testing.T) {
 		CreatedAt:  time.Now(),
 	}
 
-	action := actions.NewVacuumTableAction(metadata, mock, "posts")
+	action := actions.NewVacuumTableAction(metadata, mock, "posts", actions.VacuumOptions{})
 
 	result, err := action.Execute(context.Background())
 
@@ -141,3 +141,173 @@ func TestVacuumTableAction_RecordsTupleStats(t *testing.T) {
 	assert.Equal(t, "posts", result.Changes["table_name"])
 	assert.Equal(t, "VACUUM ANALYZE", result.Changes["operation"])
 }
+
+// tableMaintenanceMockAdapter records VACUUM FULL and storage parameter changes.
+type tableMaintenanceMockAdapter struct {
+	*MockDatabaseAdapter
+	fullCalled bool
+	fullError  error
+	sizes      []int64 // successive GetTableSize results
+	options    map[string]string
+	resets     []string
+}
+
+func (m *tableMaintenanceMockAdapter) VacuumTableFull(ctx context.Context, tableName string) error {
+	m.fullCalled = true
+	return m.fullError
+}
+
+func (m *tableMaintenanceMockAdapter) GetTableSize(ctx context.Context, tableName string) (int64, error) {
+	if len(m.sizes) == 0 {
+		return 0, errors.New("no size")
+	}
+	size := m.sizes[0]
+	m.sizes = m.sizes[1:]
+	return size, nil
+}
+
+func (m *tableMaintenanceMockAdapter) GetTableOptions(ctx context.Context, tableName string, options []string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, option := range options {
+		if value, ok := m.options[option]; ok {
+			values[option] = value
+		}
+	}
+	return values, nil
+}
+
+func (m *tableMaintenanceMockAdapter) SetTableOptions(ctx context.Context, tableName string, options map[string]string) error {
+	for name, value := range options {
+		m.options[name] = value
+	}
+	return nil
+}
+
+func (m *tableMaintenanceMockAdapter) ResetTableOptions(ctx context.Context, tableName string, options []string) error {
+	for _, name := range options {
+		delete(m.options, name)
+		m.resets = append(m.resets, name)
+	}
+	return nil
+}
+
+func newTableMaintenanceMock() *tableMaintenanceMockAdapter {
+	return &tableMaintenanceMockAdapter{
+		MockDatabaseAdapter: &MockDatabaseAdapter{
+			Capabilities: database.Capabilities{SupportsVacuum: true},
+			DeadTuples:   5000,
+		},
+		options: map[string]string{},
+	}
+}
+
+func vacuumMetadata() *models.ActionMetadata {
+	return &models.ActionMetadata{
+		ActionID:   "test-vacuum-mode",
+		ActionType: "vacuum_table",
+		DatabaseID: "test-db",
+		CreatedAt:  time.Now(),
+	}
+}
+
+func TestVacuumTableAction_FullRecordsLockWarningAndSizes(t *testing.T) {
+	mock := newTableMaintenanceMock()
+	mock.sizes = []int64{800 << 20, 200 << 20}
+
+	action := actions.NewVacuumTableAction(vacuumMetadata(), mock, "posts", actions.VacuumOptions{Mode: actions.VacuumModeFull})
+	assert.True(t, action.RequiresMaintenanceWindow())
+
+	result, err := action.Execute(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, result.Status)
+	assert.True(t, mock.fullCalled)
+	assert.False(t, mock.VacuumCalled, "full mode should not also run VACUUM ANALYZE")
+	assert.Equal(t, "VACUUM FULL ANALYZE", result.Changes["operation"])
+	assert.Contains(t, result.Changes["warning"], "ACCESS EXCLUSIVE lock")
+	assert.Equal(t, int64(600<<20), result.Changes["bytes_reclaimed"])
+	assert.False(t, result.CanRollback)
+}
+
+func TestVacuumTableAction_FullNeedsTableMaintenanceSupport(t *testing.T) {
+	mock := &MockDatabaseAdapter{Capabilities: database.Capabilities{SupportsVacuum: true}}
+
+	action := actions.NewVacuumTableAction(vacuumMetadata(), mock, "posts", actions.VacuumOptions{Mode: actions.VacuumModeFull})
+
+	assert.Equal(t, database.ErrActionNotSupported, action.Validate(context.Background()))
+}
+
+func TestVacuumTableAction_RejectsUnknownMode(t *testing.T) {
+	action := actions.NewVacuumTableAction(vacuumMetadata(), newTableMaintenanceMock(), "posts", actions.VacuumOptions{Mode: "aggressive"})
+
+	err := action.Validate(context.Background())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown vacuum mode")
+}
+
+func TestVacuumTableAction_TuneAutovacuumRollbackResetsDefault(t *testing.T) {
+	mock := newTableMaintenanceMock()
+
+	action := actions.NewVacuumTableAction(vacuumMetadata(), mock, "posts", actions.VacuumOptions{
+		Mode:                  actions.VacuumModeTuneAutovacuum,
+		AutovacuumScaleFactor: 0.02,
+	})
+	assert.False(t, action.RequiresMaintenanceWindow())
+
+	result, err := action.Execute(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, result.Status)
+	assert.True(t, result.CanRollback)
+	assert.False(t, mock.VacuumCalled)
+	assert.Equal(t, "0.02", mock.options["autovacuum_vacuum_scale_factor"])
+	assert.Equal(t, "", result.Changes["previous_autovacuum_vacuum_scale_factor"])
+
+	assert.NoError(t, action.Rollback(context.Background()))
+	assert.Equal(t, []string{"autovacuum_vacuum_scale_factor"}, mock.resets)
+	assert.NotContains(t, mock.options, "autovacuum_vacuum_scale_factor")
+}
+
+func TestVacuumTableAction_TuneAutovacuumRollbackRestoresPrevious(t *testing.T) {
+	mock := newTableMaintenanceMock()
+	mock.options["autovacuum_vacuum_scale_factor"] = "0.1"
+
+	action := actions.NewVacuumTableAction(vacuumMetadata(), mock, "posts", actions.VacuumOptions{Mode: actions.VacuumModeTuneAutovacuum})
+
+	result, err := action.Execute(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "0.05", mock.options["autovacuum_vacuum_scale_factor"], "default scale factor")
+	assert.Equal(t, "0.1", result.Changes["previous_autovacuum_vacuum_scale_factor"])
+
+	assert.NoError(t, action.Rollback(context.Background()))
+	assert.Equal(t, "0.1", mock.options["autovacuum_vacuum_scale_factor"])
+	assert.Empty(t, mock.resets)
+}
+
+func TestVacuumTableAction_RestoreTuneAutovacuum(t *testing.T) {
+	mock := newTableMaintenanceMock()
+	mock.options["autovacuum_vacuum_scale_factor"] = "0.02"
+
+	action := actions.NewVacuumTableAction(vacuumMetadata(), mock, "", actions.VacuumOptions{})
+	changes := map[string]interface{}{
+		"table_name":                     "posts",
+		"mode":                           actions.VacuumModeTuneAutovacuum,
+		"autovacuum_vacuum_scale_factor": "0.02",
+		"previous_autovacuum_vacuum_scale_factor": "0.1",
+	}
+
+	restored, err := action.Restore(context.Background(), changes)
+	assert.NoError(t, err)
+	assert.True(t, restored)
+
+	assert.NoError(t, action.Rollback(context.Background()))
+	assert.Equal(t, "0.1", mock.options["autovacuum_vacuum_scale_factor"])
+
+	// Changed again since, so there is nothing to roll back
+	mock.options["autovacuum_vacuum_scale_factor"] = "0.3"
+	restored, err = action.Restore(context.Background(), changes)
+	assert.NoError(t, err)
+	assert.False(t, restored)
+}
@@ -3,12 +3,14 @@ package main
 import (
 	"context"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/config"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/orchestrator"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/logging"
 )
 
 // main is the entry point for the Knowledge service.
@@ -24,7 +26,7 @@ import (
 // Lifecycle:
 //  1. Load configuration from environment variables
 //  2. Initialize orchestrator with Redis client and servers
-//  3. Start gRPC server (Knowledge API) and health check server
+//  3. Start health check server, then serve the gRPC Knowledge API
 //  4. Listen for shutdown signals (SIGINT, SIGTERM)
 //  5. Gracefully close all connections on shutdown
 func main() {
@@ -36,11 +38,14 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	logging.Setup("knowledge", cfg.LogLevel)
+
 	log.Printf("Configuration loaded successfully")
 	log.Printf("  gRPC Port: %s", cfg.GRPCPort)
 	log.Printf("  Health Port: %s", cfg.HealthPort)
 	log.Printf("  Redis Address: %s", cfg.RedisAddr)
 	log.Printf("  Redis DB: %d", cfg.RedisDB)
+	log.Printf("  Offline Threshold: %v", cfg.OfflineThreshold)
 	log.Printf("  Metrics Enabled: %v", cfg.EnableMetrics)
 
 	// Create orchestrator to manage service lifecycle
@@ -59,10 +64,10 @@ func main() {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
-	// Start gRPC and health check servers in background goroutine
+	// Serve gRPC and background maintenance in a goroutine
 	go func() {
 		if err := orch.Run(ctx); err != nil && err != context.Canceled {
-			log.Printf("Orchestrator error: %v", err)
+			slog.Error("Orchestrator error", "error", err)
 		}
 	}()
 
@@ -75,7 +80,7 @@ func main() {
 
 	// Close all connections and cleanup resources
 	if err := orch.Stop(); err != nil {
-		log.Printf("Error during shutdown: %v", err)
+		slog.Error("Error during shutdown", "error", err)
 	}
 
 	log.Printf("Knowledge service stopped successfully")
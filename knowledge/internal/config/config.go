@@ -3,8 +3,13 @@ package config
 import (
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/logging"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/transport"
 	"github.com/joho/godotenv"
 )
 
@@ -14,11 +19,37 @@ type Config struct {
 	GRPCPort   string
 	HealthPort string
 
+	// Mutual TLS for gRPC links (GRPC_TLS_*); plaintext when unset
+	GRPCTLS transport.TLSConfig
+
+	// Log verbosity (LOG_LEVEL: debug, info, warn, error)
+	LogLevel slog.Level
+
 	// Redis connection
 	RedisAddr     string
 	RedisPassword string
 	RedisDB       int
 
+	// Databases with no health report within this window are considered offline
+	OfflineThreshold time.Duration
+
+	// Active detections not seen again within this window are swept to stale
+	DetectionStaleAfter time.Duration
+
+	// How long IsDetectionActive answers are reused; 0 disables the cache
+	DetectionCacheTTL time.Duration
+
+	// Completed, failed and rolled back actions are purged this long after finishing, and
+	// resolved or stale detections this long after last being seen, every CleanupInterval
+	ActionRetention    time.Duration
+	DetectionRetention time.Duration
+	CleanupInterval    time.Duration
+
+	// Base64 AES-256 key for connection strings at rest; empty stores them in plaintext.
+	// Previous keys are only used to decrypt records not yet re-encrypted after a rotation.
+	EncryptionKey          string
+	PreviousEncryptionKeys []string
+
 	// Feature flags
 	EnableMetrics bool
 }
@@ -50,16 +81,34 @@ func Load() (*Config, error) {
 		// Service addresses with defaults
 		GRPCPort:   getEnvOrDefault("GRPC_PORT", "50053"),
 		HealthPort: getEnvOrDefault("HEALTH_PORT", "8083"),
+		GRPCTLS:    transport.TLSConfigFromEnv(),
 
 		// Redis connection with defaults
 		RedisAddr:     getEnvOrDefault("REDIS_ADDR", "localhost:6379"),
 		RedisPassword: os.Getenv("REDIS_PASSWORD"),
 		RedisDB:       parseIntOrDefault("REDIS_DB", 0),
 
+		OfflineThreshold:    parseDurationOrDefault("DATABASE_OFFLINE_THRESHOLD", 3*time.Minute),
+		DetectionStaleAfter: parseDurationOrDefault("DETECTION_STALE_AFTER", 30*time.Minute),
+		DetectionCacheTTL:   parseDurationOrDefault("DETECTION_CACHE_TTL", 2*time.Second),
+
+		ActionRetention:    parseDurationOrDefault("ACTION_RETENTION", 7*24*time.Hour),
+		DetectionRetention: parseDurationOrDefault("DETECTION_RETENTION", 24*time.Hour),
+		CleanupInterval:    parseDurationOrDefault("RETENTION_CLEANUP_INTERVAL", time.Hour),
+
+		EncryptionKey:          os.Getenv("KNOWLEDGE_ENCRYPTION_KEY"),
+		PreviousEncryptionKeys: parseListOrEmpty("KNOWLEDGE_ENCRYPTION_PREVIOUS_KEYS"),
+
 		// Feature flags
 		EnableMetrics: getEnvOrDefault("ENABLE_METRICS", "false") == "true",
 	}
 
+	logLevel, err := logging.ParseLevel(os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		return nil, err
+	}
+	config.LogLevel = logLevel
+
 	if err := config.Validate(); err != nil {
 		return nil, err
 	}
@@ -69,6 +118,10 @@ func Load() (*Config, error) {
 
 // Validate checks that required configuration is present.
 func (c *Config) Validate() error {
+	if err := c.GRPCTLS.Validate(); err != nil {
+		return err
+	}
+
 	if c.GRPCPort == "" {
 		return fmt.Errorf("GRPC_PORT is required")
 	}
@@ -77,6 +130,30 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("REDIS_ADDR is required")
 	}
 
+	if c.OfflineThreshold < 10*time.Second {
+		return fmt.Errorf("DATABASE_OFFLINE_THRESHOLD must be at least 10 seconds")
+	}
+
+	if c.DetectionStaleAfter < time.Minute {
+		return fmt.Errorf("DETECTION_STALE_AFTER must be at least 1 minute")
+	}
+
+	if c.DetectionCacheTTL < 0 {
+		return fmt.Errorf("DETECTION_CACHE_TTL must not be negative")
+	}
+
+	if c.ActionRetention <= 0 || c.DetectionRetention <= 0 {
+		return fmt.Errorf("ACTION_RETENTION and DETECTION_RETENTION must be positive")
+	}
+
+	if c.CleanupInterval < time.Minute {
+		return fmt.Errorf("RETENTION_CLEANUP_INTERVAL must be at least 1 minute")
+	}
+
+	if c.EncryptionKey == "" && len(c.PreviousEncryptionKeys) > 0 {
+		return fmt.Errorf("KNOWLEDGE_ENCRYPTION_PREVIOUS_KEYS requires KNOWLEDGE_ENCRYPTION_KEY")
+	}
+
 	return nil
 }
 
@@ -97,3 +174,22 @@ func parseIntOrDefault(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func parseDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+func parseListOrEmpty(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
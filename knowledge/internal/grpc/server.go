@@ -4,48 +4,67 @@ package grpc
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"log/slog"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/models"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/redis"
 	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/detectionkey"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/policy"
 )
 
 // KnowledgeServer implements the KnowledgeService gRPC interface.
 type KnowledgeServer struct {
 	pb.UnimplementedKnowledgeServiceServer
-	redisClient *redis.Client
-	startTime   time.Time
+	redisClient      *redis.Client
+	startTime        time.Time
+	offlineThreshold time.Duration
+	retention        redis.RetentionPolicy
 }
 
 // NewKnowledgeServer creates a new KnowledgeServer instance.
-func NewKnowledgeServer(redisClient *redis.Client) *KnowledgeServer {
+// Databases that have not reported health within offlineThreshold are reported as offline.
+func NewKnowledgeServer(redisClient *redis.Client, offlineThreshold time.Duration) *KnowledgeServer {
 	return &KnowledgeServer{
-		redisClient: redisClient,
-		startTime:   time.Now(),
+		redisClient:      redisClient,
+		startTime:        time.Now(),
+		offlineThreshold: offlineThreshold,
 	}
 }
 
+// SetRetentionPolicy sets how long finished actions and closed detections are kept.
+func (s *KnowledgeServer) SetRetentionPolicy(policy redis.RetentionPolicy) {
+	s.retention = policy
+}
+
+// RunCleanup purges records past the retention policy and logs what was removed.
+func (s *KnowledgeServer) RunCleanup(ctx context.Context) (*redis.CleanupResult, error) {
+	result, err := s.redisClient.Cleanup(ctx, s.retention, time.Now())
+	if err != nil {
+		return result, err
+	}
+
+	if result.ActionsPurged > 0 || result.DetectionsPurged > 0 || result.DanglingRemoved > 0 {
+		log.Printf("Retention cleanup purged %d actions and %d detections, removed %d dangling index entries",
+			result.ActionsPurged, result.DetectionsPurged, result.DanglingRemoved)
+	}
+
+	return result, nil
+}
+
 // ===== [DETECTION OPERATIONS] =====
 
 // RegisterDetection registers a new detection in the knowledge base.
 func (s *KnowledgeServer) RegisterDetection(ctx context.Context, req *pb.RegisterDetectionRequest) (*pb.DetectionResponse, error) {
-	detection := &models.Detection{
-		ID:         req.Id,
-		Key:        req.Key,
-		State:      models.StateActive,
-		Severity:   req.Severity,
-		Category:   req.Category,
-		DatabaseID: req.DatabaseId,
-		Value:      req.Value,
-		CreatedAt:  time.Unix(req.CreatedAt, 0),
-		LastSeen:   time.Now(),
-		TTL:        0,
-	}
+	detection := detectionFromRequest(req)
 
 	if err := s.redisClient.RegisterDetection(ctx, detection); err != nil {
-		log.Printf("Failed to register detection: %v", err)
+		slog.Error("Failed to register detection", "error", err)
 		return &pb.DetectionResponse{
 			Success: false,
 			Message: err.Error(),
@@ -61,24 +80,126 @@ func (s *KnowledgeServer) RegisterDetection(ctx context.Context, req *pb.Registe
 	}, nil
 }
 
+// RegisterDetectionIfAbsent registers a detection unless one with its key is already active.
+// The check and the write are one Redis transaction, so of several Analysers seeing the same
+// issue at once only one is told it registered, and only that one publishes.
+func (s *KnowledgeServer) RegisterDetectionIfAbsent(ctx context.Context, req *pb.RegisterDetectionRequest) (*pb.RegisterDetectionIfAbsentResponse, error) {
+	detection := detectionFromRequest(req)
+
+	registered, detectionID, err := s.redisClient.RegisterDetectionIfAbsent(ctx, detection)
+	if err != nil {
+		slog.Error("Failed to register detection", "error", err)
+		return &pb.RegisterDetectionIfAbsentResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	if !registered {
+		return &pb.RegisterDetectionIfAbsentResponse{
+			Success:     true,
+			Message:     "Detection already active",
+			DetectionId: detectionID,
+		}, nil
+	}
+
+	log.Printf("Detection registered: %s (key: %s)", detection.ID, detection.Key)
+
+	return &pb.RegisterDetectionIfAbsentResponse{
+		Success:     true,
+		Message:     "Detection registered successfully",
+		Registered:  true,
+		DetectionId: detectionID,
+	}, nil
+}
+
+// detectionFromRequest builds the active detection a registration request describes.
+func detectionFromRequest(req *pb.RegisterDetectionRequest) *models.Detection {
+	return &models.Detection{
+		ID:         req.Id,
+		Key:        req.Key,
+		State:      models.StateActive,
+		Severity:   req.Severity,
+		Category:   req.Category,
+		DatabaseID: req.DatabaseId,
+		Value:      req.Value,
+		CreatedAt:  time.Unix(req.CreatedAt, 0),
+		LastSeen:   time.Now(),
+		TTL:        0,
+
+		Title:          req.Title,
+		Description:    req.Description,
+		Recommendation: req.Recommendation,
+		ActionType:     req.ActionType,
+		Evidence:       req.Evidence,
+		ActionMetadata: req.ActionMetadata,
+	}
+}
+
 // IsDetectionActive checks if a detection with the given key is active.
 func (s *KnowledgeServer) IsDetectionActive(ctx context.Context, req *pb.DetectionKeyRequest) (*pb.DetectionStatusResponse, error) {
+	resp := &pb.DetectionStatusResponse{}
+
+	// A suppressed key may have no active detection at all, so check it first
+	if suppression, err := s.redisClient.GetSuppression(ctx, req.Key); err != nil {
+		slog.Error("Failed to check detection suppression", "error", err)
+	} else if suppression != nil {
+		resp.Suppressed = true
+		resp.SuppressionReason = suppression.Reason
+	}
+
 	isActive, err := s.redisClient.IsDetectionActive(ctx, req.Key)
 	if err != nil {
-		log.Printf("Failed to check detection status: %v", err)
-		return &pb.DetectionStatusResponse{
-			IsActive: false,
+		slog.Error("Failed to check detection status", "error", err)
+		return resp, nil
+	}
+
+	if !isActive {
+		return resp, nil
+	}
+
+	resp.IsActive = true
+	resp.DetectionId, _ = s.redisClient.GetDetectionIDByKey(ctx, req.Key)
+	if detection, err := s.redisClient.GetDetection(ctx, resp.DetectionId); err == nil {
+		resp.Severity = detection.Severity
+		resp.Value = detection.Value
+	}
+
+	return resp, nil
+}
+
+// UpdateDetectionSeverity stores a new severity and value on an active detection, when the
+// issue escalates or its value changes significantly.
+func (s *KnowledgeServer) UpdateDetectionSeverity(ctx context.Context, req *pb.UpdateDetectionSeverityRequest) (*pb.Response, error) {
+	if err := s.redisClient.UpdateDetectionSeverity(ctx, req.DetectionId, req.Severity, req.Value, time.Now()); err != nil {
+		slog.Error("Failed to update detection severity", "error", err)
+		return &pb.Response{
+			Success: false,
+			Message: err.Error(),
 		}, nil
 	}
 
-	detectionID := ""
-	if isActive {
-		detectionID, _ = s.redisClient.GetDetectionIDByKey(ctx, req.Key)
+	log.Printf("Detection updated: %s (severity: %s, value: %.2f)", req.DetectionId, req.Severity, req.Value)
+
+	return &pb.Response{
+		Success: true,
+		Message: "Detection severity updated",
+	}, nil
+}
+
+// RefreshDetection records that the Analyser has seen an active detection again.
+func (s *KnowledgeServer) RefreshDetection(ctx context.Context, req *pb.DetectionKeyRequest) (*pb.Response, error) {
+	if err := s.redisClient.RefreshDetectionLastSeen(ctx, req.Key, time.Now()); err != nil {
+		slog.Error("Failed to refresh detection", "error", err)
+		return &pb.Response{
+			Success: false,
+			Message: err.Error(),
+		}, nil
 	}
 
-	return &pb.DetectionStatusResponse{
-		IsActive:    isActive,
-		DetectionId: detectionID,
+	return &pb.Response{
+		Success: true,
+		Message: "Detection refreshed",
 	}, nil
 }
 
@@ -86,27 +207,49 @@ func (s *KnowledgeServer) IsDetectionActive(ctx context.Context, req *pb.Detecti
 func (s *KnowledgeServer) GetActiveDetections(ctx context.Context, req *pb.DatabaseFilterRequest) (*pb.DetectionListResponse, error) {
 	detections, err := s.redisClient.GetActiveDetections(ctx, req.DatabaseId)
 	if err != nil {
-		log.Printf("Failed to get active detections: %v", err)
+		slog.Error("Failed to get active detections", "error", err)
 		return &pb.DetectionListResponse{
 			Detections: []*pb.Detection{},
 		}, nil
 	}
 
+	suppressed := make(map[string]*models.Suppression)
+	if suppressions, err := s.redisClient.ListSuppressions(ctx); err != nil {
+		slog.Error("Failed to list suppressions", "error", err)
+	} else {
+		for _, suppression := range suppressions {
+			suppressed[suppression.Key] = suppression
+		}
+	}
+
 	pbDetections := make([]*pb.Detection, 0, len(detections))
 	for _, d := range detections {
-		pbDetections = append(pbDetections, &pb.Detection{
-			Id:         d.ID,
-			Key:        d.Key,
-			State:      string(d.State),
-			Severity:   d.Severity,
-			Category:   d.Category,
-			DatabaseId: d.DatabaseID,
-			Value:      d.Value,
-			ActionId:   d.ActionID,
-			ResolvedBy: d.ResolvedBy,
-			CreatedAt:  d.CreatedAt.Unix(),
-			LastSeen:   d.LastSeen.Unix(),
-		})
+		detection := &pb.Detection{
+			Id:            d.ID,
+			Key:           d.Key,
+			State:         string(d.State),
+			Severity:      d.Severity,
+			Category:      d.Category,
+			DatabaseId:    d.DatabaseID,
+			Value:         d.Value,
+			ActionId:      d.ActionID,
+			ResolvedBy:    d.ResolvedBy,
+			CreatedAt:     d.CreatedAt.Unix(),
+			LastSeen:      d.LastSeen.Unix(),
+			FailureReason: d.FailureReason,
+
+			Title:          d.Title,
+			Description:    d.Description,
+			Recommendation: d.Recommendation,
+			ActionType:     d.ActionType,
+			Evidence:       d.Evidence,
+			ActionMetadata: d.ActionMetadata,
+		}
+		if suppression, ok := suppressed[d.Key]; ok {
+			detection.SuppressionReason = suppression.Reason
+			detection.SuppressedUntil = suppression.ExpiresAt.Unix()
+		}
+		pbDetections = append(pbDetections, detection)
 	}
 
 	return &pb.DetectionListResponse{
@@ -117,7 +260,7 @@ func (s *KnowledgeServer) GetActiveDetections(ctx context.Context, req *pb.Datab
 // MarkDetectionResolved marks a detection as resolved.
 func (s *KnowledgeServer) MarkDetectionResolved(ctx context.Context, req *pb.ResolveDetectionRequest) (*pb.Response, error) {
 	if err := s.redisClient.MarkDetectionResolved(ctx, req.DetectionId, req.Solution); err != nil {
-		log.Printf("Failed to mark detection resolved: %v", err)
+		slog.Error("Failed to mark detection resolved", "error", err)
 		return &pb.Response{
 			Success: false,
 			Message: err.Error(),
@@ -132,22 +275,181 @@ func (s *KnowledgeServer) MarkDetectionResolved(ctx context.Context, req *pb.Res
 	}, nil
 }
 
+// ReopenDetection puts a resolved detection back in the active set because the action that
+// fixed it failed or was rolled back. A detection with nothing to reopen is not an error.
+func (s *KnowledgeServer) ReopenDetection(ctx context.Context, req *pb.ReopenDetectionRequest) (*pb.Response, error) {
+	reopened, err := s.redisClient.ReopenDetection(ctx, req.DetectionId, req.Reason)
+	if err != nil {
+		slog.Error("Failed to reopen detection", "error", err)
+		return &pb.Response{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	if !reopened {
+		return &pb.Response{
+			Success: true,
+			Message: "Detection not resolved, nothing to reopen",
+		}, nil
+	}
+
+	log.Printf("Detection reopened: %s (%s)", req.DetectionId, req.Reason)
+
+	return &pb.Response{
+		Success: true,
+		Message: "Detection reopened",
+	}, nil
+}
+
+// MarkDetectionUnactionable records that the Executor could not act on a detection.
+func (s *KnowledgeServer) MarkDetectionUnactionable(ctx context.Context, req *pb.UnactionableDetectionRequest) (*pb.Response, error) {
+	if err := s.redisClient.MarkDetectionUnactionable(ctx, req.DetectionId, req.Reason); err != nil {
+		slog.Error("Failed to mark detection unactionable", "error", err)
+		return &pb.Response{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	log.Printf("Detection unactionable: %s (reason: %s)", req.DetectionId, req.Reason)
+
+	return &pb.Response{
+		Success: true,
+		Message: "Detection marked as unactionable",
+	}, nil
+}
+
+// MarkDetectionAcknowledged parks a detection whose fix is not implemented yet.
+func (s *KnowledgeServer) MarkDetectionAcknowledged(ctx context.Context, req *pb.AcknowledgeDetectionRequest) (*pb.Response, error) {
+	if err := s.redisClient.MarkDetectionAcknowledged(ctx, req.DetectionId, req.Reason); err != nil {
+		slog.Error("Failed to acknowledge detection", "error", err)
+		return &pb.Response{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	log.Printf("Detection acknowledged with no action: %s (reason: %s)", req.DetectionId, req.Reason)
+
+	return &pb.Response{
+		Success: true,
+		Message: "Detection acknowledged",
+	}, nil
+}
+
+// SuppressDetectionKey snoozes a detection key, or lifts the suppression when the duration is zero.
+func (s *KnowledgeServer) SuppressDetectionKey(ctx context.Context, req *pb.SuppressDetectionKeyRequest) (*pb.Response, error) {
+	if req.Key == "" {
+		return &pb.Response{
+			Success: false,
+			Message: "detection key is required",
+		}, nil
+	}
+
+	if req.DurationSecs < 0 {
+		return &pb.Response{
+			Success: false,
+			Message: "duration must not be negative",
+		}, nil
+	}
+
+	if req.DurationSecs > 0 && req.Reason == "" {
+		return &pb.Response{
+			Success: false,
+			Message: "a reason is required to suppress a detection",
+		}, nil
+	}
+
+	// Lifting is left permissive so a suppression stored under an old key format can be removed
+	if req.DurationSecs > 0 {
+		if _, _, _, err := detectionkey.Parse(req.Key); err != nil {
+			return &pb.Response{
+				Success: false,
+				Message: err.Error(),
+			}, nil
+		}
+	}
+
+	duration := time.Duration(req.DurationSecs) * time.Second
+	if err := s.redisClient.SuppressDetectionKey(ctx, req.Key, duration, req.Reason); err != nil {
+		slog.Error("Failed to suppress detection key", "error", err)
+		return &pb.Response{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	if duration == 0 {
+		log.Printf("Detection suppression lifted: %s", req.Key)
+		return &pb.Response{
+			Success: true,
+			Message: "Suppression lifted",
+		}, nil
+	}
+
+	log.Printf("Detection suppressed: %s for %s (%s)", req.Key, duration, req.Reason)
+
+	return &pb.Response{
+		Success: true,
+		Message: "Detection suppressed",
+	}, nil
+}
+
+// ListSuppressions returns the suppressions that have not expired.
+func (s *KnowledgeServer) ListSuppressions(ctx context.Context, req *pb.ListSuppressionsRequest) (*pb.ListSuppressionsResponse, error) {
+	suppressions, err := s.redisClient.ListSuppressions(ctx)
+	if err != nil {
+		slog.Error("Failed to list suppressions", "error", err)
+		return &pb.ListSuppressionsResponse{
+			Suppressions: []*pb.Suppression{},
+		}, nil
+	}
+
+	pbSuppressions := make([]*pb.Suppression, 0, len(suppressions))
+	for _, suppression := range suppressions {
+		pbSuppressions = append(pbSuppressions, &pb.Suppression{
+			Key:       suppression.Key,
+			Reason:    suppression.Reason,
+			CreatedAt: suppression.CreatedAt.Unix(),
+			ExpiresAt: suppression.ExpiresAt.Unix(),
+		})
+	}
+
+	return &pb.ListSuppressionsResponse{
+		Suppressions: pbSuppressions,
+	}, nil
+}
+
 // ===== [ACTION OPERATIONS] =====
 
 // RegisterAction registers a new action in the knowledge base.
 func (s *KnowledgeServer) RegisterAction(ctx context.Context, req *pb.RegisterActionRequest) (*pb.ActionResponse, error) {
+	status := models.StatusQueued
+	if req.Status != "" {
+		status = models.ActionStatus(req.Status)
+	}
+
 	action := &models.Action{
 		ID:          req.Id,
 		DetectionID: req.DetectionId,
 		ActionType:  req.ActionType,
 		DatabaseID:  req.DatabaseId,
-		Status:      models.StatusQueued,
-		Message:     "Action queued",
+		Status:      status,
+		Message:     fmt.Sprintf("Action %s", status),
+		State:       req.ActionState,
+		Source:      req.Source,
 		CreatedAt:   time.Unix(req.CreatedAt, 0),
+
+		PolicyDecision: req.PolicyDecision,
+		PolicyRule:     req.PolicyRule,
+	}
+	if req.PolicyRule != "" {
+		action.Message = fmt.Sprintf("Action %s (policy %s)", status, req.PolicyRule)
 	}
 
 	if err := s.redisClient.RegisterAction(ctx, action); err != nil {
-		log.Printf("Failed to register action: %v", err)
+		slog.Error("Failed to register action", "error", err)
 		return &pb.ActionResponse{
 			Success: false,
 			Message: err.Error(),
@@ -165,14 +467,42 @@ func (s *KnowledgeServer) RegisterAction(ctx context.Context, req *pb.RegisterAc
 
 // UpdateActionStatus updates the status of an existing action.
 func (s *KnowledgeServer) UpdateActionStatus(ctx context.Context, req *pb.UpdateActionRequest) (*pb.Response, error) {
-	if err := s.redisClient.UpdateActionStatus(ctx, req.ActionId, models.ActionStatus(req.Status), req.Message, req.Error); err != nil {
-		log.Printf("Failed to update action status: %v", err)
+	if err := s.redisClient.UpdateActionStatus(ctx, req.ActionId, models.ActionStatus(req.Status), req.Message, req.Error, req.ErrorCode, req.Source); err != nil {
+		slog.Error("Failed to update action status", "error", err)
 		return &pb.Response{
 			Success: false,
 			Message: err.Error(),
 		}, nil
 	}
 
+	if req.Changes != "" || req.CanRollback {
+		if err := s.redisClient.SetActionResult(ctx, req.ActionId, req.Changes, req.CanRollback); err != nil {
+			slog.Error("Failed to record action result", "error", err)
+			return &pb.Response{
+				Success: false,
+				Message: err.Error(),
+			}, nil
+		}
+	}
+
+	if req.Progress != nil {
+		progress := &models.ActionProgress{
+			Phase:       req.Progress.Phase,
+			BlocksDone:  req.Progress.BlocksDone,
+			BlocksTotal: req.Progress.BlocksTotal,
+			TuplesDone:  req.Progress.TuplesDone,
+			TuplesTotal: req.Progress.TuplesTotal,
+			UpdatedAt:   time.Unix(req.Progress.UpdatedAt, 0),
+		}
+		if err := s.redisClient.SetActionProgress(ctx, req.ActionId, progress); err != nil {
+			slog.Error("Failed to record action progress", "error", err)
+			return &pb.Response{
+				Success: false,
+				Message: err.Error(),
+			}, nil
+		}
+	}
+
 	log.Printf("Action status updated %s => %s", req.ActionId, req.Status)
 
 	return &pb.Response{
@@ -181,11 +511,38 @@ func (s *KnowledgeServer) UpdateActionStatus(ctx context.Context, req *pb.Update
 	}, nil
 }
 
+// RecordActionOutcome stores the before and after metrics the Analyser saw for an action.
+func (s *KnowledgeServer) RecordActionOutcome(ctx context.Context, req *pb.RecordActionOutcomeRequest) (*pb.Response, error) {
+	outcome := &models.ActionOutcome{
+		Metric:       req.Metric,
+		MetricBefore: req.MetricBefore,
+		MetricAfter:  req.MetricAfter,
+		Verdict:      req.Verdict,
+		RecordedAt:   time.Now(),
+	}
+
+	if err := s.redisClient.SetActionOutcome(ctx, req.ActionId, outcome); err != nil {
+		slog.Error("Failed to record action outcome", "error", err)
+		return &pb.Response{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	log.Printf("Action outcome recorded %s: %s %.2f => %.2f (%s)",
+		req.ActionId, req.Metric, req.MetricBefore, req.MetricAfter, req.Verdict)
+
+	return &pb.Response{
+		Success: true,
+		Message: "Action outcome recorded",
+	}, nil
+}
+
 // GetPendingActions retrieves all pending actions, optionally filtered by database.
 func (s *KnowledgeServer) GetPendingActions(ctx context.Context, req *pb.DatabaseFilterRequest) (*pb.ActionListResponse, error) {
 	actions, err := s.redisClient.GetPendingActions(ctx, req.DatabaseId)
 	if err != nil {
-		log.Printf("Failed to get pending actions: %v", err)
+		slog.Error("Failed to get pending actions", "error", err)
 		return &pb.ActionListResponse{
 			Actions: []*pb.Action{},
 		}, nil
@@ -193,14 +550,7 @@ func (s *KnowledgeServer) GetPendingActions(ctx context.Context, req *pb.Databas
 
 	pbActions := make([]*pb.Action, 0, len(actions))
 	for _, a := range actions {
-		pbActions = append(pbActions, &pb.Action{
-			Id:          a.ID,
-			DetectionId: a.DetectionID,
-			ActionType:  a.ActionType,
-			DatabaseId:  a.DatabaseID,
-			Status:      string(a.Status),
-			CreatedAt:   a.CreatedAt.Unix(),
-		})
+		pbActions = append(pbActions, actionToProto(a))
 	}
 
 	log.Printf("Retrieved %d pending actions for database: %s", len(actions), req.DatabaseId)
@@ -210,6 +560,254 @@ func (s *KnowledgeServer) GetPendingActions(ctx context.Context, req *pb.Databas
 	}, nil
 }
 
+// GetAction retrieves a single action by ID.
+func (s *KnowledgeServer) GetAction(ctx context.Context, req *pb.GetActionRequest) (*pb.GetActionResponse, error) {
+	action, err := s.redisClient.GetAction(ctx, req.ActionId)
+	if err != nil {
+		return &pb.GetActionResponse{Found: false}, nil
+	}
+
+	return &pb.GetActionResponse{
+		Found:  true,
+		Action: actionToProto(action),
+	}, nil
+}
+
+// GetActionHistory retrieves the status timeline of an action, oldest first.
+func (s *KnowledgeServer) GetActionHistory(ctx context.Context, req *pb.GetActionHistoryRequest) (*pb.GetActionHistoryResponse, error) {
+	history, err := s.redisClient.GetActionHistory(ctx, req.ActionId)
+	if err != nil {
+		slog.Error("Failed to get action history", "error", err)
+		return &pb.GetActionHistoryResponse{
+			Entries: []*pb.ActionHistoryEntry{},
+		}, nil
+	}
+
+	entries := make([]*pb.ActionHistoryEntry, 0, len(history))
+	for _, entry := range history {
+		entries = append(entries, &pb.ActionHistoryEntry{
+			Status:    string(entry.Status),
+			Message:   entry.Message,
+			Error:     entry.Error,
+			ErrorCode: entry.ErrorCode,
+			Source:    entry.Source,
+			Timestamp: entry.Timestamp.Unix(),
+		})
+	}
+
+	return &pb.GetActionHistoryResponse{
+		Entries: entries,
+	}, nil
+}
+
+// ListActionsByStatus retrieves actions in any of the requested statuses, optionally filtered by database.
+func (s *KnowledgeServer) ListActionsByStatus(ctx context.Context, req *pb.ListActionsByStatusRequest) (*pb.ActionListResponse, error) {
+	pbActions := make([]*pb.Action, 0)
+
+	for _, status := range req.Statuses {
+		actions, err := s.redisClient.GetActionByStatus(ctx, models.ActionStatus(status))
+		if err != nil {
+			slog.Error("Failed to get actions with status", "status", status, "error", err)
+			continue
+		}
+
+		for _, a := range actions {
+			if req.DatabaseId != "" && a.DatabaseID != req.DatabaseId {
+				continue
+			}
+			pbActions = append(pbActions, actionToProto(a))
+		}
+	}
+
+	return &pb.ActionListResponse{
+		Actions: pbActions,
+	}, nil
+}
+
+// GetPendingImplementationSummary reports which unimplemented action types were requested most.
+func (s *KnowledgeServer) GetPendingImplementationSummary(ctx context.Context, req *pb.GetPendingImplementationSummaryRequest) (*pb.GetPendingImplementationSummaryResponse, error) {
+	demand, err := s.redisClient.GetPendingImplementationDemand(ctx)
+	if err != nil {
+		slog.Error("Failed to get pending implementation summary", "error", err)
+		return &pb.GetPendingImplementationSummaryResponse{
+			Summaries: []*pb.PendingImplementationSummary{},
+		}, nil
+	}
+
+	summaries := make([]*pb.PendingImplementationSummary, 0, len(demand))
+	for _, d := range demand {
+		summaries = append(summaries, &pb.PendingImplementationSummary{
+			ActionType:      d.ActionType,
+			Count:           int32(d.Count),
+			DatabaseIds:     d.DatabaseIDs,
+			LastRequestedAt: d.LastRequestedAt.Unix(),
+		})
+	}
+
+	return &pb.GetPendingImplementationSummaryResponse{
+		Summaries: summaries,
+	}, nil
+}
+
+// RecordDatabaseAction records the start of a mutating action for per-database cooldowns.
+func (s *KnowledgeServer) RecordDatabaseAction(ctx context.Context, req *pb.RecordDatabaseActionRequest) (*pb.Response, error) {
+	if req.DatabaseId == "" || req.ActionId == "" {
+		return &pb.Response{
+			Success: false,
+			Message: "database_id and action_id are required",
+		}, nil
+	}
+
+	startedAt := time.Now()
+	if req.StartedAt > 0 {
+		startedAt = time.Unix(req.StartedAt, 0)
+	}
+
+	if err := s.redisClient.RecordDatabaseAction(ctx, req.DatabaseId, req.ActionId, startedAt); err != nil {
+		slog.Error("Failed to record database action", "error", err)
+		return &pb.Response{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	return &pb.Response{
+		Success: true,
+		Message: "Database action recorded",
+	}, nil
+}
+
+// GetRecentDatabaseActions returns when mutating actions started on a database since req.Since.
+func (s *KnowledgeServer) GetRecentDatabaseActions(ctx context.Context, req *pb.RecentDatabaseActionsRequest) (*pb.RecentDatabaseActionsResponse, error) {
+	startedAt, err := s.redisClient.GetRecentDatabaseActions(ctx, req.DatabaseId, time.Unix(req.Since, 0))
+	if err != nil {
+		slog.Error("Failed to get recent database actions", "error", err)
+		return &pb.RecentDatabaseActionsResponse{}, nil
+	}
+
+	resp := &pb.RecentDatabaseActionsResponse{
+		StartedAt: make([]int64, 0, len(startedAt)),
+	}
+	for _, t := range startedAt {
+		resp.StartedAt = append(resp.StartedAt, t.Unix())
+	}
+
+	return resp, nil
+}
+
+// RecordMetricSample stores one collection cycle's metrics in the database's history.
+func (s *KnowledgeServer) RecordMetricSample(ctx context.Context, req *pb.MetricSample) (*pb.Response, error) {
+	if req.DatabaseId == "" || len(req.Values) == 0 {
+		return &pb.Response{
+			Success: false,
+			Message: "database_id and values are required",
+		}, nil
+	}
+
+	timestamp := time.Now()
+	if req.Timestamp > 0 {
+		timestamp = time.Unix(req.Timestamp, 0)
+	}
+
+	sample := &models.MetricSample{
+		DatabaseID: req.DatabaseId,
+		Timestamp:  timestamp,
+		Values:     req.Values,
+	}
+	if err := s.redisClient.RecordMetricSample(ctx, sample); err != nil {
+		slog.Error("Failed to record metric sample", "error", err)
+		return &pb.Response{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	return &pb.Response{
+		Success: true,
+		Message: "Metric sample recorded",
+	}, nil
+}
+
+// GetMetricHistory returns a database's metric samples over a time range, oldest first.
+func (s *KnowledgeServer) GetMetricHistory(ctx context.Context, req *pb.GetMetricHistoryRequest) (*pb.GetMetricHistoryResponse, error) {
+	var from, to time.Time
+	if req.StartTime > 0 {
+		from = time.Unix(req.StartTime, 0)
+	}
+	if req.EndTime > 0 {
+		to = time.Unix(req.EndTime, 0)
+	}
+
+	history, resolution, err := s.redisClient.GetMetricHistory(ctx, req.DatabaseId, from, to,
+		time.Duration(req.ResolutionSecs)*time.Second, time.Now())
+	if err != nil {
+		slog.Error("Failed to get metric history", "error", err)
+		return &pb.GetMetricHistoryResponse{}, nil
+	}
+
+	resp := &pb.GetMetricHistoryResponse{
+		Samples:        make([]*pb.MetricSample, 0, len(history)),
+		ResolutionSecs: int64(resolution / time.Second),
+	}
+	for _, sample := range history {
+		resp.Samples = append(resp.Samples, &pb.MetricSample{
+			DatabaseId: sample.DatabaseID,
+			Timestamp:  sample.Timestamp.Unix(),
+			Values:     sample.Values,
+		})
+	}
+
+	return resp, nil
+}
+
+func actionToProto(a *models.Action) *pb.Action {
+	action := &pb.Action{
+		Id:          a.ID,
+		DetectionId: a.DetectionID,
+		ActionType:  a.ActionType,
+		DatabaseId:  a.DatabaseID,
+		Status:      string(a.Status),
+		CreatedAt:   a.CreatedAt.Unix(),
+		Message:     a.Message,
+		Error:       a.Error,
+		ErrorCode:   a.ErrorCode,
+		ActionState: a.State,
+		Changes:     a.Result,
+		CanRollback: a.CanRollback,
+		Source:      a.Source,
+
+		PolicyDecision: a.PolicyDecision,
+		PolicyRule:     a.PolicyRule,
+	}
+
+	if a.CompletedAt != nil {
+		action.CompletedAt = a.CompletedAt.Unix()
+	}
+
+	if a.Progress != nil {
+		action.Progress = &pb.ActionProgress{
+			Phase:       a.Progress.Phase,
+			BlocksDone:  a.Progress.BlocksDone,
+			BlocksTotal: a.Progress.BlocksTotal,
+			TuplesDone:  a.Progress.TuplesDone,
+			TuplesTotal: a.Progress.TuplesTotal,
+			UpdatedAt:   a.Progress.UpdatedAt.Unix(),
+		}
+	}
+
+	if a.Outcome != nil {
+		action.Outcome = &pb.ActionOutcome{
+			Metric:       a.Outcome.Metric,
+			MetricBefore: a.Outcome.MetricBefore,
+			MetricAfter:  a.Outcome.MetricAfter,
+			Verdict:      a.Outcome.Verdict,
+			RecordedAt:   a.Outcome.RecordedAt.Unix(),
+		}
+	}
+
+	return action
+}
+
 // ===== [DATABASE OPERATIONS] =====
 
 // RegisterDatabase registers a new database in the knowledge base.
@@ -231,7 +829,7 @@ func (s *KnowledgeServer) RegisterDatabase(ctx context.Context, req *pb.Register
 	}
 
 	if err := s.redisClient.RegisterDatabase(ctx, database); err != nil {
-		log.Printf("Failed to register database: %v", err)
+		slog.Error("Failed to register database", "error", err)
 		return &pb.DatabaseResponse{
 			Success: false,
 			Message: err.Error(),
@@ -254,14 +852,19 @@ func (s *KnowledgeServer) GetDatabase(ctx context.Context, req *pb.GetDatabaseRe
 			log.Printf("Database not found: %s", req.DatabaseId)
 			return &pb.GetDatabaseResponse{Found: false}, nil
 		}
-		log.Printf("Failed to get database: %v", err)
+		slog.Error("Failed to get database", "error", err)
 		return &pb.GetDatabaseResponse{Found: false}, nil
 	}
 
+	connectionString := database.ConnectionString
+	if req.Redact {
+		connectionString = ""
+	}
+
 	return &pb.GetDatabaseResponse{
 		Found:            true,
 		DatabaseId:       database.ID,
-		ConnectionString: database.ConnectionString,
+		ConnectionString: connectionString,
 		DatabaseType:     database.DatabaseType,
 		DatabaseName:     database.DatabaseName,
 		Host:             database.Host,
@@ -278,46 +881,81 @@ func (s *KnowledgeServer) GetDatabase(ctx context.Context, req *pb.GetDatabaseRe
 
 // ListDatabases returns all registered databases.
 func (s *KnowledgeServer) ListDatabases(ctx context.Context, req *pb.ListDatabasesRequest) (*pb.DatabaseListResponse, error) {
-	databases, err := s.redisClient.ListDatabases(ctx)
+	databases, nextCursor, err := s.redisClient.ListDatabasesPage(ctx, req.Cursor, int(req.Limit), req.EnabledOnly)
 	if err != nil {
-		log.Printf("Failed to list databases: %v", err)
+		slog.Error("Failed to list databases", "error", err)
 		return &pb.DatabaseListResponse{}, nil
 	}
 
 	pbDatabases := make([]*pb.RegisteredDatabase, 0, len(databases))
 	for _, d := range databases {
-		if req.EnabledOnly && !d.Enabled {
-			continue
-		}
-
-		pbDatabases = append(pbDatabases, &pb.RegisteredDatabase{
-			DatabaseId:       d.ID,
-			DatabaseType:     d.DatabaseType,
-			DatabaseName:     d.DatabaseName,
-			Host:             d.Host,
-			Port:             d.Port,
-			Version:          d.Version,
-			RegisteredAt:     d.RegisteredAt.Unix(),
-			LastSeen:         d.LastSeen.Unix(),
-			Status:           d.Status,
-			HealthScore:      d.HealthScore,
-			Enabled:          d.Enabled,
-			ConnectionString: d.ConnectionString,
-		})
+		pbDatabases = append(pbDatabases, databaseToProto(d, req.Redact))
 	}
 
 	log.Printf("Listed %d databases (enabled_only: %v)", len(pbDatabases), req.EnabledOnly)
 
 	return &pb.DatabaseListResponse{
-		Databases: pbDatabases,
+		Databases:  pbDatabases,
+		NextCursor: nextCursor,
 	}, nil
 }
 
+// GetDatabasesByType returns registered databases of a single type.
+func (s *KnowledgeServer) GetDatabasesByType(ctx context.Context, req *pb.GetDatabasesByTypeRequest) (*pb.DatabaseListResponse, error) {
+	if req.DatabaseType == "" {
+		log.Printf("GetDatabasesByType called without database_type")
+		return &pb.DatabaseListResponse{}, nil
+	}
+
+	databases, nextCursor, err := s.redisClient.GetDatabasesByType(ctx, req.DatabaseType, req.Cursor, int(req.Limit), req.EnabledOnly)
+	if err != nil {
+		slog.Error("Failed to list databases by type", "error", err)
+		return &pb.DatabaseListResponse{}, nil
+	}
+
+	pbDatabases := make([]*pb.RegisteredDatabase, 0, len(databases))
+	for _, d := range databases {
+		pbDatabases = append(pbDatabases, databaseToProto(d, req.Redact))
+	}
+
+	log.Printf("Listed %d %s databases (enabled_only: %v)", len(pbDatabases), req.DatabaseType, req.EnabledOnly)
+
+	return &pb.DatabaseListResponse{
+		Databases:  pbDatabases,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// databaseToProto converts a stored database into its list representation. With redact
+// set the connection string, which carries credentials, is left out.
+func databaseToProto(d *models.Database, redact bool) *pb.RegisteredDatabase {
+	connectionString := d.ConnectionString
+	if redact {
+		connectionString = ""
+	}
+
+	return &pb.RegisteredDatabase{
+		DatabaseId:       d.ID,
+		DatabaseType:     d.DatabaseType,
+		DatabaseName:     d.DatabaseName,
+		Host:             d.Host,
+		Port:             d.Port,
+		Version:          d.Version,
+		RegisteredAt:     d.RegisteredAt.Unix(),
+		LastSeen:         d.LastSeen.Unix(),
+		Status:           d.Status,
+		HealthScore:      d.HealthScore,
+		Enabled:          d.Enabled,
+		ConnectionString: connectionString,
+		Metadata:         d.Metadata,
+	}
+}
+
 // UpdateDatabase updates an existing database configuration.
 func (s *KnowledgeServer) UpdateDatabase(ctx context.Context, req *pb.UpdateDatabaseRequest) (*pb.Response, error) {
 	database, err := s.redisClient.GetDatabase(ctx, req.DatabaseId)
 	if err != nil {
-		log.Printf("Failed to get database for update: %v", err)
+		slog.Error("Failed to get database for update", "error", err)
 		return &pb.Response{
 			Success: false,
 			Message: "Database not found",
@@ -332,8 +970,19 @@ func (s *KnowledgeServer) UpdateDatabase(ctx context.Context, req *pb.UpdateData
 	}
 	database.Enabled = req.Enabled
 
+	for key, value := range req.Metadata {
+		if value == "" {
+			delete(database.Metadata, key)
+			continue
+		}
+		if database.Metadata == nil {
+			database.Metadata = make(map[string]string)
+		}
+		database.Metadata[key] = value
+	}
+
 	if err := s.redisClient.RegisterDatabase(ctx, database); err != nil {
-		log.Printf("Failed to update database: %v", err)
+		slog.Error("Failed to update database", "error", err)
 		return &pb.Response{
 			Success: false,
 			Message: err.Error(),
@@ -351,7 +1000,7 @@ func (s *KnowledgeServer) UpdateDatabase(ctx context.Context, req *pb.UpdateData
 // UpdateDatabaseHealth updates the health status of a registered database.
 func (s *KnowledgeServer) UpdateDatabaseHealth(ctx context.Context, req *pb.UpdateDatabaseHealthRequest) (*pb.Response, error) {
 	if err := s.redisClient.UpdateDatabaseHealth(ctx, req.DatabaseId, req.LastSeen, req.Status, req.HealthScore); err != nil {
-		log.Printf("Failed to update database health: %v", err)
+		slog.Error("Failed to update database health", "error", err)
 		return &pb.Response{
 			Success: false,
 			Message: err.Error(),
@@ -369,7 +1018,7 @@ func (s *KnowledgeServer) UpdateDatabaseHealth(ctx context.Context, req *pb.Upda
 // UnregisterDatabase removes a database from the registry.
 func (s *KnowledgeServer) UnregisterDatabase(ctx context.Context, req *pb.UnregisterDatabaseRequest) (*pb.Response, error) {
 	if err := s.redisClient.UnregisterDatabase(ctx, req.DatabaseId); err != nil {
-		log.Printf("Failed to unregister database: %v", err)
+		slog.Error("Failed to unregister database", "error", err)
 		return &pb.Response{
 			Success: false,
 			Message: err.Error(),
@@ -386,58 +1035,142 @@ func (s *KnowledgeServer) UnregisterDatabase(ctx context.Context, req *pb.Unregi
 
 // ===== [SYSTEM STATISTICS] =====
 
-// GetSystemStats returns system-wide statistics.
+// GetSystemStats returns system-wide statistics. Detection and action counts come from
+// counters maintained on every state change, so this is cheap enough to poll.
 func (s *KnowledgeServer) GetSystemStats(ctx context.Context, req *pb.GetSystemStatsRequest) (*pb.GetSystemStatsResponse, error) {
 	databases, _ := s.redisClient.ListDatabases(ctx)
-	totalDatabases := int32(len(databases))
+	summary := BuildStatsSummary(databases, s.offlineThreshold, time.Now())
 
-	var healthyCount, degradedCount, offlineCount int32
-	for _, db := range databases {
-		switch db.Status {
-		case "healthy":
-			healthyCount++
-		case "degraded":
-			degradedCount++
-		case "offline":
-			offlineCount++
-		}
+	counters, err := s.redisClient.GetStatsCounters(ctx)
+	if err != nil {
+		slog.Error("Failed to get stats counters", "error", err)
+		counters = &redis.StatsCounters{}
 	}
 
-	activeDetections, _ := s.redisClient.CountAllActiveDetections(ctx)
-
 	queuedCount, _ := s.redisClient.CountActionsByStatus(ctx, models.StatusQueued)
 	executingCount, _ := s.redisClient.CountActionsByStatus(ctx, models.StatusExecuting)
 	completedCount, _ := s.redisClient.CountActionsByStatus(ctx, models.StatusCompleted)
 	failedCount, _ := s.redisClient.CountActionsByStatus(ctx, models.StatusFailed)
 
-	totalActions, _ := s.redisClient.CountAllActions(ctx)
-
 	uptime := int64(time.Since(s.startTime).Seconds())
 
+	cleanup, err := s.redisClient.GetCleanupTotals(ctx)
+	if err != nil {
+		slog.Error("Failed to get cleanup totals", "error", err)
+		cleanup = &redis.CleanupTotals{}
+	}
+
+	var lastCleanupAt int64
+	if !cleanup.LastCleanupAt.IsZero() {
+		lastCleanupAt = cleanup.LastCleanupAt.Unix()
+	}
+
 	return &pb.GetSystemStatsResponse{
-		TotalDatabases:     totalDatabases,
-		HealthyDatabases:   healthyCount,
-		DegradedDatabases:  degradedCount,
-		OfflineDatabases:   offlineCount,
-		TotalDetections:    activeDetections,
-		ActiveDetections:   activeDetections,
-		ResolvedDetections: 0,
-		TotalActions:       totalActions,
-		ActionsQueued:      queuedCount,
-		ActionsExecuting:   executingCount,
-		ActionsCompleted:   completedCount,
-		ActionsFailed:      failedCount,
-		UptimeSeconds:      uptime,
+		TotalDatabases:               summary.TotalDatabases,
+		HealthyDatabases:             summary.HealthyDatabases,
+		DegradedDatabases:            summary.DegradedDatabases,
+		OfflineDatabases:             summary.OfflineDatabases,
+		TotalDetections:              counters.ActiveDetections + counters.ResolvedDetections,
+		ActiveDetections:             counters.ActiveDetections,
+		ResolvedDetections:           counters.ResolvedDetections,
+		TotalActions:                 counters.TotalActions,
+		ActionsQueued:                queuedCount,
+		ActionsExecuting:             executingCount,
+		ActionsCompleted:             completedCount,
+		ActionsFailed:                failedCount,
+		UptimeSeconds:                uptime,
+		ActiveDetectionsByDatabase:   counters.ActiveByDatabase,
+		ActiveDetectionsByCategory:   counters.ActiveByCategory,
+		ResolvedDetectionsByDatabase: counters.ResolvedByDatabase,
+		ResolvedDetectionsByCategory: counters.ResolvedByCategory,
+		ActionsExecutedLastDay:       counters.ActionsExecutedLastDay,
+		ActionsPurged:                cleanup.ActionsPurged,
+		DetectionsPurged:             cleanup.DetectionsPurged,
+		DanglingEntriesRemoved:       cleanup.DanglingRemoved,
+		LastCleanupAt:                lastCleanupAt,
 	}, nil
 }
 
+// GetActionStats reports how each action type fared over a window, per database and summed
+// across databases and action types. A storage failure is returned as an error rather than
+// zero counts, which would read as the action types never having run.
+func (s *KnowledgeServer) GetActionStats(ctx context.Context, req *pb.GetActionStatsRequest) (*pb.GetActionStatsResponse, error) {
+	window := time.Duration(req.WindowSeconds) * time.Second
+	if window <= 0 || window > redis.ActionStatsRetention {
+		window = redis.ActionStatsRetention
+	}
+
+	pairs, err := s.redisClient.GetActionStats(ctx, time.Now().Add(-window), req.DatabaseId, req.ActionType)
+	if err != nil {
+		slog.Error("Failed to get action stats", "error", err)
+		return nil, err
+	}
+
+	total := &models.ActionStats{}
+	byType := map[string]*models.ActionStats{}
+	byDatabase := map[string]*models.ActionStats{}
+	resp := &pb.GetActionStatsResponse{WindowSeconds: int64(window.Seconds())}
+
+	for _, pair := range pairs {
+		total.Add(pair)
+		sumInto(byType, pair.ActionType, &models.ActionStats{ActionType: pair.ActionType}).Add(pair)
+		sumInto(byDatabase, pair.DatabaseID, &models.ActionStats{DatabaseID: pair.DatabaseID}).Add(pair)
+		resp.ByActionTypeAndDatabase = append(resp.ByActionTypeAndDatabase, actionStatsToProto(pair))
+	}
+
+	resp.ByActionType = sortedActionStats(byType)
+	resp.ByDatabase = sortedActionStats(byDatabase)
+	resp.Total = actionStatsToProto(total)
+
+	return resp, nil
+}
+
+// sumInto returns the entry for key, adding empty when there is none yet.
+func sumInto(entries map[string]*models.ActionStats, key string, empty *models.ActionStats) *models.ActionStats {
+	if entry, ok := entries[key]; ok {
+		return entry
+	}
+	entries[key] = empty
+	return empty
+}
+
+func sortedActionStats(entries map[string]*models.ActionStats) []*pb.ActionStats {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]*pb.ActionStats, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, actionStatsToProto(entries[key]))
+	}
+	return result
+}
+
+func actionStatsToProto(stats *models.ActionStats) *pb.ActionStats {
+	return &pb.ActionStats{
+		ActionType:          stats.ActionType,
+		DatabaseId:          stats.DatabaseID,
+		Attempts:            stats.Attempts,
+		Successes:           stats.Successes,
+		Failures:            stats.Failures,
+		Rollbacks:           stats.Rollbacks,
+		VerifiedEffective:   stats.VerifiedEffective,
+		VerifiedIneffective: stats.VerifiedIneffective,
+		SuccessRate:         stats.SuccessRate(),
+		VerifiedSuccessRate: stats.VerifiedSuccessRate(),
+		AvgExecutionMs:      stats.AvgExecutionMillis(),
+	}
+}
+
 // ===== [CONFIGURATION MANAGEMENT] =====
 
 // GetSystemConfig retrieves the system configuration.
 func (s *KnowledgeServer) GetSystemConfig(ctx context.Context, req *pb.GetSystemConfigRequest) (*pb.SystemConfig, error) {
 	config, err := s.redisClient.GetSystemConfig(ctx)
 	if err != nil {
-		log.Printf("Failed to get system config: %v", err)
+		slog.Error("Failed to get system config", "error", err)
 		return &pb.SystemConfig{
 			Thresholds: &pb.DetectionThresholds{
 				ConnectionPoolCritical:  0.8,
@@ -463,7 +1196,7 @@ func (s *KnowledgeServer) SaveSystemConfig(ctx context.Context, req *pb.SaveSyst
 	}
 
 	if err := s.redisClient.SaveSystemConfig(ctx, req.Config); err != nil {
-		log.Printf("Failed to save system config: %v", err)
+		slog.Error("Failed to save system config", "error", err)
 		return &pb.Response{
 			Success: false,
 			Message: err.Error(),
@@ -478,6 +1211,140 @@ func (s *KnowledgeServer) SaveSystemConfig(ctx context.Context, req *pb.SaveSyst
 	}, nil
 }
 
+// SetThresholds replaces the detection threshold overrides for a database or the global default.
+func (s *KnowledgeServer) SetThresholds(ctx context.Context, req *pb.SetThresholdsRequest) (*pb.Response, error) {
+	for name, value := range req.Values {
+		if name == "" || math.IsNaN(value) || math.IsInf(value, 0) || value < 0 {
+			return &pb.Response{
+				Success: false,
+				Message: fmt.Sprintf("invalid threshold %q: must be a named, non-negative number", name),
+			}, nil
+		}
+	}
+
+	if err := s.redisClient.SetThresholds(ctx, req.DatabaseId, req.Values); err != nil {
+		slog.Error("Failed to save thresholds", "error", err)
+		return &pb.Response{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	scope := req.DatabaseId
+	if scope == "" {
+		scope = "global"
+	}
+	log.Printf("Thresholds saved for %s (%d overrides)", scope, len(req.Values))
+
+	return &pb.Response{
+		Success: true,
+		Message: "Thresholds saved successfully",
+	}, nil
+}
+
+// GetThresholds retrieves threshold overrides: every set, or the global default and one database's set.
+func (s *KnowledgeServer) GetThresholds(ctx context.Context, req *pb.GetThresholdsRequest) (*pb.GetThresholdsResponse, error) {
+	sets, err := s.redisClient.GetThresholds(ctx, req.DatabaseId)
+	if err != nil {
+		slog.Error("Failed to get thresholds", "error", err)
+		return &pb.GetThresholdsResponse{
+			Sets: []*pb.ThresholdSet{},
+		}, nil
+	}
+
+	pbSets := make([]*pb.ThresholdSet, 0, len(sets))
+	for _, set := range sets {
+		pbSets = append(pbSets, &pb.ThresholdSet{
+			DatabaseId: set.DatabaseID,
+			Values:     set.Values,
+			UpdatedAt:  set.UpdatedAt.Unix(),
+		})
+	}
+
+	return &pb.GetThresholdsResponse{
+		Sets: pbSets,
+	}, nil
+}
+
+// SetDatabasePolicy replaces a database's policy; one that restricts nothing clears it.
+func (s *KnowledgeServer) SetDatabasePolicy(ctx context.Context, req *pb.SetDatabasePolicyRequest) (*pb.Response, error) {
+	if err := policy.Normalise(req.Policy); err != nil {
+		return &pb.Response{
+			Success: false,
+			Message: fmt.Sprintf("invalid policy: %v", err),
+		}, nil
+	}
+
+	p := req.Policy
+	if err := s.redisClient.SetDatabasePolicy(ctx, &models.DatabasePolicy{
+		DatabaseID:         p.DatabaseId,
+		AllowedActionTypes: p.AllowedActionTypes,
+		DeniedActionTypes:  p.DeniedActionTypes,
+		IgnoredDetectors:   p.IgnoredDetectors,
+		Reason:             p.Reason,
+	}); err != nil {
+		slog.Error("Failed to save policy", "error", err)
+		return &pb.Response{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	log.Printf("Policy saved for %s (allowed: %v, denied: %v, ignored detectors: %v)",
+		p.DatabaseId, p.AllowedActionTypes, p.DeniedActionTypes, p.IgnoredDetectors)
+
+	return &pb.Response{
+		Success: true,
+		Message: "Policy saved successfully",
+	}, nil
+}
+
+// GetDatabasePolicy retrieves a database's policy. A storage failure is returned as an error
+// rather than an empty policy, which callers would read as lifting every restriction.
+func (s *KnowledgeServer) GetDatabasePolicy(ctx context.Context, req *pb.GetDatabasePolicyRequest) (*pb.DatabasePolicy, error) {
+	p, err := s.redisClient.GetDatabasePolicy(ctx, req.DatabaseId)
+	if err != nil {
+		slog.Error("Failed to get policy", "error", err)
+		return nil, err
+	}
+	if p == nil {
+		return &pb.DatabasePolicy{DatabaseId: req.DatabaseId}, nil
+	}
+
+	return toPBPolicy(p), nil
+}
+
+// ListDatabasePolicies retrieves every stored database policy.
+func (s *KnowledgeServer) ListDatabasePolicies(ctx context.Context, req *pb.ListDatabasePoliciesRequest) (*pb.ListDatabasePoliciesResponse, error) {
+	policies, err := s.redisClient.ListDatabasePolicies(ctx)
+	if err != nil {
+		slog.Error("Failed to list policies", "error", err)
+		return &pb.ListDatabasePoliciesResponse{
+			Policies: []*pb.DatabasePolicy{},
+		}, nil
+	}
+
+	pbPolicies := make([]*pb.DatabasePolicy, 0, len(policies))
+	for _, p := range policies {
+		pbPolicies = append(pbPolicies, toPBPolicy(p))
+	}
+
+	return &pb.ListDatabasePoliciesResponse{
+		Policies: pbPolicies,
+	}, nil
+}
+
+func toPBPolicy(p *models.DatabasePolicy) *pb.DatabasePolicy {
+	return &pb.DatabasePolicy{
+		DatabaseId:         p.DatabaseID,
+		AllowedActionTypes: p.AllowedActionTypes,
+		DeniedActionTypes:  p.DeniedActionTypes,
+		IgnoredDetectors:   p.IgnoredDetectors,
+		Reason:             p.Reason,
+		UpdatedAt:          p.UpdatedAt.Unix(),
+	}
+}
+
 // GetSystemStatus returns the current system status.
 func (s *KnowledgeServer) GetSystemStatus(ctx context.Context, req *pb.GetSystemStatusRequest) (*pb.SystemStatus, error) {
 	config, _ := s.redisClient.GetSystemConfig(ctx)
@@ -502,13 +1369,41 @@ func (s *KnowledgeServer) GetSystemStatus(ctx context.Context, req *pb.GetSystem
 		Configured:         hasEnabledDB,
 		OnboardingComplete: onboardingComplete,
 		ServiceStates:      serviceStates,
+		StatsSummary:       BuildStatsSummary(databases, s.offlineThreshold, time.Now()),
 	}, nil
 }
 
+// BuildStatsSummary summarises database health. Enabled databases whose last
+// health report is older than offlineThreshold are counted as offline regardless
+// of their last reported status.
+func BuildStatsSummary(databases []*models.Database, offlineThreshold time.Duration, now time.Time) *pb.StatsSummary {
+	summary := &pb.StatsSummary{
+		TotalDatabases:     int32(len(databases)),
+		OfflineDatabaseIds: []string{},
+	}
+
+	for _, db := range databases {
+		if db.Status == "offline" || (db.Enabled && now.Sub(db.LastSeen) > offlineThreshold) {
+			summary.OfflineDatabases++
+			summary.OfflineDatabaseIds = append(summary.OfflineDatabaseIds, db.ID)
+			continue
+		}
+
+		switch db.Status {
+		case "healthy":
+			summary.HealthyDatabases++
+		case "degraded":
+			summary.DegradedDatabases++
+		}
+	}
+
+	return summary
+}
+
 // FlushAllData clears all data from Redis.
 func (s *KnowledgeServer) FlushAllData(ctx context.Context, req *pb.FlushAllDataRequest) (*pb.FlushAllDataResponse, error) {
 	if err := s.redisClient.FlushAll(ctx); err != nil {
-		log.Printf("Failed to flush all data: %v", err)
+		slog.Error("Failed to flush all data", "error", err)
 		return &pb.FlushAllDataResponse{
 			Success: false,
 			Message: err.Error(),
@@ -522,3 +1417,23 @@ func (s *KnowledgeServer) FlushAllData(ctx context.Context, req *pb.FlushAllData
 		Message: "All data flushed successfully",
 	}, nil
 }
+
+// ForceCleanup runs retention cleanup now rather than waiting for the next scheduled run.
+func (s *KnowledgeServer) ForceCleanup(ctx context.Context, req *pb.ForceCleanupRequest) (*pb.ForceCleanupResponse, error) {
+	result, err := s.RunCleanup(ctx)
+	if err != nil {
+		slog.Error("Failed to run retention cleanup", "error", err)
+		return &pb.ForceCleanupResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	return &pb.ForceCleanupResponse{
+		Success:                true,
+		Message:                "Retention cleanup completed",
+		ActionsPurged:          int32(result.ActionsPurged),
+		DetectionsPurged:       int32(result.DetectionsPurged),
+		DanglingEntriesRemoved: int32(result.DanglingRemoved),
+	}, nil
+}
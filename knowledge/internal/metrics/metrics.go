@@ -0,0 +1,66 @@
+// Package metrics exposes Prometheus metrics for the Knowledge service.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// RedisOpDuration tracks Redis command latency by command name.
+	RedisOpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "knowledge",
+		Name:      "redis_op_duration_seconds",
+		Help:      "Latency of Redis commands by command name.",
+		Buckets:   []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1},
+	}, []string{"command"})
+
+	// RedisOpErrors counts failed Redis commands by command name.
+	RedisOpErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "knowledge",
+		Name:      "redis_op_errors_total",
+		Help:      "Number of failed Redis commands by command name.",
+	}, []string{"command"})
+
+	// RedisAvailable is 1 while Redis is reachable and 0 while the circuit breaker is open.
+	RedisAvailable = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "knowledge",
+		Name:      "redis_available",
+		Help:      "Whether Redis is reachable (1) or the circuit breaker is open (0).",
+	})
+
+	// ActiveDetections reports the number of active detections across all databases.
+	ActiveDetections = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "knowledge",
+		Name:      "active_detections",
+		Help:      "Number of active detections across all databases.",
+	})
+
+	// Actions reports the number of actions by status.
+	Actions = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "knowledge",
+		Name:      "actions",
+		Help:      "Number of actions by status.",
+	}, []string{"status"})
+
+	// RegisteredDatabases reports the number of registered databases.
+	RegisteredDatabases = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "startupmonkey",
+		Subsystem: "knowledge",
+		Name:      "registered_databases",
+		Help:      "Number of databases registered with Knowledge.",
+	})
+)
+
+// Handler returns the HTTP handler serving metrics in Prometheus format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
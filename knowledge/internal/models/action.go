@@ -1,14 +1,26 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+	"unicode/utf8"
+)
 
 type ActionStatus string
 
 const (
 	StatusQueued    ActionStatus = "queued"
+	StatusScheduled ActionStatus = "scheduled"
 	StatusExecuting ActionStatus = "executing"
 	StatusCompleted ActionStatus = "completed"
 	StatusFailed    ActionStatus = "failed"
+	// StatusRolledBack marks a completed action whose changes were undone.
+	StatusRolledBack ActionStatus = "rolled_back"
+	// StatusPendingImplementation marks an action type the Executor cannot carry out yet.
+	StatusPendingImplementation ActionStatus = "pending_implementation"
 )
 
 type Action struct {
@@ -19,8 +31,142 @@ type Action struct {
 	Status      ActionStatus `json:"status"`
 	Message     string       `json:"message"`
 	Error       string       `json:"error,omitempty"`
-	Result      string       `json:"result,omitempty"`
+	ErrorCode   string       `json:"error_code,omitempty"` // Executor's classification of Error, e.g. PERMISSION_DENIED
+	Result      string       `json:"result,omitempty"`     // JSON-encoded changes made by the action
+	State       string       `json:"state,omitempty"`      // JSON-encoded parameters needed to rebuild the action
+	CanRollback bool         `json:"can_rollback"`
+	Source      string       `json:"source,omitempty"` // What triggered the action, e.g. "detector:missing_index"
 	CreatedAt   time.Time    `json:"created_at"`
-	StartedAt   *time.Time   `json:"started_at,omitempty"`
-	CompletedAt *time.Time   `json:"completed_at,omitempty"`
+
+	// How the Executor's execution policy handled the action (auto, approve or notify),
+	// and the rule that decided it
+	PolicyDecision string `json:"policy_decision,omitempty"`
+	PolicyRule     string `json:"policy_rule,omitempty"`
+
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+
+	Progress *ActionProgress `json:"progress,omitempty"` // Latest progress of a long-running action
+	Outcome  *ActionOutcome  `json:"outcome,omitempty"`  // Before and after metrics, once verified
+}
+
+// PendingImplementationDemand counts requests for one unimplemented action type.
+type PendingImplementationDemand struct {
+	ActionType      string    `json:"action_type"`
+	Count           int       `json:"count"`
+	DatabaseIDs     []string  `json:"database_ids"`
+	LastRequestedAt time.Time `json:"last_requested_at"`
+}
+
+// ActionProgress reports how far a long-running action (e.g. an index build) has got.
+type ActionProgress struct {
+	Phase       string    `json:"phase"`
+	BlocksDone  int64     `json:"blocks_done"`
+	BlocksTotal int64     `json:"blocks_total"`
+	TuplesDone  int64     `json:"tuples_done"`
+	TuplesTotal int64     `json:"tuples_total"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ActionOutcome records whether an action improved the metric behind its detection.
+type ActionOutcome struct {
+	Metric       string    `json:"metric"`
+	MetricBefore float64   `json:"metric_before"`
+	MetricAfter  float64   `json:"metric_after"`
+	Verdict      string    `json:"verdict"`
+	RecordedAt   time.Time `json:"recorded_at"`
+}
+
+// ActionHistoryEntry is one status transition in an action's audit trail.
+type ActionHistoryEntry struct {
+	Status    ActionStatus `json:"status"`
+	Message   string       `json:"message"`
+	Error     string       `json:"error,omitempty"`
+	ErrorCode string       `json:"error_code,omitempty"`
+	Source    string       `json:"source,omitempty"`
+	Timestamp time.Time    `json:"timestamp"`
+}
+
+// MaxActionResultBytes caps the JSON-encoded changes stored with an action.
+const MaxActionResultBytes = 64 * 1024
+
+// maxTruncatedValueBytes is how much of a long value survives when a result is truncated.
+const maxTruncatedValueBytes = 1024
+
+// rollbackFields are the changes the Executor reads to rebuild an action for rollback
+// after a restart. Truncation never cuts them.
+var rollbackFields = []string{
+	"index_name", "table_name", "column_names", "index_definition", "mode",
+	"container_name", "container_id", "config_volume", "pgbouncer_port", "redis_port",
+	"original_shared_buffers", "new_shared_buffers", "original_set_with_alter_system",
+	"original_config", "config_changes",
+}
+
+// TruncateResult keeps an action's JSON-encoded changes within limit bytes. Long values are
+// cut first, each ending with a marker; if that is not enough only the keys and the
+// rollback fields are kept. A truncated result is still a JSON object, with "_truncated" set
+// and "_original_bytes" holding the size it was cut down from. It reports false if even the
+// rollback fields do not fit, so the action can no longer be rolled back from its record.
+func TruncateResult(result string, limit int) (string, bool) {
+	if len(result) <= limit {
+		return result, true
+	}
+
+	var changes map[string]interface{}
+	if err := json.Unmarshal([]byte(result), &changes); err != nil {
+		changes = map[string]interface{}{}
+	}
+
+	keys := make([]string, 0, len(changes))
+	kept := map[string]interface{}{
+		"_truncated":      true,
+		"_original_bytes": len(result),
+	}
+	for key, value := range changes {
+		keys = append(keys, key)
+		if slices.Contains(rollbackFields, key) {
+			kept[key] = value
+			continue
+		}
+		changes[key] = truncateValue(value)
+	}
+	sort.Strings(keys)
+
+	changes["_truncated"] = true
+	changes["_original_bytes"] = len(result)
+	if data, err := json.Marshal(changes); err == nil && len(data) <= limit {
+		return string(data), true
+	}
+
+	kept["_keys"] = keys
+	if data, err := json.Marshal(kept); err == nil && len(data) <= limit {
+		return string(data), true
+	}
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"_truncated":      true,
+		"_original_bytes": len(result),
+		"_keys":           keys,
+	})
+	return string(data), false
+}
+
+// truncateValue cuts a long string, or replaces a large nested value with a marker.
+func truncateValue(value interface{}) interface{} {
+	if s, ok := value.(string); ok {
+		if len(s) <= maxTruncatedValueBytes {
+			return s
+		}
+		cut := maxTruncatedValueBytes
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		return fmt.Sprintf("%s...[truncated %d bytes]", s[:cut], len(s)-cut)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil || len(data) <= maxTruncatedValueBytes {
+		return value
+	}
+	return fmt.Sprintf("[truncated %d bytes]", len(data))
 }
@@ -0,0 +1,56 @@
+package models
+
+// Verdicts the Analyser records once it has verified an action.
+const (
+	VerdictEffective   = "effective"
+	VerdictIneffective = "ineffective"
+)
+
+// ActionStats counts how one action type fared on one database. ActionType or DatabaseID
+// is empty when the stats are summed across them.
+type ActionStats struct {
+	ActionType          string `json:"action_type,omitempty"`
+	DatabaseID          string `json:"database_id,omitempty"`
+	Attempts            int64  `json:"attempts"`  // actions that reached completed or failed
+	Successes           int64  `json:"successes"` // attempts that completed
+	Failures            int64  `json:"failures"`  // attempts that failed
+	Rollbacks           int64  `json:"rollbacks"`
+	VerifiedEffective   int64  `json:"verified_effective"`
+	VerifiedIneffective int64  `json:"verified_ineffective"`
+	TimedAttempts       int64  `json:"timed_attempts"`   // attempts with a known start time
+	ExecutionMillis     int64  `json:"execution_millis"` // summed over TimedAttempts
+}
+
+// Add sums other's counts into s.
+func (s *ActionStats) Add(other *ActionStats) {
+	s.Attempts += other.Attempts
+	s.Successes += other.Successes
+	s.Failures += other.Failures
+	s.Rollbacks += other.Rollbacks
+	s.VerifiedEffective += other.VerifiedEffective
+	s.VerifiedIneffective += other.VerifiedIneffective
+	s.TimedAttempts += other.TimedAttempts
+	s.ExecutionMillis += other.ExecutionMillis
+}
+
+// SuccessRate is the share of attempts that completed, or 0 without attempts.
+func (s *ActionStats) SuccessRate() float64 {
+	return ratio(s.Successes, s.Attempts)
+}
+
+// VerifiedSuccessRate is the share of verified actions the Analyser found effective.
+func (s *ActionStats) VerifiedSuccessRate() float64 {
+	return ratio(s.VerifiedEffective, s.VerifiedEffective+s.VerifiedIneffective)
+}
+
+// AvgExecutionMillis is the mean time from executing to completed or failed.
+func (s *ActionStats) AvgExecutionMillis() float64 {
+	return ratio(s.ExecutionMillis, s.TimedAttempts)
+}
+
+func ratio(n, d int64) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
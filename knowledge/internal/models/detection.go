@@ -8,6 +8,19 @@ const (
 	StateActive     DetectionState = "active"
 	StateResolved   DetectionState = "resolved"
 	StateSuperseded DetectionState = "superseded"
+	// StateUnactionable marks a detection the Executor could not act on.
+	// It stays in the active set so it keeps suppressing duplicates and remains visible.
+	StateUnactionable DetectionState = "unactionable"
+	// StateStale marks a detection not seen again within the stale window; the issue
+	// is assumed to have gone away and may re-fire if it returns.
+	StateStale DetectionState = "stale"
+	// StateAcknowledgedNoAction marks a detection whose fix is not implemented yet. It leaves
+	// the active set but keeps suppressing duplicates until it expires.
+	StateAcknowledgedNoAction DetectionState = "acknowledged_no_action"
+	// StateReopened marks a resolved detection whose action failed or was rolled back. It is
+	// back in the active set but no longer holds its key, so the issue is registered and
+	// published afresh the next time it fires, superseding this record.
+	StateReopened DetectionState = "reopened"
 )
 
 type Detection struct {
@@ -20,7 +33,18 @@ type Detection struct {
 	Value      float64        `json:"value"`
 	ActionID   string         `json:"action_id"`
 	ResolvedBy string         `json:"resolved_by"`
-	CreatedAt  time.Time      `json:"created_at"`
-	LastSeen   time.Time      `json:"last_seen"`
-	TTL        int            `json:"ttl"`
+	// FailureReason explains why an unactionable or acknowledged detection was not acted on,
+	// or why a reopened detection is open again
+	FailureReason string    `json:"failure_reason,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	LastSeen      time.Time `json:"last_seen"`
+	TTL           int       `json:"ttl"`
+
+	// What the detection says; empty on records stored before these were kept
+	Title          string `json:"title,omitempty"`
+	Description    string `json:"description,omitempty"`
+	Recommendation string `json:"recommendation,omitempty"`
+	ActionType     string `json:"action_type,omitempty"`
+	Evidence       string `json:"evidence,omitempty"`        // JSON-encoded
+	ActionMetadata string `json:"action_metadata,omitempty"` // JSON-encoded
 }
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// MetricSample is a database's health scores and key measurements from one collection
+// cycle, or their average over an interval once downsampled. Values are keyed by metric
+// name, e.g. health_score or p95_query_latency_ms.
+type MetricSample struct {
+	DatabaseID string             `json:"database_id"`
+	Timestamp  time.Time          `json:"timestamp"`
+	Values     map[string]float64 `json:"values"`
+}
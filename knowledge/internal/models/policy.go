@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// DatabasePolicy limits what StartupMonkey does to one database: the action types the
+// Executor may run (an empty allow list allows all, denied types always lose) and the
+// detectors the Analyser ignores. Reason records why operators set it.
+type DatabasePolicy struct {
+	DatabaseID         string    `json:"database_id"`
+	AllowedActionTypes []string  `json:"allowed_action_types,omitempty"`
+	DeniedActionTypes  []string  `json:"denied_action_types,omitempty"`
+	IgnoredDetectors   []string  `json:"ignored_detectors,omitempty"`
+	Reason             string    `json:"reason,omitempty"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// IsEmpty reports whether the policy restricts nothing.
+func (p *DatabasePolicy) IsEmpty() bool {
+	return len(p.AllowedActionTypes) == 0 && len(p.DeniedActionTypes) == 0 && len(p.IgnoredDetectors) == 0
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Suppression snoozes a detection key: the Analyser does not publish detections with that
+// key until ExpiresAt. Reason records why operators accepted the issue.
+type Suppression struct {
+	Key       string    `json:"key"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
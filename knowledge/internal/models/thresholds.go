@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// ThresholdSet holds detection threshold overrides for one database, or the global default
+// when DatabaseID is empty. Values are keyed by the Analyser's threshold names.
+type ThresholdSet struct {
+	DatabaseID string             `json:"database_id,omitempty"`
+	Values     map[string]float64 `json:"values"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"net"
 	"time"
 
@@ -14,9 +15,18 @@ import (
 	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/health"
 	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/redis"
 	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	sharedhealth "github.com/EricMurray-e-m-dev/StartupMonkey/proto/health"
 	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
+// staleSweepInterval is how often active detections are checked for staleness.
+const staleSweepInterval = time.Minute
+
+// healthStopTimeout bounds how long Stop waits for in-flight /health requests.
+const healthStopTimeout = 5 * time.Second
+
 // Orchestrator manages the Knowledge service lifecycle and coordinates
 // state management, Redis operations, and gRPC/HTTP server management.
 //
@@ -34,11 +44,13 @@ type Orchestrator struct {
 	config *config.Config
 
 	// Core components
-	redisClient *redis.Client
+	redisClient     *redis.Client
+	knowledgeServer *grpcserver.KnowledgeServer
 
 	// Servers
-	healthServer *health.HealthServer
+	healthServer *sharedhealth.Server
 	grpcServer   *grpc.Server
+	grpcHealth   *grpchealth.Server // NOT_SERVING while Redis is unavailable
 	grpcListener net.Listener
 }
 
@@ -56,7 +68,7 @@ func NewOrchestrator(cfg *config.Config) *Orchestrator {
 // Start connects to:
 //   - Redis (required - central state store)
 //   - gRPC server (required - provides Knowledge API)
-//   - Health check server (optional - monitoring endpoint)
+//   - Health check server (required - monitoring endpoint, fails on a port clash)
 //
 // Returns an error if any required component fails to initialize.
 func (o *Orchestrator) Start() error {
@@ -73,8 +85,7 @@ func (o *Orchestrator) Start() error {
 	}
 
 	if err := o.initializeHealthServer(); err != nil {
-		log.Printf("Warning: failed to initialize health server: %v", err)
-		log.Printf("Health check endpoint will be unavailable")
+		return fmt.Errorf("failed to start health server: %w", err)
 	}
 
 	log.Printf("Knowledge Orchestrator started successfully")
@@ -92,7 +103,28 @@ func (o *Orchestrator) connectRedis() error {
 	}
 
 	o.redisClient = client
+	client.SetDetectionCacheTTL(o.config.DetectionCacheTTL)
 	log.Printf("Connected to Redis")
+
+	if o.config.EncryptionKey != "" {
+		connCipher, err := redis.NewConnectionStringCipher(o.config.EncryptionKey, o.config.PreviousEncryptionKeys)
+		if err != nil {
+			return fmt.Errorf("KNOWLEDGE_ENCRYPTION_KEY: %w", err)
+		}
+		client.SetConnectionStringCipher(connCipher)
+		log.Printf("Connection string encryption enabled (key %s)", connCipher.ActiveKeyID())
+	} else {
+		slog.Warn("KNOWLEDGE_ENCRYPTION_KEY not set - connection strings are stored in plaintext")
+	}
+
+	if err := client.BackfillDatabaseIndexes(context.Background()); err != nil {
+		slog.Warn("Failed to backfill database indexes", "error", err)
+	}
+
+	if err := client.RebuildStatsCounters(context.Background()); err != nil {
+		slog.Warn("Failed to rebuild stats counters", "error", err)
+	}
+
 	return nil
 }
 
@@ -107,24 +139,67 @@ func (o *Orchestrator) initializeGRPCServer() error {
 	}
 	o.grpcListener = listener
 
-	// Create gRPC server
-	o.grpcServer = grpc.NewServer()
+	// Create gRPC server (mutual TLS when GRPC_TLS_* is configured)
+	serverOptions, err := o.config.GRPCTLS.ServerOptions()
+	if err != nil {
+		return err
+	}
+	if o.config.GRPCTLS.Enabled() {
+		log.Printf("gRPC server requires mutual TLS")
+	}
+	o.grpcServer = grpc.NewServer(serverOptions...)
 
 	// Register Knowledge service with Redis client
-	knowledgeServer := grpcserver.NewKnowledgeServer(o.redisClient)
-	pb.RegisterKnowledgeServiceServer(o.grpcServer, knowledgeServer)
+	o.knowledgeServer = grpcserver.NewKnowledgeServer(o.redisClient, o.config.OfflineThreshold)
+	o.knowledgeServer.SetRetentionPolicy(redis.RetentionPolicy{
+		ActionMaxAge:    o.config.ActionRetention,
+		DetectionMaxAge: o.config.DetectionRetention,
+	})
+	pb.RegisterKnowledgeServiceServer(o.grpcServer, o.knowledgeServer)
+
+	// Standard gRPC health checks, following Redis availability
+	o.grpcHealth = grpchealth.NewServer()
+	healthpb.RegisterHealthServer(o.grpcServer, o.grpcHealth)
+	o.setServingStatus(o.redisClient.Available())
+	o.redisClient.SetAvailabilityListener(o.setServingStatus)
 
 	log.Printf("gRPC server initialized on port %s", o.config.GRPCPort)
 	return nil
 }
 
-// initializeHealthServer creates the HTTP health check server.
+// setServingStatus reports the Knowledge service as serving or not to gRPC health checks.
+func (o *Orchestrator) setServingStatus(available bool) {
+	status := healthpb.HealthCheckResponse_SERVING
+	if !available {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	o.grpcHealth.SetServingStatus("", status)
+	o.grpcHealth.SetServingStatus(pb.KnowledgeService_ServiceDesc.ServiceName, status)
+}
+
+// onRedisReconnect repairs state after Redis comes back; if it restarted, counters may no
+// longer match the sets they summarise.
+func (o *Orchestrator) onRedisReconnect(ctx context.Context) {
+	if err := o.redisClient.RebuildStatsCounters(ctx); err != nil {
+		slog.Warn("Failed to rebuild stats counters after Redis reconnect", "error", err)
+		return
+	}
+	log.Printf("Redis reconnection verified, stats counters rebuilt")
+}
+
+// initializeHealthServer starts the HTTP health check server, binding its port up front
+// so a clash fails startup instead of surfacing later from Run.
 func (o *Orchestrator) initializeHealthServer() error {
 	log.Printf("Initializing health check server on port: %s", o.config.HealthPort)
 
-	o.healthServer = health.NewHealthServer(o.redisClient)
+	healthServer := health.NewServer(o.config.HealthPort, o.redisClient, o.config.EnableMetrics)
+	if err := healthServer.Start(); err != nil {
+		return err
+	}
+	o.healthServer = healthServer
 
-	log.Printf("Health check server initialized on port %s", o.config.HealthPort)
+	log.Printf("Health check server listening on port %s", o.config.HealthPort)
 	return nil
 }
 
@@ -133,18 +208,6 @@ func (o *Orchestrator) initializeHealthServer() error {
 func (o *Orchestrator) Run(ctx context.Context) error {
 	log.Printf("Starting servers...")
 
-	// Start health check server in background (if initialized)
-	healthErrChan := make(chan error, 1)
-	if o.healthServer != nil {
-		go func() {
-			addr := ":" + o.config.HealthPort
-			log.Printf("Health check server listening on port %s", o.config.HealthPort)
-			if err := o.healthServer.Start(addr); err != nil {
-				healthErrChan <- fmt.Errorf("health check server error: %w", err)
-			}
-		}()
-	}
-
 	// Start gRPC server in background
 	grpcErrChan := make(chan error, 1)
 	go func() {
@@ -154,6 +217,15 @@ func (o *Orchestrator) Run(ctx context.Context) error {
 		}
 	}()
 
+	// Expire detections the Analyser has stopped seeing
+	go o.runStaleSweeper(ctx)
+
+	// Purge finished actions and closed detections past their retention
+	go o.runRetentionCleanup(ctx)
+
+	// Detect Redis going away and coming back
+	go o.redisClient.MonitorAvailability(ctx, redis.DefaultPingInterval, o.onRedisReconnect)
+
 	log.Printf("Knowledge service ready - central state store active")
 
 	// Wait for context cancellation or server error
@@ -161,19 +233,61 @@ func (o *Orchestrator) Run(ctx context.Context) error {
 	case <-ctx.Done():
 		log.Printf("Shutdown signal received")
 		return ctx.Err()
-	case err := <-healthErrChan:
-		return err
 	case err := <-grpcErrChan:
 		return err
 	}
 }
 
+// runStaleSweeper periodically moves detections not seen within the stale window
+// out of the active set until the context is cancelled.
+func (o *Orchestrator) runStaleSweeper(ctx context.Context) {
+	ticker := time.NewTicker(staleSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		swept, err := o.redisClient.SweepStaleDetections(ctx, o.config.DetectionStaleAfter, time.Now())
+		if err != nil {
+			slog.Warn("Stale detection sweep failed", "error", err)
+		} else if swept > 0 {
+			log.Printf("Swept %d stale detections (not seen for %s)", swept, o.config.DetectionStaleAfter)
+		}
+	}
+}
+
+// runRetentionCleanup periodically purges records past the retention policy until the
+// context is cancelled.
+func (o *Orchestrator) runRetentionCleanup(ctx context.Context) {
+	ticker := time.NewTicker(o.config.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := o.knowledgeServer.RunCleanup(ctx); err != nil {
+			slog.Warn("Retention cleanup failed", "error", err)
+		}
+	}
+}
+
 // Stop gracefully closes all connections and releases resources.
 // This method should be called during application shutdown.
 func (o *Orchestrator) Stop() error {
 	log.Printf("Stopping Orchestrator...")
 
 	// Stop gRPC server (graceful shutdown)
+	if o.grpcHealth != nil {
+		o.grpcHealth.Shutdown()
+	}
 	if o.grpcServer != nil {
 		log.Printf("Stopping gRPC server...")
 		o.grpcServer.GracefulStop()
@@ -181,17 +295,17 @@ func (o *Orchestrator) Stop() error {
 
 	// Stop health check server
 	if o.healthServer != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), healthStopTimeout)
 		defer cancel()
 		if err := o.healthServer.Shutdown(ctx); err != nil {
-			log.Printf("Error stopping health server: %v", err)
+			slog.Error("Error stopping health server", "error", err)
 		}
 	}
 
 	// Close Redis connection
 	if o.redisClient != nil {
 		if err := o.redisClient.Close(); err != nil {
-			log.Printf("Error closing Redis client: %v", err)
+			slog.Error("Error closing Redis client", "error", err)
 		}
 	}
 
@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// Each action keeps an append-only list of its status transitions, so the timeline
+// survives UpdateActionStatus overwriting the action record.
+const (
+	actionHistoryKeyPrefix = "action:history:"
+
+	// actionHistoryLimit bounds the entries kept per action; the oldest are trimmed first
+	actionHistoryLimit = 50
+)
+
+func actionHistoryKey(actionID string) string {
+	return actionHistoryKeyPrefix + actionID
+}
+
+// appendActionHistory records a status transition and trims the list to actionHistoryLimit.
+func (c *Client) appendActionHistory(ctx context.Context, actionID string, entry *models.ActionHistoryEntry) error {
+	pipe := c.rdb.TxPipeline()
+	if err := queueActionHistory(ctx, pipe, actionID, entry); err != nil {
+		return err
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record action history: %w", err)
+	}
+
+	return nil
+}
+
+// queueActionHistory adds appending entry, and trimming the list, to pipe.
+func queueActionHistory(ctx context.Context, pipe redis.Pipeliner, actionID string, entry *models.ActionHistoryEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal action history entry: %w", err)
+	}
+
+	key := actionHistoryKey(actionID)
+	pipe.RPush(ctx, key, data)
+	pipe.LTrim(ctx, key, -actionHistoryLimit, -1)
+	return nil
+}
+
+// GetActionHistory returns an action's status transitions, oldest first.
+func (c *Client) GetActionHistory(ctx context.Context, actionID string) ([]*models.ActionHistoryEntry, error) {
+	items, err := c.rdb.LRange(ctx, actionHistoryKey(actionID), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get action history: %w", err)
+	}
+
+	entries := make([]*models.ActionHistoryEntry, 0, len(items))
+	for _, item := range items {
+		var entry models.ActionHistoryEntry
+		if err := json.Unmarshal([]byte(item), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Mutating action start times are kept per database in a sorted set scored by
+// Unix time, so the Executor's cooldown survives restarts.
+const (
+	databaseActionsKeyPrefix = "actions:started:"
+
+	// databaseActionsRetention bounds how far back start times are kept
+	databaseActionsRetention = 24 * time.Hour
+)
+
+func databaseActionsKey(databaseID string) string {
+	return databaseActionsKeyPrefix + databaseID
+}
+
+// RecordDatabaseAction records that a mutating action started on a database.
+func (c *Client) RecordDatabaseAction(ctx context.Context, databaseID, actionID string, startedAt time.Time) error {
+	key := databaseActionsKey(databaseID)
+	cutoff := startedAt.Add(-databaseActionsRetention).Unix()
+
+	pipe := c.rdb.TxPipeline()
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(startedAt.Unix()), Member: actionID})
+	pipe.ZRemRangeByScore(ctx, key, "-inf", fmt.Sprintf("(%d", cutoff))
+	pipe.Expire(ctx, key, databaseActionsRetention)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record database action: %w", err)
+	}
+
+	return nil
+}
+
+// GetRecentDatabaseActions returns the start times of mutating actions on a database since the given time, oldest first.
+func (c *Client) GetRecentDatabaseActions(ctx context.Context, databaseID string, since time.Time) ([]time.Time, error) {
+	entries, err := c.rdb.ZRangeByScoreWithScores(ctx, databaseActionsKey(databaseID), &redis.ZRangeBy{
+		Min: strconv.FormatInt(since.Unix(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent database actions: %w", err)
+	}
+
+	startedAt := make([]time.Time, 0, len(entries))
+	for _, entry := range entries {
+		startedAt = append(startedAt, time.Unix(int64(entry.Score), 0))
+	}
+
+	return startedAt, nil
+}
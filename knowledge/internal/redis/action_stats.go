@@ -0,0 +1,171 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// Per action type and database counters are kept in hourly hash buckets, one field per
+// "<counter>:<action type>:<database>", so GetActionStats can sum any window up to
+// ActionStatsRetention without scanning action records.
+const (
+	actionStatsBucketPrefix = "stats:action_types:"
+
+	// ActionStatsRetention is the longest window GetActionStats can answer for
+	ActionStatsRetention = 30 * 24 * time.Hour
+
+	statAttempts            = "attempts"
+	statSuccesses           = "successes"
+	statFailures            = "failures"
+	statRollbacks           = "rollbacks"
+	statVerifiedEffective   = "verified_effective"
+	statVerifiedIneffective = "verified_ineffective"
+	statTimedAttempts       = "timed_attempts"
+	statExecutionMillis     = "execution_millis"
+)
+
+func actionStatsBucketKey(t time.Time) string {
+	return fmt.Sprintf("%s%d", actionStatsBucketPrefix, t.Unix()/3600)
+}
+
+// actionStatsDelta returns the counters a status transition adds to. An action counts as an
+// attempt once, when it first reaches completed or failed.
+func actionStatsDelta(action *models.Action, previousStatus models.ActionStatus, now time.Time) map[string]int64 {
+	if action.Status == previousStatus {
+		return nil
+	}
+
+	delta := map[string]int64{}
+	wasTerminal := previousStatus == models.StatusCompleted || previousStatus == models.StatusFailed
+
+	switch action.Status {
+	case models.StatusCompleted, models.StatusFailed:
+		if wasTerminal {
+			return nil
+		}
+		delta[statAttempts] = 1
+		if action.Status == models.StatusCompleted {
+			delta[statSuccesses] = 1
+		} else {
+			delta[statFailures] = 1
+		}
+		if action.StartedAt != nil {
+			delta[statTimedAttempts] = 1
+			delta[statExecutionMillis] = now.Sub(*action.StartedAt).Milliseconds()
+		}
+	case models.StatusRolledBack:
+		delta[statRollbacks] = 1
+	default:
+		return nil
+	}
+
+	return delta
+}
+
+// recordActionStats adds delta to the action's type and database counters in the bucket for at.
+func (c *Client) recordActionStats(ctx context.Context, action *models.Action, delta map[string]int64, at time.Time) error {
+	if len(delta) == 0 {
+		return nil
+	}
+
+	key := actionStatsBucketKey(at)
+
+	pipe := c.rdb.TxPipeline()
+	for counter, n := range delta {
+		pipe.HIncrBy(ctx, key, counter+":"+action.ActionType+":"+action.DatabaseID, n)
+	}
+	pipe.Expire(ctx, key, ActionStatsRetention+time.Hour)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record action stats: %w", err)
+	}
+
+	return nil
+}
+
+// GetActionStats sums the counters recorded since the given time for each action type and
+// database pair, ordered by action type then database. Empty databaseID or actionType match
+// all. Windows reaching further back than ActionStatsRetention are cut to it.
+func (c *Client) GetActionStats(ctx context.Context, since time.Time, databaseID, actionType string) ([]*models.ActionStats, error) {
+	now := time.Now()
+	if oldest := now.Add(-ActionStatsRetention); since.Before(oldest) {
+		since = oldest
+	}
+
+	pipe := c.rdb.Pipeline()
+	var buckets []*redis.MapStringStringCmd
+	for hour := since.Truncate(time.Hour); !hour.After(now); hour = hour.Add(time.Hour) {
+		buckets = append(buckets, pipe.HGetAll(ctx, actionStatsBucketKey(hour)))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get action stats: %w", err)
+	}
+
+	byPair := map[string]*models.ActionStats{}
+	for _, bucket := range buckets {
+		for field, value := range bucket.Val() {
+			parts := strings.SplitN(field, ":", 3)
+			if len(parts) != 3 {
+				continue
+			}
+			counter, fieldType, fieldDatabase := parts[0], parts[1], parts[2]
+			if (actionType != "" && fieldType != actionType) || (databaseID != "" && fieldDatabase != databaseID) {
+				continue
+			}
+
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				continue
+			}
+
+			pair := fieldType + ":" + fieldDatabase
+			stats, ok := byPair[pair]
+			if !ok {
+				stats = &models.ActionStats{ActionType: fieldType, DatabaseID: fieldDatabase}
+				byPair[pair] = stats
+			}
+			addActionStat(stats, counter, n)
+		}
+	}
+
+	result := make([]*models.ActionStats, 0, len(byPair))
+	for _, stats := range byPair {
+		result = append(result, stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ActionType != result[j].ActionType {
+			return result[i].ActionType < result[j].ActionType
+		}
+		return result[i].DatabaseID < result[j].DatabaseID
+	})
+
+	return result, nil
+}
+
+func addActionStat(stats *models.ActionStats, counter string, n int64) {
+	switch counter {
+	case statAttempts:
+		stats.Attempts += n
+	case statSuccesses:
+		stats.Successes += n
+	case statFailures:
+		stats.Failures += n
+	case statRollbacks:
+		stats.Rollbacks += n
+	case statVerifiedEffective:
+		stats.VerifiedEffective += n
+	case statVerifiedIneffective:
+		stats.VerifiedIneffective += n
+	case statTimedAttempts:
+		stats.TimedAttempts += n
+	case statExecutionMillis:
+		stats.ExecutionMillis += n
+	}
+}
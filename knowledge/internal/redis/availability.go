@@ -0,0 +1,197 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/metrics"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// Commands are retried on connection errors, backing off between attempts
+	maxRetries      = 3
+	minRetryBackoff = 50 * time.Millisecond
+	maxRetryBackoff = time.Second
+
+	// breakerThreshold is how many consecutive connection failures open the breaker
+	breakerThreshold = 3
+
+	// DefaultPingInterval is how often the availability monitor pings Redis.
+	DefaultPingInterval = 5 * time.Second
+)
+
+// ErrUnavailable is returned without contacting Redis while the circuit breaker is open.
+var ErrUnavailable = errors.New("redis unavailable")
+
+type probeKey struct{}
+
+// breaker opens after breakerThreshold consecutive connection failures, failing commands
+// fast until the availability monitor's ping gets through again.
+type breaker struct {
+	mu       sync.Mutex
+	failures int
+	open     bool
+	onChange func(available bool)
+}
+
+// allow reports whether a command may be sent. Pings from the availability monitor are
+// always sent so the breaker can close.
+func (b *breaker) allow(ctx context.Context) bool {
+	if ctx.Value(probeKey{}) != nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.open
+}
+
+// record counts the outcome of a command; only connection errors count as failures, since
+// a reply such as redis.Nil or WRONGTYPE means Redis is up.
+func (b *breaker) record(err error) {
+	if err != nil && !isConnectionError(err) {
+		return
+	}
+
+	b.mu.Lock()
+	wasOpen := b.open
+	if err == nil {
+		b.failures = 0
+		b.open = false
+	} else {
+		b.failures++
+		b.open = b.failures >= breakerThreshold
+	}
+	changed := wasOpen != b.open
+	onChange := b.onChange
+	b.mu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	if b.open {
+		slog.Error("Redis unavailable, failing fast", "consecutive_failures", breakerThreshold, "error", err)
+		metrics.RedisAvailable.Set(0)
+	} else {
+		log.Printf("Redis available again")
+		metrics.RedisAvailable.Set(1)
+	}
+	if onChange != nil {
+		onChange(!b.open)
+	}
+}
+
+func (b *breaker) isOpen() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.open
+}
+
+// isConnectionError reports whether err means Redis could not be reached, as opposed to
+// Redis answering with an error.
+func isConnectionError(err error) bool {
+	// A caller giving up is not Redis failing; context.DeadlineExceeded is also a net.Error
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if errors.Is(err, ErrUnavailable) || errors.Is(err, redis.ErrClosed) ||
+		errors.Is(err, io.EOF) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// Redis is up but still loading its dataset from disk after a restart
+	return strings.HasPrefix(err.Error(), "LOADING ")
+}
+
+// breakerHook fails commands fast while the breaker is open and feeds it their outcomes.
+// It sees each command once, after go-redis has retried it.
+type breakerHook struct {
+	breaker *breaker
+}
+
+func (h breakerHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h breakerHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if !h.breaker.allow(ctx) {
+			cmd.SetErr(ErrUnavailable)
+			return ErrUnavailable
+		}
+		err := next(ctx, cmd)
+		h.breaker.record(err)
+		return err
+	}
+}
+
+func (h breakerHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if !h.breaker.allow(ctx) {
+			for _, cmd := range cmds {
+				cmd.SetErr(ErrUnavailable)
+			}
+			return ErrUnavailable
+		}
+		err := next(ctx, cmds)
+		h.breaker.record(err)
+		return err
+	}
+}
+
+// SetAvailabilityListener registers fn to be called when Redis becomes unavailable or
+// available again.
+func (c *Client) SetAvailabilityListener(fn func(available bool)) {
+	c.breaker.mu.Lock()
+	defer c.breaker.mu.Unlock()
+	c.breaker.onChange = fn
+}
+
+// Available reports whether Redis is reachable, as last seen by the circuit breaker.
+func (c *Client) Available() bool {
+	return !c.breaker.isOpen()
+}
+
+// MonitorAvailability pings Redis every interval until ctx is cancelled. The pings bypass
+// the circuit breaker, so they open it when Redis goes away and close it once Redis answers
+// again, after which onReconnect runs to repair state that may have drifted.
+func (c *Client) MonitorAvailability(ctx context.Context, interval time.Duration, onReconnect func(context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	probeCtx := context.WithValue(ctx, probeKey{}, true)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		wasOpen := c.breaker.isOpen()
+
+		pingCtx, cancel := context.WithTimeout(probeCtx, interval)
+		err := c.rdb.Ping(pingCtx).Err()
+		cancel()
+
+		if err == nil && wasOpen && onReconnect != nil {
+			onReconnect(ctx)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// mgetBatchSize bounds the keys one MGET fetches, so a large set never blocks Redis for long.
+const mgetBatchSize = 500
+
+// getMany fetches the values of keys with one MGET per batch, sent in a single pipeline.
+// Values line up with keys: a string, or nil where the key does not exist.
+func (c *Client) getMany(ctx context.Context, keys []string) ([]interface{}, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	pipe := c.rdb.Pipeline()
+	batches := make([]*redis.SliceCmd, 0, (len(keys)+mgetBatchSize-1)/mgetBatchSize)
+	for start := 0; start < len(keys); start += mgetBatchSize {
+		end := min(start+mgetBatchSize, len(keys))
+		batches = append(batches, pipe.MGet(ctx, keys[start:end]...))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, 0, len(keys))
+	for _, batch := range batches {
+		values = append(values, batch.Val()...)
+	}
+	return values, nil
+}
+
+// prefixKeys returns the record keys for ids, e.g. "action:" + id.
+func prefixKeys(prefix string, ids []string) []string {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = prefix + id
+	}
+	return keys
+}
@@ -4,12 +4,21 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
+	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/metrics"
 	"github.com/redis/go-redis/v9"
 )
 
 type Client struct {
-	rdb *redis.Client
+	rdb     *redis.Client
+	breaker *breaker
+
+	// Encrypts database connection strings at rest; nil stores them in plaintext
+	connCipher *ConnectionStringCipher
+
+	// Recent IsDetectionActive answers, invalidated by this client's own detection writes
+	detections *detectionCache
 }
 
 func NewClient(addr string, pword string, db int) (*Client, error) {
@@ -17,7 +26,16 @@ func NewClient(addr string, pword string, db int) (*Client, error) {
 		Addr:     addr,
 		Password: pword,
 		DB:       db,
+
+		// Ride out transient connection errors, e.g. Redis restarting
+		MaxRetries:      maxRetries,
+		MinRetryBackoff: minRetryBackoff,
+		MaxRetryBackoff: maxRetryBackoff,
 	})
+	rdb.AddHook(metricsHook{})
+
+	b := &breaker{}
+	rdb.AddHook(breakerHook{breaker: b})
 
 	ctx := context.Background()
 	if err := rdb.Ping(ctx).Err(); err != nil {
@@ -25,8 +43,21 @@ func NewClient(addr string, pword string, db int) (*Client, error) {
 	}
 
 	log.Printf("Connected to Redis: %s", addr)
+	metrics.RedisAvailable.Set(1)
+
+	return &Client{rdb: rdb, breaker: b, detections: newDetectionCache(DefaultDetectionCacheTTL)}, nil
+}
+
+// SetConnectionStringCipher enables encryption of stored connection strings. Records
+// written in plaintext before it was enabled are encrypted the next time they are read.
+func (c *Client) SetConnectionStringCipher(connCipher *ConnectionStringCipher) {
+	c.connCipher = connCipher
+}
 
-	return &Client{rdb: rdb}, nil
+// SetDetectionCacheTTL changes how long IsDetectionActive answers are reused; 0 disables
+// the cache and reads every answer from Redis.
+func (c *Client) SetDetectionCacheTTL(ttl time.Duration) {
+	c.detections.setTTL(ttl)
 }
 
 func (c *Client) Close() error {
@@ -0,0 +1,92 @@
+package redis
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultDetectionCacheTTL is how long an IsDetectionActive answer is reused. The Analyser
+// asks about the same keys on every snapshot, so even a short TTL saves most lookups.
+const DefaultDetectionCacheTTL = 2 * time.Second
+
+// maxDetectionCacheEntries bounds the cache; when full, expired entries are dropped and,
+// if none have expired, the whole cache is cleared.
+const maxDetectionCacheEntries = 10000
+
+// detectionCache remembers whether detection keys are live, for a short TTL. Every write
+// this process makes to a detection's state invalidates its key, so the dedup logic never
+// acts on a stale read of its own write; writes by other processes are seen within the TTL.
+type detectionCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration // 0 disables the cache
+	entries map[string]detectionCacheEntry
+
+	// Bumped by every invalidation, so a lookup that raced with a write doesn't cache
+	// what it read before the write
+	generation uint64
+}
+
+type detectionCacheEntry struct {
+	active  bool
+	expires time.Time
+}
+
+func newDetectionCache(ttl time.Duration) *detectionCache {
+	return &detectionCache{ttl: ttl, entries: make(map[string]detectionCacheEntry)}
+}
+
+// get returns the cached answer for key, if there is one that has not expired. On a miss
+// it returns the generation to pass to put once the answer has been read from Redis.
+func (c *detectionCache) get(key string, now time.Time) (active bool, ok bool, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !now.Before(entry.expires) {
+		return false, false, c.generation
+	}
+	return entry.active, true, c.generation
+}
+
+// put caches the answer for key until the TTL passes, unless a detection was invalidated
+// since get returned generation.
+func (c *detectionCache) put(key string, active bool, now time.Time, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ttl <= 0 || generation != c.generation {
+		return
+	}
+
+	if len(c.entries) >= maxDetectionCacheEntries {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxDetectionCacheEntries {
+			clear(c.entries)
+		}
+	}
+
+	c.entries[key] = detectionCacheEntry{active: active, expires: now.Add(c.ttl)}
+}
+
+// invalidate forgets key, after this process changed the state of its detection.
+func (c *detectionCache) invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+	c.generation++
+}
+
+// setTTL changes the TTL and forgets everything cached.
+func (c *detectionCache) setTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ttl = ttl
+	clear(c.entries)
+	c.generation++
+}
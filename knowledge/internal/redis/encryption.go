@@ -0,0 +1,108 @@
+package redis
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// ConnectionStringCipher encrypts connection strings at rest with AES-256-GCM. The active
+// key encrypts; previous keys are kept for decryption only, so a key can be rotated without
+// re-registering every database. Each ciphertext is stored with the ID of its key.
+type ConnectionStringCipher struct {
+	activeKeyID string
+	keys        map[string]cipher.AEAD
+}
+
+// NewConnectionStringCipher builds a cipher from base64-encoded 32-byte keys.
+func NewConnectionStringCipher(activeKey string, previousKeys []string) (*ConnectionStringCipher, error) {
+	c := &ConnectionStringCipher{keys: make(map[string]cipher.AEAD)}
+
+	keyID, err := c.addKey(activeKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	c.activeKeyID = keyID
+
+	for i, key := range previousKeys {
+		if _, err := c.addKey(key); err != nil {
+			return nil, fmt.Errorf("invalid previous encryption key %d: %w", i+1, err)
+		}
+	}
+
+	return c, nil
+}
+
+func (c *ConnectionStringCipher) addKey(encoded string) (string, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return "", fmt.Errorf("not valid base64: %w", err)
+	}
+	if len(key) != 32 {
+		return "", fmt.Errorf("must decode to 32 bytes, got %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+
+	// The ID is derived from the key so it never has to be configured separately
+	sum := sha256.Sum256(key)
+	keyID := hex.EncodeToString(sum[:4])
+	c.keys[keyID] = aead
+
+	return keyID, nil
+}
+
+// ActiveKeyID returns the ID of the key new ciphertexts are written with.
+func (c *ConnectionStringCipher) ActiveKeyID() string {
+	return c.activeKeyID
+}
+
+// Encrypt seals plaintext with the active key. databaseID is bound as additional data, so a
+// ciphertext copied onto another database record fails to decrypt.
+func (c *ConnectionStringCipher) Encrypt(plaintext, databaseID string) (keyID string, ciphertext string, err error) {
+	aead := c.keys[c.activeKeyID]
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(databaseID))
+	return c.activeKeyID, base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a ciphertext written with the key identified by keyID.
+func (c *ConnectionStringCipher) Decrypt(keyID, ciphertext, databaseID string) (string, error) {
+	aead, ok := c.keys[keyID]
+	if !ok {
+		return "", fmt.Errorf("encryption key %s is not configured", keyID)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("invalid ciphertext: %w", err)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("invalid ciphertext: too short")
+	}
+
+	nonce, sealed := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, []byte(databaseID))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt connection string: %w", err)
+	}
+
+	return string(plaintext), nil
+}
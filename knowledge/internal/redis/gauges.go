@@ -0,0 +1,55 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/metrics"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/models"
+)
+
+// The state gauges are set where the state changes, from the counters and sets the change
+// just wrote, so a scrape never reads Redis. A failed read leaves the gauge as it was.
+
+// gaugedActionStatuses are the statuses reported by the actions gauge.
+var gaugedActionStatuses = map[models.ActionStatus]bool{
+	models.StatusQueued:     true,
+	models.StatusScheduled:  true,
+	models.StatusExecuting:  true,
+	models.StatusCompleted:  true,
+	models.StatusFailed:     true,
+	models.StatusRolledBack: true,
+}
+
+// RefreshGauges sets every state gauge from Redis. Work sites keep them current afterwards;
+// this covers startup and changes missed while Redis was away.
+func (c *Client) RefreshGauges(ctx context.Context) {
+	c.updateActiveDetectionsGauge(ctx)
+	for status := range gaugedActionStatuses {
+		c.updateActionGauges(ctx, status)
+	}
+	c.updateDatabasesGauge(ctx)
+}
+
+func (c *Client) updateActiveDetectionsGauge(ctx context.Context) {
+	if active, err := c.rdb.HGet(ctx, statsActiveDetectionsKey, statsTotalField).Int64(); err == nil {
+		metrics.ActiveDetections.Set(float64(active))
+	}
+}
+
+func (c *Client) updateActionGauges(ctx context.Context, statuses ...models.ActionStatus) {
+	for _, status := range statuses {
+		if !gaugedActionStatuses[status] {
+			continue
+		}
+		if count, err := c.rdb.SCard(ctx, fmt.Sprintf("action:status:%s", status)).Result(); err == nil {
+			metrics.Actions.WithLabelValues(string(status)).Set(float64(count))
+		}
+	}
+}
+
+func (c *Client) updateDatabasesGauge(ctx context.Context) {
+	if count, err := c.rdb.ZCard(ctx, allDatabasesKey).Result(); err == nil {
+		metrics.RegisteredDatabases.Set(float64(count))
+	}
+}
@@ -0,0 +1,242 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// Metric samples are kept per database in two sorted sets scored by Unix time: every sample
+// for MetricHistoryRetention, and averages over MetricDownsampleInterval buckets for
+// MetricDownsampledRetention. The bucket a sample falls in is recomputed as it is written.
+const (
+	metricHistoryKeyPrefix     = "metrics:history:"
+	metricDownsampledKeyPrefix = "metrics:downsampled:"
+
+	MetricHistoryRetention     = 24 * time.Hour
+	MetricDownsampledRetention = 7 * 24 * time.Hour
+	MetricDownsampleInterval   = 5 * time.Minute
+
+	// downsampleAttempts bounds retries when another sample for the database lands
+	// while its bucket is being recomputed
+	downsampleAttempts = 5
+)
+
+// storedSample is a sample as held in Redis, kept compact as there is one per cycle.
+// Count is the number of samples averaged into a downsampled bucket.
+type storedSample struct {
+	Timestamp int64              `json:"t"`
+	Values    map[string]float64 `json:"v"`
+	Count     int                `json:"n,omitempty"`
+}
+
+func metricHistoryKey(databaseID string) string {
+	return metricHistoryKeyPrefix + databaseID
+}
+
+func metricDownsampledKey(databaseID string) string {
+	return metricDownsampledKeyPrefix + databaseID
+}
+
+// RecordMetricSample stores a sample at full resolution and updates the downsampled bucket
+// it falls in. Samples older than each retention are trimmed as new ones arrive.
+func (c *Client) RecordMetricSample(ctx context.Context, sample *models.MetricSample) error {
+	ts := sample.Timestamp.Unix()
+	data, err := json.Marshal(&storedSample{Timestamp: ts, Values: sample.Values})
+	if err != nil {
+		return fmt.Errorf("failed to marshal metric sample: %w", err)
+	}
+
+	key := metricHistoryKey(sample.DatabaseID)
+	cutoff := sample.Timestamp.Add(-MetricHistoryRetention).Unix()
+
+	pipe := c.rdb.TxPipeline()
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(ts), Member: data})
+	pipe.ZRemRangeByScore(ctx, key, "-inf", fmt.Sprintf("(%d", cutoff))
+	pipe.Expire(ctx, key, MetricHistoryRetention)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record metric sample: %w", err)
+	}
+
+	return c.downsample(ctx, sample.DatabaseID, sample.Timestamp)
+}
+
+// downsample recomputes the average of the bucket containing at from the full-resolution
+// samples. The full-resolution set is watched so a sample written meanwhile is not missed.
+func (c *Client) downsample(ctx context.Context, databaseID string, at time.Time) error {
+	interval := int64(MetricDownsampleInterval / time.Second)
+	bucket := at.Unix() - at.Unix()%interval
+
+	key := metricHistoryKey(databaseID)
+	downsampledKey := metricDownsampledKey(databaseID)
+	cutoff := at.Add(-MetricDownsampledRetention).Unix()
+
+	for attempt := 0; attempt < downsampleAttempts; attempt++ {
+		err := c.rdb.Watch(ctx, func(tx *redis.Tx) error {
+			members, err := tx.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+				Min: strconv.FormatInt(bucket, 10),
+				Max: fmt.Sprintf("(%d", bucket+interval),
+			}).Result()
+			if err != nil {
+				return fmt.Errorf("failed to read metric samples: %w", err)
+			}
+
+			samples := decodeSamples(members)
+			if len(samples) == 0 {
+				return nil
+			}
+
+			averaged := averageSamples(bucket, samples)
+			data, err := json.Marshal(averaged)
+			if err != nil {
+				return fmt.Errorf("failed to marshal downsampled metrics: %w", err)
+			}
+
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				score := strconv.FormatInt(bucket, 10)
+				pipe.ZRemRangeByScore(ctx, downsampledKey, score, score)
+				pipe.ZAdd(ctx, downsampledKey, redis.Z{Score: float64(bucket), Member: data})
+				pipe.ZRemRangeByScore(ctx, downsampledKey, "-inf", fmt.Sprintf("(%d", cutoff))
+				pipe.Expire(ctx, downsampledKey, MetricDownsampledRetention)
+				return nil
+			})
+			return err
+		}, key)
+
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to downsample metrics: %w", err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("metric samples for %s kept changing, gave up downsampling after %d attempts", databaseID, downsampleAttempts)
+}
+
+// GetMetricHistory returns a database's samples between from and to, oldest first, averaged
+// to resolution (0 for every sample). Ranges within MetricHistoryRetention are served from
+// full-resolution samples for resolutions under MetricDownsampleInterval; otherwise samples
+// come from the downsampled set and resolution is rounded up to a multiple of the interval.
+// The resolution actually returned is reported alongside the samples.
+func (c *Client) GetMetricHistory(ctx context.Context, databaseID string, from, to time.Time, resolution time.Duration, now time.Time) ([]*models.MetricSample, time.Duration, error) {
+	if to.IsZero() {
+		to = now
+	}
+	if from.IsZero() {
+		from = now.Add(-MetricDownsampledRetention)
+	}
+	if resolution < 0 {
+		resolution = 0
+	}
+
+	key := metricHistoryKey(databaseID)
+	stored := time.Duration(0)
+	if resolution >= MetricDownsampleInterval || from.Before(now.Add(-MetricHistoryRetention)) {
+		key = metricDownsampledKey(databaseID)
+		stored = MetricDownsampleInterval
+		resolution = roundUp(resolution, MetricDownsampleInterval)
+	}
+
+	members, err := c.rdb.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min: strconv.FormatInt(from.Unix(), 10),
+		Max: strconv.FormatInt(to.Unix(), 10),
+	}).Result()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get metric history: %w", err)
+	}
+
+	samples := decodeSamples(members)
+	if resolution > stored {
+		samples = regroupSamples(samples, int64(resolution/time.Second))
+	}
+
+	history := make([]*models.MetricSample, 0, len(samples))
+	for _, sample := range samples {
+		history = append(history, &models.MetricSample{
+			DatabaseID: databaseID,
+			Timestamp:  time.Unix(sample.Timestamp, 0),
+			Values:     sample.Values,
+		})
+	}
+
+	return history, resolution, nil
+}
+
+// decodeSamples unmarshals sorted set members, skipping any that don't decode.
+func decodeSamples(members []string) []*storedSample {
+	samples := make([]*storedSample, 0, len(members))
+	for _, member := range members {
+		var sample storedSample
+		if err := json.Unmarshal([]byte(member), &sample); err != nil {
+			continue
+		}
+		if sample.Count == 0 {
+			sample.Count = 1
+		}
+		samples = append(samples, &sample)
+	}
+	return samples
+}
+
+// averageSamples averages samples into one at timestamp, each weighted by the number of
+// samples it already stands for. A metric missing from some samples is averaged over the
+// samples that have it.
+func averageSamples(timestamp int64, samples []*storedSample) *storedSample {
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	total := 0
+
+	for _, sample := range samples {
+		total += sample.Count
+		for name, value := range sample.Values {
+			sums[name] += value * float64(sample.Count)
+			counts[name] += sample.Count
+		}
+	}
+
+	values := make(map[string]float64, len(sums))
+	for name, sum := range sums {
+		values[name] = sum / float64(counts[name])
+	}
+
+	return &storedSample{Timestamp: timestamp, Values: values, Count: total}
+}
+
+// regroupSamples averages time-ordered samples into buckets of the given width in seconds.
+func regroupSamples(samples []*storedSample, width int64) []*storedSample {
+	var grouped []*storedSample
+	var bucket []*storedSample
+	bucketStart := int64(0)
+
+	for _, sample := range samples {
+		start := sample.Timestamp - sample.Timestamp%width
+		if len(bucket) > 0 && start != bucketStart {
+			grouped = append(grouped, averageSamples(bucketStart, bucket))
+			bucket = nil
+		}
+		bucketStart = start
+		bucket = append(bucket, sample)
+	}
+	if len(bucket) > 0 {
+		grouped = append(grouped, averageSamples(bucketStart, bucket))
+	}
+
+	return grouped
+}
+
+// roundUp rounds d up to a multiple of unit, with a minimum of one unit.
+func roundUp(d, unit time.Duration) time.Duration {
+	if d <= unit {
+		return unit
+	}
+	return (d + unit - 1) / unit * unit
+}
@@ -0,0 +1,47 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/metrics"
+	"github.com/redis/go-redis/v9"
+)
+
+// metricsHook records Redis command latency and errors to Prometheus.
+type metricsHook struct{}
+
+func (metricsHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (metricsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		observeCommand(cmd.Name(), time.Since(start), err)
+		return err
+	}
+}
+
+func (metricsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		observeCommand("pipeline", time.Since(start), err)
+		return err
+	}
+}
+
+func observeCommand(name string, elapsed time.Duration, err error) {
+	metrics.RedisOpDuration.WithLabelValues(name).Observe(elapsed.Seconds())
+
+	// redis.Nil is a cache miss, not a failure
+	if err != nil && !errors.Is(err, redis.Nil) {
+		metrics.RedisOpErrors.WithLabelValues(name).Inc()
+	}
+}
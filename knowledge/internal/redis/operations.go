@@ -5,16 +5,23 @@ package redis
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"log/slog"
+	"sort"
 	"time"
 
 	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/models"
 	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/redis/go-redis/v9"
 )
 
 // ===== [DETECTION OPERATIONS] =====
 
-// RegisterDetection stores a new detection and adds it to the active set.
+// RegisterDetection stores a new detection and adds it to the active set. The record, key
+// mapping and set membership are written in one transaction, so the active set never
+// holds a detection without its record.
 func (c *Client) RegisterDetection(ctx context.Context, detection *models.Detection) error {
 	detectionKey := fmt.Sprintf("detection:%s", detection.ID)
 
@@ -23,25 +30,155 @@ func (c *Client) RegisterDetection(ctx context.Context, detection *models.Detect
 		return fmt.Errorf("failed to marshal detection: %w", err)
 	}
 
-	if err := c.rdb.Set(ctx, detectionKey, data, 0).Err(); err != nil {
+	keyMapping := fmt.Sprintf("detection_key:%s", detection.Key)
+	activeKey := fmt.Sprintf("detections:active:%s", detection.DatabaseID)
+	defer c.detections.invalidate(detection.Key)
+
+	pipe := c.rdb.TxPipeline()
+	pipe.Set(ctx, detectionKey, data, 0)
+	pipe.Set(ctx, keyMapping, detection.ID, 0)
+	added := pipe.SAdd(ctx, activeKey, detection.ID)
+
+	if _, err := pipe.Exec(ctx); err != nil {
 		return fmt.Errorf("failed to store detection: %w", err)
 	}
 
-	keyMapping := fmt.Sprintf("detection_key:%s", detection.Key)
-	if err := c.rdb.Set(ctx, keyMapping, detection.ID, 0).Err(); err != nil {
-		return fmt.Errorf("failed to store key mapping: %w", err)
+	// Counters drifting after a crash here are corrected by RebuildStatsCounters
+	if added.Val() > 0 {
+		return c.adjustDetectionCounters(ctx, statsActiveDetectionsKey, detection, 1)
+	}
+
+	return nil
+}
+
+// registerIfAbsentAttempts bounds how often RegisterDetectionIfAbsent retries after another
+// writer touched the key mapping; a retry normally finds that writer's detection.
+const registerIfAbsentAttempts = 5
+
+// RegisterDetectionIfAbsent stores detection unless a detection with its key is already
+// active, and reports which detection holds the key. The key mapping is watched, so of two
+// callers racing on the same key exactly one registers.
+func (c *Client) RegisterDetectionIfAbsent(ctx context.Context, detection *models.Detection) (bool, string, error) {
+	data, err := json.Marshal(detection)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to marshal detection: %w", err)
 	}
 
+	keyMapping := fmt.Sprintf("detection_key:%s", detection.Key)
 	activeKey := fmt.Sprintf("detections:active:%s", detection.DatabaseID)
-	if err := c.rdb.SAdd(ctx, activeKey, detection.ID).Err(); err != nil {
-		return fmt.Errorf("failed to add to active set: %w", err)
+	defer c.detections.invalidate(detection.Key)
+
+	for attempt := 0; attempt < registerIfAbsentAttempts; attempt++ {
+		registered := false
+		existingID := ""
+		var added, superseded *redis.IntCmd
+		var supersededDetection *models.Detection
+
+		err := c.rdb.Watch(ctx, func(tx *redis.Tx) error {
+			id, err := tx.Get(ctx, keyMapping).Result()
+			if err != nil && !errors.Is(err, redis.Nil) {
+				return fmt.Errorf("failed to check detection key: %w", err)
+			}
+			var reopened *models.Detection
+			if id != "" {
+				existing, err := tx.Get(ctx, "detection:"+id).Result()
+				if err != nil && !errors.Is(err, redis.Nil) {
+					return fmt.Errorf("failed to get detection: %w", err)
+				}
+				var stored models.Detection
+				if existing != "" && json.Unmarshal([]byte(existing), &stored) == nil {
+					if isLiveDetectionState(stored.State) {
+						existingID = id
+						return nil
+					}
+					if stored.State == models.StateReopened {
+						reopened = &stored
+					}
+				}
+			}
+
+			// A reopened detection is replaced by the one that fired again
+			var supersededData []byte
+			if reopened != nil {
+				reopened.State = models.StateSuperseded
+				reopened.TTL = 300
+				if supersededData, err = json.Marshal(reopened); err != nil {
+					return fmt.Errorf("failed to marshal detection: %w", err)
+				}
+			}
+
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				if reopened != nil {
+					pipe.Set(ctx, "detection:"+reopened.ID, supersededData, time.Duration(reopened.TTL)*time.Second)
+					superseded = pipe.SRem(ctx, fmt.Sprintf("detections:active:%s", reopened.DatabaseID), reopened.ID)
+					supersededDetection = reopened
+				}
+				pipe.Set(ctx, "detection:"+detection.ID, data, 0)
+				pipe.Set(ctx, keyMapping, detection.ID, 0)
+				added = pipe.SAdd(ctx, activeKey, detection.ID)
+				return nil
+			})
+			registered = err == nil
+			return err
+		}, keyMapping)
+
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		if err != nil {
+			return false, "", err
+		}
+		if !registered {
+			return false, existingID, nil
+		}
+
+		// Counters drifting after a crash here are corrected by RebuildStatsCounters
+		if superseded != nil && superseded.Val() > 0 {
+			if err := c.adjustDetectionCounters(ctx, statsActiveDetectionsKey, supersededDetection, -1); err != nil {
+				return true, detection.ID, err
+			}
+		}
+		if added.Val() > 0 {
+			if err := c.adjustDetectionCounters(ctx, statsActiveDetectionsKey, detection, 1); err != nil {
+				return true, detection.ID, err
+			}
+		}
+		return true, detection.ID, nil
 	}
 
-	return nil
+	return false, "", fmt.Errorf("detection key %s kept changing, gave up after %d attempts", detection.Key, registerIfAbsentAttempts)
+}
+
+// isLiveDetectionState reports whether a detection in state still holds its key. Unactionable
+// and acknowledged detections would only fail again if re-registered.
+func isLiveDetectionState(state models.DetectionState) bool {
+	switch state {
+	case models.StateActive, models.StateUnactionable, models.StateAcknowledgedNoAction:
+		return true
+	}
+	return false
 }
 
-// IsDetectionActive checks if a detection with the given key is currently active.
+// IsDetectionActive checks if a detection with the given key is currently active. Answers
+// are cached briefly; see detectionCache.
 func (c *Client) IsDetectionActive(ctx context.Context, key string) (bool, error) {
+	now := time.Now()
+	active, ok, generation := c.detections.get(key, now)
+	if ok {
+		return active, nil
+	}
+
+	active, err := c.isDetectionActive(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	c.detections.put(key, active, now, generation)
+	return active, nil
+}
+
+// isDetectionActive reads from Redis whether the detection holding key is live.
+func (c *Client) isDetectionActive(ctx context.Context, key string) (bool, error) {
 	keyMapping := fmt.Sprintf("detection_key:%s", key)
 
 	detectionID, err := c.rdb.Get(ctx, keyMapping).Result()
@@ -57,7 +194,7 @@ func (c *Client) IsDetectionActive(ctx context.Context, key string) (bool, error
 		return false, err
 	}
 
-	return detection.State == models.StateActive, nil
+	return isLiveDetectionState(detection.State), nil
 }
 
 // GetDetection retrieves a detection by ID.
@@ -96,6 +233,7 @@ func (c *Client) MarkDetectionResolved(ctx context.Context, id string, solution
 	if err != nil {
 		return err
 	}
+	defer c.detections.invalidate(detection.Key)
 
 	detection.State = models.StateResolved
 	detection.ResolvedBy = solution
@@ -112,14 +250,271 @@ func (c *Client) MarkDetectionResolved(ctx context.Context, id string, solution
 	}
 
 	activeKey := fmt.Sprintf("detections:active:%s", detection.DatabaseID)
-	if err := c.rdb.SRem(ctx, activeKey, detection.ID).Err(); err != nil {
+	removed, err := c.rdb.SRem(ctx, activeKey, detection.ID).Result()
+	if err != nil {
 		return fmt.Errorf("failed to remove from active set: %w", err)
 	}
 
+	// Only count the transition once, even if a resolution is delivered twice
+	if removed > 0 {
+		if err := c.adjustDetectionCounters(ctx, statsActiveDetectionsKey, detection, -1); err != nil {
+			return err
+		}
+		return c.adjustDetectionCounters(ctx, statsResolvedDetectionsKey, detection, 1)
+	}
+
+	return nil
+}
+
+// ReopenDetection puts a resolved detection back in the active set after the action that
+// fixed it failed or was rolled back, so it shows as an open problem again. The reopened
+// detection does not hold its key, so the next time the issue fires it is registered and
+// published as a new detection instead of being skipped as already active. It reports whether
+// the detection was reopened: one that is not resolved, has expired or was superseded by a
+// newer detection with the same key is left as it is.
+func (c *Client) ReopenDetection(ctx context.Context, id string, reason string) (bool, error) {
+	detection, err := c.GetDetection(ctx, id)
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if detection.State != models.StateResolved {
+		return false, nil
+	}
+	defer c.detections.invalidate(detection.Key)
+
+	keyMapping := fmt.Sprintf("detection_key:%s", detection.Key)
+	holder, err := c.rdb.Get(ctx, keyMapping).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return false, fmt.Errorf("failed to check detection key: %w", err)
+	}
+	if holder != "" && holder != detection.ID {
+		return false, nil
+	}
+
+	detection.State = models.StateReopened
+	detection.ResolvedBy = ""
+	detection.FailureReason = reason
+	detection.TTL = 0
+	detection.LastSeen = time.Now()
+
+	data, err := json.Marshal(detection)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal detection: %w", err)
+	}
+
+	activeKey := fmt.Sprintf("detections:active:%s", detection.DatabaseID)
+
+	pipe := c.rdb.TxPipeline()
+	pipe.Set(ctx, "detection:"+detection.ID, data, 0)
+	pipe.Set(ctx, keyMapping, detection.ID, 0)
+	added := pipe.SAdd(ctx, activeKey, detection.ID)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, fmt.Errorf("failed to reopen detection: %w", err)
+	}
+
+	if added.Val() > 0 {
+		if err := c.adjustDetectionCounters(ctx, statsResolvedDetectionsKey, detection, -1); err != nil {
+			return true, err
+		}
+		return true, c.adjustDetectionCounters(ctx, statsActiveDetectionsKey, detection, 1)
+	}
+
+	return true, nil
+}
+
+// MarkDetectionUnactionable records that the Executor could not act on a detection.
+// The detection remains in the active set so it is still surfaced to the Dashboard.
+func (c *Client) MarkDetectionUnactionable(ctx context.Context, id string, reason string) error {
+	detection, err := c.GetDetection(ctx, id)
+	if err != nil {
+		return err
+	}
+	defer c.detections.invalidate(detection.Key)
+
+	detection.State = models.StateUnactionable
+	detection.FailureReason = reason
+
+	detectionKey := fmt.Sprintf("detection:%s", detection.ID)
+	data, err := json.Marshal(detection)
+	if err != nil {
+		return fmt.Errorf("failed to marshal detection: %w", err)
+	}
+
+	if err := c.rdb.Set(ctx, detectionKey, data, 0).Err(); err != nil {
+		return fmt.Errorf("failed to update detection: %w", err)
+	}
+
 	return nil
 }
 
-// GetActiveDetections retrieves all active detections for a database.
+// acknowledgedDetectionTTL is how long a detection with no implemented fix is parked. Once it
+// expires the issue may re-fire, adding to the demand for that fix.
+const acknowledgedDetectionTTL = 24 * time.Hour
+
+// MarkDetectionAcknowledged parks a detection whose fix is not implemented yet. It leaves the
+// active set, so it no longer shows as an open problem, but keeps suppressing duplicates until
+// it expires.
+func (c *Client) MarkDetectionAcknowledged(ctx context.Context, id string, reason string) error {
+	detection, err := c.GetDetection(ctx, id)
+	if err != nil {
+		return err
+	}
+	defer c.detections.invalidate(detection.Key)
+
+	detection.State = models.StateAcknowledgedNoAction
+	detection.FailureReason = reason
+	detection.TTL = int(acknowledgedDetectionTTL / time.Second)
+
+	detectionKey := fmt.Sprintf("detection:%s", detection.ID)
+	data, err := json.Marshal(detection)
+	if err != nil {
+		return fmt.Errorf("failed to marshal detection: %w", err)
+	}
+
+	if err := c.rdb.Set(ctx, detectionKey, data, acknowledgedDetectionTTL).Err(); err != nil {
+		return fmt.Errorf("failed to update detection: %w", err)
+	}
+
+	activeKey := fmt.Sprintf("detections:active:%s", detection.DatabaseID)
+	removed, err := c.rdb.SRem(ctx, activeKey, detection.ID).Result()
+	if err != nil {
+		return fmt.Errorf("failed to remove from active set: %w", err)
+	}
+
+	if removed > 0 {
+		return c.adjustDetectionCounters(ctx, statsActiveDetectionsKey, detection, -1)
+	}
+
+	return nil
+}
+
+// UpdateDetectionSeverity records a new severity and value on a detection and refreshes LastSeen.
+func (c *Client) UpdateDetectionSeverity(ctx context.Context, id string, severity string, value float64, now time.Time) error {
+	detection, err := c.GetDetection(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	detection.Severity = severity
+	detection.Value = value
+	detection.LastSeen = now
+
+	data, err := json.Marshal(detection)
+	if err != nil {
+		return fmt.Errorf("failed to marshal detection: %w", err)
+	}
+
+	detectionKey := fmt.Sprintf("detection:%s", detection.ID)
+	if err := c.rdb.Set(ctx, detectionKey, data, 0).Err(); err != nil {
+		return fmt.Errorf("failed to update detection: %w", err)
+	}
+
+	return nil
+}
+
+// RefreshDetectionLastSeen updates LastSeen on the detection registered for key.
+func (c *Client) RefreshDetectionLastSeen(ctx context.Context, key string, now time.Time) error {
+	detectionID, err := c.GetDetectionIDByKey(ctx, key)
+	if err != nil {
+		return err
+	}
+	if detectionID == "" {
+		return fmt.Errorf("no detection registered for key: %s", key)
+	}
+
+	detection, err := c.GetDetection(ctx, detectionID)
+	if err != nil {
+		return err
+	}
+
+	detection.LastSeen = now
+
+	data, err := json.Marshal(detection)
+	if err != nil {
+		return fmt.Errorf("failed to marshal detection: %w", err)
+	}
+
+	detectionKey := fmt.Sprintf("detection:%s", detection.ID)
+	if err := c.rdb.Set(ctx, detectionKey, data, 0).Err(); err != nil {
+		return fmt.Errorf("failed to update detection: %w", err)
+	}
+
+	return nil
+}
+
+// SweepStaleDetections moves active detections whose LastSeen is older than staleAfter
+// to the stale state and out of the active sets, so the issue can re-fire if it returns.
+// Returns the number of detections swept.
+func (c *Client) SweepStaleDetections(ctx context.Context, staleAfter time.Duration, now time.Time) (int, error) {
+	swept := 0
+
+	iter := c.rdb.Scan(ctx, 0, "detections:active:*", 100).Iterator()
+	for iter.Next(ctx) {
+		activeKey := iter.Val()
+
+		detectionIDs, err := c.rdb.SMembers(ctx, activeKey).Result()
+		if err != nil {
+			return swept, fmt.Errorf("failed to get active detections: %w", err)
+		}
+
+		for _, id := range detectionIDs {
+			detection, err := c.GetDetection(ctx, id)
+			if err != nil {
+				continue
+			}
+
+			if now.Sub(detection.LastSeen) <= staleAfter {
+				continue
+			}
+
+			if err := c.markDetectionStale(ctx, activeKey, detection); err != nil {
+				return swept, err
+			}
+			swept++
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		return swept, fmt.Errorf("failed to scan active detections: %w", err)
+	}
+
+	return swept, nil
+}
+
+func (c *Client) markDetectionStale(ctx context.Context, activeKey string, detection *models.Detection) error {
+	defer c.detections.invalidate(detection.Key)
+
+	detection.State = models.StateStale
+	detection.TTL = 300
+
+	data, err := json.Marshal(detection)
+	if err != nil {
+		return fmt.Errorf("failed to marshal detection: %w", err)
+	}
+
+	detectionKey := fmt.Sprintf("detection:%s", detection.ID)
+	if err := c.rdb.Set(ctx, detectionKey, data, time.Duration(detection.TTL)*time.Second).Err(); err != nil {
+		return fmt.Errorf("failed to update detection: %w", err)
+	}
+
+	removed, err := c.rdb.SRem(ctx, activeKey, detection.ID).Result()
+	if err != nil {
+		return fmt.Errorf("failed to remove from active set: %w", err)
+	}
+
+	if removed > 0 {
+		return c.adjustDetectionCounters(ctx, statsActiveDetectionsKey, detection, -1)
+	}
+
+	return nil
+}
+
+// GetActiveDetections retrieves all active detections for a database, fetching their
+// records in batches rather than one round trip each.
 func (c *Client) GetActiveDetections(ctx context.Context, databaseID string) ([]*models.Detection, error) {
 	activeKey := fmt.Sprintf("detections:active:%s", databaseID)
 
@@ -128,13 +523,34 @@ func (c *Client) GetActiveDetections(ctx context.Context, databaseID string) ([]
 		return nil, fmt.Errorf("failed to get active detections: %w", err)
 	}
 
+	records, err := c.getMany(ctx, prefixKeys("detection:", detectionIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active detections: %w", err)
+	}
+
 	detections := make([]*models.Detection, 0, len(detectionIDs))
-	for _, id := range detectionIDs {
-		detection, err := c.GetDetection(ctx, id)
-		if err != nil {
+	var orphaned []interface{}
+	for i, id := range detectionIDs {
+		data, ok := records[i].(string)
+		if !ok {
+			// Left by a write interrupted before detections were registered transactionally
+			log.Printf("Removing active detection %s with no record from %s", id, activeKey)
+			orphaned = append(orphaned, id)
+			continue
+		}
+
+		var detection models.Detection
+		if err := json.Unmarshal([]byte(data), &detection); err != nil {
+			slog.Warn("Skipping unreadable detection", "detection_id", id, "error", err)
 			continue
 		}
-		detections = append(detections, detection)
+		detections = append(detections, &detection)
+	}
+
+	if len(orphaned) > 0 {
+		if err := c.rdb.SRem(ctx, activeKey, orphaned...).Err(); err != nil {
+			slog.Warn("Failed to remove orphaned detections", "active_key", activeKey, "error", err)
+		}
 	}
 
 	return detections, nil
@@ -162,34 +578,64 @@ func (c *Client) CountAllActiveDetections(ctx context.Context) (int32, error) {
 
 // ===== [ACTION OPERATIONS] =====
 
-// RegisterAction stores a new action and adds it to the appropriate sets.
+// RegisterAction stores a new action and adds it to the appropriate sets, in one transaction.
+// Re-registering an existing ID replaces it, so action IDs derived from detections are idempotent.
 func (c *Client) RegisterAction(ctx context.Context, action *models.Action) error {
 	actionKey := fmt.Sprintf("action:%s", action.ID)
 
+	existing, err := c.GetAction(ctx, action.ID)
+	isNew := err != nil
+
 	data, err := json.Marshal(action)
 	if err != nil {
 		return fmt.Errorf("failed to marshal action: %w", err)
 	}
 
-	if err := c.rdb.Set(ctx, actionKey, data, 0).Err(); err != nil {
-		return fmt.Errorf("failed to store action: %w", err)
+	pipe := c.rdb.TxPipeline()
+
+	if !isNew && existing.Status != action.Status {
+		oldStatusKey := fmt.Sprintf("action:status:%s", existing.Status)
+		pipe.SRem(ctx, oldStatusKey, action.ID)
 	}
 
-	dbActionsKey := fmt.Sprintf("actions:database:%s", action.DatabaseID)
-	if err := c.rdb.SAdd(ctx, dbActionsKey, action.ID).Err(); err != nil {
-		return fmt.Errorf("failed to add to database set: %w", err)
+	pipe.Set(ctx, actionKey, data, 0)
+	pipe.SAdd(ctx, fmt.Sprintf("actions:database:%s", action.DatabaseID), action.ID)
+	pipe.SAdd(ctx, fmt.Sprintf("action:status:%s", action.Status), action.ID)
+
+	if isNew {
+		pipe.Incr(ctx, statsTotalActionsKey)
 	}
 
-	statusKey := fmt.Sprintf("action:status:%s", action.Status)
-	if err := c.rdb.SAdd(ctx, statusKey, action.ID).Err(); err != nil {
-		return fmt.Errorf("failed to add to status set: %w", err)
+	if isNew || existing.Status != action.Status {
+		entry := &models.ActionHistoryEntry{
+			Status:    action.Status,
+			Message:   action.Message,
+			Source:    action.Source,
+			Timestamp: time.Now(),
+		}
+		if err := queueActionHistory(ctx, pipe, action.ID, entry); err != nil {
+			return err
+		}
 	}
 
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to store action: %w", err)
+	}
+
+	if isNew {
+		c.updateActionGauges(ctx, action.Status)
+	} else {
+		c.updateActionGauges(ctx, existing.Status, action.Status)
+	}
 	return nil
 }
 
 // UpdateActionStatus updates the status of an action and moves it between status sets.
-func (c *Client) UpdateActionStatus(ctx context.Context, actionID string, status models.ActionStatus, message string, errorMsg string) error {
+// Status changes are appended to the action's history, attributed to source or, when
+// empty, to whatever triggered the action. An error and its code replace the previous
+// ones; empty ones keep them. Attempts and rollbacks are also counted towards the action
+// type's stats.
+func (c *Client) UpdateActionStatus(ctx context.Context, actionID string, status models.ActionStatus, message string, errorMsg string, errorCode string, source string) error {
 	action, err := c.GetAction(ctx, actionID)
 	if err != nil {
 		return fmt.Errorf("failed to get action for update: %w", err)
@@ -200,18 +646,25 @@ func (c *Client) UpdateActionStatus(ctx context.Context, actionID string, status
 		return fmt.Errorf("failed to remove from old status set: %w", err)
 	}
 
+	previousStatus := action.Status
 	action.Status = status
 	action.Message = message
 
 	if errorMsg != "" {
 		action.Error = errorMsg
 	}
+	if errorCode != "" {
+		action.ErrorCode = errorCode
+	}
 
 	now := time.Now()
 	switch status {
 	case models.StatusExecuting:
-		action.StartedAt = &now
-	case models.StatusCompleted, models.StatusFailed:
+		// Progress updates re-send executing - keep the original start time
+		if previousStatus != models.StatusExecuting || action.StartedAt == nil {
+			action.StartedAt = &now
+		}
+	case models.StatusCompleted, models.StatusFailed, models.StatusPendingImplementation, models.StatusRolledBack:
 		action.CompletedAt = &now
 	}
 
@@ -229,6 +682,121 @@ func (c *Client) UpdateActionStatus(ctx context.Context, actionID string, status
 	if err := c.rdb.SAdd(ctx, newStatusKey, actionID).Err(); err != nil {
 		return fmt.Errorf("failed to add to new status set: %w", err)
 	}
+	c.updateActionGauges(ctx, previousStatus, status)
+
+	// Progress updates re-send the same status; only transitions belong in the timeline
+	if status != previousStatus {
+		if source == "" {
+			source = action.Source
+		}
+		entry := &models.ActionHistoryEntry{
+			Status:    status,
+			Message:   message,
+			Error:     errorMsg,
+			ErrorCode: errorCode,
+			Source:    source,
+			Timestamp: now,
+		}
+		if err := c.appendActionHistory(ctx, actionID, entry); err != nil {
+			return err
+		}
+	}
+
+	// Count each action once, when it first reaches a terminal state
+	wasTerminal := previousStatus == models.StatusCompleted || previousStatus == models.StatusFailed
+	if (status == models.StatusCompleted || status == models.StatusFailed) && !wasTerminal {
+		if err := c.recordActionExecuted(ctx, now); err != nil {
+			return err
+		}
+	}
+
+	return c.recordActionStats(ctx, action, actionStatsDelta(action, previousStatus, now), now)
+}
+
+// SetActionProgress records the latest progress of a long-running action.
+func (c *Client) SetActionProgress(ctx context.Context, actionID string, progress *models.ActionProgress) error {
+	action, err := c.GetAction(ctx, actionID)
+	if err != nil {
+		return fmt.Errorf("failed to get action for progress update: %w", err)
+	}
+
+	action.Progress = progress
+
+	data, err := json.Marshal(action)
+	if err != nil {
+		return fmt.Errorf("failed to marshal action: %w", err)
+	}
+
+	actionKey := fmt.Sprintf("action:%s", action.ID)
+	if err := c.rdb.Set(ctx, actionKey, data, 0).Err(); err != nil {
+		return fmt.Errorf("failed to update action progress: %w", err)
+	}
+
+	return nil
+}
+
+// SetActionOutcome records how the metric behind an action's detection moved after it ran,
+// counting the verdict towards the action type's stats.
+func (c *Client) SetActionOutcome(ctx context.Context, actionID string, outcome *models.ActionOutcome) error {
+	action, err := c.GetAction(ctx, actionID)
+	if err != nil {
+		return fmt.Errorf("failed to get action for outcome update: %w", err)
+	}
+
+	// Only the first verdict counts, so a re-sent outcome is not counted twice
+	firstVerdict := action.Outcome == nil
+	action.Outcome = outcome
+
+	data, err := json.Marshal(action)
+	if err != nil {
+		return fmt.Errorf("failed to marshal action: %w", err)
+	}
+
+	actionKey := fmt.Sprintf("action:%s", action.ID)
+	if err := c.rdb.Set(ctx, actionKey, data, 0).Err(); err != nil {
+		return fmt.Errorf("failed to update action outcome: %w", err)
+	}
+
+	if !firstVerdict {
+		return nil
+	}
+	switch outcome.Verdict {
+	case models.VerdictEffective:
+		return c.recordActionStats(ctx, action, map[string]int64{statVerifiedEffective: 1}, outcome.RecordedAt)
+	case models.VerdictIneffective:
+		return c.recordActionStats(ctx, action, map[string]int64{statVerifiedIneffective: 1}, outcome.RecordedAt)
+	}
+
+	return nil
+}
+
+// SetActionResult records the changes an action made and whether it can be rolled back.
+// Changes larger than models.MaxActionResultBytes are truncated.
+func (c *Client) SetActionResult(ctx context.Context, actionID string, changes string, canRollback bool) error {
+	action, err := c.GetAction(ctx, actionID)
+	if err != nil {
+		return fmt.Errorf("failed to get action for result update: %w", err)
+	}
+
+	result, rollbackKept := models.TruncateResult(changes, models.MaxActionResultBytes)
+	action.Result = result
+	if len(action.Result) < len(changes) {
+		log.Printf("Action %s result truncated from %d to %d bytes", actionID, len(changes), len(action.Result))
+	}
+	if canRollback && !rollbackKept {
+		slog.Warn("Action result too large to keep its rollback state, rollback disabled", "action_id", actionID)
+	}
+	action.CanRollback = canRollback && rollbackKept
+
+	data, err := json.Marshal(action)
+	if err != nil {
+		return fmt.Errorf("failed to marshal action: %w", err)
+	}
+
+	actionKey := fmt.Sprintf("action:%s", actionID)
+	if err := c.rdb.Set(ctx, actionKey, data, 0).Err(); err != nil {
+		return fmt.Errorf("failed to update action result: %w", err)
+	}
 
 	return nil
 }
@@ -250,7 +818,7 @@ func (c *Client) GetAction(ctx context.Context, id string) (*models.Action, erro
 	return &action, nil
 }
 
-// GetPendingActions retrieves all queued or executing actions for a database.
+// GetPendingActions retrieves all queued, scheduled or executing actions for a database.
 func (c *Client) GetPendingActions(ctx context.Context, databaseID string) ([]*models.Action, error) {
 	dbActionsKey := fmt.Sprintf("actions:database:%s", databaseID)
 
@@ -259,14 +827,15 @@ func (c *Client) GetPendingActions(ctx context.Context, databaseID string) ([]*m
 		return nil, fmt.Errorf("failed to get actions for %s: %w", databaseID, err)
 	}
 
-	actions := make([]*models.Action, 0)
-	for _, id := range actionIDs {
-		action, err := c.GetAction(ctx, id)
-		if err != nil {
-			continue
-		}
+	all, err := c.getActions(ctx, actionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get actions for %s: %w", databaseID, err)
+	}
 
-		if action.Status == models.StatusQueued || action.Status == models.StatusExecuting {
+	actions := make([]*models.Action, 0)
+	for _, action := range all {
+		switch action.Status {
+		case models.StatusQueued, models.StatusScheduled, models.StatusExecuting:
 			actions = append(actions, action)
 		}
 	}
@@ -283,18 +852,83 @@ func (c *Client) GetActionByStatus(ctx context.Context, status models.ActionStat
 		return nil, fmt.Errorf("failed to get actions by status: %w", err)
 	}
 
-	actions := make([]*models.Action, 0, len(actionIDs))
-	for _, id := range actionIDs {
-		action, err := c.GetAction(ctx, id)
-		if err != nil {
+	actions, err := c.getActions(ctx, actionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get actions by status: %w", err)
+	}
+
+	return actions, nil
+}
+
+// getActions fetches the actions with the given IDs in batches, skipping missing and
+// unreadable records.
+func (c *Client) getActions(ctx context.Context, ids []string) ([]*models.Action, error) {
+	records, err := c.getMany(ctx, prefixKeys("action:", ids))
+	if err != nil {
+		return nil, err
+	}
+
+	actions := make([]*models.Action, 0, len(ids))
+	for _, record := range records {
+		data, ok := record.(string)
+		if !ok {
 			continue
 		}
-		actions = append(actions, action)
+
+		var action models.Action
+		if err := json.Unmarshal([]byte(data), &action); err != nil {
+			continue
+		}
+		actions = append(actions, &action)
 	}
 
 	return actions, nil
 }
 
+// GetPendingImplementationDemand groups pending_implementation actions by action type, most
+// requested first, to show which unimplemented fixes are needed most.
+func (c *Client) GetPendingImplementationDemand(ctx context.Context) ([]*models.PendingImplementationDemand, error) {
+	actions, err := c.GetActionByStatus(ctx, models.StatusPendingImplementation)
+	if err != nil {
+		return nil, err
+	}
+
+	byType := make(map[string]*models.PendingImplementationDemand)
+	databases := make(map[string]map[string]bool)
+	for _, action := range actions {
+		demand, ok := byType[action.ActionType]
+		if !ok {
+			demand = &models.PendingImplementationDemand{ActionType: action.ActionType}
+			byType[action.ActionType] = demand
+			databases[action.ActionType] = make(map[string]bool)
+		}
+
+		demand.Count++
+		if !databases[action.ActionType][action.DatabaseID] {
+			databases[action.ActionType][action.DatabaseID] = true
+			demand.DatabaseIDs = append(demand.DatabaseIDs, action.DatabaseID)
+		}
+		if action.CreatedAt.After(demand.LastRequestedAt) {
+			demand.LastRequestedAt = action.CreatedAt
+		}
+	}
+
+	summary := make([]*models.PendingImplementationDemand, 0, len(byType))
+	for _, demand := range byType {
+		sort.Strings(demand.DatabaseIDs)
+		summary = append(summary, demand)
+	}
+
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].Count != summary[j].Count {
+			return summary[i].Count > summary[j].Count
+		}
+		return summary[i].ActionType < summary[j].ActionType
+	})
+
+	return summary, nil
+}
+
 // CountActionsByStatus counts actions with a specific status.
 func (c *Client) CountActionsByStatus(ctx context.Context, status models.ActionStatus) (int32, error) {
 	statusKey := fmt.Sprintf("action:status:%s", status)
@@ -327,23 +961,109 @@ func (c *Client) CountAllActions(ctx context.Context) (int32, error) {
 
 // ===== [DATABASE OPERATIONS] =====
 
+// storedDatabase is the Redis representation of a database. With encryption enabled the
+// connection string is kept only as ciphertext, tagged with the ID of the key that sealed it.
+type storedDatabase struct {
+	models.Database
+	EncryptedConnectionString string `json:"encrypted_connection_string,omitempty"`
+	EncryptionKeyID           string `json:"encryption_key_id,omitempty"`
+}
+
+// encodeDatabase marshals a database for storage, encrypting its connection string when
+// a cipher is configured.
+func (c *Client) encodeDatabase(database *models.Database) ([]byte, error) {
+	stored := storedDatabase{Database: *database}
+
+	if c.connCipher != nil && database.ConnectionString != "" {
+		keyID, ciphertext, err := c.connCipher.Encrypt(database.ConnectionString, database.ID)
+		if err != nil {
+			return nil, err
+		}
+		stored.ConnectionString = ""
+		stored.EncryptedConnectionString = ciphertext
+		stored.EncryptionKeyID = keyID
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal database: %w", err)
+	}
+	return data, nil
+}
+
+// decodeDatabase unmarshals a stored database and decrypts its connection string. needsRewrite
+// reports a record still in plaintext or sealed with a rotated-out key.
+func (c *Client) decodeDatabase(data string) (database *models.Database, needsRewrite bool, err error) {
+	var stored storedDatabase
+	if err := json.Unmarshal([]byte(data), &stored); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal database: %w", err)
+	}
+	database = &stored.Database
+
+	if stored.EncryptedConnectionString == "" {
+		return database, c.connCipher != nil && database.ConnectionString != "", nil
+	}
+
+	if c.connCipher == nil {
+		return nil, false, fmt.Errorf("connection string for %s is encrypted but no encryption key is configured", database.ID)
+	}
+
+	plaintext, err := c.connCipher.Decrypt(stored.EncryptionKeyID, stored.EncryptedConnectionString, database.ID)
+	if err != nil {
+		return nil, false, fmt.Errorf("database %s: %w", database.ID, err)
+	}
+	database.ConnectionString = plaintext
+
+	return database, stored.EncryptionKeyID != c.connCipher.ActiveKeyID(), nil
+}
+
+// rewriteDatabase re-stores a record read in plaintext or under an old key so it is sealed
+// with the active key. Failures are logged - the caller already has the decrypted record.
+func (c *Client) rewriteDatabase(ctx context.Context, database *models.Database) {
+	data, err := c.encodeDatabase(database)
+	if err == nil {
+		err = c.rdb.Set(ctx, fmt.Sprintf("database:%s", database.ID), data, 0).Err()
+	}
+	if err != nil {
+		slog.Warn("Failed to re-encrypt connection string", "database_id", database.ID, "error", err)
+		return
+	}
+	log.Printf("Re-encrypted connection string for %s with key %s", database.ID, c.connCipher.ActiveKeyID())
+}
+
 // RegisterDatabase stores database connection info in Redis.
 func (c *Client) RegisterDatabase(ctx context.Context, database *models.Database) error {
 	databaseKey := fmt.Sprintf("database:%s", database.ID)
 
-	data, err := json.Marshal(database)
+	existingType := ""
+	if existing, err := c.GetDatabase(ctx, database.ID); err == nil {
+		existingType = existing.DatabaseType
+	}
+
+	data, err := c.encodeDatabase(database)
 	if err != nil {
-		return fmt.Errorf("failed to marshal database: %w", err)
+		return err
 	}
 
 	if err := c.rdb.Set(ctx, databaseKey, data, 0).Err(); err != nil {
 		return fmt.Errorf("failed to store database: %w", err)
 	}
 
-	if err := c.rdb.SAdd(ctx, "databases:all", database.ID).Err(); err != nil {
+	if err := c.rdb.ZAdd(ctx, allDatabasesKey, databaseIndexEntry(database.ID)).Err(); err != nil {
 		return fmt.Errorf("failed to add to database list: %w", err)
 	}
 
+	if existingType != "" && existingType != database.DatabaseType {
+		if err := c.rdb.ZRem(ctx, databaseTypeKey(existingType), database.ID).Err(); err != nil {
+			return fmt.Errorf("failed to remove from old type list: %w", err)
+		}
+	}
+
+	if err := c.rdb.ZAdd(ctx, databaseTypeKey(database.DatabaseType), databaseIndexEntry(database.ID)).Err(); err != nil {
+		return fmt.Errorf("failed to add to type list: %w", err)
+	}
+
+	c.updateDatabasesGauge(ctx)
 	return nil
 }
 
@@ -356,31 +1076,138 @@ func (c *Client) GetDatabase(ctx context.Context, id string) (*models.Database,
 		return nil, fmt.Errorf("failed to get database: %w", err)
 	}
 
-	var database models.Database
-	if err := json.Unmarshal([]byte(data), &database); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal database: %w", err)
+	database, needsRewrite, err := c.decodeDatabase(data)
+	if err != nil {
+		return nil, err
 	}
 
-	return &database, nil
+	if needsRewrite {
+		c.rewriteDatabase(ctx, database)
+	}
+
+	return database, nil
+}
+
+// Database IDs are indexed in sorted sets with every score 0, so members are ordered by
+// ID and a page is read with ZRANGEBYLEX rather than loading and sorting the whole index.
+const allDatabasesKey = "databases:all"
+
+// databasePageBatch bounds how many database records are fetched per MGET.
+const databasePageBatch = 100
+
+func databaseTypeKey(databaseType string) string {
+	return fmt.Sprintf("databases:type:%s", databaseType)
+}
+
+func databaseIndexEntry(id string) redis.Z {
+	return redis.Z{Score: 0, Member: id}
 }
 
 // ListDatabases returns all registered databases.
 func (c *Client) ListDatabases(ctx context.Context) ([]*models.Database, error) {
-	databaseIDs, err := c.rdb.SMembers(ctx, "databases:all").Result()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get database list: %w", err)
+	databases, _, err := c.listDatabasesPage(ctx, allDatabasesKey, "", 0, false)
+	return databases, err
+}
+
+// ListDatabasesPage returns up to limit registered databases ordered by ID, starting
+// after cursor. It returns the cursor for the next page, or "" when none remain.
+// A limit of 0 returns all databases.
+func (c *Client) ListDatabasesPage(ctx context.Context, cursor string, limit int, enabledOnly bool) ([]*models.Database, string, error) {
+	return c.listDatabasesPage(ctx, allDatabasesKey, cursor, limit, enabledOnly)
+}
+
+// GetDatabasesByType returns a page of registered databases of the given type.
+func (c *Client) GetDatabasesByType(ctx context.Context, databaseType, cursor string, limit int, enabledOnly bool) ([]*models.Database, string, error) {
+	return c.listDatabasesPage(ctx, databaseTypeKey(databaseType), cursor, limit, enabledOnly)
+}
+
+// listDatabasesPage walks the IDs in indexKey in order, reading them a batch at a time
+// with ZRANGEBYLEX and fetching each batch's records with one MGET.
+func (c *Client) listDatabasesPage(ctx context.Context, indexKey, cursor string, limit int, enabledOnly bool) ([]*models.Database, string, error) {
+	batch := databasePageBatch
+	if limit > 0 && limit < batch {
+		batch = limit
+	}
+
+	from := "-"
+	if cursor != "" {
+		from = "(" + cursor
 	}
 
-	databases := make([]*models.Database, 0, len(databaseIDs))
-	for _, id := range databaseIDs {
-		database, err := c.GetDatabase(ctx, id)
+	databases := make([]*models.Database, 0)
+	for {
+		databaseIDs, err := c.rdb.ZRangeByLex(ctx, indexKey, &redis.ZRangeBy{Min: from, Max: "+", Count: int64(batch)}).Result()
 		if err != nil {
-			continue
+			return nil, "", fmt.Errorf("failed to get database list: %w", err)
+		}
+		if len(databaseIDs) == 0 {
+			return databases, "", nil
 		}
-		databases = append(databases, database)
+
+		keys := make([]string, 0, len(databaseIDs))
+		for _, id := range databaseIDs {
+			keys = append(keys, fmt.Sprintf("database:%s", id))
+		}
+
+		values, err := c.rdb.MGet(ctx, keys...).Result()
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get databases: %w", err)
+		}
+
+		for j, value := range values {
+			data, ok := value.(string)
+			if !ok {
+				continue
+			}
+
+			database, needsRewrite, err := c.decodeDatabase(data)
+			if err != nil {
+				slog.Warn("Skipping unreadable database record", "key", keys[j], "error", err)
+				continue
+			}
+
+			if needsRewrite {
+				c.rewriteDatabase(ctx, database)
+			}
+
+			if enabledOnly && !database.Enabled {
+				continue
+			}
+
+			databases = append(databases, database)
+
+			if limit > 0 && len(databases) == limit {
+				next, err := c.nextDatabaseCursor(ctx, indexKey, databaseIDs, j)
+				if err != nil {
+					return nil, "", err
+				}
+				return databases, next, nil
+			}
+		}
+
+		if len(databaseIDs) < batch {
+			return databases, "", nil
+		}
+		from = "(" + databaseIDs[len(databaseIDs)-1]
+	}
+}
+
+// nextDatabaseCursor returns the cursor after databaseIDs[last], or "" when no IDs follow
+// it in indexKey.
+func (c *Client) nextDatabaseCursor(ctx context.Context, indexKey string, databaseIDs []string, last int) (string, error) {
+	id := databaseIDs[last]
+	if last+1 < len(databaseIDs) {
+		return id, nil
 	}
 
-	return databases, nil
+	remaining, err := c.rdb.ZLexCount(ctx, indexKey, "("+id, "+").Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to get database list: %w", err)
+	}
+	if remaining == 0 {
+		return "", nil
+	}
+	return id, nil
 }
 
 // UpdateDatabaseHealth updates health status and last seen timestamp.
@@ -395,9 +1222,9 @@ func (c *Client) UpdateDatabaseHealth(ctx context.Context, id string, lastSeen i
 	database.HealthScore = healthScore
 
 	databaseKey := fmt.Sprintf("database:%s", id)
-	data, err := json.Marshal(database)
+	data, err := c.encodeDatabase(database)
 	if err != nil {
-		return fmt.Errorf("failed to marshal database: %w", err)
+		return err
 	}
 
 	if err := c.rdb.Set(ctx, databaseKey, data, 0).Err(); err != nil {
@@ -407,18 +1234,92 @@ func (c *Client) UpdateDatabaseHealth(ctx context.Context, id string, lastSeen i
 	return nil
 }
 
+// BackfillDatabaseIndexes converts database indexes kept as plain sets by earlier
+// versions into sorted sets, and adds databases registered before the per-type index
+// existed to their type index. It is safe to run on every startup.
+func (c *Client) BackfillDatabaseIndexes(ctx context.Context) error {
+	if err := c.convertDatabaseIndex(ctx, allDatabasesKey); err != nil {
+		return err
+	}
+
+	databases, err := c.ListDatabases(ctx)
+	if err != nil {
+		return err
+	}
+
+	converted := make(map[string]bool)
+	for _, database := range databases {
+		typeKey := databaseTypeKey(database.DatabaseType)
+		if !converted[typeKey] {
+			if err := c.convertDatabaseIndex(ctx, typeKey); err != nil {
+				return err
+			}
+			converted[typeKey] = true
+		}
+
+		if err := c.rdb.ZAdd(ctx, typeKey, databaseIndexEntry(database.ID)).Err(); err != nil {
+			return fmt.Errorf("failed to index database %s: %w", database.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// convertDatabaseIndex rewrites indexKey as a sorted set if it is still a plain set. The
+// old set is replaced in one transaction so the index is never seen empty.
+func (c *Client) convertDatabaseIndex(ctx context.Context, indexKey string) error {
+	keyType, err := c.rdb.Type(ctx, indexKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check %s: %w", indexKey, err)
+	}
+	if keyType != "set" {
+		return nil
+	}
+
+	ids, err := c.rdb.SMembers(ctx, indexKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", indexKey, err)
+	}
+
+	entries := make([]redis.Z, 0, len(ids))
+	for _, id := range ids {
+		entries = append(entries, databaseIndexEntry(id))
+	}
+
+	_, err = c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, indexKey)
+		if len(entries) > 0 {
+			pipe.ZAdd(ctx, indexKey, entries...)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to convert %s: %w", indexKey, err)
+	}
+
+	log.Printf("Converted %s to a sorted set (%d databases)", indexKey, len(ids))
+	return nil
+}
+
 // UnregisterDatabase removes a database from Redis.
 func (c *Client) UnregisterDatabase(ctx context.Context, id string) error {
 	databaseKey := fmt.Sprintf("database:%s", id)
 
+	if existing, err := c.GetDatabase(ctx, id); err == nil {
+		if err := c.rdb.ZRem(ctx, databaseTypeKey(existing.DatabaseType), id).Err(); err != nil {
+			return fmt.Errorf("failed to remove from type list: %w", err)
+		}
+	}
+
 	if err := c.rdb.Del(ctx, databaseKey).Err(); err != nil {
 		return fmt.Errorf("failed to delete database: %w", err)
 	}
 
-	if err := c.rdb.SRem(ctx, "databases:all", id).Err(); err != nil {
+	if err := c.rdb.ZRem(ctx, allDatabasesKey, id).Err(); err != nil {
 		return fmt.Errorf("failed to remove from database list: %w", err)
 	}
 
+	c.updateDatabasesGauge(ctx)
 	return nil
 }
 
@@ -0,0 +1,75 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// Database policies live in one hash keyed by database ID, so the Dashboard can list them
+// in a single call.
+const policiesKey = "policies"
+
+// SetDatabasePolicy replaces a database's policy. A policy that restricts nothing removes it.
+func (c *Client) SetDatabasePolicy(ctx context.Context, policy *models.DatabasePolicy) error {
+	if policy.IsEmpty() {
+		if err := c.rdb.HDel(ctx, policiesKey, policy.DatabaseID).Err(); err != nil {
+			return fmt.Errorf("failed to clear policy: %w", err)
+		}
+		return nil
+	}
+
+	policy.UpdatedAt = time.Now()
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return fmt.Errorf("failed to marshal policy: %w", err)
+	}
+
+	if err := c.rdb.HSet(ctx, policiesKey, policy.DatabaseID, data).Err(); err != nil {
+		return fmt.Errorf("failed to store policy: %w", err)
+	}
+
+	return nil
+}
+
+// GetDatabasePolicy returns a database's policy, or nil if it has none.
+func (c *Client) GetDatabasePolicy(ctx context.Context, databaseID string) (*models.DatabasePolicy, error) {
+	data, err := c.rdb.HGet(ctx, policiesKey, databaseID).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get policy: %w", err)
+	}
+
+	var policy models.DatabasePolicy
+	if err := json.Unmarshal([]byte(data), &policy); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal policy: %w", err)
+	}
+
+	return &policy, nil
+}
+
+// ListDatabasePolicies returns every stored policy.
+func (c *Client) ListDatabasePolicies(ctx context.Context) ([]*models.DatabasePolicy, error) {
+	all, err := c.rdb.HGetAll(ctx, policiesKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list policies: %w", err)
+	}
+
+	policies := make([]*models.DatabasePolicy, 0, len(all))
+	for _, data := range all {
+		var policy models.DatabasePolicy
+		if err := json.Unmarshal([]byte(data), &policy); err != nil {
+			continue
+		}
+		policies = append(policies, &policy)
+	}
+
+	return policies, nil
+}
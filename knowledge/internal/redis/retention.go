@@ -0,0 +1,306 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// statsCleanupKey holds running totals of what retention cleanup has purged.
+const statsCleanupKey = "stats:cleanup"
+
+// retainedStatuses are the terminal action statuses subject to ActionMaxAge.
+var retainedStatuses = []models.ActionStatus{
+	models.StatusCompleted,
+	models.StatusFailed,
+	models.StatusRolledBack,
+}
+
+// retainedStates are the closed detection states subject to DetectionMaxAge. Most already
+// carry a TTL; records written without one are removed here.
+var retainedStates = map[models.DetectionState]bool{
+	models.StateResolved:   true,
+	models.StateStale:      true,
+	models.StateSuperseded: true,
+}
+
+// RetentionPolicy says how long finished actions and closed detections are kept.
+type RetentionPolicy struct {
+	ActionMaxAge    time.Duration // completed, failed and rolled back actions, from completion
+	DetectionMaxAge time.Duration // resolved, stale and superseded detections, from last seen
+}
+
+// CleanupResult counts what one cleanup removed.
+type CleanupResult struct {
+	ActionsPurged    int
+	DetectionsPurged int
+	DanglingRemoved  int // index entries pointing at records that no longer exist
+}
+
+// CleanupTotals are the running totals across every cleanup.
+type CleanupTotals struct {
+	ActionsPurged    int64
+	DetectionsPurged int64
+	DanglingRemoved  int64
+	LastCleanupAt    time.Time
+}
+
+// Cleanup purges actions and detections older than policy allows, then removes entries
+// left in index sets and key mappings whose records are gone.
+func (c *Client) Cleanup(ctx context.Context, policy RetentionPolicy, now time.Time) (*CleanupResult, error) {
+	result := &CleanupResult{}
+
+	if err := c.purgeActions(ctx, policy.ActionMaxAge, now, result); err != nil {
+		return result, err
+	}
+
+	if err := c.purgeDetections(ctx, policy.DetectionMaxAge, now, result); err != nil {
+		return result, err
+	}
+
+	if err := c.pruneDanglingEntries(ctx, result); err != nil {
+		return result, err
+	}
+	c.RefreshGauges(ctx)
+
+	pipe := c.rdb.TxPipeline()
+	pipe.HIncrBy(ctx, statsCleanupKey, "actions_purged", int64(result.ActionsPurged))
+	pipe.HIncrBy(ctx, statsCleanupKey, "detections_purged", int64(result.DetectionsPurged))
+	pipe.HIncrBy(ctx, statsCleanupKey, "dangling_removed", int64(result.DanglingRemoved))
+	pipe.HSet(ctx, statsCleanupKey, "last_cleanup_at", now.Unix())
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return result, fmt.Errorf("failed to record cleanup totals: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetCleanupTotals returns what retention cleanup has purged so far.
+func (c *Client) GetCleanupTotals(ctx context.Context) (*CleanupTotals, error) {
+	fields, err := c.rdb.HGetAll(ctx, statsCleanupKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cleanup totals: %w", err)
+	}
+
+	field := func(name string) int64 {
+		n, _ := strconv.ParseInt(fields[name], 10, 64)
+		return n
+	}
+
+	totals := &CleanupTotals{
+		ActionsPurged:    field("actions_purged"),
+		DetectionsPurged: field("detections_purged"),
+		DanglingRemoved:  field("dangling_removed"),
+	}
+	if at := field("last_cleanup_at"); at > 0 {
+		totals.LastCleanupAt = time.Unix(at, 0)
+	}
+
+	return totals, nil
+}
+
+func (c *Client) purgeActions(ctx context.Context, maxAge time.Duration, now time.Time, result *CleanupResult) error {
+	for _, status := range retainedStatuses {
+		statusKey := fmt.Sprintf("action:status:%s", status)
+
+		actionIDs, err := c.rdb.SMembers(ctx, statusKey).Result()
+		if err != nil {
+			return fmt.Errorf("failed to get actions by status: %w", err)
+		}
+
+		for _, id := range actionIDs {
+			action, err := c.GetAction(ctx, id)
+			if err != nil {
+				// Missing records are pruned with the other dangling entries
+				continue
+			}
+
+			finishedAt := action.CreatedAt
+			if action.CompletedAt != nil {
+				finishedAt = *action.CompletedAt
+			}
+			if now.Sub(finishedAt) <= maxAge {
+				continue
+			}
+
+			pipe := c.rdb.TxPipeline()
+			pipe.Del(ctx, fmt.Sprintf("action:%s", id), actionHistoryKey(id))
+			pipe.SRem(ctx, statusKey, id)
+			pipe.SRem(ctx, fmt.Sprintf("actions:database:%s", action.DatabaseID), id)
+			pipe.Decr(ctx, statsTotalActionsKey)
+
+			if _, err := pipe.Exec(ctx); err != nil {
+				return fmt.Errorf("failed to purge action %s: %w", id, err)
+			}
+			result.ActionsPurged++
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) purgeDetections(ctx context.Context, maxAge time.Duration, now time.Time, result *CleanupResult) error {
+	iter := c.rdb.Scan(ctx, 0, "detection:*", 100).Iterator()
+	for iter.Next(ctx) {
+		detectionKey := iter.Val()
+
+		data, err := c.rdb.Get(ctx, detectionKey).Result()
+		if err != nil {
+			// Expired since the scan
+			continue
+		}
+
+		var detection models.Detection
+		if err := json.Unmarshal([]byte(data), &detection); err != nil {
+			continue
+		}
+
+		if !retainedStates[detection.State] || now.Sub(detection.LastSeen) <= maxAge {
+			continue
+		}
+
+		pipe := c.rdb.TxPipeline()
+		pipe.Del(ctx, detectionKey)
+		pipe.SRem(ctx, fmt.Sprintf("detections:active:%s", detection.DatabaseID), detection.ID)
+
+		_, err = pipe.Exec(ctx)
+		c.detections.invalidate(detection.Key)
+		if err != nil {
+			return fmt.Errorf("failed to purge detection %s: %w", detection.ID, err)
+		}
+		result.DetectionsPurged++
+	}
+
+	if err := iter.Err(); err != nil {
+		return fmt.Errorf("failed to scan detections: %w", err)
+	}
+
+	return nil
+}
+
+// pruneDanglingEntries removes set members and key mappings whose record no longer exists,
+// e.g. detections that expired through their TTL.
+func (c *Client) pruneDanglingEntries(ctx context.Context, result *CleanupResult) error {
+	indexSets := []struct {
+		pattern      string
+		recordPrefix string
+	}{
+		{"actions:database:*", "action:"},
+		{"action:status:*", "action:"},
+		{"detections:active:*", "detection:"},
+	}
+
+	for _, index := range indexSets {
+		iter := c.rdb.Scan(ctx, 0, index.pattern, 100).Iterator()
+		for iter.Next(ctx) {
+			setKey := iter.Val()
+
+			members, err := c.rdb.SMembers(ctx, setKey).Result()
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", setKey, err)
+			}
+
+			for _, id := range members {
+				pruned, err := c.pruneIfMissing(ctx, index.recordPrefix+id, func(pipe redis.Pipeliner) {
+					pipe.SRem(ctx, setKey, id)
+				})
+				if err != nil {
+					return fmt.Errorf("failed to prune %s from %s: %w", id, setKey, err)
+				}
+				if pruned {
+					result.DanglingRemoved++
+				}
+			}
+		}
+
+		if err := iter.Err(); err != nil {
+			return fmt.Errorf("failed to scan %s: %w", index.pattern, err)
+		}
+	}
+
+	// Key mappings outlive detections that expired through their TTL
+	iter := c.rdb.Scan(ctx, 0, "detection_key:*", 100).Iterator()
+	for iter.Next(ctx) {
+		mappingKey := iter.Val()
+
+		pruned, err := c.pruneMappingIfMissing(ctx, mappingKey)
+		if err != nil {
+			return fmt.Errorf("failed to prune %s: %w", mappingKey, err)
+		}
+		if pruned {
+			result.DanglingRemoved++
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		return fmt.Errorf("failed to scan detection key mappings: %w", err)
+	}
+
+	return nil
+}
+
+// pruneIfMissing runs prune in a transaction if recordKey does not exist. recordKey is
+// watched, so a record written meanwhile is left alone.
+func (c *Client) pruneIfMissing(ctx context.Context, recordKey string, prune func(redis.Pipeliner)) (bool, error) {
+	pruned := false
+
+	err := c.rdb.Watch(ctx, func(tx *redis.Tx) error {
+		exists, err := tx.Exists(ctx, recordKey).Result()
+		if err != nil || exists > 0 {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			prune(pipe)
+			return nil
+		})
+		pruned = err == nil
+		return err
+	}, recordKey)
+
+	if errors.Is(err, redis.TxFailedErr) {
+		return false, nil
+	}
+	return pruned, err
+}
+
+// pruneMappingIfMissing deletes a detection key mapping whose detection no longer exists.
+// The mapping is watched: registering a detection rewrites it, in the same transaction as
+// the new record.
+func (c *Client) pruneMappingIfMissing(ctx context.Context, mappingKey string) (bool, error) {
+	pruned := false
+
+	err := c.rdb.Watch(ctx, func(tx *redis.Tx) error {
+		id, err := tx.Get(ctx, mappingKey).Result()
+		if errors.Is(err, redis.Nil) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		exists, err := tx.Exists(ctx, "detection:"+id).Result()
+		if err != nil || exists > 0 {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, mappingKey)
+			return nil
+		})
+		pruned = err == nil
+		return err
+	}, mappingKey)
+
+	if errors.Is(err, redis.TxFailedErr) {
+		return false, nil
+	}
+	return pruned, err
+}
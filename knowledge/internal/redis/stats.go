@@ -0,0 +1,191 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/models"
+)
+
+// Counters are maintained incrementally as detections and actions change state,
+// so GetSystemStats reads a handful of keys instead of scanning every record.
+const (
+	statsActiveDetectionsKey   = "stats:detections:active"
+	statsResolvedDetectionsKey = "stats:detections:resolved"
+	statsTotalActionsKey       = "stats:actions:total"
+	statsExecutedBucketPrefix  = "stats:actions:executed:"
+
+	statsTotalField = "total"
+
+	// executedWindow is the rolling window for "actions executed" and is tracked in hourly buckets
+	executedWindow = 24 * time.Hour
+)
+
+// StatsCounters holds the incrementally maintained system counters.
+type StatsCounters struct {
+	ActiveDetections       int32
+	ResolvedDetections     int32
+	ActiveByDatabase       map[string]int32
+	ActiveByCategory       map[string]int32
+	ResolvedByDatabase     map[string]int32
+	ResolvedByCategory     map[string]int32
+	TotalActions           int32
+	ActionsExecutedLastDay int32
+}
+
+// adjustDetectionCounters adds delta to the total, per-database and per-category fields of a counter hash.
+func (c *Client) adjustDetectionCounters(ctx context.Context, key string, detection *models.Detection, delta int64) error {
+	pipe := c.rdb.TxPipeline()
+	pipe.HIncrBy(ctx, key, statsTotalField, delta)
+	pipe.HIncrBy(ctx, key, "db:"+detection.DatabaseID, delta)
+	pipe.HIncrBy(ctx, key, "category:"+detection.Category, delta)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to update detection counters: %w", err)
+	}
+
+	if key == statsActiveDetectionsKey {
+		c.updateActiveDetectionsGauge(ctx)
+	}
+	return nil
+}
+
+func executedBucketKey(t time.Time) string {
+	return fmt.Sprintf("%s%d", statsExecutedBucketPrefix, t.Unix()/3600)
+}
+
+// recordActionExecuted counts an action reaching a terminal state in the current hourly bucket.
+func (c *Client) recordActionExecuted(ctx context.Context, at time.Time) error {
+	key := executedBucketKey(at)
+
+	pipe := c.rdb.TxPipeline()
+	pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, executedWindow+time.Hour)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record executed action: %w", err)
+	}
+
+	return nil
+}
+
+// GetStatsCounters reads the incrementally maintained counters.
+func (c *Client) GetStatsCounters(ctx context.Context) (*StatsCounters, error) {
+	counters := &StatsCounters{}
+
+	active, err := c.rdb.HGetAll(ctx, statsActiveDetectionsKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active detection counters: %w", err)
+	}
+	counters.ActiveDetections, counters.ActiveByDatabase, counters.ActiveByCategory = splitCounterHash(active)
+
+	resolved, err := c.rdb.HGetAll(ctx, statsResolvedDetectionsKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resolved detection counters: %w", err)
+	}
+	counters.ResolvedDetections, counters.ResolvedByDatabase, counters.ResolvedByCategory = splitCounterHash(resolved)
+
+	if total, err := c.rdb.Get(ctx, statsTotalActionsKey).Int64(); err == nil {
+		counters.TotalActions = int32(total)
+	} else if err.Error() != "redis: nil" {
+		return nil, fmt.Errorf("failed to get action counter: %w", err)
+	}
+
+	now := time.Now()
+	hours := int(executedWindow / time.Hour)
+	keys := make([]string, 0, hours)
+	for i := 0; i < hours; i++ {
+		keys = append(keys, executedBucketKey(now.Add(-time.Duration(i)*time.Hour)))
+	}
+
+	buckets, err := c.rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get executed action buckets: %w", err)
+	}
+	for _, bucket := range buckets {
+		if value, ok := bucket.(string); ok {
+			n, _ := strconv.Atoi(value)
+			counters.ActionsExecutedLastDay += int32(n)
+		}
+	}
+
+	return counters, nil
+}
+
+// splitCounterHash separates a counter hash into its total, per-database and per-category counts.
+// Zero counts are dropped so resolved or removed databases do not linger in the breakdown.
+func splitCounterHash(fields map[string]string) (int32, map[string]int32, map[string]int32) {
+	var total int32
+	byDatabase := make(map[string]int32)
+	byCategory := make(map[string]int32)
+
+	for field, value := range fields {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			continue
+		}
+
+		switch {
+		case field == statsTotalField:
+			total = int32(n)
+		case strings.HasPrefix(field, "db:"):
+			byDatabase[strings.TrimPrefix(field, "db:")] = int32(n)
+		case strings.HasPrefix(field, "category:"):
+			byCategory[strings.TrimPrefix(field, "category:")] = int32(n)
+		}
+	}
+
+	return total, byDatabase, byCategory
+}
+
+// RebuildStatsCounters recomputes the active detection and total action counters from
+// the underlying sets. It runs once at startup so counters stay correct for data
+// written before they existed. Resolved and executed counts cannot be recovered, as
+// resolved detections expire, and are left untouched.
+func (c *Client) RebuildStatsCounters(ctx context.Context) error {
+	databases, err := c.ListDatabases(ctx)
+	if err != nil {
+		return err
+	}
+
+	activeFields := map[string]interface{}{statsTotalField: 0}
+	var total int
+	for _, db := range databases {
+		detections, err := c.GetActiveDetections(ctx, db.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, detection := range detections {
+			total++
+			incrementField(activeFields, "db:"+detection.DatabaseID)
+			incrementField(activeFields, "category:"+detection.Category)
+		}
+	}
+	activeFields[statsTotalField] = total
+
+	totalActions, err := c.CountAllActions(ctx)
+	if err != nil {
+		return err
+	}
+
+	pipe := c.rdb.TxPipeline()
+	pipe.Del(ctx, statsActiveDetectionsKey)
+	pipe.HSet(ctx, statsActiveDetectionsKey, activeFields)
+	pipe.Set(ctx, statsTotalActionsKey, totalActions, 0)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to rebuild stats counters: %w", err)
+	}
+
+	c.RefreshGauges(ctx)
+	return nil
+}
+
+func incrementField(fields map[string]interface{}, field string) {
+	n, _ := fields[field].(int)
+	fields[field] = n + 1
+}
@@ -0,0 +1,88 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// Each suppression is its own key with a TTL, so Redis expires it without a sweep.
+const suppressionPrefix = "suppression:"
+
+// SuppressDetectionKey snoozes a detection key for duration. A non-positive duration lifts
+// an existing suppression.
+func (c *Client) SuppressDetectionKey(ctx context.Context, key string, duration time.Duration, reason string) error {
+	redisKey := suppressionPrefix + key
+
+	if duration <= 0 {
+		if err := c.rdb.Del(ctx, redisKey).Err(); err != nil {
+			return fmt.Errorf("failed to lift suppression: %w", err)
+		}
+		return nil
+	}
+
+	now := time.Now()
+	data, err := json.Marshal(&models.Suppression{
+		Key:       key,
+		Reason:    reason,
+		CreatedAt: now,
+		ExpiresAt: now.Add(duration),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal suppression: %w", err)
+	}
+
+	if err := c.rdb.Set(ctx, redisKey, data, duration).Err(); err != nil {
+		return fmt.Errorf("failed to store suppression: %w", err)
+	}
+
+	return nil
+}
+
+// GetSuppression returns the suppression for a detection key, or nil if it is not suppressed.
+func (c *Client) GetSuppression(ctx context.Context, key string) (*models.Suppression, error) {
+	data, err := c.rdb.Get(ctx, suppressionPrefix+key).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suppression: %w", err)
+	}
+
+	var suppression models.Suppression
+	if err := json.Unmarshal([]byte(data), &suppression); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal suppression: %w", err)
+	}
+
+	return &suppression, nil
+}
+
+// ListSuppressions returns every suppression that has not expired.
+func (c *Client) ListSuppressions(ctx context.Context) ([]*models.Suppression, error) {
+	suppressions := make([]*models.Suppression, 0)
+
+	iter := c.rdb.Scan(ctx, 0, suppressionPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		data, err := c.rdb.Get(ctx, iter.Val()).Result()
+		if err != nil {
+			continue // expired since the scan saw it
+		}
+
+		var suppression models.Suppression
+		if err := json.Unmarshal([]byte(data), &suppression); err != nil {
+			continue
+		}
+		suppressions = append(suppressions, &suppression)
+	}
+
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan suppressions: %w", err)
+	}
+
+	return suppressions, nil
+}
@@ -0,0 +1,90 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/models"
+)
+
+// Threshold overrides live in one hash so the Analyser can read every set in a single call.
+// The global default is stored under thresholdsGlobalField, databases under their own field.
+const (
+	thresholdsKey            = "thresholds"
+	thresholdsGlobalField    = "global"
+	thresholdsDatabasePrefix = "database:"
+)
+
+func thresholdsField(databaseID string) string {
+	if databaseID == "" {
+		return thresholdsGlobalField
+	}
+	return thresholdsDatabasePrefix + databaseID
+}
+
+// SetThresholds replaces the overrides for a database (or the global default when
+// databaseID is empty). An empty set removes the overrides.
+func (c *Client) SetThresholds(ctx context.Context, databaseID string, values map[string]float64) error {
+	field := thresholdsField(databaseID)
+
+	if len(values) == 0 {
+		if err := c.rdb.HDel(ctx, thresholdsKey, field).Err(); err != nil {
+			return fmt.Errorf("failed to clear thresholds: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(&models.ThresholdSet{
+		DatabaseID: databaseID,
+		Values:     values,
+		UpdatedAt:  time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal thresholds: %w", err)
+	}
+
+	if err := c.rdb.HSet(ctx, thresholdsKey, field, data).Err(); err != nil {
+		return fmt.Errorf("failed to store thresholds: %w", err)
+	}
+
+	return nil
+}
+
+// GetThresholds returns the stored threshold sets. With a databaseID only the global
+// default and that database's set are returned; otherwise every set is.
+func (c *Client) GetThresholds(ctx context.Context, databaseID string) ([]*models.ThresholdSet, error) {
+	var items []string
+
+	if databaseID != "" {
+		values, err := c.rdb.HMGet(ctx, thresholdsKey, thresholdsGlobalField, thresholdsField(databaseID)).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get thresholds: %w", err)
+		}
+		for _, value := range values {
+			if s, ok := value.(string); ok {
+				items = append(items, s)
+			}
+		}
+	} else {
+		all, err := c.rdb.HGetAll(ctx, thresholdsKey).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get thresholds: %w", err)
+		}
+		for _, value := range all {
+			items = append(items, value)
+		}
+	}
+
+	sets := make([]*models.ThresholdSet, 0, len(items))
+	for _, item := range items {
+		var set models.ThresholdSet
+		if err := json.Unmarshal([]byte(item), &set); err != nil {
+			continue
+		}
+		sets = append(sets, &set)
+	}
+
+	return sets, nil
+}
@@ -0,0 +1,165 @@
+package unit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	grpcserver "github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/grpc"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/redis"
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+)
+
+// runActionThrough registers an action and moves it through statuses, failing the test on error.
+func runActionThrough(t *testing.T, client *redis.Client, id, actionType, databaseID string, statuses ...models.ActionStatus) {
+	t.Helper()
+	ctx := context.Background()
+
+	action := &models.Action{
+		ID:          id,
+		DetectionID: "det-" + id,
+		ActionType:  actionType,
+		DatabaseID:  databaseID,
+		Status:      models.StatusQueued,
+		Message:     "Action queued",
+		CreatedAt:   time.Now(),
+	}
+	if err := client.RegisterAction(ctx, action); err != nil {
+		t.Fatalf("Failed to register action %s: %v", id, err)
+	}
+	t.Cleanup(func() {
+		client.GetClient().Del(ctx, "action:"+id, "action:history:"+id, "actions:database:"+databaseID)
+		for _, status := range append(statuses, models.StatusQueued) {
+			client.GetClient().SRem(ctx, "action:status:"+string(status), id)
+		}
+	})
+
+	for _, status := range statuses {
+		if err := client.UpdateActionStatus(ctx, id, status, "", "", "", ""); err != nil {
+			t.Fatalf("Failed to move action %s to %s: %v", id, status, err)
+		}
+	}
+}
+
+func statsFor(t *testing.T, stats []*models.ActionStats, actionType string) *models.ActionStats {
+	t.Helper()
+	for _, s := range stats {
+		if s.ActionType == actionType {
+			return s
+		}
+	}
+	t.Fatalf("No stats for %s in %v", actionType, stats)
+	return nil
+}
+
+func TestActionStatsCountsOutcomes(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	db := fmt.Sprintf("stats-db-%d", time.Now().UnixNano())
+
+	runActionThrough(t, client, db+"-a1", "create_index", db, models.StatusExecuting, models.StatusCompleted, models.StatusRolledBack)
+	runActionThrough(t, client, db+"-a2", "create_index", db, models.StatusExecuting, models.StatusFailed)
+	// Re-sent completed statuses, e.g. from Executor reconciliation, are not attempts again
+	runActionThrough(t, client, db+"-a3", "vacuum_table", db, models.StatusCompleted, models.StatusCompleted)
+	runActionThrough(t, client, db+"-a4", "vacuum_table", db, models.StatusExecuting)
+
+	for id, verdict := range map[string]string{db + "-a1": models.VerdictIneffective, db + "-a3": models.VerdictEffective} {
+		for i := 0; i < 2; i++ {
+			outcome := &models.ActionOutcome{Metric: "seq_scans", Verdict: verdict, RecordedAt: time.Now()}
+			if err := client.SetActionOutcome(ctx, id, outcome); err != nil {
+				t.Fatalf("Failed to record outcome for %s: %v", id, err)
+			}
+		}
+	}
+
+	stats, err := client.GetActionStats(ctx, time.Now().Add(-time.Hour), db, "")
+	if err != nil {
+		t.Fatalf("Failed to get action stats: %v", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("Expected stats for 2 action types, got %d", len(stats))
+	}
+
+	index := statsFor(t, stats, "create_index")
+	if index.Attempts != 2 || index.Successes != 1 || index.Failures != 1 || index.Rollbacks != 1 {
+		t.Errorf("Unexpected create_index counts: %+v", index)
+	}
+	if index.VerifiedIneffective != 1 || index.VerifiedEffective != 0 {
+		t.Errorf("Expected one ineffective verdict for create_index, got %+v", index)
+	}
+	if index.TimedAttempts != 2 || index.SuccessRate() != 0.5 {
+		t.Errorf("Expected 2 timed attempts and a 0.5 success rate, got %+v", index)
+	}
+
+	vacuum := statsFor(t, stats, "vacuum_table")
+	if vacuum.Attempts != 1 || vacuum.Successes != 1 || vacuum.VerifiedEffective != 1 {
+		t.Errorf("Unexpected vacuum_table counts: %+v", vacuum)
+	}
+	if vacuum.TimedAttempts != 0 || vacuum.AvgExecutionMillis() != 0 {
+		t.Errorf("An action never seen executing has no execution time, got %+v", vacuum)
+	}
+	if vacuum.VerifiedSuccessRate() != 1 {
+		t.Errorf("Expected a verified success rate of 1, got %v", vacuum.VerifiedSuccessRate())
+	}
+
+	filtered, err := client.GetActionStats(ctx, time.Now().Add(-time.Hour), db, "vacuum_table")
+	if err != nil {
+		t.Fatalf("Failed to get filtered action stats: %v", err)
+	}
+	if len(filtered) != 1 || filtered[0].ActionType != "vacuum_table" {
+		t.Errorf("Expected only vacuum_table stats, got %v", filtered)
+	}
+
+	future, err := client.GetActionStats(ctx, time.Now().Add(2*time.Hour), db, "")
+	if err != nil {
+		t.Fatalf("Failed to get action stats for an empty window: %v", err)
+	}
+	if len(future) != 0 {
+		t.Errorf("Expected no stats in a window that starts later, got %v", future)
+	}
+}
+
+func TestGetActionStatsBreakdowns(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	suffix := time.Now().UnixNano()
+	shop := fmt.Sprintf("stats-shop-%d", suffix)
+	billing := fmt.Sprintf("stats-billing-%d", suffix)
+	actionType := fmt.Sprintf("stats_type_%d", suffix)
+
+	runActionThrough(t, client, shop+"-a1", actionType, shop, models.StatusExecuting, models.StatusCompleted)
+	runActionThrough(t, client, shop+"-a2", "create_index", shop, models.StatusExecuting, models.StatusFailed)
+	runActionThrough(t, client, billing+"-a1", actionType, billing, models.StatusExecuting, models.StatusFailed)
+
+	server := grpcserver.NewKnowledgeServer(client, 5*time.Minute)
+
+	resp, err := server.GetActionStats(context.Background(), &pb.GetActionStatsRequest{ActionType: actionType})
+	if err != nil {
+		t.Fatalf("GetActionStats returned an error: %v", err)
+	}
+	if resp.WindowSeconds != int64(redis.ActionStatsRetention.Seconds()) {
+		t.Errorf("Expected the window to default to the retention, got %ds", resp.WindowSeconds)
+	}
+	if len(resp.ByActionType) != 1 || resp.ByActionType[0].Attempts != 2 || resp.ByActionType[0].SuccessRate != 0.5 {
+		t.Errorf("Expected 2 attempts with a 0.5 success rate across databases, got %v", resp.ByActionType)
+	}
+	if len(resp.ByDatabase) != 2 || resp.ByDatabase[0].DatabaseId != billing || resp.ByDatabase[0].Failures != 1 {
+		t.Errorf("Expected billing then shop in the database breakdown, got %v", resp.ByDatabase)
+	}
+	if len(resp.ByActionTypeAndDatabase) != 2 || resp.Total.Attempts != 2 {
+		t.Errorf("Expected 2 pairs and 2 attempts in total, got %v and %v", resp.ByActionTypeAndDatabase, resp.Total)
+	}
+
+	resp, err = server.GetActionStats(context.Background(), &pb.GetActionStatsRequest{DatabaseId: shop, WindowSeconds: 3600})
+	if err != nil {
+		t.Fatalf("GetActionStats returned an error: %v", err)
+	}
+	if resp.WindowSeconds != 3600 || len(resp.ByActionType) != 2 || resp.Total.Successes != 1 || resp.Total.Failures != 1 {
+		t.Errorf("Expected both action types on %s within an hour, got %v", shop, resp)
+	}
+}
@@ -2,6 +2,9 @@ package unit
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -70,7 +73,7 @@ func TestUpdateActionStatus(t *testing.T) {
 	client.RegisterAction(ctx, action)
 
 	// Update status to executing
-	err := client.UpdateActionStatus(ctx, action.ID, models.StatusExecuting, "Executing action", "")
+	err := client.UpdateActionStatus(ctx, action.ID, models.StatusExecuting, "Executing action", "", "", "")
 	if err != nil {
 		t.Fatalf("Failed to update action status: %v", err)
 	}
@@ -96,6 +99,159 @@ func TestUpdateActionStatus(t *testing.T) {
 	client.GetClient().Del(ctx, "actions:status:executing")
 }
 
+func TestSetActionOutcome(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+
+	action := &models.Action{
+		ID:          "test-action-outcome",
+		DetectionID: "test-det-outcome",
+		ActionType:  "create_index",
+		DatabaseID:  "testdb",
+		Status:      models.StatusCompleted,
+		Message:     "Index created",
+		CreatedAt:   time.Now(),
+	}
+
+	client.RegisterAction(ctx, action)
+
+	outcome := &models.ActionOutcome{
+		Metric:       "sequential_scans_per_interval",
+		MetricBefore: 1200,
+		MetricAfter:  3,
+		Verdict:      "effective",
+		RecordedAt:   time.Now(),
+	}
+	if err := client.SetActionOutcome(ctx, action.ID, outcome); err != nil {
+		t.Fatalf("Failed to set action outcome: %v", err)
+	}
+
+	retrieved, err := client.GetAction(ctx, action.ID)
+	if err != nil {
+		t.Fatalf("Failed to retrieve action: %v", err)
+	}
+
+	if retrieved.Outcome == nil {
+		t.Fatalf("Expected outcome to be stored")
+	}
+
+	if retrieved.Outcome.MetricBefore != 1200 || retrieved.Outcome.MetricAfter != 3 || retrieved.Outcome.Verdict != "effective" {
+		t.Errorf("Expected 1200 => 3 (effective), got %+v", retrieved.Outcome)
+	}
+
+	if retrieved.Status != models.StatusCompleted {
+		t.Errorf("Expected status to be left as %s, got %s", models.StatusCompleted, retrieved.Status)
+	}
+
+	if err := client.SetActionOutcome(ctx, "missing-action", outcome); err == nil {
+		t.Errorf("Expected an error for an unknown action")
+	}
+
+	// Clean up
+	client.GetClient().Del(ctx, "action:"+action.ID, "action:history:"+action.ID)
+	client.GetClient().Del(ctx, "actions:database:"+action.DatabaseID)
+	client.GetClient().SRem(ctx, "action:status:completed", action.ID)
+}
+
+func TestRegisterActionPersistsStateAndResult(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+
+	action := &models.Action{
+		ID:          "test-action-state",
+		DetectionID: "test-det-state",
+		ActionType:  "create_index",
+		DatabaseID:  "testdb",
+		Status:      models.StatusQueued,
+		State:       `{"action_metadata":{"table_name":"users"}}`,
+		CreatedAt:   time.Now(),
+	}
+
+	if err := client.RegisterAction(ctx, action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	// Re-registering the same ID must not leave it in two status sets
+	action.Status = models.StatusExecuting
+	if err := client.RegisterAction(ctx, action); err != nil {
+		t.Fatalf("Failed to re-register action: %v", err)
+	}
+
+	queued, _ := client.GetActionByStatus(ctx, models.StatusQueued)
+	for _, a := range queued {
+		if a.ID == action.ID {
+			t.Errorf("Expected action to be removed from queued set after re-registration")
+		}
+	}
+
+	if err := client.SetActionResult(ctx, action.ID, `{"index_name":"idx_users_email"}`, true); err != nil {
+		t.Fatalf("Failed to set action result: %v", err)
+	}
+
+	retrieved, err := client.GetAction(ctx, action.ID)
+	if err != nil {
+		t.Fatalf("Failed to retrieve action: %v", err)
+	}
+
+	if retrieved.State != action.State {
+		t.Errorf("Expected State %s, got %s", action.State, retrieved.State)
+	}
+
+	if retrieved.Result != `{"index_name":"idx_users_email"}` || !retrieved.CanRollback {
+		t.Errorf("Expected recorded result with rollback, got %s (can_rollback=%v)", retrieved.Result, retrieved.CanRollback)
+	}
+
+	// Clean up
+	client.GetClient().Del(ctx, "action:"+action.ID)
+	client.GetClient().SRem(ctx, "actions:database:"+action.DatabaseID, action.ID)
+	client.GetClient().SRem(ctx, "action:status:executing", action.ID)
+}
+
+func TestRegisterActionKeepsPolicyDecision(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+
+	action := &models.Action{
+		ID:             "test-action-policy",
+		DetectionID:    "test-det-policy",
+		ActionType:     "vacuum_table",
+		DatabaseID:     "testdb",
+		Status:         "pending_approval",
+		CreatedAt:      time.Now(),
+		PolicyDecision: "approve",
+		PolicyRule:     "severity:critical=approve",
+	}
+
+	if err := client.RegisterAction(ctx, action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	// Later status changes must not lose why the action waited for approval
+	if err := client.UpdateActionStatus(ctx, action.ID, models.StatusQueued, "Approved", "", "", "manual_approval"); err != nil {
+		t.Fatalf("Failed to update action: %v", err)
+	}
+
+	retrieved, err := client.GetAction(ctx, action.ID)
+	if err != nil {
+		t.Fatalf("Failed to retrieve action: %v", err)
+	}
+
+	if retrieved.PolicyDecision != "approve" || retrieved.PolicyRule != "severity:critical=approve" {
+		t.Errorf("Expected policy approve by severity:critical=approve, got %q by %q", retrieved.PolicyDecision, retrieved.PolicyRule)
+	}
+
+	// Clean up
+	client.GetClient().Del(ctx, "action:"+action.ID)
+	client.GetClient().SRem(ctx, "actions:database:"+action.DatabaseID, action.ID)
+	client.GetClient().SRem(ctx, "action:status:queued", action.ID)
+}
+
 func TestGetPendingActions(t *testing.T) {
 	client := setupTestClient(t)
 	defer client.Close()
@@ -161,3 +317,322 @@ func TestGetPendingActions(t *testing.T) {
 	client.GetClient().Del(ctx, "actions:status:executing")
 	client.GetClient().Del(ctx, "actions:status:completed")
 }
+
+func TestPendingImplementationDemand(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	now := time.Now()
+
+	requests := []*models.Action{
+		{ID: "test-pi-001", ActionType: "fix_replication_lag", DatabaseID: "testdb-a", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "test-pi-002", ActionType: "fix_replication_lag", DatabaseID: "testdb-b", CreatedAt: now.Add(-time.Hour)},
+		{ID: "test-pi-003", ActionType: "fix_replication_lag", DatabaseID: "testdb-a", CreatedAt: now},
+		{ID: "test-pi-004", ActionType: "partition_table", DatabaseID: "testdb-a", CreatedAt: now},
+	}
+
+	for _, action := range requests {
+		action.Status = models.StatusQueued
+		if err := client.RegisterAction(ctx, action); err != nil {
+			t.Fatalf("Failed to register action: %v", err)
+		}
+		if err := client.UpdateActionStatus(ctx, action.ID, models.StatusPendingImplementation, "Not implemented yet", "", "", ""); err != nil {
+			t.Fatalf("Failed to update action status: %v", err)
+		}
+	}
+
+	// Pending implementation is terminal, not pending
+	pending, err := client.GetPendingActions(ctx, "testdb-a")
+	if err != nil {
+		t.Fatalf("Failed to get pending actions: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("Expected no pending actions, got %d", len(pending))
+	}
+
+	demand, err := client.GetPendingImplementationDemand(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get pending implementation demand: %v", err)
+	}
+
+	if len(demand) != 2 {
+		t.Fatalf("Expected 2 action types, got %d", len(demand))
+	}
+
+	top := demand[0]
+	if top.ActionType != "fix_replication_lag" || top.Count != 3 {
+		t.Errorf("Expected fix_replication_lag requested 3 times first, got %s x%d", top.ActionType, top.Count)
+	}
+	if len(top.DatabaseIDs) != 2 || top.DatabaseIDs[0] != "testdb-a" || top.DatabaseIDs[1] != "testdb-b" {
+		t.Errorf("Expected databases [testdb-a testdb-b], got %v", top.DatabaseIDs)
+	}
+	if !top.LastRequestedAt.Equal(now) {
+		t.Errorf("Expected last request at %s, got %s", now, top.LastRequestedAt)
+	}
+
+	// Clean up
+	for _, action := range requests {
+		client.GetClient().Del(ctx, "action:"+action.ID)
+		client.GetClient().Del(ctx, "action:history:"+action.ID)
+	}
+	client.GetClient().Del(ctx, "actions:database:testdb-a", "actions:database:testdb-b")
+	client.GetClient().Del(ctx, "action:status:queued", "action:status:pending_implementation")
+}
+
+func TestRecentDatabaseActions(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	now := time.Now()
+	databaseID := "testdb-cooldown"
+
+	if err := client.RecordDatabaseAction(ctx, databaseID, "test-action-old", now.Add(-2*time.Hour)); err != nil {
+		t.Fatalf("Failed to record action: %v", err)
+	}
+	if err := client.RecordDatabaseAction(ctx, databaseID, "test-action-recent", now.Add(-time.Minute)); err != nil {
+		t.Fatalf("Failed to record action: %v", err)
+	}
+
+	startedAt, err := client.GetRecentDatabaseActions(ctx, databaseID, now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to get recent actions: %v", err)
+	}
+
+	if len(startedAt) != 1 {
+		t.Fatalf("Expected 1 action in the last hour, got %d", len(startedAt))
+	}
+
+	if startedAt[0].Unix() != now.Add(-time.Minute).Unix() {
+		t.Errorf("Expected start time %v, got %v", now.Add(-time.Minute), startedAt[0])
+	}
+
+	// Clean up
+	client.GetClient().Del(ctx, "actions:started:"+databaseID)
+}
+
+func TestActionHistoryRecordsTransitions(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+
+	action := &models.Action{
+		ID:          "test-action-history",
+		DetectionID: "test-det-history",
+		ActionType:  "create_index",
+		DatabaseID:  "testdb",
+		Status:      models.StatusQueued,
+		Message:     "Action queued",
+		Source:      "detector:missing_index",
+		CreatedAt:   time.Now(),
+	}
+	defer client.GetClient().Del(ctx, "action:"+action.ID, "action:history:"+action.ID)
+
+	if err := client.RegisterAction(ctx, action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	steps := []struct {
+		status    models.ActionStatus
+		message   string
+		errMsg    string
+		errorCode string
+		source    string
+	}{
+		{models.StatusExecuting, "Action executing", "", "", ""},
+		{models.StatusExecuting, "Action executing: building (40%)", "", "", ""}, // progress, not a transition
+		{models.StatusFailed, "Execution error", "lock timeout", "CONFLICT", ""},
+		{models.ActionStatus("rolled_back"), "Action rolled back successfully", "", "", "manual_rollback"},
+	}
+	for _, step := range steps {
+		if err := client.UpdateActionStatus(ctx, action.ID, step.status, step.message, step.errMsg, step.errorCode, step.source); err != nil {
+			t.Fatalf("Failed to update action status: %v", err)
+		}
+	}
+
+	history, err := client.GetActionHistory(ctx, action.ID)
+	if err != nil {
+		t.Fatalf("Failed to get action history: %v", err)
+	}
+
+	wantStatuses := []models.ActionStatus{models.StatusQueued, models.StatusExecuting, models.StatusFailed, "rolled_back"}
+	if len(history) != len(wantStatuses) {
+		t.Fatalf("Expected %d history entries, got %d", len(wantStatuses), len(history))
+	}
+	for i, want := range wantStatuses {
+		if history[i].Status != want {
+			t.Errorf("Entry %d: expected status %s, got %s", i, want, history[i].Status)
+		}
+	}
+
+	if history[2].Error != "lock timeout" || history[2].ErrorCode != "CONFLICT" {
+		t.Errorf("Expected failure entry to keep its error and code, got %q (%s)", history[2].Error, history[2].ErrorCode)
+	}
+	if history[1].Source != "detector:missing_index" {
+		t.Errorf("Expected transition to default to the action's source, got %q", history[1].Source)
+	}
+	if history[3].Source != "manual_rollback" {
+		t.Errorf("Expected rollback entry to record its own source, got %q", history[3].Source)
+	}
+
+	// The action record itself still only holds the latest status
+	retrieved, err := client.GetAction(ctx, action.ID)
+	if err != nil {
+		t.Fatalf("Failed to retrieve action: %v", err)
+	}
+	if retrieved.Source != "detector:missing_index" {
+		t.Errorf("Expected source to be stored, got %q", retrieved.Source)
+	}
+	if retrieved.ErrorCode != "CONFLICT" {
+		t.Errorf("Expected the last failure's code to be kept, got %q", retrieved.ErrorCode)
+	}
+}
+
+func TestActionHistoryIsTrimmed(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+
+	action := &models.Action{
+		ID:          "test-action-history-trim",
+		DetectionID: "test-det-history-trim",
+		ActionType:  "create_index",
+		DatabaseID:  "testdb",
+		Status:      models.StatusQueued,
+		CreatedAt:   time.Now(),
+	}
+	defer client.GetClient().Del(ctx, "action:"+action.ID, "action:history:"+action.ID)
+
+	if err := client.RegisterAction(ctx, action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+
+	// Alternate between two statuses so every update is a transition
+	for i := 0; i < 60; i++ {
+		status := models.StatusExecuting
+		if i%2 == 1 {
+			status = models.StatusQueued
+		}
+		if err := client.UpdateActionStatus(ctx, action.ID, status, "retry", "", "", ""); err != nil {
+			t.Fatalf("Failed to update action status: %v", err)
+		}
+	}
+
+	history, err := client.GetActionHistory(ctx, action.ID)
+	if err != nil {
+		t.Fatalf("Failed to get action history: %v", err)
+	}
+	if len(history) != 50 {
+		t.Errorf("Expected history trimmed to 50 entries, got %d", len(history))
+	}
+	if history[len(history)-1].Status != models.StatusQueued {
+		t.Errorf("Expected the newest entry to be kept, got %s", history[len(history)-1].Status)
+	}
+}
+
+func TestTruncateResultKeepsSmallResults(t *testing.T) {
+	result := `{"index_name":"idx_users_email"}`
+	if got, rollbackKept := models.TruncateResult(result, models.MaxActionResultBytes); got != result || !rollbackKept {
+		t.Errorf("Expected result unchanged, got %s", got)
+	}
+}
+
+func TestTruncateResultCutsLongValues(t *testing.T) {
+	result := fmt.Sprintf(`{"index_name":"idx_users_email","output":%q}`, strings.Repeat("x", 100*1024))
+
+	truncated, _ := models.TruncateResult(result, models.MaxActionResultBytes)
+	if len(truncated) > models.MaxActionResultBytes {
+		t.Fatalf("Expected at most %d bytes, got %d", models.MaxActionResultBytes, len(truncated))
+	}
+
+	var changes map[string]interface{}
+	if err := json.Unmarshal([]byte(truncated), &changes); err != nil {
+		t.Fatalf("Truncated result is not valid JSON: %v", err)
+	}
+	if changes["index_name"] != "idx_users_email" {
+		t.Errorf("Expected short values kept, got %v", changes["index_name"])
+	}
+	if output, _ := changes["output"].(string); !strings.Contains(output, "[truncated") {
+		t.Errorf("Expected truncation marker in long value, got %d bytes", len(output))
+	}
+	if changes["_truncated"] != true || changes["_original_bytes"] != float64(len(result)) {
+		t.Errorf("Expected truncation markers, got %v / %v", changes["_truncated"], changes["_original_bytes"])
+	}
+}
+
+func TestTruncateResultFallsBackToKeys(t *testing.T) {
+	changes := map[string]string{}
+	for i := 0; i < 200; i++ {
+		changes[fmt.Sprintf("setting_%03d", i)] = strings.Repeat("v", 1000)
+	}
+	data, _ := json.Marshal(changes)
+
+	truncated, _ := models.TruncateResult(string(data), 16*1024)
+	if len(truncated) > 16*1024 {
+		t.Fatalf("Expected at most 16KB, got %d bytes", len(truncated))
+	}
+
+	var summary struct {
+		Truncated bool     `json:"_truncated"`
+		Keys      []string `json:"_keys"`
+	}
+	if err := json.Unmarshal([]byte(truncated), &summary); err != nil {
+		t.Fatalf("Truncated result is not valid JSON: %v", err)
+	}
+	if !summary.Truncated || len(summary.Keys) != 200 {
+		t.Errorf("Expected key summary of 200 keys, got truncated=%v keys=%d", summary.Truncated, len(summary.Keys))
+	}
+}
+
+func TestTruncateResultKeepsRollbackFields(t *testing.T) {
+	changes := map[string]interface{}{
+		"container_name":  "startupmonkey-pgbouncer-shop-db",
+		"index_name":      "idx_orders_customer_id",
+		"original_config": map[string]string{"work_mem": strings.Repeat("4", 2000)},
+	}
+	for i := 0; i < 200; i++ {
+		changes[fmt.Sprintf("setting_%03d", i)] = strings.Repeat("v", 1000)
+	}
+	data, _ := json.Marshal(changes)
+
+	truncated, rollbackKept := models.TruncateResult(string(data), 16*1024)
+	if len(truncated) > 16*1024 {
+		t.Fatalf("Expected at most 16KB, got %d bytes", len(truncated))
+	}
+	if !rollbackKept {
+		t.Fatal("Expected the rollback fields to fit")
+	}
+
+	var kept map[string]interface{}
+	if err := json.Unmarshal([]byte(truncated), &kept); err != nil {
+		t.Fatalf("Truncated result is not valid JSON: %v", err)
+	}
+	if kept["container_name"] != "startupmonkey-pgbouncer-shop-db" || kept["index_name"] != "idx_orders_customer_id" {
+		t.Errorf("Expected rollback fields kept, got container_name=%v index_name=%v", kept["container_name"], kept["index_name"])
+	}
+	original, _ := kept["original_config"].(map[string]interface{})
+	if original["work_mem"] != strings.Repeat("4", 2000) {
+		t.Error("Expected original_config kept whole, not cut like other long values")
+	}
+	if _, ok := kept["setting_000"]; ok {
+		t.Error("Expected other values dropped")
+	}
+	if keys, _ := kept["_keys"].([]interface{}); len(keys) != 203 {
+		t.Errorf("Expected all 203 keys listed, got %d", len(keys))
+	}
+}
+
+func TestTruncateResultReportsLostRollbackFields(t *testing.T) {
+	result := fmt.Sprintf(`{"original_config":{"work_mem":%q}}`, strings.Repeat("4", 32*1024))
+
+	truncated, rollbackKept := models.TruncateResult(result, 16*1024)
+	if rollbackKept {
+		t.Error("Expected rollback fields reported lost when they cannot fit")
+	}
+	if len(truncated) > 16*1024 {
+		t.Fatalf("Expected at most 16KB, got %d bytes", len(truncated))
+	}
+}
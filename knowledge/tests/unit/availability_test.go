@@ -0,0 +1,149 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/redis"
+)
+
+// redisProxy forwards connections to the test Redis, so a test can cut Redis off and
+// bring it back on the same address.
+type redisProxy struct {
+	t    *testing.T
+	addr string
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    []net.Conn
+}
+
+func startRedisProxy(t *testing.T) *redisProxy {
+	t.Helper()
+
+	p := &redisProxy{t: t, addr: "127.0.0.1:0"}
+	p.start()
+	p.addr = p.listener.Addr().String()
+	t.Cleanup(p.stop)
+	return p
+}
+
+func (p *redisProxy) start() {
+	listener, err := net.Listen("tcp", p.addr)
+	if err != nil {
+		p.t.Fatalf("Failed to start proxy: %v", err)
+	}
+
+	p.mu.Lock()
+	p.listener = listener
+	p.mu.Unlock()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			upstream, err := net.Dial("tcp", "localhost:6379")
+			if err != nil {
+				conn.Close()
+				continue
+			}
+
+			p.mu.Lock()
+			p.conns = append(p.conns, conn, upstream)
+			p.mu.Unlock()
+
+			go io.Copy(upstream, conn)
+			go io.Copy(conn, upstream)
+		}
+	}()
+}
+
+// stop refuses new connections and drops open ones.
+func (p *redisProxy) stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.listener != nil {
+		p.listener.Close()
+		p.listener = nil
+	}
+	for _, conn := range p.conns {
+		conn.Close()
+	}
+	p.conns = nil
+}
+
+func TestCircuitBreakerOpensAndRecovers(t *testing.T) {
+	setupTestClient(t).Close() // skip without Redis
+
+	proxy := startRedisProxy(t)
+	client, err := redis.NewClient(proxy.addr, "", 1)
+	if err != nil {
+		t.Fatalf("Failed to connect through proxy: %v", err)
+	}
+	defer client.Close()
+
+	var availability []bool
+	var mu sync.Mutex
+	client.SetAvailabilityListener(func(available bool) {
+		mu.Lock()
+		availability = append(availability, available)
+		mu.Unlock()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reconnected := make(chan struct{}, 1)
+	go client.MonitorAvailability(ctx, 50*time.Millisecond, func(context.Context) {
+		reconnected <- struct{}{}
+	})
+
+	proxy.stop()
+
+	deadline := time.Now().Add(15 * time.Second)
+	for client.Available() {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected circuit breaker to open after Redis went away")
+		}
+		client.GetActiveDetections(ctx, "testdb")
+	}
+
+	// Commands fail fast while the breaker is open
+	start := time.Now()
+	if _, err := client.GetActiveDetections(ctx, "testdb"); !errors.Is(err, redis.ErrUnavailable) {
+		t.Errorf("Expected ErrUnavailable, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Expected fast failure, took %s", elapsed)
+	}
+
+	proxy.start()
+
+	select {
+	case <-reconnected:
+	case <-time.After(15 * time.Second):
+		t.Fatal("Expected reconnection to be verified")
+	}
+
+	if !client.Available() {
+		t.Error("Expected Redis available after reconnecting")
+	}
+	if _, err := client.GetActiveDetections(ctx, "testdb"); err != nil {
+		t.Errorf("Expected commands to succeed after reconnecting, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(availability) != 2 || availability[0] || !availability[1] {
+		t.Errorf("Expected unavailable then available, got %v", availability)
+	}
+}
@@ -64,7 +64,7 @@ func TestCountAllActiveDetections(t *testing.T) {
 	}
 	client.GetClient().Del(ctx, "detections:active:test-count-db")
 	client.GetClient().Del(ctx, "database:test-count-db")
-	client.GetClient().SRem(ctx, "databases:all", "test-count-db")
+	client.GetClient().ZRem(ctx, "databases:all", "test-count-db")
 }
 
 func TestCountActionsByStatus(t *testing.T) {
@@ -214,3 +214,89 @@ func TestGetActionByStatus(t *testing.T) {
 	client.GetClient().Del(ctx, "actions:database:testdb")
 	client.GetClient().Del(ctx, "action:status:failed")
 }
+
+func TestStatsCountersTrackStateChanges(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+
+	before, err := client.GetStatsCounters(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get stats counters: %v", err)
+	}
+
+	detection := &models.Detection{
+		ID:         "stats-det-001",
+		Key:        "stats-db:det1",
+		State:      models.StateActive,
+		Category:   "stats-category",
+		DatabaseID: "stats-db",
+		CreatedAt:  time.Now(),
+		LastSeen:   time.Now(),
+	}
+
+	// Registering twice must only count once
+	client.RegisterDetection(ctx, detection)
+	client.RegisterDetection(ctx, detection)
+
+	action := &models.Action{
+		ID:          "stats-action-001",
+		DetectionID: detection.ID,
+		ActionType:  "create_index",
+		DatabaseID:  "stats-db",
+		Status:      models.StatusQueued,
+		CreatedAt:   time.Now(),
+	}
+	client.RegisterAction(ctx, action)
+	client.UpdateActionStatus(ctx, action.ID, models.StatusCompleted, "done", "", "", "")
+
+	mid, err := client.GetStatsCounters(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get stats counters: %v", err)
+	}
+
+	if mid.ActiveDetections != before.ActiveDetections+1 {
+		t.Errorf("Expected active detections %d, got %d", before.ActiveDetections+1, mid.ActiveDetections)
+	}
+
+	if mid.ActiveByDatabase["stats-db"] != 1 || mid.ActiveByCategory["stats-category"] != 1 {
+		t.Errorf("Expected per-database and per-category counts of 1, got %v / %v", mid.ActiveByDatabase, mid.ActiveByCategory)
+	}
+
+	if mid.TotalActions != before.TotalActions+1 {
+		t.Errorf("Expected total actions %d, got %d", before.TotalActions+1, mid.TotalActions)
+	}
+
+	if mid.ActionsExecutedLastDay != before.ActionsExecutedLastDay+1 {
+		t.Errorf("Expected executed actions %d, got %d", before.ActionsExecutedLastDay+1, mid.ActionsExecutedLastDay)
+	}
+
+	// Resolving twice must only move the count once
+	client.MarkDetectionResolved(ctx, detection.ID, "test")
+	client.MarkDetectionResolved(ctx, detection.ID, "test")
+
+	after, err := client.GetStatsCounters(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get stats counters: %v", err)
+	}
+
+	if after.ActiveDetections != before.ActiveDetections {
+		t.Errorf("Expected active detections back to %d, got %d", before.ActiveDetections, after.ActiveDetections)
+	}
+
+	if after.ResolvedDetections != before.ResolvedDetections+1 {
+		t.Errorf("Expected resolved detections %d, got %d", before.ResolvedDetections+1, after.ResolvedDetections)
+	}
+
+	if _, ok := after.ActiveByDatabase["stats-db"]; ok {
+		t.Errorf("Expected stats-db to drop out of the active breakdown")
+	}
+
+	// Clean up
+	client.GetClient().Del(ctx, "detection:"+detection.ID)
+	client.GetClient().Del(ctx, "detection_key:"+detection.Key)
+	client.GetClient().Del(ctx, "action:"+action.ID)
+	client.GetClient().SRem(ctx, "actions:database:stats-db", action.ID)
+	client.GetClient().SRem(ctx, "action:status:completed", action.ID)
+}
@@ -0,0 +1,201 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/models"
+)
+
+func TestIsDetectionActiveCacheInvalidatedByWrites(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	client.SetDetectionCacheTTL(time.Minute)
+
+	detection := &models.Detection{
+		ID:         "test-det-cache-001",
+		Key:        "testdb-cache:query:users:email:seq_scans",
+		State:      models.StateActive,
+		Category:   "query",
+		DatabaseID: "testdb-cache",
+		CreatedAt:  time.Now(),
+		LastSeen:   time.Now(),
+	}
+	defer func() {
+		client.GetClient().Del(ctx, "detection:"+detection.ID)
+		client.GetClient().Del(ctx, "detection_key:"+detection.Key)
+		client.GetClient().Del(ctx, "detections:active:"+detection.DatabaseID)
+	}()
+
+	// Cache the miss before the detection exists
+	if active, err := client.IsDetectionActive(ctx, detection.Key); err != nil || active {
+		t.Fatalf("Expected detection inactive before registering, got active=%v err=%v", active, err)
+	}
+
+	if err := client.RegisterDetection(ctx, detection); err != nil {
+		t.Fatalf("Failed to register detection: %v", err)
+	}
+	if active, _ := client.IsDetectionActive(ctx, detection.Key); !active {
+		t.Error("Expected registering to invalidate the cached miss")
+	}
+
+	if err := client.MarkDetectionResolved(ctx, detection.ID, "create_index"); err != nil {
+		t.Fatalf("Failed to resolve detection: %v", err)
+	}
+	if active, _ := client.IsDetectionActive(ctx, detection.Key); active {
+		t.Error("Expected resolving to invalidate the cached hit")
+	}
+}
+
+func TestIsDetectionActiveCacheServesRepeatLookups(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	client.SetDetectionCacheTTL(time.Minute)
+
+	key := "testdb-cache:cache:::cache_hit_rate"
+	if active, _ := client.IsDetectionActive(ctx, key); active {
+		t.Fatal("Expected detection inactive")
+	}
+
+	// Written behind the client's back, as another Knowledge replica would
+	record, _ := json.Marshal(&models.Detection{ID: "test-det-cache-external", Key: key, State: models.StateActive})
+	client.GetClient().Set(ctx, "detection:test-det-cache-external", record, 0)
+	client.GetClient().Set(ctx, "detection_key:"+key, "test-det-cache-external", 0)
+	defer client.GetClient().Del(ctx, "detection:test-det-cache-external", "detection_key:"+key)
+
+	if active, _ := client.IsDetectionActive(ctx, key); active {
+		t.Error("Expected the cached answer within the TTL")
+	}
+
+	client.SetDetectionCacheTTL(0)
+	if active, _ := client.IsDetectionActive(ctx, key); !active {
+		t.Error("Expected a fresh read with the cache disabled")
+	}
+}
+
+func TestGetPendingActionsSkipsMissingRecords(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	dbID := "testdb-pending-missing"
+
+	action := &models.Action{
+		ID:          "test-action-pending-001",
+		DetectionID: "test-det-pending-001",
+		ActionType:  "create_index",
+		DatabaseID:  dbID,
+		Status:      models.StatusQueued,
+		CreatedAt:   time.Now(),
+	}
+	if err := client.RegisterAction(ctx, action); err != nil {
+		t.Fatalf("Failed to register action: %v", err)
+	}
+	client.GetClient().SAdd(ctx, "actions:database:"+dbID, "test-action-pending-missing")
+
+	pending, err := client.GetPendingActions(ctx, dbID)
+	if err != nil {
+		t.Fatalf("Failed to get pending actions: %v", err)
+	}
+	if len(pending) != 1 || pending[0].ID != action.ID {
+		t.Errorf("Expected only the registered action, got %d", len(pending))
+	}
+
+	// Clean up
+	client.GetClient().Del(ctx, "action:"+action.ID)
+	client.GetClient().Del(ctx, "actions:database:"+dbID)
+	client.GetClient().SRem(ctx, "action:status:queued", action.ID)
+}
+
+// benchmarkDetections registers n active detections for dbID and returns their cleanup.
+func benchmarkDetections(b *testing.B, dbID string, n int) func() {
+	client := setupTestClient(b)
+	ctx := context.Background()
+
+	detections := make([]*models.Detection, n)
+	for i := range detections {
+		detections[i] = &models.Detection{
+			ID:         fmt.Sprintf("bench-det-%04d", i),
+			Key:        fmt.Sprintf("%s:query:table_%d:id:seq_scans", dbID, i),
+			State:      models.StateActive,
+			Category:   "query",
+			DatabaseID: dbID,
+			CreatedAt:  time.Now(),
+			LastSeen:   time.Now(),
+		}
+		if err := client.RegisterDetection(ctx, detections[i]); err != nil {
+			b.Fatalf("Failed to register detection: %v", err)
+		}
+	}
+
+	return func() {
+		for _, det := range detections {
+			client.GetClient().Del(ctx, "detection:"+det.ID)
+			client.GetClient().Del(ctx, "detection_key:"+det.Key)
+		}
+		client.GetClient().Del(ctx, "detections:active:"+dbID)
+		client.Close()
+	}
+}
+
+func BenchmarkGetActiveDetections(b *testing.B) {
+	const dbID = "benchdb"
+	cleanup := benchmarkDetections(b, dbID, 200)
+	defer cleanup()
+
+	client := setupTestClient(b)
+	defer client.Close()
+	ctx := context.Background()
+
+	b.Run("batched", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := client.GetActiveDetections(ctx, dbID); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	// One GET per member, as GetActiveDetections used to do
+	b.Run("per_member", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ids, err := client.GetClient().SMembers(ctx, "detections:active:"+dbID).Result()
+			if err != nil {
+				b.Fatal(err)
+			}
+			for _, id := range ids {
+				if _, err := client.GetDetection(ctx, id); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
+
+func BenchmarkIsDetectionActive(b *testing.B) {
+	const dbID = "benchdb-active"
+	cleanup := benchmarkDetections(b, dbID, 1)
+	defer cleanup()
+
+	client := setupTestClient(b)
+	defer client.Close()
+	ctx := context.Background()
+	key := dbID + ":query:table_0:id:seq_scans"
+
+	for _, ttl := range []time.Duration{0, time.Minute} {
+		b.Run(fmt.Sprintf("ttl=%s", ttl), func(b *testing.B) {
+			client.SetDetectionCacheTTL(ttl)
+			for i := 0; i < b.N; i++ {
+				if _, err := client.IsDetectionActive(ctx, key); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/health"
+	sharedhealth "github.com/EricMurray-e-m-dev/StartupMonkey/proto/health"
+)
+
+// fakeRedis answers pings with a fixed error.
+type fakeRedis struct {
+	err error
+}
+
+func (f *fakeRedis) Ping(ctx context.Context) error {
+	return f.err
+}
+
+func startKnowledgeHealth(t *testing.T, redis health.Pinger, enableMetrics bool) string {
+	t.Helper()
+
+	s := health.NewServer("0", redis, enableMetrics)
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		s.Shutdown(ctx)
+	})
+
+	return "http://" + s.Addr()
+}
+
+func getKnowledgeHealth(t *testing.T, baseURL string) (int, sharedhealth.HealthResponse) {
+	t.Helper()
+
+	resp, err := http.Get(baseURL + "/health")
+	if err != nil {
+		t.Fatalf("GET /health: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body sharedhealth.HealthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode /health: %v", err)
+	}
+	return resp.StatusCode, body
+}
+
+func TestHealthServer_RedisUpIsHealthy(t *testing.T) {
+	code, body := getKnowledgeHealth(t, startKnowledgeHealth(t, &fakeRedis{}, false))
+
+	if code != http.StatusOK || body.Status != "healthy" || body.Service != "knowledge" {
+		t.Errorf("Expected 200 healthy knowledge, got %d %s %s", code, body.Status, body.Service)
+	}
+	if redis := body.Dependencies["redis"]; redis.Status != "up" || !redis.Critical {
+		t.Errorf("Expected critical redis up, got %+v", redis)
+	}
+}
+
+func TestHealthServer_RedisDownIsUnhealthy(t *testing.T) {
+	code, body := getKnowledgeHealth(t, startKnowledgeHealth(t, &fakeRedis{err: errors.New("connection refused")}, false))
+
+	if code != http.StatusServiceUnavailable || body.Status != "unhealthy" {
+		t.Errorf("Expected 503 unhealthy, got %d %s", code, body.Status)
+	}
+	if redis := body.Dependencies["redis"]; redis.Status != "down" || redis.Error != "connection refused" {
+		t.Errorf("Expected redis down with its error, got %+v", redis)
+	}
+}
+
+func TestHealthServer_MetricsOnlyWhenEnabled(t *testing.T) {
+	for _, tt := range []struct {
+		enableMetrics bool
+		want          int
+	}{
+		{false, http.StatusNotFound},
+		{true, http.StatusOK},
+	} {
+		resp, err := http.Get(startKnowledgeHealth(t, &fakeRedis{}, tt.enableMetrics) + "/metrics")
+		if err != nil {
+			t.Fatalf("GET /metrics: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != tt.want {
+			t.Errorf("ENABLE_METRICS=%v: expected /metrics %d, got %d", tt.enableMetrics, tt.want, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package unit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/redis"
+)
+
+func TestMetricHistoryFullResolutionAndDownsampled(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	databaseID := fmt.Sprintf("history-%d", time.Now().UnixNano())
+
+	// Six samples a minute apart, starting on a 5 minute boundary an hour ago
+	now := time.Now()
+	start := now.Add(-time.Hour).Truncate(redis.MetricDownsampleInterval)
+	for i := 0; i < 6; i++ {
+		sample := &models.MetricSample{
+			DatabaseID: databaseID,
+			Timestamp:  start.Add(time.Duration(i) * time.Minute),
+			Values:     map[string]float64{"health_score": float64(i) / 10},
+		}
+		if i < 2 {
+			sample.Values["p95_query_latency_ms"] = 100
+		}
+		if err := client.RecordMetricSample(ctx, sample); err != nil {
+			t.Fatalf("Failed to record sample %d: %v", i, err)
+		}
+	}
+
+	full, resolution, err := client.GetMetricHistory(ctx, databaseID, start, time.Time{}, 0, now)
+	if err != nil {
+		t.Fatalf("Failed to get full resolution history: %v", err)
+	}
+	if resolution != 0 || len(full) != 6 {
+		t.Fatalf("Expected 6 samples at full resolution, got %d at %s", len(full), resolution)
+	}
+	if !full[0].Timestamp.Equal(start) || full[5].Values["health_score"] != 0.5 {
+		t.Errorf("Expected samples oldest first, got %v first and %v last", full[0].Timestamp, full[5].Values)
+	}
+
+	// The first five fall in one bucket, the sixth in the next
+	downsampled, resolution, err := client.GetMetricHistory(ctx, databaseID, start, time.Time{}, redis.MetricDownsampleInterval, now)
+	if err != nil {
+		t.Fatalf("Failed to get downsampled history: %v", err)
+	}
+	if resolution != redis.MetricDownsampleInterval {
+		t.Errorf("Expected resolution %s, got %s", redis.MetricDownsampleInterval, resolution)
+	}
+	if len(downsampled) != 2 {
+		t.Fatalf("Expected 2 buckets, got %d", len(downsampled))
+	}
+	if got := downsampled[0].Values["health_score"]; got < 0.199 || got > 0.201 {
+		t.Errorf("Expected first bucket health_score to average 0.2, got %v", got)
+	}
+	if got := downsampled[0].Values["p95_query_latency_ms"]; got != 100 {
+		t.Errorf("Expected latency averaged over the samples that have it, got %v", got)
+	}
+
+	// Coarser resolutions regroup the downsampled buckets, weighted by their sample counts
+	hourly, resolution, err := client.GetMetricHistory(ctx, databaseID, start, time.Time{}, time.Hour, now)
+	if err != nil {
+		t.Fatalf("Failed to get hourly history: %v", err)
+	}
+	if resolution != time.Hour {
+		t.Errorf("Expected hourly resolution, got %s", resolution)
+	}
+	if len(hourly) == 0 || len(hourly) > 2 {
+		t.Fatalf("Expected 1 or 2 hourly buckets, got %d", len(hourly))
+	}
+	if len(hourly) == 1 {
+		if got := hourly[0].Values["health_score"]; got < 0.249 || got > 0.251 {
+			t.Errorf("Expected hourly health_score to average 0.25, got %v", got)
+		}
+	}
+}
+
+func TestMetricHistoryOlderThanFullResolutionIsDownsampled(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	databaseID := fmt.Sprintf("history-old-%d", time.Now().UnixNano())
+
+	now := time.Now()
+	sample := &models.MetricSample{
+		DatabaseID: databaseID,
+		Timestamp:  now.Add(-time.Hour),
+		Values:     map[string]float64{"cache_hit_rate": 0.9},
+	}
+	if err := client.RecordMetricSample(ctx, sample); err != nil {
+		t.Fatalf("Failed to record sample: %v", err)
+	}
+
+	// A range reaching past full-resolution retention is served downsampled
+	history, resolution, err := client.GetMetricHistory(ctx, databaseID, now.Add(-48*time.Hour), time.Time{}, 0, now)
+	if err != nil {
+		t.Fatalf("Failed to get history: %v", err)
+	}
+	if resolution != redis.MetricDownsampleInterval {
+		t.Errorf("Expected downsampled resolution, got %s", resolution)
+	}
+	if len(history) != 1 || history[0].Values["cache_hit_rate"] != 0.9 {
+		t.Errorf("Expected one downsampled sample, got %v", history)
+	}
+}
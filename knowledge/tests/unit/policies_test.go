@@ -0,0 +1,89 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	grpcserver "github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/grpc"
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+)
+
+func TestDatabasePolicyRoundTrip(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	server := grpcserver.NewKnowledgeServer(client, 5*time.Minute)
+	ctx := context.Background()
+	defer server.SetDatabasePolicy(ctx, &pb.SetDatabasePolicyRequest{Policy: &pb.DatabasePolicy{DatabaseId: "policy-billing"}})
+
+	resp, err := server.SetDatabasePolicy(ctx, &pb.SetDatabasePolicyRequest{Policy: &pb.DatabasePolicy{
+		DatabaseId:        "policy-billing",
+		DeniedActionTypes: []string{"terminate_query", " terminate_query"},
+		IgnoredDetectors:  []string{"missing_index"},
+		Reason:            "billing jobs must never be killed",
+	}})
+	if err != nil || !resp.Success {
+		t.Fatalf("Failed to set policy: %v %v", err, resp)
+	}
+
+	policy, err := server.GetDatabasePolicy(ctx, &pb.GetDatabasePolicyRequest{DatabaseId: "policy-billing"})
+	if err != nil {
+		t.Fatalf("Failed to get policy: %v", err)
+	}
+	if len(policy.DeniedActionTypes) != 1 || policy.DeniedActionTypes[0] != "terminate_query" {
+		t.Errorf("Expected terminate_query denied once, got %v", policy.DeniedActionTypes)
+	}
+	if len(policy.IgnoredDetectors) != 1 || policy.Reason == "" || policy.UpdatedAt == 0 {
+		t.Errorf("Unexpected policy: %v", policy)
+	}
+
+	list, err := server.ListDatabasePolicies(ctx, &pb.ListDatabasePoliciesRequest{})
+	if err != nil {
+		t.Fatalf("Failed to list policies: %v", err)
+	}
+	found := false
+	for _, p := range list.Policies {
+		found = found || p.DatabaseId == "policy-billing"
+	}
+	if !found {
+		t.Errorf("Expected policy-billing to be listed")
+	}
+
+	// Clearing every list removes the policy; the database reads as unrestricted
+	if resp, _ := server.SetDatabasePolicy(ctx, &pb.SetDatabasePolicyRequest{Policy: &pb.DatabasePolicy{DatabaseId: "policy-billing"}}); !resp.Success {
+		t.Fatalf("Failed to clear policy: %s", resp.Message)
+	}
+	policy, err = server.GetDatabasePolicy(ctx, &pb.GetDatabasePolicyRequest{DatabaseId: "policy-billing"})
+	if err != nil {
+		t.Fatalf("Failed to get cleared policy: %v", err)
+	}
+	if len(policy.DeniedActionTypes) != 0 || len(policy.IgnoredDetectors) != 0 || policy.DatabaseId != "policy-billing" {
+		t.Errorf("Expected an empty policy, got %v", policy)
+	}
+}
+
+func TestSetDatabasePolicyRejectsInvalidPolicy(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	server := grpcserver.NewKnowledgeServer(client, 5*time.Minute)
+
+	for name, p := range map[string]*pb.DatabasePolicy{
+		"missing database": {DeniedActionTypes: []string{"terminate_query"}},
+		"allowed and denied": {
+			DatabaseId:         "policy-billing",
+			AllowedActionTypes: []string{"create_index"},
+			DeniedActionTypes:  []string{"create_index"},
+		},
+		"nil policy": nil,
+	} {
+		resp, err := server.SetDatabasePolicy(context.Background(), &pb.SetDatabasePolicyRequest{Policy: p})
+		if err != nil {
+			t.Fatalf("%s: SetDatabasePolicy returned an error: %v", name, err)
+		}
+		if resp.Success {
+			t.Errorf("%s: expected the policy to be rejected", name)
+		}
+	}
+}
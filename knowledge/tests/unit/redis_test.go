@@ -2,6 +2,7 @@ package unit
 
 import (
 	"context"
+	"slices"
 	"testing"
 	"time"
 
@@ -9,7 +10,7 @@ import (
 	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/redis"
 )
 
-func setupTestClient(t *testing.T) *redis.Client {
+func setupTestClient(t testing.TB) *redis.Client {
 	client, err := redis.NewClient("localhost:6379", "", 1) // Use DB 1 for testing
 	if err != nil {
 		t.Skip("Redis not available, skipping test")
@@ -61,6 +62,70 @@ func TestRegisterDetection(t *testing.T) {
 	client.GetClient().Del(ctx, "detections:active:"+detection.DatabaseID)
 }
 
+func TestRegisterDetectionKeepsDetail(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+
+	detection := &models.Detection{
+		ID:             "test-det-detail",
+		Key:            "testdb:query:orders:customer_id:seq_scans",
+		State:          models.StateActive,
+		Severity:       "warning",
+		Category:       "query",
+		DatabaseID:     "testdb",
+		CreatedAt:      time.Now(),
+		LastSeen:       time.Now(),
+		Title:          "Sequential scans on orders",
+		Description:    "orders is scanned sequentially on customer_id",
+		Recommendation: "Create an index on orders(customer_id)",
+		ActionType:     "create_index",
+		Evidence:       `{"seq_scans":1200}`,
+		ActionMetadata: `{"table_name":"orders","column_name":"customer_id"}`,
+	}
+
+	if err := client.RegisterDetection(ctx, detection); err != nil {
+		t.Fatalf("Failed to register detection: %v", err)
+	}
+
+	retrieved, err := client.GetDetection(ctx, detection.ID)
+	if err != nil {
+		t.Fatalf("Failed to retrieve detection: %v", err)
+	}
+
+	if retrieved.Title != detection.Title || retrieved.Description != detection.Description ||
+		retrieved.Recommendation != detection.Recommendation || retrieved.ActionType != detection.ActionType {
+		t.Errorf("Expected detail %+v, got %+v", detection, retrieved)
+	}
+
+	if retrieved.Evidence != detection.Evidence {
+		t.Errorf("Expected evidence %s, got %s", detection.Evidence, retrieved.Evidence)
+	}
+
+	if retrieved.ActionMetadata != detection.ActionMetadata {
+		t.Errorf("Expected action metadata %s, got %s", detection.ActionMetadata, retrieved.ActionMetadata)
+	}
+
+	// Records written before the detail fields existed still decode
+	legacy := `{"id":"test-det-legacy","key":"testdb:cache:::cache_hit_rate","state":"active","severity":"warning","category":"cache","database_id":"testdb"}`
+	client.GetClient().Set(ctx, "detection:test-det-legacy", legacy, 0)
+
+	old, err := client.GetDetection(ctx, "test-det-legacy")
+	if err != nil {
+		t.Fatalf("Failed to retrieve legacy detection: %v", err)
+	}
+
+	if old.Title != "" || old.Evidence != "" {
+		t.Errorf("Expected empty detail for legacy detection, got %+v", old)
+	}
+
+	// Clean up
+	client.GetClient().Del(ctx, "detection:"+detection.ID, "detection:test-det-legacy")
+	client.GetClient().Del(ctx, "detection_key:"+detection.Key)
+	client.GetClient().Del(ctx, "detections:active:"+detection.DatabaseID)
+}
+
 func TestIsDetectionActive(t *testing.T) {
 	client := setupTestClient(t)
 	defer client.Close()
@@ -164,6 +229,170 @@ func TestMarkDetectionResolved(t *testing.T) {
 	client.GetClient().Del(ctx, "detection_key:"+detection.Key)
 }
 
+func TestReopenDetection(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+
+	detection := &models.Detection{
+		ID:         "test-det-reopen",
+		Key:        "testdb:query:comments:post_id:seq_scans",
+		State:      models.StateActive,
+		Severity:   "warning",
+		Category:   "query",
+		DatabaseID: "testdb",
+		Value:      1200,
+		CreatedAt:  time.Now(),
+		LastSeen:   time.Now(),
+	}
+
+	client.RegisterDetection(ctx, detection)
+	defer func() {
+		client.GetClient().Del(ctx, "detection:"+detection.ID)
+		client.GetClient().Del(ctx, "detection_key:"+detection.Key)
+		client.GetClient().SRem(ctx, "detections:active:testdb", detection.ID)
+	}()
+
+	if err := client.MarkDetectionResolved(ctx, detection.ID, "index_created:comments_post_id_idx"); err != nil {
+		t.Fatalf("Failed to mark detection resolved: %v", err)
+	}
+
+	reopened, err := client.ReopenDetection(ctx, detection.ID, "action rolled back")
+	if err != nil {
+		t.Fatalf("Failed to reopen detection: %v", err)
+	}
+	if !reopened {
+		t.Fatalf("Expected resolved detection to be reopened")
+	}
+
+	retrieved, err := client.GetDetection(ctx, detection.ID)
+	if err != nil {
+		t.Fatalf("Failed to retrieve detection: %v", err)
+	}
+	if retrieved.State != models.StateReopened {
+		t.Errorf("Expected state %s, got %s", models.StateReopened, retrieved.State)
+	}
+	if retrieved.ResolvedBy != "" {
+		t.Errorf("Expected ResolvedBy to be cleared, got %s", retrieved.ResolvedBy)
+	}
+	if retrieved.FailureReason != "action rolled back" {
+		t.Errorf("Expected FailureReason 'action rolled back', got %s", retrieved.FailureReason)
+	}
+
+	isActive, err := client.IsDetectionActive(ctx, detection.Key)
+	if err != nil {
+		t.Fatalf("Failed to check if detection active: %v", err)
+	}
+	if isActive {
+		t.Errorf("Expected reopened detection not to hold its key, so the issue can fire again")
+	}
+
+	member, err := client.GetClient().SIsMember(ctx, "detections:active:testdb", detection.ID).Result()
+	if err != nil {
+		t.Fatalf("Failed to check active set: %v", err)
+	}
+	if !member {
+		t.Errorf("Expected reopened detection in the active set")
+	}
+
+	// Reopening a detection that is not resolved is a no-op
+	reopened, err = client.ReopenDetection(ctx, detection.ID, "action failed")
+	if err != nil {
+		t.Fatalf("Failed to reopen reopened detection: %v", err)
+	}
+	if reopened {
+		t.Errorf("Expected reopened detection not to be reopened again")
+	}
+
+	// A detection that no longer exists has nothing to reopen
+	reopened, err = client.ReopenDetection(ctx, "test-det-reopen-missing", "action failed")
+	if err != nil || reopened {
+		t.Errorf("Expected missing detection to be skipped, got reopened=%v err=%v", reopened, err)
+	}
+}
+
+func TestReopenDetection_RolledBackIssueFiresAgain(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	key := "testdb:query:orders:customer_id:seq_scans"
+
+	original := &models.Detection{
+		ID:         "test-det-refire-1",
+		Key:        key,
+		State:      models.StateActive,
+		Severity:   "warning",
+		Category:   "query",
+		DatabaseID: "testdb",
+		CreatedAt:  time.Now(),
+		LastSeen:   time.Now(),
+	}
+	recurrence := &models.Detection{
+		ID:         "test-det-refire-2",
+		Key:        key,
+		State:      models.StateActive,
+		Severity:   "warning",
+		Category:   "query",
+		DatabaseID: "testdb",
+		CreatedAt:  time.Now(),
+		LastSeen:   time.Now(),
+	}
+	defer func() {
+		client.GetClient().Del(ctx, "detection:"+original.ID, "detection:"+recurrence.ID, "detection_key:"+key)
+		client.GetClient().SRem(ctx, "detections:active:testdb", original.ID, recurrence.ID)
+	}()
+
+	if err := client.RegisterDetection(ctx, original); err != nil {
+		t.Fatalf("Failed to register detection: %v", err)
+	}
+	if err := client.MarkDetectionResolved(ctx, original.ID, "index_created:orders_customer_id_idx"); err != nil {
+		t.Fatalf("Failed to mark detection resolved: %v", err)
+	}
+
+	// The index is rolled back
+	if _, err := client.ReopenDetection(ctx, original.ID, "action rolled back"); err != nil {
+		t.Fatalf("Failed to reopen detection: %v", err)
+	}
+
+	// The Analyser only registers and publishes detections whose key is not active
+	isActive, err := client.IsDetectionActive(ctx, key)
+	if err != nil {
+		t.Fatalf("Failed to check if detection active: %v", err)
+	}
+	if isActive {
+		t.Fatalf("Expected the rolled back issue not to be skipped as already active")
+	}
+
+	registered, holder, err := client.RegisterDetectionIfAbsent(ctx, recurrence)
+	if err != nil {
+		t.Fatalf("Failed to register recurrence: %v", err)
+	}
+	if !registered || holder != recurrence.ID {
+		t.Fatalf("Expected the recurrence to be registered, got registered=%v holder=%s", registered, holder)
+	}
+
+	superseded, err := client.GetDetection(ctx, original.ID)
+	if err != nil {
+		t.Fatalf("Failed to retrieve reopened detection: %v", err)
+	}
+	if superseded.State != models.StateSuperseded {
+		t.Errorf("Expected reopened detection to be superseded, got %s", superseded.State)
+	}
+
+	members, err := client.GetClient().SMembers(ctx, "detections:active:testdb").Result()
+	if err != nil {
+		t.Fatalf("Failed to read active set: %v", err)
+	}
+	if slices.Contains(members, original.ID) {
+		t.Errorf("Expected superseded detection to leave the active set")
+	}
+	if !slices.Contains(members, recurrence.ID) {
+		t.Errorf("Expected the recurrence in the active set")
+	}
+}
+
 func TestGetActiveDetections(t *testing.T) {
 	client := setupTestClient(t)
 	defer client.Close()
@@ -214,3 +443,284 @@ func TestGetActiveDetections(t *testing.T) {
 	}
 	client.GetClient().Del(ctx, "detections:active:"+dbID)
 }
+
+func TestGetActiveDetectionsRemovesOrphans(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	dbID := "testdb-orphans"
+	activeKey := "detections:active:" + dbID
+
+	detection := &models.Detection{
+		ID:         "test-det-orphan-001",
+		Key:        "testdb-orphans:query:users:email:seq_scans",
+		State:      models.StateActive,
+		Category:   "query",
+		DatabaseID: dbID,
+		CreatedAt:  time.Now(),
+		LastSeen:   time.Now(),
+	}
+	if err := client.RegisterDetection(ctx, detection); err != nil {
+		t.Fatalf("Failed to register detection: %v", err)
+	}
+
+	// An ID in the active set with no record, as a crash mid-write used to leave
+	client.GetClient().SAdd(ctx, activeKey, "test-det-orphan-missing")
+
+	active, err := client.GetActiveDetections(ctx, dbID)
+	if err != nil {
+		t.Fatalf("Failed to get active detections: %v", err)
+	}
+	if len(active) != 1 || active[0].ID != detection.ID {
+		t.Errorf("Expected only the registered detection, got %d", len(active))
+	}
+
+	isMember, _ := client.GetClient().SIsMember(ctx, activeKey, "test-det-orphan-missing").Result()
+	if isMember {
+		t.Error("Expected orphaned ID removed from the active set")
+	}
+
+	// Clean up
+	client.GetClient().Del(ctx, "detection:"+detection.ID)
+	client.GetClient().Del(ctx, "detection_key:"+detection.Key)
+	client.GetClient().Del(ctx, activeKey)
+}
+
+func TestMarkDetectionUnactionable(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+
+	detection := &models.Detection{
+		ID:         "test-det-unactionable",
+		Key:        "testdb:query:orders:status:seq_scans",
+		State:      models.StateActive,
+		Category:   "query",
+		DatabaseID: "testdb-unactionable",
+		CreatedAt:  time.Now(),
+		LastSeen:   time.Now(),
+	}
+
+	if err := client.RegisterDetection(ctx, detection); err != nil {
+		t.Fatalf("Failed to register detection: %v", err)
+	}
+
+	if err := client.MarkDetectionUnactionable(ctx, detection.ID, "missing table_name in detection metadata"); err != nil {
+		t.Fatalf("Failed to mark detection unactionable: %v", err)
+	}
+
+	// Still listed so the Dashboard can surface it
+	active, err := client.GetActiveDetections(ctx, detection.DatabaseID)
+	if err != nil {
+		t.Fatalf("Failed to get active detections: %v", err)
+	}
+
+	if len(active) != 1 {
+		t.Fatalf("Expected 1 detection, got %d", len(active))
+	}
+
+	if active[0].State != models.StateUnactionable {
+		t.Errorf("Expected state unactionable, got %s", active[0].State)
+	}
+
+	if active[0].FailureReason != "missing table_name in detection metadata" {
+		t.Errorf("Expected failure reason to be recorded, got %q", active[0].FailureReason)
+	}
+
+	// Still suppresses duplicate registrations
+	isActive, err := client.IsDetectionActive(ctx, detection.Key)
+	if err != nil {
+		t.Fatalf("Failed to check detection: %v", err)
+	}
+
+	if !isActive {
+		t.Error("Expected unactionable detection to suppress duplicates")
+	}
+
+	// Clean up
+	client.GetClient().Del(ctx, "detection:"+detection.ID)
+	client.GetClient().Del(ctx, "detection_key:"+detection.Key)
+	client.GetClient().Del(ctx, "detections:active:"+detection.DatabaseID)
+}
+
+func TestMarkDetectionAcknowledged(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+
+	detection := &models.Detection{
+		ID:         "test-det-acknowledged",
+		Key:        "testdb:replication:lag",
+		State:      models.StateActive,
+		Category:   "replication",
+		DatabaseID: "testdb-acknowledged",
+		CreatedAt:  time.Now(),
+		LastSeen:   time.Now(),
+	}
+
+	if err := client.RegisterDetection(ctx, detection); err != nil {
+		t.Fatalf("Failed to register detection: %v", err)
+	}
+
+	if err := client.MarkDetectionAcknowledged(ctx, detection.ID, "fix_replication_lag is not implemented yet"); err != nil {
+		t.Fatalf("Failed to acknowledge detection: %v", err)
+	}
+
+	// No longer listed as an open problem
+	active, err := client.GetActiveDetections(ctx, detection.DatabaseID)
+	if err != nil {
+		t.Fatalf("Failed to get active detections: %v", err)
+	}
+
+	if len(active) != 0 {
+		t.Fatalf("Expected acknowledged detection to leave the active set, got %d", len(active))
+	}
+
+	stored, err := client.GetDetection(ctx, detection.ID)
+	if err != nil {
+		t.Fatalf("Failed to get detection: %v", err)
+	}
+
+	if stored.State != models.StateAcknowledgedNoAction {
+		t.Errorf("Expected state acknowledged_no_action, got %s", stored.State)
+	}
+
+	// Still suppresses duplicate registrations while parked
+	isActive, err := client.IsDetectionActive(ctx, detection.Key)
+	if err != nil {
+		t.Fatalf("Failed to check detection: %v", err)
+	}
+
+	if !isActive {
+		t.Error("Expected acknowledged detection to suppress duplicates")
+	}
+
+	ttl := client.GetClient().TTL(ctx, "detection:"+detection.ID).Val()
+	if ttl <= 0 {
+		t.Errorf("Expected acknowledged detection to expire, got TTL %s", ttl)
+	}
+
+	// Clean up
+	client.GetClient().Del(ctx, "detection:"+detection.ID)
+	client.GetClient().Del(ctx, "detection_key:"+detection.Key)
+	client.GetClient().Del(ctx, "detections:active:"+detection.DatabaseID)
+}
+
+func TestSweepStaleDetections(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	now := time.Now()
+
+	stale := &models.Detection{
+		ID:         "test-det-stale",
+		Key:        "testdb-sweep:cache:::cache_hit_rate",
+		State:      models.StateActive,
+		Category:   "cache",
+		DatabaseID: "testdb-sweep",
+		CreatedAt:  now.Add(-time.Hour),
+		LastSeen:   now.Add(-time.Hour),
+	}
+	fresh := &models.Detection{
+		ID:         "test-det-fresh",
+		Key:        "testdb-sweep:query:users:email:seq_scans",
+		State:      models.StateActive,
+		Category:   "query",
+		DatabaseID: "testdb-sweep",
+		CreatedAt:  now.Add(-time.Hour),
+		LastSeen:   now.Add(-time.Hour),
+	}
+
+	for _, det := range []*models.Detection{stale, fresh} {
+		if err := client.RegisterDetection(ctx, det); err != nil {
+			t.Fatalf("Failed to register detection: %v", err)
+		}
+	}
+
+	// The Analyser saw the fresh one again
+	if err := client.RefreshDetectionLastSeen(ctx, fresh.Key, now); err != nil {
+		t.Fatalf("Failed to refresh detection: %v", err)
+	}
+
+	swept, err := client.SweepStaleDetections(ctx, 30*time.Minute, now)
+	if err != nil {
+		t.Fatalf("Failed to sweep: %v", err)
+	}
+
+	if swept < 1 {
+		t.Errorf("Expected at least 1 detection swept, got %d", swept)
+	}
+
+	isActive, err := client.IsDetectionActive(ctx, stale.Key)
+	if err != nil {
+		t.Fatalf("Failed to check stale detection: %v", err)
+	}
+	if isActive {
+		t.Error("Expected stale detection to no longer be active")
+	}
+
+	isActive, err = client.IsDetectionActive(ctx, fresh.Key)
+	if err != nil {
+		t.Fatalf("Failed to check fresh detection: %v", err)
+	}
+	if !isActive {
+		t.Error("Expected refreshed detection to stay active")
+	}
+
+	// Clean up
+	for _, det := range []*models.Detection{stale, fresh} {
+		client.GetClient().Del(ctx, "detection:"+det.ID)
+		client.GetClient().Del(ctx, "detection_key:"+det.Key)
+	}
+	client.GetClient().Del(ctx, "detections:active:testdb-sweep")
+}
+
+func TestUpdateDetectionSeverity(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	now := time.Now()
+
+	detection := &models.Detection{
+		ID:         "test-det-escalate",
+		Key:        "testdb-escalate:connection:::active_connections",
+		State:      models.StateActive,
+		Severity:   "warning",
+		Category:   "connection",
+		DatabaseID: "testdb-escalate",
+		CreatedAt:  now.Add(-time.Minute),
+	}
+
+	if err := client.RegisterDetection(ctx, detection); err != nil {
+		t.Fatalf("Failed to register detection: %v", err)
+	}
+
+	if err := client.UpdateDetectionSeverity(ctx, detection.ID, "critical", 95, now); err != nil {
+		t.Fatalf("Failed to update severity: %v", err)
+	}
+
+	stored, err := client.GetDetection(ctx, detection.ID)
+	if err != nil {
+		t.Fatalf("Failed to get detection: %v", err)
+	}
+
+	if stored.Severity != "critical" {
+		t.Errorf("Expected severity critical, got %s", stored.Severity)
+	}
+	if stored.Value != 95 {
+		t.Errorf("Expected value 95, got %f", stored.Value)
+	}
+	if stored.LastSeen.Unix() != now.Unix() {
+		t.Errorf("Expected LastSeen to be refreshed, got %v", stored.LastSeen)
+	}
+
+	// Clean up
+	client.MarkDetectionResolved(ctx, detection.ID, "cleanup")
+	client.GetClient().Del(ctx, "detection:"+detection.ID)
+	client.GetClient().Del(ctx, "detection_key:"+detection.Key)
+}
@@ -0,0 +1,137 @@
+package unit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	grpcserver "github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/grpc"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/models"
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+)
+
+func TestRegisterDetectionIfAbsent_SecondCallerLoses(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	key := "ifabsent-db:idx:orders:customer_id"
+
+	first := &models.Detection{ID: "ifabsent-001", Key: key, State: models.StateActive, DatabaseID: "ifabsent-db", CreatedAt: time.Now(), LastSeen: time.Now()}
+	second := &models.Detection{ID: "ifabsent-002", Key: key, State: models.StateActive, DatabaseID: "ifabsent-db", CreatedAt: time.Now(), LastSeen: time.Now()}
+	defer client.GetClient().Del(ctx, "detection:ifabsent-001", "detection:ifabsent-002", "detection_key:"+key, "detections:active:ifabsent-db")
+
+	registered, id, err := client.RegisterDetectionIfAbsent(ctx, first)
+	if err != nil {
+		t.Fatalf("Failed to register detection: %v", err)
+	}
+	if !registered || id != "ifabsent-001" {
+		t.Fatalf("Expected first detection to register, got registered=%v id=%s", registered, id)
+	}
+
+	registered, id, err = client.RegisterDetectionIfAbsent(ctx, second)
+	if err != nil {
+		t.Fatalf("Failed to register detection: %v", err)
+	}
+	if registered {
+		t.Fatal("Expected second detection with the same key not to register")
+	}
+	if id != "ifabsent-001" {
+		t.Errorf("Expected the active detection's ID ifabsent-001, got %s", id)
+	}
+
+	// Once resolved, the key is free for a new detection
+	if err := client.MarkDetectionResolved(ctx, "ifabsent-001", "test"); err != nil {
+		t.Fatalf("Failed to resolve detection: %v", err)
+	}
+
+	registered, id, err = client.RegisterDetectionIfAbsent(ctx, second)
+	if err != nil {
+		t.Fatalf("Failed to register detection: %v", err)
+	}
+	if !registered || id != "ifabsent-002" {
+		t.Errorf("Expected detection to register after resolution, got registered=%v id=%s", registered, id)
+	}
+}
+
+func TestRegisterDetectionIfAbsent_ConcurrentCallersRegisterOnce(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	server := grpcserver.NewKnowledgeServer(client, 5*time.Minute)
+
+	const callers = 20
+	key := "race-db:idx:orders:customer_id"
+
+	ids := make([]string, callers)
+	keys := []string{"detection_key:" + key, "detections:active:race-db"}
+	for i := range ids {
+		ids[i] = fmt.Sprintf("race-det-%03d", i)
+		keys = append(keys, "detection:"+ids[i])
+	}
+	client.GetClient().Del(ctx, keys...)
+	defer client.GetClient().Del(ctx, keys...)
+
+	var (
+		wg      sync.WaitGroup
+		start   = make(chan struct{})
+		results = make([]*pb.RegisterDetectionIfAbsentResponse, callers)
+	)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			resp, err := server.RegisterDetectionIfAbsent(ctx, &pb.RegisterDetectionRequest{
+				Id:         ids[i],
+				Key:        key,
+				Severity:   "warning",
+				DatabaseId: "race-db",
+				CreatedAt:  time.Now().Unix(),
+			})
+			if err != nil {
+				t.Errorf("RegisterDetectionIfAbsent returned error: %v", err)
+				return
+			}
+			results[i] = resp
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	winners := 0
+	winner := ""
+	for i, resp := range results {
+		if resp == nil {
+			continue
+		}
+		if !resp.Success {
+			t.Errorf("Caller %d failed: %s", i, resp.Message)
+			continue
+		}
+		if resp.Registered {
+			winners++
+			winner = resp.DetectionId
+		}
+	}
+	if winners != 1 {
+		t.Fatalf("Expected exactly one caller to register, got %d", winners)
+	}
+
+	for i, resp := range results {
+		if resp != nil && !resp.Registered && resp.DetectionId != winner {
+			t.Errorf("Caller %d was told %s holds the key, expected %s", i, resp.DetectionId, winner)
+		}
+	}
+
+	active, err := client.GetClient().SMembers(ctx, "detections:active:race-db").Result()
+	if err != nil {
+		t.Fatalf("Failed to read active set: %v", err)
+	}
+	if len(active) != 1 || active[0] != winner {
+		t.Errorf("Expected only %s in the active set, got %v", winner, active)
+	}
+}
@@ -0,0 +1,150 @@
+package unit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/models"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/redis"
+)
+
+func TestCleanupPurgesExpiredActionsAndDetections(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	dbID := "testdb-retention"
+	now := time.Now()
+
+	oldAction := &models.Action{
+		ID: "test-retention-old", DetectionID: "d1", ActionType: "create_index",
+		DatabaseID: dbID, Status: models.StatusQueued, CreatedAt: now.Add(-10 * 24 * time.Hour),
+	}
+	recentAction := &models.Action{
+		ID: "test-retention-recent", DetectionID: "d2", ActionType: "create_index",
+		DatabaseID: dbID, Status: models.StatusQueued, CreatedAt: now,
+	}
+	runningAction := &models.Action{
+		ID: "test-retention-running", DetectionID: "d3", ActionType: "create_index",
+		DatabaseID: dbID, Status: models.StatusExecuting, CreatedAt: now.Add(-10 * 24 * time.Hour),
+	}
+	for _, action := range []*models.Action{oldAction, recentAction, runningAction} {
+		if err := client.RegisterAction(ctx, action); err != nil {
+			t.Fatalf("Failed to register action: %v", err)
+		}
+	}
+	if err := client.UpdateActionStatus(ctx, oldAction.ID, models.StatusRolledBack, "Rolled back", "", "", ""); err != nil {
+		t.Fatalf("Failed to update action: %v", err)
+	}
+	if err := client.UpdateActionStatus(ctx, recentAction.ID, models.StatusCompleted, "Done", "", "", ""); err != nil {
+		t.Fatalf("Failed to update action: %v", err)
+	}
+
+	resolved := &models.Detection{
+		ID: "test-retention-det-resolved", Key: "testdb-retention:query:resolved", State: models.StateActive,
+		Category: "query", DatabaseID: dbID, CreatedAt: now, LastSeen: now.Add(-48 * time.Hour),
+	}
+	active := &models.Detection{
+		ID: "test-retention-det-active", Key: "testdb-retention:query:active", State: models.StateActive,
+		Category: "query", DatabaseID: dbID, CreatedAt: now, LastSeen: now.Add(-48 * time.Hour),
+	}
+	for _, detection := range []*models.Detection{resolved, active} {
+		if err := client.RegisterDetection(ctx, detection); err != nil {
+			t.Fatalf("Failed to register detection: %v", err)
+		}
+	}
+	if err := client.MarkDetectionResolved(ctx, resolved.ID, "index created"); err != nil {
+		t.Fatalf("Failed to resolve detection: %v", err)
+	}
+
+	// Index entries left behind by records that expired or were lost
+	client.GetClient().SAdd(ctx, "actions:database:"+dbID, "test-retention-missing")
+	client.GetClient().SAdd(ctx, "action:status:completed", "test-retention-missing")
+	client.GetClient().SAdd(ctx, "detections:active:"+dbID, "test-retention-det-missing")
+	client.GetClient().Set(ctx, "detection_key:testdb-retention:query:missing", "test-retention-det-missing", 0)
+
+	result, err := client.Cleanup(ctx, redis.RetentionPolicy{
+		ActionMaxAge:    7 * 24 * time.Hour,
+		DetectionMaxAge: 24 * time.Hour,
+	}, now.Add(8*24*time.Hour))
+	if err != nil {
+		t.Fatalf("Cleanup failed: %v", err)
+	}
+
+	// Both finished actions are past 7 days at the cleanup time; the running one is kept
+	if result.ActionsPurged < 2 {
+		t.Errorf("Expected at least 2 actions purged, got %d", result.ActionsPurged)
+	}
+	if result.DetectionsPurged < 1 {
+		t.Errorf("Expected the resolved detection purged, got %d", result.DetectionsPurged)
+	}
+	if result.DanglingRemoved < 4 {
+		t.Errorf("Expected at least 4 dangling entries removed, got %d", result.DanglingRemoved)
+	}
+
+	for _, id := range []string{oldAction.ID, recentAction.ID} {
+		if _, err := client.GetAction(ctx, id); err == nil {
+			t.Errorf("Expected action %s purged", id)
+		}
+		if n, _ := client.GetClient().Exists(ctx, "action:history:"+id).Result(); n != 0 {
+			t.Errorf("Expected history of %s purged", id)
+		}
+	}
+	if _, err := client.GetAction(ctx, runningAction.ID); err != nil {
+		t.Errorf("Expected executing action kept: %v", err)
+	}
+	if _, err := client.GetDetection(ctx, resolved.ID); err == nil {
+		t.Error("Expected resolved detection purged")
+	}
+	if _, err := client.GetDetection(ctx, active.ID); err != nil {
+		t.Errorf("Expected active detection kept: %v", err)
+	}
+
+	assertNoDanglingMembers(t, client, "actions:database:*", "action:")
+	assertNoDanglingMembers(t, client, "action:status:*", "action:")
+	assertNoDanglingMembers(t, client, "detections:active:*", "detection:")
+
+	if n, _ := client.GetClient().Exists(ctx, "detection_key:testdb-retention:query:missing").Result(); n != 0 {
+		t.Error("Expected mapping to a missing detection removed")
+	}
+
+	totals, err := client.GetCleanupTotals(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get cleanup totals: %v", err)
+	}
+	if totals.ActionsPurged < int64(result.ActionsPurged) || totals.LastCleanupAt.IsZero() {
+		t.Errorf("Expected cleanup totals recorded, got %+v", totals)
+	}
+
+	// Clean up
+	client.GetClient().Del(ctx, "action:"+runningAction.ID, "action:history:"+runningAction.ID)
+	client.GetClient().SRem(ctx, "action:status:executing", runningAction.ID)
+	client.GetClient().Del(ctx, "actions:database:"+dbID)
+	client.GetClient().Del(ctx, "detection:"+active.ID, "detection_key:"+active.Key, "detection_key:"+resolved.Key)
+	client.GetClient().Del(ctx, "detections:active:"+dbID)
+}
+
+// assertNoDanglingMembers checks every member of the sets matching pattern has a record.
+func assertNoDanglingMembers(t *testing.T, client *redis.Client, pattern, recordPrefix string) {
+	t.Helper()
+	ctx := context.Background()
+
+	iter := client.GetClient().Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		members, err := client.GetClient().SMembers(ctx, iter.Val()).Result()
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", iter.Val(), err)
+		}
+		for _, id := range members {
+			if !strings.HasPrefix(id, "test-retention") {
+				continue
+			}
+			if n, _ := client.GetClient().Exists(ctx, fmt.Sprintf("%s%s", recordPrefix, id)).Result(); n == 0 {
+				t.Errorf("Dangling member %s left in %s", id, iter.Val())
+			}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	grpcserver "github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/grpc"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/models"
+)
+
+func TestBuildStatsSummary_FlagsStaleDatabasesOffline(t *testing.T) {
+	now := time.Now()
+
+	databases := []*models.Database{
+		{ID: "fresh-healthy", Status: "healthy", Enabled: true, LastSeen: now.Add(-30 * time.Second)},
+		{ID: "fresh-degraded", Status: "degraded", Enabled: true, LastSeen: now.Add(-30 * time.Second)},
+		{ID: "stale", Status: "healthy", Enabled: true, LastSeen: now.Add(-10 * time.Minute)},
+		{ID: "disabled", Status: "healthy", Enabled: false, LastSeen: now.Add(-10 * time.Minute)},
+	}
+
+	summary := grpcserver.BuildStatsSummary(databases, 3*time.Minute, now)
+
+	if summary.TotalDatabases != 4 {
+		t.Errorf("Expected 4 total databases, got %d", summary.TotalDatabases)
+	}
+
+	if summary.HealthyDatabases != 2 {
+		t.Errorf("Expected 2 healthy databases, got %d", summary.HealthyDatabases)
+	}
+
+	if summary.DegradedDatabases != 1 {
+		t.Errorf("Expected 1 degraded database, got %d", summary.DegradedDatabases)
+	}
+
+	if summary.OfflineDatabases != 1 {
+		t.Errorf("Expected 1 offline database, got %d", summary.OfflineDatabases)
+	}
+
+	if len(summary.OfflineDatabaseIds) != 1 || summary.OfflineDatabaseIds[0] != "stale" {
+		t.Errorf("Expected offline database IDs [stale], got %v", summary.OfflineDatabaseIds)
+	}
+}
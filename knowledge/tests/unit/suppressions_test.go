@@ -0,0 +1,98 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	grpcserver "github.com/EricMurray-e-m-dev/StartupMonkey/knowledge/internal/grpc"
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+)
+
+func TestSuppressDetectionKey(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	key := "suppress-db:missing_index:staging_events"
+	defer client.SuppressDetectionKey(ctx, key, 0, "")
+
+	if err := client.SuppressDetectionKey(ctx, key, time.Hour, "nightly batch job scans this table"); err != nil {
+		t.Fatalf("Failed to suppress detection key: %v", err)
+	}
+
+	suppression, err := client.GetSuppression(ctx, key)
+	if err != nil {
+		t.Fatalf("Failed to get suppression: %v", err)
+	}
+	if suppression == nil {
+		t.Fatalf("Expected key to be suppressed")
+	}
+	if suppression.Reason != "nightly batch job scans this table" {
+		t.Errorf("Unexpected reason: %q", suppression.Reason)
+	}
+	if remaining := time.Until(suppression.ExpiresAt); remaining <= 0 || remaining > time.Hour {
+		t.Errorf("Expected expiry within the hour, got %s", remaining)
+	}
+
+	all, err := client.ListSuppressions(ctx)
+	if err != nil {
+		t.Fatalf("Failed to list suppressions: %v", err)
+	}
+	found := false
+	for _, s := range all {
+		found = found || s.Key == key
+	}
+	if !found {
+		t.Errorf("Expected %s in suppression list", key)
+	}
+
+	// A zero duration lifts the suppression
+	if err := client.SuppressDetectionKey(ctx, key, 0, ""); err != nil {
+		t.Fatalf("Failed to lift suppression: %v", err)
+	}
+	if suppression, _ := client.GetSuppression(ctx, key); suppression != nil {
+		t.Errorf("Expected suppression to be lifted")
+	}
+}
+
+func TestSuppressionExpires(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	key := "suppress-db:long_running_query:report"
+
+	if err := client.SuppressDetectionKey(ctx, key, time.Second, "report window"); err != nil {
+		t.Fatalf("Failed to suppress detection key: %v", err)
+	}
+
+	time.Sleep(1100 * time.Millisecond)
+
+	suppression, err := client.GetSuppression(ctx, key)
+	if err != nil {
+		t.Fatalf("Failed to get suppression: %v", err)
+	}
+	if suppression != nil {
+		t.Errorf("Expected suppression to expire")
+	}
+}
+
+func TestSuppressDetectionKeyRejectsMalformedKey(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	server := grpcserver.NewKnowledgeServer(client, 5*time.Minute)
+
+	resp, err := server.SuppressDetectionKey(context.Background(), &pb.SuppressDetectionKeyRequest{
+		Key:          "staging_events",
+		DurationSecs: 3600,
+		Reason:       "nightly batch job",
+	})
+	if err != nil {
+		t.Fatalf("SuppressDetectionKey returned an error: %v", err)
+	}
+	if resp.Success {
+		t.Fatalf("Expected a key without database and detector to be rejected")
+	}
+}
@@ -0,0 +1,110 @@
+package unit
+
+import (
+	"context"
+	"testing"
+)
+
+func TestThresholdsGlobalAndPerDatabase(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+
+	defer client.SetThresholds(ctx, "", nil)
+	defer client.SetThresholds(ctx, "threshold-replica", nil)
+	defer client.SetThresholds(ctx, "threshold-primary", nil)
+
+	if err := client.SetThresholds(ctx, "", map[string]float64{"sequential_scan_threshold": 100}); err != nil {
+		t.Fatalf("Failed to set global thresholds: %v", err)
+	}
+	if err := client.SetThresholds(ctx, "threshold-replica", map[string]float64{"sequential_scan_threshold": 5000}); err != nil {
+		t.Fatalf("Failed to set replica thresholds: %v", err)
+	}
+	if err := client.SetThresholds(ctx, "threshold-primary", map[string]float64{"p95_latency_ms": 200}); err != nil {
+		t.Fatalf("Failed to set primary thresholds: %v", err)
+	}
+
+	// Scoped to one database: the global default plus that database's set only
+	sets, err := client.GetThresholds(ctx, "threshold-replica")
+	if err != nil {
+		t.Fatalf("Failed to get thresholds: %v", err)
+	}
+	if len(sets) != 2 {
+		t.Fatalf("Expected global and replica sets, got %d", len(sets))
+	}
+
+	scoped := make(map[string]float64)
+	for _, set := range sets {
+		if set.DatabaseID == "threshold-primary" {
+			t.Errorf("Primary set should not be returned for the replica")
+		}
+		if set.UpdatedAt.IsZero() {
+			t.Errorf("Expected UpdatedAt to be set for %q", set.DatabaseID)
+		}
+		scoped[set.DatabaseID] = set.Values["sequential_scan_threshold"]
+	}
+	if scoped[""] != 100 || scoped["threshold-replica"] != 5000 {
+		t.Errorf("Unexpected scoped values: %v", scoped)
+	}
+
+	// Unscoped: every set
+	all, err := client.GetThresholds(ctx, "")
+	if err != nil {
+		t.Fatalf("Failed to get thresholds: %v", err)
+	}
+	found := make(map[string]bool)
+	for _, set := range all {
+		found[set.DatabaseID] = true
+	}
+	for _, id := range []string{"", "threshold-replica", "threshold-primary"} {
+		if !found[id] {
+			t.Errorf("Expected set %q in unscoped result", id)
+		}
+	}
+}
+
+func TestSetThresholdsReplacesAndClears(t *testing.T) {
+	client := setupTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	databaseID := "threshold-replace"
+	defer client.SetThresholds(ctx, databaseID, nil)
+
+	if err := client.SetThresholds(ctx, databaseID, map[string]float64{"p95_latency_ms": 200, "table_bloat": 0.2}); err != nil {
+		t.Fatalf("Failed to set thresholds: %v", err)
+	}
+	if err := client.SetThresholds(ctx, databaseID, map[string]float64{"table_bloat": 0.3}); err != nil {
+		t.Fatalf("Failed to replace thresholds: %v", err)
+	}
+
+	sets, err := client.GetThresholds(ctx, databaseID)
+	if err != nil {
+		t.Fatalf("Failed to get thresholds: %v", err)
+	}
+
+	var values map[string]float64
+	for _, set := range sets {
+		if set.DatabaseID == databaseID {
+			values = set.Values
+		}
+	}
+	if len(values) != 1 || values["table_bloat"] != 0.3 {
+		t.Errorf("Expected the set to be replaced by {table_bloat: 0.3}, got %v", values)
+	}
+
+	if err := client.SetThresholds(ctx, databaseID, nil); err != nil {
+		t.Fatalf("Failed to clear thresholds: %v", err)
+	}
+
+	sets, err = client.GetThresholds(ctx, databaseID)
+	if err != nil {
+		t.Fatalf("Failed to get thresholds: %v", err)
+	}
+	for _, set := range sets {
+		if set.DatabaseID == databaseID {
+			t.Errorf("Expected the set to be cleared, got %v", set.Values)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Package detectionkey builds and parses detection keys. A key names an issue rather than
+// one detection of it: "<database>:<detector>:<identifier>", where the identifier is the
+// index, table, column set or query the issue is about. A detection re-published for the
+// same issue carries the same key, so the Analyser deduplicates on it, Knowledge suppresses
+// it and the Executor uses it to spot an action already pending for the issue.
+package detectionkey
+
+import (
+	"fmt"
+	"strings"
+)
+
+const separator = ":"
+
+// Build joins the parts of a key. Database IDs and detector names must not contain ':';
+// the identifier may.
+func Build(databaseID, detectorName, identifier string) string {
+	return databaseID + separator + detectorName + separator + identifier
+}
+
+// Identifier picks the part of a detection that tells one instance of an issue from another:
+// the index, the table (with its columns), or an explicit identifier or query hash. It falls
+// back to the category, for detectors that only ever report one issue per database.
+func Identifier(actionMetadata, evidence map[string]interface{}, category string) string {
+	if actionMetadata != nil {
+		if index, hasIndex := actionMetadata["index_name"].(string); hasIndex && index != "" {
+			return index
+		}
+
+		if table, hasTable := actionMetadata["table_name"].(string); hasTable {
+			if columns := stringList(actionMetadata["column_names"]); len(columns) > 0 {
+				return fmt.Sprintf("%s.%s", table, strings.Join(columns, ","))
+			}
+			if column, hasColumn := actionMetadata["column_name"].(string); hasColumn {
+				return fmt.Sprintf("%s.%s", table, column)
+			}
+			return table
+		}
+
+		if identifier, ok := actionMetadata["identifier"].(string); ok {
+			return identifier
+		}
+	}
+
+	if evidence != nil {
+		if identifier, ok := evidence["identifier"].(string); ok {
+			return identifier
+		}
+
+		if queryHash, ok := evidence["query_hash"].(string); ok {
+			return queryHash
+		}
+	}
+
+	return category
+}
+
+// Parse splits a key into its database, detector and identifier.
+func Parse(key string) (databaseID, detectorName, identifier string, err error) {
+	parts := strings.SplitN(key, separator, 3)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("invalid detection key %q: expected <database>:<detector>:<identifier>", key)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// SameIssue reports whether two keys name the same issue. Empty keys never match.
+func SameIssue(a, b string) bool {
+	return a != "" && a == b
+}
+
+// stringList accepts columns as built by a detector ([]string) or decoded from JSON ([]interface{}).
+func stringList(value interface{}) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil
+			}
+			list = append(list, s)
+		}
+		return list
+	default:
+		return nil
+	}
+}
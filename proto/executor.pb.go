@@ -156,16 +156,22 @@ func (x *ActionStatusRequest) GetActionId() string {
 }
 
 type ActionStatusResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ActionId      string                 `protobuf:"bytes,1,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
-	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // "queued", "executing", "completed", "failed"
-	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
-	ActionType    string                 `protobuf:"bytes,4,opt,name=action_type,json=actionType,proto3" json:"action_type,omitempty"`
-	DatabaseId    string                 `protobuf:"bytes,5,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
-	CreatedAt     int64                  `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	CompletedAt   int64                  `protobuf:"varint,7,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ActionId        string                 `protobuf:"bytes,1,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
+	Status          string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // "queued", "executing", "completed", "failed"
+	Message         string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	ActionType      string                 `protobuf:"bytes,4,opt,name=action_type,json=actionType,proto3" json:"action_type,omitempty"`
+	DatabaseId      string                 `protobuf:"bytes,5,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
+	CreatedAt       int64                  `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	CompletedAt     int64                  `protobuf:"varint,7,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	DetectionId     string                 `protobuf:"bytes,8,opt,name=detection_id,json=detectionId,proto3" json:"detection_id,omitempty"`
+	Error           string                 `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
+	CanRollback     bool                   `protobuf:"varint,10,opt,name=can_rollback,json=canRollback,proto3" json:"can_rollback,omitempty"`
+	RollbackError   string                 `protobuf:"bytes,11,opt,name=rollback_error,json=rollbackError,proto3" json:"rollback_error,omitempty"`
+	ExecutionTimeMs int64                  `protobuf:"varint,12,opt,name=execution_time_ms,json=executionTimeMs,proto3" json:"execution_time_ms,omitempty"`
+	QueuePosition   int32                  `protobuf:"varint,13,opt,name=queue_position,json=queuePosition,proto3" json:"queue_position,omitempty"` // 1-based position while queued
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *ActionStatusResponse) Reset() {
@@ -247,6 +253,48 @@ func (x *ActionStatusResponse) GetCompletedAt() int64 {
 	return 0
 }
 
+func (x *ActionStatusResponse) GetDetectionId() string {
+	if x != nil {
+		return x.DetectionId
+	}
+	return ""
+}
+
+func (x *ActionStatusResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ActionStatusResponse) GetCanRollback() bool {
+	if x != nil {
+		return x.CanRollback
+	}
+	return false
+}
+
+func (x *ActionStatusResponse) GetRollbackError() string {
+	if x != nil {
+		return x.RollbackError
+	}
+	return ""
+}
+
+func (x *ActionStatusResponse) GetExecutionTimeMs() int64 {
+	if x != nil {
+		return x.ExecutionTimeMs
+	}
+	return 0
+}
+
+func (x *ActionStatusResponse) GetQueuePosition() int32 {
+	if x != nil {
+		return x.QueuePosition
+	}
+	return 0
+}
+
 // List pending actions
 type ListRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -292,17 +340,78 @@ func (x *ListRequest) GetStatusFilter() string {
 	return ""
 }
 
+type ListActionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StatusFilter  string                 `protobuf:"bytes,1,opt,name=status_filter,json=statusFilter,proto3" json:"status_filter,omitempty"` // Optional: "queued", "completed", etc.
+	Cursor        string                 `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`                                 // next_cursor from the previous page; empty for the first page
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`                                  // Page size; 0 returns all actions
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListActionsRequest) Reset() {
+	*x = ListActionsRequest{}
+	mi := &file_executor_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListActionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListActionsRequest) ProtoMessage() {}
+
+func (x *ListActionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_executor_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListActionsRequest.ProtoReflect.Descriptor instead.
+func (*ListActionsRequest) Descriptor() ([]byte, []int) {
+	return file_executor_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListActionsRequest) GetStatusFilter() string {
+	if x != nil {
+		return x.StatusFilter
+	}
+	return ""
+}
+
+func (x *ListActionsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListActionsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
 type ActionList struct {
 	state         protoimpl.MessageState  `protogen:"open.v1"`
 	Actions       []*ActionStatusResponse `protobuf:"bytes,1,rep,name=actions,proto3" json:"actions,omitempty"`
-	TotalCount    int32                   `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	TotalCount    int32                   `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"` // Matching actions across all pages
+	NextCursor    string                  `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`  // Empty when there are no more pages
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ActionList) Reset() {
 	*x = ActionList{}
-	mi := &file_executor_proto_msgTypes[5]
+	mi := &file_executor_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -314,7 +423,7 @@ func (x *ActionList) String() string {
 func (*ActionList) ProtoMessage() {}
 
 func (x *ActionList) ProtoReflect() protoreflect.Message {
-	mi := &file_executor_proto_msgTypes[5]
+	mi := &file_executor_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -327,7 +436,7 @@ func (x *ActionList) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ActionList.ProtoReflect.Descriptor instead.
 func (*ActionList) Descriptor() ([]byte, []int) {
-	return file_executor_proto_rawDescGZIP(), []int{5}
+	return file_executor_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *ActionList) GetActions() []*ActionStatusResponse {
@@ -344,6 +453,57 @@ func (x *ActionList) GetTotalCount() int32 {
 	return 0
 }
 
+func (x *ActionList) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type RollbackActionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ActionId      string                 `protobuf:"bytes,1,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RollbackActionRequest) Reset() {
+	*x = RollbackActionRequest{}
+	mi := &file_executor_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollbackActionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackActionRequest) ProtoMessage() {}
+
+func (x *RollbackActionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_executor_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollbackActionRequest.ProtoReflect.Descriptor instead.
+func (*RollbackActionRequest) Descriptor() ([]byte, []int) {
+	return file_executor_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RollbackActionRequest) GetActionId() string {
+	if x != nil {
+		return x.ActionId
+	}
+	return ""
+}
+
 var File_executor_proto protoreflect.FileDescriptor
 
 const file_executor_proto_rawDesc = "" +
@@ -354,7 +514,7 @@ const file_executor_proto_rawDesc = "" +
 	"\x06status\x18\x01 \x01(\tR\x06status\x12%\n" +
 	"\x0euptime_seconds\x18\x02 \x01(\x03R\ruptimeSeconds\"2\n" +
 	"\x13ActionStatusRequest\x12\x1b\n" +
-	"\taction_id\x18\x01 \x01(\tR\bactionId\"\xe9\x01\n" +
+	"\taction_id\x18\x01 \x01(\tR\bactionId\"\xbf\x03\n" +
 	"\x14ActionStatusResponse\x12\x1b\n" +
 	"\taction_id\x18\x01 \x01(\tR\bactionId\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\tR\x06status\x12\x18\n" +
@@ -365,18 +525,35 @@ const file_executor_proto_rawDesc = "" +
 	"databaseId\x12\x1d\n" +
 	"\n" +
 	"created_at\x18\x06 \x01(\x03R\tcreatedAt\x12!\n" +
-	"\fcompleted_at\x18\a \x01(\x03R\vcompletedAt\"2\n" +
+	"\fcompleted_at\x18\a \x01(\x03R\vcompletedAt\x12!\n" +
+	"\fdetection_id\x18\b \x01(\tR\vdetectionId\x12\x14\n" +
+	"\x05error\x18\t \x01(\tR\x05error\x12!\n" +
+	"\fcan_rollback\x18\n" +
+	" \x01(\bR\vcanRollback\x12%\n" +
+	"\x0erollback_error\x18\v \x01(\tR\rrollbackError\x12*\n" +
+	"\x11execution_time_ms\x18\f \x01(\x03R\x0fexecutionTimeMs\x12%\n" +
+	"\x0equeue_position\x18\r \x01(\x05R\rqueuePosition\"2\n" +
 	"\vListRequest\x12#\n" +
 	"\rstatus_filter\x18\x01 \x01(\tR\fstatusFilter\"g\n" +
+	"\x12ListActionsRequest\x12#\n" +
+	"\rstatus_filter\x18\x01 \x01(\tR\fstatusFilter\x12\x16\n" +
+	"\x06cursor\x18\x02 \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"\x88\x01\n" +
 	"\n" +
 	"ActionList\x128\n" +
 	"\aactions\x18\x01 \x03(\v2\x1e.executor.ActionStatusResponseR\aactions\x12\x1f\n" +
 	"\vtotal_count\x18\x02 \x01(\x05R\n" +
-	"totalCount2\xe8\x01\n" +
+	"totalCount\x12\x1f\n" +
+	"\vnext_cursor\x18\x03 \x01(\tR\n" +
+	"nextCursor\"4\n" +
+	"\x15RollbackActionRequest\x12\x1b\n" +
+	"\taction_id\x18\x01 \x01(\tR\bactionId2\xfe\x02\n" +
 	"\x0fExecutorService\x12@\n" +
 	"\vHealthCheck\x12\x17.executor.HealthRequest\x1a\x18.executor.HealthResponse\x12P\n" +
 	"\x0fGetActionStatus\x12\x1d.executor.ActionStatusRequest\x1a\x1e.executor.ActionStatusResponse\x12A\n" +
-	"\x12ListPendingActions\x12\x15.executor.ListRequest\x1a\x14.executor.ActionListB3Z1github.com/EricMurray-e-m-dev/StartupMonkey/protob\x06proto3"
+	"\x12ListPendingActions\x12\x15.executor.ListRequest\x1a\x14.executor.ActionList\x12A\n" +
+	"\vListActions\x12\x1c.executor.ListActionsRequest\x1a\x14.executor.ActionList\x12Q\n" +
+	"\x0eRollbackAction\x12\x1f.executor.RollbackActionRequest\x1a\x1e.executor.ActionStatusResponseB3Z1github.com/EricMurray-e-m-dev/StartupMonkey/protob\x06proto3"
 
 var (
 	file_executor_proto_rawDescOnce sync.Once
@@ -390,25 +567,31 @@ func file_executor_proto_rawDescGZIP() []byte {
 	return file_executor_proto_rawDescData
 }
 
-var file_executor_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_executor_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_executor_proto_goTypes = []any{
-	(*HealthRequest)(nil),        // 0: executor.HealthRequest
-	(*HealthResponse)(nil),       // 1: executor.HealthResponse
-	(*ActionStatusRequest)(nil),  // 2: executor.ActionStatusRequest
-	(*ActionStatusResponse)(nil), // 3: executor.ActionStatusResponse
-	(*ListRequest)(nil),          // 4: executor.ListRequest
-	(*ActionList)(nil),           // 5: executor.ActionList
+	(*HealthRequest)(nil),         // 0: executor.HealthRequest
+	(*HealthResponse)(nil),        // 1: executor.HealthResponse
+	(*ActionStatusRequest)(nil),   // 2: executor.ActionStatusRequest
+	(*ActionStatusResponse)(nil),  // 3: executor.ActionStatusResponse
+	(*ListRequest)(nil),           // 4: executor.ListRequest
+	(*ListActionsRequest)(nil),    // 5: executor.ListActionsRequest
+	(*ActionList)(nil),            // 6: executor.ActionList
+	(*RollbackActionRequest)(nil), // 7: executor.RollbackActionRequest
 }
 var file_executor_proto_depIdxs = []int32{
 	3, // 0: executor.ActionList.actions:type_name -> executor.ActionStatusResponse
 	0, // 1: executor.ExecutorService.HealthCheck:input_type -> executor.HealthRequest
 	2, // 2: executor.ExecutorService.GetActionStatus:input_type -> executor.ActionStatusRequest
 	4, // 3: executor.ExecutorService.ListPendingActions:input_type -> executor.ListRequest
-	1, // 4: executor.ExecutorService.HealthCheck:output_type -> executor.HealthResponse
-	3, // 5: executor.ExecutorService.GetActionStatus:output_type -> executor.ActionStatusResponse
-	5, // 6: executor.ExecutorService.ListPendingActions:output_type -> executor.ActionList
-	4, // [4:7] is the sub-list for method output_type
-	1, // [1:4] is the sub-list for method input_type
+	5, // 4: executor.ExecutorService.ListActions:input_type -> executor.ListActionsRequest
+	7, // 5: executor.ExecutorService.RollbackAction:input_type -> executor.RollbackActionRequest
+	1, // 6: executor.ExecutorService.HealthCheck:output_type -> executor.HealthResponse
+	3, // 7: executor.ExecutorService.GetActionStatus:output_type -> executor.ActionStatusResponse
+	6, // 8: executor.ExecutorService.ListPendingActions:output_type -> executor.ActionList
+	6, // 9: executor.ExecutorService.ListActions:output_type -> executor.ActionList
+	3, // 10: executor.ExecutorService.RollbackAction:output_type -> executor.ActionStatusResponse
+	6, // [6:11] is the sub-list for method output_type
+	1, // [1:6] is the sub-list for method input_type
 	1, // [1:1] is the sub-list for extension type_name
 	1, // [1:1] is the sub-list for extension extendee
 	0, // [0:1] is the sub-list for field type_name
@@ -425,7 +608,7 @@ func file_executor_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_executor_proto_rawDesc), len(file_executor_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -22,6 +22,8 @@ const (
 	ExecutorService_HealthCheck_FullMethodName        = "/executor.ExecutorService/HealthCheck"
 	ExecutorService_GetActionStatus_FullMethodName    = "/executor.ExecutorService/GetActionStatus"
 	ExecutorService_ListPendingActions_FullMethodName = "/executor.ExecutorService/ListPendingActions"
+	ExecutorService_ListActions_FullMethodName        = "/executor.ExecutorService/ListActions"
+	ExecutorService_RollbackAction_FullMethodName     = "/executor.ExecutorService/RollbackAction"
 )
 
 // ExecutorServiceClient is the client API for ExecutorService service.
@@ -36,6 +38,10 @@ type ExecutorServiceClient interface {
 	GetActionStatus(ctx context.Context, in *ActionStatusRequest, opts ...grpc.CallOption) (*ActionStatusResponse, error)
 	// List all pending actions (Dashboard uses this)
 	ListPendingActions(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ActionList, error)
+	// List actions, optionally filtered by status, a page at a time
+	ListActions(ctx context.Context, in *ListActionsRequest, opts ...grpc.CallOption) (*ActionList, error)
+	// Roll back a completed action (same as POST /api/actions/{id}/rollback)
+	RollbackAction(ctx context.Context, in *RollbackActionRequest, opts ...grpc.CallOption) (*ActionStatusResponse, error)
 }
 
 type executorServiceClient struct {
@@ -76,6 +82,26 @@ func (c *executorServiceClient) ListPendingActions(ctx context.Context, in *List
 	return out, nil
 }
 
+func (c *executorServiceClient) ListActions(ctx context.Context, in *ListActionsRequest, opts ...grpc.CallOption) (*ActionList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ActionList)
+	err := c.cc.Invoke(ctx, ExecutorService_ListActions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *executorServiceClient) RollbackAction(ctx context.Context, in *RollbackActionRequest, opts ...grpc.CallOption) (*ActionStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ActionStatusResponse)
+	err := c.cc.Invoke(ctx, ExecutorService_RollbackAction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ExecutorServiceServer is the server API for ExecutorService service.
 // All implementations must embed UnimplementedExecutorServiceServer
 // for forward compatibility.
@@ -88,6 +114,10 @@ type ExecutorServiceServer interface {
 	GetActionStatus(context.Context, *ActionStatusRequest) (*ActionStatusResponse, error)
 	// List all pending actions (Dashboard uses this)
 	ListPendingActions(context.Context, *ListRequest) (*ActionList, error)
+	// List actions, optionally filtered by status, a page at a time
+	ListActions(context.Context, *ListActionsRequest) (*ActionList, error)
+	// Roll back a completed action (same as POST /api/actions/{id}/rollback)
+	RollbackAction(context.Context, *RollbackActionRequest) (*ActionStatusResponse, error)
 	mustEmbedUnimplementedExecutorServiceServer()
 }
 
@@ -107,6 +137,12 @@ func (UnimplementedExecutorServiceServer) GetActionStatus(context.Context, *Acti
 func (UnimplementedExecutorServiceServer) ListPendingActions(context.Context, *ListRequest) (*ActionList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListPendingActions not implemented")
 }
+func (UnimplementedExecutorServiceServer) ListActions(context.Context, *ListActionsRequest) (*ActionList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListActions not implemented")
+}
+func (UnimplementedExecutorServiceServer) RollbackAction(context.Context, *RollbackActionRequest) (*ActionStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RollbackAction not implemented")
+}
 func (UnimplementedExecutorServiceServer) mustEmbedUnimplementedExecutorServiceServer() {}
 func (UnimplementedExecutorServiceServer) testEmbeddedByValue()                         {}
 
@@ -182,6 +218,42 @@ func _ExecutorService_ListPendingActions_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ExecutorService_ListActions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListActionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExecutorServiceServer).ListActions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExecutorService_ListActions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExecutorServiceServer).ListActions(ctx, req.(*ListActionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExecutorService_RollbackAction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExecutorServiceServer).RollbackAction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExecutorService_RollbackAction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExecutorServiceServer).RollbackAction(ctx, req.(*RollbackActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ExecutorService_ServiceDesc is the grpc.ServiceDesc for ExecutorService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -201,6 +273,14 @@ var ExecutorService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListPendingActions",
 			Handler:    _ExecutorService_ListPendingActions_Handler,
 		},
+		{
+			MethodName: "ListActions",
+			Handler:    _ExecutorService_ListActions_Handler,
+		},
+		{
+			MethodName: "RollbackAction",
+			Handler:    _ExecutorService_RollbackAction_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "executor.proto",
@@ -0,0 +1,205 @@
+// Package health serves the /health endpoint shared by the StartupMonkey services, and
+// /metrics when the service passes a metrics handler.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// checkTimeout bounds each dependency check so a hung dependency can't hang /health.
+const checkTimeout = 2 * time.Second
+
+// Check reports whether a dependency is reachable; nil means it is.
+type Check func(ctx context.Context) error
+
+// Extension adds service-specific state to /health. It receives the standard response,
+// may change its status, and returns the value to encode, usually a struct embedding it.
+type Extension func(response *HealthResponse) interface{}
+
+// DependencyStatus is one dependency's state in the health response.
+type DependencyStatus struct {
+	Status   string `json:"status"` // "up" or "down"
+	Critical bool   `json:"critical"`
+	Error    string `json:"error,omitempty"`
+}
+
+// HealthResponse represents the JSON response from the health endpoint.
+type HealthResponse struct {
+	Status        string                      `json:"status"` // healthy, degraded or unhealthy
+	Service       string                      `json:"service"`
+	UptimeSeconds int64                       `json:"uptime_seconds"`
+	Timestamp     int64                       `json:"timestamp"`
+	Dependencies  map[string]DependencyStatus `json:"dependencies,omitempty"`
+}
+
+type dependency struct {
+	name     string
+	critical bool
+	check    Check
+}
+
+// Server serves /health and, given a metrics handler, /metrics. A critical dependency
+// being down reports the service unhealthy with a 503; any other dependency being down
+// reports it degraded.
+type Server struct {
+	service   string
+	port      string
+	metrics   http.Handler
+	startTime time.Time
+
+	mu           sync.RWMutex
+	dependencies []dependency
+	extension    Extension
+
+	server   *http.Server
+	listener net.Listener
+}
+
+// NewServer creates a health server for the service on the given port ("0" picks a free
+// one). metrics is served on /metrics; nil leaves /metrics unserved.
+func NewServer(service, port string, metrics http.Handler) *Server {
+	return &Server{
+		service:   service,
+		port:      port,
+		metrics:   metrics,
+		startTime: time.Now(),
+	}
+}
+
+// AddCheck registers a dependency reported on /health.
+func (s *Server) AddCheck(name string, critical bool, check Check) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.dependencies = append(s.dependencies, dependency{name: name, critical: critical, check: check})
+}
+
+// SetExtension registers the service's additions to the /health response.
+func (s *Server) SetExtension(extension Extension) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.extension = extension
+}
+
+// Start binds the port and serves in the background. A port that can't be bound is
+// returned as an error rather than leaving the service without a health endpoint.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", ":"+s.port)
+	if err != nil {
+		return fmt.Errorf("health server failed to listen on port %s: %w", s.port, err)
+	}
+	s.listener = listener
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", s.healthHandler)
+	if s.metrics != nil {
+		mux.Handle("/metrics", s.metrics)
+	}
+	s.server = &http.Server{Handler: mux}
+
+	log.Printf("Health check listening on %s", listener.Addr())
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("Health server stopped: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// Addr returns the address the server listens on, or "" before Start.
+func (s *Server) Addr() string {
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+// Shutdown stops the server, waiting for in-flight requests until ctx is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.server == nil {
+		return nil
+	}
+	return s.server.Shutdown(ctx)
+}
+
+// Check runs every dependency check and builds the health response.
+func (s *Server) Check(ctx context.Context) *HealthResponse {
+	s.mu.RLock()
+	dependencies := append([]dependency(nil), s.dependencies...)
+	s.mu.RUnlock()
+
+	response := &HealthResponse{
+		Status:        "healthy",
+		Service:       s.service,
+		UptimeSeconds: int64(time.Since(s.startTime).Seconds()),
+		Timestamp:     time.Now().Unix(),
+	}
+	if len(dependencies) == 0 {
+		return response
+	}
+
+	statuses := make([]DependencyStatus, len(dependencies))
+	var wg sync.WaitGroup
+	for i, dep := range dependencies {
+		wg.Add(1)
+		go func(i int, dep dependency) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+			defer cancel()
+
+			statuses[i] = DependencyStatus{Status: "up", Critical: dep.critical}
+			if err := dep.check(checkCtx); err != nil {
+				statuses[i].Status = "down"
+				statuses[i].Error = err.Error()
+			}
+		}(i, dep)
+	}
+	wg.Wait()
+
+	response.Dependencies = make(map[string]DependencyStatus, len(dependencies))
+	for i, dep := range dependencies {
+		response.Dependencies[dep.name] = statuses[i]
+		if statuses[i].Status == "up" {
+			continue
+		}
+		if dep.critical {
+			response.Status = "unhealthy"
+		} else if response.Status == "healthy" {
+			response.Status = "degraded"
+		}
+	}
+
+	return response
+}
+
+func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
+	response := s.Check(r.Context())
+
+	s.mu.RLock()
+	extension := s.extension
+	s.mu.RUnlock()
+
+	var body interface{} = response
+	if extension != nil {
+		body = extension(response)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if response.Status == "unhealthy" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+	json.NewEncoder(w).Encode(body)
+}
@@ -23,16 +23,23 @@ const (
 
 // Detection messages
 type RegisterDetectionRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
-	Severity      string                 `protobuf:"bytes,3,opt,name=severity,proto3" json:"severity,omitempty"`
-	Category      string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
-	DatabaseId    string                 `protobuf:"bytes,5,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
-	Value         float64                `protobuf:"fixed64,6,opt,name=value,proto3" json:"value,omitempty"`
-	CreatedAt     int64                  `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Key        string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Severity   string                 `protobuf:"bytes,3,opt,name=severity,proto3" json:"severity,omitempty"`
+	Category   string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
+	DatabaseId string                 `protobuf:"bytes,5,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
+	Value      float64                `protobuf:"fixed64,6,opt,name=value,proto3" json:"value,omitempty"`
+	CreatedAt  int64                  `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// What the detection says, as published by the Analyser
+	Title          string `protobuf:"bytes,8,opt,name=title,proto3" json:"title,omitempty"`
+	Description    string `protobuf:"bytes,9,opt,name=description,proto3" json:"description,omitempty"`
+	Recommendation string `protobuf:"bytes,10,opt,name=recommendation,proto3" json:"recommendation,omitempty"`
+	ActionType     string `protobuf:"bytes,11,opt,name=action_type,json=actionType,proto3" json:"action_type,omitempty"`
+	Evidence       string `protobuf:"bytes,12,opt,name=evidence,proto3" json:"evidence,omitempty"`                                   // JSON-encoded evidence
+	ActionMetadata string `protobuf:"bytes,13,opt,name=action_metadata,json=actionMetadata,proto3" json:"action_metadata,omitempty"` // JSON-encoded parameters for the action
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *RegisterDetectionRequest) Reset() {
@@ -114,9 +121,53 @@ func (x *RegisterDetectionRequest) GetCreatedAt() int64 {
 	return 0
 }
 
+func (x *RegisterDetectionRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *RegisterDetectionRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *RegisterDetectionRequest) GetRecommendation() string {
+	if x != nil {
+		return x.Recommendation
+	}
+	return ""
+}
+
+func (x *RegisterDetectionRequest) GetActionType() string {
+	if x != nil {
+		return x.ActionType
+	}
+	return ""
+}
+
+func (x *RegisterDetectionRequest) GetEvidence() string {
+	if x != nil {
+		return x.Evidence
+	}
+	return ""
+}
+
+func (x *RegisterDetectionRequest) GetActionMetadata() string {
+	if x != nil {
+		return x.ActionMetadata
+	}
+	return ""
+}
+
 type DetectionKeyRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Severity      string                 `protobuf:"bytes,2,opt,name=severity,proto3" json:"severity,omitempty"` // Current severity as seen by the caller (optional)
+	Value         float64                `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`     // Current value as seen by the caller (optional)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -158,12 +209,30 @@ func (x *DetectionKeyRequest) GetKey() string {
 	return ""
 }
 
+func (x *DetectionKeyRequest) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *DetectionKeyRequest) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
 type DetectionStatusResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	IsActive      bool                   `protobuf:"varint,1,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
-	DetectionId   string                 `protobuf:"bytes,2,opt,name=detection_id,json=detectionId,proto3" json:"detection_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	IsActive          bool                   `protobuf:"varint,1,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	DetectionId       string                 `protobuf:"bytes,2,opt,name=detection_id,json=detectionId,proto3" json:"detection_id,omitempty"`
+	Severity          string                 `protobuf:"bytes,3,opt,name=severity,proto3" json:"severity,omitempty"`      // Stored severity of the active detection
+	Value             float64                `protobuf:"fixed64,4,opt,name=value,proto3" json:"value,omitempty"`          // Stored value of the active detection
+	Suppressed        bool                   `protobuf:"varint,5,opt,name=suppressed,proto3" json:"suppressed,omitempty"` // The key is snoozed; the caller should not publish it
+	SuppressionReason string                 `protobuf:"bytes,6,opt,name=suppression_reason,json=suppressionReason,proto3" json:"suppression_reason,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *DetectionStatusResponse) Reset() {
@@ -210,6 +279,94 @@ func (x *DetectionStatusResponse) GetDetectionId() string {
 	return ""
 }
 
+func (x *DetectionStatusResponse) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *DetectionStatusResponse) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *DetectionStatusResponse) GetSuppressed() bool {
+	if x != nil {
+		return x.Suppressed
+	}
+	return false
+}
+
+func (x *DetectionStatusResponse) GetSuppressionReason() string {
+	if x != nil {
+		return x.SuppressionReason
+	}
+	return ""
+}
+
+type UpdateDetectionSeverityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DetectionId   string                 `protobuf:"bytes,1,opt,name=detection_id,json=detectionId,proto3" json:"detection_id,omitempty"`
+	Severity      string                 `protobuf:"bytes,2,opt,name=severity,proto3" json:"severity,omitempty"`
+	Value         float64                `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateDetectionSeverityRequest) Reset() {
+	*x = UpdateDetectionSeverityRequest{}
+	mi := &file_knowledge_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDetectionSeverityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDetectionSeverityRequest) ProtoMessage() {}
+
+func (x *UpdateDetectionSeverityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDetectionSeverityRequest.ProtoReflect.Descriptor instead.
+func (*UpdateDetectionSeverityRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UpdateDetectionSeverityRequest) GetDetectionId() string {
+	if x != nil {
+		return x.DetectionId
+	}
+	return ""
+}
+
+func (x *UpdateDetectionSeverityRequest) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *UpdateDetectionSeverityRequest) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
 type DatabaseFilterRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	DatabaseId    string                 `protobuf:"bytes,1,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
@@ -219,7 +376,7 @@ type DatabaseFilterRequest struct {
 
 func (x *DatabaseFilterRequest) Reset() {
 	*x = DatabaseFilterRequest{}
-	mi := &file_knowledge_proto_msgTypes[3]
+	mi := &file_knowledge_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -231,7 +388,7 @@ func (x *DatabaseFilterRequest) String() string {
 func (*DatabaseFilterRequest) ProtoMessage() {}
 
 func (x *DatabaseFilterRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[3]
+	mi := &file_knowledge_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -244,7 +401,7 @@ func (x *DatabaseFilterRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DatabaseFilterRequest.ProtoReflect.Descriptor instead.
 func (*DatabaseFilterRequest) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{3}
+	return file_knowledge_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *DatabaseFilterRequest) GetDatabaseId() string {
@@ -265,7 +422,7 @@ type DetectionResponse struct {
 
 func (x *DetectionResponse) Reset() {
 	*x = DetectionResponse{}
-	mi := &file_knowledge_proto_msgTypes[4]
+	mi := &file_knowledge_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -277,7 +434,7 @@ func (x *DetectionResponse) String() string {
 func (*DetectionResponse) ProtoMessage() {}
 
 func (x *DetectionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[4]
+	mi := &file_knowledge_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -290,7 +447,7 @@ func (x *DetectionResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DetectionResponse.ProtoReflect.Descriptor instead.
 func (*DetectionResponse) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{4}
+	return file_knowledge_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *DetectionResponse) GetSuccess() bool {
@@ -314,6 +471,74 @@ func (x *DetectionResponse) GetDetectionId() string {
 	return ""
 }
 
+type RegisterDetectionIfAbsentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Registered    bool                   `protobuf:"varint,3,opt,name=registered,proto3" json:"registered,omitempty"`                     // The caller's detection was stored; false if one with its key was already active
+	DetectionId   string                 `protobuf:"bytes,4,opt,name=detection_id,json=detectionId,proto3" json:"detection_id,omitempty"` // The stored detection's ID, or the already active one's
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterDetectionIfAbsentResponse) Reset() {
+	*x = RegisterDetectionIfAbsentResponse{}
+	mi := &file_knowledge_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterDetectionIfAbsentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterDetectionIfAbsentResponse) ProtoMessage() {}
+
+func (x *RegisterDetectionIfAbsentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterDetectionIfAbsentResponse.ProtoReflect.Descriptor instead.
+func (*RegisterDetectionIfAbsentResponse) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RegisterDetectionIfAbsentResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RegisterDetectionIfAbsentResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RegisterDetectionIfAbsentResponse) GetRegistered() bool {
+	if x != nil {
+		return x.Registered
+	}
+	return false
+}
+
+func (x *RegisterDetectionIfAbsentResponse) GetDetectionId() string {
+	if x != nil {
+		return x.DetectionId
+	}
+	return ""
+}
+
 type DetectionListResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Detections    []*Detection           `protobuf:"bytes,1,rep,name=detections,proto3" json:"detections,omitempty"`
@@ -323,7 +548,7 @@ type DetectionListResponse struct {
 
 func (x *DetectionListResponse) Reset() {
 	*x = DetectionListResponse{}
-	mi := &file_knowledge_proto_msgTypes[5]
+	mi := &file_knowledge_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -335,7 +560,7 @@ func (x *DetectionListResponse) String() string {
 func (*DetectionListResponse) ProtoMessage() {}
 
 func (x *DetectionListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[5]
+	mi := &file_knowledge_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -348,7 +573,7 @@ func (x *DetectionListResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DetectionListResponse.ProtoReflect.Descriptor instead.
 func (*DetectionListResponse) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{5}
+	return file_knowledge_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *DetectionListResponse) GetDetections() []*Detection {
@@ -359,25 +584,35 @@ func (x *DetectionListResponse) GetDetections() []*Detection {
 }
 
 type Detection struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
-	State         string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
-	Severity      string                 `protobuf:"bytes,4,opt,name=severity,proto3" json:"severity,omitempty"`
-	Category      string                 `protobuf:"bytes,5,opt,name=category,proto3" json:"category,omitempty"`
-	DatabaseId    string                 `protobuf:"bytes,6,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
-	Value         float64                `protobuf:"fixed64,7,opt,name=value,proto3" json:"value,omitempty"`
-	ActionId      string                 `protobuf:"bytes,8,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
-	ResolvedBy    string                 `protobuf:"bytes,9,opt,name=resolved_by,json=resolvedBy,proto3" json:"resolved_by,omitempty"`
-	CreatedAt     int64                  `protobuf:"varint,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	LastSeen      int64                  `protobuf:"varint,11,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Key               string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	State             string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	Severity          string                 `protobuf:"bytes,4,opt,name=severity,proto3" json:"severity,omitempty"`
+	Category          string                 `protobuf:"bytes,5,opt,name=category,proto3" json:"category,omitempty"`
+	DatabaseId        string                 `protobuf:"bytes,6,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
+	Value             float64                `protobuf:"fixed64,7,opt,name=value,proto3" json:"value,omitempty"`
+	ActionId          string                 `protobuf:"bytes,8,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
+	ResolvedBy        string                 `protobuf:"bytes,9,opt,name=resolved_by,json=resolvedBy,proto3" json:"resolved_by,omitempty"`
+	CreatedAt         int64                  `protobuf:"varint,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LastSeen          int64                  `protobuf:"varint,11,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	FailureReason     string                 `protobuf:"bytes,12,opt,name=failure_reason,json=failureReason,proto3" json:"failure_reason,omitempty"`
+	SuppressionReason string                 `protobuf:"bytes,13,opt,name=suppression_reason,json=suppressionReason,proto3" json:"suppression_reason,omitempty"` // Set while the detection's key is suppressed
+	SuppressedUntil   int64                  `protobuf:"varint,14,opt,name=suppressed_until,json=suppressedUntil,proto3" json:"suppressed_until,omitempty"`
+	// Empty for detections registered before Knowledge stored them
+	Title          string `protobuf:"bytes,15,opt,name=title,proto3" json:"title,omitempty"`
+	Description    string `protobuf:"bytes,16,opt,name=description,proto3" json:"description,omitempty"`
+	Recommendation string `protobuf:"bytes,17,opt,name=recommendation,proto3" json:"recommendation,omitempty"`
+	ActionType     string `protobuf:"bytes,18,opt,name=action_type,json=actionType,proto3" json:"action_type,omitempty"`
+	Evidence       string `protobuf:"bytes,19,opt,name=evidence,proto3" json:"evidence,omitempty"`                                   // JSON-encoded evidence
+	ActionMetadata string `protobuf:"bytes,20,opt,name=action_metadata,json=actionMetadata,proto3" json:"action_metadata,omitempty"` // JSON-encoded parameters for the action
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *Detection) Reset() {
 	*x = Detection{}
-	mi := &file_knowledge_proto_msgTypes[6]
+	mi := &file_knowledge_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -389,7 +624,7 @@ func (x *Detection) String() string {
 func (*Detection) ProtoMessage() {}
 
 func (x *Detection) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[6]
+	mi := &file_knowledge_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -402,7 +637,7 @@ func (x *Detection) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Detection.ProtoReflect.Descriptor instead.
 func (*Detection) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{6}
+	return file_knowledge_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *Detection) GetId() string {
@@ -482,6 +717,69 @@ func (x *Detection) GetLastSeen() int64 {
 	return 0
 }
 
+func (x *Detection) GetFailureReason() string {
+	if x != nil {
+		return x.FailureReason
+	}
+	return ""
+}
+
+func (x *Detection) GetSuppressionReason() string {
+	if x != nil {
+		return x.SuppressionReason
+	}
+	return ""
+}
+
+func (x *Detection) GetSuppressedUntil() int64 {
+	if x != nil {
+		return x.SuppressedUntil
+	}
+	return 0
+}
+
+func (x *Detection) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Detection) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Detection) GetRecommendation() string {
+	if x != nil {
+		return x.Recommendation
+	}
+	return ""
+}
+
+func (x *Detection) GetActionType() string {
+	if x != nil {
+		return x.ActionType
+	}
+	return ""
+}
+
+func (x *Detection) GetEvidence() string {
+	if x != nil {
+		return x.Evidence
+	}
+	return ""
+}
+
+func (x *Detection) GetActionMetadata() string {
+	if x != nil {
+		return x.ActionMetadata
+	}
+	return ""
+}
+
 type ResolveDetectionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	DetectionId   string                 `protobuf:"bytes,1,opt,name=detection_id,json=detectionId,proto3" json:"detection_id,omitempty"`
@@ -492,7 +790,7 @@ type ResolveDetectionRequest struct {
 
 func (x *ResolveDetectionRequest) Reset() {
 	*x = ResolveDetectionRequest{}
-	mi := &file_knowledge_proto_msgTypes[7]
+	mi := &file_knowledge_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -504,7 +802,7 @@ func (x *ResolveDetectionRequest) String() string {
 func (*ResolveDetectionRequest) ProtoMessage() {}
 
 func (x *ResolveDetectionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[7]
+	mi := &file_knowledge_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -517,7 +815,7 @@ func (x *ResolveDetectionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ResolveDetectionRequest.ProtoReflect.Descriptor instead.
 func (*ResolveDetectionRequest) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{7}
+	return file_knowledge_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ResolveDetectionRequest) GetDetectionId() string {
@@ -534,21 +832,182 @@ func (x *ResolveDetectionRequest) GetSolution() string {
 	return ""
 }
 
-// Action messages
-type RegisterActionRequest struct {
+type ReopenDetectionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	DetectionId   string                 `protobuf:"bytes,2,opt,name=detection_id,json=detectionId,proto3" json:"detection_id,omitempty"`
-	ActionType    string                 `protobuf:"bytes,3,opt,name=action_type,json=actionType,proto3" json:"action_type,omitempty"`
-	DatabaseId    string                 `protobuf:"bytes,4,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
-	CreatedAt     int64                  `protobuf:"varint,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	DetectionId   string                 `protobuf:"bytes,1,opt,name=detection_id,json=detectionId,proto3" json:"detection_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"` // e.g. "action rolled back"
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RegisterActionRequest) Reset() {
-	*x = RegisterActionRequest{}
-	mi := &file_knowledge_proto_msgTypes[8]
+func (x *ReopenDetectionRequest) Reset() {
+	*x = ReopenDetectionRequest{}
+	mi := &file_knowledge_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReopenDetectionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReopenDetectionRequest) ProtoMessage() {}
+
+func (x *ReopenDetectionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReopenDetectionRequest.ProtoReflect.Descriptor instead.
+func (*ReopenDetectionRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ReopenDetectionRequest) GetDetectionId() string {
+	if x != nil {
+		return x.DetectionId
+	}
+	return ""
+}
+
+func (x *ReopenDetectionRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type UnactionableDetectionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DetectionId   string                 `protobuf:"bytes,1,opt,name=detection_id,json=detectionId,proto3" json:"detection_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnactionableDetectionRequest) Reset() {
+	*x = UnactionableDetectionRequest{}
+	mi := &file_knowledge_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnactionableDetectionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnactionableDetectionRequest) ProtoMessage() {}
+
+func (x *UnactionableDetectionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnactionableDetectionRequest.ProtoReflect.Descriptor instead.
+func (*UnactionableDetectionRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UnactionableDetectionRequest) GetDetectionId() string {
+	if x != nil {
+		return x.DetectionId
+	}
+	return ""
+}
+
+func (x *UnactionableDetectionRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type AcknowledgeDetectionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DetectionId   string                 `protobuf:"bytes,1,opt,name=detection_id,json=detectionId,proto3" json:"detection_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcknowledgeDetectionRequest) Reset() {
+	*x = AcknowledgeDetectionRequest{}
+	mi := &file_knowledge_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcknowledgeDetectionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcknowledgeDetectionRequest) ProtoMessage() {}
+
+func (x *AcknowledgeDetectionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcknowledgeDetectionRequest.ProtoReflect.Descriptor instead.
+func (*AcknowledgeDetectionRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AcknowledgeDetectionRequest) GetDetectionId() string {
+	if x != nil {
+		return x.DetectionId
+	}
+	return ""
+}
+
+func (x *AcknowledgeDetectionRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// Action messages
+type RegisterActionRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DetectionId    string                 `protobuf:"bytes,2,opt,name=detection_id,json=detectionId,proto3" json:"detection_id,omitempty"`
+	ActionType     string                 `protobuf:"bytes,3,opt,name=action_type,json=actionType,proto3" json:"action_type,omitempty"`
+	DatabaseId     string                 `protobuf:"bytes,4,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
+	CreatedAt      int64                  `protobuf:"varint,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Status         string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`                                       // Initial status, defaults to "queued"
+	ActionState    string                 `protobuf:"bytes,7,opt,name=action_state,json=actionState,proto3" json:"action_state,omitempty"`          // JSON-encoded parameters needed to rebuild the action
+	Source         string                 `protobuf:"bytes,8,opt,name=source,proto3" json:"source,omitempty"`                                       // What triggered the action, e.g. "detector:missing_index" or "manual"
+	PolicyDecision string                 `protobuf:"bytes,9,opt,name=policy_decision,json=policyDecision,proto3" json:"policy_decision,omitempty"` // How the execution policy handled the action: auto, approve or notify
+	PolicyRule     string                 `protobuf:"bytes,10,opt,name=policy_rule,json=policyRule,proto3" json:"policy_rule,omitempty"`            // The policy rule that decided it, e.g. "severity:critical=approve"
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RegisterActionRequest) Reset() {
+	*x = RegisterActionRequest{}
+	mi := &file_knowledge_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -560,7 +1019,7 @@ func (x *RegisterActionRequest) String() string {
 func (*RegisterActionRequest) ProtoMessage() {}
 
 func (x *RegisterActionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[8]
+	mi := &file_knowledge_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -573,7 +1032,7 @@ func (x *RegisterActionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterActionRequest.ProtoReflect.Descriptor instead.
 func (*RegisterActionRequest) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{8}
+	return file_knowledge_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *RegisterActionRequest) GetId() string {
@@ -611,6 +1070,41 @@ func (x *RegisterActionRequest) GetCreatedAt() int64 {
 	return 0
 }
 
+func (x *RegisterActionRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *RegisterActionRequest) GetActionState() string {
+	if x != nil {
+		return x.ActionState
+	}
+	return ""
+}
+
+func (x *RegisterActionRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *RegisterActionRequest) GetPolicyDecision() string {
+	if x != nil {
+		return x.PolicyDecision
+	}
+	return ""
+}
+
+func (x *RegisterActionRequest) GetPolicyRule() string {
+	if x != nil {
+		return x.PolicyRule
+	}
+	return ""
+}
+
 type ActionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
@@ -622,7 +1116,7 @@ type ActionResponse struct {
 
 func (x *ActionResponse) Reset() {
 	*x = ActionResponse{}
-	mi := &file_knowledge_proto_msgTypes[9]
+	mi := &file_knowledge_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -634,7 +1128,7 @@ func (x *ActionResponse) String() string {
 func (*ActionResponse) ProtoMessage() {}
 
 func (x *ActionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[9]
+	mi := &file_knowledge_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -647,7 +1141,7 @@ func (x *ActionResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ActionResponse.ProtoReflect.Descriptor instead.
 func (*ActionResponse) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{9}
+	return file_knowledge_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *ActionResponse) GetSuccess() bool {
@@ -678,13 +1172,18 @@ type UpdateActionRequest struct {
 	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
 	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
 	Timestamp     int64                  `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Changes       string                 `protobuf:"bytes,6,opt,name=changes,proto3" json:"changes,omitempty"` // JSON-encoded changes made by the action (index name, container, original config)
+	CanRollback   bool                   `protobuf:"varint,7,opt,name=can_rollback,json=canRollback,proto3" json:"can_rollback,omitempty"`
+	Progress      *ActionProgress        `protobuf:"bytes,8,opt,name=progress,proto3" json:"progress,omitempty"`                     // Optional progress of a long-running action
+	Source        string                 `protobuf:"bytes,9,opt,name=source,proto3" json:"source,omitempty"`                         // What caused this transition; defaults to the action's source
+	ErrorCode     string                 `protobuf:"bytes,10,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"` // Why a failed action failed, e.g. PERMISSION_DENIED or TIMEOUT
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateActionRequest) Reset() {
 	*x = UpdateActionRequest{}
-	mi := &file_knowledge_proto_msgTypes[10]
+	mi := &file_knowledge_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -696,7 +1195,7 @@ func (x *UpdateActionRequest) String() string {
 func (*UpdateActionRequest) ProtoMessage() {}
 
 func (x *UpdateActionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[10]
+	mi := &file_knowledge_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -709,7 +1208,7 @@ func (x *UpdateActionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateActionRequest.ProtoReflect.Descriptor instead.
 func (*UpdateActionRequest) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{10}
+	return file_knowledge_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *UpdateActionRequest) GetActionId() string {
@@ -747,77 +1246,68 @@ func (x *UpdateActionRequest) GetTimestamp() int64 {
 	return 0
 }
 
-type ActionListResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Actions       []*Action              `protobuf:"bytes,1,rep,name=actions,proto3" json:"actions,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *ActionListResponse) Reset() {
-	*x = ActionListResponse{}
-	mi := &file_knowledge_proto_msgTypes[11]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *UpdateActionRequest) GetChanges() string {
+	if x != nil {
+		return x.Changes
+	}
+	return ""
 }
 
-func (x *ActionListResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *UpdateActionRequest) GetCanRollback() bool {
+	if x != nil {
+		return x.CanRollback
+	}
+	return false
 }
 
-func (*ActionListResponse) ProtoMessage() {}
-
-func (x *ActionListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[11]
+func (x *UpdateActionRequest) GetProgress() *ActionProgress {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Progress
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use ActionListResponse.ProtoReflect.Descriptor instead.
-func (*ActionListResponse) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{11}
+func (x *UpdateActionRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
 }
 
-func (x *ActionListResponse) GetActions() []*Action {
+func (x *UpdateActionRequest) GetErrorCode() string {
 	if x != nil {
-		return x.Actions
+		return x.ErrorCode
 	}
-	return nil
+	return ""
 }
 
-type Action struct {
+type ActionProgress struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	DetectionId   string                 `protobuf:"bytes,2,opt,name=detection_id,json=detectionId,proto3" json:"detection_id,omitempty"`
-	ActionType    string                 `protobuf:"bytes,3,opt,name=action_type,json=actionType,proto3" json:"action_type,omitempty"`
-	DatabaseId    string                 `protobuf:"bytes,4,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
-	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
-	CreatedAt     int64                  `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Phase         string                 `protobuf:"bytes,1,opt,name=phase,proto3" json:"phase,omitempty"`
+	BlocksDone    int64                  `protobuf:"varint,2,opt,name=blocks_done,json=blocksDone,proto3" json:"blocks_done,omitempty"`
+	BlocksTotal   int64                  `protobuf:"varint,3,opt,name=blocks_total,json=blocksTotal,proto3" json:"blocks_total,omitempty"`
+	TuplesDone    int64                  `protobuf:"varint,4,opt,name=tuples_done,json=tuplesDone,proto3" json:"tuples_done,omitempty"`
+	TuplesTotal   int64                  `protobuf:"varint,5,opt,name=tuples_total,json=tuplesTotal,proto3" json:"tuples_total,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // Unix timestamp
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Action) Reset() {
-	*x = Action{}
-	mi := &file_knowledge_proto_msgTypes[12]
+func (x *ActionProgress) Reset() {
+	*x = ActionProgress{}
+	mi := &file_knowledge_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Action) String() string {
+func (x *ActionProgress) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Action) ProtoMessage() {}
+func (*ActionProgress) ProtoMessage() {}
 
-func (x *Action) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[12]
+func (x *ActionProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -828,85 +1318,79 @@ func (x *Action) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Action.ProtoReflect.Descriptor instead.
-func (*Action) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use ActionProgress.ProtoReflect.Descriptor instead.
+func (*ActionProgress) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *Action) GetId() string {
+func (x *ActionProgress) GetPhase() string {
 	if x != nil {
-		return x.Id
+		return x.Phase
 	}
 	return ""
 }
 
-func (x *Action) GetDetectionId() string {
+func (x *ActionProgress) GetBlocksDone() int64 {
 	if x != nil {
-		return x.DetectionId
+		return x.BlocksDone
 	}
-	return ""
+	return 0
 }
 
-func (x *Action) GetActionType() string {
+func (x *ActionProgress) GetBlocksTotal() int64 {
 	if x != nil {
-		return x.ActionType
+		return x.BlocksTotal
 	}
-	return ""
+	return 0
 }
 
-func (x *Action) GetDatabaseId() string {
+func (x *ActionProgress) GetTuplesDone() int64 {
 	if x != nil {
-		return x.DatabaseId
+		return x.TuplesDone
 	}
-	return ""
+	return 0
 }
 
-func (x *Action) GetStatus() string {
+func (x *ActionProgress) GetTuplesTotal() int64 {
 	if x != nil {
-		return x.Status
+		return x.TuplesTotal
 	}
-	return ""
+	return 0
 }
 
-func (x *Action) GetCreatedAt() int64 {
+func (x *ActionProgress) GetUpdatedAt() int64 {
 	if x != nil {
-		return x.CreatedAt
+		return x.UpdatedAt
 	}
 	return 0
 }
 
-// Database messages
-type RegisterDatabaseRequest struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	DatabaseId       string                 `protobuf:"bytes,1,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
-	ConnectionString string                 `protobuf:"bytes,2,opt,name=connection_string,json=connectionString,proto3" json:"connection_string,omitempty"`
-	DatabaseType     string                 `protobuf:"bytes,3,opt,name=database_type,json=databaseType,proto3" json:"database_type,omitempty"`
-	DatabaseName     string                 `protobuf:"bytes,4,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
-	Host             string                 `protobuf:"bytes,5,opt,name=host,proto3" json:"host,omitempty"`
-	Port             int32                  `protobuf:"varint,6,opt,name=port,proto3" json:"port,omitempty"`
-	Version          string                 `protobuf:"bytes,7,opt,name=version,proto3" json:"version,omitempty"`
-	RegisteredAt     int64                  `protobuf:"varint,8,opt,name=registered_at,json=registeredAt,proto3" json:"registered_at,omitempty"`
-	Metadata         map[string]string      `protobuf:"bytes,9,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	Enabled          bool                   `protobuf:"varint,10,opt,name=enabled,proto3" json:"enabled,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+type ActionOutcome struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Metric        string                 `protobuf:"bytes,1,opt,name=metric,proto3" json:"metric,omitempty"`                                   // e.g. sequential_scans_per_interval, cache_hit_rate, p95_latency_ms
+	MetricBefore  float64                `protobuf:"fixed64,2,opt,name=metric_before,json=metricBefore,proto3" json:"metric_before,omitempty"` // Value when the detection fired
+	MetricAfter   float64                `protobuf:"fixed64,3,opt,name=metric_after,json=metricAfter,proto3" json:"metric_after,omitempty"`    // Value when the action was verified
+	Verdict       string                 `protobuf:"bytes,4,opt,name=verdict,proto3" json:"verdict,omitempty"`                                 // effective or ineffective
+	RecordedAt    int64                  `protobuf:"varint,5,opt,name=recorded_at,json=recordedAt,proto3" json:"recorded_at,omitempty"`        // Unix timestamp
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RegisterDatabaseRequest) Reset() {
-	*x = RegisterDatabaseRequest{}
-	mi := &file_knowledge_proto_msgTypes[13]
+func (x *ActionOutcome) Reset() {
+	*x = ActionOutcome{}
+	mi := &file_knowledge_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RegisterDatabaseRequest) String() string {
+func (x *ActionOutcome) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RegisterDatabaseRequest) ProtoMessage() {}
+func (*ActionOutcome) ProtoMessage() {}
 
-func (x *RegisterDatabaseRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[13]
+func (x *ActionOutcome) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -917,104 +1401,144 @@ func (x *RegisterDatabaseRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RegisterDatabaseRequest.ProtoReflect.Descriptor instead.
-func (*RegisterDatabaseRequest) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use ActionOutcome.ProtoReflect.Descriptor instead.
+func (*ActionOutcome) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *RegisterDatabaseRequest) GetDatabaseId() string {
+func (x *ActionOutcome) GetMetric() string {
 	if x != nil {
-		return x.DatabaseId
+		return x.Metric
 	}
 	return ""
 }
 
-func (x *RegisterDatabaseRequest) GetConnectionString() string {
+func (x *ActionOutcome) GetMetricBefore() float64 {
 	if x != nil {
-		return x.ConnectionString
+		return x.MetricBefore
 	}
-	return ""
+	return 0
 }
 
-func (x *RegisterDatabaseRequest) GetDatabaseType() string {
+func (x *ActionOutcome) GetMetricAfter() float64 {
 	if x != nil {
-		return x.DatabaseType
+		return x.MetricAfter
 	}
-	return ""
+	return 0
 }
 
-func (x *RegisterDatabaseRequest) GetDatabaseName() string {
+func (x *ActionOutcome) GetVerdict() string {
 	if x != nil {
-		return x.DatabaseName
+		return x.Verdict
 	}
 	return ""
 }
 
-func (x *RegisterDatabaseRequest) GetHost() string {
+func (x *ActionOutcome) GetRecordedAt() int64 {
 	if x != nil {
-		return x.Host
+		return x.RecordedAt
 	}
-	return ""
+	return 0
 }
 
-func (x *RegisterDatabaseRequest) GetPort() int32 {
+type RecordActionOutcomeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ActionId      string                 `protobuf:"bytes,1,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
+	Metric        string                 `protobuf:"bytes,2,opt,name=metric,proto3" json:"metric,omitempty"`
+	MetricBefore  float64                `protobuf:"fixed64,3,opt,name=metric_before,json=metricBefore,proto3" json:"metric_before,omitempty"`
+	MetricAfter   float64                `protobuf:"fixed64,4,opt,name=metric_after,json=metricAfter,proto3" json:"metric_after,omitempty"`
+	Verdict       string                 `protobuf:"bytes,5,opt,name=verdict,proto3" json:"verdict,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordActionOutcomeRequest) Reset() {
+	*x = RecordActionOutcomeRequest{}
+	mi := &file_knowledge_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordActionOutcomeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordActionOutcomeRequest) ProtoMessage() {}
+
+func (x *RecordActionOutcomeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[18]
 	if x != nil {
-		return x.Port
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *RegisterDatabaseRequest) GetVersion() string {
+// Deprecated: Use RecordActionOutcomeRequest.ProtoReflect.Descriptor instead.
+func (*RecordActionOutcomeRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RecordActionOutcomeRequest) GetActionId() string {
 	if x != nil {
-		return x.Version
+		return x.ActionId
 	}
 	return ""
 }
 
-func (x *RegisterDatabaseRequest) GetRegisteredAt() int64 {
+func (x *RecordActionOutcomeRequest) GetMetric() string {
 	if x != nil {
-		return x.RegisteredAt
+		return x.Metric
+	}
+	return ""
+}
+
+func (x *RecordActionOutcomeRequest) GetMetricBefore() float64 {
+	if x != nil {
+		return x.MetricBefore
 	}
 	return 0
 }
 
-func (x *RegisterDatabaseRequest) GetMetadata() map[string]string {
+func (x *RecordActionOutcomeRequest) GetMetricAfter() float64 {
 	if x != nil {
-		return x.Metadata
+		return x.MetricAfter
 	}
-	return nil
+	return 0
 }
 
-func (x *RegisterDatabaseRequest) GetEnabled() bool {
+func (x *RecordActionOutcomeRequest) GetVerdict() string {
 	if x != nil {
-		return x.Enabled
+		return x.Verdict
 	}
-	return false
+	return ""
 }
 
-type DatabaseResponse struct {
+type GetActionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	ActionId      string                 `protobuf:"bytes,1,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DatabaseResponse) Reset() {
-	*x = DatabaseResponse{}
-	mi := &file_knowledge_proto_msgTypes[14]
+func (x *GetActionRequest) Reset() {
+	*x = GetActionRequest{}
+	mi := &file_knowledge_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DatabaseResponse) String() string {
+func (x *GetActionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DatabaseResponse) ProtoMessage() {}
+func (*GetActionRequest) ProtoMessage() {}
 
-func (x *DatabaseResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[14]
+func (x *GetActionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1025,47 +1549,41 @@ func (x *DatabaseResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DatabaseResponse.ProtoReflect.Descriptor instead.
-func (*DatabaseResponse) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{14}
-}
-
-func (x *DatabaseResponse) GetSuccess() bool {
-	if x != nil {
-		return x.Success
-	}
-	return false
+// Deprecated: Use GetActionRequest.ProtoReflect.Descriptor instead.
+func (*GetActionRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *DatabaseResponse) GetMessage() string {
+func (x *GetActionRequest) GetActionId() string {
 	if x != nil {
-		return x.Message
+		return x.ActionId
 	}
 	return ""
 }
 
-type GetDatabaseRequest struct {
+type GetActionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	DatabaseId    string                 `protobuf:"bytes,1,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
+	Found         bool                   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	Action        *Action                `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetDatabaseRequest) Reset() {
-	*x = GetDatabaseRequest{}
-	mi := &file_knowledge_proto_msgTypes[15]
+func (x *GetActionResponse) Reset() {
+	*x = GetActionResponse{}
+	mi := &file_knowledge_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetDatabaseRequest) String() string {
+func (x *GetActionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDatabaseRequest) ProtoMessage() {}
+func (*GetActionResponse) ProtoMessage() {}
 
-func (x *GetDatabaseRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[15]
+func (x *GetActionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1076,53 +1594,47 @@ func (x *GetDatabaseRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDatabaseRequest.ProtoReflect.Descriptor instead.
-func (*GetDatabaseRequest) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use GetActionResponse.ProtoReflect.Descriptor instead.
+func (*GetActionResponse) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *GetDatabaseRequest) GetDatabaseId() string {
+func (x *GetActionResponse) GetFound() bool {
 	if x != nil {
-		return x.DatabaseId
+		return x.Found
 	}
-	return ""
+	return false
 }
 
-type GetDatabaseResponse struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	Found            bool                   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
-	DatabaseId       string                 `protobuf:"bytes,2,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
-	ConnectionString string                 `protobuf:"bytes,3,opt,name=connection_string,json=connectionString,proto3" json:"connection_string,omitempty"`
-	DatabaseType     string                 `protobuf:"bytes,4,opt,name=database_type,json=databaseType,proto3" json:"database_type,omitempty"`
-	DatabaseName     string                 `protobuf:"bytes,5,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
-	Host             string                 `protobuf:"bytes,6,opt,name=host,proto3" json:"host,omitempty"`
-	Port             int32                  `protobuf:"varint,7,opt,name=port,proto3" json:"port,omitempty"`
-	Version          string                 `protobuf:"bytes,8,opt,name=version,proto3" json:"version,omitempty"`
-	RegisteredAt     int64                  `protobuf:"varint,9,opt,name=registered_at,json=registeredAt,proto3" json:"registered_at,omitempty"`
-	LastSeen         int64                  `protobuf:"varint,10,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
-	Status           string                 `protobuf:"bytes,11,opt,name=status,proto3" json:"status,omitempty"`
-	HealthScore      float64                `protobuf:"fixed64,12,opt,name=health_score,json=healthScore,proto3" json:"health_score,omitempty"`
-	Metadata         map[string]string      `protobuf:"bytes,13,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	Enabled          bool                   `protobuf:"varint,14,opt,name=enabled,proto3" json:"enabled,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+func (x *GetActionResponse) GetAction() *Action {
+	if x != nil {
+		return x.Action
+	}
+	return nil
 }
 
-func (x *GetDatabaseResponse) Reset() {
-	*x = GetDatabaseResponse{}
-	mi := &file_knowledge_proto_msgTypes[16]
+type GetActionHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ActionId      string                 `protobuf:"bytes,1,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetActionHistoryRequest) Reset() {
+	*x = GetActionHistoryRequest{}
+	mi := &file_knowledge_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetDatabaseResponse) String() string {
+func (x *GetActionHistoryRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDatabaseResponse) ProtoMessage() {}
+func (*GetActionHistoryRequest) ProtoMessage() {}
 
-func (x *GetDatabaseResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[16]
+func (x *GetActionHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1133,131 +1645,124 @@ func (x *GetDatabaseResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDatabaseResponse.ProtoReflect.Descriptor instead.
-func (*GetDatabaseResponse) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{16}
-}
-
-func (x *GetDatabaseResponse) GetFound() bool {
-	if x != nil {
-		return x.Found
-	}
-	return false
+// Deprecated: Use GetActionHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetActionHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *GetDatabaseResponse) GetDatabaseId() string {
+func (x *GetActionHistoryRequest) GetActionId() string {
 	if x != nil {
-		return x.DatabaseId
+		return x.ActionId
 	}
 	return ""
 }
 
-func (x *GetDatabaseResponse) GetConnectionString() string {
-	if x != nil {
-		return x.ConnectionString
-	}
-	return ""
+type ActionHistoryEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	Source        string                 `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Unix timestamp
+	ErrorCode     string                 `protobuf:"bytes,6,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetDatabaseResponse) GetDatabaseType() string {
-	if x != nil {
-		return x.DatabaseType
-	}
-	return ""
+func (x *ActionHistoryEntry) Reset() {
+	*x = ActionHistoryEntry{}
+	mi := &file_knowledge_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetDatabaseResponse) GetDatabaseName() string {
-	if x != nil {
-		return x.DatabaseName
-	}
-	return ""
+func (x *ActionHistoryEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *GetDatabaseResponse) GetHost() string {
+func (*ActionHistoryEntry) ProtoMessage() {}
+
+func (x *ActionHistoryEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[22]
 	if x != nil {
-		return x.Host
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *GetDatabaseResponse) GetPort() int32 {
-	if x != nil {
-		return x.Port
-	}
-	return 0
+// Deprecated: Use ActionHistoryEntry.ProtoReflect.Descriptor instead.
+func (*ActionHistoryEntry) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *GetDatabaseResponse) GetVersion() string {
+func (x *ActionHistoryEntry) GetStatus() string {
 	if x != nil {
-		return x.Version
+		return x.Status
 	}
 	return ""
 }
 
-func (x *GetDatabaseResponse) GetRegisteredAt() int64 {
+func (x *ActionHistoryEntry) GetMessage() string {
 	if x != nil {
-		return x.RegisteredAt
+		return x.Message
 	}
-	return 0
+	return ""
 }
 
-func (x *GetDatabaseResponse) GetLastSeen() int64 {
+func (x *ActionHistoryEntry) GetError() string {
 	if x != nil {
-		return x.LastSeen
+		return x.Error
 	}
-	return 0
+	return ""
 }
 
-func (x *GetDatabaseResponse) GetStatus() string {
+func (x *ActionHistoryEntry) GetSource() string {
 	if x != nil {
-		return x.Status
+		return x.Source
 	}
 	return ""
 }
 
-func (x *GetDatabaseResponse) GetHealthScore() float64 {
+func (x *ActionHistoryEntry) GetTimestamp() int64 {
 	if x != nil {
-		return x.HealthScore
+		return x.Timestamp
 	}
 	return 0
 }
 
-func (x *GetDatabaseResponse) GetMetadata() map[string]string {
-	if x != nil {
-		return x.Metadata
-	}
-	return nil
-}
-
-func (x *GetDatabaseResponse) GetEnabled() bool {
+func (x *ActionHistoryEntry) GetErrorCode() string {
 	if x != nil {
-		return x.Enabled
+		return x.ErrorCode
 	}
-	return false
+	return ""
 }
 
-type ListDatabasesRequest struct {
+type GetActionHistoryResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	EnabledOnly   bool                   `protobuf:"varint,1,opt,name=enabled_only,json=enabledOnly,proto3" json:"enabled_only,omitempty"` // Filter to only return enabled databases
+	Entries       []*ActionHistoryEntry  `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListDatabasesRequest) Reset() {
-	*x = ListDatabasesRequest{}
-	mi := &file_knowledge_proto_msgTypes[17]
+func (x *GetActionHistoryResponse) Reset() {
+	*x = GetActionHistoryResponse{}
+	mi := &file_knowledge_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListDatabasesRequest) String() string {
+func (x *GetActionHistoryResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListDatabasesRequest) ProtoMessage() {}
+func (*GetActionHistoryResponse) ProtoMessage() {}
 
-func (x *ListDatabasesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[17]
+func (x *GetActionHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1268,40 +1773,41 @@ func (x *ListDatabasesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListDatabasesRequest.ProtoReflect.Descriptor instead.
-func (*ListDatabasesRequest) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use GetActionHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetActionHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *ListDatabasesRequest) GetEnabledOnly() bool {
+func (x *GetActionHistoryResponse) GetEntries() []*ActionHistoryEntry {
 	if x != nil {
-		return x.EnabledOnly
+		return x.Entries
 	}
-	return false
+	return nil
 }
 
-type DatabaseListResponse struct {
+type ListActionsByStatusRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Databases     []*RegisteredDatabase  `protobuf:"bytes,1,rep,name=databases,proto3" json:"databases,omitempty"`
+	Statuses      []string               `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	DatabaseId    string                 `protobuf:"bytes,2,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"` // Optional
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DatabaseListResponse) Reset() {
-	*x = DatabaseListResponse{}
-	mi := &file_knowledge_proto_msgTypes[18]
+func (x *ListActionsByStatusRequest) Reset() {
+	*x = ListActionsByStatusRequest{}
+	mi := &file_knowledge_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DatabaseListResponse) String() string {
+func (x *ListActionsByStatusRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DatabaseListResponse) ProtoMessage() {}
+func (*ListActionsByStatusRequest) ProtoMessage() {}
 
-func (x *DatabaseListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[18]
+func (x *ListActionsByStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1312,51 +1818,46 @@ func (x *DatabaseListResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DatabaseListResponse.ProtoReflect.Descriptor instead.
-func (*DatabaseListResponse) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use ListActionsByStatusRequest.ProtoReflect.Descriptor instead.
+func (*ListActionsByStatusRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *DatabaseListResponse) GetDatabases() []*RegisteredDatabase {
+func (x *ListActionsByStatusRequest) GetStatuses() []string {
 	if x != nil {
-		return x.Databases
+		return x.Statuses
 	}
 	return nil
 }
 
-type RegisteredDatabase struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	DatabaseId       string                 `protobuf:"bytes,1,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
-	DatabaseType     string                 `protobuf:"bytes,2,opt,name=database_type,json=databaseType,proto3" json:"database_type,omitempty"`
-	DatabaseName     string                 `protobuf:"bytes,3,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
-	Host             string                 `protobuf:"bytes,4,opt,name=host,proto3" json:"host,omitempty"`
-	Port             int32                  `protobuf:"varint,5,opt,name=port,proto3" json:"port,omitempty"`
-	Version          string                 `protobuf:"bytes,6,opt,name=version,proto3" json:"version,omitempty"`
-	RegisteredAt     int64                  `protobuf:"varint,7,opt,name=registered_at,json=registeredAt,proto3" json:"registered_at,omitempty"`
-	LastSeen         int64                  `protobuf:"varint,8,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
-	Status           string                 `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`
-	HealthScore      float64                `protobuf:"fixed64,10,opt,name=health_score,json=healthScore,proto3" json:"health_score,omitempty"`
-	Enabled          bool                   `protobuf:"varint,11,opt,name=enabled,proto3" json:"enabled,omitempty"`
-	ConnectionString string                 `protobuf:"bytes,12,opt,name=connection_string,json=connectionString,proto3" json:"connection_string,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+func (x *ListActionsByStatusRequest) GetDatabaseId() string {
+	if x != nil {
+		return x.DatabaseId
+	}
+	return ""
 }
 
-func (x *RegisteredDatabase) Reset() {
-	*x = RegisteredDatabase{}
-	mi := &file_knowledge_proto_msgTypes[19]
+type GetPendingImplementationSummaryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPendingImplementationSummaryRequest) Reset() {
+	*x = GetPendingImplementationSummaryRequest{}
+	mi := &file_knowledge_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RegisteredDatabase) String() string {
+func (x *GetPendingImplementationSummaryRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RegisteredDatabase) ProtoMessage() {}
+func (*GetPendingImplementationSummaryRequest) ProtoMessage() {}
 
-func (x *RegisteredDatabase) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[19]
+func (x *GetPendingImplementationSummaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1367,120 +1868,258 @@ func (x *RegisteredDatabase) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RegisteredDatabase.ProtoReflect.Descriptor instead.
-func (*RegisteredDatabase) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{19}
+// Deprecated: Use GetPendingImplementationSummaryRequest.ProtoReflect.Descriptor instead.
+func (*GetPendingImplementationSummaryRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *RegisteredDatabase) GetDatabaseId() string {
+type GetPendingImplementationSummaryResponse struct {
+	state         protoimpl.MessageState          `protogen:"open.v1"`
+	Summaries     []*PendingImplementationSummary `protobuf:"bytes,1,rep,name=summaries,proto3" json:"summaries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPendingImplementationSummaryResponse) Reset() {
+	*x = GetPendingImplementationSummaryResponse{}
+	mi := &file_knowledge_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPendingImplementationSummaryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPendingImplementationSummaryResponse) ProtoMessage() {}
+
+func (x *GetPendingImplementationSummaryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[26]
 	if x != nil {
-		return x.DatabaseId
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *RegisteredDatabase) GetDatabaseType() string {
+// Deprecated: Use GetPendingImplementationSummaryResponse.ProtoReflect.Descriptor instead.
+func (*GetPendingImplementationSummaryResponse) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetPendingImplementationSummaryResponse) GetSummaries() []*PendingImplementationSummary {
 	if x != nil {
-		return x.DatabaseType
+		return x.Summaries
 	}
-	return ""
+	return nil
 }
 
-func (x *RegisteredDatabase) GetDatabaseName() string {
+// Demand for one unimplemented action type
+type PendingImplementationSummary struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ActionType      string                 `protobuf:"bytes,1,opt,name=action_type,json=actionType,proto3" json:"action_type,omitempty"`
+	Count           int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	DatabaseIds     []string               `protobuf:"bytes,3,rep,name=database_ids,json=databaseIds,proto3" json:"database_ids,omitempty"`
+	LastRequestedAt int64                  `protobuf:"varint,4,opt,name=last_requested_at,json=lastRequestedAt,proto3" json:"last_requested_at,omitempty"` // Unix timestamp of the newest request
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PendingImplementationSummary) Reset() {
+	*x = PendingImplementationSummary{}
+	mi := &file_knowledge_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PendingImplementationSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PendingImplementationSummary) ProtoMessage() {}
+
+func (x *PendingImplementationSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[27]
 	if x != nil {
-		return x.DatabaseName
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *RegisteredDatabase) GetHost() string {
+// Deprecated: Use PendingImplementationSummary.ProtoReflect.Descriptor instead.
+func (*PendingImplementationSummary) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *PendingImplementationSummary) GetActionType() string {
 	if x != nil {
-		return x.Host
+		return x.ActionType
 	}
 	return ""
 }
 
-func (x *RegisteredDatabase) GetPort() int32 {
+func (x *PendingImplementationSummary) GetCount() int32 {
 	if x != nil {
-		return x.Port
+		return x.Count
 	}
 	return 0
 }
 
-func (x *RegisteredDatabase) GetVersion() string {
+func (x *PendingImplementationSummary) GetDatabaseIds() []string {
 	if x != nil {
-		return x.Version
+		return x.DatabaseIds
 	}
-	return ""
+	return nil
 }
 
-func (x *RegisteredDatabase) GetRegisteredAt() int64 {
+func (x *PendingImplementationSummary) GetLastRequestedAt() int64 {
 	if x != nil {
-		return x.RegisteredAt
+		return x.LastRequestedAt
 	}
 	return 0
 }
 
-func (x *RegisteredDatabase) GetLastSeen() int64 {
+type RecordDatabaseActionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseId    string                 `protobuf:"bytes,1,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
+	ActionId      string                 `protobuf:"bytes,2,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
+	StartedAt     int64                  `protobuf:"varint,3,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"` // Unix timestamp
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordDatabaseActionRequest) Reset() {
+	*x = RecordDatabaseActionRequest{}
+	mi := &file_knowledge_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordDatabaseActionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordDatabaseActionRequest) ProtoMessage() {}
+
+func (x *RecordDatabaseActionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[28]
 	if x != nil {
-		return x.LastSeen
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *RegisteredDatabase) GetStatus() string {
+// Deprecated: Use RecordDatabaseActionRequest.ProtoReflect.Descriptor instead.
+func (*RecordDatabaseActionRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RecordDatabaseActionRequest) GetDatabaseId() string {
 	if x != nil {
-		return x.Status
+		return x.DatabaseId
 	}
 	return ""
 }
 
-func (x *RegisteredDatabase) GetHealthScore() float64 {
+func (x *RecordDatabaseActionRequest) GetActionId() string {
 	if x != nil {
-		return x.HealthScore
+		return x.ActionId
+	}
+	return ""
+}
+
+func (x *RecordDatabaseActionRequest) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
 	}
 	return 0
 }
 
-func (x *RegisteredDatabase) GetEnabled() bool {
+type RecentDatabaseActionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseId    string                 `protobuf:"bytes,1,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
+	Since         int64                  `protobuf:"varint,2,opt,name=since,proto3" json:"since,omitempty"` // Unix timestamp
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecentDatabaseActionsRequest) Reset() {
+	*x = RecentDatabaseActionsRequest{}
+	mi := &file_knowledge_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecentDatabaseActionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecentDatabaseActionsRequest) ProtoMessage() {}
+
+func (x *RecentDatabaseActionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[29]
 	if x != nil {
-		return x.Enabled
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return false
+	return mi.MessageOf(x)
 }
 
-func (x *RegisteredDatabase) GetConnectionString() string {
+// Deprecated: Use RecentDatabaseActionsRequest.ProtoReflect.Descriptor instead.
+func (*RecentDatabaseActionsRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RecentDatabaseActionsRequest) GetDatabaseId() string {
 	if x != nil {
-		return x.ConnectionString
+		return x.DatabaseId
 	}
 	return ""
 }
 
-type UpdateDatabaseHealthRequest struct {
+func (x *RecentDatabaseActionsRequest) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+type RecentDatabaseActionsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	DatabaseId    string                 `protobuf:"bytes,1,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
-	LastSeen      int64                  `protobuf:"varint,2,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
-	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
-	HealthScore   float64                `protobuf:"fixed64,4,opt,name=health_score,json=healthScore,proto3" json:"health_score,omitempty"`
+	StartedAt     []int64                `protobuf:"varint,1,rep,packed,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"` // Unix timestamps, oldest first
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateDatabaseHealthRequest) Reset() {
-	*x = UpdateDatabaseHealthRequest{}
-	mi := &file_knowledge_proto_msgTypes[20]
+func (x *RecentDatabaseActionsResponse) Reset() {
+	*x = RecentDatabaseActionsResponse{}
+	mi := &file_knowledge_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateDatabaseHealthRequest) String() string {
+func (x *RecentDatabaseActionsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateDatabaseHealthRequest) ProtoMessage() {}
+func (*RecentDatabaseActionsResponse) ProtoMessage() {}
 
-func (x *UpdateDatabaseHealthRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[20]
+func (x *RecentDatabaseActionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1491,64 +2130,2387 @@ func (x *UpdateDatabaseHealthRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateDatabaseHealthRequest.ProtoReflect.Descriptor instead.
-func (*UpdateDatabaseHealthRequest) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{20}
+// Deprecated: Use RecentDatabaseActionsResponse.ProtoReflect.Descriptor instead.
+func (*RecentDatabaseActionsResponse) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *UpdateDatabaseHealthRequest) GetDatabaseId() string {
+func (x *RecentDatabaseActionsResponse) GetStartedAt() []int64 {
 	if x != nil {
-		return x.DatabaseId
+		return x.StartedAt
 	}
-	return ""
+	return nil
 }
 
-func (x *UpdateDatabaseHealthRequest) GetLastSeen() int64 {
+// Metric history messages
+type MetricSample struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseId    string                 `protobuf:"bytes,1,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                                                                      // Unix timestamp
+	Values        map[string]float64     `protobuf:"bytes,3,rep,name=values,proto3" json:"values,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"` // e.g. health_score, p95_query_latency_ms, cache_hit_rate
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetricSample) Reset() {
+	*x = MetricSample{}
+	mi := &file_knowledge_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetricSample) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricSample) ProtoMessage() {}
+
+func (x *MetricSample) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[31]
 	if x != nil {
-		return x.LastSeen
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *UpdateDatabaseHealthRequest) GetStatus() string {
+// Deprecated: Use MetricSample.ProtoReflect.Descriptor instead.
+func (*MetricSample) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *MetricSample) GetDatabaseId() string {
 	if x != nil {
-		return x.Status
+		return x.DatabaseId
 	}
 	return ""
 }
 
-func (x *UpdateDatabaseHealthRequest) GetHealthScore() float64 {
+func (x *MetricSample) GetTimestamp() int64 {
 	if x != nil {
-		return x.HealthScore
+		return x.Timestamp
 	}
 	return 0
 }
 
-type UpdateDatabaseRequest struct {
+func (x *MetricSample) GetValues() map[string]float64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type GetMetricHistoryRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseId     string                 `protobuf:"bytes,1,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
+	StartTime      int64                  `protobuf:"varint,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`                // Unix timestamp; 0 for as far back as history goes
+	EndTime        int64                  `protobuf:"varint,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`                      // Unix timestamp; 0 for now
+	ResolutionSecs int64                  `protobuf:"varint,4,opt,name=resolution_secs,json=resolutionSecs,proto3" json:"resolution_secs,omitempty"` // 0 for full resolution; rounded up to what is stored for the range
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetMetricHistoryRequest) Reset() {
+	*x = GetMetricHistoryRequest{}
+	mi := &file_knowledge_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMetricHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMetricHistoryRequest) ProtoMessage() {}
+
+func (x *GetMetricHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMetricHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetMetricHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *GetMetricHistoryRequest) GetDatabaseId() string {
+	if x != nil {
+		return x.DatabaseId
+	}
+	return ""
+}
+
+func (x *GetMetricHistoryRequest) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *GetMetricHistoryRequest) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *GetMetricHistoryRequest) GetResolutionSecs() int64 {
+	if x != nil {
+		return x.ResolutionSecs
+	}
+	return 0
+}
+
+type GetMetricHistoryResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Samples        []*MetricSample        `protobuf:"bytes,1,rep,name=samples,proto3" json:"samples,omitempty"`                                      // Oldest first
+	ResolutionSecs int64                  `protobuf:"varint,2,opt,name=resolution_secs,json=resolutionSecs,proto3" json:"resolution_secs,omitempty"` // Resolution actually returned; 0 for full resolution
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetMetricHistoryResponse) Reset() {
+	*x = GetMetricHistoryResponse{}
+	mi := &file_knowledge_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMetricHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMetricHistoryResponse) ProtoMessage() {}
+
+func (x *GetMetricHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMetricHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetMetricHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetMetricHistoryResponse) GetSamples() []*MetricSample {
+	if x != nil {
+		return x.Samples
+	}
+	return nil
+}
+
+func (x *GetMetricHistoryResponse) GetResolutionSecs() int64 {
+	if x != nil {
+		return x.ResolutionSecs
+	}
+	return 0
+}
+
+type ActionListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Actions       []*Action              `protobuf:"bytes,1,rep,name=actions,proto3" json:"actions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActionListResponse) Reset() {
+	*x = ActionListResponse{}
+	mi := &file_knowledge_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActionListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActionListResponse) ProtoMessage() {}
+
+func (x *ActionListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActionListResponse.ProtoReflect.Descriptor instead.
+func (*ActionListResponse) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ActionListResponse) GetActions() []*Action {
+	if x != nil {
+		return x.Actions
+	}
+	return nil
+}
+
+type Action struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DetectionId    string                 `protobuf:"bytes,2,opt,name=detection_id,json=detectionId,proto3" json:"detection_id,omitempty"`
+	ActionType     string                 `protobuf:"bytes,3,opt,name=action_type,json=actionType,proto3" json:"action_type,omitempty"`
+	DatabaseId     string                 `protobuf:"bytes,4,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
+	Status         string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt      int64                  `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Message        string                 `protobuf:"bytes,7,opt,name=message,proto3" json:"message,omitempty"`
+	Error          string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	ActionState    string                 `protobuf:"bytes,9,opt,name=action_state,json=actionState,proto3" json:"action_state,omitempty"`
+	Changes        string                 `protobuf:"bytes,10,opt,name=changes,proto3" json:"changes,omitempty"`
+	CanRollback    bool                   `protobuf:"varint,11,opt,name=can_rollback,json=canRollback,proto3" json:"can_rollback,omitempty"`
+	CompletedAt    int64                  `protobuf:"varint,12,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	Progress       *ActionProgress        `protobuf:"bytes,13,opt,name=progress,proto3" json:"progress,omitempty"`
+	Source         string                 `protobuf:"bytes,14,opt,name=source,proto3" json:"source,omitempty"`
+	Outcome        *ActionOutcome         `protobuf:"bytes,15,opt,name=outcome,proto3" json:"outcome,omitempty"` // Set once the Analyser has verified the action
+	PolicyDecision string                 `protobuf:"bytes,16,opt,name=policy_decision,json=policyDecision,proto3" json:"policy_decision,omitempty"`
+	PolicyRule     string                 `protobuf:"bytes,17,opt,name=policy_rule,json=policyRule,proto3" json:"policy_rule,omitempty"`
+	ErrorCode      string                 `protobuf:"bytes,18,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"` // Code of the last failure, kept like error
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Action) Reset() {
+	*x = Action{}
+	mi := &file_knowledge_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Action) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Action) ProtoMessage() {}
+
+func (x *Action) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Action.ProtoReflect.Descriptor instead.
+func (*Action) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *Action) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Action) GetDetectionId() string {
+	if x != nil {
+		return x.DetectionId
+	}
+	return ""
+}
+
+func (x *Action) GetActionType() string {
+	if x != nil {
+		return x.ActionType
+	}
+	return ""
+}
+
+func (x *Action) GetDatabaseId() string {
+	if x != nil {
+		return x.DatabaseId
+	}
+	return ""
+}
+
+func (x *Action) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Action) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Action) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Action) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Action) GetActionState() string {
+	if x != nil {
+		return x.ActionState
+	}
+	return ""
+}
+
+func (x *Action) GetChanges() string {
+	if x != nil {
+		return x.Changes
+	}
+	return ""
+}
+
+func (x *Action) GetCanRollback() bool {
+	if x != nil {
+		return x.CanRollback
+	}
+	return false
+}
+
+func (x *Action) GetCompletedAt() int64 {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return 0
+}
+
+func (x *Action) GetProgress() *ActionProgress {
+	if x != nil {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *Action) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Action) GetOutcome() *ActionOutcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return nil
+}
+
+func (x *Action) GetPolicyDecision() string {
+	if x != nil {
+		return x.PolicyDecision
+	}
+	return ""
+}
+
+func (x *Action) GetPolicyRule() string {
+	if x != nil {
+		return x.PolicyRule
+	}
+	return ""
+}
+
+func (x *Action) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+// Database messages
+type RegisterDatabaseRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseId       string                 `protobuf:"bytes,1,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
+	ConnectionString string                 `protobuf:"bytes,2,opt,name=connection_string,json=connectionString,proto3" json:"connection_string,omitempty"`
+	DatabaseType     string                 `protobuf:"bytes,3,opt,name=database_type,json=databaseType,proto3" json:"database_type,omitempty"`
+	DatabaseName     string                 `protobuf:"bytes,4,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
+	Host             string                 `protobuf:"bytes,5,opt,name=host,proto3" json:"host,omitempty"`
+	Port             int32                  `protobuf:"varint,6,opt,name=port,proto3" json:"port,omitempty"`
+	Version          string                 `protobuf:"bytes,7,opt,name=version,proto3" json:"version,omitempty"`
+	RegisteredAt     int64                  `protobuf:"varint,8,opt,name=registered_at,json=registeredAt,proto3" json:"registered_at,omitempty"`
+	Metadata         map[string]string      `protobuf:"bytes,9,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Enabled          bool                   `protobuf:"varint,10,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RegisterDatabaseRequest) Reset() {
+	*x = RegisterDatabaseRequest{}
+	mi := &file_knowledge_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterDatabaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterDatabaseRequest) ProtoMessage() {}
+
+func (x *RegisterDatabaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterDatabaseRequest.ProtoReflect.Descriptor instead.
+func (*RegisterDatabaseRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *RegisterDatabaseRequest) GetDatabaseId() string {
+	if x != nil {
+		return x.DatabaseId
+	}
+	return ""
+}
+
+func (x *RegisterDatabaseRequest) GetConnectionString() string {
+	if x != nil {
+		return x.ConnectionString
+	}
+	return ""
+}
+
+func (x *RegisterDatabaseRequest) GetDatabaseType() string {
+	if x != nil {
+		return x.DatabaseType
+	}
+	return ""
+}
+
+func (x *RegisterDatabaseRequest) GetDatabaseName() string {
+	if x != nil {
+		return x.DatabaseName
+	}
+	return ""
+}
+
+func (x *RegisterDatabaseRequest) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *RegisterDatabaseRequest) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *RegisterDatabaseRequest) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *RegisterDatabaseRequest) GetRegisteredAt() int64 {
+	if x != nil {
+		return x.RegisteredAt
+	}
+	return 0
+}
+
+func (x *RegisterDatabaseRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *RegisterDatabaseRequest) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+type DatabaseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DatabaseResponse) Reset() {
+	*x = DatabaseResponse{}
+	mi := &file_knowledge_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DatabaseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DatabaseResponse) ProtoMessage() {}
+
+func (x *DatabaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DatabaseResponse.ProtoReflect.Descriptor instead.
+func (*DatabaseResponse) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *DatabaseResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DatabaseResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type GetDatabaseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseId    string                 `protobuf:"bytes,1,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
+	Redact        bool                   `protobuf:"varint,2,opt,name=redact,proto3" json:"redact,omitempty"` // Omit connection_string, for callers that only display databases
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDatabaseRequest) Reset() {
+	*x = GetDatabaseRequest{}
+	mi := &file_knowledge_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDatabaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDatabaseRequest) ProtoMessage() {}
+
+func (x *GetDatabaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDatabaseRequest.ProtoReflect.Descriptor instead.
+func (*GetDatabaseRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *GetDatabaseRequest) GetDatabaseId() string {
+	if x != nil {
+		return x.DatabaseId
+	}
+	return ""
+}
+
+func (x *GetDatabaseRequest) GetRedact() bool {
+	if x != nil {
+		return x.Redact
+	}
+	return false
+}
+
+type GetDatabaseResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Found            bool                   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	DatabaseId       string                 `protobuf:"bytes,2,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
+	ConnectionString string                 `protobuf:"bytes,3,opt,name=connection_string,json=connectionString,proto3" json:"connection_string,omitempty"`
+	DatabaseType     string                 `protobuf:"bytes,4,opt,name=database_type,json=databaseType,proto3" json:"database_type,omitempty"`
+	DatabaseName     string                 `protobuf:"bytes,5,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
+	Host             string                 `protobuf:"bytes,6,opt,name=host,proto3" json:"host,omitempty"`
+	Port             int32                  `protobuf:"varint,7,opt,name=port,proto3" json:"port,omitempty"`
+	Version          string                 `protobuf:"bytes,8,opt,name=version,proto3" json:"version,omitempty"`
+	RegisteredAt     int64                  `protobuf:"varint,9,opt,name=registered_at,json=registeredAt,proto3" json:"registered_at,omitempty"`
+	LastSeen         int64                  `protobuf:"varint,10,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	Status           string                 `protobuf:"bytes,11,opt,name=status,proto3" json:"status,omitempty"`
+	HealthScore      float64                `protobuf:"fixed64,12,opt,name=health_score,json=healthScore,proto3" json:"health_score,omitempty"`
+	Metadata         map[string]string      `protobuf:"bytes,13,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Enabled          bool                   `protobuf:"varint,14,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetDatabaseResponse) Reset() {
+	*x = GetDatabaseResponse{}
+	mi := &file_knowledge_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDatabaseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDatabaseResponse) ProtoMessage() {}
+
+func (x *GetDatabaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDatabaseResponse.ProtoReflect.Descriptor instead.
+func (*GetDatabaseResponse) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *GetDatabaseResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *GetDatabaseResponse) GetDatabaseId() string {
+	if x != nil {
+		return x.DatabaseId
+	}
+	return ""
+}
+
+func (x *GetDatabaseResponse) GetConnectionString() string {
+	if x != nil {
+		return x.ConnectionString
+	}
+	return ""
+}
+
+func (x *GetDatabaseResponse) GetDatabaseType() string {
+	if x != nil {
+		return x.DatabaseType
+	}
+	return ""
+}
+
+func (x *GetDatabaseResponse) GetDatabaseName() string {
+	if x != nil {
+		return x.DatabaseName
+	}
+	return ""
+}
+
+func (x *GetDatabaseResponse) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *GetDatabaseResponse) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *GetDatabaseResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *GetDatabaseResponse) GetRegisteredAt() int64 {
+	if x != nil {
+		return x.RegisteredAt
+	}
+	return 0
+}
+
+func (x *GetDatabaseResponse) GetLastSeen() int64 {
+	if x != nil {
+		return x.LastSeen
+	}
+	return 0
+}
+
+func (x *GetDatabaseResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetDatabaseResponse) GetHealthScore() float64 {
+	if x != nil {
+		return x.HealthScore
+	}
+	return 0
+}
+
+func (x *GetDatabaseResponse) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *GetDatabaseResponse) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+type ListDatabasesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EnabledOnly   bool                   `protobuf:"varint,1,opt,name=enabled_only,json=enabledOnly,proto3" json:"enabled_only,omitempty"` // Filter to only return enabled databases
+	Cursor        string                 `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`                               // next_cursor from the previous page; empty for the first page
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`                                // Page size; 0 returns all databases
+	Redact        bool                   `protobuf:"varint,4,opt,name=redact,proto3" json:"redact,omitempty"`                              // Omit connection_string, for callers that only display databases
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDatabasesRequest) Reset() {
+	*x = ListDatabasesRequest{}
+	mi := &file_knowledge_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDatabasesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDatabasesRequest) ProtoMessage() {}
+
+func (x *ListDatabasesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDatabasesRequest.ProtoReflect.Descriptor instead.
+func (*ListDatabasesRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ListDatabasesRequest) GetEnabledOnly() bool {
+	if x != nil {
+		return x.EnabledOnly
+	}
+	return false
+}
+
+func (x *ListDatabasesRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListDatabasesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListDatabasesRequest) GetRedact() bool {
+	if x != nil {
+		return x.Redact
+	}
+	return false
+}
+
+type GetDatabasesByTypeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseType  string                 `protobuf:"bytes,1,opt,name=database_type,json=databaseType,proto3" json:"database_type,omitempty"`
+	EnabledOnly   bool                   `protobuf:"varint,2,opt,name=enabled_only,json=enabledOnly,proto3" json:"enabled_only,omitempty"`
+	Cursor        string                 `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	Redact        bool                   `protobuf:"varint,5,opt,name=redact,proto3" json:"redact,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDatabasesByTypeRequest) Reset() {
+	*x = GetDatabasesByTypeRequest{}
+	mi := &file_knowledge_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDatabasesByTypeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDatabasesByTypeRequest) ProtoMessage() {}
+
+func (x *GetDatabasesByTypeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDatabasesByTypeRequest.ProtoReflect.Descriptor instead.
+func (*GetDatabasesByTypeRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *GetDatabasesByTypeRequest) GetDatabaseType() string {
+	if x != nil {
+		return x.DatabaseType
+	}
+	return ""
+}
+
+func (x *GetDatabasesByTypeRequest) GetEnabledOnly() bool {
+	if x != nil {
+		return x.EnabledOnly
+	}
+	return false
+}
+
+func (x *GetDatabasesByTypeRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *GetDatabasesByTypeRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetDatabasesByTypeRequest) GetRedact() bool {
+	if x != nil {
+		return x.Redact
+	}
+	return false
+}
+
+type DatabaseListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Databases     []*RegisteredDatabase  `protobuf:"bytes,1,rep,name=databases,proto3" json:"databases,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // Empty when there are no more pages
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DatabaseListResponse) Reset() {
+	*x = DatabaseListResponse{}
+	mi := &file_knowledge_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DatabaseListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DatabaseListResponse) ProtoMessage() {}
+
+func (x *DatabaseListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DatabaseListResponse.ProtoReflect.Descriptor instead.
+func (*DatabaseListResponse) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *DatabaseListResponse) GetDatabases() []*RegisteredDatabase {
+	if x != nil {
+		return x.Databases
+	}
+	return nil
+}
+
+func (x *DatabaseListResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type RegisteredDatabase struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseId       string                 `protobuf:"bytes,1,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
+	DatabaseType     string                 `protobuf:"bytes,2,opt,name=database_type,json=databaseType,proto3" json:"database_type,omitempty"`
+	DatabaseName     string                 `protobuf:"bytes,3,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
+	Host             string                 `protobuf:"bytes,4,opt,name=host,proto3" json:"host,omitempty"`
+	Port             int32                  `protobuf:"varint,5,opt,name=port,proto3" json:"port,omitempty"`
+	Version          string                 `protobuf:"bytes,6,opt,name=version,proto3" json:"version,omitempty"`
+	RegisteredAt     int64                  `protobuf:"varint,7,opt,name=registered_at,json=registeredAt,proto3" json:"registered_at,omitempty"`
+	LastSeen         int64                  `protobuf:"varint,8,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	Status           string                 `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`
+	HealthScore      float64                `protobuf:"fixed64,10,opt,name=health_score,json=healthScore,proto3" json:"health_score,omitempty"`
+	Enabled          bool                   `protobuf:"varint,11,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	ConnectionString string                 `protobuf:"bytes,12,opt,name=connection_string,json=connectionString,proto3" json:"connection_string,omitempty"`
+	Metadata         map[string]string      `protobuf:"bytes,13,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RegisteredDatabase) Reset() {
+	*x = RegisteredDatabase{}
+	mi := &file_knowledge_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisteredDatabase) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisteredDatabase) ProtoMessage() {}
+
+func (x *RegisteredDatabase) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisteredDatabase.ProtoReflect.Descriptor instead.
+func (*RegisteredDatabase) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *RegisteredDatabase) GetDatabaseId() string {
+	if x != nil {
+		return x.DatabaseId
+	}
+	return ""
+}
+
+func (x *RegisteredDatabase) GetDatabaseType() string {
+	if x != nil {
+		return x.DatabaseType
+	}
+	return ""
+}
+
+func (x *RegisteredDatabase) GetDatabaseName() string {
+	if x != nil {
+		return x.DatabaseName
+	}
+	return ""
+}
+
+func (x *RegisteredDatabase) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *RegisteredDatabase) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *RegisteredDatabase) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *RegisteredDatabase) GetRegisteredAt() int64 {
+	if x != nil {
+		return x.RegisteredAt
+	}
+	return 0
+}
+
+func (x *RegisteredDatabase) GetLastSeen() int64 {
+	if x != nil {
+		return x.LastSeen
+	}
+	return 0
+}
+
+func (x *RegisteredDatabase) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *RegisteredDatabase) GetHealthScore() float64 {
+	if x != nil {
+		return x.HealthScore
+	}
+	return 0
+}
+
+func (x *RegisteredDatabase) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *RegisteredDatabase) GetConnectionString() string {
+	if x != nil {
+		return x.ConnectionString
+	}
+	return ""
+}
+
+func (x *RegisteredDatabase) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type UpdateDatabaseHealthRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseId    string                 `protobuf:"bytes,1,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
+	LastSeen      int64                  `protobuf:"varint,2,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	HealthScore   float64                `protobuf:"fixed64,4,opt,name=health_score,json=healthScore,proto3" json:"health_score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateDatabaseHealthRequest) Reset() {
+	*x = UpdateDatabaseHealthRequest{}
+	mi := &file_knowledge_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDatabaseHealthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDatabaseHealthRequest) ProtoMessage() {}
+
+func (x *UpdateDatabaseHealthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDatabaseHealthRequest.ProtoReflect.Descriptor instead.
+func (*UpdateDatabaseHealthRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *UpdateDatabaseHealthRequest) GetDatabaseId() string {
+	if x != nil {
+		return x.DatabaseId
+	}
+	return ""
+}
+
+func (x *UpdateDatabaseHealthRequest) GetLastSeen() int64 {
+	if x != nil {
+		return x.LastSeen
+	}
+	return 0
+}
+
+func (x *UpdateDatabaseHealthRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *UpdateDatabaseHealthRequest) GetHealthScore() float64 {
+	if x != nil {
+		return x.HealthScore
+	}
+	return 0
+}
+
+type UpdateDatabaseRequest struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
 	DatabaseId       string                 `protobuf:"bytes,1,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
 	ConnectionString string                 `protobuf:"bytes,2,opt,name=connection_string,json=connectionString,proto3" json:"connection_string,omitempty"`
 	DatabaseName     string                 `protobuf:"bytes,3,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
 	Enabled          bool                   `protobuf:"varint,4,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Metadata         map[string]string      `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Merged into the stored metadata; an empty value removes the key
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
 
-func (x *UpdateDatabaseRequest) Reset() {
-	*x = UpdateDatabaseRequest{}
-	mi := &file_knowledge_proto_msgTypes[21]
+func (x *UpdateDatabaseRequest) Reset() {
+	*x = UpdateDatabaseRequest{}
+	mi := &file_knowledge_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDatabaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDatabaseRequest) ProtoMessage() {}
+
+func (x *UpdateDatabaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDatabaseRequest.ProtoReflect.Descriptor instead.
+func (*UpdateDatabaseRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *UpdateDatabaseRequest) GetDatabaseId() string {
+	if x != nil {
+		return x.DatabaseId
+	}
+	return ""
+}
+
+func (x *UpdateDatabaseRequest) GetConnectionString() string {
+	if x != nil {
+		return x.ConnectionString
+	}
+	return ""
+}
+
+func (x *UpdateDatabaseRequest) GetDatabaseName() string {
+	if x != nil {
+		return x.DatabaseName
+	}
+	return ""
+}
+
+func (x *UpdateDatabaseRequest) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *UpdateDatabaseRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type UnregisterDatabaseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseId    string                 `protobuf:"bytes,1,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnregisterDatabaseRequest) Reset() {
+	*x = UnregisterDatabaseRequest{}
+	mi := &file_knowledge_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnregisterDatabaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnregisterDatabaseRequest) ProtoMessage() {}
+
+func (x *UnregisterDatabaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnregisterDatabaseRequest.ProtoReflect.Descriptor instead.
+func (*UnregisterDatabaseRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *UnregisterDatabaseRequest) GetDatabaseId() string {
+	if x != nil {
+		return x.DatabaseId
+	}
+	return ""
+}
+
+// System statistics messages
+type GetSystemStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSystemStatsRequest) Reset() {
+	*x = GetSystemStatsRequest{}
+	mi := &file_knowledge_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSystemStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSystemStatsRequest) ProtoMessage() {}
+
+func (x *GetSystemStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSystemStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetSystemStatsRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{47}
+}
+
+type GetSystemStatsResponse struct {
+	state                        protoimpl.MessageState `protogen:"open.v1"`
+	TotalDatabases               int32                  `protobuf:"varint,1,opt,name=total_databases,json=totalDatabases,proto3" json:"total_databases,omitempty"`
+	HealthyDatabases             int32                  `protobuf:"varint,2,opt,name=healthy_databases,json=healthyDatabases,proto3" json:"healthy_databases,omitempty"`
+	DegradedDatabases            int32                  `protobuf:"varint,3,opt,name=degraded_databases,json=degradedDatabases,proto3" json:"degraded_databases,omitempty"`
+	OfflineDatabases             int32                  `protobuf:"varint,4,opt,name=offline_databases,json=offlineDatabases,proto3" json:"offline_databases,omitempty"`
+	TotalDetections              int32                  `protobuf:"varint,5,opt,name=total_detections,json=totalDetections,proto3" json:"total_detections,omitempty"`
+	ActiveDetections             int32                  `protobuf:"varint,6,opt,name=active_detections,json=activeDetections,proto3" json:"active_detections,omitempty"`
+	ResolvedDetections           int32                  `protobuf:"varint,7,opt,name=resolved_detections,json=resolvedDetections,proto3" json:"resolved_detections,omitempty"`
+	TotalActions                 int32                  `protobuf:"varint,8,opt,name=total_actions,json=totalActions,proto3" json:"total_actions,omitempty"`
+	ActionsQueued                int32                  `protobuf:"varint,9,opt,name=actions_queued,json=actionsQueued,proto3" json:"actions_queued,omitempty"`
+	ActionsExecuting             int32                  `protobuf:"varint,10,opt,name=actions_executing,json=actionsExecuting,proto3" json:"actions_executing,omitempty"`
+	ActionsCompleted             int32                  `protobuf:"varint,11,opt,name=actions_completed,json=actionsCompleted,proto3" json:"actions_completed,omitempty"`
+	ActionsFailed                int32                  `protobuf:"varint,12,opt,name=actions_failed,json=actionsFailed,proto3" json:"actions_failed,omitempty"`
+	UptimeSeconds                int64                  `protobuf:"varint,13,opt,name=uptime_seconds,json=uptimeSeconds,proto3" json:"uptime_seconds,omitempty"`
+	ActiveDetectionsByDatabase   map[string]int32       `protobuf:"bytes,14,rep,name=active_detections_by_database,json=activeDetectionsByDatabase,proto3" json:"active_detections_by_database,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	ActiveDetectionsByCategory   map[string]int32       `protobuf:"bytes,15,rep,name=active_detections_by_category,json=activeDetectionsByCategory,proto3" json:"active_detections_by_category,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	ResolvedDetectionsByDatabase map[string]int32       `protobuf:"bytes,16,rep,name=resolved_detections_by_database,json=resolvedDetectionsByDatabase,proto3" json:"resolved_detections_by_database,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	ResolvedDetectionsByCategory map[string]int32       `protobuf:"bytes,17,rep,name=resolved_detections_by_category,json=resolvedDetectionsByCategory,proto3" json:"resolved_detections_by_category,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	ActionsExecutedLastDay       int32                  `protobuf:"varint,18,opt,name=actions_executed_last_day,json=actionsExecutedLastDay,proto3" json:"actions_executed_last_day,omitempty"` // Rolling 24 hour window
+	// Running totals of what retention cleanup has purged
+	ActionsPurged          int64 `protobuf:"varint,19,opt,name=actions_purged,json=actionsPurged,proto3" json:"actions_purged,omitempty"`
+	DetectionsPurged       int64 `protobuf:"varint,20,opt,name=detections_purged,json=detectionsPurged,proto3" json:"detections_purged,omitempty"`
+	DanglingEntriesRemoved int64 `protobuf:"varint,21,opt,name=dangling_entries_removed,json=danglingEntriesRemoved,proto3" json:"dangling_entries_removed,omitempty"` // Index entries whose record no longer existed
+	LastCleanupAt          int64 `protobuf:"varint,22,opt,name=last_cleanup_at,json=lastCleanupAt,proto3" json:"last_cleanup_at,omitempty"`                            // Unix seconds, 0 if cleanup has not run
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *GetSystemStatsResponse) Reset() {
+	*x = GetSystemStatsResponse{}
+	mi := &file_knowledge_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSystemStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSystemStatsResponse) ProtoMessage() {}
+
+func (x *GetSystemStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSystemStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetSystemStatsResponse) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *GetSystemStatsResponse) GetTotalDatabases() int32 {
+	if x != nil {
+		return x.TotalDatabases
+	}
+	return 0
+}
+
+func (x *GetSystemStatsResponse) GetHealthyDatabases() int32 {
+	if x != nil {
+		return x.HealthyDatabases
+	}
+	return 0
+}
+
+func (x *GetSystemStatsResponse) GetDegradedDatabases() int32 {
+	if x != nil {
+		return x.DegradedDatabases
+	}
+	return 0
+}
+
+func (x *GetSystemStatsResponse) GetOfflineDatabases() int32 {
+	if x != nil {
+		return x.OfflineDatabases
+	}
+	return 0
+}
+
+func (x *GetSystemStatsResponse) GetTotalDetections() int32 {
+	if x != nil {
+		return x.TotalDetections
+	}
+	return 0
+}
+
+func (x *GetSystemStatsResponse) GetActiveDetections() int32 {
+	if x != nil {
+		return x.ActiveDetections
+	}
+	return 0
+}
+
+func (x *GetSystemStatsResponse) GetResolvedDetections() int32 {
+	if x != nil {
+		return x.ResolvedDetections
+	}
+	return 0
+}
+
+func (x *GetSystemStatsResponse) GetTotalActions() int32 {
+	if x != nil {
+		return x.TotalActions
+	}
+	return 0
+}
+
+func (x *GetSystemStatsResponse) GetActionsQueued() int32 {
+	if x != nil {
+		return x.ActionsQueued
+	}
+	return 0
+}
+
+func (x *GetSystemStatsResponse) GetActionsExecuting() int32 {
+	if x != nil {
+		return x.ActionsExecuting
+	}
+	return 0
+}
+
+func (x *GetSystemStatsResponse) GetActionsCompleted() int32 {
+	if x != nil {
+		return x.ActionsCompleted
+	}
+	return 0
+}
+
+func (x *GetSystemStatsResponse) GetActionsFailed() int32 {
+	if x != nil {
+		return x.ActionsFailed
+	}
+	return 0
+}
+
+func (x *GetSystemStatsResponse) GetUptimeSeconds() int64 {
+	if x != nil {
+		return x.UptimeSeconds
+	}
+	return 0
+}
+
+func (x *GetSystemStatsResponse) GetActiveDetectionsByDatabase() map[string]int32 {
+	if x != nil {
+		return x.ActiveDetectionsByDatabase
+	}
+	return nil
+}
+
+func (x *GetSystemStatsResponse) GetActiveDetectionsByCategory() map[string]int32 {
+	if x != nil {
+		return x.ActiveDetectionsByCategory
+	}
+	return nil
+}
+
+func (x *GetSystemStatsResponse) GetResolvedDetectionsByDatabase() map[string]int32 {
+	if x != nil {
+		return x.ResolvedDetectionsByDatabase
+	}
+	return nil
+}
+
+func (x *GetSystemStatsResponse) GetResolvedDetectionsByCategory() map[string]int32 {
+	if x != nil {
+		return x.ResolvedDetectionsByCategory
+	}
+	return nil
+}
+
+func (x *GetSystemStatsResponse) GetActionsExecutedLastDay() int32 {
+	if x != nil {
+		return x.ActionsExecutedLastDay
+	}
+	return 0
+}
+
+func (x *GetSystemStatsResponse) GetActionsPurged() int64 {
+	if x != nil {
+		return x.ActionsPurged
+	}
+	return 0
+}
+
+func (x *GetSystemStatsResponse) GetDetectionsPurged() int64 {
+	if x != nil {
+		return x.DetectionsPurged
+	}
+	return 0
+}
+
+func (x *GetSystemStatsResponse) GetDanglingEntriesRemoved() int64 {
+	if x != nil {
+		return x.DanglingEntriesRemoved
+	}
+	return 0
+}
+
+func (x *GetSystemStatsResponse) GetLastCleanupAt() int64 {
+	if x != nil {
+		return x.LastCleanupAt
+	}
+	return 0
+}
+
+type GetActionStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WindowSeconds int64                  `protobuf:"varint,1,opt,name=window_seconds,json=windowSeconds,proto3" json:"window_seconds,omitempty"` // How far back to count (0 or beyond retention: the whole 30 day retention)
+	DatabaseId    string                 `protobuf:"bytes,2,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`           // Optional
+	ActionType    string                 `protobuf:"bytes,3,opt,name=action_type,json=actionType,proto3" json:"action_type,omitempty"`           // Optional
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetActionStatsRequest) Reset() {
+	*x = GetActionStatsRequest{}
+	mi := &file_knowledge_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetActionStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetActionStatsRequest) ProtoMessage() {}
+
+func (x *GetActionStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetActionStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetActionStatsRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *GetActionStatsRequest) GetWindowSeconds() int64 {
+	if x != nil {
+		return x.WindowSeconds
+	}
+	return 0
+}
+
+func (x *GetActionStatsRequest) GetDatabaseId() string {
+	if x != nil {
+		return x.DatabaseId
+	}
+	return ""
+}
+
+func (x *GetActionStatsRequest) GetActionType() string {
+	if x != nil {
+		return x.ActionType
+	}
+	return ""
+}
+
+// Counts for one action type on one database; either is empty when summed across them
+type ActionStats struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	ActionType          string                 `protobuf:"bytes,1,opt,name=action_type,json=actionType,proto3" json:"action_type,omitempty"`
+	DatabaseId          string                 `protobuf:"bytes,2,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
+	Attempts            int64                  `protobuf:"varint,3,opt,name=attempts,proto3" json:"attempts,omitempty"` // Actions that reached completed or failed
+	Successes           int64                  `protobuf:"varint,4,opt,name=successes,proto3" json:"successes,omitempty"`
+	Failures            int64                  `protobuf:"varint,5,opt,name=failures,proto3" json:"failures,omitempty"`
+	Rollbacks           int64                  `protobuf:"varint,6,opt,name=rollbacks,proto3" json:"rollbacks,omitempty"`
+	VerifiedEffective   int64                  `protobuf:"varint,7,opt,name=verified_effective,json=verifiedEffective,proto3" json:"verified_effective,omitempty"` // Verdicts recorded by the Analyser's verification
+	VerifiedIneffective int64                  `protobuf:"varint,8,opt,name=verified_ineffective,json=verifiedIneffective,proto3" json:"verified_ineffective,omitempty"`
+	SuccessRate         float64                `protobuf:"fixed64,9,opt,name=success_rate,json=successRate,proto3" json:"success_rate,omitempty"`                            // successes / attempts
+	VerifiedSuccessRate float64                `protobuf:"fixed64,10,opt,name=verified_success_rate,json=verifiedSuccessRate,proto3" json:"verified_success_rate,omitempty"` // verified_effective / all verdicts
+	AvgExecutionMs      float64                `protobuf:"fixed64,11,opt,name=avg_execution_ms,json=avgExecutionMs,proto3" json:"avg_execution_ms,omitempty"`                // From executing to completed or failed
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *ActionStats) Reset() {
+	*x = ActionStats{}
+	mi := &file_knowledge_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActionStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActionStats) ProtoMessage() {}
+
+func (x *ActionStats) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActionStats.ProtoReflect.Descriptor instead.
+func (*ActionStats) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ActionStats) GetActionType() string {
+	if x != nil {
+		return x.ActionType
+	}
+	return ""
+}
+
+func (x *ActionStats) GetDatabaseId() string {
+	if x != nil {
+		return x.DatabaseId
+	}
+	return ""
+}
+
+func (x *ActionStats) GetAttempts() int64 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *ActionStats) GetSuccesses() int64 {
+	if x != nil {
+		return x.Successes
+	}
+	return 0
+}
+
+func (x *ActionStats) GetFailures() int64 {
+	if x != nil {
+		return x.Failures
+	}
+	return 0
+}
+
+func (x *ActionStats) GetRollbacks() int64 {
+	if x != nil {
+		return x.Rollbacks
+	}
+	return 0
+}
+
+func (x *ActionStats) GetVerifiedEffective() int64 {
+	if x != nil {
+		return x.VerifiedEffective
+	}
+	return 0
+}
+
+func (x *ActionStats) GetVerifiedIneffective() int64 {
+	if x != nil {
+		return x.VerifiedIneffective
+	}
+	return 0
+}
+
+func (x *ActionStats) GetSuccessRate() float64 {
+	if x != nil {
+		return x.SuccessRate
+	}
+	return 0
+}
+
+func (x *ActionStats) GetVerifiedSuccessRate() float64 {
+	if x != nil {
+		return x.VerifiedSuccessRate
+	}
+	return 0
+}
+
+func (x *ActionStats) GetAvgExecutionMs() float64 {
+	if x != nil {
+		return x.AvgExecutionMs
+	}
+	return 0
+}
+
+type GetActionStatsResponse struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	ByActionType            []*ActionStats         `protobuf:"bytes,1,rep,name=by_action_type,json=byActionType,proto3" json:"by_action_type,omitempty"` // Summed across databases
+	ByDatabase              []*ActionStats         `protobuf:"bytes,2,rep,name=by_database,json=byDatabase,proto3" json:"by_database,omitempty"`         // Summed across action types
+	ByActionTypeAndDatabase []*ActionStats         `protobuf:"bytes,3,rep,name=by_action_type_and_database,json=byActionTypeAndDatabase,proto3" json:"by_action_type_and_database,omitempty"`
+	Total                   *ActionStats           `protobuf:"bytes,4,opt,name=total,proto3" json:"total,omitempty"`
+	WindowSeconds           int64                  `protobuf:"varint,5,opt,name=window_seconds,json=windowSeconds,proto3" json:"window_seconds,omitempty"` // The window actually counted
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *GetActionStatsResponse) Reset() {
+	*x = GetActionStatsResponse{}
+	mi := &file_knowledge_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetActionStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetActionStatsResponse) ProtoMessage() {}
+
+func (x *GetActionStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetActionStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetActionStatsResponse) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *GetActionStatsResponse) GetByActionType() []*ActionStats {
+	if x != nil {
+		return x.ByActionType
+	}
+	return nil
+}
+
+func (x *GetActionStatsResponse) GetByDatabase() []*ActionStats {
+	if x != nil {
+		return x.ByDatabase
+	}
+	return nil
+}
+
+func (x *GetActionStatsResponse) GetByActionTypeAndDatabase() []*ActionStats {
+	if x != nil {
+		return x.ByActionTypeAndDatabase
+	}
+	return nil
+}
+
+func (x *GetActionStatsResponse) GetTotal() *ActionStats {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *GetActionStatsResponse) GetWindowSeconds() int64 {
+	if x != nil {
+		return x.WindowSeconds
+	}
+	return 0
+}
+
+// Configuration management messages
+type DetectionThresholds struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	ConnectionPoolCritical  float64                `protobuf:"fixed64,1,opt,name=connection_pool_critical,json=connectionPoolCritical,proto3" json:"connection_pool_critical,omitempty"`
+	SequentialScanThreshold int64                  `protobuf:"varint,2,opt,name=sequential_scan_threshold,json=sequentialScanThreshold,proto3" json:"sequential_scan_threshold,omitempty"`
+	SequentialScanDelta     float64                `protobuf:"fixed64,3,opt,name=sequential_scan_delta,json=sequentialScanDelta,proto3" json:"sequential_scan_delta,omitempty"`
+	P95LatencyMs            float64                `protobuf:"fixed64,4,opt,name=p95_latency_ms,json=p95LatencyMs,proto3" json:"p95_latency_ms,omitempty"`
+	CacheHitRateThreshold   float64                `protobuf:"fixed64,5,opt,name=cache_hit_rate_threshold,json=cacheHitRateThreshold,proto3" json:"cache_hit_rate_threshold,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *DetectionThresholds) Reset() {
+	*x = DetectionThresholds{}
+	mi := &file_knowledge_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DetectionThresholds) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DetectionThresholds) ProtoMessage() {}
+
+func (x *DetectionThresholds) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DetectionThresholds.ProtoReflect.Descriptor instead.
+func (*DetectionThresholds) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *DetectionThresholds) GetConnectionPoolCritical() float64 {
+	if x != nil {
+		return x.ConnectionPoolCritical
+	}
+	return 0
+}
+
+func (x *DetectionThresholds) GetSequentialScanThreshold() int64 {
+	if x != nil {
+		return x.SequentialScanThreshold
+	}
+	return 0
+}
+
+func (x *DetectionThresholds) GetSequentialScanDelta() float64 {
+	if x != nil {
+		return x.SequentialScanDelta
+	}
+	return 0
+}
+
+func (x *DetectionThresholds) GetP95LatencyMs() float64 {
+	if x != nil {
+		return x.P95LatencyMs
+	}
+	return 0
+}
+
+func (x *DetectionThresholds) GetCacheHitRateThreshold() float64 {
+	if x != nil {
+		return x.CacheHitRateThreshold
+	}
+	return 0
+}
+
+type WebhookConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	AuthHeader    string                 `protobuf:"bytes,2,opt,name=auth_header,json=authHeader,proto3" json:"auth_header,omitempty"`
+	Enabled       bool                   `protobuf:"varint,3,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Events        []string               `protobuf:"bytes,4,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookConfig) Reset() {
+	*x = WebhookConfig{}
+	mi := &file_knowledge_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookConfig) ProtoMessage() {}
+
+func (x *WebhookConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookConfig.ProtoReflect.Descriptor instead.
+func (*WebhookConfig) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *WebhookConfig) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *WebhookConfig) GetAuthHeader() string {
+	if x != nil {
+		return x.AuthHeader
+	}
+	return ""
+}
+
+func (x *WebhookConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *WebhookConfig) GetEvents() []string {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type SystemConfig struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Thresholds         *DetectionThresholds   `protobuf:"bytes,2,opt,name=thresholds,proto3" json:"thresholds,omitempty"`
+	OnboardingComplete bool                   `protobuf:"varint,3,opt,name=onboarding_complete,json=onboardingComplete,proto3" json:"onboarding_complete,omitempty"`
+	ExecutionMode      string                 `protobuf:"bytes,4,opt,name=execution_mode,json=executionMode,proto3" json:"execution_mode,omitempty"`
+	Webhook            *WebhookConfig         `protobuf:"bytes,5,opt,name=webhook,proto3" json:"webhook,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *SystemConfig) Reset() {
+	*x = SystemConfig{}
+	mi := &file_knowledge_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SystemConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemConfig) ProtoMessage() {}
+
+func (x *SystemConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemConfig.ProtoReflect.Descriptor instead.
+func (*SystemConfig) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *SystemConfig) GetThresholds() *DetectionThresholds {
+	if x != nil {
+		return x.Thresholds
+	}
+	return nil
+}
+
+func (x *SystemConfig) GetOnboardingComplete() bool {
+	if x != nil {
+		return x.OnboardingComplete
+	}
+	return false
+}
+
+func (x *SystemConfig) GetExecutionMode() string {
+	if x != nil {
+		return x.ExecutionMode
+	}
+	return ""
+}
+
+func (x *SystemConfig) GetWebhook() *WebhookConfig {
+	if x != nil {
+		return x.Webhook
+	}
+	return nil
+}
+
+type SystemStatus struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Configured         bool                   `protobuf:"varint,1,opt,name=configured,proto3" json:"configured,omitempty"`
+	OnboardingComplete bool                   `protobuf:"varint,2,opt,name=onboarding_complete,json=onboardingComplete,proto3" json:"onboarding_complete,omitempty"`
+	ServiceStates      map[string]string      `protobuf:"bytes,3,rep,name=service_states,json=serviceStates,proto3" json:"service_states,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	StatsSummary       *StatsSummary          `protobuf:"bytes,4,opt,name=stats_summary,json=statsSummary,proto3" json:"stats_summary,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *SystemStatus) Reset() {
+	*x = SystemStatus{}
+	mi := &file_knowledge_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SystemStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemStatus) ProtoMessage() {}
+
+func (x *SystemStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemStatus.ProtoReflect.Descriptor instead.
+func (*SystemStatus) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *SystemStatus) GetConfigured() bool {
+	if x != nil {
+		return x.Configured
+	}
+	return false
+}
+
+func (x *SystemStatus) GetOnboardingComplete() bool {
+	if x != nil {
+		return x.OnboardingComplete
+	}
+	return false
+}
+
+func (x *SystemStatus) GetServiceStates() map[string]string {
+	if x != nil {
+		return x.ServiceStates
+	}
+	return nil
+}
+
+func (x *SystemStatus) GetStatsSummary() *StatsSummary {
+	if x != nil {
+		return x.StatsSummary
+	}
+	return nil
+}
+
+// Per-database health summary, offline = no health report within the staleness window
+type StatsSummary struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	TotalDatabases     int32                  `protobuf:"varint,1,opt,name=total_databases,json=totalDatabases,proto3" json:"total_databases,omitempty"`
+	HealthyDatabases   int32                  `protobuf:"varint,2,opt,name=healthy_databases,json=healthyDatabases,proto3" json:"healthy_databases,omitempty"`
+	DegradedDatabases  int32                  `protobuf:"varint,3,opt,name=degraded_databases,json=degradedDatabases,proto3" json:"degraded_databases,omitempty"`
+	OfflineDatabases   int32                  `protobuf:"varint,4,opt,name=offline_databases,json=offlineDatabases,proto3" json:"offline_databases,omitempty"`
+	OfflineDatabaseIds []string               `protobuf:"bytes,5,rep,name=offline_database_ids,json=offlineDatabaseIds,proto3" json:"offline_database_ids,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *StatsSummary) Reset() {
+	*x = StatsSummary{}
+	mi := &file_knowledge_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsSummary) ProtoMessage() {}
+
+func (x *StatsSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsSummary.ProtoReflect.Descriptor instead.
+func (*StatsSummary) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *StatsSummary) GetTotalDatabases() int32 {
+	if x != nil {
+		return x.TotalDatabases
+	}
+	return 0
+}
+
+func (x *StatsSummary) GetHealthyDatabases() int32 {
+	if x != nil {
+		return x.HealthyDatabases
+	}
+	return 0
+}
+
+func (x *StatsSummary) GetDegradedDatabases() int32 {
+	if x != nil {
+		return x.DegradedDatabases
+	}
+	return 0
+}
+
+func (x *StatsSummary) GetOfflineDatabases() int32 {
+	if x != nil {
+		return x.OfflineDatabases
+	}
+	return 0
+}
+
+func (x *StatsSummary) GetOfflineDatabaseIds() []string {
+	if x != nil {
+		return x.OfflineDatabaseIds
+	}
+	return nil
+}
+
+type GetSystemConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSystemConfigRequest) Reset() {
+	*x = GetSystemConfigRequest{}
+	mi := &file_knowledge_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSystemConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSystemConfigRequest) ProtoMessage() {}
+
+func (x *GetSystemConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSystemConfigRequest.ProtoReflect.Descriptor instead.
+func (*GetSystemConfigRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{57}
+}
+
+type SaveSystemConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Config        *SystemConfig          `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SaveSystemConfigRequest) Reset() {
+	*x = SaveSystemConfigRequest{}
+	mi := &file_knowledge_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SaveSystemConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveSystemConfigRequest) ProtoMessage() {}
+
+func (x *SaveSystemConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveSystemConfigRequest.ProtoReflect.Descriptor instead.
+func (*SaveSystemConfigRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *SaveSystemConfigRequest) GetConfig() *SystemConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+type GetSystemStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSystemStatusRequest) Reset() {
+	*x = GetSystemStatusRequest{}
+	mi := &file_knowledge_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSystemStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSystemStatusRequest) ProtoMessage() {}
+
+func (x *GetSystemStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSystemStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetSystemStatusRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{59}
+}
+
+// Detection threshold overrides for one scope, keyed by the Analyser's threshold names
+// (e.g. "sequential_scan_threshold"). A name missing from a database's set falls back to
+// the global default, then to the Analyser's own configuration.
+type ThresholdSet struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseId    string                 `protobuf:"bytes,1,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"` // Empty for the global default
+	Values        map[string]float64     `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	UpdatedAt     int64                  `protobuf:"varint,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThresholdSet) Reset() {
+	*x = ThresholdSet{}
+	mi := &file_knowledge_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThresholdSet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThresholdSet) ProtoMessage() {}
+
+func (x *ThresholdSet) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThresholdSet.ProtoReflect.Descriptor instead.
+func (*ThresholdSet) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *ThresholdSet) GetDatabaseId() string {
+	if x != nil {
+		return x.DatabaseId
+	}
+	return ""
+}
+
+func (x *ThresholdSet) GetValues() map[string]float64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *ThresholdSet) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+type SetThresholdsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseId    string                 `protobuf:"bytes,1,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`                                                   // Empty sets the global default
+	Values        map[string]float64     `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"` // Replaces the whole set; empty clears it
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetThresholdsRequest) Reset() {
+	*x = SetThresholdsRequest{}
+	mi := &file_knowledge_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateDatabaseRequest) String() string {
+func (x *SetThresholdsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateDatabaseRequest) ProtoMessage() {}
+func (*SetThresholdsRequest) ProtoMessage() {}
 
-func (x *UpdateDatabaseRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[21]
+func (x *SetThresholdsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[61]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1559,61 +4521,47 @@ func (x *UpdateDatabaseRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateDatabaseRequest.ProtoReflect.Descriptor instead.
-func (*UpdateDatabaseRequest) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{21}
+// Deprecated: Use SetThresholdsRequest.ProtoReflect.Descriptor instead.
+func (*SetThresholdsRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{61}
 }
 
-func (x *UpdateDatabaseRequest) GetDatabaseId() string {
+func (x *SetThresholdsRequest) GetDatabaseId() string {
 	if x != nil {
 		return x.DatabaseId
 	}
 	return ""
 }
 
-func (x *UpdateDatabaseRequest) GetConnectionString() string {
-	if x != nil {
-		return x.ConnectionString
-	}
-	return ""
-}
-
-func (x *UpdateDatabaseRequest) GetDatabaseName() string {
-	if x != nil {
-		return x.DatabaseName
-	}
-	return ""
-}
-
-func (x *UpdateDatabaseRequest) GetEnabled() bool {
+func (x *SetThresholdsRequest) GetValues() map[string]float64 {
 	if x != nil {
-		return x.Enabled
+		return x.Values
 	}
-	return false
+	return nil
 }
 
-type UnregisterDatabaseRequest struct {
+type GetThresholdsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	DatabaseId    string                 `protobuf:"bytes,1,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
+	DatabaseId    string                 `protobuf:"bytes,1,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"` // Optional: only the global default and this database's set
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UnregisterDatabaseRequest) Reset() {
-	*x = UnregisterDatabaseRequest{}
-	mi := &file_knowledge_proto_msgTypes[22]
+func (x *GetThresholdsRequest) Reset() {
+	*x = GetThresholdsRequest{}
+	mi := &file_knowledge_proto_msgTypes[62]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UnregisterDatabaseRequest) String() string {
+func (x *GetThresholdsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UnregisterDatabaseRequest) ProtoMessage() {}
+func (*GetThresholdsRequest) ProtoMessage() {}
 
-func (x *UnregisterDatabaseRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[22]
+func (x *GetThresholdsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1624,40 +4572,40 @@ func (x *UnregisterDatabaseRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UnregisterDatabaseRequest.ProtoReflect.Descriptor instead.
-func (*UnregisterDatabaseRequest) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{22}
+// Deprecated: Use GetThresholdsRequest.ProtoReflect.Descriptor instead.
+func (*GetThresholdsRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{62}
 }
 
-func (x *UnregisterDatabaseRequest) GetDatabaseId() string {
+func (x *GetThresholdsRequest) GetDatabaseId() string {
 	if x != nil {
 		return x.DatabaseId
 	}
 	return ""
 }
 
-// System statistics messages
-type GetSystemStatsRequest struct {
+type GetThresholdsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sets          []*ThresholdSet        `protobuf:"bytes,1,rep,name=sets,proto3" json:"sets,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetSystemStatsRequest) Reset() {
-	*x = GetSystemStatsRequest{}
-	mi := &file_knowledge_proto_msgTypes[23]
+func (x *GetThresholdsResponse) Reset() {
+	*x = GetThresholdsResponse{}
+	mi := &file_knowledge_proto_msgTypes[63]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetSystemStatsRequest) String() string {
+func (x *GetThresholdsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSystemStatsRequest) ProtoMessage() {}
+func (*GetThresholdsResponse) ProtoMessage() {}
 
-func (x *GetSystemStatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[23]
+func (x *GetThresholdsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[63]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1668,45 +4616,48 @@ func (x *GetSystemStatsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSystemStatsRequest.ProtoReflect.Descriptor instead.
-func (*GetSystemStatsRequest) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{23}
+// Deprecated: Use GetThresholdsResponse.ProtoReflect.Descriptor instead.
+func (*GetThresholdsResponse) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{63}
 }
 
-type GetSystemStatsResponse struct {
+func (x *GetThresholdsResponse) GetSets() []*ThresholdSet {
+	if x != nil {
+		return x.Sets
+	}
+	return nil
+}
+
+// What StartupMonkey may do to one database, e.g. never terminate_query against billing.
+// The Analyser does not publish detections from ignored detectors; the Executor refuses
+// denied action types, and any type missing from a non-empty allow list.
+type DatabasePolicy struct {
 	state              protoimpl.MessageState `protogen:"open.v1"`
-	TotalDatabases     int32                  `protobuf:"varint,1,opt,name=total_databases,json=totalDatabases,proto3" json:"total_databases,omitempty"`
-	HealthyDatabases   int32                  `protobuf:"varint,2,opt,name=healthy_databases,json=healthyDatabases,proto3" json:"healthy_databases,omitempty"`
-	DegradedDatabases  int32                  `protobuf:"varint,3,opt,name=degraded_databases,json=degradedDatabases,proto3" json:"degraded_databases,omitempty"`
-	OfflineDatabases   int32                  `protobuf:"varint,4,opt,name=offline_databases,json=offlineDatabases,proto3" json:"offline_databases,omitempty"`
-	TotalDetections    int32                  `protobuf:"varint,5,opt,name=total_detections,json=totalDetections,proto3" json:"total_detections,omitempty"`
-	ActiveDetections   int32                  `protobuf:"varint,6,opt,name=active_detections,json=activeDetections,proto3" json:"active_detections,omitempty"`
-	ResolvedDetections int32                  `protobuf:"varint,7,opt,name=resolved_detections,json=resolvedDetections,proto3" json:"resolved_detections,omitempty"`
-	TotalActions       int32                  `protobuf:"varint,8,opt,name=total_actions,json=totalActions,proto3" json:"total_actions,omitempty"`
-	ActionsQueued      int32                  `protobuf:"varint,9,opt,name=actions_queued,json=actionsQueued,proto3" json:"actions_queued,omitempty"`
-	ActionsExecuting   int32                  `protobuf:"varint,10,opt,name=actions_executing,json=actionsExecuting,proto3" json:"actions_executing,omitempty"`
-	ActionsCompleted   int32                  `protobuf:"varint,11,opt,name=actions_completed,json=actionsCompleted,proto3" json:"actions_completed,omitempty"`
-	ActionsFailed      int32                  `protobuf:"varint,12,opt,name=actions_failed,json=actionsFailed,proto3" json:"actions_failed,omitempty"`
-	UptimeSeconds      int64                  `protobuf:"varint,13,opt,name=uptime_seconds,json=uptimeSeconds,proto3" json:"uptime_seconds,omitempty"`
+	DatabaseId         string                 `protobuf:"bytes,1,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
+	AllowedActionTypes []string               `protobuf:"bytes,2,rep,name=allowed_action_types,json=allowedActionTypes,proto3" json:"allowed_action_types,omitempty"` // Empty allows every action type
+	DeniedActionTypes  []string               `protobuf:"bytes,3,rep,name=denied_action_types,json=deniedActionTypes,proto3" json:"denied_action_types,omitempty"`    // Takes precedence over allowed_action_types
+	IgnoredDetectors   []string               `protobuf:"bytes,4,rep,name=ignored_detectors,json=ignoredDetectors,proto3" json:"ignored_detectors,omitempty"`
+	Reason             string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"` // Why the policy exists, recorded on refused actions
+	UpdatedAt          int64                  `protobuf:"varint,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
 	unknownFields      protoimpl.UnknownFields
 	sizeCache          protoimpl.SizeCache
 }
 
-func (x *GetSystemStatsResponse) Reset() {
-	*x = GetSystemStatsResponse{}
-	mi := &file_knowledge_proto_msgTypes[24]
+func (x *DatabasePolicy) Reset() {
+	*x = DatabasePolicy{}
+	mi := &file_knowledge_proto_msgTypes[64]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetSystemStatsResponse) String() string {
+func (x *DatabasePolicy) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSystemStatsResponse) ProtoMessage() {}
+func (*DatabasePolicy) ProtoMessage() {}
 
-func (x *GetSystemStatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[24]
+func (x *DatabasePolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[64]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1717,129 +4668,119 @@ func (x *GetSystemStatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSystemStatsResponse.ProtoReflect.Descriptor instead.
-func (*GetSystemStatsResponse) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{24}
+// Deprecated: Use DatabasePolicy.ProtoReflect.Descriptor instead.
+func (*DatabasePolicy) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{64}
 }
 
-func (x *GetSystemStatsResponse) GetTotalDatabases() int32 {
+func (x *DatabasePolicy) GetDatabaseId() string {
 	if x != nil {
-		return x.TotalDatabases
+		return x.DatabaseId
 	}
-	return 0
+	return ""
 }
 
-func (x *GetSystemStatsResponse) GetHealthyDatabases() int32 {
+func (x *DatabasePolicy) GetAllowedActionTypes() []string {
 	if x != nil {
-		return x.HealthyDatabases
+		return x.AllowedActionTypes
 	}
-	return 0
+	return nil
 }
 
-func (x *GetSystemStatsResponse) GetDegradedDatabases() int32 {
+func (x *DatabasePolicy) GetDeniedActionTypes() []string {
 	if x != nil {
-		return x.DegradedDatabases
+		return x.DeniedActionTypes
 	}
-	return 0
+	return nil
 }
 
-func (x *GetSystemStatsResponse) GetOfflineDatabases() int32 {
+func (x *DatabasePolicy) GetIgnoredDetectors() []string {
 	if x != nil {
-		return x.OfflineDatabases
+		return x.IgnoredDetectors
 	}
-	return 0
+	return nil
 }
 
-func (x *GetSystemStatsResponse) GetTotalDetections() int32 {
+func (x *DatabasePolicy) GetReason() string {
 	if x != nil {
-		return x.TotalDetections
+		return x.Reason
 	}
-	return 0
+	return ""
 }
 
-func (x *GetSystemStatsResponse) GetActiveDetections() int32 {
+func (x *DatabasePolicy) GetUpdatedAt() int64 {
 	if x != nil {
-		return x.ActiveDetections
+		return x.UpdatedAt
 	}
 	return 0
 }
 
-func (x *GetSystemStatsResponse) GetResolvedDetections() int32 {
-	if x != nil {
-		return x.ResolvedDetections
-	}
-	return 0
+type SetDatabasePolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Policy        *DatabasePolicy        `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"` // Replaces the whole policy; one with empty lists clears it
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetSystemStatsResponse) GetTotalActions() int32 {
-	if x != nil {
-		return x.TotalActions
-	}
-	return 0
+func (x *SetDatabasePolicyRequest) Reset() {
+	*x = SetDatabasePolicyRequest{}
+	mi := &file_knowledge_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetSystemStatsResponse) GetActionsQueued() int32 {
-	if x != nil {
-		return x.ActionsQueued
-	}
-	return 0
+func (x *SetDatabasePolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *GetSystemStatsResponse) GetActionsExecuting() int32 {
-	if x != nil {
-		return x.ActionsExecuting
-	}
-	return 0
-}
+func (*SetDatabasePolicyRequest) ProtoMessage() {}
 
-func (x *GetSystemStatsResponse) GetActionsCompleted() int32 {
+func (x *SetDatabasePolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[65]
 	if x != nil {
-		return x.ActionsCompleted
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *GetSystemStatsResponse) GetActionsFailed() int32 {
-	if x != nil {
-		return x.ActionsFailed
-	}
-	return 0
+// Deprecated: Use SetDatabasePolicyRequest.ProtoReflect.Descriptor instead.
+func (*SetDatabasePolicyRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{65}
 }
 
-func (x *GetSystemStatsResponse) GetUptimeSeconds() int64 {
+func (x *SetDatabasePolicyRequest) GetPolicy() *DatabasePolicy {
 	if x != nil {
-		return x.UptimeSeconds
+		return x.Policy
 	}
-	return 0
+	return nil
 }
 
-// Configuration management messages
-type DetectionThresholds struct {
-	state                   protoimpl.MessageState `protogen:"open.v1"`
-	ConnectionPoolCritical  float64                `protobuf:"fixed64,1,opt,name=connection_pool_critical,json=connectionPoolCritical,proto3" json:"connection_pool_critical,omitempty"`
-	SequentialScanThreshold int64                  `protobuf:"varint,2,opt,name=sequential_scan_threshold,json=sequentialScanThreshold,proto3" json:"sequential_scan_threshold,omitempty"`
-	SequentialScanDelta     float64                `protobuf:"fixed64,3,opt,name=sequential_scan_delta,json=sequentialScanDelta,proto3" json:"sequential_scan_delta,omitempty"`
-	P95LatencyMs            float64                `protobuf:"fixed64,4,opt,name=p95_latency_ms,json=p95LatencyMs,proto3" json:"p95_latency_ms,omitempty"`
-	CacheHitRateThreshold   float64                `protobuf:"fixed64,5,opt,name=cache_hit_rate_threshold,json=cacheHitRateThreshold,proto3" json:"cache_hit_rate_threshold,omitempty"`
-	unknownFields           protoimpl.UnknownFields
-	sizeCache               protoimpl.SizeCache
+type GetDatabasePolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseId    string                 `protobuf:"bytes,1,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DetectionThresholds) Reset() {
-	*x = DetectionThresholds{}
-	mi := &file_knowledge_proto_msgTypes[25]
+func (x *GetDatabasePolicyRequest) Reset() {
+	*x = GetDatabasePolicyRequest{}
+	mi := &file_knowledge_proto_msgTypes[66]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DetectionThresholds) String() string {
+func (x *GetDatabasePolicyRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DetectionThresholds) ProtoMessage() {}
+func (*GetDatabasePolicyRequest) ProtoMessage() {}
 
-func (x *DetectionThresholds) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[25]
+func (x *GetDatabasePolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[66]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1850,71 +4791,39 @@ func (x *DetectionThresholds) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DetectionThresholds.ProtoReflect.Descriptor instead.
-func (*DetectionThresholds) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{25}
-}
-
-func (x *DetectionThresholds) GetConnectionPoolCritical() float64 {
-	if x != nil {
-		return x.ConnectionPoolCritical
-	}
-	return 0
-}
-
-func (x *DetectionThresholds) GetSequentialScanThreshold() int64 {
-	if x != nil {
-		return x.SequentialScanThreshold
-	}
-	return 0
-}
-
-func (x *DetectionThresholds) GetSequentialScanDelta() float64 {
-	if x != nil {
-		return x.SequentialScanDelta
-	}
-	return 0
-}
-
-func (x *DetectionThresholds) GetP95LatencyMs() float64 {
-	if x != nil {
-		return x.P95LatencyMs
-	}
-	return 0
+// Deprecated: Use GetDatabasePolicyRequest.ProtoReflect.Descriptor instead.
+func (*GetDatabasePolicyRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{66}
 }
 
-func (x *DetectionThresholds) GetCacheHitRateThreshold() float64 {
+func (x *GetDatabasePolicyRequest) GetDatabaseId() string {
 	if x != nil {
-		return x.CacheHitRateThreshold
+		return x.DatabaseId
 	}
-	return 0
+	return ""
 }
 
-type WebhookConfig struct {
+type ListDatabasePoliciesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
-	AuthHeader    string                 `protobuf:"bytes,2,opt,name=auth_header,json=authHeader,proto3" json:"auth_header,omitempty"`
-	Enabled       bool                   `protobuf:"varint,3,opt,name=enabled,proto3" json:"enabled,omitempty"`
-	Events        []string               `protobuf:"bytes,4,rep,name=events,proto3" json:"events,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *WebhookConfig) Reset() {
-	*x = WebhookConfig{}
-	mi := &file_knowledge_proto_msgTypes[26]
+func (x *ListDatabasePoliciesRequest) Reset() {
+	*x = ListDatabasePoliciesRequest{}
+	mi := &file_knowledge_proto_msgTypes[67]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WebhookConfig) String() string {
+func (x *ListDatabasePoliciesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WebhookConfig) ProtoMessage() {}
+func (*ListDatabasePoliciesRequest) ProtoMessage() {}
 
-func (x *WebhookConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[26]
+func (x *ListDatabasePoliciesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[67]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1925,64 +4834,81 @@ func (x *WebhookConfig) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WebhookConfig.ProtoReflect.Descriptor instead.
-func (*WebhookConfig) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{26}
+// Deprecated: Use ListDatabasePoliciesRequest.ProtoReflect.Descriptor instead.
+func (*ListDatabasePoliciesRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{67}
 }
 
-func (x *WebhookConfig) GetUrl() string {
-	if x != nil {
-		return x.Url
-	}
-	return ""
+type ListDatabasePoliciesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Policies      []*DatabasePolicy      `protobuf:"bytes,1,rep,name=policies,proto3" json:"policies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *WebhookConfig) GetAuthHeader() string {
-	if x != nil {
-		return x.AuthHeader
-	}
-	return ""
+func (x *ListDatabasePoliciesResponse) Reset() {
+	*x = ListDatabasePoliciesResponse{}
+	mi := &file_knowledge_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *WebhookConfig) GetEnabled() bool {
-	if x != nil {
-		return x.Enabled
-	}
-	return false
+func (x *ListDatabasePoliciesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *WebhookConfig) GetEvents() []string {
+func (*ListDatabasePoliciesResponse) ProtoMessage() {}
+
+func (x *ListDatabasePoliciesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[68]
 	if x != nil {
-		return x.Events
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-type SystemConfig struct {
-	state              protoimpl.MessageState `protogen:"open.v1"`
-	Thresholds         *DetectionThresholds   `protobuf:"bytes,2,opt,name=thresholds,proto3" json:"thresholds,omitempty"`
-	OnboardingComplete bool                   `protobuf:"varint,3,opt,name=onboarding_complete,json=onboardingComplete,proto3" json:"onboarding_complete,omitempty"`
-	ExecutionMode      string                 `protobuf:"bytes,4,opt,name=execution_mode,json=executionMode,proto3" json:"execution_mode,omitempty"`
-	Webhook            *WebhookConfig         `protobuf:"bytes,5,opt,name=webhook,proto3" json:"webhook,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+// Deprecated: Use ListDatabasePoliciesResponse.ProtoReflect.Descriptor instead.
+func (*ListDatabasePoliciesResponse) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{68}
 }
 
-func (x *SystemConfig) Reset() {
-	*x = SystemConfig{}
-	mi := &file_knowledge_proto_msgTypes[27]
+func (x *ListDatabasePoliciesResponse) GetPolicies() []*DatabasePolicy {
+	if x != nil {
+		return x.Policies
+	}
+	return nil
+}
+
+// A detection key operators have accepted for a while, e.g. a nightly batch job's sequential scans
+type Suppression struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Suppression) Reset() {
+	*x = Suppression{}
+	mi := &file_knowledge_proto_msgTypes[69]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SystemConfig) String() string {
+func (x *Suppression) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SystemConfig) ProtoMessage() {}
+func (*Suppression) ProtoMessage() {}
 
-func (x *SystemConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[27]
+func (x *Suppression) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[69]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1993,63 +4919,63 @@ func (x *SystemConfig) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SystemConfig.ProtoReflect.Descriptor instead.
-func (*SystemConfig) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{27}
+// Deprecated: Use Suppression.ProtoReflect.Descriptor instead.
+func (*Suppression) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{69}
 }
 
-func (x *SystemConfig) GetThresholds() *DetectionThresholds {
+func (x *Suppression) GetKey() string {
 	if x != nil {
-		return x.Thresholds
+		return x.Key
 	}
-	return nil
+	return ""
 }
 
-func (x *SystemConfig) GetOnboardingComplete() bool {
+func (x *Suppression) GetReason() string {
 	if x != nil {
-		return x.OnboardingComplete
+		return x.Reason
 	}
-	return false
+	return ""
 }
 
-func (x *SystemConfig) GetExecutionMode() string {
+func (x *Suppression) GetCreatedAt() int64 {
 	if x != nil {
-		return x.ExecutionMode
+		return x.CreatedAt
 	}
-	return ""
+	return 0
 }
 
-func (x *SystemConfig) GetWebhook() *WebhookConfig {
+func (x *Suppression) GetExpiresAt() int64 {
 	if x != nil {
-		return x.Webhook
+		return x.ExpiresAt
 	}
-	return nil
+	return 0
 }
 
-type SystemStatus struct {
-	state              protoimpl.MessageState `protogen:"open.v1"`
-	Configured         bool                   `protobuf:"varint,1,opt,name=configured,proto3" json:"configured,omitempty"`
-	OnboardingComplete bool                   `protobuf:"varint,2,opt,name=onboarding_complete,json=onboardingComplete,proto3" json:"onboarding_complete,omitempty"`
-	ServiceStates      map[string]string      `protobuf:"bytes,3,rep,name=service_states,json=serviceStates,proto3" json:"service_states,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+type SuppressDetectionKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	DurationSecs  int64                  `protobuf:"varint,2,opt,name=duration_secs,json=durationSecs,proto3" json:"duration_secs,omitempty"` // Zero lifts an existing suppression
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SystemStatus) Reset() {
-	*x = SystemStatus{}
-	mi := &file_knowledge_proto_msgTypes[28]
+func (x *SuppressDetectionKeyRequest) Reset() {
+	*x = SuppressDetectionKeyRequest{}
+	mi := &file_knowledge_proto_msgTypes[70]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SystemStatus) String() string {
+func (x *SuppressDetectionKeyRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SystemStatus) ProtoMessage() {}
+func (*SuppressDetectionKeyRequest) ProtoMessage() {}
 
-func (x *SystemStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[28]
+func (x *SuppressDetectionKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[70]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2060,53 +4986,53 @@ func (x *SystemStatus) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SystemStatus.ProtoReflect.Descriptor instead.
-func (*SystemStatus) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{28}
+// Deprecated: Use SuppressDetectionKeyRequest.ProtoReflect.Descriptor instead.
+func (*SuppressDetectionKeyRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{70}
 }
 
-func (x *SystemStatus) GetConfigured() bool {
+func (x *SuppressDetectionKeyRequest) GetKey() string {
 	if x != nil {
-		return x.Configured
+		return x.Key
 	}
-	return false
+	return ""
 }
 
-func (x *SystemStatus) GetOnboardingComplete() bool {
+func (x *SuppressDetectionKeyRequest) GetDurationSecs() int64 {
 	if x != nil {
-		return x.OnboardingComplete
+		return x.DurationSecs
 	}
-	return false
+	return 0
 }
 
-func (x *SystemStatus) GetServiceStates() map[string]string {
+func (x *SuppressDetectionKeyRequest) GetReason() string {
 	if x != nil {
-		return x.ServiceStates
+		return x.Reason
 	}
-	return nil
+	return ""
 }
 
-type GetSystemConfigRequest struct {
+type ListSuppressionsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetSystemConfigRequest) Reset() {
-	*x = GetSystemConfigRequest{}
-	mi := &file_knowledge_proto_msgTypes[29]
+func (x *ListSuppressionsRequest) Reset() {
+	*x = ListSuppressionsRequest{}
+	mi := &file_knowledge_proto_msgTypes[71]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetSystemConfigRequest) String() string {
+func (x *ListSuppressionsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSystemConfigRequest) ProtoMessage() {}
+func (*ListSuppressionsRequest) ProtoMessage() {}
 
-func (x *GetSystemConfigRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[29]
+func (x *ListSuppressionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[71]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2117,33 +5043,33 @@ func (x *GetSystemConfigRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSystemConfigRequest.ProtoReflect.Descriptor instead.
-func (*GetSystemConfigRequest) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{29}
+// Deprecated: Use ListSuppressionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSuppressionsRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{71}
 }
 
-type SaveSystemConfigRequest struct {
+type ListSuppressionsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Config        *SystemConfig          `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	Suppressions  []*Suppression         `protobuf:"bytes,1,rep,name=suppressions,proto3" json:"suppressions,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SaveSystemConfigRequest) Reset() {
-	*x = SaveSystemConfigRequest{}
-	mi := &file_knowledge_proto_msgTypes[30]
+func (x *ListSuppressionsResponse) Reset() {
+	*x = ListSuppressionsResponse{}
+	mi := &file_knowledge_proto_msgTypes[72]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SaveSystemConfigRequest) String() string {
+func (x *ListSuppressionsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SaveSystemConfigRequest) ProtoMessage() {}
+func (*ListSuppressionsResponse) ProtoMessage() {}
 
-func (x *SaveSystemConfigRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[30]
+func (x *ListSuppressionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[72]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2154,39 +5080,39 @@ func (x *SaveSystemConfigRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SaveSystemConfigRequest.ProtoReflect.Descriptor instead.
-func (*SaveSystemConfigRequest) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{30}
+// Deprecated: Use ListSuppressionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSuppressionsResponse) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{72}
 }
 
-func (x *SaveSystemConfigRequest) GetConfig() *SystemConfig {
+func (x *ListSuppressionsResponse) GetSuppressions() []*Suppression {
 	if x != nil {
-		return x.Config
+		return x.Suppressions
 	}
 	return nil
 }
 
-type GetSystemStatusRequest struct {
+type FlushAllDataRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetSystemStatusRequest) Reset() {
-	*x = GetSystemStatusRequest{}
-	mi := &file_knowledge_proto_msgTypes[31]
+func (x *FlushAllDataRequest) Reset() {
+	*x = FlushAllDataRequest{}
+	mi := &file_knowledge_proto_msgTypes[73]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetSystemStatusRequest) String() string {
+func (x *FlushAllDataRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSystemStatusRequest) ProtoMessage() {}
+func (*FlushAllDataRequest) ProtoMessage() {}
 
-func (x *GetSystemStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[31]
+func (x *FlushAllDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[73]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2197,32 +5123,34 @@ func (x *GetSystemStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSystemStatusRequest.ProtoReflect.Descriptor instead.
-func (*GetSystemStatusRequest) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{31}
+// Deprecated: Use FlushAllDataRequest.ProtoReflect.Descriptor instead.
+func (*FlushAllDataRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{73}
 }
 
-type FlushAllDataRequest struct {
+type FlushAllDataResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *FlushAllDataRequest) Reset() {
-	*x = FlushAllDataRequest{}
-	mi := &file_knowledge_proto_msgTypes[32]
+func (x *FlushAllDataResponse) Reset() {
+	*x = FlushAllDataResponse{}
+	mi := &file_knowledge_proto_msgTypes[74]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *FlushAllDataRequest) String() string {
+func (x *FlushAllDataResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FlushAllDataRequest) ProtoMessage() {}
+func (*FlushAllDataResponse) ProtoMessage() {}
 
-func (x *FlushAllDataRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[32]
+func (x *FlushAllDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[74]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2233,34 +5161,47 @@ func (x *FlushAllDataRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FlushAllDataRequest.ProtoReflect.Descriptor instead.
-func (*FlushAllDataRequest) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{32}
+// Deprecated: Use FlushAllDataResponse.ProtoReflect.Descriptor instead.
+func (*FlushAllDataResponse) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{74}
 }
 
-type FlushAllDataResponse struct {
+func (x *FlushAllDataResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *FlushAllDataResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// Retention cleanup messages
+type ForceCleanupRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *FlushAllDataResponse) Reset() {
-	*x = FlushAllDataResponse{}
-	mi := &file_knowledge_proto_msgTypes[33]
+func (x *ForceCleanupRequest) Reset() {
+	*x = ForceCleanupRequest{}
+	mi := &file_knowledge_proto_msgTypes[75]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *FlushAllDataResponse) String() string {
+func (x *ForceCleanupRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FlushAllDataResponse) ProtoMessage() {}
+func (*ForceCleanupRequest) ProtoMessage() {}
 
-func (x *FlushAllDataResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[33]
+func (x *ForceCleanupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[75]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2271,25 +5212,87 @@ func (x *FlushAllDataResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FlushAllDataResponse.ProtoReflect.Descriptor instead.
-func (*FlushAllDataResponse) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{33}
+// Deprecated: Use ForceCleanupRequest.ProtoReflect.Descriptor instead.
+func (*ForceCleanupRequest) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{75}
 }
 
-func (x *FlushAllDataResponse) GetSuccess() bool {
+type ForceCleanupResponse struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Success                bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message                string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	ActionsPurged          int32                  `protobuf:"varint,3,opt,name=actions_purged,json=actionsPurged,proto3" json:"actions_purged,omitempty"`
+	DetectionsPurged       int32                  `protobuf:"varint,4,opt,name=detections_purged,json=detectionsPurged,proto3" json:"detections_purged,omitempty"`
+	DanglingEntriesRemoved int32                  `protobuf:"varint,5,opt,name=dangling_entries_removed,json=danglingEntriesRemoved,proto3" json:"dangling_entries_removed,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *ForceCleanupResponse) Reset() {
+	*x = ForceCleanupResponse{}
+	mi := &file_knowledge_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForceCleanupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForceCleanupResponse) ProtoMessage() {}
+
+func (x *ForceCleanupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_knowledge_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForceCleanupResponse.ProtoReflect.Descriptor instead.
+func (*ForceCleanupResponse) Descriptor() ([]byte, []int) {
+	return file_knowledge_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *ForceCleanupResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *FlushAllDataResponse) GetMessage() string {
+func (x *ForceCleanupResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
+func (x *ForceCleanupResponse) GetActionsPurged() int32 {
+	if x != nil {
+		return x.ActionsPurged
+	}
+	return 0
+}
+
+func (x *ForceCleanupResponse) GetDetectionsPurged() int32 {
+	if x != nil {
+		return x.DetectionsPurged
+	}
+	return 0
+}
+
+func (x *ForceCleanupResponse) GetDanglingEntriesRemoved() int32 {
+	if x != nil {
+		return x.DanglingEntriesRemoved
+	}
+	return 0
+}
+
 // Generic response
 type Response struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -2301,7 +5304,7 @@ type Response struct {
 
 func (x *Response) Reset() {
 	*x = Response{}
-	mi := &file_knowledge_proto_msgTypes[34]
+	mi := &file_knowledge_proto_msgTypes[77]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2313,7 +5316,7 @@ func (x *Response) String() string {
 func (*Response) ProtoMessage() {}
 
 func (x *Response) ProtoReflect() protoreflect.Message {
-	mi := &file_knowledge_proto_msgTypes[34]
+	mi := &file_knowledge_proto_msgTypes[77]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2326,7 +5329,7 @@ func (x *Response) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Response.ProtoReflect.Descriptor instead.
 func (*Response) Descriptor() ([]byte, []int) {
-	return file_knowledge_proto_rawDescGZIP(), []int{34}
+	return file_knowledge_proto_rawDescGZIP(), []int{77}
 }
 
 func (x *Response) GetSuccess() bool {
@@ -2347,7 +5350,7 @@ var File_knowledge_proto protoreflect.FileDescriptor
 
 const file_knowledge_proto_rawDesc = "" +
 	"\n" +
-	"\x0fknowledge.proto\x12\tknowledge\"\xca\x01\n" +
+	"\x0fknowledge.proto\x12\tknowledge\"\x90\x03\n" +
 	"\x18RegisterDetectionRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
 	"\x03key\x18\x02 \x01(\tR\x03key\x12\x1a\n" +
@@ -2357,23 +5360,50 @@ const file_knowledge_proto_rawDesc = "" +
 	"databaseId\x12\x14\n" +
 	"\x05value\x18\x06 \x01(\x01R\x05value\x12\x1d\n" +
 	"\n" +
-	"created_at\x18\a \x01(\x03R\tcreatedAt\"'\n" +
+	"created_at\x18\a \x01(\x03R\tcreatedAt\x12\x14\n" +
+	"\x05title\x18\b \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\t \x01(\tR\vdescription\x12&\n" +
+	"\x0erecommendation\x18\n" +
+	" \x01(\tR\x0erecommendation\x12\x1f\n" +
+	"\vaction_type\x18\v \x01(\tR\n" +
+	"actionType\x12\x1a\n" +
+	"\bevidence\x18\f \x01(\tR\bevidence\x12'\n" +
+	"\x0faction_metadata\x18\r \x01(\tR\x0eactionMetadata\"Y\n" +
 	"\x13DetectionKeyRequest\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\"Y\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x1a\n" +
+	"\bseverity\x18\x02 \x01(\tR\bseverity\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\x01R\x05value\"\xda\x01\n" +
 	"\x17DetectionStatusResponse\x12\x1b\n" +
 	"\tis_active\x18\x01 \x01(\bR\bisActive\x12!\n" +
-	"\fdetection_id\x18\x02 \x01(\tR\vdetectionId\"8\n" +
+	"\fdetection_id\x18\x02 \x01(\tR\vdetectionId\x12\x1a\n" +
+	"\bseverity\x18\x03 \x01(\tR\bseverity\x12\x14\n" +
+	"\x05value\x18\x04 \x01(\x01R\x05value\x12\x1e\n" +
+	"\n" +
+	"suppressed\x18\x05 \x01(\bR\n" +
+	"suppressed\x12-\n" +
+	"\x12suppression_reason\x18\x06 \x01(\tR\x11suppressionReason\"u\n" +
+	"\x1eUpdateDetectionSeverityRequest\x12!\n" +
+	"\fdetection_id\x18\x01 \x01(\tR\vdetectionId\x12\x1a\n" +
+	"\bseverity\x18\x02 \x01(\tR\bseverity\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\x01R\x05value\"8\n" +
 	"\x15DatabaseFilterRequest\x12\x1f\n" +
 	"\vdatabase_id\x18\x01 \x01(\tR\n" +
 	"databaseId\"j\n" +
 	"\x11DetectionResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12!\n" +
-	"\fdetection_id\x18\x03 \x01(\tR\vdetectionId\"M\n" +
+	"\fdetection_id\x18\x03 \x01(\tR\vdetectionId\"\x9a\x01\n" +
+	"!RegisterDetectionIfAbsentResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1e\n" +
+	"\n" +
+	"registered\x18\x03 \x01(\bR\n" +
+	"registered\x12!\n" +
+	"\fdetection_id\x18\x04 \x01(\tR\vdetectionId\"M\n" +
 	"\x15DetectionListResponse\x124\n" +
 	"\n" +
 	"detections\x18\x01 \x03(\v2\x14.knowledge.DetectionR\n" +
-	"detections\"\xac\x02\n" +
+	"detections\"\xf3\x04\n" +
 	"\tDetection\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
 	"\x03key\x18\x02 \x01(\tR\x03key\x12\x14\n" +
@@ -2389,10 +5419,29 @@ const file_knowledge_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\n" +
 	" \x01(\x03R\tcreatedAt\x12\x1b\n" +
-	"\tlast_seen\x18\v \x01(\x03R\blastSeen\"X\n" +
+	"\tlast_seen\x18\v \x01(\x03R\blastSeen\x12%\n" +
+	"\x0efailure_reason\x18\f \x01(\tR\rfailureReason\x12-\n" +
+	"\x12suppression_reason\x18\r \x01(\tR\x11suppressionReason\x12)\n" +
+	"\x10suppressed_until\x18\x0e \x01(\x03R\x0fsuppressedUntil\x12\x14\n" +
+	"\x05title\x18\x0f \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x10 \x01(\tR\vdescription\x12&\n" +
+	"\x0erecommendation\x18\x11 \x01(\tR\x0erecommendation\x12\x1f\n" +
+	"\vaction_type\x18\x12 \x01(\tR\n" +
+	"actionType\x12\x1a\n" +
+	"\bevidence\x18\x13 \x01(\tR\bevidence\x12'\n" +
+	"\x0faction_metadata\x18\x14 \x01(\tR\x0eactionMetadata\"X\n" +
 	"\x17ResolveDetectionRequest\x12!\n" +
 	"\fdetection_id\x18\x01 \x01(\tR\vdetectionId\x12\x1a\n" +
-	"\bsolution\x18\x02 \x01(\tR\bsolution\"\xab\x01\n" +
+	"\bsolution\x18\x02 \x01(\tR\bsolution\"S\n" +
+	"\x16ReopenDetectionRequest\x12!\n" +
+	"\fdetection_id\x18\x01 \x01(\tR\vdetectionId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"Y\n" +
+	"\x1cUnactionableDetectionRequest\x12!\n" +
+	"\fdetection_id\x18\x01 \x01(\tR\vdetectionId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"X\n" +
+	"\x1bAcknowledgeDetectionRequest\x12!\n" +
+	"\fdetection_id\x18\x01 \x01(\tR\vdetectionId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\xc8\x02\n" +
 	"\x15RegisterActionRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
 	"\fdetection_id\x18\x02 \x01(\tR\vdetectionId\x12\x1f\n" +
@@ -2401,19 +5450,117 @@ const file_knowledge_proto_rawDesc = "" +
 	"\vdatabase_id\x18\x04 \x01(\tR\n" +
 	"databaseId\x12\x1d\n" +
 	"\n" +
-	"created_at\x18\x05 \x01(\x03R\tcreatedAt\"a\n" +
+	"created_at\x18\x05 \x01(\x03R\tcreatedAt\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12!\n" +
+	"\faction_state\x18\a \x01(\tR\vactionState\x12\x16\n" +
+	"\x06source\x18\b \x01(\tR\x06source\x12'\n" +
+	"\x0fpolicy_decision\x18\t \x01(\tR\x0epolicyDecision\x12\x1f\n" +
+	"\vpolicy_rule\x18\n" +
+	" \x01(\tR\n" +
+	"policyRule\"a\n" +
 	"\x0eActionResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1b\n" +
-	"\taction_id\x18\x03 \x01(\tR\bactionId\"\x98\x01\n" +
+	"\taction_id\x18\x03 \x01(\tR\bactionId\"\xc3\x02\n" +
 	"\x13UpdateActionRequest\x12\x1b\n" +
 	"\taction_id\x18\x01 \x01(\tR\bactionId\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\tR\x06status\x12\x18\n" +
 	"\amessage\x18\x03 \x01(\tR\amessage\x12\x14\n" +
 	"\x05error\x18\x04 \x01(\tR\x05error\x12\x1c\n" +
-	"\ttimestamp\x18\x05 \x01(\x03R\ttimestamp\"A\n" +
+	"\ttimestamp\x18\x05 \x01(\x03R\ttimestamp\x12\x18\n" +
+	"\achanges\x18\x06 \x01(\tR\achanges\x12!\n" +
+	"\fcan_rollback\x18\a \x01(\bR\vcanRollback\x125\n" +
+	"\bprogress\x18\b \x01(\v2\x19.knowledge.ActionProgressR\bprogress\x12\x16\n" +
+	"\x06source\x18\t \x01(\tR\x06source\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\n" +
+	" \x01(\tR\terrorCode\"\xcd\x01\n" +
+	"\x0eActionProgress\x12\x14\n" +
+	"\x05phase\x18\x01 \x01(\tR\x05phase\x12\x1f\n" +
+	"\vblocks_done\x18\x02 \x01(\x03R\n" +
+	"blocksDone\x12!\n" +
+	"\fblocks_total\x18\x03 \x01(\x03R\vblocksTotal\x12\x1f\n" +
+	"\vtuples_done\x18\x04 \x01(\x03R\n" +
+	"tuplesDone\x12!\n" +
+	"\ftuples_total\x18\x05 \x01(\x03R\vtuplesTotal\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\x03R\tupdatedAt\"\xaa\x01\n" +
+	"\rActionOutcome\x12\x16\n" +
+	"\x06metric\x18\x01 \x01(\tR\x06metric\x12#\n" +
+	"\rmetric_before\x18\x02 \x01(\x01R\fmetricBefore\x12!\n" +
+	"\fmetric_after\x18\x03 \x01(\x01R\vmetricAfter\x12\x18\n" +
+	"\averdict\x18\x04 \x01(\tR\averdict\x12\x1f\n" +
+	"\vrecorded_at\x18\x05 \x01(\x03R\n" +
+	"recordedAt\"\xb3\x01\n" +
+	"\x1aRecordActionOutcomeRequest\x12\x1b\n" +
+	"\taction_id\x18\x01 \x01(\tR\bactionId\x12\x16\n" +
+	"\x06metric\x18\x02 \x01(\tR\x06metric\x12#\n" +
+	"\rmetric_before\x18\x03 \x01(\x01R\fmetricBefore\x12!\n" +
+	"\fmetric_after\x18\x04 \x01(\x01R\vmetricAfter\x12\x18\n" +
+	"\averdict\x18\x05 \x01(\tR\averdict\"/\n" +
+	"\x10GetActionRequest\x12\x1b\n" +
+	"\taction_id\x18\x01 \x01(\tR\bactionId\"T\n" +
+	"\x11GetActionResponse\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05found\x12)\n" +
+	"\x06action\x18\x02 \x01(\v2\x11.knowledge.ActionR\x06action\"6\n" +
+	"\x17GetActionHistoryRequest\x12\x1b\n" +
+	"\taction_id\x18\x01 \x01(\tR\bactionId\"\xb1\x01\n" +
+	"\x12ActionHistoryEntry\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\x12\x1c\n" +
+	"\ttimestamp\x18\x05 \x01(\x03R\ttimestamp\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x06 \x01(\tR\terrorCode\"S\n" +
+	"\x18GetActionHistoryResponse\x127\n" +
+	"\aentries\x18\x01 \x03(\v2\x1d.knowledge.ActionHistoryEntryR\aentries\"Y\n" +
+	"\x1aListActionsByStatusRequest\x12\x1a\n" +
+	"\bstatuses\x18\x01 \x03(\tR\bstatuses\x12\x1f\n" +
+	"\vdatabase_id\x18\x02 \x01(\tR\n" +
+	"databaseId\"(\n" +
+	"&GetPendingImplementationSummaryRequest\"p\n" +
+	"'GetPendingImplementationSummaryResponse\x12E\n" +
+	"\tsummaries\x18\x01 \x03(\v2'.knowledge.PendingImplementationSummaryR\tsummaries\"\xa4\x01\n" +
+	"\x1cPendingImplementationSummary\x12\x1f\n" +
+	"\vaction_type\x18\x01 \x01(\tR\n" +
+	"actionType\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\x12!\n" +
+	"\fdatabase_ids\x18\x03 \x03(\tR\vdatabaseIds\x12*\n" +
+	"\x11last_requested_at\x18\x04 \x01(\x03R\x0flastRequestedAt\"z\n" +
+	"\x1bRecordDatabaseActionRequest\x12\x1f\n" +
+	"\vdatabase_id\x18\x01 \x01(\tR\n" +
+	"databaseId\x12\x1b\n" +
+	"\taction_id\x18\x02 \x01(\tR\bactionId\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\x03 \x01(\x03R\tstartedAt\"U\n" +
+	"\x1cRecentDatabaseActionsRequest\x12\x1f\n" +
+	"\vdatabase_id\x18\x01 \x01(\tR\n" +
+	"databaseId\x12\x14\n" +
+	"\x05since\x18\x02 \x01(\x03R\x05since\">\n" +
+	"\x1dRecentDatabaseActionsResponse\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\x01 \x03(\x03R\tstartedAt\"\xc5\x01\n" +
+	"\fMetricSample\x12\x1f\n" +
+	"\vdatabase_id\x18\x01 \x01(\tR\n" +
+	"databaseId\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\x12;\n" +
+	"\x06values\x18\x03 \x03(\v2#.knowledge.MetricSample.ValuesEntryR\x06values\x1a9\n" +
+	"\vValuesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"\x9d\x01\n" +
+	"\x17GetMetricHistoryRequest\x12\x1f\n" +
+	"\vdatabase_id\x18\x01 \x01(\tR\n" +
+	"databaseId\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x02 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x03 \x01(\x03R\aendTime\x12'\n" +
+	"\x0fresolution_secs\x18\x04 \x01(\x03R\x0eresolutionSecs\"v\n" +
+	"\x18GetMetricHistoryResponse\x121\n" +
+	"\asamples\x18\x01 \x03(\v2\x17.knowledge.MetricSampleR\asamples\x12'\n" +
+	"\x0fresolution_secs\x18\x02 \x01(\x03R\x0eresolutionSecs\"A\n" +
 	"\x12ActionListResponse\x12+\n" +
-	"\aactions\x18\x01 \x03(\v2\x11.knowledge.ActionR\aactions\"\xb4\x01\n" +
+	"\aactions\x18\x01 \x03(\v2\x11.knowledge.ActionR\aactions\"\xd3\x04\n" +
 	"\x06Action\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
 	"\fdetection_id\x18\x02 \x01(\tR\vdetectionId\x12\x1f\n" +
@@ -2423,7 +5570,22 @@ const file_knowledge_proto_rawDesc = "" +
 	"databaseId\x12\x16\n" +
 	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1d\n" +
 	"\n" +
-	"created_at\x18\x06 \x01(\x03R\tcreatedAt\"\xbd\x03\n" +
+	"created_at\x18\x06 \x01(\x03R\tcreatedAt\x12\x18\n" +
+	"\amessage\x18\a \x01(\tR\amessage\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\x12!\n" +
+	"\faction_state\x18\t \x01(\tR\vactionState\x12\x18\n" +
+	"\achanges\x18\n" +
+	" \x01(\tR\achanges\x12!\n" +
+	"\fcan_rollback\x18\v \x01(\bR\vcanRollback\x12!\n" +
+	"\fcompleted_at\x18\f \x01(\x03R\vcompletedAt\x125\n" +
+	"\bprogress\x18\r \x01(\v2\x19.knowledge.ActionProgressR\bprogress\x12\x16\n" +
+	"\x06source\x18\x0e \x01(\tR\x06source\x122\n" +
+	"\aoutcome\x18\x0f \x01(\v2\x18.knowledge.ActionOutcomeR\aoutcome\x12'\n" +
+	"\x0fpolicy_decision\x18\x10 \x01(\tR\x0epolicyDecision\x12\x1f\n" +
+	"\vpolicy_rule\x18\x11 \x01(\tR\n" +
+	"policyRule\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x12 \x01(\tR\terrorCode\"\xbd\x03\n" +
 	"\x17RegisterDatabaseRequest\x12\x1f\n" +
 	"\vdatabase_id\x18\x01 \x01(\tR\n" +
 	"databaseId\x12+\n" +
@@ -2442,10 +5604,11 @@ const file_knowledge_proto_rawDesc = "" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"F\n" +
 	"\x10DatabaseResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"5\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"M\n" +
 	"\x12GetDatabaseRequest\x12\x1f\n" +
 	"\vdatabase_id\x18\x01 \x01(\tR\n" +
-	"databaseId\"\xa3\x04\n" +
+	"databaseId\x12\x16\n" +
+	"\x06redact\x18\x02 \x01(\bR\x06redact\"\xa3\x04\n" +
 	"\x13GetDatabaseResponse\x12\x14\n" +
 	"\x05found\x18\x01 \x01(\bR\x05found\x12\x1f\n" +
 	"\vdatabase_id\x18\x02 \x01(\tR\n" +
@@ -2465,11 +5628,22 @@ const file_knowledge_proto_rawDesc = "" +
 	"\aenabled\x18\x0e \x01(\bR\aenabled\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"9\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x7f\n" +
 	"\x14ListDatabasesRequest\x12!\n" +
-	"\fenabled_only\x18\x01 \x01(\bR\venabledOnly\"S\n" +
+	"\fenabled_only\x18\x01 \x01(\bR\venabledOnly\x12\x16\n" +
+	"\x06cursor\x18\x02 \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06redact\x18\x04 \x01(\bR\x06redact\"\xa9\x01\n" +
+	"\x19GetDatabasesByTypeRequest\x12#\n" +
+	"\rdatabase_type\x18\x01 \x01(\tR\fdatabaseType\x12!\n" +
+	"\fenabled_only\x18\x02 \x01(\bR\venabledOnly\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06redact\x18\x05 \x01(\bR\x06redact\"t\n" +
 	"\x14DatabaseListResponse\x12;\n" +
-	"\tdatabases\x18\x01 \x03(\v2\x1d.knowledge.RegisteredDatabaseR\tdatabases\"\x85\x03\n" +
+	"\tdatabases\x18\x01 \x03(\v2\x1d.knowledge.RegisteredDatabaseR\tdatabases\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"\x8b\x04\n" +
 	"\x12RegisteredDatabase\x12\x1f\n" +
 	"\vdatabase_id\x18\x01 \x01(\tR\n" +
 	"databaseId\x12#\n" +
@@ -2484,23 +5658,31 @@ const file_knowledge_proto_rawDesc = "" +
 	"\fhealth_score\x18\n" +
 	" \x01(\x01R\vhealthScore\x12\x18\n" +
 	"\aenabled\x18\v \x01(\bR\aenabled\x12+\n" +
-	"\x11connection_string\x18\f \x01(\tR\x10connectionString\"\x96\x01\n" +
+	"\x11connection_string\x18\f \x01(\tR\x10connectionString\x12G\n" +
+	"\bmetadata\x18\r \x03(\v2+.knowledge.RegisteredDatabase.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x96\x01\n" +
 	"\x1bUpdateDatabaseHealthRequest\x12\x1f\n" +
 	"\vdatabase_id\x18\x01 \x01(\tR\n" +
 	"databaseId\x12\x1b\n" +
 	"\tlast_seen\x18\x02 \x01(\x03R\blastSeen\x12\x16\n" +
 	"\x06status\x18\x03 \x01(\tR\x06status\x12!\n" +
-	"\fhealth_score\x18\x04 \x01(\x01R\vhealthScore\"\xa4\x01\n" +
+	"\fhealth_score\x18\x04 \x01(\x01R\vhealthScore\"\xad\x02\n" +
 	"\x15UpdateDatabaseRequest\x12\x1f\n" +
 	"\vdatabase_id\x18\x01 \x01(\tR\n" +
 	"databaseId\x12+\n" +
 	"\x11connection_string\x18\x02 \x01(\tR\x10connectionString\x12#\n" +
 	"\rdatabase_name\x18\x03 \x01(\tR\fdatabaseName\x12\x18\n" +
-	"\aenabled\x18\x04 \x01(\bR\aenabled\"<\n" +
+	"\aenabled\x18\x04 \x01(\bR\aenabled\x12J\n" +
+	"\bmetadata\x18\x05 \x03(\v2..knowledge.UpdateDatabaseRequest.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"<\n" +
 	"\x19UnregisterDatabaseRequest\x12\x1f\n" +
 	"\vdatabase_id\x18\x01 \x01(\tR\n" +
 	"databaseId\"\x17\n" +
-	"\x15GetSystemStatsRequest\"\xc7\x04\n" +
+	"\x15GetSystemStatsRequest\"\xa0\r\n" +
 	"\x16GetSystemStatsResponse\x12'\n" +
 	"\x0ftotal_databases\x18\x01 \x01(\x05R\x0etotalDatabases\x12+\n" +
 	"\x11healthy_databases\x18\x02 \x01(\x05R\x10healthyDatabases\x12-\n" +
@@ -2515,7 +5697,56 @@ const file_knowledge_proto_rawDesc = "" +
 	" \x01(\x05R\x10actionsExecuting\x12+\n" +
 	"\x11actions_completed\x18\v \x01(\x05R\x10actionsCompleted\x12%\n" +
 	"\x0eactions_failed\x18\f \x01(\x05R\ractionsFailed\x12%\n" +
-	"\x0euptime_seconds\x18\r \x01(\x03R\ruptimeSeconds\"\x9e\x02\n" +
+	"\x0euptime_seconds\x18\r \x01(\x03R\ruptimeSeconds\x12\x84\x01\n" +
+	"\x1dactive_detections_by_database\x18\x0e \x03(\v2A.knowledge.GetSystemStatsResponse.ActiveDetectionsByDatabaseEntryR\x1aactiveDetectionsByDatabase\x12\x84\x01\n" +
+	"\x1dactive_detections_by_category\x18\x0f \x03(\v2A.knowledge.GetSystemStatsResponse.ActiveDetectionsByCategoryEntryR\x1aactiveDetectionsByCategory\x12\x8a\x01\n" +
+	"\x1fresolved_detections_by_database\x18\x10 \x03(\v2C.knowledge.GetSystemStatsResponse.ResolvedDetectionsByDatabaseEntryR\x1cresolvedDetectionsByDatabase\x12\x8a\x01\n" +
+	"\x1fresolved_detections_by_category\x18\x11 \x03(\v2C.knowledge.GetSystemStatsResponse.ResolvedDetectionsByCategoryEntryR\x1cresolvedDetectionsByCategory\x129\n" +
+	"\x19actions_executed_last_day\x18\x12 \x01(\x05R\x16actionsExecutedLastDay\x12%\n" +
+	"\x0eactions_purged\x18\x13 \x01(\x03R\ractionsPurged\x12+\n" +
+	"\x11detections_purged\x18\x14 \x01(\x03R\x10detectionsPurged\x128\n" +
+	"\x18dangling_entries_removed\x18\x15 \x01(\x03R\x16danglingEntriesRemoved\x12&\n" +
+	"\x0flast_cleanup_at\x18\x16 \x01(\x03R\rlastCleanupAt\x1aM\n" +
+	"\x1fActiveDetectionsByDatabaseEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1aM\n" +
+	"\x1fActiveDetectionsByCategoryEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1aO\n" +
+	"!ResolvedDetectionsByDatabaseEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1aO\n" +
+	"!ResolvedDetectionsByCategoryEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"\x80\x01\n" +
+	"\x15GetActionStatsRequest\x12%\n" +
+	"\x0ewindow_seconds\x18\x01 \x01(\x03R\rwindowSeconds\x12\x1f\n" +
+	"\vdatabase_id\x18\x02 \x01(\tR\n" +
+	"databaseId\x12\x1f\n" +
+	"\vaction_type\x18\x03 \x01(\tR\n" +
+	"actionType\"\xa6\x03\n" +
+	"\vActionStats\x12\x1f\n" +
+	"\vaction_type\x18\x01 \x01(\tR\n" +
+	"actionType\x12\x1f\n" +
+	"\vdatabase_id\x18\x02 \x01(\tR\n" +
+	"databaseId\x12\x1a\n" +
+	"\battempts\x18\x03 \x01(\x03R\battempts\x12\x1c\n" +
+	"\tsuccesses\x18\x04 \x01(\x03R\tsuccesses\x12\x1a\n" +
+	"\bfailures\x18\x05 \x01(\x03R\bfailures\x12\x1c\n" +
+	"\trollbacks\x18\x06 \x01(\x03R\trollbacks\x12-\n" +
+	"\x12verified_effective\x18\a \x01(\x03R\x11verifiedEffective\x121\n" +
+	"\x14verified_ineffective\x18\b \x01(\x03R\x13verifiedIneffective\x12!\n" +
+	"\fsuccess_rate\x18\t \x01(\x01R\vsuccessRate\x122\n" +
+	"\x15verified_success_rate\x18\n" +
+	" \x01(\x01R\x13verifiedSuccessRate\x12(\n" +
+	"\x10avg_execution_ms\x18\v \x01(\x01R\x0eavgExecutionMs\"\xba\x02\n" +
+	"\x16GetActionStatsResponse\x12<\n" +
+	"\x0eby_action_type\x18\x01 \x03(\v2\x16.knowledge.ActionStatsR\fbyActionType\x127\n" +
+	"\vby_database\x18\x02 \x03(\v2\x16.knowledge.ActionStatsR\n" +
+	"byDatabase\x12T\n" +
+	"\x1bby_action_type_and_database\x18\x03 \x03(\v2\x16.knowledge.ActionStatsR\x17byActionTypeAndDatabase\x12,\n" +
+	"\x05total\x18\x04 \x01(\v2\x16.knowledge.ActionStatsR\x05total\x12%\n" +
+	"\x0ewindow_seconds\x18\x05 \x01(\x03R\rwindowSeconds\"\x9e\x02\n" +
 	"\x13DetectionThresholds\x128\n" +
 	"\x18connection_pool_critical\x18\x01 \x01(\x01R\x16connectionPoolCritical\x12:\n" +
 	"\x19sequential_scan_threshold\x18\x02 \x01(\x03R\x17sequentialScanThreshold\x122\n" +
@@ -2534,45 +5765,137 @@ const file_knowledge_proto_rawDesc = "" +
 	"thresholds\x12/\n" +
 	"\x13onboarding_complete\x18\x03 \x01(\bR\x12onboardingComplete\x12%\n" +
 	"\x0eexecution_mode\x18\x04 \x01(\tR\rexecutionMode\x122\n" +
-	"\awebhook\x18\x05 \x01(\v2\x18.knowledge.WebhookConfigR\awebhookJ\x04\b\x01\x10\x02\"\xf4\x01\n" +
+	"\awebhook\x18\x05 \x01(\v2\x18.knowledge.WebhookConfigR\awebhookJ\x04\b\x01\x10\x02\"\xb2\x02\n" +
 	"\fSystemStatus\x12\x1e\n" +
 	"\n" +
 	"configured\x18\x01 \x01(\bR\n" +
 	"configured\x12/\n" +
 	"\x13onboarding_complete\x18\x02 \x01(\bR\x12onboardingComplete\x12Q\n" +
-	"\x0eservice_states\x18\x03 \x03(\v2*.knowledge.SystemStatus.ServiceStatesEntryR\rserviceStates\x1a@\n" +
+	"\x0eservice_states\x18\x03 \x03(\v2*.knowledge.SystemStatus.ServiceStatesEntryR\rserviceStates\x12<\n" +
+	"\rstats_summary\x18\x04 \x01(\v2\x17.knowledge.StatsSummaryR\fstatsSummary\x1a@\n" +
 	"\x12ServiceStatesEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x18\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xf2\x01\n" +
+	"\fStatsSummary\x12'\n" +
+	"\x0ftotal_databases\x18\x01 \x01(\x05R\x0etotalDatabases\x12+\n" +
+	"\x11healthy_databases\x18\x02 \x01(\x05R\x10healthyDatabases\x12-\n" +
+	"\x12degraded_databases\x18\x03 \x01(\x05R\x11degradedDatabases\x12+\n" +
+	"\x11offline_databases\x18\x04 \x01(\x05R\x10offlineDatabases\x120\n" +
+	"\x14offline_database_ids\x18\x05 \x03(\tR\x12offlineDatabaseIds\"\x18\n" +
 	"\x16GetSystemConfigRequest\"J\n" +
 	"\x17SaveSystemConfigRequest\x12/\n" +
 	"\x06config\x18\x01 \x01(\v2\x17.knowledge.SystemConfigR\x06config\"\x18\n" +
-	"\x16GetSystemStatusRequest\"\x15\n" +
+	"\x16GetSystemStatusRequest\"\xc6\x01\n" +
+	"\fThresholdSet\x12\x1f\n" +
+	"\vdatabase_id\x18\x01 \x01(\tR\n" +
+	"databaseId\x12;\n" +
+	"\x06values\x18\x02 \x03(\v2#.knowledge.ThresholdSet.ValuesEntryR\x06values\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x03 \x01(\x03R\tupdatedAt\x1a9\n" +
+	"\vValuesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"\xb7\x01\n" +
+	"\x14SetThresholdsRequest\x12\x1f\n" +
+	"\vdatabase_id\x18\x01 \x01(\tR\n" +
+	"databaseId\x12C\n" +
+	"\x06values\x18\x02 \x03(\v2+.knowledge.SetThresholdsRequest.ValuesEntryR\x06values\x1a9\n" +
+	"\vValuesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"7\n" +
+	"\x14GetThresholdsRequest\x12\x1f\n" +
+	"\vdatabase_id\x18\x01 \x01(\tR\n" +
+	"databaseId\"D\n" +
+	"\x15GetThresholdsResponse\x12+\n" +
+	"\x04sets\x18\x01 \x03(\v2\x17.knowledge.ThresholdSetR\x04sets\"\xf7\x01\n" +
+	"\x0eDatabasePolicy\x12\x1f\n" +
+	"\vdatabase_id\x18\x01 \x01(\tR\n" +
+	"databaseId\x120\n" +
+	"\x14allowed_action_types\x18\x02 \x03(\tR\x12allowedActionTypes\x12.\n" +
+	"\x13denied_action_types\x18\x03 \x03(\tR\x11deniedActionTypes\x12+\n" +
+	"\x11ignored_detectors\x18\x04 \x03(\tR\x10ignoredDetectors\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\x03R\tupdatedAt\"M\n" +
+	"\x18SetDatabasePolicyRequest\x121\n" +
+	"\x06policy\x18\x01 \x01(\v2\x19.knowledge.DatabasePolicyR\x06policy\";\n" +
+	"\x18GetDatabasePolicyRequest\x12\x1f\n" +
+	"\vdatabase_id\x18\x01 \x01(\tR\n" +
+	"databaseId\"\x1d\n" +
+	"\x1bListDatabasePoliciesRequest\"U\n" +
+	"\x1cListDatabasePoliciesResponse\x125\n" +
+	"\bpolicies\x18\x01 \x03(\v2\x19.knowledge.DatabasePolicyR\bpolicies\"u\n" +
+	"\vSuppression\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\x03R\texpiresAt\"l\n" +
+	"\x1bSuppressDetectionKeyRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12#\n" +
+	"\rduration_secs\x18\x02 \x01(\x03R\fdurationSecs\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\x19\n" +
+	"\x17ListSuppressionsRequest\"V\n" +
+	"\x18ListSuppressionsResponse\x12:\n" +
+	"\fsuppressions\x18\x01 \x03(\v2\x16.knowledge.SuppressionR\fsuppressions\"\x15\n" +
 	"\x13FlushAllDataRequest\"J\n" +
 	"\x14FlushAllDataResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\">\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x15\n" +
+	"\x13ForceCleanupRequest\"\xd8\x01\n" +
+	"\x14ForceCleanupResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12%\n" +
+	"\x0eactions_purged\x18\x03 \x01(\x05R\ractionsPurged\x12+\n" +
+	"\x11detections_purged\x18\x04 \x01(\x05R\x10detectionsPurged\x128\n" +
+	"\x18dangling_entries_removed\x18\x05 \x01(\x05R\x16danglingEntriesRemoved\">\n" +
 	"\bResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage2\x81\v\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage2\x90\x1d\n" +
 	"\x10KnowledgeService\x12V\n" +
-	"\x11RegisterDetection\x12#.knowledge.RegisterDetectionRequest\x1a\x1c.knowledge.DetectionResponse\x12W\n" +
-	"\x11IsDetectionActive\x12\x1e.knowledge.DetectionKeyRequest\x1a\".knowledge.DetectionStatusResponse\x12Y\n" +
+	"\x11RegisterDetection\x12#.knowledge.RegisterDetectionRequest\x1a\x1c.knowledge.DetectionResponse\x12n\n" +
+	"\x19RegisterDetectionIfAbsent\x12#.knowledge.RegisterDetectionRequest\x1a,.knowledge.RegisterDetectionIfAbsentResponse\x12W\n" +
+	"\x11IsDetectionActive\x12\x1e.knowledge.DetectionKeyRequest\x1a\".knowledge.DetectionStatusResponse\x12G\n" +
+	"\x10RefreshDetection\x12\x1e.knowledge.DetectionKeyRequest\x1a\x13.knowledge.Response\x12Y\n" +
+	"\x17UpdateDetectionSeverity\x12).knowledge.UpdateDetectionSeverityRequest\x1a\x13.knowledge.Response\x12Y\n" +
 	"\x13GetActiveDetections\x12 .knowledge.DatabaseFilterRequest\x1a .knowledge.DetectionListResponse\x12P\n" +
-	"\x15MarkDetectionResolved\x12\".knowledge.ResolveDetectionRequest\x1a\x13.knowledge.Response\x12M\n" +
+	"\x15MarkDetectionResolved\x12\".knowledge.ResolveDetectionRequest\x1a\x13.knowledge.Response\x12I\n" +
+	"\x0fReopenDetection\x12!.knowledge.ReopenDetectionRequest\x1a\x13.knowledge.Response\x12Y\n" +
+	"\x19MarkDetectionUnactionable\x12'.knowledge.UnactionableDetectionRequest\x1a\x13.knowledge.Response\x12X\n" +
+	"\x19MarkDetectionAcknowledged\x12&.knowledge.AcknowledgeDetectionRequest\x1a\x13.knowledge.Response\x12S\n" +
+	"\x14SuppressDetectionKey\x12&.knowledge.SuppressDetectionKeyRequest\x1a\x13.knowledge.Response\x12[\n" +
+	"\x10ListSuppressions\x12\".knowledge.ListSuppressionsRequest\x1a#.knowledge.ListSuppressionsResponse\x12M\n" +
 	"\x0eRegisterAction\x12 .knowledge.RegisterActionRequest\x1a\x19.knowledge.ActionResponse\x12I\n" +
 	"\x12UpdateActionStatus\x12\x1e.knowledge.UpdateActionRequest\x1a\x13.knowledge.Response\x12T\n" +
-	"\x11GetPendingActions\x12 .knowledge.DatabaseFilterRequest\x1a\x1d.knowledge.ActionListResponse\x12S\n" +
+	"\x11GetPendingActions\x12 .knowledge.DatabaseFilterRequest\x1a\x1d.knowledge.ActionListResponse\x12F\n" +
+	"\tGetAction\x12\x1b.knowledge.GetActionRequest\x1a\x1c.knowledge.GetActionResponse\x12[\n" +
+	"\x10GetActionHistory\x12\".knowledge.GetActionHistoryRequest\x1a#.knowledge.GetActionHistoryResponse\x12Q\n" +
+	"\x13RecordActionOutcome\x12%.knowledge.RecordActionOutcomeRequest\x1a\x13.knowledge.Response\x12[\n" +
+	"\x13ListActionsByStatus\x12%.knowledge.ListActionsByStatusRequest\x1a\x1d.knowledge.ActionListResponse\x12\x88\x01\n" +
+	"\x1fGetPendingImplementationSummary\x121.knowledge.GetPendingImplementationSummaryRequest\x1a2.knowledge.GetPendingImplementationSummaryResponse\x12S\n" +
+	"\x14RecordDatabaseAction\x12&.knowledge.RecordDatabaseActionRequest\x1a\x13.knowledge.Response\x12m\n" +
+	"\x18GetRecentDatabaseActions\x12'.knowledge.RecentDatabaseActionsRequest\x1a(.knowledge.RecentDatabaseActionsResponse\x12B\n" +
+	"\x12RecordMetricSample\x12\x17.knowledge.MetricSample\x1a\x13.knowledge.Response\x12[\n" +
+	"\x10GetMetricHistory\x12\".knowledge.GetMetricHistoryRequest\x1a#.knowledge.GetMetricHistoryResponse\x12S\n" +
 	"\x10RegisterDatabase\x12\".knowledge.RegisterDatabaseRequest\x1a\x1b.knowledge.DatabaseResponse\x12L\n" +
 	"\vGetDatabase\x12\x1d.knowledge.GetDatabaseRequest\x1a\x1e.knowledge.GetDatabaseResponse\x12Q\n" +
-	"\rListDatabases\x12\x1f.knowledge.ListDatabasesRequest\x1a\x1f.knowledge.DatabaseListResponse\x12S\n" +
+	"\rListDatabases\x12\x1f.knowledge.ListDatabasesRequest\x1a\x1f.knowledge.DatabaseListResponse\x12[\n" +
+	"\x12GetDatabasesByType\x12$.knowledge.GetDatabasesByTypeRequest\x1a\x1f.knowledge.DatabaseListResponse\x12S\n" +
 	"\x14UpdateDatabaseHealth\x12&.knowledge.UpdateDatabaseHealthRequest\x1a\x13.knowledge.Response\x12O\n" +
 	"\x12UnregisterDatabase\x12$.knowledge.UnregisterDatabaseRequest\x1a\x13.knowledge.Response\x12G\n" +
 	"\x0eUpdateDatabase\x12 .knowledge.UpdateDatabaseRequest\x1a\x13.knowledge.Response\x12M\n" +
 	"\x0fGetSystemConfig\x12!.knowledge.GetSystemConfigRequest\x1a\x17.knowledge.SystemConfig\x12K\n" +
-	"\x10SaveSystemConfig\x12\".knowledge.SaveSystemConfigRequest\x1a\x13.knowledge.Response\x12M\n" +
-	"\x0fGetSystemStatus\x12!.knowledge.GetSystemStatusRequest\x1a\x17.knowledge.SystemStatus\x12O\n" +
-	"\fFlushAllData\x12\x1e.knowledge.FlushAllDataRequest\x1a\x1f.knowledge.FlushAllDataResponseB3Z1github.com/EricMurray-e-m-dev/StartupMonkey/protob\x06proto3"
+	"\x10SaveSystemConfig\x12\".knowledge.SaveSystemConfigRequest\x1a\x13.knowledge.Response\x12E\n" +
+	"\rSetThresholds\x12\x1f.knowledge.SetThresholdsRequest\x1a\x13.knowledge.Response\x12R\n" +
+	"\rGetThresholds\x12\x1f.knowledge.GetThresholdsRequest\x1a .knowledge.GetThresholdsResponse\x12M\n" +
+	"\x11SetDatabasePolicy\x12#.knowledge.SetDatabasePolicyRequest\x1a\x13.knowledge.Response\x12S\n" +
+	"\x11GetDatabasePolicy\x12#.knowledge.GetDatabasePolicyRequest\x1a\x19.knowledge.DatabasePolicy\x12g\n" +
+	"\x14ListDatabasePolicies\x12&.knowledge.ListDatabasePoliciesRequest\x1a'.knowledge.ListDatabasePoliciesResponse\x12M\n" +
+	"\x0fGetSystemStatus\x12!.knowledge.GetSystemStatusRequest\x1a\x17.knowledge.SystemStatus\x12U\n" +
+	"\x0eGetSystemStats\x12 .knowledge.GetSystemStatsRequest\x1a!.knowledge.GetSystemStatsResponse\x12U\n" +
+	"\x0eGetActionStats\x12 .knowledge.GetActionStatsRequest\x1a!.knowledge.GetActionStatsResponse\x12O\n" +
+	"\fFlushAllData\x12\x1e.knowledge.FlushAllDataRequest\x1a\x1f.knowledge.FlushAllDataResponse\x12O\n" +
+	"\fForceCleanup\x12\x1e.knowledge.ForceCleanupRequest\x1a\x1f.knowledge.ForceCleanupResponseB3Z1github.com/EricMurray-e-m-dev/StartupMonkey/protob\x06proto3"
 
 var (
 	file_knowledge_proto_rawDescOnce sync.Once
@@ -2586,96 +5909,225 @@ func file_knowledge_proto_rawDescGZIP() []byte {
 	return file_knowledge_proto_rawDescData
 }
 
-var file_knowledge_proto_msgTypes = make([]protoimpl.MessageInfo, 38)
+var file_knowledge_proto_msgTypes = make([]protoimpl.MessageInfo, 90)
 var file_knowledge_proto_goTypes = []any{
-	(*RegisterDetectionRequest)(nil),    // 0: knowledge.RegisterDetectionRequest
-	(*DetectionKeyRequest)(nil),         // 1: knowledge.DetectionKeyRequest
-	(*DetectionStatusResponse)(nil),     // 2: knowledge.DetectionStatusResponse
-	(*DatabaseFilterRequest)(nil),       // 3: knowledge.DatabaseFilterRequest
-	(*DetectionResponse)(nil),           // 4: knowledge.DetectionResponse
-	(*DetectionListResponse)(nil),       // 5: knowledge.DetectionListResponse
-	(*Detection)(nil),                   // 6: knowledge.Detection
-	(*ResolveDetectionRequest)(nil),     // 7: knowledge.ResolveDetectionRequest
-	(*RegisterActionRequest)(nil),       // 8: knowledge.RegisterActionRequest
-	(*ActionResponse)(nil),              // 9: knowledge.ActionResponse
-	(*UpdateActionRequest)(nil),         // 10: knowledge.UpdateActionRequest
-	(*ActionListResponse)(nil),          // 11: knowledge.ActionListResponse
-	(*Action)(nil),                      // 12: knowledge.Action
-	(*RegisterDatabaseRequest)(nil),     // 13: knowledge.RegisterDatabaseRequest
-	(*DatabaseResponse)(nil),            // 14: knowledge.DatabaseResponse
-	(*GetDatabaseRequest)(nil),          // 15: knowledge.GetDatabaseRequest
-	(*GetDatabaseResponse)(nil),         // 16: knowledge.GetDatabaseResponse
-	(*ListDatabasesRequest)(nil),        // 17: knowledge.ListDatabasesRequest
-	(*DatabaseListResponse)(nil),        // 18: knowledge.DatabaseListResponse
-	(*RegisteredDatabase)(nil),          // 19: knowledge.RegisteredDatabase
-	(*UpdateDatabaseHealthRequest)(nil), // 20: knowledge.UpdateDatabaseHealthRequest
-	(*UpdateDatabaseRequest)(nil),       // 21: knowledge.UpdateDatabaseRequest
-	(*UnregisterDatabaseRequest)(nil),   // 22: knowledge.UnregisterDatabaseRequest
-	(*GetSystemStatsRequest)(nil),       // 23: knowledge.GetSystemStatsRequest
-	(*GetSystemStatsResponse)(nil),      // 24: knowledge.GetSystemStatsResponse
-	(*DetectionThresholds)(nil),         // 25: knowledge.DetectionThresholds
-	(*WebhookConfig)(nil),               // 26: knowledge.WebhookConfig
-	(*SystemConfig)(nil),                // 27: knowledge.SystemConfig
-	(*SystemStatus)(nil),                // 28: knowledge.SystemStatus
-	(*GetSystemConfigRequest)(nil),      // 29: knowledge.GetSystemConfigRequest
-	(*SaveSystemConfigRequest)(nil),     // 30: knowledge.SaveSystemConfigRequest
-	(*GetSystemStatusRequest)(nil),      // 31: knowledge.GetSystemStatusRequest
-	(*FlushAllDataRequest)(nil),         // 32: knowledge.FlushAllDataRequest
-	(*FlushAllDataResponse)(nil),        // 33: knowledge.FlushAllDataResponse
-	(*Response)(nil),                    // 34: knowledge.Response
-	nil,                                 // 35: knowledge.RegisterDatabaseRequest.MetadataEntry
-	nil,                                 // 36: knowledge.GetDatabaseResponse.MetadataEntry
-	nil,                                 // 37: knowledge.SystemStatus.ServiceStatesEntry
+	(*RegisterDetectionRequest)(nil),                // 0: knowledge.RegisterDetectionRequest
+	(*DetectionKeyRequest)(nil),                     // 1: knowledge.DetectionKeyRequest
+	(*DetectionStatusResponse)(nil),                 // 2: knowledge.DetectionStatusResponse
+	(*UpdateDetectionSeverityRequest)(nil),          // 3: knowledge.UpdateDetectionSeverityRequest
+	(*DatabaseFilterRequest)(nil),                   // 4: knowledge.DatabaseFilterRequest
+	(*DetectionResponse)(nil),                       // 5: knowledge.DetectionResponse
+	(*RegisterDetectionIfAbsentResponse)(nil),       // 6: knowledge.RegisterDetectionIfAbsentResponse
+	(*DetectionListResponse)(nil),                   // 7: knowledge.DetectionListResponse
+	(*Detection)(nil),                               // 8: knowledge.Detection
+	(*ResolveDetectionRequest)(nil),                 // 9: knowledge.ResolveDetectionRequest
+	(*ReopenDetectionRequest)(nil),                  // 10: knowledge.ReopenDetectionRequest
+	(*UnactionableDetectionRequest)(nil),            // 11: knowledge.UnactionableDetectionRequest
+	(*AcknowledgeDetectionRequest)(nil),             // 12: knowledge.AcknowledgeDetectionRequest
+	(*RegisterActionRequest)(nil),                   // 13: knowledge.RegisterActionRequest
+	(*ActionResponse)(nil),                          // 14: knowledge.ActionResponse
+	(*UpdateActionRequest)(nil),                     // 15: knowledge.UpdateActionRequest
+	(*ActionProgress)(nil),                          // 16: knowledge.ActionProgress
+	(*ActionOutcome)(nil),                           // 17: knowledge.ActionOutcome
+	(*RecordActionOutcomeRequest)(nil),              // 18: knowledge.RecordActionOutcomeRequest
+	(*GetActionRequest)(nil),                        // 19: knowledge.GetActionRequest
+	(*GetActionResponse)(nil),                       // 20: knowledge.GetActionResponse
+	(*GetActionHistoryRequest)(nil),                 // 21: knowledge.GetActionHistoryRequest
+	(*ActionHistoryEntry)(nil),                      // 22: knowledge.ActionHistoryEntry
+	(*GetActionHistoryResponse)(nil),                // 23: knowledge.GetActionHistoryResponse
+	(*ListActionsByStatusRequest)(nil),              // 24: knowledge.ListActionsByStatusRequest
+	(*GetPendingImplementationSummaryRequest)(nil),  // 25: knowledge.GetPendingImplementationSummaryRequest
+	(*GetPendingImplementationSummaryResponse)(nil), // 26: knowledge.GetPendingImplementationSummaryResponse
+	(*PendingImplementationSummary)(nil),            // 27: knowledge.PendingImplementationSummary
+	(*RecordDatabaseActionRequest)(nil),             // 28: knowledge.RecordDatabaseActionRequest
+	(*RecentDatabaseActionsRequest)(nil),            // 29: knowledge.RecentDatabaseActionsRequest
+	(*RecentDatabaseActionsResponse)(nil),           // 30: knowledge.RecentDatabaseActionsResponse
+	(*MetricSample)(nil),                            // 31: knowledge.MetricSample
+	(*GetMetricHistoryRequest)(nil),                 // 32: knowledge.GetMetricHistoryRequest
+	(*GetMetricHistoryResponse)(nil),                // 33: knowledge.GetMetricHistoryResponse
+	(*ActionListResponse)(nil),                      // 34: knowledge.ActionListResponse
+	(*Action)(nil),                                  // 35: knowledge.Action
+	(*RegisterDatabaseRequest)(nil),                 // 36: knowledge.RegisterDatabaseRequest
+	(*DatabaseResponse)(nil),                        // 37: knowledge.DatabaseResponse
+	(*GetDatabaseRequest)(nil),                      // 38: knowledge.GetDatabaseRequest
+	(*GetDatabaseResponse)(nil),                     // 39: knowledge.GetDatabaseResponse
+	(*ListDatabasesRequest)(nil),                    // 40: knowledge.ListDatabasesRequest
+	(*GetDatabasesByTypeRequest)(nil),               // 41: knowledge.GetDatabasesByTypeRequest
+	(*DatabaseListResponse)(nil),                    // 42: knowledge.DatabaseListResponse
+	(*RegisteredDatabase)(nil),                      // 43: knowledge.RegisteredDatabase
+	(*UpdateDatabaseHealthRequest)(nil),             // 44: knowledge.UpdateDatabaseHealthRequest
+	(*UpdateDatabaseRequest)(nil),                   // 45: knowledge.UpdateDatabaseRequest
+	(*UnregisterDatabaseRequest)(nil),               // 46: knowledge.UnregisterDatabaseRequest
+	(*GetSystemStatsRequest)(nil),                   // 47: knowledge.GetSystemStatsRequest
+	(*GetSystemStatsResponse)(nil),                  // 48: knowledge.GetSystemStatsResponse
+	(*GetActionStatsRequest)(nil),                   // 49: knowledge.GetActionStatsRequest
+	(*ActionStats)(nil),                             // 50: knowledge.ActionStats
+	(*GetActionStatsResponse)(nil),                  // 51: knowledge.GetActionStatsResponse
+	(*DetectionThresholds)(nil),                     // 52: knowledge.DetectionThresholds
+	(*WebhookConfig)(nil),                           // 53: knowledge.WebhookConfig
+	(*SystemConfig)(nil),                            // 54: knowledge.SystemConfig
+	(*SystemStatus)(nil),                            // 55: knowledge.SystemStatus
+	(*StatsSummary)(nil),                            // 56: knowledge.StatsSummary
+	(*GetSystemConfigRequest)(nil),                  // 57: knowledge.GetSystemConfigRequest
+	(*SaveSystemConfigRequest)(nil),                 // 58: knowledge.SaveSystemConfigRequest
+	(*GetSystemStatusRequest)(nil),                  // 59: knowledge.GetSystemStatusRequest
+	(*ThresholdSet)(nil),                            // 60: knowledge.ThresholdSet
+	(*SetThresholdsRequest)(nil),                    // 61: knowledge.SetThresholdsRequest
+	(*GetThresholdsRequest)(nil),                    // 62: knowledge.GetThresholdsRequest
+	(*GetThresholdsResponse)(nil),                   // 63: knowledge.GetThresholdsResponse
+	(*DatabasePolicy)(nil),                          // 64: knowledge.DatabasePolicy
+	(*SetDatabasePolicyRequest)(nil),                // 65: knowledge.SetDatabasePolicyRequest
+	(*GetDatabasePolicyRequest)(nil),                // 66: knowledge.GetDatabasePolicyRequest
+	(*ListDatabasePoliciesRequest)(nil),             // 67: knowledge.ListDatabasePoliciesRequest
+	(*ListDatabasePoliciesResponse)(nil),            // 68: knowledge.ListDatabasePoliciesResponse
+	(*Suppression)(nil),                             // 69: knowledge.Suppression
+	(*SuppressDetectionKeyRequest)(nil),             // 70: knowledge.SuppressDetectionKeyRequest
+	(*ListSuppressionsRequest)(nil),                 // 71: knowledge.ListSuppressionsRequest
+	(*ListSuppressionsResponse)(nil),                // 72: knowledge.ListSuppressionsResponse
+	(*FlushAllDataRequest)(nil),                     // 73: knowledge.FlushAllDataRequest
+	(*FlushAllDataResponse)(nil),                    // 74: knowledge.FlushAllDataResponse
+	(*ForceCleanupRequest)(nil),                     // 75: knowledge.ForceCleanupRequest
+	(*ForceCleanupResponse)(nil),                    // 76: knowledge.ForceCleanupResponse
+	(*Response)(nil),                                // 77: knowledge.Response
+	nil,                                             // 78: knowledge.MetricSample.ValuesEntry
+	nil,                                             // 79: knowledge.RegisterDatabaseRequest.MetadataEntry
+	nil,                                             // 80: knowledge.GetDatabaseResponse.MetadataEntry
+	nil,                                             // 81: knowledge.RegisteredDatabase.MetadataEntry
+	nil,                                             // 82: knowledge.UpdateDatabaseRequest.MetadataEntry
+	nil,                                             // 83: knowledge.GetSystemStatsResponse.ActiveDetectionsByDatabaseEntry
+	nil,                                             // 84: knowledge.GetSystemStatsResponse.ActiveDetectionsByCategoryEntry
+	nil,                                             // 85: knowledge.GetSystemStatsResponse.ResolvedDetectionsByDatabaseEntry
+	nil,                                             // 86: knowledge.GetSystemStatsResponse.ResolvedDetectionsByCategoryEntry
+	nil,                                             // 87: knowledge.SystemStatus.ServiceStatesEntry
+	nil,                                             // 88: knowledge.ThresholdSet.ValuesEntry
+	nil,                                             // 89: knowledge.SetThresholdsRequest.ValuesEntry
 }
 var file_knowledge_proto_depIdxs = []int32{
-	6,  // 0: knowledge.DetectionListResponse.detections:type_name -> knowledge.Detection
-	12, // 1: knowledge.ActionListResponse.actions:type_name -> knowledge.Action
-	35, // 2: knowledge.RegisterDatabaseRequest.metadata:type_name -> knowledge.RegisterDatabaseRequest.MetadataEntry
-	36, // 3: knowledge.GetDatabaseResponse.metadata:type_name -> knowledge.GetDatabaseResponse.MetadataEntry
-	19, // 4: knowledge.DatabaseListResponse.databases:type_name -> knowledge.RegisteredDatabase
-	25, // 5: knowledge.SystemConfig.thresholds:type_name -> knowledge.DetectionThresholds
-	26, // 6: knowledge.SystemConfig.webhook:type_name -> knowledge.WebhookConfig
-	37, // 7: knowledge.SystemStatus.service_states:type_name -> knowledge.SystemStatus.ServiceStatesEntry
-	27, // 8: knowledge.SaveSystemConfigRequest.config:type_name -> knowledge.SystemConfig
-	0,  // 9: knowledge.KnowledgeService.RegisterDetection:input_type -> knowledge.RegisterDetectionRequest
-	1,  // 10: knowledge.KnowledgeService.IsDetectionActive:input_type -> knowledge.DetectionKeyRequest
-	3,  // 11: knowledge.KnowledgeService.GetActiveDetections:input_type -> knowledge.DatabaseFilterRequest
-	7,  // 12: knowledge.KnowledgeService.MarkDetectionResolved:input_type -> knowledge.ResolveDetectionRequest
-	8,  // 13: knowledge.KnowledgeService.RegisterAction:input_type -> knowledge.RegisterActionRequest
-	10, // 14: knowledge.KnowledgeService.UpdateActionStatus:input_type -> knowledge.UpdateActionRequest
-	3,  // 15: knowledge.KnowledgeService.GetPendingActions:input_type -> knowledge.DatabaseFilterRequest
-	13, // 16: knowledge.KnowledgeService.RegisterDatabase:input_type -> knowledge.RegisterDatabaseRequest
-	15, // 17: knowledge.KnowledgeService.GetDatabase:input_type -> knowledge.GetDatabaseRequest
-	17, // 18: knowledge.KnowledgeService.ListDatabases:input_type -> knowledge.ListDatabasesRequest
-	20, // 19: knowledge.KnowledgeService.UpdateDatabaseHealth:input_type -> knowledge.UpdateDatabaseHealthRequest
-	22, // 20: knowledge.KnowledgeService.UnregisterDatabase:input_type -> knowledge.UnregisterDatabaseRequest
-	21, // 21: knowledge.KnowledgeService.UpdateDatabase:input_type -> knowledge.UpdateDatabaseRequest
-	29, // 22: knowledge.KnowledgeService.GetSystemConfig:input_type -> knowledge.GetSystemConfigRequest
-	30, // 23: knowledge.KnowledgeService.SaveSystemConfig:input_type -> knowledge.SaveSystemConfigRequest
-	31, // 24: knowledge.KnowledgeService.GetSystemStatus:input_type -> knowledge.GetSystemStatusRequest
-	32, // 25: knowledge.KnowledgeService.FlushAllData:input_type -> knowledge.FlushAllDataRequest
-	4,  // 26: knowledge.KnowledgeService.RegisterDetection:output_type -> knowledge.DetectionResponse
-	2,  // 27: knowledge.KnowledgeService.IsDetectionActive:output_type -> knowledge.DetectionStatusResponse
-	5,  // 28: knowledge.KnowledgeService.GetActiveDetections:output_type -> knowledge.DetectionListResponse
-	34, // 29: knowledge.KnowledgeService.MarkDetectionResolved:output_type -> knowledge.Response
-	9,  // 30: knowledge.KnowledgeService.RegisterAction:output_type -> knowledge.ActionResponse
-	34, // 31: knowledge.KnowledgeService.UpdateActionStatus:output_type -> knowledge.Response
-	11, // 32: knowledge.KnowledgeService.GetPendingActions:output_type -> knowledge.ActionListResponse
-	14, // 33: knowledge.KnowledgeService.RegisterDatabase:output_type -> knowledge.DatabaseResponse
-	16, // 34: knowledge.KnowledgeService.GetDatabase:output_type -> knowledge.GetDatabaseResponse
-	18, // 35: knowledge.KnowledgeService.ListDatabases:output_type -> knowledge.DatabaseListResponse
-	34, // 36: knowledge.KnowledgeService.UpdateDatabaseHealth:output_type -> knowledge.Response
-	34, // 37: knowledge.KnowledgeService.UnregisterDatabase:output_type -> knowledge.Response
-	34, // 38: knowledge.KnowledgeService.UpdateDatabase:output_type -> knowledge.Response
-	27, // 39: knowledge.KnowledgeService.GetSystemConfig:output_type -> knowledge.SystemConfig
-	34, // 40: knowledge.KnowledgeService.SaveSystemConfig:output_type -> knowledge.Response
-	28, // 41: knowledge.KnowledgeService.GetSystemStatus:output_type -> knowledge.SystemStatus
-	33, // 42: knowledge.KnowledgeService.FlushAllData:output_type -> knowledge.FlushAllDataResponse
-	26, // [26:43] is the sub-list for method output_type
-	9,  // [9:26] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	8,  // 0: knowledge.DetectionListResponse.detections:type_name -> knowledge.Detection
+	16, // 1: knowledge.UpdateActionRequest.progress:type_name -> knowledge.ActionProgress
+	35, // 2: knowledge.GetActionResponse.action:type_name -> knowledge.Action
+	22, // 3: knowledge.GetActionHistoryResponse.entries:type_name -> knowledge.ActionHistoryEntry
+	27, // 4: knowledge.GetPendingImplementationSummaryResponse.summaries:type_name -> knowledge.PendingImplementationSummary
+	78, // 5: knowledge.MetricSample.values:type_name -> knowledge.MetricSample.ValuesEntry
+	31, // 6: knowledge.GetMetricHistoryResponse.samples:type_name -> knowledge.MetricSample
+	35, // 7: knowledge.ActionListResponse.actions:type_name -> knowledge.Action
+	16, // 8: knowledge.Action.progress:type_name -> knowledge.ActionProgress
+	17, // 9: knowledge.Action.outcome:type_name -> knowledge.ActionOutcome
+	79, // 10: knowledge.RegisterDatabaseRequest.metadata:type_name -> knowledge.RegisterDatabaseRequest.MetadataEntry
+	80, // 11: knowledge.GetDatabaseResponse.metadata:type_name -> knowledge.GetDatabaseResponse.MetadataEntry
+	43, // 12: knowledge.DatabaseListResponse.databases:type_name -> knowledge.RegisteredDatabase
+	81, // 13: knowledge.RegisteredDatabase.metadata:type_name -> knowledge.RegisteredDatabase.MetadataEntry
+	82, // 14: knowledge.UpdateDatabaseRequest.metadata:type_name -> knowledge.UpdateDatabaseRequest.MetadataEntry
+	83, // 15: knowledge.GetSystemStatsResponse.active_detections_by_database:type_name -> knowledge.GetSystemStatsResponse.ActiveDetectionsByDatabaseEntry
+	84, // 16: knowledge.GetSystemStatsResponse.active_detections_by_category:type_name -> knowledge.GetSystemStatsResponse.ActiveDetectionsByCategoryEntry
+	85, // 17: knowledge.GetSystemStatsResponse.resolved_detections_by_database:type_name -> knowledge.GetSystemStatsResponse.ResolvedDetectionsByDatabaseEntry
+	86, // 18: knowledge.GetSystemStatsResponse.resolved_detections_by_category:type_name -> knowledge.GetSystemStatsResponse.ResolvedDetectionsByCategoryEntry
+	50, // 19: knowledge.GetActionStatsResponse.by_action_type:type_name -> knowledge.ActionStats
+	50, // 20: knowledge.GetActionStatsResponse.by_database:type_name -> knowledge.ActionStats
+	50, // 21: knowledge.GetActionStatsResponse.by_action_type_and_database:type_name -> knowledge.ActionStats
+	50, // 22: knowledge.GetActionStatsResponse.total:type_name -> knowledge.ActionStats
+	52, // 23: knowledge.SystemConfig.thresholds:type_name -> knowledge.DetectionThresholds
+	53, // 24: knowledge.SystemConfig.webhook:type_name -> knowledge.WebhookConfig
+	87, // 25: knowledge.SystemStatus.service_states:type_name -> knowledge.SystemStatus.ServiceStatesEntry
+	56, // 26: knowledge.SystemStatus.stats_summary:type_name -> knowledge.StatsSummary
+	54, // 27: knowledge.SaveSystemConfigRequest.config:type_name -> knowledge.SystemConfig
+	88, // 28: knowledge.ThresholdSet.values:type_name -> knowledge.ThresholdSet.ValuesEntry
+	89, // 29: knowledge.SetThresholdsRequest.values:type_name -> knowledge.SetThresholdsRequest.ValuesEntry
+	60, // 30: knowledge.GetThresholdsResponse.sets:type_name -> knowledge.ThresholdSet
+	64, // 31: knowledge.SetDatabasePolicyRequest.policy:type_name -> knowledge.DatabasePolicy
+	64, // 32: knowledge.ListDatabasePoliciesResponse.policies:type_name -> knowledge.DatabasePolicy
+	69, // 33: knowledge.ListSuppressionsResponse.suppressions:type_name -> knowledge.Suppression
+	0,  // 34: knowledge.KnowledgeService.RegisterDetection:input_type -> knowledge.RegisterDetectionRequest
+	0,  // 35: knowledge.KnowledgeService.RegisterDetectionIfAbsent:input_type -> knowledge.RegisterDetectionRequest
+	1,  // 36: knowledge.KnowledgeService.IsDetectionActive:input_type -> knowledge.DetectionKeyRequest
+	1,  // 37: knowledge.KnowledgeService.RefreshDetection:input_type -> knowledge.DetectionKeyRequest
+	3,  // 38: knowledge.KnowledgeService.UpdateDetectionSeverity:input_type -> knowledge.UpdateDetectionSeverityRequest
+	4,  // 39: knowledge.KnowledgeService.GetActiveDetections:input_type -> knowledge.DatabaseFilterRequest
+	9,  // 40: knowledge.KnowledgeService.MarkDetectionResolved:input_type -> knowledge.ResolveDetectionRequest
+	10, // 41: knowledge.KnowledgeService.ReopenDetection:input_type -> knowledge.ReopenDetectionRequest
+	11, // 42: knowledge.KnowledgeService.MarkDetectionUnactionable:input_type -> knowledge.UnactionableDetectionRequest
+	12, // 43: knowledge.KnowledgeService.MarkDetectionAcknowledged:input_type -> knowledge.AcknowledgeDetectionRequest
+	70, // 44: knowledge.KnowledgeService.SuppressDetectionKey:input_type -> knowledge.SuppressDetectionKeyRequest
+	71, // 45: knowledge.KnowledgeService.ListSuppressions:input_type -> knowledge.ListSuppressionsRequest
+	13, // 46: knowledge.KnowledgeService.RegisterAction:input_type -> knowledge.RegisterActionRequest
+	15, // 47: knowledge.KnowledgeService.UpdateActionStatus:input_type -> knowledge.UpdateActionRequest
+	4,  // 48: knowledge.KnowledgeService.GetPendingActions:input_type -> knowledge.DatabaseFilterRequest
+	19, // 49: knowledge.KnowledgeService.GetAction:input_type -> knowledge.GetActionRequest
+	21, // 50: knowledge.KnowledgeService.GetActionHistory:input_type -> knowledge.GetActionHistoryRequest
+	18, // 51: knowledge.KnowledgeService.RecordActionOutcome:input_type -> knowledge.RecordActionOutcomeRequest
+	24, // 52: knowledge.KnowledgeService.ListActionsByStatus:input_type -> knowledge.ListActionsByStatusRequest
+	25, // 53: knowledge.KnowledgeService.GetPendingImplementationSummary:input_type -> knowledge.GetPendingImplementationSummaryRequest
+	28, // 54: knowledge.KnowledgeService.RecordDatabaseAction:input_type -> knowledge.RecordDatabaseActionRequest
+	29, // 55: knowledge.KnowledgeService.GetRecentDatabaseActions:input_type -> knowledge.RecentDatabaseActionsRequest
+	31, // 56: knowledge.KnowledgeService.RecordMetricSample:input_type -> knowledge.MetricSample
+	32, // 57: knowledge.KnowledgeService.GetMetricHistory:input_type -> knowledge.GetMetricHistoryRequest
+	36, // 58: knowledge.KnowledgeService.RegisterDatabase:input_type -> knowledge.RegisterDatabaseRequest
+	38, // 59: knowledge.KnowledgeService.GetDatabase:input_type -> knowledge.GetDatabaseRequest
+	40, // 60: knowledge.KnowledgeService.ListDatabases:input_type -> knowledge.ListDatabasesRequest
+	41, // 61: knowledge.KnowledgeService.GetDatabasesByType:input_type -> knowledge.GetDatabasesByTypeRequest
+	44, // 62: knowledge.KnowledgeService.UpdateDatabaseHealth:input_type -> knowledge.UpdateDatabaseHealthRequest
+	46, // 63: knowledge.KnowledgeService.UnregisterDatabase:input_type -> knowledge.UnregisterDatabaseRequest
+	45, // 64: knowledge.KnowledgeService.UpdateDatabase:input_type -> knowledge.UpdateDatabaseRequest
+	57, // 65: knowledge.KnowledgeService.GetSystemConfig:input_type -> knowledge.GetSystemConfigRequest
+	58, // 66: knowledge.KnowledgeService.SaveSystemConfig:input_type -> knowledge.SaveSystemConfigRequest
+	61, // 67: knowledge.KnowledgeService.SetThresholds:input_type -> knowledge.SetThresholdsRequest
+	62, // 68: knowledge.KnowledgeService.GetThresholds:input_type -> knowledge.GetThresholdsRequest
+	65, // 69: knowledge.KnowledgeService.SetDatabasePolicy:input_type -> knowledge.SetDatabasePolicyRequest
+	66, // 70: knowledge.KnowledgeService.GetDatabasePolicy:input_type -> knowledge.GetDatabasePolicyRequest
+	67, // 71: knowledge.KnowledgeService.ListDatabasePolicies:input_type -> knowledge.ListDatabasePoliciesRequest
+	59, // 72: knowledge.KnowledgeService.GetSystemStatus:input_type -> knowledge.GetSystemStatusRequest
+	47, // 73: knowledge.KnowledgeService.GetSystemStats:input_type -> knowledge.GetSystemStatsRequest
+	49, // 74: knowledge.KnowledgeService.GetActionStats:input_type -> knowledge.GetActionStatsRequest
+	73, // 75: knowledge.KnowledgeService.FlushAllData:input_type -> knowledge.FlushAllDataRequest
+	75, // 76: knowledge.KnowledgeService.ForceCleanup:input_type -> knowledge.ForceCleanupRequest
+	5,  // 77: knowledge.KnowledgeService.RegisterDetection:output_type -> knowledge.DetectionResponse
+	6,  // 78: knowledge.KnowledgeService.RegisterDetectionIfAbsent:output_type -> knowledge.RegisterDetectionIfAbsentResponse
+	2,  // 79: knowledge.KnowledgeService.IsDetectionActive:output_type -> knowledge.DetectionStatusResponse
+	77, // 80: knowledge.KnowledgeService.RefreshDetection:output_type -> knowledge.Response
+	77, // 81: knowledge.KnowledgeService.UpdateDetectionSeverity:output_type -> knowledge.Response
+	7,  // 82: knowledge.KnowledgeService.GetActiveDetections:output_type -> knowledge.DetectionListResponse
+	77, // 83: knowledge.KnowledgeService.MarkDetectionResolved:output_type -> knowledge.Response
+	77, // 84: knowledge.KnowledgeService.ReopenDetection:output_type -> knowledge.Response
+	77, // 85: knowledge.KnowledgeService.MarkDetectionUnactionable:output_type -> knowledge.Response
+	77, // 86: knowledge.KnowledgeService.MarkDetectionAcknowledged:output_type -> knowledge.Response
+	77, // 87: knowledge.KnowledgeService.SuppressDetectionKey:output_type -> knowledge.Response
+	72, // 88: knowledge.KnowledgeService.ListSuppressions:output_type -> knowledge.ListSuppressionsResponse
+	14, // 89: knowledge.KnowledgeService.RegisterAction:output_type -> knowledge.ActionResponse
+	77, // 90: knowledge.KnowledgeService.UpdateActionStatus:output_type -> knowledge.Response
+	34, // 91: knowledge.KnowledgeService.GetPendingActions:output_type -> knowledge.ActionListResponse
+	20, // 92: knowledge.KnowledgeService.GetAction:output_type -> knowledge.GetActionResponse
+	23, // 93: knowledge.KnowledgeService.GetActionHistory:output_type -> knowledge.GetActionHistoryResponse
+	77, // 94: knowledge.KnowledgeService.RecordActionOutcome:output_type -> knowledge.Response
+	34, // 95: knowledge.KnowledgeService.ListActionsByStatus:output_type -> knowledge.ActionListResponse
+	26, // 96: knowledge.KnowledgeService.GetPendingImplementationSummary:output_type -> knowledge.GetPendingImplementationSummaryResponse
+	77, // 97: knowledge.KnowledgeService.RecordDatabaseAction:output_type -> knowledge.Response
+	30, // 98: knowledge.KnowledgeService.GetRecentDatabaseActions:output_type -> knowledge.RecentDatabaseActionsResponse
+	77, // 99: knowledge.KnowledgeService.RecordMetricSample:output_type -> knowledge.Response
+	33, // 100: knowledge.KnowledgeService.GetMetricHistory:output_type -> knowledge.GetMetricHistoryResponse
+	37, // 101: knowledge.KnowledgeService.RegisterDatabase:output_type -> knowledge.DatabaseResponse
+	39, // 102: knowledge.KnowledgeService.GetDatabase:output_type -> knowledge.GetDatabaseResponse
+	42, // 103: knowledge.KnowledgeService.ListDatabases:output_type -> knowledge.DatabaseListResponse
+	42, // 104: knowledge.KnowledgeService.GetDatabasesByType:output_type -> knowledge.DatabaseListResponse
+	77, // 105: knowledge.KnowledgeService.UpdateDatabaseHealth:output_type -> knowledge.Response
+	77, // 106: knowledge.KnowledgeService.UnregisterDatabase:output_type -> knowledge.Response
+	77, // 107: knowledge.KnowledgeService.UpdateDatabase:output_type -> knowledge.Response
+	54, // 108: knowledge.KnowledgeService.GetSystemConfig:output_type -> knowledge.SystemConfig
+	77, // 109: knowledge.KnowledgeService.SaveSystemConfig:output_type -> knowledge.Response
+	77, // 110: knowledge.KnowledgeService.SetThresholds:output_type -> knowledge.Response
+	63, // 111: knowledge.KnowledgeService.GetThresholds:output_type -> knowledge.GetThresholdsResponse
+	77, // 112: knowledge.KnowledgeService.SetDatabasePolicy:output_type -> knowledge.Response
+	64, // 113: knowledge.KnowledgeService.GetDatabasePolicy:output_type -> knowledge.DatabasePolicy
+	68, // 114: knowledge.KnowledgeService.ListDatabasePolicies:output_type -> knowledge.ListDatabasePoliciesResponse
+	55, // 115: knowledge.KnowledgeService.GetSystemStatus:output_type -> knowledge.SystemStatus
+	48, // 116: knowledge.KnowledgeService.GetSystemStats:output_type -> knowledge.GetSystemStatsResponse
+	51, // 117: knowledge.KnowledgeService.GetActionStats:output_type -> knowledge.GetActionStatsResponse
+	74, // 118: knowledge.KnowledgeService.FlushAllData:output_type -> knowledge.FlushAllDataResponse
+	76, // 119: knowledge.KnowledgeService.ForceCleanup:output_type -> knowledge.ForceCleanupResponse
+	77, // [77:120] is the sub-list for method output_type
+	34, // [34:77] is the sub-list for method input_type
+	34, // [34:34] is the sub-list for extension type_name
+	34, // [34:34] is the sub-list for extension extendee
+	0,  // [0:34] is the sub-list for field type_name
 }
 
 func init() { file_knowledge_proto_init() }
@@ -2689,7 +6141,7 @@ func file_knowledge_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_knowledge_proto_rawDesc), len(file_knowledge_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   38,
+			NumMessages:   90,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
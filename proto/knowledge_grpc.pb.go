@@ -19,23 +19,49 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	KnowledgeService_RegisterDetection_FullMethodName     = "/knowledge.KnowledgeService/RegisterDetection"
-	KnowledgeService_IsDetectionActive_FullMethodName     = "/knowledge.KnowledgeService/IsDetectionActive"
-	KnowledgeService_GetActiveDetections_FullMethodName   = "/knowledge.KnowledgeService/GetActiveDetections"
-	KnowledgeService_MarkDetectionResolved_FullMethodName = "/knowledge.KnowledgeService/MarkDetectionResolved"
-	KnowledgeService_RegisterAction_FullMethodName        = "/knowledge.KnowledgeService/RegisterAction"
-	KnowledgeService_UpdateActionStatus_FullMethodName    = "/knowledge.KnowledgeService/UpdateActionStatus"
-	KnowledgeService_GetPendingActions_FullMethodName     = "/knowledge.KnowledgeService/GetPendingActions"
-	KnowledgeService_RegisterDatabase_FullMethodName      = "/knowledge.KnowledgeService/RegisterDatabase"
-	KnowledgeService_GetDatabase_FullMethodName           = "/knowledge.KnowledgeService/GetDatabase"
-	KnowledgeService_ListDatabases_FullMethodName         = "/knowledge.KnowledgeService/ListDatabases"
-	KnowledgeService_UpdateDatabaseHealth_FullMethodName  = "/knowledge.KnowledgeService/UpdateDatabaseHealth"
-	KnowledgeService_UnregisterDatabase_FullMethodName    = "/knowledge.KnowledgeService/UnregisterDatabase"
-	KnowledgeService_UpdateDatabase_FullMethodName        = "/knowledge.KnowledgeService/UpdateDatabase"
-	KnowledgeService_GetSystemConfig_FullMethodName       = "/knowledge.KnowledgeService/GetSystemConfig"
-	KnowledgeService_SaveSystemConfig_FullMethodName      = "/knowledge.KnowledgeService/SaveSystemConfig"
-	KnowledgeService_GetSystemStatus_FullMethodName       = "/knowledge.KnowledgeService/GetSystemStatus"
-	KnowledgeService_FlushAllData_FullMethodName          = "/knowledge.KnowledgeService/FlushAllData"
+	KnowledgeService_RegisterDetection_FullMethodName               = "/knowledge.KnowledgeService/RegisterDetection"
+	KnowledgeService_RegisterDetectionIfAbsent_FullMethodName       = "/knowledge.KnowledgeService/RegisterDetectionIfAbsent"
+	KnowledgeService_IsDetectionActive_FullMethodName               = "/knowledge.KnowledgeService/IsDetectionActive"
+	KnowledgeService_RefreshDetection_FullMethodName                = "/knowledge.KnowledgeService/RefreshDetection"
+	KnowledgeService_UpdateDetectionSeverity_FullMethodName         = "/knowledge.KnowledgeService/UpdateDetectionSeverity"
+	KnowledgeService_GetActiveDetections_FullMethodName             = "/knowledge.KnowledgeService/GetActiveDetections"
+	KnowledgeService_MarkDetectionResolved_FullMethodName           = "/knowledge.KnowledgeService/MarkDetectionResolved"
+	KnowledgeService_ReopenDetection_FullMethodName                 = "/knowledge.KnowledgeService/ReopenDetection"
+	KnowledgeService_MarkDetectionUnactionable_FullMethodName       = "/knowledge.KnowledgeService/MarkDetectionUnactionable"
+	KnowledgeService_MarkDetectionAcknowledged_FullMethodName       = "/knowledge.KnowledgeService/MarkDetectionAcknowledged"
+	KnowledgeService_SuppressDetectionKey_FullMethodName            = "/knowledge.KnowledgeService/SuppressDetectionKey"
+	KnowledgeService_ListSuppressions_FullMethodName                = "/knowledge.KnowledgeService/ListSuppressions"
+	KnowledgeService_RegisterAction_FullMethodName                  = "/knowledge.KnowledgeService/RegisterAction"
+	KnowledgeService_UpdateActionStatus_FullMethodName              = "/knowledge.KnowledgeService/UpdateActionStatus"
+	KnowledgeService_GetPendingActions_FullMethodName               = "/knowledge.KnowledgeService/GetPendingActions"
+	KnowledgeService_GetAction_FullMethodName                       = "/knowledge.KnowledgeService/GetAction"
+	KnowledgeService_GetActionHistory_FullMethodName                = "/knowledge.KnowledgeService/GetActionHistory"
+	KnowledgeService_RecordActionOutcome_FullMethodName             = "/knowledge.KnowledgeService/RecordActionOutcome"
+	KnowledgeService_ListActionsByStatus_FullMethodName             = "/knowledge.KnowledgeService/ListActionsByStatus"
+	KnowledgeService_GetPendingImplementationSummary_FullMethodName = "/knowledge.KnowledgeService/GetPendingImplementationSummary"
+	KnowledgeService_RecordDatabaseAction_FullMethodName            = "/knowledge.KnowledgeService/RecordDatabaseAction"
+	KnowledgeService_GetRecentDatabaseActions_FullMethodName        = "/knowledge.KnowledgeService/GetRecentDatabaseActions"
+	KnowledgeService_RecordMetricSample_FullMethodName              = "/knowledge.KnowledgeService/RecordMetricSample"
+	KnowledgeService_GetMetricHistory_FullMethodName                = "/knowledge.KnowledgeService/GetMetricHistory"
+	KnowledgeService_RegisterDatabase_FullMethodName                = "/knowledge.KnowledgeService/RegisterDatabase"
+	KnowledgeService_GetDatabase_FullMethodName                     = "/knowledge.KnowledgeService/GetDatabase"
+	KnowledgeService_ListDatabases_FullMethodName                   = "/knowledge.KnowledgeService/ListDatabases"
+	KnowledgeService_GetDatabasesByType_FullMethodName              = "/knowledge.KnowledgeService/GetDatabasesByType"
+	KnowledgeService_UpdateDatabaseHealth_FullMethodName            = "/knowledge.KnowledgeService/UpdateDatabaseHealth"
+	KnowledgeService_UnregisterDatabase_FullMethodName              = "/knowledge.KnowledgeService/UnregisterDatabase"
+	KnowledgeService_UpdateDatabase_FullMethodName                  = "/knowledge.KnowledgeService/UpdateDatabase"
+	KnowledgeService_GetSystemConfig_FullMethodName                 = "/knowledge.KnowledgeService/GetSystemConfig"
+	KnowledgeService_SaveSystemConfig_FullMethodName                = "/knowledge.KnowledgeService/SaveSystemConfig"
+	KnowledgeService_SetThresholds_FullMethodName                   = "/knowledge.KnowledgeService/SetThresholds"
+	KnowledgeService_GetThresholds_FullMethodName                   = "/knowledge.KnowledgeService/GetThresholds"
+	KnowledgeService_SetDatabasePolicy_FullMethodName               = "/knowledge.KnowledgeService/SetDatabasePolicy"
+	KnowledgeService_GetDatabasePolicy_FullMethodName               = "/knowledge.KnowledgeService/GetDatabasePolicy"
+	KnowledgeService_ListDatabasePolicies_FullMethodName            = "/knowledge.KnowledgeService/ListDatabasePolicies"
+	KnowledgeService_GetSystemStatus_FullMethodName                 = "/knowledge.KnowledgeService/GetSystemStatus"
+	KnowledgeService_GetSystemStats_FullMethodName                  = "/knowledge.KnowledgeService/GetSystemStats"
+	KnowledgeService_GetActionStats_FullMethodName                  = "/knowledge.KnowledgeService/GetActionStats"
+	KnowledgeService_FlushAllData_FullMethodName                    = "/knowledge.KnowledgeService/FlushAllData"
+	KnowledgeService_ForceCleanup_FullMethodName                    = "/knowledge.KnowledgeService/ForceCleanup"
 )
 
 // KnowledgeServiceClient is the client API for KnowledgeService service.
@@ -46,24 +72,60 @@ const (
 type KnowledgeServiceClient interface {
 	// Detection operations
 	RegisterDetection(ctx context.Context, in *RegisterDetectionRequest, opts ...grpc.CallOption) (*DetectionResponse, error)
+	// Registers a detection unless one with the same key is already active, atomically; only the winner should publish it
+	RegisterDetectionIfAbsent(ctx context.Context, in *RegisterDetectionRequest, opts ...grpc.CallOption) (*RegisterDetectionIfAbsentResponse, error)
 	// Checks if a detection with the given key is currently active
 	IsDetectionActive(ctx context.Context, in *DetectionKeyRequest, opts ...grpc.CallOption) (*DetectionStatusResponse, error)
+	// Refreshes LastSeen on the active detection for a key, keeping it from going stale
+	RefreshDetection(ctx context.Context, in *DetectionKeyRequest, opts ...grpc.CallOption) (*Response, error)
+	// Stores a new severity and value on an active detection when the issue escalates or its value changes significantly
+	UpdateDetectionSeverity(ctx context.Context, in *UpdateDetectionSeverityRequest, opts ...grpc.CallOption) (*Response, error)
 	// Retrieves all active (unresolved) detections, optionally filtered by database
 	GetActiveDetections(ctx context.Context, in *DatabaseFilterRequest, opts ...grpc.CallOption) (*DetectionListResponse, error)
 	// Marks a detection as resolved, removing it from the active detections list
 	MarkDetectionResolved(ctx context.Context, in *ResolveDetectionRequest, opts ...grpc.CallOption) (*Response, error)
+	// Puts a resolved detection back in the active set after the action that fixed it failed or was rolled back
+	ReopenDetection(ctx context.Context, in *ReopenDetectionRequest, opts ...grpc.CallOption) (*Response, error)
+	// Records that the Executor could not act on a detection; it stays visible in GetActiveDetections
+	MarkDetectionUnactionable(ctx context.Context, in *UnactionableDetectionRequest, opts ...grpc.CallOption) (*Response, error)
+	// Parks a detection whose fix is not implemented yet; it leaves GetActiveDetections but keeps suppressing duplicates
+	MarkDetectionAcknowledged(ctx context.Context, in *AcknowledgeDetectionRequest, opts ...grpc.CallOption) (*Response, error)
+	// Snoozes a detection key for a while, so the Analyser stops publishing it; a zero duration lifts it
+	SuppressDetectionKey(ctx context.Context, in *SuppressDetectionKeyRequest, opts ...grpc.CallOption) (*Response, error)
+	// Retrieves the suppressions that have not expired yet
+	ListSuppressions(ctx context.Context, in *ListSuppressionsRequest, opts ...grpc.CallOption) (*ListSuppressionsResponse, error)
 	// Registers a new action in the knowledge base
 	RegisterAction(ctx context.Context, in *RegisterActionRequest, opts ...grpc.CallOption) (*ActionResponse, error)
 	// Updates the status of an existing action (e.g., pending, completed, failed)
 	UpdateActionStatus(ctx context.Context, in *UpdateActionRequest, opts ...grpc.CallOption) (*Response, error)
 	// Retrieves all pending actions, optionally filtered by database
 	GetPendingActions(ctx context.Context, in *DatabaseFilterRequest, opts ...grpc.CallOption) (*ActionListResponse, error)
+	// Retrieves a single action, including the state needed to rebuild it
+	GetAction(ctx context.Context, in *GetActionRequest, opts ...grpc.CallOption) (*GetActionResponse, error)
+	// Retrieves the status timeline of an action, oldest first
+	GetActionHistory(ctx context.Context, in *GetActionHistoryRequest, opts ...grpc.CallOption) (*GetActionHistoryResponse, error)
+	// Records whether a verified action improved the metric behind its detection
+	RecordActionOutcome(ctx context.Context, in *RecordActionOutcomeRequest, opts ...grpc.CallOption) (*Response, error)
+	// Retrieves all actions in any of the given statuses, optionally filtered by database
+	ListActionsByStatus(ctx context.Context, in *ListActionsByStatusRequest, opts ...grpc.CallOption) (*ActionListResponse, error)
+	// Retrieves pending_implementation actions grouped by action type, most requested first
+	GetPendingImplementationSummary(ctx context.Context, in *GetPendingImplementationSummaryRequest, opts ...grpc.CallOption) (*GetPendingImplementationSummaryResponse, error)
+	// Records that a mutating action started on a database, for cooldown and rate limiting
+	RecordDatabaseAction(ctx context.Context, in *RecordDatabaseActionRequest, opts ...grpc.CallOption) (*Response, error)
+	// Retrieves when mutating actions started on a database since a given time
+	GetRecentDatabaseActions(ctx context.Context, in *RecentDatabaseActionsRequest, opts ...grpc.CallOption) (*RecentDatabaseActionsResponse, error)
+	// Records one collection cycle's health scores and key measurements for a database
+	RecordMetricSample(ctx context.Context, in *MetricSample, opts ...grpc.CallOption) (*Response, error)
+	// Retrieves a database's metric samples over a time range, averaged to a resolution
+	GetMetricHistory(ctx context.Context, in *GetMetricHistoryRequest, opts ...grpc.CallOption) (*GetMetricHistoryResponse, error)
 	// Registers a new database with the knowledge service
 	RegisterDatabase(ctx context.Context, in *RegisterDatabaseRequest, opts ...grpc.CallOption) (*DatabaseResponse, error)
 	// Retrieves detailed information about a specific registered database
 	GetDatabase(ctx context.Context, in *GetDatabaseRequest, opts ...grpc.CallOption) (*GetDatabaseResponse, error)
 	// Lists all registered databases in the system
 	ListDatabases(ctx context.Context, in *ListDatabasesRequest, opts ...grpc.CallOption) (*DatabaseListResponse, error)
+	// Lists registered databases of a single type (postgres, mysql, mongodb, ...)
+	GetDatabasesByType(ctx context.Context, in *GetDatabasesByTypeRequest, opts ...grpc.CallOption) (*DatabaseListResponse, error)
 	// Updates the health status of a registered database
 	UpdateDatabaseHealth(ctx context.Context, in *UpdateDatabaseHealthRequest, opts ...grpc.CallOption) (*Response, error)
 	// Removes a database from the registry
@@ -74,10 +136,26 @@ type KnowledgeServiceClient interface {
 	GetSystemConfig(ctx context.Context, in *GetSystemConfigRequest, opts ...grpc.CallOption) (*SystemConfig, error)
 	// Saves or updates the system configuration settings
 	SaveSystemConfig(ctx context.Context, in *SaveSystemConfigRequest, opts ...grpc.CallOption) (*Response, error)
+	// Replaces the detection threshold overrides for one database, or the global default
+	SetThresholds(ctx context.Context, in *SetThresholdsRequest, opts ...grpc.CallOption) (*Response, error)
+	// Retrieves detection threshold overrides (the global default plus per-database sets)
+	GetThresholds(ctx context.Context, in *GetThresholdsRequest, opts ...grpc.CallOption) (*GetThresholdsResponse, error)
+	// Replaces a database's policy: the action types it allows or denies and the detectors it ignores
+	SetDatabasePolicy(ctx context.Context, in *SetDatabasePolicyRequest, opts ...grpc.CallOption) (*Response, error)
+	// Retrieves a database's policy; a database without one gets an empty policy
+	GetDatabasePolicy(ctx context.Context, in *GetDatabasePolicyRequest, opts ...grpc.CallOption) (*DatabasePolicy, error)
+	// Lists every stored database policy
+	ListDatabasePolicies(ctx context.Context, in *ListDatabasePoliciesRequest, opts ...grpc.CallOption) (*ListDatabasePoliciesResponse, error)
 	// Retrieves the current operational status of the system
 	GetSystemStatus(ctx context.Context, in *GetSystemStatusRequest, opts ...grpc.CallOption) (*SystemStatus, error)
+	// Retrieves system-wide counts of databases, detections and actions (served from counters, cheap to poll)
+	GetSystemStats(ctx context.Context, in *GetSystemStatsRequest, opts ...grpc.CallOption) (*GetSystemStatsResponse, error)
+	// Retrieves how often each action type succeeded, failed, was rolled back and was verified effective
+	GetActionStats(ctx context.Context, in *GetActionStatsRequest, opts ...grpc.CallOption) (*GetActionStatsResponse, error)
 	// Clears all data from the knowledge service (detections, actions, etc.)
 	FlushAllData(ctx context.Context, in *FlushAllDataRequest, opts ...grpc.CallOption) (*FlushAllDataResponse, error)
+	// Runs retention cleanup now instead of waiting for the next scheduled run
+	ForceCleanup(ctx context.Context, in *ForceCleanupRequest, opts ...grpc.CallOption) (*ForceCleanupResponse, error)
 }
 
 type knowledgeServiceClient struct {
@@ -98,6 +176,16 @@ func (c *knowledgeServiceClient) RegisterDetection(ctx context.Context, in *Regi
 	return out, nil
 }
 
+func (c *knowledgeServiceClient) RegisterDetectionIfAbsent(ctx context.Context, in *RegisterDetectionRequest, opts ...grpc.CallOption) (*RegisterDetectionIfAbsentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterDetectionIfAbsentResponse)
+	err := c.cc.Invoke(ctx, KnowledgeService_RegisterDetectionIfAbsent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *knowledgeServiceClient) IsDetectionActive(ctx context.Context, in *DetectionKeyRequest, opts ...grpc.CallOption) (*DetectionStatusResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DetectionStatusResponse)
@@ -108,6 +196,26 @@ func (c *knowledgeServiceClient) IsDetectionActive(ctx context.Context, in *Dete
 	return out, nil
 }
 
+func (c *knowledgeServiceClient) RefreshDetection(ctx context.Context, in *DetectionKeyRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, KnowledgeService_RefreshDetection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *knowledgeServiceClient) UpdateDetectionSeverity(ctx context.Context, in *UpdateDetectionSeverityRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, KnowledgeService_UpdateDetectionSeverity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *knowledgeServiceClient) GetActiveDetections(ctx context.Context, in *DatabaseFilterRequest, opts ...grpc.CallOption) (*DetectionListResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DetectionListResponse)
@@ -128,6 +236,56 @@ func (c *knowledgeServiceClient) MarkDetectionResolved(ctx context.Context, in *
 	return out, nil
 }
 
+func (c *knowledgeServiceClient) ReopenDetection(ctx context.Context, in *ReopenDetectionRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, KnowledgeService_ReopenDetection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *knowledgeServiceClient) MarkDetectionUnactionable(ctx context.Context, in *UnactionableDetectionRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, KnowledgeService_MarkDetectionUnactionable_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *knowledgeServiceClient) MarkDetectionAcknowledged(ctx context.Context, in *AcknowledgeDetectionRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, KnowledgeService_MarkDetectionAcknowledged_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *knowledgeServiceClient) SuppressDetectionKey(ctx context.Context, in *SuppressDetectionKeyRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, KnowledgeService_SuppressDetectionKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *knowledgeServiceClient) ListSuppressions(ctx context.Context, in *ListSuppressionsRequest, opts ...grpc.CallOption) (*ListSuppressionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSuppressionsResponse)
+	err := c.cc.Invoke(ctx, KnowledgeService_ListSuppressions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *knowledgeServiceClient) RegisterAction(ctx context.Context, in *RegisterActionRequest, opts ...grpc.CallOption) (*ActionResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ActionResponse)
@@ -158,6 +316,96 @@ func (c *knowledgeServiceClient) GetPendingActions(ctx context.Context, in *Data
 	return out, nil
 }
 
+func (c *knowledgeServiceClient) GetAction(ctx context.Context, in *GetActionRequest, opts ...grpc.CallOption) (*GetActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetActionResponse)
+	err := c.cc.Invoke(ctx, KnowledgeService_GetAction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *knowledgeServiceClient) GetActionHistory(ctx context.Context, in *GetActionHistoryRequest, opts ...grpc.CallOption) (*GetActionHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetActionHistoryResponse)
+	err := c.cc.Invoke(ctx, KnowledgeService_GetActionHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *knowledgeServiceClient) RecordActionOutcome(ctx context.Context, in *RecordActionOutcomeRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, KnowledgeService_RecordActionOutcome_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *knowledgeServiceClient) ListActionsByStatus(ctx context.Context, in *ListActionsByStatusRequest, opts ...grpc.CallOption) (*ActionListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ActionListResponse)
+	err := c.cc.Invoke(ctx, KnowledgeService_ListActionsByStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *knowledgeServiceClient) GetPendingImplementationSummary(ctx context.Context, in *GetPendingImplementationSummaryRequest, opts ...grpc.CallOption) (*GetPendingImplementationSummaryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPendingImplementationSummaryResponse)
+	err := c.cc.Invoke(ctx, KnowledgeService_GetPendingImplementationSummary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *knowledgeServiceClient) RecordDatabaseAction(ctx context.Context, in *RecordDatabaseActionRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, KnowledgeService_RecordDatabaseAction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *knowledgeServiceClient) GetRecentDatabaseActions(ctx context.Context, in *RecentDatabaseActionsRequest, opts ...grpc.CallOption) (*RecentDatabaseActionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecentDatabaseActionsResponse)
+	err := c.cc.Invoke(ctx, KnowledgeService_GetRecentDatabaseActions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *knowledgeServiceClient) RecordMetricSample(ctx context.Context, in *MetricSample, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, KnowledgeService_RecordMetricSample_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *knowledgeServiceClient) GetMetricHistory(ctx context.Context, in *GetMetricHistoryRequest, opts ...grpc.CallOption) (*GetMetricHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMetricHistoryResponse)
+	err := c.cc.Invoke(ctx, KnowledgeService_GetMetricHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *knowledgeServiceClient) RegisterDatabase(ctx context.Context, in *RegisterDatabaseRequest, opts ...grpc.CallOption) (*DatabaseResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DatabaseResponse)
@@ -188,6 +436,16 @@ func (c *knowledgeServiceClient) ListDatabases(ctx context.Context, in *ListData
 	return out, nil
 }
 
+func (c *knowledgeServiceClient) GetDatabasesByType(ctx context.Context, in *GetDatabasesByTypeRequest, opts ...grpc.CallOption) (*DatabaseListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DatabaseListResponse)
+	err := c.cc.Invoke(ctx, KnowledgeService_GetDatabasesByType_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *knowledgeServiceClient) UpdateDatabaseHealth(ctx context.Context, in *UpdateDatabaseHealthRequest, opts ...grpc.CallOption) (*Response, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Response)
@@ -238,6 +496,56 @@ func (c *knowledgeServiceClient) SaveSystemConfig(ctx context.Context, in *SaveS
 	return out, nil
 }
 
+func (c *knowledgeServiceClient) SetThresholds(ctx context.Context, in *SetThresholdsRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, KnowledgeService_SetThresholds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *knowledgeServiceClient) GetThresholds(ctx context.Context, in *GetThresholdsRequest, opts ...grpc.CallOption) (*GetThresholdsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetThresholdsResponse)
+	err := c.cc.Invoke(ctx, KnowledgeService_GetThresholds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *knowledgeServiceClient) SetDatabasePolicy(ctx context.Context, in *SetDatabasePolicyRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, KnowledgeService_SetDatabasePolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *knowledgeServiceClient) GetDatabasePolicy(ctx context.Context, in *GetDatabasePolicyRequest, opts ...grpc.CallOption) (*DatabasePolicy, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DatabasePolicy)
+	err := c.cc.Invoke(ctx, KnowledgeService_GetDatabasePolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *knowledgeServiceClient) ListDatabasePolicies(ctx context.Context, in *ListDatabasePoliciesRequest, opts ...grpc.CallOption) (*ListDatabasePoliciesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDatabasePoliciesResponse)
+	err := c.cc.Invoke(ctx, KnowledgeService_ListDatabasePolicies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *knowledgeServiceClient) GetSystemStatus(ctx context.Context, in *GetSystemStatusRequest, opts ...grpc.CallOption) (*SystemStatus, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(SystemStatus)
@@ -248,6 +556,26 @@ func (c *knowledgeServiceClient) GetSystemStatus(ctx context.Context, in *GetSys
 	return out, nil
 }
 
+func (c *knowledgeServiceClient) GetSystemStats(ctx context.Context, in *GetSystemStatsRequest, opts ...grpc.CallOption) (*GetSystemStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSystemStatsResponse)
+	err := c.cc.Invoke(ctx, KnowledgeService_GetSystemStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *knowledgeServiceClient) GetActionStats(ctx context.Context, in *GetActionStatsRequest, opts ...grpc.CallOption) (*GetActionStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetActionStatsResponse)
+	err := c.cc.Invoke(ctx, KnowledgeService_GetActionStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *knowledgeServiceClient) FlushAllData(ctx context.Context, in *FlushAllDataRequest, opts ...grpc.CallOption) (*FlushAllDataResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(FlushAllDataResponse)
@@ -258,6 +586,16 @@ func (c *knowledgeServiceClient) FlushAllData(ctx context.Context, in *FlushAllD
 	return out, nil
 }
 
+func (c *knowledgeServiceClient) ForceCleanup(ctx context.Context, in *ForceCleanupRequest, opts ...grpc.CallOption) (*ForceCleanupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ForceCleanupResponse)
+	err := c.cc.Invoke(ctx, KnowledgeService_ForceCleanup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // KnowledgeServiceServer is the server API for KnowledgeService service.
 // All implementations must embed UnimplementedKnowledgeServiceServer
 // for forward compatibility.
@@ -266,24 +604,60 @@ func (c *knowledgeServiceClient) FlushAllData(ctx context.Context, in *FlushAllD
 type KnowledgeServiceServer interface {
 	// Detection operations
 	RegisterDetection(context.Context, *RegisterDetectionRequest) (*DetectionResponse, error)
+	// Registers a detection unless one with the same key is already active, atomically; only the winner should publish it
+	RegisterDetectionIfAbsent(context.Context, *RegisterDetectionRequest) (*RegisterDetectionIfAbsentResponse, error)
 	// Checks if a detection with the given key is currently active
 	IsDetectionActive(context.Context, *DetectionKeyRequest) (*DetectionStatusResponse, error)
+	// Refreshes LastSeen on the active detection for a key, keeping it from going stale
+	RefreshDetection(context.Context, *DetectionKeyRequest) (*Response, error)
+	// Stores a new severity and value on an active detection when the issue escalates or its value changes significantly
+	UpdateDetectionSeverity(context.Context, *UpdateDetectionSeverityRequest) (*Response, error)
 	// Retrieves all active (unresolved) detections, optionally filtered by database
 	GetActiveDetections(context.Context, *DatabaseFilterRequest) (*DetectionListResponse, error)
 	// Marks a detection as resolved, removing it from the active detections list
 	MarkDetectionResolved(context.Context, *ResolveDetectionRequest) (*Response, error)
+	// Puts a resolved detection back in the active set after the action that fixed it failed or was rolled back
+	ReopenDetection(context.Context, *ReopenDetectionRequest) (*Response, error)
+	// Records that the Executor could not act on a detection; it stays visible in GetActiveDetections
+	MarkDetectionUnactionable(context.Context, *UnactionableDetectionRequest) (*Response, error)
+	// Parks a detection whose fix is not implemented yet; it leaves GetActiveDetections but keeps suppressing duplicates
+	MarkDetectionAcknowledged(context.Context, *AcknowledgeDetectionRequest) (*Response, error)
+	// Snoozes a detection key for a while, so the Analyser stops publishing it; a zero duration lifts it
+	SuppressDetectionKey(context.Context, *SuppressDetectionKeyRequest) (*Response, error)
+	// Retrieves the suppressions that have not expired yet
+	ListSuppressions(context.Context, *ListSuppressionsRequest) (*ListSuppressionsResponse, error)
 	// Registers a new action in the knowledge base
 	RegisterAction(context.Context, *RegisterActionRequest) (*ActionResponse, error)
 	// Updates the status of an existing action (e.g., pending, completed, failed)
 	UpdateActionStatus(context.Context, *UpdateActionRequest) (*Response, error)
 	// Retrieves all pending actions, optionally filtered by database
 	GetPendingActions(context.Context, *DatabaseFilterRequest) (*ActionListResponse, error)
+	// Retrieves a single action, including the state needed to rebuild it
+	GetAction(context.Context, *GetActionRequest) (*GetActionResponse, error)
+	// Retrieves the status timeline of an action, oldest first
+	GetActionHistory(context.Context, *GetActionHistoryRequest) (*GetActionHistoryResponse, error)
+	// Records whether a verified action improved the metric behind its detection
+	RecordActionOutcome(context.Context, *RecordActionOutcomeRequest) (*Response, error)
+	// Retrieves all actions in any of the given statuses, optionally filtered by database
+	ListActionsByStatus(context.Context, *ListActionsByStatusRequest) (*ActionListResponse, error)
+	// Retrieves pending_implementation actions grouped by action type, most requested first
+	GetPendingImplementationSummary(context.Context, *GetPendingImplementationSummaryRequest) (*GetPendingImplementationSummaryResponse, error)
+	// Records that a mutating action started on a database, for cooldown and rate limiting
+	RecordDatabaseAction(context.Context, *RecordDatabaseActionRequest) (*Response, error)
+	// Retrieves when mutating actions started on a database since a given time
+	GetRecentDatabaseActions(context.Context, *RecentDatabaseActionsRequest) (*RecentDatabaseActionsResponse, error)
+	// Records one collection cycle's health scores and key measurements for a database
+	RecordMetricSample(context.Context, *MetricSample) (*Response, error)
+	// Retrieves a database's metric samples over a time range, averaged to a resolution
+	GetMetricHistory(context.Context, *GetMetricHistoryRequest) (*GetMetricHistoryResponse, error)
 	// Registers a new database with the knowledge service
 	RegisterDatabase(context.Context, *RegisterDatabaseRequest) (*DatabaseResponse, error)
 	// Retrieves detailed information about a specific registered database
 	GetDatabase(context.Context, *GetDatabaseRequest) (*GetDatabaseResponse, error)
 	// Lists all registered databases in the system
 	ListDatabases(context.Context, *ListDatabasesRequest) (*DatabaseListResponse, error)
+	// Lists registered databases of a single type (postgres, mysql, mongodb, ...)
+	GetDatabasesByType(context.Context, *GetDatabasesByTypeRequest) (*DatabaseListResponse, error)
 	// Updates the health status of a registered database
 	UpdateDatabaseHealth(context.Context, *UpdateDatabaseHealthRequest) (*Response, error)
 	// Removes a database from the registry
@@ -294,10 +668,26 @@ type KnowledgeServiceServer interface {
 	GetSystemConfig(context.Context, *GetSystemConfigRequest) (*SystemConfig, error)
 	// Saves or updates the system configuration settings
 	SaveSystemConfig(context.Context, *SaveSystemConfigRequest) (*Response, error)
+	// Replaces the detection threshold overrides for one database, or the global default
+	SetThresholds(context.Context, *SetThresholdsRequest) (*Response, error)
+	// Retrieves detection threshold overrides (the global default plus per-database sets)
+	GetThresholds(context.Context, *GetThresholdsRequest) (*GetThresholdsResponse, error)
+	// Replaces a database's policy: the action types it allows or denies and the detectors it ignores
+	SetDatabasePolicy(context.Context, *SetDatabasePolicyRequest) (*Response, error)
+	// Retrieves a database's policy; a database without one gets an empty policy
+	GetDatabasePolicy(context.Context, *GetDatabasePolicyRequest) (*DatabasePolicy, error)
+	// Lists every stored database policy
+	ListDatabasePolicies(context.Context, *ListDatabasePoliciesRequest) (*ListDatabasePoliciesResponse, error)
 	// Retrieves the current operational status of the system
 	GetSystemStatus(context.Context, *GetSystemStatusRequest) (*SystemStatus, error)
+	// Retrieves system-wide counts of databases, detections and actions (served from counters, cheap to poll)
+	GetSystemStats(context.Context, *GetSystemStatsRequest) (*GetSystemStatsResponse, error)
+	// Retrieves how often each action type succeeded, failed, was rolled back and was verified effective
+	GetActionStats(context.Context, *GetActionStatsRequest) (*GetActionStatsResponse, error)
 	// Clears all data from the knowledge service (detections, actions, etc.)
 	FlushAllData(context.Context, *FlushAllDataRequest) (*FlushAllDataResponse, error)
+	// Runs retention cleanup now instead of waiting for the next scheduled run
+	ForceCleanup(context.Context, *ForceCleanupRequest) (*ForceCleanupResponse, error)
 	mustEmbedUnimplementedKnowledgeServiceServer()
 }
 
@@ -311,15 +701,39 @@ type UnimplementedKnowledgeServiceServer struct{}
 func (UnimplementedKnowledgeServiceServer) RegisterDetection(context.Context, *RegisterDetectionRequest) (*DetectionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RegisterDetection not implemented")
 }
+func (UnimplementedKnowledgeServiceServer) RegisterDetectionIfAbsent(context.Context, *RegisterDetectionRequest) (*RegisterDetectionIfAbsentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterDetectionIfAbsent not implemented")
+}
 func (UnimplementedKnowledgeServiceServer) IsDetectionActive(context.Context, *DetectionKeyRequest) (*DetectionStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method IsDetectionActive not implemented")
 }
+func (UnimplementedKnowledgeServiceServer) RefreshDetection(context.Context, *DetectionKeyRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshDetection not implemented")
+}
+func (UnimplementedKnowledgeServiceServer) UpdateDetectionSeverity(context.Context, *UpdateDetectionSeverityRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDetectionSeverity not implemented")
+}
 func (UnimplementedKnowledgeServiceServer) GetActiveDetections(context.Context, *DatabaseFilterRequest) (*DetectionListResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetActiveDetections not implemented")
 }
 func (UnimplementedKnowledgeServiceServer) MarkDetectionResolved(context.Context, *ResolveDetectionRequest) (*Response, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method MarkDetectionResolved not implemented")
 }
+func (UnimplementedKnowledgeServiceServer) ReopenDetection(context.Context, *ReopenDetectionRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReopenDetection not implemented")
+}
+func (UnimplementedKnowledgeServiceServer) MarkDetectionUnactionable(context.Context, *UnactionableDetectionRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MarkDetectionUnactionable not implemented")
+}
+func (UnimplementedKnowledgeServiceServer) MarkDetectionAcknowledged(context.Context, *AcknowledgeDetectionRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MarkDetectionAcknowledged not implemented")
+}
+func (UnimplementedKnowledgeServiceServer) SuppressDetectionKey(context.Context, *SuppressDetectionKeyRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuppressDetectionKey not implemented")
+}
+func (UnimplementedKnowledgeServiceServer) ListSuppressions(context.Context, *ListSuppressionsRequest) (*ListSuppressionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSuppressions not implemented")
+}
 func (UnimplementedKnowledgeServiceServer) RegisterAction(context.Context, *RegisterActionRequest) (*ActionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RegisterAction not implemented")
 }
@@ -329,6 +743,33 @@ func (UnimplementedKnowledgeServiceServer) UpdateActionStatus(context.Context, *
 func (UnimplementedKnowledgeServiceServer) GetPendingActions(context.Context, *DatabaseFilterRequest) (*ActionListResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPendingActions not implemented")
 }
+func (UnimplementedKnowledgeServiceServer) GetAction(context.Context, *GetActionRequest) (*GetActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAction not implemented")
+}
+func (UnimplementedKnowledgeServiceServer) GetActionHistory(context.Context, *GetActionHistoryRequest) (*GetActionHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetActionHistory not implemented")
+}
+func (UnimplementedKnowledgeServiceServer) RecordActionOutcome(context.Context, *RecordActionOutcomeRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecordActionOutcome not implemented")
+}
+func (UnimplementedKnowledgeServiceServer) ListActionsByStatus(context.Context, *ListActionsByStatusRequest) (*ActionListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListActionsByStatus not implemented")
+}
+func (UnimplementedKnowledgeServiceServer) GetPendingImplementationSummary(context.Context, *GetPendingImplementationSummaryRequest) (*GetPendingImplementationSummaryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPendingImplementationSummary not implemented")
+}
+func (UnimplementedKnowledgeServiceServer) RecordDatabaseAction(context.Context, *RecordDatabaseActionRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecordDatabaseAction not implemented")
+}
+func (UnimplementedKnowledgeServiceServer) GetRecentDatabaseActions(context.Context, *RecentDatabaseActionsRequest) (*RecentDatabaseActionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRecentDatabaseActions not implemented")
+}
+func (UnimplementedKnowledgeServiceServer) RecordMetricSample(context.Context, *MetricSample) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecordMetricSample not implemented")
+}
+func (UnimplementedKnowledgeServiceServer) GetMetricHistory(context.Context, *GetMetricHistoryRequest) (*GetMetricHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMetricHistory not implemented")
+}
 func (UnimplementedKnowledgeServiceServer) RegisterDatabase(context.Context, *RegisterDatabaseRequest) (*DatabaseResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RegisterDatabase not implemented")
 }
@@ -338,6 +779,9 @@ func (UnimplementedKnowledgeServiceServer) GetDatabase(context.Context, *GetData
 func (UnimplementedKnowledgeServiceServer) ListDatabases(context.Context, *ListDatabasesRequest) (*DatabaseListResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListDatabases not implemented")
 }
+func (UnimplementedKnowledgeServiceServer) GetDatabasesByType(context.Context, *GetDatabasesByTypeRequest) (*DatabaseListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDatabasesByType not implemented")
+}
 func (UnimplementedKnowledgeServiceServer) UpdateDatabaseHealth(context.Context, *UpdateDatabaseHealthRequest) (*Response, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateDatabaseHealth not implemented")
 }
@@ -353,12 +797,36 @@ func (UnimplementedKnowledgeServiceServer) GetSystemConfig(context.Context, *Get
 func (UnimplementedKnowledgeServiceServer) SaveSystemConfig(context.Context, *SaveSystemConfigRequest) (*Response, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SaveSystemConfig not implemented")
 }
+func (UnimplementedKnowledgeServiceServer) SetThresholds(context.Context, *SetThresholdsRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetThresholds not implemented")
+}
+func (UnimplementedKnowledgeServiceServer) GetThresholds(context.Context, *GetThresholdsRequest) (*GetThresholdsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetThresholds not implemented")
+}
+func (UnimplementedKnowledgeServiceServer) SetDatabasePolicy(context.Context, *SetDatabasePolicyRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDatabasePolicy not implemented")
+}
+func (UnimplementedKnowledgeServiceServer) GetDatabasePolicy(context.Context, *GetDatabasePolicyRequest) (*DatabasePolicy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDatabasePolicy not implemented")
+}
+func (UnimplementedKnowledgeServiceServer) ListDatabasePolicies(context.Context, *ListDatabasePoliciesRequest) (*ListDatabasePoliciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDatabasePolicies not implemented")
+}
 func (UnimplementedKnowledgeServiceServer) GetSystemStatus(context.Context, *GetSystemStatusRequest) (*SystemStatus, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetSystemStatus not implemented")
 }
+func (UnimplementedKnowledgeServiceServer) GetSystemStats(context.Context, *GetSystemStatsRequest) (*GetSystemStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSystemStats not implemented")
+}
+func (UnimplementedKnowledgeServiceServer) GetActionStats(context.Context, *GetActionStatsRequest) (*GetActionStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetActionStats not implemented")
+}
 func (UnimplementedKnowledgeServiceServer) FlushAllData(context.Context, *FlushAllDataRequest) (*FlushAllDataResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FlushAllData not implemented")
 }
+func (UnimplementedKnowledgeServiceServer) ForceCleanup(context.Context, *ForceCleanupRequest) (*ForceCleanupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ForceCleanup not implemented")
+}
 func (UnimplementedKnowledgeServiceServer) mustEmbedUnimplementedKnowledgeServiceServer() {}
 func (UnimplementedKnowledgeServiceServer) testEmbeddedByValue()                          {}
 
@@ -398,89 +866,233 @@ func _KnowledgeService_RegisterDetection_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
-func _KnowledgeService_IsDetectionActive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DetectionKeyRequest)
+func _KnowledgeService_RegisterDetectionIfAbsent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterDetectionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(KnowledgeServiceServer).IsDetectionActive(ctx, in)
+		return srv.(KnowledgeServiceServer).RegisterDetectionIfAbsent(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: KnowledgeService_IsDetectionActive_FullMethodName,
+		FullMethod: KnowledgeService_RegisterDetectionIfAbsent_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(KnowledgeServiceServer).IsDetectionActive(ctx, req.(*DetectionKeyRequest))
+		return srv.(KnowledgeServiceServer).RegisterDetectionIfAbsent(ctx, req.(*RegisterDetectionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _KnowledgeService_GetActiveDetections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DatabaseFilterRequest)
+func _KnowledgeService_IsDetectionActive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DetectionKeyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(KnowledgeServiceServer).GetActiveDetections(ctx, in)
+		return srv.(KnowledgeServiceServer).IsDetectionActive(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: KnowledgeService_GetActiveDetections_FullMethodName,
+		FullMethod: KnowledgeService_IsDetectionActive_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(KnowledgeServiceServer).GetActiveDetections(ctx, req.(*DatabaseFilterRequest))
+		return srv.(KnowledgeServiceServer).IsDetectionActive(ctx, req.(*DetectionKeyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _KnowledgeService_MarkDetectionResolved_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ResolveDetectionRequest)
+func _KnowledgeService_RefreshDetection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DetectionKeyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(KnowledgeServiceServer).MarkDetectionResolved(ctx, in)
+		return srv.(KnowledgeServiceServer).RefreshDetection(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: KnowledgeService_MarkDetectionResolved_FullMethodName,
+		FullMethod: KnowledgeService_RefreshDetection_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(KnowledgeServiceServer).MarkDetectionResolved(ctx, req.(*ResolveDetectionRequest))
+		return srv.(KnowledgeServiceServer).RefreshDetection(ctx, req.(*DetectionKeyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _KnowledgeService_RegisterAction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RegisterActionRequest)
+func _KnowledgeService_UpdateDetectionSeverity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateDetectionSeverityRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(KnowledgeServiceServer).RegisterAction(ctx, in)
+		return srv.(KnowledgeServiceServer).UpdateDetectionSeverity(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: KnowledgeService_RegisterAction_FullMethodName,
+		FullMethod: KnowledgeService_UpdateDetectionSeverity_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(KnowledgeServiceServer).RegisterAction(ctx, req.(*RegisterActionRequest))
+		return srv.(KnowledgeServiceServer).UpdateDetectionSeverity(ctx, req.(*UpdateDetectionSeverityRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _KnowledgeService_UpdateActionStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateActionRequest)
+func _KnowledgeService_GetActiveDetections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DatabaseFilterRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(KnowledgeServiceServer).UpdateActionStatus(ctx, in)
+		return srv.(KnowledgeServiceServer).GetActiveDetections(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: KnowledgeService_UpdateActionStatus_FullMethodName,
+		FullMethod: KnowledgeService_GetActiveDetections_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).GetActiveDetections(ctx, req.(*DatabaseFilterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KnowledgeService_MarkDetectionResolved_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveDetectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).MarkDetectionResolved(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_MarkDetectionResolved_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).MarkDetectionResolved(ctx, req.(*ResolveDetectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KnowledgeService_ReopenDetection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReopenDetectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).ReopenDetection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_ReopenDetection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).ReopenDetection(ctx, req.(*ReopenDetectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KnowledgeService_MarkDetectionUnactionable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnactionableDetectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).MarkDetectionUnactionable(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_MarkDetectionUnactionable_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).MarkDetectionUnactionable(ctx, req.(*UnactionableDetectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KnowledgeService_MarkDetectionAcknowledged_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcknowledgeDetectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).MarkDetectionAcknowledged(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_MarkDetectionAcknowledged_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).MarkDetectionAcknowledged(ctx, req.(*AcknowledgeDetectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KnowledgeService_SuppressDetectionKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuppressDetectionKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).SuppressDetectionKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_SuppressDetectionKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).SuppressDetectionKey(ctx, req.(*SuppressDetectionKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KnowledgeService_ListSuppressions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSuppressionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).ListSuppressions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_ListSuppressions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).ListSuppressions(ctx, req.(*ListSuppressionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KnowledgeService_RegisterAction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).RegisterAction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_RegisterAction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).RegisterAction(ctx, req.(*RegisterActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KnowledgeService_UpdateActionStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).UpdateActionStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_UpdateActionStatus_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(KnowledgeServiceServer).UpdateActionStatus(ctx, req.(*UpdateActionRequest))
@@ -506,6 +1118,168 @@ func _KnowledgeService_GetPendingActions_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _KnowledgeService_GetAction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).GetAction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_GetAction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).GetAction(ctx, req.(*GetActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KnowledgeService_GetActionHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetActionHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).GetActionHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_GetActionHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).GetActionHistory(ctx, req.(*GetActionHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KnowledgeService_RecordActionOutcome_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecordActionOutcomeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).RecordActionOutcome(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_RecordActionOutcome_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).RecordActionOutcome(ctx, req.(*RecordActionOutcomeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KnowledgeService_ListActionsByStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListActionsByStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).ListActionsByStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_ListActionsByStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).ListActionsByStatus(ctx, req.(*ListActionsByStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KnowledgeService_GetPendingImplementationSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPendingImplementationSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).GetPendingImplementationSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_GetPendingImplementationSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).GetPendingImplementationSummary(ctx, req.(*GetPendingImplementationSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KnowledgeService_RecordDatabaseAction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecordDatabaseActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).RecordDatabaseAction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_RecordDatabaseAction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).RecordDatabaseAction(ctx, req.(*RecordDatabaseActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KnowledgeService_GetRecentDatabaseActions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecentDatabaseActionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).GetRecentDatabaseActions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_GetRecentDatabaseActions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).GetRecentDatabaseActions(ctx, req.(*RecentDatabaseActionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KnowledgeService_RecordMetricSample_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MetricSample)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).RecordMetricSample(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_RecordMetricSample_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).RecordMetricSample(ctx, req.(*MetricSample))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KnowledgeService_GetMetricHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMetricHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).GetMetricHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_GetMetricHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).GetMetricHistory(ctx, req.(*GetMetricHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _KnowledgeService_RegisterDatabase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RegisterDatabaseRequest)
 	if err := dec(in); err != nil {
@@ -560,6 +1334,24 @@ func _KnowledgeService_ListDatabases_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _KnowledgeService_GetDatabasesByType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDatabasesByTypeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).GetDatabasesByType(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_GetDatabasesByType_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).GetDatabasesByType(ctx, req.(*GetDatabasesByTypeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _KnowledgeService_UpdateDatabaseHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpdateDatabaseHealthRequest)
 	if err := dec(in); err != nil {
@@ -650,6 +1442,96 @@ func _KnowledgeService_SaveSystemConfig_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _KnowledgeService_SetThresholds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetThresholdsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).SetThresholds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_SetThresholds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).SetThresholds(ctx, req.(*SetThresholdsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KnowledgeService_GetThresholds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetThresholdsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).GetThresholds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_GetThresholds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).GetThresholds(ctx, req.(*GetThresholdsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KnowledgeService_SetDatabasePolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDatabasePolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).SetDatabasePolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_SetDatabasePolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).SetDatabasePolicy(ctx, req.(*SetDatabasePolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KnowledgeService_GetDatabasePolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDatabasePolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).GetDatabasePolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_GetDatabasePolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).GetDatabasePolicy(ctx, req.(*GetDatabasePolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KnowledgeService_ListDatabasePolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDatabasePoliciesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).ListDatabasePolicies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_ListDatabasePolicies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).ListDatabasePolicies(ctx, req.(*ListDatabasePoliciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _KnowledgeService_GetSystemStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetSystemStatusRequest)
 	if err := dec(in); err != nil {
@@ -668,6 +1550,42 @@ func _KnowledgeService_GetSystemStatus_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _KnowledgeService_GetSystemStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSystemStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).GetSystemStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_GetSystemStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).GetSystemStats(ctx, req.(*GetSystemStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KnowledgeService_GetActionStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetActionStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).GetActionStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_GetActionStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).GetActionStats(ctx, req.(*GetActionStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _KnowledgeService_FlushAllData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(FlushAllDataRequest)
 	if err := dec(in); err != nil {
@@ -686,6 +1604,24 @@ func _KnowledgeService_FlushAllData_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _KnowledgeService_ForceCleanup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForceCleanupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KnowledgeServiceServer).ForceCleanup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KnowledgeService_ForceCleanup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KnowledgeServiceServer).ForceCleanup(ctx, req.(*ForceCleanupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // KnowledgeService_ServiceDesc is the grpc.ServiceDesc for KnowledgeService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -697,10 +1633,22 @@ var KnowledgeService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RegisterDetection",
 			Handler:    _KnowledgeService_RegisterDetection_Handler,
 		},
+		{
+			MethodName: "RegisterDetectionIfAbsent",
+			Handler:    _KnowledgeService_RegisterDetectionIfAbsent_Handler,
+		},
 		{
 			MethodName: "IsDetectionActive",
 			Handler:    _KnowledgeService_IsDetectionActive_Handler,
 		},
+		{
+			MethodName: "RefreshDetection",
+			Handler:    _KnowledgeService_RefreshDetection_Handler,
+		},
+		{
+			MethodName: "UpdateDetectionSeverity",
+			Handler:    _KnowledgeService_UpdateDetectionSeverity_Handler,
+		},
 		{
 			MethodName: "GetActiveDetections",
 			Handler:    _KnowledgeService_GetActiveDetections_Handler,
@@ -709,6 +1657,26 @@ var KnowledgeService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "MarkDetectionResolved",
 			Handler:    _KnowledgeService_MarkDetectionResolved_Handler,
 		},
+		{
+			MethodName: "ReopenDetection",
+			Handler:    _KnowledgeService_ReopenDetection_Handler,
+		},
+		{
+			MethodName: "MarkDetectionUnactionable",
+			Handler:    _KnowledgeService_MarkDetectionUnactionable_Handler,
+		},
+		{
+			MethodName: "MarkDetectionAcknowledged",
+			Handler:    _KnowledgeService_MarkDetectionAcknowledged_Handler,
+		},
+		{
+			MethodName: "SuppressDetectionKey",
+			Handler:    _KnowledgeService_SuppressDetectionKey_Handler,
+		},
+		{
+			MethodName: "ListSuppressions",
+			Handler:    _KnowledgeService_ListSuppressions_Handler,
+		},
 		{
 			MethodName: "RegisterAction",
 			Handler:    _KnowledgeService_RegisterAction_Handler,
@@ -721,6 +1689,42 @@ var KnowledgeService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetPendingActions",
 			Handler:    _KnowledgeService_GetPendingActions_Handler,
 		},
+		{
+			MethodName: "GetAction",
+			Handler:    _KnowledgeService_GetAction_Handler,
+		},
+		{
+			MethodName: "GetActionHistory",
+			Handler:    _KnowledgeService_GetActionHistory_Handler,
+		},
+		{
+			MethodName: "RecordActionOutcome",
+			Handler:    _KnowledgeService_RecordActionOutcome_Handler,
+		},
+		{
+			MethodName: "ListActionsByStatus",
+			Handler:    _KnowledgeService_ListActionsByStatus_Handler,
+		},
+		{
+			MethodName: "GetPendingImplementationSummary",
+			Handler:    _KnowledgeService_GetPendingImplementationSummary_Handler,
+		},
+		{
+			MethodName: "RecordDatabaseAction",
+			Handler:    _KnowledgeService_RecordDatabaseAction_Handler,
+		},
+		{
+			MethodName: "GetRecentDatabaseActions",
+			Handler:    _KnowledgeService_GetRecentDatabaseActions_Handler,
+		},
+		{
+			MethodName: "RecordMetricSample",
+			Handler:    _KnowledgeService_RecordMetricSample_Handler,
+		},
+		{
+			MethodName: "GetMetricHistory",
+			Handler:    _KnowledgeService_GetMetricHistory_Handler,
+		},
 		{
 			MethodName: "RegisterDatabase",
 			Handler:    _KnowledgeService_RegisterDatabase_Handler,
@@ -733,6 +1737,10 @@ var KnowledgeService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListDatabases",
 			Handler:    _KnowledgeService_ListDatabases_Handler,
 		},
+		{
+			MethodName: "GetDatabasesByType",
+			Handler:    _KnowledgeService_GetDatabasesByType_Handler,
+		},
 		{
 			MethodName: "UpdateDatabaseHealth",
 			Handler:    _KnowledgeService_UpdateDatabaseHealth_Handler,
@@ -753,14 +1761,46 @@ var KnowledgeService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SaveSystemConfig",
 			Handler:    _KnowledgeService_SaveSystemConfig_Handler,
 		},
+		{
+			MethodName: "SetThresholds",
+			Handler:    _KnowledgeService_SetThresholds_Handler,
+		},
+		{
+			MethodName: "GetThresholds",
+			Handler:    _KnowledgeService_GetThresholds_Handler,
+		},
+		{
+			MethodName: "SetDatabasePolicy",
+			Handler:    _KnowledgeService_SetDatabasePolicy_Handler,
+		},
+		{
+			MethodName: "GetDatabasePolicy",
+			Handler:    _KnowledgeService_GetDatabasePolicy_Handler,
+		},
+		{
+			MethodName: "ListDatabasePolicies",
+			Handler:    _KnowledgeService_ListDatabasePolicies_Handler,
+		},
 		{
 			MethodName: "GetSystemStatus",
 			Handler:    _KnowledgeService_GetSystemStatus_Handler,
 		},
+		{
+			MethodName: "GetSystemStats",
+			Handler:    _KnowledgeService_GetSystemStats_Handler,
+		},
+		{
+			MethodName: "GetActionStats",
+			Handler:    _KnowledgeService_GetActionStats_Handler,
+		},
 		{
 			MethodName: "FlushAllData",
 			Handler:    _KnowledgeService_FlushAllData_Handler,
 		},
+		{
+			MethodName: "ForceCleanup",
+			Handler:    _KnowledgeService_ForceCleanup_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "knowledge.proto",
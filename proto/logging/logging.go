@@ -0,0 +1,87 @@
+// Package logging sets up structured logging shared by every service and carries the
+// correlation ID that follows one collection cycle from the Collector through the Analyser
+// to the Executor. Grepping a correlation ID shows the full path of a single detection.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// CorrelationIDKey is the attribute name used for correlation IDs in log lines.
+const CorrelationIDKey = "correlation_id"
+
+// ParseLevel parses a LOG_LEVEL value (debug, info, warn or error). Empty means info.
+func ParseLevel(value string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "debug":
+		return slog.LevelDebug, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("invalid LOG_LEVEL %q (use debug, info, warn or error)", value)
+	}
+}
+
+// Setup installs a slog default logger writing to stderr at level, tagged with service.
+// LOG_FORMAT=json switches from key=value text to JSON lines. Output from the standard
+// log package is routed through the same handler at info level, so log.Printf is only
+// for progress messages; warnings and errors go through slog at their own level.
+func Setup(service string, level slog.Level) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	if strings.EqualFold(os.Getenv("LOG_FORMAT"), "json") {
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	} else {
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	}
+
+	logger := slog.New(handler).With("service", service)
+	slog.SetDefault(logger)
+	return logger
+}
+
+// NewCorrelationID returns a random 16-character hex ID.
+func NewCorrelationID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+type correlationKey struct{}
+
+// WithCorrelationID returns a context carrying id.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationID returns the ID carried by ctx, or "" if there is none.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// FromContext returns the default logger, tagged with the correlation ID carried by ctx.
+func FromContext(ctx context.Context) *slog.Logger {
+	return With(CorrelationID(ctx))
+}
+
+// With returns the default logger tagged with id; an empty id adds nothing.
+func With(id string) *slog.Logger {
+	if id == "" {
+		return slog.Default()
+	}
+	return slog.Default().With(CorrelationIDKey, id)
+}
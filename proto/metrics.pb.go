@@ -124,6 +124,20 @@ type MetricSnapshot struct {
 	DatabaseId   string `protobuf:"bytes,1,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
 	DatabaseType string `protobuf:"bytes,2,opt,name=database_type,json=databaseType,proto3" json:"database_type,omitempty"`
 	Timestamp    int64  `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Generated per collection cycle; carried onto detections and actions for log tracing
+	CorrelationId string `protobuf:"bytes,4,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	// Assigned by the Collector, increasing; acknowledged via MetricsAck.acked_sequence
+	Sequence uint64 `protobuf:"varint,5,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	// Sent late from the Collector's spool after an outage; timestamp is still when it was
+	// collected, so it describes the past rather than the database now
+	Replayed bool `protobuf:"varint,6,opt,name=replayed,proto3" json:"replayed,omitempty"`
+	// Seconds since the previous collection on the Collector's monotonic clock, which NTP
+	// adjustments don't move; 0 on the first snapshot or when it couldn't be measured
+	ElapsedSeconds float64 `protobuf:"fixed64,7,opt,name=elapsed_seconds,json=elapsedSeconds,proto3" json:"elapsed_seconds,omitempty"`
+	// The interval this database is being collected at. The Collector stretches it while the
+	// database is unhealthy or slow to answer its metric queries, so deltas cover longer
+	// periods than usual; 0 from older Collectors
+	CollectionIntervalSeconds float64 `protobuf:"fixed64,8,opt,name=collection_interval_seconds,json=collectionIntervalSeconds,proto3" json:"collection_interval_seconds,omitempty"`
 	// === Normalized Health Scores (0.0 - 1.0) ===
 	HealthScore      float64 `protobuf:"fixed64,10,opt,name=health_score,json=healthScore,proto3" json:"health_score,omitempty"`
 	ConnectionHealth float64 `protobuf:"fixed64,11,opt,name=connection_health,json=connectionHealth,proto3" json:"connection_health,omitempty"`
@@ -140,8 +154,12 @@ type MetricSnapshot struct {
 	// === Delta Fields ===
 	MetricDeltas     map[string]float64 `protobuf:"bytes,60,rep,name=metric_deltas,json=metricDeltas,proto3" json:"metric_deltas,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
 	TimeDeltaSeconds *float64           `protobuf:"fixed64,61,opt,name=time_delta_seconds,json=timeDeltaSeconds,proto3,oneof" json:"time_delta_seconds,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	// False when the deltas can't be trusted: the first snapshot, an interval too short to
+	// measure (time_delta_seconds is clamped), or a counter that went backwards because the
+	// database restarted or its statistics were reset. Unset from older Collectors
+	DeltasReliable *bool `protobuf:"varint,62,opt,name=deltas_reliable,json=deltasReliable,proto3,oneof" json:"deltas_reliable,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *MetricSnapshot) Reset() {
@@ -195,6 +213,41 @@ func (x *MetricSnapshot) GetTimestamp() int64 {
 	return 0
 }
 
+func (x *MetricSnapshot) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *MetricSnapshot) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *MetricSnapshot) GetReplayed() bool {
+	if x != nil {
+		return x.Replayed
+	}
+	return false
+}
+
+func (x *MetricSnapshot) GetElapsedSeconds() float64 {
+	if x != nil {
+		return x.ElapsedSeconds
+	}
+	return 0
+}
+
+func (x *MetricSnapshot) GetCollectionIntervalSeconds() float64 {
+	if x != nil {
+		return x.CollectionIntervalSeconds
+	}
+	return 0
+}
+
 func (x *MetricSnapshot) GetHealthScore() float64 {
 	if x != nil {
 		return x.HealthScore
@@ -272,6 +325,13 @@ func (x *MetricSnapshot) GetTimeDeltaSeconds() float64 {
 	return 0
 }
 
+func (x *MetricSnapshot) GetDeltasReliable() bool {
+	if x != nil && x.DeltasReliable != nil {
+		return *x.DeltasReliable
+	}
+	return false
+}
+
 // Measurements contains raw values for Analyser to detect anomalies
 type Measurements struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -504,9 +564,17 @@ func (x *RegistrationAck) GetAssignedId() string {
 
 // Acknowledge metrics received
 type MetricsAck struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TotalMetrics  int64                  `protobuf:"varint,1,opt,name=total_metrics,json=totalMetrics,proto3" json:"total_metrics,omitempty"`
-	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	TotalMetrics int64                  `protobuf:"varint,1,opt,name=total_metrics,json=totalMetrics,proto3" json:"total_metrics,omitempty"`
+	Status       string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// Highest snapshot sequence processed; the Collector can forget everything up to it
+	AckedSequence uint64 `protobuf:"varint,3,opt,name=acked_sequence,json=ackedSequence,proto3" json:"acked_sequence,omitempty"`
+	// Snapshots since the previous ack that were run through the detectors, and those
+	// dropped by validation or rate limiting. Rejected snapshots are acked too; resending
+	// them would be rejected again
+	Accepted      int64                `protobuf:"varint,4,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Rejected      int64                `protobuf:"varint,5,opt,name=rejected,proto3" json:"rejected,omitempty"`
+	Rejections    []*SnapshotRejection `protobuf:"bytes,6,rep,name=rejections,proto3" json:"rejections,omitempty"` // Capped; rejected holds the full count
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -555,6 +623,244 @@ func (x *MetricsAck) GetStatus() string {
 	return ""
 }
 
+func (x *MetricsAck) GetAckedSequence() uint64 {
+	if x != nil {
+		return x.AckedSequence
+	}
+	return 0
+}
+
+func (x *MetricsAck) GetAccepted() int64 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+func (x *MetricsAck) GetRejected() int64 {
+	if x != nil {
+		return x.Rejected
+	}
+	return 0
+}
+
+func (x *MetricsAck) GetRejections() []*SnapshotRejection {
+	if x != nil {
+		return x.Rejections
+	}
+	return nil
+}
+
+// A snapshot the Analyser dropped without running detectors
+type SnapshotRejection struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      uint64                 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	DatabaseId    string                 `protobuf:"bytes,2,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"` // missing_database_id, missing_timestamp, future_timestamp, duplicate_timestamp, out_of_order, rate_limited
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotRejection) Reset() {
+	*x = SnapshotRejection{}
+	mi := &file_metrics_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotRejection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotRejection) ProtoMessage() {}
+
+func (x *SnapshotRejection) ProtoReflect() protoreflect.Message {
+	mi := &file_metrics_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotRejection.ProtoReflect.Descriptor instead.
+func (*SnapshotRejection) Descriptor() ([]byte, []int) {
+	return file_metrics_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SnapshotRejection) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *SnapshotRejection) GetDatabaseId() string {
+	if x != nil {
+		return x.DatabaseId
+	}
+	return ""
+}
+
+func (x *SnapshotRejection) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type RegisteredDetectorsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Thresholds for this database, including its overrides from Knowledge; empty for the global ones
+	DatabaseId    string `protobuf:"bytes,1,opt,name=database_id,json=databaseId,proto3" json:"database_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisteredDetectorsRequest) Reset() {
+	*x = RegisteredDetectorsRequest{}
+	mi := &file_metrics_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisteredDetectorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisteredDetectorsRequest) ProtoMessage() {}
+
+func (x *RegisteredDetectorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_metrics_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisteredDetectorsRequest.ProtoReflect.Descriptor instead.
+func (*RegisteredDetectorsRequest) Descriptor() ([]byte, []int) {
+	return file_metrics_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RegisteredDetectorsRequest) GetDatabaseId() string {
+	if x != nil {
+		return x.DatabaseId
+	}
+	return ""
+}
+
+type RegisteredDetector struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Category      string                 `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
+	Thresholds    map[string]float64     `protobuf:"bytes,3,rep,name=thresholds,proto3" json:"thresholds,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"` // Threshold names as used for overrides in Knowledge
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisteredDetector) Reset() {
+	*x = RegisteredDetector{}
+	mi := &file_metrics_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisteredDetector) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisteredDetector) ProtoMessage() {}
+
+func (x *RegisteredDetector) ProtoReflect() protoreflect.Message {
+	mi := &file_metrics_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisteredDetector.ProtoReflect.Descriptor instead.
+func (*RegisteredDetector) Descriptor() ([]byte, []int) {
+	return file_metrics_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RegisteredDetector) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RegisteredDetector) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *RegisteredDetector) GetThresholds() map[string]float64 {
+	if x != nil {
+		return x.Thresholds
+	}
+	return nil
+}
+
+type RegisteredDetectorsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Detectors     []*RegisteredDetector  `protobuf:"bytes,1,rep,name=detectors,proto3" json:"detectors,omitempty"` // In the order they run
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisteredDetectorsResponse) Reset() {
+	*x = RegisteredDetectorsResponse{}
+	mi := &file_metrics_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisteredDetectorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisteredDetectorsResponse) ProtoMessage() {}
+
+func (x *RegisteredDetectorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_metrics_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisteredDetectorsResponse.ProtoReflect.Descriptor instead.
+func (*RegisteredDetectorsResponse) Descriptor() ([]byte, []int) {
+	return file_metrics_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RegisteredDetectorsResponse) GetDetectors() []*RegisteredDetector {
+	if x != nil {
+		return x.Detectors
+	}
+	return nil
+}
+
 var File_metrics_proto protoreflect.FileDescriptor
 
 const file_metrics_proto_rawDesc = "" +
@@ -569,12 +875,17 @@ const file_metrics_proto_rawDesc = "" +
 	"\x04host\x18\x05 \x01(\tR\x04host\x12'\n" +
 	"\x0fmax_connections\x18\n" +
 	" \x01(\x05R\x0emaxConnections\x12<\n" +
-	"\x1aconnection_pooling_enabled\x18\v \x01(\bR\x18connectionPoolingEnabled\"\x81\a\n" +
+	"\x1aconnection_pooling_enabled\x18\v \x01(\bR\x18connectionPoolingEnabled\"\x8b\t\n" +
 	"\x0eMetricSnapshot\x12\x1f\n" +
 	"\vdatabase_id\x18\x01 \x01(\tR\n" +
 	"databaseId\x12#\n" +
 	"\rdatabase_type\x18\x02 \x01(\tR\fdatabaseType\x12\x1c\n" +
-	"\ttimestamp\x18\x03 \x01(\x03R\ttimestamp\x12!\n" +
+	"\ttimestamp\x18\x03 \x01(\x03R\ttimestamp\x12%\n" +
+	"\x0ecorrelation_id\x18\x04 \x01(\tR\rcorrelationId\x12\x1a\n" +
+	"\bsequence\x18\x05 \x01(\x04R\bsequence\x12\x1a\n" +
+	"\breplayed\x18\x06 \x01(\bR\breplayed\x12'\n" +
+	"\x0felapsed_seconds\x18\a \x01(\x01R\x0eelapsedSeconds\x12>\n" +
+	"\x1bcollection_interval_seconds\x18\b \x01(\x01R\x19collectionIntervalSeconds\x12!\n" +
 	"\fhealth_score\x18\n" +
 	" \x01(\x01R\vhealthScore\x12+\n" +
 	"\x11connection_health\x18\v \x01(\x01R\x10connectionHealth\x12!\n" +
@@ -586,7 +897,8 @@ const file_metrics_proto_rawDesc = "" +
 	"\x10extended_metrics\x18( \x03(\v2*.proto.MetricSnapshot.ExtendedMetricsEntryR\x0fextendedMetrics\x129\n" +
 	"\x06labels\x182 \x03(\v2!.proto.MetricSnapshot.LabelsEntryR\x06labels\x12L\n" +
 	"\rmetric_deltas\x18< \x03(\v2'.proto.MetricSnapshot.MetricDeltasEntryR\fmetricDeltas\x121\n" +
-	"\x12time_delta_seconds\x18= \x01(\x01H\x00R\x10timeDeltaSeconds\x88\x01\x01\x1aB\n" +
+	"\x12time_delta_seconds\x18= \x01(\x01H\x00R\x10timeDeltaSeconds\x88\x01\x01\x12,\n" +
+	"\x0fdeltas_reliable\x18> \x01(\bH\x01R\x0edeltasReliable\x88\x01\x01\x1aB\n" +
 	"\x14ExtendedMetricsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\x1a9\n" +
@@ -596,7 +908,8 @@ const file_metrics_proto_rawDesc = "" +
 	"\x11MetricDeltasEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01B\x15\n" +
-	"\x13_time_delta_seconds\"\x97\t\n" +
+	"\x13_time_delta_secondsB\x12\n" +
+	"\x10_deltas_reliable\"\x97\t\n" +
 	"\fMeasurements\x122\n" +
 	"\x12active_connections\x18\x01 \x01(\x05H\x00R\x11activeConnections\x88\x01\x01\x12.\n" +
 	"\x10idle_connections\x18\x02 \x01(\x05H\x01R\x0fidleConnections\x88\x01\x01\x12,\n" +
@@ -636,14 +949,40 @@ const file_metrics_proto_rawDesc = "" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1f\n" +
 	"\vassigned_id\x18\x03 \x01(\tR\n" +
-	"assignedId\"I\n" +
+	"assignedId\"\xe2\x01\n" +
 	"\n" +
 	"MetricsAck\x12#\n" +
 	"\rtotal_metrics\x18\x01 \x01(\x03R\ftotalMetrics\x12\x16\n" +
-	"\x06status\x18\x02 \x01(\tR\x06status2\x8e\x01\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12%\n" +
+	"\x0eacked_sequence\x18\x03 \x01(\x04R\rackedSequence\x12\x1a\n" +
+	"\baccepted\x18\x04 \x01(\x03R\baccepted\x12\x1a\n" +
+	"\brejected\x18\x05 \x01(\x03R\brejected\x128\n" +
+	"\n" +
+	"rejections\x18\x06 \x03(\v2\x18.proto.SnapshotRejectionR\n" +
+	"rejections\"h\n" +
+	"\x11SnapshotRejection\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x04R\bsequence\x12\x1f\n" +
+	"\vdatabase_id\x18\x02 \x01(\tR\n" +
+	"databaseId\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"=\n" +
+	"\x1aRegisteredDetectorsRequest\x12\x1f\n" +
+	"\vdatabase_id\x18\x01 \x01(\tR\n" +
+	"databaseId\"\xce\x01\n" +
+	"\x12RegisteredDetector\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\bcategory\x18\x02 \x01(\tR\bcategory\x12I\n" +
+	"\n" +
+	"thresholds\x18\x03 \x03(\v2).proto.RegisteredDetector.ThresholdsEntryR\n" +
+	"thresholds\x1a=\n" +
+	"\x0fThresholdsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"V\n" +
+	"\x1bRegisteredDetectorsResponse\x127\n" +
+	"\tdetectors\x18\x01 \x03(\v2\x19.proto.RegisteredDetectorR\tdetectors2\xf1\x01\n" +
 	"\x0eMetricsService\x12?\n" +
-	"\x10RegisterDatabase\x12\x13.proto.DatabaseInfo\x1a\x16.proto.RegistrationAck\x12;\n" +
-	"\rStreamMetrics\x12\x15.proto.MetricSnapshot\x1a\x11.proto.MetricsAck(\x01B3Z1github.com/EricMurray-e-m-dev/StartupMonkey/protob\x06proto3"
+	"\x10RegisterDatabase\x12\x13.proto.DatabaseInfo\x1a\x16.proto.RegistrationAck\x12=\n" +
+	"\rStreamMetrics\x12\x15.proto.MetricSnapshot\x1a\x11.proto.MetricsAck(\x010\x01\x12_\n" +
+	"\x16GetRegisteredDetectors\x12!.proto.RegisteredDetectorsRequest\x1a\".proto.RegisteredDetectorsResponseB3Z1github.com/EricMurray-e-m-dev/StartupMonkey/protob\x06proto3"
 
 var (
 	file_metrics_proto_rawDescOnce sync.Once
@@ -657,31 +996,41 @@ func file_metrics_proto_rawDescGZIP() []byte {
 	return file_metrics_proto_rawDescData
 }
 
-var file_metrics_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_metrics_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_metrics_proto_goTypes = []any{
-	(*DatabaseInfo)(nil),    // 0: proto.DatabaseInfo
-	(*MetricSnapshot)(nil),  // 1: proto.MetricSnapshot
-	(*Measurements)(nil),    // 2: proto.Measurements
-	(*RegistrationAck)(nil), // 3: proto.RegistrationAck
-	(*MetricsAck)(nil),      // 4: proto.MetricsAck
-	nil,                     // 5: proto.MetricSnapshot.ExtendedMetricsEntry
-	nil,                     // 6: proto.MetricSnapshot.LabelsEntry
-	nil,                     // 7: proto.MetricSnapshot.MetricDeltasEntry
+	(*DatabaseInfo)(nil),                // 0: proto.DatabaseInfo
+	(*MetricSnapshot)(nil),              // 1: proto.MetricSnapshot
+	(*Measurements)(nil),                // 2: proto.Measurements
+	(*RegistrationAck)(nil),             // 3: proto.RegistrationAck
+	(*MetricsAck)(nil),                  // 4: proto.MetricsAck
+	(*SnapshotRejection)(nil),           // 5: proto.SnapshotRejection
+	(*RegisteredDetectorsRequest)(nil),  // 6: proto.RegisteredDetectorsRequest
+	(*RegisteredDetector)(nil),          // 7: proto.RegisteredDetector
+	(*RegisteredDetectorsResponse)(nil), // 8: proto.RegisteredDetectorsResponse
+	nil,                                 // 9: proto.MetricSnapshot.ExtendedMetricsEntry
+	nil,                                 // 10: proto.MetricSnapshot.LabelsEntry
+	nil,                                 // 11: proto.MetricSnapshot.MetricDeltasEntry
+	nil,                                 // 12: proto.RegisteredDetector.ThresholdsEntry
 }
 var file_metrics_proto_depIdxs = []int32{
-	2, // 0: proto.MetricSnapshot.measurements:type_name -> proto.Measurements
-	5, // 1: proto.MetricSnapshot.extended_metrics:type_name -> proto.MetricSnapshot.ExtendedMetricsEntry
-	6, // 2: proto.MetricSnapshot.labels:type_name -> proto.MetricSnapshot.LabelsEntry
-	7, // 3: proto.MetricSnapshot.metric_deltas:type_name -> proto.MetricSnapshot.MetricDeltasEntry
-	0, // 4: proto.MetricsService.RegisterDatabase:input_type -> proto.DatabaseInfo
-	1, // 5: proto.MetricsService.StreamMetrics:input_type -> proto.MetricSnapshot
-	3, // 6: proto.MetricsService.RegisterDatabase:output_type -> proto.RegistrationAck
-	4, // 7: proto.MetricsService.StreamMetrics:output_type -> proto.MetricsAck
-	6, // [6:8] is the sub-list for method output_type
-	4, // [4:6] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	2,  // 0: proto.MetricSnapshot.measurements:type_name -> proto.Measurements
+	9,  // 1: proto.MetricSnapshot.extended_metrics:type_name -> proto.MetricSnapshot.ExtendedMetricsEntry
+	10, // 2: proto.MetricSnapshot.labels:type_name -> proto.MetricSnapshot.LabelsEntry
+	11, // 3: proto.MetricSnapshot.metric_deltas:type_name -> proto.MetricSnapshot.MetricDeltasEntry
+	5,  // 4: proto.MetricsAck.rejections:type_name -> proto.SnapshotRejection
+	12, // 5: proto.RegisteredDetector.thresholds:type_name -> proto.RegisteredDetector.ThresholdsEntry
+	7,  // 6: proto.RegisteredDetectorsResponse.detectors:type_name -> proto.RegisteredDetector
+	0,  // 7: proto.MetricsService.RegisterDatabase:input_type -> proto.DatabaseInfo
+	1,  // 8: proto.MetricsService.StreamMetrics:input_type -> proto.MetricSnapshot
+	6,  // 9: proto.MetricsService.GetRegisteredDetectors:input_type -> proto.RegisteredDetectorsRequest
+	3,  // 10: proto.MetricsService.RegisterDatabase:output_type -> proto.RegistrationAck
+	4,  // 11: proto.MetricsService.StreamMetrics:output_type -> proto.MetricsAck
+	8,  // 12: proto.MetricsService.GetRegisteredDetectors:output_type -> proto.RegisteredDetectorsResponse
+	10, // [10:13] is the sub-list for method output_type
+	7,  // [7:10] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_metrics_proto_init() }
@@ -697,7 +1046,7 @@ func file_metrics_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_metrics_proto_rawDesc), len(file_metrics_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,8 +19,9 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	MetricsService_RegisterDatabase_FullMethodName = "/proto.MetricsService/RegisterDatabase"
-	MetricsService_StreamMetrics_FullMethodName    = "/proto.MetricsService/StreamMetrics"
+	MetricsService_RegisterDatabase_FullMethodName       = "/proto.MetricsService/RegisterDatabase"
+	MetricsService_StreamMetrics_FullMethodName          = "/proto.MetricsService/StreamMetrics"
+	MetricsService_GetRegisteredDetectors_FullMethodName = "/proto.MetricsService/GetRegisteredDetectors"
 )
 
 // MetricsServiceClient is the client API for MetricsService service.
@@ -31,8 +32,11 @@ const (
 type MetricsServiceClient interface {
 	// Collector registers database when it starts
 	RegisterDatabase(ctx context.Context, in *DatabaseInfo, opts ...grpc.CallOption) (*RegistrationAck, error)
-	// Collector streams metrics continuously
-	StreamMetrics(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[MetricSnapshot, MetricsAck], error)
+	// Collector holds one stream open and sends snapshots as they are produced; the
+	// Analyser acknowledges periodically with the highest sequence it has processed
+	StreamMetrics(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[MetricSnapshot, MetricsAck], error)
+	// Lists the detectors this Analyser runs, with the thresholds in effect for them
+	GetRegisteredDetectors(ctx context.Context, in *RegisteredDetectorsRequest, opts ...grpc.CallOption) (*RegisteredDetectorsResponse, error)
 }
 
 type metricsServiceClient struct {
@@ -53,7 +57,7 @@ func (c *metricsServiceClient) RegisterDatabase(ctx context.Context, in *Databas
 	return out, nil
 }
 
-func (c *metricsServiceClient) StreamMetrics(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[MetricSnapshot, MetricsAck], error) {
+func (c *metricsServiceClient) StreamMetrics(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[MetricSnapshot, MetricsAck], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &MetricsService_ServiceDesc.Streams[0], MetricsService_StreamMetrics_FullMethodName, cOpts...)
 	if err != nil {
@@ -64,7 +68,17 @@ func (c *metricsServiceClient) StreamMetrics(ctx context.Context, opts ...grpc.C
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type MetricsService_StreamMetricsClient = grpc.ClientStreamingClient[MetricSnapshot, MetricsAck]
+type MetricsService_StreamMetricsClient = grpc.BidiStreamingClient[MetricSnapshot, MetricsAck]
+
+func (c *metricsServiceClient) GetRegisteredDetectors(ctx context.Context, in *RegisteredDetectorsRequest, opts ...grpc.CallOption) (*RegisteredDetectorsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisteredDetectorsResponse)
+	err := c.cc.Invoke(ctx, MetricsService_GetRegisteredDetectors_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
 
 // MetricsServiceServer is the server API for MetricsService service.
 // All implementations must embed UnimplementedMetricsServiceServer
@@ -74,8 +88,11 @@ type MetricsService_StreamMetricsClient = grpc.ClientStreamingClient[MetricSnaps
 type MetricsServiceServer interface {
 	// Collector registers database when it starts
 	RegisterDatabase(context.Context, *DatabaseInfo) (*RegistrationAck, error)
-	// Collector streams metrics continuously
-	StreamMetrics(grpc.ClientStreamingServer[MetricSnapshot, MetricsAck]) error
+	// Collector holds one stream open and sends snapshots as they are produced; the
+	// Analyser acknowledges periodically with the highest sequence it has processed
+	StreamMetrics(grpc.BidiStreamingServer[MetricSnapshot, MetricsAck]) error
+	// Lists the detectors this Analyser runs, with the thresholds in effect for them
+	GetRegisteredDetectors(context.Context, *RegisteredDetectorsRequest) (*RegisteredDetectorsResponse, error)
 	mustEmbedUnimplementedMetricsServiceServer()
 }
 
@@ -89,9 +106,12 @@ type UnimplementedMetricsServiceServer struct{}
 func (UnimplementedMetricsServiceServer) RegisterDatabase(context.Context, *DatabaseInfo) (*RegistrationAck, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RegisterDatabase not implemented")
 }
-func (UnimplementedMetricsServiceServer) StreamMetrics(grpc.ClientStreamingServer[MetricSnapshot, MetricsAck]) error {
+func (UnimplementedMetricsServiceServer) StreamMetrics(grpc.BidiStreamingServer[MetricSnapshot, MetricsAck]) error {
 	return status.Errorf(codes.Unimplemented, "method StreamMetrics not implemented")
 }
+func (UnimplementedMetricsServiceServer) GetRegisteredDetectors(context.Context, *RegisteredDetectorsRequest) (*RegisteredDetectorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRegisteredDetectors not implemented")
+}
 func (UnimplementedMetricsServiceServer) mustEmbedUnimplementedMetricsServiceServer() {}
 func (UnimplementedMetricsServiceServer) testEmbeddedByValue()                        {}
 
@@ -136,7 +156,25 @@ func _MetricsService_StreamMetrics_Handler(srv interface{}, stream grpc.ServerSt
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type MetricsService_StreamMetricsServer = grpc.ClientStreamingServer[MetricSnapshot, MetricsAck]
+type MetricsService_StreamMetricsServer = grpc.BidiStreamingServer[MetricSnapshot, MetricsAck]
+
+func _MetricsService_GetRegisteredDetectors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisteredDetectorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricsServiceServer).GetRegisteredDetectors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MetricsService_GetRegisteredDetectors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricsServiceServer).GetRegisteredDetectors(ctx, req.(*RegisteredDetectorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
 // MetricsService_ServiceDesc is the grpc.ServiceDesc for MetricsService service.
 // It's only intended for direct use with grpc.RegisterService,
@@ -149,11 +187,16 @@ var MetricsService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RegisterDatabase",
 			Handler:    _MetricsService_RegisterDatabase_Handler,
 		},
+		{
+			MethodName: "GetRegisteredDetectors",
+			Handler:    _MetricsService_GetRegisteredDetectors_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "StreamMetrics",
 			Handler:       _MetricsService_StreamMetrics_Handler,
+			ServerStreams: true,
 			ClientStreams: true,
 		},
 	},
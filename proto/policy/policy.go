@@ -0,0 +1,143 @@
+// Package policy evaluates the per-database policies held by Knowledge: which action types
+// the Executor may run against a database and which detectors the Analyser ignores for it.
+// Both services read policies through a Cache, so a change made from the Dashboard applies
+// within one TTL without restarting either of them.
+package policy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+)
+
+// DefaultCacheTTL is how long a policy read from Knowledge is used before it is read again.
+const DefaultCacheTTL = 15 * time.Second
+
+// Rules recorded on refused actions, saying which list refused them.
+const (
+	RuleDenied     = "database_policy:denied_action_types"
+	RuleNotAllowed = "database_policy:allowed_action_types"
+)
+
+// ActionAllowed reports whether a policy lets an action type run, and if not, the rule that
+// refused it. A nil policy allows everything; denied types win over allowed ones.
+func ActionAllowed(p *pb.DatabasePolicy, actionType string) (bool, string) {
+	if p == nil {
+		return true, ""
+	}
+	if slices.Contains(p.DeniedActionTypes, actionType) {
+		return false, RuleDenied
+	}
+	if len(p.AllowedActionTypes) > 0 && !slices.Contains(p.AllowedActionTypes, actionType) {
+		return false, RuleNotAllowed
+	}
+	return true, ""
+}
+
+// DetectorIgnored reports whether a policy ignores a detector.
+func DetectorIgnored(p *pb.DatabasePolicy, detectorName string) bool {
+	return p != nil && slices.Contains(p.IgnoredDetectors, detectorName)
+}
+
+// IsEmpty reports whether a policy restricts nothing.
+func IsEmpty(p *pb.DatabasePolicy) bool {
+	return p == nil || len(p.AllowedActionTypes) == 0 && len(p.DeniedActionTypes) == 0 && len(p.IgnoredDetectors) == 0
+}
+
+// Normalise trims, deduplicates and sorts a policy's lists in place, and rejects a policy
+// without a database or one that both allows and denies an action type.
+func Normalise(p *pb.DatabasePolicy) error {
+	if p == nil || strings.TrimSpace(p.DatabaseId) == "" {
+		return fmt.Errorf("database_id is required")
+	}
+	p.DatabaseId = strings.TrimSpace(p.DatabaseId)
+	p.AllowedActionTypes = normaliseList(p.AllowedActionTypes)
+	p.DeniedActionTypes = normaliseList(p.DeniedActionTypes)
+	p.IgnoredDetectors = normaliseList(p.IgnoredDetectors)
+
+	for _, actionType := range p.DeniedActionTypes {
+		if slices.Contains(p.AllowedActionTypes, actionType) {
+			return fmt.Errorf("action type %q is both allowed and denied", actionType)
+		}
+	}
+	return nil
+}
+
+func normaliseList(values []string) []string {
+	list := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			list = append(list, value)
+		}
+	}
+	slices.Sort(list)
+	return slices.Compact(list)
+}
+
+// Fetcher reads a database's policy from Knowledge. A database without a policy may return nil.
+type Fetcher func(ctx context.Context, databaseID string) (*pb.DatabasePolicy, error)
+
+// Cache holds policies read through a Fetcher for up to a TTL each.
+type Cache struct {
+	fetch Fetcher
+	ttl   time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	policy    *pb.DatabasePolicy
+	fetchedAt time.Time
+}
+
+// NewCache creates a cache; a ttl of zero or less uses DefaultCacheTTL.
+func NewCache(fetch Fetcher, ttl time.Duration) *Cache {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &Cache{
+		fetch:   fetch,
+		ttl:     ttl,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// Get returns a database's policy, reading it again once the cached copy is older than the
+// TTL. If Knowledge can't be read, the last policy read is returned with the error, so a
+// denial keeps holding through an outage; it is nil if the policy was never read.
+func (c *Cache) Get(ctx context.Context, databaseID string) (*pb.DatabasePolicy, error) {
+	c.mu.Lock()
+	entry, cached := c.entries[databaseID]
+	c.mu.Unlock()
+
+	if cached && time.Since(entry.fetchedAt) < c.ttl {
+		return entry.policy, nil
+	}
+
+	p, err := c.fetch(ctx, databaseID)
+	if err != nil {
+		return entry.policy, err
+	}
+	if IsEmpty(p) {
+		p = nil
+	}
+
+	c.mu.Lock()
+	c.entries[databaseID] = cacheEntry{policy: p, fetchedAt: time.Now()}
+	c.mu.Unlock()
+
+	return p, nil
+}
+
+// Invalidate drops a database's cached policy so the next Get reads it from Knowledge.
+func (c *Cache) Invalidate(databaseID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, databaseID)
+}
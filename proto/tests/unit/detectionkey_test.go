@@ -0,0 +1,104 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/detectionkey"
+)
+
+func TestIdentifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata map[string]interface{}
+		evidence map[string]interface{}
+		want     string
+	}{
+		{
+			name:     "index name wins",
+			metadata: map[string]interface{}{"index_name": "idx_orders_user_id", "table_name": "orders"},
+			want:     "idx_orders_user_id",
+		},
+		{
+			name:     "table with columns",
+			metadata: map[string]interface{}{"table_name": "orders", "column_names": []string{"user_id", "created_at"}},
+			want:     "orders.user_id,created_at",
+		},
+		{
+			name:     "table with columns decoded from JSON",
+			metadata: map[string]interface{}{"table_name": "orders", "column_names": []interface{}{"user_id", "created_at"}},
+			want:     "orders.user_id,created_at",
+		},
+		{
+			name:     "table with one column",
+			metadata: map[string]interface{}{"table_name": "orders", "column_name": "user_id"},
+			want:     "orders.user_id",
+		},
+		{
+			name:     "table alone",
+			metadata: map[string]interface{}{"table_name": "orders"},
+			want:     "orders",
+		},
+		{
+			name:     "metadata identifier",
+			metadata: map[string]interface{}{"identifier": "max_connections"},
+			want:     "max_connections",
+		},
+		{
+			name:     "evidence identifier",
+			evidence: map[string]interface{}{"identifier": "pid:4242"},
+			want:     "pid:4242",
+		},
+		{
+			name:     "query hash",
+			evidence: map[string]interface{}{"query_hash": "a1b2c3"},
+			want:     "a1b2c3",
+		},
+		{
+			name: "falls back to category",
+			want: "cache",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectionkey.Identifier(tt.metadata, tt.evidence, "cache"); got != tt.want {
+				t.Errorf("Expected identifier %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBuildAndParseRoundTrip(t *testing.T) {
+	key := detectionkey.Build("prod-db", "lock_contention", "pid:4242")
+	if key != "prod-db:lock_contention:pid:4242" {
+		t.Fatalf("Unexpected key %q", key)
+	}
+
+	databaseID, detector, identifier, err := detectionkey.Parse(key)
+	if err != nil {
+		t.Fatalf("Failed to parse key: %v", err)
+	}
+	if databaseID != "prod-db" || detector != "lock_contention" || identifier != "pid:4242" {
+		t.Errorf("Expected prod-db/lock_contention/pid:4242, got %s/%s/%s", databaseID, detector, identifier)
+	}
+}
+
+func TestParseRejectsMalformedKeys(t *testing.T) {
+	for _, key := range []string{"", "prod-db", "prod-db:missing_index", "prod-db::orders", ":missing_index:orders", "prod-db:missing_index:"} {
+		if _, _, _, err := detectionkey.Parse(key); err == nil {
+			t.Errorf("Expected %q to be rejected", key)
+		}
+	}
+}
+
+func TestSameIssue(t *testing.T) {
+	if !detectionkey.SameIssue("db:missing_index:orders.user_id", "db:missing_index:orders.user_id") {
+		t.Errorf("Expected identical keys to match")
+	}
+	if detectionkey.SameIssue("db:missing_index:orders.user_id", "db:missing_index:orders.status") {
+		t.Errorf("Expected different identifiers not to match")
+	}
+	if detectionkey.SameIssue("", "") {
+		t.Errorf("Expected empty keys never to match")
+	}
+}
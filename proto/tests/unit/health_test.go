@@ -0,0 +1,208 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/health"
+)
+
+// startHealthServer starts s on a free port and shuts it down when the test ends.
+func startHealthServer(t *testing.T, s *health.Server) string {
+	t.Helper()
+
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		s.Shutdown(ctx)
+	})
+
+	return "http://" + s.Addr()
+}
+
+func getHealth(t *testing.T, baseURL string, body interface{}) int {
+	t.Helper()
+
+	resp, err := http.Get(baseURL + "/health")
+	if err != nil {
+		t.Fatalf("GET /health: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(body); err != nil {
+		t.Fatalf("decode /health: %v", err)
+	}
+	return resp.StatusCode
+}
+
+func upCheck(ctx context.Context) error   { return nil }
+func downCheck(ctx context.Context) error { return errors.New("connection refused") }
+
+func TestHealthServer_AllDependenciesUp(t *testing.T) {
+	s := health.NewServer("executor", "0", nil)
+	s.AddCheck("nats", true, upCheck)
+	s.AddCheck("docker", false, upCheck)
+
+	var body health.HealthResponse
+	code := getHealth(t, startHealthServer(t, s), &body)
+
+	if code != http.StatusOK || body.Status != "healthy" || body.Service != "executor" {
+		t.Errorf("Expected 200 healthy executor, got %d %s %s", code, body.Status, body.Service)
+	}
+	if nats := body.Dependencies["nats"]; nats.Status != "up" || !nats.Critical {
+		t.Errorf("Expected critical nats up, got %+v", nats)
+	}
+	if docker := body.Dependencies["docker"]; docker.Status != "up" || docker.Critical {
+		t.Errorf("Expected optional docker up, got %+v", docker)
+	}
+}
+
+func TestHealthServer_OptionalDependencyDownIsDegraded(t *testing.T) {
+	s := health.NewServer("executor", "0", nil)
+	s.AddCheck("nats", true, upCheck)
+	s.AddCheck("docker", false, downCheck)
+
+	var body health.HealthResponse
+	code := getHealth(t, startHealthServer(t, s), &body)
+
+	if code != http.StatusOK || body.Status != "degraded" {
+		t.Errorf("Expected 200 degraded, got %d %s", code, body.Status)
+	}
+	if docker := body.Dependencies["docker"]; docker.Status != "down" || docker.Error != "connection refused" {
+		t.Errorf("Expected docker down with its error, got %+v", docker)
+	}
+}
+
+func TestHealthServer_CriticalDependencyDownIsUnhealthy(t *testing.T) {
+	s := health.NewServer("executor", "0", nil)
+	s.AddCheck("nats", true, downCheck)
+	s.AddCheck("docker", false, downCheck)
+
+	var body health.HealthResponse
+	code := getHealth(t, startHealthServer(t, s), &body)
+
+	if code != http.StatusServiceUnavailable || body.Status != "unhealthy" {
+		t.Errorf("Expected 503 unhealthy, got %d %s", code, body.Status)
+	}
+	if body.Dependencies["nats"].Status != "down" {
+		t.Errorf("Expected nats down, got %+v", body.Dependencies["nats"])
+	}
+}
+
+func TestHealthServer_HungCheckTimesOut(t *testing.T) {
+	s := health.NewServer("executor", "0", nil)
+	s.AddCheck("knowledge", false, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	start := time.Now()
+	body := s.Check(context.Background())
+
+	if elapsed := time.Since(start); elapsed >= 5*time.Second {
+		t.Errorf("Expected the check to time out, took %s", elapsed)
+	}
+	if body.Status != "degraded" || body.Dependencies["knowledge"].Status != "down" {
+		t.Errorf("Expected degraded with knowledge down, got %s %+v", body.Status, body.Dependencies["knowledge"])
+	}
+}
+
+func TestHealthServer_ServesInjectedMetrics(t *testing.T) {
+	metrics := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "startupmonkey_up 1\n")
+	})
+
+	withMetrics := startHealthServer(t, health.NewServer("analyser", "0", metrics))
+	resp, err := http.Get(withMetrics + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "startupmonkey_up 1\n" {
+		t.Errorf("Expected the injected metrics handler, got %d %q", resp.StatusCode, body)
+	}
+
+	withoutMetrics := startHealthServer(t, health.NewServer("knowledge", "0", nil))
+	resp, err = http.Get(withoutMetrics + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected /metrics unserved without a handler, got %d", resp.StatusCode)
+	}
+}
+
+func TestHealthServer_ExtensionAddsFieldsAndStatus(t *testing.T) {
+	type extended struct {
+		health.HealthResponse
+		Buffered int `json:"buffered"`
+	}
+
+	s := health.NewServer("collector", "0", nil)
+	s.AddCheck("knowledge", true, upCheck)
+	s.SetExtension(func(response *health.HealthResponse) interface{} {
+		response.Status = "degraded"
+		return extended{HealthResponse: *response, Buffered: 3}
+	})
+
+	var body extended
+	code := getHealth(t, startHealthServer(t, s), &body)
+
+	if code != http.StatusOK || body.Status != "degraded" || body.Buffered != 3 {
+		t.Errorf("Expected 200 degraded with buffered=3, got %d %s %d", code, body.Status, body.Buffered)
+	}
+	if body.Dependencies["knowledge"].Status != "up" {
+		t.Errorf("Expected the standard fields kept, got %+v", body.Dependencies)
+	}
+}
+
+func TestHealthServer_StartFailsWhenPortInUse(t *testing.T) {
+	taken, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer taken.Close()
+
+	_, port, err := net.SplitHostPort(taken.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := health.NewServer("executor", port, nil).Start(); err == nil {
+		t.Error("Expected Start to fail on a port in use")
+	}
+}
+
+func TestHealthServer_ShutdownStopsServing(t *testing.T) {
+	s := health.NewServer("executor", "0", nil)
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	baseURL := "http://" + s.Addr()
+
+	var body health.HealthResponse
+	if code := getHealth(t, baseURL, &body); code != http.StatusOK {
+		t.Fatalf("Expected 200 before shutdown, got %d", code)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	if _, err := http.Get(baseURL + "/health"); err == nil {
+		t.Error("Expected requests to fail after shutdown")
+	}
+}
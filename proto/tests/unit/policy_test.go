@@ -0,0 +1,121 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/proto/policy"
+)
+
+func TestActionAllowed(t *testing.T) {
+	billing := &pb.DatabasePolicy{
+		DatabaseId:         "billing",
+		AllowedActionTypes: []string{"create_index", "vacuum_table"},
+		DeniedActionTypes:  []string{"terminate_query"},
+	}
+
+	tests := []struct {
+		name       string
+		policy     *pb.DatabasePolicy
+		actionType string
+		allowed    bool
+		rule       string
+	}{
+		{"no policy allows everything", nil, "terminate_query", true, ""},
+		{"denied type", billing, "terminate_query", false, policy.RuleDenied},
+		{"type on the allow list", billing, "create_index", true, ""},
+		{"type missing from the allow list", billing, "drop_index", false, policy.RuleNotAllowed},
+		{"deny list only", &pb.DatabasePolicy{DeniedActionTypes: []string{"drop_index"}}, "create_index", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, rule := policy.ActionAllowed(tt.policy, tt.actionType)
+			if allowed != tt.allowed || rule != tt.rule {
+				t.Errorf("ActionAllowed(%q) = %v, %q; want %v, %q", tt.actionType, allowed, rule, tt.allowed, tt.rule)
+			}
+		})
+	}
+}
+
+func TestDetectorIgnored(t *testing.T) {
+	p := &pb.DatabasePolicy{IgnoredDetectors: []string{"missing_index"}}
+	if !policy.DetectorIgnored(p, "missing_index") {
+		t.Errorf("Expected missing_index to be ignored")
+	}
+	if policy.DetectorIgnored(p, "deadlock") || policy.DetectorIgnored(nil, "missing_index") {
+		t.Errorf("Only listed detectors should be ignored")
+	}
+}
+
+func TestNormalise(t *testing.T) {
+	p := &pb.DatabasePolicy{
+		DatabaseId:        " billing ",
+		DeniedActionTypes: []string{"terminate_query", " drop_index", "", "terminate_query"},
+	}
+	if err := policy.Normalise(p); err != nil {
+		t.Fatalf("Normalise: %v", err)
+	}
+	if p.DatabaseId != "billing" {
+		t.Errorf("Expected trimmed database ID, got %q", p.DatabaseId)
+	}
+	if len(p.DeniedActionTypes) != 2 || p.DeniedActionTypes[0] != "drop_index" || p.DeniedActionTypes[1] != "terminate_query" {
+		t.Errorf("Expected sorted, deduplicated denied types, got %v", p.DeniedActionTypes)
+	}
+
+	if err := policy.Normalise(&pb.DatabasePolicy{}); err == nil {
+		t.Errorf("Expected a policy without a database to be rejected")
+	}
+
+	overlap := &pb.DatabasePolicy{
+		DatabaseId:         "billing",
+		AllowedActionTypes: []string{"create_index"},
+		DeniedActionTypes:  []string{"create_index"},
+	}
+	if err := policy.Normalise(overlap); err == nil {
+		t.Errorf("Expected an action type both allowed and denied to be rejected")
+	}
+}
+
+func TestCache(t *testing.T) {
+	fetches := 0
+	var fetchErr error
+	current := &pb.DatabasePolicy{DatabaseId: "billing", DeniedActionTypes: []string{"terminate_query"}}
+
+	cache := policy.NewCache(func(ctx context.Context, databaseID string) (*pb.DatabasePolicy, error) {
+		fetches++
+		if fetchErr != nil {
+			return nil, fetchErr
+		}
+		return current, nil
+	}, 50*time.Millisecond)
+
+	ctx := context.Background()
+	for range 3 {
+		if p, err := cache.Get(ctx, "billing"); err != nil || p != current {
+			t.Fatalf("Get = %v, %v", p, err)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("Expected one fetch within the TTL, got %d", fetches)
+	}
+
+	// Once expired, a failed read keeps the last policy in force
+	time.Sleep(60 * time.Millisecond)
+	fetchErr = errors.New("knowledge unreachable")
+	p, err := cache.Get(ctx, "billing")
+	if err == nil || p != current {
+		t.Errorf("Expected the stale policy with the error, got %v, %v", p, err)
+	}
+
+	// A cleared policy reads as nil
+	fetchErr = nil
+	current = &pb.DatabasePolicy{DatabaseId: "billing"}
+	cache.Invalidate("billing")
+	if p, err := cache.Get(ctx, "billing"); err != nil || p != nil {
+		t.Errorf("Expected an empty policy to read as nil, got %v, %v", p, err)
+	}
+}
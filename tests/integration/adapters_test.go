@@ -0,0 +1,103 @@
+package integration
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/tests/integration/framework"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCollector_MySQL checks the collector registers and collects from a MySQL database.
+func TestCollector_MySQL(t *testing.T) {
+	testCollectorAgainst(t, framework.MySQLDatabase("adapter_test"), "SELECT DATABASE()", "3306")
+}
+
+// TestCollector_MongoDB checks the collector registers and collects from a MongoDB database.
+func TestCollector_MongoDB(t *testing.T) {
+	testCollectorAgainst(t, framework.MongoDBDatabase("adapter_test"), "db.getName()", "27017")
+}
+
+// TestCollector_MongoDBMetrics checks a collection against a real MongoDB reads
+// serverStatus, dbStats and currentOp without falling back to missing metrics.
+func TestCollector_MongoDBMetrics(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := framework.MongoDBDatabase("adapter_metrics_test")
+	env := framework.NewTestEnvironment(t, []string{
+		"redis",
+		"nats",
+		"knowledge",
+		"analyser",
+		"collector",
+	})
+	env.ConfigureCollector(db)
+	env.SetServiceEnv("collector", map[string]string{"LOG_LEVEL": "debug"})
+
+	err := env.Start()
+	require.NoError(t, err, "Failed to start services")
+	defer env.Cleanup()
+
+	err = env.WaitForHealthy(90 * time.Second)
+	require.NoError(t, err, "Services did not become healthy")
+
+	err = env.WaitForMetricsInLogs("collector", "Collection cycle complete", 90*time.Second)
+	require.NoError(t, err, "Collector did not complete a collection from MongoDB")
+
+	logs, err := env.GetLogs("collector")
+	require.NoError(t, err)
+	for _, warning := range []string{
+		"failed to get server status",
+		"failed to get database stats",
+		"failed to get current operations",
+	} {
+		require.False(t, strings.Contains(logs, warning), "Collector logged %q", warning)
+	}
+}
+
+// testCollectorAgainst starts the pipeline with the collector pointed at db, then checks
+// the database answers directly and the collector registers it with Knowledge.
+func testCollectorAgainst(t *testing.T, db framework.Database, query, containerPort string) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	env := framework.NewTestEnvironment(t, []string{
+		"redis",
+		"nats",
+		"knowledge",
+		"analyser",
+		"collector",
+	})
+	env.ConfigureCollector(db)
+
+	err := env.Start()
+	require.NoError(t, err, "Failed to start services")
+	defer env.Cleanup()
+
+	err = env.WaitForHealthy(90 * time.Second)
+	require.NoError(t, err, "Services did not become healthy")
+
+	err = env.WaitForServiceHealthy(db.Service, 90*time.Second)
+	require.NoError(t, err, "%s did not become healthy", db.Service)
+
+	port, err := env.GetPublishedPort(db.Service, containerPort)
+	require.NoError(t, err, "Failed to determine %s published port", db.Service)
+	require.NotEmpty(t, port)
+
+	var output string
+	switch db.Adapter {
+	case "mysql":
+		output, err = env.QueryMySQL(db, query)
+	case "mongodb":
+		output, err = env.QueryMongoDB(db, query)
+	}
+	require.NoError(t, err, "Failed to query %s", db.Service)
+	require.Equal(t, db.Name, output, "Test database should be created for the test")
+
+	err = env.WaitForMetricsInLogs("collector", "Database registered with Knowledge: "+db.DatabaseID, 60*time.Second)
+	require.NoError(t, err, "Collector did not register the %s database", db.Adapter)
+}
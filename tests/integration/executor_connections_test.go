@@ -0,0 +1,94 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/tests/integration/framework"
+	"github.com/stretchr/testify/require"
+)
+
+// executorConnectionLimit matches the Executor's default pool size per database.
+const executorConnectionLimit = 4
+
+// TestExecutor_ConnectionsStayBoundedAcrossActions publishes many actionable detections
+// for one database and checks the Executor's connections to it never pile up - each
+// action used to open its own pool that was never closed.
+func TestExecutor_ConnectionsStayBoundedAcrossActions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	env := framework.NewTestEnvironment(t, []string{
+		"postgres",
+		"redis",
+		"nats",
+		"knowledge",
+		"analyser",
+		"collector",
+		"executor",
+	})
+
+	err := env.Start()
+	require.NoError(t, err, "Failed to start services")
+	defer env.Cleanup()
+
+	err = env.WaitForHealthy(90 * time.Second)
+	require.NoError(t, err, "Services did not become healthy")
+
+	// The Collector registers the database with Knowledge, which the Executor needs to connect
+	err = env.WaitForMetricsInLogs("collector", "Database registered with Knowledge: docker-test-db", 60*time.Second)
+	require.NoError(t, err, "Collector did not register the test database")
+
+	_, err = env.QueryPostgres("CREATE TABLE IF NOT EXISTS connection_test (id serial primary key)")
+	require.NoError(t, err, "Failed to create test table")
+
+	nc := connectToNATS(t, env)
+	defer nc.Close()
+
+	for i := 0; i < 50; i++ {
+		detection, err := json.Marshal(map[string]interface{}{
+			"id":            fmt.Sprintf("connection-test-%d-%d", time.Now().UnixNano(), i),
+			"detector_name": "table_bloat",
+			"category":      "maintenance",
+			"severity":      "warning",
+			"database_id":   "docker-test-db",
+			"timestamp":     time.Now().Unix(),
+			"title":         "Connection leak regression",
+			"action_type":   "vacuum_table",
+			"action_metadata": map[string]interface{}{
+				"table_name":    "connection_test",
+				"database_type": "postgres",
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, nc.Publish("detections", detection), "Failed to publish detection")
+	}
+	require.NoError(t, nc.Flush())
+
+	err = env.WaitForMetricsInLogs("executor", "Action created", 30*time.Second)
+	require.NoError(t, err, "Executor did not create any actions")
+
+	peak := 0
+	deadline := time.Now().Add(30 * time.Second)
+	for time.Now().Before(deadline) {
+		output, err := env.QueryPostgres(
+			"SELECT count(*) FROM pg_stat_activity WHERE application_name = 'startupmonkey-executor'")
+		require.NoError(t, err, "Failed to count Executor connections")
+
+		count, err := strconv.Atoi(output)
+		require.NoError(t, err, "Unexpected pg_stat_activity output: %q", output)
+		if count > peak {
+			peak = count
+		}
+
+		time.Sleep(2 * time.Second)
+	}
+
+	t.Logf("Peak Executor connections: %d", peak)
+	require.Greater(t, peak, 0, "Executor should have connected to the database")
+	require.LessOrEqual(t, peak, executorConnectionLimit, "Executor connections should stay bounded across actions")
+}
@@ -0,0 +1,82 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/tests/integration/framework"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExecutor_MongoDBCreateIndex publishes a missing index detection for a MongoDB database
+// and checks the Executor builds the index under MongoDB's naming convention.
+func TestExecutor_MongoDBCreateIndex(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := framework.MongoDBDatabase("executor_test")
+
+	env := framework.NewTestEnvironment(t, []string{
+		"redis",
+		"nats",
+		"knowledge",
+		"analyser",
+		"collector",
+		"executor",
+	})
+	env.ConfigureCollector(db)
+
+	err := env.Start()
+	require.NoError(t, err, "Failed to start services")
+	defer env.Cleanup()
+
+	err = env.WaitForHealthy(90 * time.Second)
+	require.NoError(t, err, "Services did not become healthy")
+
+	err = env.WaitForServiceHealthy(db.Service, 90*time.Second)
+	require.NoError(t, err, "%s did not become healthy", db.Service)
+
+	// The Collector registers the database with Knowledge, which the Executor needs to connect
+	err = env.WaitForMetricsInLogs("collector", "Database registered with Knowledge: "+db.DatabaseID, 60*time.Second)
+	require.NoError(t, err, "Collector did not register the MongoDB database")
+
+	_, err = env.QueryMongoDB(db, `db.orders.insertOne({user_id: 42, total: 10})`)
+	require.NoError(t, err, "Failed to create test collection")
+
+	nc := connectToNATS(t, env)
+	defer nc.Close()
+
+	detection, err := json.Marshal(map[string]interface{}{
+		"id":            fmt.Sprintf("mongo-index-%d", time.Now().UnixNano()),
+		"detector_name": "missing_index",
+		"category":      "query",
+		"severity":      "warning",
+		"database_id":   db.DatabaseID,
+		"timestamp":     time.Now().Unix(),
+		"title":         "Collection scan on orders",
+		"action_type":   "create_index",
+		"action_metadata": map[string]interface{}{
+			"table_name":    "orders",
+			"column_names":  []string{"user_id"},
+			"database_type": "mongodb",
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, nc.Publish("detections", detection), "Failed to publish detection")
+	require.NoError(t, nc.Flush())
+
+	var indexes string
+	deadline := time.Now().Add(60 * time.Second)
+	for time.Now().Before(deadline) {
+		indexes, err = env.QueryMongoDB(db, `db.orders.getIndexes().map(i => i.name).join(",")`)
+		if err == nil && strings.Contains(indexes, "user_id_1") {
+			break
+		}
+		time.Sleep(2 * time.Second)
+	}
+	require.Contains(t, indexes, "user_id_1", "Executor should create the index with MongoDB's naming convention")
+}
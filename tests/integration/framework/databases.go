@@ -0,0 +1,75 @@
+package framework
+
+import "fmt"
+
+// Credentials for the opt-in database services in docker-compose.yml
+const (
+	mysqlRootPassword = "mysql"
+	mongoUser         = "root"
+	mongoPassword     = "mongo"
+)
+
+// profiledServices are opt-in compose services, each behind a profile named after it
+var profiledServices = map[string]bool{
+	"mysql":   true,
+	"mongodb": true,
+}
+
+// Database describes a database service a test can point the collector at.
+type Database struct {
+	Service    string // Compose service name
+	Adapter    string // Collector DB_ADAPTER
+	Name       string // Database created for the test
+	DatabaseID string // ID the collector registers with Knowledge
+}
+
+// MySQLDatabase returns the mysql service with a database called name.
+func MySQLDatabase(name string) Database {
+	return Database{
+		Service:    "mysql",
+		Adapter:    "mysql",
+		Name:       name,
+		DatabaseID: fmt.Sprintf("mysql-%s", name),
+	}
+}
+
+// MongoDBDatabase returns the mongodb service with a database called name.
+func MongoDBDatabase(name string) Database {
+	return Database{
+		Service:    "mongodb",
+		Adapter:    "mongodb",
+		Name:       name,
+		DatabaseID: fmt.Sprintf("mongodb-%s", name),
+	}
+}
+
+// ConnectionString returns the connection string the collector uses inside the compose network.
+func (d Database) ConnectionString() string {
+	switch d.Adapter {
+	case "mysql":
+		return fmt.Sprintf("mysql://root:%s@%s:3306/%s", mysqlRootPassword, d.Service, d.Name)
+	case "mongodb":
+		return fmt.Sprintf("mongodb://%s:%s@%s:27017/%s?authSource=admin", mongoUser, mongoPassword, d.Service, d.Name)
+	default:
+		return ""
+	}
+}
+
+// serviceEnv creates the test's database when the service container initialises.
+func (d Database) serviceEnv() map[string]string {
+	switch d.Adapter {
+	case "mysql":
+		return map[string]string{
+			"MYSQL_ROOT_PASSWORD": mysqlRootPassword,
+			"MYSQL_DATABASE":      d.Name,
+		}
+	case "mongodb":
+		return map[string]string{
+			"MONGO_INITDB_ROOT_USERNAME": mongoUser,
+			"MONGO_INITDB_ROOT_PASSWORD": mongoPassword,
+			"MONGO_INITDB_DATABASE":      d.Name,
+		}
+	default:
+		return nil
+	}
+}
@@ -1,8 +1,11 @@
 package framework
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 )
@@ -11,22 +14,20 @@ import (
 func (e *TestEnvironment) Start() error {
 	e.t.Logf("Starting Docker Services: %v", e.Services)
 
+	if err := e.writeOverrideFile(); err != nil {
+		return err
+	}
+
 	// Build images first
-	buildCmd := exec.Command("docker", "compose",
-		"-f", e.ComposeFile,
-		"-p", e.ProjectName,
-		"build",
-	)
+	buildCmd := e.composeCommand("build")
 
 	if output, err := buildCmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("docker-compose build failed: %w\n%s", err, output)
 	}
 
 	// Start services
-	args := []string{"compose", "-f", e.ComposeFile, "-p", e.ProjectName, "up", "-d"}
-	args = append(args, e.Services...)
-
-	cmd := exec.Command("docker", args...)
+	args := append([]string{"up", "-d"}, e.Services...)
+	cmd := e.composeCommand(args...)
 
 	if output, err := cmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("docker-compose up failed: %w\n%s", err, output)
@@ -44,9 +45,7 @@ func (e *TestEnvironment) WaitForHealthy(timeout time.Duration) error {
 
 	for time.Now().Before(deadline) {
 		// Check if all services are running
-		cmd := exec.Command("docker", "compose",
-			"-f", e.ComposeFile,
-			"-p", e.ProjectName,
+		cmd := e.composeCommand(
 			"ps", "--services", "--filter", "status=running",
 		)
 
@@ -84,11 +83,62 @@ func (e *TestEnvironment) WaitForHealthy(timeout time.Duration) error {
 	return fmt.Errorf("services did not become healthy within timeout")
 }
 
+// WaitForServiceHealthy waits for a service's compose healthcheck to report healthy.
+// WaitForHealthy only checks that containers are running, which databases reach well
+// before they accept connections.
+func (e *TestEnvironment) WaitForServiceHealthy(serviceName string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		cmd := e.composeCommand(
+			"ps", "--format", "{{.Health}}", serviceName,
+		)
+
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("failed to check %s health: %w\n%s", serviceName, err, output)
+		}
+
+		if strings.TrimSpace(string(output)) == "healthy" {
+			e.t.Logf("Service %s healthy", serviceName)
+			return nil
+		}
+
+		time.Sleep(2 * time.Second)
+	}
+
+	return fmt.Errorf("%s did not become healthy within timeout", serviceName)
+}
+
+// StopService stops a single service, leaving the rest of the environment running
+func (e *TestEnvironment) StopService(serviceName string) error {
+	cmd := e.composeCommand(
+		"stop", serviceName,
+	)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to stop %s: %w\n%s", serviceName, err, output)
+	}
+
+	return nil
+}
+
+// StartService starts a previously stopped service
+func (e *TestEnvironment) StartService(serviceName string) error {
+	cmd := e.composeCommand(
+		"start", serviceName,
+	)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to start %s: %w\n%s", serviceName, err, output)
+	}
+
+	return nil
+}
+
 // GetLogs retrieves logs from a specific service
 func (e *TestEnvironment) GetLogs(serviceName string) (string, error) {
-	cmd := exec.Command("docker", "compose",
-		"-f", e.ComposeFile,
-		"-p", e.ProjectName,
+	cmd := e.composeCommand(
 		"logs", serviceName,
 	)
 
@@ -100,13 +150,61 @@ func (e *TestEnvironment) GetLogs(serviceName string) (string, error) {
 	return string(output), nil
 }
 
+// QueryPostgres runs a query with psql inside the postgres service and returns the
+// unaligned, tuples-only output
+func (e *TestEnvironment) QueryPostgres(query string) (string, error) {
+	cmd := e.composeCommand(
+		"exec", "-T", "postgres",
+		"psql", "-U", "postgres", "-d", "testdb", "-tAc", query,
+	)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to query postgres: %w\n%s", err, output)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// QueryMySQL runs a query with the mysql client inside the mysql service against db and
+// returns the tab-separated output without column names
+func (e *TestEnvironment) QueryMySQL(db Database, query string) (string, error) {
+	cmd := e.composeCommand(
+		"exec", "-T", db.Service,
+		"mysql", "-u", "root", "-p"+mysqlRootPassword, "-N", "-B", "-e", query, db.Name,
+	)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to query mysql: %w\n%s", err, output)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// QueryMongoDB evaluates a script with mongosh inside the mongodb service against db and
+// returns its output
+func (e *TestEnvironment) QueryMongoDB(db Database, script string) (string, error) {
+	cmd := e.composeCommand(
+		"exec", "-T", db.Service,
+		"mongosh", "--quiet",
+		"-u", mongoUser, "-p", mongoPassword, "--authenticationDatabase", "admin",
+		db.Name, "--eval", script,
+	)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to query mongodb: %w\n%s", err, output)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
 // Cleanup stops and removes all containers
 func (e *TestEnvironment) Cleanup() {
 	e.t.Log("Cleaning up docker services...")
 
-	cmd := exec.Command("docker", "compose",
-		"-f", e.ComposeFile,
-		"-p", e.ProjectName,
+	cmd := e.composeCommand(
 		"down", "-v",
 	)
 
@@ -117,3 +215,63 @@ func (e *TestEnvironment) Cleanup() {
 	duration := time.Since(e.StartTime)
 	e.t.Logf("Test completed in: %v", duration)
 }
+
+// composeCommand builds a docker compose command scoped to this environment's project,
+// including the per-test override file and the profiles of any opt-in services
+func (e *TestEnvironment) composeCommand(args ...string) *exec.Cmd {
+	composeArgs := []string{"compose", "-f", e.ComposeFile}
+	if e.OverrideFile != "" {
+		composeArgs = append(composeArgs, "-f", e.OverrideFile)
+	}
+	composeArgs = append(composeArgs, "-p", e.ProjectName)
+
+	// Opt-in services sit behind a profile of the same name
+	for _, service := range e.Services {
+		if profiledServices[service] {
+			composeArgs = append(composeArgs, "--profile", service)
+		}
+	}
+
+	return exec.Command("docker", append(composeArgs, args...)...)
+}
+
+// writeOverrideFile writes the per-test service overrides as a compose file. JSON is
+// valid YAML, so compose merges it over ComposeFile like any override.
+func (e *TestEnvironment) writeOverrideFile() error {
+	if len(e.serviceEnv) == 0 && len(e.dependsOn) == 0 {
+		return nil
+	}
+
+	services := make(map[string]map[string]interface{})
+	service := func(name string) map[string]interface{} {
+		if services[name] == nil {
+			services[name] = make(map[string]interface{})
+		}
+		return services[name]
+	}
+
+	for name, env := range e.serviceEnv {
+		service(name)["environment"] = env
+	}
+
+	for name, dependencies := range e.dependsOn {
+		conditions := make(map[string]map[string]string, len(dependencies))
+		for _, dependency := range dependencies {
+			conditions[dependency] = map[string]string{"condition": "service_healthy"}
+		}
+		service(name)["depends_on"] = conditions
+	}
+
+	data, err := json.MarshalIndent(map[string]interface{}{"services": services}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode compose override: %w", err)
+	}
+
+	path := filepath.Join(e.t.TempDir(), "docker-compose.override.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write compose override: %w", err)
+	}
+
+	e.OverrideFile = path
+	return nil
+}
@@ -12,6 +12,11 @@ type TestEnvironment struct {
 	ProjectName string
 	Services    []string
 	StartTime   time.Time
+
+	// Per-test compose overrides, written to OverrideFile on Start
+	serviceEnv   map[string]map[string]string
+	dependsOn    map[string][]string
+	OverrideFile string
 }
 
 func NewTestEnvironment(t *testing.T, services []string) *TestEnvironment {
@@ -24,5 +29,39 @@ func NewTestEnvironment(t *testing.T, services []string) *TestEnvironment {
 		ProjectName: projectName,
 		Services:    services,
 		StartTime:   time.Now(),
+		serviceEnv:  make(map[string]map[string]string),
+		dependsOn:   make(map[string][]string),
+	}
+}
+
+// SetServiceEnv overrides environment variables for a service in this test only.
+// Must be called before Start.
+func (e *TestEnvironment) SetServiceEnv(service string, env map[string]string) {
+	if e.serviceEnv[service] == nil {
+		e.serviceEnv[service] = make(map[string]string)
+	}
+	for key, value := range env {
+		e.serviceEnv[service][key] = value
+	}
+}
+
+// ConfigureCollector points the collector at db instead of the default Postgres database,
+// starting db alongside it and holding the collector back until db is healthy.
+// Must be called before Start.
+func (e *TestEnvironment) ConfigureCollector(db Database) {
+	e.SetServiceEnv(db.Service, db.serviceEnv())
+	e.SetServiceEnv("collector", map[string]string{
+		"DB_ADAPTER":           db.Adapter,
+		"DB_CONNECTION_STRING": db.ConnectionString(),
+		"DATABASE_ID":          db.DatabaseID,
+		"DATABASE_NAME":        db.Name,
+	})
+	e.dependsOn["collector"] = append(e.dependsOn["collector"], db.Service)
+
+	for _, service := range e.Services {
+		if service == db.Service {
+			return
+		}
 	}
+	e.Services = append(e.Services, db.Service)
 }
@@ -1,15 +1,11 @@
 package framework
 
 import (
-	"os/exec"
 	"strings"
 )
 
 func (e *TestEnvironment) GetPublishedPort(service string, containerPort string) (string, error) {
-	cmd := exec.Command("docker", "compose",
-		"-f", e.ComposeFile,
-		"-p", e.ProjectName,
-		"port", service, containerPort)
+	cmd := e.composeCommand("port", service, containerPort)
 
 	out, err := cmd.Output()
 	if err != nil {
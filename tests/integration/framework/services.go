@@ -56,3 +56,17 @@ func (e *TestEnvironment) WaitForMetricsInLogs(serviceName string, searchString
 
 	return fmt.Errorf("did not find '%s' in %s logs within timeout", searchString, serviceName)
 }
+
+// CreateKnowledgeClient creates a gRPC client connected to Knowledge
+func CreateKnowledgeClient(t *testing.T, address string) (pb.KnowledgeServiceClient, *grpc.ClientConn) {
+	conn, err := grpc.NewClient(
+		address,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+
+	if err != nil {
+		t.Fatalf("Failed to create Knowledge client: %v", err)
+	}
+
+	return pb.NewKnowledgeServiceClient(conn), conn
+}
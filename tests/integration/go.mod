@@ -23,3 +23,5 @@ require (
 	google.golang.org/protobuf v1.36.10 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace github.com/EricMurray-e-m-dev/StartupMonkey/proto => ../../proto
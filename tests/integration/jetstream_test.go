@@ -0,0 +1,78 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/tests/integration/framework"
+	"github.com/stretchr/testify/require"
+)
+
+// TestJetStream_DetectionSurvivesExecutorRestart verifies a detection published
+// while the Executor is stopped is processed once the Executor comes back.
+func TestJetStream_DetectionSurvivesExecutorRestart(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	env := framework.NewTestEnvironment(t, []string{
+		"postgres",
+		"redis",
+		"nats",
+		"knowledge",
+		"executor",
+	})
+
+	err := env.Start()
+	require.NoError(t, err, "Failed to start services")
+	defer env.Cleanup()
+
+	err = env.WaitForHealthy(90 * time.Second)
+	require.NoError(t, err, "Services did not become healthy")
+
+	// Executor creates the DETECTIONS stream and its durable consumer on startup
+	err = env.WaitForMetricsInLogs("executor", "Subscribed to 'detections' (jetstream=true)", 60*time.Second)
+	require.NoError(t, err, "Executor did not subscribe via JetStream")
+
+	t.Log("Stopping Executor...")
+	err = env.StopService("executor")
+	require.NoError(t, err, "Failed to stop executor")
+
+	nc := connectToNATS(t, env)
+	defer nc.Close()
+
+	js, err := nc.JetStream()
+	require.NoError(t, err, "Failed to create JetStream context")
+
+	title := fmt.Sprintf("JetStream redelivery test %d", time.Now().UnixNano())
+	detection := map[string]interface{}{
+		"detection_id":    fmt.Sprintf("jetstream-test-%d", time.Now().UnixNano()),
+		"detector_name":   "test_detector",
+		"category":        "query",
+		"severity":        "info",
+		"database_id":     "dummy_app_01",
+		"timestamp":       time.Now().Unix(),
+		"title":           title,
+		"description":     "Published while the Executor was stopped",
+		"recommendation":  "This is a test",
+		"action_type":     "test_action",
+		"action_metadata": map[string]interface{}{"test": true},
+		"evidence":        map[string]interface{}{"test": true},
+	}
+
+	data, err := json.Marshal(detection)
+	require.NoError(t, err, "Failed to marshal detection")
+
+	_, err = js.Publish("detections", data)
+	require.NoError(t, err, "Failed to publish detection to JetStream")
+	t.Log("Detection published while Executor was down")
+
+	t.Log("Starting Executor...")
+	err = env.StartService("executor")
+	require.NoError(t, err, "Failed to start executor")
+
+	err = env.WaitForMetricsInLogs("executor", title, 90*time.Second)
+	require.NoError(t, err, "Executor did not process the detection published while it was down")
+}
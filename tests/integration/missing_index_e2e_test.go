@@ -0,0 +1,194 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/tests/integration/framework"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+)
+
+const e2eDatabaseID = "docker-test-db"
+
+// TestMissingIndex_DetectedIndexedAndResolved runs the full loop against a real database:
+// sequential scans on an unindexed column are collected, the Analyser publishes a
+// missing_index detection, the Executor creates the index, and once scans stop the
+// verification path marks the detection resolved in Knowledge.
+func TestMissingIndex_DetectedIndexedAndResolved(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	// Shorter cycles so verification completes within the test
+	t.Setenv("COLLECTION_INTERVAL", "5s")
+
+	env := framework.NewTestEnvironment(t, []string{
+		"postgres",
+		"redis",
+		"nats",
+		"knowledge",
+		"analyser",
+		"collector",
+		"executor",
+	})
+
+	err := env.Start()
+	require.NoError(t, err, "Failed to start services")
+	defer env.Cleanup()
+
+	err = env.WaitForHealthy(90 * time.Second)
+	require.NoError(t, err, "Services did not become healthy")
+
+	err = env.WaitForMetricsInLogs("collector", "Database registered with Knowledge: "+e2eDatabaseID, 60*time.Second)
+	require.NoError(t, err, "Collector did not register the test database")
+
+	// 1. Seed a table with no index on the column the workload filters by
+	_, err = env.QueryPostgres(`
+		CREATE TABLE orders (
+			id SERIAL PRIMARY KEY,
+			customer_id INTEGER NOT NULL,
+			total NUMERIC(10, 2) NOT NULL,
+			created_at TIMESTAMP DEFAULT NOW()
+		);
+		INSERT INTO orders (customer_id, total)
+		SELECT (random() * 999 + 1)::INTEGER, (random() * 500)::NUMERIC(10, 2)
+		FROM generate_series(1, 100000);
+		ANALYZE orders;
+	`)
+	require.NoError(t, err, "Failed to seed orders table")
+
+	nc := connectToNATS(t, env)
+	defer nc.Close()
+
+	detections := make(chan map[string]interface{}, 10)
+	_, err = nc.Subscribe("detections", func(msg *nats.Msg) {
+		var detection map[string]interface{}
+		if json.Unmarshal(msg.Data, &detection) == nil {
+			detections <- detection
+		}
+	})
+	require.NoError(t, err, "Failed to subscribe to detections")
+	require.NoError(t, nc.Flush())
+
+	port, err := env.GetPublishedPort("knowledge", "50053")
+	require.NoError(t, err, "Failed to determine Knowledge published port")
+
+	knowledge, conn := framework.CreateKnowledgeClient(t, "localhost:"+port)
+	defer conn.Close()
+
+	ctx := context.Background()
+
+	// Queries filter on customer_id; pg_stat_statements folds them into one statement
+	var workload strings.Builder
+	for i := 1; i <= 30; i++ {
+		fmt.Fprintf(&workload, "SELECT COUNT(*) FROM orders WHERE customer_id = %d;\n", i)
+	}
+	runWorkload := func() {
+		if _, err := env.QueryPostgres(workload.String()); err != nil {
+			t.Logf("Workload failed: %v", err)
+		}
+	}
+
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	dumpLogs := func() {
+		for _, service := range []string{"collector", "analyser", "executor"} {
+			logs, _ := env.GetLogs(service)
+			start := max(0, len(logs)-2000)
+			t.Logf("%s logs (last 2000 chars):\n%s", service, logs[start:])
+		}
+	}
+
+	// 2. Sequential scans produce a missing_index detection recommending customer_id
+	var detection map[string]interface{}
+	deadline := time.After(120 * time.Second)
+waitForDetection:
+	for {
+		select {
+		case d := <-detections:
+			if d["detector_name"] == "missing_index" && d["action_type"] == "create_index" && d["database_id"] == e2eDatabaseID {
+				detection = d
+				break waitForDetection
+			}
+		case <-ticker.C:
+			runWorkload()
+		case <-deadline:
+			dumpLogs()
+			t.Fatal("Analyser did not publish a create_index missing_index detection")
+		}
+	}
+
+	detectionID, _ := detection["id"].(string)
+	detectionKey, _ := detection["key"].(string)
+	require.NotEmpty(t, detectionID, "Detection should carry its ID")
+	require.NotEmpty(t, detectionKey, "Detection should carry its deduplication key")
+	t.Logf("Detection %s published: %v", detectionID, detection["title"])
+
+	// 3. The Executor creates the index
+	var indexName string
+	deadline = time.After(60 * time.Second)
+	for indexName == "" {
+		select {
+		case <-ticker.C:
+			indexName, err = env.QueryPostgres(
+				"SELECT indexname FROM pg_indexes WHERE tablename = 'orders' AND indexdef LIKE '%(customer_id)%'")
+			require.NoError(t, err, "Failed to query pg_indexes")
+		case <-deadline:
+			dumpLogs()
+			t.Fatal("Executor did not create an index on orders.customer_id")
+		}
+	}
+	t.Logf("Index created: %s", indexName)
+
+	// 4. The action is recorded as completed in Knowledge
+	var action *pb.Action
+	deadline = time.After(30 * time.Second)
+	for action == nil {
+		select {
+		case <-ticker.C:
+			resp, err := knowledge.ListActionsByStatus(ctx, &pb.ListActionsByStatusRequest{
+				Statuses:   []string{"completed"},
+				DatabaseId: e2eDatabaseID,
+			})
+			require.NoError(t, err, "Failed to list completed actions")
+
+			for _, a := range resp.Actions {
+				if a.DetectionId == detectionID && a.ActionType == "create_index" {
+					action = a
+					break
+				}
+			}
+		case <-deadline:
+			dumpLogs()
+			t.Fatal("create_index action did not reach completed in Knowledge")
+		}
+	}
+	t.Logf("Action %s completed: %s", action.Id, action.Message)
+
+	// 5. With the index in place scans stop rising, so verification resolves the detection
+	deadline = time.After(120 * time.Second)
+	for {
+		select {
+		case <-ticker.C:
+			runWorkload()
+
+			status, err := knowledge.IsDetectionActive(ctx, &pb.DetectionKeyRequest{Key: detectionKey})
+			require.NoError(t, err, "Failed to check detection status")
+
+			if !status.IsActive {
+				t.Log("Detection resolved by verification")
+				return
+			}
+		case <-deadline:
+			dumpLogs()
+			t.Fatal("Detection was not marked resolved after the index was created")
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package integration
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EricMurray-e-m-dev/StartupMonkey/tests/integration/framework"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestQueryLatency_ReachesAnalyser enables pg_stat_statements in the test container, runs
+// slow statements, and checks the Collector's latency percentiles get the Analyser to
+// publish a high_query_latency detection.
+func TestQueryLatency_ReachesAnalyser(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	t.Setenv("COLLECTION_INTERVAL", "5s")
+
+	env := framework.NewTestEnvironment(t, []string{
+		"postgres",
+		"redis",
+		"nats",
+		"knowledge",
+		"analyser",
+		"collector",
+	})
+
+	err := env.Start()
+	require.NoError(t, err, "Failed to start services")
+	defer env.Cleanup()
+
+	err = env.WaitForHealthy(90 * time.Second)
+	require.NoError(t, err, "Services did not become healthy")
+
+	err = env.WaitForMetricsInLogs("collector", "Database registered with Knowledge: "+e2eDatabaseID, 60*time.Second)
+	require.NoError(t, err, "Collector did not register the test database")
+
+	// The container preloads the library; start from empty statistics
+	_, err = env.QueryPostgres(`
+		CREATE EXTENSION IF NOT EXISTS pg_stat_statements;
+		SELECT pg_stat_statements_reset();
+	`)
+	require.NoError(t, err, "Failed to enable pg_stat_statements")
+
+	nc := connectToNATS(t, env)
+	defer nc.Close()
+
+	detections := make(chan map[string]interface{}, 10)
+	_, err = nc.Subscribe("detections", func(msg *nats.Msg) {
+		var detection map[string]interface{}
+		if json.Unmarshal(msg.Data, &detection) == nil {
+			detections <- detection
+		}
+	})
+	require.NoError(t, err, "Failed to subscribe to detections")
+	require.NoError(t, nc.Flush())
+
+	// Enough 200ms statements to outweigh the Collector's own fast queries at the 95th percentile
+	workload := strings.Repeat("SELECT pg_sleep(0.2);\n", 5)
+	runWorkload := func() {
+		if _, err := env.QueryPostgres(workload); err != nil {
+			t.Logf("Workload failed: %v", err)
+		}
+	}
+
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	deadline := time.After(120 * time.Second)
+	for {
+		select {
+		case d := <-detections:
+			if d["detector_name"] != "high_query_latency" || d["database_id"] != e2eDatabaseID {
+				continue
+			}
+
+			evidence, _ := d["evidence"].(map[string]interface{})
+			p95, ok := evidence["p95_latency_ms"].(float64)
+			require.True(t, ok, "Detection should carry the p95 latency: %v", evidence)
+			assert.Greater(t, p95, 100.0)
+			t.Logf("Detection published: %v (p95 %.1fms)", d["title"], p95)
+			return
+		case <-ticker.C:
+			runWorkload()
+		case <-deadline:
+			for _, service := range []string{"collector", "analyser"} {
+				logs, _ := env.GetLogs(service)
+				start := max(0, len(logs)-2000)
+				t.Logf("%s logs (last 2000 chars):\n%s", service, logs[start:])
+			}
+			t.Fatal("Analyser did not publish a high_query_latency detection")
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	pb "github.com/EricMurray-e-m-dev/StartupMonkey/proto"
+	"github.com/EricMurray-e-m-dev/StartupMonkey/tests/integration/framework"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+)
+
+const rollbackRequestSubject = "actions.rollback.request"
+
+// TestVerification_RollbackRequestedWhenIssuePersists drives the Analyser through a
+// create_index action that doesn't help: sequential scans keep rising after the index is
+// reported created, so the verification tracker must request a rollback.
+func TestVerification_RollbackRequestedWhenIssuePersists(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	env := framework.NewTestEnvironment(t, []string{
+		"redis",
+		"nats",
+		"knowledge",
+		"analyser",
+	})
+
+	err := env.Start()
+	require.NoError(t, err, "Failed to start services")
+	defer env.Cleanup()
+
+	err = env.WaitForHealthy(90 * time.Second)
+	require.NoError(t, err, "Services did not become healthy")
+
+	err = env.WaitForMetricsInLogs("analyser", "Subscribed to 'actions.completed'", 60*time.Second)
+	require.NoError(t, err, "Analyser did not subscribe to action completions")
+
+	nc := connectToNATS(t, env)
+	defer nc.Close()
+
+	detections := make(chan map[string]interface{}, 10)
+	_, err = nc.Subscribe("detections", func(msg *nats.Msg) {
+		var detection map[string]interface{}
+		if json.Unmarshal(msg.Data, &detection) == nil {
+			detections <- detection
+		}
+	})
+	require.NoError(t, err, "Failed to subscribe to detections")
+
+	rollbacks := make(chan map[string]interface{}, 10)
+	_, err = nc.Subscribe(rollbackRequestSubject, func(msg *nats.Msg) {
+		var request map[string]interface{}
+		if json.Unmarshal(msg.Data, &request) == nil {
+			rollbacks <- request
+		}
+	})
+	require.NoError(t, err, "Failed to subscribe to rollback requests")
+	require.NoError(t, nc.Flush())
+
+	port, err := env.GetPublishedPort("analyser", "50051")
+	require.NoError(t, err, "Failed to determine Analyser published port")
+
+	client, conn := framework.CreateAnalyserClient(t, "localhost:"+port)
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.StreamMetrics(ctx)
+	require.NoError(t, err, "Failed to open metrics stream")
+
+	// Drain acks so the stream never stalls on flow control
+	go func() {
+		for {
+			if _, err := stream.Recv(); err != nil {
+				return
+			}
+		}
+	}()
+
+	databaseID := fmt.Sprintf("rollback_test_%d", time.Now().UnixNano())
+	var sequence uint64
+	seqScans := int32(1000)
+
+	sendSnapshot := func() {
+		sequence++
+		seqScans += 500
+
+		snapshot := &pb.MetricSnapshot{
+			DatabaseId:   databaseID,
+			DatabaseType: "postgres",
+			Timestamp:    time.Now().Unix(),
+			Sequence:     sequence,
+			Measurements: &pb.Measurements{
+				SequentialScans: &seqScans,
+			},
+			ExtendedMetrics: map[string]float64{
+				"pg.table.orders.seq_scans":    float64(seqScans),
+				"pg.table.orders.seq_tup_read": float64(seqScans) * 1000,
+			},
+			Labels: map[string]string{
+				"pg.worst_seq_scan_table":     "orders",
+				"pg.recommended_index_column": "customer_id",
+			},
+			MetricDeltas: map[string]float64{
+				"sequential_scans": 500,
+			},
+		}
+
+		require.NoError(t, stream.Send(snapshot), "Failed to send snapshot")
+	}
+
+	// 1. Rising sequential scans produce a missing_index detection
+	sendSnapshot()
+
+	var detection map[string]interface{}
+	select {
+	case detection = <-detections:
+	case <-time.After(30 * time.Second):
+		t.Fatal("Analyser did not publish a missing_index detection")
+	}
+	require.Equal(t, "missing_index", detection["detector_name"])
+	require.Equal(t, databaseID, detection["database_id"])
+
+	detectionID, _ := detection["id"].(string)
+	detectionKey, _ := detection["key"].(string)
+	require.NotEmpty(t, detectionKey, "Detection should carry its deduplication key")
+
+	// 2. Report the index as created, as the Executor would
+	actionID := fmt.Sprintf("rollback-test-action-%d", time.Now().UnixNano())
+	completed, err := json.Marshal(map[string]interface{}{
+		"action_id":     actionID,
+		"detection_id":  detectionID,
+		"detection_key": detectionKey,
+		"action_type":   "create_index",
+		"database_id":   databaseID,
+		"status":        "completed",
+		"solution":      "index_created:orders_customer_id_idx",
+		"message":       "Index created",
+		"timestamp":     time.Now().Unix(),
+	})
+	require.NoError(t, err)
+
+	js, err := nc.JetStream()
+	require.NoError(t, err, "Failed to create JetStream context")
+	_, err = js.Publish("actions.completed", completed)
+	require.NoError(t, err, "Failed to publish action completion")
+
+	err = env.WaitForMetricsInLogs("analyser", "Action added to verification queue", 30*time.Second)
+	require.NoError(t, err, "Analyser did not start verifying the action")
+
+	// 3. Sequential scans keep rising, so the same detection re-fires and a rollback is requested
+	deadline := time.After(60 * time.Second)
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case request := <-rollbacks:
+			require.Equal(t, actionID, request["action_id"])
+			require.Equal(t, "create_index", request["action_type"])
+			require.Equal(t, databaseID, request["database_id"])
+			t.Logf("Rollback requested: %v", request["reason"])
+			return
+		case <-ticker.C:
+			sendSnapshot()
+		case <-deadline:
+			logs, _ := env.GetLogs("analyser")
+			start := max(0, len(logs)-2000)
+			t.Logf("Analyser logs (last 2000 chars):\n%s", logs[start:])
+			t.Fatal("No rollback request observed on " + rollbackRequestSubject)
+		}
+	}
+}